@@ -0,0 +1,164 @@
+package authentication
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// APIKeyVerifier verifies static API keys presented by services and maps them to their configured identity.
+type APIKeyVerifier struct {
+	header string
+	keys   []apiKey
+}
+
+type apiKey struct {
+	digest    []byte
+	details   UserDetails
+	domains   []string
+	resources []*regexp.Regexp
+}
+
+// NewAPIKeyVerifier creates a new APIKeyVerifier from the configuration, it returns nil if no API keys are configured.
+func NewAPIKeyVerifier(config schema.APIKeyAuthenticationBackendConfiguration) (verifier *APIKeyVerifier, err error) {
+	if len(config.Keys) == 0 {
+		return nil, nil
+	}
+
+	verifier = &APIKeyVerifier{
+		header: config.Header,
+		keys:   make([]apiKey, len(config.Keys)),
+	}
+
+	for i, key := range config.Keys {
+		var digest []byte
+
+		if digest, err = hex.DecodeString(key.Digest); err != nil || len(digest) != sha256.Size {
+			return nil, fmt.Errorf("unable to decode the digest of the API key '%s': it must be a hex encoded SHA-256 digest", key.Name)
+		}
+
+		verifier.keys[i] = apiKey{
+			digest: digest,
+			details: UserDetails{
+				Username:    key.Username,
+				DisplayName: key.Name,
+				Groups:      key.Groups,
+			},
+			domains:   key.Domains,
+			resources: make([]*regexp.Regexp, 0, len(key.Resources)),
+		}
+
+		for _, resource := range key.Resources {
+			pattern, err := regexp.Compile(resource)
+			if err != nil {
+				return nil, fmt.Errorf("unable to compile the resource '%s' of the API key '%s': %w", resource, key.Name, err)
+			}
+
+			verifier.keys[i].resources = append(verifier.keys[i].resources, pattern)
+		}
+	}
+
+	return verifier, nil
+}
+
+// Header returns the name of the header containing the API key.
+func (v *APIKeyVerifier) Header() string {
+	return v.header
+}
+
+// Presented returns true if the header value carries an API key. When the header is the Authorization header only
+// values using the Bearer scheme carry an API key so other schemes such as Basic can still be used.
+func (v *APIKeyVerifier) Presented(value string) bool {
+	if v.isAuthorizationHeader() {
+		return hasBearerPrefix(value)
+	}
+
+	return value != ""
+}
+
+// Verify extracts the API key from the header value and returns the details of the identity it is mapped to. When
+// the header is the Authorization header the key must use the Bearer scheme. It returns nil details without an error
+// when the value is not a configured API key so credentials meant for the protected application, such as its own
+// bearer tokens, fall through to the other authentication methods. It returns an error when the key is configured but
+// is not permitted to access the target URL.
+func (v *APIKeyVerifier) Verify(value string, targetURL *url.URL) (details *UserDetails, err error) {
+	if v.isAuthorizationHeader() {
+		if !hasBearerPrefix(value) {
+			return nil, nil
+		}
+
+		value = value[len(apiKeyBearerPrefix):]
+	}
+
+	if value == "" {
+		return nil, nil
+	}
+
+	digest := sha256.Sum256([]byte(value))
+
+	// Every key is compared so the time taken does not reveal which key, if any, matched.
+	match := -1
+
+	for i, key := range v.keys {
+		if subtle.ConstantTimeCompare(digest[:], key.digest) == 1 {
+			match = i
+		}
+	}
+
+	if match == -1 {
+		return nil, nil
+	}
+
+	key := &v.keys[match]
+
+	if !key.isPermitted(targetURL) {
+		return nil, fmt.Errorf("the API key '%s' is not permitted to access '%s'", key.details.DisplayName, targetURL.String())
+	}
+
+	details = &UserDetails{
+		Username:    key.details.Username,
+		DisplayName: key.details.DisplayName,
+		Groups:      append([]string(nil), key.details.Groups...),
+	}
+
+	return details, nil
+}
+
+// isPermitted returns true if the target URL is on one of the domains of the key and, when the key is restricted to
+// resources, its path matches one of them.
+func (k *apiKey) isPermitted(targetURL *url.URL) bool {
+	if !isDomainInDomains(targetURL.Hostname(), k.domains) {
+		return false
+	}
+
+	if len(k.resources) == 0 {
+		return true
+	}
+
+	resource := targetURL.Path
+	if targetURL.RawQuery != "" {
+		resource += "?" + targetURL.RawQuery
+	}
+
+	for _, pattern := range k.resources {
+		if pattern.MatchString(resource) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v *APIKeyVerifier) isAuthorizationHeader() bool {
+	return strings.EqualFold(v.header, apiKeyHeaderAuthorization)
+}
+
+func hasBearerPrefix(value string) bool {
+	return len(value) > len(apiKeyBearerPrefix) && strings.EqualFold(value[:len(apiKeyBearerPrefix)], apiKeyBearerPrefix)
+}
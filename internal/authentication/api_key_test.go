@@ -0,0 +1,133 @@
+package authentication
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestAPIKeyConfiguration(header string) schema.APIKeyAuthenticationBackendConfiguration {
+	return schema.APIKeyAuthenticationBackendConfiguration{
+		Header: header,
+		Keys: []schema.APIKeyConfiguration{
+			{
+				Name:     "backup",
+				Digest:   "6ca13d52ca70c883e0f0bb101e425a89e8624de51db2d2392593af6a84118090",
+				Username: "svc-backup",
+				Groups:   []string{"services", "backup"},
+				Domains:  []string{"*.example.com"},
+			},
+			{
+				Name:      "metrics",
+				Digest:    "d9298a10d1b0735837dc4bd85dac641b0f3cef27a47e5d53a54f2f3f5b2fcffa",
+				Username:  "svc-metrics",
+				Domains:   []string{"metrics.example.com"},
+				Resources: []string{"^/metrics$"},
+			},
+		},
+	}
+}
+
+var testAPIKeyTargetURL = &url.URL{Scheme: "https", Host: "metrics.example.com", Path: "/metrics"}
+
+func TestShouldNotCreateAPIKeyVerifierWithoutKeys(t *testing.T) {
+	verifier, err := NewAPIKeyVerifier(schema.APIKeyAuthenticationBackendConfiguration{Header: "Authorization"})
+
+	assert.NoError(t, err)
+	assert.Nil(t, verifier)
+}
+
+func TestShouldFailToCreateAPIKeyVerifierWithInvalidDigest(t *testing.T) {
+	config := newTestAPIKeyConfiguration("Authorization")
+	config.Keys[1].Digest = "abc"
+
+	verifier, err := NewAPIKeyVerifier(config)
+
+	assert.EqualError(t, err, "unable to decode the digest of the API key 'metrics': it must be a hex encoded SHA-256 digest")
+	assert.Nil(t, verifier)
+}
+
+func TestShouldVerifyAPIKeyWithBearerScheme(t *testing.T) {
+	verifier, err := NewAPIKeyVerifier(newTestAPIKeyConfiguration("Authorization"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Authorization", verifier.Header())
+	assert.True(t, verifier.Presented("Bearer abc123"))
+	assert.False(t, verifier.Presented("Basic YWJjMTIz"))
+	assert.False(t, verifier.Presented(""))
+
+	details, err := verifier.Verify("Bearer abc123", testAPIKeyTargetURL)
+	require.NoError(t, err)
+	assert.Equal(t, "svc-backup", details.Username)
+	assert.Equal(t, "backup", details.DisplayName)
+	assert.Equal(t, []string{"services", "backup"}, details.Groups)
+
+	details, err = verifier.Verify("bearer other", testAPIKeyTargetURL)
+	require.NoError(t, err)
+	assert.Equal(t, "svc-metrics", details.Username)
+	assert.Len(t, details.Groups, 0)
+
+	details, err = verifier.Verify("abc123", testAPIKeyTargetURL)
+	assert.NoError(t, err)
+	assert.Nil(t, details)
+
+	details, err = verifier.Verify("Basic YWJjMTIz", testAPIKeyTargetURL)
+	assert.NoError(t, err)
+	assert.Nil(t, details)
+
+	details, err = verifier.Verify("Bearer abc1234", testAPIKeyTargetURL)
+	assert.NoError(t, err)
+	assert.Nil(t, details)
+}
+
+func TestShouldNotVerifyAPIKeyOutsideItsScope(t *testing.T) {
+	verifier, err := NewAPIKeyVerifier(newTestAPIKeyConfiguration("Authorization"))
+	require.NoError(t, err)
+
+	details, err := verifier.Verify("Bearer abc123", &url.URL{Scheme: "https", Host: "example.org", Path: "/"})
+	assert.EqualError(t, err, "the API key 'backup' is not permitted to access 'https://example.org/'")
+	assert.Nil(t, details)
+
+	details, err = verifier.Verify("Bearer other", &url.URL{Scheme: "https", Host: "metrics.example.com", Path: "/metrics", RawQuery: "debug=1"})
+	assert.EqualError(t, err, "the API key 'metrics' is not permitted to access 'https://metrics.example.com/metrics?debug=1'")
+	assert.Nil(t, details)
+
+	details, err = verifier.Verify("Bearer other", &url.URL{Scheme: "https", Host: "metrics.example.com:8443", Path: "/metrics"})
+	require.NoError(t, err)
+	assert.Equal(t, "svc-metrics", details.Username)
+}
+
+func TestShouldFailToCreateAPIKeyVerifierWithInvalidResource(t *testing.T) {
+	config := newTestAPIKeyConfiguration("Authorization")
+	config.Keys[1].Resources = []string{"^/metrics("}
+
+	verifier, err := NewAPIKeyVerifier(config)
+
+	assert.EqualError(t, err, "unable to compile the resource '^/metrics(' of the API key 'metrics': error parsing regexp: missing closing ): `^/metrics(`")
+	assert.Nil(t, verifier)
+}
+
+func TestShouldVerifyAPIKeyWithCustomHeader(t *testing.T) {
+	verifier, err := NewAPIKeyVerifier(newTestAPIKeyConfiguration("X-API-Key"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "X-API-Key", verifier.Header())
+	assert.True(t, verifier.Presented("abc123"))
+	assert.False(t, verifier.Presented(""))
+
+	details, err := verifier.Verify("abc123", testAPIKeyTargetURL)
+	require.NoError(t, err)
+	assert.Equal(t, "svc-backup", details.Username)
+
+	details, err = verifier.Verify("Bearer abc123", testAPIKeyTargetURL)
+	assert.NoError(t, err)
+	assert.Nil(t, details)
+
+	details, err = verifier.Verify("", testAPIKeyTargetURL)
+	assert.NoError(t, err)
+	assert.Nil(t, details)
+}
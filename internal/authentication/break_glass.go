@@ -0,0 +1,68 @@
+package authentication
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// BreakGlassAccount is the local account which can authenticate administrators when the primary authentication backend
+// is unavailable. The primary authentication backend is never consulted for this account.
+type BreakGlassAccount struct {
+	hash    string
+	details UserDetails
+}
+
+// NewBreakGlassAccount creates a new BreakGlassAccount from the configuration, it returns nil if the break-glass
+// account is not enabled.
+func NewBreakGlassAccount(config schema.BreakGlassAuthenticationBackendConfiguration) (account *BreakGlassAccount, err error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	if _, err = ParseHash(config.Password); err != nil {
+		return nil, fmt.Errorf("unable to parse the password hash of the break-glass account: %w", err)
+	}
+
+	account = &BreakGlassAccount{
+		hash: config.Password,
+		details: UserDetails{
+			Username:    config.Username,
+			DisplayName: config.DisplayName,
+			Groups:      []string{config.Group},
+		},
+	}
+
+	if config.Email != "" {
+		account.details.Emails = []string{config.Email}
+	}
+
+	return account, nil
+}
+
+// IsAccount returns true if the username refers to the break-glass account. The comparison is case-insensitive so the
+// account can't be shadowed by a differently cased user of a case-insensitive primary authentication backend.
+func (a *BreakGlassAccount) IsAccount(username string) bool {
+	return strings.EqualFold(username, a.details.Username)
+}
+
+// Username returns the username of the break-glass account.
+func (a *BreakGlassAccount) Username() string {
+	return a.details.Username
+}
+
+// CheckPassword checks the password of the break-glass account.
+func (a *BreakGlassAccount) CheckPassword(password string) (valid bool, err error) {
+	return CheckPassword(password, a.hash)
+}
+
+// GetDetails returns the details of the break-glass account.
+func (a *BreakGlassAccount) GetDetails() (details *UserDetails) {
+	return &UserDetails{
+		Username:    a.details.Username,
+		DisplayName: a.details.DisplayName,
+		Emails:      append([]string(nil), a.details.Emails...),
+		Groups:      append([]string(nil), a.details.Groups...),
+	}
+}
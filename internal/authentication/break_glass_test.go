@@ -0,0 +1,82 @@
+package authentication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestBreakGlassConfig(t *testing.T) schema.BreakGlassAuthenticationBackendConfiguration {
+	hash, err := HashPassword("password", "aFr56HjK3DrB8t3S", HashingAlgorithmSHA512, 5000, 0, 0, 0, 16)
+	require.NoError(t, err)
+
+	return schema.BreakGlassAuthenticationBackendConfiguration{
+		Enabled:     true,
+		Username:    "breakglass",
+		Password:    hash,
+		DisplayName: "Break-Glass Administrator",
+		Email:       "security@example.com",
+		Group:       "admins",
+	}
+}
+
+func TestShouldNotCreateBreakGlassAccountWhenDisabled(t *testing.T) {
+	config := newTestBreakGlassConfig(t)
+	config.Enabled = false
+
+	account, err := NewBreakGlassAccount(config)
+
+	assert.NoError(t, err)
+	assert.Nil(t, account)
+}
+
+func TestShouldErrorCreatingBreakGlassAccountWithInvalidHash(t *testing.T) {
+	config := newTestBreakGlassConfig(t)
+	config.Password = "password"
+
+	account, err := NewBreakGlassAccount(config)
+
+	assert.Nil(t, account)
+	assert.EqualError(t, err, "unable to parse the password hash of the break-glass account: Hash key is not the last parameter, the hash is likely malformed (password)")
+}
+
+func TestShouldCheckBreakGlassAccount(t *testing.T) {
+	account, err := NewBreakGlassAccount(newTestBreakGlassConfig(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "breakglass", account.Username())
+	assert.True(t, account.IsAccount("breakglass"))
+	assert.True(t, account.IsAccount("BreakGlass"))
+	assert.False(t, account.IsAccount("john"))
+	assert.False(t, account.IsAccount(""))
+
+	valid, err := account.CheckPassword("password")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = account.CheckPassword("wrong")
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestShouldReturnBreakGlassAccountDetails(t *testing.T) {
+	account, err := NewBreakGlassAccount(newTestBreakGlassConfig(t))
+	require.NoError(t, err)
+
+	details := account.GetDetails()
+
+	assert.Equal(t, &UserDetails{
+		Username:    "breakglass",
+		DisplayName: "Break-Glass Administrator",
+		Emails:      []string{"security@example.com"},
+		Groups:      []string{"admins"},
+	}, details)
+
+	// The returned details must not alter the account.
+	details.Groups[0] = "users"
+
+	assert.Equal(t, []string{"admins"}, account.GetDetails().Groups)
+}
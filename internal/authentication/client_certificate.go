@@ -0,0 +1,179 @@
+package authentication
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ClientCertificateVerifier verifies client certificates forwarded by the proxy and extracts the username from them.
+// The forwarded certificate is only trusted when the request was received directly from one of the trusted networks.
+type ClientCertificateVerifier struct {
+	header    string
+	attribute string
+	roots     *x509.CertPool
+	networks  []*net.IPNet
+}
+
+// NewClientCertificateVerifier creates a new ClientCertificateVerifier from the configuration, it returns nil if
+// client certificate authentication is not enabled.
+func NewClientCertificateVerifier(config schema.ClientCertificateAuthenticationBackendConfiguration) (verifier *ClientCertificateVerifier, err error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(config.CertificateAuthority)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the client certificate authority: %w", err)
+	}
+
+	roots := x509.NewCertPool()
+
+	if !roots.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("unable to parse the client certificate authority '%s': no PEM encoded certificates were found", config.CertificateAuthority)
+	}
+
+	verifier = &ClientCertificateVerifier{
+		header:    config.Header,
+		attribute: config.UsernameAttribute,
+		roots:     roots,
+		networks:  make([]*net.IPNet, 0, len(config.Networks)),
+	}
+
+	for _, network := range config.Networks {
+		cidr, err := utils.ParseNetwork(network)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the client certificate network '%s': %w", network, err)
+		}
+
+		verifier.networks = append(verifier.networks, cidr)
+	}
+
+	return verifier, nil
+}
+
+// Header returns the name of the header containing the forwarded client certificate.
+func (v *ClientCertificateVerifier) Header() string {
+	return v.header
+}
+
+// Verify parses the forwarded client certificate value, verifies the chain against the certificate authority at the
+// provided time, and returns the username from the configured attribute. The remote IP must be the IP of the peer the
+// request was directly received from, not one taken from a forwarded header, otherwise anyone could forward a
+// certificate without holding its private key.
+func (v *ClientCertificateVerifier) Verify(remoteIP net.IP, value string, now time.Time) (username string, err error) {
+	if !utils.IsIPInNetworks(remoteIP, v.networks) {
+		return "", fmt.Errorf("the request was received from %s which is not a trusted network", remoteIP)
+	}
+
+	certs, err := parseForwardedClientCertificate(value)
+	if err != nil {
+		return "", err
+	}
+
+	intermediates := x509.NewCertPool()
+
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	if _, err = certs[0].Verify(x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return "", fmt.Errorf("client certificate with subject '%s' failed verification: %w", certs[0].Subject, err)
+	}
+
+	switch v.attribute {
+	case "email_address":
+		if len(certs[0].EmailAddresses) != 0 {
+			username = certs[0].EmailAddresses[0]
+		}
+	case "dns_name":
+		if len(certs[0].DNSNames) != 0 {
+			username = certs[0].DNSNames[0]
+		}
+	case "uri":
+		if len(certs[0].URIs) != 0 {
+			username = certs[0].URIs[0].String()
+		}
+	default:
+		username = certs[0].Subject.CommonName
+	}
+
+	if username == "" {
+		return "", fmt.Errorf("client certificate with subject '%s' does not have a value for the '%s' attribute", certs[0].Subject, v.attribute)
+	}
+
+	return username, nil
+}
+
+// parseForwardedClientCertificate parses a client certificate chain forwarded by a proxy, which may be URL encoded
+// and either PEM encoded or base64 encoded DER. The leaf certificate is always the first certificate.
+func parseForwardedClientCertificate(value string) (certs []*x509.Certificate, err error) {
+	if value == "" {
+		return nil, errors.New("no client certificate was provided")
+	}
+
+	if strings.Contains(value, "%") {
+		if value, err = url.PathUnescape(value); err != nil {
+			return nil, fmt.Errorf("unable to decode the client certificate: %w", err)
+		}
+	}
+
+	if strings.Contains(value, "-----BEGIN") {
+		data := []byte(value)
+
+		for {
+			var block *pem.Block
+
+			if block, data = pem.Decode(data); block == nil {
+				break
+			}
+
+			var cert *x509.Certificate
+
+			if cert, err = x509.ParseCertificate(block.Bytes); err != nil {
+				return nil, fmt.Errorf("unable to parse the client certificate: %w", err)
+			}
+
+			certs = append(certs, cert)
+		}
+	} else {
+		// Some proxies send a comma separated list of base64 encoded DER certificates without the PEM armor.
+		for _, encoded := range strings.Split(value, ",") {
+			var (
+				der  []byte
+				cert *x509.Certificate
+			)
+
+			if der, err = base64.StdEncoding.DecodeString(strings.TrimSpace(encoded)); err != nil {
+				return nil, fmt.Errorf("unable to decode the client certificate: %w", err)
+			}
+
+			if cert, err = x509.ParseCertificate(der); err != nil {
+				return nil, fmt.Errorf("unable to parse the client certificate: %w", err)
+			}
+
+			certs = append(certs, cert)
+		}
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("no client certificate was found in the provided value")
+	}
+
+	return certs, nil
+}
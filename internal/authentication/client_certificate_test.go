@@ -0,0 +1,221 @@
+package authentication
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+type testClientCertificateAuthority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestClientCertificateAuthority(t *testing.T) *testClientCertificateAuthority {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Authelia Test CA"},
+		NotBefore:             time.Unix(1000000000, 0),
+		NotAfter:              time.Unix(2000000000, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testClientCertificateAuthority{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+func (ca *testClientCertificateAuthority) issue(t *testing.T, template *x509.Certificate) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(2)
+	template.NotBefore = time.Unix(1000000000, 0)
+	template.NotAfter = time.Unix(1800000000, 0)
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+
+	if template.ExtKeyUsage == nil {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	return der
+}
+
+var testClientCertificateRemoteIP = net.ParseIP("10.0.0.1")
+
+func newTestClientCertificateVerifier(t *testing.T, ca *testClientCertificateAuthority, attribute string) *ClientCertificateVerifier {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+
+	require.NoError(t, os.WriteFile(path, ca.pem, 0600))
+
+	verifier, err := NewClientCertificateVerifier(schema.ClientCertificateAuthenticationBackendConfiguration{
+		Enabled:              true,
+		Header:               "X-Forwarded-Tls-Client-Cert",
+		CertificateAuthority: path,
+		UsernameAttribute:    attribute,
+		Networks:             []string{"10.0.0.0/8"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, verifier)
+
+	return verifier
+}
+
+func TestShouldNotCreateClientCertificateVerifierWhenDisabled(t *testing.T) {
+	verifier, err := NewClientCertificateVerifier(schema.ClientCertificateAuthenticationBackendConfiguration{})
+
+	assert.NoError(t, err)
+	assert.Nil(t, verifier)
+}
+
+func TestShouldFailToCreateClientCertificateVerifierWithInvalidCA(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+
+	require.NoError(t, os.WriteFile(path, []byte("not a certificate"), 0600))
+
+	_, err := NewClientCertificateVerifier(schema.ClientCertificateAuthenticationBackendConfiguration{
+		Enabled:              true,
+		CertificateAuthority: path,
+	})
+
+	assert.EqualError(t, err, "unable to parse the client certificate authority '"+path+"': no PEM encoded certificates were found")
+}
+
+func TestShouldVerifyClientCertificateEncodings(t *testing.T) {
+	ca := newTestClientCertificateAuthority(t)
+	verifier := newTestClientCertificateVerifier(t, ca, "common_name")
+
+	assert.Equal(t, "X-Forwarded-Tls-Client-Cert", verifier.Header())
+
+	der := ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "john"}})
+	encoded := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	testCases := []struct {
+		name  string
+		value string
+	}{
+		{"PEM", encoded},
+		{"URLEncodedPEM", url.PathEscape(encoded)},
+		{"DER", base64.StdEncoding.EncodeToString(der)},
+		{"DERChain", base64.StdEncoding.EncodeToString(der) + "," + base64.StdEncoding.EncodeToString(ca.cert.Raw)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			username, err := verifier.Verify(testClientCertificateRemoteIP, tc.value, time.Unix(1500000000, 0))
+
+			assert.NoError(t, err)
+			assert.Equal(t, "john", username)
+		})
+	}
+}
+
+func TestShouldVerifyClientCertificateUsernameAttributes(t *testing.T) {
+	ca := newTestClientCertificateAuthority(t)
+
+	uri, err := url.Parse("spiffe://authelia.com/john")
+	require.NoError(t, err)
+
+	der := ca.issue(t, &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "john"},
+		EmailAddresses: []string{"john@authelia.com"},
+		DNSNames:       []string{"john.authelia.com"},
+		URIs:           []*url.URL{uri},
+	})
+	value := base64.StdEncoding.EncodeToString(der)
+
+	testCases := []struct {
+		attribute, expected string
+	}{
+		{"common_name", "john"},
+		{"email_address", "john@authelia.com"},
+		{"dns_name", "john.authelia.com"},
+		{"uri", "spiffe://authelia.com/john"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.attribute, func(t *testing.T) {
+			username, err := newTestClientCertificateVerifier(t, ca, tc.attribute).Verify(testClientCertificateRemoteIP, value, time.Unix(1500000000, 0))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, username)
+		})
+	}
+}
+
+func TestShouldNotVerifyClientCertificateFromUntrustedNetwork(t *testing.T) {
+	ca := newTestClientCertificateAuthority(t)
+	verifier := newTestClientCertificateVerifier(t, ca, "common_name")
+
+	valid := base64.StdEncoding.EncodeToString(ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "john"}}))
+
+	username, err := verifier.Verify(net.ParseIP("192.168.0.1"), valid, time.Unix(1500000000, 0))
+	assert.EqualError(t, err, "the request was received from 192.168.0.1 which is not a trusted network")
+	assert.Equal(t, "", username)
+
+	username, err = verifier.Verify(testClientCertificateRemoteIP, valid, time.Unix(1500000000, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, "john", username)
+}
+
+func TestShouldNotVerifyInvalidClientCertificates(t *testing.T) {
+	ca := newTestClientCertificateAuthority(t)
+	other := newTestClientCertificateAuthority(t)
+	verifier := newTestClientCertificateVerifier(t, ca, "email_address")
+
+	valid := base64.StdEncoding.EncodeToString(ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "john"}, EmailAddresses: []string{"john@authelia.com"}}))
+
+	_, err := verifier.Verify(testClientCertificateRemoteIP, valid, time.Unix(1900000000, 0))
+	assert.Regexp(t, `^client certificate with subject 'CN=john' failed verification: x509: certificate has expired or is not yet valid`, err.Error())
+
+	_, err = verifier.Verify(testClientCertificateRemoteIP, base64.StdEncoding.EncodeToString(other.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "john"}})), time.Unix(1500000000, 0))
+	assert.Regexp(t, `^client certificate with subject 'CN=john' failed verification: x509: certificate signed by unknown authority`, err.Error())
+
+	_, err = verifier.Verify(testClientCertificateRemoteIP, base64.StdEncoding.EncodeToString(ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "john"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}})), time.Unix(1500000000, 0))
+	assert.Regexp(t, `^client certificate with subject 'CN=john' failed verification: x509: certificate specifies an incompatible key usage`, err.Error())
+
+	_, err = verifier.Verify(testClientCertificateRemoteIP, base64.StdEncoding.EncodeToString(ca.issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "john"}})), time.Unix(1500000000, 0))
+	assert.EqualError(t, err, "client certificate with subject 'CN=john' does not have a value for the 'email_address' attribute")
+
+	_, err = verifier.Verify(testClientCertificateRemoteIP, "", time.Unix(1500000000, 0))
+	assert.EqualError(t, err, "no client certificate was provided")
+
+	_, err = verifier.Verify(testClientCertificateRemoteIP, "-----BEGIN nothing", time.Unix(1500000000, 0))
+	assert.EqualError(t, err, "no client certificate was found in the provided value")
+
+	_, err = verifier.Verify(testClientCertificateRemoteIP, "not-base64!", time.Unix(1500000000, 0))
+	assert.Regexp(t, `^unable to decode the client certificate: `, err.Error())
+}
@@ -2,6 +2,8 @@ package authentication
 
 import (
 	"errors"
+	"expvar"
+	"time"
 )
 
 // Level is the type representing a level of authentication.
@@ -16,11 +18,41 @@ const (
 	TwoFactor Level = iota
 )
 
+const (
+	apiKeyHeaderAuthorization = "Authorization"
+	apiKeyBearerPrefix        = "Bearer "
+)
+
+const (
+	// remoteKeySetMinimumRefresh is the minimum time between fetching a remote JWKS, which prevents tokens with random
+	// key IDs or a server which disables caching from causing a request for each token.
+	remoteKeySetMinimumRefresh = time.Minute
+
+	remoteKeySetTimeout = time.Second * 10
+)
+
 const (
 	ldapSupportedExtensionAttribute = "supportedExtension"
 	ldapOIDPasswdModifyExtension    = "1.3.6.1.4.1.4203.1.11.1" // http://oidref.com/1.3.6.1.4.1.4203.1.11.1
 )
 
+// The data codes Active Directory includes in the diagnostic message of a bind which failed with invalid credentials,
+// they are only returned when the password itself is correct.
+const (
+	ldapActiveDirectoryDataPasswordExpired    = "data 532"
+	ldapActiveDirectoryDataAccountExpired     = "data 701"
+	ldapActiveDirectoryDataPasswordMustChange = "data 773"
+)
+
+const (
+	// ldapFileTimeNever is the value of an Active Directory file time which never occurs.
+	ldapFileTimeNever = 0x7FFFFFFFFFFFFFFF
+
+	// ldapFileTimeUnixEpoch is the number of 100 nanosecond intervals between the epoch of an Active Directory file time
+	// which is 1601-01-01 and the unix epoch.
+	ldapFileTimeUnixEpoch = 116444736000000000
+)
+
 const (
 	ldapPlaceholderInput             = "{input}"
 	ldapPlaceholderDistinguishedName = "{dn}"
@@ -53,6 +85,29 @@ var HashingPossibleSaltCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQ
 // ErrUserNotFound indicates the user wasn't found in the authentication backend.
 var ErrUserNotFound = errors.New("user not found")
 
+// ErrUserAlreadyExists indicates the user already exists in the authentication backend.
+var ErrUserAlreadyExists = errors.New("user already exists")
+
+// ErrUserDisabled indicates the user has been disabled in the authentication backend.
+var ErrUserDisabled = errors.New("user is disabled")
+
+// ErrBackendBusy indicates an operation timed out waiting for the concurrency limit of the authentication backend.
+var ErrBackendBusy = errors.New("timed out waiting for the concurrency limit of the authentication backend")
+
+// ErrPasswordExpired indicates the password of the user has expired in the authentication backend.
+var ErrPasswordExpired = errors.New("password has expired")
+
+// ErrPasswordMustChange indicates the user must change their password before authenticating.
+var ErrPasswordMustChange = errors.New("password must be changed")
+
+// ErrAccountExpired indicates the account of the user has expired in the authentication backend.
+var ErrAccountExpired = errors.New("account has expired")
+
+var (
+	errCacheControlNoCache  = errors.New("the response must not be cached")
+	errCacheControlNoMaxAge = errors.New("the response has no max-age directive")
+)
+
 const argon2id = "argon2id"
 const sha512 = "sha512"
 
@@ -60,6 +115,15 @@ const testPassword = "my;secure*password"
 
 const fileAuthenticationMode = 0600
 
+const (
+	ldapExpvarQueued   = "queued"
+	ldapExpvarRejected = "rejected"
+)
+
+// ldapExpvars counts the LDAP operations which were queued and rejected due to the concurrency limit, they are exposed
+// by the expvars endpoint.
+var ldapExpvars = expvar.NewMap("authelia_ldap_concurrency")
+
 // OWASP recommends to escape some special characters.
 // https://github.com/OWASP/CheatSheetSeries/blob/master/cheatsheets/LDAP_Injection_Prevention_Cheat_Sheet.md
 const specialLDAPRunes = ",#+<>;\"="
@@ -1,6 +1,7 @@
 package authentication
 
 import (
+	"context"
 	_ "embed" // Embed users_database.template.yml.
 	"fmt"
 	"os"
@@ -27,6 +28,7 @@ type UserDetailsModel struct {
 	DisplayName    string   `yaml:"displayname" valid:"required"`
 	Email          string   `yaml:"email"`
 	Groups         []string `yaml:"groups"`
+	Disabled       bool     `yaml:"disabled,omitempty"`
 }
 
 // DatabaseModel is the model of users file database.
@@ -59,6 +61,10 @@ func NewFileUserProvider(configuration *schema.FileAuthenticationBackendConfigur
 		panic(err)
 	}
 
+	if configuration.Password != nil && configuration.Password.MemoryPercentage != 0 {
+		resolvePasswordMemory(configuration.Password)
+	}
+
 	return &FileUserProvider{
 		configuration: configuration,
 		database:      database,
@@ -66,13 +72,29 @@ func NewFileUserProvider(configuration *schema.FileAuthenticationBackendConfigur
 	}
 }
 
+// resolvePasswordMemory resolves the argon2id memory parameter used to hash new passwords from the available system
+// memory and logs the resolved parameters.
+func resolvePasswordMemory(config *schema.PasswordConfiguration) {
+	logger := logging.Logger()
+
+	memory, err := ResolvePasswordMemory(config)
+	if err != nil {
+		logger.Warnf("Unable to resolve the argon2id memory parameter from the available system memory, using the minimum of %d MB: %v", memory, err)
+	}
+
+	config.Memory = memory
+
+	logger.Infof("Resolved the argon2id password hashing parameters to %d MB memory (%d%% of the available system memory), %d iterations, %d parallelism, a key length of %d and a salt length of %d",
+		config.Memory, config.MemoryPercentage, config.Iterations, config.Parallelism, config.KeyLength, config.SaltLength)
+}
+
 func checkPasswordHashes(database *DatabaseModel) error {
 	for u, v := range database.Users {
 		v.HashedPassword = strings.ReplaceAll(v.HashedPassword, "{CRYPT}", "")
 		_, err := ParseHash(v.HashedPassword)
 
 		if err != nil {
-			return fmt.Errorf("Unable to parse hash of user %s: %s", u, err)
+			return fmt.Errorf("Unable to parse hash of user '%s': %s", u, err)
 		}
 
 		database.Users[u] = v
@@ -174,33 +196,128 @@ func (p *FileUserProvider) UpdatePassword(username string, newPassword string) e
 		return ErrUserNotFound
 	}
 
-	algorithm, err := ConfigAlgoToCryptoAlgo(p.configuration.Password.Algorithm)
+	hash, err := p.HashPassword(newPassword)
 	if err != nil {
 		return err
 	}
 
-	hash, err := HashPassword(
-		newPassword, "", algorithm, p.configuration.Password.Iterations,
-		p.configuration.Password.Memory*1024, p.configuration.Password.Parallelism,
-		p.configuration.Password.KeyLength, p.configuration.Password.SaltLength)
+	details.HashedPassword = hash
+
+	p.lock.Lock()
+	p.database.Users[username] = details
 
+	b, err := yaml.Marshal(p.database)
 	if err != nil {
+		p.lock.Unlock()
 		return err
 	}
 
-	details.HashedPassword = hash
+	err = os.WriteFile(p.configuration.Path, b, fileAuthenticationMode)
+	p.lock.Unlock()
+
+	return err
+}
+
+// IsUserDisabled returns true if the given user is disabled. The database is held in memory so this doesn't read the
+// file.
+func (p *FileUserProvider) IsUserDisabled(username string) (disabled bool, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	details, ok := p.database.Users[username]
+	if !ok {
+		return false, ErrUserNotFound
+	}
 
+	return details.Disabled, nil
+}
+
+// SetUserDisabled disables or enables the given user.
+func (p *FileUserProvider) SetUserDisabled(username string, disabled bool) (err error) {
 	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	details, ok := p.database.Users[username]
+	if !ok {
+		return ErrUserNotFound
+	}
+
+	previous := details
+
+	details.Disabled = disabled
+
 	p.database.Users[username] = details
 
 	b, err := yaml.Marshal(p.database)
 	if err != nil {
-		p.lock.Unlock()
+		p.database.Users[username] = previous
+
 		return err
 	}
 
-	err = os.WriteFile(p.configuration.Path, b, fileAuthenticationMode)
-	p.lock.Unlock()
+	if err = os.WriteFile(p.configuration.Path, b, fileAuthenticationMode); err != nil {
+		p.database.Users[username] = previous
+
+		return err
+	}
+
+	return nil
+}
+
+// HashPassword hashes the given password using the configured password hashing algorithm.
+func (p *FileUserProvider) HashPassword(password string) (hash string, err error) {
+	algorithm, err := ConfigAlgoToCryptoAlgo(p.configuration.Password.Algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	return HashPassword(
+		password, "", algorithm, p.configuration.Password.Iterations,
+		p.configuration.Password.Memory*1024, p.configuration.Password.Parallelism,
+		p.configuration.Password.KeyLength, p.configuration.Password.SaltLength)
+}
+
+// AddUser adds a new user with an already hashed password to the database.
+func (p *FileUserProvider) AddUser(username string, details UserDetails, hashedPassword string) (err error) {
+	var email string
+
+	if len(details.Emails) != 0 {
+		email = details.Emails[0]
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if _, ok := p.database.Users[username]; ok {
+		return ErrUserAlreadyExists
+	}
+
+	p.database.Users[username] = UserDetailsModel{
+		HashedPassword: hashedPassword,
+		DisplayName:    details.DisplayName,
+		Email:          email,
+		Groups:         details.Groups,
+	}
+
+	b, err := yaml.Marshal(p.database)
+	if err != nil {
+		delete(p.database.Users, username)
+
+		return err
+	}
+
+	if err = os.WriteFile(p.configuration.Path, b, fileAuthenticationMode); err != nil {
+		delete(p.database.Users, username)
+
+		return err
+	}
+
+	return nil
+}
+
+// HealthCheck implements the health check provider interface by checking the users database file exists.
+func (p *FileUserProvider) HealthCheck(_ context.Context) (err error) {
+	_, err = os.Stat(p.configuration.Path)
 
 	return err
 }
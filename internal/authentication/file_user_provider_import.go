@@ -0,0 +1,153 @@
+package authentication
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"gopkg.in/yaml.v3"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// UserImportExportModel is the model of a single user when importing or exporting the file database.
+type UserImportExportModel struct {
+	Username       string   `json:"username"`
+	DisplayName    string   `json:"displayname"`
+	Email          string   `json:"email"`
+	Groups         []string `json:"groups"`
+	Password       string   `json:"password,omitempty"`
+	HashedPassword string   `json:"hashed_password,omitempty"`
+}
+
+// ExportUsersDatabase reads the file database at the given path and returns the users sorted by username. The
+// passwords are only ever exported in their hashed form.
+func ExportUsersDatabase(path string) (users []UserImportExportModel, err error) {
+	database, err := readDatabase(path)
+	if err != nil {
+		return nil, err
+	}
+
+	users = make([]UserImportExportModel, 0, len(database.Users))
+
+	for username, details := range database.Users {
+		users = append(users, UserImportExportModel{
+			Username:       username,
+			DisplayName:    details.DisplayName,
+			Email:          details.Email,
+			Groups:         details.Groups,
+			HashedPassword: details.HashedPassword,
+		})
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+
+	return users, nil
+}
+
+// ImportUsersDatabase validates the users and adds them to the file database at the given path, creating it if it
+// doesn't exist. Plaintext passwords are hashed using the provided configuration. Users which fail validation are
+// skipped and reported in errs without preventing the other users from being imported, existing users are only
+// replaced when overwrite is true.
+func ImportUsersDatabase(path string, users []UserImportExportModel, config *schema.PasswordConfiguration, overwrite bool) (imported int, errs []error, err error) {
+	database := &DatabaseModel{Users: map[string]UserDetailsModel{}}
+
+	if _, err = os.Stat(path); err == nil {
+		if database, err = readDatabase(path); err != nil {
+			return 0, nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return 0, nil, fmt.Errorf("Unable to read database from file %s: %w", path, err)
+	}
+
+	algorithm, err := ConfigAlgoToCryptoAlgo(config.Algorithm)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	seen := map[string]bool{}
+
+	for i, user := range users {
+		var details UserDetailsModel
+
+		if details, err = importUser(user, algorithm, config); err != nil {
+			errs = append(errs, fmt.Errorf("entry %d: %w", i+1, err))
+
+			continue
+		}
+
+		if seen[user.Username] {
+			errs = append(errs, fmt.Errorf("entry %d: user '%s' is specified more than once", i+1, user.Username))
+
+			continue
+		}
+
+		seen[user.Username] = true
+
+		if _, ok := database.Users[user.Username]; ok && !overwrite {
+			errs = append(errs, fmt.Errorf("entry %d: user '%s' already exists", i+1, user.Username))
+
+			continue
+		}
+
+		database.Users[user.Username] = details
+		imported++
+	}
+
+	if imported == 0 {
+		return 0, errs, nil
+	}
+
+	b, err := yaml.Marshal(database)
+	if err != nil {
+		return 0, errs, err
+	}
+
+	if err = os.WriteFile(path, b, fileAuthenticationMode); err != nil {
+		return 0, errs, err
+	}
+
+	return imported, errs, nil
+}
+
+func importUser(user UserImportExportModel, algorithm CryptAlgo, config *schema.PasswordConfiguration) (details UserDetailsModel, err error) {
+	switch {
+	case user.Username == "":
+		return details, fmt.Errorf("username is required")
+	case user.DisplayName == "":
+		return details, fmt.Errorf("user '%s' must have a display name", user.Username)
+	case user.Email != "" && !govalidator.IsEmail(user.Email):
+		return details, fmt.Errorf("user '%s' has an invalid email '%s'", user.Username, user.Email)
+	case user.Password == "" && user.HashedPassword == "":
+		return details, fmt.Errorf("user '%s' must have either a password or a hashed password", user.Username)
+	case user.Password != "" && user.HashedPassword != "":
+		return details, fmt.Errorf("user '%s' must not have both a password and a hashed password", user.Username)
+	}
+
+	details = UserDetailsModel{
+		DisplayName: user.DisplayName,
+		Email:       user.Email,
+		Groups:      user.Groups,
+	}
+
+	if user.HashedPassword != "" {
+		if _, err = ParseHash(strings.ReplaceAll(user.HashedPassword, "{CRYPT}", "")); err != nil {
+			return details, fmt.Errorf("user '%s' has an invalid hashed password: %w", user.Username, err)
+		}
+
+		details.HashedPassword = user.HashedPassword
+
+		return details, nil
+	}
+
+	if details.HashedPassword, err = HashPassword(user.Password, "", algorithm, config.Iterations,
+		config.Memory*1024, config.Parallelism, config.KeyLength, config.SaltLength); err != nil {
+		return details, fmt.Errorf("user '%s' password could not be hashed: %w", user.Username, err)
+	}
+
+	return details, nil
+}
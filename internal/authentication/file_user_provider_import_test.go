@@ -0,0 +1,103 @@
+package authentication
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShouldExportUsersDatabaseWithoutPlaintextPasswords(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		users, err := ExportUsersDatabase(path)
+		require.NoError(t, err)
+
+		require.Len(t, users, 5)
+
+		assert.Equal(t, "bob", users[0].Username)
+		assert.Equal(t, "Bob Dylan", users[0].DisplayName)
+		assert.Equal(t, "bob.dylan@authelia.com", users[0].Email)
+		assert.Equal(t, []string{"dev"}, users[0].Groups)
+
+		for _, user := range users {
+			assert.Equal(t, "", user.Password)
+			assert.True(t, strings.HasPrefix(user.HashedPassword, "{CRYPT}$") || strings.HasPrefix(user.HashedPassword, "$"))
+		}
+	})
+}
+
+func TestShouldImportUsersDatabaseAndReportInvalidEntries(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		users := []UserImportExportModel{
+			{Username: "alice", DisplayName: "Alice", Email: "alice@authelia.com", Groups: []string{"dev"}, Password: "password"},
+			{Username: "carol", DisplayName: "Carol", HashedPassword: "{CRYPT}$6$rounds=500000$jgiCMRyGXzoqpxS3$w2pJeZnnH8bwW3zzvoMWtTRfQYsHbWbD/hquuQ5vUeIyl9gdwBIt6RWk2S6afBA0DPakbeWgD/4SZPiS0hYtU/"},
+			{Username: "", DisplayName: "Nobody", Password: "password"},
+			{Username: "dave", DisplayName: "Dave", Email: "not-an-email", Password: "password"},
+			{Username: "erin", DisplayName: "Erin"},
+			{Username: "frank", DisplayName: "Frank", HashedPassword: "not-a-hash"},
+			{Username: "john", DisplayName: "John Doe", Password: "new-password"},
+			{Username: "alice", DisplayName: "Alice", Password: "password"},
+		}
+
+		imported, errs, err := ImportUsersDatabase(path, users, DefaultFileAuthenticationBackendConfiguration.Password, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, imported)
+		require.Len(t, errs, 6)
+
+		assert.EqualError(t, errs[0], "entry 3: username is required")
+		assert.EqualError(t, errs[1], "entry 4: user 'dave' has an invalid email 'not-an-email'")
+		assert.EqualError(t, errs[2], "entry 5: user 'erin' must have either a password or a hashed password")
+		assert.Contains(t, errs[3].Error(), "entry 6: user 'frank' has an invalid hashed password: ")
+		assert.EqualError(t, errs[4], "entry 7: user 'john' already exists")
+		assert.EqualError(t, errs[5], "entry 8: user 'alice' is specified more than once")
+
+		database, err := readDatabase(path)
+		require.NoError(t, err)
+
+		assert.Len(t, database.Users, 7)
+
+		ok, err := CheckPassword("password", database.Users["alice"].HashedPassword)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		assert.Equal(t, users[1].HashedPassword, database.Users["carol"].HashedPassword)
+
+		ok, err = CheckPassword("new-password", database.Users["john"].HashedPassword)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestShouldImportUsersDatabaseOverwriteAndCreate(t *testing.T) {
+	dir, err := os.MkdirTemp("", "authelia-users")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "users.yml")
+
+	users := []UserImportExportModel{
+		{Username: "john", DisplayName: "John Doe", Password: "password"},
+	}
+
+	imported, errs, err := ImportUsersDatabase(path, users, DefaultFileAuthenticationBackendConfiguration.Password, false)
+	require.NoError(t, err)
+	assert.Len(t, errs, 0)
+	assert.Equal(t, 1, imported)
+
+	users[0].DisplayName = "John Smith"
+
+	imported, errs, err = ImportUsersDatabase(path, users, DefaultFileAuthenticationBackendConfiguration.Password, true)
+	require.NoError(t, err)
+	assert.Len(t, errs, 0)
+	assert.Equal(t, 1, imported)
+
+	database, err := readDatabase(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "John Smith", database.Users["john"].DisplayName)
+}
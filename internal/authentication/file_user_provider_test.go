@@ -1,6 +1,7 @@
 package authentication
 
 import (
+	"context"
 	"log"
 	"os"
 	"runtime"
@@ -59,6 +60,20 @@ func TestShouldErrorAndGenerateUserDB(t *testing.T) {
 	require.EqualError(t, errors[2], "Generated database at: ./nonexistent.yml")
 }
 
+func TestShouldHealthCheckFileUserProvider(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+		provider := NewFileUserProvider(&config)
+
+		assert.NoError(t, provider.HealthCheck(context.Background()))
+
+		config.Path = path + ".missing"
+
+		assert.True(t, os.IsNotExist(provider.HealthCheck(context.Background())))
+	})
+}
+
 func TestShouldCheckUserArgon2idPasswordIsCorrect(t *testing.T) {
 	WithDatabase(UserDatabaseContent, func(path string) {
 		config := DefaultFileAuthenticationBackendConfiguration
@@ -148,6 +163,74 @@ func TestShouldUpdatePassword(t *testing.T) {
 	})
 }
 
+func TestShouldAddUser(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+		provider := NewFileUserProvider(&config)
+
+		hash, err := provider.HashPassword("newpassword")
+		require.NoError(t, err)
+
+		err = provider.AddUser("jane", UserDetails{DisplayName: "Jane Doe", Emails: []string{"jane.doe@authelia.com"}, Groups: []string{"dev"}}, hash)
+		assert.NoError(t, err)
+
+		err = provider.AddUser("john", UserDetails{DisplayName: "John Doe"}, hash)
+		assert.Equal(t, ErrUserAlreadyExists, err)
+
+		// Reset the provider to force a read from disk.
+		provider = NewFileUserProvider(&config)
+		ok, err := provider.CheckUserPassword("jane", "newpassword")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		details, err := provider.GetDetails("jane")
+		assert.NoError(t, err)
+		assert.Equal(t, "Jane Doe", details.DisplayName)
+		assert.Equal(t, []string{"jane.doe@authelia.com"}, details.Emails)
+		assert.Equal(t, []string{"dev"}, details.Groups)
+	})
+}
+
+func TestShouldDisableUser(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+		provider := NewFileUserProvider(&config)
+
+		disabled, err := provider.IsUserDisabled("john")
+		assert.NoError(t, err)
+		assert.False(t, disabled)
+
+		assert.NoError(t, provider.SetUserDisabled("john", true))
+		assert.Equal(t, ErrUserNotFound, provider.SetUserDisabled("fake", true))
+
+		// Reset the provider to force a read from disk.
+		provider = NewFileUserProvider(&config)
+
+		disabled, err = provider.IsUserDisabled("john")
+		assert.NoError(t, err)
+		assert.True(t, disabled)
+
+		disabled, err = IsUserDisabled(provider, "harry")
+		assert.NoError(t, err)
+		assert.False(t, disabled)
+
+		_, err = provider.IsUserDisabled("fake")
+		assert.Equal(t, ErrUserNotFound, err)
+
+		disabled, err = IsUserDisabled(provider, "fake")
+		assert.NoError(t, err)
+		assert.False(t, disabled)
+
+		assert.NoError(t, provider.SetUserDisabled("john", false))
+
+		disabled, err = provider.IsUserDisabled("john")
+		assert.NoError(t, err)
+		assert.False(t, disabled)
+	})
+}
+
 // Checks both that the hashing algo changes and that it removes {CRYPT} from the start.
 func TestShouldUpdatePasswordHashingAlgorithmToArgon2id(t *testing.T) {
 	WithDatabase(UserDatabaseContent, func(path string) {
@@ -212,7 +295,7 @@ func TestShouldRaiseWhenLoadingDatabaseWithBadSHA512HashesForTheFirstTime(t *tes
 	WithDatabase(BadSHA512HashContent, func(path string) {
 		config := DefaultFileAuthenticationBackendConfiguration
 		config.Path = path
-		assert.PanicsWithError(t, "Unable to parse hash of user john: Hash key is not the last parameter, the hash is likely malformed ($6$rounds00000$jgiCMRyGXzoqpxS3$w2pJeZnnH8bwW3zzvoMWtTRfQYsHbWbD/hquuQ5vUeIyl9gdwBIt6RWk2S6afBA0DPakbeWgD/4SZPiS0hYtU/)", func() {
+		assert.PanicsWithError(t, "Unable to parse hash of user 'john': Hash key is not the last parameter, the hash is likely malformed ($6$rounds00000$jgiCMRyGXzoqpxS3$w2pJeZnnH8bwW3zzvoMWtTRfQYsHbWbD/hquuQ5vUeIyl9gdwBIt6RWk2S6afBA0DPakbeWgD/4SZPiS0hYtU/)", func() {
 			NewFileUserProvider(&config)
 		})
 	})
@@ -222,7 +305,7 @@ func TestShouldRaiseWhenLoadingDatabaseWithBadArgon2idHashSettingsForTheFirstTim
 	WithDatabase(BadArgon2idHashSettingsContent, func(path string) {
 		config := DefaultFileAuthenticationBackendConfiguration
 		config.Path = path
-		assert.PanicsWithError(t, "Unable to parse hash of user john: Hash key is not the last parameter, the hash is likely malformed ($argon2id$v=19$m65536,t3,p2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM)", func() {
+		assert.PanicsWithError(t, "Unable to parse hash of user 'john': Hash key is not the last parameter, the hash is likely malformed ($argon2id$v=19$m65536,t3,p2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM)", func() {
 			NewFileUserProvider(&config)
 		})
 	})
@@ -232,7 +315,7 @@ func TestShouldRaiseWhenLoadingDatabaseWithBadArgon2idHashKeyForTheFirstTime(t *
 	WithDatabase(BadArgon2idHashKeyContent, func(path string) {
 		config := DefaultFileAuthenticationBackendConfiguration
 		config.Path = path
-		assert.PanicsWithError(t, "Unable to parse hash of user john: Hash key contains invalid base64 characters", func() {
+		assert.PanicsWithError(t, "Unable to parse hash of user 'john': Hash key contains invalid base64 characters", func() {
 			NewFileUserProvider(&config)
 		})
 	})
@@ -242,7 +325,7 @@ func TestShouldRaiseWhenLoadingDatabaseWithBadArgon2idHashSaltForTheFirstTime(t
 	WithDatabase(BadArgon2idHashSaltContent, func(path string) {
 		config := DefaultFileAuthenticationBackendConfiguration
 		config.Path = path
-		assert.PanicsWithError(t, "Unable to parse hash of user john: Salt contains invalid base64 characters", func() {
+		assert.PanicsWithError(t, "Unable to parse hash of user 'john': Salt contains invalid base64 characters", func() {
 			NewFileUserProvider(&config)
 		})
 	})
@@ -3,11 +3,15 @@ package authentication
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-ldap/ldap/v3"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/text/encoding/unicode"
 
@@ -26,6 +30,9 @@ type LDAPUserProvider struct {
 
 	disableResetPassword bool
 
+	// Limits the number of concurrent operations, nil when unlimited.
+	semaphore chan struct{}
+
 	// Automatically detected ldap features.
 	supportExtensionPasswdModify bool
 
@@ -77,12 +84,50 @@ func newLDAPUserProvider(configuration schema.LDAPAuthenticationBackendConfigura
 		disableResetPassword: disableResetPassword,
 	}
 
+	if configuration.MaxConcurrency > 0 {
+		provider.semaphore = make(chan struct{}, configuration.MaxConcurrency)
+	}
+
 	provider.parseDynamicUsersConfiguration()
 	provider.parseDynamicGroupsConfiguration()
 
 	return provider
 }
 
+// acquire waits for the concurrency limit to allow another operation, queueing the operation for at most the queue
+// timeout. The returned release function must be called once the operation has completed.
+func (p *LDAPUserProvider) acquire() (release func(), err error) {
+	if p.semaphore == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case p.semaphore <- struct{}{}:
+		return p.release, nil
+	default:
+	}
+
+	ldapExpvars.Add(ldapExpvarQueued, 1)
+	p.log.Debugf("LDAP concurrency limit of %d operations reached, queueing the operation", cap(p.semaphore))
+
+	timer := time.NewTimer(p.configuration.QueueTimeout)
+	defer timer.Stop()
+
+	select {
+	case p.semaphore <- struct{}{}:
+		return p.release, nil
+	case <-timer.C:
+		ldapExpvars.Add(ldapExpvarRejected, 1)
+		p.log.Warnf("LDAP concurrency limit of %d operations reached and the operation was queued for more than %s, rejecting the operation", cap(p.semaphore), p.configuration.QueueTimeout)
+
+		return nil, ErrBackendBusy
+	}
+}
+
+func (p *LDAPUserProvider) release() {
+	<-p.semaphore
+}
+
 func (p *LDAPUserProvider) connect(userDN string, password string) (LDAPConnection, error) {
 	conn, err := p.connectionFactory.DialURL(p.configuration.URL, p.dialOpts...)
 	if err != nil {
@@ -104,6 +149,12 @@ func (p *LDAPUserProvider) connect(userDN string, password string) (LDAPConnecti
 
 // CheckUserPassword checks if provided password matches for the given user.
 func (p *LDAPUserProvider) CheckUserPassword(inputUsername string, password string) (bool, error) {
+	release, err := p.acquire()
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
 	conn, err := p.connect(p.configuration.User, p.configuration.Password)
 	if err != nil {
 		return false, err
@@ -117,13 +168,46 @@ func (p *LDAPUserProvider) CheckUserPassword(inputUsername string, password stri
 
 	userConn, err := p.connect(profile.DN, password)
 	if err != nil {
+		if p.configuration.PasswordExpiration.Enabled {
+			if expirationErr := ldapGetBindExpirationError(err); expirationErr != nil {
+				return false, expirationErr
+			}
+		}
+
 		return false, fmt.Errorf("authentication failed. Cause: %w", err)
 	}
 	defer userConn.Close()
 
+	if p.configuration.PasswordExpiration.Enabled {
+		if err = profile.checkExpiration(time.Now()); err != nil {
+			return false, err
+		}
+	}
+
 	return true, nil
 }
 
+// ldapGetBindExpirationError returns the expiration error matching the data code Active Directory includes in the
+// diagnostic message of a failed bind, or nil if the bind failed for another reason.
+func ldapGetBindExpirationError(err error) error {
+	var ldapErr *ldap.Error
+
+	if !errors.As(err, &ldapErr) || ldapErr.ResultCode != ldap.LDAPResultInvalidCredentials || ldapErr.Err == nil {
+		return nil
+	}
+
+	switch message := ldapErr.Err.Error(); {
+	case strings.Contains(message, ldapActiveDirectoryDataAccountExpired):
+		return ErrAccountExpired
+	case strings.Contains(message, ldapActiveDirectoryDataPasswordMustChange):
+		return ErrPasswordMustChange
+	case strings.Contains(message, ldapActiveDirectoryDataPasswordExpired):
+		return ErrPasswordExpired
+	default:
+		return nil
+	}
+}
+
 func (p *LDAPUserProvider) ldapEscape(inputUsername string) string {
 	inputUsername = ldap.EscapeFilter(inputUsername)
 	for _, c := range specialLDAPRunes {
@@ -138,6 +222,26 @@ type ldapUserProfile struct {
 	Emails      []string
 	DisplayName string
 	Username    string
+	Subject     uuid.UUID
+
+	AccountExpires     time.Time
+	PasswordExpires    time.Time
+	PasswordMustChange bool
+}
+
+// checkExpiration returns an error if the account or the password of the user has expired at the provided time, or
+// if the user must change their password.
+func (profile *ldapUserProfile) checkExpiration(now time.Time) error {
+	switch {
+	case !profile.AccountExpires.IsZero() && !now.Before(profile.AccountExpires):
+		return ErrAccountExpired
+	case profile.PasswordMustChange:
+		return ErrPasswordMustChange
+	case !profile.PasswordExpires.IsZero() && !now.Before(profile.PasswordExpires):
+		return ErrPasswordExpired
+	default:
+		return nil
+	}
 }
 
 func (p *LDAPUserProvider) resolveUsersFilter(inputUsername string) (filter string) {
@@ -196,15 +300,108 @@ func (p *LDAPUserProvider) getUserProfile(conn LDAPConnection, inputUsername str
 
 			userProfile.Username = attr.Values[0]
 		}
+
+		if p.configuration.SubjectAttribute != "" && attr.Name == p.configuration.SubjectAttribute {
+			if userProfile.Subject, err = ldapGetSubject(attr); err != nil {
+				return nil, fmt.Errorf("user '%s' has an invalid value for attribute '%s': %w",
+					inputUsername, p.configuration.SubjectAttribute, err)
+			}
+		}
+
+		if p.configuration.PasswordExpiration.Enabled {
+			if err = p.setUserProfileExpiration(&userProfile, attr); err != nil {
+				return nil, fmt.Errorf("user '%s' has an invalid value for attribute '%s': %w",
+					inputUsername, attr.Name, err)
+			}
+		}
 	}
 
 	if userProfile.DN == "" {
-		return nil, fmt.Errorf("no DN has been found for user %s", inputUsername)
+		return nil, fmt.Errorf("no DN has been found for user '%s'", inputUsername)
+	}
+
+	if p.configuration.SubjectAttribute != "" && userProfile.Subject == uuid.Nil {
+		return nil, fmt.Errorf("user '%s' must have a value for attribute '%s'",
+			inputUsername, p.configuration.SubjectAttribute)
 	}
 
 	return &userProfile, nil
 }
 
+// ldapGetSubject parses the value of a subject attribute. Binary values are parsed as a GUID in the byte order used by
+// Active Directory for the objectGUID attribute, so the subject matches the string representation shown by Microsoft
+// tools, otherwise the value is parsed as the string representation of a UUID like the entryUUID attribute of RFC4530.
+func ldapGetSubject(attr *ldap.EntryAttribute) (subject uuid.UUID, err error) {
+	if len(attr.ByteValues) != 1 {
+		return uuid.Nil, fmt.Errorf("the attribute must have exactly one value but it has %d", len(attr.ByteValues))
+	}
+
+	value := attr.ByteValues[0]
+
+	if len(value) == 16 {
+		copy(subject[:], []byte{
+			value[3], value[2], value[1], value[0],
+			value[5], value[4],
+			value[7], value[6],
+		})
+		copy(subject[8:], value[8:])
+
+		return subject, nil
+	}
+
+	if subject, err = uuid.ParseBytes(value); err != nil {
+		return uuid.Nil, err
+	}
+
+	return subject, nil
+}
+
+func (p *LDAPUserProvider) setUserProfileExpiration(profile *ldapUserProfile, attr *ldap.EntryAttribute) (err error) {
+	config := p.configuration.PasswordExpiration
+
+	switch attr.Name {
+	case "":
+		break
+	case config.AccountExpiresAttribute:
+		profile.AccountExpires, err = ldapGetTime(attr)
+	case config.PasswordExpiresAttribute:
+		profile.PasswordExpires, err = ldapGetTime(attr)
+	case config.PasswordMustChangeAttribute:
+		profile.PasswordMustChange = len(attr.Values) != 0 && (attr.Values[0] == "0" || strings.EqualFold(attr.Values[0], "TRUE"))
+	}
+
+	return err
+}
+
+// ldapGetTime parses the value of a time attribute. Numeric values are parsed as an Active Directory file time where
+// 0 and the maximum value mean the time never occurs, otherwise the value is parsed as a generalized time. The zero
+// time is returned when the time never occurs.
+func ldapGetTime(attr *ldap.EntryAttribute) (t time.Time, err error) {
+	if len(attr.Values) != 1 {
+		return t, fmt.Errorf("the attribute must have exactly one value but it has %d", len(attr.Values))
+	}
+
+	value := attr.Values[0]
+
+	if fileTime, parseErr := strconv.ParseInt(value, 10, 64); parseErr == nil {
+		if fileTime == 0 || fileTime == ldapFileTimeNever {
+			return t, nil
+		}
+
+		fileTime -= ldapFileTimeUnixEpoch
+
+		return time.Unix(fileTime/10000000, fileTime%10000000*100).UTC(), nil
+	}
+
+	for _, layout := range []string{"20060102150405Z0700", "20060102150405.999999999Z0700"} {
+		if t, err = time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return t, fmt.Errorf("the value '%s' is neither a file time nor a generalized time", value)
+}
+
 func (p *LDAPUserProvider) resolveGroupsFilter(inputUsername string, profile *ldapUserProfile) (filter string, err error) { //nolint:unparam
 	filter = p.configuration.GroupsFilter
 
@@ -230,6 +427,12 @@ func (p *LDAPUserProvider) resolveGroupsFilter(inputUsername string, profile *ld
 
 // GetDetails retrieve the groups a user belongs to.
 func (p *LDAPUserProvider) GetDetails(inputUsername string) (*UserDetails, error) {
+	release, err := p.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	conn, err := p.connect(p.configuration.User, p.configuration.Password)
 	if err != nil {
 		return nil, err
@@ -262,7 +465,7 @@ func (p *LDAPUserProvider) GetDetails(inputUsername string) (*UserDetails, error
 
 	for _, res := range sr.Entries {
 		if len(res.Attributes) == 0 {
-			p.log.Warningf("No groups retrieved from LDAP for user %s", inputUsername)
+			p.log.Warningf("No groups retrieved from LDAP for user '%s'", inputUsername)
 			break
 		}
 
@@ -275,11 +478,18 @@ func (p *LDAPUserProvider) GetDetails(inputUsername string) (*UserDetails, error
 		DisplayName: profile.DisplayName,
 		Emails:      profile.Emails,
 		Groups:      groups,
+		Subject:     profile.Subject,
 	}, nil
 }
 
 // UpdatePassword update the password of the given user.
 func (p *LDAPUserProvider) UpdatePassword(inputUsername string, newPassword string) error {
+	release, err := p.acquire()
+	if err != nil {
+		return fmt.Errorf("unable to update password. Cause: %w", err)
+	}
+	defer release()
+
 	conn, err := p.connect(p.configuration.User, p.configuration.Password)
 	if err != nil {
 		return fmt.Errorf("unable to update password. Cause: %w", err)
@@ -1,6 +1,7 @@
 package authentication
 
 import (
+	"context"
 	"strings"
 
 	"github.com/go-ldap/ldap/v3"
@@ -8,6 +9,24 @@ import (
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 )
 
+// HealthCheck implements the health check provider interface by binding with the configured user.
+func (p *LDAPUserProvider) HealthCheck(_ context.Context) (err error) {
+	release, err := p.acquire()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	conn, err := p.connect(p.configuration.User, p.configuration.Password)
+	if err != nil {
+		return err
+	}
+
+	conn.Close()
+
+	return nil
+}
+
 // StartupCheck implements the startup check provider interface.
 func (p *LDAPUserProvider) StartupCheck() (err error) {
 	conn, err := p.connect(p.configuration.User, p.configuration.Password)
@@ -68,6 +87,22 @@ func (p *LDAPUserProvider) parseDynamicUsersConfiguration() {
 		p.configuration.UsernameAttribute,
 	}
 
+	if p.configuration.SubjectAttribute != "" {
+		p.usersAttributes = append(p.usersAttributes, p.configuration.SubjectAttribute)
+	}
+
+	if p.configuration.PasswordExpiration.Enabled {
+		for _, attribute := range []string{
+			p.configuration.PasswordExpiration.AccountExpiresAttribute,
+			p.configuration.PasswordExpiration.PasswordExpiresAttribute,
+			p.configuration.PasswordExpiration.PasswordMustChangeAttribute,
+		} {
+			if attribute != "" {
+				p.usersAttributes = append(p.usersAttributes, attribute)
+			}
+		}
+	}
+
 	if p.configuration.AdditionalUsersDN != "" {
 		p.usersBaseDN = p.configuration.AdditionalUsersDN + "," + p.configuration.BaseDN
 	} else {
@@ -2,8 +2,11 @@ package authentication
 
 import (
 	"errors"
+	"expvar"
 	"fmt"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/go-ldap/ldap/v3"
 	"github.com/golang/mock/gomock"
@@ -659,6 +662,177 @@ func TestShouldReturnUsernameFromLDAP(t *testing.T) {
 	assert.Equal(t, details.Username, "John")
 }
 
+func TestShouldReturnSubjectFromLDAP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPConnectionFactory(ctrl)
+	mockConn := NewMockLDAPConnection(ctrl)
+
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			SubjectAttribute:     "objectGUID",
+			UsersFilter:          "uid={input}",
+			AdditionalUsersDN:    "ou=users",
+			BaseDN:               "dc=example,dc=com",
+		},
+		false,
+		nil,
+		mockFactory)
+
+	assert.Equal(t, []string{"displayName", "mail", "uid", "objectGUID"}, ldapClient.usersAttributes)
+
+	dialURL := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockConn, nil)
+
+	connBind := mockConn.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	connClose := mockConn.EXPECT().Close()
+
+	searchGroups := mockConn.EXPECT().
+		Search(gomock.Any()).
+		Return(createSearchResultWithAttributeValues("group1", "group2"), nil)
+
+	searchProfile := mockConn.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{
+			Entries: []*ldap.Entry{
+				{
+					DN: "uid=test,dc=example,dc=com",
+					Attributes: []*ldap.EntryAttribute{
+						{
+							Name:   "uid",
+							Values: []string{"John"},
+						},
+						{
+							Name: "objectGUID",
+							ByteValues: [][]byte{
+								{0xff, 0x19, 0x96, 0x6f, 0x86, 0x8b, 0x11, 0xd0, 0xb4, 0x2d, 0x00, 0xc0, 0x4f, 0xc9, 0x64, 0xff},
+							},
+						},
+					},
+				},
+			},
+		}, nil)
+
+	gomock.InOrder(dialURL, connBind, searchProfile, searchGroups, connClose)
+
+	details, err := ldapClient.GetDetails("john")
+	require.NoError(t, err)
+
+	assert.Equal(t, "John", details.Username)
+	assert.Equal(t, "6f9619ff-8b86-d011-b42d-00c04fc964ff", details.Subject.String())
+}
+
+func TestShouldReturnErrorWhenSubjectIsMissingFromLDAP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPConnectionFactory(ctrl)
+	mockConn := NewMockLDAPConnection(ctrl)
+
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			SubjectAttribute:     "entryUUID",
+			UsersFilter:          "uid={input}",
+			AdditionalUsersDN:    "ou=users",
+			BaseDN:               "dc=example,dc=com",
+		},
+		false,
+		nil,
+		mockFactory)
+
+	dialURL := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockConn, nil)
+
+	connBind := mockConn.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	connClose := mockConn.EXPECT().Close()
+
+	searchProfile := mockConn.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{
+			Entries: []*ldap.Entry{
+				{
+					DN: "uid=test,dc=example,dc=com",
+					Attributes: []*ldap.EntryAttribute{
+						{
+							Name:   "uid",
+							Values: []string{"John"},
+						},
+					},
+				},
+			},
+		}, nil)
+
+	gomock.InOrder(dialURL, connBind, searchProfile, connClose)
+
+	details, err := ldapClient.GetDetails("john")
+	assert.Nil(t, details)
+	assert.EqualError(t, err, "user 'john' must have a value for attribute 'entryUUID'")
+}
+
+func TestLDAPGetSubject(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     [][]byte
+		expected string
+		err      string
+	}{
+		{
+			name:     "ShouldParseBinaryGUID",
+			have:     [][]byte{{0xff, 0x19, 0x96, 0x6f, 0x86, 0x8b, 0x11, 0xd0, 0xb4, 0x2d, 0x00, 0xc0, 0x4f, 0xc9, 0x64, 0xff}},
+			expected: "6f9619ff-8b86-d011-b42d-00c04fc964ff",
+		},
+		{
+			name:     "ShouldParseStringUUID",
+			have:     [][]byte{[]byte("597ae2f6-16a6-1027-98f4-ab46f1f1a6d7")},
+			expected: "597ae2f6-16a6-1027-98f4-ab46f1f1a6d7",
+		},
+		{
+			name: "ShouldRaiseErrorOnMultipleValues",
+			have: [][]byte{[]byte("597ae2f6-16a6-1027-98f4-ab46f1f1a6d7"), []byte("6f9619ff-8b86-d011-b42d-00c04fc964ff")},
+			err:  "the attribute must have exactly one value but it has 2",
+		},
+		{
+			name: "ShouldRaiseErrorOnInvalidValue",
+			have: [][]byte{[]byte("john")},
+			err:  "invalid UUID length: 4",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			subject, err := ldapGetSubject(&ldap.EntryAttribute{Name: "objectGUID", ByteValues: tc.have})
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, subject.String())
+			}
+		})
+	}
+}
+
 func TestShouldUpdateUserPasswordPasswdModifyExtension(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1108,6 +1282,254 @@ func TestShouldCheckInvalidUserPassword(t *testing.T) {
 	require.EqualError(t, err, "authentication failed. Cause: invalid username or password")
 }
 
+func TestShouldReturnExpirationErrorFromActiveDirectoryBind(t *testing.T) {
+	testCases := []struct {
+		name     string
+		message  string
+		expected error
+	}{
+		{"ShouldReturnPasswordExpired", "80090308: LdapErr: DSID-0C09044E, comment: AcceptSecurityContext error, data 532, v2580", ErrPasswordExpired},
+		{"ShouldReturnAccountExpired", "80090308: LdapErr: DSID-0C09044E, comment: AcceptSecurityContext error, data 701, v2580", ErrAccountExpired},
+		{"ShouldReturnPasswordMustChange", "80090308: LdapErr: DSID-0C09044E, comment: AcceptSecurityContext error, data 773, v2580", ErrPasswordMustChange},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFactory := NewMockLDAPConnectionFactory(ctrl)
+			mockConn := NewMockLDAPConnection(ctrl)
+
+			ldapClient := newLDAPUserProvider(
+				schema.LDAPAuthenticationBackendConfiguration{
+					URL:                  "ldap://127.0.0.1:389",
+					User:                 "cn=admin,dc=example,dc=com",
+					Password:             "password",
+					UsernameAttribute:    "sAMAccountName",
+					MailAttribute:        "mail",
+					DisplayNameAttribute: "displayName",
+					UsersFilter:          "sAMAccountName={input}",
+					BaseDN:               "dc=example,dc=com",
+					PasswordExpiration: schema.LDAPPasswordExpirationConfiguration{
+						Enabled: true,
+					},
+				},
+				false,
+				nil,
+				mockFactory)
+
+			gomock.InOrder(
+				mockFactory.EXPECT().
+					DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+					Return(mockConn, nil),
+				mockConn.EXPECT().
+					Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+					Return(nil),
+				mockConn.EXPECT().
+					Search(gomock.Any()).
+					Return(&ldap.SearchResult{
+						Entries: []*ldap.Entry{
+							{
+								DN: "cn=john,dc=example,dc=com",
+								Attributes: []*ldap.EntryAttribute{
+									{
+										Name:   "sAMAccountName",
+										Values: []string{"john"},
+									},
+								},
+							},
+						},
+					}, nil),
+				mockFactory.EXPECT().
+					DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+					Return(mockConn, nil),
+				mockConn.EXPECT().
+					Bind(gomock.Eq("cn=john,dc=example,dc=com"), gomock.Eq("password")).
+					Return(ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New(tc.message))),
+				mockConn.EXPECT().Close(),
+			)
+
+			valid, err := ldapClient.CheckUserPassword("john", "password")
+
+			assert.False(t, valid)
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestLDAPGetBindExpirationError(t *testing.T) {
+	err := ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("AcceptSecurityContext error, data 532, v2580"))
+
+	assert.Nil(t, ldapGetBindExpirationError(errors.New("data 532")))
+	assert.Nil(t, ldapGetBindExpirationError(ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("AcceptSecurityContext error, data 52e, v2580"))))
+	assert.Nil(t, ldapGetBindExpirationError(ldap.NewError(ldap.LDAPResultBusy, errors.New("data 532"))))
+	assert.Equal(t, ErrPasswordExpired, ldapGetBindExpirationError(fmt.Errorf("bind: %w", err)))
+}
+
+func TestShouldReturnExpirationErrorFromAttributes(t *testing.T) {
+	past := strconv.FormatInt(time.Now().Add(-time.Hour).Unix()*10000000+ldapFileTimeUnixEpoch, 10)
+	future := strconv.FormatInt(time.Now().Add(time.Hour).Unix()*10000000+ldapFileTimeUnixEpoch, 10)
+
+	testCases := []struct {
+		name               string
+		accountExpires     string
+		passwordExpires    string
+		passwordMustChange string
+		expected           error
+	}{
+		{"ShouldAllowValidPassword", "0", future, "132930000000000000", nil},
+		{"ShouldAllowPasswordWhichNeverExpires", "9223372036854775807", "9223372036854775807", "132930000000000000", nil},
+		{"ShouldReturnAccountExpired", past, future, "132930000000000000", ErrAccountExpired},
+		{"ShouldReturnPasswordExpired", "0", past, "132930000000000000", ErrPasswordExpired},
+		{"ShouldReturnPasswordExpiredGeneralizedTime", "0", "20200101000000Z", "FALSE", ErrPasswordExpired},
+		{"ShouldReturnPasswordMustChange", "0", future, "0", ErrPasswordMustChange},
+		{"ShouldReturnPasswordMustChangeBoolean", "0", future, "TRUE", ErrPasswordMustChange},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFactory := NewMockLDAPConnectionFactory(ctrl)
+			mockConn := NewMockLDAPConnection(ctrl)
+
+			ldapClient := newLDAPUserProvider(
+				schema.LDAPAuthenticationBackendConfiguration{
+					URL:                  "ldap://127.0.0.1:389",
+					User:                 "cn=admin,dc=example,dc=com",
+					Password:             "password",
+					UsernameAttribute:    "uid",
+					MailAttribute:        "mail",
+					DisplayNameAttribute: "displayName",
+					UsersFilter:          "uid={input}",
+					BaseDN:               "dc=example,dc=com",
+					PasswordExpiration: schema.LDAPPasswordExpirationConfiguration{
+						Enabled:                     true,
+						AccountExpiresAttribute:     "accountExpires",
+						PasswordExpiresAttribute:    "passwordExpires",
+						PasswordMustChangeAttribute: "pwdReset",
+					},
+				},
+				false,
+				nil,
+				mockFactory)
+
+			assert.Equal(t, []string{"displayName", "mail", "uid", "accountExpires", "passwordExpires", "pwdReset"}, ldapClient.usersAttributes)
+
+			gomock.InOrder(
+				mockFactory.EXPECT().
+					DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+					Return(mockConn, nil),
+				mockConn.EXPECT().
+					Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+					Return(nil),
+				mockConn.EXPECT().
+					Search(gomock.Any()).
+					Return(&ldap.SearchResult{
+						Entries: []*ldap.Entry{
+							{
+								DN: "uid=john,dc=example,dc=com",
+								Attributes: []*ldap.EntryAttribute{
+									{
+										Name:   "uid",
+										Values: []string{"john"},
+									},
+									{
+										Name:   "accountExpires",
+										Values: []string{tc.accountExpires},
+									},
+									{
+										Name:   "passwordExpires",
+										Values: []string{tc.passwordExpires},
+									},
+									{
+										Name:   "pwdReset",
+										Values: []string{tc.passwordMustChange},
+									},
+								},
+							},
+						},
+					}, nil),
+				mockFactory.EXPECT().
+					DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+					Return(mockConn, nil),
+				mockConn.EXPECT().
+					Bind(gomock.Eq("uid=john,dc=example,dc=com"), gomock.Eq("password")).
+					Return(nil),
+				mockConn.EXPECT().Close().Times(2),
+			)
+
+			valid, err := ldapClient.CheckUserPassword("john", "password")
+
+			if tc.expected == nil {
+				assert.True(t, valid)
+				assert.NoError(t, err)
+			} else {
+				assert.False(t, valid)
+				assert.Equal(t, tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestLDAPGetTime(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     []string
+		expected time.Time
+		err      string
+	}{
+		{
+			name:     "ShouldParseFileTime",
+			have:     []string{"132223104000000000"},
+			expected: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "ShouldParseFileTimeNeverAsZero",
+			have: []string{"0"},
+		},
+		{
+			name: "ShouldParseFileTimeMaximumAsZero",
+			have: []string{"9223372036854775807"},
+		},
+		{
+			name:     "ShouldParseGeneralizedTime",
+			have:     []string{"20200101000000Z"},
+			expected: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "ShouldParseGeneralizedTimeWithFraction",
+			have:     []string{"20200101000000.5Z"},
+			expected: time.Date(2020, time.January, 1, 0, 0, 0, 500000000, time.UTC),
+		},
+		{
+			name: "ShouldRaiseErrorOnMultipleValues",
+			have: []string{"0", "0"},
+			err:  "the attribute must have exactly one value but it has 2",
+		},
+		{
+			name: "ShouldRaiseErrorOnInvalidValue",
+			have: []string{"yesterday"},
+			err:  "the value 'yesterday' is neither a file time nor a generalized time",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ldapGetTime(&ldap.EntryAttribute{Name: "accountExpires", Values: tc.have})
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, tc.expected.Equal(actual), "expected %s but got %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
 func TestShouldCallStartTLSWhenEnabled(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -1344,3 +1766,99 @@ func TestShouldReturnLDAPSAlreadySecuredWhenStartTLSAttempted(t *testing.T) {
 	_, err := ldapClient.GetDetails("john")
 	assert.EqualError(t, err, "LDAP Result Code 200 \"Network Error\": ldap: already encrypted")
 }
+
+func TestShouldRejectOperationsWhenConcurrencyLimitIsReached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The factory has no expectations so any connection attempt fails the test.
+	mockFactory := NewMockLDAPConnectionFactory(ctrl)
+
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL:            "ldap://127.0.0.1:389",
+			MaxConcurrency: 1,
+			QueueTimeout:   time.Millisecond * 10,
+		},
+		false,
+		nil,
+		mockFactory)
+
+	release, err := ldapClient.acquire()
+	require.NoError(t, err)
+
+	rejected := ldapExpvarInt(ldapExpvarRejected)
+
+	_, err = ldapClient.CheckUserPassword("john", "password")
+	assert.ErrorIs(t, err, ErrBackendBusy)
+
+	_, err = ldapClient.GetDetails("john")
+	assert.ErrorIs(t, err, ErrBackendBusy)
+
+	err = ldapClient.UpdatePassword("john", "password")
+	assert.ErrorIs(t, err, ErrBackendBusy)
+
+	assert.Equal(t, rejected+3, ldapExpvarInt(ldapExpvarRejected))
+
+	release()
+
+	release, err = ldapClient.acquire()
+	require.NoError(t, err)
+
+	release()
+}
+
+func TestShouldQueueOperationsUntilConcurrencyLimitAllows(t *testing.T) {
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL:            "ldap://127.0.0.1:389",
+			MaxConcurrency: 1,
+			QueueTimeout:   time.Second * 5,
+		},
+		false,
+		nil,
+		nil)
+
+	release, err := ldapClient.acquire()
+	require.NoError(t, err)
+
+	queued := ldapExpvarInt(ldapExpvarQueued)
+
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		release()
+	}()
+
+	release, err = ldapClient.acquire()
+	require.NoError(t, err)
+
+	release()
+
+	assert.Equal(t, queued+1, ldapExpvarInt(ldapExpvarQueued))
+}
+
+func TestShouldNotLimitConcurrencyByDefault(t *testing.T) {
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL: "ldap://127.0.0.1:389",
+		},
+		false,
+		nil,
+		nil)
+
+	assert.Nil(t, ldapClient.semaphore)
+
+	for i := 0; i < 10; i++ {
+		_, err := ldapClient.acquire()
+		require.NoError(t, err)
+	}
+}
+
+func ldapExpvarInt(key string) int64 {
+	value, ok := ldapExpvars.Get(key).(*expvar.Int)
+	if !ok {
+		return 0
+	}
+
+	return value.Value()
+}
@@ -0,0 +1,44 @@
+package authentication
+
+import (
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ResolvePasswordMemory returns the argon2id memory parameter in MB. When the memory is configured as a percentage of
+// the available system memory it's resolved from the memory currently available to the process and clamped to the
+// configured minimum and maximum. If the available memory can't be determined the minimum is returned with the error.
+// Existing hashes are unaffected as they're always verified with the parameters encoded in the hash.
+func ResolvePasswordMemory(config *schema.PasswordConfiguration) (memory int, err error) {
+	if config.MemoryPercentage == 0 {
+		return config.Memory, nil
+	}
+
+	minimum, maximum := config.MemoryMinimum, config.MemoryMaximum
+
+	if minimum == 0 {
+		minimum = config.Parallelism * 8
+	}
+
+	if maximum == 0 {
+		maximum = config.Memory
+	}
+
+	available, err := utils.AvailableMemory()
+	if err != nil {
+		return minimum, err
+	}
+
+	return clampPasswordMemory(int(available/1024/1024*uint64(config.MemoryPercentage)/100), minimum, maximum), nil
+}
+
+func clampPasswordMemory(memory, minimum, maximum int) int {
+	switch {
+	case memory < minimum:
+		return minimum
+	case memory > maximum:
+		return maximum
+	default:
+		return memory
+	}
+}
@@ -0,0 +1,34 @@
+package authentication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldClampPasswordMemory(t *testing.T) {
+	assert.Equal(t, 64, clampPasswordMemory(32, 64, 1024))
+	assert.Equal(t, 512, clampPasswordMemory(512, 64, 1024))
+	assert.Equal(t, 1024, clampPasswordMemory(4096, 64, 1024))
+}
+
+func TestShouldNotResolvePasswordMemoryWithoutPercentage(t *testing.T) {
+	memory, err := ResolvePasswordMemory(&schema.PasswordConfiguration{Memory: 128, Parallelism: 8})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 128, memory)
+}
+
+func TestShouldResolvePasswordMemoryWithinBounds(t *testing.T) {
+	memory, err := ResolvePasswordMemory(&schema.PasswordConfiguration{Memory: 64, Parallelism: 1, MemoryPercentage: 100, MemoryMinimum: 8, MemoryMaximum: 16})
+	if err != nil {
+		assert.Equal(t, 8, memory)
+
+		return
+	}
+
+	assert.GreaterOrEqual(t, memory, 8)
+	assert.LessOrEqual(t, memory, 16)
+}
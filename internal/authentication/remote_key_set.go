@@ -0,0 +1,248 @@
+package authentication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+)
+
+// RemoteKeySet is a cache of a JSON Web Key Set fetched from a remote URL. The key set is considered fresh for the
+// lifespan indicated by the HTTP cache headers of the response, bounded by the configured lifespan. Once it's no longer
+// fresh it's refreshed in the background while the last fetched key set continues to be used, until it has been stale
+// for longer than the configured max stale duration.
+type RemoteKeySet struct {
+	name     string
+	url      string
+	lifespan time.Duration
+	maxStale time.Duration
+	maxSize  int64
+
+	client *http.Client
+
+	mutex      sync.Mutex
+	keys       *jose.JSONWebKeySet
+	etag       string
+	expires    time.Time
+	attempted  time.Time
+	refreshing bool
+
+	// wg tracks the background refreshes.
+	wg sync.WaitGroup
+}
+
+// NewRemoteKeySet creates a new RemoteKeySet. The name describes the key set in log messages. The key set is fetched
+// when the keys are first requested.
+func NewRemoteKeySet(name, url string, config schema.JWKSCacheConfiguration, client *http.Client) (keySet *RemoteKeySet) {
+	return &RemoteKeySet{
+		name:     name,
+		url:      url,
+		lifespan: config.Lifespan,
+		maxStale: config.MaxStale,
+		maxSize:  int64(config.MaxSize),
+		client:   client,
+	}
+}
+
+// Keys returns the cached key set, fetching it if it has never been fetched or if it has been stale for longer than the
+// max stale duration. A stale key set which is within the max stale duration is returned as is and refreshed in the
+// background.
+func (ks *RemoteKeySet) Keys(now time.Time) (keys *jose.JSONWebKeySet, err error) {
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+
+	switch {
+	case ks.keys == nil:
+		if err = ks.fetch(now); err != nil {
+			return nil, err
+		}
+	case now.Before(ks.expires):
+		break
+	case now.Before(ks.expires.Add(ks.maxStale)):
+		if !ks.refreshing && now.Sub(ks.attempted) >= remoteKeySetMinimumRefresh {
+			ks.refreshing = true
+			ks.attempted = now
+
+			ks.wg.Add(1)
+
+			go ks.refresh(now, ks.etag)
+		}
+	default:
+		if err = ks.fetch(now); err != nil {
+			return nil, fmt.Errorf("the JWKS has been stale for longer than %s: %w", ks.maxStale, err)
+		}
+	}
+
+	return ks.keys, nil
+}
+
+// Refresh fetches the key set immediately, which is used when a key is not in the cached key set. Fetches are rate
+// limited so the key set is returned as is if it was fetched or refreshed recently.
+func (ks *RemoteKeySet) Refresh(now time.Time) (keys *jose.JSONWebKeySet, err error) {
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+
+	if ks.keys != nil && (ks.refreshing || now.Sub(ks.attempted) < remoteKeySetMinimumRefresh) {
+		return ks.keys, nil
+	}
+
+	if err = ks.fetch(now); err != nil {
+		return nil, err
+	}
+
+	return ks.keys, nil
+}
+
+// fetch retrieves the key set while holding the lock.
+func (ks *RemoteKeySet) fetch(now time.Time) (err error) {
+	ks.attempted = now
+
+	keys, etag, lifespan, err := ks.retrieve(now, ks.etag)
+	if err != nil {
+		logging.Logger().Errorf("Unable to fetch the %s JWKS from '%s': %v", ks.name, ks.url, err)
+
+		return err
+	}
+
+	ks.store(now, keys, etag, lifespan)
+
+	return nil
+}
+
+// refresh retrieves the key set in the background without holding the lock while the request is in progress. The
+// last fetched key set continues to be used if the refresh fails.
+func (ks *RemoteKeySet) refresh(now time.Time, etag string) {
+	defer ks.wg.Done()
+
+	keys, etag, lifespan, err := ks.retrieve(now, etag)
+
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+
+	ks.refreshing = false
+
+	if err != nil {
+		logging.Logger().Errorf("Unable to refresh the %s JWKS from '%s', the last fetched JWKS will be used until %s: %v",
+			ks.name, ks.url, ks.expires.Add(ks.maxStale).Format(time.RFC3339), err)
+
+		return
+	}
+
+	ks.store(now, keys, etag, lifespan)
+}
+
+func (ks *RemoteKeySet) store(now time.Time, keys *jose.JSONWebKeySet, etag string, lifespan time.Duration) {
+	// A nil key set means the key set was not modified since it was last fetched.
+	if keys != nil {
+		ks.keys, ks.etag = keys, etag
+	}
+
+	ks.expires = now.Add(lifespan)
+}
+
+// retrieve performs the request for the key set. The returned key set is nil if the server responded that the key set
+// identified by the entity tag was not modified.
+func (ks *RemoteKeySet) retrieve(now time.Time, etag string) (keys *jose.JSONWebKeySet, etagNew string, lifespan time.Duration, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteKeySetTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	defer resp.Body.Close()
+
+	lifespan = ks.responseLifespan(resp, now)
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && etag != "":
+		return nil, etag, lifespan, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", 0, fmt.Errorf("the response status code was %d", resp.StatusCode)
+	case resp.ContentLength > ks.maxSize:
+		return nil, "", 0, fmt.Errorf("the JWKS exceeds the maximum size of %d bytes", ks.maxSize)
+	}
+
+	// The key set is decoded as it's read so large key sets are not buffered, but reading stops at the maximum size.
+	reader := &io.LimitedReader{R: resp.Body, N: ks.maxSize + 1}
+
+	keys = &jose.JSONWebKeySet{}
+
+	err = json.NewDecoder(reader).Decode(keys)
+
+	switch {
+	case reader.N <= 0:
+		return nil, "", 0, fmt.Errorf("the JWKS exceeds the maximum size of %d bytes", ks.maxSize)
+	case err != nil:
+		return nil, "", 0, fmt.Errorf("unable to decode the JWKS: %w", err)
+	}
+
+	return keys, resp.Header.Get("ETag"), lifespan, nil
+}
+
+// responseLifespan returns the lifespan of the response according to the Cache-Control and Expires headers, bounded by
+// the minimum refresh interval and the configured lifespan.
+func (ks *RemoteKeySet) responseLifespan(resp *http.Response, now time.Time) (lifespan time.Duration) {
+	lifespan = ks.lifespan
+
+	if maxAge, err := parseCacheControlMaxAge(resp.Header.Get("Cache-Control")); err == nil {
+		lifespan = maxAge
+	} else if errors.Is(err, errCacheControlNoCache) {
+		lifespan = 0
+	} else if expires, err := http.ParseTime(resp.Header.Get("Expires")); err == nil {
+		lifespan = expires.Sub(now)
+	}
+
+	switch {
+	case lifespan < remoteKeySetMinimumRefresh:
+		return remoteKeySetMinimumRefresh
+	case lifespan > ks.lifespan:
+		return ks.lifespan
+	default:
+		return lifespan
+	}
+}
+
+// parseCacheControlMaxAge parses the max-age directive of a Cache-Control header value.
+func parseCacheControlMaxAge(value string) (maxAge time.Duration, err error) {
+	for _, directive := range strings.Split(value, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+
+		switch {
+		case directive == "no-cache" || directive == "no-store":
+			return 0, errCacheControlNoCache
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.Atoi(strings.Trim(directive[len("max-age="):], `"`))
+			if err != nil || seconds < 0 {
+				return 0, errCacheControlNoMaxAge
+			}
+
+			return time.Duration(seconds) * time.Second, nil
+		}
+	}
+
+	return 0, errCacheControlNoMaxAge
+}
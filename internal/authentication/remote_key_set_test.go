@@ -0,0 +1,225 @@
+package authentication
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+type testRemoteKeySetServer struct {
+	server       *httptest.Server
+	key          *rsa.PrivateKey
+	kid          atomic.Value
+	status       int32
+	cacheControl string
+	etag         string
+	fetches      int32
+	revalidated  int32
+}
+
+func newTestRemoteKeySetServer(t *testing.T) *testRemoteKeySetServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	s := &testRemoteKeySetServer{key: key, status: http.StatusOK}
+	s.kid.Store("abc")
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.fetches, 1)
+
+		if status := int(atomic.LoadInt32(&s.status)); status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+
+		if s.cacheControl != "" {
+			w.Header().Set("Cache-Control", s.cacheControl)
+		}
+
+		if s.etag != "" {
+			w.Header().Set("ETag", s.etag)
+
+			if r.Header.Get("If-None-Match") == s.etag {
+				atomic.AddInt32(&s.revalidated, 1)
+				w.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+		}
+
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{{Key: &s.key.PublicKey, KeyID: s.kid.Load().(string), Algorithm: "RS256", Use: "sig"}},
+		})
+	}))
+
+	t.Cleanup(s.server.Close)
+
+	return s
+}
+
+func (s *testRemoteKeySetServer) keySet() *RemoteKeySet {
+	return NewRemoteKeySet("test", s.server.URL, schema.JWKSCacheConfiguration{
+		Lifespan: time.Hour,
+		MaxStale: time.Hour * 24,
+		MaxSize:  1024 * 1024,
+	}, s.server.Client())
+}
+
+func TestShouldCacheRemoteKeySet(t *testing.T) {
+	server := newTestRemoteKeySetServer(t)
+	keySet := server.keySet()
+	now := time.Now()
+
+	keys, err := keySet.Keys(now)
+	require.NoError(t, err)
+	assert.Len(t, keys.Key("abc"), 1)
+
+	keys, err = keySet.Keys(now.Add(time.Minute * 59))
+	require.NoError(t, err)
+	assert.Len(t, keys.Key("abc"), 1)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&server.fetches))
+}
+
+func TestShouldRefreshStaleRemoteKeySetInBackground(t *testing.T) {
+	server := newTestRemoteKeySetServer(t)
+	server.cacheControl = "public, max-age=120"
+	keySet := server.keySet()
+	now := time.Now()
+
+	_, err := keySet.Keys(now)
+	require.NoError(t, err)
+
+	server.kid.Store("def")
+
+	// The key set is stale according to the max-age directive, so the last fetched key set is returned while it's
+	// refreshed in the background.
+	keys, err := keySet.Keys(now.Add(time.Minute * 3))
+	require.NoError(t, err)
+	assert.Len(t, keys.Key("abc"), 1)
+
+	keySet.wg.Wait()
+
+	keys, err = keySet.Keys(now.Add(time.Minute * 3))
+	require.NoError(t, err)
+	assert.Len(t, keys.Key("def"), 1)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&server.fetches))
+}
+
+func TestShouldServeLastFetchedRemoteKeySetWithinMaxStale(t *testing.T) {
+	server := newTestRemoteKeySetServer(t)
+	keySet := server.keySet()
+	now := time.Now()
+
+	_, err := keySet.Keys(now)
+	require.NoError(t, err)
+
+	atomic.StoreInt32(&server.status, http.StatusInternalServerError)
+
+	stale := now.Add(time.Hour * 2)
+
+	keys, err := keySet.Keys(stale)
+	require.NoError(t, err)
+	assert.Len(t, keys.Key("abc"), 1)
+
+	keySet.wg.Wait()
+
+	keys, err = keySet.Keys(stale)
+	require.NoError(t, err)
+	assert.Len(t, keys.Key("abc"), 1)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&server.fetches))
+
+	_, err = keySet.Keys(now.Add(time.Hour * 26))
+	assert.EqualError(t, err, "the JWKS has been stale for longer than 24h0m0s: the response status code was 500")
+}
+
+func TestShouldRevalidateRemoteKeySetWithEntityTag(t *testing.T) {
+	server := newTestRemoteKeySetServer(t)
+	server.etag = `"v1"`
+	keySet := server.keySet()
+	now := time.Now()
+
+	_, err := keySet.Keys(now)
+	require.NoError(t, err)
+
+	keys, err := keySet.Refresh(now.Add(time.Minute * 2))
+	require.NoError(t, err)
+	assert.Len(t, keys.Key("abc"), 1)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&server.fetches))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&server.revalidated))
+}
+
+func TestShouldRateLimitRemoteKeySetRefresh(t *testing.T) {
+	server := newTestRemoteKeySetServer(t)
+	keySet := server.keySet()
+	now := time.Now()
+
+	_, err := keySet.Keys(now)
+	require.NoError(t, err)
+
+	_, err = keySet.Refresh(now.Add(time.Second * 30))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&server.fetches))
+
+	_, err = keySet.Refresh(now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&server.fetches))
+}
+
+func TestShouldNotFetchRemoteKeySetLargerThanMaxSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The response is streamed without a content length so the size is only known when it's read.
+		w.(http.Flusher).Flush()
+
+		_, _ = w.Write([]byte(`{"keys":[{"kty":"oct","k":"` + strings.Repeat("a", 2048) + `"}]}`))
+	}))
+
+	t.Cleanup(server.Close)
+
+	keySet := NewRemoteKeySet("test", server.URL, schema.JWKSCacheConfiguration{
+		Lifespan: time.Hour,
+		MaxStale: time.Hour,
+		MaxSize:  1024,
+	}, server.Client())
+
+	_, err := keySet.Keys(time.Now())
+	assert.EqualError(t, err, "the JWKS exceeds the maximum size of 1024 bytes")
+}
+
+func TestParseCacheControlMaxAge(t *testing.T) {
+	testCases := []struct {
+		desc, have string
+		want       time.Duration
+		err        error
+	}{
+		{desc: "ShouldParseMaxAge", have: "max-age=300", want: time.Minute * 5},
+		{desc: "ShouldParseMaxAgeWithOtherDirectives", have: "public, Max-Age=60, must-revalidate", want: time.Minute},
+		{desc: "ShouldNotCacheWithNoCache", have: "no-cache", err: errCacheControlNoCache},
+		{desc: "ShouldNotCacheWithNoStore", have: "private, no-store", err: errCacheControlNoCache},
+		{desc: "ShouldErrorWithoutMaxAge", have: "public", err: errCacheControlNoMaxAge},
+		{desc: "ShouldErrorWithInvalidMaxAge", have: "max-age=abc", err: errCacheControlNoMaxAge},
+		{desc: "ShouldErrorWithEmptyValue", have: "", err: errCacheControlNoMaxAge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			actual, err := parseCacheControlMaxAge(tc.have)
+
+			assert.Equal(t, tc.want, actual)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
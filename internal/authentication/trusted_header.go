@@ -0,0 +1,108 @@
+package authentication
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// TrustedHeaderVerifier verifies the identity forwarded in a header by an upstream authenticator which has already
+// authenticated the user. The identity is only trusted when the request was received directly from one of the trusted
+// networks and is for one of the trusted domains.
+type TrustedHeaderVerifier struct {
+	header       string
+	groupsHeader string
+	networks     []*net.IPNet
+	domains      []string
+}
+
+// NewTrustedHeaderVerifier creates a new TrustedHeaderVerifier from the configuration, it returns nil if trusted header
+// authentication is not enabled.
+func NewTrustedHeaderVerifier(config schema.TrustedHeaderAuthenticationBackendConfiguration) (verifier *TrustedHeaderVerifier, err error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	verifier = &TrustedHeaderVerifier{
+		header:       config.Header,
+		groupsHeader: config.GroupsHeader,
+		networks:     make([]*net.IPNet, 0, len(config.Networks)),
+		domains:      config.Domains,
+	}
+
+	for _, network := range config.Networks {
+		cidr, err := utils.ParseNetwork(network)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the trusted header network '%s': %w", network, err)
+		}
+
+		verifier.networks = append(verifier.networks, cidr)
+	}
+
+	return verifier, nil
+}
+
+// Header returns the name of the header containing the forwarded username.
+func (v *TrustedHeaderVerifier) Header() string {
+	return v.header
+}
+
+// GroupsHeader returns the name of the header containing the forwarded comma separated groups.
+func (v *TrustedHeaderVerifier) GroupsHeader() string {
+	return v.groupsHeader
+}
+
+// IsDomainTrusted returns true if the forwarded identity is trusted for the domain. A trusted domain prefixed with
+// '*.' matches all of its subdomains.
+func (v *TrustedHeaderVerifier) IsDomainTrusted(domain string) bool {
+	return isDomainInDomains(domain, v.domains)
+}
+
+// isDomainInDomains returns true if the domain matches one of the lower-cased domains. A domain prefixed with '*.'
+// matches all of its subdomains.
+func isDomainInDomains(domain string, domains []string) bool {
+	domain = strings.ToLower(domain)
+
+	for _, d := range domains {
+		switch {
+		case strings.HasPrefix(d, "*."):
+			if strings.HasSuffix(domain, d[1:]) {
+				return true
+			}
+		case domain == d:
+			return true
+		}
+	}
+
+	return false
+}
+
+// Verify returns the user details from the forwarded username and groups. The remote IP must be the IP of the peer the
+// request was directly received from, not one taken from a forwarded header, otherwise anyone could claim to be a
+// trusted upstream.
+func (v *TrustedHeaderVerifier) Verify(remoteIP net.IP, username, groups string) (details *UserDetails, err error) {
+	if !utils.IsIPInNetworks(remoteIP, v.networks) {
+		return nil, fmt.Errorf("the request was received from %s which is not a trusted network", remoteIP)
+	}
+
+	if username = strings.TrimSpace(username); username == "" {
+		return nil, errors.New("the forwarded username is empty")
+	}
+
+	details = &UserDetails{
+		Username:    username,
+		DisplayName: username,
+	}
+
+	for _, group := range strings.Split(groups, ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			details.Groups = append(details.Groups, group)
+		}
+	}
+
+	return details, nil
+}
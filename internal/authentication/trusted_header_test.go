@@ -0,0 +1,82 @@
+package authentication
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestTrustedHeaderVerifier(t *testing.T) *TrustedHeaderVerifier {
+	verifier, err := NewTrustedHeaderVerifier(schema.TrustedHeaderAuthenticationBackendConfiguration{
+		Enabled:      true,
+		Header:       "Remote-User",
+		GroupsHeader: "Remote-Groups",
+		Networks:     []string{"10.0.0.0/8", "192.168.1.1"},
+		Domains:      []string{"app.example.com", "*.internal.example.com"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, verifier)
+
+	return verifier
+}
+
+func TestShouldNotCreateTrustedHeaderVerifierWhenDisabled(t *testing.T) {
+	verifier, err := NewTrustedHeaderVerifier(schema.TrustedHeaderAuthenticationBackendConfiguration{})
+
+	assert.NoError(t, err)
+	assert.Nil(t, verifier)
+}
+
+func TestShouldNotCreateTrustedHeaderVerifierWithInvalidNetwork(t *testing.T) {
+	verifier, err := NewTrustedHeaderVerifier(schema.TrustedHeaderAuthenticationBackendConfiguration{
+		Enabled:  true,
+		Networks: []string{"abc"},
+	})
+
+	assert.EqualError(t, err, "unable to parse the trusted header network 'abc': invalid CIDR address: abc/128")
+	assert.Nil(t, verifier)
+}
+
+func TestShouldMatchTrustedHeaderDomains(t *testing.T) {
+	verifier := newTestTrustedHeaderVerifier(t)
+
+	assert.True(t, verifier.IsDomainTrusted("app.example.com"))
+	assert.True(t, verifier.IsDomainTrusted("APP.example.com"))
+	assert.True(t, verifier.IsDomainTrusted("a.internal.example.com"))
+	assert.True(t, verifier.IsDomainTrusted("a.b.internal.example.com"))
+	assert.False(t, verifier.IsDomainTrusted("internal.example.com"))
+	assert.False(t, verifier.IsDomainTrusted("other.example.com"))
+	assert.False(t, verifier.IsDomainTrusted("app.example.com.evil.com"))
+}
+
+func TestShouldVerifyTrustedHeader(t *testing.T) {
+	verifier := newTestTrustedHeaderVerifier(t)
+
+	details, err := verifier.Verify(net.ParseIP("10.1.2.3"), "john", "admins, dev,,")
+
+	require.NoError(t, err)
+	assert.Equal(t, &UserDetails{Username: "john", DisplayName: "john", Groups: []string{"admins", "dev"}}, details)
+
+	details, err = verifier.Verify(net.ParseIP("192.168.1.1"), " harry ", "")
+
+	require.NoError(t, err)
+	assert.Equal(t, &UserDetails{Username: "harry", DisplayName: "harry"}, details)
+}
+
+func TestShouldNotVerifyTrustedHeaderFromUntrustedNetwork(t *testing.T) {
+	verifier := newTestTrustedHeaderVerifier(t)
+
+	details, err := verifier.Verify(net.ParseIP("192.168.1.2"), "john", "admins")
+
+	assert.EqualError(t, err, "the request was received from 192.168.1.2 which is not a trusted network")
+	assert.Nil(t, details)
+
+	details, err = verifier.Verify(net.ParseIP("10.1.2.3"), " ", "admins")
+
+	assert.EqualError(t, err, "the forwarded username is empty")
+	assert.Nil(t, details)
+}
@@ -0,0 +1,199 @@
+package authentication
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// TrustedJWTVerifier verifies JWTs forwarded by an upstream identity provider and extracts the user details from
+// their claims.
+type TrustedJWTVerifier struct {
+	header     string
+	issuer     string
+	audience   string
+	algorithms []string
+	leeway     time.Duration
+	claims     schema.TrustedJWTClaimsConfiguration
+
+	keySet *RemoteKeySet
+}
+
+// NewTrustedJWTVerifier creates a new TrustedJWTVerifier from the configuration, it returns nil if trusted JWT
+// authentication is not enabled. The JWKS is fetched when the first token is verified.
+func NewTrustedJWTVerifier(config schema.TrustedJWTAuthenticationBackendConfiguration, certPool *x509.CertPool) (verifier *TrustedJWTVerifier) {
+	if !config.Enabled {
+		return nil
+	}
+
+	client := &http.Client{
+		Timeout: remoteKeySetTimeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS12},
+		},
+	}
+
+	return &TrustedJWTVerifier{
+		header:     config.Header,
+		issuer:     config.Issuer,
+		audience:   config.Audience,
+		algorithms: config.Algorithms,
+		leeway:     config.Leeway,
+		claims:     config.Claims,
+		keySet:     NewRemoteKeySet("trusted JWT", config.JWKSURL, config.JWKSCache, client),
+	}
+}
+
+// Header returns the name of the header containing the forwarded JWT.
+func (v *TrustedJWTVerifier) Header() string {
+	return v.header
+}
+
+// Verify verifies the signature of the forwarded JWT against the JWKS of the upstream identity provider, validates the
+// issuer, audience, and time based claims at the provided time, and returns the user details from the claims. The
+// value may optionally have the Bearer prefix.
+func (v *TrustedJWTVerifier) Verify(value string, now time.Time) (details *UserDetails, err error) {
+	if len(value) > len(apiKeyBearerPrefix) && strings.EqualFold(value[:len(apiKeyBearerPrefix)], apiKeyBearerPrefix) {
+		value = value[len(apiKeyBearerPrefix):]
+	}
+
+	token, err := jwt.ParseSigned(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the trusted JWT: %w", err)
+	}
+
+	if len(token.Headers) != 1 {
+		return nil, errors.New("the trusted JWT must have exactly one signature")
+	}
+
+	header := token.Headers[0]
+
+	if !utils.IsStringInSlice(header.Algorithm, v.algorithms) {
+		return nil, fmt.Errorf("the trusted JWT is signed with the algorithm '%s' which is not allowed", header.Algorithm)
+	}
+
+	key, err := v.key(header.KeyID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		claims jwt.Claims
+		values map[string]interface{}
+	)
+
+	if err = token.Claims(key, &claims, &values); err != nil {
+		return nil, fmt.Errorf("the trusted JWT failed signature verification: %w", err)
+	}
+
+	if claims.Expiry == nil {
+		return nil, errors.New("the trusted JWT does not have the 'exp' claim")
+	}
+
+	if err = claims.ValidateWithLeeway(jwt.Expected{Issuer: v.issuer, Audience: jwt.Audience{v.audience}, Time: now}, v.leeway); err != nil {
+		return nil, fmt.Errorf("the trusted JWT failed validation: %w", err)
+	}
+
+	details = &UserDetails{
+		Username:    trustedJWTStringClaim(values, v.claims.Username),
+		DisplayName: trustedJWTStringClaim(values, v.claims.DisplayName),
+		Groups:      trustedJWTStringsClaim(values, v.claims.Groups),
+	}
+
+	if details.Username == "" {
+		return nil, fmt.Errorf("the trusted JWT does not have a value for the '%s' claim", v.claims.Username)
+	}
+
+	if details.DisplayName == "" {
+		details.DisplayName = details.Username
+	}
+
+	if email := trustedJWTStringClaim(values, v.claims.Email); email != "" {
+		details.Emails = []string{email}
+	}
+
+	return details, nil
+}
+
+// key returns the key with the key ID from the JWKS. The JWKS is refreshed if it doesn't contain the key, which is rate
+// limited by the RemoteKeySet.
+func (v *TrustedJWTVerifier) key(kid string, now time.Time) (key *jose.JSONWebKey, err error) {
+	keys, err := v.keySet.Keys(now)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch the trusted JWT JWKS: %w", err)
+	}
+
+	if key = trustedJWTFindKey(keys, kid); key == nil {
+		if keys, err = v.keySet.Refresh(now); err != nil {
+			return nil, fmt.Errorf("unable to fetch the trusted JWT JWKS: %w", err)
+		}
+
+		key = trustedJWTFindKey(keys, kid)
+	}
+
+	if key == nil {
+		return nil, fmt.Errorf("the trusted JWT is signed with the key '%s' which is not in the JWKS", kid)
+	}
+
+	return key, nil
+}
+
+func trustedJWTFindKey(keys *jose.JSONWebKeySet, kid string) (key *jose.JSONWebKey) {
+	var candidates []jose.JSONWebKey
+
+	if kid != "" {
+		candidates = keys.Key(kid)
+	} else {
+		candidates = keys.Keys
+	}
+
+	var found *jose.JSONWebKey
+
+	for i := range candidates {
+		if candidates[i].Use == "enc" || !candidates[i].IsPublic() {
+			continue
+		}
+
+		// Tokens without a key ID are only accepted when the JWKS has a single signing key.
+		if found != nil {
+			return nil
+		}
+
+		found = &candidates[i]
+	}
+
+	return found
+}
+
+func trustedJWTStringClaim(values map[string]interface{}, name string) string {
+	value, _ := values[name].(string)
+
+	return value
+}
+
+// trustedJWTStringsClaim returns the values of a claim which is either a list of strings or a single string.
+func trustedJWTStringsClaim(values map[string]interface{}, name string) (result []string) {
+	switch value := values[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,206 @@
+package authentication
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+type testTrustedJWTIssuer struct {
+	key     *rsa.PrivateKey
+	kid     string
+	server  *httptest.Server
+	fetches int32
+}
+
+func newTestTrustedJWTIssuer(t *testing.T) *testTrustedJWTIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	issuer := &testTrustedJWTIssuer{key: key, kid: "abc"}
+
+	issuer.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&issuer.fetches, 1)
+
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{
+			Keys: []jose.JSONWebKey{{Key: &issuer.key.PublicKey, KeyID: issuer.kid, Algorithm: "RS256", Use: "sig"}},
+		})
+	}))
+
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (i *testTrustedJWTIssuer) verifier(t *testing.T) *TrustedJWTVerifier {
+	pool := x509.NewCertPool()
+	pool.AddCert(i.server.Certificate())
+
+	verifier := NewTrustedJWTVerifier(schema.TrustedJWTAuthenticationBackendConfiguration{
+		Enabled:    true,
+		Header:     "X-Forwarded-JWT",
+		Issuer:     "https://idp.example.com",
+		Audience:   "authelia",
+		JWKSURL:    i.server.URL,
+		JWKSCache:  schema.DefaultTrustedJWTAuthenticationBackendConfiguration.JWKSCache,
+		Algorithms: []string{"RS256"},
+		Leeway:     time.Minute,
+		Claims:     schema.DefaultTrustedJWTAuthenticationBackendConfiguration.Claims,
+	}, pool)
+	require.NotNil(t, verifier)
+
+	return verifier
+}
+
+func (i *testTrustedJWTIssuer) sign(t *testing.T, alg jose.SignatureAlgorithm, kid string, claims jwt.Claims, custom map[string]interface{}) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: jose.JSONWebKey{Key: i.key, KeyID: kid}}, nil)
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(signer).Claims(claims).Claims(custom).CompactSerialize()
+	require.NoError(t, err)
+
+	return token
+}
+
+func testTrustedJWTClaims(now time.Time) jwt.Claims {
+	return jwt.Claims{
+		Issuer:   "https://idp.example.com",
+		Audience: jwt.Audience{"authelia"},
+		Expiry:   jwt.NewNumericDate(now.Add(time.Minute)),
+		IssuedAt: jwt.NewNumericDate(now),
+	}
+}
+
+func TestShouldNotCreateTrustedJWTVerifierWhenDisabled(t *testing.T) {
+	assert.Nil(t, NewTrustedJWTVerifier(schema.TrustedJWTAuthenticationBackendConfiguration{}, nil))
+}
+
+func TestShouldVerifyTrustedJWT(t *testing.T) {
+	issuer := newTestTrustedJWTIssuer(t)
+	verifier := issuer.verifier(t)
+	now := time.Now()
+
+	assert.Equal(t, "X-Forwarded-JWT", verifier.Header())
+
+	token := issuer.sign(t, jose.RS256, "abc", testTrustedJWTClaims(now), map[string]interface{}{
+		"preferred_username": "john",
+		"name":               "John Doe",
+		"email":              "john@example.com",
+		"groups":             []string{"admins", "dev"},
+	})
+
+	details, err := verifier.Verify(token, now)
+	require.NoError(t, err)
+	assert.Equal(t, &UserDetails{
+		Username:    "john",
+		DisplayName: "John Doe",
+		Emails:      []string{"john@example.com"},
+		Groups:      []string{"admins", "dev"},
+	}, details)
+
+	details, err = verifier.Verify("Bearer "+issuer.sign(t, jose.RS256, "", testTrustedJWTClaims(now), map[string]interface{}{
+		"preferred_username": "harry",
+		"groups":             "dev",
+	}), now)
+	require.NoError(t, err)
+	assert.Equal(t, &UserDetails{
+		Username:    "harry",
+		DisplayName: "harry",
+		Groups:      []string{"dev"},
+	}, details)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&issuer.fetches))
+}
+
+func TestShouldNotVerifyInvalidTrustedJWTs(t *testing.T) {
+	issuer := newTestTrustedJWTIssuer(t)
+	other := newTestTrustedJWTIssuer(t)
+	verifier := issuer.verifier(t)
+	now := time.Now()
+
+	custom := map[string]interface{}{"preferred_username": "john"}
+
+	_, err := verifier.Verify("not-a-jwt", now)
+	assert.EqualError(t, err, "unable to parse the trusted JWT: square/go-jose: compact JWS format must have three parts")
+
+	_, err = verifier.Verify(issuer.sign(t, jose.PS256, "abc", testTrustedJWTClaims(now), custom), now)
+	assert.EqualError(t, err, "the trusted JWT is signed with the algorithm 'PS256' which is not allowed")
+
+	_, err = verifier.Verify(other.sign(t, jose.RS256, "abc", testTrustedJWTClaims(now), custom), now)
+	assert.EqualError(t, err, "the trusted JWT failed signature verification: square/go-jose: error in cryptographic primitive")
+
+	_, err = verifier.Verify(issuer.sign(t, jose.RS256, "xyz", testTrustedJWTClaims(now), custom), now)
+	assert.EqualError(t, err, "the trusted JWT is signed with the key 'xyz' which is not in the JWKS")
+
+	claims := testTrustedJWTClaims(now)
+	claims.Issuer = "https://other.example.com"
+
+	_, err = verifier.Verify(issuer.sign(t, jose.RS256, "abc", claims, custom), now)
+	assert.EqualError(t, err, "the trusted JWT failed validation: square/go-jose/jwt: validation failed, invalid issuer claim (iss)")
+
+	claims = testTrustedJWTClaims(now)
+	claims.Audience = jwt.Audience{"other"}
+
+	_, err = verifier.Verify(issuer.sign(t, jose.RS256, "abc", claims, custom), now)
+	assert.EqualError(t, err, "the trusted JWT failed validation: square/go-jose/jwt: validation failed, invalid audience claim (aud)")
+
+	_, err = verifier.Verify(issuer.sign(t, jose.RS256, "abc", testTrustedJWTClaims(now), custom), now.Add(time.Minute*3))
+	assert.EqualError(t, err, "the trusted JWT failed validation: square/go-jose/jwt: validation failed, token is expired (exp)")
+
+	claims = testTrustedJWTClaims(now)
+	claims.Expiry = nil
+
+	_, err = verifier.Verify(issuer.sign(t, jose.RS256, "abc", claims, custom), now)
+	assert.EqualError(t, err, "the trusted JWT does not have the 'exp' claim")
+
+	_, err = verifier.Verify(issuer.sign(t, jose.RS256, "abc", testTrustedJWTClaims(now), map[string]interface{}{"name": "John Doe"}), now)
+	assert.EqualError(t, err, "the trusted JWT does not have a value for the 'preferred_username' claim")
+}
+
+func TestShouldRefreshTrustedJWTKeySetForUnknownKeys(t *testing.T) {
+	issuer := newTestTrustedJWTIssuer(t)
+	verifier := issuer.verifier(t)
+	now := time.Now()
+
+	custom := map[string]interface{}{"preferred_username": "john"}
+
+	_, err := verifier.Verify(issuer.sign(t, jose.RS256, "abc", testTrustedJWTClaims(now), custom), now)
+	require.NoError(t, err)
+
+	issuer.kid = "def"
+
+	// The JWKS is not fetched again for an unknown key until the minimum refresh interval has passed.
+	_, err = verifier.Verify(issuer.sign(t, jose.RS256, "def", testTrustedJWTClaims(now), custom), now)
+	assert.EqualError(t, err, "the trusted JWT is signed with the key 'def' which is not in the JWKS")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&issuer.fetches))
+
+	later := now.Add(time.Minute * 2)
+
+	_, err = verifier.Verify(issuer.sign(t, jose.RS256, "def", testTrustedJWTClaims(later), custom), later)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&issuer.fetches))
+}
+
+func TestShouldNotVerifyTrustedJWTWhenKeySetUnavailable(t *testing.T) {
+	issuer := newTestTrustedJWTIssuer(t)
+	verifier := issuer.verifier(t)
+	now := time.Now()
+
+	issuer.server.Close()
+
+	_, err := verifier.Verify(issuer.sign(t, jose.RS256, "abc", testTrustedJWTClaims(now), map[string]interface{}{"preferred_username": "john"}), now)
+	assert.Regexp(t, `^unable to fetch the trusted JWT JWKS: `, err.Error())
+}
@@ -1,9 +1,16 @@
 package authentication
 
+import (
+	"github.com/google/uuid"
+)
+
 // UserDetails represent the details retrieved for a given user.
 type UserDetails struct {
 	Username    string
 	DisplayName string
 	Emails      []string
 	Groups      []string
+
+	// Subject is the immutable identifier of the user, it's uuid.Nil if the backend isn't configured to retrieve one.
+	Subject uuid.UUID
 }
@@ -0,0 +1,164 @@
+package authentication
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// UserDetailsCache is a least recently used cache of the details of users resolved by a UserProvider. Entries expire
+// once they're older than the configured lifespan and are never refreshed in place, so a change to the details of a
+// user in the backend is always observed within the lifespan. A nil *UserDetailsCache is valid and retrieves the
+// details directly from the provider.
+type UserDetailsCache struct {
+	lifespan   time.Duration
+	maxEntries int
+
+	clock utils.Clock
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+
+	// generation is incremented by each invalidation so lookups which were in progress during an invalidation don't
+	// store the details they retrieved before it.
+	generation uint64
+}
+
+type userDetailsCacheEntry struct {
+	username string
+	details  UserDetails
+	expires  time.Time
+}
+
+// NewUserDetailsCache creates a new UserDetailsCache. It returns nil if the cache is not enabled.
+func NewUserDetailsCache(config schema.UserDetailsCacheConfiguration, clock utils.Clock) (cache *UserDetailsCache) {
+	if !config.Enabled {
+		return nil
+	}
+
+	return &UserDetailsCache{
+		lifespan:   config.Lifespan,
+		maxEntries: config.MaxEntries,
+		clock:      clock,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+// GetDetails returns the cached details of the user if they have not expired, otherwise it retrieves them from the
+// provider and caches them.
+func (c *UserDetailsCache) GetDetails(provider UserProvider, username string) (details *UserDetails, err error) {
+	if c == nil {
+		return provider.GetDetails(username)
+	}
+
+	c.mutex.Lock()
+
+	if element, ok := c.entries[username]; ok {
+		entry := element.Value.(*userDetailsCacheEntry)
+
+		if c.clock.Now().Before(entry.expires) {
+			c.order.MoveToFront(element)
+
+			details = copyUserDetails(&entry.details)
+
+			c.mutex.Unlock()
+
+			return details, nil
+		}
+
+		c.remove(element)
+	}
+
+	generation := c.generation
+
+	c.mutex.Unlock()
+
+	if details, err = provider.GetDetails(username); err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if generation == c.generation {
+		c.store(username, details)
+	}
+
+	return copyUserDetails(details), nil
+}
+
+// Invalidate removes the cached details of the user. Entries are matched case-insensitively against both the username
+// they were looked up with and the username the provider resolved, so every variation of the username is removed.
+func (c *UserDetailsCache) Invalidate(username string) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.generation++
+
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		entry := element.Value.(*userDetailsCacheEntry)
+
+		if strings.EqualFold(entry.username, username) || strings.EqualFold(entry.details.Username, username) {
+			c.remove(element)
+		}
+
+		element = next
+	}
+}
+
+// Len returns the number of entries in the cache including the ones which have expired but have not been evicted.
+func (c *UserDetailsCache) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+// store adds the details to the cache while holding the lock, evicting the least recently used entries when the cache
+// is full.
+func (c *UserDetailsCache) store(username string, details *UserDetails) {
+	if element, ok := c.entries[username]; ok {
+		c.remove(element)
+	}
+
+	for c.order.Len() >= c.maxEntries {
+		c.remove(c.order.Back())
+	}
+
+	c.entries[username] = c.order.PushFront(&userDetailsCacheEntry{
+		username: username,
+		details:  *copyUserDetails(details),
+		expires:  c.clock.Now().Add(c.lifespan),
+	})
+}
+
+func (c *UserDetailsCache) remove(element *list.Element) {
+	delete(c.entries, element.Value.(*userDetailsCacheEntry).username)
+	c.order.Remove(element)
+}
+
+// copyUserDetails copies the details so neither the cache nor its callers can modify the groups or emails of the other.
+func copyUserDetails(details *UserDetails) *UserDetails {
+	return &UserDetails{
+		Username:    details.Username,
+		DisplayName: details.DisplayName,
+		Emails:      append([]string(nil), details.Emails...),
+		Groups:      append([]string(nil), details.Groups...),
+		Subject:     details.Subject,
+	}
+}
@@ -0,0 +1,207 @@
+package authentication
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+type testUserDetailsCacheClock struct {
+	now time.Time
+}
+
+func (c *testUserDetailsCacheClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testUserDetailsCacheClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+type testUserDetailsCacheProvider struct {
+	groups  []string
+	lookups int
+
+	// during is called while the details are being retrieved.
+	during func()
+}
+
+func (p *testUserDetailsCacheProvider) StartupCheck() (err error) {
+	return nil
+}
+
+func (p *testUserDetailsCacheProvider) CheckUserPassword(_, _ string) (valid bool, err error) {
+	return false, nil
+}
+
+func (p *testUserDetailsCacheProvider) GetDetails(username string) (details *UserDetails, err error) {
+	p.lookups++
+
+	if username == "unknown" {
+		return nil, errors.New("user not found")
+	}
+
+	if p.during != nil {
+		p.during()
+	}
+
+	return &UserDetails{
+		Username:    username,
+		DisplayName: "John Smith",
+		Emails:      []string{fmt.Sprintf("%s@example.com", username)},
+		Groups:      append([]string(nil), p.groups...),
+	}, nil
+}
+
+func (p *testUserDetailsCacheProvider) UpdatePassword(_, _ string) (err error) {
+	return nil
+}
+
+func newTestUserDetailsCache(maxEntries int) (cache *UserDetailsCache, clock *testUserDetailsCacheClock) {
+	clock = &testUserDetailsCacheClock{now: time.Unix(1600000000, 0)}
+
+	cache = NewUserDetailsCache(schema.UserDetailsCacheConfiguration{
+		Enabled:    true,
+		Lifespan:   time.Minute,
+		MaxEntries: maxEntries,
+	}, clock)
+
+	return cache, clock
+}
+
+func TestShouldNotCreateUserDetailsCacheWhenDisabled(t *testing.T) {
+	cache := NewUserDetailsCache(schema.UserDetailsCacheConfiguration{Lifespan: time.Minute, MaxEntries: 10}, &testUserDetailsCacheClock{})
+
+	require.Nil(t, cache)
+
+	provider := &testUserDetailsCacheProvider{groups: []string{"admins"}}
+
+	details, err := cache.GetDetails(provider, "john")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admins"}, details.Groups)
+
+	_, err = cache.GetDetails(provider, "john")
+	require.NoError(t, err)
+
+	cache.Invalidate("john")
+
+	assert.Equal(t, 2, provider.lookups)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestShouldCacheUserDetailsForLifespan(t *testing.T) {
+	cache, clock := newTestUserDetailsCache(10)
+	provider := &testUserDetailsCacheProvider{groups: []string{"admins"}}
+
+	details, err := cache.GetDetails(provider, "john")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admins"}, details.Groups)
+
+	provider.groups = []string{"users"}
+	clock.now = clock.now.Add(time.Second * 59)
+
+	details, err = cache.GetDetails(provider, "john")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admins"}, details.Groups)
+	assert.Equal(t, 1, provider.lookups)
+
+	clock.now = clock.now.Add(time.Second)
+
+	details, err = cache.GetDetails(provider, "john")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users"}, details.Groups)
+	assert.Equal(t, 2, provider.lookups)
+}
+
+func TestShouldNotCacheUserDetailsErrors(t *testing.T) {
+	cache, _ := newTestUserDetailsCache(10)
+	provider := &testUserDetailsCacheProvider{}
+
+	_, err := cache.GetDetails(provider, "unknown")
+	assert.EqualError(t, err, "user not found")
+
+	_, err = cache.GetDetails(provider, "unknown")
+	assert.EqualError(t, err, "user not found")
+
+	assert.Equal(t, 2, provider.lookups)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestShouldNotShareUserDetailsWithCallers(t *testing.T) {
+	cache, _ := newTestUserDetailsCache(10)
+	provider := &testUserDetailsCacheProvider{groups: []string{"admins"}}
+
+	details, err := cache.GetDetails(provider, "john")
+	require.NoError(t, err)
+
+	details.Groups[0] = "modified"
+
+	details, err = cache.GetDetails(provider, "john")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admins"}, details.Groups)
+}
+
+func TestShouldEvictLeastRecentlyUsedUserDetails(t *testing.T) {
+	cache, _ := newTestUserDetailsCache(2)
+	provider := &testUserDetailsCacheProvider{}
+
+	for _, username := range []string{"john", "harry", "john", "bob"} {
+		_, err := cache.GetDetails(provider, username)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, cache.Len())
+	assert.Equal(t, 3, provider.lookups)
+
+	_, err := cache.GetDetails(provider, "john")
+	require.NoError(t, err)
+	assert.Equal(t, 3, provider.lookups)
+
+	_, err = cache.GetDetails(provider, "harry")
+	require.NoError(t, err)
+	assert.Equal(t, 4, provider.lookups)
+}
+
+func TestShouldInvalidateUserDetailsCaseInsensitively(t *testing.T) {
+	cache, _ := newTestUserDetailsCache(10)
+	provider := &testUserDetailsCacheProvider{}
+
+	for _, username := range []string{"john", "John", "harry"} {
+		_, err := cache.GetDetails(provider, username)
+		require.NoError(t, err)
+	}
+
+	cache.Invalidate("JOHN")
+
+	assert.Equal(t, 1, cache.Len())
+
+	_, err := cache.GetDetails(provider, "john")
+	require.NoError(t, err)
+	assert.Equal(t, 4, provider.lookups)
+}
+
+func TestShouldNotCacheUserDetailsRetrievedDuringInvalidation(t *testing.T) {
+	cache, _ := newTestUserDetailsCache(10)
+	provider := &testUserDetailsCacheProvider{groups: []string{"admins"}}
+
+	provider.during = func() {
+		cache.Invalidate("john")
+	}
+
+	details, err := cache.GetDetails(provider, "john")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admins"}, details.Groups)
+	assert.Equal(t, 0, cache.Len())
+
+	provider.during = nil
+
+	_, err = cache.GetDetails(provider, "john")
+	require.NoError(t, err)
+	assert.Equal(t, 1, cache.Len())
+}
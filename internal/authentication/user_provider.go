@@ -1,6 +1,8 @@
 package authentication
 
 import (
+	"errors"
+
 	"github.com/authelia/authelia/v4/internal/model"
 )
 
@@ -13,3 +15,31 @@ type UserProvider interface {
 	GetDetails(username string) (details *UserDetails, err error)
 	UpdatePassword(username string, newPassword string) (err error)
 }
+
+// UserRegistrationProvider is the interface implemented by user providers which are able to create users.
+type UserRegistrationProvider interface {
+	HashPassword(password string) (hash string, err error)
+	AddUser(username string, details UserDetails, hashedPassword string) (err error)
+}
+
+// UserDisableProvider is the interface implemented by user providers which are able to disable users. Implementations
+// must be able to answer IsUserDisabled cheaply as it's checked on every request authenticated by a session.
+type UserDisableProvider interface {
+	IsUserDisabled(username string) (disabled bool, err error)
+	SetUserDisabled(username string, disabled bool) (err error)
+}
+
+// IsUserDisabled returns true if the provider is able to disable users and the given user is disabled. Users which don't
+// exist are not considered disabled.
+func IsUserDisabled(provider UserProvider, username string) (disabled bool, err error) {
+	p, ok := provider.(UserDisableProvider)
+	if !ok {
+		return false, nil
+	}
+
+	if disabled, err = p.IsUserDisabled(username); err != nil && !errors.Is(err, ErrUserNotFound) {
+		return false, err
+	}
+
+	return disabled, nil
+}
@@ -0,0 +1,34 @@
+package authentication
+
+import (
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// CanonicalizeUsername returns the canonical form of a username entered by a user so the same user always resolves to
+// the same username. The configured domain suffix is stripped first, then the pattern is replaced, and finally the
+// username is lowercased.
+func CanonicalizeUsername(config schema.UsernameCanonicalizationConfiguration, username string) (canonical string) {
+	canonical = username
+
+	for _, domain := range config.StripDomainSuffixes {
+		suffix := "@" + domain
+
+		if len(canonical) > len(suffix) && strings.EqualFold(canonical[len(canonical)-len(suffix):], suffix) {
+			canonical = canonical[:len(canonical)-len(suffix)]
+
+			break
+		}
+	}
+
+	if config.Pattern != nil {
+		canonical = config.Pattern.ReplaceAllString(canonical, config.Replacement)
+	}
+
+	if config.Lowercase {
+		canonical = strings.ToLower(canonical)
+	}
+
+	return canonical
+}
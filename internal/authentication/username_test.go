@@ -0,0 +1,75 @@
+package authentication
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestCanonicalizeUsername(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config schema.UsernameCanonicalizationConfiguration
+		have   string
+		want   string
+	}{
+		{
+			desc: "ShouldNotAlterUsernameByDefault",
+			have: "John@Example.com",
+			want: "John@Example.com",
+		},
+		{
+			desc:   "ShouldLowercase",
+			config: schema.UsernameCanonicalizationConfiguration{Lowercase: true},
+			have:   "JoHn",
+			want:   "john",
+		},
+		{
+			desc:   "ShouldStripDomainSuffixCaseInsensitively",
+			config: schema.UsernameCanonicalizationConfiguration{StripDomainSuffixes: []string{"example.com", "example.org"}},
+			have:   "John@EXAMPLE.org",
+			want:   "John",
+		},
+		{
+			desc:   "ShouldNotStripOtherDomainSuffix",
+			config: schema.UsernameCanonicalizationConfiguration{StripDomainSuffixes: []string{"example.com"}},
+			have:   "john@sub.example.com",
+			want:   "john@sub.example.com",
+		},
+		{
+			desc:   "ShouldNotStripDomainSuffixLeavingEmptyUsername",
+			config: schema.UsernameCanonicalizationConfiguration{StripDomainSuffixes: []string{"example.com"}},
+			have:   "@example.com",
+			want:   "@example.com",
+		},
+		{
+			desc: "ShouldReplacePattern",
+			config: schema.UsernameCanonicalizationConfiguration{
+				Pattern:     regexp.MustCompile(`^EXAMPLE\\(.+)$`),
+				Replacement: "$1",
+			},
+			have: `EXAMPLE\john`,
+			want: "john",
+		},
+		{
+			desc: "ShouldApplyAllInOrder",
+			config: schema.UsernameCanonicalizationConfiguration{
+				StripDomainSuffixes: []string{"example.com"},
+				Pattern:             regexp.MustCompile(`\.`),
+				Replacement:         "_",
+				Lowercase:           true,
+			},
+			have: "John.Smith@Example.com",
+			want: "john_smith",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.want, CanonicalizeUsername(tc.config, tc.have))
+		})
+	}
+}
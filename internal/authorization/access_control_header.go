@@ -0,0 +1,33 @@
+package authorization
+
+import (
+	"regexp"
+)
+
+// AccessControlHeader represents an ACL header condition.
+type AccessControlHeader struct {
+	Name  string
+	Value *regexp.Regexp
+}
+
+// IsMatch returns true if the object has the header and, when the ACL header has a value pattern, one of the values
+// of the header matches it. Header names are case-insensitive.
+func (ach AccessControlHeader) IsMatch(object Object) (match bool) {
+	values := object.Header.Values(ach.Name)
+
+	if len(values) == 0 {
+		return false
+	}
+
+	if ach.Value == nil {
+		return true
+	}
+
+	for _, value := range values {
+		if ach.Value.MatchString(value) {
+			return true
+		}
+	}
+
+	return false
+}
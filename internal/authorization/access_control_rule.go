@@ -2,6 +2,8 @@ package authorization
 
 import (
 	"net"
+	"regexp"
+	"time"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -12,7 +14,12 @@ func NewAccessControlRules(config schema.AccessControlConfiguration) (rules []*A
 	networksMap, networksCacheMap := parseSchemaNetworks(config.Networks)
 
 	for i, schemaRule := range config.Rules {
-		rules = append(rules, NewAccessControlRule(i+1, schemaRule, networksMap, networksCacheMap))
+		rule := NewAccessControlRule(i+1, schemaRule, networksMap, networksCacheMap)
+
+		rule.Shadow = rule.Shadow || config.Shadow
+		rule.ShadowTwoFactor = config.ShadowTwoFactor
+
+		rules = append(rules, rule)
 	}
 
 	return rules
@@ -21,25 +28,102 @@ func NewAccessControlRules(config schema.AccessControlConfiguration) (rules []*A
 // NewAccessControlRule parses a schema ACL and generates an internal ACL.
 func NewAccessControlRule(pos int, rule schema.ACLRule, networksMap map[string][]*net.IPNet, networksCacheMap map[string]*net.IPNet) *AccessControlRule {
 	return &AccessControlRule{
-		Position:  pos,
-		Domains:   schemaDomainsToACL(rule.Domains, rule.DomainsRegex),
-		Resources: schemaResourcesToACL(rule.Resources),
-		Methods:   schemaMethodsToACL(rule.Methods),
-		Networks:  schemaNetworksToACL(rule.Networks, networksMap, networksCacheMap),
-		Subjects:  schemaSubjectsToACL(rule.Subjects),
-		Policy:    PolicyToLevel(rule.Policy),
+		Position:   pos,
+		Name:       rule.Name,
+		Domains:    schemaDomainsToACL(rule.Domains, rule.DomainsRegex),
+		Resources:  schemaResourcesToACL(rule.Resources),
+		Methods:    schemaMethodsToACL(rule.Methods),
+		Headers:    schemaHeadersToACL(rule.Headers),
+		UserAgents: rule.UserAgents,
+		Networks:   schemaNetworksToACL(rule.Networks, networksMap, networksCacheMap),
+		Subjects:   schemaSubjectsToACL(rule.Subjects),
+		Policy:     PolicyToLevel(rule.Policy),
+
+		NetworkPolicies: schemaNetworkPoliciesToACL(rule.NetworkPolicies, networksMap, networksCacheMap),
+
+		MaximumAuthenticationAge: rule.MaximumAuthenticationAge,
+
+		SecondFactorMethods: rule.SecondFactorMethods,
+
+		TimeWindow: NewAccessControlTimeWindow(rule.TimeWindow),
+
+		DenyResponse: rule.DenyResponse,
+
+		Identity: rule.Identity,
+
+		Shadow: rule.Shadow,
 	}
 }
 
 // AccessControlRule controls and represents an ACL internally.
 type AccessControlRule struct {
-	Position  int
-	Domains   []SubjectObjectMatcher
-	Resources []AccessControlResource
-	Methods   []string
-	Networks  []*net.IPNet
-	Subjects  []AccessControlSubjects
-	Policy    Level
+	Position   int
+	Name       string
+	Domains    []SubjectObjectMatcher
+	Resources  []AccessControlResource
+	Methods    []string
+	Headers    []AccessControlHeader
+	UserAgents []regexp.Regexp
+	Networks   []*net.IPNet
+	Subjects   []AccessControlSubjects
+	Policy     Level
+
+	NetworkPolicies []AccessControlNetworkPolicy
+
+	MaximumAuthenticationAge time.Duration
+
+	// SecondFactorMethods restricts the second factor methods which satisfy the two factor policy when set.
+	SecondFactorMethods []string
+
+	// TimeWindow restricts access to the days and hours it permits when set.
+	TimeWindow *AccessControlTimeWindow
+
+	// DenyResponse overrides the global response sent to users who are forbidden from accessing the resource when set.
+	DenyResponse *schema.ACLDenyResponse
+
+	// Identity is the synthetic identity forwarded for requests bypassed by the rule from its networks when set.
+	Identity *schema.ACLIdentity
+
+	// Shadow makes the rule only log the requests it would deny, the rules after it are applied to them instead.
+	Shadow bool
+
+	// ShadowTwoFactor includes the two_factor policy in the shadow mode of the rule.
+	ShadowTwoFactor bool
+}
+
+// AccessControlNetworkPolicy represents a policy of an ACL which only applies to subjects from specific networks.
+type AccessControlNetworkPolicy struct {
+	Networks []*net.IPNet
+	Policy   Level
+}
+
+// GetPolicy returns the policy of the AccessControlRule for the subject. The policy of the first network policy which
+// contains the IP of the subject is returned, otherwise the policy of the rule is returned.
+func (acr *AccessControlRule) GetPolicy(subject Subject) (policy Level) {
+	for _, networkPolicy := range acr.NetworkPolicies {
+		if utils.IsIPInNetworks(subject.IP, networkPolicy.Networks) {
+			return networkPolicy.Policy
+		}
+	}
+
+	return acr.Policy
+}
+
+// IsShadowed returns true if the rule is in shadow mode and the provided policy of the rule is only logged rather than
+// enforced. This is only the case for the deny policy, and for the two_factor policy when it's explicitly included.
+func (acr *AccessControlRule) IsShadowed(policy Level) bool {
+	if !acr.Shadow {
+		return false
+	}
+
+	switch policy {
+	case Denied:
+		return true
+	case TwoFactor:
+		return acr.ShadowTwoFactor
+	default:
+		return false
+	}
 }
 
 // IsMatch returns true if all elements of an AccessControlRule match the object and subject.
@@ -56,6 +140,10 @@ func (acr *AccessControlRule) IsMatch(subject Subject, object Object) (match boo
 		return false
 	}
 
+	if !isMatchForHeaders(object, acr) {
+		return false
+	}
+
 	if !isMatchForNetworks(subject, acr) {
 		return false
 	}
@@ -108,15 +196,24 @@ func isMatchForMethods(object Object, acl *AccessControlRule) (match bool) {
 	return utils.IsStringInSlice(object.Method, acl.Methods)
 }
 
-func isMatchForNetworks(subject Subject, acl *AccessControlRule) (match bool) {
-	// If there are no networks in this rule then the network condition is a match.
-	if len(acl.Networks) == 0 {
+func isMatchForHeaders(object Object, acl *AccessControlRule) (match bool) {
+	// Unlike the other conditions all the headers must match.
+	for _, header := range acl.Headers {
+		if !header.IsMatch(object) {
+			return false
+		}
+	}
+
+	// If there are no user agents in this rule then the user agent condition is a match.
+	if len(acl.UserAgents) == 0 {
 		return true
 	}
 
-	// Iterate over the networks until we find a match (return true) or until we exit the loop (return false).
-	for _, network := range acl.Networks {
-		if network.Contains(subject.IP) {
+	userAgent := object.Header.Get(headerUserAgent)
+
+	// Iterate over the user agents until we find a match (return true) or until we exit the loop (return false).
+	for _, pattern := range acl.UserAgents {
+		if pattern.MatchString(userAgent) {
 			return true
 		}
 	}
@@ -124,6 +221,15 @@ func isMatchForNetworks(subject Subject, acl *AccessControlRule) (match bool) {
 	return false
 }
 
+func isMatchForNetworks(subject Subject, acl *AccessControlRule) (match bool) {
+	// If there are no networks in this rule then the network condition is a match.
+	if len(acl.Networks) == 0 {
+		return true
+	}
+
+	return utils.IsIPInNetworks(subject.IP, acl.Networks)
+}
+
 // Same as isExactMatchForSubjects except it theoretically matches if subject is anonymous since they'd need to authenticate.
 func isMatchForSubjects(subject Subject, acl *AccessControlRule) (match bool) {
 	if subject.IsAnonymous() {
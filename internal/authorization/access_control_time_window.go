@@ -0,0 +1,110 @@
+package authorization
+
+import (
+	"strings"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewAccessControlTimeWindow creates a new AccessControlTimeWindow from a schema.ACLTimeWindow.
+func NewAccessControlTimeWindow(config *schema.ACLTimeWindow) *AccessControlTimeWindow {
+	if config == nil {
+		return nil
+	}
+
+	window := &AccessControlTimeWindow{
+		TerminateSessions: config.TerminateSessions,
+	}
+
+	for _, day := range config.Days {
+		for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+			if strings.EqualFold(day, weekday.String()) {
+				window.Days = append(window.Days, weekday)
+			}
+		}
+	}
+
+	// The values are checked by the validator and fallback to the zero value.
+	start, _ := time.Parse(schema.ACLTimeWindowTimeLayout, config.Start)
+	end, _ := time.Parse(schema.ACLTimeWindowTimeLayout, config.End)
+
+	window.StartHour, window.StartMinute = start.Hour(), start.Minute()
+	window.EndHour, window.EndMinute = end.Hour(), end.Minute()
+
+	var err error
+
+	if window.Location, err = time.LoadLocation(config.Timezone); err != nil {
+		window.Location = time.UTC
+	}
+
+	return window
+}
+
+// AccessControlTimeWindow represents the days and hours during which an ACL permits access. The hours are wall clock
+// times in the location so daylight saving time transitions are handled by the time zone database.
+type AccessControlTimeWindow struct {
+	Days []time.Weekday
+
+	StartHour, StartMinute int
+	EndHour, EndMinute     int
+
+	Location *time.Location
+
+	// TerminateSessions denies sessions established during the time window once the time window has closed.
+	TerminateSessions bool
+}
+
+// IsWithin returns true if the time is within the time window. When the end is before the start the time window spans
+// midnight and the days apply to the day the time window starts.
+func (w AccessControlTimeWindow) IsWithin(t time.Time) bool {
+	t = t.In(w.Location)
+
+	clock, start, end := t.Hour()*60+t.Minute(), w.StartHour*60+w.StartMinute, w.EndHour*60+w.EndMinute
+
+	switch {
+	case start < end:
+		return clock >= start && clock < end && w.isDay(t.Weekday())
+	case clock >= start:
+		return w.isDay(t.Weekday())
+	case clock < end:
+		return w.isDay(t.AddDate(0, 0, -1).Weekday())
+	default:
+		return false
+	}
+}
+
+// IsEstablishedWithin returns true if the established time is within the most recent occurrence of the time window
+// which started at or before now.
+func (w AccessControlTimeWindow) IsEstablishedWithin(established, now time.Time) bool {
+	now = now.In(w.Location)
+
+	// The most recent start is always within the last week when at least one day is permitted.
+	for i := 0; i <= 7; i++ {
+		day := now.AddDate(0, 0, -i)
+
+		start := time.Date(day.Year(), day.Month(), day.Day(), w.StartHour, w.StartMinute, 0, 0, w.Location)
+
+		if start.After(now) || !w.isDay(start.Weekday()) {
+			continue
+		}
+
+		return !established.Before(start) && w.IsWithin(established)
+	}
+
+	return false
+}
+
+func (w AccessControlTimeWindow) isDay(weekday time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+
+	for _, day := range w.Days {
+		if day == weekday {
+			return true
+		}
+	}
+
+	return false
+}
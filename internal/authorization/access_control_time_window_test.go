@@ -0,0 +1,94 @@
+package authorization
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldNotCreateTimeWindowWhenNotConfigured(t *testing.T) {
+	assert.Nil(t, NewAccessControlTimeWindow(nil))
+}
+
+func TestShouldCreateTimeWindow(t *testing.T) {
+	window := NewAccessControlTimeWindow(&schema.ACLTimeWindow{
+		Days:              []string{"Monday", "friday"},
+		Start:             "09:30",
+		End:               "17:00",
+		Timezone:          "Europe/Berlin",
+		TerminateSessions: true,
+	})
+
+	require.NotNil(t, window)
+
+	assert.Equal(t, []time.Weekday{time.Monday, time.Friday}, window.Days)
+	assert.Equal(t, 9, window.StartHour)
+	assert.Equal(t, 30, window.StartMinute)
+	assert.Equal(t, 17, window.EndHour)
+	assert.Equal(t, 0, window.EndMinute)
+	assert.Equal(t, "Europe/Berlin", window.Location.String())
+	assert.True(t, window.TerminateSessions)
+}
+
+func TestShouldMatchTimeWindowWithDaylightSavingTime(t *testing.T) {
+	window := NewAccessControlTimeWindow(&schema.ACLTimeWindow{
+		Days:     []string{"monday", "tuesday", "wednesday", "thursday", "friday"},
+		Start:    "09:00",
+		End:      "17:00",
+		Timezone: "Europe/Berlin",
+	})
+
+	testCases := []struct {
+		name     string
+		have     time.Time
+		expected bool
+	}{
+		{"ShouldMatchWinterMorning", time.Date(2022, time.January, 10, 8, 30, 0, 0, time.UTC), true},
+		{"ShouldNotMatchWinterBeforeStart", time.Date(2022, time.January, 10, 7, 30, 0, 0, time.UTC), false},
+		{"ShouldMatchSummerMorning", time.Date(2022, time.July, 11, 7, 30, 0, 0, time.UTC), true},
+		{"ShouldNotMatchSummerAfterEnd", time.Date(2022, time.July, 11, 15, 30, 0, 0, time.UTC), false},
+		{"ShouldMatchWinterAfternoon", time.Date(2022, time.January, 10, 15, 30, 0, 0, time.UTC), true},
+		{"ShouldNotMatchWeekend", time.Date(2022, time.January, 8, 10, 0, 0, 0, time.UTC), false},
+		{"ShouldNotMatchAtEnd", time.Date(2022, time.January, 10, 16, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, window.IsWithin(tc.have))
+		})
+	}
+}
+
+func TestShouldMatchTimeWindowSpanningMidnight(t *testing.T) {
+	window := NewAccessControlTimeWindow(&schema.ACLTimeWindow{
+		Days:     []string{"friday"},
+		Start:    "22:00",
+		End:      "06:00",
+		Timezone: "UTC",
+	})
+
+	assert.True(t, window.IsWithin(time.Date(2022, time.January, 7, 23, 0, 0, 0, time.UTC)))
+	assert.True(t, window.IsWithin(time.Date(2022, time.January, 8, 5, 59, 0, 0, time.UTC)))
+	assert.False(t, window.IsWithin(time.Date(2022, time.January, 8, 6, 0, 0, 0, time.UTC)))
+	assert.False(t, window.IsWithin(time.Date(2022, time.January, 8, 23, 0, 0, 0, time.UTC)))
+	assert.False(t, window.IsWithin(time.Date(2022, time.January, 7, 5, 0, 0, 0, time.UTC)))
+}
+
+func TestShouldDetermineIfEstablishedWithinTimeWindow(t *testing.T) {
+	window := NewAccessControlTimeWindow(&schema.ACLTimeWindow{
+		Start:    "09:00",
+		End:      "17:00",
+		Timezone: "UTC",
+	})
+
+	now := time.Date(2022, time.January, 10, 18, 0, 0, 0, time.UTC)
+
+	assert.True(t, window.IsEstablishedWithin(time.Date(2022, time.January, 10, 16, 0, 0, 0, time.UTC), now))
+	assert.False(t, window.IsEstablishedWithin(time.Date(2022, time.January, 10, 8, 0, 0, 0, time.UTC), now))
+	assert.False(t, window.IsEstablishedWithin(time.Date(2022, time.January, 9, 16, 0, 0, 0, time.UTC), now))
+	assert.True(t, window.IsEstablishedWithin(time.Date(2022, time.January, 10, 10, 0, 0, 0, time.UTC), time.Date(2022, time.January, 11, 8, 0, 0, 0, time.UTC)))
+}
@@ -1,6 +1,8 @@
 package authorization
 
 import (
+	"strings"
+
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
 )
@@ -9,6 +11,7 @@ import (
 type Authorizer struct {
 	defaultPolicy Level
 	rules         []*AccessControlRule
+	debugTrace    bool
 	configuration *schema.Configuration
 }
 
@@ -17,6 +20,7 @@ func NewAuthorizer(configuration *schema.Configuration) *Authorizer {
 	return &Authorizer{
 		defaultPolicy: PolicyToLevel(configuration.AccessControl.DefaultPolicy),
 		rules:         NewAccessControlRules(configuration.AccessControl),
+		debugTrace:    configuration.AccessControl.DebugTrace,
 		configuration: configuration,
 	}
 }
@@ -31,6 +35,12 @@ func (p Authorizer) IsSecondFactorEnabled() bool {
 		if rule.Policy == TwoFactor {
 			return true
 		}
+
+		for _, networkPolicy := range rule.NetworkPolicies {
+			if networkPolicy.Policy == TwoFactor {
+				return true
+			}
+		}
 	}
 
 	if p.configuration.IdentityProviders.OIDC != nil {
@@ -46,16 +56,35 @@ func (p Authorizer) IsSecondFactorEnabled() bool {
 
 // GetRequiredLevel retrieve the required level of authorization to access the object.
 func (p Authorizer) GetRequiredLevel(subject Subject, object Object) Level {
+	level, _ := p.GetRequiredLevelAndRule(subject, object)
+
+	return level
+}
+
+// GetRequiredLevelAndRule retrieve the required level of authorization to access the object and the rule which
+// matched. The rule is nil when no rule matched and the default policy was applied. Rules in shadow mode are skipped
+// when their policy for the subject is shadowed, see GetShadowLevelAndRule.
+func (p Authorizer) GetRequiredLevelAndRule(subject Subject, object Object) (level Level, matched *AccessControlRule) {
 	logger := logging.Logger()
 
 	logger.Debugf("Check authorization of subject %s and object %s (method %s).",
 		subject.String(), object.String(), object.Method)
 
+	if p.debugTrace {
+		p.logDecisionTrace(subject, object)
+	}
+
 	for _, rule := range p.rules {
 		if rule.IsMatch(subject, object) {
+			if level = rule.GetPolicy(subject); rule.IsShadowed(level) {
+				logger.Tracef(traceFmtACLHitMiss, "SHADOW", rule.Position, subject.String(), object.String(), object.Method)
+
+				continue
+			}
+
 			logger.Tracef(traceFmtACLHitMiss, "HIT", rule.Position, subject.String(), object.String(), object.Method)
 
-			return rule.Policy
+			return level, rule
 		}
 
 		logger.Tracef(traceFmtACLHitMiss, "MISS", rule.Position, subject.String(), object.String(), object.Method)
@@ -64,7 +93,26 @@ func (p Authorizer) GetRequiredLevel(subject Subject, object Object) Level {
 	logger.Debugf("No matching rule for subject %s and url %s... Applying default policy.",
 		subject.String(), object.String())
 
-	return p.defaultPolicy
+	return p.defaultPolicy, nil
+}
+
+// GetShadowLevelAndRule returns the first rule in shadow mode which matched before the rule applied by
+// GetRequiredLevelAndRule and the level it would have required if it was enforced. The rule is nil when no rule in shadow
+// mode matched.
+func (p Authorizer) GetShadowLevelAndRule(subject Subject, object Object) (level Level, matched *AccessControlRule) {
+	for _, rule := range p.rules {
+		if !rule.IsMatch(subject, object) {
+			continue
+		}
+
+		if level = rule.GetPolicy(subject); rule.IsShadowed(level) {
+			return level, rule
+		}
+
+		break
+	}
+
+	return Bypass, nil
 }
 
 // GetRuleMatchResults iterates through the rules and produces a list of RuleMatchResult provided a subject and object.
@@ -74,20 +122,62 @@ func (p Authorizer) GetRuleMatchResults(subject Subject, object Object) (results
 	results = make([]RuleMatchResult, len(p.rules))
 
 	for i, rule := range p.rules {
-		results[i] = RuleMatchResult{
-			Rule:    rule,
-			Skipped: skipped,
-
-			MatchDomain:        isMatchForDomains(subject, object, rule),
-			MatchResources:     isMatchForResources(object, rule),
-			MatchMethods:       isMatchForMethods(object, rule),
-			MatchNetworks:      isMatchForNetworks(subject, rule),
-			MatchSubjects:      isMatchForSubjects(subject, rule),
-			MatchSubjectsExact: isExactMatchForSubjects(subject, rule),
-		}
+		results[i] = newRuleMatchResult(subject, object, rule, skipped)
 
-		skipped = skipped || results[i].IsMatch()
+		skipped = skipped || (results[i].IsMatch() && !rule.IsShadowed(rule.GetPolicy(subject)))
 	}
 
 	return results
 }
+
+// logDecisionTrace logs the evaluation of every criteria of each rule for the subject and object at the debug level.
+// The evaluation stops at the first rule which matches and isn't shadowed as the rules after it are never considered.
+func (p Authorizer) logDecisionTrace(subject Subject, object Object) {
+	logger := logging.Logger()
+
+	for _, rule := range p.rules {
+		result := newRuleMatchResult(subject, object, rule, false)
+
+		matched := rule.IsMatch(subject, object)
+
+		decision := "MISS"
+
+		if matched {
+			policy := rule.GetPolicy(subject)
+
+			if rule.IsShadowed(policy) {
+				decision = "SHADOW policy " + LevelToPolicy(policy)
+				matched = false
+			} else {
+				decision = "HIT policy " + LevelToPolicy(policy)
+			}
+		}
+
+		logger.Debugf(traceFmtACLDecision, rule.Position, rule.Name, subject.Username, strings.Join(subject.Groups, ","),
+			subject.IP.String(), object.String(), object.Method, result.MatchDomain, result.MatchResources,
+			result.MatchMethods, result.MatchHeaders, result.MatchNetworks, result.MatchSubjects, result.MatchSubjectsExact,
+			decision)
+
+		if matched {
+			return
+		}
+	}
+
+	logger.Debugf(traceFmtACLDecisionDefault, subject.Username, strings.Join(subject.Groups, ","), subject.IP.String(),
+		object.String(), object.Method, LevelToPolicy(p.defaultPolicy))
+}
+
+func newRuleMatchResult(subject Subject, object Object, rule *AccessControlRule, skipped bool) (result RuleMatchResult) {
+	return RuleMatchResult{
+		Rule:    rule,
+		Skipped: skipped,
+
+		MatchDomain:        isMatchForDomains(subject, object, rule),
+		MatchResources:     isMatchForResources(object, rule),
+		MatchMethods:       isMatchForMethods(object, rule),
+		MatchHeaders:       isMatchForHeaders(object, rule),
+		MatchNetworks:      isMatchForNetworks(subject, rule),
+		MatchSubjects:      isMatchForSubjects(subject, rule),
+		MatchSubjectsExact: isExactMatchForSubjects(subject, rule),
+	}
+}
@@ -2,15 +2,20 @@ package authorization
 
 import (
 	"net"
+	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
 )
 
 type AuthorizerSuite struct {
@@ -41,6 +46,35 @@ func (s *AuthorizerTester) CheckAuthorizations(t *testing.T, subject Subject, re
 	assert.Equal(t, expectedLevel, level)
 }
 
+func (s *AuthorizerTester) CheckAuthorizationsWithHeader(t *testing.T, subject Subject, requestURI, method string, header http.Header, expectedLevel Level) {
+	targetURL, _ := url.ParseRequestURI(requestURI)
+
+	object := NewObject(targetURL, method)
+	object.Header = header
+
+	level := s.GetRequiredLevel(subject, object)
+
+	assert.Equal(t, expectedLevel, level)
+}
+
+func (s *AuthorizerTester) CheckShadowAuthorizations(t *testing.T, subject Subject, requestURI, method string, expectedLevel Level, expectedPosition int) {
+	targetURL, _ := url.ParseRequestURI(requestURI)
+
+	object := NewObject(targetURL, method)
+
+	level, rule := s.GetShadowLevelAndRule(subject, object)
+
+	if expectedPosition == 0 {
+		assert.Nil(t, rule)
+
+		return
+	}
+
+	require.NotNil(t, rule)
+	assert.Equal(t, expectedPosition, rule.Position)
+	assert.Equal(t, expectedLevel, level)
+}
+
 func (s *AuthorizerTester) GetRuleMatchResults(subject Subject, requestURI, method string) (results []RuleMatchResult) {
 	targetURL, _ := url.ParseRequestURI(requestURI)
 
@@ -67,6 +101,18 @@ func (b *AuthorizerTesterBuilder) WithRule(rule schema.ACLRule) *AuthorizerTeste
 	return b
 }
 
+func (b *AuthorizerTesterBuilder) WithDebugTrace() *AuthorizerTesterBuilder {
+	b.config.DebugTrace = true
+	return b
+}
+
+func (b *AuthorizerTesterBuilder) WithShadow(shadow, shadowTwoFactor bool) *AuthorizerTesterBuilder {
+	b.config.Shadow = shadow
+	b.config.ShadowTwoFactor = shadowTwoFactor
+
+	return b
+}
+
 func (b *AuthorizerTesterBuilder) Build() *AuthorizerTester {
 	return NewAuthorizerTester(b.config)
 }
@@ -446,6 +492,47 @@ func (s *AuthorizerSuite) TestShouldCheckIPMatching() {
 	tester.CheckAuthorizations(s.T(), Sam, "https://ipv6.example.com/", "GET", TwoFactor)
 }
 
+func (s *AuthorizerSuite) TestShouldCheckNetworkPolicies() {
+	tester := NewAuthorizerTester(schema.AccessControlConfiguration{
+		DefaultPolicy: deny,
+		Networks: []schema.ACLNetwork{
+			{Name: "office", Networks: []string{"10.0.0.0/8"}},
+		},
+		Rules: []schema.ACLRule{
+			{
+				Domains: []string{"app.example.com"},
+				Policy:  twoFactor,
+				NetworkPolicies: []schema.ACLNetworkPolicy{
+					// The first network policy which contains the IP applies when networks overlap.
+					{Networks: []string{"10.0.0.7"}, Policy: deny},
+					{Networks: []string{"office"}, Policy: oneFactor},
+					{Networks: []string{"10.0.0.0/16"}, Policy: bypass},
+					{Networks: []string{"fec0::/64"}, Policy: oneFactor},
+				},
+			},
+			{
+				Domains: []string{"admin.example.com"},
+				Policy:  oneFactor,
+				NetworkPolicies: []schema.ACLNetworkPolicy{
+					{Networks: []string{"0.0.0.0/0"}, Policy: twoFactor},
+					{Networks: []string{"10.0.0.0/8"}, Policy: oneFactor},
+				},
+			},
+		},
+	})
+
+	tester.CheckAuthorizations(s.T(), Bob, "https://app.example.com/", "GET", Denied)
+	tester.CheckAuthorizations(s.T(), John, "https://app.example.com/", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), Sam, "https://app.example.com/", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), AnonymousUser, "https://app.example.com/", "GET", TwoFactor)
+
+	tester.CheckAuthorizations(s.T(), John, "https://admin.example.com/", "GET", TwoFactor)
+	tester.CheckAuthorizations(s.T(), AnonymousUser, "https://admin.example.com/", "GET", TwoFactor)
+	tester.CheckAuthorizations(s.T(), Sam, "https://admin.example.com/", "GET", OneFactor)
+
+	s.Assert().True(tester.IsSecondFactorEnabled())
+}
+
 func (s *AuthorizerSuite) TestShouldCheckMethodMatching() {
 	tester := NewAuthorizerBuilder().
 		WithDefaultPolicy(deny).
@@ -479,6 +566,106 @@ func (s *AuthorizerSuite) TestShouldCheckMethodMatching() {
 	tester.CheckAuthorizations(s.T(), AnonymousUser, "https://protected.example.com/", "DELETE", TwoFactor)
 }
 
+func (s *AuthorizerSuite) TestShouldCheckMethodConditionedPolicies() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.ACLRule{
+			Domains: []string{"app.example.com"},
+			Policy:  oneFactor,
+			Methods: []string{"GET", "HEAD"},
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"app.example.com"},
+			Policy:  twoFactor,
+		}).
+		Build()
+
+	tester.CheckAuthorizations(s.T(), John, "https://app.example.com/", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://app.example.com/", "head", OneFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://app.example.com/", "POST", TwoFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://app.example.com/", "PUT", TwoFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://app.example.com/", "DELETE", TwoFactor)
+
+	// Requests without a method never match the rules which have methods.
+	tester.CheckAuthorizations(s.T(), John, "https://app.example.com/", "", TwoFactor)
+}
+
+func (s *AuthorizerSuite) TestShouldApplyRulesAfterShadowedRules() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(oneFactor).
+		WithRule(schema.ACLRule{
+			Domains:  []string{"app.example.com"},
+			Policy:   deny,
+			Subjects: [][]string{{"user:john"}},
+			Shadow:   true,
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"app.example.com"},
+			Policy:  twoFactor,
+			Shadow:  true,
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"app.example.com"},
+			Policy:  bypass,
+			Shadow:  true,
+		}).
+		Build()
+
+	// The deny policy of the first rule is shadowed, the two_factor policy of the second rule is enforced.
+	tester.CheckAuthorizations(s.T(), John, "https://app.example.com/", "GET", TwoFactor)
+	tester.CheckAuthorizations(s.T(), Bob, "https://app.example.com/", "GET", TwoFactor)
+
+	tester.CheckShadowAuthorizations(s.T(), John, "https://app.example.com/", "GET", Denied, 1)
+	tester.CheckShadowAuthorizations(s.T(), Bob, "https://app.example.com/", "GET", Bypass, 0)
+
+	results := tester.GetRuleMatchResults(John, "https://app.example.com/", "GET")
+
+	s.Require().Len(results, 3)
+	s.Assert().False(results[0].Skipped)
+	s.Assert().False(results[1].Skipped)
+	s.Assert().True(results[2].Skipped)
+}
+
+func (s *AuthorizerSuite) TestShouldShadowTwoFactorOnlyWhenIncluded() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(oneFactor).
+		WithShadow(true, false).
+		WithRule(schema.ACLRule{
+			Domains: []string{"deny.example.com"},
+			Policy:  deny,
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"*.example.com"},
+			Policy:  twoFactor,
+		}).
+		Build()
+
+	tester.CheckAuthorizations(s.T(), John, "https://deny.example.com/", "GET", TwoFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://app.example.com/", "GET", TwoFactor)
+
+	tester.CheckShadowAuthorizations(s.T(), John, "https://deny.example.com/", "GET", Denied, 1)
+	tester.CheckShadowAuthorizations(s.T(), John, "https://app.example.com/", "GET", Bypass, 0)
+
+	tester = NewAuthorizerBuilder().
+		WithDefaultPolicy(oneFactor).
+		WithShadow(true, true).
+		WithRule(schema.ACLRule{
+			Domains: []string{"deny.example.com"},
+			Policy:  deny,
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"*.example.com"},
+			Policy:  twoFactor,
+		}).
+		Build()
+
+	tester.CheckAuthorizations(s.T(), John, "https://deny.example.com/", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://app.example.com/", "GET", OneFactor)
+
+	tester.CheckShadowAuthorizations(s.T(), John, "https://deny.example.com/", "GET", Denied, 1)
+	tester.CheckShadowAuthorizations(s.T(), John, "https://app.example.com/", "GET", TwoFactor, 2)
+}
+
 func (s *AuthorizerSuite) TestShouldCheckResourceMatching() {
 	createSliceRegexRule := func(t *testing.T, rules []string) []regexp.Regexp {
 		result, err := stringSliceToRegexpSlice(rules)
@@ -510,6 +697,37 @@ func (s *AuthorizerSuite) TestShouldCheckResourceMatching() {
 	tester.CheckAuthorizations(s.T(), John, "https://resource.example.com/xyz/embedded/abc", "GET", Bypass)
 }
 
+func (s *AuthorizerSuite) TestShouldCheckHeaderMatching() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.ACLRule{
+			Domains:    []string{"app.example.com"},
+			Policy:     deny,
+			UserAgents: []regexp.Regexp{*regexp.MustCompile(`MSIE [5-9]\.`), *regexp.MustCompile(`^curl/`)},
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"app.example.com"},
+			Policy:  oneFactor,
+			Headers: []schema.ACLHeader{
+				{Name: "x-vpn-client", Value: regexp.MustCompile(`^(yes|true)$`)},
+				{Name: "X-VPN-Network"},
+			},
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"app.example.com"},
+			Policy:  twoFactor,
+		}).
+		Build()
+
+	tester.CheckAuthorizationsWithHeader(s.T(), John, "https://app.example.com/", "GET", nil, TwoFactor)
+	tester.CheckAuthorizationsWithHeader(s.T(), John, "https://app.example.com/", "GET", http.Header{"User-Agent": []string{"Mozilla/4.0 (compatible; MSIE 6.0; Windows NT 5.1)"}}, Denied)
+	tester.CheckAuthorizationsWithHeader(s.T(), John, "https://app.example.com/", "GET", http.Header{"User-Agent": []string{"curl/7.80.0"}, "X-Vpn-Client": []string{"yes"}, "X-Vpn-Network": []string{"office"}}, Denied)
+	tester.CheckAuthorizationsWithHeader(s.T(), John, "https://app.example.com/", "GET", http.Header{"User-Agent": []string{"Mozilla/5.0"}, "X-Vpn-Client": []string{"yes"}, "X-Vpn-Network": []string{"office"}}, OneFactor)
+	tester.CheckAuthorizationsWithHeader(s.T(), John, "https://app.example.com/", "GET", http.Header{"X-Vpn-Client": []string{"no", "true"}, "X-Vpn-Network": []string{""}}, OneFactor)
+	tester.CheckAuthorizationsWithHeader(s.T(), John, "https://app.example.com/", "GET", http.Header{"X-Vpn-Client": []string{"no"}, "X-Vpn-Network": []string{"office"}}, TwoFactor)
+	tester.CheckAuthorizationsWithHeader(s.T(), John, "https://app.example.com/", "GET", http.Header{"X-Vpn-Client": []string{"yes"}}, TwoFactor)
+}
+
 // This test assures that rules without domains (not allowed by schema validator at this time) will pass validation correctly.
 func (s *AuthorizerSuite) TestShouldMatchAnyDomainIfBlank() {
 	tester := NewAuthorizerBuilder().
@@ -668,6 +886,134 @@ func (s *AuthorizerSuite) TestShouldMatchResourceWithSubjectRules() {
 	assert.True(s.T(), results[6].MatchMethods)
 }
 
+func (s *AuthorizerSuite) TestShouldReturnMatchedRule() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.ACLRule{
+			Domains: []string{"public.example.com"},
+			Policy:  bypass,
+		}).
+		WithRule(schema.ACLRule{
+			Name:    "protected",
+			Domains: []string{"protected.example.com"},
+			Policy:  oneFactor,
+		}).
+		Build()
+
+	targetURL, _ := url.ParseRequestURI("https://public.example.com/")
+	level, rule := tester.GetRequiredLevelAndRule(AnonymousUser, NewObject(targetURL, "GET"))
+
+	s.Assert().Equal(Bypass, level)
+	s.Require().NotNil(rule)
+	s.Assert().Equal(1, rule.Position)
+	s.Assert().Equal("", rule.Name)
+
+	targetURL, _ = url.ParseRequestURI("https://protected.example.com/")
+	level, rule = tester.GetRequiredLevelAndRule(AnonymousUser, NewObject(targetURL, "GET"))
+
+	s.Assert().Equal(OneFactor, level)
+	s.Require().NotNil(rule)
+	s.Assert().Equal(2, rule.Position)
+	s.Assert().Equal("protected", rule.Name)
+
+	targetURL, _ = url.ParseRequestURI("https://unknown.example.com/")
+	level, rule = tester.GetRequiredLevelAndRule(AnonymousUser, NewObject(targetURL, "GET"))
+
+	s.Assert().Equal(Denied, level)
+	s.Assert().Nil(rule)
+}
+
+func (s *AuthorizerSuite) TestShouldLogDecisionTraceWhenEnabled() {
+	hook := test.NewLocal(logging.Logger())
+	defer hook.Reset()
+
+	logLevel := logging.Logger().GetLevel()
+	defer logging.Logger().SetLevel(logLevel)
+
+	logging.Logger().SetLevel(logrus.DebugLevel)
+
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithDebugTrace().
+		WithRule(schema.ACLRule{
+			Name:      "admins",
+			Domains:   []string{"protected.example.com"},
+			Resources: []regexp.Regexp{*regexp.MustCompile("^/admin.*$")},
+			Subjects:  [][]string{{"group:admins"}},
+			Policy:    twoFactor,
+		}).
+		WithRule(schema.ACLRule{
+			Domains:  []string{"protected.example.com"},
+			Networks: []string{"10.0.0.0/8"},
+			Policy:   oneFactor,
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"*.example.com"},
+			Policy:  bypass,
+		}).
+		Build()
+
+	targetURL, _ := url.ParseRequestURI("https://protected.example.com/public")
+	level, _ := tester.GetRequiredLevelAndRule(John, NewObject(targetURL, "GET"))
+
+	s.Assert().Equal(OneFactor, level)
+
+	var traces []string
+
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.DebugLevel && strings.HasPrefix(entry.Message, "ACL Decision Trace") {
+			traces = append(traces, entry.Message)
+		}
+	}
+
+	// The trace stops at the first rule which matches.
+	s.Require().Len(traces, 2)
+	s.Assert().Equal("ACL Decision Trace Position 1 (name 'admins') for username 'john' groups 'dev,admins' ip '10.0.0.8' "+
+		"and target https://protected.example.com/public (method GET): domain=true resources=false methods=true "+
+		"headers=true networks=true subjects=true subjects_exact=true result=MISS", traces[0])
+	s.Assert().Equal("ACL Decision Trace Position 2 (name '') for username 'john' groups 'dev,admins' ip '10.0.0.8' "+
+		"and target https://protected.example.com/public (method GET): domain=true resources=true methods=true "+
+		"headers=true networks=true subjects=true subjects_exact=true result=HIT policy one_factor", traces[1])
+
+	hook.Reset()
+
+	targetURL, _ = url.ParseRequestURI("https://unknown.com/")
+	level, _ = tester.GetRequiredLevelAndRule(Bob, NewObject(targetURL, "POST"))
+
+	s.Assert().Equal(Denied, level)
+
+	entries := hook.AllEntries()
+
+	s.Require().Len(entries, 6)
+	s.Assert().Equal("ACL Decision Trace no rule matched for username 'bob' groups '' ip '10.0.0.7' and target "+
+		"https://unknown.com/ (method POST): result=default policy deny", entries[4].Message)
+}
+
+func (s *AuthorizerSuite) TestShouldNotLogDecisionTraceWhenDisabled() {
+	hook := test.NewLocal(logging.Logger())
+	defer hook.Reset()
+
+	logLevel := logging.Logger().GetLevel()
+	defer logging.Logger().SetLevel(logLevel)
+
+	logging.Logger().SetLevel(logrus.DebugLevel)
+
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.ACLRule{
+			Domains: []string{"protected.example.com"},
+			Policy:  oneFactor,
+		}).
+		Build()
+
+	targetURL, _ := url.ParseRequestURI("https://protected.example.com/")
+	tester.GetRequiredLevel(John, NewObject(targetURL, "GET"))
+
+	for _, entry := range hook.AllEntries() {
+		s.Assert().False(strings.HasPrefix(entry.Message, "ACL Decision Trace"))
+	}
+}
+
 func (s *AuthorizerSuite) TestPolicyToLevel() {
 	s.Assert().Equal(Bypass, PolicyToLevel(bypass))
 	s.Assert().Equal(OneFactor, PolicyToLevel(oneFactor))
@@ -781,3 +1127,26 @@ func TestAuthorizerIsSecondFactorEnabledRuleWithOIDC(t *testing.T) {
 
 	assert.True(t, authorizer.IsSecondFactorEnabled())
 }
+
+func TestAuthorizerIsSecondFactorEnabledNetworkPolicy(t *testing.T) {
+	config := &schema.Configuration{
+		AccessControl: schema.AccessControlConfiguration{
+			DefaultPolicy: deny,
+			Rules: []schema.ACLRule{
+				{
+					Domains: []string{"example.com"},
+					Policy:  oneFactor,
+					NetworkPolicies: []schema.ACLNetworkPolicy{
+						{Networks: []string{"10.0.0.0/8"}, Policy: bypass},
+					},
+				},
+			},
+		},
+	}
+
+	authorizer := NewAuthorizer(config)
+	assert.False(t, authorizer.IsSecondFactorEnabled())
+
+	authorizer.rules[0].NetworkPolicies[0].Policy = TwoFactor
+	assert.True(t, authorizer.IsSecondFactorEnabled())
+}
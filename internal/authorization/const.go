@@ -26,6 +26,8 @@ const (
 	deny      = "deny"
 )
 
+const headerUserAgent = "User-Agent"
+
 const (
 	subexpNameUser  = "User"
 	subexpNameGroup = "Group"
@@ -37,3 +39,10 @@ var (
 )
 
 const traceFmtACLHitMiss = "ACL %s Position %d for subject %s and object %s (Method %s)"
+
+const (
+	traceFmtACLDecision = "ACL Decision Trace Position %d (name '%s') for username '%s' groups '%s' ip '%s' and target %s " +
+		"(method %s): domain=%t resources=%t methods=%t headers=%t networks=%t subjects=%t subjects_exact=%t result=%s"
+	traceFmtACLDecisionDefault = "ACL Decision Trace no rule matched for username '%s' groups '%s' ip '%s' and target %s " +
+		"(method %s): result=default policy %s"
+)
@@ -3,6 +3,7 @@ package authorization
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"strings"
 )
@@ -41,6 +42,7 @@ type Object struct {
 	Domain string
 	Path   string
 	Method string
+	Header http.Header
 }
 
 // String is a string representation of the Object.
@@ -53,12 +55,13 @@ func NewObjectRaw(targetURL *url.URL, method []byte) (object Object) {
 	return NewObject(targetURL, string(method))
 }
 
-// NewObject creates a new Object type from a URL and a method header.
+// NewObject creates a new Object type from a URL and a method header. The method is upper-cased like the methods of
+// the rules so it matches regardless of how the proxy forwarded it.
 func NewObject(targetURL *url.URL, method string) (object Object) {
 	object = Object{
 		Scheme: targetURL.Scheme,
 		Domain: targetURL.Hostname(),
-		Method: method,
+		Method: strings.ToUpper(method),
 	}
 
 	if targetURL.RawQuery == "" {
@@ -79,6 +82,7 @@ type RuleMatchResult struct {
 	MatchDomain        bool
 	MatchResources     bool
 	MatchMethods       bool
+	MatchHeaders       bool
 	MatchNetworks      bool
 	MatchSubjects      bool
 	MatchSubjectsExact bool
@@ -86,10 +90,10 @@ type RuleMatchResult struct {
 
 // IsMatch returns true if all the criteria matched.
 func (r RuleMatchResult) IsMatch() (match bool) {
-	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchNetworks && r.MatchSubjectsExact
+	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchHeaders && r.MatchNetworks && r.MatchSubjectsExact
 }
 
 // IsPotentialMatch returns true if the rule is potentially a match.
 func (r RuleMatchResult) IsPotentialMatch() (match bool) {
-	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchNetworks && r.MatchSubjects && !r.MatchSubjectsExact
+	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchHeaders && r.MatchNetworks && r.MatchSubjects && !r.MatchSubjectsExact
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // PolicyToLevel converts a string policy to int authorization level.
@@ -98,13 +99,21 @@ func schemaMethodsToACL(methodRules []string) (methods []string) {
 	return methods
 }
 
+func schemaHeadersToACL(headerRules []schema.ACLHeader) (headers []AccessControlHeader) {
+	for _, header := range headerRules {
+		headers = append(headers, AccessControlHeader{Name: header.Name, Value: header.Value})
+	}
+
+	return headers
+}
+
 func schemaNetworksToACL(networkRules []string, networksMap map[string][]*net.IPNet, networksCacheMap map[string]*net.IPNet) (networks []*net.IPNet) {
 	for _, network := range networkRules {
 		if _, ok := networksMap[network]; !ok {
 			if _, ok := networksCacheMap[network]; ok {
 				networks = append(networks, networksCacheMap[network])
 			} else {
-				cidr, err := parseNetwork(network)
+				cidr, err := utils.ParseNetwork(network)
 				if err == nil {
 					networks = append(networks, cidr)
 					networksCacheMap[cidr.String()] = cidr
@@ -122,6 +131,17 @@ func schemaNetworksToACL(networkRules []string, networksMap map[string][]*net.IP
 	return networks
 }
 
+func schemaNetworkPoliciesToACL(networkPolicyRules []schema.ACLNetworkPolicy, networksMap map[string][]*net.IPNet, networksCacheMap map[string]*net.IPNet) (networkPolicies []AccessControlNetworkPolicy) {
+	for _, networkPolicyRule := range networkPolicyRules {
+		networkPolicies = append(networkPolicies, AccessControlNetworkPolicy{
+			Networks: schemaNetworksToACL(networkPolicyRule.Networks, networksMap, networksCacheMap),
+			Policy:   PolicyToLevel(networkPolicyRule.Policy),
+		})
+	}
+
+	return networkPolicies
+}
+
 func parseSchemaNetworks(schemaNetworks []schema.ACLNetwork) (networksMap map[string][]*net.IPNet, networksCacheMap map[string]*net.IPNet) {
 	// These maps store pointers to the net.IPNet values so we can reuse them efficiently.
 	// The networksMap contains the named networks as keys, the networksCacheMap contains the CIDR notations as keys.
@@ -132,7 +152,7 @@ func parseSchemaNetworks(schemaNetworks []schema.ACLNetwork) (networksMap map[st
 		var networks []*net.IPNet
 
 		for _, networkRule := range aclNetwork.Networks {
-			cidr, err := parseNetwork(networkRule)
+			cidr, err := utils.ParseNetwork(networkRule)
 			if err == nil {
 				networks = append(networks, cidr)
 				networksCacheMap[cidr.String()] = cidr
@@ -151,21 +171,6 @@ func parseSchemaNetworks(schemaNetworks []schema.ACLNetwork) (networksMap map[st
 	return networksMap, networksCacheMap
 }
 
-func parseNetwork(networkRule string) (cidr *net.IPNet, err error) {
-	if !strings.Contains(networkRule, "/") {
-		ip := net.ParseIP(networkRule)
-		if ip.To4() != nil {
-			_, cidr, err = net.ParseCIDR(networkRule + "/32")
-		} else {
-			_, cidr, err = net.ParseCIDR(networkRule + "/128")
-		}
-	} else {
-		_, cidr, err = net.ParseCIDR(networkRule)
-	}
-
-	return cidr, err
-}
-
 func schemaSubjectsToACL(subjectRules [][]string) (subjects []AccessControlSubjects) {
 	for _, subjectRule := range subjectRules {
 		subject := AccessControlSubjects{}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"errors"
+	"regexp"
 )
 
 const cmdAutheliaExample = `authelia --config /etc/authelia/config.yml --config /etc/authelia/access-control.yml
@@ -108,6 +109,10 @@ const (
 	storageExportFormatPNG = "png"
 )
 
+const (
+	storageTOTPDescriptionDefault = "Primary"
+)
+
 var (
 	errNoStorageProvider = errors.New("no storage provider configured")
 )
@@ -115,3 +120,71 @@ var (
 const (
 	identifierServiceOpenIDConnect = "openid_connect"
 )
+
+const (
+	// passwordResetCodeCharacters excludes the characters which are easily confused with one another.
+	passwordResetCodeCharacters = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	passwordResetCodeLength     = 16
+)
+
+const (
+	usersFormatCSV  = "csv"
+	usersFormatJSON = "json"
+)
+
+var (
+	errNoFileAuthenticationBackend = errors.New("the file authentication backend is not configured")
+)
+
+var usersCSVHeader = []string{"username", "displayname", "email", "groups", "password", "hashed_password"}
+
+const configValidateLong = `
+Check a configuration against the internal configuration validation mechanisms.
+
+All errors and warnings are output grouped by the configuration section they relate to along with the configuration key
+when it can be determined. The command exits with a non-zero exit code if there are any errors, or any warnings when
+strict mode is enabled.
+`
+
+const (
+	configValidateFormatText = "text"
+	configValidateFormatJSON = "json"
+
+	configValidateGroupGeneral = "general"
+)
+
+var (
+	errConfigurationInvalid = errors.New("the configuration is not valid")
+)
+
+var (
+	reConfigKeyGroup            = regexp.MustCompile(`^[^.\[]+`)
+	reConfigErrorKeyOnly        = regexp.MustCompile(`^(?:configuration key not expected: (\S+)|invalid configuration key '([^']+)')`)
+	reConfigErrorSegmentName    = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	reConfigErrorSegmentElement = regexp.MustCompile(`^([a-z_]+) (?:'([^']*)'|#(\d+))$`)
+	reConfigErrorOption         = regexp.MustCompile(`(?:'([a-z0-9_]+)' option|option '([a-z0-9_]+)')`)
+)
+
+const notifierTestLong = `
+Send a test notification using the configured notifier.
+
+The notification is sent using the same process as all other notifications. For the smtp notifier each step of the
+connection is logged and if it fails the likely cause is output along with the options to check. The password is never
+output. For the filesystem notifier the notification is written to the configured file.
+`
+
+const notifierTestExample = `authelia notifier test --config config.yml --recipient john@example.com`
+
+const (
+	notifierTestTitle = "Test Notification"
+	notifierTestBody  = "This is a test notification sent by the authelia notifier test command."
+)
+
+var (
+	errNotifierTestNoRecipient = errors.New("the recipient flag is required")
+	errNotifierTestFailed      = errors.New("failed to send the test notification")
+)
+
+var configErrorSegmentAliases = map[string]string{
+	"access control": "access_control",
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/authelia/authelia/v4/internal/configuration"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/oidc"
 )
 
 // NewHashPasswordCmd returns a new Hash Password Cmd.
@@ -58,8 +59,11 @@ func cmdHashPasswordRun(cmd *cobra.Command, args []string) {
 			iterations = config.AuthenticationBackend.File.Password.Iterations
 			keyLength = config.AuthenticationBackend.File.Password.KeyLength
 			saltLength = config.AuthenticationBackend.File.Password.SaltLength
-			memory = config.AuthenticationBackend.File.Password.Memory
 			parallelism = config.AuthenticationBackend.File.Password.Parallelism
+
+			if memory, err = authentication.ResolvePasswordMemory(config.AuthenticationBackend.File.Password); err != nil {
+				logger.Warnf("Unable to resolve the argon2id memory parameter from the available system memory, using the minimum of %d MB: %v", memory, err)
+			}
 		}
 	}
 
@@ -89,3 +93,28 @@ func cmdHashPasswordRun(cmd *cobra.Command, args []string) {
 
 	fmt.Printf("Password hash: %s\n", hash)
 }
+
+// NewHashClientSecretCmd returns a new Hash Client Secret Cmd.
+func NewHashClientSecretCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "hash-client-secret [secret]",
+		Short: "Hash a secret to be used as the secret of an OpenID Connect client. The algorithm is pbkdf2-sha512.",
+		Args:  cobra.ExactArgs(1),
+		Run:   cmdHashClientSecretRun,
+	}
+
+	cmd.Flags().IntP("iterations", "i", oidc.ClientSecretDefaultIterations, "set the number of hashing iterations")
+
+	return cmd
+}
+
+func cmdHashClientSecretRun(cmd *cobra.Command, args []string) {
+	iterations, _ := cmd.Flags().GetInt("iterations")
+
+	digest, err := oidc.HashClientSecret(args[0], iterations)
+	if err != nil {
+		logging.Logger().Fatalf("Error occurred during hashing: %v\n", err)
+	}
+
+	fmt.Printf("Client secret digest: %s\n", digest)
+}
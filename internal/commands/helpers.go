@@ -1,8 +1,12 @@
 package commands
 
 import (
+	"crypto/x509"
+
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/events"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/notification"
 	"github.com/authelia/authelia/v4/internal/ntp"
@@ -27,6 +31,44 @@ func getStorageProvider() (provider storage.Provider) {
 	}
 }
 
+func getNotifierProvider(config *schema.NotifierConfiguration, certPool *x509.CertPool) (notifier notification.Notifier) {
+	switch {
+	case config.SMTP != nil:
+		notifier = notification.NewSMTPNotifier(config.SMTP, certPool)
+	case config.FileSystem != nil:
+		notifier = notification.NewFileNotifier(*config.FileSystem)
+	}
+
+	if len(config.Notifiers) == 0 {
+		return notifier
+	}
+
+	notifiers := make([]notification.NamedNotifier, 0, len(config.Notifiers)+1)
+
+	if notifier != nil {
+		notifiers = append(notifiers, notification.NamedNotifier{Name: "default", Notifier: notifier})
+	}
+
+	for _, instance := range config.Notifiers {
+		named := notification.NamedNotifier{Name: instance.Name, Events: instance.Events}
+
+		switch {
+		case instance.SMTP != nil:
+			named.Notifier = notification.NewSMTPNotifier(instance.SMTP, certPool)
+		case instance.FileSystem != nil:
+			named.Notifier = notification.NewFileNotifier(*instance.FileSystem)
+		case instance.Webhook != nil:
+			named.Notifier = notification.NewWebhookNotifier(*instance.Webhook)
+		default:
+			continue
+		}
+
+		notifiers = append(notifiers, named)
+	}
+
+	return notification.NewMultiNotifier(notifiers...)
+}
+
 func getProviders() (providers middlewares.Providers, warnings []error, errors []error) {
 	// TODO: Adjust this so the CertPool can be used like a provider.
 	autheliaCertPool, warnings, errors := utils.NewX509CertPool(config.CertificatesDirectory)
@@ -48,20 +90,13 @@ func getProviders() (providers middlewares.Providers, warnings []error, errors [
 		userProvider = authentication.NewLDAPUserProvider(config.AuthenticationBackend, autheliaCertPool)
 	}
 
-	var notifier notification.Notifier
-
-	switch {
-	case config.Notifier.SMTP != nil:
-		notifier = notification.NewSMTPNotifier(config.Notifier.SMTP, autheliaCertPool)
-	case config.Notifier.FileSystem != nil:
-		notifier = notification.NewFileNotifier(*config.Notifier.FileSystem)
-	}
+	notifier := getNotifierProvider(config.Notifier, autheliaCertPool)
 
 	ntpProvider := ntp.NewProvider(&config.NTP)
 
 	clock := utils.RealClock{}
 	authorizer := authorization.NewAuthorizer(config)
-	sessionProvider := session.NewProvider(config.Session, autheliaCertPool)
+	sessionProvider := session.NewProvider(config.Session, autheliaCertPool, storageProvider)
 	regulator := regulation.NewRegulator(config.Regulation, storageProvider, clock)
 
 	oidcProvider, err := oidc.NewOpenIDConnectProvider(config.IdentityProviders.OIDC, storageProvider)
@@ -69,10 +104,51 @@ func getProviders() (providers middlewares.Providers, warnings []error, errors [
 		errors = append(errors, err)
 	}
 
+	clientCertificateVerifier, err := authentication.NewClientCertificateVerifier(config.AuthenticationBackend.ClientCertificate)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	trustedJWTVerifier := authentication.NewTrustedJWTVerifier(config.AuthenticationBackend.TrustedJWT, autheliaCertPool)
+
+	trustedHeaderVerifier, err := authentication.NewTrustedHeaderVerifier(config.AuthenticationBackend.TrustedHeader)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	apiKeyVerifier, err := authentication.NewAPIKeyVerifier(config.AuthenticationBackend.APIKeys)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	breakGlassAccount, err := authentication.NewBreakGlassAccount(config.AuthenticationBackend.BreakGlass)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
 	totpProvider := totp.NewTimeBasedProvider(config.TOTP)
 
+	userDetailsCache := authentication.NewUserDetailsCache(config.AuthenticationBackend.Cache, clock)
+
 	passwordPolicyProvider := middlewares.NewPasswordPolicyProvider(config.PasswordPolicy)
 
+	trustedProxies, err := middlewares.NewTrustedProxies(config.Server.TrustedProxies)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	var captchaProvider regulation.CAPTCHAProvider
+
+	if config.Regulation.CAPTCHA.Provider != "" {
+		captchaProvider = regulation.NewCAPTCHAVerifier(config.Regulation.CAPTCHA)
+	}
+
+	var eventsEmitter *events.Emitter
+
+	if config.Events.NATS != nil {
+		eventsEmitter = events.NewEmitter(events.NewNATSSink(config.Events.NATS, autheliaCertPool), config.Events.BufferSize, clock)
+	}
+
 	return middlewares.Providers{
 		Authorizer:      authorizer,
 		UserProvider:    userProvider,
@@ -84,5 +160,15 @@ func getProviders() (providers middlewares.Providers, warnings []error, errors [
 		SessionProvider: sessionProvider,
 		TOTP:            totpProvider,
 		PasswordPolicy:  passwordPolicyProvider,
+		TrustedProxies:  trustedProxies,
+
+		ClientCertificate: clientCertificateVerifier,
+		TrustedJWT:        trustedJWTVerifier,
+		TrustedHeader:     trustedHeaderVerifier,
+		APIKey:            apiKeyVerifier,
+		BreakGlass:        breakGlassAccount,
+		UserDetailsCache:  userDetailsCache,
+		CAPTCHA:           captchaProvider,
+		Events:            eventsEmitter,
 	}, warnings, errors
 }
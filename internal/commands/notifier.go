@@ -0,0 +1,291 @@
+package commands
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/configuration"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/configuration/validator"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewNotifierCmd returns a new notifier *cobra.Command.
+func NewNotifierCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "notifier",
+		Short: "Perform notifier related operations",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newNotifierTestCmd(),
+	)
+
+	return cmd
+}
+
+func newNotifierTestCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "test",
+		Short:   "Send a test notification using the configured notifier",
+		Long:    notifierTestLong,
+		Example: notifierTestExample,
+		Args:    cobra.NoArgs,
+		RunE:    notifierTestRunE,
+	}
+
+	cmdWithConfigFlags(cmd, false, []string{"configuration.yml"})
+
+	cmd.Flags().String("recipient", "", "the email address to send the test notification to")
+
+	return cmd
+}
+
+func notifierTestRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		configs   []string
+		recipient string
+	)
+
+	if configs, err = cmd.Flags().GetStringSlice("config"); err != nil {
+		return err
+	}
+
+	if recipient, err = cmd.Flags().GetString("recipient"); err != nil {
+		return err
+	}
+
+	if recipient == "" {
+		return errNotifierTestNoRecipient
+	}
+
+	if _, err = mail.ParseAddress(recipient); err != nil {
+		return fmt.Errorf("the recipient '%s' is not a valid email address: %w", recipient, err)
+	}
+
+	sources := make([]configuration.Source, 0, len(configs)+2)
+
+	for _, configFile := range configs {
+		if _, err = os.Stat(configFile); os.IsNotExist(err) {
+			return fmt.Errorf("could not load the provided configuration file %s: %w", configFile, err)
+		}
+
+		sources = append(sources, configuration.NewYAMLFileSource(configFile))
+	}
+
+	sources = append(sources, configuration.NewEnvironmentSource(configuration.DefaultEnvPrefix, configuration.DefaultEnvDelimiter))
+	sources = append(sources, configuration.NewSecretsSource(configuration.DefaultEnvPrefix, configuration.DefaultEnvDelimiter))
+
+	val := schema.NewStructValidator()
+
+	notifierConfig := &schema.Configuration{}
+
+	if _, err = configuration.LoadAdvanced(val, "", &notifierConfig, sources...); err != nil {
+		return err
+	}
+
+	validator.ValidateNotifier(notifierConfig.Notifier, val)
+
+	if val.HasErrors() {
+		var finalErr error
+
+		for i, err := range val.Errors() {
+			if i == 0 {
+				finalErr = err
+				continue
+			}
+
+			finalErr = fmt.Errorf("%w, %v", finalErr, err)
+		}
+
+		return finalErr
+	}
+
+	certPool, _, errs := utils.NewX509CertPool(notifierConfig.CertificatesDirectory)
+	if len(errs) != 0 {
+		return fmt.Errorf("failed to load the certificates directory: %w", errs[0])
+	}
+
+	w := cmd.OutOrStdout()
+
+	writeNotifierTestConfiguration(w, notifierConfig.Notifier, recipient)
+
+	// The SMTP notifier logs each step of the conversation with the server at the debug level.
+	logging.Logger().SetLevel(logrus.DebugLevel)
+
+	notifier := getNotifierProvider(notifierConfig.Notifier, certPool)
+
+	// The filesystem notifier relies on the startup check to create the directory of the file.
+	if notifierTestHasFileSystem(notifierConfig.Notifier) {
+		err = notifier.StartupCheck()
+	}
+
+	if err == nil {
+		err = notifier.Send(recipient, notifierTestTitle, notifierTestBody, "")
+	}
+
+	if err != nil {
+		cmd.SilenceUsage = true
+
+		_, _ = fmt.Fprintf(w, "\nFailed to send the test notification: %v\n", err)
+
+		if diagnosis := notifierTestErrorDiagnosis(notifierConfig.Notifier, err); diagnosis != "" {
+			_, _ = fmt.Fprintf(w, "\n%s\n", diagnosis)
+		}
+
+		_, _ = fmt.Fprintln(w)
+
+		return errNotifierTestFailed
+	}
+
+	_, _ = fmt.Fprintf(w, "\nSuccessfully sent the test notification to '%s'.\n\n", recipient)
+
+	return nil
+}
+
+// writeNotifierTestConfiguration writes the effective notifier configuration excluding any credentials.
+func writeNotifierTestConfiguration(w io.Writer, config *schema.NotifierConfiguration, recipient string) {
+	if len(config.Notifiers) != 0 {
+		_, _ = fmt.Fprintf(w, "Sending a test notification to '%s' using the following notifiers:\n\n", recipient)
+
+		if config.SMTP != nil || config.FileSystem != nil {
+			_, _ = fmt.Fprintf(w, "\tdefault\n")
+		}
+
+		for _, instance := range config.Notifiers {
+			_, _ = fmt.Fprintf(w, "\t%s\n", instance.Name)
+		}
+
+		_, _ = fmt.Fprintln(w)
+
+		return
+	}
+
+	if config.FileSystem != nil {
+		_, _ = fmt.Fprintf(w, "Sending a test notification to '%s' using the filesystem notifier with the file '%s'.\n", recipient, config.FileSystem.Filename)
+
+		return
+	}
+
+	smtp := config.SMTP
+
+	_, _ = fmt.Fprintf(w, "Sending a test notification to '%s' using the smtp notifier with the following configuration:\n\n", recipient)
+	_, _ = fmt.Fprintf(w, "\tAddress: %s\n", net.JoinHostPort(smtp.Host, strconv.Itoa(smtp.Port)))
+	_, _ = fmt.Fprintf(w, "\tTimeout: %s\n", smtp.Timeout)
+	_, _ = fmt.Fprintf(w, "\tSender: %s\n", smtp.Sender.String())
+	_, _ = fmt.Fprintf(w, "\tIdentifier: %s\n", smtp.Identifier)
+
+	if smtp.Password == "" {
+		_, _ = fmt.Fprintf(w, "\tAuthentication: disabled\n")
+	} else {
+		_, _ = fmt.Fprintf(w, "\tAuthentication: enabled (username '%s', password not shown)\n", smtp.Username)
+	}
+
+	switch {
+	case smtp.Port == 465:
+		_, _ = fmt.Fprintf(w, "\tTLS: implicit (submissions)\n")
+	case smtp.DisableRequireTLS:
+		_, _ = fmt.Fprintf(w, "\tTLS: STARTTLS if supported by the server\n")
+	default:
+		_, _ = fmt.Fprintf(w, "\tTLS: STARTTLS required\n")
+	}
+
+	if smtp.TLS != nil {
+		_, _ = fmt.Fprintf(w, "\tTLS Server Name: %s\n", smtp.TLS.ServerName)
+		_, _ = fmt.Fprintf(w, "\tTLS Skip Verify: %t\n", smtp.TLS.SkipVerify)
+		_, _ = fmt.Fprintf(w, "\tTLS Minimum Version: %s\n", smtp.TLS.MinimumVersion)
+	}
+
+	_, _ = fmt.Fprintln(w)
+}
+
+// notifierTestHasFileSystem returns true if any of the configured notifiers is a filesystem notifier.
+func notifierTestHasFileSystem(config *schema.NotifierConfiguration) bool {
+	if config.FileSystem != nil {
+		return true
+	}
+
+	for _, instance := range config.Notifiers {
+		if instance.FileSystem != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notifierTestErrorDiagnosis returns an explanation of the likely cause of a notifier error along with the options
+// which should be checked, or an empty string if the cause can't be determined.
+func notifierTestErrorDiagnosis(config *schema.NotifierConfiguration, err error) string {
+	if len(config.Notifiers) != 0 {
+		return "Check the log messages above for the cause of the failure of each notifier."
+	}
+
+	if config.SMTP == nil {
+		return "Check the directory of the configured filename exists and Authelia has permission to write to it."
+	}
+
+	var (
+		errUnknownAuthority x509.UnknownAuthorityError
+		errHostname         x509.HostnameError
+		errCertInvalid      x509.CertificateInvalidError
+		errRecordHeader     tls.RecordHeaderError
+		errProtocol         *textproto.Error
+		errNet              net.Error
+		errOp               *net.OpError
+	)
+
+	switch {
+	case errors.As(err, &errUnknownAuthority):
+		return "TLS certificate verification failed: the certificate presented by the SMTP server is not signed by a " +
+			"trusted certificate authority. Add the certificate authority to the 'certificates_directory' or fix the " +
+			"certificate chain sent by the server."
+	case errors.As(err, &errHostname):
+		return fmt.Sprintf("TLS certificate verification failed: the certificate presented by the SMTP server is not "+
+			"valid for the name '%s'. Either connect using a name included in the certificate or configure the "+
+			"'notifier.smtp.tls.server_name' option with one.", errHostname.Host)
+	case errors.As(err, &errCertInvalid):
+		return "TLS certificate verification failed: the certificate presented by the SMTP server is invalid, for " +
+			"example it may have expired or not be valid for server authentication."
+	case errors.As(err, &errRecordHeader):
+		return "TLS handshake failed: the SMTP server did not respond using TLS. Port 465 uses implicit TLS " +
+			"(submissions) and all other ports use STARTTLS, check the 'notifier.smtp.port' option matches the " +
+			"server configuration."
+	case errors.As(err, &errProtocol):
+		return notifierTestSMTPErrorDiagnosis(errProtocol)
+	case errors.As(err, &errNet) && errNet.Timeout():
+		return "The connection to the SMTP server timed out. Check the 'notifier.smtp.host' and 'notifier.smtp.port' " +
+			"options, any firewall between Authelia and the server, and the 'notifier.smtp.timeout' option."
+	case errors.As(err, &errOp) && errOp.Op == "dial":
+		return "Could not connect to the SMTP server. Check the 'notifier.smtp.host' and 'notifier.smtp.port' " +
+			"options and that the server is reachable from Authelia."
+	default:
+		return ""
+	}
+}
+
+func notifierTestSMTPErrorDiagnosis(err *textproto.Error) string {
+	switch {
+	case err.Code == 530 || err.Code == 534 || err.Code == 535:
+		return fmt.Sprintf("Authentication failed: the SMTP server rejected the credentials with code %d. Check the "+
+			"'notifier.smtp.username' and 'notifier.smtp.password' options.", err.Code)
+	case err.Code >= 550 && err.Code <= 553:
+		return fmt.Sprintf("The SMTP server rejected the sender or recipient address with code %d. Check the "+
+			"'notifier.smtp.sender' option is permitted to send using the configured account.", err.Code)
+	default:
+		return fmt.Sprintf("The SMTP server responded with the error code %d.", err.Code)
+	}
+}
@@ -0,0 +1,153 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestNotifierTestErrorDiagnosis(t *testing.T) {
+	smtp := &schema.NotifierConfiguration{SMTP: &schema.SMTPNotifierConfiguration{}}
+
+	testCases := []struct {
+		name     string
+		config   *schema.NotifierConfiguration
+		err      error
+		expected string
+	}{
+		{
+			"ShouldDiagnoseUnknownAuthority",
+			smtp,
+			fmt.Errorf("handshake: %w", x509.UnknownAuthorityError{}),
+			"TLS certificate verification failed: the certificate presented by the SMTP server is not signed by a trusted certificate authority",
+		},
+		{
+			"ShouldDiagnoseHostname",
+			smtp,
+			x509.HostnameError{Certificate: &x509.Certificate{}, Host: "smtp.example.com"},
+			"is not valid for the name 'smtp.example.com'",
+		},
+		{
+			"ShouldDiagnoseCertificateInvalid",
+			smtp,
+			x509.CertificateInvalidError{Reason: x509.Expired},
+			"the certificate presented by the SMTP server is invalid",
+		},
+		{
+			"ShouldDiagnoseRecordHeader",
+			smtp,
+			tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"},
+			"TLS handshake failed: the SMTP server did not respond using TLS",
+		},
+		{
+			"ShouldDiagnoseAuthentication",
+			smtp,
+			&textproto.Error{Code: 535, Msg: "5.7.8 Authentication credentials invalid"},
+			"Authentication failed: the SMTP server rejected the credentials with code 535",
+		},
+		{
+			"ShouldDiagnoseAddressRejected",
+			smtp,
+			&textproto.Error{Code: 553, Msg: "5.7.1 Sender address rejected"},
+			"rejected the sender or recipient address with code 553",
+		},
+		{
+			"ShouldDiagnoseDial",
+			smtp,
+			&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			"Could not connect to the SMTP server",
+		},
+		{
+			"ShouldDiagnoseFileSystem",
+			&schema.NotifierConfiguration{FileSystem: &schema.FileSystemNotifierConfiguration{}},
+			errors.New("permission denied"),
+			"Check the directory of the configured filename exists",
+		},
+		{
+			"ShouldNotDiagnoseUnknown",
+			smtp,
+			errors.New("unknown"),
+			"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := notifierTestErrorDiagnosis(tc.config, tc.err)
+
+			if tc.expected == "" {
+				assert.Equal(t, "", actual)
+			} else {
+				assert.Contains(t, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestWriteNotifierTestConfigurationShouldNotWritePassword(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	writeNotifierTestConfiguration(buf, &schema.NotifierConfiguration{
+		SMTP: &schema.SMTPNotifierConfiguration{
+			Host:     "smtp.example.com",
+			Port:     587,
+			Username: "john",
+			Password: "a-very-secret-password",
+			Sender:   mail.Address{Address: "admin@example.com"},
+			TLS:      &schema.TLSConfig{ServerName: "smtp.example.com"},
+		},
+	}, "john@example.com")
+
+	assert.Contains(t, buf.String(), "Address: smtp.example.com:587")
+	assert.Contains(t, buf.String(), "Authentication: enabled (username 'john', password not shown)")
+	assert.Contains(t, buf.String(), "TLS: STARTTLS required")
+	assert.NotContains(t, buf.String(), "a-very-secret-password")
+}
+
+func TestNotifierTestShouldWriteFileSystemNotification(t *testing.T) {
+	dir := t.TempDir()
+
+	configFile := filepath.Join(dir, "configuration.yml")
+	notificationFile := filepath.Join(dir, "notifications", "notification.txt")
+
+	require.NoError(t, os.WriteFile(configFile, []byte("notifier:\n  filesystem:\n    filename: "+notificationFile+"\n"), 0600))
+
+	buf := &bytes.Buffer{}
+
+	cmd := newNotifierTestCmd()
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--config", configFile, "--recipient", "john@example.com"})
+
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "Successfully sent the test notification to 'john@example.com'.")
+
+	data, err := os.ReadFile(notificationFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Recipient: john@example.com")
+	assert.Contains(t, string(data), "Subject: Test Notification")
+}
+
+func TestNotifierTestShouldRequireValidRecipient(t *testing.T) {
+	cmd := newNotifierTestCmd()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	cmd.SetArgs([]string{})
+	assert.EqualError(t, cmd.Execute(), "the recipient flag is required")
+
+	cmd.SetArgs([]string{"--recipient", "not an address"})
+	assert.EqualError(t, cmd.Execute(), "the recipient 'not an address' is not a valid email address: mail: no angle-addr")
+}
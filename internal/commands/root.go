@@ -1,18 +1,29 @@
 package commands
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/configuration/validator"
+	"github.com/authelia/authelia/v4/internal/events"
 	"github.com/authelia/authelia/v4/internal/logging"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/server"
+	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -38,10 +49,14 @@ func NewRootCmd() (cmd *cobra.Command) {
 		NewCertificatesCmd(),
 		newCompletionCmd(),
 		NewHashPasswordCmd(),
+		NewHashClientSecretCmd(),
 		NewRSACmd(),
 		NewStorageCmd(),
+		NewUsersCmd(),
+		NewConfigCmd(),
 		newValidateConfigCmd(),
 		newAccessControlCommand(),
+		NewNotifierCmd(),
 	)
 
 	return cmd
@@ -60,6 +75,8 @@ func cmdRootRun(_ *cobra.Command, _ []string) {
 		logger.Fatalf("Cannot initialize logger: %v", err)
 	}
 
+	oidcStaticClients, oidcClientsDigest := loadOpenIDConnectClientsStartup(logger)
+
 	providers, warnings, errors := getProviders()
 	if len(warnings) != 0 {
 		for _, err := range warnings {
@@ -77,6 +94,25 @@ func cmdRootRun(_ *cobra.Command, _ []string) {
 
 	doStartupChecks(config, &providers)
 
+	doSelfTest(config, &providers)
+
+	providers.Events.Emit(events.Event{
+		Type:    events.TypeLifecycleStartup,
+		Details: map[string]string{"version": utils.Version()},
+	})
+
+	if config.Storage.AuthenticationLogsRetention > 0 {
+		go runAuthenticationLogsRetention(logger, providers.StorageProvider, config.Storage.AuthenticationLogsRetention)
+	}
+
+	if !config.Storage.Cleanup.Disable {
+		go runStorageCleanup(logger, providers.StorageProvider, config)
+	}
+
+	if oidcClientsDigest != nil {
+		go runOpenIDConnectClientsReload(logger, providers.OpenIDConnect.Store, providers.Authorizer.IsSecondFactorEnabled(), config, oidcStaticClients, oidcClientsDigest)
+	}
+
 	s, listener := server.CreateServer(*config, providers)
 
 	logger.Fatal(s.Serve(listener))
@@ -97,9 +133,14 @@ func doStartupChecks(config *schema.Configuration, providers *middlewares.Provid
 	}
 
 	if err = doStartupCheck(logger, "user", providers.UserProvider, false); err != nil {
-		logger.Errorf("Failure running the user provider startup check: %+v", err)
+		if providers.BreakGlass != nil {
+			// The break-glass account must remain usable when the primary authentication backend is unavailable.
+			logger.Warnf("Failure running the user provider startup check, only the break-glass account can log in until it becomes available: %+v", err)
+		} else {
+			logger.Errorf("Failure running the user provider startup check: %+v", err)
 
-		failures = append(failures, "user")
+			failures = append(failures, "user")
+		}
 	}
 
 	if err = doStartupCheck(logger, "notification", providers.Notifier, config.Notifier.DisableStartupCheck); err != nil {
@@ -108,6 +149,13 @@ func doStartupChecks(config *schema.Configuration, providers *middlewares.Provid
 		failures = append(failures, "notification")
 	}
 
+	if providers.Events != nil {
+		// The events are delivered asynchronously so an unavailable sink must not prevent the startup.
+		if err = doStartupCheck(logger, "events", providers.Events, false); err != nil {
+			logger.Warnf("Failure running the events provider startup check, the events will be delivered once it becomes available: %+v", err)
+		}
+	}
+
 	if !config.NTP.DisableStartupCheck && !providers.Authorizer.IsSecondFactorEnabled() {
 		logger.Debug("The NTP startup check was skipped due to there being no configured 2FA access control rules")
 	} else if err = doStartupCheck(logger, "ntp", providers.NTP, config.NTP.DisableStartupCheck); err != nil {
@@ -123,6 +171,42 @@ func doStartupChecks(config *schema.Configuration, providers *middlewares.Provid
 	}
 }
 
+// doSelfTest checks the connectivity of the providers when the self-test is enabled, and either prevents the startup or
+// logs a warning when a check fails depending on the failure mode.
+func doSelfTest(config *schema.Configuration, providers *middlewares.Providers) {
+	if !config.SelfTest.Enabled {
+		return
+	}
+
+	logger := logging.Logger()
+
+	checks := middlewares.NewProviderHealthChecks(config.SelfTest, *providers)
+	failures := middlewares.RunProviderHealthChecks(context.Background(), checks)
+
+	var names []string
+
+	for _, check := range checks {
+		err, failed := failures[check.Name]
+
+		switch {
+		case !failed:
+			logger.Debugf("%s provider: self-test check succeeded", check.Name)
+
+			continue
+		case config.SelfTest.FailureMode == schema.SelfTestFailureModeWarn:
+			logger.Warnf("Failure running the %s provider self-test check: %+v", check.Name, err)
+		default:
+			logger.Errorf("Failure running the %s provider self-test check: %+v", check.Name, err)
+		}
+
+		names = append(names, check.Name)
+	}
+
+	if len(names) != 0 && config.SelfTest.FailureMode != schema.SelfTestFailureModeWarn {
+		logger.Fatalf("The following providers failed the self-test during startup: %s", strings.Join(names, ", "))
+	}
+}
+
 func doStartupCheck(logger *logrus.Logger, name string, provider model.StartupCheck, disabled bool) error {
 	if disabled {
 		logger.Debugf("%s provider: startup check skipped as it is disabled", name)
@@ -135,3 +219,185 @@ func doStartupCheck(logger *logrus.Logger, name string, provider model.StartupCh
 
 	return provider.StartupCheck()
 }
+
+// runAuthenticationLogsRetention deletes the authentication logs older than the retention at startup and then hourly.
+func runAuthenticationLogsRetention(logger *logrus.Logger, provider storage.Provider, retention time.Duration) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if err := provider.DeleteAuthenticationLogs(context.Background(), time.Now().Add(-retention)); err != nil {
+			logger.Errorf("Failed to delete the authentication logs older than %s: %v", retention, err)
+		}
+
+		<-ticker.C
+	}
+}
+
+// runStorageCleanup deletes the expired rows from the storage at startup and then at the configured interval.
+func runStorageCleanup(logger *logrus.Logger, provider storage.Provider, config *schema.Configuration) {
+	ticker := time.NewTicker(config.Storage.Cleanup.Interval)
+	defer ticker.Stop()
+
+	for {
+		doStorageCleanup(logger, provider, config, time.Now())
+
+		<-ticker.C
+	}
+}
+
+func doStorageCleanup(logger *logrus.Logger, provider storage.Provider, config *schema.Configuration, now time.Time) {
+	var oauth2Before time.Time
+
+	// The OAuth 2.0 sessions are retained for the longest lifespan after they were requested so the reuse of an
+	// invalidated token can still be detected for at least the lifespan of the token.
+	if oidcConfig := config.IdentityProviders.OIDC; oidcConfig != nil {
+		lifespan := oidcConfig.AccessTokenLifespan
+
+		for _, l := range []time.Duration{oidcConfig.AuthorizeCodeLifespan, oidcConfig.IDTokenLifespan, oidcConfig.RefreshTokenLifespan} {
+			if l > lifespan {
+				lifespan = l
+			}
+		}
+
+		oauth2Before = now.Add(-lifespan)
+	}
+
+	deleted, err := provider.DeleteExpiredRows(context.Background(), now, oauth2Before, config.Storage.Cleanup.BatchSize)
+
+	switch {
+	case errors.Is(err, storage.ErrCleanupLocked):
+		logger.Debug("Skipped the storage cleanup as another instance is performing it")
+
+		return
+	case err != nil:
+		logger.Errorf("Failed to delete the expired rows from the storage: %v", err)
+	}
+
+	tables := make([]string, 0, len(deleted))
+
+	for table := range deleted {
+		tables = append(tables, table)
+	}
+
+	sort.Strings(tables)
+
+	for _, table := range tables {
+		if deleted[table] != 0 {
+			logger.Infof("Deleted %d expired rows from the storage table '%s'", deleted[table], table)
+		}
+	}
+}
+
+// loadOpenIDConnectClientsStartup adds the OpenID Connect clients from the clients path to the configured clients, and
+// returns the clients from the configuration file and the digest of the clients path so they can be reloaded later.
+func loadOpenIDConnectClientsStartup(logger *logrus.Logger) (static []schema.OpenIDConnectClientConfiguration, digest []byte) {
+	oidcConfig := config.IdentityProviders.OIDC
+
+	if oidcConfig == nil || oidcConfig.ClientsPath == "" {
+		return nil, nil
+	}
+
+	static = oidcConfig.Clients
+
+	var (
+		loaded  []schema.OpenIDConnectClientConfiguration
+		clients []schema.OpenIDConnectClientConfiguration
+		errs    []error
+		err     error
+	)
+
+	if loaded, digest, err = configuration.LoadOpenIDConnectClients(oidcConfig.ClientsPath); err != nil {
+		logger.Fatalf("Can't continue due to the error loading the OpenID Connect clients: %+v", err)
+	}
+
+	if clients, errs = validateOpenIDConnectClients(oidcConfig, static, loaded); len(errs) != 0 {
+		for _, err = range errs {
+			logger.Errorf("Configuration: %+v", err)
+		}
+
+		logger.Fatalf("Can't continue due to the errors loading the OpenID Connect clients from '%s'", oidcConfig.ClientsPath)
+	}
+
+	oidcConfig.Clients = clients
+
+	return static, digest
+}
+
+// runOpenIDConnectClientsReload polls the clients path every reload interval and replaces the OpenID Connect clients
+// whenever its content changed and all the clients are valid, otherwise the clients currently in use are kept. Clients
+// which change whether a two_factor policy is configured are rejected as the startup checks and the portal rely on it.
+func runOpenIDConnectClientsReload(logger *logrus.Logger, store *oidc.OpenIDConnectStore, secondFactor bool, config *schema.Configuration, static []schema.OpenIDConnectClientConfiguration, digest []byte) {
+	oidcConfig := config.IdentityProviders.OIDC
+
+	ticker := time.NewTicker(oidcConfig.ClientsReloadInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		loaded, current, err := configuration.LoadOpenIDConnectClients(oidcConfig.ClientsPath)
+		if err != nil {
+			logger.Errorf("Failed to reload the OpenID Connect clients, the current clients remain in use: %+v", err)
+
+			continue
+		}
+
+		if bytes.Equal(digest, current) {
+			continue
+		}
+
+		digest = current
+
+		clients, errs := validateOpenIDConnectClients(oidcConfig, static, loaded)
+		if len(errs) != 0 {
+			for _, err = range errs {
+				logger.Errorf("Failed to reload the OpenID Connect clients from '%s', the current clients remain in use: %+v", oidcConfig.ClientsPath, err)
+			}
+
+			continue
+		}
+
+		if isSecondFactorEnabledWithClients(config, clients) != secondFactor {
+			logger.Errorf("Failed to reload the OpenID Connect clients from '%s', the current clients remain in use: the clients change whether a two_factor policy is configured which requires a restart", oidcConfig.ClientsPath)
+
+			continue
+		}
+
+		removed := store.ReplaceClients(clients)
+
+		logger.Infof("Reloaded %d OpenID Connect clients from '%s'", len(clients), oidcConfig.ClientsPath)
+
+		if len(removed) != 0 {
+			logger.Warnf("Removed the OpenID Connect clients %s, their tokens and in progress authorizations can no longer be used", strings.Join(removed, ", "))
+		}
+	}
+}
+
+// isSecondFactorEnabledWithClients returns true if a two_factor policy is configured when the OpenID Connect clients are
+// replaced with the provided clients.
+func isSecondFactorEnabledWithClients(config *schema.Configuration, clients []schema.OpenIDConnectClientConfiguration) bool {
+	c, oidcConfig := *config, *config.IdentityProviders.OIDC
+
+	oidcConfig.Clients = clients
+	c.IdentityProviders.OIDC = &oidcConfig
+
+	return authorization.NewAuthorizer(&c).IsSecondFactorEnabled()
+}
+
+// validateOpenIDConnectClients validates the clients from the configuration file combined with the loaded clients.
+func validateOpenIDConnectClients(config *schema.OpenIDConnectConfiguration, static, loaded []schema.OpenIDConnectClientConfiguration) (clients []schema.OpenIDConnectClientConfiguration, errs []error) {
+	c := *config
+
+	c.Clients = make([]schema.OpenIDConnectClientConfiguration, 0, len(static)+len(loaded))
+	c.Clients = append(c.Clients, static...)
+	c.Clients = append(c.Clients, loaded...)
+
+	val := schema.NewStructValidator()
+
+	validator.ValidateOpenIDConnectClients(&c, val)
+
+	if val.HasErrors() {
+		return nil, val.Errors()
+	}
+
+	return c.Clients, nil
+}
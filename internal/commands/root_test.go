@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldDetermineSecondFactorWithReloadedClients(t *testing.T) {
+	config := &schema.Configuration{
+		AccessControl: schema.AccessControlConfiguration{DefaultPolicy: "one_factor"},
+		IdentityProviders: schema.IdentityProvidersConfiguration{
+			OIDC: &schema.OpenIDConnectConfiguration{
+				Clients: []schema.OpenIDConnectClientConfiguration{{ID: "static", Policy: "one_factor"}},
+			},
+		},
+	}
+
+	assert.False(t, isSecondFactorEnabledWithClients(config, config.IdentityProviders.OIDC.Clients))
+	assert.True(t, isSecondFactorEnabledWithClients(config, []schema.OpenIDConnectClientConfiguration{{ID: "static", Policy: "one_factor"}, {ID: "loaded", Policy: "two_factor"}}))
+
+	// The configuration in use must not be modified.
+	assert.Len(t, config.IdentityProviders.OIDC.Clients, 1)
+}
@@ -57,6 +57,7 @@ func newStorageUserCmd() (cmd *cobra.Command) {
 	cmd.AddCommand(
 		newStorageUserIdentifiersCmd(),
 		newStorageTOTPCmd(),
+		newStoragePasswordResetCodeCmd(),
 	)
 
 	return cmd
@@ -150,6 +151,7 @@ func newStorageEncryptionChangeKeyCmd() (cmd *cobra.Command) {
 	}
 
 	cmd.Flags().String("new-encryption-key", "", "the new key to encrypt the data with")
+	cmd.Flags().Bool("dry-run", false, "performs the change and verifies it without saving it")
 
 	return cmd
 }
@@ -164,6 +166,7 @@ func newStorageTOTPCmd() (cmd *cobra.Command) {
 		newStorageTOTPGenerateCmd(),
 		newStorageTOTPDeleteCmd(),
 		newStorageTOTPExportCmd(),
+		newStorageTOTPImportCmd(),
 	)
 
 	return cmd
@@ -183,6 +186,7 @@ func newStorageTOTPGenerateCmd() (cmd *cobra.Command) {
 	cmd.Flags().Uint("digits", 6, "set the TOTP digits")
 	cmd.Flags().String("algorithm", "SHA1", "set the TOTP algorithm")
 	cmd.Flags().String("issuer", "Authelia", "set the TOTP issuer")
+	cmd.Flags().String("description", "Primary", "set the TOTP description, generating a configuration with the description of an existing configuration requires --force")
 	cmd.Flags().BoolP("force", "f", false, "forces the TOTP configuration to be generated regardless if it exists or not")
 	cmd.Flags().StringP("path", "p", "", "path to a file to create a PNG file with the QR code (optional)")
 
@@ -192,7 +196,7 @@ func newStorageTOTPGenerateCmd() (cmd *cobra.Command) {
 func newStorageTOTPDeleteCmd() (cmd *cobra.Command) {
 	cmd = &cobra.Command{
 		Use:   "delete username",
-		Short: "Delete a TOTP configuration for a user",
+		Short: "Delete all TOTP configurations for a user",
 		RunE:  storageTOTPDeleteRunE,
 		Args:  cobra.ExactArgs(1),
 	}
@@ -213,6 +217,57 @@ func newStorageTOTPExportCmd() (cmd *cobra.Command) {
 	return cmd
 }
 
+func newStorageTOTPImportCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "import",
+		Short: "Import the TOTP configurations of users from a YAML file",
+		RunE:  storageTOTPImportRunE,
+	}
+
+	cmd.Flags().StringP("file", "f", "totp-configurations.yml", "The file name for the YAML import")
+	cmd.Flags().Bool("force", false, "forces the TOTP configurations to be imported when the user has a configuration with the same description")
+
+	return cmd
+}
+
+func newStoragePasswordResetCodeCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "password-reset-code",
+		Short: "Manage the password reset codes issued by administrators",
+	}
+
+	cmd.AddCommand(
+		newStoragePasswordResetCodeGenerateCmd(),
+		newStoragePasswordResetCodeDeleteCmd(),
+	)
+
+	return cmd
+}
+
+func newStoragePasswordResetCodeGenerateCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "generate [username]",
+		Short: "Generate a one-time password reset code for a user, replacing any existing code",
+		RunE:  storagePasswordResetCodeGenerateRunE,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().Duration("lifespan", 0, "set how long the code is valid for, defaults to the configured code lifespan")
+
+	return cmd
+}
+
+func newStoragePasswordResetCodeDeleteCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "delete [username]",
+		Short: "Delete the password reset code of a user",
+		RunE:  storagePasswordResetCodeDeleteRunE,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
 func newStorageSchemaInfoCmd() (cmd *cobra.Command) {
 	cmd = &cobra.Command{
 		Use:   "schema-info",
@@ -10,6 +10,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
@@ -101,7 +103,7 @@ func storagePersistentPreRunE(cmd *cobra.Command, _ []string) (err error) {
 		return finalErr
 	}
 
-	validator.ValidateStorage(config.Storage, val)
+	validator.ValidateStorage(&config.Storage, val)
 
 	validator.ValidateTOTP(config, val)
 
@@ -162,6 +164,7 @@ func storageSchemaEncryptionChangeKeyRunE(cmd *cobra.Command, args []string) (er
 		provider storage.Provider
 		key      string
 		version  int
+		dryRun   bool
 
 		ctx = context.Background()
 	)
@@ -195,22 +198,39 @@ func storageSchemaEncryptionChangeKeyRunE(cmd *cobra.Command, args []string) (er
 		return errors.New("the new encryption key must be at least 20 characters")
 	}
 
-	if err = provider.SchemaEncryptionChangeKey(ctx, key); err != nil {
+	if dryRun, err = cmd.Flags().GetBool("dry-run"); err != nil {
 		return err
 	}
 
+	if err = provider.SchemaEncryptionChangeKey(ctx, key, dryRun, storageSchemaEncryptionChangeKeyProgress); err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Println("Completed the encryption key change dry run, no changes were saved.")
+
+		return nil
+	}
+
 	fmt.Println("Completed the encryption key change. Please adjust your configuration to use the new key.")
 
 	return nil
 }
 
+func storageSchemaEncryptionChangeKeyProgress(result storage.EncryptionChangeKeyResult) {
+	fmt.Printf("Table '%s' column '%s': %d of %d rows processed (%d changed, %d already using the new key)\n",
+		result.Table, result.Column, result.Changed+result.Skipped, result.Total, result.Changed, result.Skipped)
+}
+
 func storageTOTPGenerateRunE(cmd *cobra.Command, args []string) (err error) {
 	var (
 		provider         storage.Provider
 		ctx              = context.Background()
 		c                *model.TOTPConfiguration
+		configs          []model.TOTPConfiguration
 		force            bool
 		filename, secret string
+		description      string
 		file             *os.File
 		img              image.Image
 	)
@@ -221,22 +241,28 @@ func storageTOTPGenerateRunE(cmd *cobra.Command, args []string) (err error) {
 		_ = provider.Close()
 	}()
 
-	if force, filename, secret, err = storageTOTPGenerateRunEOptsFromFlags(cmd.Flags()); err != nil {
+	if force, filename, secret, description, err = storageTOTPGenerateRunEOptsFromFlags(cmd.Flags()); err != nil {
 		return err
 	}
 
-	if _, err = provider.LoadTOTPConfiguration(ctx, args[0]); err == nil && !force {
-		return fmt.Errorf("%s already has a TOTP configuration, use --force to overwrite", args[0])
-	} else if err != nil && !errors.Is(err, storage.ErrNoTOTPConfiguration) {
+	if configs, err = provider.LoadTOTPConfigurationsByUsername(ctx, args[0]); err != nil && !errors.Is(err, storage.ErrNoTOTPConfiguration) {
 		return err
 	}
 
+	for _, existing := range configs {
+		if existing.Description == description && !force {
+			return fmt.Errorf("%s already has a TOTP configuration with the description '%s', use --force to overwrite", args[0], description)
+		}
+	}
+
 	totpProvider := totp.NewTimeBasedProvider(config.TOTP)
 
 	if c, err = totpProvider.GenerateCustom(args[0], config.TOTP.Algorithm, secret, config.TOTP.Digits, config.TOTP.Period, config.TOTP.SecretSize); err != nil {
 		return err
 	}
 
+	c.Description = description
+
 	extraInfo := ""
 
 	if filename != "" {
@@ -270,26 +296,34 @@ func storageTOTPGenerateRunE(cmd *cobra.Command, args []string) (err error) {
 	return nil
 }
 
-func storageTOTPGenerateRunEOptsFromFlags(flags *pflag.FlagSet) (force bool, filename, secret string, err error) {
+func storageTOTPGenerateRunEOptsFromFlags(flags *pflag.FlagSet) (force bool, filename, secret, description string, err error) {
 	if force, err = flags.GetBool("force"); err != nil {
-		return force, filename, secret, err
+		return force, filename, secret, description, err
 	}
 
 	if filename, err = flags.GetString("path"); err != nil {
-		return force, filename, secret, err
+		return force, filename, secret, description, err
 	}
 
 	if secret, err = flags.GetString("secret"); err != nil {
-		return force, filename, secret, err
+		return force, filename, secret, description, err
+	}
+
+	if description, err = flags.GetString("description"); err != nil {
+		return force, filename, secret, description, err
 	}
 
 	secretLength := base32.StdEncoding.WithPadding(base32.NoPadding).DecodedLen(len(secret))
 	if secret != "" && secretLength < schema.TOTPSecretSizeMinimum {
-		return force, filename, secret, fmt.Errorf("decoded length of the base32 secret must have "+
+		return force, filename, secret, description, fmt.Errorf("decoded length of the base32 secret must have "+
 			"a length of more than %d but '%s' has a decoded length of %d", schema.TOTPSecretSizeMinimum, secret, secretLength)
 	}
 
-	return force, filename, secret, nil
+	if description == "" || utf8.RuneCountInString(description) > 30 {
+		return force, filename, secret, description, fmt.Errorf("the description must be between 1 and 30 characters but '%s' is not", description)
+	}
+
+	return force, filename, secret, description, nil
 }
 
 func storageTOTPDeleteRunE(cmd *cobra.Command, args []string) (err error) {
@@ -306,7 +340,7 @@ func storageTOTPDeleteRunE(cmd *cobra.Command, args []string) (err error) {
 		_ = provider.Close()
 	}()
 
-	if _, err = provider.LoadTOTPConfiguration(ctx, user); err != nil {
+	if _, err = provider.LoadTOTPConfigurationsByUsername(ctx, user); err != nil {
 		return fmt.Errorf("can't delete configuration for user '%s': %+v", user, err)
 	}
 
@@ -314,7 +348,250 @@ func storageTOTPDeleteRunE(cmd *cobra.Command, args []string) (err error) {
 		return fmt.Errorf("can't delete configuration for user '%s': %+v", user, err)
 	}
 
-	fmt.Printf("Deleted TOTP configuration for user '%s'.", user)
+	fmt.Printf("Deleted all TOTP configurations for user '%s'.", user)
+
+	return nil
+}
+
+func storageTOTPImportRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		provider storage.Provider
+
+		ctx = context.Background()
+
+		file  string
+		force bool
+		stat  os.FileInfo
+	)
+
+	if file, err = cmd.Flags().GetString("file"); err != nil {
+		return err
+	}
+
+	if force, err = cmd.Flags().GetBool("force"); err != nil {
+		return err
+	}
+
+	if stat, err = os.Stat(file); err != nil {
+		return fmt.Errorf("must specify a file that exists but '%s' had an error opening it: %w", file, err)
+	}
+
+	if stat.IsDir() {
+		return fmt.Errorf("must specify a file that exists but '%s' is a directory", file)
+	}
+
+	var (
+		data    []byte
+		imports model.TOTPConfigurationsImport
+	)
+
+	if data, err = os.ReadFile(file); err != nil {
+		return err
+	}
+
+	if err = yaml.Unmarshal(data, &imports); err != nil {
+		return err
+	}
+
+	if len(imports.Configurations) == 0 {
+		return fmt.Errorf("can't import a file with no data")
+	}
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	if err = checkStorageSchemaUpToDate(ctx, provider); err != nil {
+		return err
+	}
+
+	totpProvider := totp.NewTimeBasedProvider(config.TOTP)
+
+	failed := 0
+
+	for i, row := range imports.Configurations {
+		if err = storageTOTPImportConfiguration(ctx, provider, totpProvider, row, force); err != nil {
+			failed++
+
+			fmt.Printf("Row %d: failed to import the TOTP configuration for user '%s': %v\n", i+1, row.Username, err)
+
+			continue
+		}
+
+		fmt.Printf("Row %d: imported the TOTP configuration for user '%s'\n", i+1, row.Username)
+	}
+
+	fmt.Printf("Imported %d of %d TOTP configurations from %s\n", len(imports.Configurations)-failed, len(imports.Configurations), file)
+
+	if failed != 0 {
+		cmd.SilenceUsage = true
+
+		return fmt.Errorf("failed to import %d TOTP configurations", failed)
+	}
+
+	return nil
+}
+
+// storageTOTPImportConfiguration saves an imported TOTP configuration after verifying the code of the import is a
+// current code of the configuration.
+func storageTOTPImportConfiguration(ctx context.Context, provider storage.Provider, totpProvider totp.Provider, row model.TOTPConfigurationImport, force bool) (err error) {
+	var (
+		c       *model.TOTPConfiguration
+		configs []model.TOTPConfiguration
+		valid   bool
+	)
+
+	if c, err = newTOTPConfigurationFromImport(row, config.TOTP); err != nil {
+		return err
+	}
+
+	if valid, _, err = totpProvider.Validate(row.Code, c); err != nil {
+		return fmt.Errorf("error validating the code: %w", err)
+	}
+
+	if !valid {
+		return fmt.Errorf("the code '%s' is not a current code of the configuration", row.Code)
+	}
+
+	if configs, err = provider.LoadTOTPConfigurationsByUsername(ctx, c.Username); err != nil && !errors.Is(err, storage.ErrNoTOTPConfiguration) {
+		return err
+	}
+
+	for _, existing := range configs {
+		if existing.Description == c.Description && !force {
+			return fmt.Errorf("the user already has a TOTP configuration with the description '%s', use --force to overwrite", c.Description)
+		}
+	}
+
+	return provider.SaveTOTPConfiguration(ctx, *c)
+}
+
+// newTOTPConfigurationFromImport returns the model.TOTPConfiguration of an imported TOTP configuration. The options
+// which aren't set in the import are the defaults of the TOTP standard, except the issuer and the description which
+// default to the configured issuer and the default description.
+func newTOTPConfigurationFromImport(row model.TOTPConfigurationImport, totpConfig schema.TOTPConfiguration) (c *model.TOTPConfiguration, err error) {
+	if row.Username == "" {
+		return nil, errors.New("the username is required")
+	}
+
+	if row.Code == "" {
+		return nil, errors.New("the code is required")
+	}
+
+	secret := strings.TrimRight(strings.ToUpper(strings.Join(strings.Fields(row.Secret), "")), "=")
+
+	if secret == "" {
+		return nil, errors.New("the secret is required")
+	}
+
+	if _, err = base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret); err != nil {
+		return nil, fmt.Errorf("the secret must be base32 encoded: %w", err)
+	}
+
+	c = &model.TOTPConfiguration{
+		CreatedAt:   time.Now(),
+		Username:    row.Username,
+		Description: row.Description,
+		Issuer:      row.Issuer,
+		Algorithm:   strings.ToUpper(row.Algorithm),
+		Digits:      row.Digits,
+		Period:      row.Period,
+		Secret:      []byte(secret),
+	}
+
+	if c.Description == "" {
+		c.Description = storageTOTPDescriptionDefault
+	} else if utf8.RuneCountInString(c.Description) > 30 {
+		return nil, fmt.Errorf("the description must be between 1 and 30 characters but '%s' is not", c.Description)
+	}
+
+	if c.Issuer == "" {
+		c.Issuer = totpConfig.Issuer
+	}
+
+	switch {
+	case c.Algorithm == "":
+		c.Algorithm = schema.TOTPAlgorithmSHA1
+	case !utils.IsStringInSlice(c.Algorithm, schema.TOTPPossibleAlgorithms):
+		return nil, fmt.Errorf("the algorithm must be one of '%s' but it is '%s'", strings.Join(schema.TOTPPossibleAlgorithms, "', '"), row.Algorithm)
+	}
+
+	switch {
+	case c.Digits == 0:
+		c.Digits = 6
+	case c.Digits != 6 && c.Digits != 8:
+		return nil, fmt.Errorf("the digits must be 6 or 8 but it is %d", c.Digits)
+	}
+
+	switch {
+	case c.Period == 0:
+		c.Period = 30
+	case c.Period < 15:
+		return nil, fmt.Errorf("the period must be 15 or more but it is %d", c.Period)
+	}
+
+	return c, nil
+}
+
+func storagePasswordResetCodeGenerateRunE(cmd *cobra.Command, args []string) (err error) {
+	var (
+		provider storage.Provider
+		lifespan time.Duration
+		ctx      = context.Background()
+	)
+
+	if lifespan, err = cmd.Flags().GetDuration("lifespan"); err != nil {
+		return err
+	}
+
+	switch {
+	case lifespan < 0:
+		return fmt.Errorf("the lifespan must be more than 0 but it is '%s'", lifespan)
+	case lifespan == 0:
+		if lifespan = config.AuthenticationBackend.PasswordReset.CodeLifespan; lifespan <= 0 {
+			lifespan = schema.DefaultPasswordResetAuthenticationBackendConfiguration.CodeLifespan
+		}
+	}
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	code := utils.RandomString(passwordResetCodeLength, passwordResetCodeCharacters, true)
+	resetCode := model.NewPasswordResetCode(args[0], code, time.Now(), lifespan)
+
+	if err = provider.SavePasswordResetCode(ctx, resetCode); err != nil {
+		return err
+	}
+
+	fmt.Printf("Generated the password reset code '%s' for user '%s' which expires at %s.\n", code, args[0], resetCode.ExpiresAt.Format(time.RFC1123))
+
+	return nil
+}
+
+func storagePasswordResetCodeDeleteRunE(cmd *cobra.Command, args []string) (err error) {
+	var (
+		provider storage.Provider
+		ctx      = context.Background()
+	)
+
+	user := args[0]
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	if err = provider.DeletePasswordResetCode(ctx, user); err != nil {
+		return fmt.Errorf("can't delete the password reset code for user '%s': %+v", user, err)
+	}
+
+	fmt.Printf("Deleted the password reset code for user '%s'.\n", user)
 
 	return nil
 }
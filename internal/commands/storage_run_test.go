@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestNewTOTPConfigurationFromImport(t *testing.T) {
+	totpConfig := schema.TOTPConfiguration{Issuer: "example.com"}
+
+	c, err := newTOTPConfigurationFromImport(model.TOTPConfigurationImport{
+		Username: "john",
+		Secret:   "jbsw y3dp ehpk 3pxp jbsw y3dp ehpk 3pxp====",
+		Code:     "123456",
+	}, totpConfig)
+
+	require.NoError(t, err)
+	assert.Equal(t, "john", c.Username)
+	assert.Equal(t, "Primary", c.Description)
+	assert.Equal(t, "example.com", c.Issuer)
+	assert.Equal(t, "SHA1", c.Algorithm)
+	assert.Equal(t, uint(6), c.Digits)
+	assert.Equal(t, uint(30), c.Period)
+	assert.Equal(t, []byte("JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"), c.Secret)
+
+	c, err = newTOTPConfigurationFromImport(model.TOTPConfigurationImport{
+		Username:    "john",
+		Description: "Legacy",
+		Issuer:      "legacy.example.com",
+		Algorithm:   "sha256",
+		Digits:      8,
+		Period:      60,
+		Secret:      "JBSWY3DPEHPK3PXP",
+		Code:        "12345678",
+	}, totpConfig)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Legacy", c.Description)
+	assert.Equal(t, "legacy.example.com", c.Issuer)
+	assert.Equal(t, "SHA256", c.Algorithm)
+	assert.Equal(t, uint(8), c.Digits)
+	assert.Equal(t, uint(60), c.Period)
+}
+
+func TestNewTOTPConfigurationFromImportShouldRejectInvalidRows(t *testing.T) {
+	testCases := []struct {
+		name     string
+		row      model.TOTPConfigurationImport
+		expected string
+	}{
+		{"ShouldRejectNoUsername", model.TOTPConfigurationImport{Secret: "JBSWY3DPEHPK3PXP", Code: "123456"}, "the username is required"},
+		{"ShouldRejectNoCode", model.TOTPConfigurationImport{Username: "john", Secret: "JBSWY3DPEHPK3PXP"}, "the code is required"},
+		{"ShouldRejectNoSecret", model.TOTPConfigurationImport{Username: "john", Code: "123456"}, "the secret is required"},
+		{"ShouldRejectMalformedSecret", model.TOTPConfigurationImport{Username: "john", Secret: "JBSWY3DP1", Code: "123456"}, "the secret must be base32 encoded: illegal base32 data at input byte 8"},
+		{"ShouldRejectInvalidAlgorithm", model.TOTPConfigurationImport{Username: "john", Secret: "JBSWY3DPEHPK3PXP", Algorithm: "MD5", Code: "123456"}, "the algorithm must be one of 'SHA1', 'SHA256', 'SHA512' but it is 'MD5'"},
+		{"ShouldRejectInvalidDigits", model.TOTPConfigurationImport{Username: "john", Secret: "JBSWY3DPEHPK3PXP", Digits: 7, Code: "123456"}, "the digits must be 6 or 8 but it is 7"},
+		{"ShouldRejectInvalidPeriod", model.TOTPConfigurationImport{Username: "john", Secret: "JBSWY3DPEHPK3PXP", Period: 10, Code: "123456"}, "the period must be 15 or more but it is 10"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := newTOTPConfigurationFromImport(tc.row, schema.TOTPConfiguration{})
+
+			assert.Nil(t, c)
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package commands
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/configuration/validator"
+)
+
+// NewUsersCmd returns a new users *cobra.Command.
+func NewUsersCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:               "users",
+		Short:             "Manage the users in the file authentication backend",
+		Args:              cobra.NoArgs,
+		PersistentPreRunE: usersPersistentPreRunE,
+	}
+
+	cmdWithConfigFlags(cmd, true, []string{"configuration.yml"})
+
+	cmd.AddCommand(
+		newUsersImportCmd(),
+		newUsersExportCmd(),
+	)
+
+	return cmd
+}
+
+func newUsersImportCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "import",
+		Short: "Import users from a CSV or JSON file into the users database",
+		RunE:  usersImportRunE,
+	}
+
+	cmd.Flags().StringP("file", "f", "users.csv", "The file name for the import")
+	cmd.Flags().String("format", "", "The format of the file, valid values are: csv, json (defaults to the file extension)")
+	cmd.Flags().Bool("overwrite", false, "Replaces users which already exist in the users database")
+
+	return cmd
+}
+
+func newUsersExportCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export users from the users database to a CSV or JSON file",
+		RunE:  usersExportRunE,
+	}
+
+	cmd.Flags().StringP("file", "f", "users.csv", "The file name for the export")
+	cmd.Flags().String("format", "", "The format of the file, valid values are: csv, json (defaults to the file extension)")
+
+	return cmd
+}
+
+func usersPersistentPreRunE(cmd *cobra.Command, _ []string) (err error) {
+	var configs []string
+
+	if configs, err = cmd.Flags().GetStringSlice("config"); err != nil {
+		return err
+	}
+
+	sources := make([]configuration.Source, 0, len(configs)+2)
+
+	for _, configFile := range configs {
+		if _, err = os.Stat(configFile); os.IsNotExist(err) {
+			return fmt.Errorf("could not load the provided configuration file %s: %w", configFile, err)
+		}
+
+		sources = append(sources, configuration.NewYAMLFileSource(configFile))
+	}
+
+	sources = append(sources, configuration.NewEnvironmentSource(configuration.DefaultEnvPrefix, configuration.DefaultEnvDelimiter))
+	sources = append(sources, configuration.NewSecretsSource(configuration.DefaultEnvPrefix, configuration.DefaultEnvDelimiter))
+
+	val := schema.NewStructValidator()
+
+	config = &schema.Configuration{}
+
+	if _, err = configuration.LoadAdvanced(val, "", &config, sources...); err != nil {
+		return err
+	}
+
+	validator.ValidateAuthenticationBackend(&config.AuthenticationBackend, val)
+
+	if val.HasErrors() {
+		var finalErr error
+
+		for i, err := range val.Errors() {
+			if i == 0 {
+				finalErr = err
+				continue
+			}
+
+			finalErr = fmt.Errorf("%w, %v", finalErr, err)
+		}
+
+		return finalErr
+	}
+
+	if config.AuthenticationBackend.File == nil {
+		return errNoFileAuthenticationBackend
+	}
+
+	return nil
+}
+
+func usersImportRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		file, format string
+		overwrite    bool
+		f            *os.File
+		users        []authentication.UserImportExportModel
+	)
+
+	if file, format, err = usersGetFileAndFormat(cmd); err != nil {
+		return err
+	}
+
+	if overwrite, err = cmd.Flags().GetBool("overwrite"); err != nil {
+		return err
+	}
+
+	if f, err = os.Open(file); err != nil {
+		return fmt.Errorf("must specify a file that exists but '%s' had an error opening it: %w", file, err)
+	}
+
+	defer f.Close()
+
+	switch format {
+	case usersFormatJSON:
+		err = json.NewDecoder(f).Decode(&users)
+	default:
+		users, err = usersReadCSV(f)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error occurred parsing file '%s': %w", file, err)
+	}
+
+	if len(users) == 0 {
+		return fmt.Errorf("can't import a file with no data")
+	}
+
+	imported, errs, err := authentication.ImportUsersDatabase(config.AuthenticationBackend.File.Path, users, config.AuthenticationBackend.File.Password, overwrite)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range errs {
+		fmt.Printf("Skipped %v\n", e)
+	}
+
+	fmt.Printf("Imported %d of %d users from %s into %s\n", imported, len(users), file, config.AuthenticationBackend.File.Path)
+
+	return nil
+}
+
+func usersExportRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		file, format string
+		users        []authentication.UserImportExportModel
+		f            *os.File
+	)
+
+	if file, format, err = usersGetFileAndFormat(cmd); err != nil {
+		return err
+	}
+
+	_, err = os.Stat(file)
+
+	switch {
+	case err == nil:
+		return fmt.Errorf("must specify a file that doesn't exist but '%s' exists", file)
+	case !os.IsNotExist(err):
+		return fmt.Errorf("error occurred opening '%s': %w", file, err)
+	}
+
+	if users, err = authentication.ExportUsersDatabase(config.AuthenticationBackend.File.Path); err != nil {
+		return err
+	}
+
+	if len(users) == 0 {
+		return fmt.Errorf("no data to export")
+	}
+
+	if f, err = os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600); err != nil {
+		return fmt.Errorf("error occurred opening '%s': %w", file, err)
+	}
+
+	defer f.Close()
+
+	switch format {
+	case usersFormatJSON:
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(users)
+	default:
+		err = usersWriteCSV(f, users)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error occurred writing to file '%s': %w", file, err)
+	}
+
+	fmt.Printf("Exported %d users to %s\n", len(users), file)
+
+	return nil
+}
+
+func usersGetFileAndFormat(cmd *cobra.Command) (file, format string, err error) {
+	if file, err = cmd.Flags().GetString("file"); err != nil {
+		return "", "", err
+	}
+
+	if format, err = cmd.Flags().GetString("format"); err != nil {
+		return "", "", err
+	}
+
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+	}
+
+	switch format {
+	case usersFormatCSV, usersFormatJSON:
+		return file, format, nil
+	default:
+		return "", "", fmt.Errorf("format must be one of '%s', or '%s' but it's '%s'", usersFormatCSV, usersFormatJSON, format)
+	}
+}
+
+// usersReadCSV reads users from CSV data which has a header row with the columns in usersCSVHeader. The groups column
+// is a semicolon separated list.
+func usersReadCSV(r io.Reader) (users []authentication.UserImportExportModel, err error) {
+	reader := csv.NewReader(r)
+
+	var records [][]string
+
+	if records, err = reader.ReadAll(); err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(strings.ToLower(name))] = i
+	}
+
+	if _, ok := columns["username"]; !ok {
+		return nil, fmt.Errorf("the header row must have the 'username' column")
+	}
+
+	value := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+
+		return ""
+	}
+
+	for _, record := range records[1:] {
+		user := authentication.UserImportExportModel{
+			Username:       value(record, "username"),
+			DisplayName:    value(record, "displayname"),
+			Email:          value(record, "email"),
+			Password:       value(record, "password"),
+			HashedPassword: value(record, "hashed_password"),
+		}
+
+		for _, group := range strings.Split(value(record, "groups"), ";") {
+			if group = strings.TrimSpace(group); group != "" {
+				user.Groups = append(user.Groups, group)
+			}
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func usersWriteCSV(w io.Writer, users []authentication.UserImportExportModel) (err error) {
+	writer := csv.NewWriter(w)
+
+	if err = writer.Write(usersCSVHeader); err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err = writer.Write([]string{user.Username, user.DisplayName, user.Email, strings.Join(user.Groups, ";"), "", user.HashedPassword}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
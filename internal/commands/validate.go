@@ -1,70 +1,261 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
-
-	"github.com/authelia/authelia/v4/internal/configuration/schema"
 )
 
-func newValidateConfigCmd() (cmd *cobra.Command) {
+// NewConfigCmd returns a new config *cobra.Command.
+func NewConfigCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "config",
+		Short: "Perform configuration related operations",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(
+		newConfigValidateCmd(),
+	)
+
+	return cmd
+}
+
+func newConfigValidateCmd() (cmd *cobra.Command) {
 	cmd = &cobra.Command{
-		Use:   "validate-config",
+		Use:   "validate",
 		Short: "Check a configuration against the internal configuration validation mechanisms",
+		Long:  configValidateLong,
 		Args:  cobra.NoArgs,
-		RunE:  cmdValidateConfigRunE,
+		RunE:  cmdConfigValidateRunE,
 	}
 
-	cmdWithConfigFlags(cmd, false, []string{"configuration.yml"})
+	cmdWithConfigValidateFlags(cmd)
 
 	return cmd
 }
 
-func cmdValidateConfigRunE(cmd *cobra.Command, _ []string) (err error) {
+// newValidateConfigCmd is the legacy form of the config validate command.
+func newValidateConfigCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:        "validate-config",
+		Short:      "Check a configuration against the internal configuration validation mechanisms",
+		Args:       cobra.NoArgs,
+		RunE:       cmdConfigValidateRunE,
+		Deprecated: "use 'authelia config validate' instead",
+	}
+
+	cmdWithConfigValidateFlags(cmd)
+
+	return cmd
+}
+
+func cmdWithConfigValidateFlags(cmd *cobra.Command) {
+	cmdWithConfigFlags(cmd, false, []string{"configuration.yml"})
+
+	cmd.Flags().Bool("strict", false, "treat warnings as errors")
+	cmd.Flags().String("format", configValidateFormatText, fmt.Sprintf("the output format, valid values are: %s, %s", configValidateFormatText, configValidateFormatJSON))
+}
+
+func cmdConfigValidateRunE(cmd *cobra.Command, _ []string) (err error) {
 	var (
 		configs []string
-		val     *schema.StructValidator
+		format  string
+		strict  bool
 	)
 
 	if configs, err = cmd.Flags().GetStringSlice("config"); err != nil {
 		return err
 	}
 
-	config, val, err = loadConfig(configs, true, true)
+	if strict, err = cmd.Flags().GetBool("strict"); err != nil {
+		return err
+	}
+
+	if format, err = cmd.Flags().GetString("format"); err != nil {
+		return err
+	}
+
+	if format != configValidateFormatText && format != configValidateFormatJSON {
+		return fmt.Errorf("format must be one of '%s', or '%s' but it's '%s'", configValidateFormatText, configValidateFormatJSON, format)
+	}
+
+	result := configValidationResult{Strict: strict, Errors: []configValidationIssue{}, Warnings: []configValidationIssue{}}
+
+	_, val, err := loadConfig(configs, true, true)
 	if err != nil {
-		return fmt.Errorf("error occurred loading configuration: %v", err)
+		if format == configValidateFormatText {
+			return fmt.Errorf("error occurred loading configuration: %v", err)
+		}
+
+		result.Errors = append(result.Errors, newConfigValidationIssue(fmt.Errorf("error occurred loading configuration: %w", err)))
+	} else {
+		for _, e := range val.Errors() {
+			result.Errors = append(result.Errors, newConfigValidationIssue(e))
+		}
+
+		for _, e := range val.Warnings() {
+			result.Warnings = append(result.Warnings, newConfigValidationIssue(e))
+		}
+	}
+
+	result.Valid = len(result.Errors) == 0 && (!strict || len(result.Warnings) == 0)
+
+	switch format {
+	case configValidateFormatJSON:
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+
+		if err = encoder.Encode(result); err != nil {
+			return err
+		}
+	default:
+		result.WriteText(cmd.OutOrStdout())
+	}
+
+	if !result.Valid {
+		cmd.SilenceUsage = true
+
+		return errConfigurationInvalid
+	}
+
+	return nil
+}
+
+type configValidationResult struct {
+	Valid    bool                    `json:"valid"`
+	Strict   bool                    `json:"strict"`
+	Errors   []configValidationIssue `json:"errors"`
+	Warnings []configValidationIssue `json:"warnings"`
+}
+
+type configValidationIssue struct {
+	Group   string `json:"group"`
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+// WriteText writes the result in the human readable format with the issues grouped by the configuration section.
+func (r configValidationResult) WriteText(w io.Writer) {
+	if len(r.Errors) == 0 && len(r.Warnings) == 0 {
+		_, _ = fmt.Fprintf(w, "Configuration parsed and loaded successfully without errors.\n\n")
+
+		return
+	}
+
+	if len(r.Errors) != 0 {
+		_, _ = fmt.Fprintf(w, "Configuration parsed and loaded with errors:\n\n")
+
+		writeConfigValidationIssues(w, r.Errors)
+	}
+
+	if len(r.Warnings) != 0 {
+		if r.Strict {
+			_, _ = fmt.Fprintf(w, "Configuration parsed and loaded with warnings which are treated as errors in strict mode:\n\n")
+		} else {
+			_, _ = fmt.Fprintf(w, "Configuration parsed and loaded with warnings:\n\n")
+		}
+
+		writeConfigValidationIssues(w, r.Warnings)
+	}
+}
+
+func writeConfigValidationIssues(w io.Writer, issues []configValidationIssue) {
+	groups := map[string][]configValidationIssue{}
+
+	var names []string
+
+	for _, issue := range issues {
+		if _, ok := groups[issue.Group]; !ok {
+			names = append(names, issue.Group)
+		}
+
+		groups[issue.Group] = append(groups[issue.Group], issue)
 	}
 
-	switch {
-	case val.HasErrors():
-		fmt.Println("Configuration parsed and loaded with errors:")
-		fmt.Println("")
+	sort.Strings(names)
+
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "\t%s:\n", name)
 
-		for _, err = range val.Errors() {
-			fmt.Printf("\t - %v\n", err)
+		for _, issue := range groups[name] {
+			if issue.Key == "" {
+				_, _ = fmt.Fprintf(w, "\t\t - %s\n", issue.Message)
+			} else {
+				_, _ = fmt.Fprintf(w, "\t\t - %s: %s\n", issue.Key, issue.Message)
+			}
 		}
 
-		fmt.Println("")
+		_, _ = fmt.Fprintln(w)
+	}
+}
+
+func newConfigValidationIssue(err error) (issue configValidationIssue) {
+	issue = configValidationIssue{
+		Key:     configKeyFromError(err),
+		Message: err.Error(),
+	}
+
+	if issue.Key == "" {
+		issue.Group = configValidateGroupGeneral
+	} else {
+		issue.Group = reConfigKeyGroup.FindString(issue.Key)
+	}
+
+	return issue
+}
+
+// configKeyFromError derives the configuration key path an error refers to from the conventional format of the
+// validation errors i.e. 'section: subsection: element 'name': option 'key' is invalid'. Elements of a list are
+// represented with their name or number in square brackets. It returns an empty string if the key can't be determined.
+func configKeyFromError(err error) (key string) {
+	message := err.Error()
 
-		if !val.HasWarnings() {
+	if matches := reConfigErrorKeyOnly.FindStringSubmatch(message); matches != nil {
+		return matches[1] + matches[2]
+	}
+
+	var parts []string
+
+	for {
+		i := strings.Index(message, ": ")
+		if i == -1 {
 			break
 		}
 
-		fallthrough
-	case val.HasWarnings():
-		fmt.Println("Configuration parsed and loaded with warnings:")
-		fmt.Println("")
+		segment := message[:i]
 
-		for _, err = range val.Warnings() {
-			fmt.Printf("\t - %v\n", err)
+		if alias, ok := configErrorSegmentAliases[segment]; ok {
+			segment = alias
 		}
 
-		fmt.Println("")
-	default:
-		fmt.Println("Configuration parsed and loaded successfully without errors.")
-		fmt.Println("")
+		if reConfigErrorSegmentName.MatchString(segment) {
+			parts = append(parts, segment)
+		} else if matches := reConfigErrorSegmentElement.FindStringSubmatch(segment); matches != nil {
+			element := fmt.Sprintf("[%s%s]", matches[2], matches[3])
+
+			// The element is appended to the previous part when it's the list the element belongs to.
+			if n := len(parts); n != 0 && (matches[1] == "entry" || strings.HasSuffix(parts[n-1], matches[1]+"s")) {
+				parts[n-1] += element
+			} else {
+				parts = append(parts, matches[1]+"s"+element)
+			}
+		} else {
+			break
+		}
+
+		message = message[i+2:]
 	}
 
-	return nil
+	// The option name either precedes or follows the word option, i.e. 'option 'name' is required' or ''name' option
+	// 'value' is invalid'.
+	if matches := reConfigErrorOption.FindStringSubmatch(message); matches != nil {
+		parts = append(parts, matches[1]+matches[2])
+	}
+
+	return strings.Join(parts, ".")
 }
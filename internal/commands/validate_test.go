@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigKeyFromError(t *testing.T) {
+	testCases := []struct {
+		err      string
+		expected string
+	}{
+		{"configuration key not expected: loggy_file", "loggy_file"},
+		{"invalid configuration key 'logs_level' was replaced by 'log.level'", "logs_level"},
+		{"option 'jwt_secret' is required", "jwt_secret"},
+		{"storage: postgres: option 'host' is required", "storage.postgres.host"},
+		{"storage: configuration for a 'local', 'mysql' or 'postgres' database must be provided", "storage"},
+		{"identity_providers: oidc: client 'myapp': option 'secret' is required", "identity_providers.oidc.clients[myapp].secret"},
+		{"identity_providers: oidc: custom_scopes: scope 'hr': option 'claims' is required", "identity_providers.oidc.custom_scopes[hr].claims"},
+		{"identity_providers: oidc: issuer_private_keys: key #2: option 'key' is required", "identity_providers.oidc.issuer_private_keys[2].key"},
+		{"session: default_redirection_urls: entry #1: option 'domain' is required", "session.default_redirection_urls[1].domain"},
+		{"access control: option 'default_policy' must be one of 'bypass' but it is configured as 'x'", "access_control.default_policy"},
+		{"access control: 'default_policy' option 'deny' is invalid: when no rules are specified it must be 'two_factor' or 'one_factor'", "access_control.default_policy"},
+		{"the location 'certificates_directory' refers to '/tmp' is not a directory", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.err, func(t *testing.T) {
+			assert.Equal(t, tc.expected, configKeyFromError(errors.New(tc.err)))
+		})
+	}
+}
+
+func TestNewConfigValidationIssue(t *testing.T) {
+	assert.Equal(t, configValidationIssue{
+		Group:   "identity_providers",
+		Key:     "identity_providers.oidc.clients[myapp].secret",
+		Message: "identity_providers: oidc: client 'myapp': option 'secret' is required",
+	}, newConfigValidationIssue(errors.New("identity_providers: oidc: client 'myapp': option 'secret' is required")))
+
+	assert.Equal(t, configValidationIssue{
+		Group:   "general",
+		Message: "the location 'certificates_directory' could not be inspected",
+	}, newConfigValidationIssue(errors.New("the location 'certificates_directory' could not be inspected")))
+}
+
+func TestConfigValidationResultWriteText(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	configValidationResult{}.WriteText(buf)
+
+	assert.Equal(t, "Configuration parsed and loaded successfully without errors.\n\n", buf.String())
+
+	buf.Reset()
+
+	configValidationResult{
+		Strict: true,
+		Errors: []configValidationIssue{
+			newConfigValidationIssue(errors.New("storage: postgres: option 'host' is required")),
+			newConfigValidationIssue(errors.New("option 'jwt_secret' is required")),
+			newConfigValidationIssue(errors.New("the location 'certificates_directory' could not be inspected")),
+			newConfigValidationIssue(errors.New("storage: postgres: option 'password' is required")),
+		},
+		Warnings: []configValidationIssue{
+			newConfigValidationIssue(errors.New("configuration environment variable not expected: AUTHELIA_EXAMPLE")),
+		},
+	}.WriteText(buf)
+
+	assert.Equal(t, "Configuration parsed and loaded with errors:\n\n"+
+		"\tgeneral:\n"+
+		"\t\t - the location 'certificates_directory' could not be inspected\n\n"+
+		"\tjwt_secret:\n"+
+		"\t\t - jwt_secret: option 'jwt_secret' is required\n\n"+
+		"\tstorage:\n"+
+		"\t\t - storage.postgres.host: storage: postgres: option 'host' is required\n"+
+		"\t\t - storage.postgres.password: storage: postgres: option 'password' is required\n\n"+
+		"Configuration parsed and loaded with warnings which are treated as errors in strict mode:\n\n"+
+		"\tgeneral:\n"+
+		"\t\t - configuration environment variable not expected: AUTHELIA_EXAMPLE\n\n", buf.String())
+}
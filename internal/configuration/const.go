@@ -16,6 +16,9 @@ const (
 	constDelimiter = "."
 
 	constWindows = "windows"
+
+	extYML  = ".yml"
+	extYAML = ".yaml"
 )
 
 var (
@@ -0,0 +1,102 @@
+package configuration
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/rawbytes"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// LoadOpenIDConnectClients loads the OpenID Connect clients from the 'clients' key of a YAML file or of every YAML file
+// in a directory in lexical order. The digest is a hash of the loaded paths and their content which changes whenever
+// any of the files is added, removed, or modified.
+func LoadOpenIDConnectClients(path string) (clients []schema.OpenIDConnectClientConfiguration, digest []byte, err error) {
+	var paths []string
+
+	if paths, err = openIDConnectClientsPaths(path); err != nil {
+		return nil, nil, err
+	}
+
+	hash := sha256.New()
+
+	for _, p := range paths {
+		var (
+			data   []byte
+			loaded []schema.OpenIDConnectClientConfiguration
+		)
+
+		if data, err = os.ReadFile(p); err != nil {
+			return nil, nil, fmt.Errorf("error occurred reading the clients file '%s': %w", p, err)
+		}
+
+		hash.Write([]byte(p))
+		hash.Write(data)
+
+		if loaded, err = loadOpenIDConnectClientsFile(data); err != nil {
+			return nil, nil, fmt.Errorf("error occurred loading the clients file '%s': %w", p, err)
+		}
+
+		clients = append(clients, loaded...)
+	}
+
+	return clients, hash.Sum(nil), nil
+}
+
+func openIDConnectClientsPaths(path string) (paths []string, err error) {
+	var info os.FileInfo
+
+	if info, err = os.Stat(path); err != nil {
+		return nil, fmt.Errorf("error occurred accessing the clients path '%s': %w", path, err)
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var entries []os.DirEntry
+
+	if entries, err = os.ReadDir(path); err != nil {
+		return nil, fmt.Errorf("error occurred reading the clients directory '%s': %w", path, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case extYML, extYAML:
+			paths = append(paths, filepath.Join(path, entry.Name()))
+		}
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+func loadOpenIDConnectClientsFile(data []byte) (clients []schema.OpenIDConnectClientConfiguration, err error) {
+	ko := koanf.New(constDelimiter)
+
+	if err = ko.Load(rawbytes.Provider(data), yaml.Parser()); err != nil {
+		return nil, err
+	}
+
+	val := schema.NewStructValidator()
+
+	unmarshal(ko, val, "clients", &clients)
+
+	if val.HasErrors() {
+		return nil, val.Errors()[0]
+	}
+
+	return clients, nil
+}
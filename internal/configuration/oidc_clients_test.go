@@ -0,0 +1,77 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShouldLoadOpenIDConnectClientsFromDirectory(t *testing.T) {
+	dir, err := os.MkdirTemp("", "authelia-test-oidc-clients")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yml"), []byte("clients:\n  - id: b\n    redirect_uris: https://b.example.com/callback\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("clients:\n  - id: a\n    secret: abc\n    redirect_uris:\n      - https://a.example.com/callback\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ignored.txt"), []byte("clients:\n  - id: c\n"), 0600))
+
+	clients, digest, err := LoadOpenIDConnectClients(dir)
+	require.NoError(t, err)
+	require.Len(t, clients, 2)
+
+	assert.Equal(t, "a", clients[0].ID)
+	assert.Equal(t, "abc", clients[0].Secret)
+	assert.Equal(t, []string{"https://a.example.com/callback"}, clients[0].RedirectURIs)
+	assert.Equal(t, "b", clients[1].ID)
+	assert.Equal(t, []string{"https://b.example.com/callback"}, clients[1].RedirectURIs)
+
+	_, same, err := LoadOpenIDConnectClients(dir)
+	require.NoError(t, err)
+	assert.Equal(t, digest, same)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yml"), []byte("clients:\n  - id: b\n    public: true\n"), 0600))
+
+	clients, changed, err := LoadOpenIDConnectClients(dir)
+	require.NoError(t, err)
+	require.Len(t, clients, 2)
+
+	assert.NotEqual(t, digest, changed)
+	assert.True(t, clients[1].Public)
+}
+
+func TestShouldLoadOpenIDConnectClientsFromFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "authelia-test-oidc-clients")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "clients.yml")
+
+	require.NoError(t, os.WriteFile(path, []byte("clients:\n  - id: a\n"), 0600))
+
+	clients, _, err := LoadOpenIDConnectClients(path)
+	require.NoError(t, err)
+	require.Len(t, clients, 1)
+	assert.Equal(t, "a", clients[0].ID)
+}
+
+func TestShouldErrorLoadingOpenIDConnectClients(t *testing.T) {
+	dir, err := os.MkdirTemp("", "authelia-test-oidc-clients")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	_, _, err = LoadOpenIDConnectClients(filepath.Join(dir, "missing.yml"))
+	assert.EqualError(t, err, "error occurred accessing the clients path '"+filepath.Join(dir, "missing.yml")+"': stat "+filepath.Join(dir, "missing.yml")+": no such file or directory")
+
+	path := filepath.Join(dir, "bad.yml")
+
+	require.NoError(t, os.WriteFile(path, []byte("clients: [\n"), 0600))
+
+	_, _, err = LoadOpenIDConnectClients(path)
+	assert.ErrorContains(t, err, "error occurred loading the clients file '"+path+"'")
+}
@@ -2,6 +2,7 @@ package configuration
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/knadh/koanf"
 	"github.com/mitchellh/mapstructure"
@@ -46,6 +47,7 @@ func unmarshal(ko *koanf.Koanf, val *schema.StructValidator, path string, o inte
 				mapstructure.StringToSliceHookFunc(","),
 				StringToMailAddressHookFunc(),
 				ToTimeDurationHookFunc(),
+				mapstructure.StringToTimeHookFunc(time.RFC3339),
 				StringToURLHookFunc(),
 				StringToRegexpFunc(),
 			),
@@ -2,13 +2,24 @@ package schema
 
 import (
 	"regexp"
+	"time"
 )
 
 // AccessControlConfiguration represents the configuration related to ACLs.
 type AccessControlConfiguration struct {
-	DefaultPolicy string       `koanf:"default_policy"`
-	Networks      []ACLNetwork `koanf:"networks"`
-	Rules         []ACLRule    `koanf:"rules"`
+	DefaultPolicy string          `koanf:"default_policy"`
+	DenyResponse  ACLDenyResponse `koanf:"deny_response"`
+	Networks      []ACLNetwork    `koanf:"networks"`
+	Rules         []ACLRule       `koanf:"rules"`
+
+	// DebugTrace logs the evaluation of each rule for every request at the debug level.
+	DebugTrace bool `koanf:"debug_trace"`
+
+	// Shadow puts all rules in shadow mode, see ACLRule.Shadow.
+	Shadow bool `koanf:"shadow"`
+
+	// ShadowTwoFactor includes the two_factor policy of the rules in shadow mode, which is otherwise always enforced.
+	ShadowTwoFactor bool `koanf:"shadow_two_factor"`
 }
 
 // ACLNetwork represents one ACL network group entry.
@@ -19,6 +30,7 @@ type ACLNetwork struct {
 
 // ACLRule represents one ACL rule entry.
 type ACLRule struct {
+	Name         string          `koanf:"name"`
 	Domains      []string        `koanf:"domain"`
 	DomainsRegex []regexp.Regexp `koanf:"domain_regex"`
 	Policy       string          `koanf:"policy"`
@@ -26,6 +38,71 @@ type ACLRule struct {
 	Networks     []string        `koanf:"networks"`
 	Resources    []regexp.Regexp `koanf:"resources"`
 	Methods      []string        `koanf:"methods"`
+	Headers      []ACLHeader     `koanf:"headers"`
+	UserAgents   []regexp.Regexp `koanf:"user_agent"`
+
+	NetworkPolicies []ACLNetworkPolicy `koanf:"network_policies"`
+
+	MaximumAuthenticationAge time.Duration `koanf:"maximum_authentication_age"`
+
+	SecondFactorMethods []string `koanf:"second_factor_methods"`
+
+	TimeWindow *ACLTimeWindow `koanf:"time_window"`
+
+	DenyResponse *ACLDenyResponse `koanf:"deny_response"`
+
+	Identity *ACLIdentity `koanf:"identity"`
+
+	// Shadow logs the requests the rule would deny instead of denying them, the rules after it are applied instead.
+	Shadow bool `koanf:"shadow"`
+}
+
+// ACLIdentity represents a synthetic identity forwarded to the backend for requests matching a bypass ACL rule entry
+// from its networks. The identity is never associated with a session.
+type ACLIdentity struct {
+	Username    string   `koanf:"username"`
+	DisplayName string   `koanf:"display_name"`
+	Email       string   `koanf:"email"`
+	Groups      []string `koanf:"groups"`
+}
+
+// ACLTimeWindow represents the days and hours during which an ACL rule entry permits access. The start and end are
+// times in the 24-hour format HH:MM in the timezone, the window spans midnight when the end is before the start.
+type ACLTimeWindow struct {
+	Days              []string `koanf:"days"`
+	Start             string   `koanf:"start"`
+	End               string   `koanf:"end"`
+	Timezone          string   `koanf:"timezone"`
+	TerminateSessions bool     `koanf:"terminate_sessions"`
+}
+
+// ACLDenyResponse represents the response sent to a user who is forbidden from accessing a resource. Either the user
+// is redirected to the redirect URL or the message is sent as the body of a response with the status code. Otherwise
+// the error page is sent which includes the help message and the help URL when configured.
+type ACLDenyResponse struct {
+	StatusCode  int    `koanf:"status_code"`
+	RedirectURL string `koanf:"redirect_url"`
+	Message     string `koanf:"message"`
+	HelpMessage string `koanf:"help_message"`
+	HelpURL     string `koanf:"help_url"`
+}
+
+// ACLHeader represents a header condition of an ACL rule entry. The header must be present and when the value is
+// configured it must also match the value.
+type ACLHeader struct {
+	Name  string         `koanf:"name"`
+	Value *regexp.Regexp `koanf:"value"`
+}
+
+// ACLNetworkPolicy represents a policy of an ACL rule entry which only applies to requests from specific networks.
+type ACLNetworkPolicy struct {
+	Networks []string `koanf:"networks"`
+	Policy   string   `koanf:"policy"`
+}
+
+// DefaultACLTimeWindow represents the default configuration related to access control rule time windows.
+var DefaultACLTimeWindow = ACLTimeWindow{
+	Timezone: "UTC",
 }
 
 // DefaultACLNetwork represents the default configuration related to access control network group configuration.
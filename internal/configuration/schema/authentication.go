@@ -2,6 +2,7 @@ package schema
 
 import (
 	"net/url"
+	"regexp"
 	"time"
 )
 
@@ -25,9 +26,25 @@ type LDAPAuthenticationBackendConfiguration struct {
 	UsernameAttribute    string `koanf:"username_attribute"`
 	MailAttribute        string `koanf:"mail_attribute"`
 	DisplayNameAttribute string `koanf:"display_name_attribute"`
+	SubjectAttribute     string `koanf:"subject_attribute"`
 
 	User     string `koanf:"user"`
 	Password string `koanf:"password"`
+
+	MaxConcurrency int           `koanf:"max_concurrency"`
+	QueueTimeout   time.Duration `koanf:"queue_timeout"`
+
+	PasswordExpiration LDAPPasswordExpirationConfiguration `koanf:"password_expiration"`
+}
+
+// LDAPPasswordExpirationConfiguration represents the configuration related to detecting expired passwords and accounts
+// in the LDAP directory.
+type LDAPPasswordExpirationConfiguration struct {
+	Enabled                     bool   `koanf:"enabled"`
+	Action                      string `koanf:"action"`
+	AccountExpiresAttribute     string `koanf:"account_expires_attribute"`
+	PasswordExpiresAttribute    string `koanf:"password_expires_attribute"`
+	PasswordMustChangeAttribute string `koanf:"password_must_change_attribute"`
 }
 
 // FileAuthenticationBackendConfiguration represents the configuration related to file-based backend.
@@ -44,6 +61,10 @@ type PasswordConfiguration struct {
 	Algorithm   string `koanf:"algorithm"`
 	Memory      int    `koanf:"memory"`
 	Parallelism int    `koanf:"parallelism"`
+
+	MemoryPercentage int `koanf:"memory_percentage"`
+	MemoryMinimum    int `koanf:"memory_minimum"`
+	MemoryMaximum    int `koanf:"memory_maximum"`
 }
 
 // AuthenticationBackendConfiguration represents the configuration related to the authentication backend.
@@ -53,13 +74,172 @@ type AuthenticationBackendConfiguration struct {
 
 	PasswordReset PasswordResetAuthenticationBackendConfiguration `koanf:"password_reset"`
 
+	ClientCertificate ClientCertificateAuthenticationBackendConfiguration `koanf:"client_certificate"`
+	APIKeys           APIKeyAuthenticationBackendConfiguration            `koanf:"api_keys"`
+	TrustedJWT        TrustedJWTAuthenticationBackendConfiguration        `koanf:"trusted_jwt"`
+	TrustedHeader     TrustedHeaderAuthenticationBackendConfiguration     `koanf:"trusted_header"`
+	BreakGlass        BreakGlassAuthenticationBackendConfiguration        `koanf:"break_glass"`
+
+	UsernameCanonicalization UsernameCanonicalizationConfiguration `koanf:"username_canonicalization"`
+
+	Cache UserDetailsCacheConfiguration `koanf:"cache"`
+
 	DisableResetPassword bool   `koanf:"disable_reset_password"`
 	RefreshInterval      string `koanf:"refresh_interval"`
+	AdminGroup           string `koanf:"admin_group"`
 }
 
 // PasswordResetAuthenticationBackendConfiguration represents the configuration related to password reset functionality.
 type PasswordResetAuthenticationBackendConfiguration struct {
-	CustomURL url.URL `koanf:"custom_url"`
+	CustomURL    url.URL       `koanf:"custom_url"`
+	Method       string        `koanf:"method"`
+	CodeLifespan time.Duration `koanf:"code_lifespan"`
+}
+
+// DefaultPasswordResetAuthenticationBackendConfiguration represents the default password reset configuration.
+var DefaultPasswordResetAuthenticationBackendConfiguration = PasswordResetAuthenticationBackendConfiguration{
+	Method:       PasswordResetMethodEmail,
+	CodeLifespan: time.Hour,
+}
+
+// ClientCertificateAuthenticationBackendConfiguration represents the configuration related to authenticating users
+// with a client certificate forwarded by the proxy.
+type ClientCertificateAuthenticationBackendConfiguration struct {
+	Enabled              bool     `koanf:"enabled"`
+	Header               string   `koanf:"header"`
+	CertificateAuthority string   `koanf:"certificate_authority"`
+	UsernameAttribute    string   `koanf:"username_attribute"`
+	Networks             []string `koanf:"networks"`
+}
+
+// APIKeyAuthenticationBackendConfiguration represents the configuration related to authenticating services with a
+// static API key.
+type APIKeyAuthenticationBackendConfiguration struct {
+	Header string                `koanf:"header"`
+	Keys   []APIKeyConfiguration `koanf:"keys"`
+}
+
+// APIKeyConfiguration represents a single API key and the identity it authenticates as.
+type APIKeyConfiguration struct {
+	Name      string   `koanf:"name"`
+	Digest    string   `koanf:"digest"`
+	Username  string   `koanf:"username"`
+	Groups    []string `koanf:"groups"`
+	Domains   []string `koanf:"domains"`
+	Resources []string `koanf:"resources"`
+}
+
+// TrustedJWTAuthenticationBackendConfiguration represents the configuration related to authenticating users with a
+// signed JWT forwarded by an upstream identity provider.
+type TrustedJWTAuthenticationBackendConfiguration struct {
+	Enabled    bool                          `koanf:"enabled"`
+	Header     string                        `koanf:"header"`
+	Issuer     string                        `koanf:"issuer"`
+	Audience   string                        `koanf:"audience"`
+	JWKSURL    string                        `koanf:"jwks_url"`
+	JWKSCache  JWKSCacheConfiguration        `koanf:"jwks_cache"`
+	Algorithms []string                      `koanf:"algorithms"`
+	Leeway     time.Duration                 `koanf:"leeway"`
+	Claims     TrustedJWTClaimsConfiguration `koanf:"claims"`
+}
+
+// JWKSCacheConfiguration represents the configuration of the cache of a JSON Web Key Set fetched from a remote URL.
+type JWKSCacheConfiguration struct {
+	Lifespan time.Duration `koanf:"lifespan"`
+	MaxStale time.Duration `koanf:"max_stale"`
+	MaxSize  int           `koanf:"max_size"`
+}
+
+// TrustedJWTClaimsConfiguration represents the names of the claims of a trusted JWT which contain the user attributes.
+type TrustedJWTClaimsConfiguration struct {
+	Username    string `koanf:"username"`
+	DisplayName string `koanf:"display_name"`
+	Email       string `koanf:"email"`
+	Groups      string `koanf:"groups"`
+}
+
+// TrustedHeaderAuthenticationBackendConfiguration represents the configuration related to trusting the identity of
+// users forwarded in a header by an upstream authenticator which has already authenticated them.
+type TrustedHeaderAuthenticationBackendConfiguration struct {
+	Enabled      bool     `koanf:"enabled"`
+	Header       string   `koanf:"header"`
+	GroupsHeader string   `koanf:"groups_header"`
+	Networks     []string `koanf:"networks"`
+	Domains      []string `koanf:"domains"`
+}
+
+// BreakGlassAuthenticationBackendConfiguration represents the configuration related to the local break-glass account
+// which can authenticate administrators when the primary authentication backend is unavailable.
+type BreakGlassAuthenticationBackendConfiguration struct {
+	Enabled     bool   `koanf:"enabled"`
+	Username    string `koanf:"username"`
+	Password    string `koanf:"password"`
+	DisplayName string `koanf:"display_name"`
+	Email       string `koanf:"email"`
+	Group       string `koanf:"group"`
+}
+
+// UsernameCanonicalizationConfiguration represents the configuration related to the canonicalization of the usernames
+// entered by users so the same user always resolves to the same username.
+type UsernameCanonicalizationConfiguration struct {
+	StripDomainSuffixes []string       `koanf:"strip_domain_suffixes"`
+	Pattern             *regexp.Regexp `koanf:"pattern"`
+	Replacement         string         `koanf:"replacement"`
+	Lowercase           bool           `koanf:"lowercase"`
+}
+
+// UserDetailsCacheConfiguration represents the configuration of the in-memory cache of the user details retrieved from
+// the authentication backend.
+type UserDetailsCacheConfiguration struct {
+	Enabled    bool          `koanf:"enabled"`
+	Lifespan   time.Duration `koanf:"lifespan"`
+	MaxEntries int           `koanf:"max_entries"`
+}
+
+// DefaultUserDetailsCacheConfiguration represents the default user details cache configuration.
+var DefaultUserDetailsCacheConfiguration = UserDetailsCacheConfiguration{
+	Lifespan:   time.Minute,
+	MaxEntries: 1000,
+}
+
+// DefaultBreakGlassAuthenticationBackendConfiguration represents the default break-glass account configuration.
+var DefaultBreakGlassAuthenticationBackendConfiguration = BreakGlassAuthenticationBackendConfiguration{
+	DisplayName: "Break-Glass Administrator",
+}
+
+// DefaultTrustedHeaderAuthenticationBackendConfiguration represents the default trusted header configuration.
+var DefaultTrustedHeaderAuthenticationBackendConfiguration = TrustedHeaderAuthenticationBackendConfiguration{
+	Header:       "Remote-User",
+	GroupsHeader: "Remote-Groups",
+}
+
+// DefaultTrustedJWTAuthenticationBackendConfiguration represents the default trusted JWT configuration.
+var DefaultTrustedJWTAuthenticationBackendConfiguration = TrustedJWTAuthenticationBackendConfiguration{
+	Header: "X-Forwarded-JWT",
+	JWKSCache: JWKSCacheConfiguration{
+		Lifespan: time.Hour,
+		MaxStale: time.Hour * 24,
+		MaxSize:  1024 * 1024,
+	},
+	Algorithms: []string{"RS256"},
+	Leeway:     time.Minute,
+	Claims: TrustedJWTClaimsConfiguration{
+		Username:    "preferred_username",
+		DisplayName: "name",
+		Email:       "email",
+		Groups:      "groups",
+	},
+}
+
+// DefaultAPIKeyAuthenticationBackendConfiguration represents the default API key configuration.
+var DefaultAPIKeyAuthenticationBackendConfiguration = APIKeyAuthenticationBackendConfiguration{
+	Header: "Authorization",
+}
+
+// DefaultClientCertificateAuthenticationBackendConfiguration represents the default client certificate configuration.
+var DefaultClientCertificateAuthenticationBackendConfiguration = ClientCertificateAuthenticationBackendConfiguration{
+	Header:            "X-Forwarded-Tls-Client-Cert",
+	UsernameAttribute: "common_name",
 }
 
 // DefaultPasswordConfiguration represents the default configuration related to Argon2id hashing.
@@ -100,6 +280,7 @@ var DefaultLDAPAuthenticationBackendConfiguration = LDAPAuthenticationBackendCon
 	TLS: &TLSConfig{
 		MinimumVersion: "TLS1.2",
 	},
+	QueueTimeout: time.Second * 5,
 }
 
 // DefaultLDAPAuthenticationBackendImplementationActiveDirectoryConfiguration represents the default LDAP config for the MSAD Implementation.
@@ -110,4 +291,19 @@ var DefaultLDAPAuthenticationBackendImplementationActiveDirectoryConfiguration =
 	DisplayNameAttribute: "displayName",
 	GroupsFilter:         "(&(member={dn})(objectClass=group))",
 	GroupNameAttribute:   "cn",
+	PasswordExpiration: LDAPPasswordExpirationConfiguration{
+		AccountExpiresAttribute:     "accountExpires",
+		PasswordExpiresAttribute:    "msDS-UserPasswordExpiryTimeComputed",
+		PasswordMustChangeAttribute: "pwdLastSet",
+	},
+}
+
+// DefaultLDAPAuthenticationBackendImplementationActiveDirectoryPasswordExpirationUsersFilter represents the default
+// users filter for the MSAD Implementation when password expiration is enabled, it doesn't exclude the users who must
+// change their password so they can be informed of it.
+var DefaultLDAPAuthenticationBackendImplementationActiveDirectoryPasswordExpirationUsersFilter = "(&(|({username_attribute}={input})({mail_attribute}={input}))(sAMAccountType=805306368)(!(userAccountControl:1.2.840.113556.1.4.803:=2)))"
+
+// DefaultLDAPPasswordExpirationConfiguration represents the default LDAP password expiration config.
+var DefaultLDAPPasswordExpirationConfiguration = LDAPPasswordExpirationConfiguration{
+	Action: LDAPPasswordExpirationActionDeny,
 }
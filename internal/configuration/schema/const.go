@@ -28,6 +28,15 @@ const (
 	LDAPImplementationActiveDirectory = "activedirectory"
 )
 
+// LDAP password expiration actions.
+const (
+	// LDAPPasswordExpirationActionDeny denies the authentication of users with an expired password.
+	LDAPPasswordExpirationActionDeny = "deny"
+
+	// LDAPPasswordExpirationActionReset redirects users with an expired password to the password reset flow.
+	LDAPPasswordExpirationActionReset = "reset"
+)
+
 // TOTP Algorithm.
 const (
 	TOTPAlgorithmSHA1   = "SHA1"
@@ -35,6 +44,177 @@ const (
 	TOTPAlgorithmSHA512 = "SHA512"
 )
 
+// Session concurrency policies.
+const (
+	// SessionConcurrencyPolicyReject refuses new logins when the user has reached the concurrent session limit.
+	SessionConcurrencyPolicyReject = "reject"
+
+	// SessionConcurrencyPolicyEvictOldest destroys the oldest session of the user when the concurrent session limit
+	// has been reached.
+	SessionConcurrencyPolicyEvictOldest = "evict_oldest"
+)
+
+// Session binding modes.
+const (
+	// SessionBindingModeNone doesn't bind sessions to the client.
+	SessionBindingModeNone = "none"
+
+	// SessionBindingModeUserAgent binds sessions to the user agent of the client.
+	SessionBindingModeUserAgent = "user_agent"
+
+	// SessionBindingModeNetwork binds sessions to the network of the client IP.
+	SessionBindingModeNetwork = "network"
+
+	// SessionBindingModeUserAgentAndNetwork binds sessions to both the user agent and the network of the client IP.
+	SessionBindingModeUserAgentAndNetwork = "user_agent_and_network"
+)
+
+// Session providers.
+const (
+	// SessionProviderMemory stores sessions in the memory of the Authelia process.
+	SessionProviderMemory = "memory"
+
+	// SessionProviderRedis stores sessions in Redis.
+	SessionProviderRedis = "redis"
+
+	// SessionProviderStorage stores sessions in the SQL storage backend.
+	SessionProviderStorage = "storage"
+)
+
+// Self-test failure modes.
+const (
+	// SelfTestFailureModeFatal prevents the startup when a self-test check fails.
+	SelfTestFailureModeFatal = "fatal"
+
+	// SelfTestFailureModeWarn logs a warning when a self-test check fails.
+	SelfTestFailureModeWarn = "warn"
+)
+
+// Unknown acr values policies.
+const (
+	// UnknownACRValuesIgnore ignores acr values in an authorization request which are not configured.
+	UnknownACRValuesIgnore = "ignore"
+
+	// UnknownACRValuesError rejects authorization requests with acr values which are not configured.
+	UnknownACRValuesError = "error"
+)
+
+// Password reset methods.
+const (
+	// PasswordResetMethodEmail verifies the identity of the user by sending them a link by email.
+	PasswordResetMethodEmail = "email"
+
+	// PasswordResetMethodAdminCode verifies the identity of the user with a one-time code issued by an administrator.
+	PasswordResetMethodAdminCode = "admin_code"
+)
+
+// Log redaction methods.
+const (
+	// LogRedactionMethodHash replaces sensitive values with a salted hash so the same value can be correlated.
+	LogRedactionMethodHash = "hash"
+
+	// LogRedactionMethodMask replaces sensitive values with a fixed mask.
+	LogRedactionMethodMask = "mask"
+)
+
+// Log redaction fields.
+const (
+	// LogRedactionFieldUsername redacts usernames.
+	LogRedactionFieldUsername = "username"
+
+	// LogRedactionFieldRemoteIP redacts IP addresses.
+	LogRedactionFieldRemoteIP = "remote_ip"
+
+	// LogRedactionFieldEmail redacts email addresses.
+	LogRedactionFieldEmail = "email"
+)
+
+// Log syslog networks.
+const (
+	// LogSyslogNetworkUnix writes the logs to the local syslog socket.
+	LogSyslogNetworkUnix = "unix"
+
+	// LogSyslogNetworkUDP writes the logs to a remote syslog server over UDP.
+	LogSyslogNetworkUDP = "udp"
+
+	// LogSyslogNetworkTCP writes the logs to a remote syslog server over TCP.
+	LogSyslogNetworkTCP = "tcp"
+
+	// LogSyslogNetworkTCPTLS writes the logs to a remote syslog server over TCP with TLS.
+	LogSyslogNetworkTCPTLS = "tcp+tls"
+)
+
+// CAPTCHA providers.
+const (
+	// CAPTCHAProviderReCAPTCHA is the Google reCAPTCHA provider.
+	CAPTCHAProviderReCAPTCHA = "recaptcha"
+
+	// CAPTCHAProviderHCaptcha is the hCaptcha provider.
+	CAPTCHAProviderHCaptcha = "hcaptcha"
+
+	// CAPTCHAProviderTurnstile is the Cloudflare Turnstile provider.
+	CAPTCHAProviderTurnstile = "turnstile"
+)
+
+// CAPTCHA failure modes.
+const (
+	// CAPTCHAFailureModeClosed rejects the authentication attempt when the CAPTCHA provider can't be reached.
+	CAPTCHAFailureModeClosed = "closed"
+
+	// CAPTCHAFailureModeOpen skips the CAPTCHA challenge when the CAPTCHA provider can't be reached.
+	CAPTCHAFailureModeOpen = "open"
+)
+
+// Second factor limit actions.
+const (
+	// SecondFactorLimitActionDestroySession destroys the session once the maximum number of failed second factor
+	// attempts is reached so the user has to complete the first factor again.
+	SecondFactorLimitActionDestroySession = "destroy_session"
+
+	// SecondFactorLimitActionBan destroys the session and bans the user for the regulation ban time once the maximum
+	// number of failed second factor attempts is reached.
+	SecondFactorLimitActionBan = "ban"
+)
+
+// Duo failure modes.
+const (
+	// DuoFailureModeDeny rejects the second factor when the Duo API times out or can't be reached.
+	DuoFailureModeDeny = "deny"
+
+	// DuoFailureModeAllow completes the second factor with a warning when the Duo API times out or can't be reached.
+	DuoFailureModeAllow = "allow"
+)
+
+// Sensitive actions.
+const (
+	// SensitiveActionTOTPRegistration is the registration of a TOTP configuration.
+	SensitiveActionTOTPRegistration = "totp_registration"
+
+	// SensitiveActionTOTPDeletion is the deletion of a TOTP configuration.
+	SensitiveActionTOTPDeletion = "totp_deletion"
+
+	// SensitiveActionTOTPRotation is the rotation of the secret of a TOTP configuration.
+	SensitiveActionTOTPRotation = "totp_rotation"
+
+	// SensitiveActionWebauthnRegistration is the registration of a Webauthn device.
+	SensitiveActionWebauthnRegistration = "webauthn_registration"
+
+	// SensitiveActionDuoDeviceSelection is the selection of the Duo device and method.
+	SensitiveActionDuoDeviceSelection = "duo_device_selection"
+
+	// SensitiveActionPreferredMethod is the change of the preferred second factor method.
+	SensitiveActionPreferredMethod = "preferred_method"
+
+	// SensitiveActionOpenIDConnectRefreshTokenRevocation is the revocation of an OpenID Connect refresh token.
+	SensitiveActionOpenIDConnectRefreshTokenRevocation = "oidc_refresh_token_revocation"
+
+	// SensitiveActionAdministration is the use of the administration endpoints.
+	SensitiveActionAdministration = "administration"
+)
+
+// ACLTimeWindowTimeLayout is the layout of the start and end of an access control rule time window.
+const ACLTimeWindowTimeLayout = "15:04"
+
 const (
 	// RememberMeDisabled represents the duration for a disabled remember me session configuration.
 	RememberMeDisabled = time.Second * -1
@@ -43,6 +223,9 @@ const (
 var (
 	// TOTPPossibleAlgorithms is a list of valid TOTP Algorithms.
 	TOTPPossibleAlgorithms = []string{TOTPAlgorithmSHA1, TOTPAlgorithmSHA256, TOTPAlgorithmSHA512}
+
+	// TOTPAccountLabelPlaceholders is a list of valid TOTP account label placeholders.
+	TOTPAccountLabelPlaceholders = []string{"username", "email", "displayname"}
 )
 
 const (
@@ -1,9 +1,30 @@
 package schema
 
+import (
+	"time"
+)
+
 // DuoAPIConfiguration represents the configuration related to Duo API.
 type DuoAPIConfiguration struct {
-	Hostname             string `koanf:"hostname"`
-	EnableSelfEnrollment bool   `koanf:"enable_self_enrollment"`
-	IntegrationKey       string `koanf:"integration_key"`
-	SecretKey            string `koanf:"secret_key"`
+	Hostname             string                      `koanf:"hostname"`
+	EnableSelfEnrollment bool                        `koanf:"enable_self_enrollment"`
+	IntegrationKey       string                      `koanf:"integration_key"`
+	SecretKey            string                      `koanf:"secret_key"`
+	Timeouts             DuoAPITimeoutsConfiguration `koanf:"timeouts"`
+	FailureMode          string                      `koanf:"failure_mode"`
+}
+
+// DuoAPITimeoutsConfiguration represents the timeouts of the calls to the Duo API endpoints.
+type DuoAPITimeoutsConfiguration struct {
+	PreAuth time.Duration `koanf:"preauth,weak"`
+	Auth    time.Duration `koanf:"auth,weak"`
+}
+
+// DefaultDuoAPIConfiguration represents the default Duo API configuration.
+var DefaultDuoAPIConfiguration = DuoAPIConfiguration{
+	Timeouts: DuoAPITimeoutsConfiguration{
+		PreAuth: time.Second * 10,
+		Auth:    time.Second * 75,
+	},
+	FailureMode: DuoFailureModeDeny,
 }
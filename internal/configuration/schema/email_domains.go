@@ -0,0 +1,8 @@
+package schema
+
+// EmailDomainsConfiguration represents the configuration of the email domains Authelia is permitted to send identity
+// verification emails to and which self-registered accounts may use.
+type EmailDomainsConfiguration struct {
+	Allowed []string `koanf:"allowed"`
+	Denied  []string `koanf:"denied"`
+}
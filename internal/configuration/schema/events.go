@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"time"
+)
+
+// EventsConfiguration represents the configuration of the event sinks authentication and lifecycle events are
+// published to.
+type EventsConfiguration struct {
+	BufferSize int                      `koanf:"buffer_size"`
+	NATS       *NATSEventsConfiguration `koanf:"nats"`
+}
+
+// NATSEventsConfiguration represents the configuration of the NATS server to publish events to.
+type NATSEventsConfiguration struct {
+	Host          string        `koanf:"host"`
+	Port          int           `koanf:"port"`
+	Timeout       time.Duration `koanf:"timeout"`
+	Username      string        `koanf:"username"`
+	Password      string        `koanf:"password"`
+	Token         string        `koanf:"token"`
+	SubjectPrefix string        `koanf:"subject_prefix"`
+	TLS           *TLSConfig    `koanf:"tls"`
+}
+
+// DefaultEventsConfiguration represents the default configuration parameters for the event sinks.
+var DefaultEventsConfiguration = EventsConfiguration{
+	BufferSize: 1024,
+}
+
+// DefaultNATSEventsConfiguration represents the default configuration parameters for the NATS event sink.
+var DefaultNATSEventsConfiguration = NATSEventsConfiguration{
+	Port:          4222,
+	Timeout:       time.Second * 5,
+	SubjectPrefix: "authelia",
+	TLS: &TLSConfig{
+		MinimumVersion: "TLS1.2",
+	},
+}
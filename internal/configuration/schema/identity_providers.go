@@ -15,22 +15,76 @@ type OpenIDConnectConfiguration struct {
 	HMACSecret       string `koanf:"hmac_secret"`
 	IssuerPrivateKey string `koanf:"issuer_private_key"`
 
+	IssuerPrivateKeys      []OpenIDConnectIssuerPrivateKeyConfiguration `koanf:"issuer_private_keys"`
+	KeyRotationGracePeriod time.Duration                                `koanf:"key_rotation_grace_period"`
+
 	AccessTokenLifespan   time.Duration `koanf:"access_token_lifespan"`
 	AuthorizeCodeLifespan time.Duration `koanf:"authorize_code_lifespan"`
 	IDTokenLifespan       time.Duration `koanf:"id_token_lifespan"`
 	RefreshTokenLifespan  time.Duration `koanf:"refresh_token_lifespan"`
 
+	ClockSkew time.Duration `koanf:"clock_skew"`
+
+	RevokeRefreshTokensOnLogout bool `koanf:"revoke_refresh_tokens_on_logout"`
+
 	EnableClientDebugMessages bool `koanf:"enable_client_debug_messages"`
 	MinimumParameterEntropy   int  `koanf:"minimum_parameter_entropy"`
 
 	EnforcePKCE              string `koanf:"enforce_pkce"`
 	EnablePKCEPlainChallenge bool   `koanf:"enable_pkce_plain_challenge"`
 
+	AllowedGrantTypes    []string `koanf:"allowed_grant_types"`
+	AllowedResponseTypes []string `koanf:"allowed_response_types"`
+
 	CORS OpenIDConnectCORSConfiguration `koanf:"cors"`
 
+	CustomScopes []OpenIDConnectCustomScopeConfiguration `koanf:"custom_scopes"`
+
+	ACRValues        []OpenIDConnectACRValueConfiguration `koanf:"acr_values"`
+	UnknownACRValues string                               `koanf:"unknown_acr_values"`
+
+	TokenEndpoint OpenIDConnectTokenEndpointConfiguration `koanf:"token_endpoint"`
+
+	ClientsPath           string        `koanf:"clients_path"`
+	ClientsReloadInterval time.Duration `koanf:"clients_reload_interval"`
+
 	Clients []OpenIDConnectClientConfiguration `koanf:"clients"`
 }
 
+// OpenIDConnectIssuerPrivateKeyConfiguration represents an additional OpenID Connect issuer private key which is either
+// scheduled to become the signing key at a point in time or is only published for verification.
+type OpenIDConnectIssuerPrivateKeyConfiguration struct {
+	Key        string    `koanf:"key"`
+	ActivateAt time.Time `koanf:"activate_at"`
+	ExpiresAt  time.Time `koanf:"expires_at"`
+}
+
+// OpenIDConnectTokenEndpointConfiguration represents the request size and rate limits of the OpenID Connect token
+// endpoint which apply independently of any other limits.
+type OpenIDConnectTokenEndpointConfiguration struct {
+	MaxRequestBodySize int `koanf:"max_request_body_size"`
+
+	DisableRateLimit          bool          `koanf:"disable_rate_limit"`
+	RateLimit                 int           `koanf:"rate_limit"`
+	ClientRateLimit           int           `koanf:"client_rate_limit"`
+	FirstPartyClientRateLimit int           `koanf:"first_party_client_rate_limit"`
+	RateLimitPeriod           time.Duration `koanf:"rate_limit_period"`
+}
+
+// OpenIDConnectCustomScopeConfiguration represents a custom OpenID Connect scope and the claims it releases.
+type OpenIDConnectCustomScopeConfiguration struct {
+	Name        string   `koanf:"name"`
+	Description string   `koanf:"description"`
+	Claims      []string `koanf:"claims"`
+}
+
+// OpenIDConnectACRValueConfiguration represents an Authentication Context Class Reference value which can be requested
+// with the acr_values parameter and the authorization policy it requires.
+type OpenIDConnectACRValueConfiguration struct {
+	Value  string `koanf:"value"`
+	Policy string `koanf:"policy"`
+}
+
 // OpenIDConnectCORSConfiguration represents an OpenID Connect CORS config.
 type OpenIDConnectCORSConfiguration struct {
 	Endpoints      []string  `koanf:"endpoints"`
@@ -47,30 +101,75 @@ type OpenIDConnectClientConfiguration struct {
 	SectorIdentifier url.URL `koanf:"sector_identifier"`
 	Public           bool    `koanf:"public"`
 
-	RedirectURIs []string `koanf:"redirect_uris"`
+	PreviousSecret          string    `koanf:"previous_secret"`
+	PreviousSecretExpiresAt time.Time `koanf:"previous_secret_expires_at"`
 
-	Audience      []string `koanf:"audience"`
-	Scopes        []string `koanf:"scopes"`
-	GrantTypes    []string `koanf:"grant_types"`
-	ResponseTypes []string `koanf:"response_types"`
-	ResponseModes []string `koanf:"response_modes"`
+	RedirectURIs   []string  `koanf:"redirect_uris"`
+	AllowedOrigins []url.URL `koanf:"allowed_origins"`
+	AllowedGroups  []string  `koanf:"allowed_groups"`
 
+	Audience       []string                           `koanf:"audience"`
+	ScopeAudiences []OpenIDConnectClientScopeAudience `koanf:"scope_audiences"`
+	Scopes         []string                           `koanf:"scopes"`
+	DefaultScopes  []string                           `koanf:"default_scopes"`
+	GrantTypes     []string                           `koanf:"grant_types"`
+	ResponseTypes  []string                           `koanf:"response_types"`
+	ResponseModes  []string                           `koanf:"response_modes"`
+
+	TokenEndpointAuthMethod  string `koanf:"token_endpoint_auth_method"`
 	UserinfoSigningAlgorithm string `koanf:"userinfo_signing_algorithm"`
 
+	RequirePKCE         bool   `koanf:"require_pkce"`
+	PKCEChallengeMethod string `koanf:"pkce_challenge_method"`
+
 	Policy string `koanf:"authorization_policy"`
 
 	PreConfiguredConsentDuration *time.Duration `koanf:"pre_configured_consent_duration"`
+
+	FirstParty          bool     `koanf:"first_party"`
+	PreAuthorizedScopes []string `koanf:"pre_authorized_scopes"`
+
+	Introspection OpenIDConnectClientIntrospectionConfiguration `koanf:"introspection"`
+}
+
+// OpenIDConnectClientIntrospectionConfiguration represents the OAuth 2.0 Token Introspection permissions of a client.
+type OpenIDConnectClientIntrospectionConfiguration struct {
+	Enabled        bool     `koanf:"enabled"`
+	AllowedClients []string `koanf:"allowed_clients"`
+	Scopes         []string `koanf:"scopes"`
+}
+
+// OpenIDConnectClientScopeAudience represents the audiences granted to the tokens of a client when a scope is granted.
+type OpenIDConnectClientScopeAudience struct {
+	Scope    string   `koanf:"scope"`
+	Audience []string `koanf:"audience"`
 }
 
 // DefaultOpenIDConnectConfiguration contains defaults for OIDC.
 var DefaultOpenIDConnectConfiguration = OpenIDConnectConfiguration{
-	AccessTokenLifespan:   time.Hour,
-	AuthorizeCodeLifespan: time.Minute,
-	IDTokenLifespan:       time.Hour,
-	RefreshTokenLifespan:  time.Minute * 90,
-	EnforcePKCE:           "public_clients_only",
+	AccessTokenLifespan:    time.Hour,
+	AuthorizeCodeLifespan:  time.Minute,
+	IDTokenLifespan:        time.Hour,
+	RefreshTokenLifespan:   time.Minute * 90,
+	KeyRotationGracePeriod: time.Hour * 24,
+	ClockSkew:              time.Second * 5,
+	ClientsReloadInterval:  time.Minute,
+	EnforcePKCE:            "public_clients_only",
+	UnknownACRValues:       UnknownACRValuesIgnore,
+	AllowedGrantTypes:      []string{"authorization_code", "implicit", "refresh_token", "client_credentials"},
+	AllowedResponseTypes:   []string{"code", "token", "id_token", "code token", "code id_token", "token id_token", "code token id_token", "none"},
+	TokenEndpoint: OpenIDConnectTokenEndpointConfiguration{
+		MaxRequestBodySize:        32768,
+		RateLimit:                 300,
+		ClientRateLimit:           300,
+		FirstPartyClientRateLimit: 1500,
+		RateLimitPeriod:           time.Minute,
+	},
 }
 
+// MaximumOpenIDConnectClockSkew is the maximum clock skew which can be configured for OIDC.
+const MaximumOpenIDConnectClockSkew = time.Minute * 5
+
 // DefaultOpenIDConnectClientConfiguration contains defaults for OIDC Clients.
 var DefaultOpenIDConnectClientConfiguration = OpenIDConnectClientConfiguration{
 	Policy:        "two_factor",
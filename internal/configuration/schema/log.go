@@ -1,11 +1,45 @@
 package schema
 
+import (
+	"time"
+)
+
 // LogConfiguration represents the logging configuration.
 type LogConfiguration struct {
 	Level      string `koanf:"level"`
 	Format     string `koanf:"format"`
 	FilePath   string `koanf:"file_path"`
 	KeepStdout bool   `koanf:"keep_stdout"`
+
+	SlowRequestThreshold time.Duration `koanf:"slow_request_threshold"`
+
+	Redaction LogRedactionConfiguration `koanf:"redaction"`
+
+	Syslog   LogSyslogConfiguration   `koanf:"syslog"`
+	Journald LogJournaldConfiguration `koanf:"journald"`
+}
+
+// LogRedactionConfiguration represents the configuration of the redaction of sensitive values from log lines.
+type LogRedactionConfiguration struct {
+	Enabled bool     `koanf:"enabled"`
+	Method  string   `koanf:"method"`
+	Secret  string   `koanf:"secret"`
+	Fields  []string `koanf:"fields"`
+}
+
+// LogSyslogConfiguration represents the configuration of the output of the logs to a local or remote syslog server.
+type LogSyslogConfiguration struct {
+	Enabled  bool       `koanf:"enabled"`
+	Network  string     `koanf:"network"`
+	Address  string     `koanf:"address"`
+	Facility string     `koanf:"facility"`
+	Tag      string     `koanf:"tag"`
+	TLS      *TLSConfig `koanf:"tls"`
+}
+
+// LogJournaldConfiguration represents the configuration of the output of the logs to the systemd journal.
+type LogJournaldConfiguration struct {
+	Enabled bool `koanf:"enabled"`
 }
 
 // DefaultLoggingConfiguration is the default logging configuration.
@@ -13,3 +47,19 @@ var DefaultLoggingConfiguration = LogConfiguration{
 	Level:  "info",
 	Format: "text",
 }
+
+// DefaultLogSyslogConfiguration is the default log syslog configuration.
+var DefaultLogSyslogConfiguration = LogSyslogConfiguration{
+	Network:  LogSyslogNetworkUnix,
+	Facility: "daemon",
+	Tag:      "authelia",
+	TLS: &TLSConfig{
+		MinimumVersion: "TLS1.2",
+	},
+}
+
+// DefaultLogRedactionConfiguration is the default log redaction configuration.
+var DefaultLogRedactionConfiguration = LogRedactionConfiguration{
+	Method: LogRedactionMethodHash,
+	Fields: []string{LogRedactionFieldUsername, LogRedactionFieldRemoteIP, LogRedactionFieldEmail},
+}
@@ -0,0 +1,20 @@
+package schema
+
+import (
+	"time"
+)
+
+// LoginNotificationsConfiguration represents the configuration of the notifications sent to users when they log in
+// from a device or location which hasn't been seen before.
+type LoginNotificationsConfiguration struct {
+	Enabled       bool          `koanf:"enabled"`
+	Remember      time.Duration `koanf:"remember"`
+	Throttle      time.Duration `koanf:"throttle"`
+	HeaderCountry string        `koanf:"header_country"`
+}
+
+// DefaultLoginNotificationsConfiguration represents the default configuration parameters for login notifications.
+var DefaultLoginNotificationsConfiguration = LoginNotificationsConfiguration{
+	Remember: time.Hour * 24 * 90,
+	Throttle: time.Hour,
+}
@@ -26,12 +26,30 @@ type SMTPNotifierConfiguration struct {
 	TLS                 *TLSConfig    `koanf:"tls"`
 }
 
+// WebhookNotifierConfiguration represents the configuration of the notifier sending notifications to a HTTP endpoint.
+type WebhookNotifierConfiguration struct {
+	URL     string        `koanf:"url"`
+	Timeout time.Duration `koanf:"timeout"`
+}
+
+// NotifierInstanceConfiguration represents the configuration of one of the notifiers which are sent notifications
+// simultaneously, optionally scoped to a list of event types.
+type NotifierInstanceConfiguration struct {
+	Name       string                           `koanf:"name"`
+	Events     []string                         `koanf:"events"`
+	FileSystem *FileSystemNotifierConfiguration `koanf:"filesystem"`
+	SMTP       *SMTPNotifierConfiguration       `koanf:"smtp"`
+	Webhook    *WebhookNotifierConfiguration    `koanf:"webhook"`
+}
+
 // NotifierConfiguration represents the configuration of the notifier to use when sending notifications to users.
 type NotifierConfiguration struct {
 	DisableStartupCheck bool                             `koanf:"disable_startup_check"`
 	FileSystem          *FileSystemNotifierConfiguration `koanf:"filesystem"`
 	SMTP                *SMTPNotifierConfiguration       `koanf:"smtp"`
 	TemplatePath        string                           `koanf:"template_path"`
+
+	Notifiers []NotifierInstanceConfiguration `koanf:"notifiers"`
 }
 
 // DefaultSMTPNotifierConfiguration represents default configuration parameters for the SMTP notifier.
@@ -44,3 +62,8 @@ var DefaultSMTPNotifierConfiguration = SMTPNotifierConfiguration{
 		MinimumVersion: "TLS1.2",
 	},
 }
+
+// DefaultWebhookNotifierConfiguration represents default configuration parameters for the webhook notifier.
+var DefaultWebhookNotifierConfiguration = WebhookNotifierConfiguration{
+	Timeout: time.Second * 5,
+}
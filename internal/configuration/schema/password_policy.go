@@ -1,5 +1,9 @@
 package schema
 
+import (
+	"time"
+)
+
 // PasswordPolicyStandardParams represents the configuration related to standard parameters of password policy.
 type PasswordPolicyStandardParams struct {
 	Enabled          bool `koanf:"enabled"`
@@ -20,6 +24,20 @@ type PasswordPolicyZXCVBNParams struct {
 type PasswordPolicyConfiguration struct {
 	Standard PasswordPolicyStandardParams `koanf:"standard"`
 	ZXCVBN   PasswordPolicyZXCVBNParams   `koanf:"zxcvbn"`
+
+	Policies []PasswordPolicyGroupConfiguration `koanf:"policies"`
+
+	MinimumAge time.Duration `koanf:"minimum_age"`
+}
+
+// PasswordPolicyGroupConfiguration represents the configuration related to a named password policy which applies to
+// the members of a list of groups instead of the global password policy.
+type PasswordPolicyGroupConfiguration struct {
+	Name   string   `koanf:"name"`
+	Groups []string `koanf:"groups"`
+
+	Standard PasswordPolicyStandardParams `koanf:"standard"`
+	ZXCVBN   PasswordPolicyZXCVBNParams   `koanf:"zxcvbn"`
 }
 
 // DefaultPasswordPolicyConfiguration is the default password policy configuration.
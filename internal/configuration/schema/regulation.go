@@ -9,6 +9,48 @@ type RegulationConfiguration struct {
 	MaxRetries int           `koanf:"max_retries"`
 	FindTime   time.Duration `koanf:"find_time,weak"`
 	BanTime    time.Duration `koanf:"ban_time,weak"`
+
+	ImpossibleTravel ImpossibleTravelConfiguration `koanf:"impossible_travel"`
+	CAPTCHA          CAPTCHAConfiguration          `koanf:"captcha"`
+	Unlock           RegulationUnlockConfiguration `koanf:"unlock"`
+
+	SecondFactor RegulationSecondFactorConfiguration `koanf:"second_factor"`
+}
+
+// ImpossibleTravelConfiguration represents the configuration related to impossible travel detection.
+type ImpossibleTravelConfiguration struct {
+	Enabled         bool     `koanf:"enabled"`
+	Networks        []string `koanf:"networks"`
+	HeaderLatitude  string   `koanf:"header_latitude"`
+	HeaderLongitude string   `koanf:"header_longitude"`
+	MaximumSpeed    int      `koanf:"maximum_speed"`
+	MinimumDistance int      `koanf:"minimum_distance"`
+	Notify          bool     `koanf:"notify"`
+}
+
+// CAPTCHAConfiguration represents the configuration related to the CAPTCHA challenge required after failed attempts.
+type CAPTCHAConfiguration struct {
+	Provider    string        `koanf:"provider"`
+	SiteKey     string        `koanf:"site_key"`
+	SecretKey   string        `koanf:"secret_key"`
+	Threshold   int           `koanf:"threshold"`
+	Timeout     time.Duration `koanf:"timeout,weak"`
+	FailureMode string        `koanf:"failure_mode"`
+}
+
+// RegulationUnlockConfiguration represents the configuration related to the self-service unlock of banned users via a
+// link sent to their email address.
+type RegulationUnlockConfiguration struct {
+	Enabled     bool          `koanf:"enabled"`
+	Lifespan    time.Duration `koanf:"lifespan,weak"`
+	MaxAttempts int           `koanf:"max_attempts"`
+}
+
+// RegulationSecondFactorConfiguration represents the configuration related to the maximum number of consecutive failed
+// second factor attempts within a session and the action taken once it's reached.
+type RegulationSecondFactorConfiguration struct {
+	MaxAttempts int    `koanf:"max_attempts"`
+	Action      string `koanf:"action"`
 }
 
 // DefaultRegulationConfiguration represents default configuration parameters for the regulator.
@@ -16,4 +58,21 @@ var DefaultRegulationConfiguration = RegulationConfiguration{
 	MaxRetries: 3,
 	FindTime:   time.Minute * 2,
 	BanTime:    time.Minute * 5,
+	ImpossibleTravel: ImpossibleTravelConfiguration{
+		HeaderLatitude:  "X-Geo-Latitude",
+		HeaderLongitude: "X-Geo-Longitude",
+		MaximumSpeed:    1000,
+		MinimumDistance: 100,
+	},
+	CAPTCHA: CAPTCHAConfiguration{
+		Timeout:     time.Second * 5,
+		FailureMode: CAPTCHAFailureModeClosed,
+	},
+	Unlock: RegulationUnlockConfiguration{
+		Lifespan:    time.Minute * 15,
+		MaxAttempts: 3,
+	},
+	SecondFactor: RegulationSecondFactorConfiguration{
+		Action: SecondFactorLimitActionDestroySession,
+	},
 }
@@ -0,0 +1,18 @@
+package schema
+
+import (
+	"time"
+)
+
+// SecondFactorEnrollmentConfiguration represents the configuration which forces users to enroll a second factor
+// method within a grace period after their first login.
+type SecondFactorEnrollmentConfiguration struct {
+	Enforce     bool          `koanf:"enforce"`
+	GracePeriod time.Duration `koanf:"grace_period"`
+}
+
+// DefaultSecondFactorEnrollmentConfiguration represents the default configuration parameters for the enforcement of
+// second factor enrollment.
+var DefaultSecondFactorEnrollmentConfiguration = SecondFactorEnrollmentConfiguration{
+	GracePeriod: time.Hour * 24 * 7,
+}
@@ -0,0 +1,32 @@
+package schema
+
+import (
+	"time"
+)
+
+// SelfRegistrationConfiguration represents the configuration related to account self-registration.
+type SelfRegistrationConfiguration struct {
+	Enabled           bool                                           `koanf:"enabled"`
+	AdminEmails       []string                                       `koanf:"admin_emails"`
+	DefaultGroups     []string                                       `koanf:"default_groups"`
+	RateLimit         int                                            `koanf:"rate_limit"`
+	RateLimitPeriod   time.Duration                                  `koanf:"rate_limit_period"`
+	EmailVerification SelfRegistrationEmailVerificationConfiguration `koanf:"email_verification"`
+}
+
+// SelfRegistrationEmailVerificationConfiguration represents the configuration related to verifying the email address
+// of self-registered accounts.
+type SelfRegistrationEmailVerificationConfiguration struct {
+	Enabled  bool          `koanf:"enabled"`
+	Lifespan time.Duration `koanf:"lifespan"`
+}
+
+// DefaultSelfRegistrationConfiguration is the default self-registration configuration.
+var DefaultSelfRegistrationConfiguration = SelfRegistrationConfiguration{
+	Enabled:         false,
+	RateLimit:       10,
+	RateLimitPeriod: time.Hour,
+	EmailVerification: SelfRegistrationEmailVerificationConfiguration{
+		Lifespan: time.Hour * 24,
+	},
+}
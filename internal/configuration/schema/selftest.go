@@ -0,0 +1,18 @@
+package schema
+
+// SelfTestConfiguration represents the configuration of the self-test which checks the connectivity of the providers
+// during startup.
+type SelfTestConfiguration struct {
+	Enabled     bool   `koanf:"enabled"`
+	FailureMode string `koanf:"failure_mode"`
+
+	DisableAuthenticationBackend bool `koanf:"disable_authentication_backend"`
+	DisableSession               bool `koanf:"disable_session"`
+	DisableStorage               bool `koanf:"disable_storage"`
+	DisableNotifier              bool `koanf:"disable_notifier"`
+}
+
+// DefaultSelfTestConfiguration represents the default self-test configuration.
+var DefaultSelfTestConfiguration = SelfTestConfiguration{
+	FailureMode: SelfTestFailureModeFatal,
+}
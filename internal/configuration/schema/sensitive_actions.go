@@ -0,0 +1,26 @@
+package schema
+
+import (
+	"time"
+)
+
+// SensitiveActionsConfiguration represents the configuration which requires users to have recently completed a factor
+// to perform sensitive actions.
+type SensitiveActionsConfiguration struct {
+	Enabled bool          `koanf:"enabled"`
+	Window  time.Duration `koanf:"window"`
+	Actions []string      `koanf:"actions"`
+}
+
+// DefaultSensitiveActionsConfiguration represents the default configuration parameters for sensitive actions.
+var DefaultSensitiveActionsConfiguration = SensitiveActionsConfiguration{
+	Window: time.Minute * 5,
+	Actions: []string{
+		SensitiveActionTOTPRegistration,
+		SensitiveActionTOTPDeletion,
+		SensitiveActionTOTPRotation,
+		SensitiveActionWebauthnRegistration,
+		SensitiveActionDuoDeviceSelection,
+		SensitiveActionAdministration,
+	},
+}
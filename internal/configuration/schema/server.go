@@ -1,5 +1,9 @@
 package schema
 
+import (
+	"time"
+)
+
 // ServerConfiguration represents the configuration of the http server.
 type ServerConfiguration struct {
 	Host               string `koanf:"host"`
@@ -12,8 +16,24 @@ type ServerConfiguration struct {
 	EnableExpvars      bool   `koanf:"enable_expvars"`
 	DisableHealthcheck bool   `koanf:"disable_healthcheck"`
 
-	TLS     ServerTLSConfiguration     `koanf:"tls"`
-	Headers ServerHeadersConfiguration `koanf:"headers"`
+	EnableMatchedRuleHeader bool `koanf:"enable_matched_rule_header"`
+
+	ReadTimeout              time.Duration `koanf:"read_timeout"`
+	WriteTimeout             time.Duration `koanf:"write_timeout"`
+	IdleTimeout              time.Duration `koanf:"idle_timeout"`
+	MaxConnections           int           `koanf:"max_connections"`
+	MaxConnectionsPerIP      int           `koanf:"max_connections_per_ip"`
+	MaxRequestsPerConnection int           `koanf:"max_requests_per_connection"`
+	DisableKeepAlive         bool          `koanf:"disable_keep_alive"`
+
+	TrustedProxies []string `koanf:"trusted_proxies"`
+
+	RobotsTXT   string `koanf:"robots_txt"`
+	SecurityTXT string `koanf:"security_txt"`
+
+	TLS           ServerTLSConfiguration           `koanf:"tls"`
+	Headers       ServerHeadersConfiguration       `koanf:"headers"`
+	ProxyProtocol ServerProxyProtocolConfiguration `koanf:"proxy_protocol"`
 }
 
 // ServerTLSConfiguration represents the configuration of the http servers TLS options.
@@ -21,11 +41,27 @@ type ServerTLSConfiguration struct {
 	Certificate        string   `koanf:"certificate"`
 	Key                string   `koanf:"key"`
 	ClientCertificates []string `koanf:"client_certificates"`
+
+	MinimumVersion string   `koanf:"minimum_version"`
+	MaximumVersion string   `koanf:"maximum_version"`
+	CipherSuites   []string `koanf:"cipher_suites"`
 }
 
 // ServerHeadersConfiguration represents the customization of the http server headers.
 type ServerHeadersConfiguration struct {
 	CSPTemplate string `koanf:"csp_template"`
+
+	ReferrerPolicy          string `koanf:"referrer_policy"`
+	PermissionsPolicy       string `koanf:"permissions_policy"`
+	FrameOptions            string `koanf:"frame_options"`
+	StrictTransportSecurity string `koanf:"strict_transport_security"`
+}
+
+// ServerProxyProtocolConfiguration represents the configuration of the PROXY protocol support of the http server.
+type ServerProxyProtocolConfiguration struct {
+	Enabled          bool          `koanf:"enabled"`
+	TrustedUpstreams []string      `koanf:"trusted_upstreams"`
+	HeaderTimeout    time.Duration `koanf:"header_timeout"`
 }
 
 // DefaultServerConfiguration represents the default values of the ServerConfiguration.
@@ -34,4 +70,24 @@ var DefaultServerConfiguration = ServerConfiguration{
 	Port:            9091,
 	ReadBufferSize:  4096,
 	WriteBufferSize: 4096,
+	ReadTimeout:     time.Second * 6,
+	WriteTimeout:    time.Second * 6,
+	IdleTimeout:     time.Second * 30,
+	RobotsTXT:       "User-agent: *\nDisallow: /\n",
+	TLS: ServerTLSConfiguration{
+		MinimumVersion: "TLS1.2",
+		MaximumVersion: "TLS1.3",
+	},
+	Headers: ServerHeadersConfiguration{
+		ReferrerPolicy:          "strict-origin-when-cross-origin",
+		PermissionsPolicy:       "accelerometer=(), autoplay=(), camera=(), display-capture=(), geolocation=(), gyroscope=(), keyboard-map=(), magnetometer=(), microphone=(), midi=(), payment=(), picture-in-picture=(), screen-wake-lock=(), sync-xhr=(), usb=(), xr-spatial-tracking=(), interest-cohort=()",
+		FrameOptions:            "DENY",
+		StrictTransportSecurity: "max-age=31536000",
+	},
+	ProxyProtocol: ServerProxyProtocolConfiguration{
+		HeaderTimeout: time.Second * 5,
+	},
 }
+
+// ServerHeaderValueDisabled is the value which disables a configurable server header.
+const ServerHeaderValueDisabled = "none"
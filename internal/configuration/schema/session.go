@@ -33,16 +33,73 @@ type RedisSessionConfiguration struct {
 	HighAvailability         *RedisHighAvailabilityConfiguration `koanf:"high_availability"`
 }
 
+// SessionConcurrencyConfiguration represents the configuration related to the concurrent sessions of a single user.
+type SessionConcurrencyConfiguration struct {
+	Limit           int    `koanf:"limit"`
+	RememberMeLimit int    `koanf:"remember_me_limit"`
+	Policy          string `koanf:"policy"`
+}
+
+// SessionRememberMeConfiguration represents the configuration of sessions when the remember me box is checked. The
+// session option remember_me_duration is a shorthand for the validity and cookie max age.
+type SessionRememberMeConfiguration struct {
+	Validity                   time.Duration `koanf:"validity"`
+	CookieMaxAge               time.Duration `koanf:"cookie_max_age"`
+	SecondFactorReverification time.Duration `koanf:"second_factor_reverification"`
+}
+
+// SessionIntrospectionConfiguration represents the configuration of the endpoint which trusted services use to
+// introspect the session of a user.
+type SessionIntrospectionConfiguration struct {
+	Enabled         bool          `koanf:"enabled"`
+	TrustedNetworks []string      `koanf:"trusted_networks"`
+	Secret          string        `koanf:"secret"`
+	RateLimit       int           `koanf:"rate_limit"`
+	RateLimitPeriod time.Duration `koanf:"rate_limit_period"`
+}
+
+// SessionBindingConfiguration represents the configuration which binds a session to a coarse fingerprint of the client
+// recorded when the first factor is completed.
+type SessionBindingConfiguration struct {
+	Mode     string `koanf:"mode"`
+	IPv4Mask int    `koanf:"ipv4_mask"`
+	IPv6Mask int    `koanf:"ipv6_mask"`
+}
+
+// SessionDefaultRedirectionURLConfiguration represents the default redirection URL used after authentication when the
+// portal is accessed on a specific domain.
+type SessionDefaultRedirectionURLConfiguration struct {
+	Domain string `koanf:"domain"`
+	URL    string `koanf:"url"`
+}
+
 // SessionConfiguration represents the configuration related to user sessions.
 type SessionConfiguration struct {
 	Name               string        `koanf:"name"`
 	Domain             string        `koanf:"domain"`
 	SameSite           string        `koanf:"same_site"`
+	Partitioned        bool          `koanf:"partitioned"`
 	Secret             string        `koanf:"secret"`
+	Provider           string        `koanf:"provider"`
 	Expiration         time.Duration `koanf:"expiration"`
 	Inactivity         time.Duration `koanf:"inactivity"`
+	InactivityWarning  time.Duration `koanf:"inactivity_warning"`
 	RememberMeDuration time.Duration `koanf:"remember_me_duration"`
 
+	MaximumAuthenticationAge time.Duration `koanf:"maximum_authentication_age"`
+
+	DisableLogoutOnPasswordChange bool `koanf:"disable_logout_on_password_change"`
+
+	RememberMe SessionRememberMeConfiguration `koanf:"remember_me"`
+
+	DefaultRedirectionURLs []SessionDefaultRedirectionURLConfiguration `koanf:"default_redirection_urls"`
+
+	Concurrency SessionConcurrencyConfiguration `koanf:"concurrency"`
+
+	Introspection SessionIntrospectionConfiguration `koanf:"introspection"`
+
+	Binding SessionBindingConfiguration `koanf:"binding"`
+
 	Redis *RedisSessionConfiguration `koanf:"redis"`
 }
 
@@ -51,6 +108,19 @@ var DefaultSessionConfiguration = SessionConfiguration{
 	Name:               "authelia_session",
 	Expiration:         time.Hour,
 	Inactivity:         time.Minute * 5,
+	InactivityWarning:  time.Minute,
 	RememberMeDuration: time.Hour * 24 * 30,
 	SameSite:           "lax",
+	Concurrency: SessionConcurrencyConfiguration{
+		Policy: SessionConcurrencyPolicyEvictOldest,
+	},
+	Introspection: SessionIntrospectionConfiguration{
+		RateLimit:       60,
+		RateLimitPeriod: time.Minute,
+	},
+	Binding: SessionBindingConfiguration{
+		Mode:     SessionBindingModeNone,
+		IPv4Mask: 24,
+		IPv6Mask: 64,
+	},
 }
@@ -48,6 +48,27 @@ type StorageConfiguration struct {
 	PostgreSQL *PostgreSQLStorageConfiguration `koanf:"postgres"`
 
 	EncryptionKey string `koanf:"encryption_key"`
+
+	AuthenticationLogsRetention time.Duration `koanf:"authentication_logs_retention,weak"`
+
+	Cleanup StorageCleanupConfiguration `koanf:"cleanup"`
+
+	ConnectionRetry StorageConnectionRetryConfiguration `koanf:"connection_retry"`
+}
+
+// StorageCleanupConfiguration represents the configuration of the periodic deletion of expired rows from the storage.
+type StorageCleanupConfiguration struct {
+	Disable   bool          `koanf:"disable"`
+	Interval  time.Duration `koanf:"interval,weak"`
+	BatchSize int           `koanf:"batch_size"`
+}
+
+// StorageConnectionRetryConfiguration represents the configuration of the retries of the connection to the storage
+// backend during startup.
+type StorageConnectionRetryConfiguration struct {
+	Interval      time.Duration `koanf:"interval,weak"`
+	BackoffFactor float64       `koanf:"backoff_factor"`
+	MaxDuration   time.Duration `koanf:"max_duration,weak"`
 }
 
 // DefaultSQLStorageConfiguration represents the default SQL configuration.
@@ -55,6 +76,19 @@ var DefaultSQLStorageConfiguration = SQLStorageConfiguration{
 	Timeout: 5 * time.Second,
 }
 
+// DefaultStorageCleanupConfiguration represents the default storage cleanup configuration.
+var DefaultStorageCleanupConfiguration = StorageCleanupConfiguration{
+	Interval:  time.Hour,
+	BatchSize: 1000,
+}
+
+// DefaultStorageConnectionRetryConfiguration represents the default storage connection retry configuration.
+var DefaultStorageConnectionRetryConfiguration = StorageConnectionRetryConfiguration{
+	Interval:      500 * time.Millisecond,
+	BackoffFactor: 1,
+	MaxDuration:   10 * time.Second,
+}
+
 // DefaultPostgreSQLStorageConfiguration represents the default PostgreSQL configuration.
 var DefaultPostgreSQLStorageConfiguration = PostgreSQLStorageConfiguration{
 	Schema: "public",
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"net/url"
 	"time"
 
 	"github.com/go-webauthn/webauthn/protocol"
@@ -11,6 +12,9 @@ type WebauthnConfiguration struct {
 	Disable     bool   `koanf:"disable"`
 	DisplayName string `koanf:"display_name"`
 
+	RPID           string    `koanf:"rp_id"`
+	AllowedOrigins []url.URL `koanf:"allowed_origins"`
+
 	ConveyancePreference protocol.ConveyancePreference        `koanf:"attestation_conveyance_preference"`
 	UserVerification     protocol.UserVerificationRequirement `koanf:"user_verification"`
 
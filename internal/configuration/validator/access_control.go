@@ -3,10 +3,15 @@ package validator
 import (
 	"fmt"
 	"net"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -61,6 +66,8 @@ func ValidateAccessControl(config *schema.Configuration, validator *schema.Struc
 		validator.Push(fmt.Errorf(errFmtAccessControlDefaultPolicyValue, strings.Join(validACLRulePolicies, "', '"), config.AccessControl.DefaultPolicy))
 	}
 
+	validateDenyResponse("access control: ", &config.AccessControl.DenyResponse, config.Session.Domain, validator)
+
 	if config.AccessControl.Networks != nil {
 		for _, n := range config.AccessControl.Networks {
 			for _, networks := range n.Networks {
@@ -103,12 +110,182 @@ func ValidateRules(config *schema.Configuration, validator *schema.StructValidat
 
 		validateMethods(rulePosition, rule, validator)
 
+		validateNetworkPolicies(rulePosition, rule, config.AccessControl, validator)
+
+		validateHeaders(rulePosition, rule, validator)
+
+		if rule.TimeWindow != nil {
+			validateTimeWindow(rulePosition, rule, validator)
+		}
+
+		if rule.MaximumAuthenticationAge < 0 {
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleMaximumAuthenticationAgeNegative, ruleDescriptor(rulePosition, rule), rule.MaximumAuthenticationAge))
+		}
+
+		if len(rule.SecondFactorMethods) != 0 {
+			validateSecondFactorMethods(rulePosition, rule, config, validator)
+		}
+
+		if rule.DenyResponse != nil {
+			validateDenyResponse(fmt.Sprintf("access control: rule %s: ", ruleDescriptor(rulePosition, rule)), rule.DenyResponse, config.Session.Domain, validator)
+		}
+
 		if rule.Policy == policyBypass {
 			validateBypass(rulePosition, rule, validator)
 		}
+
+		if rule.Identity != nil {
+			validateIdentity(rulePosition, rule, validator)
+		}
+
+		if rule.Shadow {
+			validateShadow(rulePosition, rule, config.AccessControl, validator)
+		}
 	}
 }
 
+// validateShadow validates the shadow mode of a rule. Only the deny policy, and the two_factor policy when it's
+// explicitly included, are shadowed so the rule must have one of these policies for the shadow mode to have any effect.
+func validateShadow(rulePosition int, rule schema.ACLRule, config schema.AccessControlConfiguration, validator *schema.StructValidator) {
+	policies := []string{rule.Policy}
+
+	for _, networkPolicy := range rule.NetworkPolicies {
+		policies = append(policies, networkPolicy.Policy)
+	}
+
+	for _, policy := range policies {
+		if policy == policyDeny || (policy == policyTwoFactor && config.ShadowTwoFactor) {
+			return
+		}
+	}
+
+	validator.Push(fmt.Errorf(errFmtAccessControlRuleShadowPolicy, ruleDescriptor(rulePosition, rule)))
+}
+
+// validateSecondFactorMethods validates the second factor methods required by a rule. The methods must be enabled and
+// the rule must require two factor authentication for at least some subjects for the methods to have any effect.
+func validateSecondFactorMethods(rulePosition int, rule schema.ACLRule, config *schema.Configuration, validator *schema.StructValidator) {
+	for _, method := range rule.SecondFactorMethods {
+		switch {
+		case !utils.IsStringInSlice(method, validACLRuleSecondFactorMethods):
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleSecondFactorMethodInvalid, ruleDescriptor(rulePosition, rule), method, strings.Join(validACLRuleSecondFactorMethods, "', '")))
+		case method == model.SecondFactorMethodTOTP && config.TOTP.Disable,
+			method == model.SecondFactorMethodWebauthn && config.Webauthn.Disable,
+			method == model.SecondFactorMethodDuo && config.DuoAPI == nil:
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleSecondFactorMethodDisabled, ruleDescriptor(rulePosition, rule), method))
+		}
+	}
+
+	if rule.Policy == policyTwoFactor {
+		return
+	}
+
+	for _, networkPolicy := range rule.NetworkPolicies {
+		if networkPolicy.Policy == policyTwoFactor {
+			return
+		}
+	}
+
+	validator.Push(fmt.Errorf(errFmtAccessControlRuleSecondFactorMethodsPolicy, ruleDescriptor(rulePosition, rule)))
+}
+
+// validateIdentity validates the synthetic identity of a rule. The identity is only forwarded for requests the rule
+// bypasses based on the network alone, so the rule must use the bypass policy and must be restricted to networks.
+func validateIdentity(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
+	if rule.Policy != policyBypass {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleIdentityPolicy, ruleDescriptor(rulePosition, rule), rule.Policy))
+	}
+
+	if len(rule.Networks) == 0 {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleIdentityNoNetworks, ruleDescriptor(rulePosition, rule)))
+	}
+
+	if len(rule.NetworkPolicies) != 0 {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleIdentityNetworkPolicies, ruleDescriptor(rulePosition, rule)))
+	}
+
+	if rule.Identity.Username == "" {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleIdentityNoUsername, ruleDescriptor(rulePosition, rule)))
+	}
+
+	for _, group := range rule.Identity.Groups {
+		if group == "" || strings.Contains(group, ",") {
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleIdentityGroupInvalid, ruleDescriptor(rulePosition, rule), group))
+		}
+	}
+}
+
+// validateDenyResponse validates the response sent to forbidden users. The status code must not allow the request
+// through the proxy and the redirect URL must be on the protected domain to avoid open redirects.
+func validateDenyResponse(prefix string, config *schema.ACLDenyResponse, domain string, validator *schema.StructValidator) {
+	validateDenyResponseHelp(prefix, config, validator)
+
+	if config.RedirectURL == "" {
+		switch {
+		case config.StatusCode == 0:
+			config.StatusCode = http.StatusForbidden
+		case config.StatusCode < 400 || config.StatusCode > 599:
+			validator.Push(fmt.Errorf(errFmtAccessControlDenyResponseStatusCode, prefix, config.StatusCode))
+		}
+
+		return
+	}
+
+	if config.StatusCode == 0 {
+		config.StatusCode = http.StatusFound
+	}
+
+	if !isIntegerInSlice(config.StatusCode, validACLDenyResponseRedirectStatusCodes) {
+		codes := make([]string, len(validACLDenyResponseRedirectStatusCodes))
+
+		for i, code := range validACLDenyResponseRedirectStatusCodes {
+			codes[i] = strconv.Itoa(code)
+		}
+
+		validator.Push(fmt.Errorf(errFmtAccessControlDenyResponseStatusCodeRedirect, prefix, strings.Join(codes, "', '"), config.StatusCode))
+	}
+
+	if safe, err := utils.IsRedirectionURISafe(config.RedirectURL, domain); err != nil || !safe {
+		validator.Push(fmt.Errorf(errFmtAccessControlDenyResponseRedirectURL, prefix, config.RedirectURL))
+	}
+
+	if config.Message != "" {
+		validator.Push(fmt.Errorf(errFmtAccessControlDenyResponseMessageWithRedirect, prefix))
+	}
+}
+
+// validateDenyResponseHelp validates the help shown on the error page. The help is only shown on the error page so it
+// can't be combined with the options which replace it.
+func validateDenyResponseHelp(prefix string, config *schema.ACLDenyResponse, validator *schema.StructValidator) {
+	if config.HelpURL != "" {
+		if u, err := url.Parse(config.HelpURL); err != nil || !u.IsAbs() || (u.Scheme != schemeHTTPS && u.Scheme != schemeMailTo) {
+			validator.Push(fmt.Errorf(errFmtAccessControlDenyResponseHelpURL, prefix, config.HelpURL))
+		}
+	}
+
+	if config.RedirectURL == "" && config.Message == "" {
+		return
+	}
+
+	if config.HelpMessage != "" {
+		validator.Push(fmt.Errorf(errFmtAccessControlDenyResponseHelpWithRedirectOrMessage, prefix, "help_message"))
+	}
+
+	if config.HelpURL != "" {
+		validator.Push(fmt.Errorf(errFmtAccessControlDenyResponseHelpWithRedirectOrMessage, prefix, "help_url"))
+	}
+}
+
+func isIntegerInSlice(needle int, haystack []int) bool {
+	for _, value := range haystack {
+		if value == needle {
+			return true
+		}
+	}
+
+	return false
+}
+
 func validateBypass(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
 	if len(rule.Subjects) != 0 {
 		validator.Push(fmt.Errorf(errAccessControlRuleBypassPolicyInvalidWithSubjects, ruleDescriptor(rulePosition, rule)))
@@ -132,6 +309,81 @@ func validateNetworks(rulePosition int, rule schema.ACLRule, config schema.Acces
 	}
 }
 
+func validateNetworkPolicies(rulePosition int, rule schema.ACLRule, config schema.AccessControlConfiguration, validator *schema.StructValidator) {
+	for i, networkPolicy := range rule.NetworkPolicies {
+		policyPosition := i + 1
+
+		if !IsPolicyValid(networkPolicy.Policy) {
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleNetworkPolicyInvalidPolicy, ruleDescriptor(rulePosition, rule), policyPosition, networkPolicy.Policy))
+		}
+
+		if networkPolicy.Policy == policyBypass && len(rule.Subjects) != 0 {
+			validator.Push(fmt.Errorf(errAccessControlRuleNetworkPolicyBypassInvalidWithSubjects, ruleDescriptor(rulePosition, rule), policyPosition))
+		}
+
+		if len(networkPolicy.Networks) == 0 {
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleNetworkPolicyNoNetworks, ruleDescriptor(rulePosition, rule), policyPosition))
+		}
+
+		for _, network := range networkPolicy.Networks {
+			if !IsNetworkValid(network) && !IsNetworkGroupValid(config, network) {
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleNetworkPolicyNetworksInvalid, ruleDescriptor(rulePosition, rule), policyPosition, network))
+			}
+		}
+	}
+}
+
+func validateHeaders(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
+	for i, header := range rule.Headers {
+		if header.Name == "" {
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleHeaderNoName, ruleDescriptor(rulePosition, rule), i+1))
+		}
+	}
+}
+
+// validateTimeWindow validates the time window of a rule. The timezone defaults to UTC and must be a valid IANA time
+// zone name so daylight saving time is handled by the time zone database.
+func validateTimeWindow(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
+	config := rule.TimeWindow
+
+	for _, day := range config.Days {
+		if !utils.IsStringInSliceFold(day, validACLTimeWindowDays) {
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleTimeWindowDayInvalid, ruleDescriptor(rulePosition, rule), day, strings.Join(validACLTimeWindowDays, "', '")))
+		}
+	}
+
+	validStart := validateTimeWindowTime(rulePosition, rule, "start", config.Start, validator)
+	validEnd := validateTimeWindowTime(rulePosition, rule, "end", config.End, validator)
+
+	if validStart && validEnd && config.Start == config.End {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleTimeWindowTimeEqual, ruleDescriptor(rulePosition, rule), config.Start))
+	}
+
+	if config.Timezone == "" {
+		config.Timezone = schema.DefaultACLTimeWindow.Timezone
+	}
+
+	if _, err := time.LoadLocation(config.Timezone); err != nil {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleTimeWindowTimezoneInvalid, ruleDescriptor(rulePosition, rule), config.Timezone, err))
+	}
+}
+
+func validateTimeWindowTime(rulePosition int, rule schema.ACLRule, option, value string, validator *schema.StructValidator) (valid bool) {
+	if value == "" {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleTimeWindowTimeRequired, ruleDescriptor(rulePosition, rule), option))
+
+		return false
+	}
+
+	if _, err := time.Parse(schema.ACLTimeWindowTimeLayout, value); err != nil {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleTimeWindowTimeInvalid, ruleDescriptor(rulePosition, rule), option, value))
+
+		return false
+	}
+
+	return true
+}
+
 func validateSubjects(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
 	for _, subjectRule := range rule.Subjects {
 		for _, subject := range subjectRule {
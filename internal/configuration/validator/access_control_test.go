@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -181,6 +182,334 @@ func (suite *AccessControl) TestShouldRaiseErrorInvalidMethod() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'methods' option 'HOP' is invalid: must be one of 'GET', 'HEAD', 'POST', 'PUT', 'PATCH', 'DELETE', 'TRACE', 'CONNECT', 'OPTIONS', 'COPY', 'LOCK', 'MKCOL', 'MOVE', 'PROPFIND', 'PROPPATCH', 'UNLOCK'")
 }
 
+func (suite *AccessControl) TestShouldRaiseErrorNegativeMaximumAuthenticationAge() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:                  []string{"public.example.com"},
+			Policy:                   "two_factor",
+			MaximumAuthenticationAge: -time.Minute,
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'maximum_authentication_age' option must be 0 or more but it is configured as '-1m0s'")
+}
+
+func (suite *AccessControl) TestShouldValidateSecondFactorMethods() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:             []string{"public.example.com"},
+			Policy:              "two_factor",
+			SecondFactorMethods: []string{"webauthn", "totp"},
+		},
+		{
+			Domains: []string{"internal.example.com"},
+			Policy:  "one_factor",
+			NetworkPolicies: []schema.ACLNetworkPolicy{
+				{Networks: []string{"10.0.0.0/8"}, Policy: "two_factor"},
+			},
+			SecondFactorMethods: []string{"webauthn"},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidSecondFactorMethods() {
+	suite.config.TOTP.Disable = true
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:             []string{"public.example.com"},
+			Policy:              "one_factor",
+			SecondFactorMethods: []string{"sms", "totp", "mobile_push"},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 4)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'second_factor_methods' option 'sms' is invalid: must be one of 'totp', 'webauthn', 'mobile_push'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #1 (domain 'public.example.com'): 'second_factor_methods' option 'totp' is invalid: the method is disabled")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access control: rule #1 (domain 'public.example.com'): 'second_factor_methods' option 'mobile_push' is invalid: the method is disabled")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "access control: rule #1 (domain 'public.example.com'): 'second_factor_methods' option can only be configured when the 'policy' option or the 'policy' option of a network policy is 'two_factor'")
+}
+
+func (suite *AccessControl) TestShouldSetDefaultDenyResponseStatusCode() {
+	ValidateAccessControl(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Errors(), 0)
+	suite.Assert().Equal(403, suite.config.AccessControl.DenyResponse.StatusCode)
+
+	suite.config.Session.Domain = "example.com"
+	suite.config.AccessControl.DenyResponse = schema.ACLDenyResponse{RedirectURL: "https://login.example.com/denied"}
+
+	ValidateAccessControl(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Errors(), 0)
+	suite.Assert().Equal(302, suite.config.AccessControl.DenyResponse.StatusCode)
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidDenyResponse() {
+	suite.config.Session.Domain = "example.com"
+	suite.config.AccessControl.DenyResponse = schema.ACLDenyResponse{StatusCode: 200}
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:      []string{"public.example.com"},
+			Policy:       "deny",
+			DenyResponse: &schema.ACLDenyResponse{StatusCode: 403, RedirectURL: "https://evil.com/denied", Message: "denied"},
+		},
+	}
+
+	ValidateAccessControl(suite.config, suite.validator)
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 4)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: deny_response: option 'status_code' must be between 400 and 599 but it is configured as '200'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #1 (domain 'public.example.com'): deny_response: option 'status_code' must be one of '301', '302', '303', '307', '308' when the option 'redirect_url' is configured but it is configured as '403'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access control: rule #1 (domain 'public.example.com'): deny_response: option 'redirect_url' must be a https URL on the 'session' option 'domain' but it is configured as 'https://evil.com/denied'")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "access control: rule #1 (domain 'public.example.com'): deny_response: option 'message' can't be configured when the option 'redirect_url' is configured")
+}
+
+func (suite *AccessControl) TestShouldValidateDenyResponseHelp() {
+	suite.config.AccessControl.DenyResponse = schema.ACLDenyResponse{HelpMessage: "Contact the help desk.", HelpURL: "mailto:help@example.com"}
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:      []string{"public.example.com"},
+			Policy:       "deny",
+			DenyResponse: &schema.ACLDenyResponse{HelpURL: "https://help.example.com/access"},
+		},
+	}
+
+	ValidateAccessControl(suite.config, suite.validator)
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidDenyResponseHelp() {
+	suite.config.AccessControl.DenyResponse = schema.ACLDenyResponse{HelpURL: "javascript:alert(1)"}
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:      []string{"public.example.com"},
+			Policy:       "deny",
+			DenyResponse: &schema.ACLDenyResponse{Message: "denied", HelpMessage: "Contact the help desk.", HelpURL: "/help"},
+		},
+	}
+
+	ValidateAccessControl(suite.config, suite.validator)
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 4)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: deny_response: option 'help_url' must be an absolute URL with the 'https' or 'mailto' scheme but it is configured as 'javascript:alert(1)'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #1 (domain 'public.example.com'): deny_response: option 'help_url' must be an absolute URL with the 'https' or 'mailto' scheme but it is configured as '/help'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access control: rule #1 (domain 'public.example.com'): deny_response: option 'help_message' can't be configured when the option 'redirect_url' or the option 'message' is configured")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "access control: rule #1 (domain 'public.example.com'): deny_response: option 'help_url' can't be configured when the option 'redirect_url' or the option 'message' is configured")
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidNetworkPolicies() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:  []string{"public.example.com"},
+			Policy:   "two_factor",
+			Subjects: [][]string{{"group:admins"}},
+			NetworkPolicies: []schema.ACLNetworkPolicy{
+				{Networks: []string{"internal"}, Policy: "one_factor"},
+				{Networks: []string{"abc.def.ghi.jkl"}, Policy: "invalid"},
+				{Policy: "bypass"},
+			},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 4)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): network_policies: policy #2: 'policy' option 'invalid' is invalid: must be one of 'deny', 'two_factor', 'one_factor' or 'bypass'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #1 (domain 'public.example.com'): network_policies: policy #2: the network 'abc.def.ghi.jkl' is not a valid Group Name, IP, or CIDR notation")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access control: rule #1 (domain 'public.example.com'): network_policies: policy #3: 'policy' option 'bypass' is not supported when 'subject' option is configured")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "access control: rule #1 (domain 'public.example.com'): network_policies: policy #3: option 'networks' is required")
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorHeaderWithoutName() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  "one_factor",
+			Headers: []schema.ACLHeader{
+				{Name: "X-VPN-Client", Value: regexp.MustCompile("^yes$")},
+				{Value: regexp.MustCompile("^yes$")},
+			},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): headers: header #2: option 'name' is required")
+}
+
+func (suite *AccessControl) TestShouldSetDefaultTimeWindowTimezone() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:    []string{"public.example.com"},
+			Policy:     "one_factor",
+			TimeWindow: &schema.ACLTimeWindow{Days: []string{"Monday", "friday"}, Start: "09:00", End: "17:00"},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal("UTC", suite.config.AccessControl.Rules[0].TimeWindow.Timezone)
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidTimeWindow() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:    []string{"public.example.com"},
+			Policy:     "one_factor",
+			TimeWindow: &schema.ACLTimeWindow{Days: []string{"mon"}, Start: "9am", Timezone: "Mars/Olympus_Mons"},
+		},
+		{
+			Domains:    []string{"private.example.com"},
+			Policy:     "one_factor",
+			TimeWindow: &schema.ACLTimeWindow{Start: "09:00", End: "09:00", Timezone: "Europe/Berlin"},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 5)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): time_window: option 'days' contains an invalid day 'mon': must be one of 'monday', 'tuesday', 'wednesday', 'thursday', 'friday', 'saturday', 'sunday'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #1 (domain 'public.example.com'): time_window: option 'start' must be a time in the 24-hour format 'HH:MM' but it is configured as '9am'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access control: rule #1 (domain 'public.example.com'): time_window: option 'end' is required")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "access control: rule #1 (domain 'public.example.com'): time_window: option 'timezone' with value 'Mars/Olympus_Mons' is invalid: unknown time zone Mars/Olympus_Mons")
+	suite.Assert().EqualError(suite.validator.Errors()[4], "access control: rule #2 (domain 'private.example.com'): time_window: options 'start' and 'end' must not be equal but both are configured as '09:00'")
+}
+
+func (suite *AccessControl) TestShouldValidateIdentity() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:  []string{"public.example.com"},
+			Policy:   "bypass",
+			Networks: []string{"10.0.0.0/8"},
+			Identity: &schema.ACLIdentity{Username: "monitoring", Groups: []string{"agents"}},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidIdentity() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:  []string{"public.example.com"},
+			Policy:   "one_factor",
+			Identity: &schema.ACLIdentity{Groups: []string{"agents,admins", ""}},
+			NetworkPolicies: []schema.ACLNetworkPolicy{
+				{Networks: []string{"10.0.0.0/8"}, Policy: "bypass"},
+			},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 6)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): identity: the 'policy' option must be 'bypass' when the 'identity' option is configured but it is configured as 'one_factor'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #1 (domain 'public.example.com'): identity: the 'networks' option is required when the 'identity' option is configured")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access control: rule #1 (domain 'public.example.com'): identity: the 'network_policies' option can't be configured when the 'identity' option is configured")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "access control: rule #1 (domain 'public.example.com'): identity: option 'username' is required")
+	suite.Assert().EqualError(suite.validator.Errors()[4], "access control: rule #1 (domain 'public.example.com'): identity: option 'groups' contains an invalid group 'agents,admins': groups must not be empty or contain a comma")
+	suite.Assert().EqualError(suite.validator.Errors()[5], "access control: rule #1 (domain 'public.example.com'): identity: option 'groups' contains an invalid group '': groups must not be empty or contain a comma")
+}
+
+func (suite *AccessControl) TestShouldValidateShadow() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  "deny",
+			Shadow:  true,
+		},
+		{
+			Domains: []string{"private.example.com"},
+			Policy:  "one_factor",
+			Shadow:  true,
+			NetworkPolicies: []schema.ACLNetworkPolicy{
+				{Networks: []string{"10.0.0.0/8"}, Policy: "deny"},
+			},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorShadowWithoutShadowedPolicy() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  "one_factor",
+			Shadow:  true,
+		},
+		{
+			Domains: []string{"private.example.com"},
+			Policy:  "two_factor",
+			Shadow:  true,
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'shadow' option can only be configured when the 'policy' option or the 'policy' option of a network policy is 'deny', or is 'two_factor' when the 'shadow_two_factor' option is true")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: rule #2 (domain 'private.example.com'): 'shadow' option can only be configured when the 'policy' option or the 'policy' option of a network policy is 'deny', or is 'two_factor' when the 'shadow_two_factor' option is true")
+
+	suite.SetupTest()
+
+	suite.config.AccessControl.ShadowTwoFactor = true
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains: []string{"private.example.com"},
+			Policy:  "two_factor",
+			Shadow:  true,
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Errors(), 0)
+}
+
 func (suite *AccessControl) TestShouldRaiseErrorInvalidSubject() {
 	domains := []string{"public.example.com"}
 	subjects := [][]string{{"invalid"}}
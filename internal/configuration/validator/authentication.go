@@ -3,7 +3,12 @@ package validator
 import (
 	"fmt"
 	"net/url"
+	"os"
+	"regexp"
 	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -34,6 +39,65 @@ func ValidateAuthenticationBackend(config *schema.AuthenticationBackendConfigura
 		}
 	}
 
+	validatePasswordResetAuthenticationBackend(config, validator)
+
+	if config.LDAP != nil && config.LDAP.PasswordExpiration.Enabled &&
+		config.LDAP.PasswordExpiration.Action == schema.LDAPPasswordExpirationActionReset && config.DisableResetPassword {
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendPasswordExpirationResetDisabled))
+	}
+
+	if config.ClientCertificate.Enabled {
+		validateClientCertificateAuthenticationBackend(&config.ClientCertificate, validator)
+	}
+
+	validateAPIKeyAuthenticationBackend(&config.APIKeys, validator)
+
+	if config.TrustedJWT.Enabled {
+		validateTrustedJWTAuthenticationBackend(&config.TrustedJWT, validator)
+	}
+
+	if config.TrustedHeader.Enabled {
+		validateTrustedHeaderAuthenticationBackend(&config.TrustedHeader, validator)
+	}
+
+	validateUsernameCanonicalization(&config.UsernameCanonicalization, validator)
+
+	if config.Cache.Enabled {
+		validateUserDetailsCache(&config.Cache, validator)
+	}
+}
+
+// validateUserDetailsCache validates and updates the user details cache configuration.
+func validateUserDetailsCache(config *schema.UserDetailsCacheConfiguration, validator *schema.StructValidator) {
+	switch {
+	case config.Lifespan == 0:
+		config.Lifespan = schema.DefaultUserDetailsCacheConfiguration.Lifespan
+	case config.Lifespan < 0:
+		validator.Push(fmt.Errorf(errFmtUserDetailsCacheLifespan, config.Lifespan))
+	}
+
+	switch {
+	case config.MaxEntries == 0:
+		config.MaxEntries = schema.DefaultUserDetailsCacheConfiguration.MaxEntries
+	case config.MaxEntries < 0:
+		validator.Push(fmt.Errorf(errFmtUserDetailsCacheMaxEntries, config.MaxEntries))
+	}
+}
+
+func validateUsernameCanonicalization(config *schema.UsernameCanonicalizationConfiguration, validator *schema.StructValidator) {
+	for _, domain := range config.StripDomainSuffixes {
+		if !govalidator.IsDNSName(domain) {
+			validator.Push(fmt.Errorf(errFmtUsernameCanonicalizationDomainSuffix, domain))
+		}
+	}
+
+	if config.Pattern == nil && config.Replacement != "" {
+		validator.Push(fmt.Errorf(errUsernameCanonicalizationReplacementWithoutPattern))
+	}
+}
+
+// validatePasswordResetAuthenticationBackend validates and updates the password reset configuration.
+func validatePasswordResetAuthenticationBackend(config *schema.AuthenticationBackendConfiguration, validator *schema.StructValidator) {
 	if config.PasswordReset.CustomURL.String() != "" {
 		switch config.PasswordReset.CustomURL.Scheme {
 		case schemeHTTP, schemeHTTPS:
@@ -42,6 +106,213 @@ func ValidateAuthenticationBackend(config *schema.AuthenticationBackendConfigura
 			validator.Push(fmt.Errorf(errFmtAuthBackendPasswordResetCustomURLScheme, config.PasswordReset.CustomURL.String(), config.PasswordReset.CustomURL.Scheme))
 		}
 	}
+
+	switch config.PasswordReset.Method {
+	case "":
+		config.PasswordReset.Method = schema.DefaultPasswordResetAuthenticationBackendConfiguration.Method
+	case schema.PasswordResetMethodEmail:
+		break
+	case schema.PasswordResetMethodAdminCode:
+		if config.PasswordReset.CustomURL.String() != "" {
+			validator.Push(fmt.Errorf(errFmtAuthBackendPasswordResetMethodCustomURL, config.PasswordReset.Method))
+		}
+	default:
+		validator.Push(fmt.Errorf(errFmtAuthBackendPasswordResetMethod, strings.Join(validPasswordResetMethods, "', '"), config.PasswordReset.Method))
+	}
+
+	switch {
+	case config.PasswordReset.CodeLifespan == 0:
+		config.PasswordReset.CodeLifespan = schema.DefaultPasswordResetAuthenticationBackendConfiguration.CodeLifespan
+	case config.PasswordReset.CodeLifespan < 0:
+		validator.Push(fmt.Errorf(errFmtAuthBackendPasswordResetCodeLifespan, config.PasswordReset.CodeLifespan))
+	}
+}
+
+// validateClientCertificateAuthenticationBackend validates and updates the client certificate authentication configuration.
+func validateClientCertificateAuthenticationBackend(config *schema.ClientCertificateAuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	if config.Header == "" {
+		config.Header = schema.DefaultClientCertificateAuthenticationBackendConfiguration.Header
+	}
+
+	if config.UsernameAttribute == "" {
+		config.UsernameAttribute = schema.DefaultClientCertificateAuthenticationBackendConfiguration.UsernameAttribute
+	} else if !utils.IsStringInSlice(config.UsernameAttribute, validClientCertificateUsernameAttributes) {
+		validator.Push(fmt.Errorf(errFmtClientCertificateAuthBackendUsernameAttribute, strings.Join(validClientCertificateUsernameAttributes, "', '"), config.UsernameAttribute))
+	}
+
+	if config.CertificateAuthority == "" {
+		validator.Push(fmt.Errorf(errFmtClientCertificateAuthBackendNoCA))
+	} else if _, err := os.ReadFile(config.CertificateAuthority); err != nil {
+		validator.Push(fmt.Errorf(errFmtClientCertificateAuthBackendCADoesNotExist, config.CertificateAuthority, err))
+	}
+
+	if len(config.Networks) == 0 {
+		validator.Push(fmt.Errorf(errFmtClientCertificateAuthBackendNoNetworks))
+	}
+
+	for _, network := range config.Networks {
+		if !IsNetworkValid(network) {
+			validator.Push(fmt.Errorf(errFmtClientCertificateAuthBackendNetworkInvalid, network))
+		}
+	}
+}
+
+// validateAPIKeyAuthenticationBackend validates and updates the API key authentication configuration.
+func validateAPIKeyAuthenticationBackend(config *schema.APIKeyAuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	if config.Header == "" {
+		config.Header = schema.DefaultAPIKeyAuthenticationBackendConfiguration.Header
+	}
+
+	names := make([]string, 0, len(config.Keys))
+
+	for i, key := range config.Keys {
+		if key.Name == "" {
+			validator.Push(fmt.Errorf(errFmtAPIKeyAuthBackendNoName))
+		} else if utils.IsStringInSlice(key.Name, names) {
+			validator.Push(fmt.Errorf(errFmtAPIKeyAuthBackendDuplicateName, key.Name))
+		} else {
+			names = append(names, key.Name)
+		}
+
+		if key.Username == "" {
+			validator.Push(fmt.Errorf(errFmtAPIKeyAuthBackendNoUsername, key.Name))
+		}
+
+		config.Keys[i].Digest = strings.ToLower(key.Digest)
+
+		if !regexpAPIKeyDigest.MatchString(config.Keys[i].Digest) {
+			validator.Push(fmt.Errorf(errFmtAPIKeyAuthBackendInvalidDigest, key.Name, key.Digest))
+		}
+
+		if len(key.Domains) == 0 {
+			validator.Push(fmt.Errorf(errFmtAPIKeyAuthBackendNoDomains, key.Name))
+		}
+
+		for j, domain := range key.Domains {
+			config.Keys[i].Domains[j] = strings.ToLower(domain)
+		}
+
+		for _, resource := range key.Resources {
+			if _, err := regexp.Compile(resource); err != nil {
+				validator.Push(fmt.Errorf(errFmtAPIKeyAuthBackendInvalidResource, key.Name, resource, err))
+			}
+		}
+	}
+}
+
+// validateJWKSCache validates and updates the trusted JWT JWKS cache configuration.
+func validateJWKSCache(config *schema.JWKSCacheConfiguration, validator *schema.StructValidator) {
+	defaults := schema.DefaultTrustedJWTAuthenticationBackendConfiguration.JWKSCache
+
+	switch {
+	case config.Lifespan == 0:
+		config.Lifespan = defaults.Lifespan
+	case config.Lifespan < time.Minute:
+		validator.Push(fmt.Errorf(errFmtTrustedJWTAuthBackendJWKSCacheLifespan, config.Lifespan))
+	}
+
+	switch {
+	case config.MaxStale == 0:
+		config.MaxStale = defaults.MaxStale
+	case config.MaxStale < 0:
+		validator.Push(fmt.Errorf(errFmtTrustedJWTAuthBackendJWKSCacheMaxStale, config.MaxStale))
+	}
+
+	switch {
+	case config.MaxSize == 0:
+		config.MaxSize = defaults.MaxSize
+	case config.MaxSize < 0:
+		validator.Push(fmt.Errorf(errFmtTrustedJWTAuthBackendJWKSCacheMaxSize, config.MaxSize))
+	}
+}
+
+// validateTrustedJWTAuthenticationBackend validates and updates the trusted JWT authentication configuration.
+func validateTrustedJWTAuthenticationBackend(config *schema.TrustedJWTAuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	if config.Header == "" {
+		config.Header = schema.DefaultTrustedJWTAuthenticationBackendConfiguration.Header
+	}
+
+	if config.Issuer == "" {
+		validator.Push(fmt.Errorf(errFmtTrustedJWTAuthBackendOptionRequired, "issuer"))
+	}
+
+	if config.Audience == "" {
+		validator.Push(fmt.Errorf(errFmtTrustedJWTAuthBackendOptionRequired, "audience"))
+	}
+
+	if config.JWKSURL == "" {
+		validator.Push(fmt.Errorf(errFmtTrustedJWTAuthBackendOptionRequired, "jwks_url"))
+	} else if u, err := url.Parse(config.JWKSURL); err != nil || u.Scheme != schemeHTTPS || u.Host == "" {
+		validator.Push(fmt.Errorf(errFmtTrustedJWTAuthBackendJWKSURL, config.JWKSURL))
+	}
+
+	validateJWKSCache(&config.JWKSCache, validator)
+
+	if len(config.Algorithms) == 0 {
+		config.Algorithms = schema.DefaultTrustedJWTAuthenticationBackendConfiguration.Algorithms
+	}
+
+	for _, alg := range config.Algorithms {
+		if !utils.IsStringInSlice(alg, validTrustedJWTAlgorithms) {
+			validator.Push(fmt.Errorf(errFmtTrustedJWTAuthBackendAlgorithm, strings.Join(validTrustedJWTAlgorithms, "', '"), alg))
+		}
+	}
+
+	switch {
+	case config.Leeway == 0:
+		config.Leeway = schema.DefaultTrustedJWTAuthenticationBackendConfiguration.Leeway
+	case config.Leeway < 0:
+		validator.Push(fmt.Errorf(errFmtTrustedJWTAuthBackendLeeway, config.Leeway))
+	}
+
+	if config.Claims.Username == "" {
+		config.Claims.Username = schema.DefaultTrustedJWTAuthenticationBackendConfiguration.Claims.Username
+	}
+
+	if config.Claims.DisplayName == "" {
+		config.Claims.DisplayName = schema.DefaultTrustedJWTAuthenticationBackendConfiguration.Claims.DisplayName
+	}
+
+	if config.Claims.Email == "" {
+		config.Claims.Email = schema.DefaultTrustedJWTAuthenticationBackendConfiguration.Claims.Email
+	}
+
+	if config.Claims.Groups == "" {
+		config.Claims.Groups = schema.DefaultTrustedJWTAuthenticationBackendConfiguration.Claims.Groups
+	}
+}
+
+// validateTrustedHeaderAuthenticationBackend validates and updates the trusted header authentication configuration.
+func validateTrustedHeaderAuthenticationBackend(config *schema.TrustedHeaderAuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	if config.Header == "" {
+		config.Header = schema.DefaultTrustedHeaderAuthenticationBackendConfiguration.Header
+	}
+
+	if config.GroupsHeader == "" {
+		config.GroupsHeader = schema.DefaultTrustedHeaderAuthenticationBackendConfiguration.GroupsHeader
+	}
+
+	if strings.EqualFold(config.Header, config.GroupsHeader) {
+		validator.Push(fmt.Errorf(errFmtTrustedHeaderAuthBackendSameHeader, config.Header))
+	}
+
+	if len(config.Networks) == 0 {
+		validator.Push(fmt.Errorf(errFmtTrustedHeaderAuthBackendOptionRequired, "networks"))
+	}
+
+	for _, network := range config.Networks {
+		if !IsNetworkValid(network) {
+			validator.Push(fmt.Errorf(errFmtTrustedHeaderAuthBackendNetworkInvalid, network))
+		}
+	}
+
+	if len(config.Domains) == 0 {
+		validator.Push(fmt.Errorf(errFmtTrustedHeaderAuthBackendOptionRequired, "domains"))
+	}
+
+	for i, domain := range config.Domains {
+		config.Domains[i] = strings.ToLower(domain)
+	}
 }
 
 // validateFileAuthenticationBackend validates and updates the file authentication backend configuration.
@@ -105,6 +376,8 @@ func validateFileAuthenticationBackendArgon2id(config *schema.FileAuthentication
 		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordArgon2idInvalidMemory, config.Password.Parallelism, config.Password.Parallelism*8, config.Password.Memory))
 	}
 
+	validateFileAuthenticationBackendArgon2idMemoryPercentage(config, validator)
+
 	// Key Length.
 	if config.Password.KeyLength == 0 {
 		config.Password.KeyLength = schema.DefaultPasswordConfiguration.KeyLength
@@ -113,6 +386,34 @@ func validateFileAuthenticationBackendArgon2id(config *schema.FileAuthentication
 	}
 }
 
+// validateFileAuthenticationBackendArgon2idMemoryPercentage validates the bounds of the memory parameter when it's
+// resolved from the available system memory. The maximum defaults to the memory option so the resolved memory never
+// exceeds what would be used without the percentage.
+func validateFileAuthenticationBackendArgon2idMemoryPercentage(config *schema.FileAuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	switch {
+	case config.Password.MemoryPercentage == 0:
+		return
+	case config.Password.MemoryPercentage < 0 || config.Password.MemoryPercentage > 100:
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordArgon2idInvalidMemoryPercentage, config.Password.MemoryPercentage))
+
+		return
+	}
+
+	if config.Password.MemoryMinimum == 0 {
+		config.Password.MemoryMinimum = config.Password.Parallelism * 8
+	} else if config.Password.MemoryMinimum < config.Password.Parallelism*8 {
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordArgon2idInvalidMemoryMinimum, config.Password.Parallelism, config.Password.Parallelism*8, config.Password.MemoryMinimum))
+	}
+
+	if config.Password.MemoryMaximum == 0 {
+		config.Password.MemoryMaximum = config.Password.Memory
+	}
+
+	if config.Password.MemoryMaximum < config.Password.MemoryMinimum {
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordArgon2idInvalidMemoryMaximum, config.Password.MemoryMinimum, config.Password.MemoryMaximum))
+	}
+}
+
 func validateLDAPAuthenticationBackend(config *schema.LDAPAuthenticationBackendConfiguration, validator *schema.StructValidator) {
 	if config.Timeout == 0 {
 		config.Timeout = schema.DefaultLDAPAuthenticationBackendConfiguration.Timeout
@@ -122,6 +423,15 @@ func validateLDAPAuthenticationBackend(config *schema.LDAPAuthenticationBackendC
 		config.Implementation = schema.DefaultLDAPAuthenticationBackendConfiguration.Implementation
 	}
 
+	switch {
+	case config.MaxConcurrency < 0:
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendMaxConcurrency, config.MaxConcurrency))
+	case config.QueueTimeout < 0:
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendQueueTimeout, config.QueueTimeout))
+	case config.QueueTimeout == 0:
+		config.QueueTimeout = schema.DefaultLDAPAuthenticationBackendConfiguration.QueueTimeout
+	}
+
 	if config.TLS == nil {
 		config.TLS = schema.DefaultLDAPAuthenticationBackendConfiguration.TLS
 	}
@@ -143,6 +453,10 @@ func validateLDAPAuthenticationBackend(config *schema.LDAPAuthenticationBackendC
 		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendImplementation, config.Implementation, strings.Join([]string{schema.LDAPImplementationCustom, schema.LDAPImplementationActiveDirectory}, "', '")))
 	}
 
+	validateLDAPSubjectAttribute(config, validator)
+
+	validateLDAPPasswordExpiration(config, validator)
+
 	if strings.Contains(config.UsersFilter, "{0}") {
 		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendFilterReplacedPlaceholders, "users_filter", "{0}", "{input}"))
 	}
@@ -164,6 +478,39 @@ func validateLDAPAuthenticationBackend(config *schema.LDAPAuthenticationBackendC
 	validateLDAPRequiredParameters(config, validator)
 }
 
+// validateLDAPSubjectAttribute ensures the subject attribute isn't one of the attributes which can be changed by or for
+// the user, as the subject must never change for the lifetime of the user.
+func validateLDAPSubjectAttribute(config *schema.LDAPAuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	if config.SubjectAttribute == "" {
+		return
+	}
+
+	switch {
+	case strings.EqualFold(config.SubjectAttribute, config.UsernameAttribute):
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendSubjectAttributeMutable, config.SubjectAttribute, "username_attribute"))
+	case strings.EqualFold(config.SubjectAttribute, config.MailAttribute):
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendSubjectAttributeMutable, config.SubjectAttribute, "mail_attribute"))
+	case strings.EqualFold(config.SubjectAttribute, config.DisplayNameAttribute):
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendSubjectAttributeMutable, config.SubjectAttribute, "display_name_attribute"))
+	}
+}
+
+// validateLDAPPasswordExpiration validates and updates the password expiration configuration.
+func validateLDAPPasswordExpiration(config *schema.LDAPAuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	if !config.PasswordExpiration.Enabled {
+		return
+	}
+
+	switch config.PasswordExpiration.Action {
+	case "":
+		config.PasswordExpiration.Action = schema.DefaultLDAPPasswordExpirationConfiguration.Action
+	case schema.LDAPPasswordExpirationActionDeny, schema.LDAPPasswordExpirationActionReset:
+		break
+	default:
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendPasswordExpirationAction, strings.Join(validLDAPPasswordExpirationActions, "', '"), config.PasswordExpiration.Action))
+	}
+}
+
 func validateLDAPAuthenticationBackendURL(config *schema.LDAPAuthenticationBackendConfiguration, validator *schema.StructValidator) {
 	var (
 		parsedURL *url.URL
@@ -229,7 +576,11 @@ func validateLDAPRequiredParameters(config *schema.LDAPAuthenticationBackendConf
 
 func setDefaultImplementationActiveDirectoryLDAPAuthenticationBackend(config *schema.LDAPAuthenticationBackendConfiguration) {
 	if config.UsersFilter == "" {
-		config.UsersFilter = schema.DefaultLDAPAuthenticationBackendImplementationActiveDirectoryConfiguration.UsersFilter
+		if config.PasswordExpiration.Enabled {
+			config.UsersFilter = schema.DefaultLDAPAuthenticationBackendImplementationActiveDirectoryPasswordExpirationUsersFilter
+		} else {
+			config.UsersFilter = schema.DefaultLDAPAuthenticationBackendImplementationActiveDirectoryConfiguration.UsersFilter
+		}
 	}
 
 	if config.UsernameAttribute == "" {
@@ -251,6 +602,22 @@ func setDefaultImplementationActiveDirectoryLDAPAuthenticationBackend(config *sc
 	if config.GroupNameAttribute == "" {
 		config.GroupNameAttribute = schema.DefaultLDAPAuthenticationBackendImplementationActiveDirectoryConfiguration.GroupNameAttribute
 	}
+
+	if !config.PasswordExpiration.Enabled {
+		return
+	}
+
+	if config.PasswordExpiration.AccountExpiresAttribute == "" {
+		config.PasswordExpiration.AccountExpiresAttribute = schema.DefaultLDAPAuthenticationBackendImplementationActiveDirectoryConfiguration.PasswordExpiration.AccountExpiresAttribute
+	}
+
+	if config.PasswordExpiration.PasswordExpiresAttribute == "" {
+		config.PasswordExpiration.PasswordExpiresAttribute = schema.DefaultLDAPAuthenticationBackendImplementationActiveDirectoryConfiguration.PasswordExpiration.PasswordExpiresAttribute
+	}
+
+	if config.PasswordExpiration.PasswordMustChangeAttribute == "" {
+		config.PasswordExpiration.PasswordMustChangeAttribute = schema.DefaultLDAPAuthenticationBackendImplementationActiveDirectoryConfiguration.PasswordExpiration.PasswordMustChangeAttribute
+	}
 }
 
 func setDefaultImplementationCustomLDAPAuthenticationBackend(config *schema.LDAPAuthenticationBackendConfiguration) {
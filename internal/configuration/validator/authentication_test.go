@@ -2,6 +2,7 @@ package validator
 
 import (
 	"net/url"
+	"regexp"
 	"testing"
 	"time"
 
@@ -37,6 +38,235 @@ func TestShouldRaiseErrorWhenNoBackendProvided(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], "authentication_backend: you must ensure either the 'file' or 'ldap' authentication backend is configured")
 }
 
+func TestShouldSetDefaultClientCertificateValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{Path: "/a/path"},
+		ClientCertificate: schema.ClientCertificateAuthenticationBackendConfiguration{
+			Enabled:              true,
+			CertificateAuthority: "./authentication.go",
+			Networks:             []string{"10.0.0.0/8"},
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "X-Forwarded-Tls-Client-Cert", backendConfig.ClientCertificate.Header)
+	assert.Equal(t, "common_name", backendConfig.ClientCertificate.UsernameAttribute)
+}
+
+func TestShouldRaiseErrorsOnInvalidClientCertificateValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{Path: "/a/path"},
+		ClientCertificate: schema.ClientCertificateAuthenticationBackendConfiguration{
+			Enabled:           true,
+			UsernameAttribute: "serial_number",
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: client_certificate: option 'username_attribute' must be one of 'common_name', 'email_address', 'dns_name', 'uri' but it is configured as 'serial_number'")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: client_certificate: option 'certificate_authority' is required when client certificate authentication is enabled")
+	assert.EqualError(t, validator.Errors()[2], "authentication_backend: client_certificate: option 'networks' is required when client certificate authentication is enabled")
+
+	validator = schema.NewStructValidator()
+	backendConfig.ClientCertificate.UsernameAttribute = "email_address"
+	backendConfig.ClientCertificate.CertificateAuthority = "/a/path/ca.pem"
+	backendConfig.ClientCertificate.Networks = []string{"10.0.0.0/8", "10.0.0.0/33"}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: client_certificate: option 'certificate_authority' with value '/a/path/ca.pem' could not be loaded: open /a/path/ca.pem: no such file or directory")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: client_certificate: option 'networks' must only contain valid IP addresses or CIDR notations but it contains '10.0.0.0/33'")
+}
+
+func TestShouldSetDefaultTrustedJWTValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{Path: "/a/path"},
+		TrustedJWT: schema.TrustedJWTAuthenticationBackendConfiguration{
+			Enabled:  true,
+			Issuer:   "https://idp.example.com",
+			Audience: "authelia",
+			JWKSURL:  "https://idp.example.com/jwks.json",
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "X-Forwarded-JWT", backendConfig.TrustedJWT.Header)
+	assert.Equal(t, []string{"RS256"}, backendConfig.TrustedJWT.Algorithms)
+	assert.Equal(t, time.Minute, backendConfig.TrustedJWT.Leeway)
+	assert.Equal(t, schema.TrustedJWTClaimsConfiguration{
+		Username:    "preferred_username",
+		DisplayName: "name",
+		Email:       "email",
+		Groups:      "groups",
+	}, backendConfig.TrustedJWT.Claims)
+	assert.Equal(t, schema.JWKSCacheConfiguration{
+		Lifespan: time.Hour,
+		MaxStale: time.Hour * 24,
+		MaxSize:  1024 * 1024,
+	}, backendConfig.TrustedJWT.JWKSCache)
+}
+
+func TestShouldRaiseErrorsOnInvalidTrustedJWTValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{Path: "/a/path"},
+		TrustedJWT: schema.TrustedJWTAuthenticationBackendConfiguration{
+			Enabled: true,
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: trusted_jwt: option 'issuer' is required when trusted JWT authentication is enabled")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: trusted_jwt: option 'audience' is required when trusted JWT authentication is enabled")
+	assert.EqualError(t, validator.Errors()[2], "authentication_backend: trusted_jwt: option 'jwks_url' is required when trusted JWT authentication is enabled")
+
+	validator = schema.NewStructValidator()
+	backendConfig.TrustedJWT = schema.TrustedJWTAuthenticationBackendConfiguration{
+		Enabled:    true,
+		Issuer:     "https://idp.example.com",
+		Audience:   "authelia",
+		JWKSURL:    "http://idp.example.com/jwks.json",
+		Algorithms: []string{"RS256", "HS256"},
+		Leeway:     -time.Second,
+		JWKSCache: schema.JWKSCacheConfiguration{
+			Lifespan: time.Second * 30,
+			MaxStale: -time.Hour,
+			MaxSize:  -1,
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 6)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: trusted_jwt: option 'jwks_url' must be a https URL but it is configured as 'http://idp.example.com/jwks.json'")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: trusted_jwt: jwks_cache: option 'lifespan' must be at least 1m but it is configured as '30s'")
+	assert.EqualError(t, validator.Errors()[2], "authentication_backend: trusted_jwt: jwks_cache: option 'max_stale' must be more than 0 but it is configured as '-1h0m0s'")
+	assert.EqualError(t, validator.Errors()[3], "authentication_backend: trusted_jwt: jwks_cache: option 'max_size' must be more than 0 but it is configured as '-1'")
+	assert.EqualError(t, validator.Errors()[4], "authentication_backend: trusted_jwt: option 'algorithms' must only contain the values 'RS256', 'RS384', 'RS512', 'PS256', 'PS384', 'PS512', 'ES256', 'ES384', 'ES512', 'EdDSA' but it contains 'HS256'")
+	assert.EqualError(t, validator.Errors()[5], "authentication_backend: trusted_jwt: option 'leeway' must be 0 or more but it is configured as '-1s'")
+}
+
+func TestShouldRaiseErrorsOnInvalidUsernameCanonicalizationValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{Path: "/a/path"},
+		UsernameCanonicalization: schema.UsernameCanonicalizationConfiguration{
+			StripDomainSuffixes: []string{"example.com", "@example.org"},
+			Replacement:         "$1",
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: username_canonicalization: option 'strip_domain_suffixes' must only contain domains but it contains '@example.org'")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: username_canonicalization: option 'replacement' can't be configured without the option 'pattern'")
+
+	validator = schema.NewStructValidator()
+	backendConfig.UsernameCanonicalization = schema.UsernameCanonicalizationConfiguration{
+		StripDomainSuffixes: []string{"example.com"},
+		Pattern:             regexp.MustCompile(`^EXAMPLE\\(.+)$`),
+		Replacement:         "$1",
+		Lowercase:           true,
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestShouldSetDefaultUserDetailsCacheValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File:  &schema.FileAuthenticationBackendConfiguration{Path: "/a/path"},
+		Cache: schema.UserDetailsCacheConfiguration{Enabled: true},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.UserDetailsCacheConfiguration{
+		Enabled:    true,
+		Lifespan:   time.Minute,
+		MaxEntries: 1000,
+	}, backendConfig.Cache)
+}
+
+func TestShouldRaiseErrorsOnInvalidUserDetailsCacheValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{Path: "/a/path"},
+		Cache: schema.UserDetailsCacheConfiguration{
+			Enabled:    true,
+			Lifespan:   -time.Minute,
+			MaxEntries: -1,
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: cache: option 'lifespan' must be more than 0 but it is configured as '-1m0s'")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: cache: option 'max_entries' must be more than 0 but it is configured as '-1'")
+}
+
+func TestShouldSetDefaultAPIKeyValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{Path: "/a/path"},
+		APIKeys: schema.APIKeyAuthenticationBackendConfiguration{
+			Keys: []schema.APIKeyConfiguration{
+				{Name: "backup", Username: "svc-backup", Digest: "6C4A9D1F2B3E5A7C8D0E1F2A3B4C5D6E7F8A9B0C1D2E3F4A5B6C7D8E9F0A1B2C", Domains: []string{"*.Example.com"}},
+			},
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "Authorization", backendConfig.APIKeys.Header)
+	assert.Equal(t, "6c4a9d1f2b3e5a7c8d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b2c", backendConfig.APIKeys.Keys[0].Digest)
+	assert.Equal(t, []string{"*.example.com"}, backendConfig.APIKeys.Keys[0].Domains)
+}
+
+func TestShouldRaiseErrorsOnInvalidAPIKeyValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{Path: "/a/path"},
+		APIKeys: schema.APIKeyAuthenticationBackendConfiguration{
+			Header: "X-API-Key",
+			Keys: []schema.APIKeyConfiguration{
+				{Username: "svc-backup", Digest: "6c4a9d1f2b3e5a7c8d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b2c", Domains: []string{"example.com"}},
+				{Name: "metrics", Username: "svc-metrics", Digest: "abc", Domains: []string{"example.com"}, Resources: []string{"^/metrics("}},
+				{Name: "metrics", Digest: "6c4a9d1f2b3e5a7c8d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5b6c7d8e9f0a1b2c"},
+			},
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 6)
+	assert.Equal(t, "X-API-Key", backendConfig.APIKeys.Header)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: api_keys: keys: option 'name' is required but it is not configured on one or more keys")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: api_keys: keys: key 'metrics': option 'digest' must be the hex encoded SHA-256 digest of the key but it is configured as 'abc'")
+	assert.EqualError(t, validator.Errors()[2], "authentication_backend: api_keys: keys: key 'metrics': option 'resources' must only contain valid regular expressions but it contains '^/metrics(' which is invalid: error parsing regexp: missing closing ): `^/metrics(`")
+	assert.EqualError(t, validator.Errors()[3], "authentication_backend: api_keys: keys: key 'metrics': option 'name' must be unique but it is configured on more than one key")
+	assert.EqualError(t, validator.Errors()[4], "authentication_backend: api_keys: keys: key 'metrics': option 'username' is required")
+	assert.EqualError(t, validator.Errors()[5], "authentication_backend: api_keys: keys: key 'metrics': option 'domains' is required")
+}
+
 type FileBasedAuthenticationBackend struct {
 	suite.Suite
 	config    schema.AuthenticationBackendConfiguration
@@ -86,6 +316,53 @@ func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenMemoryNotMo
 	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: option 'memory' must at least be parallelism multiplied by 8 when using algorithm 'argon2id' with parallelism 2 it should be at least 16 but it is configured as '8'")
 }
 
+func (suite *FileBasedAuthenticationBackend) TestShouldSetDefaultMemoryBoundsWhenMemoryPercentageSet() {
+	suite.config.File.Password.Memory = 1024
+	suite.config.File.Password.MemoryPercentage = 10
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(64, suite.config.File.Password.MemoryMinimum)
+	suite.Assert().Equal(1024, suite.config.File.Password.MemoryMaximum)
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldNotSetMemoryBoundsWhenMemoryPercentageNotSet() {
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(0, suite.config.File.Password.MemoryMinimum)
+	suite.Assert().Equal(0, suite.config.File.Password.MemoryMaximum)
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenMemoryPercentageInvalid() {
+	suite.config.File.Password.MemoryPercentage = 101
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: option 'memory_percentage' must be between 0 and 100 but it is configured as '101'")
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenMemoryBoundsInvalid() {
+	suite.config.File.Password.MemoryPercentage = 25
+	suite.config.File.Password.MemoryMinimum = 32
+	suite.config.File.Password.MemoryMaximum = 16
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: option 'memory_minimum' must at least be parallelism multiplied by 8 when using algorithm 'argon2id' with parallelism 8 it should be at least 64 but it is configured as '32'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "authentication_backend: file: password: option 'memory_maximum' must be more than or equal to the option 'memory_minimum' which is configured as '32' but it is configured as '16'")
+}
+
 func (suite *FileBasedAuthenticationBackend) TestShouldSetDefaultConfigurationWhenBlank() {
 	suite.config.File.Password = &schema.PasswordConfiguration{}
 
@@ -270,6 +547,41 @@ func (suite *FileBasedAuthenticationBackend) TestShouldConfigureDisableResetPass
 	suite.Assert().False(suite.config.DisableResetPassword)
 }
 
+func (suite *FileBasedAuthenticationBackend) TestShouldSetDefaultPasswordResetMethod() {
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.PasswordResetMethodEmail, suite.config.PasswordReset.Method)
+	suite.Assert().Equal(time.Hour, suite.config.PasswordReset.CodeLifespan)
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenPasswordResetMethodIsInvalid() {
+	suite.config.PasswordReset.Method = "sms"
+	suite.config.PasswordReset.CodeLifespan = -time.Minute
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: password_reset: option 'method' must be one of 'email', 'admin_code' but it is configured as 'sms'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "authentication_backend: password_reset: option 'code_lifespan' must be more than 0 but it is configured as '-1m0s'")
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenPasswordResetAdminCodeWithCustomURL() {
+	suite.config.PasswordReset.Method = schema.PasswordResetMethodAdminCode
+	suite.config.PasswordReset.CustomURL = url.URL{Scheme: "https", Host: "google.com"}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: password_reset: option 'method' is configured as 'admin_code' which can't be used with the option 'custom_url'")
+}
+
 func (suite *LDAPAuthenticationBackendSuite) TestShouldValidateDefaultImplementationAndUsernameAttribute() {
 	suite.config.LDAP.Implementation = ""
 	suite.config.LDAP.UsernameAttribute = ""
@@ -293,6 +605,99 @@ func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseErrorWhenImplementat
 	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: option 'implementation' is configured as 'masd' but must be one of the following values: 'custom', 'activedirectory'")
 }
 
+func (suite *LDAPAuthenticationBackendSuite) TestShouldSetDefaultQueueTimeout() {
+	suite.config.LDAP.MaxConcurrency = 10
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(10, suite.config.LDAP.MaxConcurrency)
+	suite.Assert().Equal(schema.DefaultLDAPAuthenticationBackendConfiguration.QueueTimeout, suite.config.LDAP.QueueTimeout)
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseErrorOnNegativeMaxConcurrency() {
+	suite.config.LDAP.MaxConcurrency = -1
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: option 'max_concurrency' must be 0 or more but it is configured as '-1'")
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseErrorOnNegativeQueueTimeout() {
+	suite.config.LDAP.QueueTimeout = -time.Second
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: option 'queue_timeout' must be more than 0 but it is configured as '-1s'")
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldAllowImmutableSubjectAttribute() {
+	suite.config.LDAP.SubjectAttribute = "entryUUID"
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseErrorWhenSubjectAttributeIsMutable() {
+	suite.config.LDAP.SubjectAttribute = "UID"
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: option 'subject_attribute' is configured as 'UID' which is the same attribute as the option 'username_attribute' but it must be an immutable attribute")
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldSetDefaultPasswordExpirationAction() {
+	suite.config.LDAP.PasswordExpiration.Enabled = true
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.LDAPPasswordExpirationActionDeny, suite.config.LDAP.PasswordExpiration.Action)
+	suite.Assert().Equal("", suite.config.LDAP.PasswordExpiration.AccountExpiresAttribute)
+	suite.Assert().Equal("", suite.config.LDAP.PasswordExpiration.PasswordExpiresAttribute)
+	suite.Assert().Equal("", suite.config.LDAP.PasswordExpiration.PasswordMustChangeAttribute)
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseErrorOnInvalidPasswordExpirationAction() {
+	suite.config.LDAP.PasswordExpiration.Enabled = true
+	suite.config.LDAP.PasswordExpiration.Action = "ignore"
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: password_expiration: option 'action' must be one of 'deny', 'reset' but it is configured as 'ignore'")
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseErrorOnPasswordExpirationResetWhenResetIsDisabled() {
+	suite.config.DisableResetPassword = true
+	suite.config.LDAP.PasswordExpiration.Enabled = true
+	suite.config.LDAP.PasswordExpiration.Action = schema.LDAPPasswordExpirationActionReset
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: password_expiration: option 'action' is configured as 'reset' but the password reset is disabled")
+}
+
 func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseErrorWhenURLNotProvided() {
 	suite.config.LDAP.URL = ""
 	ValidateAuthenticationBackend(&suite.config, suite.validator)
@@ -557,6 +962,24 @@ func (suite *ActiveDirectoryAuthenticationBackendSuite) TestShouldSetActiveDirec
 		suite.config.LDAP.GroupNameAttribute)
 }
 
+func (suite *ActiveDirectoryAuthenticationBackendSuite) TestShouldSetActiveDirectoryPasswordExpirationDefaults() {
+	suite.config.LDAP.PasswordExpiration.Enabled = true
+	suite.config.LDAP.PasswordExpiration.PasswordMustChangeAttribute = "pwdReset"
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(
+		schema.DefaultLDAPAuthenticationBackendImplementationActiveDirectoryPasswordExpirationUsersFilter,
+		suite.config.LDAP.UsersFilter)
+	suite.Assert().Equal(schema.LDAPPasswordExpirationActionDeny, suite.config.LDAP.PasswordExpiration.Action)
+	suite.Assert().Equal("accountExpires", suite.config.LDAP.PasswordExpiration.AccountExpiresAttribute)
+	suite.Assert().Equal("msDS-UserPasswordExpiryTimeComputed", suite.config.LDAP.PasswordExpiration.PasswordExpiresAttribute)
+	suite.Assert().Equal("pwdReset", suite.config.LDAP.PasswordExpiration.PasswordMustChangeAttribute)
+}
+
 func (suite *ActiveDirectoryAuthenticationBackendSuite) TestShouldOnlySetDefaultsIfNotManuallyConfigured() {
 	suite.config.LDAP.Timeout = time.Second * 2
 	suite.config.LDAP.UsersFilter = "(&({username_attribute}={input})(objectCategory=person)(objectClass=user)(!userAccountControl:1.2.840.113556.1.4.803:=2))"
@@ -612,3 +1035,53 @@ func (suite *ActiveDirectoryAuthenticationBackendSuite) TestShouldRaiseErrorOnIn
 func TestActiveDirectoryAuthenticationBackend(t *testing.T) {
 	suite.Run(t, new(ActiveDirectoryAuthenticationBackendSuite))
 }
+
+func TestShouldSetDefaultTrustedHeaderValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{Path: "/a/path"},
+		TrustedHeader: schema.TrustedHeaderAuthenticationBackendConfiguration{
+			Enabled:  true,
+			Networks: []string{"10.0.0.0/8"},
+			Domains:  []string{"*.Example.com"},
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "Remote-User", backendConfig.TrustedHeader.Header)
+	assert.Equal(t, "Remote-Groups", backendConfig.TrustedHeader.GroupsHeader)
+	assert.Equal(t, []string{"*.example.com"}, backendConfig.TrustedHeader.Domains)
+}
+
+func TestShouldRaiseErrorsOnInvalidTrustedHeaderValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{Path: "/a/path"},
+		TrustedHeader: schema.TrustedHeaderAuthenticationBackendConfiguration{
+			Enabled: true,
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: trusted_header: option 'networks' is required when trusted header authentication is enabled")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: trusted_header: option 'domains' is required when trusted header authentication is enabled")
+
+	validator = schema.NewStructValidator()
+	backendConfig.TrustedHeader = schema.TrustedHeaderAuthenticationBackendConfiguration{
+		Enabled:      true,
+		Header:       "X-User",
+		GroupsHeader: "x-user",
+		Networks:     []string{"10.0.0.0/8", "abc"},
+		Domains:      []string{"app.example.com"},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: trusted_header: options 'header' and 'groups_header' must not be the same header but both are configured as 'X-User'")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: trusted_header: option 'networks' must only contain valid IP addresses or CIDR notations but it contains 'abc'")
+}
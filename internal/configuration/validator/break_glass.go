@@ -0,0 +1,39 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// ValidateBreakGlass validates and updates the break-glass account configuration.
+func ValidateBreakGlass(config *schema.Configuration, validator *schema.StructValidator) {
+	breakGlass := &config.AuthenticationBackend.BreakGlass
+
+	if !breakGlass.Enabled {
+		return
+	}
+
+	if breakGlass.Username == "" {
+		validator.Push(fmt.Errorf(errFmtBreakGlassAuthBackendOptionRequired, "username"))
+	}
+
+	if breakGlass.Password == "" {
+		validator.Push(fmt.Errorf(errFmtBreakGlassAuthBackendOptionRequired, "password"))
+	} else if _, err := authentication.ParseHash(breakGlass.Password); err != nil {
+		validator.Push(fmt.Errorf(errFmtBreakGlassAuthBackendPasswordInvalid, err))
+	}
+
+	if breakGlass.Group == "" {
+		validator.Push(fmt.Errorf(errFmtBreakGlassAuthBackendOptionRequired, "group"))
+	}
+
+	if breakGlass.DisplayName == "" {
+		breakGlass.DisplayName = schema.DefaultBreakGlassAuthenticationBackendConfiguration.DisplayName
+	}
+
+	if config.TOTP.Disable && config.Webauthn.Disable && config.DuoAPI == nil {
+		validator.Push(fmt.Errorf(errBreakGlassAuthBackendNoSecondFactorMethods))
+	}
+}
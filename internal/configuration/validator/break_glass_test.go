@@ -0,0 +1,99 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+const testBreakGlassPasswordHash = "$6$rounds=50000$aFr56HjK3DrB8t3S$zhPQiS85cgBlNhUKKE6n/AHMlpqrvYSnSL3fEVkK0yHFQ.oFFAd8D4OhPAy18K5U61Z2eBhxQXExGU/eknXlY1"
+
+func TestShouldNotValidateBreakGlassWhenDisabled(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{}
+
+	ValidateBreakGlass(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "", config.AuthenticationBackend.BreakGlass.DisplayName)
+}
+
+func TestShouldSetBreakGlassDefaults(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		AuthenticationBackend: schema.AuthenticationBackendConfiguration{
+			BreakGlass: schema.BreakGlassAuthenticationBackendConfiguration{
+				Enabled:  true,
+				Username: "breakglass",
+				Password: testBreakGlassPasswordHash,
+				Group:    "admins",
+			},
+		},
+	}
+
+	ValidateBreakGlass(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultBreakGlassAuthenticationBackendConfiguration.DisplayName, config.AuthenticationBackend.BreakGlass.DisplayName)
+}
+
+func TestShouldRaiseErrorsWhenBreakGlassMissingOptions(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		AuthenticationBackend: schema.AuthenticationBackendConfiguration{
+			BreakGlass: schema.BreakGlassAuthenticationBackendConfiguration{
+				Enabled: true,
+			},
+		},
+	}
+
+	ValidateBreakGlass(config, validator)
+
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: break_glass: option 'username' is required when the break-glass account is enabled")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: break_glass: option 'password' is required when the break-glass account is enabled")
+	assert.EqualError(t, validator.Errors()[2], "authentication_backend: break_glass: option 'group' is required when the break-glass account is enabled")
+}
+
+func TestShouldRaiseErrorWhenBreakGlassPasswordIsNotHashed(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		AuthenticationBackend: schema.AuthenticationBackendConfiguration{
+			BreakGlass: schema.BreakGlassAuthenticationBackendConfiguration{
+				Enabled:  true,
+				Username: "breakglass",
+				Password: "password",
+				Group:    "admins",
+			},
+		},
+	}
+
+	ValidateBreakGlass(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: break_glass: option 'password' must be an argon2id or sha512 crypt hash of the password but it could not be parsed: Hash key is not the last parameter, the hash is likely malformed (password)")
+}
+
+func TestShouldRaiseErrorWhenBreakGlassHasNoSecondFactorMethods(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		TOTP:     schema.TOTPConfiguration{Disable: true},
+		Webauthn: schema.WebauthnConfiguration{Disable: true},
+		AuthenticationBackend: schema.AuthenticationBackendConfiguration{
+			BreakGlass: schema.BreakGlassAuthenticationBackendConfiguration{
+				Enabled:  true,
+				Username: "breakglass",
+				Password: testBreakGlassPasswordHash,
+				Group:    "admins",
+			},
+		},
+	}
+
+	ValidateBreakGlass(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: break_glass: option 'enabled' can't be enabled when totp and webauthn are disabled and duo_api is not configured as the account requires 2FA")
+}
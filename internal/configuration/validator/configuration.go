@@ -41,6 +41,8 @@ func ValidateConfiguration(config *schema.Configuration, validator *schema.Struc
 
 	ValidateWebauthn(config, validator)
 
+	ValidateDuo(config, validator)
+
 	ValidateAuthenticationBackend(&config.AuthenticationBackend, validator)
 
 	ValidateAccessControl(config, validator)
@@ -53,7 +55,9 @@ func ValidateConfiguration(config *schema.Configuration, validator *schema.Struc
 
 	ValidateServer(config, validator)
 
-	ValidateStorage(config.Storage, validator)
+	ValidateStorage(&config.Storage, validator)
+
+	validateSessionStorage(config, validator)
 
 	ValidateNotifier(config.Notifier, validator)
 
@@ -62,4 +66,20 @@ func ValidateConfiguration(config *schema.Configuration, validator *schema.Struc
 	ValidateNTP(config, validator)
 
 	ValidatePasswordPolicy(&config.PasswordPolicy, validator)
+
+	ValidateSelfRegistration(config, validator)
+
+	ValidateEvents(&config.Events, validator)
+
+	ValidateSelfTest(&config.SelfTest, validator)
+
+	ValidateLoginNotifications(&config.LoginNotifications, validator)
+
+	ValidateSecondFactorEnrollment(config, validator)
+
+	ValidateBreakGlass(config, validator)
+
+	ValidateEmailDomains(&config.EmailDomains, validator)
+
+	ValidateSensitiveActions(&config.SensitiveActions, validator)
 }
@@ -5,6 +5,8 @@ import (
 
 	"github.com/go-webauthn/webauthn/protocol"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
 )
 
@@ -29,10 +31,11 @@ const (
 
 // Scheme constants.
 const (
-	schemeLDAP  = "ldap"
-	schemeLDAPS = "ldaps"
-	schemeHTTP  = "http"
-	schemeHTTPS = "https"
+	schemeLDAP   = "ldap"
+	schemeLDAPS  = "ldaps"
+	schemeHTTP   = "http"
+	schemeHTTPS  = "https"
+	schemeMailTo = "mailto"
 )
 
 // Test constants.
@@ -51,12 +54,24 @@ const (
 const (
 	errFmtNotifierMultipleConfigured = "notifier: please ensure only one of the 'smtp' or 'filesystem' notifier is configured"
 	errFmtNotifierNotConfigured      = "notifier: you must ensure either the 'smtp' or 'filesystem' notifier " +
-		"is configured"
+		"or the 'notifiers' option is configured"
 	errFmtNotifierTemplatePathNotExist            = "notifier: option 'template_path' refers to location '%s' which does not exist"
 	errFmtNotifierTemplatePathUnknownError        = "notifier: option 'template_path' refers to location '%s' which couldn't be opened: %w"
 	errFmtNotifierTemplateLoad                    = "notifier: error loading template '%s': %w"
 	errFmtNotifierFileSystemFileNameNotConfigured = "notifier: filesystem: option 'filename' is required "
 	errFmtNotifierSMTPNotConfigured               = "notifier: smtp: option '%s' is required"
+
+	errFmtNotifierInstanceNameNotConfigured = "notifier: notifiers: option 'name' is required for notifier #%d"
+	errFmtNotifierInstanceNameDuplicate     = "notifier: notifiers: notifier '%s': option 'name' must be unique"
+	errFmtNotifierInstanceNotConfigured     = "notifier: notifiers: notifier '%s': you must ensure one of the " +
+		"'smtp', 'filesystem', or 'webhook' notifier is configured"
+	errFmtNotifierInstanceMultipleConfigured = "notifier: notifiers: notifier '%s': please ensure only one of the " +
+		"'smtp', 'filesystem', or 'webhook' notifier is configured"
+	errFmtNotifierInstanceNotConfiguredOption = "notifier: notifiers: notifier '%s': %s: option '%s' is required"
+	errFmtNotifierInstanceInvalidEvent        = "notifier: notifiers: notifier '%s': option 'events' must only " +
+		"contain values from '%s' but it has a value of '%s'"
+	errFmtNotifierWebhookInvalidURL = "notifier: notifiers: notifier '%s': webhook: option 'url' must be an " +
+		"absolute URL with the 'http' or 'https' scheme but it has a value of '%s'"
 )
 
 // Authentication Backend Error constants.
@@ -69,6 +84,75 @@ const (
 		"it must be either a duration notation or one of 'disable', or 'always': %w"
 	errFmtAuthBackendPasswordResetCustomURLScheme = "authentication_backend: password_reset: option 'custom_url' is" +
 		" configured to '%s' which has the scheme '%s' but the scheme must be either 'http' or 'https'"
+	errFmtAuthBackendPasswordResetMethod = "authentication_backend: password_reset: option 'method' must be one of " +
+		"'%s' but it is configured as '%s'"
+	errFmtAuthBackendPasswordResetMethodCustomURL = "authentication_backend: password_reset: option 'method' is " +
+		"configured as '%s' which can't be used with the option 'custom_url'"
+	errFmtAuthBackendPasswordResetCodeLifespan = "authentication_backend: password_reset: option 'code_lifespan' " +
+		"must be more than 0 but it is configured as '%s'"
+
+	errFmtClientCertificateAuthBackendNoCA = "authentication_backend: client_certificate: option " +
+		"'certificate_authority' is required when client certificate authentication is enabled"
+	errFmtClientCertificateAuthBackendCADoesNotExist = "authentication_backend: client_certificate: option " +
+		"'certificate_authority' with value '%s' could not be loaded: %v"
+	errFmtClientCertificateAuthBackendUsernameAttribute = "authentication_backend: client_certificate: option " +
+		"'username_attribute' must be one of '%s' but it is configured as '%s'"
+	errFmtClientCertificateAuthBackendNoNetworks = "authentication_backend: client_certificate: option " +
+		"'networks' is required when client certificate authentication is enabled"
+	errFmtClientCertificateAuthBackendNetworkInvalid = "authentication_backend: client_certificate: option " +
+		"'networks' must only contain valid IP addresses or CIDR notations but it contains '%s'"
+
+	errFmtAPIKeyAuthBackendNoName = "authentication_backend: api_keys: keys: option 'name' is required " +
+		"but it is not configured on one or more keys"
+	errFmtAPIKeyAuthBackendDuplicateName = "authentication_backend: api_keys: keys: key '%s': option 'name' " +
+		"must be unique but it is configured on more than one key"
+	errFmtAPIKeyAuthBackendNoUsername = "authentication_backend: api_keys: keys: key '%s': option 'username' " +
+		"is required"
+	errFmtAPIKeyAuthBackendInvalidDigest = "authentication_backend: api_keys: keys: key '%s': option 'digest' " +
+		"must be the hex encoded SHA-256 digest of the key but it is configured as '%s'"
+	errFmtAPIKeyAuthBackendNoDomains = "authentication_backend: api_keys: keys: key '%s': option 'domains' " +
+		"is required"
+	errFmtAPIKeyAuthBackendInvalidResource = "authentication_backend: api_keys: keys: key '%s': option 'resources' " +
+		"must only contain valid regular expressions but it contains '%s' which is invalid: %w"
+
+	errFmtTrustedJWTAuthBackendOptionRequired = "authentication_backend: trusted_jwt: option '%s' is required " +
+		"when trusted JWT authentication is enabled"
+	errFmtTrustedJWTAuthBackendJWKSURL = "authentication_backend: trusted_jwt: option 'jwks_url' must be a " +
+		"https URL but it is configured as '%s'"
+	errFmtTrustedJWTAuthBackendAlgorithm = "authentication_backend: trusted_jwt: option 'algorithms' must only " +
+		"contain the values '%s' but it contains '%s'"
+	errFmtTrustedJWTAuthBackendLeeway = "authentication_backend: trusted_jwt: option 'leeway' must be 0 or more " +
+		"but it is configured as '%s'"
+	errFmtTrustedJWTAuthBackendJWKSCacheLifespan = "authentication_backend: trusted_jwt: jwks_cache: option " +
+		"'lifespan' must be at least 1m but it is configured as '%s'"
+	errFmtTrustedJWTAuthBackendJWKSCacheMaxStale = "authentication_backend: trusted_jwt: jwks_cache: option " +
+		"'max_stale' must be more than 0 but it is configured as '%s'"
+	errFmtTrustedJWTAuthBackendJWKSCacheMaxSize = "authentication_backend: trusted_jwt: jwks_cache: option " +
+		"'max_size' must be more than 0 but it is configured as '%d'"
+
+	errFmtTrustedHeaderAuthBackendOptionRequired = "authentication_backend: trusted_header: option '%s' is required " +
+		"when trusted header authentication is enabled"
+	errFmtTrustedHeaderAuthBackendNetworkInvalid = "authentication_backend: trusted_header: option 'networks' must " +
+		"only contain valid IP addresses or CIDR notations but it contains '%s'"
+	errFmtTrustedHeaderAuthBackendSameHeader = "authentication_backend: trusted_header: options 'header' and " +
+		"'groups_header' must not be the same header but both are configured as '%s'"
+
+	errFmtBreakGlassAuthBackendOptionRequired = "authentication_backend: break_glass: option '%s' is required " +
+		"when the break-glass account is enabled"
+	errFmtBreakGlassAuthBackendPasswordInvalid = "authentication_backend: break_glass: option 'password' must be " +
+		"an argon2id or sha512 crypt hash of the password but it could not be parsed: %w"
+	errBreakGlassAuthBackendNoSecondFactorMethods = "authentication_backend: break_glass: option 'enabled' can't be " +
+		"enabled when totp and webauthn are disabled and duo_api is not configured as the account requires 2FA"
+
+	errFmtUsernameCanonicalizationDomainSuffix = "authentication_backend: username_canonicalization: option " +
+		"'strip_domain_suffixes' must only contain domains but it contains '%s'"
+	errUsernameCanonicalizationReplacementWithoutPattern = "authentication_backend: username_canonicalization: " +
+		"option 'replacement' can't be configured without the option 'pattern'"
+
+	errFmtUserDetailsCacheLifespan = "authentication_backend: cache: option 'lifespan' must be more than 0 but it " +
+		"is configured as '%s'"
+	errFmtUserDetailsCacheMaxEntries = "authentication_backend: cache: option 'max_entries' must be more than 0 but " +
+		"it is configured as '%d'"
 
 	errFmtFileAuthBackendPathNotConfigured  = "authentication_backend: file: option 'path' is required"
 	errFmtFileAuthBackendPasswordSaltLength = "authentication_backend: file: password: option 'salt_length' " +
@@ -84,8 +168,28 @@ const (
 	errFmtFileAuthBackendPasswordArgon2idInvalidMemory = "authentication_backend: file: password: option 'memory' " +
 		"must at least be parallelism multiplied by 8 when using algorithm 'argon2id' " +
 		"with parallelism %d it should be at least %d but it is configured as '%d'"
+	errFmtFileAuthBackendPasswordArgon2idInvalidMemoryPercentage = "authentication_backend: file: password: " +
+		"option 'memory_percentage' must be between 0 and 100 but it is configured as '%d'"
+	errFmtFileAuthBackendPasswordArgon2idInvalidMemoryMinimum = "authentication_backend: file: password: option " +
+		"'memory_minimum' must at least be parallelism multiplied by 8 when using algorithm 'argon2id' " +
+		"with parallelism %d it should be at least %d but it is configured as '%d'"
+	errFmtFileAuthBackendPasswordArgon2idInvalidMemoryMaximum = "authentication_backend: file: password: option " +
+		"'memory_maximum' must be more than or equal to the option 'memory_minimum' which is configured as '%d' " +
+		"but it is configured as '%d'"
 
 	errFmtLDAPAuthBackendMissingOption = "authentication_backend: ldap: option '%s' is required"
+
+	errFmtLDAPAuthBackendMaxConcurrency = "authentication_backend: ldap: option 'max_concurrency' must be 0 or " +
+		"more but it is configured as '%d'"
+	errFmtLDAPAuthBackendQueueTimeout = "authentication_backend: ldap: option 'queue_timeout' must be more " +
+		"than 0 but it is configured as '%s'"
+	errFmtLDAPAuthBackendSubjectAttributeMutable = "authentication_backend: ldap: option 'subject_attribute' " +
+		"is configured as '%s' which is the same attribute as the option '%s' but it must be an immutable attribute"
+	errFmtLDAPAuthBackendPasswordExpirationAction = "authentication_backend: ldap: password_expiration: option " +
+		"'action' must be one of '%s' but it is configured as '%s'"
+	errFmtLDAPAuthBackendPasswordExpirationResetDisabled = "authentication_backend: ldap: password_expiration: " +
+		"option 'action' is configured as 'reset' but the password reset is disabled"
+
 	errFmtLDAPAuthBackendTLSMinVersion = "authentication_backend: ldap: tls: option " +
 		"'minimum_tls_version' is invalid: %s: %w"
 	errFmtLDAPAuthBackendImplementation = "authentication_backend: ldap: option 'implementation' " +
@@ -108,31 +212,67 @@ const (
 	errFmtTOTPInvalidPeriod     = "totp: option 'period' option must be 15 or more but it is configured as '%d'"
 	errFmtTOTPInvalidDigits     = "totp: option 'digits' must be 6 or 8 but it is configured as '%d'"
 	errFmtTOTPInvalidSecretSize = "totp: option 'secret_size' must be %d or higher but it is configured as '%d'" //nolint:gosec
+	errFmtTOTPInvalidIssuer     = "totp: option 'issuer' must not contain the ':' character or non-printable " +
+		"characters but it is configured as '%s'"
+	errFmtTOTPInvalidAccountLabel = "totp: option 'account_label' must not contain the ':' character or " +
+		"non-printable characters outside of placeholders but it is configured as '%s'"
+	errFmtTOTPInvalidAccountLabelPlaceholder = "totp: option 'account_label' must only contain the placeholders " +
+		"'%s' but it contains the placeholder '%s'"
 )
 
 // Storage Error constants.
 const (
-	errStrStorage                            = "storage: configuration for a 'local', 'mysql' or 'postgres' database must be provided"
-	errStrStorageEncryptionKeyMustBeProvided = "storage: option 'encryption_key' must is required"
-	errStrStorageEncryptionKeyTooShort       = "storage: option 'encryption_key' must be 20 characters or longer"
-	errFmtStorageUserPassMustBeProvided      = "storage: %s: option 'username' and 'password' are required" //nolint:gosec
-	errFmtStorageOptionMustBeProvided        = "storage: %s: option '%s' is required"
-	errFmtStoragePostgreSQLInvalidSSLMode    = "storage: postgres: ssl: option 'mode' must be one of '%s' but it is configured as '%s'"
+	errStrStorage                             = "storage: configuration for a 'local', 'mysql' or 'postgres' database must be provided"
+	errStrStorageEncryptionKeyMustBeProvided  = "storage: option 'encryption_key' must is required"
+	errStrStorageEncryptionKeyTooShort        = "storage: option 'encryption_key' must be 20 characters or longer"
+	errFmtStorageLogsRetentionTooShort        = "storage: option 'authentication_logs_retention' must be 0 or at least the regulation option 'ban_time' of '%s' but it is configured as '%s'"
+	errFmtStorageCleanupNegative              = "storage: cleanup: option '%s' must be 0 or more but it is configured as '%v'"
+	errFmtStorageConnectionRetryNegative      = "storage: connection_retry: option '%s' must be 0 or more but it is configured as '%v'"
+	errFmtStorageConnectionRetryBackoffFactor = "storage: connection_retry: option 'backoff_factor' must be 1 or more but it is configured as '%v'"
+	errFmtStorageUserPassMustBeProvided       = "storage: %s: option 'username' and 'password' are required" //nolint:gosec
+	errFmtStorageOptionMustBeProvided         = "storage: %s: option '%s' is required"
+	errFmtStoragePostgreSQLInvalidSSLMode     = "storage: postgres: ssl: option 'mode' must be one of '%s' but it is configured as '%s'"
 )
 
 // OpenID Error constants.
 const (
 	errFmtOIDCNoClientsConfigured = "identity_providers: oidc: option 'clients' must have one or " +
 		"more clients configured"
-	errFmtOIDCNoPrivateKey            = "identity_providers: oidc: option 'issuer_private_key' is required"
-	errFmtOIDCEnforcePKCEInvalidValue = "identity_providers: oidc: option 'enforce_pkce' must be 'never', " +
+	errFmtOIDCNoPrivateKey = "identity_providers: oidc: option 'issuer_private_key' is required"
+
+	errFmtOIDCIssuerPrivateKeysNoKey               = "identity_providers: oidc: issuer_private_keys: key #%d: option 'key' is required"
+	errFmtOIDCIssuerPrivateKeysActivateExpires     = "identity_providers: oidc: issuer_private_keys: key #%d: option 'expires_at' can't be configured with option 'activate_at' as scheduled keys are retired after option 'key_rotation_grace_period'"
+	errFmtOIDCIssuerPrivateKeysDuplicateActivateAt = "identity_providers: oidc: issuer_private_keys: key #%d: option 'activate_at' must be unique but it's configured as '%s' on more than one key"
+	errFmtOIDCKeyRotationGracePeriodNegative       = "identity_providers: oidc: option 'key_rotation_grace_period' must be 0 or more but it is configured as '%s'"
+	errFmtOIDCClientsReloadIntervalNegative        = "identity_providers: oidc: option 'clients_reload_interval' must be 0 or more but it is configured as '%s'"
+	errFmtOIDCClockSkewOutOfRange                  = "identity_providers: oidc: option 'clock_skew' must be between 0 and %s but it is configured as '%s'"
+	errFmtOIDCEnforcePKCEInvalidValue              = "identity_providers: oidc: option 'enforce_pkce' must be 'never', " +
 		"'public_clients_only' or 'always', but it is configured as '%s'"
 
+	errFmtOIDCTokenEndpointNegative       = "identity_providers: oidc: token_endpoint: option '%s' must be more than 0 but it is configured as '%d'"
+	errFmtOIDCTokenEndpointPeriodNegative = "identity_providers: oidc: token_endpoint: option 'rate_limit_period' must be more than 0 but it is configured as '%s'"
+
+	errFmtOIDCAllowedInvalidEntry                 = "identity_providers: oidc: option '%s' must only have the values '%s' but one option is configured as '%s'"
+	errFmtOIDCAllowedResponseTypeRequiresImplicit = "identity_providers: oidc: option 'allowed_response_types' contains the value '%s' which requires the 'implicit' grant type but it's not included in option 'allowed_grant_types'"
+	errFmtOIDCClientTypeNotAllowed                = "identity_providers: oidc: client '%s': option '%s' contains the value '%s' which is not included in option '%s' and will be rejected"
+	errFmtOIDCClientResponseTypeRequiresGrantType = "identity_providers: oidc: client '%s': option 'response_types' contains the value '%s' which requires the '%s' grant type but it's not included in option 'grant_types'"
+
 	errFmtOIDCCORSInvalidOrigin                    = "identity_providers: oidc: cors: option 'allowed_origins' contains an invalid value '%s' as it has a %s: origins must only be scheme, hostname, and an optional port"
 	errFmtOIDCCORSInvalidOriginWildcard            = "identity_providers: oidc: cors: option 'allowed_origins' contains the wildcard origin '*' with more than one origin but the wildcard origin must be defined by itself"
 	errFmtOIDCCORSInvalidOriginWildcardWithClients = "identity_providers: oidc: cors: option 'allowed_origins' contains the wildcard origin '*' cannot be specified with option 'allowed_origins_from_client_redirect_uris' enabled"
 	errFmtOIDCCORSInvalidEndpoint                  = "identity_providers: oidc: cors: option 'endpoints' contains an invalid value '%s': must be one of '%s'"
 
+	errFmtOIDCCustomScopeEmptyName     = "identity_providers: oidc: custom_scopes: one or more custom scopes have been configured with an empty name"
+	errFmtOIDCCustomScopeDuplicateName = "identity_providers: oidc: custom_scopes: scope '%s': option 'name' must be unique but it's configured more than once"
+	errFmtOIDCCustomScopeStandardName  = "identity_providers: oidc: custom_scopes: scope '%s': option 'name' must not be the same as one of the standard scopes '%s'"
+	errFmtOIDCCustomScopeNoClaims      = "identity_providers: oidc: custom_scopes: scope '%s': option 'claims' must have one or more claims configured"
+	errFmtOIDCCustomScopeInvalidClaim  = "identity_providers: oidc: custom_scopes: scope '%s': option 'claims' must only have the values '%s' but one option is configured as '%s'"
+
+	errFmtOIDCACRValueEmptyValue      = "identity_providers: oidc: acr_values: one or more acr values have been configured with an empty value"
+	errFmtOIDCACRValueDuplicateValue  = "identity_providers: oidc: acr_values: value '%s': option 'value' must be unique but it's configured more than once"
+	errFmtOIDCACRValueInvalidPolicy   = "identity_providers: oidc: acr_values: value '%s': option 'policy' must be 'one_factor' or 'two_factor' but it is configured as '%s'"
+	errFmtOIDCUnknownACRValuesInvalid = "identity_providers: oidc: option 'unknown_acr_values' must be 'ignore' or 'error' but it is configured as '%s'"
+
 	errFmtOIDCClientsDuplicateID = "identity_providers: oidc: one or more clients have the same id but all client" +
 		"id's must be unique"
 	errFmtOIDCClientsWithEmptyID = "identity_providers: oidc: one or more clients have been configured with " +
@@ -141,7 +281,14 @@ const (
 	errFmtOIDCClientInvalidSecret       = "identity_providers: oidc: client '%s': option 'secret' is required"
 	errFmtOIDCClientPublicInvalidSecret = "identity_providers: oidc: client '%s': option 'secret' is " +
 		"required to be empty when option 'public' is true"
-	errFmtOIDCClientRedirectURI = "identity_providers: oidc: client '%s': option 'redirect_uris' has an " +
+	errFmtOIDCClientPublicInvalidPreviousSecret = "identity_providers: oidc: client '%s': option 'previous_secret' is " +
+		"required to be empty when option 'public' is true"
+	errFmtOIDCClientPreviousSecretExpiresAtRequired = "identity_providers: oidc: client '%s': option " +
+		"'previous_secret_expires_at' is required when option 'previous_secret' is configured"
+	errFmtOIDCClientPreviousSecretRequired = "identity_providers: oidc: client '%s': option 'previous_secret' is " +
+		"required when option 'previous_secret_expires_at' is configured"
+	errFmtOIDCClientSecretInvalidDigest = "identity_providers: oidc: client '%s': option '%s' is not a valid digest: %w"
+	errFmtOIDCClientRedirectURI         = "identity_providers: oidc: client '%s': option 'redirect_uris' has an " +
 		"invalid value: redirect uri '%s' must have a scheme of 'http' or 'https' but '%s' is configured"
 	errFmtOIDCClientRedirectURICantBeParsed = "identity_providers: oidc: client '%s': option 'redirect_uris' has an " +
 		"invalid value: redirect uri '%s' could not be parsed: %v"
@@ -150,18 +297,50 @@ const (
 		"for the openid connect confidential client type"
 	errFmtOIDCClientRedirectURIAbsolute = "identity_providers: oidc: client '%s': option 'redirect_uris' has an " +
 		"invalid value: redirect uri '%s' must have the scheme 'http' or 'https' but it has no scheme"
+	errFmtOIDCClientInvalidAllowedOrigin = "identity_providers: oidc: client '%s': option 'allowed_origins' contains " +
+		"an invalid value '%s' as %s: origins must only be scheme, hostname, and an optional port"
 	errFmtOIDCClientInvalidPolicy = "identity_providers: oidc: client '%s': option 'policy' must be 'one_factor' " +
 		"or 'two_factor' but it is configured as '%s'"
 	errFmtOIDCClientInvalidEntry = "identity_providers: oidc: client '%s': option '%s' must only have the values " +
 		"'%s' but one option is configured as '%s'"
 	errFmtOIDCClientInvalidUserinfoAlgorithm = "identity_providers: oidc: client '%s': option " +
 		"'userinfo_signing_algorithm' must be one of '%s' but it is configured as '%s'"
+	errFmtOIDCClientInvalidTokenEndpointAuthMethod = "identity_providers: oidc: client '%s': option " +
+		"'token_endpoint_auth_method' must be one of '%s' but it is configured as '%s'"
+	errFmtOIDCClientInvalidTokenEndpointAuthMethodPublic = "identity_providers: oidc: client '%s': option " +
+		"'token_endpoint_auth_method' must be 'none' when option 'public' is true but it is configured as '%s'"
+	errFmtOIDCClientInvalidTokenEndpointAuthMethodConfidential = "identity_providers: oidc: client '%s': option " +
+		"'token_endpoint_auth_method' must not be 'none' when option 'public' is false"
+	errFmtOIDCClientTokenEndpointAuthMethodSecretDigest = "identity_providers: oidc: client '%s': option " +
+		"'%s' must be a plaintext secret when option 'token_endpoint_auth_method' is 'client_secret_jwt' as it's used to verify the signature of the client assertion"
+	errFmtOIDCClientInvalidPKCEChallengeMethod = "identity_providers: oidc: client '%s': option " +
+		"'pkce_challenge_method' must be one of '%s' but it is configured as '%s'"
+	errFmtOIDCClientPKCEPlainChallengeNotEnabled = "identity_providers: oidc: client '%s': option " +
+		"'pkce_challenge_method' is configured as 'plain' but option 'enable_pkce_plain_challenge' is false"
 	errFmtOIDCClientInvalidSectorIdentifier = "identity_providers: oidc: client '%s': option " +
 		"'sector_identifier' with value '%s': must be a URL with only the host component for example '%s' but it has a %s with the value '%s'"
 	errFmtOIDCClientInvalidSectorIdentifierWithoutValue = "identity_providers: oidc: client '%s': option " +
 		"'sector_identifier' with value '%s': must be a URL with only the host component for example '%s' but it has a %s"
 	errFmtOIDCClientInvalidSectorIdentifierHost = "identity_providers: oidc: client '%s': option " +
 		"'sector_identifier' with value '%s': must be a URL with only the host component but appears to be invalid"
+	errFmtOIDCClientPreAuthorizedScopesNotFirstParty = "identity_providers: oidc: client '%s': option " +
+		"'pre_authorized_scopes' must only be configured when option 'first_party' is true"
+	errFmtOIDCClientPreAuthorizedScopesInvalid = "identity_providers: oidc: client '%s': option " +
+		"'pre_authorized_scopes' must only have the values of option 'scopes' '%s' but one option is configured as '%s'"
+	errFmtOIDCClientDefaultScopesInvalid = "identity_providers: oidc: client '%s': option " +
+		"'default_scopes' must only have the values of option 'scopes' '%s' but one option is configured as '%s'"
+	errFmtOIDCClientScopeAudiencesScopeInvalid = "identity_providers: oidc: client '%s': option " +
+		"'scope_audiences' must only have the values of option 'scopes' '%s' as a scope but one is configured as '%s'"
+	errFmtOIDCClientScopeAudiencesScopeDuplicate = "identity_providers: oidc: client '%s': option " +
+		"'scope_audiences' has the scope '%s' configured more than once"
+	errFmtOIDCClientScopeAudiencesNoAudience = "identity_providers: oidc: client '%s': option " +
+		"'scope_audiences' must have at least one audience for the scope '%s'"
+	errFmtOIDCClientScopeAudiencesAudienceInvalid = "identity_providers: oidc: client '%s': option " +
+		"'scope_audiences' must only have the values of option 'audience' '%s' as an audience but the scope '%s' has the audience '%s'"
+	errFmtOIDCClientIntrospectionAllowedClientInvalid = "identity_providers: oidc: client '%s': option " +
+		"'introspection' option 'allowed_clients' must only have the id of a configured client but one is configured as '%s'"
+	errFmtOIDCClientIntrospectionScopeInvalid = "identity_providers: oidc: client '%s': option " +
+		"'introspection' option 'scopes' must only have the values '%s' but one is configured as '%s'"
 	errFmtOIDCServerInsecureParameterEntropy = "openid connect provider: SECURITY ISSUE - minimum parameter entropy is " +
 		"configured to an unsafe value, it should be above 8 but it's configured to %d"
 )
@@ -170,6 +349,9 @@ const (
 const (
 	errFmtWebauthnConveyancePreference = "webauthn: option 'attestation_conveyance_preference' must be one of '%s' but it is configured as '%s'"
 	errFmtWebauthnUserVerification     = "webauthn: option 'user_verification' must be one of 'discouraged', 'preferred', 'required' but it is configured as '%s'"
+	errFmtWebauthnRPIDInvalid          = "webauthn: option 'rp_id' with value '%s' is invalid: it must only be a domain name without a scheme, port, or path"
+	errFmtWebauthnOriginInvalid        = "webauthn: option 'allowed_origins' contains an invalid value '%s': origins must only be a http or https scheme, hostname, and an optional port"
+	errFmtWebauthnOriginNotInRPID      = "webauthn: option 'allowed_origins' contains the value '%s' which is not permitted for the rp_id '%s': the rp_id must be the hostname of the origin or a registrable suffix of it"
 )
 
 // Access Control error constants.
@@ -198,11 +380,63 @@ const (
 		"invalid: must start with 'user:' or 'group:'"
 	errFmtAccessControlRuleMethodInvalid = "access control: rule %s: 'methods' option '%s' is " +
 		"invalid: must be one of '%s'"
+	errFmtAccessControlRuleMaximumAuthenticationAgeNegative = "access control: rule %s: 'maximum_authentication_age' " +
+		"option must be 0 or more but it is configured as '%s'"
+	errFmtAccessControlRuleSecondFactorMethodInvalid = "access control: rule %s: 'second_factor_methods' " +
+		"option '%s' is invalid: must be one of '%s'"
+	errFmtAccessControlRuleSecondFactorMethodDisabled = "access control: rule %s: 'second_factor_methods' " +
+		"option '%s' is invalid: the method is disabled"
+	errFmtAccessControlRuleSecondFactorMethodsPolicy = "access control: rule %s: 'second_factor_methods' " +
+		"option can only be configured when the 'policy' option or the 'policy' option of a network policy is 'two_factor'"
+	errFmtAccessControlRuleShadowPolicy = "access control: rule %s: 'shadow' option can only be configured when " +
+		"the 'policy' option or the 'policy' option of a network policy is 'deny', or is 'two_factor' when the " +
+		"'shadow_two_factor' option is true"
+	errFmtAccessControlRuleNetworkPolicyInvalidPolicy = "access control: rule %s: network_policies: policy #%d: " +
+		"'policy' option '%s' is invalid: must be one of 'deny', 'two_factor', 'one_factor' or 'bypass'"
+	errFmtAccessControlRuleNetworkPolicyNoNetworks = "access control: rule %s: network_policies: policy #%d: " +
+		"option 'networks' is required"
+	errFmtAccessControlRuleNetworkPolicyNetworksInvalid = "access control: rule %s: network_policies: policy #%d: " +
+		"the network '%s' is not a valid Group Name, IP, or CIDR notation"
+	errAccessControlRuleNetworkPolicyBypassInvalidWithSubjects = "access control: rule %s: network_policies: " +
+		"policy #%d: 'policy' option 'bypass' is not supported when 'subject' option is configured"
+	errFmtAccessControlRuleHeaderNoName         = "access control: rule %s: headers: header #%d: option 'name' is required"
+	errFmtAccessControlRuleTimeWindowDayInvalid = "access control: rule %s: time_window: option 'days' contains " +
+		"an invalid day '%s': must be one of '%s'"
+	errFmtAccessControlRuleTimeWindowTimeRequired = "access control: rule %s: time_window: option '%s' is required"
+	errFmtAccessControlRuleTimeWindowTimeInvalid  = "access control: rule %s: time_window: option '%s' must be a " +
+		"time in the 24-hour format 'HH:MM' but it is configured as '%s'"
+	errFmtAccessControlRuleTimeWindowTimeEqual = "access control: rule %s: time_window: options 'start' and 'end' " +
+		"must not be equal but both are configured as '%s'"
+	errFmtAccessControlRuleTimeWindowTimezoneInvalid = "access control: rule %s: time_window: option 'timezone' " +
+		"with value '%s' is invalid: %w"
+	errFmtAccessControlRuleIdentityPolicy = "access control: rule %s: identity: the 'policy' option must be " +
+		"'bypass' when the 'identity' option is configured but it is configured as '%s'"
+	errFmtAccessControlRuleIdentityNoNetworks = "access control: rule %s: identity: the 'networks' option is " +
+		"required when the 'identity' option is configured"
+	errFmtAccessControlRuleIdentityNetworkPolicies = "access control: rule %s: identity: the 'network_policies' " +
+		"option can't be configured when the 'identity' option is configured"
+	errFmtAccessControlRuleIdentityNoUsername   = "access control: rule %s: identity: option 'username' is required"
+	errFmtAccessControlRuleIdentityGroupInvalid = "access control: rule %s: identity: option 'groups' contains " +
+		"an invalid group '%s': groups must not be empty or contain a comma"
+
+	errFmtAccessControlDenyResponseStatusCode = "%sdeny_response: option 'status_code' must be between 400 and 599 " +
+		"but it is configured as '%d'"
+	errFmtAccessControlDenyResponseStatusCodeRedirect = "%sdeny_response: option 'status_code' must be one of '%s' " +
+		"when the option 'redirect_url' is configured but it is configured as '%d'"
+	errFmtAccessControlDenyResponseRedirectURL = "%sdeny_response: option 'redirect_url' must be a https URL on the " +
+		"'session' option 'domain' but it is configured as '%s'"
+	errFmtAccessControlDenyResponseMessageWithRedirect = "%sdeny_response: option 'message' can't be configured when " +
+		"the option 'redirect_url' is configured"
+	errFmtAccessControlDenyResponseHelpURL = "%sdeny_response: option 'help_url' must be an absolute URL with the " +
+		"'https' or 'mailto' scheme but it is configured as '%s'"
+	errFmtAccessControlDenyResponseHelpWithRedirectOrMessage = "%sdeny_response: option '%s' can't be configured when " +
+		"the option 'redirect_url' or the option 'message' is configured"
 )
 
 // Theme Error constants.
 const (
-	errFmtThemeName = "option 'theme' must be one of '%s' but it is configured as '%s'"
+	errFmtThemeName         = "option 'theme' must be one of '%s' but it is configured as '%s'"
+	errFmtThemeFallbackName = "option 'theme_fallback' must be one of '%s' but it is configured as '%s'"
 )
 
 // NTP Error constants.
@@ -212,13 +446,34 @@ const (
 
 // Session error constants.
 const (
-	errFmtSessionOptionRequired           = "session: option '%s' is required"
-	errFmtSessionDomainMustBeRoot         = "session: option 'domain' must be the domain you wish to protect not a wildcard domain but it is configured as '%s'"
-	errFmtSessionSameSite                 = "session: option 'same_site' must be one of '%s' but is configured as '%s'"
-	errFmtSessionSecretRequired           = "session: option 'secret' is required when using the '%s' provider"
-	errFmtSessionRedisPortRange           = "session: redis: option 'port' must be between 1 and 65535 but is configured as '%d'"
-	errFmtSessionRedisHostRequired        = "session: redis: option 'host' is required"
-	errFmtSessionRedisHostOrNodesRequired = "session: redis: option 'host' or the 'high_availability' option 'nodes' is required"
+	errFmtSessionOptionRequired                       = "session: option '%s' is required"
+	errFmtSessionDomainMustBeRoot                     = "session: option 'domain' must be the domain you wish to protect not a wildcard domain but it is configured as '%s'"
+	errFmtSessionSameSite                             = "session: option 'same_site' must be one of '%s' but is configured as '%s'"
+	errFmtSessionPartitionedSameSite                  = "session: option 'partitioned' must only be enabled when option 'same_site' is 'none' but it's configured as '%s'"
+	errFmtSessionSecretRequired                       = "session: option 'secret' is required when using the '%s' provider"
+	errFmtSessionProvider                             = "session: option 'provider' must be one of '%s' but it is configured as '%s'"
+	errFmtSessionProviderRedisNotConfigured           = "session: option 'provider' is configured as 'redis' but the 'redis' section is not configured"
+	errFmtSessionProviderRedisConfigured              = "session: option 'provider' is configured as '%s' but the 'redis' section is also configured"
+	errFmtSessionProviderStorage                      = "session: option 'provider' is configured as 'storage' which requires the 'postgres' or 'mysql' storage provider"
+	errFmtSessionMaximumAuthenticationAge             = "session: option 'maximum_authentication_age' must be 0 or more but it is configured as '%s'"
+	errFmtSessionInactivityWarning                    = "session: option 'inactivity_warning' must be less than the 'inactivity' option '%s' but it is configured as '%s'"
+	errFmtSessionDefaultRedirectionURLsOptionRequired = "session: default_redirection_urls: entry #%d: option '%s' is required"
+	errFmtSessionDefaultRedirectionURLsDomain         = "session: default_redirection_urls: entry #%d: option 'domain' must be the 'session' option 'domain' or a subdomain of it but it is configured as '%s'"
+	errFmtSessionDefaultRedirectionURLsDuplicate      = "session: default_redirection_urls: entry #%d: option 'domain' must be unique but '%s' is configured more than once"
+	errFmtSessionDefaultRedirectionURLsURLUnsafe      = "session: default_redirection_urls: entry #%d: option 'url' must be a https URL on the 'session' option 'domain' but it is configured as '%s'"
+	errFmtSessionRememberMeNegative                   = "session: remember_me: option '%s' must be 0 or more but it is configured as '%s'"
+	errFmtSessionRememberMeValidityConflict           = "session: remember_me: option 'validity' must be the same as the 'session' option 'remember_me_duration' '%s' when both are configured but it is configured as '%s'"
+	errFmtSessionConcurrencyLimitNegative             = "session: concurrency: option '%s' must be 0 or more but it is configured as '%d'"
+	errFmtSessionConcurrencyPolicy                    = "session: concurrency: option 'policy' must be one of '%s' but it is configured as '%s'"
+	errFmtSessionBindingMode                          = "session: binding: option 'mode' must be one of '%s' but it is configured as '%s'"
+	errFmtSessionBindingMask                          = "session: binding: option '%s' must be between 1 and %d but it is configured as '%d'"
+	errFmtSessionIntrospectionRestriction             = "session: introspection: option 'trusted_networks' or 'secret' must be configured when the endpoint is enabled"
+	errFmtSessionIntrospectionTrustedNetwork          = "session: introspection: option 'trusted_networks' must only contain valid IP addresses or CIDR notations but it contains '%s'"
+	errFmtSessionIntrospectionRateLimitNegative       = "session: introspection: option 'rate_limit' must be 0 or more but it is configured as '%d'"
+	errFmtSessionIntrospectionRateLimitPeriodNegative = "session: introspection: option 'rate_limit_period' must be 0 or more but it is configured as '%s'"
+	errFmtSessionRedisPortRange                       = "session: redis: option 'port' must be between 1 and 65535 but is configured as '%d'"
+	errFmtSessionRedisHostRequired                    = "session: redis: option 'host' is required"
+	errFmtSessionRedisHostOrNodesRequired             = "session: redis: option 'host' or the 'high_availability' option 'nodes' is required"
 
 	errFmtSessionRedisSentinelMissingName     = "session: redis: high_availability: option 'sentinel_name' is required"
 	errFmtSessionRedisSentinelNodeHostMissing = "session: redis: high_availability: option 'nodes': option 'host' is required for each node but one or more nodes are missing this"
@@ -227,6 +482,87 @@ const (
 // Regulation Error Consts.
 const (
 	errFmtRegulationFindTimeGreaterThanBanTime = "regulation: option 'find_time' must be less than or equal to option 'ban_time'"
+	errFmtRegulationImpossibleTravelNegative   = "regulation: impossible_travel: option '%s' must be 0 or more but it is configured as '%d'"
+	errRegulationImpossibleTravelNoNetworks    = "regulation: impossible_travel: option 'networks' is required when " +
+		"impossible travel detection is enabled"
+	errFmtRegulationImpossibleTravelNetworkInvalid = "regulation: impossible_travel: option 'networks' must only " +
+		"contain valid IP addresses or CIDR notations but it contains '%s'"
+
+	errFmtRegulationCAPTCHAProvider = "regulation: captcha: option 'provider' must be one of '%s' but it is " +
+		"configured as '%s'"
+	errFmtRegulationCAPTCHAFailureMode = "regulation: captcha: option 'failure_mode' must be one of '%s' but it is " +
+		"configured as '%s'"
+	errFmtRegulationUnlockNegative = "regulation: unlock: option '%s' must be more than 0 but it is configured as '%v'"
+
+	errFmtRegulationSecondFactorMaxAttempts = "regulation: second_factor: option 'max_attempts' must be 0 or more but it is configured as '%d'"
+	errFmtRegulationSecondFactorAction      = "regulation: second_factor: option 'action' must be one of '%s' but it is " +
+		"configured as '%s'"
+	errFmtRegulationSecondFactorBanDisabled = "regulation: second_factor: option 'action' can't be configured as " +
+		"'ban' when the regulation is disabled by configuring the 'max_retries' option as 0"
+
+	errFmtRegulationCAPTCHARequired      = "regulation: captcha: option '%s' is required when the provider is configured"
+	errFmtRegulationCAPTCHAThreshold     = "regulation: captcha: option 'threshold' must be 0 or more but it is configured as '%d'"
+	errFmtRegulationCAPTCHAThresholdBans = "regulation: captcha: option 'threshold' is configured as '%d' which is " +
+		"not less than option 'max_retries' so users will be banned before they are asked to complete a CAPTCHA challenge"
+)
+
+// Self-Registration Error constants.
+const (
+	errSelfRegistrationRequiresFileBackend = "self_registration: option 'enabled' requires the 'file' authentication " +
+		"backend as accounts can't be created in the 'ldap' authentication backend"
+	errFmtSelfRegistrationAdminEmailInvalid = "self_registration: option 'admin_emails' must only contain valid " +
+		"email addresses but it contains '%s'"
+	errFmtSelfRegistrationRateLimitNegative = "self_registration: option 'rate_limit' must be 0 or more but it is " +
+		"configured as '%d'"
+	errFmtSelfRegistrationRateLimitPeriodNegative = "self_registration: option 'rate_limit_period' must be 0 or more " +
+		"but it is configured as '%s'"
+	errFmtSelfRegistrationEmailVerificationLifespan = "self_registration: email_verification: option 'lifespan' " +
+		"must be more than 0 but it is configured as '%s'"
+)
+
+// Events Error constants.
+const (
+	errFmtEventsBufferSize        = "events: option 'buffer_size' must be more than 0 but it is configured as '%d'"
+	errFmtEventsNATSNotConfigured = "events: nats: option '%s' is required"
+	errFmtEventsNATSSubjectPrefix = "events: nats: option 'subject_prefix' must only contain alphanumeric characters, " +
+		"hyphens, underscores, and periods but it is configured as '%s'"
+	errFmtEventsNATSAuthentication = "events: nats: option 'token' must not be configured at the same time as the " +
+		"'username' and 'password' options"
+)
+
+// Self-test Error constants.
+const (
+	errFmtSelfTestFailureMode = "self_test: option 'failure_mode' must be one of '%s' but it is configured as '%s'"
+)
+
+// Login Notifications Error constants.
+const (
+	errFmtLoginNotificationsNegative = "login_notifications: option '%s' must be more than 0 but it is configured as '%s'"
+)
+
+// Second Factor Enrollment Error constants.
+const (
+	errFmtSecondFactorEnrollmentGracePeriodNegative = "second_factor_enrollment: option 'grace_period' must be more than 0 but it is configured as '%s'"
+
+	errSecondFactorEnrollmentNoMethods = "second_factor_enrollment: option 'enforce' can't be enabled when totp and webauthn are disabled and duo_api is not configured"
+)
+
+// Email Domains Error constants.
+const (
+	errFmtEmailDomainsInvalidPattern = "email_domains: option '%s' must only contain domains or domains prefixed " +
+		"with '*.' but it contains '%s'"
+)
+
+// Sensitive Actions Error constants.
+const (
+	errFmtSensitiveActionsWindowNegative = "sensitive_actions: option 'window' must be more than 0 but it is configured as '%s'"
+	errFmtSensitiveActionsInvalidAction  = "sensitive_actions: option 'actions' must only contain values from '%s' but it contains '%s'"
+)
+
+// Duo Error constants.
+const (
+	errFmtDuoTimeoutNegative = "duo_api: timeouts: option '%s' must be more than 0 but it is configured as '%s'"
+	errFmtDuoFailureMode     = "duo_api: option 'failure_mode' must be one of '%s' but it is configured as '%s'"
 )
 
 // Server Error constants.
@@ -237,15 +573,40 @@ const (
 	errFmtServerTLSKeyFileDoesNotExist            = "server: tls: file path %s provided in 'key' does not exist"
 	errFmtServerTLSClientAuthCertFileDoesNotExist = "server: tls: client_certificates: certificates: file path %s does not exist"
 	errFmtServerTLSClientAuthNoAuth               = "server: tls: client authentication cannot be configured if no server certificate and key are provided"
+	errFmtServerTLSVersion                        = "server: tls: option '%s_version' with value '%s' is invalid: %w"
+	errFmtServerTLSVersionMinimumGreaterThanMax   = "server: tls: option 'minimum_version' with value '%s' must not be greater than option 'maximum_version' with value '%s'"
+	errFmtServerTLSCipherSuite                    = "server: tls: option 'cipher_suites' contains an invalid value '%s': %w"
+	errFmtServerTLSCipherSuiteInsecure            = "server: tls: option 'cipher_suites' contains the cipher suite '%s' which has known security issues"
+	errFmtServerTLSCipherSuiteTLS13               = "server: tls: option 'cipher_suites' has no effect when option 'minimum_version' is 'TLS1.3' as the TLS 1.3 cipher suites are not configurable"
 
 	errFmtServerPathNoForwardSlashes = "server: option 'path' must not contain any forward slashes"
 	errFmtServerPathAlphaNum         = "server: option 'path' must only contain alpha numeric characters"
 	errFmtServerBufferSize           = "server: option '%s_buffer_size' must be above 0 but it is configured as '%d'"
+	errFmtServerTrustedProxyInvalid  = "server: option 'trusted_proxies' must only contain valid IP addresses or CIDR notations but it contains '%s'"
+
+	errFmtServerSecurityTXTMissingField = "server: option 'security_txt' should contain the '%s' field which is required by RFC9116"
+
+	errFmtServerTimeout             = "server: option '%s_timeout' must be at least 1s but it is configured as '%s'"
+	errFmtServerConnectionLimit     = "server: option '%s' must be 0 or more but it is configured as '%d'"
+	errFmtServerMaxConnectionsPerIP = "server: option 'max_connections_per_ip' must not be greater than option 'max_connections' but it is configured as '%d' and 'max_connections' is configured as '%d'"
+
+	errFmtServerProxyProtocolNoTrustedUpstreams = "server: proxy_protocol: option 'trusted_upstreams' must be configured when the PROXY protocol is enabled"
+	errFmtServerProxyProtocolUpstreamInvalid    = "server: proxy_protocol: option 'trusted_upstreams' must only contain valid IP addresses or CIDR notations but it contains '%s'"
+	errFmtServerProxyProtocolHeaderTimeout      = "server: proxy_protocol: option 'header_timeout' must be above 0 but it is configured as '%s'"
+
+	errFmtServerHeadersFrameOptions = "server: headers: option 'frame_options' must be one of 'DENY', 'SAMEORIGIN', or 'none' but it is configured as '%s'"
 )
 
 const (
 	errFmtPasswordPolicyMinLengthNotGreaterThanZero = "password_policy: standard: option 'min_length' must be greater than 0 but is configured as %d"
 	errPasswordPolicyMultipleDefined                = "password_policy: only a single password policy mechanism can be specified"
+	errFmtPasswordPolicyMinimumAgeNegative          = "password_policy: option 'minimum_age' must be 0 or more but is configured as '%s'"
+
+	errFmtPasswordPolicyPoliciesNameEmpty       = "password_policy: policies: policy #%d: option 'name' must be configured"
+	errFmtPasswordPolicyPoliciesNameDuplicate   = "password_policy: policies: policy '%s': option 'name' must be unique"
+	errFmtPasswordPolicyPoliciesGroupsEmpty     = "password_policy: policies: policy '%s': option 'groups' must have at least one group"
+	errFmtPasswordPolicyPoliciesMultipleDefined = "password_policy: policies: policy '%s': only a single password policy mechanism can be specified"
+	errFmtPasswordPolicyPoliciesMinLength       = "password_policy: policies: policy '%s': standard: option 'min_length' must be greater than 0 but is configured as %d"
 )
 
 // Error constants.
@@ -262,7 +623,15 @@ const (
 
 	errFmtReplacedConfigurationKey = "invalid configuration key '%s' was replaced by '%s'"
 
-	errFmtLoggingLevelInvalid = "log: option 'level' must be one of '%s' but it is configured as '%s'"
+	errFmtLoggingLevelInvalid                 = "log: option 'level' must be one of '%s' but it is configured as '%s'"
+	errFmtLoggingSlowRequestThresholdNegative = "log: option 'slow_request_threshold' must be 0 or more but it is configured as '%s'"
+	errFmtLoggingRedactionMethodInvalid       = "log: redaction: option 'method' must be one of '%s' but it is configured as '%s'"
+	errFmtLoggingRedactionFieldInvalid        = "log: redaction: option 'fields' must only have the values '%s' but one option is configured as '%s'"
+	errLoggingRedactionSecretRequired         = "log: redaction: option 'secret' is required when the method is 'hash'"
+	errFmtLoggingSyslogNetworkInvalid         = "log: syslog: option 'network' must be one of '%s' but it is configured as '%s'"
+	errFmtLoggingSyslogAddressRequired        = "log: syslog: option 'address' is required when the network is '%s'"
+	errFmtLoggingSyslogFacilityInvalid        = "log: syslog: option 'facility' must be one of '%s' but it is configured as '%s'"
+	errFmtLoggingSyslogTLSMinVersion          = "log: syslog: tls: option 'minimum_version' is invalid: %s: %w"
 
 	errFileHashing  = "config key incorrect: authentication_backend.file.hashing should be authentication_backend.file.password"
 	errFilePHashing = "config key incorrect: authentication_backend.file.password_hashing should be authentication_backend.file.password"
@@ -273,10 +642,39 @@ var validStoragePostgreSQLSSLModes = []string{testModeDisabled, "require", "veri
 
 var validThemeNames = []string{"light", "dark", "grey", "auto"}
 
+var validThemeFallbackNames = []string{"light", "dark", "grey"}
+
 var validSessionSameSiteValues = []string{"none", "lax", "strict"}
 
+var validACLDenyResponseRedirectStatusCodes = []int{301, 302, 303, 307, 308}
+
+var validSelfTestFailureModes = []string{schema.SelfTestFailureModeFatal, schema.SelfTestFailureModeWarn}
+
+var validSensitiveActions = []string{
+	schema.SensitiveActionTOTPRegistration, schema.SensitiveActionTOTPDeletion, schema.SensitiveActionTOTPRotation,
+	schema.SensitiveActionWebauthnRegistration, schema.SensitiveActionDuoDeviceSelection, schema.SensitiveActionPreferredMethod,
+	schema.SensitiveActionOpenIDConnectRefreshTokenRevocation, schema.SensitiveActionAdministration,
+}
+
+var validSessionProviders = []string{schema.SessionProviderMemory, schema.SessionProviderRedis, schema.SessionProviderStorage}
+
+var validSessionConcurrencyPolicies = []string{schema.SessionConcurrencyPolicyReject, schema.SessionConcurrencyPolicyEvictOldest}
+
+var validSessionBindingModes = []string{schema.SessionBindingModeNone, schema.SessionBindingModeUserAgent, schema.SessionBindingModeNetwork, schema.SessionBindingModeUserAgentAndNetwork}
+
 var validLoLevels = []string{"trace", "debug", "info", "warn", "error"}
 
+var validLogRedactionMethods = []string{schema.LogRedactionMethodHash, schema.LogRedactionMethodMask}
+
+var validLogSyslogNetworks = []string{schema.LogSyslogNetworkUnix, schema.LogSyslogNetworkUDP, schema.LogSyslogNetworkTCP, schema.LogSyslogNetworkTCPTLS}
+
+var validLogSyslogFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news", "uucp", "cron", "authpriv", "ftp",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+var validLogRedactionFields = []string{schema.LogRedactionFieldUsername, schema.LogRedactionFieldRemoteIP, schema.LogRedactionFieldEmail}
+
 var validWebauthnConveyancePreferences = []string{string(protocol.PreferNoAttestation), string(protocol.PreferIndirectAttestation), string(protocol.PreferDirectAttestation)}
 var validWebauthnUserVerificationRequirement = []string{string(protocol.VerificationDiscouraged), string(protocol.VerificationPreferred), string(protocol.VerificationRequired)}
 
@@ -285,22 +683,57 @@ var validRFC4918HTTPMethodVerbs = []string{"COPY", "LOCK", "MKCOL", "MOVE", "PRO
 
 var validACLHTTPMethodVerbs = append(validRFC7231HTTPMethodVerbs, validRFC4918HTTPMethodVerbs...)
 
+var validACLTimeWindowDays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
 var validACLRulePolicies = []string{policyBypass, policyOneFactor, policyTwoFactor, policyDeny}
 
+var validACLRuleSecondFactorMethods = []string{model.SecondFactorMethodTOTP, model.SecondFactorMethodWebauthn, model.SecondFactorMethodDuo}
+
+var validCAPTCHAProviders = []string{schema.CAPTCHAProviderReCAPTCHA, schema.CAPTCHAProviderHCaptcha, schema.CAPTCHAProviderTurnstile}
+
+var validCAPTCHAFailureModes = []string{schema.CAPTCHAFailureModeClosed, schema.CAPTCHAFailureModeOpen}
+
+var validSecondFactorLimitActions = []string{schema.SecondFactorLimitActionDestroySession, schema.SecondFactorLimitActionBan}
+
+var validDuoFailureModes = []string{schema.DuoFailureModeDeny, schema.DuoFailureModeAllow}
+
+var validLDAPPasswordExpirationActions = []string{schema.LDAPPasswordExpirationActionDeny, schema.LDAPPasswordExpirationActionReset}
+
+var validOIDCClientTokenEndpointAuthMethods = []string{
+	oidc.ClientAuthMethodClientSecretBasic, oidc.ClientAuthMethodClientSecretPost, oidc.ClientAuthMethodClientSecretJWT, oidc.ClientAuthMethodNone,
+}
+
+var validOIDCClientPKCEChallengeMethods = []string{oidc.PKCEChallengeMethodSHA256, oidc.PKCEChallengeMethodPlain}
+
+var validPasswordResetMethods = []string{schema.PasswordResetMethodEmail, schema.PasswordResetMethodAdminCode}
+
+var validClientCertificateUsernameAttributes = []string{"common_name", "email_address", "dns_name", "uri"}
+
+var validTrustedJWTAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
 var validOIDCScopes = []string{oidc.ScopeOpenID, oidc.ScopeEmail, oidc.ScopeProfile, oidc.ScopeGroups, "offline_access"}
+
+var validOIDCClaims = []string{oidc.ClaimGroups, oidc.ClaimDisplayName, oidc.ClaimPreferredUsername, oidc.ClaimEmail, oidc.ClaimEmailVerified, oidc.ClaimEmailAlts}
 var validOIDCGrantTypes = []string{"implicit", "refresh_token", "authorization_code", "password", "client_credentials"}
+var validOIDCAllowedGrantTypes = []string{"authorization_code", "implicit", "refresh_token", "client_credentials"}
+var validOIDCResponseTypes = []string{"code", "token", "id_token", "code token", "code id_token", "token id_token", "code token id_token", "none"}
 var validOIDCResponseModes = []string{"form_post", "query", "fragment"}
 var validOIDCUserinfoAlgorithms = []string{"none", "RS256"}
 var validOIDCCORSEndpoints = []string{oidc.AuthorizationEndpoint, oidc.TokenEndpoint, oidc.IntrospectionEndpoint, oidc.RevocationEndpoint, oidc.UserinfoEndpoint}
 
 var reKeyReplacer = regexp.MustCompile(`\[\d+]`)
 
+var regexpAPIKeyDigest = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+var regexpTOTPAccountLabelPlaceholder = regexp.MustCompile(`{([^{}]*)}`)
+
 // ValidKeys is a list of valid keys that are not secret names. For the sake of consistency please place any secret in
 // the secret names map and reuse it in relevant sections.
 var ValidKeys = []string{
 	// Root Keys.
 	"certificates_directory",
 	"theme",
+	"theme_fallback",
 	"default_redirection_url",
 	"jwt_secret",
 
@@ -309,6 +742,20 @@ var ValidKeys = []string{
 	"log.format",
 	"log.file_path",
 	"log.keep_stdout",
+	"log.slow_request_threshold",
+	"log.redaction.enabled",
+	"log.redaction.method",
+	"log.redaction.secret",
+	"log.redaction.fields",
+	"log.syslog.enabled",
+	"log.syslog.network",
+	"log.syslog.address",
+	"log.syslog.facility",
+	"log.syslog.tag",
+	"log.syslog.tls.minimum_version",
+	"log.syslog.tls.skip_verify",
+	"log.syslog.tls.server_name",
+	"log.journald.enabled",
 
 	// Server Keys.
 	"server.host",
@@ -320,13 +767,36 @@ var ValidKeys = []string{
 	"server.enable_pprof",
 	"server.enable_expvars",
 	"server.disable_healthcheck",
+	"server.enable_matched_rule_header",
+	"server.read_timeout",
+	"server.write_timeout",
+	"server.idle_timeout",
+	"server.max_connections",
+	"server.max_connections_per_ip",
+	"server.max_requests_per_connection",
+	"server.disable_keep_alive",
+	"server.trusted_proxies",
+	"server.robots_txt",
+	"server.security_txt",
 	"server.tls.key",
 	"server.tls.certificate",
+	"server.tls.client_certificates",
+	"server.tls.minimum_version",
+	"server.tls.maximum_version",
+	"server.tls.cipher_suites",
 	"server.headers.csp_template",
+	"server.headers.referrer_policy",
+	"server.headers.permissions_policy",
+	"server.headers.frame_options",
+	"server.headers.strict_transport_security",
+	"server.proxy_protocol.enabled",
+	"server.proxy_protocol.trusted_upstreams",
+	"server.proxy_protocol.header_timeout",
 
 	// TOTP Keys.
 	"totp.disable",
 	"totp.issuer",
+	"totp.account_label",
 	"totp.algorithm",
 	"totp.digits",
 	"totp.period",
@@ -336,6 +806,8 @@ var ValidKeys = []string{
 	// Webauthn Keys.
 	"webauthn.disable",
 	"webauthn.display_name",
+	"webauthn.rp_id",
+	"webauthn.allowed_origins",
 	"webauthn.attestation_conveyance_preference",
 	"webauthn.user_verification",
 	"webauthn.timeout",
@@ -345,13 +817,25 @@ var ValidKeys = []string{
 	"duo_api.enable_self_enrollment",
 	"duo_api.secret_key",
 	"duo_api.integration_key",
+	"duo_api.timeouts.preauth",
+	"duo_api.timeouts.auth",
+	"duo_api.failure_mode",
 
 	// Access Control Keys.
 	"access_control.default_policy",
+	"access_control.debug_trace",
+	"access_control.shadow",
+	"access_control.shadow_two_factor",
+	"access_control.deny_response.status_code",
+	"access_control.deny_response.redirect_url",
+	"access_control.deny_response.message",
+	"access_control.deny_response.help_message",
+	"access_control.deny_response.help_url",
 	"access_control.networks",
 	"access_control.networks[].name",
 	"access_control.networks[].networks",
 	"access_control.rules",
+	"access_control.rules[].name",
 	"access_control.rules[].domain",
 	"access_control.rules[].domain_regex",
 	"access_control.rules[].methods",
@@ -359,15 +843,61 @@ var ValidKeys = []string{
 	"access_control.rules[].subject",
 	"access_control.rules[].policy",
 	"access_control.rules[].resources",
+	"access_control.rules[].network_policies",
+	"access_control.rules[].network_policies[].networks",
+	"access_control.rules[].network_policies[].policy",
+	"access_control.rules[].maximum_authentication_age",
+	"access_control.rules[].second_factor_methods",
+	"access_control.rules[].deny_response.status_code",
+	"access_control.rules[].deny_response.redirect_url",
+	"access_control.rules[].deny_response.message",
+	"access_control.rules[].deny_response.help_message",
+	"access_control.rules[].deny_response.help_url",
+	"access_control.rules[].headers",
+	"access_control.rules[].headers[].name",
+	"access_control.rules[].headers[].value",
+	"access_control.rules[].user_agent",
+	"access_control.rules[].time_window.days",
+	"access_control.rules[].time_window.start",
+	"access_control.rules[].time_window.end",
+	"access_control.rules[].time_window.timezone",
+	"access_control.rules[].time_window.terminate_sessions",
+	"access_control.rules[].identity.username",
+	"access_control.rules[].identity.display_name",
+	"access_control.rules[].identity.email",
+	"access_control.rules[].identity.groups",
+	"access_control.rules[].shadow",
 
 	// Session Keys.
 	"session.name",
 	"session.domain",
 	"session.secret",
 	"session.same_site",
+	"session.partitioned",
 	"session.expiration",
 	"session.inactivity",
+	"session.inactivity_warning",
 	"session.remember_me_duration",
+	"session.remember_me.validity",
+	"session.remember_me.cookie_max_age",
+	"session.remember_me.second_factor_reverification",
+	"session.provider",
+	"session.maximum_authentication_age",
+	"session.disable_logout_on_password_change",
+	"session.default_redirection_urls",
+	"session.default_redirection_urls[].domain",
+	"session.default_redirection_urls[].url",
+	"session.concurrency.limit",
+	"session.concurrency.remember_me_limit",
+	"session.concurrency.policy",
+	"session.binding.mode",
+	"session.binding.ipv4_mask",
+	"session.binding.ipv6_mask",
+	"session.introspection.enabled",
+	"session.introspection.trusted_networks",
+	"session.introspection.secret",
+	"session.introspection.rate_limit",
+	"session.introspection.rate_limit_period",
 
 	// Redis Session Keys.
 	"session.redis.host",
@@ -391,6 +921,13 @@ var ValidKeys = []string{
 
 	// Storage Keys.
 	"storage.encryption_key",
+	"storage.authentication_logs_retention",
+	"storage.cleanup.disable",
+	"storage.cleanup.interval",
+	"storage.cleanup.batch_size",
+	"storage.connection_retry.interval",
+	"storage.connection_retry.backoff_factor",
+	"storage.connection_retry.max_duration",
 
 	// Local Storage Keys.
 	"storage.local.path",
@@ -438,16 +975,153 @@ var ValidKeys = []string{
 	"notifier.smtp.tls.skip_verify",
 	"notifier.smtp.tls.server_name",
 	"notifier.template_path",
+	"notifier.notifiers",
+	"notifier.notifiers[].name",
+	"notifier.notifiers[].events",
+	"notifier.notifiers[].filesystem.filename",
+	"notifier.notifiers[].smtp.host",
+	"notifier.notifiers[].smtp.port",
+	"notifier.notifiers[].smtp.timeout",
+	"notifier.notifiers[].smtp.username",
+	"notifier.notifiers[].smtp.password",
+	"notifier.notifiers[].smtp.identifier",
+	"notifier.notifiers[].smtp.sender",
+	"notifier.notifiers[].smtp.subject",
+	"notifier.notifiers[].smtp.startup_check_address",
+	"notifier.notifiers[].smtp.disable_require_tls",
+	"notifier.notifiers[].smtp.disable_html_emails",
+	"notifier.notifiers[].smtp.tls.minimum_version",
+	"notifier.notifiers[].smtp.tls.skip_verify",
+	"notifier.notifiers[].smtp.tls.server_name",
+	"notifier.notifiers[].webhook.url",
+	"notifier.notifiers[].webhook.timeout",
 
 	// Regulation Keys.
 	"regulation.max_retries",
 	"regulation.find_time",
 	"regulation.ban_time",
+	"regulation.impossible_travel.enabled",
+	"regulation.impossible_travel.networks",
+	"regulation.impossible_travel.header_latitude",
+	"regulation.impossible_travel.header_longitude",
+	"regulation.impossible_travel.maximum_speed",
+	"regulation.impossible_travel.minimum_distance",
+	"regulation.impossible_travel.notify",
+	"regulation.captcha.provider",
+	"regulation.captcha.site_key",
+	"regulation.captcha.secret_key",
+	"regulation.captcha.threshold",
+	"regulation.captcha.timeout",
+	"regulation.captcha.failure_mode",
+	"regulation.unlock.enabled",
+	"regulation.unlock.lifespan",
+	"regulation.unlock.max_attempts",
+	"regulation.second_factor.max_attempts",
+	"regulation.second_factor.action",
+
+	// Self-Registration Keys.
+	"self_registration.enabled",
+	"self_registration.admin_emails",
+	"self_registration.default_groups",
+	"self_registration.rate_limit",
+	"self_registration.rate_limit_period",
+	"self_registration.email_verification.enabled",
+	"self_registration.email_verification.lifespan",
+
+	// Events Keys.
+	"events.buffer_size",
+	"events.nats.host",
+	"events.nats.port",
+	"events.nats.timeout",
+	"events.nats.username",
+	"events.nats.password",
+	"events.nats.token",
+	"events.nats.subject_prefix",
+	"events.nats.tls.minimum_version",
+	"events.nats.tls.skip_verify",
+	"events.nats.tls.server_name",
+
+	// Self-test Keys.
+	"self_test.enabled",
+	"self_test.failure_mode",
+	"self_test.disable_authentication_backend",
+	"self_test.disable_session",
+	"self_test.disable_storage",
+	"self_test.disable_notifier",
+
+	// Login Notifications Keys.
+	"login_notifications.enabled",
+	"login_notifications.remember",
+	"login_notifications.throttle",
+	"login_notifications.header_country",
+
+	// Second Factor Enrollment Keys.
+	"second_factor_enrollment.enforce",
+	"second_factor_enrollment.grace_period",
+
+	// Email Domains Keys.
+	"email_domains.allowed",
+	"email_domains.denied",
+
+	// Sensitive Actions Keys.
+	"sensitive_actions.enabled",
+	"sensitive_actions.window",
+	"sensitive_actions.actions",
 
 	// Authentication Backend Keys.
 	"authentication_backend.disable_reset_password",
 	"authentication_backend.password_reset.custom_url",
+	"authentication_backend.password_reset.method",
+	"authentication_backend.password_reset.code_lifespan",
 	"authentication_backend.refresh_interval",
+	"authentication_backend.admin_group",
+
+	"authentication_backend.client_certificate.enabled",
+	"authentication_backend.client_certificate.header",
+	"authentication_backend.client_certificate.certificate_authority",
+	"authentication_backend.client_certificate.username_attribute",
+	"authentication_backend.client_certificate.networks",
+
+	"authentication_backend.api_keys.header",
+	"authentication_backend.api_keys.keys",
+	"authentication_backend.api_keys.keys[].name",
+	"authentication_backend.api_keys.keys[].digest",
+	"authentication_backend.api_keys.keys[].username",
+	"authentication_backend.api_keys.keys[].groups",
+	"authentication_backend.api_keys.keys[].domains",
+	"authentication_backend.api_keys.keys[].resources",
+	"authentication_backend.trusted_jwt.enabled",
+	"authentication_backend.trusted_jwt.header",
+	"authentication_backend.trusted_jwt.issuer",
+	"authentication_backend.trusted_jwt.audience",
+	"authentication_backend.trusted_jwt.jwks_url",
+	"authentication_backend.trusted_jwt.jwks_cache.lifespan",
+	"authentication_backend.trusted_jwt.jwks_cache.max_stale",
+	"authentication_backend.trusted_jwt.jwks_cache.max_size",
+	"authentication_backend.trusted_jwt.algorithms",
+	"authentication_backend.trusted_jwt.leeway",
+	"authentication_backend.trusted_jwt.claims.username",
+	"authentication_backend.trusted_jwt.claims.display_name",
+	"authentication_backend.trusted_jwt.claims.email",
+	"authentication_backend.trusted_jwt.claims.groups",
+	"authentication_backend.trusted_header.enabled",
+	"authentication_backend.trusted_header.header",
+	"authentication_backend.trusted_header.groups_header",
+	"authentication_backend.trusted_header.networks",
+	"authentication_backend.trusted_header.domains",
+	"authentication_backend.break_glass.enabled",
+	"authentication_backend.break_glass.username",
+	"authentication_backend.break_glass.password",
+	"authentication_backend.break_glass.display_name",
+	"authentication_backend.break_glass.email",
+	"authentication_backend.break_glass.group",
+	"authentication_backend.username_canonicalization.strip_domain_suffixes",
+	"authentication_backend.username_canonicalization.pattern",
+	"authentication_backend.username_canonicalization.replacement",
+	"authentication_backend.username_canonicalization.lowercase",
+	"authentication_backend.cache.enabled",
+	"authentication_backend.cache.lifespan",
+	"authentication_backend.cache.max_entries",
 
 	// LDAP Authentication Backend Keys.
 	"authentication_backend.ldap.implementation",
@@ -462,8 +1136,16 @@ var ValidKeys = []string{
 	"authentication_backend.ldap.group_name_attribute",
 	"authentication_backend.ldap.mail_attribute",
 	"authentication_backend.ldap.display_name_attribute",
+	"authentication_backend.ldap.subject_attribute",
 	"authentication_backend.ldap.user",
 	"authentication_backend.ldap.password",
+	"authentication_backend.ldap.max_concurrency",
+	"authentication_backend.ldap.queue_timeout",
+	"authentication_backend.ldap.password_expiration.enabled",
+	"authentication_backend.ldap.password_expiration.action",
+	"authentication_backend.ldap.password_expiration.account_expires_attribute",
+	"authentication_backend.ldap.password_expiration.password_expires_attribute",
+	"authentication_backend.ldap.password_expiration.password_must_change_attribute",
 	"authentication_backend.ldap.start_tls",
 	"authentication_backend.ldap.tls.minimum_version",
 	"authentication_backend.ldap.tls.skip_verify",
@@ -476,37 +1158,80 @@ var ValidKeys = []string{
 	"authentication_backend.file.password.key_length",
 	"authentication_backend.file.password.salt_length",
 	"authentication_backend.file.password.memory",
+	"authentication_backend.file.password.memory_percentage",
+	"authentication_backend.file.password.memory_minimum",
+	"authentication_backend.file.password.memory_maximum",
 	"authentication_backend.file.password.parallelism",
 
 	// Identity Provider Keys.
 	"identity_providers.oidc.hmac_secret",
 	"identity_providers.oidc.issuer_private_key",
+	"identity_providers.oidc.issuer_private_keys",
+	"identity_providers.oidc.issuer_private_keys[].key",
+	"identity_providers.oidc.issuer_private_keys[].activate_at",
+	"identity_providers.oidc.issuer_private_keys[].expires_at",
+	"identity_providers.oidc.key_rotation_grace_period",
+	"identity_providers.oidc.clock_skew",
 	"identity_providers.oidc.id_token_lifespan",
 	"identity_providers.oidc.access_token_lifespan",
 	"identity_providers.oidc.refresh_token_lifespan",
+	"identity_providers.oidc.revoke_refresh_tokens_on_logout",
 	"identity_providers.oidc.authorize_code_lifespan",
 	"identity_providers.oidc.enforce_pkce",
 	"identity_providers.oidc.enable_pkce_plain_challenge",
 	"identity_providers.oidc.enable_client_debug_messages",
 	"identity_providers.oidc.minimum_parameter_entropy",
+	"identity_providers.oidc.allowed_grant_types",
+	"identity_providers.oidc.allowed_response_types",
 	"identity_providers.oidc.cors.endpoints",
 	"identity_providers.oidc.cors.allowed_origins",
 	"identity_providers.oidc.cors.enable_origins_from_clients",
+	"identity_providers.oidc.custom_scopes",
+	"identity_providers.oidc.custom_scopes[].name",
+	"identity_providers.oidc.custom_scopes[].description",
+	"identity_providers.oidc.custom_scopes[].claims",
+	"identity_providers.oidc.acr_values",
+	"identity_providers.oidc.acr_values[].value",
+	"identity_providers.oidc.acr_values[].policy",
+	"identity_providers.oidc.unknown_acr_values",
+	"identity_providers.oidc.token_endpoint.max_request_body_size",
+	"identity_providers.oidc.token_endpoint.disable_rate_limit",
+	"identity_providers.oidc.token_endpoint.rate_limit",
+	"identity_providers.oidc.token_endpoint.client_rate_limit",
+	"identity_providers.oidc.token_endpoint.first_party_client_rate_limit",
+	"identity_providers.oidc.token_endpoint.rate_limit_period",
+	"identity_providers.oidc.clients_path",
+	"identity_providers.oidc.clients_reload_interval",
 	"identity_providers.oidc.clients",
 	"identity_providers.oidc.clients[].id",
 	"identity_providers.oidc.clients[].description",
 	"identity_providers.oidc.clients[].secret",
 	"identity_providers.oidc.clients[].sector_identifier",
 	"identity_providers.oidc.clients[].public",
+	"identity_providers.oidc.clients[].previous_secret",
+	"identity_providers.oidc.clients[].previous_secret_expires_at",
 	"identity_providers.oidc.clients[].redirect_uris",
+	"identity_providers.oidc.clients[].allowed_origins",
+	"identity_providers.oidc.clients[].allowed_groups",
+	"identity_providers.oidc.clients[].first_party",
+	"identity_providers.oidc.clients[].pre_authorized_scopes",
 	"identity_providers.oidc.clients[].authorization_policy",
 	"identity_providers.oidc.clients[].pre_configured_consent_duration",
 	"identity_providers.oidc.clients[].scopes",
+	"identity_providers.oidc.clients[].default_scopes",
 	"identity_providers.oidc.clients[].audience",
+	"identity_providers.oidc.clients[].scope_audiences[].scope",
+	"identity_providers.oidc.clients[].scope_audiences[].audience",
 	"identity_providers.oidc.clients[].grant_types",
 	"identity_providers.oidc.clients[].response_types",
 	"identity_providers.oidc.clients[].response_modes",
+	"identity_providers.oidc.clients[].token_endpoint_auth_method",
 	"identity_providers.oidc.clients[].userinfo_signing_algorithm",
+	"identity_providers.oidc.clients[].require_pkce",
+	"identity_providers.oidc.clients[].pkce_challenge_method",
+	"identity_providers.oidc.clients[].introspection.enabled",
+	"identity_providers.oidc.clients[].introspection.allowed_clients",
+	"identity_providers.oidc.clients[].introspection.scopes",
 
 	// NTP keys.
 	"ntp.address",
@@ -524,6 +1249,17 @@ var ValidKeys = []string{
 	"password_policy.standard.require_number",
 	"password_policy.standard.require_special",
 	"password_policy.zxcvbn.enabled",
+	"password_policy.policies[].name",
+	"password_policy.policies[].groups",
+	"password_policy.policies[].standard.enabled",
+	"password_policy.policies[].standard.min_length",
+	"password_policy.policies[].standard.max_length",
+	"password_policy.policies[].standard.require_uppercase",
+	"password_policy.policies[].standard.require_lowercase",
+	"password_policy.policies[].standard.require_number",
+	"password_policy.policies[].standard.require_special",
+	"password_policy.policies[].zxcvbn.enabled",
+	"password_policy.minimum_age",
 }
 
 var replacedKeys = map[string]string{
@@ -0,0 +1,37 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ValidateDuo validates and updates the Duo API configuration.
+func ValidateDuo(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.DuoAPI == nil {
+		return
+	}
+
+	switch {
+	case config.DuoAPI.Timeouts.PreAuth == 0:
+		config.DuoAPI.Timeouts.PreAuth = schema.DefaultDuoAPIConfiguration.Timeouts.PreAuth
+	case config.DuoAPI.Timeouts.PreAuth < 0:
+		validator.Push(fmt.Errorf(errFmtDuoTimeoutNegative, "preauth", config.DuoAPI.Timeouts.PreAuth))
+	}
+
+	switch {
+	case config.DuoAPI.Timeouts.Auth == 0:
+		config.DuoAPI.Timeouts.Auth = schema.DefaultDuoAPIConfiguration.Timeouts.Auth
+	case config.DuoAPI.Timeouts.Auth < 0:
+		validator.Push(fmt.Errorf(errFmtDuoTimeoutNegative, "auth", config.DuoAPI.Timeouts.Auth))
+	}
+
+	switch {
+	case config.DuoAPI.FailureMode == "":
+		config.DuoAPI.FailureMode = schema.DefaultDuoAPIConfiguration.FailureMode
+	case !utils.IsStringInSlice(config.DuoAPI.FailureMode, validDuoFailureModes):
+		validator.Push(fmt.Errorf(errFmtDuoFailureMode, strings.Join(validDuoFailureModes, "', '"), config.DuoAPI.FailureMode))
+	}
+}
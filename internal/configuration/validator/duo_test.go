@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldNotValidateDuoWhenNotConfigured(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{}
+
+	ValidateDuo(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Nil(t, config.DuoAPI)
+}
+
+func TestShouldSetDuoDefaults(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		DuoAPI: &schema.DuoAPIConfiguration{
+			Hostname: "api-123456789.example.com",
+		},
+	}
+
+	ValidateDuo(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, 10*time.Second, config.DuoAPI.Timeouts.PreAuth)
+	assert.Equal(t, 75*time.Second, config.DuoAPI.Timeouts.Auth)
+	assert.Equal(t, schema.DuoFailureModeDeny, config.DuoAPI.FailureMode)
+}
+
+func TestShouldNotOverrideDuoOptions(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		DuoAPI: &schema.DuoAPIConfiguration{
+			Timeouts: schema.DuoAPITimeoutsConfiguration{
+				PreAuth: 5 * time.Second,
+				Auth:    30 * time.Second,
+			},
+			FailureMode: schema.DuoFailureModeAllow,
+		},
+	}
+
+	ValidateDuo(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, 5*time.Second, config.DuoAPI.Timeouts.PreAuth)
+	assert.Equal(t, 30*time.Second, config.DuoAPI.Timeouts.Auth)
+	assert.Equal(t, schema.DuoFailureModeAllow, config.DuoAPI.FailureMode)
+}
+
+func TestShouldRaiseErrorsOnInvalidDuoOptions(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		DuoAPI: &schema.DuoAPIConfiguration{
+			Timeouts: schema.DuoAPITimeoutsConfiguration{
+				PreAuth: -1 * time.Second,
+				Auth:    -2 * time.Second,
+			},
+			FailureMode: "ignore",
+		},
+	}
+
+	ValidateDuo(config, validator)
+
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "duo_api: timeouts: option 'preauth' must be more than 0 but it is configured as '-1s'")
+	assert.EqualError(t, validator.Errors()[1], "duo_api: timeouts: option 'auth' must be more than 0 but it is configured as '-2s'")
+	assert.EqualError(t, validator.Errors()[2], "duo_api: option 'failure_mode' must be one of 'deny', 'allow' but it is configured as 'ignore'")
+}
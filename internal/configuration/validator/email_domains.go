@@ -0,0 +1,31 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// ValidateEmailDomains validates the email domains configuration.
+func ValidateEmailDomains(config *schema.EmailDomainsConfiguration, validator *schema.StructValidator) {
+	for _, pattern := range config.Allowed {
+		if !isValidEmailDomainPattern(pattern) {
+			validator.Push(fmt.Errorf(errFmtEmailDomainsInvalidPattern, "allowed", pattern))
+		}
+	}
+
+	for _, pattern := range config.Denied {
+		if !isValidEmailDomainPattern(pattern) {
+			validator.Push(fmt.Errorf(errFmtEmailDomainsInvalidPattern, "denied", pattern))
+		}
+	}
+}
+
+func isValidEmailDomainPattern(pattern string) bool {
+	domain := strings.TrimPrefix(pattern, "*.")
+
+	return domain != "" && !strings.Contains(domain, "*") && govalidator.IsDNSName(domain)
+}
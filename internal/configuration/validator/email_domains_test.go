@@ -0,0 +1,38 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldValidateEmailDomains(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.EmailDomainsConfiguration{
+		Allowed: []string{"example.com", "*.example.com", "Example.ORG"},
+		Denied:  []string{"old.example.com"},
+	}
+
+	ValidateEmailDomains(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestShouldRaiseErrorsOnInvalidEmailDomains(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.EmailDomainsConfiguration{
+		Allowed: []string{"*", "mail.*.example.com"},
+		Denied:  []string{"@example.com", ""},
+	}
+
+	ValidateEmailDomains(config, validator)
+
+	require.Len(t, validator.Errors(), 4)
+	assert.EqualError(t, validator.Errors()[0], "email_domains: option 'allowed' must only contain domains or domains prefixed with '*.' but it contains '*'")
+	assert.EqualError(t, validator.Errors()[1], "email_domains: option 'allowed' must only contain domains or domains prefixed with '*.' but it contains 'mail.*.example.com'")
+	assert.EqualError(t, validator.Errors()[2], "email_domains: option 'denied' must only contain domains or domains prefixed with '*.' but it contains '@example.com'")
+	assert.EqualError(t, validator.Errors()[3], "email_domains: option 'denied' must only contain domains or domains prefixed with '*.' but it contains ''")
+}
@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+var regexpNATSSubjectPrefix = regexp.MustCompile(`^[a-zA-Z0-9_-]+(\.[a-zA-Z0-9_-]+)*$`)
+
+// ValidateEvents validates and updates the events configuration.
+func ValidateEvents(config *schema.EventsConfiguration, validator *schema.StructValidator) {
+	if config.NATS == nil {
+		return
+	}
+
+	switch {
+	case config.BufferSize == 0:
+		config.BufferSize = schema.DefaultEventsConfiguration.BufferSize
+	case config.BufferSize < 0:
+		validator.Push(fmt.Errorf(errFmtEventsBufferSize, config.BufferSize))
+	}
+
+	validateNATSEvents(config.NATS, validator)
+}
+
+func validateNATSEvents(config *schema.NATSEventsConfiguration, validator *schema.StructValidator) {
+	if config.Host == "" {
+		validator.Push(fmt.Errorf(errFmtEventsNATSNotConfigured, "host"))
+	}
+
+	if config.Port == 0 {
+		config.Port = schema.DefaultNATSEventsConfiguration.Port
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = schema.DefaultNATSEventsConfiguration.Timeout
+	}
+
+	if config.SubjectPrefix == "" {
+		config.SubjectPrefix = schema.DefaultNATSEventsConfiguration.SubjectPrefix
+	} else if !regexpNATSSubjectPrefix.MatchString(config.SubjectPrefix) {
+		validator.Push(fmt.Errorf(errFmtEventsNATSSubjectPrefix, config.SubjectPrefix))
+	}
+
+	if config.Token != "" && (config.Username != "" || config.Password != "") {
+		validator.Push(fmt.Errorf(errFmtEventsNATSAuthentication))
+	}
+
+	if config.TLS != nil && config.TLS.ServerName == "" {
+		config.TLS.ServerName = config.Host
+	}
+}
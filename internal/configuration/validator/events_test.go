@@ -0,0 +1,60 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldNotValidateEventsWhenNoSinkConfigured(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.EventsConfiguration{BufferSize: -1}
+
+	ValidateEvents(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, -1, config.BufferSize)
+}
+
+func TestShouldSetDefaultEventsConfiguration(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.EventsConfiguration{
+		NATS: &schema.NATSEventsConfiguration{
+			Host: "nats.example.com",
+			TLS:  &schema.TLSConfig{},
+		},
+	}
+
+	ValidateEvents(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, 1024, config.BufferSize)
+	assert.Equal(t, 4222, config.NATS.Port)
+	assert.Equal(t, time.Second*5, config.NATS.Timeout)
+	assert.Equal(t, "authelia", config.NATS.SubjectPrefix)
+	assert.Equal(t, "nats.example.com", config.NATS.TLS.ServerName)
+}
+
+func TestShouldRaiseErrorsOnInvalidEventsConfiguration(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.EventsConfiguration{
+		BufferSize: -5,
+		NATS: &schema.NATSEventsConfiguration{
+			SubjectPrefix: "authelia.*",
+			Username:      "authelia",
+			Token:         "secret",
+		},
+	}
+
+	ValidateEvents(config, validator)
+
+	require.Len(t, validator.Errors(), 4)
+	assert.EqualError(t, validator.Errors()[0], "events: option 'buffer_size' must be more than 0 but it is configured as '-5'")
+	assert.EqualError(t, validator.Errors()[1], "events: nats: option 'host' is required")
+	assert.EqualError(t, validator.Errors()[2], "events: nats: option 'subject_prefix' must only contain alphanumeric characters, hyphens, underscores, and periods but it is configured as 'authelia.*'")
+	assert.EqualError(t, validator.Errors()[3], "events: nats: option 'token' must not be configured at the same time as the 'username' and 'password' options")
+}
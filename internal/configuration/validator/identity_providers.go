@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -21,6 +22,8 @@ func validateOIDC(config *schema.OpenIDConnectConfiguration, validator *schema.S
 			validator.Push(fmt.Errorf(errFmtOIDCNoPrivateKey))
 		}
 
+		validateOIDCIssuerPrivateKeys(config, validator)
+
 		if config.AccessTokenLifespan == time.Duration(0) {
 			config.AccessTokenLifespan = schema.DefaultOpenIDConnectConfiguration.AccessTokenLifespan
 		}
@@ -37,6 +40,8 @@ func validateOIDC(config *schema.OpenIDConnectConfiguration, validator *schema.S
 			config.RefreshTokenLifespan = schema.DefaultOpenIDConnectConfiguration.RefreshTokenLifespan
 		}
 
+		validateOIDCClockSkew(config, validator)
+
 		if config.MinimumParameterEntropy != 0 && config.MinimumParameterEntropy < 8 {
 			validator.PushWarning(fmt.Errorf(errFmtOIDCServerInsecureParameterEntropy, config.MinimumParameterEntropy))
 		}
@@ -49,15 +54,117 @@ func validateOIDC(config *schema.OpenIDConnectConfiguration, validator *schema.S
 			validator.Push(fmt.Errorf(errFmtOIDCEnforcePKCEInvalidValue, config.EnforcePKCE))
 		}
 
+		validateOIDCAllowedTypes(config, validator)
 		validateOIDCOptionsCORS(config, validator)
+		validateOIDCCustomScopes(config, validator)
+		validateOIDCACRValues(config, validator)
+		validateOIDCTokenEndpoint(config, validator)
+		validateOIDCClientsPath(config, validator)
 		validateOIDCClients(config, validator)
 
-		if len(config.Clients) == 0 {
-			validator.Push(fmt.Errorf(errFmtOIDCNoClientsConfigured))
+		if config.ClientsPath == "" {
+			validateOIDCClientsIntrospectionAllowedClients(config, validator)
+
+			if len(config.Clients) == 0 {
+				validator.Push(fmt.Errorf(errFmtOIDCNoClientsConfigured))
+			}
 		}
 	}
 }
 
+// ValidateOpenIDConnectClients validates the clients of the OpenID Connect configuration, this is used to validate the
+// clients loaded from the clients path combined with the clients from the configuration.
+func ValidateOpenIDConnectClients(config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	validateOIDCClients(config, validator)
+	validateOIDCClientsIntrospectionAllowedClients(config, validator)
+
+	if len(config.Clients) == 0 {
+		validator.Push(fmt.Errorf(errFmtOIDCNoClientsConfigured))
+	}
+}
+
+func validateOIDCClientsPath(config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	switch {
+	case config.ClientsReloadInterval == time.Duration(0):
+		config.ClientsReloadInterval = schema.DefaultOpenIDConnectConfiguration.ClientsReloadInterval
+	case config.ClientsReloadInterval < 0:
+		validator.Push(fmt.Errorf(errFmtOIDCClientsReloadIntervalNegative, config.ClientsReloadInterval))
+	}
+}
+
+func validateOIDCClockSkew(config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	switch {
+	case config.ClockSkew == time.Duration(0):
+		config.ClockSkew = schema.DefaultOpenIDConnectConfiguration.ClockSkew
+	case config.ClockSkew < 0 || config.ClockSkew > schema.MaximumOpenIDConnectClockSkew:
+		validator.Push(fmt.Errorf(errFmtOIDCClockSkewOutOfRange, schema.MaximumOpenIDConnectClockSkew, config.ClockSkew))
+	}
+}
+
+func validateOIDCTokenEndpoint(config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	defaults := schema.DefaultOpenIDConnectConfiguration.TokenEndpoint
+
+	for _, limit := range []struct {
+		name  string
+		value *int
+		def   int
+	}{
+		{"max_request_body_size", &config.TokenEndpoint.MaxRequestBodySize, defaults.MaxRequestBodySize},
+		{"rate_limit", &config.TokenEndpoint.RateLimit, defaults.RateLimit},
+		{"client_rate_limit", &config.TokenEndpoint.ClientRateLimit, defaults.ClientRateLimit},
+		{"first_party_client_rate_limit", &config.TokenEndpoint.FirstPartyClientRateLimit, defaults.FirstPartyClientRateLimit},
+	} {
+		switch {
+		case *limit.value == 0:
+			*limit.value = limit.def
+		case *limit.value < 0:
+			validator.Push(fmt.Errorf(errFmtOIDCTokenEndpointNegative, limit.name, *limit.value))
+		}
+	}
+
+	switch {
+	case config.TokenEndpoint.RateLimitPeriod == 0:
+		config.TokenEndpoint.RateLimitPeriod = defaults.RateLimitPeriod
+	case config.TokenEndpoint.RateLimitPeriod < 0:
+		validator.Push(fmt.Errorf(errFmtOIDCTokenEndpointPeriodNegative, config.TokenEndpoint.RateLimitPeriod))
+	}
+}
+
+func validateOIDCIssuerPrivateKeys(config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	switch {
+	case config.KeyRotationGracePeriod == time.Duration(0):
+		config.KeyRotationGracePeriod = schema.DefaultOpenIDConnectConfiguration.KeyRotationGracePeriod
+	case config.KeyRotationGracePeriod < 0:
+		validator.Push(fmt.Errorf(errFmtOIDCKeyRotationGracePeriodNegative, config.KeyRotationGracePeriod))
+	}
+
+	activations := make([]time.Time, 0, len(config.IssuerPrivateKeys))
+
+	for i, key := range config.IssuerPrivateKeys {
+		if key.Key == "" {
+			validator.Push(fmt.Errorf(errFmtOIDCIssuerPrivateKeysNoKey, i+1))
+		}
+
+		if key.ActivateAt.IsZero() {
+			continue
+		}
+
+		if !key.ExpiresAt.IsZero() {
+			validator.Push(fmt.Errorf(errFmtOIDCIssuerPrivateKeysActivateExpires, i+1))
+		}
+
+		for _, activation := range activations {
+			if activation.Equal(key.ActivateAt) {
+				validator.Push(fmt.Errorf(errFmtOIDCIssuerPrivateKeysDuplicateActivateAt, i+1, key.ActivateAt.Format(time.RFC3339)))
+
+				break
+			}
+		}
+
+		activations = append(activations, key.ActivateAt)
+	}
+}
+
 func validateOIDCOptionsCORS(config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
 	validateOIDCOptionsCORSAllowedOrigins(config, validator)
 
@@ -116,6 +223,74 @@ func validateOIDCOptionsCORSEndpoints(config *schema.OpenIDConnectConfiguration,
 		}
 	}
 }
+
+func validateOIDCCustomScopes(config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	var names []string
+
+	for s, scope := range config.CustomScopes {
+		if scope.Name == "" {
+			validator.Push(fmt.Errorf(errFmtOIDCCustomScopeEmptyName))
+
+			continue
+		}
+
+		if utils.IsStringInSlice(scope.Name, validOIDCScopes) {
+			validator.Push(fmt.Errorf(errFmtOIDCCustomScopeStandardName, scope.Name, strings.Join(validOIDCScopes, "', '")))
+		}
+
+		if utils.IsStringInSlice(scope.Name, names) {
+			validator.Push(fmt.Errorf(errFmtOIDCCustomScopeDuplicateName, scope.Name))
+		}
+
+		names = append(names, scope.Name)
+
+		if scope.Description == "" {
+			config.CustomScopes[s].Description = scope.Name
+		}
+
+		if len(scope.Claims) == 0 {
+			validator.Push(fmt.Errorf(errFmtOIDCCustomScopeNoClaims, scope.Name))
+		}
+
+		for _, claim := range scope.Claims {
+			if !utils.IsStringInSlice(claim, validOIDCClaims) {
+				validator.Push(fmt.Errorf(errFmtOIDCCustomScopeInvalidClaim, scope.Name, strings.Join(validOIDCClaims, "', '"), claim))
+			}
+		}
+	}
+}
+
+func validateOIDCACRValues(config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	switch {
+	case config.UnknownACRValues == "":
+		config.UnknownACRValues = schema.DefaultOpenIDConnectConfiguration.UnknownACRValues
+	case config.UnknownACRValues != schema.UnknownACRValuesIgnore && config.UnknownACRValues != schema.UnknownACRValuesError:
+		validator.Push(fmt.Errorf(errFmtOIDCUnknownACRValuesInvalid, config.UnknownACRValues))
+	}
+
+	var values []string
+
+	for i, acr := range config.ACRValues {
+		if acr.Value == "" {
+			validator.Push(fmt.Errorf(errFmtOIDCACRValueEmptyValue))
+
+			continue
+		}
+
+		if utils.IsStringInSlice(acr.Value, values) {
+			validator.Push(fmt.Errorf(errFmtOIDCACRValueDuplicateValue, acr.Value))
+		}
+
+		values = append(values, acr.Value)
+
+		if acr.Policy == "" {
+			config.ACRValues[i].Policy = schema.DefaultOpenIDConnectClientConfiguration.Policy
+		} else if acr.Policy != policyOneFactor && acr.Policy != policyTwoFactor {
+			validator.Push(fmt.Errorf(errFmtOIDCACRValueInvalidPolicy, acr.Value, acr.Policy))
+		}
+	}
+}
+
 func validateOIDCClients(config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
 	invalidID, duplicateIDs := false, false
 
@@ -135,15 +310,7 @@ func validateOIDCClients(config *schema.OpenIDConnectConfiguration, validator *s
 			ids = append(ids, client.ID)
 		}
 
-		if client.Public {
-			if client.Secret != "" {
-				validator.Push(fmt.Errorf(errFmtOIDCClientPublicInvalidSecret, client.ID))
-			}
-		} else {
-			if client.Secret == "" {
-				validator.Push(fmt.Errorf(errFmtOIDCClientInvalidSecret, client.ID))
-			}
-		}
+		validateOIDCClientSecret(client, validator)
 
 		if client.Policy == "" {
 			config.Clients[c].Policy = schema.DefaultOpenIDConnectClientConfiguration.Policy
@@ -153,11 +320,18 @@ func validateOIDCClients(config *schema.OpenIDConnectConfiguration, validator *s
 
 		validateOIDCClientSectorIdentifier(client, validator)
 		validateOIDCClientScopes(c, config, validator)
+		validateOIDCClientDefaultScopes(c, config, validator)
+		validateOIDCClientPreAuthorizedScopes(c, config, validator)
+		validateOIDCClientScopeAudiences(c, config, validator)
 		validateOIDCClientGrantTypes(c, config, validator)
 		validateOIDCClientResponseTypes(c, config, validator)
 		validateOIDCClientResponseModes(c, config, validator)
+		validateOIDCClientTokenEndpointAuthMethod(client, validator)
 		validateOIDDClientUserinfoAlgorithm(c, config, validator)
+		validateOIDCClientPKCE(c, config, validator)
 		validateOIDCClientRedirectURIs(client, validator)
+		validateOIDCClientAllowedOrigins(c, config, validator)
+		validateOIDCClientIntrospectionScopes(c, config, validator)
 	}
 
 	if invalidID {
@@ -169,6 +343,64 @@ func validateOIDCClients(config *schema.OpenIDConnectConfiguration, validator *s
 	}
 }
 
+// validateOIDCClientAllowedOrigins validates the origins registered to a client. They're deliberately not added to the
+// global CORS allowed origins so they only permit requests made on behalf of the client.
+func validateOIDCClientAllowedOrigins(c int, config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	for _, origin := range config.Clients[c].AllowedOrigins {
+		switch {
+		case origin.String() == "*":
+			validator.Push(fmt.Errorf(errFmtOIDCClientInvalidAllowedOrigin, config.Clients[c].ID, origin.String(), "the wildcard origin is not permitted for clients"))
+		case !origin.IsAbs() || origin.Host == "":
+			validator.Push(fmt.Errorf(errFmtOIDCClientInvalidAllowedOrigin, config.Clients[c].ID, origin.String(), "it has no scheme or hostname"))
+		case origin.Path != "":
+			validator.Push(fmt.Errorf(errFmtOIDCClientInvalidAllowedOrigin, config.Clients[c].ID, origin.String(), "it has a path"))
+		case origin.RawQuery != "":
+			validator.Push(fmt.Errorf(errFmtOIDCClientInvalidAllowedOrigin, config.Clients[c].ID, origin.String(), "it has a query string"))
+		}
+	}
+}
+
+// validateOIDCClientSecret validates the secrets of a client. The values of the secrets are never included in the
+// errors so they're not logged.
+func validateOIDCClientSecret(client schema.OpenIDConnectClientConfiguration, validator *schema.StructValidator) {
+	if client.Public {
+		if client.Secret != "" {
+			validator.Push(fmt.Errorf(errFmtOIDCClientPublicInvalidSecret, client.ID))
+		}
+
+		if client.PreviousSecret != "" {
+			validator.Push(fmt.Errorf(errFmtOIDCClientPublicInvalidPreviousSecret, client.ID))
+		}
+
+		return
+	}
+
+	if client.Secret == "" {
+		validator.Push(fmt.Errorf(errFmtOIDCClientInvalidSecret, client.ID))
+	} else {
+		validateOIDCClientSecretDigest(client.ID, "secret", client.Secret, validator)
+	}
+
+	switch {
+	case client.PreviousSecret != "" && client.PreviousSecretExpiresAt.IsZero():
+		validator.Push(fmt.Errorf(errFmtOIDCClientPreviousSecretExpiresAtRequired, client.ID))
+	case client.PreviousSecret == "" && !client.PreviousSecretExpiresAt.IsZero():
+		validator.Push(fmt.Errorf(errFmtOIDCClientPreviousSecretRequired, client.ID))
+	case client.PreviousSecret != "":
+		validateOIDCClientSecretDigest(client.ID, "previous_secret", client.PreviousSecret, validator)
+	}
+}
+
+func validateOIDCClientSecretDigest(id, option, secret string, validator *schema.StructValidator) {
+	if !oidc.IsClientSecretDigest(secret) {
+		return
+	}
+
+	if _, err := oidc.ParseClientSecretDigest(secret); err != nil {
+		validator.Push(fmt.Errorf(errFmtOIDCClientSecretInvalidDigest, id, option, err))
+	}
+}
+
 func validateOIDCClientSectorIdentifier(client schema.OpenIDConnectClientConfiguration, validator *schema.StructValidator) {
 	if client.SectorIdentifier.String() != "" {
 		if utils.IsURLHostComponent(client.SectorIdentifier) || utils.IsURLHostComponentWithPort(client.SectorIdentifier) {
@@ -215,15 +447,173 @@ func validateOIDCClientScopes(c int, configuration *schema.OpenIDConnectConfigur
 		configuration.Clients[c].Scopes = append(configuration.Clients[c].Scopes, "openid")
 	}
 
+	scopes := getOIDCScopes(configuration)
+
 	for _, scope := range configuration.Clients[c].Scopes {
-		if !utils.IsStringInSlice(scope, validOIDCScopes) {
+		if !utils.IsStringInSlice(scope, scopes) {
 			validator.Push(fmt.Errorf(
 				errFmtOIDCClientInvalidEntry,
-				configuration.Clients[c].ID, "scopes", strings.Join(validOIDCScopes, "', '"), scope))
+				configuration.Clients[c].ID, "scopes", strings.Join(scopes, "', '"), scope))
+		}
+	}
+}
+
+// getOIDCScopes returns the standard scopes and the names of the custom scopes.
+func getOIDCScopes(configuration *schema.OpenIDConnectConfiguration) (scopes []string) {
+	scopes = make([]string, len(validOIDCScopes), len(validOIDCScopes)+len(configuration.CustomScopes))
+	copy(scopes, validOIDCScopes)
+
+	for _, scope := range configuration.CustomScopes {
+		if scope.Name != "" && !utils.IsStringInSlice(scope.Name, scopes) {
+			scopes = append(scopes, scope.Name)
+		}
+	}
+
+	return scopes
+}
+
+// validateOIDCClientIntrospectionScopes ensures the scopes whose claims are included in the introspection responses
+// of a client are known scopes.
+func validateOIDCClientIntrospectionScopes(c int, configuration *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	if len(configuration.Clients[c].Introspection.Scopes) == 0 {
+		return
+	}
+
+	scopes := getOIDCScopes(configuration)
+
+	for _, scope := range configuration.Clients[c].Introspection.Scopes {
+		if !utils.IsStringInSlice(scope, scopes) {
+			validator.Push(fmt.Errorf(errFmtOIDCClientIntrospectionScopeInvalid, configuration.Clients[c].ID, strings.Join(scopes, "', '"), scope))
+		}
+	}
+}
+
+// validateOIDCClientsIntrospectionAllowedClients ensures the clients whose tokens may be introspected by a client are
+// configured clients. This must only be validated once all of the clients are known.
+func validateOIDCClientsIntrospectionAllowedClients(configuration *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	ids := make([]string, len(configuration.Clients))
+
+	for c, client := range configuration.Clients {
+		ids[c] = client.ID
+	}
+
+	for _, client := range configuration.Clients {
+		for _, id := range client.Introspection.AllowedClients {
+			if !utils.IsStringInSlice(id, ids) {
+				validator.Push(fmt.Errorf(errFmtOIDCClientIntrospectionAllowedClientInvalid, client.ID, id))
+			}
+		}
+	}
+}
+
+// validateOIDCClientDefaultScopes ensures the scopes requested on behalf of clients which omit the scope parameter are
+// scopes the client is permitted to request. The scopes are defaulted by validateOIDCClientScopes so this must be
+// validated after them.
+func validateOIDCClientDefaultScopes(c int, configuration *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	client := configuration.Clients[c]
+
+	for _, scope := range client.DefaultScopes {
+		if !utils.IsStringInSlice(scope, client.Scopes) {
+			validator.Push(fmt.Errorf(errFmtOIDCClientDefaultScopesInvalid, client.ID, strings.Join(client.Scopes, "', '"), scope))
+		}
+	}
+}
+
+// validateOIDCClientPreAuthorizedScopes ensures pre-authorized scopes are only configured on first party clients and
+// defaults them to the scopes of the client, so they must be validated after the scopes.
+func validateOIDCClientPreAuthorizedScopes(c int, configuration *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	client := configuration.Clients[c]
+
+	if !client.FirstParty {
+		if len(client.PreAuthorizedScopes) != 0 {
+			validator.Push(fmt.Errorf(errFmtOIDCClientPreAuthorizedScopesNotFirstParty, client.ID))
+		}
+
+		return
+	}
+
+	if len(client.PreAuthorizedScopes) == 0 {
+		configuration.Clients[c].PreAuthorizedScopes = client.Scopes
+		return
+	}
+
+	for _, scope := range client.PreAuthorizedScopes {
+		if !utils.IsStringInSlice(scope, client.Scopes) {
+			validator.Push(fmt.Errorf(errFmtOIDCClientPreAuthorizedScopesInvalid, client.ID, strings.Join(client.Scopes, "', '"), scope))
+		}
+	}
+}
+
+// validateOIDCClientScopeAudiences ensures each scope mapped to audiences is a scope of the client and each audience
+// is one of the audiences of the client, which are the only audiences permitted for the tokens of the client. The
+// scopes are defaulted by validateOIDCClientScopes so this must be validated after them.
+func validateOIDCClientScopeAudiences(c int, configuration *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	client := configuration.Clients[c]
+
+	var scopes []string
+
+	for _, scopeAudience := range client.ScopeAudiences {
+		if !utils.IsStringInSlice(scopeAudience.Scope, client.Scopes) {
+			validator.Push(fmt.Errorf(errFmtOIDCClientScopeAudiencesScopeInvalid, client.ID, strings.Join(client.Scopes, "', '"), scopeAudience.Scope))
+		}
+
+		if utils.IsStringInSlice(scopeAudience.Scope, scopes) {
+			validator.Push(fmt.Errorf(errFmtOIDCClientScopeAudiencesScopeDuplicate, client.ID, scopeAudience.Scope))
+		}
+
+		scopes = append(scopes, scopeAudience.Scope)
+
+		if len(scopeAudience.Audience) == 0 {
+			validator.Push(fmt.Errorf(errFmtOIDCClientScopeAudiencesNoAudience, client.ID, scopeAudience.Scope))
+		}
+
+		for _, audience := range scopeAudience.Audience {
+			if !utils.IsStringInSlice(audience, client.Audience) {
+				validator.Push(fmt.Errorf(errFmtOIDCClientScopeAudiencesAudienceInvalid, client.ID, strings.Join(client.Audience, "', '"), scopeAudience.Scope, audience))
+			}
 		}
 	}
 }
 
+func validateOIDCAllowedTypes(config *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	if len(config.AllowedGrantTypes) == 0 {
+		config.AllowedGrantTypes = schema.DefaultOpenIDConnectConfiguration.AllowedGrantTypes
+	}
+
+	for _, grantType := range config.AllowedGrantTypes {
+		if !utils.IsStringInSlice(grantType, validOIDCAllowedGrantTypes) {
+			validator.Push(fmt.Errorf(errFmtOIDCAllowedInvalidEntry, "allowed_grant_types", strings.Join(validOIDCAllowedGrantTypes, "', '"), grantType))
+		}
+	}
+
+	implicit := utils.IsStringInSlice("implicit", config.AllowedGrantTypes)
+
+	if len(config.AllowedResponseTypes) == 0 {
+		for _, responseType := range schema.DefaultOpenIDConnectConfiguration.AllowedResponseTypes {
+			if implicit || !oidcResponseTypeRequiresImplicit(responseType) {
+				config.AllowedResponseTypes = append(config.AllowedResponseTypes, responseType)
+			}
+		}
+
+		return
+	}
+
+	for _, responseType := range config.AllowedResponseTypes {
+		switch {
+		case !utils.IsStringInSlice(responseType, validOIDCResponseTypes):
+			validator.Push(fmt.Errorf(errFmtOIDCAllowedInvalidEntry, "allowed_response_types", strings.Join(validOIDCResponseTypes, "', '"), responseType))
+		case !implicit && oidcResponseTypeRequiresImplicit(responseType):
+			validator.Push(fmt.Errorf(errFmtOIDCAllowedResponseTypeRequiresImplicit, responseType))
+		}
+	}
+}
+
+// oidcResponseTypeRequiresImplicit returns true if the response type issues tokens from the authorization endpoint
+// which requires the implicit grant type.
+func oidcResponseTypeRequiresImplicit(responseType string) bool {
+	return responseType != "code" && responseType != "none"
+}
+
 func validateOIDCClientGrantTypes(c int, configuration *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
 	if len(configuration.Clients[c].GrantTypes) == 0 {
 		configuration.Clients[c].GrantTypes = schema.DefaultOpenIDConnectClientConfiguration.GrantTypes
@@ -231,19 +621,44 @@ func validateOIDCClientGrantTypes(c int, configuration *schema.OpenIDConnectConf
 	}
 
 	for _, grantType := range configuration.Clients[c].GrantTypes {
-		if !utils.IsStringInSlice(grantType, validOIDCGrantTypes) {
+		switch {
+		case !utils.IsStringInSlice(grantType, validOIDCGrantTypes):
 			validator.Push(fmt.Errorf(
 				errFmtOIDCClientInvalidEntry,
 				configuration.Clients[c].ID, "grant_types", strings.Join(validOIDCGrantTypes, "', '"), grantType))
+		case len(configuration.AllowedGrantTypes) != 0 && !utils.IsStringInSlice(grantType, configuration.AllowedGrantTypes):
+			validator.PushWarning(fmt.Errorf(
+				errFmtOIDCClientTypeNotAllowed,
+				configuration.Clients[c].ID, "grant_types", grantType, "allowed_grant_types"))
 		}
 	}
 }
 
-func validateOIDCClientResponseTypes(c int, configuration *schema.OpenIDConnectConfiguration, _ *schema.StructValidator) {
+func validateOIDCClientResponseTypes(c int, configuration *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
 	if len(configuration.Clients[c].ResponseTypes) == 0 {
 		configuration.Clients[c].ResponseTypes = schema.DefaultOpenIDConnectClientConfiguration.ResponseTypes
 		return
 	}
+
+	for _, responseType := range configuration.Clients[c].ResponseTypes {
+		if len(configuration.AllowedResponseTypes) != 0 && !utils.IsStringInSlice(responseType, configuration.AllowedResponseTypes) {
+			validator.PushWarning(fmt.Errorf(
+				errFmtOIDCClientTypeNotAllowed,
+				configuration.Clients[c].ID, "response_types", responseType, "allowed_response_types"))
+		}
+
+		if utils.IsStringInSlice("code", strings.Fields(responseType)) && !utils.IsStringInSlice("authorization_code", configuration.Clients[c].GrantTypes) {
+			validator.Push(fmt.Errorf(
+				errFmtOIDCClientResponseTypeRequiresGrantType,
+				configuration.Clients[c].ID, responseType, "authorization_code"))
+		}
+
+		if oidcResponseTypeRequiresImplicit(responseType) && !utils.IsStringInSlice("implicit", configuration.Clients[c].GrantTypes) {
+			validator.Push(fmt.Errorf(
+				errFmtOIDCClientResponseTypeRequiresGrantType,
+				configuration.Clients[c].ID, responseType, "implicit"))
+		}
+	}
 }
 
 func validateOIDCClientResponseModes(c int, configuration *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
@@ -270,6 +685,57 @@ func validateOIDDClientUserinfoAlgorithm(c int, configuration *schema.OpenIDConn
 	}
 }
 
+// validateOIDCClientTokenEndpointAuthMethod validates the token endpoint authentication method of a client. Public
+// clients may only use the none method, and the client_secret_jwt method requires plaintext secrets as they're the key
+// the client assertion is signed with.
+func validateOIDCClientTokenEndpointAuthMethod(client schema.OpenIDConnectClientConfiguration, validator *schema.StructValidator) {
+	switch method := client.TokenEndpointAuthMethod; {
+	case method == "":
+		return
+	case !utils.IsStringInSlice(method, validOIDCClientTokenEndpointAuthMethods):
+		validator.Push(fmt.Errorf(errFmtOIDCClientInvalidTokenEndpointAuthMethod,
+			client.ID, strings.Join(validOIDCClientTokenEndpointAuthMethods, ", "), method))
+	case client.Public && method != oidc.ClientAuthMethodNone:
+		validator.Push(fmt.Errorf(errFmtOIDCClientInvalidTokenEndpointAuthMethodPublic, client.ID, method))
+	case !client.Public && method == oidc.ClientAuthMethodNone:
+		validator.Push(fmt.Errorf(errFmtOIDCClientInvalidTokenEndpointAuthMethodConfidential, client.ID))
+	case method == oidc.ClientAuthMethodClientSecretJWT:
+		if oidc.IsClientSecretDigest(client.Secret) {
+			validator.Push(fmt.Errorf(errFmtOIDCClientTokenEndpointAuthMethodSecretDigest, client.ID, "secret"))
+		}
+
+		if oidc.IsClientSecretDigest(client.PreviousSecret) {
+			validator.Push(fmt.Errorf(errFmtOIDCClientTokenEndpointAuthMethodSecretDigest, client.ID, "previous_secret"))
+		}
+	}
+}
+
+// validateOIDCClientPKCE validates and updates the PKCE requirements of a client. Public clients always require PKCE
+// with the S256 challenge method unless another method is configured, and configuring a challenge method requires PKCE.
+func validateOIDCClientPKCE(c int, configuration *schema.OpenIDConnectConfiguration, validator *schema.StructValidator) {
+	client := &configuration.Clients[c]
+
+	switch client.PKCEChallengeMethod {
+	case "":
+		if client.Public {
+			client.PKCEChallengeMethod = oidc.PKCEChallengeMethodSHA256
+		}
+	case oidc.PKCEChallengeMethodSHA256:
+		break
+	case oidc.PKCEChallengeMethodPlain:
+		if !configuration.EnablePKCEPlainChallenge {
+			validator.Push(fmt.Errorf(errFmtOIDCClientPKCEPlainChallengeNotEnabled, client.ID))
+		}
+	default:
+		validator.Push(fmt.Errorf(errFmtOIDCClientInvalidPKCEChallengeMethod,
+			client.ID, strings.Join(validOIDCClientPKCEChallengeMethods, ", "), client.PKCEChallengeMethod))
+	}
+
+	if client.Public || client.PKCEChallengeMethod != "" {
+		client.RequirePKCE = true
+	}
+}
+
 func validateOIDCClientRedirectURIs(client schema.OpenIDConnectClientConfiguration, validator *schema.StructValidator) {
 	for _, redirectURI := range client.RedirectURIs {
 		if redirectURI == oauth2InstalledApp {
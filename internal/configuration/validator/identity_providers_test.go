@@ -135,6 +135,68 @@ func TestShouldRaiseErrorWhenOIDCCORSOriginsHasInvalidValues(t *testing.T) {
 	assert.Equal(t, "https://example.com", config.OIDC.CORS.AllowedOrigins[4].String())
 }
 
+func TestShouldValidateOIDCClientAllowedOrigins(t *testing.T) {
+	validator := schema.NewStructValidator()
+
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			CORS: schema.OpenIDConnectCORSConfiguration{
+				AllowedOrigins: utils.URLsFromStringSlice([]string{"https://example.com"}),
+			},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:             "myclient",
+					Secret:         "jk12nb3klqwmnelqkwenm",
+					Policy:         "two_factor",
+					RedirectURIs:   []string{"https://example.com/oauth2_callback"},
+					AllowedOrigins: utils.URLsFromStringSlice([]string{"https://example.com", "https://app.example.com", "https://app.example.com/path", "https://app.example.com?example=true", "app.example.com", "*"}),
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 4)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'myclient': option 'allowed_origins' contains an invalid value 'https://app.example.com/path' as it has a path: origins must only be scheme, hostname, and an optional port")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'myclient': option 'allowed_origins' contains an invalid value 'https://app.example.com?example=true' as it has a query string: origins must only be scheme, hostname, and an optional port")
+	assert.EqualError(t, validator.Errors()[2], "identity_providers: oidc: client 'myclient': option 'allowed_origins' contains an invalid value 'app.example.com' as it has no scheme or hostname: origins must only be scheme, hostname, and an optional port")
+	assert.EqualError(t, validator.Errors()[3], "identity_providers: oidc: client 'myclient': option 'allowed_origins' contains an invalid value '*' as the wildcard origin is not permitted for clients: origins must only be scheme, hostname, and an optional port")
+
+	// The origins of clients only apply to requests made on behalf of the client so they're not added to the global list.
+	assert.Equal(t, []string{"https://example.com"}, utils.StringSliceFromURLs(config.OIDC.CORS.AllowedOrigins))
+}
+
+func TestShouldNotAddOIDCClientAllowedOriginsToWildcardCORSOrigins(t *testing.T) {
+	validator := schema.NewStructValidator()
+
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			CORS: schema.OpenIDConnectCORSConfiguration{
+				AllowedOrigins: utils.URLsFromStringSlice([]string{"*"}),
+			},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:             "myclient",
+					Secret:         "jk12nb3klqwmnelqkwenm",
+					Policy:         "two_factor",
+					RedirectURIs:   []string{"https://example.com/oauth2_callback"},
+					AllowedOrigins: utils.URLsFromStringSlice([]string{"https://app.example.com"}),
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, []string{"*"}, utils.StringSliceFromURLs(config.OIDC.CORS.AllowedOrigins))
+}
+
 func TestShouldRaiseErrorWhenOIDCServerNoClients(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := &schema.IdentityProvidersConfiguration{
@@ -151,6 +213,44 @@ func TestShouldRaiseErrorWhenOIDCServerNoClients(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], errFmtOIDCNoClientsConfigured)
 }
 
+func TestShouldNotRaiseErrorWhenOIDCServerNoClientsWithClientsPath(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			ClientsPath:      "/config/clients",
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, time.Minute, config.OIDC.ClientsReloadInterval)
+
+	ValidateOpenIDConnectClients(config.OIDC, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], errFmtOIDCNoClientsConfigured)
+}
+
+func TestShouldRaiseErrorWhenOIDCClientsReloadIntervalNegative(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:            "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey:      "key-material",
+			ClientsPath:           "/config/clients",
+			ClientsReloadInterval: -time.Minute,
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: option 'clients_reload_interval' must be 0 or more but it is configured as '-1m0s'")
+}
+
 func TestShouldRaiseErrorWhenOIDCServerClientBadValues(t *testing.T) {
 	mustParseURL := func(u string) url.URL {
 		out, err := url.Parse(u)
@@ -365,18 +465,24 @@ func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadScopes(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'scopes' must only have the values 'openid', 'email', 'profile', 'groups', 'offline_access' but one option is configured as 'bad_scope'")
 }
 
-func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadGrantTypes(t *testing.T) {
+func TestShouldNotRaiseErrorWhenOIDCClientConfiguredWithCustomScopes(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := &schema.IdentityProvidersConfiguration{
 		OIDC: &schema.OpenIDConnectConfiguration{
 			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
 			IssuerPrivateKey: "key-material",
+			CustomScopes: []schema.OpenIDConnectCustomScopeConfiguration{
+				{
+					Name:   "company:hr",
+					Claims: []string{"groups", "email"},
+				},
+			},
 			Clients: []schema.OpenIDConnectClientConfiguration{
 				{
-					ID:         "good_id",
-					Secret:     "good_secret",
-					Policy:     "two_factor",
-					GrantTypes: []string{"bad_grant_type"},
+					ID:     "good_id",
+					Secret: "good_secret",
+					Policy: "two_factor",
+					Scopes: []string{"openid", "company:hr"},
 					RedirectURIs: []string{
 						"https://google.com/callback",
 					},
@@ -387,22 +493,38 @@ func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadGrantTypes(t *testing.T)
 
 	ValidateIdentityProviders(config, validator)
 
-	require.Len(t, validator.Errors(), 1)
-	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'grant_types' must only have the values 'implicit', 'refresh_token', 'authorization_code', 'password', 'client_credentials' but one option is configured as 'bad_grant_type'")
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "company:hr", config.OIDC.CustomScopes[0].Description)
 }
 
-func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadResponseModes(t *testing.T) {
+func TestShouldRaiseErrorWhenOIDCCustomScopesHaveBadValues(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := &schema.IdentityProvidersConfiguration{
 		OIDC: &schema.OpenIDConnectConfiguration{
 			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
 			IssuerPrivateKey: "key-material",
+			CustomScopes: []schema.OpenIDConnectCustomScopeConfiguration{
+				{
+					Name:   "company:hr",
+					Claims: []string{"groups", "salary"},
+				},
+				{
+					Name:   "company:hr",
+					Claims: []string{"groups"},
+				},
+				{
+					Name: "profile",
+				},
+				{
+					Claims: []string{"groups"},
+				},
+			},
 			Clients: []schema.OpenIDConnectClientConfiguration{
 				{
-					ID:            "good_id",
-					Secret:        "good_secret",
-					Policy:        "two_factor",
-					ResponseModes: []string{"bad_responsemode"},
+					ID:     "good_id",
+					Secret: "good_secret",
+					Policy: "two_factor",
+					Scopes: []string{"openid", "company:finance"},
 					RedirectURIs: []string{
 						"https://google.com/callback",
 					},
@@ -413,22 +535,35 @@ func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadResponseModes(t *testing
 
 	ValidateIdentityProviders(config, validator)
 
-	require.Len(t, validator.Errors(), 1)
-	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'response_modes' must only have the values 'form_post', 'query', 'fragment' but one option is configured as 'bad_responsemode'")
+	require.Len(t, validator.Errors(), 6)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: custom_scopes: scope 'company:hr': option 'claims' must only have the values 'groups', 'name', 'preferred_username', 'email', 'email_verified', 'alt_emails' but one option is configured as 'salary'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: custom_scopes: scope 'company:hr': option 'name' must be unique but it's configured more than once")
+	assert.EqualError(t, validator.Errors()[2], "identity_providers: oidc: custom_scopes: scope 'profile': option 'name' must not be the same as one of the standard scopes 'openid', 'email', 'profile', 'groups', 'offline_access'")
+	assert.EqualError(t, validator.Errors()[3], "identity_providers: oidc: custom_scopes: scope 'profile': option 'claims' must have one or more claims configured")
+	assert.EqualError(t, validator.Errors()[4], "identity_providers: oidc: custom_scopes: one or more custom scopes have been configured with an empty name")
+	assert.EqualError(t, validator.Errors()[5], "identity_providers: oidc: client 'good_id': option 'scopes' must only have the values 'openid', 'email', 'profile', 'groups', 'offline_access', 'company:hr' but one option is configured as 'company:finance'")
 }
 
-func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadUserinfoAlg(t *testing.T) {
+func TestShouldSetDefaultsWhenOIDCACRValuesConfigured(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := &schema.IdentityProvidersConfiguration{
 		OIDC: &schema.OpenIDConnectConfiguration{
 			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
 			IssuerPrivateKey: "key-material",
+			ACRValues: []schema.OpenIDConnectACRValueConfiguration{
+				{
+					Value:  "urn:example:loa:1",
+					Policy: "one_factor",
+				},
+				{
+					Value: "urn:example:loa:2",
+				},
+			},
 			Clients: []schema.OpenIDConnectClientConfiguration{
 				{
-					ID:                       "good_id",
-					Secret:                   "good_secret",
-					Policy:                   "two_factor",
-					UserinfoSigningAlgorithm: "rs256",
+					ID:     "good_id",
+					Secret: "good_secret",
+					Policy: "one_factor",
 					RedirectURIs: []string{
 						"https://google.com/callback",
 					},
@@ -439,17 +574,32 @@ func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadUserinfoAlg(t *testing.T
 
 	ValidateIdentityProviders(config, validator)
 
-	require.Len(t, validator.Errors(), 1)
-	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'userinfo_signing_algorithm' must be one of 'none, RS256' but it is configured as 'rs256'")
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "one_factor", config.OIDC.ACRValues[0].Policy)
+	assert.Equal(t, "two_factor", config.OIDC.ACRValues[1].Policy)
+	assert.Equal(t, "ignore", config.OIDC.UnknownACRValues)
 }
 
-func TestValidateIdentityProvidersShouldRaiseWarningOnSecurityIssue(t *testing.T) {
+func TestShouldRaiseErrorWhenOIDCACRValuesHaveBadValues(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := &schema.IdentityProvidersConfiguration{
 		OIDC: &schema.OpenIDConnectConfiguration{
-			HMACSecret:              "abc",
-			IssuerPrivateKey:        "abc",
-			MinimumParameterEntropy: 1,
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			UnknownACRValues: "reject",
+			ACRValues: []schema.OpenIDConnectACRValueConfiguration{
+				{
+					Value:  "urn:example:loa:1",
+					Policy: "bypass",
+				},
+				{
+					Value:  "urn:example:loa:1",
+					Policy: "one_factor",
+				},
+				{
+					Policy: "two_factor",
+				},
+			},
 			Clients: []schema.OpenIDConnectClientConfiguration{
 				{
 					ID:     "good_id",
@@ -465,35 +615,27 @@ func TestValidateIdentityProvidersShouldRaiseWarningOnSecurityIssue(t *testing.T
 
 	ValidateIdentityProviders(config, validator)
 
-	assert.Len(t, validator.Errors(), 0)
-	require.Len(t, validator.Warnings(), 1)
-
-	assert.EqualError(t, validator.Warnings()[0], "openid connect provider: SECURITY ISSUE - minimum parameter entropy is configured to an unsafe value, it should be above 8 but it's configured to 1")
+	require.Len(t, validator.Errors(), 4)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: option 'unknown_acr_values' must be 'ignore' or 'error' but it is configured as 'reject'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: acr_values: value 'urn:example:loa:1': option 'policy' must be 'one_factor' or 'two_factor' but it is configured as 'bypass'")
+	assert.EqualError(t, validator.Errors()[2], "identity_providers: oidc: acr_values: value 'urn:example:loa:1': option 'value' must be unique but it's configured more than once")
+	assert.EqualError(t, validator.Errors()[3], "identity_providers: oidc: acr_values: one or more acr values have been configured with an empty value")
 }
 
-func TestValidateIdentityProvidersShouldRaiseErrorsOnInvalidClientTypes(t *testing.T) {
+func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadGrantTypes(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := &schema.IdentityProvidersConfiguration{
 		OIDC: &schema.OpenIDConnectConfiguration{
-			HMACSecret:       "hmac1",
-			IssuerPrivateKey: "key2",
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
 			Clients: []schema.OpenIDConnectClientConfiguration{
 				{
-					ID:     "client-with-invalid-secret",
-					Secret: "a-secret",
-					Public: true,
-					Policy: "two_factor",
-					RedirectURIs: []string{
-						"https://localhost",
-					},
-				},
-				{
-					ID:     "client-with-bad-redirect-uri",
-					Secret: "a-secret",
-					Public: false,
-					Policy: "two_factor",
+					ID:         "good_id",
+					Secret:     "good_secret",
+					Policy:     "two_factor",
+					GrantTypes: []string{"bad_grant_type"},
 					RedirectURIs: []string{
-						oauth2InstalledApp,
+						"https://google.com/callback",
 					},
 				},
 			},
@@ -502,42 +644,35 @@ func TestValidateIdentityProvidersShouldRaiseErrorsOnInvalidClientTypes(t *testi
 
 	ValidateIdentityProviders(config, validator)
 
-	require.Len(t, validator.Errors(), 2)
-	assert.Len(t, validator.Warnings(), 0)
-
-	assert.EqualError(t, validator.Errors()[0], fmt.Sprintf(errFmtOIDCClientPublicInvalidSecret, "client-with-invalid-secret"))
-	assert.EqualError(t, validator.Errors()[1], fmt.Sprintf(errFmtOIDCClientRedirectURIPublic, "client-with-bad-redirect-uri", oauth2InstalledApp))
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'grant_types' must only have the values 'implicit', 'refresh_token', 'authorization_code', 'password', 'client_credentials' but one option is configured as 'bad_grant_type'")
 }
 
-func TestValidateIdentityProvidersShouldNotRaiseErrorsOnValidPublicClients(t *testing.T) {
+func TestShouldRaiseErrorWhenOIDCClientResponseTypesRequireMissingGrantTypes(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := &schema.IdentityProvidersConfiguration{
 		OIDC: &schema.OpenIDConnectConfiguration{
-			HMACSecret:       "hmac1",
-			IssuerPrivateKey: "key2",
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
 			Clients: []schema.OpenIDConnectClientConfiguration{
 				{
-					ID:     "installed-app-client",
-					Public: true,
-					Policy: "two_factor",
-					RedirectURIs: []string{
-						oauth2InstalledApp,
-					},
-				},
-				{
-					ID:     "client-with-https-scheme",
-					Public: true,
-					Policy: "two_factor",
+					ID:            "hybrid",
+					Secret:        "good_secret",
+					Policy:        "two_factor",
+					GrantTypes:    []string{"authorization_code"},
+					ResponseTypes: []string{"code", "code id_token"},
 					RedirectURIs: []string{
-						"https://localhost:9000",
+						"https://google.com/callback",
 					},
 				},
 				{
-					ID:     "client-with-loopback",
-					Public: true,
-					Policy: "two_factor",
+					ID:            "implicit",
+					Secret:        "good_secret",
+					Policy:        "two_factor",
+					GrantTypes:    []string{"implicit"},
+					ResponseTypes: []string{"id_token", "code token id_token"},
 					RedirectURIs: []string{
-						"http://127.0.0.1",
+						"https://google.com/callback",
 					},
 				},
 			},
@@ -546,46 +681,26 @@ func TestValidateIdentityProvidersShouldNotRaiseErrorsOnValidPublicClients(t *te
 
 	ValidateIdentityProviders(config, validator)
 
-	assert.Len(t, validator.Errors(), 0)
-	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'hybrid': option 'response_types' contains the value 'code id_token' which requires the 'implicit' grant type but it's not included in option 'grant_types'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'implicit': option 'response_types' contains the value 'code token id_token' which requires the 'authorization_code' grant type but it's not included in option 'grant_types'")
 }
 
-func TestValidateIdentityProvidersShouldSetDefaultValues(t *testing.T) {
+func TestShouldRaiseErrorWhenOIDCAllowedTypesHaveBadValues(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := &schema.IdentityProvidersConfiguration{
 		OIDC: &schema.OpenIDConnectConfiguration{
-			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
-			IssuerPrivateKey: "../../../README.md",
+			HMACSecret:           "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey:     "key-material",
+			AllowedGrantTypes:    []string{"authorization_code", "password"},
+			AllowedResponseTypes: []string{"code", "token", "bad"},
 			Clients: []schema.OpenIDConnectClientConfiguration{
 				{
-					ID:     "a-client",
-					Secret: "a-client-secret",
-					RedirectURIs: []string{
-						"https://google.com",
-					},
-				},
-				{
-					ID:                       "b-client",
-					Description:              "Normal Description",
-					Secret:                   "b-client-secret",
-					Policy:                   policyOneFactor,
-					UserinfoSigningAlgorithm: "RS256",
+					ID:     "good_id",
+					Secret: "good_secret",
+					Policy: "two_factor",
 					RedirectURIs: []string{
-						"https://google.com",
-					},
-					Scopes: []string{
-						"groups",
-					},
-					GrantTypes: []string{
-						"refresh_token",
-					},
-					ResponseTypes: []string{
-						"token",
-						"code",
-					},
-					ResponseModes: []string{
-						"form_post",
-						"fragment",
+						"https://google.com/callback",
 					},
 				},
 			},
@@ -594,19 +709,637 @@ func TestValidateIdentityProvidersShouldSetDefaultValues(t *testing.T) {
 
 	ValidateIdentityProviders(config, validator)
 
-	assert.Len(t, validator.Warnings(), 0)
-	assert.Len(t, validator.Errors(), 0)
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: option 'allowed_grant_types' must only have the values 'authorization_code', 'implicit', 'refresh_token', 'client_credentials' but one option is configured as 'password'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: option 'allowed_response_types' contains the value 'token' which requires the 'implicit' grant type but it's not included in option 'allowed_grant_types'")
+	assert.EqualError(t, validator.Errors()[2], "identity_providers: oidc: option 'allowed_response_types' must only have the values 'code', 'token', 'id_token', 'code token', 'code id_token', 'token id_token', 'code token id_token', 'none' but one option is configured as 'bad'")
+}
 
-	// Assert Clients[0] Policy is set to the default, and the default doesn't override Clients[1]'s Policy.
-	assert.Equal(t, policyTwoFactor, config.OIDC.Clients[0].Policy)
-	assert.Equal(t, policyOneFactor, config.OIDC.Clients[1].Policy)
+func TestShouldSetOIDCAllowedTypesDefaults(t *testing.T) {
+	testCases := []struct {
+		name                     string
+		grantTypes               []string
+		expectedGrantTypes       []string
+		expectedResponseTypes    []string
+		expectedWarningsForTypes []string
+	}{
+		{
+			"ShouldDefaultToAllSupportedTypes",
+			nil,
+			[]string{"authorization_code", "implicit", "refresh_token", "client_credentials"},
+			[]string{"code", "token", "id_token", "code token", "code id_token", "token id_token", "code token id_token", "none"},
+			nil,
+		},
+		{
+			"ShouldExcludeImplicitResponseTypesWhenImplicitNotAllowed",
+			[]string{"authorization_code", "refresh_token"},
+			[]string{"authorization_code", "refresh_token"},
+			[]string{"code", "none"},
+			[]string{"grant_types", "implicit", "response_types", "token"},
+		},
+	}
 
-	assert.Equal(t, "none", config.OIDC.Clients[0].UserinfoSigningAlgorithm)
-	assert.Equal(t, "RS256", config.OIDC.Clients[1].UserinfoSigningAlgorithm)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.IdentityProvidersConfiguration{
+				OIDC: &schema.OpenIDConnectConfiguration{
+					HMACSecret:        "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+					IssuerPrivateKey:  "key-material",
+					AllowedGrantTypes: tc.grantTypes,
+					Clients: []schema.OpenIDConnectClientConfiguration{
+						{
+							ID:            "good_id",
+							Secret:        "good_secret",
+							Policy:        "two_factor",
+							GrantTypes:    []string{"authorization_code", "implicit"},
+							ResponseTypes: []string{"code", "token"},
+							RedirectURIs: []string{
+								"https://google.com/callback",
+							},
+						},
+					},
+				},
+			}
 
-	// Assert Clients[0] Description is set to the Clients[0] ID, and Clients[1]'s Description is not overridden.
-	assert.Equal(t, config.OIDC.Clients[0].ID, config.OIDC.Clients[0].Description)
-	assert.Equal(t, "Normal Description", config.OIDC.Clients[1].Description)
+			ValidateIdentityProviders(config, validator)
+
+			assert.Len(t, validator.Errors(), 0)
+			assert.Equal(t, tc.expectedGrantTypes, config.OIDC.AllowedGrantTypes)
+			assert.Equal(t, tc.expectedResponseTypes, config.OIDC.AllowedResponseTypes)
+
+			if tc.expectedWarningsForTypes == nil {
+				assert.Len(t, validator.Warnings(), 0)
+			} else {
+				require.Len(t, validator.Warnings(), 2)
+				assert.EqualError(t, validator.Warnings()[0], fmt.Sprintf("identity_providers: oidc: client 'good_id': option '%s' contains the value '%s' which is not included in option 'allowed_grant_types' and will be rejected", tc.expectedWarningsForTypes[0], tc.expectedWarningsForTypes[1]))
+				assert.EqualError(t, validator.Warnings()[1], fmt.Sprintf("identity_providers: oidc: client 'good_id': option '%s' contains the value '%s' which is not included in option 'allowed_response_types' and will be rejected", tc.expectedWarningsForTypes[2], tc.expectedWarningsForTypes[3]))
+			}
+		})
+	}
+}
+
+func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadResponseModes(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:            "good_id",
+					Secret:        "good_secret",
+					Policy:        "two_factor",
+					ResponseModes: []string{"bad_responsemode"},
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'response_modes' must only have the values 'form_post', 'query', 'fragment' but one option is configured as 'bad_responsemode'")
+}
+
+func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadUserinfoAlg(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:                       "good_id",
+					Secret:                   "good_secret",
+					Policy:                   "two_factor",
+					UserinfoSigningAlgorithm: "rs256",
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'userinfo_signing_algorithm' must be one of 'none, RS256' but it is configured as 'rs256'")
+}
+
+func TestShouldValidateOIDCClientPKCE(t *testing.T) {
+	testCases := []struct {
+		name            string
+		public          bool
+		requirePKCE     bool
+		method          string
+		enablePlain     bool
+		expectedRequire bool
+		expectedMethod  string
+		expectedErr     string
+	}{
+		{"ShouldNotRequirePKCEForConfidentialClientsByDefault", false, false, "", false, false, "", ""},
+		{"ShouldAllowConfidentialClientsToRequirePKCE", false, true, "", false, true, "", ""},
+		{"ShouldRequireSHA256ForPublicClientsByDefault", true, false, "", false, true, "S256", ""},
+		{"ShouldRequirePKCEWhenMethodConfigured", false, false, "S256", false, true, "S256", ""},
+		{"ShouldAllowPlainWhenEnabled", true, false, "plain", true, true, "plain", ""},
+		{"ShouldRaiseErrorOnPlainWhenNotEnabled", false, false, "plain", false, true, "plain",
+			"identity_providers: oidc: client 'good_id': option 'pkce_challenge_method' is configured as 'plain' but option 'enable_pkce_plain_challenge' is false"},
+		{"ShouldRaiseErrorOnInvalidMethod", false, true, "s256", false, true, "s256",
+			"identity_providers: oidc: client 'good_id': option 'pkce_challenge_method' must be one of 'S256, plain' but it is configured as 's256'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.IdentityProvidersConfiguration{
+				OIDC: &schema.OpenIDConnectConfiguration{
+					HMACSecret:               "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+					IssuerPrivateKey:         "key-material",
+					EnablePKCEPlainChallenge: tc.enablePlain,
+					Clients: []schema.OpenIDConnectClientConfiguration{
+						{
+							ID:                  "good_id",
+							Public:              tc.public,
+							RequirePKCE:         tc.requirePKCE,
+							PKCEChallengeMethod: tc.method,
+							Policy:              "two_factor",
+							RedirectURIs: []string{
+								"https://google.com/callback",
+							},
+						},
+					},
+				},
+			}
+
+			if !tc.public {
+				config.OIDC.Clients[0].Secret = "good_secret"
+			}
+
+			ValidateIdentityProviders(config, validator)
+
+			assert.Len(t, validator.Warnings(), 0)
+
+			if tc.expectedErr == "" {
+				assert.Len(t, validator.Errors(), 0)
+			} else {
+				require.Len(t, validator.Errors(), 1)
+				assert.EqualError(t, validator.Errors()[0], tc.expectedErr)
+			}
+
+			assert.Equal(t, tc.expectedRequire, config.OIDC.Clients[0].RequirePKCE)
+			assert.Equal(t, tc.expectedMethod, config.OIDC.Clients[0].PKCEChallengeMethod)
+		})
+	}
+}
+
+func TestShouldValidateOIDCClientTokenEndpointAuthMethod(t *testing.T) {
+	testCases := []struct {
+		name           string
+		public         bool
+		method         string
+		secret         string
+		previousSecret string
+		expectedErrs   []string
+	}{
+		{"ShouldAllowNoMethod", false, "", "good_secret", "", nil},
+		{"ShouldAllowClientSecretBasic", false, "client_secret_basic", "good_secret", "", nil},
+		{"ShouldAllowClientSecretPost", false, "client_secret_post", "good_secret", "", nil},
+		{"ShouldAllowClientSecretJWT", false, "client_secret_jwt", "$plaintext$good_secret", "good_previous_secret", nil},
+		{"ShouldAllowNoneForPublicClients", true, "none", "", "", nil},
+		{"ShouldRaiseErrorOnInvalidMethod", false, "private_key_jwt", "good_secret", "", []string{
+			"identity_providers: oidc: client 'good_id': option 'token_endpoint_auth_method' must be one of 'client_secret_basic, client_secret_post, client_secret_jwt, none' but it is configured as 'private_key_jwt'",
+		}},
+		{"ShouldRaiseErrorOnSecretMethodForPublicClients", true, "client_secret_post", "", "", []string{
+			"identity_providers: oidc: client 'good_id': option 'token_endpoint_auth_method' must be 'none' when option 'public' is true but it is configured as 'client_secret_post'",
+		}},
+		{"ShouldRaiseErrorOnNoneForConfidentialClients", false, "none", "good_secret", "", []string{
+			"identity_providers: oidc: client 'good_id': option 'token_endpoint_auth_method' must not be 'none' when option 'public' is false",
+		}},
+		{"ShouldRaiseErrorOnClientSecretJWTWithDigests", false, "client_secret_jwt", "$2b$04$/XqS0fVvHA.3NTiH7rkb/uK3wlsttD1VTS5dgbNtdR2QDRLS8i1LK", "$2b$04$/XqS0fVvHA.3NTiH7rkb/uK3wlsttD1VTS5dgbNtdR2QDRLS8i1LK", []string{
+			"identity_providers: oidc: client 'good_id': option 'secret' must be a plaintext secret when option 'token_endpoint_auth_method' is 'client_secret_jwt' as it's used to verify the signature of the client assertion",
+			"identity_providers: oidc: client 'good_id': option 'previous_secret' must be a plaintext secret when option 'token_endpoint_auth_method' is 'client_secret_jwt' as it's used to verify the signature of the client assertion",
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.IdentityProvidersConfiguration{
+				OIDC: &schema.OpenIDConnectConfiguration{
+					HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+					IssuerPrivateKey: "key-material",
+					Clients: []schema.OpenIDConnectClientConfiguration{
+						{
+							ID:                      "good_id",
+							Public:                  tc.public,
+							Secret:                  tc.secret,
+							TokenEndpointAuthMethod: tc.method,
+							Policy:                  "two_factor",
+							RedirectURIs: []string{
+								"https://google.com/callback",
+							},
+						},
+					},
+				},
+			}
+
+			if tc.previousSecret != "" {
+				config.OIDC.Clients[0].PreviousSecret = tc.previousSecret
+				config.OIDC.Clients[0].PreviousSecretExpiresAt = time.Now().Add(time.Hour)
+			}
+
+			ValidateIdentityProviders(config, validator)
+
+			assert.Len(t, validator.Warnings(), 0)
+			require.Len(t, validator.Errors(), len(tc.expectedErrs))
+
+			for i, expected := range tc.expectedErrs {
+				assert.EqualError(t, validator.Errors()[i], expected)
+			}
+		})
+	}
+}
+
+func TestShouldValidateOIDCClientSecrets(t *testing.T) {
+	expiresAt := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		public         bool
+		secret         string
+		previousSecret string
+		expiresAt      time.Time
+		expectedErr    string
+	}{
+		{"ShouldAllowPlainTextSecret", false, "good_secret", "", time.Time{}, ""},
+		{"ShouldAllowPBKDF2Digest", false, "$pbkdf2-sha512$310000$c8p78n7pUMln0jzvd4aK4Q$JNRBzwAo0ek5qKn50cFzzvE9RXV88h1wJn5KGiHrD0YKtZaR/nCb2CJPOsKaPK0hjf.9yHxzQGZziziccp6Yng", "", time.Time{}, ""},
+		{"ShouldAllowBCryptDigest", false, "$2b$12$BqKrdI3nUBR2xQQx5bS1JuLYKSqJbPdXe2LDEZr7hkqBcSOkJ1N/i", "", time.Time{}, ""},
+		{"ShouldAllowPreviousSecret", false, "good_secret", "$plaintext$old_secret", expiresAt, ""},
+		{"ShouldRaiseErrorOnInvalidPBKDF2Digest", false, "$pbkdf2-sha512$abc$c8p78n7pUMln0jzvd4aK4Q$JNRBzwAo0ek5qKn50cFzzvE9RXV88h1wJn5KGiHrD0YK", "", time.Time{},
+			"identity_providers: oidc: client 'good_id': option 'secret' is not a valid digest: the pbkdf2 digest iterations must be a number more than 0 but it is 'abc'"},
+		{"ShouldRaiseErrorOnInvalidPreviousSecretDigest", false, "good_secret", "$pbkdf2$1000$salt", expiresAt,
+			"identity_providers: oidc: client 'good_id': option 'previous_secret' is not a valid digest: the pbkdf2 digest must have 3 parts after the algorithm but it has 2"},
+		{"ShouldRaiseErrorOnPreviousSecretWithoutExpiresAt", false, "good_secret", "old_secret", time.Time{},
+			"identity_providers: oidc: client 'good_id': option 'previous_secret_expires_at' is required when option 'previous_secret' is configured"},
+		{"ShouldRaiseErrorOnExpiresAtWithoutPreviousSecret", false, "good_secret", "", expiresAt,
+			"identity_providers: oidc: client 'good_id': option 'previous_secret' is required when option 'previous_secret_expires_at' is configured"},
+		{"ShouldRaiseErrorOnPublicClientWithPreviousSecret", true, "", "old_secret", expiresAt,
+			"identity_providers: oidc: client 'good_id': option 'previous_secret' is required to be empty when option 'public' is true"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.IdentityProvidersConfiguration{
+				OIDC: &schema.OpenIDConnectConfiguration{
+					HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+					IssuerPrivateKey: "key-material",
+					Clients: []schema.OpenIDConnectClientConfiguration{
+						{
+							ID:                      "good_id",
+							Secret:                  tc.secret,
+							Public:                  tc.public,
+							PreviousSecret:          tc.previousSecret,
+							PreviousSecretExpiresAt: tc.expiresAt,
+							Policy:                  "two_factor",
+							RedirectURIs: []string{
+								"https://google.com/callback",
+							},
+						},
+					},
+				},
+			}
+
+			ValidateIdentityProviders(config, validator)
+
+			assert.Len(t, validator.Warnings(), 0)
+
+			if tc.expectedErr == "" {
+				assert.Len(t, validator.Errors(), 0)
+
+				return
+			}
+
+			require.Len(t, validator.Errors(), 1)
+			assert.EqualError(t, validator.Errors()[0], tc.expectedErr)
+
+			// The values of the secrets must never be included in the errors as they're logged.
+			for _, secret := range []string{tc.secret, tc.previousSecret} {
+				if secret != "" {
+					assert.NotContains(t, validator.Errors()[0].Error(), secret)
+				}
+			}
+		})
+	}
+}
+
+func TestShouldValidateOIDCClientPreAuthorizedScopes(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:           "first-party",
+					Secret:       "good_secret",
+					FirstParty:   true,
+					Scopes:       []string{"openid", "profile"},
+					RedirectURIs: []string{"https://google.com/callback"},
+				},
+				{
+					ID:                  "first-party-subset",
+					Secret:              "good_secret",
+					FirstParty:          true,
+					Scopes:              []string{"openid", "profile", "groups"},
+					PreAuthorizedScopes: []string{"openid", "email"},
+					RedirectURIs:        []string{"https://google.com/callback"},
+				},
+				{
+					ID:                  "third-party",
+					Secret:              "good_secret",
+					PreAuthorizedScopes: []string{"openid"},
+					RedirectURIs:        []string{"https://google.com/callback"},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'first-party-subset': option 'pre_authorized_scopes' must only have the values of option 'scopes' 'openid', 'profile', 'groups' but one option is configured as 'email'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'third-party': option 'pre_authorized_scopes' must only be configured when option 'first_party' is true")
+
+	assert.Equal(t, []string{"openid", "profile"}, config.OIDC.Clients[0].PreAuthorizedScopes)
+}
+
+func TestShouldValidateOIDCClientDefaultScopes(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:            "good",
+					Secret:        "good_secret",
+					DefaultScopes: []string{"openid", "profile"},
+					RedirectURIs:  []string{"https://google.com/callback"},
+				},
+				{
+					ID:            "bad",
+					Secret:        "good_secret",
+					Scopes:        []string{"openid", "profile"},
+					DefaultScopes: []string{"openid", "groups"},
+					RedirectURIs:  []string{"https://google.com/callback"},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'bad': option 'default_scopes' must only have the values of option 'scopes' 'openid', 'profile' but one option is configured as 'groups'")
+}
+
+func TestValidateIdentityProvidersShouldRaiseWarningOnSecurityIssue(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:              "abc",
+			IssuerPrivateKey:        "abc",
+			MinimumParameterEntropy: 1,
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "good_id",
+					Secret: "good_secret",
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	require.Len(t, validator.Warnings(), 1)
+
+	assert.EqualError(t, validator.Warnings()[0], "openid connect provider: SECURITY ISSUE - minimum parameter entropy is configured to an unsafe value, it should be above 8 but it's configured to 1")
+}
+
+func TestValidateIdentityProvidersShouldRaiseErrorsOnInvalidClientTypes(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "hmac1",
+			IssuerPrivateKey: "key2",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "client-with-invalid-secret",
+					Secret: "a-secret",
+					Public: true,
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"https://localhost",
+					},
+				},
+				{
+					ID:     "client-with-bad-redirect-uri",
+					Secret: "a-secret",
+					Public: false,
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						oauth2InstalledApp,
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.Len(t, validator.Warnings(), 0)
+
+	assert.EqualError(t, validator.Errors()[0], fmt.Sprintf(errFmtOIDCClientPublicInvalidSecret, "client-with-invalid-secret"))
+	assert.EqualError(t, validator.Errors()[1], fmt.Sprintf(errFmtOIDCClientRedirectURIPublic, "client-with-bad-redirect-uri", oauth2InstalledApp))
+}
+
+func TestShouldSetOIDCTokenEndpointDefaults(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "hmac1",
+			IssuerPrivateKey: "key2",
+			TokenEndpoint: schema.OpenIDConnectTokenEndpointConfiguration{
+				ClientRateLimit: 10,
+			},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:           "good_id",
+					Secret:       "good_secret",
+					Policy:       "two_factor",
+					RedirectURIs: []string{"https://google.com/callback"},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, validator.Warnings(), 0)
+
+	assert.Equal(t, schema.OpenIDConnectTokenEndpointConfiguration{
+		MaxRequestBodySize:        schema.DefaultOpenIDConnectConfiguration.TokenEndpoint.MaxRequestBodySize,
+		RateLimit:                 schema.DefaultOpenIDConnectConfiguration.TokenEndpoint.RateLimit,
+		ClientRateLimit:           10,
+		FirstPartyClientRateLimit: schema.DefaultOpenIDConnectConfiguration.TokenEndpoint.FirstPartyClientRateLimit,
+		RateLimitPeriod:           time.Minute,
+	}, config.OIDC.TokenEndpoint)
+}
+
+func TestShouldRaiseErrorWhenOIDCTokenEndpointHasNegativeValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "hmac1",
+			IssuerPrivateKey: "key2",
+			TokenEndpoint: schema.OpenIDConnectTokenEndpointConfiguration{
+				MaxRequestBodySize:        -1,
+				FirstPartyClientRateLimit: -5,
+				RateLimitPeriod:           -time.Second,
+			},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:           "good_id",
+					Secret:       "good_secret",
+					Policy:       "two_factor",
+					RedirectURIs: []string{"https://google.com/callback"},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 3)
+
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: token_endpoint: option 'max_request_body_size' must be more than 0 but it is configured as '-1'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: token_endpoint: option 'first_party_client_rate_limit' must be more than 0 but it is configured as '-5'")
+	assert.EqualError(t, validator.Errors()[2], "identity_providers: oidc: token_endpoint: option 'rate_limit_period' must be more than 0 but it is configured as '-1s'")
+}
+
+func TestValidateIdentityProvidersShouldNotRaiseErrorsOnValidPublicClients(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "hmac1",
+			IssuerPrivateKey: "key2",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "installed-app-client",
+					Public: true,
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						oauth2InstalledApp,
+					},
+				},
+				{
+					ID:     "client-with-https-scheme",
+					Public: true,
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"https://localhost:9000",
+					},
+				},
+				{
+					ID:     "client-with-loopback",
+					Public: true,
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"http://127.0.0.1",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, validator.Warnings(), 0)
+}
+
+func TestValidateIdentityProvidersShouldSetDefaultValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "../../../README.md",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "a-client",
+					Secret: "a-client-secret",
+					RedirectURIs: []string{
+						"https://google.com",
+					},
+				},
+				{
+					ID:                       "b-client",
+					Description:              "Normal Description",
+					Secret:                   "b-client-secret",
+					Policy:                   policyOneFactor,
+					UserinfoSigningAlgorithm: "RS256",
+					RedirectURIs: []string{
+						"https://google.com",
+					},
+					Scopes: []string{
+						"groups",
+					},
+					GrantTypes: []string{
+						"refresh_token",
+						"implicit",
+						"authorization_code",
+					},
+					ResponseTypes: []string{
+						"token",
+						"code",
+					},
+					ResponseModes: []string{
+						"form_post",
+						"fragment",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+
+	// Assert Clients[0] Policy is set to the default, and the default doesn't override Clients[1]'s Policy.
+	assert.Equal(t, policyTwoFactor, config.OIDC.Clients[0].Policy)
+	assert.Equal(t, policyOneFactor, config.OIDC.Clients[1].Policy)
+
+	assert.Equal(t, "none", config.OIDC.Clients[0].UserinfoSigningAlgorithm)
+	assert.Equal(t, "RS256", config.OIDC.Clients[1].UserinfoSigningAlgorithm)
+
+	// Assert Clients[0] Description is set to the Clients[0] ID, and Clients[1]'s Description is not overridden.
+	assert.Equal(t, config.OIDC.Clients[0].ID, config.OIDC.Clients[0].Description)
+	assert.Equal(t, "Normal Description", config.OIDC.Clients[1].Description)
 
 	// Assert Clients[0] ends up configured with the default Scopes.
 	require.Len(t, config.OIDC.Clients[0].Scopes, 4)
@@ -626,8 +1359,10 @@ func TestValidateIdentityProvidersShouldSetDefaultValues(t *testing.T) {
 	assert.Equal(t, "authorization_code", config.OIDC.Clients[0].GrantTypes[1])
 
 	// Assert Clients[1] ends up configured with only the configured GrantTypes.
-	require.Len(t, config.OIDC.Clients[1].GrantTypes, 1)
+	require.Len(t, config.OIDC.Clients[1].GrantTypes, 3)
 	assert.Equal(t, "refresh_token", config.OIDC.Clients[1].GrantTypes[0])
+	assert.Equal(t, "implicit", config.OIDC.Clients[1].GrantTypes[1])
+	assert.Equal(t, "authorization_code", config.OIDC.Clients[1].GrantTypes[2])
 
 	// Assert Clients[0] ends up configured with the default ResponseTypes.
 	require.Len(t, config.OIDC.Clients[0].ResponseTypes, 1)
@@ -654,6 +1389,82 @@ func TestValidateIdentityProvidersShouldSetDefaultValues(t *testing.T) {
 	assert.Equal(t, time.Minute, config.OIDC.AuthorizeCodeLifespan)
 	assert.Equal(t, time.Hour, config.OIDC.IDTokenLifespan)
 	assert.Equal(t, time.Minute*90, config.OIDC.RefreshTokenLifespan)
+	assert.Equal(t, time.Hour*24, config.OIDC.KeyRotationGracePeriod)
+	assert.Equal(t, time.Second*5, config.OIDC.ClockSkew)
+}
+
+func TestShouldRaiseErrorWhenOIDCClockSkewIsOutOfRange(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     time.Duration
+		expected string
+	}{
+		{"ShouldRaiseErrorOnNegative", -time.Second, "identity_providers: oidc: option 'clock_skew' must be between 0 and 5m0s but it is configured as '-1s'"},
+		{"ShouldRaiseErrorAboveMaximum", time.Minute * 10, "identity_providers: oidc: option 'clock_skew' must be between 0 and 5m0s but it is configured as '10m0s'"},
+		{"ShouldAllowMaximum", time.Minute * 5, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.IdentityProvidersConfiguration{
+				OIDC: &schema.OpenIDConnectConfiguration{
+					HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+					IssuerPrivateKey: "key-material",
+					ClockSkew:        tc.have,
+					Clients: []schema.OpenIDConnectClientConfiguration{
+						{
+							ID:     "example",
+							Secret: "example",
+						},
+					},
+				},
+			}
+
+			ValidateIdentityProviders(config, validator)
+
+			if tc.expected == "" {
+				assert.Len(t, validator.Errors(), 0)
+				assert.Equal(t, tc.have, config.OIDC.ClockSkew)
+			} else {
+				require.Len(t, validator.Errors(), 1)
+				assert.EqualError(t, validator.Errors()[0], tc.expected)
+			}
+		})
+	}
+}
+
+func TestShouldRaiseErrorWhenOIDCIssuerPrivateKeysHaveBadValues(t *testing.T) {
+	activate := time.Unix(1700000000, 0).UTC()
+
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:             "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey:       "key-material",
+			KeyRotationGracePeriod: -time.Minute,
+			IssuerPrivateKeys: []schema.OpenIDConnectIssuerPrivateKeyConfiguration{
+				{Key: "key-material-2", ActivateAt: activate},
+				{ActivateAt: activate.Add(time.Hour)},
+				{Key: "key-material-3", ActivateAt: activate, ExpiresAt: activate.Add(time.Hour)},
+				{Key: "key-material-4", ExpiresAt: activate.Add(time.Hour)},
+			},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "example",
+					Secret: "example",
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 4)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: option 'key_rotation_grace_period' must be 0 or more but it is configured as '-1m0s'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: issuer_private_keys: key #2: option 'key' is required")
+	assert.EqualError(t, validator.Errors()[2], "identity_providers: oidc: issuer_private_keys: key #3: option 'expires_at' can't be configured with option 'activate_at' as scheduled keys are retired after option 'key_rotation_grace_period'")
+	assert.EqualError(t, validator.Errors()[3], "identity_providers: oidc: issuer_private_keys: key #3: option 'activate_at' must be unique but it's configured as '2023-11-14T22:13:20Z' on more than one key")
 }
 
 // All valid schemes are supported as defined in https://datatracker.ietf.org/doc/html/rfc8252#section-7.1
@@ -691,3 +1502,112 @@ func TestValidateOIDCClientRedirectURIsSupportingPrivateUseURISchemes(t *testing
 		})
 	})
 }
+
+func TestShouldValidateOIDCClientScopeAudiences(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:           "good",
+					Secret:       "good_secret",
+					Scopes:       []string{"openid", "groups"},
+					Audience:     []string{"https://api.example.com", "https://other.example.com"},
+					RedirectURIs: []string{"https://google.com/callback"},
+					ScopeAudiences: []schema.OpenIDConnectClientScopeAudience{
+						{Scope: "groups", Audience: []string{"https://api.example.com", "https://other.example.com"}},
+					},
+				},
+				{
+					ID:           "bad",
+					Secret:       "good_secret",
+					Scopes:       []string{"openid", "groups"},
+					Audience:     []string{"https://api.example.com"},
+					RedirectURIs: []string{"https://google.com/callback"},
+					ScopeAudiences: []schema.OpenIDConnectClientScopeAudience{
+						{Scope: "groups", Audience: []string{"https://api.example.com", "https://other.example.com"}},
+						{Scope: "groups", Audience: []string{"https://api.example.com"}},
+						{Scope: "email"},
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 4)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'bad': option 'scope_audiences' must only have the values of option 'audience' 'https://api.example.com' as an audience but the scope 'groups' has the audience 'https://other.example.com'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'bad': option 'scope_audiences' has the scope 'groups' configured more than once")
+	assert.EqualError(t, validator.Errors()[2], "identity_providers: oidc: client 'bad': option 'scope_audiences' must only have the values of option 'scopes' 'openid', 'groups' as a scope but one is configured as 'email'")
+	assert.EqualError(t, validator.Errors()[3], "identity_providers: oidc: client 'bad': option 'scope_audiences' must have at least one audience for the scope 'email'")
+}
+
+func TestShouldValidateOIDCClientIntrospection(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			CustomScopes: []schema.OpenIDConnectCustomScopeConfiguration{
+				{Name: "department", Claims: []string{"groups"}},
+			},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:           "app",
+					Secret:       "good_secret",
+					RedirectURIs: []string{"https://google.com/callback"},
+				},
+				{
+					ID:           "api",
+					Secret:       "good_secret",
+					RedirectURIs: []string{"https://google.com/callback"},
+					Introspection: schema.OpenIDConnectClientIntrospectionConfiguration{
+						Enabled:        true,
+						AllowedClients: []string{"app", "missing"},
+						Scopes:         []string{"groups", "department", "unknown"},
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'api': option 'introspection' option 'scopes' must only have the values 'openid', 'email', 'profile', 'groups', 'offline_access', 'department' but one is configured as 'unknown'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'api': option 'introspection' option 'allowed_clients' must only have the id of a configured client but one is configured as 'missing'")
+}
+
+func TestShouldNotValidateOIDCClientIntrospectionAllowedClientsWithClientsPath(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: "key-material",
+			ClientsPath:      "/config/clients",
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:           "api",
+					Secret:       "good_secret",
+					RedirectURIs: []string{"https://google.com/callback"},
+					Introspection: schema.OpenIDConnectClientIntrospectionConfiguration{
+						Enabled:        true,
+						AllowedClients: []string{"app"},
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+
+	ValidateOpenIDConnectClients(config.OIDC, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'api': option 'introspection' option 'allowed_clients' must only have the id of a configured client but one is configured as 'app'")
+}
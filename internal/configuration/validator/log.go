@@ -21,4 +21,84 @@ func ValidateLog(config *schema.Configuration, validator *schema.StructValidator
 	if !utils.IsStringInSlice(config.Log.Level, validLoLevels) {
 		validator.Push(fmt.Errorf(errFmtLoggingLevelInvalid, strings.Join(validLoLevels, "', '"), config.Log.Level))
 	}
+
+	if config.Log.SlowRequestThreshold < 0 {
+		validator.Push(fmt.Errorf(errFmtLoggingSlowRequestThresholdNegative, config.Log.SlowRequestThreshold))
+	}
+
+	if config.Log.Redaction.Enabled {
+		validateLogRedaction(&config.Log.Redaction, validator)
+	}
+
+	if config.Log.Syslog.Enabled {
+		validateLogSyslog(&config.Log.Syslog, validator)
+	}
+}
+
+// validateLogSyslog validates and updates the log syslog configuration.
+func validateLogSyslog(config *schema.LogSyslogConfiguration, validator *schema.StructValidator) {
+	switch config.Network {
+	case "":
+		config.Network = schema.DefaultLogSyslogConfiguration.Network
+	case schema.LogSyslogNetworkUnix:
+		break
+	case schema.LogSyslogNetworkUDP, schema.LogSyslogNetworkTCP, schema.LogSyslogNetworkTCPTLS:
+		if config.Address == "" {
+			validator.Push(fmt.Errorf(errFmtLoggingSyslogAddressRequired, config.Network))
+		}
+	default:
+		validator.Push(fmt.Errorf(errFmtLoggingSyslogNetworkInvalid, strings.Join(validLogSyslogNetworks, "', '"), config.Network))
+	}
+
+	if config.Facility == "" {
+		config.Facility = schema.DefaultLogSyslogConfiguration.Facility
+	} else if !utils.IsStringInSlice(config.Facility, validLogSyslogFacilities) {
+		validator.Push(fmt.Errorf(errFmtLoggingSyslogFacilityInvalid, strings.Join(validLogSyslogFacilities, "', '"), config.Facility))
+	}
+
+	if config.Tag == "" {
+		config.Tag = schema.DefaultLogSyslogConfiguration.Tag
+	}
+
+	if config.TLS == nil {
+		tlsConfig := *schema.DefaultLogSyslogConfiguration.TLS
+
+		config.TLS = &tlsConfig
+	}
+
+	if config.TLS.MinimumVersion == "" {
+		config.TLS.MinimumVersion = schema.DefaultLogSyslogConfiguration.TLS.MinimumVersion
+	}
+
+	if _, err := utils.TLSStringToTLSConfigVersion(config.TLS.MinimumVersion); err != nil {
+		validator.Push(fmt.Errorf(errFmtLoggingSyslogTLSMinVersion, config.TLS.MinimumVersion, err))
+	}
+}
+
+// validateLogRedaction validates and updates the log redaction configuration.
+func validateLogRedaction(config *schema.LogRedactionConfiguration, validator *schema.StructValidator) {
+	switch config.Method {
+	case "":
+		config.Method = schema.DefaultLogRedactionConfiguration.Method
+	case schema.LogRedactionMethodHash, schema.LogRedactionMethodMask:
+		break
+	default:
+		validator.Push(fmt.Errorf(errFmtLoggingRedactionMethodInvalid, strings.Join(validLogRedactionMethods, "', '"), config.Method))
+	}
+
+	if config.Method == schema.LogRedactionMethodHash && config.Secret == "" {
+		validator.Push(fmt.Errorf(errLoggingRedactionSecretRequired))
+	}
+
+	if len(config.Fields) == 0 {
+		config.Fields = schema.DefaultLogRedactionConfiguration.Fields
+
+		return
+	}
+
+	for _, field := range config.Fields {
+		if !utils.IsStringInSlice(field, validLogRedactionFields) {
+			validator.Push(fmt.Errorf(errFmtLoggingRedactionFieldInvalid, strings.Join(validLogRedactionFields, "', '"), field))
+		}
+	}
 }
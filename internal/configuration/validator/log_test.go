@@ -2,6 +2,7 @@ package validator
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -42,3 +43,154 @@ func TestShouldRaiseErrorOnInvalidLoggingLevel(t *testing.T) {
 
 	assert.EqualError(t, validator.Errors()[0], "log: option 'level' must be one of 'trace', 'debug', 'info', 'warn', 'error' but it is configured as 'TRACE'")
 }
+
+func TestShouldRaiseErrorOnNegativeSlowRequestThreshold(t *testing.T) {
+	config := &schema.Configuration{
+		Log: schema.LogConfiguration{
+			SlowRequestThreshold: -time.Second,
+		},
+	}
+
+	validator := schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "log: option 'slow_request_threshold' must be 0 or more but it is configured as '-1s'")
+}
+
+func TestShouldSetDefaultLogRedactionValues(t *testing.T) {
+	config := &schema.Configuration{
+		Log: schema.LogConfiguration{
+			Redaction: schema.LogRedactionConfiguration{
+				Enabled: true,
+				Secret:  "a-very-long-secret",
+			},
+		},
+	}
+
+	validator := schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+
+	assert.Equal(t, "hash", config.Log.Redaction.Method)
+	assert.Equal(t, []string{"username", "remote_ip", "email"}, config.Log.Redaction.Fields)
+}
+
+func TestShouldNotValidateLogRedactionWhenDisabled(t *testing.T) {
+	config := &schema.Configuration{
+		Log: schema.LogConfiguration{
+			Redaction: schema.LogRedactionConfiguration{
+				Method: "bad",
+			},
+		},
+	}
+
+	validator := schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, config.Log.Redaction.Fields, 0)
+}
+
+func TestShouldRaiseErrorsOnInvalidLogRedaction(t *testing.T) {
+	config := &schema.Configuration{
+		Log: schema.LogConfiguration{
+			Redaction: schema.LogRedactionConfiguration{
+				Enabled: true,
+				Fields:  []string{"username", "password"},
+			},
+		},
+	}
+
+	validator := schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 2)
+
+	assert.EqualError(t, validator.Errors()[0], "log: redaction: option 'secret' is required when the method is 'hash'")
+	assert.EqualError(t, validator.Errors()[1], "log: redaction: option 'fields' must only have the values 'username', 'remote_ip', 'email' but one option is configured as 'password'")
+
+	config.Log.Redaction = schema.LogRedactionConfiguration{
+		Enabled: true,
+		Method:  "encrypt",
+	}
+
+	validator = schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "log: redaction: option 'method' must be one of 'hash', 'mask' but it is configured as 'encrypt'")
+}
+
+func TestShouldSetDefaultLogSyslogValues(t *testing.T) {
+	config := &schema.Configuration{
+		Log: schema.LogConfiguration{
+			Syslog: schema.LogSyslogConfiguration{
+				Enabled: true,
+			},
+		},
+	}
+
+	validator := schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+
+	assert.Equal(t, "unix", config.Log.Syslog.Network)
+	assert.Equal(t, "daemon", config.Log.Syslog.Facility)
+	assert.Equal(t, "authelia", config.Log.Syslog.Tag)
+	require.NotNil(t, config.Log.Syslog.TLS)
+	assert.Equal(t, "TLS1.2", config.Log.Syslog.TLS.MinimumVersion)
+}
+
+func TestShouldRaiseErrorsOnInvalidLogSyslog(t *testing.T) {
+	config := &schema.Configuration{
+		Log: schema.LogConfiguration{
+			Syslog: schema.LogSyslogConfiguration{
+				Enabled:  true,
+				Network:  "tcp+tls",
+				Facility: "local8",
+				TLS: &schema.TLSConfig{
+					MinimumVersion: "SSL3.0",
+				},
+			},
+		},
+	}
+
+	validator := schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 3)
+
+	assert.EqualError(t, validator.Errors()[0], "log: syslog: option 'address' is required when the network is 'tcp+tls'")
+	assert.EqualError(t, validator.Errors()[1], "log: syslog: option 'facility' must be one of 'kern', 'user', 'mail', 'daemon', 'auth', 'syslog', 'lpr', 'news', 'uucp', 'cron', 'authpriv', 'ftp', 'local0', 'local1', 'local2', 'local3', 'local4', 'local5', 'local6', 'local7' but it is configured as 'local8'")
+	assert.EqualError(t, validator.Errors()[2], "log: syslog: tls: option 'minimum_version' is invalid: SSL3.0: supplied tls version isn't supported")
+
+	config.Log.Syslog = schema.LogSyslogConfiguration{
+		Enabled: true,
+		Network: "tls",
+	}
+
+	validator = schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "log: syslog: option 'network' must be one of 'unix', 'udp', 'tcp', 'tcp+tls' but it is configured as 'tls'")
+}
@@ -0,0 +1,28 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// ValidateLoginNotifications validates and updates the login notifications configuration.
+func ValidateLoginNotifications(config *schema.LoginNotificationsConfiguration, validator *schema.StructValidator) {
+	if !config.Enabled {
+		return
+	}
+
+	switch {
+	case config.Remember == 0:
+		config.Remember = schema.DefaultLoginNotificationsConfiguration.Remember
+	case config.Remember < 0:
+		validator.Push(fmt.Errorf(errFmtLoginNotificationsNegative, "remember", config.Remember))
+	}
+
+	switch {
+	case config.Throttle == 0:
+		config.Throttle = schema.DefaultLoginNotificationsConfiguration.Throttle
+	case config.Throttle < 0:
+		validator.Push(fmt.Errorf(errFmtLoginNotificationsNegative, "throttle", config.Throttle))
+	}
+}
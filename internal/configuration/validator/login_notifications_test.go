@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldNotSetLoginNotificationsDefaultsWhenDisabled(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.LoginNotificationsConfiguration{}
+
+	ValidateLoginNotifications(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, time.Duration(0), config.Remember)
+	assert.Equal(t, time.Duration(0), config.Throttle)
+}
+
+func TestShouldSetLoginNotificationsDefaults(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.LoginNotificationsConfiguration{Enabled: true}
+
+	ValidateLoginNotifications(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultLoginNotificationsConfiguration.Remember, config.Remember)
+	assert.Equal(t, schema.DefaultLoginNotificationsConfiguration.Throttle, config.Throttle)
+}
+
+func TestShouldRaiseErrorsOnNegativeLoginNotificationsDurations(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.LoginNotificationsConfiguration{
+		Enabled:  true,
+		Remember: -time.Hour,
+		Throttle: -time.Minute,
+	}
+
+	ValidateLoginNotifications(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+
+	assert.EqualError(t, validator.Errors()[0], "login_notifications: option 'remember' must be more than 0 but it is configured as '-1h0m0s'")
+	assert.EqualError(t, validator.Errors()[1], "login_notifications: option 'throttle' must be more than 0 but it is configured as '-1m0s'")
+}
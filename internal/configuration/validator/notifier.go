@@ -2,17 +2,21 @@ package validator
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/template"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/notification"
 	"github.com/authelia/authelia/v4/internal/templates"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // ValidateNotifier validates and update notifier configuration.
 func ValidateNotifier(config *schema.NotifierConfiguration, validator *schema.StructValidator) {
-	if config == nil || (config.SMTP == nil && config.FileSystem == nil) {
+	if config == nil || (config.SMTP == nil && config.FileSystem == nil && len(config.Notifiers) == 0) {
 		validator.Push(fmt.Errorf(errFmtNotifierNotConfigured))
 
 		return
@@ -22,19 +26,83 @@ func ValidateNotifier(config *schema.NotifierConfiguration, validator *schema.St
 		return
 	}
 
-	if config.FileSystem != nil {
+	validateNotifierInstances(config.Notifiers, validator)
+
+	switch {
+	case config.FileSystem != nil:
 		if config.FileSystem.Filename == "" {
 			validator.Push(fmt.Errorf(errFmtNotifierFileSystemFileNameNotConfigured))
 		}
 
 		return
+	case config.SMTP != nil:
+		validateSMTPNotifier(config.SMTP, validator)
 	}
 
-	validateSMTPNotifier(config.SMTP, validator)
-
 	validateNotifierTemplates(config, validator)
 }
 
+func validateNotifierInstances(instances []schema.NotifierInstanceConfiguration, validator *schema.StructValidator) {
+	names := make([]string, 0, len(instances))
+
+	for i := range instances {
+		instance := &instances[i]
+
+		if instance.Name == "" {
+			validator.Push(fmt.Errorf(errFmtNotifierInstanceNameNotConfigured, i+1))
+
+			continue
+		}
+
+		if utils.IsStringInSlice(instance.Name, names) {
+			validator.Push(fmt.Errorf(errFmtNotifierInstanceNameDuplicate, instance.Name))
+		}
+
+		names = append(names, instance.Name)
+
+		for _, event := range instance.Events {
+			if !utils.IsStringInSlice(event, notification.Events) {
+				validator.Push(fmt.Errorf(errFmtNotifierInstanceInvalidEvent, instance.Name, strings.Join(notification.Events, "', '"), event))
+			}
+		}
+
+		configured := 0
+
+		for _, ok := range []bool{instance.FileSystem != nil, instance.SMTP != nil, instance.Webhook != nil} {
+			if ok {
+				configured++
+			}
+		}
+
+		switch {
+		case configured == 0:
+			validator.Push(fmt.Errorf(errFmtNotifierInstanceNotConfigured, instance.Name))
+		case configured > 1:
+			validator.Push(fmt.Errorf(errFmtNotifierInstanceMultipleConfigured, instance.Name))
+		case instance.FileSystem != nil:
+			if instance.FileSystem.Filename == "" {
+				validator.Push(fmt.Errorf(errFmtNotifierInstanceNotConfiguredOption, instance.Name, "filesystem", "filename"))
+			}
+		case instance.SMTP != nil:
+			validateSMTPNotifier(instance.SMTP, validator)
+		case instance.Webhook != nil:
+			validateWebhookNotifier(instance.Name, instance.Webhook, validator)
+		}
+	}
+}
+
+func validateWebhookNotifier(name string, config *schema.WebhookNotifierConfiguration, validator *schema.StructValidator) {
+	if config.URL == "" {
+		validator.Push(fmt.Errorf(errFmtNotifierInstanceNotConfiguredOption, name, "webhook", "url"))
+	} else if u, err := url.Parse(config.URL); err != nil || !u.IsAbs() || (u.Scheme != schemeHTTP && u.Scheme != schemeHTTPS) {
+		validator.Push(fmt.Errorf(errFmtNotifierWebhookInvalidURL, name, config.URL))
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = schema.DefaultWebhookNotifierConfiguration.Timeout
+	}
+}
+
 func validateNotifierTemplates(config *schema.NotifierConfiguration, validator *schema.StructValidator) {
 	if config.TemplatePath == "" {
 		return
@@ -111,7 +179,10 @@ func validateSMTPNotifier(config *schema.SMTPNotifierConfiguration, validator *s
 	}
 
 	if config.TLS == nil {
-		config.TLS = schema.DefaultSMTPNotifierConfiguration.TLS
+		// The default is copied as the server name is set below which must not be shared between notifiers.
+		tlsConfig := *schema.DefaultSMTPNotifierConfiguration.TLS
+
+		config.TLS = &tlsConfig
 	}
 
 	if config.TLS.ServerName == "" {
@@ -26,6 +26,7 @@ func (suite *NotifierSuite) SetupTest() {
 		Port:     25,
 	}
 	suite.config.FileSystem = nil
+	suite.config.Notifiers = nil
 }
 
 /*
@@ -159,6 +160,95 @@ func (suite *NotifierSuite) TestFileShouldEnsureFilenameIsProvided() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], errFmtNotifierFileSystemFileNameNotConfigured)
 }
 
+/*
+	Notifiers Tests.
+*/
+func (suite *NotifierSuite) TestNotifiersShouldAllowWithoutDefaultNotifier() {
+	suite.config.SMTP = nil
+	suite.config.Notifiers = []schema.NotifierInstanceConfiguration{
+		{
+			Name:    "alerts",
+			Events:  []string{"login", "password_changed"},
+			Webhook: &schema.WebhookNotifierConfiguration{URL: "https://hooks.example.com/authelia"},
+		},
+		{
+			Name:       "file",
+			FileSystem: &schema.FileSystemNotifierConfiguration{Filename: "test"},
+		},
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.DefaultWebhookNotifierConfiguration.Timeout, suite.config.Notifiers[0].Webhook.Timeout)
+}
+
+func (suite *NotifierSuite) TestNotifiersShouldSetSMTPDefaults() {
+	suite.config.Notifiers = []schema.NotifierInstanceConfiguration{
+		{
+			Name: "mail",
+			SMTP: &schema.SMTPNotifierConfiguration{
+				Sender: mail.Address{Address: "authelia@example.com"},
+				Host:   "smtp.example.com",
+				Port:   25,
+			},
+		},
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.DefaultSMTPNotifierConfiguration.Timeout, suite.config.Notifiers[0].SMTP.Timeout)
+	suite.Assert().Equal("smtp.example.com", suite.config.Notifiers[0].SMTP.TLS.ServerName)
+}
+
+func (suite *NotifierSuite) TestNotifiersShouldRaiseErrorsOnInvalidNotifiers() {
+	suite.config.Notifiers = []schema.NotifierInstanceConfiguration{
+		{
+			FileSystem: &schema.FileSystemNotifierConfiguration{Filename: "test"},
+		},
+		{
+			Name: "none",
+		},
+		{
+			Name:       "both",
+			FileSystem: &schema.FileSystemNotifierConfiguration{Filename: "test"},
+			Webhook:    &schema.WebhookNotifierConfiguration{URL: "https://hooks.example.com/authelia"},
+		},
+		{
+			Name:       "both",
+			Events:     []string{"login", "logout"},
+			FileSystem: &schema.FileSystemNotifierConfiguration{},
+		},
+		{
+			Name:    "webhook",
+			Webhook: &schema.WebhookNotifierConfiguration{URL: "ftp://hooks.example.com"},
+		},
+		{
+			Name:    "webhook-empty",
+			Webhook: &schema.WebhookNotifierConfiguration{},
+		},
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 8)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "notifier: notifiers: option 'name' is required for notifier #1")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "notifier: notifiers: notifier 'none': you must ensure one of the 'smtp', 'filesystem', or 'webhook' notifier is configured")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "notifier: notifiers: notifier 'both': please ensure only one of the 'smtp', 'filesystem', or 'webhook' notifier is configured")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "notifier: notifiers: notifier 'both': option 'name' must be unique")
+	suite.Assert().EqualError(suite.validator.Errors()[4], "notifier: notifiers: notifier 'both': option 'events' must only contain values from 'identity_verification', 'registration', 'password_changed', 'account_locked', 'login' but it has a value of 'logout'")
+	suite.Assert().EqualError(suite.validator.Errors()[5], "notifier: notifiers: notifier 'both': filesystem: option 'filename' is required")
+	suite.Assert().EqualError(suite.validator.Errors()[6], "notifier: notifiers: notifier 'webhook': webhook: option 'url' must be an absolute URL with the 'http' or 'https' scheme but it has a value of 'ftp://hooks.example.com'")
+	suite.Assert().EqualError(suite.validator.Errors()[7], "notifier: notifiers: notifier 'webhook-empty': webhook: option 'url' is required")
+}
+
 func TestNotifierSuite(t *testing.T) {
 	suite.Run(t, new(NotifierSuite))
 }
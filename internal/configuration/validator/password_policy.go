@@ -24,4 +24,45 @@ func ValidatePasswordPolicy(config *schema.PasswordPolicyConfiguration, validato
 			config.Standard.MaxLength = schema.DefaultPasswordPolicyConfiguration.Standard.MaxLength
 		}
 	}
+
+	if config.MinimumAge < 0 {
+		validator.Push(fmt.Errorf(errFmtPasswordPolicyMinimumAgeNegative, config.MinimumAge))
+	}
+
+	validatePasswordPolicyPolicies(config, validator)
+}
+
+func validatePasswordPolicyPolicies(config *schema.PasswordPolicyConfiguration, validator *schema.StructValidator) {
+	var names []string
+
+	for i := range config.Policies {
+		policy := &config.Policies[i]
+
+		switch {
+		case policy.Name == "":
+			validator.Push(fmt.Errorf(errFmtPasswordPolicyPoliciesNameEmpty, i+1))
+
+			continue
+		case utils.IsStringInSlice(policy.Name, names):
+			validator.Push(fmt.Errorf(errFmtPasswordPolicyPoliciesNameDuplicate, policy.Name))
+		default:
+			names = append(names, policy.Name)
+		}
+
+		if len(policy.Groups) == 0 {
+			validator.Push(fmt.Errorf(errFmtPasswordPolicyPoliciesGroupsEmpty, policy.Name))
+		}
+
+		if !utils.IsBoolCountLessThanN(1, true, policy.Standard.Enabled, policy.ZXCVBN.Enabled) {
+			validator.Push(fmt.Errorf(errFmtPasswordPolicyPoliciesMultipleDefined, policy.Name))
+		}
+
+		if policy.Standard.Enabled {
+			if policy.Standard.MinLength == 0 {
+				policy.Standard.MinLength = schema.DefaultPasswordPolicyConfiguration.Standard.MinLength
+			} else if policy.Standard.MinLength < 0 {
+				validator.Push(fmt.Errorf(errFmtPasswordPolicyPoliciesMinLength, policy.Name, policy.Standard.MinLength))
+			}
+		}
+	}
 }
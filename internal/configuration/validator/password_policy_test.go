@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -84,6 +85,18 @@ func TestValidatePasswordPolicy(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "ShouldRaiseErrorOnNegativeMinimumAge",
+			have: &schema.PasswordPolicyConfiguration{
+				MinimumAge: -time.Hour,
+			},
+			expected: &schema.PasswordPolicyConfiguration{
+				MinimumAge: -time.Hour,
+			},
+			expectedErrs: []string{
+				"password_policy: option 'minimum_age' must be 0 or more but is configured as '-1h0m0s'",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -111,3 +124,75 @@ func TestValidatePasswordPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePasswordPolicyPolicies(t *testing.T) {
+	config := &schema.PasswordPolicyConfiguration{
+		Standard: schema.PasswordPolicyStandardParams{
+			Enabled: true,
+		},
+		Policies: []schema.PasswordPolicyGroupConfiguration{
+			{
+				Name:   "admins",
+				Groups: []string{"admins"},
+				Standard: schema.PasswordPolicyStandardParams{
+					Enabled:        true,
+					RequireSpecial: true,
+				},
+			},
+			{
+				Name:   "developers",
+				Groups: []string{"dev"},
+				ZXCVBN: schema.PasswordPolicyZXCVBNParams{
+					Enabled: true,
+				},
+			},
+		},
+	}
+
+	validator := schema.NewStructValidator()
+	ValidatePasswordPolicy(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+
+	assert.Equal(t, 8, config.Policies[0].Standard.MinLength)
+	assert.Equal(t, 0, config.Policies[1].Standard.MinLength)
+}
+
+func TestValidatePasswordPolicyPoliciesShouldRaiseErrors(t *testing.T) {
+	config := &schema.PasswordPolicyConfiguration{
+		Policies: []schema.PasswordPolicyGroupConfiguration{
+			{
+				Groups: []string{"admins"},
+			},
+			{
+				Name:   "admins",
+				Groups: []string{"admins"},
+				Standard: schema.PasswordPolicyStandardParams{
+					Enabled:   true,
+					MinLength: -1,
+				},
+				ZXCVBN: schema.PasswordPolicyZXCVBNParams{
+					Enabled: true,
+				},
+			},
+			{
+				Name: "admins",
+			},
+		},
+	}
+
+	validator := schema.NewStructValidator()
+	ValidatePasswordPolicy(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+
+	errs := validator.Errors()
+	require.Len(t, errs, 5)
+
+	assert.EqualError(t, errs[0], "password_policy: policies: policy #1: option 'name' must be configured")
+	assert.EqualError(t, errs[1], "password_policy: policies: policy 'admins': only a single password policy mechanism can be specified")
+	assert.EqualError(t, errs[2], "password_policy: policies: policy 'admins': standard: option 'min_length' must be greater than 0 but is configured as -1")
+	assert.EqualError(t, errs[3], "password_policy: policies: policy 'admins': option 'name' must be unique")
+	assert.EqualError(t, errs[4], "password_policy: policies: policy 'admins': option 'groups' must have at least one group")
+}
@@ -2,8 +2,10 @@ package validator
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // ValidateRegulation validates and update regulator configuration.
@@ -19,4 +21,119 @@ func ValidateRegulation(config *schema.Configuration, validator *schema.StructVa
 	if config.Regulation.FindTime > config.Regulation.BanTime {
 		validator.Push(fmt.Errorf(errFmtRegulationFindTimeGreaterThanBanTime))
 	}
+
+	// The regulator relies on the authentication logs to determine bans so they must be retained for at least as long.
+	if retention := config.Storage.AuthenticationLogsRetention; retention != 0 && retention < config.Regulation.BanTime {
+		validator.Push(fmt.Errorf(errFmtStorageLogsRetentionTooShort, config.Regulation.BanTime, retention))
+	}
+
+	validateRegulationImpossibleTravel(&config.Regulation.ImpossibleTravel, validator)
+	validateRegulationCAPTCHA(&config.Regulation, validator)
+	validateRegulationUnlock(&config.Regulation.Unlock, validator)
+	validateRegulationSecondFactor(&config.Regulation, validator)
+}
+
+func validateRegulationImpossibleTravel(config *schema.ImpossibleTravelConfiguration, validator *schema.StructValidator) {
+	if config.Enabled && len(config.Networks) == 0 {
+		validator.Push(fmt.Errorf(errRegulationImpossibleTravelNoNetworks))
+	}
+
+	for _, network := range config.Networks {
+		if !IsNetworkValid(network) {
+			validator.Push(fmt.Errorf(errFmtRegulationImpossibleTravelNetworkInvalid, network))
+		}
+	}
+
+	if config.HeaderLatitude == "" {
+		config.HeaderLatitude = schema.DefaultRegulationConfiguration.ImpossibleTravel.HeaderLatitude
+	}
+
+	if config.HeaderLongitude == "" {
+		config.HeaderLongitude = schema.DefaultRegulationConfiguration.ImpossibleTravel.HeaderLongitude
+	}
+
+	switch {
+	case config.MaximumSpeed == 0:
+		config.MaximumSpeed = schema.DefaultRegulationConfiguration.ImpossibleTravel.MaximumSpeed
+	case config.MaximumSpeed < 0:
+		validator.Push(fmt.Errorf(errFmtRegulationImpossibleTravelNegative, "maximum_speed", config.MaximumSpeed))
+	}
+
+	switch {
+	case config.MinimumDistance == 0:
+		config.MinimumDistance = schema.DefaultRegulationConfiguration.ImpossibleTravel.MinimumDistance
+	case config.MinimumDistance < 0:
+		validator.Push(fmt.Errorf(errFmtRegulationImpossibleTravelNegative, "minimum_distance", config.MinimumDistance))
+	}
+}
+
+func validateRegulationCAPTCHA(config *schema.RegulationConfiguration, validator *schema.StructValidator) {
+	if config.CAPTCHA.Provider == "" {
+		return
+	}
+
+	if !utils.IsStringInSlice(config.CAPTCHA.Provider, validCAPTCHAProviders) {
+		validator.Push(fmt.Errorf(errFmtRegulationCAPTCHAProvider, strings.Join(validCAPTCHAProviders, "', '"), config.CAPTCHA.Provider))
+	}
+
+	if config.CAPTCHA.SiteKey == "" {
+		validator.Push(fmt.Errorf(errFmtRegulationCAPTCHARequired, "site_key"))
+	}
+
+	if config.CAPTCHA.SecretKey == "" {
+		validator.Push(fmt.Errorf(errFmtRegulationCAPTCHARequired, "secret_key"))
+	}
+
+	switch {
+	case config.CAPTCHA.Threshold < 0:
+		validator.Push(fmt.Errorf(errFmtRegulationCAPTCHAThreshold, config.CAPTCHA.Threshold))
+	case config.MaxRetries > 0 && config.CAPTCHA.Threshold >= config.MaxRetries:
+		validator.PushWarning(fmt.Errorf(errFmtRegulationCAPTCHAThresholdBans, config.CAPTCHA.Threshold))
+	}
+
+	if config.CAPTCHA.Timeout <= 0 {
+		config.CAPTCHA.Timeout = schema.DefaultRegulationConfiguration.CAPTCHA.Timeout
+	}
+
+	switch {
+	case config.CAPTCHA.FailureMode == "":
+		config.CAPTCHA.FailureMode = schema.DefaultRegulationConfiguration.CAPTCHA.FailureMode
+	case !utils.IsStringInSlice(config.CAPTCHA.FailureMode, validCAPTCHAFailureModes):
+		validator.Push(fmt.Errorf(errFmtRegulationCAPTCHAFailureMode, strings.Join(validCAPTCHAFailureModes, "', '"), config.CAPTCHA.FailureMode))
+	}
+}
+
+func validateRegulationUnlock(config *schema.RegulationUnlockConfiguration, validator *schema.StructValidator) {
+	if !config.Enabled {
+		return
+	}
+
+	switch {
+	case config.Lifespan == 0:
+		config.Lifespan = schema.DefaultRegulationConfiguration.Unlock.Lifespan
+	case config.Lifespan < 0:
+		validator.Push(fmt.Errorf(errFmtRegulationUnlockNegative, "lifespan", config.Lifespan))
+	}
+
+	switch {
+	case config.MaxAttempts == 0:
+		config.MaxAttempts = schema.DefaultRegulationConfiguration.Unlock.MaxAttempts
+	case config.MaxAttempts < 0:
+		validator.Push(fmt.Errorf(errFmtRegulationUnlockNegative, "max_attempts", config.MaxAttempts))
+	}
+}
+
+func validateRegulationSecondFactor(config *schema.RegulationConfiguration, validator *schema.StructValidator) {
+	if config.SecondFactor.MaxAttempts < 0 {
+		validator.Push(fmt.Errorf(errFmtRegulationSecondFactorMaxAttempts, config.SecondFactor.MaxAttempts))
+	}
+
+	switch {
+	case config.SecondFactor.Action == "":
+		config.SecondFactor.Action = schema.DefaultRegulationConfiguration.SecondFactor.Action
+	case !utils.IsStringInSlice(config.SecondFactor.Action, validSecondFactorLimitActions):
+		validator.Push(fmt.Errorf(errFmtRegulationSecondFactorAction, strings.Join(validSecondFactorLimitActions, "', '"), config.SecondFactor.Action))
+	case config.SecondFactor.Action == schema.SecondFactorLimitActionBan && config.SecondFactor.MaxAttempts > 0 && config.MaxRetries <= 0:
+		validator.Push(fmt.Errorf(errFmtRegulationSecondFactorBanDisabled))
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 )
@@ -52,3 +53,204 @@ func TestShouldRaiseErrorWhenFindTimeLessThanBanTime(t *testing.T) {
 	assert.Len(t, validator.Errors(), 1)
 	assert.EqualError(t, validator.Errors()[0], "regulation: option 'find_time' must be less than or equal to option 'ban_time'")
 }
+
+func TestShouldSetDefaultRegulationImpossibleTravelValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+	config.Regulation.ImpossibleTravel.Enabled = true
+	config.Regulation.ImpossibleTravel.Networks = []string{"10.0.0.0/8"}
+
+	ValidateRegulation(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultRegulationConfiguration.ImpossibleTravel.HeaderLatitude, config.Regulation.ImpossibleTravel.HeaderLatitude)
+	assert.Equal(t, schema.DefaultRegulationConfiguration.ImpossibleTravel.HeaderLongitude, config.Regulation.ImpossibleTravel.HeaderLongitude)
+	assert.Equal(t, schema.DefaultRegulationConfiguration.ImpossibleTravel.MaximumSpeed, config.Regulation.ImpossibleTravel.MaximumSpeed)
+	assert.Equal(t, schema.DefaultRegulationConfiguration.ImpossibleTravel.MinimumDistance, config.Regulation.ImpossibleTravel.MinimumDistance)
+}
+
+func TestShouldRaiseErrorWhenRegulationImpossibleTravelValuesNegative(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+	config.Regulation.ImpossibleTravel.MaximumSpeed = -1
+	config.Regulation.ImpossibleTravel.MinimumDistance = -10
+
+	ValidateRegulation(&config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "regulation: impossible_travel: option 'maximum_speed' must be 0 or more but it is configured as '-1'")
+	assert.EqualError(t, validator.Errors()[1], "regulation: impossible_travel: option 'minimum_distance' must be 0 or more but it is configured as '-10'")
+}
+
+func TestShouldRaiseErrorWhenRegulationImpossibleTravelNetworksInvalid(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+	config.Regulation.ImpossibleTravel.Enabled = true
+
+	ValidateRegulation(&config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "regulation: impossible_travel: option 'networks' is required when impossible travel detection is enabled")
+
+	validator = schema.NewStructValidator()
+	config.Regulation.ImpossibleTravel.Networks = []string{"10.0.0.0/8", "abc"}
+
+	ValidateRegulation(&config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "regulation: impossible_travel: option 'networks' must only contain valid IP addresses or CIDR notations but it contains 'abc'")
+}
+
+func TestShouldNotValidateRegulationCAPTCHAWhenDisabled(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+	config.Regulation.CAPTCHA.FailureMode = "bad"
+
+	ValidateRegulation(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Equal(t, time.Duration(0), config.Regulation.CAPTCHA.Timeout)
+}
+
+func TestShouldSetDefaultRegulationCAPTCHAValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+	config.Regulation.CAPTCHA = schema.CAPTCHAConfiguration{
+		Provider:  schema.CAPTCHAProviderTurnstile,
+		SiteKey:   "site",
+		SecretKey: "secret",
+	}
+
+	ValidateRegulation(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Equal(t, schema.DefaultRegulationConfiguration.CAPTCHA.Timeout, config.Regulation.CAPTCHA.Timeout)
+	assert.Equal(t, schema.CAPTCHAFailureModeClosed, config.Regulation.CAPTCHA.FailureMode)
+}
+
+func TestShouldRaiseErrorsWhenRegulationCAPTCHAValuesInvalid(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+	config.Regulation.CAPTCHA = schema.CAPTCHAConfiguration{
+		Provider:    "captcha",
+		Threshold:   -1,
+		FailureMode: "ajar",
+	}
+
+	ValidateRegulation(&config, validator)
+
+	require.Len(t, validator.Errors(), 5)
+	assert.EqualError(t, validator.Errors()[0], "regulation: captcha: option 'provider' must be one of 'recaptcha', 'hcaptcha', 'turnstile' but it is configured as 'captcha'")
+	assert.EqualError(t, validator.Errors()[1], "regulation: captcha: option 'site_key' is required when the provider is configured")
+	assert.EqualError(t, validator.Errors()[2], "regulation: captcha: option 'secret_key' is required when the provider is configured")
+	assert.EqualError(t, validator.Errors()[3], "regulation: captcha: option 'threshold' must be 0 or more but it is configured as '-1'")
+	assert.EqualError(t, validator.Errors()[4], "regulation: captcha: option 'failure_mode' must be one of 'closed', 'open' but it is configured as 'ajar'")
+}
+
+func TestShouldRaiseWarningWhenRegulationCAPTCHAThresholdNotLessThanMaxRetries(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+	config.Regulation.MaxRetries = 3
+	config.Regulation.CAPTCHA = schema.CAPTCHAConfiguration{
+		Provider:  schema.CAPTCHAProviderHCaptcha,
+		SiteKey:   "site",
+		SecretKey: "secret",
+		Threshold: 3,
+	}
+
+	ValidateRegulation(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	require.Len(t, validator.Warnings(), 1)
+	assert.EqualError(t, validator.Warnings()[0], "regulation: captcha: option 'threshold' is configured as '3' which is not less than option 'max_retries' so users will be banned before they are asked to complete a CAPTCHA challenge")
+}
+
+func TestShouldRaiseErrorWhenAuthenticationLogsRetentionLessThanBanTime(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+	config.Storage.AuthenticationLogsRetention = time.Minute
+
+	ValidateRegulation(&config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "storage: option 'authentication_logs_retention' must be 0 or at least the regulation option 'ban_time' of '5m0s' but it is configured as '1m0s'")
+
+	validator.Clear()
+
+	config.Storage.AuthenticationLogsRetention = time.Hour * 24 * 30
+
+	ValidateRegulation(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestShouldSetDefaultRegulationUnlockValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+	config.Regulation.Unlock.Enabled = true
+
+	ValidateRegulation(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, time.Minute*15, config.Regulation.Unlock.Lifespan)
+	assert.Equal(t, 3, config.Regulation.Unlock.MaxAttempts)
+}
+
+func TestShouldRaiseErrorsWhenRegulationUnlockValuesNegative(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+	config.Regulation.Unlock = schema.RegulationUnlockConfiguration{
+		Enabled:     true,
+		Lifespan:    -time.Minute,
+		MaxAttempts: -1,
+	}
+
+	ValidateRegulation(&config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "regulation: unlock: option 'lifespan' must be more than 0 but it is configured as '-1m0s'")
+	assert.EqualError(t, validator.Errors()[1], "regulation: unlock: option 'max_attempts' must be more than 0 but it is configured as '-1'")
+}
+
+func TestShouldSetDefaultRegulationSecondFactorAction(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+	config.Regulation.SecondFactor.MaxAttempts = 5
+
+	ValidateRegulation(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.SecondFactorLimitActionDestroySession, config.Regulation.SecondFactor.Action)
+}
+
+func TestShouldRaiseErrorsWhenRegulationSecondFactorValuesInvalid(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+	config.Regulation.SecondFactor = schema.RegulationSecondFactorConfiguration{
+		MaxAttempts: -1,
+		Action:      "lock",
+	}
+
+	ValidateRegulation(&config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "regulation: second_factor: option 'max_attempts' must be 0 or more but it is configured as '-1'")
+	assert.EqualError(t, validator.Errors()[1], "regulation: second_factor: option 'action' must be one of 'destroy_session', 'ban' but it is configured as 'lock'")
+}
+
+func TestShouldRaiseErrorWhenRegulationSecondFactorBanWithRegulationDisabled(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+	config.Regulation.MaxRetries = 0
+	config.Regulation.SecondFactor = schema.RegulationSecondFactorConfiguration{
+		MaxAttempts: 3,
+		Action:      schema.SecondFactorLimitActionBan,
+	}
+
+	ValidateRegulation(&config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "regulation: second_factor: option 'action' can't be configured as 'ban' when the regulation is disabled by configuring the 'max_retries' option as 0")
+}
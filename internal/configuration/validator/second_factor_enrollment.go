@@ -0,0 +1,25 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// ValidateSecondFactorEnrollment validates and updates the second factor enrollment configuration.
+func ValidateSecondFactorEnrollment(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.SecondFactorEnrollment.Enforce {
+		return
+	}
+
+	switch {
+	case config.SecondFactorEnrollment.GracePeriod == 0:
+		config.SecondFactorEnrollment.GracePeriod = schema.DefaultSecondFactorEnrollmentConfiguration.GracePeriod
+	case config.SecondFactorEnrollment.GracePeriod < 0:
+		validator.Push(fmt.Errorf(errFmtSecondFactorEnrollmentGracePeriodNegative, config.SecondFactorEnrollment.GracePeriod))
+	}
+
+	if config.TOTP.Disable && config.Webauthn.Disable && config.DuoAPI == nil {
+		validator.Push(fmt.Errorf(errSecondFactorEnrollmentNoMethods))
+	}
+}
@@ -0,0 +1,69 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldNotSetSecondFactorEnrollmentDefaultsWhenNotEnforced(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{}
+
+	ValidateSecondFactorEnrollment(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, time.Duration(0), config.SecondFactorEnrollment.GracePeriod)
+}
+
+func TestShouldSetSecondFactorEnrollmentDefaults(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		SecondFactorEnrollment: schema.SecondFactorEnrollmentConfiguration{Enforce: true},
+	}
+
+	ValidateSecondFactorEnrollment(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultSecondFactorEnrollmentConfiguration.GracePeriod, config.SecondFactorEnrollment.GracePeriod)
+}
+
+func TestShouldRaiseErrorOnNegativeSecondFactorEnrollmentGracePeriod(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		SecondFactorEnrollment: schema.SecondFactorEnrollmentConfiguration{Enforce: true, GracePeriod: -time.Hour},
+	}
+
+	ValidateSecondFactorEnrollment(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "second_factor_enrollment: option 'grace_period' must be more than 0 but it is configured as '-1h0m0s'")
+}
+
+func TestShouldRaiseErrorWhenSecondFactorEnrollmentEnforcedWithoutMethods(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		SecondFactorEnrollment: schema.SecondFactorEnrollmentConfiguration{Enforce: true},
+		TOTP:                   schema.TOTPConfiguration{Disable: true},
+		Webauthn:               schema.WebauthnConfiguration{Disable: true},
+	}
+
+	ValidateSecondFactorEnrollment(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "second_factor_enrollment: option 'enforce' can't be enabled when totp and webauthn are disabled and duo_api is not configured")
+
+	validator.Clear()
+
+	config.DuoAPI = &schema.DuoAPIConfiguration{Hostname: "api-123456789.example.com"}
+
+	ValidateSecondFactorEnrollment(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+}
@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/asaskevich/govalidator"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// ValidateSelfRegistration validates and updates the self-registration configuration.
+func ValidateSelfRegistration(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.SelfRegistration.Enabled {
+		return
+	}
+
+	if config.AuthenticationBackend.LDAP != nil {
+		validator.Push(fmt.Errorf(errSelfRegistrationRequiresFileBackend))
+	}
+
+	for _, email := range config.SelfRegistration.AdminEmails {
+		if !govalidator.IsEmail(email) {
+			validator.Push(fmt.Errorf(errFmtSelfRegistrationAdminEmailInvalid, email))
+		}
+	}
+
+	switch {
+	case config.SelfRegistration.RateLimit < 0:
+		validator.Push(fmt.Errorf(errFmtSelfRegistrationRateLimitNegative, config.SelfRegistration.RateLimit))
+	case config.SelfRegistration.RateLimit == 0:
+		config.SelfRegistration.RateLimit = schema.DefaultSelfRegistrationConfiguration.RateLimit
+	}
+
+	switch {
+	case config.SelfRegistration.RateLimitPeriod < 0:
+		validator.Push(fmt.Errorf(errFmtSelfRegistrationRateLimitPeriodNegative, config.SelfRegistration.RateLimitPeriod))
+	case config.SelfRegistration.RateLimitPeriod == 0:
+		config.SelfRegistration.RateLimitPeriod = schema.DefaultSelfRegistrationConfiguration.RateLimitPeriod
+	}
+
+	switch {
+	case config.SelfRegistration.EmailVerification.Lifespan == 0:
+		config.SelfRegistration.EmailVerification.Lifespan = schema.DefaultSelfRegistrationConfiguration.EmailVerification.Lifespan
+	case config.SelfRegistration.EmailVerification.Lifespan < 0:
+		validator.Push(fmt.Errorf(errFmtSelfRegistrationEmailVerificationLifespan, config.SelfRegistration.EmailVerification.Lifespan))
+	}
+}
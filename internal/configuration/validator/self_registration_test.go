@@ -0,0 +1,68 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldNotValidateSelfRegistrationWhenDisabled(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		AuthenticationBackend: schema.AuthenticationBackendConfiguration{LDAP: &schema.LDAPAuthenticationBackendConfiguration{}},
+		SelfRegistration:      schema.SelfRegistrationConfiguration{AdminEmails: []string{"invalid"}},
+	}
+
+	ValidateSelfRegistration(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, 0, config.SelfRegistration.RateLimit)
+}
+
+func TestShouldSetDefaultSelfRegistrationOptions(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		AuthenticationBackend: schema.AuthenticationBackendConfiguration{File: &schema.FileAuthenticationBackendConfiguration{}},
+		SelfRegistration: schema.SelfRegistrationConfiguration{
+			Enabled:     true,
+			AdminEmails: []string{"admin@example.com"},
+		},
+	}
+
+	ValidateSelfRegistration(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, 10, config.SelfRegistration.RateLimit)
+	assert.Equal(t, time.Hour, config.SelfRegistration.RateLimitPeriod)
+	assert.Equal(t, time.Hour*24, config.SelfRegistration.EmailVerification.Lifespan)
+}
+
+func TestShouldRaiseErrorsOnInvalidSelfRegistration(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		AuthenticationBackend: schema.AuthenticationBackendConfiguration{LDAP: &schema.LDAPAuthenticationBackendConfiguration{}},
+		SelfRegistration: schema.SelfRegistrationConfiguration{
+			Enabled:         true,
+			AdminEmails:     []string{"admin@example.com", "admin"},
+			RateLimit:       -1,
+			RateLimitPeriod: -time.Minute,
+			EmailVerification: schema.SelfRegistrationEmailVerificationConfiguration{
+				Enabled:  true,
+				Lifespan: -time.Minute,
+			},
+		},
+	}
+
+	ValidateSelfRegistration(config, validator)
+
+	require.Len(t, validator.Errors(), 5)
+	assert.EqualError(t, validator.Errors()[0], "self_registration: option 'enabled' requires the 'file' authentication backend as accounts can't be created in the 'ldap' authentication backend")
+	assert.EqualError(t, validator.Errors()[1], "self_registration: option 'admin_emails' must only contain valid email addresses but it contains 'admin'")
+	assert.EqualError(t, validator.Errors()[2], "self_registration: option 'rate_limit' must be 0 or more but it is configured as '-1'")
+	assert.EqualError(t, validator.Errors()[3], "self_registration: option 'rate_limit_period' must be 0 or more but it is configured as '-1m0s'")
+	assert.EqualError(t, validator.Errors()[4], "self_registration: email_verification: option 'lifespan' must be more than 0 but it is configured as '-1m0s'")
+}
@@ -0,0 +1,19 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ValidateSelfTest validates and updates the self-test configuration.
+func ValidateSelfTest(config *schema.SelfTestConfiguration, validator *schema.StructValidator) {
+	switch {
+	case config.FailureMode == "":
+		config.FailureMode = schema.DefaultSelfTestConfiguration.FailureMode
+	case !utils.IsStringInSlice(config.FailureMode, validSelfTestFailureModes):
+		validator.Push(fmt.Errorf(errFmtSelfTestFailureMode, strings.Join(validSelfTestFailureModes, "', '"), config.FailureMode))
+	}
+}
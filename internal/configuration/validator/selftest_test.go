@@ -0,0 +1,40 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldSetDefaultSelfTestFailureMode(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.SelfTestConfiguration{Enabled: true}
+
+	ValidateSelfTest(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.SelfTestFailureModeFatal, config.FailureMode)
+}
+
+func TestShouldNotOverrideSelfTestFailureMode(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.SelfTestConfiguration{Enabled: true, FailureMode: schema.SelfTestFailureModeWarn}
+
+	ValidateSelfTest(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.SelfTestFailureModeWarn, config.FailureMode)
+}
+
+func TestShouldRaiseErrorOnInvalidSelfTestFailureMode(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.SelfTestConfiguration{Enabled: true, FailureMode: "ignore"}
+
+	ValidateSelfTest(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "self_test: option 'failure_mode' must be one of 'fatal', 'warn' but it is configured as 'ignore'")
+}
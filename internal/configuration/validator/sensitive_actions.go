@@ -0,0 +1,35 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ValidateSensitiveActions validates and updates the sensitive actions configuration.
+func ValidateSensitiveActions(config *schema.SensitiveActionsConfiguration, validator *schema.StructValidator) {
+	if !config.Enabled {
+		return
+	}
+
+	switch {
+	case config.Window == 0:
+		config.Window = schema.DefaultSensitiveActionsConfiguration.Window
+	case config.Window < 0:
+		validator.Push(fmt.Errorf(errFmtSensitiveActionsWindowNegative, config.Window))
+	}
+
+	if len(config.Actions) == 0 {
+		config.Actions = append([]string(nil), schema.DefaultSensitiveActionsConfiguration.Actions...)
+
+		return
+	}
+
+	for _, action := range config.Actions {
+		if !utils.IsStringInSlice(action, validSensitiveActions) {
+			validator.Push(fmt.Errorf(errFmtSensitiveActionsInvalidAction, strings.Join(validSensitiveActions, "', '"), action))
+		}
+	}
+}
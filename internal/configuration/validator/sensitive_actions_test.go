@@ -0,0 +1,72 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldNotSetSensitiveActionsDefaultsWhenDisabled(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.SensitiveActionsConfiguration{}
+
+	ValidateSensitiveActions(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, time.Duration(0), config.Window)
+	assert.Len(t, config.Actions, 0)
+}
+
+func TestShouldSetSensitiveActionsDefaults(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.SensitiveActionsConfiguration{Enabled: true}
+
+	ValidateSensitiveActions(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultSensitiveActionsConfiguration.Window, config.Window)
+	assert.Equal(t, schema.DefaultSensitiveActionsConfiguration.Actions, config.Actions)
+}
+
+func TestShouldNotOverrideConfiguredSensitiveActions(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.SensitiveActionsConfiguration{
+		Enabled: true,
+		Window:  time.Minute,
+		Actions: []string{schema.SensitiveActionPreferredMethod},
+	}
+
+	ValidateSensitiveActions(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, time.Minute, config.Window)
+	assert.Equal(t, []string{schema.SensitiveActionPreferredMethod}, config.Actions)
+}
+
+func TestShouldRaiseErrorOnNegativeSensitiveActionsWindow(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.SensitiveActionsConfiguration{Enabled: true, Window: -time.Minute}
+
+	ValidateSensitiveActions(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "sensitive_actions: option 'window' must be more than 0 but it is configured as '-1m0s'")
+}
+
+func TestShouldRaiseErrorOnInvalidSensitiveAction(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.SensitiveActionsConfiguration{Enabled: true, Actions: []string{"totp_registration", "password_change"}}
+
+	ValidateSensitiveActions(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "sensitive_actions: option 'actions' must only contain values from "+
+		"'totp_registration', 'totp_deletion', 'totp_rotation', 'webauthn_registration', 'duo_device_selection', "+
+		"'preferred_method', 'oidc_refresh_token_revocation', 'administration' but it contains 'password_change'")
+}
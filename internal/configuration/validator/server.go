@@ -1,9 +1,11 @@
 package validator
 
 import (
+	"crypto/tls"
 	"fmt"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -45,6 +47,51 @@ func ValidateServerTLS(config *schema.Configuration, validator *schema.StructVal
 	for _, clientCertPath := range config.Server.TLS.ClientCertificates {
 		validateFileExists(clientCertPath, validator, errFmtServerTLSClientAuthCertFileDoesNotExist)
 	}
+
+	validateServerTLSOptions(config, validator)
+}
+
+func validateServerTLSOptions(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Server.TLS.MinimumVersion == "" {
+		config.Server.TLS.MinimumVersion = schema.DefaultServerConfiguration.TLS.MinimumVersion
+	}
+
+	if config.Server.TLS.MaximumVersion == "" {
+		config.Server.TLS.MaximumVersion = schema.DefaultServerConfiguration.TLS.MaximumVersion
+	}
+
+	minVersion, errMin := utils.TLSStringToTLSConfigVersion(config.Server.TLS.MinimumVersion)
+	if errMin != nil {
+		validator.Push(fmt.Errorf(errFmtServerTLSVersion, "minimum", config.Server.TLS.MinimumVersion, errMin))
+	}
+
+	maxVersion, errMax := utils.TLSStringToTLSConfigVersion(config.Server.TLS.MaximumVersion)
+	if errMax != nil {
+		validator.Push(fmt.Errorf(errFmtServerTLSVersion, "maximum", config.Server.TLS.MaximumVersion, errMax))
+	}
+
+	if errMin == nil && errMax == nil && minVersion > maxVersion {
+		validator.Push(fmt.Errorf(errFmtServerTLSVersionMinimumGreaterThanMax, config.Server.TLS.MinimumVersion, config.Server.TLS.MaximumVersion))
+	}
+
+	if len(config.Server.TLS.CipherSuites) == 0 {
+		return
+	}
+
+	if errMin == nil && minVersion == tls.VersionTLS13 {
+		validator.PushWarning(fmt.Errorf(errFmtServerTLSCipherSuiteTLS13))
+	}
+
+	for _, name := range config.Server.TLS.CipherSuites {
+		_, insecure, err := utils.TLSStringToTLSConfigCipherSuite(name)
+
+		switch {
+		case err != nil:
+			validator.Push(fmt.Errorf(errFmtServerTLSCipherSuite, name, err))
+		case insecure:
+			validator.PushWarning(fmt.Errorf(errFmtServerTLSCipherSuiteInsecure, name))
+		}
+	}
 }
 
 // ValidateServer checks a server configuration is correct.
@@ -59,6 +106,12 @@ func ValidateServer(config *schema.Configuration, validator *schema.StructValida
 
 	ValidateServerTLS(config, validator)
 
+	for _, network := range config.Server.TrustedProxies {
+		if !IsNetworkValid(network) {
+			validator.Push(fmt.Errorf(errFmtServerTrustedProxyInvalid, network))
+		}
+	}
+
 	switch {
 	case strings.Contains(config.Server.Path, "/"):
 		validator.Push(fmt.Errorf(errFmtServerPathNoForwardSlashes))
@@ -81,4 +134,110 @@ func ValidateServer(config *schema.Configuration, validator *schema.StructValida
 	} else if config.Server.WriteBufferSize < 0 {
 		validator.Push(fmt.Errorf(errFmtServerBufferSize, "write", config.Server.WriteBufferSize))
 	}
+
+	validateServerConnections(config, validator)
+	validateServerHeaders(config, validator)
+	validateServerProxyProtocol(config, validator)
+	validateServerTextFiles(config, validator)
+}
+
+func validateServerTextFiles(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Server.RobotsTXT == "" {
+		config.Server.RobotsTXT = schema.DefaultServerConfiguration.RobotsTXT
+	}
+
+	if config.Server.SecurityTXT == "" {
+		return
+	}
+
+	fields := map[string]bool{}
+
+	for _, line := range strings.Split(config.Server.SecurityTXT, "\n") {
+		if name, _, found := strings.Cut(line, ":"); found {
+			fields[strings.ToLower(strings.TrimSpace(name))] = true
+		}
+	}
+
+	for _, field := range []string{"Contact", "Expires"} {
+		if !fields[strings.ToLower(field)] {
+			validator.PushWarning(fmt.Errorf(errFmtServerSecurityTXTMissingField, field))
+		}
+	}
+}
+
+func validateServerConnections(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Server.ReadTimeout == 0 {
+		config.Server.ReadTimeout = schema.DefaultServerConfiguration.ReadTimeout
+	} else if config.Server.ReadTimeout < time.Second {
+		validator.Push(fmt.Errorf(errFmtServerTimeout, "read", config.Server.ReadTimeout))
+	}
+
+	if config.Server.WriteTimeout == 0 {
+		config.Server.WriteTimeout = schema.DefaultServerConfiguration.WriteTimeout
+	} else if config.Server.WriteTimeout < time.Second {
+		validator.Push(fmt.Errorf(errFmtServerTimeout, "write", config.Server.WriteTimeout))
+	}
+
+	if config.Server.IdleTimeout == 0 {
+		config.Server.IdleTimeout = schema.DefaultServerConfiguration.IdleTimeout
+	} else if config.Server.IdleTimeout < time.Second {
+		validator.Push(fmt.Errorf(errFmtServerTimeout, "idle", config.Server.IdleTimeout))
+	}
+
+	if config.Server.MaxConnections < 0 {
+		validator.Push(fmt.Errorf(errFmtServerConnectionLimit, "max_connections", config.Server.MaxConnections))
+	}
+
+	if config.Server.MaxConnectionsPerIP < 0 {
+		validator.Push(fmt.Errorf(errFmtServerConnectionLimit, "max_connections_per_ip", config.Server.MaxConnectionsPerIP))
+	} else if config.Server.MaxConnections > 0 && config.Server.MaxConnectionsPerIP > config.Server.MaxConnections {
+		validator.Push(fmt.Errorf(errFmtServerMaxConnectionsPerIP, config.Server.MaxConnectionsPerIP, config.Server.MaxConnections))
+	}
+
+	if config.Server.MaxRequestsPerConnection < 0 {
+		validator.Push(fmt.Errorf(errFmtServerConnectionLimit, "max_requests_per_connection", config.Server.MaxRequestsPerConnection))
+	}
+}
+
+func validateServerProxyProtocol(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Server.ProxyProtocol.HeaderTimeout == 0 {
+		config.Server.ProxyProtocol.HeaderTimeout = schema.DefaultServerConfiguration.ProxyProtocol.HeaderTimeout
+	} else if config.Server.ProxyProtocol.HeaderTimeout < 0 {
+		validator.Push(fmt.Errorf(errFmtServerProxyProtocolHeaderTimeout, config.Server.ProxyProtocol.HeaderTimeout))
+	}
+
+	for _, network := range config.Server.ProxyProtocol.TrustedUpstreams {
+		if !IsNetworkValid(network) {
+			validator.Push(fmt.Errorf(errFmtServerProxyProtocolUpstreamInvalid, network))
+		}
+	}
+
+	if config.Server.ProxyProtocol.Enabled && len(config.Server.ProxyProtocol.TrustedUpstreams) == 0 {
+		validator.Push(fmt.Errorf(errFmtServerProxyProtocolNoTrustedUpstreams))
+	}
+}
+
+func validateServerHeaders(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Server.Headers.ReferrerPolicy == "" {
+		config.Server.Headers.ReferrerPolicy = schema.DefaultServerConfiguration.Headers.ReferrerPolicy
+	}
+
+	if config.Server.Headers.PermissionsPolicy == "" {
+		config.Server.Headers.PermissionsPolicy = schema.DefaultServerConfiguration.Headers.PermissionsPolicy
+	}
+
+	if config.Server.Headers.StrictTransportSecurity == "" {
+		config.Server.Headers.StrictTransportSecurity = schema.DefaultServerConfiguration.Headers.StrictTransportSecurity
+	}
+
+	switch strings.ToUpper(config.Server.Headers.FrameOptions) {
+	case "":
+		config.Server.Headers.FrameOptions = schema.DefaultServerConfiguration.Headers.FrameOptions
+	case "DENY", "SAMEORIGIN":
+		config.Server.Headers.FrameOptions = strings.ToUpper(config.Server.Headers.FrameOptions)
+	case strings.ToUpper(schema.ServerHeaderValueDisabled):
+		config.Server.Headers.FrameOptions = schema.ServerHeaderValueDisabled
+	default:
+		validator.Push(fmt.Errorf(errFmtServerHeadersFrameOptions, config.Server.Headers.FrameOptions))
+	}
 }
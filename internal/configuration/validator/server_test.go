@@ -3,6 +3,7 @@ package validator
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,11 +26,43 @@ func TestShouldSetDefaultServerValues(t *testing.T) {
 	assert.Equal(t, schema.DefaultServerConfiguration.Port, config.Server.Port)
 	assert.Equal(t, schema.DefaultServerConfiguration.ReadBufferSize, config.Server.ReadBufferSize)
 	assert.Equal(t, schema.DefaultServerConfiguration.WriteBufferSize, config.Server.WriteBufferSize)
+	assert.Equal(t, schema.DefaultServerConfiguration.ReadTimeout, config.Server.ReadTimeout)
+	assert.Equal(t, schema.DefaultServerConfiguration.WriteTimeout, config.Server.WriteTimeout)
+	assert.Equal(t, schema.DefaultServerConfiguration.IdleTimeout, config.Server.IdleTimeout)
 	assert.Equal(t, schema.DefaultServerConfiguration.TLS.Key, config.Server.TLS.Key)
 	assert.Equal(t, schema.DefaultServerConfiguration.TLS.Certificate, config.Server.TLS.Certificate)
+	assert.Equal(t, schema.DefaultServerConfiguration.TLS.MinimumVersion, config.Server.TLS.MinimumVersion)
+	assert.Equal(t, schema.DefaultServerConfiguration.TLS.MaximumVersion, config.Server.TLS.MaximumVersion)
 	assert.Equal(t, schema.DefaultServerConfiguration.Path, config.Server.Path)
 	assert.Equal(t, schema.DefaultServerConfiguration.EnableExpvars, config.Server.EnableExpvars)
 	assert.Equal(t, schema.DefaultServerConfiguration.EnablePprof, config.Server.EnablePprof)
+	assert.Equal(t, schema.DefaultServerConfiguration.Headers, config.Server.Headers)
+	assert.Equal(t, schema.DefaultServerConfiguration.RobotsTXT, config.Server.RobotsTXT)
+	assert.Equal(t, "", config.Server.SecurityTXT)
+}
+
+func TestShouldWarnWhenServerSecurityTXTMissingRequiredFields(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Server: schema.ServerConfiguration{
+			SecurityTXT: "Contact: mailto:security@example.com\nPreferred-Languages: en\n",
+		},
+	}
+
+	ValidateServer(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	require.Len(t, validator.Warnings(), 1)
+	assert.EqualError(t, validator.Warnings()[0], "server: option 'security_txt' should contain the 'Expires' field which is required by RFC9116")
+
+	validator.Clear()
+
+	config.Server.SecurityTXT = "contact: mailto:security@example.com\nexpires: 2030-01-01T00:00:00.000Z\n"
+
+	ValidateServer(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, validator.Warnings(), 0)
 }
 
 func TestShouldSetDefaultConfig(t *testing.T) {
@@ -45,6 +78,31 @@ func TestShouldSetDefaultConfig(t *testing.T) {
 	assert.Equal(t, schema.DefaultServerConfiguration.WriteBufferSize, config.Server.WriteBufferSize)
 }
 
+func TestShouldValidateServerHeaders(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Server: schema.ServerConfiguration{
+			Headers: schema.ServerHeadersConfiguration{
+				ReferrerPolicy: "no-referrer",
+				FrameOptions:   "sameorigin",
+			},
+		},
+	}
+
+	ValidateServer(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "no-referrer", config.Server.Headers.ReferrerPolicy)
+	assert.Equal(t, "SAMEORIGIN", config.Server.Headers.FrameOptions)
+
+	config.Server.Headers.FrameOptions = "ALLOW-FROM https://example.com"
+
+	ValidateServer(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "server: headers: option 'frame_options' must be one of 'DENY', 'SAMEORIGIN', or 'none' but it is configured as 'ALLOW-FROM https://example.com'")
+}
+
 func TestShouldParsePathCorrectly(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := &schema.Configuration{
@@ -266,3 +324,217 @@ func TestShouldValidateAndUpdatePort(t *testing.T) {
 	require.Len(t, validator.Errors(), 0)
 	assert.Equal(t, 9091, config.Server.Port)
 }
+
+func TestShouldRaiseErrorOnInvalidTrustedProxies(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultConfig()
+	config.Server.TrustedProxies = []string{"10.0.0.0/8", "fec0::1", "proxy.example.com"}
+
+	ValidateServer(&config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "server: option 'trusted_proxies' must only contain valid IP addresses or CIDR notations but it contains 'proxy.example.com'")
+}
+
+func TestShouldValidateServerTLSOptions(t *testing.T) {
+	testCases := []struct {
+		name             string
+		have             schema.ServerTLSConfiguration
+		errors, warnings []string
+	}{
+		{
+			"ShouldAllowValidOptions",
+			schema.ServerTLSConfiguration{MinimumVersion: "TLS1.2", MaximumVersion: "TLS1.3", CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "tls_ecdhe_ecdsa_with_aes_256_gcm_sha384"}},
+			nil,
+			nil,
+		},
+		{
+			"ShouldRaiseErrorOnInvalidVersions",
+			schema.ServerTLSConfiguration{MinimumVersion: "SSL3.0", MaximumVersion: "TLS1.4"},
+			[]string{
+				"server: tls: option 'minimum_version' with value 'SSL3.0' is invalid: supplied tls version isn't supported",
+				"server: tls: option 'maximum_version' with value 'TLS1.4' is invalid: supplied tls version isn't supported",
+			},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorOnMinimumGreaterThanMaximum",
+			schema.ServerTLSConfiguration{MinimumVersion: "TLS1.3", MaximumVersion: "TLS1.2"},
+			[]string{"server: tls: option 'minimum_version' with value 'TLS1.3' must not be greater than option 'maximum_version' with value 'TLS1.2'"},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorOnUnknownCipherSuite",
+			schema.ServerTLSConfiguration{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_BAD_CIPHER"}},
+			[]string{"server: tls: option 'cipher_suites' contains an invalid value 'TLS_BAD_CIPHER': supplied tls cipher suite isn't supported"},
+			nil,
+		},
+		{
+			"ShouldRaiseWarningOnInsecureCipherSuite",
+			schema.ServerTLSConfiguration{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+			nil,
+			[]string{"server: tls: option 'cipher_suites' contains the cipher suite 'TLS_RSA_WITH_RC4_128_SHA' which has known security issues"},
+		},
+		{
+			"ShouldRaiseWarningOnCipherSuitesWithTLS13Minimum",
+			schema.ServerTLSConfiguration{MinimumVersion: "TLS1.3", CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}},
+			nil,
+			[]string{"server: tls: option 'cipher_suites' has no effect when option 'minimum_version' is 'TLS1.3' as the TLS 1.3 cipher suites are not configurable"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{
+				Server: schema.ServerConfiguration{
+					TLS: tc.have,
+				},
+			}
+
+			ValidateServer(config, validator)
+
+			require.Len(t, validator.Errors(), len(tc.errors))
+			require.Len(t, validator.Warnings(), len(tc.warnings))
+
+			for i, err := range tc.errors {
+				assert.EqualError(t, validator.Errors()[i], err)
+			}
+
+			for i, err := range tc.warnings {
+				assert.EqualError(t, validator.Warnings()[i], err)
+			}
+		})
+	}
+}
+
+func TestShouldValidateServerProxyProtocol(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.ServerProxyProtocolConfiguration
+		expected time.Duration
+		errors   []string
+	}{
+		{
+			"ShouldSetDefaultHeaderTimeout",
+			schema.ServerProxyProtocolConfiguration{},
+			time.Second * 5,
+			nil,
+		},
+		{
+			"ShouldAllowValidOptions",
+			schema.ServerProxyProtocolConfiguration{Enabled: true, TrustedUpstreams: []string{"10.0.0.0/8", "fec0::1"}, HeaderTimeout: time.Second},
+			time.Second,
+			nil,
+		},
+		{
+			"ShouldRaiseErrorOnEnabledWithoutTrustedUpstreams",
+			schema.ServerProxyProtocolConfiguration{Enabled: true},
+			time.Second * 5,
+			[]string{"server: proxy_protocol: option 'trusted_upstreams' must be configured when the PROXY protocol is enabled"},
+		},
+		{
+			"ShouldRaiseErrorOnInvalidTrustedUpstreams",
+			schema.ServerProxyProtocolConfiguration{Enabled: true, TrustedUpstreams: []string{"10.0.0.0/8", "lb.example.com"}},
+			time.Second * 5,
+			[]string{"server: proxy_protocol: option 'trusted_upstreams' must only contain valid IP addresses or CIDR notations but it contains 'lb.example.com'"},
+		},
+		{
+			"ShouldRaiseErrorOnNegativeHeaderTimeout",
+			schema.ServerProxyProtocolConfiguration{Enabled: true, TrustedUpstreams: []string{"10.0.0.0/8"}, HeaderTimeout: -time.Second},
+			-time.Second,
+			[]string{"server: proxy_protocol: option 'header_timeout' must be above 0 but it is configured as '-1s'"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := newDefaultConfig()
+			config.Server.ProxyProtocol = tc.have
+
+			ValidateServer(&config, validator)
+
+			require.Len(t, validator.Errors(), len(tc.errors))
+
+			for i, err := range tc.errors {
+				assert.EqualError(t, validator.Errors()[i], err)
+			}
+
+			assert.Equal(t, tc.expected, config.Server.ProxyProtocol.HeaderTimeout)
+		})
+	}
+}
+
+func TestShouldValidateServerConnections(t *testing.T) {
+	testCases := []struct {
+		name   string
+		have   schema.ServerConfiguration
+		errors []string
+	}{
+		{
+			"ShouldAllowValidOptions",
+			schema.ServerConfiguration{
+				ReadTimeout:              time.Second * 10,
+				WriteTimeout:             time.Second * 10,
+				IdleTimeout:              time.Minute,
+				MaxConnections:           1000,
+				MaxConnectionsPerIP:      100,
+				MaxRequestsPerConnection: 50,
+				DisableKeepAlive:         true,
+			},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorOnTimeoutsBelowOneSecond",
+			schema.ServerConfiguration{
+				ReadTimeout:  time.Millisecond * 500,
+				WriteTimeout: -time.Second,
+				IdleTimeout:  time.Millisecond,
+			},
+			[]string{
+				"server: option 'read_timeout' must be at least 1s but it is configured as '500ms'",
+				"server: option 'write_timeout' must be at least 1s but it is configured as '-1s'",
+				"server: option 'idle_timeout' must be at least 1s but it is configured as '1ms'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnNegativeConnectionLimits",
+			schema.ServerConfiguration{
+				MaxConnections:           -1,
+				MaxConnectionsPerIP:      -1,
+				MaxRequestsPerConnection: -1,
+			},
+			[]string{
+				"server: option 'max_connections' must be 0 or more but it is configured as '-1'",
+				"server: option 'max_connections_per_ip' must be 0 or more but it is configured as '-1'",
+				"server: option 'max_requests_per_connection' must be 0 or more but it is configured as '-1'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnMaxConnectionsPerIPGreaterThanMaxConnections",
+			schema.ServerConfiguration{
+				MaxConnections:      10,
+				MaxConnectionsPerIP: 20,
+			},
+			[]string{
+				"server: option 'max_connections_per_ip' must not be greater than option 'max_connections' but it is configured as '20' and 'max_connections' is configured as '10'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{Server: tc.have}
+
+			ValidateServer(config, validator)
+
+			require.Len(t, validator.Errors(), len(tc.errors))
+
+			for i, err := range tc.errors {
+				assert.EqualError(t, validator.Errors()[i], err)
+			}
+		})
+	}
+}
@@ -15,6 +15,8 @@ func ValidateSession(config *schema.SessionConfiguration, validator *schema.Stru
 		config.Name = schema.DefaultSessionConfiguration.Name
 	}
 
+	validateSessionProvider(config, validator)
+
 	if config.Redis != nil {
 		if config.Redis.HighAvailability != nil {
 			validateRedisSentinel(config, validator)
@@ -26,6 +28,76 @@ func ValidateSession(config *schema.SessionConfiguration, validator *schema.Stru
 	validateSession(config, validator)
 }
 
+func validateSessionProvider(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	switch config.Provider {
+	case "":
+		// The provider is determined by the presence of the redis section when not configured.
+		return
+	case schema.SessionProviderRedis:
+		if config.Redis == nil {
+			validator.Push(errors.New(errFmtSessionProviderRedisNotConfigured))
+		}
+	case schema.SessionProviderMemory, schema.SessionProviderStorage:
+		if config.Redis != nil {
+			validator.Push(fmt.Errorf(errFmtSessionProviderRedisConfigured, config.Provider))
+		}
+	default:
+		validator.Push(fmt.Errorf(errFmtSessionProvider, strings.Join(validSessionProviders, "', '"), config.Provider))
+	}
+
+	if config.Provider == schema.SessionProviderStorage && config.Secret == "" {
+		validator.Push(fmt.Errorf(errFmtSessionSecretRequired, schema.SessionProviderStorage))
+	}
+}
+
+// validateSessionStorage ensures the storage session provider is only used with a storage backend which is shared
+// between instances.
+func validateSessionStorage(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Session.Provider != schema.SessionProviderStorage {
+		return
+	}
+
+	if config.Storage.PostgreSQL == nil && config.Storage.MySQL == nil {
+		validator.Push(errors.New(errFmtSessionProviderStorage))
+	}
+}
+
+// validateSessionRememberMe validates the remember me options. The remember_me_duration option is a shorthand for the
+// validity and cookie max age of remember me sessions, and the validity overrides it when only the validity is configured.
+func validateSessionRememberMe(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	if config.RememberMeDuration == schema.RememberMeDisabled {
+		return
+	}
+
+	switch {
+	case config.RememberMe.Validity < 0:
+		validator.Push(fmt.Errorf(errFmtSessionRememberMeNegative, "validity", config.RememberMe.Validity))
+	case config.RememberMe.Validity == 0:
+		// The remember_me_duration option or its default applies.
+	case config.RememberMeDuration <= 0:
+		config.RememberMeDuration = config.RememberMe.Validity
+	case config.RememberMeDuration != config.RememberMe.Validity:
+		validator.Push(fmt.Errorf(errFmtSessionRememberMeValidityConflict, config.RememberMeDuration, config.RememberMe.Validity))
+	}
+
+	if config.RememberMeDuration <= 0 {
+		config.RememberMeDuration = schema.DefaultSessionConfiguration.RememberMeDuration // 1 month.
+	}
+
+	config.RememberMe.Validity = config.RememberMeDuration
+
+	switch {
+	case config.RememberMe.CookieMaxAge == 0:
+		config.RememberMe.CookieMaxAge = config.RememberMe.Validity
+	case config.RememberMe.CookieMaxAge < 0:
+		validator.Push(fmt.Errorf(errFmtSessionRememberMeNegative, "cookie_max_age", config.RememberMe.CookieMaxAge))
+	}
+
+	if config.RememberMe.SecondFactorReverification < 0 {
+		validator.Push(fmt.Errorf(errFmtSessionRememberMeNegative, "second_factor_reverification", config.RememberMe.SecondFactorReverification))
+	}
+}
+
 func validateSession(config *schema.SessionConfiguration, validator *schema.StructValidator) {
 	if config.Expiration <= 0 {
 		config.Expiration = schema.DefaultSessionConfiguration.Expiration // 1 hour.
@@ -35,8 +107,22 @@ func validateSession(config *schema.SessionConfiguration, validator *schema.Stru
 		config.Inactivity = schema.DefaultSessionConfiguration.Inactivity // 5 min.
 	}
 
-	if config.RememberMeDuration <= 0 && config.RememberMeDuration != schema.RememberMeDisabled {
-		config.RememberMeDuration = schema.DefaultSessionConfiguration.RememberMeDuration // 1 month.
+	switch {
+	case config.InactivityWarning <= 0:
+		config.InactivityWarning = schema.DefaultSessionConfiguration.InactivityWarning // 1 min.
+
+		// Short inactivity periods would otherwise always be in the warning period.
+		if config.InactivityWarning >= config.Inactivity {
+			config.InactivityWarning = config.Inactivity / 2
+		}
+	case config.InactivityWarning >= config.Inactivity:
+		validator.Push(fmt.Errorf(errFmtSessionInactivityWarning, config.Inactivity, config.InactivityWarning))
+	}
+
+	validateSessionRememberMe(config, validator)
+
+	if config.MaximumAuthenticationAge < 0 {
+		validator.Push(fmt.Errorf(errFmtSessionMaximumAuthenticationAge, config.MaximumAuthenticationAge))
 	}
 
 	if config.Domain == "" {
@@ -52,6 +138,107 @@ func validateSession(config *schema.SessionConfiguration, validator *schema.Stru
 	} else if !utils.IsStringInSlice(config.SameSite, validSessionSameSiteValues) {
 		validator.Push(fmt.Errorf(errFmtSessionSameSite, strings.Join(validSessionSameSiteValues, "', '"), config.SameSite))
 	}
+
+	// Partitioned cookies are only sent in a cross-site context which requires the cookie to have SameSite=None.
+	if config.Partitioned && config.SameSite != "none" {
+		validator.Push(fmt.Errorf(errFmtSessionPartitionedSameSite, config.SameSite))
+	}
+
+	validateSessionDefaultRedirectionURLs(config, validator)
+	validateSessionConcurrency(config, validator)
+	validateSessionIntrospection(config, validator)
+	validateSessionBinding(config, validator)
+}
+
+func validateSessionDefaultRedirectionURLs(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	domains := make([]string, 0, len(config.DefaultRedirectionURLs))
+
+	for i, entry := range config.DefaultRedirectionURLs {
+		switch {
+		case entry.Domain == "":
+			validator.Push(fmt.Errorf(errFmtSessionDefaultRedirectionURLsOptionRequired, i+1, "domain"))
+		case config.Domain != "" && entry.Domain != config.Domain && !strings.HasSuffix(entry.Domain, "."+config.Domain):
+			validator.Push(fmt.Errorf(errFmtSessionDefaultRedirectionURLsDomain, i+1, entry.Domain))
+		case utils.IsStringInSliceFold(entry.Domain, domains):
+			validator.Push(fmt.Errorf(errFmtSessionDefaultRedirectionURLsDuplicate, i+1, entry.Domain))
+		default:
+			domains = append(domains, entry.Domain)
+		}
+
+		if entry.URL == "" {
+			validator.Push(fmt.Errorf(errFmtSessionDefaultRedirectionURLsOptionRequired, i+1, "url"))
+		} else if safe, err := utils.IsRedirectionURISafe(entry.URL, config.Domain); err != nil || !safe {
+			validator.Push(fmt.Errorf(errFmtSessionDefaultRedirectionURLsURLUnsafe, i+1, entry.URL))
+		}
+	}
+}
+
+func validateSessionConcurrency(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	if config.Concurrency.Limit < 0 {
+		validator.Push(fmt.Errorf(errFmtSessionConcurrencyLimitNegative, "limit", config.Concurrency.Limit))
+	}
+
+	if config.Concurrency.RememberMeLimit < 0 {
+		validator.Push(fmt.Errorf(errFmtSessionConcurrencyLimitNegative, "remember_me_limit", config.Concurrency.RememberMeLimit))
+	}
+
+	if config.Concurrency.Policy == "" {
+		config.Concurrency.Policy = schema.DefaultSessionConfiguration.Concurrency.Policy
+	} else if !utils.IsStringInSlice(config.Concurrency.Policy, validSessionConcurrencyPolicies) {
+		validator.Push(fmt.Errorf(errFmtSessionConcurrencyPolicy, strings.Join(validSessionConcurrencyPolicies, "', '"), config.Concurrency.Policy))
+	}
+}
+
+func validateSessionBinding(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	if config.Binding.Mode == "" {
+		config.Binding.Mode = schema.DefaultSessionConfiguration.Binding.Mode
+	} else if !utils.IsStringInSlice(config.Binding.Mode, validSessionBindingModes) {
+		validator.Push(fmt.Errorf(errFmtSessionBindingMode, strings.Join(validSessionBindingModes, "', '"), config.Binding.Mode))
+	}
+
+	switch {
+	case config.Binding.IPv4Mask == 0:
+		config.Binding.IPv4Mask = schema.DefaultSessionConfiguration.Binding.IPv4Mask
+	case config.Binding.IPv4Mask < 0 || config.Binding.IPv4Mask > 32:
+		validator.Push(fmt.Errorf(errFmtSessionBindingMask, "ipv4_mask", 32, config.Binding.IPv4Mask))
+	}
+
+	switch {
+	case config.Binding.IPv6Mask == 0:
+		config.Binding.IPv6Mask = schema.DefaultSessionConfiguration.Binding.IPv6Mask
+	case config.Binding.IPv6Mask < 0 || config.Binding.IPv6Mask > 128:
+		validator.Push(fmt.Errorf(errFmtSessionBindingMask, "ipv6_mask", 128, config.Binding.IPv6Mask))
+	}
+}
+
+func validateSessionIntrospection(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	if !config.Introspection.Enabled {
+		return
+	}
+
+	if len(config.Introspection.TrustedNetworks) == 0 && config.Introspection.Secret == "" {
+		validator.Push(errors.New(errFmtSessionIntrospectionRestriction))
+	}
+
+	for _, network := range config.Introspection.TrustedNetworks {
+		if _, err := utils.ParseNetwork(network); err != nil {
+			validator.Push(fmt.Errorf(errFmtSessionIntrospectionTrustedNetwork, network))
+		}
+	}
+
+	switch {
+	case config.Introspection.RateLimit < 0:
+		validator.Push(fmt.Errorf(errFmtSessionIntrospectionRateLimitNegative, config.Introspection.RateLimit))
+	case config.Introspection.RateLimit == 0:
+		config.Introspection.RateLimit = schema.DefaultSessionConfiguration.Introspection.RateLimit
+	}
+
+	switch {
+	case config.Introspection.RateLimitPeriod < 0:
+		validator.Push(fmt.Errorf(errFmtSessionIntrospectionRateLimitPeriodNegative, config.Introspection.RateLimitPeriod))
+	case config.Introspection.RateLimitPeriod == 0:
+		config.Introspection.RateLimitPeriod = schema.DefaultSessionConfiguration.Introspection.RateLimitPeriod
+	}
 }
 
 func validateRedisCommon(config *schema.SessionConfiguration, validator *schema.StructValidator) {
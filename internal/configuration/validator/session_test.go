@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,6 +29,7 @@ func TestShouldSetDefaultSessionValues(t *testing.T) {
 	assert.False(t, validator.HasErrors())
 	assert.Equal(t, schema.DefaultSessionConfiguration.Name, config.Name)
 	assert.Equal(t, schema.DefaultSessionConfiguration.Inactivity, config.Inactivity)
+	assert.Equal(t, schema.DefaultSessionConfiguration.InactivityWarning, config.InactivityWarning)
 	assert.Equal(t, schema.DefaultSessionConfiguration.Expiration, config.Expiration)
 	assert.Equal(t, schema.DefaultSessionConfiguration.RememberMeDuration, config.RememberMeDuration)
 	assert.Equal(t, schema.DefaultSessionConfiguration.SameSite, config.SameSite)
@@ -406,6 +408,27 @@ func TestShouldNotRaiseErrorWhenSameSiteSetCorrectly(t *testing.T) {
 	}
 }
 
+func TestShouldRaiseErrorWhenPartitionedWithoutSameSiteNone(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Partitioned = true
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "session: option 'partitioned' must only be enabled when option 'same_site' is 'none' but it's configured as 'lax'")
+
+	validator.Clear()
+
+	config.SameSite = "none"
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.Len(t, validator.Errors(), 0)
+}
+
 func TestShouldSetDefaultWhenNegativeAndNotOverrideDisabledRememberMe(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
@@ -433,3 +456,324 @@ func TestShouldSetDefaultRememberMeDuration(t *testing.T) {
 	assert.False(t, validator.HasErrors())
 	assert.Equal(t, config.RememberMeDuration, schema.DefaultSessionConfiguration.RememberMeDuration)
 }
+
+func TestShouldSetRememberMeValuesFromRememberMeDuration(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.RememberMeDuration = time.Hour * 24 * 7
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.False(t, validator.HasErrors())
+	assert.Equal(t, time.Hour*24*7, config.RememberMe.Validity)
+	assert.Equal(t, time.Hour*24*7, config.RememberMe.CookieMaxAge)
+	assert.Equal(t, time.Duration(0), config.RememberMe.SecondFactorReverification)
+}
+
+func TestShouldSetRememberMeDurationFromValidity(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.RememberMe = schema.SessionRememberMeConfiguration{
+		Validity:                   time.Hour * 24 * 14,
+		CookieMaxAge:               time.Hour * 24,
+		SecondFactorReverification: time.Hour * 8,
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.False(t, validator.HasErrors())
+	assert.Equal(t, time.Hour*24*14, config.RememberMeDuration)
+	assert.Equal(t, time.Hour*24*14, config.RememberMe.Validity)
+	assert.Equal(t, time.Hour*24, config.RememberMe.CookieMaxAge)
+	assert.Equal(t, time.Hour*8, config.RememberMe.SecondFactorReverification)
+}
+
+func TestShouldRaiseErrorsWhenRememberMeIncorrectlyConfigured(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.RememberMeDuration = time.Hour * 24
+	config.RememberMe = schema.SessionRememberMeConfiguration{
+		Validity:                   time.Hour * 48,
+		CookieMaxAge:               -time.Hour,
+		SecondFactorReverification: -time.Minute,
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "session: remember_me: option 'validity' must be the same as the 'session' option 'remember_me_duration' '24h0m0s' when both are configured but it is configured as '48h0m0s'")
+	assert.EqualError(t, validator.Errors()[1], "session: remember_me: option 'cookie_max_age' must be 0 or more but it is configured as '-1h0m0s'")
+	assert.EqualError(t, validator.Errors()[2], "session: remember_me: option 'second_factor_reverification' must be 0 or more but it is configured as '-1m0s'")
+
+	validator.Clear()
+
+	config = newDefaultSessionConfig()
+	config.RememberMe.Validity = -time.Hour
+
+	ValidateSession(&config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "session: remember_me: option 'validity' must be 0 or more but it is configured as '-1h0m0s'")
+}
+
+func TestShouldSetDefaultSessionConcurrencyPolicy(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Concurrency.Limit = 3
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.False(t, validator.HasErrors())
+	assert.Equal(t, schema.SessionConcurrencyPolicyEvictOldest, config.Concurrency.Policy)
+}
+
+func TestShouldRaiseErrorsWhenSessionConcurrencyIncorrectlyConfigured(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Concurrency = schema.SessionConcurrencyConfiguration{
+		Limit:           -1,
+		RememberMeLimit: -2,
+		Policy:          "evict_newest",
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "session: concurrency: option 'limit' must be 0 or more but it is configured as '-1'")
+	assert.EqualError(t, validator.Errors()[1], "session: concurrency: option 'remember_me_limit' must be 0 or more but it is configured as '-2'")
+	assert.EqualError(t, validator.Errors()[2], "session: concurrency: option 'policy' must be one of 'reject', 'evict_oldest' but it is configured as 'evict_newest'")
+}
+
+func TestShouldSetDefaultSessionIntrospectionRateLimit(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Introspection = schema.SessionIntrospectionConfiguration{
+		Enabled:         true,
+		TrustedNetworks: []string{"10.0.0.0/8", "192.168.1.20"},
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.False(t, validator.HasErrors())
+	assert.Equal(t, 60, config.Introspection.RateLimit)
+	assert.Equal(t, time.Minute, config.Introspection.RateLimitPeriod)
+}
+
+func TestShouldRaiseErrorsWhenSessionIntrospectionIncorrectlyConfigured(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Introspection = schema.SessionIntrospectionConfiguration{
+		Enabled:         true,
+		RateLimit:       -1,
+		RateLimitPeriod: -time.Second,
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "session: introspection: option 'trusted_networks' or 'secret' must be configured when the endpoint is enabled")
+	assert.EqualError(t, validator.Errors()[1], "session: introspection: option 'rate_limit' must be 0 or more but it is configured as '-1'")
+	assert.EqualError(t, validator.Errors()[2], "session: introspection: option 'rate_limit_period' must be 0 or more but it is configured as '-1s'")
+
+	validator.Clear()
+
+	config.Introspection = schema.SessionIntrospectionConfiguration{
+		Enabled:         true,
+		TrustedNetworks: []string{"10.0.0.0/8", "not-a-network"},
+	}
+
+	ValidateSession(&config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "session: introspection: option 'trusted_networks' must only contain valid IP addresses or CIDR notations but it contains 'not-a-network'")
+}
+
+func TestShouldRaiseErrorWhenMaximumAuthenticationAgeNegative(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.MaximumAuthenticationAge = -time.Hour
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "session: option 'maximum_authentication_age' must be 0 or more but it is configured as '-1h0m0s'")
+}
+
+func TestShouldSetDefaultInactivityWarningWithinInactivity(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Inactivity = time.Second * 30
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.False(t, validator.HasErrors())
+	assert.Equal(t, time.Second*15, config.InactivityWarning)
+}
+
+func TestShouldRaiseErrorWhenInactivityWarningExceedsInactivity(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.InactivityWarning = time.Minute * 5
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "session: option 'inactivity_warning' must be less than the 'inactivity' option '5m0s' but it is configured as '5m0s'")
+}
+
+func TestShouldValidateSessionDefaultRedirectionURLs(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+
+	config.DefaultRedirectionURLs = []schema.SessionDefaultRedirectionURLConfiguration{
+		{Domain: "example.com", URL: "https://home.example.com"},
+		{Domain: "dev.example.com", URL: "https://dashboard.dev.example.com/path"},
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.False(t, validator.HasErrors())
+}
+
+func TestShouldRaiseErrorsWhenSessionDefaultRedirectionURLsInvalid(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+
+	config.DefaultRedirectionURLs = []schema.SessionDefaultRedirectionURLConfiguration{
+		{URL: "https://home.example.com"},
+		{Domain: "example.org", URL: "https://home.example.org"},
+		{Domain: "dev.example.com", URL: "http://dashboard.dev.example.com"},
+		{Domain: "Dev.example.com"},
+	}
+
+	ValidateSession(&config, validator)
+
+	require.Len(t, validator.Errors(), 6)
+	assert.EqualError(t, validator.Errors()[0], "session: default_redirection_urls: entry #1: option 'domain' is required")
+	assert.EqualError(t, validator.Errors()[1], "session: default_redirection_urls: entry #2: option 'domain' must be the 'session' option 'domain' or a subdomain of it but it is configured as 'example.org'")
+	assert.EqualError(t, validator.Errors()[2], "session: default_redirection_urls: entry #2: option 'url' must be a https URL on the 'session' option 'domain' but it is configured as 'https://home.example.org'")
+	assert.EqualError(t, validator.Errors()[3], "session: default_redirection_urls: entry #3: option 'url' must be a https URL on the 'session' option 'domain' but it is configured as 'http://dashboard.dev.example.com'")
+	assert.EqualError(t, validator.Errors()[4], "session: default_redirection_urls: entry #4: option 'domain' must be unique but 'Dev.example.com' is configured more than once")
+	assert.EqualError(t, validator.Errors()[5], "session: default_redirection_urls: entry #4: option 'url' is required")
+}
+
+func TestShouldValidateSessionProvider(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+
+	config.Provider = schema.SessionProviderStorage
+
+	ValidateSession(&config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+
+	validator.Clear()
+
+	config.Provider = schema.SessionProviderRedis
+	config.Redis = &schema.RedisSessionConfiguration{
+		Host: "redis.localhost",
+		Port: 6379,
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestShouldRaiseErrorsWhenSessionProviderInvalid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		provider string
+		redis    bool
+		secret   string
+		expected string
+	}{
+		{"ShouldRaiseErrorOnUnknownProvider", "memcached", false, testJWTSecret, "session: option 'provider' must be one of 'memory', 'redis', 'storage' but it is configured as 'memcached'"},
+		{"ShouldRaiseErrorOnRedisWithoutSection", schema.SessionProviderRedis, false, testJWTSecret, "session: option 'provider' is configured as 'redis' but the 'redis' section is not configured"},
+		{"ShouldRaiseErrorOnMemoryWithRedisSection", schema.SessionProviderMemory, true, testJWTSecret, "session: option 'provider' is configured as 'memory' but the 'redis' section is also configured"},
+		{"ShouldRaiseErrorOnStorageWithRedisSection", schema.SessionProviderStorage, true, testJWTSecret, "session: option 'provider' is configured as 'storage' but the 'redis' section is also configured"},
+		{"ShouldRaiseErrorOnStorageWithoutSecret", schema.SessionProviderStorage, false, "", "session: option 'secret' is required when using the 'storage' provider"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := newDefaultSessionConfig()
+
+			config.Provider, config.Secret = tc.provider, tc.secret
+
+			if tc.redis {
+				config.Redis = &schema.RedisSessionConfiguration{
+					Host: "redis.localhost",
+					Port: 6379,
+				}
+			}
+
+			ValidateSession(&config, validator)
+
+			require.Len(t, validator.Errors(), 1)
+			assert.EqualError(t, validator.Errors()[0], tc.expected)
+		})
+	}
+}
+
+func TestShouldRaiseErrorWhenSessionStorageProviderWithoutSharedStorage(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Session: schema.SessionConfiguration{Provider: schema.SessionProviderStorage},
+		Storage: schema.StorageConfiguration{Local: &schema.LocalStorageConfiguration{Path: "/tmp/db.sqlite3"}},
+	}
+
+	validateSessionStorage(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "session: option 'provider' is configured as 'storage' which requires the 'postgres' or 'mysql' storage provider")
+
+	validator.Clear()
+
+	config.Storage = schema.StorageConfiguration{PostgreSQL: &schema.PostgreSQLStorageConfiguration{}}
+
+	validateSessionStorage(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestShouldSetDefaultSessionBindingValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasErrors())
+	assert.Equal(t, schema.DefaultSessionConfiguration.Binding, config.Binding)
+}
+
+func TestShouldRaiseErrorsWhenSessionBindingIncorrectlyConfigured(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Binding = schema.SessionBindingConfiguration{
+		Mode:     "ip",
+		IPv4Mask: 33,
+		IPv6Mask: -1,
+	}
+
+	ValidateSession(&config, validator)
+
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "session: binding: option 'mode' must be one of 'none', 'user_agent', 'network', 'user_agent_and_network' but it is configured as 'ip'")
+	assert.EqualError(t, validator.Errors()[1], "session: binding: option 'ipv4_mask' must be between 1 and 32 but it is configured as '33'")
+	assert.EqualError(t, validator.Errors()[2], "session: binding: option 'ipv6_mask' must be between 1 and 128 but it is configured as '-1'")
+}
@@ -10,7 +10,7 @@ import (
 )
 
 // ValidateStorage validates storage configuration.
-func ValidateStorage(config schema.StorageConfiguration, validator *schema.StructValidator) {
+func ValidateStorage(config *schema.StorageConfiguration, validator *schema.StructValidator) {
 	if config.Local == nil && config.MySQL == nil && config.PostgreSQL == nil {
 		validator.Push(errors.New(errStrStorage))
 	}
@@ -29,6 +29,48 @@ func ValidateStorage(config schema.StorageConfiguration, validator *schema.Struc
 	} else if len(config.EncryptionKey) < 20 {
 		validator.Push(errors.New(errStrStorageEncryptionKeyTooShort))
 	}
+
+	validateStorageCleanup(&config.Cleanup, validator)
+	validateStorageConnectionRetry(&config.ConnectionRetry, validator)
+}
+
+func validateStorageCleanup(config *schema.StorageCleanupConfiguration, validator *schema.StructValidator) {
+	switch {
+	case config.Interval == 0:
+		config.Interval = schema.DefaultStorageCleanupConfiguration.Interval
+	case config.Interval < 0:
+		validator.Push(fmt.Errorf(errFmtStorageCleanupNegative, "interval", config.Interval))
+	}
+
+	switch {
+	case config.BatchSize == 0:
+		config.BatchSize = schema.DefaultStorageCleanupConfiguration.BatchSize
+	case config.BatchSize < 0:
+		validator.Push(fmt.Errorf(errFmtStorageCleanupNegative, "batch_size", config.BatchSize))
+	}
+}
+
+func validateStorageConnectionRetry(config *schema.StorageConnectionRetryConfiguration, validator *schema.StructValidator) {
+	switch {
+	case config.Interval == 0:
+		config.Interval = schema.DefaultStorageConnectionRetryConfiguration.Interval
+	case config.Interval < 0:
+		validator.Push(fmt.Errorf(errFmtStorageConnectionRetryNegative, "interval", config.Interval))
+	}
+
+	switch {
+	case config.BackoffFactor == 0:
+		config.BackoffFactor = schema.DefaultStorageConnectionRetryConfiguration.BackoffFactor
+	case config.BackoffFactor < 1:
+		validator.Push(fmt.Errorf(errFmtStorageConnectionRetryBackoffFactor, config.BackoffFactor))
+	}
+
+	switch {
+	case config.MaxDuration == 0:
+		config.MaxDuration = schema.DefaultStorageConnectionRetryConfiguration.MaxDuration
+	case config.MaxDuration < 0:
+		validator.Push(fmt.Errorf(errFmtStorageConnectionRetryNegative, "max_duration", config.MaxDuration))
+	}
 }
 
 func validateSQLConfiguration(config *schema.SQLStorageConfiguration, validator *schema.StructValidator, provider string) {
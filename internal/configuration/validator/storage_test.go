@@ -2,6 +2,7 @@ package validator
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
@@ -20,6 +21,8 @@ func (suite *StorageSuite) SetupTest() {
 	suite.config.Local = nil
 	suite.config.PostgreSQL = nil
 	suite.config.MySQL = nil
+	suite.config.Cleanup = schema.StorageCleanupConfiguration{}
+	suite.config.ConnectionRetry = schema.StorageConnectionRetryConfiguration{}
 }
 
 func (suite *StorageSuite) TestShouldValidateOneStorageIsConfigured() {
@@ -27,7 +30,7 @@ func (suite *StorageSuite) TestShouldValidateOneStorageIsConfigured() {
 	suite.config.PostgreSQL = nil
 	suite.config.MySQL = nil
 
-	ValidateStorage(suite.config, suite.validator)
+	ValidateStorage(&suite.config, suite.validator)
 
 	suite.Require().Len(suite.validator.Warnings(), 0)
 	suite.Require().Len(suite.validator.Errors(), 1)
@@ -39,7 +42,7 @@ func (suite *StorageSuite) TestShouldValidateLocalPathIsProvided() {
 		Path: "",
 	}
 
-	ValidateStorage(suite.config, suite.validator)
+	ValidateStorage(&suite.config, suite.validator)
 
 	suite.Require().Len(suite.validator.Warnings(), 0)
 	suite.Require().Len(suite.validator.Errors(), 1)
@@ -49,7 +52,7 @@ func (suite *StorageSuite) TestShouldValidateLocalPathIsProvided() {
 	suite.validator.Clear()
 	suite.config.Local.Path = "/myapth"
 
-	ValidateStorage(suite.config, suite.validator)
+	ValidateStorage(&suite.config, suite.validator)
 
 	suite.Require().Len(suite.validator.Warnings(), 0)
 	suite.Require().Len(suite.validator.Errors(), 0)
@@ -57,7 +60,7 @@ func (suite *StorageSuite) TestShouldValidateLocalPathIsProvided() {
 
 func (suite *StorageSuite) TestShouldValidateMySQLHostUsernamePasswordAndDatabaseAreProvided() {
 	suite.config.MySQL = &schema.MySQLStorageConfiguration{}
-	ValidateStorage(suite.config, suite.validator)
+	ValidateStorage(&suite.config, suite.validator)
 
 	suite.Require().Len(suite.validator.Errors(), 3)
 	suite.Assert().EqualError(suite.validator.Errors()[0], "storage: mysql: option 'host' is required")
@@ -73,7 +76,7 @@ func (suite *StorageSuite) TestShouldValidateMySQLHostUsernamePasswordAndDatabas
 			Database: "database",
 		},
 	}
-	ValidateStorage(suite.config, suite.validator)
+	ValidateStorage(&suite.config, suite.validator)
 
 	suite.Require().Len(suite.validator.Warnings(), 0)
 	suite.Require().Len(suite.validator.Errors(), 0)
@@ -82,7 +85,7 @@ func (suite *StorageSuite) TestShouldValidateMySQLHostUsernamePasswordAndDatabas
 func (suite *StorageSuite) TestShouldValidatePostgreSQLHostUsernamePasswordAndDatabaseAreProvided() {
 	suite.config.PostgreSQL = &schema.PostgreSQLStorageConfiguration{}
 	suite.config.MySQL = nil
-	ValidateStorage(suite.config, suite.validator)
+	ValidateStorage(&suite.config, suite.validator)
 
 	suite.Require().Len(suite.validator.Errors(), 3)
 	suite.Assert().EqualError(suite.validator.Errors()[0], "storage: postgres: option 'host' is required")
@@ -98,7 +101,7 @@ func (suite *StorageSuite) TestShouldValidatePostgreSQLHostUsernamePasswordAndDa
 			Database: "database",
 		},
 	}
-	ValidateStorage(suite.config, suite.validator)
+	ValidateStorage(&suite.config, suite.validator)
 
 	suite.Assert().Len(suite.validator.Warnings(), 0)
 	suite.Assert().Len(suite.validator.Errors(), 0)
@@ -114,7 +117,7 @@ func (suite *StorageSuite) TestShouldValidatePostgresSSLModeAndSchemaDefaults()
 		},
 	}
 
-	ValidateStorage(suite.config, suite.validator)
+	ValidateStorage(&suite.config, suite.validator)
 
 	suite.Assert().Len(suite.validator.Warnings(), 0)
 	suite.Assert().Len(suite.validator.Errors(), 0)
@@ -137,7 +140,7 @@ func (suite *StorageSuite) TestShouldValidatePostgresDefaultsDontOverrideConfigu
 		},
 	}
 
-	ValidateStorage(suite.config, suite.validator)
+	ValidateStorage(&suite.config, suite.validator)
 
 	suite.Assert().Len(suite.validator.Warnings(), 0)
 	suite.Assert().Len(suite.validator.Errors(), 0)
@@ -159,7 +162,7 @@ func (suite *StorageSuite) TestShouldValidatePostgresSSLModeMustBeValid() {
 		},
 	}
 
-	ValidateStorage(suite.config, suite.validator)
+	ValidateStorage(&suite.config, suite.validator)
 
 	suite.Assert().Len(suite.validator.Warnings(), 0)
 	suite.Require().Len(suite.validator.Errors(), 1)
@@ -178,7 +181,7 @@ func (suite *StorageSuite) TestShouldValidatePostgresSSLModeMustBeMappedForDepre
 		SSLMode: "require",
 	}
 
-	ValidateStorage(suite.config, suite.validator)
+	ValidateStorage(&suite.config, suite.validator)
 
 	suite.Assert().Len(suite.validator.Warnings(), 0)
 	suite.Assert().Len(suite.validator.Errors(), 0)
@@ -192,7 +195,7 @@ func (suite *StorageSuite) TestShouldRaiseErrorOnNoEncryptionKey() {
 		Path: "/this/is/a/path",
 	}
 
-	ValidateStorage(suite.config, suite.validator)
+	ValidateStorage(&suite.config, suite.validator)
 
 	suite.Require().Len(suite.validator.Warnings(), 0)
 	suite.Require().Len(suite.validator.Errors(), 1)
@@ -205,13 +208,78 @@ func (suite *StorageSuite) TestShouldRaiseErrorOnShortEncryptionKey() {
 		Path: "/this/is/a/path",
 	}
 
-	ValidateStorage(suite.config, suite.validator)
+	ValidateStorage(&suite.config, suite.validator)
 
 	suite.Require().Len(suite.validator.Warnings(), 0)
 	suite.Require().Len(suite.validator.Errors(), 1)
 	suite.Assert().EqualError(suite.validator.Errors()[0], "storage: option 'encryption_key' must be 20 characters or longer")
 }
 
+func (suite *StorageSuite) TestShouldSetDefaultCleanupValues() {
+	suite.config.Local = &schema.LocalStorageConfiguration{
+		Path: "/this/is/a/path",
+	}
+
+	ValidateStorage(&suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.DefaultStorageCleanupConfiguration.Interval, suite.config.Cleanup.Interval)
+	suite.Assert().Equal(schema.DefaultStorageCleanupConfiguration.BatchSize, suite.config.Cleanup.BatchSize)
+}
+
+func (suite *StorageSuite) TestShouldRaiseErrorOnNegativeCleanupValues() {
+	suite.config.Cleanup = schema.StorageCleanupConfiguration{
+		Interval:  -time.Minute,
+		BatchSize: -1,
+	}
+	suite.config.Local = &schema.LocalStorageConfiguration{
+		Path: "/this/is/a/path",
+	}
+
+	ValidateStorage(&suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+	suite.Assert().EqualError(suite.validator.Errors()[0], "storage: cleanup: option 'interval' must be 0 or more but it is configured as '-1m0s'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "storage: cleanup: option 'batch_size' must be 0 or more but it is configured as '-1'")
+}
+
+func (suite *StorageSuite) TestShouldSetDefaultConnectionRetryValues() {
+	suite.config.Local = &schema.LocalStorageConfiguration{
+		Path: "/this/is/a/path",
+	}
+
+	ValidateStorage(&suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.DefaultStorageConnectionRetryConfiguration.Interval, suite.config.ConnectionRetry.Interval)
+	suite.Assert().Equal(schema.DefaultStorageConnectionRetryConfiguration.BackoffFactor, suite.config.ConnectionRetry.BackoffFactor)
+	suite.Assert().Equal(schema.DefaultStorageConnectionRetryConfiguration.MaxDuration, suite.config.ConnectionRetry.MaxDuration)
+}
+
+func (suite *StorageSuite) TestShouldRaiseErrorOnInvalidConnectionRetryValues() {
+	suite.config.ConnectionRetry = schema.StorageConnectionRetryConfiguration{
+		Interval:      -time.Second,
+		BackoffFactor: 0.5,
+		MaxDuration:   -time.Minute,
+	}
+	suite.config.Local = &schema.LocalStorageConfiguration{
+		Path: "/this/is/a/path",
+	}
+
+	ValidateStorage(&suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 3)
+	suite.Assert().EqualError(suite.validator.Errors()[0], "storage: connection_retry: option 'interval' must be 0 or more but it is configured as '-1s'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "storage: connection_retry: option 'backoff_factor' must be 1 or more but it is configured as '0.5'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "storage: connection_retry: option 'max_duration' must be 0 or more but it is configured as '-1m0s'")
+}
+
 func TestShouldRunStorageSuite(t *testing.T) {
 	suite.Run(t, new(StorageSuite))
 }
@@ -17,4 +17,12 @@ func ValidateTheme(config *schema.Configuration, validator *schema.StructValidat
 	if !utils.IsStringInSlice(config.Theme, validThemeNames) {
 		validator.Push(fmt.Errorf(errFmtThemeName, strings.Join(validThemeNames, "', '"), config.Theme))
 	}
+
+	if config.ThemeFallback == "" {
+		config.ThemeFallback = "light"
+	}
+
+	if !utils.IsStringInSlice(config.ThemeFallback, validThemeFallbackNames) {
+		validator.Push(fmt.Errorf(errFmtThemeFallbackName, strings.Join(validThemeFallbackNames, "', '"), config.ThemeFallback))
+	}
 }
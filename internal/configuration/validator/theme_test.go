@@ -39,6 +39,29 @@ func (suite *Theme) TestShouldRaiseErrorWhenInvalidThemeProvided() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], "option 'theme' must be one of 'light', 'dark', 'grey', 'auto' but it is configured as 'invalid'")
 }
 
+func (suite *Theme) TestShouldSetDefaultThemeFallback() {
+	suite.config.Theme = "auto"
+
+	ValidateTheme(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal("light", suite.config.ThemeFallback)
+}
+
+func (suite *Theme) TestShouldRaiseErrorWhenInvalidThemeFallbackProvided() {
+	suite.config.Theme = "auto"
+	suite.config.ThemeFallback = "auto"
+
+	ValidateTheme(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "option 'theme_fallback' must be one of 'light', 'dark', 'grey' but it is configured as 'auto'")
+}
+
 func TestThemes(t *testing.T) {
 	suite.Run(t, new(Theme))
 }
@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"strings"
+	"unicode"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -14,8 +15,19 @@ func ValidateTOTP(config *schema.Configuration, validator *schema.StructValidato
 		return
 	}
 
-	if config.TOTP.Issuer == "" {
+	switch {
+	case config.TOTP.Issuer == "" && config.Session.Domain != "":
+		config.TOTP.Issuer = config.Session.Domain
+	case config.TOTP.Issuer == "":
 		config.TOTP.Issuer = schema.DefaultTOTPConfiguration.Issuer
+	case !isValidTOTPLabelComponent(config.TOTP.Issuer):
+		validator.Push(fmt.Errorf(errFmtTOTPInvalidIssuer, config.TOTP.Issuer))
+	}
+
+	if config.TOTP.AccountLabel == "" {
+		config.TOTP.AccountLabel = schema.DefaultTOTPConfiguration.AccountLabel
+	} else {
+		validateTOTPAccountLabel(config.TOTP.AccountLabel, validator)
 	}
 
 	if config.TOTP.Algorithm == "" {
@@ -50,3 +62,27 @@ func ValidateTOTP(config *schema.Configuration, validator *schema.StructValidato
 		validator.Push(fmt.Errorf(errFmtTOTPInvalidSecretSize, schema.TOTPSecretSizeMinimum, config.TOTP.SecretSize))
 	}
 }
+
+func validateTOTPAccountLabel(label string, validator *schema.StructValidator) {
+	for _, match := range regexpTOTPAccountLabelPlaceholder.FindAllStringSubmatch(label, -1) {
+		if !utils.IsStringInSlice(match[1], schema.TOTPAccountLabelPlaceholders) {
+			validator.Push(fmt.Errorf(errFmtTOTPInvalidAccountLabelPlaceholder, strings.Join(schema.TOTPAccountLabelPlaceholders, "', '"), match[1]))
+		}
+	}
+
+	if !isValidTOTPLabelComponent(regexpTOTPAccountLabelPlaceholder.ReplaceAllString(label, "")) {
+		validator.Push(fmt.Errorf(errFmtTOTPInvalidAccountLabel, label))
+	}
+}
+
+// isValidTOTPLabelComponent returns false if the value contains the ':' character which separates the issuer from the
+// account name in the otpauth URI label, or any character which isn't printable.
+func isValidTOTPLabelComponent(value string) bool {
+	for _, r := range value {
+		if r == ':' || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+
+	return true
+}
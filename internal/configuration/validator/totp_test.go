@@ -14,6 +14,7 @@ func TestValidateTOTP(t *testing.T) {
 	testCases := []struct {
 		desc     string
 		have     schema.TOTPConfiguration
+		domain   string
 		expected schema.TOTPConfiguration
 		errs     []string
 		warns    []string
@@ -22,6 +23,45 @@ func TestValidateTOTP(t *testing.T) {
 			desc:     "ShouldSetDefaultTOTPValues",
 			expected: schema.DefaultTOTPConfiguration,
 		},
+		{
+			desc:   "ShouldSetDefaultTOTPIssuerToSessionDomain",
+			domain: "example.com",
+			expected: schema.TOTPConfiguration{
+				Issuer:       "example.com",
+				AccountLabel: "{username}",
+				Algorithm:    schema.TOTPAlgorithmSHA1,
+				Period:       30,
+				SecretSize:   schema.TOTPSecretSizeDefault,
+				Skew:         schema.DefaultTOTPConfiguration.Skew,
+			},
+		},
+		{
+			desc: "ShouldAllowAccountLabelPlaceholders",
+			have: schema.TOTPConfiguration{
+				Issuer:       "abc",
+				AccountLabel: "{displayname} ({email})",
+			},
+			expected: schema.TOTPConfiguration{
+				Issuer:       "abc",
+				AccountLabel: "{displayname} ({email})",
+				Algorithm:    schema.TOTPAlgorithmSHA1,
+				Period:       30,
+				SecretSize:   schema.TOTPSecretSizeDefault,
+				Skew:         schema.DefaultTOTPConfiguration.Skew,
+			},
+		},
+		{
+			desc: "ShouldRaiseErrorWhenInvalidTOTPIssuerAndAccountLabel",
+			have: schema.TOTPConfiguration{
+				Issuer:       "abc:def",
+				AccountLabel: "{username}:{groups}",
+			},
+			errs: []string{
+				"totp: option 'issuer' must not contain the ':' character or non-printable characters but it is configured as 'abc:def'",
+				"totp: option 'account_label' must only contain the placeholders 'username', 'email', 'displayname' but it contains the placeholder 'groups'",
+				"totp: option 'account_label' must not contain the ':' character or non-printable characters outside of placeholders but it is configured as '{username}:{groups}'",
+			},
+		},
 		{
 			desc:     "ShouldNotSetDefaultTOTPValuesWhenDisabled",
 			have:     schema.TOTPConfiguration{Disable: true},
@@ -38,12 +78,13 @@ func TestValidateTOTP(t *testing.T) {
 				Issuer:     "abc",
 			},
 			expected: schema.TOTPConfiguration{
-				Algorithm:  "SHA1",
-				Digits:     6,
-				Period:     30,
-				SecretSize: 32,
-				Skew:       schema.DefaultTOTPConfiguration.Skew,
-				Issuer:     "abc",
+				Algorithm:    "SHA1",
+				Digits:       6,
+				Period:       30,
+				SecretSize:   32,
+				Skew:         schema.DefaultTOTPConfiguration.Skew,
+				Issuer:       "abc",
+				AccountLabel: "{username}",
 			},
 		},
 		{
@@ -80,7 +121,7 @@ func TestValidateTOTP(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
 			validator := schema.NewStructValidator()
-			config := &schema.Configuration{TOTP: tc.have}
+			config := &schema.Configuration{TOTP: tc.have, Session: schema.SessionConfiguration{Domain: tc.domain}}
 
 			ValidateTOTP(config, validator)
 
@@ -92,6 +133,7 @@ func TestValidateTOTP(t *testing.T) {
 				assert.Len(t, warns, 0)
 				assert.Equal(t, tc.expected.Disable, config.TOTP.Disable)
 				assert.Equal(t, tc.expected.Issuer, config.TOTP.Issuer)
+				assert.Equal(t, tc.expected.AccountLabel, config.TOTP.AccountLabel)
 				assert.Equal(t, tc.expected.Algorithm, config.TOTP.Algorithm)
 				assert.Equal(t, tc.expected.Skew, config.TOTP.Skew)
 				assert.Equal(t, tc.expected.Period, config.TOTP.Period)
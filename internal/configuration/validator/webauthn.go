@@ -2,6 +2,7 @@ package validator
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -31,4 +32,29 @@ func ValidateWebauthn(config *schema.Configuration, validator *schema.StructVali
 	case !utils.IsStringInSlice(string(config.Webauthn.UserVerification), validWebauthnUserVerificationRequirement):
 		validator.Push(fmt.Errorf(errFmtWebauthnUserVerification, config.Webauthn.UserVerification))
 	}
+
+	validateWebauthnRelyingParty(config, validator)
+}
+
+func validateWebauthnRelyingParty(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Webauthn.RPID != "" {
+		if rpid, err := url.Parse(config.Webauthn.RPID); err != nil || !utils.IsURLHostComponent(*rpid) || strings.Contains(config.Webauthn.RPID, "/") {
+			validator.Push(fmt.Errorf(errFmtWebauthnRPIDInvalid, config.Webauthn.RPID))
+
+			return
+		}
+	}
+
+	for _, origin := range config.Webauthn.AllowedOrigins {
+		if (origin.Scheme != schemeHTTPS && origin.Scheme != schemeHTTP) || origin.Hostname() == "" ||
+			(origin.Path != "" && origin.Path != "/") || origin.RawQuery != "" || origin.Fragment != "" || origin.User != nil {
+			validator.Push(fmt.Errorf(errFmtWebauthnOriginInvalid, origin.String()))
+
+			continue
+		}
+
+		if config.Webauthn.RPID != "" && !utils.IsHostnameInDomain(origin.Hostname(), config.Webauthn.RPID) {
+			validator.Push(fmt.Errorf(errFmtWebauthnOriginNotInRPID, origin.String(), config.Webauthn.RPID))
+		}
+	}
 }
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"net/url"
 	"testing"
 	"time"
 
@@ -96,3 +97,91 @@ func TestWebauthnShouldRaiseErrorsOnInvalidOptions(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], "webauthn: option 'attestation_conveyance_preference' must be one of 'none', 'indirect', 'direct' but it is configured as 'no'")
 	assert.EqualError(t, validator.Errors()[1], "webauthn: option 'user_verification' must be one of 'discouraged', 'preferred', 'required' but it is configured as 'yes'")
 }
+
+func TestWebauthnShouldNotRaiseErrorsOnValidRelyingParty(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Webauthn: schema.WebauthnConfiguration{
+			RPID: "example.com",
+			AllowedOrigins: []url.URL{
+				{Scheme: "https", Host: "example.com"},
+				{Scheme: "https", Host: "auth.example.com"},
+				{Scheme: "https", Host: "auth.internal.example.com:8443"},
+			},
+		},
+	}
+
+	ValidateWebauthn(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestWebauthnShouldRaiseErrorsOnInvalidRelyingParty(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rpid     string
+		origins  []url.URL
+		expected []string
+	}{
+		{
+			name:     "ShouldRaiseErrorOnRPIDWithScheme",
+			rpid:     "https://example.com",
+			expected: []string{"webauthn: option 'rp_id' with value 'https://example.com' is invalid: it must only be a domain name without a scheme, port, or path"},
+		},
+		{
+			name:     "ShouldRaiseErrorOnRPIDWithPort",
+			rpid:     "example.com:443",
+			expected: []string{"webauthn: option 'rp_id' with value 'example.com:443' is invalid: it must only be a domain name without a scheme, port, or path"},
+		},
+		{
+			name:     "ShouldRaiseErrorOnRPIDWithPath",
+			rpid:     "example.com/path",
+			expected: []string{"webauthn: option 'rp_id' with value 'example.com/path' is invalid: it must only be a domain name without a scheme, port, or path"},
+		},
+		{
+			name: "ShouldRaiseErrorOnInvalidOrigins",
+			origins: []url.URL{
+				{Scheme: "ftp", Host: "example.com"},
+				{Scheme: "https", Host: "example.com", Path: "/path"},
+			},
+			expected: []string{
+				"webauthn: option 'allowed_origins' contains an invalid value 'ftp://example.com': origins must only be a http or https scheme, hostname, and an optional port",
+				"webauthn: option 'allowed_origins' contains an invalid value 'https://example.com/path': origins must only be a http or https scheme, hostname, and an optional port",
+			},
+		},
+		{
+			name: "ShouldRaiseErrorOnOriginNotInRPID",
+			rpid: "auth.example.com",
+			origins: []url.URL{
+				{Scheme: "https", Host: "auth.example.com"},
+				{Scheme: "https", Host: "example.com"},
+				{Scheme: "https", Host: "notexample.com"},
+			},
+			expected: []string{
+				"webauthn: option 'allowed_origins' contains the value 'https://example.com' which is not permitted for the rp_id 'auth.example.com': the rp_id must be the hostname of the origin or a registrable suffix of it",
+				"webauthn: option 'allowed_origins' contains the value 'https://notexample.com' which is not permitted for the rp_id 'auth.example.com': the rp_id must be the hostname of the origin or a registrable suffix of it",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{
+				Webauthn: schema.WebauthnConfiguration{
+					RPID:           tc.rpid,
+					AllowedOrigins: tc.origins,
+				},
+			}
+
+			ValidateWebauthn(config, validator)
+
+			require.Len(t, validator.Errors(), len(tc.expected))
+
+			for i, expected := range tc.expected {
+				assert.EqualError(t, validator.Errors()[i], expected)
+			}
+		})
+	}
+}
@@ -1,5 +1,21 @@
 package duo
 
+import (
+	"errors"
+)
+
+// Duo API endpoints.
+const (
+	// PathPreAuth is the endpoint determining if the user can authenticate and the devices they can use.
+	PathPreAuth = "/auth/v2/preauth"
+
+	// PathAuth is the endpoint performing the authentication, it waits for the user to respond to push notifications.
+	PathAuth = "/auth/v2/auth"
+)
+
+// ErrTimeout is returned when a call to the Duo API did not complete within the configured timeout.
+var ErrTimeout = errors.New("the duo api call timed out")
+
 // Duo Methods.
 const (
 	// Push Method - The device is activated for Duo Push.
@@ -2,27 +2,54 @@ package duo
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
 	"net/url"
+	"time"
 
 	duoapi "github.com/duosecurity/duo_api_golang"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 )
 
-// NewDuoAPI create duo API instance.
-func NewDuoAPI(duoAPI *duoapi.DuoApi) *APIImpl {
-	api := new(APIImpl)
-	api.DuoApi = duoAPI
+// NewDuoAPI create duo API instance. Each endpoint uses its own client so the configured timeouts apply per endpoint.
+func NewDuoAPI(config *schema.DuoAPIConfiguration, insecure bool) *APIImpl {
+	newClient := func(timeout time.Duration) *duoapi.DuoApi {
+		if insecure {
+			return duoapi.NewDuoApi(config.IntegrationKey, config.SecretKey, config.Hostname, "", duoapi.SetTimeout(timeout), duoapi.SetInsecure())
+		}
 
-	return api
+		return duoapi.NewDuoApi(config.IntegrationKey, config.SecretKey, config.Hostname, "", duoapi.SetTimeout(timeout))
+	}
+
+	return &APIImpl{
+		DuoApi:         newClient(config.Timeouts.PreAuth),
+		auth:           newClient(config.Timeouts.Auth),
+		preAuthTimeout: config.Timeouts.PreAuth,
+		authTimeout:    config.Timeouts.Auth,
+	}
 }
 
 // Call call to the DuoAPI.
 func (d *APIImpl) Call(ctx *middlewares.AutheliaCtx, values url.Values, method string, path string) (*Response, error) {
 	var response Response
 
-	_, responseBytes, err := d.DuoApi.SignedCall(method, path, values)
+	client, timeout := d.DuoApi, d.preAuthTimeout
+
+	if path == PathAuth {
+		client, timeout = d.auth, d.authTimeout
+	}
+
+	_, responseBytes, err := client.SignedCall(method, path, values, duoapi.UseTimeout)
 	if err != nil {
+		var netErr net.Error
+
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, fmt.Errorf("%w: %s %s did not respond within %s", ErrTimeout, method, path, timeout)
+		}
+
 		return nil, err
 	}
 
@@ -47,7 +74,7 @@ func (d *APIImpl) Call(ctx *middlewares.AutheliaCtx, values url.Values, method s
 func (d *APIImpl) PreAuthCall(ctx *middlewares.AutheliaCtx, values url.Values) (*PreAuthResponse, error) {
 	var preAuthResponse PreAuthResponse
 
-	response, err := d.Call(ctx, values, "POST", "/auth/v2/preauth")
+	response, err := d.Call(ctx, values, "POST", PathPreAuth)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +91,7 @@ func (d *APIImpl) PreAuthCall(ctx *middlewares.AutheliaCtx, values url.Values) (
 func (d *APIImpl) AuthCall(ctx *middlewares.AutheliaCtx, values url.Values) (*AuthResponse, error) {
 	var authResponse AuthResponse
 
-	response, err := d.Call(ctx, values, "POST", "/auth/v2/auth")
+	response, err := d.Call(ctx, values, "POST", PathAuth)
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,7 @@ package duo
 import (
 	"encoding/json"
 	"net/url"
+	"time"
 
 	duoapi "github.com/duosecurity/duo_api_golang"
 
@@ -19,6 +20,11 @@ type API interface {
 // APIImpl implementation of DuoAPI interface.
 type APIImpl struct {
 	*duoapi.DuoApi
+
+	auth *duoapi.DuoApi
+
+	preAuthTimeout time.Duration
+	authTimeout    time.Duration
 }
 
 // Device holds all necessary info for frontend.
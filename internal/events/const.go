@@ -0,0 +1,32 @@
+package events
+
+const (
+	// TypeAuthenticationAttempt is the type of the events emitted for each first or second factor authentication attempt.
+	TypeAuthenticationAttempt = "authentication.attempt"
+
+	// TypeSessionLogout is the type of the events emitted when a user logs out.
+	TypeSessionLogout = "session.logout"
+
+	// TypeWebauthnEnrollment is the type of the events emitted when an administrator enrolls a Webauthn device on
+	// behalf of a user.
+	TypeWebauthnEnrollment = "webauthn.enrollment"
+
+	// TypeBreakGlassAuthentication is the type of the events emitted for each authentication attempt with the
+	// break-glass account.
+	TypeBreakGlassAuthentication = "authentication.break_glass"
+
+	// TypeEmailDomainRefused is the type of the events emitted when an email isn't sent or a self-registration is
+	// refused because the domain of the email address is not permitted.
+	TypeEmailDomainRefused = "email.domain_refused"
+
+	// TypeAccessControlShadowDeny is the type of the events emitted when a request is authorized but would have been
+	// denied by an access control rule in shadow mode.
+	TypeAccessControlShadowDeny = "access_control.shadow_deny"
+
+	// TypeLifecycleStartup is the type of the event emitted when Authelia has started.
+	TypeLifecycleStartup = "lifecycle.startup"
+)
+
+const (
+	natsProtocolCRLF = "\r\n"
+)
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// Emitter publishes events to a Sink asynchronously. Events are queued in a bounded buffer and dropped when the buffer
+// is full so that emitting an event never blocks the authentication flow.
+type Emitter struct {
+	sink  Sink
+	clock utils.Clock
+	log   *logrus.Logger
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan Event
+	done   chan struct{}
+}
+
+// NewEmitter creates a new Emitter publishing to the provided sink with a buffer of the provided size and starts
+// delivering the queued events in the background.
+func NewEmitter(sink Sink, size int, clock utils.Clock) (emitter *Emitter) {
+	emitter = &Emitter{
+		sink:  sink,
+		clock: clock,
+		log:   logging.Logger(),
+		queue: make(chan Event, size),
+		done:  make(chan struct{}),
+	}
+
+	go emitter.run()
+
+	return emitter
+}
+
+// StartupCheck implements the startup check provider interface.
+func (e *Emitter) StartupCheck() (err error) {
+	return e.sink.StartupCheck()
+}
+
+// Emit queues the event for delivery, setting the ID and time when they're not already set. It's safe to call on a
+// nil Emitter, which is the case when no sink is configured.
+func (e *Emitter) Emit(event Event) {
+	if e == nil {
+		return
+	}
+
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+
+	if event.Time.IsZero() {
+		event.Time = e.clock.Now().UTC()
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.closed {
+		return
+	}
+
+	select {
+	case e.queue <- event:
+	default:
+		e.log.Warnf("Dropped event '%s' of type '%s' as the event buffer is full", event.ID, event.Type)
+	}
+}
+
+// Close stops accepting events, delivers the events remaining in the buffer, and closes the sink.
+func (e *Emitter) Close() (err error) {
+	e.mu.Lock()
+
+	if !e.closed {
+		e.closed = true
+
+		close(e.queue)
+	}
+
+	e.mu.Unlock()
+
+	<-e.done
+
+	return e.sink.Close()
+}
+
+func (e *Emitter) run() {
+	defer close(e.done)
+
+	for event := range e.queue {
+		if err := e.sink.Publish(event); err != nil {
+			e.log.Errorf("Failed to publish event '%s' of type '%s': %+v", event.ID, event.Type, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+type testSink struct {
+	mu      sync.Mutex
+	events  []Event
+	block   chan struct{}
+	err     error
+	closed  bool
+	checked bool
+}
+
+func (s *testSink) StartupCheck() (err error) {
+	s.checked = true
+
+	return s.err
+}
+
+func (s *testSink) Publish(event Event) (err error) {
+	if s.block != nil {
+		<-s.block
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, event)
+
+	return s.err
+}
+
+func (s *testSink) Close() (err error) {
+	s.closed = true
+
+	return nil
+}
+
+func newTestEmitter(sink Sink, size int) (emitter *Emitter, hook *test.Hook) {
+	emitter = NewEmitter(sink, size, utils.RealClock{})
+
+	emitter.log, hook = test.NewNullLogger()
+
+	return emitter, hook
+}
+
+func TestShouldNotPanicWhenEmittingWithoutEmitter(t *testing.T) {
+	var emitter *Emitter
+
+	assert.NotPanics(t, func() {
+		emitter.Emit(Event{Type: TypeSessionLogout})
+	})
+}
+
+func TestShouldDeliverEventsAndCloseSink(t *testing.T) {
+	sink := &testSink{}
+	emitter, _ := newTestEmitter(sink, 10)
+
+	assert.NoError(t, emitter.StartupCheck())
+	assert.True(t, sink.checked)
+
+	emitter.Emit(Event{Type: TypeSessionLogout, Username: "john"})
+	emitter.Emit(Event{ID: "abc", Type: TypeLifecycleStartup, Time: time.Unix(1640000000, 0)})
+
+	require.NoError(t, emitter.Close())
+	assert.True(t, sink.closed)
+
+	require.Len(t, sink.events, 2)
+	assert.Equal(t, TypeSessionLogout, sink.events[0].Type)
+	assert.Equal(t, "john", sink.events[0].Username)
+	assert.Len(t, sink.events[0].ID, 36)
+	assert.False(t, sink.events[0].Time.IsZero())
+	assert.Equal(t, "abc", sink.events[1].ID)
+	assert.Equal(t, time.Unix(1640000000, 0), sink.events[1].Time)
+
+	// Events emitted after the emitter has been closed are discarded.
+	emitter.Emit(Event{Type: TypeSessionLogout})
+	assert.Len(t, sink.events, 2)
+}
+
+func TestShouldDropEventsWhenBufferIsFull(t *testing.T) {
+	sink := &testSink{block: make(chan struct{})}
+	emitter, hook := newTestEmitter(sink, 1)
+
+	// The first event is picked up by the delivery goroutine which blocks, the second fills the buffer.
+	emitter.Emit(Event{ID: "1", Type: TypeSessionLogout})
+
+	require.Eventually(t, func() bool { return len(emitter.queue) == 0 }, time.Second, time.Millisecond)
+
+	emitter.Emit(Event{ID: "2", Type: TypeSessionLogout})
+	emitter.Emit(Event{ID: "3", Type: TypeSessionLogout})
+
+	require.NotNil(t, hook.LastEntry())
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	assert.Equal(t, "Dropped event '3' of type 'session.logout' as the event buffer is full", hook.LastEntry().Message)
+
+	close(sink.block)
+
+	require.NoError(t, emitter.Close())
+
+	require.Len(t, sink.events, 2)
+	assert.Equal(t, "1", sink.events[0].ID)
+	assert.Equal(t, "2", sink.events[1].ID)
+}
+
+func TestShouldLogEventsFailingToPublish(t *testing.T) {
+	sink := &testSink{err: errors.New("bad connection")}
+	emitter, hook := newTestEmitter(sink, 1)
+
+	emitter.Emit(Event{ID: "1", Type: TypeSessionLogout})
+
+	require.NoError(t, emitter.Close())
+
+	require.NotNil(t, hook.LastEntry())
+	assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+	assert.Equal(t, "Failed to publish event '1' of type 'session.logout': bad connection", hook.LastEntry().Message)
+}
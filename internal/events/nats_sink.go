@@ -0,0 +1,342 @@
+package events
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NATSSink a sink publishing events to a NATS server using the NATS client protocol. Events are published to the
+// subject made of the configured prefix and the event type, for example 'authelia.authentication.attempt'.
+type NATSSink struct {
+	configuration *schema.NATSEventsConfiguration
+	tlsConfig     *tls.Config
+	log           *logrus.Logger
+
+	mu         sync.Mutex
+	conn       net.Conn
+	writer     *bufio.Writer
+	maxPayload int
+}
+
+type natsServerInfo struct {
+	TLSRequired bool `json:"tls_required"`
+	MaxPayload  int  `json:"max_payload"`
+}
+
+type natsConnectOptions struct {
+	Verbose     bool   `json:"verbose"`
+	Pedantic    bool   `json:"pedantic"`
+	TLSRequired bool   `json:"tls_required"`
+	Name        string `json:"name"`
+	Lang        string `json:"lang"`
+	Version     string `json:"version"`
+	Protocol    int    `json:"protocol"`
+	User        string `json:"user,omitempty"`
+	Pass        string `json:"pass,omitempty"`
+	AuthToken   string `json:"auth_token,omitempty"`
+}
+
+// NewNATSSink creates a NATSSink using the NATS events configuration.
+func NewNATSSink(configuration *schema.NATSEventsConfiguration, certPool *x509.CertPool) *NATSSink {
+	sink := &NATSSink{
+		configuration: configuration,
+		log:           logging.Logger(),
+	}
+
+	if configuration.TLS != nil {
+		sink.tlsConfig = utils.NewTLSConfig(configuration.TLS, tls.VersionTLS12, certPool)
+	}
+
+	return sink
+}
+
+// StartupCheck implements the startup check provider interface.
+func (s *NATSSink) StartupCheck() (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		return nil
+	}
+
+	return s.connect()
+}
+
+// Publish publishes the event to the subject derived from its type, connecting or reconnecting to the server
+// when necessary.
+func (s *NATSSink) Publish(event Event) (err error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("unable to marshal the event: %w", err)
+	}
+
+	subject := s.configuration.SubjectPrefix + "." + event.Type
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if err = s.connect(); err != nil {
+			return err
+		}
+	}
+
+	if s.maxPayload > 0 && len(payload) > s.maxPayload {
+		return fmt.Errorf("the event size of %d bytes exceeds the maximum payload size of %d bytes of the server", len(payload), s.maxPayload)
+	}
+
+	if err = s.publish(subject, payload); err == nil {
+		return nil
+	}
+
+	// The connection may have been closed by the server since the last event, so reconnect and try once more.
+	s.log.Debugf("Events NATS sink failed to publish, reconnecting: %+v", err)
+
+	s.disconnect()
+
+	if err = s.connect(); err != nil {
+		return err
+	}
+
+	if err = s.publish(subject, payload); err != nil {
+		s.disconnect()
+
+		return err
+	}
+
+	return nil
+}
+
+// Close closes the connection to the server.
+func (s *NATSSink) Close() (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.disconnect()
+
+	return nil
+}
+
+func (s *NATSSink) publish(subject string, payload []byte) (err error) {
+	if err = s.conn.SetWriteDeadline(time.Now().Add(s.configuration.Timeout)); err != nil {
+		return err
+	}
+
+	if _, err = s.writer.WriteString("PUB " + subject + " " + strconv.Itoa(len(payload)) + natsProtocolCRLF); err != nil {
+		return err
+	}
+
+	if _, err = s.writer.Write(payload); err != nil {
+		return err
+	}
+
+	if _, err = s.writer.WriteString(natsProtocolCRLF); err != nil {
+		return err
+	}
+
+	return s.writer.Flush()
+}
+
+// connect dials the server and performs the handshake, the lock must be held by the caller.
+func (s *NATSSink) connect() (err error) {
+	address := net.JoinHostPort(s.configuration.Host, strconv.Itoa(s.configuration.Port))
+
+	conn, err := net.DialTimeout("tcp", address, s.configuration.Timeout)
+	if err != nil {
+		return fmt.Errorf("unable to connect to the NATS server '%s': %w", address, err)
+	}
+
+	if err = conn.SetDeadline(time.Now().Add(s.configuration.Timeout)); err != nil {
+		_ = conn.Close()
+
+		return err
+	}
+
+	reader := bufio.NewReader(conn)
+
+	info, err := readNATSServerInfo(reader)
+	if err != nil {
+		_ = conn.Close()
+
+		return fmt.Errorf("unable to read the NATS server '%s' information: %w", address, err)
+	}
+
+	if info.TLSRequired || s.tlsConfig != nil {
+		if s.tlsConfig == nil {
+			_ = conn.Close()
+
+			return fmt.Errorf("the NATS server '%s' requires TLS but the 'tls' option is not configured", address)
+		}
+
+		tlsConn := tls.Client(conn, s.tlsConfig)
+
+		if err = tlsConn.Handshake(); err != nil {
+			_ = conn.Close()
+
+			return fmt.Errorf("unable to perform the TLS handshake with the NATS server '%s': %w", address, err)
+		}
+
+		conn, reader = tlsConn, bufio.NewReader(tlsConn)
+	}
+
+	writer := bufio.NewWriter(conn)
+
+	if err = s.handshake(reader, writer); err != nil {
+		_ = conn.Close()
+
+		return fmt.Errorf("unable to connect to the NATS server '%s': %w", address, err)
+	}
+
+	if err = conn.SetDeadline(time.Time{}); err != nil {
+		_ = conn.Close()
+
+		return err
+	}
+
+	s.conn, s.writer, s.maxPayload = conn, writer, info.MaxPayload
+
+	go s.read(conn, reader)
+
+	s.log.Debugf("Events NATS sink connected to the server '%s'", address)
+
+	return nil
+}
+
+func (s *NATSSink) handshake(reader *bufio.Reader, writer *bufio.Writer) (err error) {
+	options, err := json.Marshal(natsConnectOptions{
+		TLSRequired: s.tlsConfig != nil,
+		Name:        "authelia",
+		Lang:        "go",
+		Version:     utils.Version(),
+		Protocol:    1,
+		User:        s.configuration.Username,
+		Pass:        s.configuration.Password,
+		AuthToken:   s.configuration.Token,
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err = writer.WriteString("CONNECT " + string(options) + natsProtocolCRLF + "PING" + natsProtocolCRLF); err != nil {
+		return err
+	}
+
+	if err = writer.Flush(); err != nil {
+		return err
+	}
+
+	// The server replies to the PING with a PONG once the CONNECT has been accepted, or with an error otherwise.
+	for {
+		var line string
+
+		if line, err = readNATSLine(reader); err != nil {
+			return err
+		}
+
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err = writer.WriteString("PONG" + natsProtocolCRLF); err != nil {
+				return err
+			}
+
+			if err = writer.Flush(); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("the server returned an error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// read handles the messages sent by the server for the lifetime of the connection, replying to the keep-alive pings.
+func (s *NATSSink) read(conn net.Conn, reader *bufio.Reader) {
+	for {
+		line, err := readNATSLine(reader)
+		if err != nil {
+			s.mu.Lock()
+
+			if s.conn == conn {
+				s.log.Debugf("Events NATS sink connection closed: %+v", err)
+				s.disconnect()
+			}
+
+			s.mu.Unlock()
+
+			return
+		}
+
+		switch {
+		case line == "PING":
+			s.mu.Lock()
+
+			if s.conn == conn {
+				if _, err = s.writer.WriteString("PONG" + natsProtocolCRLF); err == nil {
+					err = s.writer.Flush()
+				}
+
+				if err != nil {
+					s.log.Debugf("Events NATS sink failed to reply to the server ping: %+v", err)
+				}
+			}
+
+			s.mu.Unlock()
+		case strings.HasPrefix(line, "-ERR"):
+			s.log.Errorf("Events NATS server returned an error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// disconnect closes the current connection, the lock must be held by the caller.
+func (s *NATSSink) disconnect() {
+	if s.conn == nil {
+		return
+	}
+
+	_ = s.conn.Close()
+
+	s.conn, s.writer = nil, nil
+}
+
+func readNATSServerInfo(reader *bufio.Reader) (info *natsServerInfo, err error) {
+	line, err := readNATSLine(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(line, "INFO ") {
+		return nil, errors.New("the server did not send the INFO message")
+	}
+
+	info = &natsServerInfo{}
+
+	if err = json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func readNATSLine(reader *bufio.Reader) (line string, err error) {
+	if line, err = reader.ReadString('\n'); err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(line, natsProtocolCRLF), nil
+}
@@ -0,0 +1,157 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+type testNATSMessage struct {
+	subject string
+	payload []byte
+}
+
+// newTestNATSServer starts a minimal NATS server accepting a single connection, it replies to the CONNECT with the
+// provided error when it's not empty and otherwise forwards the published messages to the returned channel.
+func newTestNATSServer(t *testing.T, info, connectErr string) (port int, connects chan string, messages chan testNATSMessage) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	connects, messages = make(chan string, 1), make(chan testNATSMessage, 10)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+
+		_, _ = conn.Write([]byte("INFO " + info + "\r\n"))
+
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			line = strings.TrimRight(line, "\r\n")
+
+			switch {
+			case strings.HasPrefix(line, "CONNECT "):
+				connects <- strings.TrimPrefix(line, "CONNECT ")
+
+				if connectErr != "" {
+					_, _ = conn.Write([]byte("-ERR '" + connectErr + "'\r\n"))
+
+					return
+				}
+			case line == "PING":
+				_, _ = conn.Write([]byte("PONG\r\n"))
+			case strings.HasPrefix(line, "PUB "):
+				fields := strings.Fields(line)
+				size, _ := strconv.Atoi(fields[2])
+				payload := make([]byte, size+2)
+
+				if _, err = io.ReadFull(reader, payload); err != nil {
+					return
+				}
+
+				messages <- testNATSMessage{subject: fields[1], payload: payload[:size]}
+			}
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, connects, messages
+}
+
+func TestShouldPublishEventsToNATS(t *testing.T) {
+	port, connects, messages := newTestNATSServer(t, `{"server_id":"test","max_payload":1048576}`, "")
+
+	sink := NewNATSSink(&schema.NATSEventsConfiguration{
+		Host:          "127.0.0.1",
+		Port:          port,
+		Timeout:       time.Second,
+		Username:      "authelia",
+		Password:      "secret",
+		SubjectPrefix: "auth.events",
+	}, nil)
+
+	defer sink.Close()
+
+	require.NoError(t, sink.StartupCheck())
+
+	connect := map[string]interface{}{}
+
+	require.NoError(t, json.Unmarshal([]byte(<-connects), &connect))
+	assert.Equal(t, "authelia", connect["name"])
+	assert.Equal(t, "authelia", connect["user"])
+	assert.Equal(t, "secret", connect["pass"])
+	assert.Equal(t, false, connect["verbose"])
+
+	successful := true
+
+	require.NoError(t, sink.Publish(Event{
+		ID:         "8f1d6b3a-8c2e-4a38-9b7c-2f1a7e8f1f3d",
+		Type:       TypeAuthenticationAttempt,
+		Time:       time.Unix(1640000000, 0).UTC(),
+		Username:   "john",
+		RemoteIP:   "192.168.0.1",
+		Method:     "1FA",
+		Successful: &successful,
+	}))
+
+	select {
+	case message := <-messages:
+		assert.Equal(t, "auth.events.authentication.attempt", message.subject)
+		assert.JSONEq(t, `{"id":"8f1d6b3a-8c2e-4a38-9b7c-2f1a7e8f1f3d","type":"authentication.attempt","time":"2021-12-20T11:33:20Z","username":"john","remote_ip":"192.168.0.1","method":"1FA","successful":true}`, string(message.payload))
+	case <-time.After(time.Second):
+		t.Fatal("the event was not published")
+	}
+}
+
+func TestShouldNotPublishEventsExceedingTheMaximumPayload(t *testing.T) {
+	port, _, _ := newTestNATSServer(t, `{"server_id":"test","max_payload":16}`, "")
+
+	sink := NewNATSSink(&schema.NATSEventsConfiguration{Host: "127.0.0.1", Port: port, Timeout: time.Second, SubjectPrefix: "authelia"}, nil)
+
+	defer sink.Close()
+
+	err := sink.Publish(Event{ID: "1", Type: TypeSessionLogout, Time: time.Unix(1640000000, 0).UTC()})
+
+	assert.EqualError(t, err, "the event size of 64 bytes exceeds the maximum payload size of 16 bytes of the server")
+}
+
+func TestShouldFailNATSStartupCheckOnAuthorizationError(t *testing.T) {
+	port, _, _ := newTestNATSServer(t, `{"server_id":"test","auth_required":true}`, "Authorization Violation")
+
+	sink := NewNATSSink(&schema.NATSEventsConfiguration{Host: "127.0.0.1", Port: port, Timeout: time.Second, Token: "bad"}, nil)
+
+	err := sink.StartupCheck()
+
+	assert.EqualError(t, err, "unable to connect to the NATS server '127.0.0.1:"+strconv.Itoa(port)+"': the server returned an error: 'Authorization Violation'")
+}
+
+func TestShouldFailNATSStartupCheckWhenServerRequiresTLS(t *testing.T) {
+	port, _, _ := newTestNATSServer(t, `{"server_id":"test","tls_required":true}`, "")
+
+	sink := NewNATSSink(&schema.NATSEventsConfiguration{Host: "127.0.0.1", Port: port, Timeout: time.Second}, nil)
+
+	err := sink.StartupCheck()
+
+	assert.EqualError(t, err, "the NATS server '127.0.0.1:"+strconv.Itoa(port)+"' requires TLS but the 'tls' option is not configured")
+}
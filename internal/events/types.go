@@ -0,0 +1,28 @@
+package events
+
+import (
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// Sink is implemented by the providers which deliver events to an external system such as a message bus.
+type Sink interface {
+	model.StartupCheck
+
+	Publish(event Event) (err error)
+	Close() (err error)
+}
+
+// Event is the JSON schema of an authentication or lifecycle event published to the sinks.
+type Event struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	Time       time.Time         `json:"time"`
+	Username   string            `json:"username,omitempty"`
+	RemoteIP   string            `json:"remote_ip,omitempty"`
+	Method     string            `json:"method,omitempty"`
+	Successful *bool             `json:"successful,omitempty"`
+	Banned     bool              `json:"banned,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+)
+
+// NewCapabilities returns the capabilities of the portal enabled by the provided configuration.
+func NewCapabilities(config *schema.Configuration) (capabilities Capabilities) {
+	capabilities = Capabilities{
+		ResetPassword:       !config.AuthenticationBackend.DisableResetPassword,
+		RememberMe:          config.Session.RememberMeDuration != schema.RememberMeDisabled,
+		SelfRegistration:    config.SelfRegistration.Enabled,
+		DuoSelfEnrollment:   config.DuoAPI != nil && config.DuoAPI.EnableSelfEnrollment,
+		SecondFactorMethods: middlewares.AvailableSecondFactorMethods(config),
+		IdentityProviders:   []string{},
+	}
+
+	if capabilities.ResetPassword {
+		capabilities.ResetPasswordMethod = config.AuthenticationBackend.PasswordReset.Method
+		capabilities.ResetPasswordCustomURL = config.AuthenticationBackend.PasswordReset.CustomURL.String()
+	}
+
+	if config.AuthenticationBackend.TrustedJWT.Enabled {
+		capabilities.IdentityProviders = append(capabilities.IdentityProviders, identityProviderTrustedJWT)
+	}
+
+	return capabilities
+}
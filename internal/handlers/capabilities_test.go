@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestShouldReturnCapabilitiesFromConfiguration(t *testing.T) {
+	config := &schema.Configuration{
+		AuthenticationBackend: schema.AuthenticationBackendConfiguration{
+			PasswordReset: schema.PasswordResetAuthenticationBackendConfiguration{
+				Method: "email",
+			},
+			TrustedJWT: schema.TrustedJWTAuthenticationBackendConfiguration{
+				Enabled: true,
+			},
+		},
+		Session: schema.SessionConfiguration{
+			RememberMeDuration: schema.RememberMeDisabled,
+		},
+		TOTP: schema.TOTPConfiguration{
+			Disable: true,
+		},
+		DuoAPI: &schema.DuoAPIConfiguration{
+			EnableSelfEnrollment: true,
+		},
+	}
+
+	capabilities := NewCapabilities(config)
+
+	assert.True(t, capabilities.ResetPassword)
+	assert.Equal(t, "email", capabilities.ResetPasswordMethod)
+	assert.False(t, capabilities.RememberMe)
+	assert.True(t, capabilities.DuoSelfEnrollment)
+	assert.Equal(t, []string{model.SecondFactorMethodWebauthn, model.SecondFactorMethodDuo}, capabilities.SecondFactorMethods)
+	assert.Equal(t, []string{identityProviderTrustedJWT}, capabilities.IdentityProviders)
+
+	config.AuthenticationBackend.DisableResetPassword = true
+
+	capabilities = NewCapabilities(config)
+
+	assert.False(t, capabilities.ResetPassword)
+	assert.Equal(t, "", capabilities.ResetPasswordMethod)
+	assert.Equal(t, []string{identityProviderTrustedJWT}, capabilities.IdentityProviders)
+}
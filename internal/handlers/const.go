@@ -21,11 +21,15 @@ var (
 	headerAuthorization      = []byte(fasthttp.HeaderAuthorization)
 	headerProxyAuthorization = []byte(fasthttp.HeaderProxyAuthorization)
 
+	headerPrefixBearer = []byte("Bearer ")
+
 	headerSessionUsername = []byte("Session-Username")
 	headerRemoteUser      = []byte("Remote-User")
 	headerRemoteGroups    = []byte("Remote-Groups")
 	headerRemoteName      = []byte("Remote-Name")
 	headerRemoteEmail     = []byte("Remote-Email")
+
+	headerAutheliaRule = []byte("X-Authelia-Rule")
 )
 
 const (
@@ -38,13 +42,28 @@ const (
 )
 
 const (
-	messageOperationFailed                 = "Operation failed."
-	messageAuthenticationFailed            = "Authentication failed. Check your credentials."
-	messageUnableToRegisterOneTimePassword = "Unable to set up one-time passwords." //nolint:gosec
-	messageUnableToRegisterSecurityKey     = "Unable to register your security key."
-	messageUnableToResetPassword           = "Unable to reset your password."
-	messageMFAValidationFailed             = "Authentication failed, please retry later."
-	messagePasswordWeak                    = "Your supplied password does not meet the password policy requirements"
+	messageOperationFailed                    = "Operation failed."
+	messageAuthenticationFailed               = "Authentication failed. Check your credentials."
+	messageAuthenticationBackendBusy          = "The authentication service is busy, please try again later."
+	messageUnableToRegisterOneTimePassword    = "Unable to set up one-time passwords." //nolint:gosec
+	messageUnableToRegisterSecurityKey        = "Unable to register your security key."
+	messageUnableToResetPassword              = "Unable to reset your password."
+	messageMFAValidationFailed                = "Authentication failed, please retry later."
+	messageDuoTimeout                         = "Duo did not respond in time, please retry later."
+	messagePasswordWeak                       = "Your supplied password does not meet the password policy requirements"
+	messagePasswordTooRecent                  = "Your password was changed too recently and can't be changed again yet."
+	messageUnableToRegisterAccount            = "Unable to register your account."
+	messageUnableToVerifyEmail                = "Unable to verify your email address."
+	messageEmailDomainNotPermitted            = "Your email address domain is not permitted."
+	messageUnableToUnlockAccount              = "Unable to unlock your account."
+	messageUnableToSuppressLoginNotifications = "Unable to suppress login notifications."
+	messageConcurrentSessionLimitReached      = "You have reached the maximum number of active sessions."
+	messageCAPTCHARequired                    = "Please complete the CAPTCHA challenge."
+	messageOutsideTimeWindow                  = "Access is not permitted at this time."
+	messageAccountExpired                     = "Your account has expired."
+	messagePasswordExpired                    = "Your password has expired."
+	messagePasswordMustChange                 = "Your password must be changed."
+	messagePasswordResetRequired              = "Your password must be reset."
 )
 
 const (
@@ -55,9 +74,46 @@ const (
 	logFmtErrSessionReset         = "Could not reset session during %s authentication for user '%s': %+v"
 	logFmtErrSessionSave          = "Could not save session with the %s during %s authentication for user '%s': %+v"
 	logFmtErrObtainProfileDetails = "Could not obtain profile details during %s authentication for user '%s': %+v"
+	logFmtErrSessionRegister      = "Could not register session during %s authentication for user '%s': %+v"
 	logFmtTraceProfileDetails     = "Profile details for user '%s' => groups: %s, emails %s"
 )
 
+const (
+	// webauthnDefaultDescription is the description of Webauthn devices which are registered without one.
+	webauthnDefaultDescription = "Primary"
+
+	// webauthnMaxDescriptionLength is the maximum length of the description of a Webauthn device in the storage.
+	webauthnMaxDescriptionLength = 30
+)
+
+const (
+	userActivityLimitDefault = 20
+	userActivityLimitMaximum = 100
+
+	// userActivityPageMaximum bounds the page so the offset computed by the storage from the page and the limit can't
+	// overflow.
+	userActivityPageMaximum = 10000
+)
+
+const (
+	totpDescriptionDefault   = "Primary"
+	totpDescriptionMaxLength = 30
+
+	// totpRotationLifespan is how long the user has to confirm the new secret of a TOTP registration being rotated.
+	totpRotationLifespan = time.Minute * 5
+)
+
+// emailVerificationTokenLength is the length of the token sent to verify the email address of a self-registration.
+const (
+	logoutScopeCurrent = "current"
+	logoutScopeAll     = "all"
+)
+
+const emailVerificationTokenLength = 32
+
+// loginNotificationSuppressTokenLength is the length of the token sent to suppress future login notifications.
+const loginNotificationSuppressTokenLength = 32
+
 const (
 	testInactivity     = time.Second * 10
 	testRedirectionURL = "http://redirection.local"
@@ -79,6 +135,11 @@ const (
 
 const authPrefix = "Basic "
 
+// Identity provider constants.
+const (
+	identityProviderTrustedJWT = "trusted_jwt"
+)
+
 const ldapPasswordComplexityCode = "0000052D."
 
 var ldapPasswordComplexityCodes = []string{
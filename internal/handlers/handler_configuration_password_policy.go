@@ -1,30 +1,73 @@
 package handlers
 
 import (
+	"fmt"
+
 	"github.com/authelia/authelia/v4/internal/middlewares"
 )
 
-// PasswordPolicyConfigurationGet get the password policy configuration.
+// PasswordPolicyConfigurationGet get the password policy configuration which applies to the current user.
 func PasswordPolicyConfigurationGet(ctx *middlewares.AutheliaCtx) {
 	policyResponse := PassworPolicyBody{
 		Mode: "disabled",
 	}
 
-	if ctx.Configuration.PasswordPolicy.Standard.Enabled {
+	var (
+		name string
+		err  error
+	)
+
+	userSession := ctx.GetSession()
+
+	switch {
+	case userSession.PasswordResetUsername != nil:
+		if name, _, err = getPasswordPolicy(ctx, *userSession.PasswordResetUsername); err != nil {
+			ctx.Error(fmt.Errorf("unable to determine the password policy of user '%s': %w", *userSession.PasswordResetUsername, err), messageOperationFailed)
+			return
+		}
+	case userSession.Username != "":
+		name, _ = ctx.Providers.PasswordPolicy.ForGroups(userSession.Groups)
+	}
+
+	standard, zxcvbn := ctx.Configuration.PasswordPolicy.Standard, ctx.Configuration.PasswordPolicy.ZXCVBN
+
+	for _, policy := range ctx.Configuration.PasswordPolicy.Policies {
+		if name != "" && policy.Name == name {
+			standard, zxcvbn = policy.Standard, policy.ZXCVBN
+			break
+		}
+	}
+
+	if standard.Enabled {
 		policyResponse.Mode = "standard"
-		policyResponse.MinLength = ctx.Configuration.PasswordPolicy.Standard.MinLength
-		policyResponse.MaxLength = ctx.Configuration.PasswordPolicy.Standard.MaxLength
-		policyResponse.RequireLowercase = ctx.Configuration.PasswordPolicy.Standard.RequireLowercase
-		policyResponse.RequireUppercase = ctx.Configuration.PasswordPolicy.Standard.RequireUppercase
-		policyResponse.RequireNumber = ctx.Configuration.PasswordPolicy.Standard.RequireNumber
-		policyResponse.RequireSpecial = ctx.Configuration.PasswordPolicy.Standard.RequireSpecial
-	} else if ctx.Configuration.PasswordPolicy.ZXCVBN.Enabled {
+		policyResponse.MinLength = standard.MinLength
+		policyResponse.MaxLength = standard.MaxLength
+		policyResponse.RequireLowercase = standard.RequireLowercase
+		policyResponse.RequireUppercase = standard.RequireUppercase
+		policyResponse.RequireNumber = standard.RequireNumber
+		policyResponse.RequireSpecial = standard.RequireSpecial
+	} else if zxcvbn.Enabled {
 		policyResponse.Mode = "zxcvbn"
 	}
 
-	var err error
-
 	if err = ctx.SetJSONBody(policyResponse); err != nil {
 		ctx.Logger.Errorf("Unable to send password Policy: %s", err)
 	}
 }
+
+// getPasswordPolicy returns the name of the password policy and the password policy which applies to a user. The groups
+// of the user are only retrieved from the user provider when group password policies are configured.
+func getPasswordPolicy(ctx *middlewares.AutheliaCtx, username string) (name string, policy middlewares.PasswordPolicyProvider, err error) {
+	if !ctx.Providers.PasswordPolicy.HasGroupPolicies() {
+		return "", ctx.Providers.PasswordPolicy, nil
+	}
+
+	details, err := ctx.Providers.UserProvider.GetDetails(username)
+	if err != nil {
+		return "", policy, err
+	}
+
+	name, policy = ctx.Providers.PasswordPolicy.ForGroups(details.Groups)
+
+	return name, policy, nil
+}
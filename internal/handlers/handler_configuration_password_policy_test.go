@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func newTestGroupPasswordPolicy(mock *mocks.MockAutheliaCtx) {
+	mock.Ctx.Configuration.PasswordPolicy = schema.PasswordPolicyConfiguration{
+		Standard: schema.PasswordPolicyStandardParams{Enabled: true, MinLength: 8},
+		Policies: []schema.PasswordPolicyGroupConfiguration{
+			{
+				Name:     "admins",
+				Groups:   []string{"admins"},
+				Standard: schema.PasswordPolicyStandardParams{Enabled: true, MinLength: 16, RequireSpecial: true},
+			},
+		},
+	}
+
+	mock.Ctx.Providers.PasswordPolicy = middlewares.NewPasswordPolicyProvider(mock.Ctx.Configuration.PasswordPolicy)
+}
+
+func TestPasswordPolicyConfigurationGetShouldReturnGlobalPolicy(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	newTestGroupPasswordPolicy(mock)
+
+	PasswordPolicyConfigurationGet(mock.Ctx)
+
+	mock.Assert200OK(t, PassworPolicyBody{Mode: "standard", MinLength: 8})
+}
+
+func TestPasswordPolicyConfigurationGetShouldReturnGroupPolicyOfUser(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	newTestGroupPasswordPolicy(mock)
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.Groups = []string{"users", "admins"}
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	PasswordPolicyConfigurationGet(mock.Ctx)
+
+	mock.Assert200OK(t, PassworPolicyBody{Mode: "standard", MinLength: 16, RequireSpecial: true})
+}
+
+func TestPasswordPolicyConfigurationGetShouldReturnGroupPolicyOfPasswordReset(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	newTestGroupPasswordPolicy(mock)
+
+	username := testUsername
+	userSession := mock.Ctx.GetSession()
+	userSession.PasswordResetUsername = &username
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	mock.UserProviderMock.EXPECT().
+		GetDetails(gomock.Eq(testUsername)).
+		Return(&authentication.UserDetails{Username: testUsername, Groups: []string{"admins"}}, nil)
+
+	PasswordPolicyConfigurationGet(mock.Ctx)
+
+	mock.Assert200OK(t, PassworPolicyBody{Mode: "standard", MinLength: 16, RequireSpecial: true})
+}
+
+func TestResetPasswordPOSTShouldEnforceGroupPolicy(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	newTestGroupPasswordPolicy(mock)
+
+	username := testUsername
+	userSession := mock.Ctx.GetSession()
+	userSession.PasswordResetUsername = &username
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	mock.UserProviderMock.EXPECT().
+		GetDetails(gomock.Eq(testUsername)).
+		Return(&authentication.UserDetails{Username: testUsername, Groups: []string{"admins"}}, nil)
+
+	mock.Ctx.Request.SetBodyString(`{"password":"password1234"}`)
+
+	ResetPasswordPOST(mock.Ctx)
+
+	mock.Assert200KO(t, messagePasswordWeak)
+	assert.Equal(t, "the password of user 'john' does not meet the password policy 'admins': the supplied password does not met the security policy", mock.Hook.LastEntry().Message)
+}
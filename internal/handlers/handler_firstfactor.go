@@ -1,13 +1,23 @@
 package handlers
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/events"
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/notification"
 	"github.com/authelia/authelia/v4/internal/regulation"
 	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/templates"
 )
 
 // FirstFactorPOST is the handler performing the first factory.
@@ -32,6 +42,17 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 			return
 		}
 
+		// The username is canonicalized before it's used so the same user is always regulated, looked up, and stored
+		// with the same username regardless of how it was entered.
+		bodyJSON.Username = authentication.CanonicalizeUsername(ctx.Configuration.AuthenticationBackend.UsernameCanonicalization, bodyJSON.Username)
+
+		breakGlass := isBreakGlassAccount(ctx, bodyJSON.Username)
+
+		if breakGlass {
+			// The configured username is used so the attempts are regulated regardless of the case of the username.
+			bodyJSON.Username = ctx.Providers.BreakGlass.Username()
+		}
+
 		if bannedUntil, err := ctx.Providers.Regulator.Regulate(ctx, bodyJSON.Username); err != nil {
 			if errors.Is(err, regulation.ErrUserIsBanned) {
 				_ = markAuthenticationAttempt(ctx, false, &bannedUntil, bodyJSON.Username, regulation.AuthType1FA, nil)
@@ -48,10 +69,33 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 			return
 		}
 
-		userPasswordOk, err := ctx.Providers.UserProvider.CheckUserPassword(bodyJSON.Username, bodyJSON.Password)
+		if !verifyFirstFactorCAPTCHA(ctx, bodyJSON.Username, bodyJSON.CAPTCHAToken) {
+			respondUnauthorized(ctx, messageCAPTCHARequired)
+
+			return
+		}
+
+		ctx.SetBackend(getAuthenticationBackendName(ctx.Configuration.AuthenticationBackend))
+
+		userPasswordOk, err := checkFirstFactorPassword(ctx, bodyJSON.Username, bodyJSON.Password, breakGlass)
+		if errors.Is(err, authentication.ErrBackendBusy) {
+			// The credentials were not checked so this is not recorded as a failed authentication attempt.
+			ctx.Logger.Errorf("Unable to check the credentials of user '%s': %v", bodyJSON.Username, err)
+
+			respondServiceUnavailable(ctx, messageAuthenticationBackendBusy)
+
+			return
+		}
+
+		if respondFirstFactorExpired(ctx, bodyJSON.Username, err) {
+			return
+		}
+
 		if err != nil {
 			_ = markAuthenticationAttempt(ctx, false, nil, bodyJSON.Username, regulation.AuthType1FA, err)
 
+			isFirstFactorCAPTCHARequired(ctx, bodyJSON.Username)
+
 			respondUnauthorized(ctx, messageAuthenticationFailed)
 
 			return
@@ -60,6 +104,19 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 		if !userPasswordOk {
 			_ = markAuthenticationAttempt(ctx, false, nil, bodyJSON.Username, regulation.AuthType1FA, nil)
 
+			isFirstFactorCAPTCHARequired(ctx, bodyJSON.Username)
+
+			sendRegulationUnlock(ctx, bodyJSON.Username)
+
+			respondUnauthorized(ctx, messageAuthenticationFailed)
+
+			return
+		}
+
+		// Disabled users receive the same response as invalid credentials so the state of the account isn't revealed.
+		if isUserDisabled(ctx, bodyJSON.Username) {
+			_ = markAuthenticationAttempt(ctx, false, nil, bodyJSON.Username, regulation.AuthType1FA, authentication.ErrUserDisabled)
+
 			respondUnauthorized(ctx, messageAuthenticationFailed)
 
 			return
@@ -108,7 +165,7 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 		}
 
 		// Get the details of the given user from the user provider.
-		userDetails, err := ctx.Providers.UserProvider.GetDetails(bodyJSON.Username)
+		userDetails, err := getFirstFactorUserDetails(ctx, bodyJSON.Username, breakGlass)
 		if err != nil {
 			ctx.Logger.Errorf(logFmtErrObtainProfileDetails, regulation.AuthType1FA, bodyJSON.Username, err)
 
@@ -119,8 +176,34 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 
 		ctx.Logger.Tracef(logFmtTraceProfileDetails, bodyJSON.Username, userDetails.Groups, userDetails.Emails)
 
+		if isTargetURLOutsideTimeWindow(ctx, bodyJSON.TargetURL, bodyJSON.RequestMethod, userDetails) {
+			respondUnauthorized(ctx, messageOutsideTimeWindow)
+
+			return
+		}
+
+		if err = ctx.Providers.SessionProvider.RegisterUserSession(ctx.RequestCtx, userDetails.Username, keepMeLoggedIn); err != nil {
+			ctx.Logger.Errorf(logFmtErrSessionRegister, regulation.AuthType1FA, bodyJSON.Username, err)
+
+			if errors.Is(err, session.ErrConcurrentSessionLimitReached) {
+				respondUnauthorized(ctx, messageConcurrentSessionLimitReached)
+			} else {
+				respondUnauthorized(ctx, messageAuthenticationFailed)
+			}
+
+			return
+		}
+
 		userSession.SetOneFactor(ctx.Clock.Now(), userDetails, keepMeLoggedIn)
 
+		userSession.BreakGlass = breakGlass
+
+		bindSession(ctx, &userSession)
+
+		userSession.ImpossibleTravel = isImpossibleTravel(ctx, userDetails)
+
+		userSession.SecondFactorEnrollmentRequired = isSecondFactorEnrollmentRequired(ctx, userDetails.Username)
+
 		if refresh, refreshInterval := getProfileRefreshSettings(ctx.Configuration.AuthenticationBackend); refresh {
 			userSession.RefreshTTL = ctx.Clock.Now().Add(refreshInterval)
 		}
@@ -135,6 +218,8 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 
 		successful = true
 
+		sendLoginNotification(ctx, userDetails)
+
 		if userSession.ConsentChallengeID != nil {
 			handleOIDCWorkflowResponse(ctx)
 		} else {
@@ -142,3 +227,301 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 		}
 	}
 }
+
+// isBreakGlassAccount returns true if the break-glass account is enabled and the username refers to it.
+func isBreakGlassAccount(ctx *middlewares.AutheliaCtx, username string) bool {
+	return ctx.Providers.BreakGlass != nil && ctx.Providers.BreakGlass.IsAccount(username)
+}
+
+// checkFirstFactorPassword checks the password of the user with the break-glass account or the user provider. Every
+// use of the break-glass account is logged and emitted as an event regardless of the outcome.
+func checkFirstFactorPassword(ctx *middlewares.AutheliaCtx, username, password string, breakGlass bool) (valid bool, err error) {
+	if !breakGlass {
+		return ctx.Providers.UserProvider.CheckUserPassword(username, password)
+	}
+
+	valid, err = ctx.Providers.BreakGlass.CheckPassword(password)
+
+	successful := valid && err == nil
+
+	if successful {
+		ctx.Logger.Warnf("The break-glass account '%s' was used to log in from %s, the second factor is required", username, ctx.RemoteIP())
+	} else {
+		ctx.Logger.Errorf("Unsuccessful attempt to log in with the break-glass account '%s' from %s", username, ctx.RemoteIP())
+	}
+
+	ctx.Providers.Events.Emit(events.Event{
+		Type:       events.TypeBreakGlassAuthentication,
+		Username:   username,
+		RemoteIP:   ctx.RemoteIP().String(),
+		Method:     regulation.AuthType1FA,
+		Successful: &successful,
+	})
+
+	return valid, err
+}
+
+// getFirstFactorUserDetails returns the details of the user from the break-glass account or the user provider.
+func getFirstFactorUserDetails(ctx *middlewares.AutheliaCtx, username string, breakGlass bool) (details *authentication.UserDetails, err error) {
+	if breakGlass {
+		return ctx.Providers.BreakGlass.GetDetails(), nil
+	}
+
+	return ctx.Providers.UserProvider.GetDetails(username)
+}
+
+// respondFirstFactorExpired responds and returns true if the authentication backend reported the account or the
+// password of the user has expired. The backend only reports this once the credentials were verified so this is not
+// recorded as a failed authentication attempt, and a KO status is used instead of an unauthorized status so the portal
+// can inform the user or redirect them to the password reset.
+func respondFirstFactorExpired(ctx *middlewares.AutheliaCtx, username string, err error) bool {
+	reset := ctx.Configuration.AuthenticationBackend.LDAP != nil &&
+		ctx.Configuration.AuthenticationBackend.LDAP.PasswordExpiration.Action == schema.LDAPPasswordExpirationActionReset
+
+	switch {
+	case errors.Is(err, authentication.ErrAccountExpired):
+		ctx.SetJSONError(messageAccountExpired)
+	case errors.Is(err, authentication.ErrPasswordExpired) && reset, errors.Is(err, authentication.ErrPasswordMustChange) && reset:
+		ctx.SetJSONError(messagePasswordResetRequired)
+	case errors.Is(err, authentication.ErrPasswordExpired):
+		ctx.SetJSONError(messagePasswordExpired)
+	case errors.Is(err, authentication.ErrPasswordMustChange):
+		ctx.SetJSONError(messagePasswordMustChange)
+	default:
+		return false
+	}
+
+	ctx.Logger.Infof("User '%s' can't log in: %v", username, err)
+
+	return true
+}
+
+// verifyFirstFactorCAPTCHA returns true if the user doesn't need to complete a CAPTCHA challenge or if the provider
+// verified the submitted token. When the provider can't verify the token the configured failure mode applies.
+func verifyFirstFactorCAPTCHA(ctx *middlewares.AutheliaCtx, username, token string) bool {
+	if !isFirstFactorCAPTCHARequired(ctx, username) {
+		return true
+	}
+
+	if token == "" {
+		ctx.Logger.Debugf("User '%s' must complete a CAPTCHA challenge but no token was provided", username)
+
+		return false
+	}
+
+	valid, err := ctx.Providers.CAPTCHA.Verify(ctx, token, ctx.RemoteIP())
+
+	switch {
+	case err != nil && ctx.Providers.CAPTCHA.FailOpen():
+		ctx.Logger.Warnf("Skipping the CAPTCHA challenge for user '%s' as the token could not be verified: %v", username, err)
+
+		return true
+	case err != nil:
+		ctx.Logger.Errorf("Unable to verify the CAPTCHA token of user '%s': %v", username, err)
+
+		return false
+	case !valid:
+		ctx.Logger.Debugf("User '%s' provided an invalid CAPTCHA token", username)
+
+		return false
+	}
+
+	return true
+}
+
+// isFirstFactorCAPTCHARequired returns true if a CAPTCHA provider is configured and the user has reached the configured
+// number of failed attempts. The session is flagged so the state endpoint informs the portal of the challenge.
+func isFirstFactorCAPTCHARequired(ctx *middlewares.AutheliaCtx, username string) bool {
+	if ctx.Providers.CAPTCHA == nil {
+		return false
+	}
+
+	threshold := ctx.Providers.CAPTCHA.Threshold()
+
+	failed, err := ctx.Providers.Regulator.FailedAttempts(ctx, username, threshold)
+	if err != nil {
+		// The challenge is required when the failed attempts can't be determined to prevent bypassing it.
+		ctx.Logger.Errorf("Unable to load the failed authentication attempts of user '%s': %v", username, err)
+	} else if failed < threshold {
+		return false
+	}
+
+	if userSession := ctx.GetSession(); !userSession.CAPTCHARequired {
+		userSession.CAPTCHARequired = true
+
+		if err = ctx.SaveSession(userSession); err != nil {
+			ctx.Logger.Errorf("Unable to save the CAPTCHA requirement in the session of user '%s': %v", username, err)
+		}
+	}
+
+	return true
+}
+
+// isImpossibleTravel records the login location of the user provided by the configured headers and returns true if
+// the regulator detected impossible travel, notifying the user when configured. Any failure to determine the location
+// or perform the detection is logged and ignored so users are never locked out when the detection itself fails.
+func isImpossibleTravel(ctx *middlewares.AutheliaCtx, details *authentication.UserDetails) bool {
+	config := ctx.Configuration.Regulation.ImpossibleTravel
+
+	username := details.Username
+
+	if !config.Enabled {
+		return false
+	}
+
+	if remoteIP := ctx.RequestCtx.RemoteIP(); !ctx.Providers.Regulator.IsLocationTrusted(remoteIP) {
+		ctx.Logger.Debugf("Skipping impossible travel detection for user '%s': the request was received from %s which is not a trusted network", username, remoteIP)
+
+		return false
+	}
+
+	latitude, err := strconv.ParseFloat(string(ctx.Request.Header.Peek(config.HeaderLatitude)), 64)
+	if err != nil {
+		ctx.Logger.Debugf("Skipping impossible travel detection for user '%s': could not parse the %s header: %v", username, config.HeaderLatitude, err)
+
+		return false
+	}
+
+	longitude, err := strconv.ParseFloat(string(ctx.Request.Header.Peek(config.HeaderLongitude)), 64)
+	if err != nil {
+		ctx.Logger.Debugf("Skipping impossible travel detection for user '%s': could not parse the %s header: %v", username, config.HeaderLongitude, err)
+
+		return false
+	}
+
+	location := model.UserLoginLocation{
+		Time:      ctx.Clock.Now(),
+		Username:  username,
+		RemoteIP:  model.NewNullIP(ctx.RemoteIP()),
+		Latitude:  latitude,
+		Longitude: longitude,
+	}
+
+	previous, detected, err := ctx.Providers.Regulator.ImpossibleTravel(ctx, location)
+	if err != nil {
+		ctx.Logger.Errorf("Unable to perform impossible travel detection for user '%s': %v", username, err)
+
+		return false
+	}
+
+	if detected {
+		ctx.Logger.Warnf("Impossible travel detected for user '%s': previous login from %s (%f, %f) at %s and current login from %s (%f, %f) at %s, the second factor is required",
+			username, previous.RemoteIP.IP, previous.Latitude, previous.Longitude, previous.Time,
+			location.RemoteIP.IP, location.Latitude, location.Longitude, location.Time)
+
+		if config.Notify {
+			if err = sendImpossibleTravelEmail(ctx, details, previous, location); err != nil {
+				ctx.Logger.Errorf("Unable to send the impossible travel notification to user '%s': %+v", username, err)
+			}
+		}
+	}
+
+	return detected
+}
+
+func sendImpossibleTravelEmail(ctx *middlewares.AutheliaCtx, details *authentication.UserDetails, previous *model.UserLoginLocation, current model.UserLoginLocation) (err error) {
+	if len(details.Emails) == 0 {
+		return fmt.Errorf("the user has no email address")
+	}
+
+	bufText := new(bytes.Buffer)
+
+	if err = templates.EmailImpossibleTravelPlainText.Execute(bufText, map[string]interface{}{
+		"Username":          details.Username,
+		"DisplayName":       details.DisplayName,
+		"PreviousTime":      previous.Time.Format(time.RFC1123),
+		"PreviousRemoteIP":  previous.RemoteIP.IP,
+		"PreviousLatitude":  previous.Latitude,
+		"PreviousLongitude": previous.Longitude,
+		"Time":              current.Time.Format(time.RFC1123),
+		"RemoteIP":          current.RemoteIP.IP,
+		"Latitude":          current.Latitude,
+		"Longitude":         current.Longitude,
+	}); err != nil {
+		return err
+	}
+
+	ctx.Logger.Debugf("Sending an impossible travel notification to user '%s' (%s)", details.Username, details.Emails[0])
+
+	return notification.SendEvent(ctx.Providers.Notifier, notification.EventLogin, details.Emails[0], "Unusual sign-in to your account", bufText.String(), "")
+}
+
+// isSecondFactorEnrollmentRequired records the first login of the user when the enforcement of second factor
+// enrollment is enabled and returns true if the grace period since the first login has elapsed and the user hasn't
+// enrolled a second factor method. Any failure to determine this is logged and ignored so users are never locked out by
+// this check.
+func isSecondFactorEnrollmentRequired(ctx *middlewares.AutheliaCtx, username string) bool {
+	config := ctx.Configuration.SecondFactorEnrollment
+
+	if !config.Enforce {
+		return false
+	}
+
+	now := ctx.Clock.Now()
+
+	firstLogin, err := ctx.Providers.StorageProvider.LoadUserFirstLogin(ctx, username)
+	if err != nil {
+		ctx.Logger.Errorf("Unable to load the first login of user '%s': %v", username, err)
+
+		return false
+	}
+
+	if firstLogin == nil {
+		if err = ctx.Providers.StorageProvider.SaveUserFirstLogin(ctx, username, now); err != nil {
+			ctx.Logger.Errorf("Unable to save the first login of user '%s': %v", username, err)
+		}
+
+		return false
+	}
+
+	if now.Sub(*firstLogin) < config.GracePeriod {
+		return false
+	}
+
+	info, err := ctx.Providers.StorageProvider.LoadUserInfo(ctx, username)
+	if err != nil {
+		ctx.Logger.Errorf("Unable to load the second factor methods of user '%s': %v", username, err)
+
+		return false
+	}
+
+	if info.HasTOTP || info.HasWebauthn || info.HasDuo {
+		return false
+	}
+
+	ctx.Logger.Infof("User '%s' must enroll a second factor method as the grace period since their first login on %s has elapsed", username, *firstLogin)
+
+	return true
+}
+
+// isTargetURLOutsideTimeWindow returns true if the rule matching the target URL for the user has a time window and the
+// current time is outside of it.
+func isTargetURLOutsideTimeWindow(ctx *middlewares.AutheliaCtx, targetURI, requestMethod string, details *authentication.UserDetails) bool {
+	if targetURI == "" {
+		return false
+	}
+
+	targetURL, err := url.ParseRequestURI(targetURI)
+	if err != nil {
+		return false
+	}
+
+	object := authorization.NewObject(targetURL, requestMethod)
+	object.Header = ctx.RequestHeader()
+
+	_, rule := ctx.Providers.Authorizer.GetRequiredLevelAndRule(
+		authorization.Subject{
+			Username: details.Username,
+			Groups:   details.Groups,
+			IP:       ctx.RemoteIP(),
+		},
+		object)
+
+	if rule == nil || rule.TimeWindow == nil || rule.TimeWindow.IsWithin(ctx.Clock.Now()) {
+		return false
+	}
+
+	ctx.Logger.Infof("User '%s' can't log in to %s outside of the time window of the matched rule", details.Username, targetURI)
+
+	return true
+}
@@ -1,19 +1,27 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/events"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
 )
 
 type FirstFactorSuite struct {
@@ -77,6 +85,69 @@ func (s *FirstFactorSuite) TestShouldFailIfUserProviderCheckPasswordFail() {
 	s.mock.Assert401KO(s.T(), "Authentication failed. Check your credentials.")
 }
 
+func (s *FirstFactorSuite) TestShouldRespondServiceUnavailableWhenUserProviderIsBusy() {
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		Return(false, authentication.ErrBackendBusy)
+
+	// The StorageMock has no expectations so recording an authentication attempt fails the test.
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"keepMeLoggedIn": true
+	}`)
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	assert.Equal(s.T(), "Unable to check the credentials of user 'test': timed out waiting for the concurrency limit of the authentication backend", s.mock.Hook.LastEntry().Message)
+	assert.Equal(s.T(), fasthttp.StatusServiceUnavailable, s.mock.Ctx.Response.StatusCode())
+	assert.Equal(s.T(), `{"status":"KO","message":"The authentication service is busy, please try again later."}`, string(s.mock.Ctx.Response.Body()))
+}
+
+func (s *FirstFactorSuite) TestShouldRespondExpiredWhenPasswordHasExpired() {
+	testCases := []struct {
+		name     string
+		action   string
+		err      error
+		expected string
+	}{
+		{"ShouldDenyAccountExpired", schema.LDAPPasswordExpirationActionReset, authentication.ErrAccountExpired, messageAccountExpired},
+		{"ShouldDenyPasswordExpired", schema.LDAPPasswordExpirationActionDeny, authentication.ErrPasswordExpired, messagePasswordExpired},
+		{"ShouldDenyPasswordMustChange", schema.LDAPPasswordExpirationActionDeny, authentication.ErrPasswordMustChange, messagePasswordMustChange},
+		{"ShouldResetPasswordExpired", schema.LDAPPasswordExpirationActionReset, authentication.ErrPasswordExpired, messagePasswordResetRequired},
+		{"ShouldResetPasswordMustChange", schema.LDAPPasswordExpirationActionReset, authentication.ErrPasswordMustChange, messagePasswordResetRequired},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Configuration.AuthenticationBackend.LDAP = &schema.LDAPAuthenticationBackendConfiguration{
+				PasswordExpiration: schema.LDAPPasswordExpirationConfiguration{
+					Enabled: true,
+					Action:  tc.action,
+				},
+			}
+
+			mock.UserProviderMock.
+				EXPECT().
+				CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+				Return(false, tc.err)
+
+			// The StorageMock has no expectations so recording an authentication attempt fails the test.
+			mock.Ctx.Request.SetBodyString(`{
+				"username": "test",
+				"password": "hello"
+			}`)
+			FirstFactorPOST(nil)(mock.Ctx)
+
+			mock.Assert200KO(t, tc.expected)
+			assert.Equal(t, fmt.Sprintf("User 'test' can't log in: %v", tc.err), mock.Hook.LastEntry().Message)
+		})
+	}
+}
+
 func (s *FirstFactorSuite) TestShouldCheckAuthenticationIsNotMarkedWhenProviderCheckPasswordError() {
 	s.mock.UserProviderMock.
 		EXPECT().
@@ -218,6 +289,88 @@ func (s *FirstFactorSuite) TestShouldAuthenticateUserWithRememberMeChecked() {
 	assert.Equal(s.T(), []string{"dev", "admins"}, session.Groups)
 }
 
+func (s *FirstFactorSuite) TestShouldAuthenticateUserWithCanonicalUsername() {
+	s.mock.Ctx.Configuration.AuthenticationBackend.UsernameCanonicalization = schema.UsernameCanonicalizationConfiguration{
+		StripDomainSuffixes: []string{"example.com"},
+		Lowercase:           true,
+	}
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		Return(true, nil)
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Eq("test")).
+		Return(&authentication.UserDetails{
+			Username: "test",
+			Emails:   []string{"test@example.com"},
+			Groups:   []string{"dev", "admins"},
+		}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, attempt model.AuthenticationAttempt) error {
+			s.Assert().Equal("test", attempt.Username)
+
+			return nil
+		})
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "TeSt@Example.COM",
+		"password": "hello"
+	}`)
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Assert().Equal("test", s.mock.Ctx.GetSession().Username)
+}
+
+func (s *FirstFactorSuite) TestShouldFailIfConcurrentSessionLimitReached() {
+	config := s.mock.Ctx.Configuration.Session
+	config.Concurrency = schema.SessionConcurrencyConfiguration{Limit: 1, Policy: schema.SessionConcurrencyPolicyReject}
+
+	provider := session.NewProvider(config, nil, nil)
+	s.mock.Ctx.Providers.SessionProvider = provider
+
+	// Establish an existing session for the user from another client.
+	other := &fasthttp.RequestCtx{}
+	s.Require().NoError(provider.SaveSession(other, session.NewDefaultUserSession()))
+	s.Require().NoError(provider.RegisterUserSession(other, "test", false))
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		Return(true, nil)
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Eq("test")).
+		Return(&authentication.UserDetails{
+			Username: "test",
+			Emails:   []string{"test@example.com"},
+			Groups:   []string{"dev", "admins"},
+		}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"keepMeLoggedIn": false
+	}`)
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	assert.Equal(s.T(), "Could not register session during 1FA authentication for user 'test': the concurrent session limit has been reached", s.mock.Hook.LastEntry().Message)
+	s.mock.Assert401KO(s.T(), messageConcurrentSessionLimitReached)
+	assert.Equal(s.T(), authentication.NotAuthenticated, s.mock.Ctx.GetSession().AuthenticationLevel)
+}
+
 func (s *FirstFactorSuite) TestShouldAuthenticateUserWithRememberMeUnchecked() {
 	s.mock.UserProviderMock.
 		EXPECT().
@@ -259,6 +412,286 @@ func (s *FirstFactorSuite) TestShouldAuthenticateUserWithRememberMeUnchecked() {
 	assert.Equal(s.T(), []string{"dev", "admins"}, session.Groups)
 }
 
+func (s *FirstFactorSuite) TestShouldClearLoginHintWhenAuthenticated() {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.LoginHint = "test"
+
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		Return(true, nil)
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Eq("test")).
+		Return(&authentication.UserDetails{
+			Username: "test",
+			Emails:   []string{"test@example.com"},
+			Groups:   []string{"dev", "admins"},
+		}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"requestMethod": "GET",
+		"keepMeLoggedIn": false
+	}`)
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	assert.Equal(s.T(), 200, s.mock.Ctx.Response.StatusCode())
+
+	session := s.mock.Ctx.GetSession()
+	assert.Equal(s.T(), "test", session.Username)
+	assert.Equal(s.T(), "", session.LoginHint)
+}
+
+func (s *FirstFactorSuite) TestShouldRecordFirstLoginWhenSecondFactorEnrollmentEnforced() {
+	s.mock.Ctx.Configuration.SecondFactorEnrollment = schema.SecondFactorEnrollmentConfiguration{Enforce: true, GracePeriod: time.Hour * 24 * 7}
+
+	s.expectSecondFactorEnrollmentLogin()
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadUserFirstLogin(s.mock.Ctx, gomock.Eq("test")).
+		Return(nil, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		SaveUserFirstLogin(s.mock.Ctx, gomock.Eq("test"), gomock.Eq(s.mock.Clock.Now())).
+		Return(nil)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Assert().False(s.mock.Ctx.GetSession().SecondFactorEnrollmentRequired)
+}
+
+func (s *FirstFactorSuite) TestShouldNotRequireSecondFactorEnrollmentWithinGracePeriod() {
+	s.mock.Ctx.Configuration.SecondFactorEnrollment = schema.SecondFactorEnrollmentConfiguration{Enforce: true, GracePeriod: time.Hour * 24 * 7}
+
+	s.expectSecondFactorEnrollmentLogin()
+
+	firstLogin := s.mock.Clock.Now().Add(-time.Hour * 24 * 6)
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadUserFirstLogin(s.mock.Ctx, gomock.Eq("test")).
+		Return(&firstLogin, nil)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Assert().False(s.mock.Ctx.GetSession().SecondFactorEnrollmentRequired)
+}
+
+func (s *FirstFactorSuite) TestShouldNotRequireSecondFactorEnrollmentWhenMethodEnrolled() {
+	s.mock.Ctx.Configuration.SecondFactorEnrollment = schema.SecondFactorEnrollmentConfiguration{Enforce: true, GracePeriod: time.Hour * 24 * 7}
+
+	s.expectSecondFactorEnrollmentLogin()
+
+	firstLogin := s.mock.Clock.Now().Add(-time.Hour * 24 * 8)
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			LoadUserFirstLogin(s.mock.Ctx, gomock.Eq("test")).
+			Return(&firstLogin, nil),
+		s.mock.StorageMock.
+			EXPECT().
+			LoadUserInfo(s.mock.Ctx, gomock.Eq("test")).
+			Return(model.UserInfo{HasWebauthn: true}, nil),
+	)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Assert().False(s.mock.Ctx.GetSession().SecondFactorEnrollmentRequired)
+}
+
+func (s *FirstFactorSuite) TestShouldRequireSecondFactorEnrollmentAfterGracePeriod() {
+	s.mock.Ctx.Configuration.SecondFactorEnrollment = schema.SecondFactorEnrollmentConfiguration{Enforce: true, GracePeriod: time.Hour * 24 * 7}
+
+	s.expectSecondFactorEnrollmentLogin()
+
+	firstLogin := s.mock.Clock.Now().Add(-time.Hour * 24 * 8)
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			LoadUserFirstLogin(s.mock.Ctx, gomock.Eq("test")).
+			Return(&firstLogin, nil),
+		s.mock.StorageMock.
+			EXPECT().
+			LoadUserInfo(s.mock.Ctx, gomock.Eq("test")).
+			Return(model.UserInfo{}, nil),
+	)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Assert().True(s.mock.Ctx.GetSession().SecondFactorEnrollmentRequired)
+	s.Assert().Equal(authentication.OneFactor, s.mock.Ctx.GetSession().AuthenticationLevel)
+}
+
+func (s *FirstFactorSuite) setupImpossibleTravel(remoteIP string) {
+	s.mock.Ctx.Configuration.Regulation.ImpossibleTravel = schema.DefaultRegulationConfiguration.ImpossibleTravel
+	s.mock.Ctx.Configuration.Regulation.ImpossibleTravel.Enabled = true
+	s.mock.Ctx.Configuration.Regulation.ImpossibleTravel.Networks = []string{"10.0.0.0/8"}
+	s.mock.Ctx.Providers.Regulator = regulation.NewRegulator(s.mock.Ctx.Configuration.Regulation, s.mock.StorageMock, &s.mock.Clock)
+
+	s.mock.Ctx.SetRemoteAddr(&net.TCPAddr{IP: net.ParseIP(remoteIP), Port: 443})
+	s.mock.Ctx.Request.Header.Set("X-Geo-Latitude", "-33.8688")
+	s.mock.Ctx.Request.Header.Set("X-Geo-Longitude", "151.2093")
+}
+
+func (s *FirstFactorSuite) TestShouldDetectImpossibleTravelFromTrustedNetwork() {
+	s.setupImpossibleTravel("10.0.0.1")
+	s.expectSecondFactorEnrollmentLogin()
+
+	// London to Sydney is roughly 17,000km which can't be travelled in an hour.
+	s.mock.StorageMock.
+		EXPECT().
+		LoadUserLoginLocation(s.mock.Ctx, gomock.Eq("test")).
+		Return(&model.UserLoginLocation{Time: s.mock.Clock.Now().Add(-time.Hour), Username: "test", Latitude: 51.5072, Longitude: -0.1276}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		SaveUserLoginLocation(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Assert().True(s.mock.Ctx.GetSession().ImpossibleTravel)
+}
+
+func (s *FirstFactorSuite) TestShouldNotifyUserWhenImpossibleTravelDetected() {
+	s.setupImpossibleTravel("10.0.0.1")
+	s.expectSecondFactorEnrollmentLogin()
+
+	s.mock.Ctx.Configuration.Regulation.ImpossibleTravel.Notify = true
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadUserLoginLocation(s.mock.Ctx, gomock.Eq("test")).
+		Return(&model.UserLoginLocation{Time: s.mock.Clock.Now().Add(-time.Hour), Username: "test", Latitude: 51.5072, Longitude: -0.1276}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		SaveUserLoginLocation(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	var body string
+
+	s.mock.NotifierMock.
+		EXPECT().
+		Send(gomock.Eq("test@example.com"), gomock.Eq("Unusual sign-in to your account"), gomock.Any(), gomock.Eq("")).
+		DoAndReturn(func(_, _, text, _ string) error {
+			body = text
+			return nil
+		})
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Assert().True(s.mock.Ctx.GetSession().ImpossibleTravel)
+	s.Assert().Contains(body, "51.5072")
+	s.Assert().Contains(body, "10.0.0.1")
+}
+
+func (s *FirstFactorSuite) TestShouldIgnoreLocationFromUntrustedNetwork() {
+	s.setupImpossibleTravel("192.168.0.1")
+	s.expectSecondFactorEnrollmentLogin()
+
+	// The StorageMock has no location expectations so loading or saving the location fails the test.
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Assert().False(s.mock.Ctx.GetSession().ImpossibleTravel)
+}
+
+func (s *FirstFactorSuite) TestShouldFailOutsideTimeWindowOfTargetURL() {
+	s.mock.Ctx.Clock = &s.mock.Clock
+	s.mock.Clock.Set(time.Date(2022, time.January, 10, 18, 0, 0, 0, time.UTC))
+
+	s.mock.Ctx.Configuration.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:    []string{"one-factor.example.com"},
+			Policy:     "one_factor",
+			TimeWindow: &schema.ACLTimeWindow{Start: "09:00", End: "17:00", Timezone: "UTC"},
+		},
+	}
+	s.mock.Ctx.Providers.Authorizer = authorization.NewAuthorizer(&s.mock.Ctx.Configuration)
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		Return(true, nil)
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Eq("test")).
+		Return(&authentication.UserDetails{
+			Username: "test",
+			Emails:   []string{"test@example.com"},
+			Groups:   []string{"dev", "admins"},
+		}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"targetURL": "https://one-factor.example.com",
+		"requestMethod": "GET"
+	}`)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageOutsideTimeWindow)
+	s.Assert().Equal("User 'test' can't log in to https://one-factor.example.com outside of the time window of the matched rule", s.mock.Hook.LastEntry().Message)
+	s.Assert().Equal(authentication.NotAuthenticated, s.mock.Ctx.GetSession().AuthenticationLevel)
+}
+
+func (s *FirstFactorSuite) expectSecondFactorEnrollmentLogin() {
+	s.mock.Ctx.Clock = &s.mock.Clock
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		Return(true, nil)
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Eq("test")).
+		Return(&authentication.UserDetails{
+			Username: "test",
+			Emails:   []string{"test@example.com"},
+			Groups:   []string{"dev", "admins"},
+		}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"requestMethod": "GET"
+	}`)
+}
+
 func (s *FirstFactorSuite) TestShouldSaveUsernameFromAuthenticationBackendInSession() {
 	s.mock.UserProviderMock.
 		EXPECT().
@@ -303,6 +736,69 @@ func (s *FirstFactorSuite) TestShouldSaveUsernameFromAuthenticationBackendInSess
 	assert.Equal(s.T(), []string{"dev", "admins"}, session.Groups)
 }
 
+func (s *FirstFactorSuite) TestShouldRegenerateSessionAndPreserveOIDCWorkflow() {
+	challengeID := uuid.New()
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.ConsentChallengeID = &challengeID
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	provider := s.mock.Ctx.Providers.SessionProvider
+	oldID := string(s.mock.Ctx.Request.Header.Cookie("authelia_session"))
+	s.Require().NotEmpty(oldID)
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		Return(true, nil)
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Eq("test")).
+		Return(&authentication.UserDetails{
+			Username: "test",
+			Emails:   []string{"test@example.com"},
+			Groups:   []string{"dev"},
+		}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	// The consent challenge of the OpenID Connect flow must survive the regeneration of the session.
+	s.mock.StorageMock.
+		EXPECT().
+		LoadOAuth2ConsentSessionByChallengeID(s.mock.Ctx, gomock.Eq(challengeID)).
+		Return(nil, fmt.Errorf("not found"))
+
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"keepMeLoggedIn": true
+	}`)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	newID := string(s.mock.Ctx.Request.Header.Cookie("authelia_session"))
+
+	s.Assert().NotEqual(oldID, newID)
+
+	_, found, err := provider.GetSessionByID(oldID)
+	s.Require().NoError(err)
+	s.Assert().False(found)
+
+	userSession, found, err = provider.GetSessionByID(newID)
+	s.Require().NoError(err)
+	s.Require().True(found)
+	s.Assert().Equal("test", userSession.Username)
+	s.Assert().Equal(authentication.OneFactor, userSession.AuthenticationLevel)
+	s.Assert().True(userSession.KeepMeLoggedIn)
+	s.Assert().Equal(&challengeID, userSession.ConsentChallengeID)
+}
+
 type FirstFactorRedirectionSuite struct {
 	suite.Suite
 
@@ -440,7 +936,317 @@ func (s *FirstFactorRedirectionSuite) TestShouldReply200WhenUnsafeTargetURLProvi
 	s.mock.Assert200OK(s.T(), nil)
 }
 
+type FirstFactorCAPTCHASuite struct {
+	suite.Suite
+
+	mock        *mocks.MockAutheliaCtx
+	captchaMock *mocks.MockCAPTCHAProvider
+}
+
+func (s *FirstFactorCAPTCHASuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Ctx.Logger.Logger.SetLevel(logrus.DebugLevel)
+
+	s.captchaMock = mocks.NewMockCAPTCHAProvider(s.mock.Ctrl)
+	s.mock.Ctx.Providers.CAPTCHA = s.captchaMock
+
+	s.captchaMock.EXPECT().Threshold().Return(2).AnyTimes()
+}
+
+func (s *FirstFactorCAPTCHASuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *FirstFactorCAPTCHASuite) expectFailedAttempts(failed int) *gomock.Call {
+	attempts := make([]model.AuthenticationAttempt, failed)
+
+	for i := range attempts {
+		attempts[i] = model.AuthenticationAttempt{Username: "test", Successful: false, Time: s.mock.Clock.Now()}
+	}
+
+	return s.mock.StorageMock.
+		EXPECT().
+		LoadAuthenticationLogs(s.mock.Ctx, gomock.Eq("test"), gomock.Any(), gomock.Eq(2), gomock.Eq(0)).
+		Return(attempts, nil)
+}
+
+func (s *FirstFactorCAPTCHASuite) expectInvalidCredentials() {
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		Return(false, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+}
+
+func (s *FirstFactorCAPTCHASuite) TestShouldRequireCAPTCHAAfterThreshold() {
+	gomock.InOrder(
+		s.expectFailedAttempts(1),
+		s.expectFailedAttempts(2),
+	)
+
+	s.expectInvalidCredentials()
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello"
+	}`)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageAuthenticationFailed)
+	s.Assert().True(s.mock.Ctx.GetSession().CAPTCHARequired)
+}
+
+func (s *FirstFactorCAPTCHASuite) TestShouldFailWhenCAPTCHATokenIsMissing() {
+	s.expectFailedAttempts(2)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello"
+	}`)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageCAPTCHARequired)
+	s.Assert().Equal("User 'test' must complete a CAPTCHA challenge but no token was provided", s.mock.Hook.LastEntry().Message)
+	s.Assert().True(s.mock.Ctx.GetSession().CAPTCHARequired)
+}
+
+func (s *FirstFactorCAPTCHASuite) TestShouldFailWhenCAPTCHATokenIsInvalid() {
+	s.expectFailedAttempts(3)
+
+	s.captchaMock.EXPECT().Verify(s.mock.Ctx, gomock.Eq("token"), gomock.Any()).Return(false, nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"captchaToken": "token"
+	}`)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageCAPTCHARequired)
+	s.Assert().Equal("User 'test' provided an invalid CAPTCHA token", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *FirstFactorCAPTCHASuite) TestShouldFailClosedWhenCAPTCHAProviderFails() {
+	s.expectFailedAttempts(2)
+
+	s.captchaMock.EXPECT().Verify(s.mock.Ctx, gomock.Eq("token"), gomock.Any()).Return(false, fmt.Errorf("timeout"))
+	s.captchaMock.EXPECT().FailOpen().Return(false)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"captchaToken": "token"
+	}`)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageCAPTCHARequired)
+	s.Assert().Equal("Unable to verify the CAPTCHA token of user 'test': timeout", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *FirstFactorCAPTCHASuite) TestShouldFailOpenWhenCAPTCHAProviderFails() {
+	s.expectFailedAttempts(2).Times(2)
+
+	s.captchaMock.EXPECT().Verify(s.mock.Ctx, gomock.Eq("token"), gomock.Any()).Return(false, fmt.Errorf("timeout"))
+	s.captchaMock.EXPECT().FailOpen().Return(true)
+
+	s.expectInvalidCredentials()
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"captchaToken": "token"
+	}`)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageAuthenticationFailed)
+}
+
+func (s *FirstFactorCAPTCHASuite) TestShouldCheckPasswordWhenCAPTCHATokenIsValid() {
+	s.expectFailedAttempts(2).Times(2)
+
+	s.captchaMock.EXPECT().Verify(s.mock.Ctx, gomock.Eq("token"), gomock.Any()).Return(true, nil)
+
+	s.expectInvalidCredentials()
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"captchaToken": "token"
+	}`)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageAuthenticationFailed)
+}
+
+type FirstFactorBreakGlassSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *FirstFactorBreakGlassSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Ctx.Clock = &s.mock.Clock
+
+	hash, err := authentication.HashPassword("glass", "aFr56HjK3DrB8t3S", authentication.HashingAlgorithmSHA512, 5000, 0, 0, 0, 16)
+	s.Require().NoError(err)
+
+	s.mock.Ctx.Providers.BreakGlass, err = authentication.NewBreakGlassAccount(schema.BreakGlassAuthenticationBackendConfiguration{
+		Enabled:     true,
+		Username:    "breakglass",
+		Password:    hash,
+		DisplayName: "Break-Glass Administrator",
+		Email:       "security@example.com",
+		Group:       "admins",
+	})
+	s.Require().NoError(err)
+
+	s.mock.Ctx.Configuration.AccessControl.DefaultPolicy = "deny"
+	s.mock.Ctx.Configuration.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains: []string{"one-factor.example.com"},
+			Policy:  "one_factor",
+		},
+	}
+	s.mock.Ctx.Providers.Authorizer = authorization.NewAuthorizer(&s.mock.Ctx.Configuration)
+}
+
+func (s *FirstFactorBreakGlassSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *FirstFactorBreakGlassSuite) expectEvent(successful bool) {
+	sink := mocks.NewMockEventSink(s.mock.Ctrl)
+
+	gomock.InOrder(
+		sink.EXPECT().
+			Publish(gomock.Any()).
+			DoAndReturn(func(event events.Event) error {
+				s.Assert().Equal(events.TypeBreakGlassAuthentication, event.Type)
+				s.Assert().Equal("breakglass", event.Username)
+				s.Assert().Equal("0.0.0.0", event.RemoteIP)
+				s.Require().NotNil(event.Successful)
+				s.Assert().Equal(successful, *event.Successful)
+
+				return nil
+			}),
+		sink.EXPECT().Publish(gomock.Any()).Return(nil),
+		sink.EXPECT().Close().Return(nil),
+	)
+
+	s.mock.Ctx.Providers.Events = events.NewEmitter(sink, 10, &s.mock.Clock)
+}
+
+func (s *FirstFactorBreakGlassSuite) TestShouldAuthenticateWithoutUserProviderAndRequireSecondFactor() {
+	s.expectEvent(true)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "breakglass",
+			Successful: true,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthType1FA,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		})).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "BreakGlass",
+		"password": "glass",
+		"targetURL": "https://one-factor.example.com",
+		"requestMethod": "GET",
+		"keepMeLoggedIn": false
+	}`)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Require().NoError(s.mock.Ctx.Providers.Events.Close())
+
+	userSession := s.mock.Ctx.GetSession()
+	s.Assert().Equal("breakglass", userSession.Username)
+	s.Assert().Equal(authentication.OneFactor, userSession.AuthenticationLevel)
+	s.Assert().True(userSession.BreakGlass)
+	s.Assert().Equal([]string{"admins"}, userSession.Groups)
+	s.Assert().Equal([]string{"security@example.com"}, userSession.Emails)
+
+	s.Assert().Equal("https://one-factor.example.com requires 2FA, cannot be redirected yet", s.mock.Hook.LastEntry().Message)
+
+	for _, entry := range s.mock.Hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel && entry.Message == "The break-glass account 'breakglass' was used to log in from 0.0.0.0, the second factor is required" {
+			return
+		}
+	}
+
+	s.Fail("the use of the break-glass account was not logged")
+}
+
+func (s *FirstFactorBreakGlassSuite) TestShouldFailWithInvalidPassword() {
+	s.expectEvent(false)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "breakglass",
+			Successful: false,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthType1FA,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		})).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "breakglass",
+		"password": "hello",
+		"keepMeLoggedIn": false
+	}`)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageAuthenticationFailed)
+	s.Require().NoError(s.mock.Ctx.Providers.Events.Close())
+
+	s.Assert().False(s.mock.Ctx.GetSession().BreakGlass)
+}
+
+func (s *FirstFactorBreakGlassSuite) TestShouldUseUserProviderForOtherUsers() {
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Eq("test"), gomock.Eq("glass")).
+		Return(false, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "glass",
+		"keepMeLoggedIn": false
+	}`)
+
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageAuthenticationFailed)
+}
+
 func TestFirstFactorSuite(t *testing.T) {
 	suite.Run(t, new(FirstFactorSuite))
 	suite.Run(t, new(FirstFactorRedirectionSuite))
+	suite.Run(t, new(FirstFactorCAPTCHASuite))
+	suite.Run(t, new(FirstFactorBreakGlassSuite))
 }
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/notification"
+	"github.com/authelia/authelia/v4/internal/templates"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// LoginNotificationSuppressGET handler for suppressing future login notifications for the device a login notification
+// was sent for, using the token included in the notification. The user is redirected to the portal afterwards.
+func LoginNotificationSuppressGET(ctx *middlewares.AutheliaCtx) {
+	username, token := string(ctx.QueryArgs().Peek("username")), string(ctx.QueryArgs().Peek("token"))
+
+	id, err := strconv.Atoi(string(ctx.QueryArgs().Peek("id")))
+	if err != nil || username == "" || token == "" {
+		ctx.Error(fmt.Errorf("no username, id, or token was provided to suppress login notifications"), messageUnableToSuppressLoginNotifications)
+		return
+	}
+
+	fingerprints, err := ctx.Providers.StorageProvider.LoadLoginFingerprints(ctx, username)
+	if err != nil {
+		ctx.Error(err, messageUnableToSuppressLoginNotifications)
+		return
+	}
+
+	var fingerprint *model.LoginFingerprint
+
+	for i := range fingerprints {
+		if fingerprints[i].ID == id && fingerprints[i].MatchesSuppressToken(token) {
+			fingerprint = &fingerprints[i]
+			break
+		}
+	}
+
+	if fingerprint == nil {
+		ctx.Error(fmt.Errorf("the login notification suppression token of user '%s' is invalid or has expired", username), messageUnableToSuppressLoginNotifications)
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.SuppressLoginFingerprint(ctx, username, fingerprint.ID); err != nil {
+		ctx.Error(err, messageUnableToSuppressLoginNotifications)
+		return
+	}
+
+	ctx.Logger.Infof("User '%s' has suppressed login notifications for a device", username)
+
+	uri, err := ctx.ExternalRootURL()
+	if err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	ctx.Redirect(uri, fasthttp.StatusFound)
+}
+
+// sendLoginNotification records the fingerprint of a successful login and sends a notification to the user when the
+// login is from a device or location which hasn't been seen recently. The first login of a user only records the
+// fingerprint, and at most one notification is sent to a user per the configured throttle. Failures are logged and
+// otherwise ignored so users are never prevented from logging in by this feature.
+func sendLoginNotification(ctx *middlewares.AutheliaCtx, details *authentication.UserDetails) {
+	config := ctx.Configuration.LoginNotifications
+
+	if !config.Enabled {
+		return
+	}
+
+	now := ctx.Clock.Now()
+
+	var country string
+
+	if config.HeaderCountry != "" {
+		country = string(ctx.Request.Header.Peek(config.HeaderCountry))
+	}
+
+	current := model.NewLoginFingerprint(details.Username, string(ctx.UserAgent()), ctx.RemoteIP(), country, now)
+
+	if err := ctx.Providers.StorageProvider.DeleteLoginFingerprints(ctx, details.Username, now.Add(-config.Remember)); err != nil {
+		ctx.Logger.Errorf("Unable to process the login notification for user '%s': %+v", details.Username, err)
+		return
+	}
+
+	fingerprints, err := ctx.Providers.StorageProvider.LoadLoginFingerprints(ctx, details.Username)
+	if err != nil {
+		ctx.Logger.Errorf("Unable to process the login notification for user '%s': %+v", details.Username, err)
+		return
+	}
+
+	var (
+		recognized   bool
+		lastNotified time.Time
+	)
+
+	for _, fingerprint := range fingerprints {
+		if fingerprint.Matches(current) {
+			if err = ctx.Providers.StorageProvider.UpdateLoginFingerprintSeen(ctx, fingerprint.ID, now); err != nil {
+				ctx.Logger.Errorf("Unable to process the login notification for user '%s': %+v", details.Username, err)
+			}
+
+			return
+		}
+
+		if fingerprint.Recognizes(current) {
+			recognized = true
+		}
+
+		if fingerprint.NotifiedAt != nil && fingerprint.NotifiedAt.After(lastNotified) {
+			lastNotified = *fingerprint.NotifiedAt
+		}
+	}
+
+	var token string
+
+	switch {
+	case recognized:
+		ctx.Logger.Debugf("Not sending a login notification to user '%s' as they have suppressed notifications for this device", details.Username)
+	case len(fingerprints) == 0:
+		ctx.Logger.Debugf("Not sending a login notification to user '%s' as this is the first recorded login", details.Username)
+	case now.Sub(lastNotified) < config.Throttle:
+		ctx.Logger.Debugf("Not sending a login notification to user '%s' as one was sent at %s", details.Username, lastNotified)
+	case len(details.Emails) == 0:
+		ctx.Logger.Errorf("Unable to send the login notification to user '%s': the user has no email address", details.Username)
+	default:
+		token = utils.RandomString(loginNotificationSuppressTokenLength, utils.AlphaNumericCharacters, true)
+
+		current.NotifiedAt = &now
+		current.SetSuppressToken(token)
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveLoginFingerprint(ctx, current); err != nil {
+		ctx.Logger.Errorf("Unable to process the login notification for user '%s': %+v", details.Username, err)
+		return
+	}
+
+	if token == "" {
+		return
+	}
+
+	if err = sendLoginNotificationEmail(ctx, details, token, now); err != nil {
+		ctx.Logger.Errorf("Unable to send the login notification to user '%s': %+v", details.Username, err)
+	}
+}
+
+func sendLoginNotificationEmail(ctx *middlewares.AutheliaCtx, details *authentication.UserDetails, token string, now time.Time) (err error) {
+	// The fingerprint was just saved so it's loaded again to determine the id for the suppression link.
+	fingerprints, err := ctx.Providers.StorageProvider.LoadLoginFingerprints(ctx, details.Username)
+	if err != nil {
+		return err
+	}
+
+	var id int
+
+	for _, fingerprint := range fingerprints {
+		if fingerprint.MatchesSuppressToken(token) {
+			id = fingerprint.ID
+			break
+		}
+	}
+
+	if id == 0 {
+		return fmt.Errorf("the login fingerprint could not be found after it was saved")
+	}
+
+	uri, err := ctx.ExternalRootURL()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("username", details.Username)
+	query.Set("id", strconv.Itoa(id))
+	query.Set("token", token)
+
+	bufText := new(bytes.Buffer)
+
+	if err = templates.EmailLoginNotificationPlainText.Execute(bufText, map[string]interface{}{
+		"Username":    details.Username,
+		"DisplayName": details.DisplayName,
+		"Time":        now.Format(time.RFC1123),
+		"RemoteIP":    ctx.RemoteIP().String(),
+		"UserAgent":   string(ctx.UserAgent()),
+		"LinkURL":     fmt.Sprintf("%s/api/login-notifications/suppress?%s", uri, query.Encode()),
+	}); err != nil {
+		return err
+	}
+
+	ctx.Logger.Debugf("Sending a login notification to user '%s' (%s)", details.Username, details.Emails[0])
+
+	return notification.SendEvent(ctx.Providers.Notifier, notification.EventLogin, details.Emails[0], "New sign-in to your account", bufText.String(), "")
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+type LoginNotificationSuite struct {
+	suite.Suite
+
+	mock    *mocks.MockAutheliaCtx
+	details *authentication.UserDetails
+}
+
+func (s *LoginNotificationSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Clock.Set(time.Unix(1640000000, 0))
+	s.mock.Ctx.Clock = &s.mock.Clock
+	s.mock.Ctx.Logger.Logger.SetLevel(logrus.DebugLevel)
+
+	s.mock.Ctx.Configuration.LoginNotifications = schema.LoginNotificationsConfiguration{
+		Enabled:       true,
+		Remember:      time.Hour * 24 * 90,
+		Throttle:      time.Hour,
+		HeaderCountry: "X-Geo-Country",
+	}
+
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-For", "192.168.1.20")
+	s.mock.Ctx.Request.Header.Set("X-Geo-Country", "NZ")
+	s.mock.Ctx.Request.Header.SetUserAgent("Mozilla/5.0")
+
+	s.details = &authentication.UserDetails{Username: testUsername, DisplayName: "John Doe", Emails: []string{"john@example.com"}}
+}
+
+func (s *LoginNotificationSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *LoginNotificationSuite) fingerprint(id int, userAgent, ip string) model.LoginFingerprint {
+	fingerprint := model.NewLoginFingerprint(testUsername, userAgent, net.ParseIP(ip), "NZ", s.mock.Clock.Now().Add(-time.Hour*24))
+	fingerprint.ID = id
+
+	return fingerprint
+}
+
+func (s *LoginNotificationSuite) expectDelete() *gomock.Call {
+	return s.mock.StorageMock.EXPECT().
+		DeleteLoginFingerprints(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(s.mock.Clock.Now().Add(-time.Hour*24*90))).
+		Return(nil)
+}
+
+func (s *LoginNotificationSuite) TestShouldSendNotificationForNewDevice() {
+	var (
+		saved model.LoginFingerprint
+		body  string
+	)
+
+	known := []model.LoginFingerprint{s.fingerprint(1, "curl/7.0", "192.168.1.20")}
+
+	gomock.InOrder(
+		s.expectDelete(),
+		s.mock.StorageMock.EXPECT().
+			LoadLoginFingerprints(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(known, nil),
+		s.mock.StorageMock.EXPECT().
+			SaveLoginFingerprint(s.mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, f model.LoginFingerprint) error {
+				saved = f
+				return nil
+			}),
+		s.mock.StorageMock.EXPECT().
+			LoadLoginFingerprints(s.mock.Ctx, gomock.Eq(testUsername)).
+			DoAndReturn(func(_ interface{}, _ string) ([]model.LoginFingerprint, error) {
+				saved.ID = 2
+				return append(known, saved), nil
+			}),
+		s.mock.NotifierMock.EXPECT().
+			Send(gomock.Eq("john@example.com"), gomock.Eq("New sign-in to your account"), gomock.Any(), gomock.Eq("")).
+			DoAndReturn(func(_, _, text, _ string) error {
+				body = text
+				return nil
+			}),
+	)
+
+	sendLoginNotification(s.mock.Ctx, s.details)
+
+	s.Assert().Equal("192.168.1.0/24", saved.Network)
+	s.Assert().Equal("NZ", saved.Country)
+	s.Require().NotNil(saved.NotifiedAt)
+	s.Assert().Equal(s.mock.Clock.Now(), *saved.NotifiedAt)
+
+	s.Assert().Contains(body, "IP: 192.168.1.20")
+	s.Assert().Contains(body, "Device: Mozilla/5.0")
+
+	matches := regexp.MustCompile(`https://auth\.example\.com/api/login-notifications/suppress\?id=2&token=([a-zA-Z0-9]{32})&username=john`).FindStringSubmatch(body)
+	s.Require().Len(matches, 2)
+	s.Assert().True(saved.MatchesSuppressToken(matches[1]))
+}
+
+func (s *LoginNotificationSuite) TestShouldUpdateKnownFingerprint() {
+	gomock.InOrder(
+		s.expectDelete(),
+		s.mock.StorageMock.EXPECT().
+			LoadLoginFingerprints(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return([]model.LoginFingerprint{s.fingerprint(1, "Mozilla/5.0", "192.168.1.99")}, nil),
+		s.mock.StorageMock.EXPECT().
+			UpdateLoginFingerprintSeen(s.mock.Ctx, gomock.Eq(1), gomock.Eq(s.mock.Clock.Now())).
+			Return(nil),
+	)
+
+	sendLoginNotification(s.mock.Ctx, s.details)
+}
+
+func (s *LoginNotificationSuite) TestShouldNotSendNotificationForFirstLogin() {
+	gomock.InOrder(
+		s.expectDelete(),
+		s.mock.StorageMock.EXPECT().
+			LoadLoginFingerprints(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(nil, nil),
+		s.mock.StorageMock.EXPECT().
+			SaveLoginFingerprint(s.mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, f model.LoginFingerprint) error {
+				s.Assert().Nil(f.NotifiedAt)
+				s.Assert().Equal("", f.SuppressTokenHash)
+				return nil
+			}),
+	)
+
+	sendLoginNotification(s.mock.Ctx, s.details)
+}
+
+func (s *LoginNotificationSuite) TestShouldNotSendNotificationForSuppressedDevice() {
+	suppressed := s.fingerprint(1, "Mozilla/5.0", "10.0.0.1")
+	suppressed.Suppressed = true
+
+	gomock.InOrder(
+		s.expectDelete(),
+		s.mock.StorageMock.EXPECT().
+			LoadLoginFingerprints(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return([]model.LoginFingerprint{suppressed}, nil),
+		s.mock.StorageMock.EXPECT().
+			SaveLoginFingerprint(s.mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, f model.LoginFingerprint) error {
+				s.Assert().Nil(f.NotifiedAt)
+				return nil
+			}),
+	)
+
+	sendLoginNotification(s.mock.Ctx, s.details)
+
+	s.Assert().Equal("Not sending a login notification to user 'john' as they have suppressed notifications for this device", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *LoginNotificationSuite) TestShouldThrottleNotifications() {
+	notified := s.fingerprint(1, "curl/7.0", "10.0.0.1")
+	notifiedAt := s.mock.Clock.Now().Add(-time.Minute * 30)
+	notified.NotifiedAt = &notifiedAt
+
+	gomock.InOrder(
+		s.expectDelete(),
+		s.mock.StorageMock.EXPECT().
+			LoadLoginFingerprints(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return([]model.LoginFingerprint{notified}, nil),
+		s.mock.StorageMock.EXPECT().
+			SaveLoginFingerprint(s.mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, f model.LoginFingerprint) error {
+				s.Assert().Nil(f.NotifiedAt)
+				return nil
+			}),
+	)
+
+	sendLoginNotification(s.mock.Ctx, s.details)
+
+	s.Assert().Regexp(`^Not sending a login notification to user 'john' as one was sent at`, s.mock.Hook.LastEntry().Message)
+}
+
+func (s *LoginNotificationSuite) TestShouldNotSendNotificationWhenDisabled() {
+	s.mock.Ctx.Configuration.LoginNotifications.Enabled = false
+
+	sendLoginNotification(s.mock.Ctx, s.details)
+}
+
+func (s *LoginNotificationSuite) TestShouldLogErrorOnStorageError() {
+	gomock.InOrder(
+		s.expectDelete(),
+		s.mock.StorageMock.EXPECT().
+			LoadLoginFingerprints(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(nil, fmt.Errorf("failed to connect")),
+	)
+
+	sendLoginNotification(s.mock.Ctx, s.details)
+
+	s.Assert().Equal("Unable to process the login notification for user 'john': failed to connect", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *LoginNotificationSuite) TestShouldSuppressNotifications() {
+	fingerprint := s.fingerprint(2, "Mozilla/5.0", "192.168.1.20")
+	fingerprint.SetSuppressToken("abc123")
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadLoginFingerprints(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return([]model.LoginFingerprint{s.fingerprint(1, "curl/7.0", "10.0.0.1"), fingerprint}, nil),
+		s.mock.StorageMock.EXPECT().
+			SuppressLoginFingerprint(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(2)).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetRequestURI("/api/login-notifications/suppress?username=john&id=2&token=abc123")
+
+	LoginNotificationSuppressGET(s.mock.Ctx)
+
+	s.Assert().Equal(302, s.mock.Ctx.Response.StatusCode())
+	s.Assert().Equal("https://auth.example.com/", string(s.mock.Ctx.Response.Header.Peek("Location")))
+}
+
+func (s *LoginNotificationSuite) TestShouldNotSuppressNotificationsWithInvalidToken() {
+	fingerprint := s.fingerprint(2, "Mozilla/5.0", "192.168.1.20")
+	fingerprint.SetSuppressToken("abc123")
+
+	s.mock.StorageMock.EXPECT().
+		LoadLoginFingerprints(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return([]model.LoginFingerprint{fingerprint}, nil)
+
+	s.mock.Ctx.Request.SetRequestURI("/api/login-notifications/suppress?username=john&id=2&token=abc124")
+
+	LoginNotificationSuppressGET(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToSuppressLoginNotifications)
+	s.Assert().Equal("the login notification suppression token of user 'john' is invalid or has expired", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *LoginNotificationSuite) TestShouldNotSuppressNotificationsWithoutID() {
+	s.mock.Ctx.Request.SetRequestURI("/api/login-notifications/suppress?username=john&token=abc123")
+
+	LoginNotificationSuppressGET(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToSuppressLoginNotifications)
+	s.Assert().Equal("no username, id, or token was provided to suppress login notifications", s.mock.Hook.LastEntry().Message)
+}
+
+func TestRunLoginNotificationSuite(t *testing.T) {
+	suite.Run(t, new(LoginNotificationSuite))
+}
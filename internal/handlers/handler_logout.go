@@ -4,19 +4,22 @@ import (
 	"fmt"
 	"net/url"
 
+	"github.com/authelia/authelia/v4/internal/events"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 type logoutBody struct {
 	TargetURL string `json:"targetURL"`
+	Scope     string `json:"scope"`
 }
 
 type logoutResponseBody struct {
 	SafeTargetURL bool `json:"safeTargetURL"`
 }
 
-// LogoutPOST is the handler logging out the user attached to the given cookie.
+// LogoutPOST is the handler logging out the user attached to the given cookie. By default only the current session is
+// destroyed, the all scope also destroys the other sessions of the user.
 func LogoutPOST(ctx *middlewares.AutheliaCtx) {
 	body := logoutBody{}
 	responseBody := logoutResponseBody{SafeTargetURL: false}
@@ -26,9 +29,53 @@ func LogoutPOST(ctx *middlewares.AutheliaCtx) {
 		ctx.Error(fmt.Errorf("unable to parse body during logout: %s", err), messageOperationFailed)
 	}
 
+	userSession := ctx.GetSession()
+
+	switch body.Scope {
+	case "", logoutScopeCurrent:
+		break
+	case logoutScopeAll:
+		if !ctx.Providers.SessionProvider.IsUserSessionsIndexed() {
+			ctx.Error(fmt.Errorf("unable to logout all sessions of user '%s': the sessions of each user are not recorded", userSession.Username), messageOperationFailed)
+
+			return
+		}
+
+		if userSession.Username != "" {
+			destroyed, err := ctx.Providers.SessionProvider.DestroyUserSessions(ctx.RequestCtx, userSession.Username)
+			if err != nil {
+				ctx.Error(fmt.Errorf("unable to destroy the sessions of user '%s' during logout: %w", userSession.Username, err), messageOperationFailed)
+
+				return
+			}
+
+			ctx.Logger.Debugf("Destroyed %d other sessions of user '%s' during logout", destroyed, userSession.Username)
+		}
+	default:
+		ctx.Error(fmt.Errorf("unable to logout with unknown scope '%s'", body.Scope), messageOperationFailed)
+
+		return
+	}
+
+	if oidc := ctx.Configuration.IdentityProviders.OIDC; oidc != nil && oidc.RevokeRefreshTokensOnLogout && userSession.Username != "" {
+		if err = revokeOpenIDConnectRefreshTokens(ctx, userSession.Username); err != nil {
+			ctx.Logger.Errorf("Unable to revoke the refresh tokens of user '%s' during logout: %v", userSession.Username, err)
+		}
+	}
+
+	if userSession.Username != "" {
+		ctx.Providers.UserDetailsCache.Invalidate(userSession.Username)
+	}
+
 	err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx)
 	if err != nil {
 		ctx.Error(fmt.Errorf("unable to destroy session during logout: %s", err), messageOperationFailed)
+	} else if userSession.Username != "" {
+		ctx.Providers.Events.Emit(events.Event{
+			Type:     events.TypeSessionLogout,
+			Username: userSession.Username,
+			RemoteIP: ctx.RemoteIP().String(),
+		})
 	}
 
 	redirectionURL, err := url.Parse(body.TargetURL)
@@ -4,11 +4,14 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/authelia/authelia/v4/internal/events"
 	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/session"
 )
 
 type LogoutSuite struct {
@@ -38,6 +41,86 @@ func (s *LogoutSuite) TestShouldDestroySession() {
 	assert.True(s.T(), strings.HasPrefix(string(b), "authelia_session=;"))
 }
 
+func (s *LogoutSuite) TestShouldEmitLogoutEvent() {
+	sink := mocks.NewMockEventSink(s.mock.Ctrl)
+
+	gomock.InOrder(
+		sink.EXPECT().
+			Publish(gomock.Any()).
+			DoAndReturn(func(event events.Event) error {
+				s.Assert().Equal(events.TypeSessionLogout, event.Type)
+				s.Assert().Equal(testUsername, event.Username)
+				s.Assert().Equal("0.0.0.0", event.RemoteIP)
+
+				return nil
+			}),
+		sink.EXPECT().Close().Return(nil),
+	)
+
+	s.mock.Ctx.Providers.Events = events.NewEmitter(sink, 10, &s.mock.Clock)
+
+	LogoutPOST(s.mock.Ctx)
+
+	s.Require().NoError(s.mock.Ctx.Providers.Events.Close())
+}
+
+func (s *LogoutSuite) newOtherSession() string {
+	userSession := session.NewDefaultUserSession()
+	userSession.Username = testUsername
+
+	return s.mock.NewOtherUserSession(s.T(), userSession)
+}
+
+func (s *LogoutSuite) TestShouldOnlyDestroyCurrentSessionByDefault() {
+	other := s.newOtherSession()
+
+	s.Require().NoError(s.mock.Ctx.Providers.SessionProvider.RegisterUserSession(s.mock.Ctx.RequestCtx, testUsername, false))
+
+	for _, body := range []string{`{}`, `{"scope":"current"}`} {
+		s.mock.Ctx.Request.SetBodyString(body)
+
+		LogoutPOST(s.mock.Ctx)
+
+		s.Equal(200, s.mock.Ctx.Response.StatusCode())
+
+		_, found, err := s.mock.Ctx.Providers.SessionProvider.GetSessionByID(other)
+		s.Require().NoError(err)
+		s.True(found)
+	}
+}
+
+func (s *LogoutSuite) TestShouldDestroyAllSessionsWithAllScope() {
+	other := s.newOtherSession()
+
+	s.Require().NoError(s.mock.Ctx.Providers.SessionProvider.RegisterUserSession(s.mock.Ctx.RequestCtx, testUsername, false))
+
+	s.mock.Ctx.Request.SetBodyString(`{"scope":"all"}`)
+
+	LogoutPOST(s.mock.Ctx)
+
+	s.Equal(200, s.mock.Ctx.Response.StatusCode())
+	s.True(strings.HasPrefix(string(s.mock.Ctx.Response.Header.PeekCookie("authelia_session")), "authelia_session=;"))
+
+	_, found, err := s.mock.Ctx.Providers.SessionProvider.GetSessionByID(other)
+	s.Require().NoError(err)
+	s.False(found)
+}
+
+func (s *LogoutSuite) TestShouldRejectUnknownScope() {
+	other := s.newOtherSession()
+
+	s.mock.Ctx.Request.SetBodyString(`{"scope":"elsewhere"}`)
+
+	LogoutPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Operation failed.")
+	s.Equal("unable to logout with unknown scope 'elsewhere'", s.mock.Hook.LastEntry().Message)
+
+	_, found, err := s.mock.Ctx.Providers.SessionProvider.GetSessionByID(other)
+	s.Require().NoError(err)
+	s.True(found)
+}
+
 func TestRunLogoutSuite(t *testing.T) {
 	s := new(LogoutSuite)
 	suite.Run(t, s)
@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"net/http"
+	"net/url"
 
 	"github.com/ory/fosite"
+	"github.com/pkg/errors"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
 )
 
@@ -15,9 +18,18 @@ import (
 func OAuthIntrospectionPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *http.Request) {
 	var (
 		responder fosite.IntrospectionResponder
+		client    *oidc.Client
 		err       error
 	)
 
+	if client, err = oauthIntrospectionClient(ctx, req); err != nil {
+		ctx.Logger.Errorf("Introspection Request failed with error: %s", fosite.ErrorToRFC6749Error(err).GetDescription())
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteIntrospectionError(rw, err)
+
+		return
+	}
+
 	oidcSession := oidc.NewSession()
 
 	if responder, err = ctx.Providers.OpenIDConnect.Fosite.NewIntrospectionRequest(ctx, req, oidcSession); err != nil {
@@ -34,5 +46,66 @@ func OAuthIntrospectionPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter
 
 	ctx.Logger.Tracef("Introspection Request yeilded a %s (active: %t) requested at %s created with request id '%s' on client with id '%s'", responder.GetTokenUse(), responder.IsActive(), requester.GetRequestedAt().String(), requester.GetID(), requester.GetClient().GetID())
 
+	if !client.IsIntrospectionAllowed(requester) {
+		ctx.Logger.Debugf("Introspection Request by client with id '%s' for a token with request id '%s' on client with id '%s' was reported as inactive as the client is not permitted to introspect tokens of that client", client.GetID(), requester.GetID(), requester.GetClient().GetID())
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteIntrospectionResponse(rw, &fosite.IntrospectionResponse{Active: false})
+
+		return
+	}
+
+	claims := map[string]interface{}{}
+
+	if session, ok := requester.GetSession().(*model.OpenIDSession); ok {
+		claims = client.GetIntrospectionClaims(requester, session.IDTokenClaims().Extra, ctx.Providers.OpenIDConnect.CustomScopes)
+	}
+
+	requester.SetSession(&introspectionSession{Session: requester.GetSession(), claims: claims})
+
 	ctx.Providers.OpenIDConnect.Fosite.WriteIntrospectionResponse(rw, responder)
 }
+
+// oauthIntrospectionClient returns the client making the introspection request, which is either the client of the
+// bearer token or the client of the basic authorization header, and ensures the client is permitted to introspect
+// tokens. The client credentials are verified by fosite.
+func oauthIntrospectionClient(ctx *middlewares.AutheliaCtx, req *http.Request) (client *oidc.Client, err error) {
+	var id string
+
+	if token := fosite.AccessTokenFromRequest(req); token != "" {
+		var requester fosite.AccessRequester
+
+		if _, requester, err = ctx.Providers.OpenIDConnect.Fosite.IntrospectToken(req.Context(), token, fosite.AccessToken, oidc.NewSession()); err != nil {
+			return nil, errors.WithStack(fosite.ErrRequestUnauthorized.WithHint("HTTP Authorization header missing, malformed, or credentials used are invalid."))
+		}
+
+		id = requester.GetClient().GetID()
+	} else {
+		basic, _, ok := req.BasicAuth()
+		if !ok {
+			return nil, errors.WithStack(fosite.ErrRequestUnauthorized.WithHint("HTTP Authorization header missing."))
+		}
+
+		if id, err = url.QueryUnescape(basic); err != nil {
+			return nil, errors.WithStack(fosite.ErrRequestUnauthorized.WithHint("Unable to decode OAuth 2.0 Client ID from HTTP basic authorization header, make sure it is properly encoded."))
+		}
+	}
+
+	if client, err = ctx.Providers.OpenIDConnect.Store.GetFullClient(id); err != nil || !client.IntrospectionEnabled {
+		return nil, errors.WithStack(fosite.ErrRequestUnauthorized.WithHint("The OAuth 2.0 Client is not permitted to introspect tokens."))
+	}
+
+	return client, nil
+}
+
+// introspectionSession limits the extra claims of the introspection response to the claims the client is permitted
+// to receive.
+type introspectionSession struct {
+	fosite.Session
+
+	claims map[string]interface{}
+}
+
+// GetExtraClaims implements fosite.ExtraClaimsSession.
+func (s *introspectionSession) GetExtraClaims() map[string]interface{} {
+	return s.claims
+}
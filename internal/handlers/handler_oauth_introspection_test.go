@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func TestOAuthIntrospectionPOST_ShouldOnlyAllowEnabledClients(t *testing.T) {
+	testCases := []struct {
+		name         string
+		enabled      bool
+		id           string
+		expectedCode int
+		expectedBody string
+	}{
+		{"ShouldRejectClientWithoutIntrospection", false, "test", http.StatusUnauthorized, `"error":"request_unauthorized"`},
+		{"ShouldRejectUnknownClient", true, "unknown", http.StatusUnauthorized, `"error":"request_unauthorized"`},
+		{"ShouldProcessClientWithIntrospection", true, "test", http.StatusOK, `{"active":false}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t, mock, []string{"authorization_code", "refresh_token"}, []string{"code"}, schema.OpenIDConnectClientConfiguration{
+				ID:            "test",
+				Secret:        "secret",
+				Policy:        "two_factor",
+				RedirectURIs:  []string{"https://example.com/callback"},
+				Scopes:        []string{"openid"},
+				GrantTypes:    []string{"authorization_code", "refresh_token"},
+				ResponseTypes: []string{"code"},
+				Introspection: schema.OpenIDConnectClientIntrospectionConfiguration{
+					Enabled: tc.enabled,
+				},
+			})
+
+			mock.StorageMock.EXPECT().
+				LoadOAuth2Session(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(nil, sql.ErrNoRows).
+				AnyTimes()
+
+			form := url.Values{}
+			form.Set("token", "invalid")
+
+			req := httptest.NewRequest(http.MethodPost, "https://auth.example.com/api/oidc/introspection", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetBasicAuth(tc.id, "secret")
+
+			rw := httptest.NewRecorder()
+
+			OAuthIntrospectionPOST(mock.Ctx, rw, req)
+
+			assert.Equal(t, tc.expectedCode, rw.Code)
+			assert.Contains(t, rw.Body.String(), tc.expectedBody)
+		})
+	}
+}
@@ -3,10 +3,12 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/ory/fosite"
+	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
@@ -26,7 +28,15 @@ func OpenIDConnectAuthorizationGET(ctx *middlewares.AutheliaCtx, rw http.Respons
 		err       error
 	)
 
-	if requester, err = ctx.Providers.OpenIDConnect.Fosite.NewAuthorizeRequest(ctx, r); err != nil {
+	requester, err = ctx.Providers.OpenIDConnect.Fosite.NewAuthorizeRequest(ctx, r)
+
+	// The response mode is parsed before the request is validated so errors are also written with the form post
+	// response mode when it was requested.
+	if requester != nil && requester.GetResponseMode() == fosite.ResponseModeFormPost {
+		rw.Header().Set(fasthttp.HeaderContentSecurityPolicy, oidc.FormPostContentSecurityPolicy)
+	}
+
+	if err != nil {
 		rfc := fosite.ErrorToRFC6749Error(err)
 
 		ctx.Logger.Errorf("Authorization Request failed with error: %s", rfc.GetDescription())
@@ -40,6 +50,16 @@ func OpenIDConnectAuthorizationGET(ctx *middlewares.AutheliaCtx, rw http.Respons
 
 	ctx.Logger.Debugf("Authorization Request with id '%s' on client with id '%s' is being processed", requester.GetID(), clientID)
 
+	if !ctx.Providers.OpenIDConnect.IsResponseTypeAllowed(requester.GetResponseTypes()) {
+		responseType := strings.Join(requester.GetResponseTypes(), " ")
+
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: the response type '%s' is not allowed", requester.GetID(), clientID, responseType)
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, fosite.ErrUnsupportedResponseType.WithHintf("The response type '%s' is not allowed by this server.", responseType))
+
+		return
+	}
+
 	if client, err = ctx.Providers.OpenIDConnect.Store.GetFullClient(clientID); err != nil {
 		if errors.Is(err, fosite.ErrNotFound) {
 			ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: client was not found", requester.GetID(), clientID)
@@ -52,6 +72,30 @@ func OpenIDConnectAuthorizationGET(ctx *middlewares.AutheliaCtx, rw http.Respons
 		return
 	}
 
+	oidcApplyDefaultScopes(ctx, requester, client)
+
+	var acr oidc.ACRValue
+
+	if client, acr, err = oidcApplyACRValues(ctx, client, requester.GetRequestForm()); err != nil {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: %+v", requester.GetID(), clientID, err)
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, fosite.ErrInvalidRequest.WithHintf("The 'acr_values' parameter is invalid: %s.", err))
+
+		return
+	}
+
+	var claimsRequest *oidc.ClaimsRequest
+
+	if claimsRequest, err = oidc.NewClaimsRequest(requester.GetRequestForm()); err != nil {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: %+v", requester.GetID(), clientID, err)
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, fosite.ErrInvalidRequest.WithHint("The 'claims' parameter must be a valid JSON object."))
+
+		return
+	}
+
+	oidcApplyClaimsRequest(ctx, requester, client, claimsRequest)
+
 	if issuer, err = ctx.ExternalRootURL(); err != nil {
 		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred determining issuer: %+v", requester.GetID(), clientID, err)
 
@@ -62,9 +106,29 @@ func OpenIDConnectAuthorizationGET(ctx *middlewares.AutheliaCtx, rw http.Respons
 
 	userSession := ctx.GetSession()
 
+	client = oidcApplyBreakGlassPolicy(client, userSession)
+
+	if err = oidcHandleMaximumAuthenticationAge(ctx, requester, &userSession); err != nil {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred checking the maximum authentication age: %+v", requester.GetID(), clientID, err)
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, fosite.ErrInvalidRequest.WithHint("Could not check the maximum authentication age."))
+
+		return
+	}
+
+	oidcApplyLoginHint(ctx, requester, &userSession)
+
+	if userSession.Username != "" && !client.IsUserAllowed(userSession.Groups) {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: user '%s' is not a member of any of the groups allowed to use this client", requester.GetID(), client.GetID(), userSession.Username)
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, fosite.ErrAccessDenied.WithHint("The user is not permitted to use this client."))
+
+		return
+	}
+
 	var subject uuid.UUID
 
-	if subject, err = ctx.Providers.OpenIDConnect.Store.GetSubject(ctx, client.GetSectorIdentifier(), userSession.Username); err != nil {
+	if subject, err = oidcGetSubject(ctx, client, &userSession); err != nil {
 		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred retrieving subject for user '%s': %+v", requester.GetID(), client.GetID(), userSession.Username, err)
 
 		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, fosite.ErrServerError.WithHint("Could not retrieve the subject."))
@@ -81,7 +145,15 @@ func OpenIDConnectAuthorizationGET(ctx *middlewares.AutheliaCtx, rw http.Respons
 		return
 	}
 
-	extraClaims := oidcGrantRequests(requester, consent, &userSession)
+	extraClaims := oidcGrantRequests(requester, consent, &userSession, ctx.Providers.OpenIDConnect.CustomScopes)
+
+	if err = oidcCheckClaimsRequest(claimsRequest, consent.Subject.String(), extraClaims, ctx.Providers.OpenIDConnect.CustomScopes); err != nil {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: %+v", requester.GetID(), client.GetID(), err)
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, fosite.ErrAccessDenied.WithHint(err.Error()))
+
+		return
+	}
 
 	if authTime, err = userSession.AuthenticatedTime(client.Policy); err != nil {
 		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred checking authentication time: %+v", requester.GetID(), client.GetID(), err)
@@ -96,6 +168,10 @@ func OpenIDConnectAuthorizationGET(ctx *middlewares.AutheliaCtx, rw http.Respons
 	oidcSession := oidc.NewSessionWithAuthorizeRequest(issuer, ctx.Providers.OpenIDConnect.KeyManager.GetActiveKeyID(),
 		userSession.Username, userSession.AuthenticationMethodRefs.MarshalRFC8176(), extraClaims, authTime, consent, requester)
 
+	if acr.Value != "" && client.IsAuthenticationLevelSufficient(userSession.AuthenticationLevel) {
+		oidcSession.Claims.AuthenticationContextClassReference = acr.Value
+	}
+
 	ctx.Logger.Tracef("Authorization Request with id '%s' on client with id '%s' creating session for Authorization Response for subject '%s' with username '%s' with claims: %+v",
 		requester.GetID(), oidcSession.ClientID, oidcSession.Subject, oidcSession.Username, oidcSession.Claims)
 	ctx.Logger.Tracef("Authorization Request with id '%s' on client with id '%s' creating session for Authorization Response for subject '%s' with username '%s' with headers: %+v",
@@ -115,10 +115,14 @@ func handleOIDCAuthorizationConsentOrGenerate(ctx *middlewares.AutheliaCtx, root
 		}
 	}
 
-	if consent != nil && consent.HasExactGrants(scopes, audience) && consent.CanGrant() {
+	if consent != nil && consent.HasExactGrants(scopes, audience) && consent.CanGrant() && client.IsAuthenticationLevelSufficient(userSession.AuthenticationLevel) {
 		return consent, false
 	}
 
+	if client.IsPreAuthorized(requester.GetRequestedScopes()) && !oidcIsConsentPromptRequested(requester) && client.IsAuthenticationLevelSufficient(userSession.AuthenticationLevel) {
+		return handleOIDCAuthorizationConsentPreAuthorized(ctx, client, subject, rw, requester)
+	}
+
 	if consent, err = model.NewOAuth2ConsentSession(subject, requester); err != nil {
 		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred generating consent: %+v", requester.GetID(), requester.GetClient().GetID(), err)
 
@@ -150,6 +154,56 @@ func handleOIDCAuthorizationConsentOrGenerate(ctx *middlewares.AutheliaCtx, root
 	return consent, true
 }
 
+// handleOIDCAuthorizationConsentPreAuthorized records a consent session which is implicitly granted by the
+// configuration of a first party client so the user is not asked for consent.
+func handleOIDCAuthorizationConsentPreAuthorized(ctx *middlewares.AutheliaCtx, client *oidc.Client, subject uuid.UUID,
+	rw http.ResponseWriter, requester fosite.AuthorizeRequester) (consent *model.OAuth2ConsentSession, handled bool) {
+	var err error
+
+	if consent, err = model.NewOAuth2ConsentSession(subject, requester); err != nil {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred generating consent: %+v", requester.GetID(), client.GetID(), err)
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, fosite.ErrServerError.WithHint("Could not generate the consent session."))
+
+		return nil, true
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveOAuth2ConsentSession(ctx, *consent); err != nil {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred saving consent session: %+v", requester.GetID(), client.GetID(), err)
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, fosite.ErrServerError.WithHint("Could not save the consent session."))
+
+		return nil, true
+	}
+
+	// The consent session is loaded again as the ID is required to record the response and that it has been granted.
+	if consent, err = ctx.Providers.StorageProvider.LoadOAuth2ConsentSessionByChallengeID(ctx, consent.ChallengeID); err != nil {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred during consent session lookup: %+v", requester.GetID(), client.GetID(), err)
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, fosite.ErrServerError.WithHint("Failed to lookup consent session."))
+
+		return nil, true
+	}
+
+	consent.GrantedScopes, consent.GrantedAudience = getExpectedScopesAndAudience(requester)
+
+	if err = ctx.Providers.StorageProvider.SaveOAuth2ConsentSessionResponse(ctx, *consent, true); err != nil {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred saving consent session response: %+v", requester.GetID(), client.GetID(), err)
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAuthorizeError(rw, requester, fosite.ErrServerError.WithHint("Could not save the consent session."))
+
+		return nil, true
+	}
+
+	respondedAt := ctx.Clock.Now()
+
+	consent.Authorized, consent.RespondedAt = true, &respondedAt
+
+	ctx.Logger.Debugf("Authorization Request with id '%s' on first party client with id '%s' was implicitly granted consent for subject '%s' and scopes '%s'", requester.GetID(), client.GetID(), subject.String(), strings.Join(requester.GetRequestedScopes(), " "))
+
+	return consent, false
+}
+
 func handleOIDCAuthorizationConsentRedirect(destination string, client *oidc.Client, userSession session.UserSession, rw http.ResponseWriter, r *http.Request) {
 	if client.IsAuthenticationLevelSufficient(userSession.AuthenticationLevel) {
 		destination = fmt.Sprintf("%s/consent", destination)
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func newTestConsentAuthorizeRequest(client *oidc.Client, form url.Values, scopes ...string) *fosite.AuthorizeRequest {
+	requester := fosite.NewAuthorizeRequest()
+
+	requester.ID = "abc"
+	requester.Client = client
+	requester.Form = form
+	requester.RequestedScope = scopes
+
+	return requester
+}
+
+func TestHandleOIDCAuthorizationConsentOrGenerate(t *testing.T) {
+	client := &oidc.Client{
+		ID:                  "test",
+		Policy:              authorization.OneFactor,
+		PreAuthorizedScopes: []string{"openid", "profile"},
+	}
+
+	testCases := []struct {
+		name          string
+		form          url.Values
+		scopes        []string
+		preAuthorized bool
+	}{
+		{"ShouldImplicitlyGrantPreAuthorizedScopes", url.Values{}, []string{"openid", "profile"}, true},
+		{"ShouldPromptWhenConsentPromptRequested", url.Values{oidc.FormParameterPrompt: []string{"login consent"}}, []string{"openid"}, false},
+		{"ShouldPromptWhenScopesNotPreAuthorized", url.Values{}, []string{"openid", "groups"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			userSession := mock.Ctx.GetSession()
+			userSession.Username = testUsername
+			userSession.AuthenticationLevel = authentication.OneFactor
+
+			subject := uuid.New()
+			requester := newTestConsentAuthorizeRequest(client, tc.form, tc.scopes...)
+
+			gomock.InOrder(
+				mock.StorageMock.EXPECT().LoadOAuth2ConsentSessionsPreConfigured(mock.Ctx, "test", subject).Return(&storage.ConsentSessionRows{}, nil),
+				mock.StorageMock.EXPECT().SaveOAuth2ConsentSession(mock.Ctx, gomock.Any()).Return(nil),
+			)
+
+			if tc.preAuthorized {
+				mock.StorageMock.EXPECT().LoadOAuth2ConsentSessionByChallengeID(mock.Ctx, gomock.Any()).
+					DoAndReturn(func(_ interface{}, challengeID uuid.UUID) (*model.OAuth2ConsentSession, error) {
+						return &model.OAuth2ConsentSession{ID: 5, ChallengeID: challengeID, ClientID: "test", Subject: subject}, nil
+					})
+				mock.StorageMock.EXPECT().SaveOAuth2ConsentSessionResponse(mock.Ctx, gomock.Any(), true).Return(nil)
+			}
+
+			rw := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "https://auth.example.com/api/oidc/authorization", nil)
+
+			consent, handled := handleOIDCAuthorizationConsentOrGenerate(mock.Ctx, "https://auth.example.com", client, userSession, subject, rw, r, requester)
+
+			require.NotNil(t, consent)
+
+			if tc.preAuthorized {
+				assert.False(t, handled)
+				assert.Equal(t, 5, consent.ID)
+				assert.True(t, consent.CanGrant())
+				assert.Equal(t, model.StringSlicePipeDelimited{"openid", "profile"}, consent.GrantedScopes)
+				assert.Equal(t, model.StringSlicePipeDelimited{"test"}, consent.GrantedAudience)
+				assert.Nil(t, mock.Ctx.GetSession().ConsentChallengeID)
+			} else {
+				assert.True(t, handled)
+				assert.Equal(t, http.StatusFound, rw.Code)
+				assert.Equal(t, "https://auth.example.com/consent", rw.Header().Get("Location"))
+				assert.NotNil(t, mock.Ctx.GetSession().ConsentChallengeID)
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+func TestOpenIDConnectAuthorizationGET_ShouldRejectResponseTypesNotAllowed(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t, mock, []string{"authorization_code", "refresh_token"}, []string{"code"}, schema.OpenIDConnectClientConfiguration{
+		ID:            "test",
+		Secret:        "secret",
+		Policy:        "two_factor",
+		RedirectURIs:  []string{"https://example.com/callback"},
+		Scopes:        []string{"openid"},
+		GrantTypes:    []string{"authorization_code", "implicit"},
+		ResponseTypes: []string{"code", "token"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "https://auth.example.com/api/oidc/authorization?client_id=test&response_type=token&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&scope=openid&state=abcdefghijklmnop", nil)
+
+	rw := httptest.NewRecorder()
+
+	OpenIDConnectAuthorizationGET(mock.Ctx, rw, req)
+
+	assert.Equal(t, http.StatusSeeOther, rw.Code)
+	assert.Contains(t, rw.Header().Get("Location"), "error=unsupported_response_type")
+}
+
+func TestOpenIDConnectAuthorizationGET_ShouldRejectScopesNotAllowed(t *testing.T) {
+	testCases := []struct {
+		name  string
+		scope string
+	}{
+		{"ShouldRejectUnpermittedScope", "openid%20email"},
+		{"ShouldRejectUnknownScope", "openid%20admin"},
+		{"ShouldRejectPrefixedScope", "openid%20profile.admin"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t, mock, []string{"authorization_code", "refresh_token"}, []string{"code"}, schema.OpenIDConnectClientConfiguration{
+				ID:            "test",
+				Secret:        "secret",
+				Policy:        "one_factor",
+				RedirectURIs:  []string{"https://example.com/callback"},
+				Scopes:        []string{"openid", "profile"},
+				GrantTypes:    []string{"authorization_code"},
+				ResponseTypes: []string{"code"},
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "https://auth.example.com/api/oidc/authorization?client_id=test&response_type=code&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&scope="+tc.scope+"&state=abcdefghijklmnop", nil)
+
+			rw := httptest.NewRecorder()
+
+			OpenIDConnectAuthorizationGET(mock.Ctx, rw, req)
+
+			assert.Equal(t, http.StatusSeeOther, rw.Code)
+			assert.Contains(t, rw.Header().Get("Location"), "error=invalid_scope")
+		})
+	}
+}
+
+func TestOpenIDConnectAuthorizationGET_ShouldRejectMalformedClaimsParameter(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t, mock, []string{"authorization_code", "refresh_token"}, []string{"code"}, schema.OpenIDConnectClientConfiguration{
+		ID:            "test",
+		Secret:        "secret",
+		Policy:        "two_factor",
+		RedirectURIs:  []string{"https://example.com/callback"},
+		Scopes:        []string{"openid", "email"},
+		GrantTypes:    []string{"authorization_code"},
+		ResponseTypes: []string{"code"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "https://auth.example.com/api/oidc/authorization?client_id=test&response_type=code&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&scope=openid&state=abcdefghijklmnop&claims=%7B%22id_token%22%3A%5B%5D%7D", nil)
+
+	rw := httptest.NewRecorder()
+
+	OpenIDConnectAuthorizationGET(mock.Ctx, rw, req)
+
+	assert.Equal(t, http.StatusSeeOther, rw.Code)
+	assert.Contains(t, rw.Header().Get("Location"), "error=invalid_request")
+	assert.Regexp(t, "the claims parameter is not a valid JSON object", mock.Hook.LastEntry().Message)
+}
+
+func TestOpenIDConnectAuthorizationGET_ShouldDenyUserNotInAllowedGroups(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t, mock, []string{"authorization_code", "refresh_token"}, []string{"code"}, schema.OpenIDConnectClientConfiguration{
+		ID:            "test",
+		Secret:        "secret",
+		Policy:        "one_factor",
+		RedirectURIs:  []string{"https://example.com/callback"},
+		Scopes:        []string{"openid"},
+		GrantTypes:    []string{"authorization_code"},
+		ResponseTypes: []string{"code"},
+		AllowedGroups: []string{"admins"},
+	})
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = "john"
+	userSession.Groups = []string{"dev"}
+	userSession.AuthenticationLevel = authentication.OneFactor
+
+	assert.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+
+	req := httptest.NewRequest(http.MethodGet, "https://auth.example.com/api/oidc/authorization?client_id=test&response_type=code&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&scope=openid&state=abcdefghijklmnop", nil)
+
+	rw := httptest.NewRecorder()
+
+	OpenIDConnectAuthorizationGET(mock.Ctx, rw, req)
+
+	assert.Equal(t, http.StatusSeeOther, rw.Code)
+
+	location := rw.Header().Get("Location")
+
+	assert.Contains(t, location, "error=access_denied")
+	assert.NotContains(t, location, "admins")
+	assert.NotContains(t, location, "dev")
+	assert.Regexp(t, "user 'john' is not a member of any of the groups allowed to use this client", mock.Hook.LastEntry().Message)
+}
+
+func TestOpenIDConnectAuthorizationGET_ShouldWriteFormPostResponse(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t, mock, []string{"authorization_code", "refresh_token"}, []string{"code"}, schema.OpenIDConnectClientConfiguration{
+		ID:            "test",
+		Secret:        "secret",
+		Policy:        "one_factor",
+		RedirectURIs:  []string{"https://example.com/callback"},
+		Scopes:        []string{"openid"},
+		GrantTypes:    []string{"authorization_code"},
+		ResponseTypes: []string{"code"},
+		ResponseModes: []string{"form_post"},
+		AllowedGroups: []string{"admins"},
+	})
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = "john"
+	userSession.Groups = []string{"dev"}
+	userSession.AuthenticationLevel = authentication.OneFactor
+
+	assert.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+
+	req := httptest.NewRequest(http.MethodGet, "https://auth.example.com/api/oidc/authorization?client_id=test&response_type=code&response_mode=form_post&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&scope=openid&state=abcdefghijklmnop", nil)
+
+	rw := httptest.NewRecorder()
+
+	OpenIDConnectAuthorizationGET(mock.Ctx, rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "text/html;charset=UTF-8", rw.Header().Get("Content-Type"))
+	assert.Equal(t, oidc.FormPostContentSecurityPolicy, rw.Header().Get("Content-Security-Policy"))
+	assert.Empty(t, rw.Header().Get("Location"))
+
+	body := rw.Body.String()
+
+	assert.Contains(t, body, `<form method="post" action="https://example.com/callback">`)
+	assert.Contains(t, body, `<input type="hidden" name="error" value="access_denied"/>`)
+	assert.Contains(t, body, `<input type="hidden" name="state" value="abcdefghijklmnop"/>`)
+	assert.NotContains(t, body, "onload")
+}
+
+func TestOpenIDConnectAuthorizationGET_ShouldRejectResponseModeNotAllowedForClient(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t, mock, []string{"authorization_code", "refresh_token"}, []string{"code"}, schema.OpenIDConnectClientConfiguration{
+		ID:            "test",
+		Secret:        "secret",
+		Policy:        "one_factor",
+		RedirectURIs:  []string{"https://example.com/callback"},
+		Scopes:        []string{"openid"},
+		GrantTypes:    []string{"authorization_code"},
+		ResponseTypes: []string{"code"},
+		ResponseModes: []string{"query"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "https://auth.example.com/api/oidc/authorization?client_id=test&response_type=code&response_mode=form_post&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&scope=openid&state=abcdefghijklmnop", nil)
+
+	rw := httptest.NewRecorder()
+
+	OpenIDConnectAuthorizationGET(mock.Ctx, rw, req)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Contains(t, rw.Body.String(), `<input type="hidden" name="error" value="unsupported_response_mode"/>`)
+}
+
+func TestOpenIDConnectAuthorizationGET_HybridFlow(t *testing.T) {
+	testCases := []struct {
+		name          string
+		responseType  string
+		nonce         string
+		expectedError string
+		expected      []string
+	}{
+		{"ShouldIssueCodeAndIDToken", "code id_token", "abcdefghijklmnop", "", []string{"code", "id_token"}},
+		{"ShouldIssueCodeAndAccessToken", "code token", "", "", []string{"code", "access_token"}},
+		{"ShouldIssueCodeAccessTokenAndIDToken", "code id_token token", "abcdefghijklmnop", "", []string{"code", "access_token", "id_token"}},
+		{"ShouldRejectIDTokenWithoutNonce", "code id_token", "", "invalid_request", nil},
+		{"ShouldRejectResponseTypeNotPermittedForClient", "id_token token", "abcdefghijklmnop", "unsupported_response_type", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t, mock, []string{"authorization_code", "refresh_token", "implicit"}, nil, schema.OpenIDConnectClientConfiguration{
+				ID:            "test",
+				Secret:        "secret",
+				Policy:        "one_factor",
+				RedirectURIs:  []string{"https://example.com/callback"},
+				Scopes:        []string{"openid"},
+				GrantTypes:    []string{"authorization_code", "implicit"},
+				ResponseTypes: []string{"code id_token", "code token", "code id_token token"},
+			})
+
+			challenge, subject, now := uuid.New(), uuid.New(), time.Now()
+
+			userSession := mock.Ctx.GetSession()
+			userSession.Username = "john"
+			userSession.AuthenticationLevel = authentication.OneFactor
+			userSession.FirstFactorAuthnTimestamp = now.Unix()
+			userSession.ConsentChallengeID = &challenge
+
+			require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+			mock.StorageMock.EXPECT().LoadUserOpaqueIdentifierBySignature(gomock.Any(), "openid", "", "john").AnyTimes().Return(&model.UserOpaqueIdentifier{Identifier: subject}, nil)
+			mock.StorageMock.EXPECT().LoadOAuth2ConsentSessionByChallengeID(gomock.Any(), challenge).AnyTimes().Return(&model.OAuth2ConsentSession{ID: 1, ChallengeID: challenge, Subject: subject, Authorized: true, RespondedAt: &now, GrantedScopes: []string{"openid"}}, nil)
+			mock.StorageMock.EXPECT().SaveOAuth2Session(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+			mock.StorageMock.EXPECT().SaveOAuth2ConsentSessionGranted(gomock.Any(), 1).AnyTimes().Return(nil)
+
+			mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+			mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+
+			query := url.Values{
+				"client_id":     []string{"test"},
+				"response_type": []string{tc.responseType},
+				"redirect_uri":  []string{"https://example.com/callback"},
+				"scope":         []string{"openid"},
+				"state":         []string{"abcdefghijklmnop"},
+			}
+
+			if tc.nonce != "" {
+				query.Set("nonce", tc.nonce)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "https://auth.example.com/api/oidc/authorization?"+query.Encode(), nil)
+
+			rw := httptest.NewRecorder()
+
+			OpenIDConnectAuthorizationGET(mock.Ctx, rw, req)
+
+			require.Equal(t, http.StatusSeeOther, rw.Code)
+
+			location, err := url.Parse(rw.Header().Get("Location"))
+			require.NoError(t, err)
+
+			fragment, err := url.ParseQuery(location.Fragment)
+			require.NoError(t, err)
+
+			if tc.expectedError != "" {
+				// Errors which occur before the response mode can be trusted are returned in the query instead.
+				if fragment.Get("error") == "" {
+					fragment = location.Query()
+				}
+
+				assert.Equal(t, tc.expectedError, fragment.Get("error"))
+
+				return
+			}
+
+			assert.Equal(t, "", fragment.Get("error"))
+			assert.Equal(t, "abcdefghijklmnop", fragment.Get("state"))
+
+			for _, parameter := range tc.expected {
+				assert.NotEmpty(t, fragment.Get(parameter), parameter)
+			}
+
+			if fragment.Get("id_token") == "" {
+				return
+			}
+
+			claims := decodeTestJWTClaims(t, fragment.Get("id_token"))
+
+			assert.Equal(t, tc.nonce, claims["nonce"])
+			assert.Equal(t, testHalfHash(fragment.Get("code")), claims["c_hash"])
+
+			if fragment.Get("access_token") != "" {
+				assert.Equal(t, testHalfHash(fragment.Get("access_token")), claims["at_hash"])
+			} else {
+				assert.NotContains(t, claims, "at_hash")
+			}
+		})
+	}
+}
+
+func decodeTestJWTClaims(t *testing.T, token string) (claims map[string]interface{}) {
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+
+	require.NoError(t, json.Unmarshal(payload, &claims))
+
+	return claims
+}
+
+// testHalfHash returns the left-most half of the SHA-256 hash of the value as used by the at_hash and c_hash claims.
+func testHalfHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+}
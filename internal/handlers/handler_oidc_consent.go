@@ -26,7 +26,19 @@ func OpenIDConnectConsentGET(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	if err := ctx.SetJSONBody(client.GetConsentResponseBody(consent)); err != nil {
+	body := client.GetConsentResponseBody(consent)
+
+	for _, scope := range body.Scopes {
+		if customScope, ok := ctx.Providers.OpenIDConnect.CustomScopes[scope]; ok {
+			if body.ScopeDescriptions == nil {
+				body.ScopeDescriptions = map[string]string{}
+			}
+
+			body.ScopeDescriptions[scope] = customScope.Description
+		}
+	}
+
+	if err := ctx.SetJSONBody(body); err != nil {
 		ctx.Error(fmt.Errorf("unable to set JSON body: %v", err), "Operation failed")
 	}
 }
@@ -140,7 +152,7 @@ func oidcConsentGetSessionsAndClient(ctx *middlewares.AutheliaCtx) (userSession
 		return userSession, nil, nil, true
 	}
 
-	if client, err = ctx.Providers.OpenIDConnect.Store.GetFullClient(consent.ClientID); err != nil {
+	if client, err = oidcGetConsentClient(ctx, consent); err != nil {
 		ctx.Logger.Errorf("Unable to find related client configuration with name '%s': %v", consent.ClientID, err)
 		ctx.ReplyForbidden()
 
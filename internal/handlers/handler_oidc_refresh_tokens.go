@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// OpenIDConnectRefreshTokensGET returns the active OpenID Connect refresh tokens of the user identified by the session,
+// or of the user identified by the username query argument if the session belongs to an administrator.
+func OpenIDConnectRefreshTokensGET(ctx *middlewares.AutheliaCtx) {
+	username, ok := getOpenIDConnectRefreshTokensUsername(ctx, string(ctx.QueryArgs().Peek("username")))
+	if !ok {
+		ctx.ReplyForbidden()
+		return
+	}
+
+	tokens, err := loadOpenIDConnectRefreshTokens(ctx, username)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to load the refresh tokens of user '%s': %w", username, err), messageOperationFailed)
+		return
+	}
+
+	response := make([]oidcRefreshTokenResponse, len(tokens))
+
+	for i, token := range tokens {
+		response[i] = oidcRefreshTokenResponse{
+			ID:         token.RequestID,
+			ClientID:   token.ClientID,
+			Scopes:     token.GrantedScopes,
+			IssuedAt:   token.RequestedAt,
+			LastUsedAt: token.LastUsedAt,
+			ExpiresAt:  getOpenIDConnectRefreshTokenExpiration(ctx, token),
+		}
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.Errorf("Unable to set refresh tokens response in body: %s", err)
+	}
+}
+
+// OpenIDConnectRefreshTokensRevokePOST revokes an active OpenID Connect refresh token and the access tokens issued
+// with it. The refresh token is rejected by the token endpoint immediately after it has been revoked.
+func OpenIDConnectRefreshTokensRevokePOST(ctx *middlewares.AutheliaCtx) {
+	var bodyJSON oidcRefreshTokenRevokeRequestBody
+
+	if err := ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	username, ok := getOpenIDConnectRefreshTokensUsername(ctx, bodyJSON.Username)
+	if !ok {
+		ctx.ReplyForbidden()
+		return
+	}
+
+	tokens, err := loadOpenIDConnectRefreshTokens(ctx, username)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to load the refresh tokens of user '%s': %w", username, err), messageOperationFailed)
+		return
+	}
+
+	found := false
+
+	for _, token := range tokens {
+		if token.RequestID == bodyJSON.ID {
+			found = true
+
+			break
+		}
+	}
+
+	if !found {
+		ctx.Error(fmt.Errorf("unable to revoke refresh token '%s' of user '%s': refresh token not found", bodyJSON.ID, username), messageOperationFailed)
+		return
+	}
+
+	if err = revokeOpenIDConnectRefreshToken(ctx, bodyJSON.ID); err != nil {
+		ctx.Error(fmt.Errorf("unable to revoke refresh token '%s' of user '%s': %w", bodyJSON.ID, username, err), messageOperationFailed)
+		return
+	}
+
+	ctx.Logger.Infof("Refresh token '%s' of user '%s' has been revoked by '%s'", bodyJSON.ID, username, ctx.GetSession().Username)
+
+	ctx.ReplyOK()
+}
+
+// revokeOpenIDConnectRefreshTokens revokes all active OpenID Connect refresh tokens of a user.
+func revokeOpenIDConnectRefreshTokens(ctx *middlewares.AutheliaCtx, username string) (err error) {
+	tokens, err := loadOpenIDConnectRefreshTokens(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		if err = revokeOpenIDConnectRefreshToken(ctx, token.RequestID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func revokeOpenIDConnectRefreshToken(ctx *middlewares.AutheliaCtx, requestID string) (err error) {
+	if err = ctx.Providers.StorageProvider.RevokeOAuth2SessionByRequestID(ctx, storage.OAuth2SessionTypeRefreshToken, requestID); err != nil {
+		return err
+	}
+
+	return ctx.Providers.StorageProvider.RevokeOAuth2SessionByRequestID(ctx, storage.OAuth2SessionTypeAccessToken, requestID)
+}
+
+// loadOpenIDConnectRefreshTokens returns the active OpenID Connect refresh tokens of a user which haven't expired.
+func loadOpenIDConnectRefreshTokens(ctx *middlewares.AutheliaCtx, username string) (tokens []model.OAuth2RefreshToken, err error) {
+	all, err := ctx.Providers.StorageProvider.LoadOAuth2RefreshTokensActive(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	now := ctx.Clock.Now()
+
+	for _, token := range all {
+		if getOpenIDConnectRefreshTokenExpiration(ctx, token).Before(now) {
+			continue
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+// getOpenIDConnectRefreshTokenExpiration returns the time a refresh token expires. Refresh tokens are rotated each time
+// they're used so the lifespan starts at the last use.
+func getOpenIDConnectRefreshTokenExpiration(ctx *middlewares.AutheliaCtx, token model.OAuth2RefreshToken) time.Time {
+	issued := token.RequestedAt
+
+	if token.LastUsedAt != nil {
+		issued = *token.LastUsedAt
+	}
+
+	return issued.Add(ctx.Configuration.IdentityProviders.OIDC.RefreshTokenLifespan)
+}
+
+// getOpenIDConnectRefreshTokensUsername returns the username of the user whose refresh tokens are requested. Only
+// administrators are permitted to request the refresh tokens of other users.
+func getOpenIDConnectRefreshTokensUsername(ctx *middlewares.AutheliaCtx, username string) (string, bool) {
+	userSession := ctx.GetSession()
+
+	if username == "" || username == userSession.Username {
+		return userSession.Username, true
+	}
+
+	return username, isUserAdministrator(ctx)
+}
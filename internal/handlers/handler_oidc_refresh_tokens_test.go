@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+type OpenIDConnectRefreshTokensSuite struct {
+	suite.Suite
+
+	mock   *mocks.MockAutheliaCtx
+	now    time.Time
+	tokens []model.OAuth2RefreshToken
+}
+
+func (s *OpenIDConnectRefreshTokensSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.now = time.Unix(1700000000, 0)
+	s.mock.Clock.Set(s.now)
+	s.mock.Ctx.Clock = &s.mock.Clock
+
+	s.mock.Ctx.Configuration.IdentityProviders.OIDC = &schema.OpenIDConnectConfiguration{
+		RefreshTokenLifespan: time.Hour,
+	}
+	s.mock.Ctx.Configuration.AuthenticationBackend.AdminGroup = "admins"
+
+	lastUsed := s.now.Add(-time.Minute * 30)
+
+	s.tokens = []model.OAuth2RefreshToken{
+		{ID: 1, RequestID: "expired", ClientID: "app", RequestedAt: s.now.Add(-time.Hour * 2), Active: true},
+		{ID: 2, RequestID: "rotated", ClientID: "app", GrantedScopes: []string{"openid", "offline_access"}, RequestedAt: s.now.Add(-time.Hour * 3), LastUsedAt: &lastUsed, Active: true},
+		{ID: 3, RequestID: "issued", ClientID: "other", RequestedAt: s.now.Add(-time.Minute), Active: true},
+	}
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.TwoFactor
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *OpenIDConnectRefreshTokensSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *OpenIDConnectRefreshTokensSuite) TestShouldListActiveRefreshTokens() {
+	s.mock.StorageMock.EXPECT().LoadOAuth2RefreshTokensActive(s.mock.Ctx, testUsername).Return(s.tokens, nil)
+
+	OpenIDConnectRefreshTokensGET(s.mock.Ctx)
+
+	s.Require().Equal(200, s.mock.Ctx.Response.StatusCode())
+
+	var response struct {
+		Status string                     `json:"status"`
+		Data   []oidcRefreshTokenResponse `json:"data"`
+	}
+
+	s.Require().NoError(json.Unmarshal(s.mock.Ctx.Response.Body(), &response))
+	s.Require().Len(response.Data, 2)
+
+	s.Assert().Equal("rotated", response.Data[0].ID)
+	s.Assert().Equal([]string{"openid", "offline_access"}, response.Data[0].Scopes)
+	s.Assert().Equal(s.now.Add(-time.Hour*3).Unix(), response.Data[0].IssuedAt.Unix())
+	s.Require().NotNil(response.Data[0].LastUsedAt)
+	s.Assert().Equal(s.now.Add(-time.Minute*30).Unix(), response.Data[0].LastUsedAt.Unix())
+	s.Assert().Equal(s.now.Add(time.Minute*30).Unix(), response.Data[0].ExpiresAt.Unix())
+
+	s.Assert().Equal("issued", response.Data[1].ID)
+	s.Assert().Nil(response.Data[1].LastUsedAt)
+	s.Assert().Equal(s.now.Add(time.Minute*59).Unix(), response.Data[1].ExpiresAt.Unix())
+}
+
+func (s *OpenIDConnectRefreshTokensSuite) TestShouldNotListRefreshTokensOfOtherUser() {
+	s.mock.Ctx.QueryArgs().Add("username", "harry")
+
+	OpenIDConnectRefreshTokensGET(s.mock.Ctx)
+
+	s.Assert().Equal(403, s.mock.Ctx.Response.StatusCode())
+}
+
+func (s *OpenIDConnectRefreshTokensSuite) TestShouldListRefreshTokensOfOtherUserAsAdministrator() {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Groups = []string{"admins"}
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.Ctx.QueryArgs().Add("username", "harry")
+
+	s.mock.StorageMock.EXPECT().LoadOAuth2RefreshTokensActive(s.mock.Ctx, "harry").Return(nil, nil)
+
+	OpenIDConnectRefreshTokensGET(s.mock.Ctx)
+
+	s.Assert().Equal(200, s.mock.Ctx.Response.StatusCode())
+	s.Assert().Equal(`{"status":"OK","data":[]}`, string(s.mock.Ctx.Response.Body()))
+}
+
+func (s *OpenIDConnectRefreshTokensSuite) TestShouldRevokeRefreshToken() {
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().LoadOAuth2RefreshTokensActive(s.mock.Ctx, testUsername).Return(s.tokens, nil),
+		s.mock.StorageMock.EXPECT().RevokeOAuth2SessionByRequestID(s.mock.Ctx, storage.OAuth2SessionTypeRefreshToken, "rotated").Return(nil),
+		s.mock.StorageMock.EXPECT().RevokeOAuth2SessionByRequestID(s.mock.Ctx, storage.OAuth2SessionTypeAccessToken, "rotated").Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"id":"rotated"}`)
+
+	OpenIDConnectRefreshTokensRevokePOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Assert().Equal("Refresh token 'rotated' of user 'john' has been revoked by 'john'", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *OpenIDConnectRefreshTokensSuite) TestShouldNotRevokeExpiredRefreshToken() {
+	s.mock.StorageMock.EXPECT().LoadOAuth2RefreshTokensActive(s.mock.Ctx, testUsername).Return(s.tokens, nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"id":"expired"}`)
+
+	OpenIDConnectRefreshTokensRevokePOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Assert().Equal("unable to revoke refresh token 'expired' of user 'john': refresh token not found", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *OpenIDConnectRefreshTokensSuite) TestShouldNotRevokeRefreshTokenOfOtherUser() {
+	s.mock.Ctx.Request.SetBodyString(`{"username":"harry","id":"rotated"}`)
+
+	OpenIDConnectRefreshTokensRevokePOST(s.mock.Ctx)
+
+	s.Assert().Equal(403, s.mock.Ctx.Response.StatusCode())
+}
+
+func (s *OpenIDConnectRefreshTokensSuite) TestShouldRevokeRefreshTokensOnLogout() {
+	s.mock.Ctx.Configuration.IdentityProviders.OIDC.RevokeRefreshTokensOnLogout = true
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().LoadOAuth2RefreshTokensActive(s.mock.Ctx, testUsername).Return(s.tokens, nil),
+		s.mock.StorageMock.EXPECT().RevokeOAuth2SessionByRequestID(s.mock.Ctx, storage.OAuth2SessionTypeRefreshToken, "rotated").Return(nil),
+		s.mock.StorageMock.EXPECT().RevokeOAuth2SessionByRequestID(s.mock.Ctx, storage.OAuth2SessionTypeAccessToken, "rotated").Return(nil),
+		s.mock.StorageMock.EXPECT().RevokeOAuth2SessionByRequestID(s.mock.Ctx, storage.OAuth2SessionTypeRefreshToken, "issued").Return(nil),
+		s.mock.StorageMock.EXPECT().RevokeOAuth2SessionByRequestID(s.mock.Ctx, storage.OAuth2SessionTypeAccessToken, "issued").Return(nil),
+	)
+
+	LogoutPOST(s.mock.Ctx)
+
+	s.Assert().Equal(200, s.mock.Ctx.Response.StatusCode())
+}
+
+func (s *OpenIDConnectRefreshTokensSuite) TestShouldNotRevokeRefreshTokensOnLogoutByDefault() {
+	LogoutPOST(s.mock.Ctx)
+
+	s.Assert().Equal(200, s.mock.Ctx.Response.StatusCode())
+}
+
+func TestRunOpenIDConnectRefreshTokensSuite(t *testing.T) {
+	suite.Run(t, new(OpenIDConnectRefreshTokensSuite))
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"net/url"
 
 	"github.com/ory/fosite"
 
@@ -19,6 +20,15 @@ func OpenIDConnectTokenPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter
 		err       error
 	)
 
+	// Reject grant types which are not globally allowed before the request is processed regardless of the client.
+	if grantType := req.PostFormValue("grant_type"); grantType != "" && !ctx.Providers.OpenIDConnect.IsGrantTypeAllowed(grantType) {
+		ctx.Logger.Errorf("Access Request failed with error: the grant type '%s' is not allowed", grantType)
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAccessError(rw, requester, fosite.ErrUnsupportedGrantType.WithHintf("The grant type '%s' is not allowed by this server.", grantType))
+
+		return
+	}
+
 	oidcSession := oidc.NewSession()
 
 	if requester, err = ctx.Providers.OpenIDConnect.Fosite.NewAccessRequest(ctx, req, oidcSession); err != nil {
@@ -44,6 +54,16 @@ func OpenIDConnectTokenPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter
 		}
 	}
 
+	if err = oidcGrantAudience(ctx, requester); err != nil {
+		rfc := fosite.ErrorToRFC6749Error(err)
+
+		ctx.Logger.Errorf("Access Request with id '%s' on client with id '%s' failed with error: %s", requester.GetID(), client.GetID(), rfc.GetDescription())
+
+		ctx.Providers.OpenIDConnect.Fosite.WriteAccessError(rw, requester, err)
+
+		return
+	}
+
 	if responder, err = ctx.Providers.OpenIDConnect.Fosite.NewAccessResponse(ctx, requester); err != nil {
 		rfc := fosite.ErrorToRFC6749Error(err)
 
@@ -60,3 +80,41 @@ func OpenIDConnectTokenPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter
 
 	ctx.Providers.OpenIDConnect.Fosite.WriteAccessResponse(rw, requester, responder)
 }
+
+// oidcGrantAudience grants the audiences of the RFC8707 resource indicators of the request if any were requested,
+// otherwise it grants the audiences mapped to the granted scopes by the client configuration. Requested resources must
+// be absolute URIs without a fragment and must be one of the audiences of the client.
+func oidcGrantAudience(ctx *middlewares.AutheliaCtx, requester fosite.AccessRequester) (err error) {
+	var client *oidc.Client
+
+	if client, err = ctx.Providers.OpenIDConnect.Store.GetFullClient(requester.GetClient().GetID()); err != nil {
+		return fosite.ErrInvalidClient.WithWrap(err).WithDebug(err.Error())
+	}
+
+	resources := requester.GetRequestForm()["resource"]
+
+	if len(resources) == 0 {
+		for _, audience := range client.GetScopeAudiences(requester.GetGrantedScopes()) {
+			requester.GrantAudience(audience)
+		}
+
+		return nil
+	}
+
+	for _, resource := range resources {
+		uri, err := url.Parse(resource)
+		if err != nil || !uri.IsAbs() || uri.Fragment != "" {
+			return oidc.ErrInvalidTarget.WithHintf("The resource '%s' must be an absolute URI without a fragment.", resource)
+		}
+
+		if !client.IsAudienceAllowed(resource) {
+			return oidc.ErrInvalidTarget.WithHintf("The resource '%s' is not permitted for this client.", resource)
+		}
+	}
+
+	for _, resource := range resources {
+		requester.GrantAudience(resource)
+	}
+
+	return nil
+}
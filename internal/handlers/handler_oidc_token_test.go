@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"database/sql"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func newTestOpenIDConnectProvider(t *testing.T, mock *mocks.MockAutheliaCtx, grantTypes, responseTypes []string, client schema.OpenIDConnectClientConfiguration) oidc.OpenIDConnectProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	provider, err := oidc.NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey:     string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		HMACSecret:           "asbdhaaskmdlkamdklasmdlkamsasbdhaaskmdlkamdklasmdlkams",
+		AccessTokenLifespan:  schema.DefaultOpenIDConnectConfiguration.AccessTokenLifespan,
+		AllowedGrantTypes:    grantTypes,
+		AllowedResponseTypes: responseTypes,
+		Clients:              []schema.OpenIDConnectClientConfiguration{client},
+	}, mock.StorageMock)
+	require.NoError(t, err)
+
+	return provider
+}
+
+func TestOpenIDConnectTokenPOST_ShouldRejectGrantTypesNotAllowed(t *testing.T) {
+	testCases := []struct {
+		name          string
+		allowed       []string
+		expectedCode  int
+		expectedError string
+	}{
+		{"ShouldRejectDisallowedGrantType", []string{"authorization_code", "refresh_token"}, http.StatusBadRequest, "unsupported_grant_type"},
+		{"ShouldProcessAllowedGrantType", []string{"authorization_code", "refresh_token", "client_credentials"}, http.StatusUnauthorized, "invalid_client"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t, mock, tc.allowed, []string{"code"}, schema.OpenIDConnectClientConfiguration{
+				ID:            "test",
+				Secret:        "secret",
+				Policy:        "two_factor",
+				RedirectURIs:  []string{"https://example.com/callback"},
+				Scopes:        []string{"openid"},
+				GrantTypes:    []string{"authorization_code", "refresh_token", "client_credentials"},
+				ResponseTypes: []string{"code"},
+			})
+
+			form := url.Values{}
+			form.Set("grant_type", "client_credentials")
+
+			req := httptest.NewRequest(http.MethodPost, "https://auth.example.com/api/oidc/token", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetBasicAuth("test", "bad-secret")
+
+			rw := httptest.NewRecorder()
+
+			OpenIDConnectTokenPOST(mock.Ctx, rw, req)
+
+			assert.Equal(t, tc.expectedCode, rw.Code)
+			assert.Contains(t, rw.Body.String(), tc.expectedError)
+		})
+	}
+}
+
+func TestOpenIDConnectTokenPOST_ShouldGrantAudience(t *testing.T) {
+	testCases := []struct {
+		name          string
+		resources     []string
+		expectedCode  int
+		expectedError string
+		expected      []string
+	}{
+		{"ShouldGrantScopeAudiences", nil, http.StatusOK, "", []string{"https://api.example.com"}},
+		{"ShouldGrantRequestedResources", []string{"https://other.example.com"}, http.StatusOK, "", []string{"https://other.example.com"}},
+		{"ShouldRejectResourceNotPermitted", []string{"https://api.example.com", "https://bad.example.com"}, http.StatusBadRequest, "The resource 'https://bad.example.com' is not permitted for this client.", nil},
+		{"ShouldRejectRelativeResource", []string{"/api"}, http.StatusBadRequest, "The resource '/api' must be an absolute URI without a fragment.", nil},
+		{"ShouldRejectResourceWithFragment", []string{"https://api.example.com#fragment"}, http.StatusBadRequest, "The resource 'https://api.example.com#fragment' must be an absolute URI without a fragment.", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t, mock, []string{"client_credentials"}, []string{"code"}, schema.OpenIDConnectClientConfiguration{
+				ID:            "test",
+				Secret:        "secret",
+				Policy:        "two_factor",
+				RedirectURIs:  []string{"https://example.com/callback"},
+				Scopes:        []string{"openid", "groups"},
+				Audience:      []string{"https://api.example.com", "https://other.example.com"},
+				GrantTypes:    []string{"client_credentials"},
+				ResponseTypes: []string{"code"},
+				ScopeAudiences: []schema.OpenIDConnectClientScopeAudience{
+					{Scope: "groups", Audience: []string{"https://api.example.com"}},
+				},
+			})
+
+			var saved model.OAuth2Session
+
+			if tc.expectedCode == http.StatusOK {
+				mock.StorageMock.EXPECT().
+					SaveOAuth2Session(gomock.Any(), storage.OAuth2SessionTypeAccessToken, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ storage.OAuth2SessionType, session model.OAuth2Session) error {
+						saved = session
+
+						return nil
+					})
+			}
+
+			form := url.Values{}
+			form.Set("grant_type", "client_credentials")
+			form.Set("scope", "groups")
+
+			for _, resource := range tc.resources {
+				form.Add("resource", resource)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "https://auth.example.com/api/oidc/token", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetBasicAuth("test", "secret")
+
+			rw := httptest.NewRecorder()
+
+			OpenIDConnectTokenPOST(mock.Ctx, rw, req)
+
+			assert.Equal(t, tc.expectedCode, rw.Code)
+
+			if tc.expectedError != "" {
+				assert.Contains(t, rw.Body.String(), "invalid_target")
+				assert.Contains(t, rw.Body.String(), tc.expectedError)
+
+				return
+			}
+
+			assert.Equal(t, tc.expected, []string(saved.GrantedAudience))
+		})
+	}
+}
+
+func TestOpenIDConnectTokenPOST_ShouldRejectRevokedRefreshToken(t *testing.T) {
+	testCases := []struct {
+		name          string
+		err           error
+		expectedCode  int
+		expectedError string
+	}{
+		{"ShouldRejectRevokedRefreshToken", sql.ErrNoRows, http.StatusBadRequest, "invalid_grant"},
+		{"ShouldRejectInactiveRefreshToken", nil, http.StatusUnauthorized, "token_inactive"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Providers.OpenIDConnect = newTestOpenIDConnectProvider(t, mock, nil, nil, schema.OpenIDConnectClientConfiguration{
+				ID:            "test",
+				Secret:        "secret",
+				Policy:        "two_factor",
+				RedirectURIs:  []string{"https://example.com/callback"},
+				Scopes:        []string{"openid", "offline_access"},
+				GrantTypes:    []string{"authorization_code", "refresh_token"},
+				ResponseTypes: []string{"code"},
+			})
+
+			client, err := mock.Ctx.Providers.OpenIDConnect.Store.GetClient(context.Background(), "test")
+			require.NoError(t, err)
+
+			request := fosite.NewRequest()
+			request.ID = "request-id"
+			request.Client = client
+			request.Session = oidc.NewSession()
+			request.GrantScope("openid")
+			request.GrantScope("offline_access")
+
+			session, err := model.NewOAuth2SessionFromRequest("signature", request)
+			require.NoError(t, err)
+
+			session.Active = false
+
+			if tc.err != nil {
+				mock.StorageMock.EXPECT().
+					LoadOAuth2Session(gomock.Any(), storage.OAuth2SessionTypeRefreshToken, "signature").
+					Return(nil, tc.err)
+			} else {
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().
+						LoadOAuth2Session(gomock.Any(), storage.OAuth2SessionTypeRefreshToken, "signature").
+						Return(session, nil),
+					mock.StorageMock.EXPECT().BeginTX(gomock.Any()).DoAndReturn(func(ctx context.Context) (context.Context, error) { return ctx, nil }),
+					mock.StorageMock.EXPECT().RevokeOAuth2Session(gomock.Any(), storage.OAuth2SessionTypeRefreshToken, "signature").Return(nil),
+					mock.StorageMock.EXPECT().DeactivateOAuth2SessionByRequestID(gomock.Any(), storage.OAuth2SessionTypeRefreshToken, "request-id").Return(nil),
+					mock.StorageMock.EXPECT().RevokeOAuth2SessionByRequestID(gomock.Any(), storage.OAuth2SessionTypeAccessToken, "request-id").Return(nil),
+					mock.StorageMock.EXPECT().Commit(gomock.Any()).Return(nil),
+				)
+			}
+
+			form := url.Values{}
+			form.Set("grant_type", "refresh_token")
+			form.Set("refresh_token", "key.signature")
+
+			req := httptest.NewRequest(http.MethodPost, "https://auth.example.com/api/oidc/token", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetBasicAuth("test", "secret")
+
+			rw := httptest.NewRecorder()
+
+			OpenIDConnectTokenPOST(mock.Ctx, rw, req)
+
+			assert.Equal(t, tc.expectedCode, rw.Code)
+			assert.Contains(t, rw.Body.String(), tc.expectedError)
+		})
+	}
+}
@@ -28,7 +28,7 @@ func DuoDevicesGET(duoAPI duo.API) middlewares.RequestHandler {
 
 		if result == auth {
 			if devices == nil {
-				ctx.Logger.Debugf("No applicable device/method available for Duo user %s", userSession.Username)
+				ctx.Logger.Debugf("No applicable device/method available for Duo user '%s'", userSession.Username)
 
 				if err := ctx.SetJSONBody(DuoDevicesResponse{Result: enroll}); err != nil {
 					ctx.Error(fmt.Errorf("unable to set JSON body in response"), messageMFAValidationFailed)
@@ -45,7 +45,7 @@ func DuoDevicesGET(duoAPI duo.API) middlewares.RequestHandler {
 		}
 
 		if result == allow {
-			ctx.Logger.Debugf("Device selection not possible for user %s, because Duo authentication was bypassed - Defaults to Auto Push", userSession.Username)
+			ctx.Logger.Debugf("Device selection not possible for user '%s', because Duo authentication was bypassed - Defaults to Auto Push", userSession.Username)
 
 			if err := ctx.SetJSONBody(DuoDevicesResponse{Result: allow}); err != nil {
 				ctx.Error(fmt.Errorf("unable to set JSON body in response"), messageMFAValidationFailed)
@@ -94,7 +94,7 @@ func DuoDevicePOST(ctx *middlewares.AutheliaCtx) {
 	}
 
 	userSession := ctx.GetSession()
-	ctx.Logger.Debugf("Save new preferred Duo device and method of user %s to %s using %s", userSession.Username, device.Device, device.Method)
+	ctx.Logger.Debugf("Save new preferred Duo device and method of user '%s' to %s using %s", userSession.Username, device.Device, device.Method)
 	err = ctx.Providers.StorageProvider.SavePreferredDuoDevice(ctx, model.DuoDevice{Username: userSession.Username, Device: device.Device, Method: device.Method})
 
 	if err != nil {
@@ -108,7 +108,7 @@ func DuoDevicePOST(ctx *middlewares.AutheliaCtx) {
 // SecondFactorDuoDeviceDelete deletes the useres preferred Duo device and method.
 func SecondFactorDuoDeviceDelete(ctx *middlewares.AutheliaCtx) {
 	userSession := ctx.GetSession()
-	ctx.Logger.Debugf("Deleting preferred Duo device and method of user %s", userSession.Username)
+	ctx.Logger.Debugf("Deleting preferred Duo device and method of user '%s'", userSession.Username)
 	err := ctx.Providers.StorageProvider.DeletePreferredDuoDevice(ctx, userSession.Username)
 
 	if err != nil {
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
@@ -13,7 +16,7 @@ func identityRetrieverFromSession(ctx *middlewares.AutheliaCtx) (*session.Identi
 	userSession := ctx.GetSession()
 
 	if len(userSession.Emails) == 0 {
-		return nil, fmt.Errorf("user %s does not have any email address", userSession.Username)
+		return nil, fmt.Errorf("user '%s' does not have any email address", userSession.Username)
 	}
 
 	return &session.Identity{
@@ -37,14 +40,23 @@ var TOTPIdentityStart = middlewares.IdentityVerificationStart(middlewares.Identi
 
 func totpIdentityFinish(ctx *middlewares.AutheliaCtx, username string) {
 	var (
-		config *model.TOTPConfiguration
-		err    error
+		config      *model.TOTPConfiguration
+		description string
+		err         error
 	)
 
+	if description, err = totpRegistrationDescription(ctx); err != nil {
+		ctx.Error(err, messageUnableToRegisterOneTimePassword)
+		return
+	}
+
 	if config, err = ctx.Providers.TOTP.Generate(username); err != nil {
 		ctx.Error(fmt.Errorf("unable to generate TOTP key: %s", err), messageUnableToRegisterOneTimePassword)
+		return
 	}
 
+	config.Description = description
+
 	err = ctx.Providers.StorageProvider.SaveTOTPConfiguration(ctx, *config)
 	if err != nil {
 		ctx.Error(fmt.Errorf("unable to save TOTP secret in DB: %s", err), messageUnableToRegisterOneTimePassword)
@@ -52,7 +64,7 @@ func totpIdentityFinish(ctx *middlewares.AutheliaCtx, username string) {
 	}
 
 	response := TOTPKeyResponse{
-		OTPAuthURL:   config.URI(),
+		OTPAuthURL:   config.URIWithAccountName(totpAccountLabel(ctx, username)),
 		Base32Secret: string(config.Secret),
 	}
 
@@ -62,6 +74,55 @@ func totpIdentityFinish(ctx *middlewares.AutheliaCtx, username string) {
 	}
 }
 
+// totpRegistrationDescription returns the description of the TOTP registration from the request body. Registering with
+// the description of an existing registration replaces it.
+func totpRegistrationDescription(ctx *middlewares.AutheliaCtx) (description string, err error) {
+	var body totpRegistrationBody
+
+	if err = json.Unmarshal(ctx.PostBody(), &body); err != nil {
+		return "", fmt.Errorf("unable to parse TOTP registration body: %w", err)
+	}
+
+	description = strings.TrimSpace(body.Description)
+
+	switch {
+	case description == "":
+		return totpDescriptionDefault, nil
+	case utf8.RuneCountInString(description) > totpDescriptionMaxLength:
+		return "", fmt.Errorf("the TOTP registration description must not be longer than %d characters", totpDescriptionMaxLength)
+	default:
+		return description, nil
+	}
+}
+
+// totpAccountLabel renders the configured TOTP account label template using the attributes of the current user. The
+// username is used when the template is not configured or renders to an empty value.
+func totpAccountLabel(ctx *middlewares.AutheliaCtx, username string) (label string) {
+	if ctx.Configuration.TOTP.AccountLabel == "" {
+		return username
+	}
+
+	userSession := ctx.GetSession()
+
+	var email string
+
+	if len(userSession.Emails) != 0 {
+		email = userSession.Emails[0]
+	}
+
+	label = strings.NewReplacer(
+		"{username}", username,
+		"{email}", email,
+		"{displayname}", userSession.DisplayName,
+	).Replace(ctx.Configuration.TOTP.AccountLabel)
+
+	if strings.TrimSpace(label) == "" {
+		return username
+	}
+
+	return label
+}
+
 // TOTPIdentityFinish the handler for finishing the identity validation.
 var TOTPIdentityFinish = middlewares.IdentityVerificationFinish(
 	middlewares.IdentityVerificationFinishArgs{
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func TestShouldRenderTOTPAccountLabel(t *testing.T) {
+	testCases := []struct {
+		name, template, expected string
+		emails                   []string
+	}{
+		{"ShouldDefaultToUsername", "", "john", []string{"john@example.com"}},
+		{"ShouldRenderUsername", "{username}", "john", []string{"john@example.com"}},
+		{"ShouldRenderDisplayNameAndEmail", "{displayname} ({email})", "John Doe (john@example.com)", []string{"john@example.com"}},
+		{"ShouldFallbackToUsernameWhenEmpty", "{email}", "john", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Configuration.TOTP.AccountLabel = tc.template
+
+			userSession := mock.Ctx.GetSession()
+			userSession.Username = testUsername
+			userSession.DisplayName = "John Doe"
+			userSession.Emails = tc.emails
+
+			require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+			assert.Equal(t, tc.expected, totpAccountLabel(mock.Ctx, testUsername))
+		})
+	}
+}
+
+func TestShouldParseTOTPRegistrationDescription(t *testing.T) {
+	testCases := []struct {
+		name, body, expected, err string
+	}{
+		{"ShouldDefaultWhenAbsent", `{"token":"abc"}`, "Primary", ""},
+		{"ShouldDefaultWhenBlank", `{"token":"abc","description":"  "}`, "Primary", ""},
+		{"ShouldTrimDescription", `{"token":"abc","description":" Phone "}`, "Phone", ""},
+		{"ShouldErrorWhenTooLong", `{"token":"abc","description":"abcdefghijklmnopqrstuvwxyzabcde"}`, "", "the TOTP registration description must not be longer than 30 characters"},
+		{"ShouldErrorWhenInvalidJSON", `abc`, "", "unable to parse TOTP registration body: invalid character 'a' looking for beginning of value"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Request.SetBodyString(tc.body)
+
+			description, err := totpRegistrationDescription(mock.Ctx)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, description)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/events"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/notification"
+	"github.com/authelia/authelia/v4/internal/templates"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+var regexpRegistrationUsername = regexp.MustCompile(`^[a-zA-Z0-9_.@-]{1,100}$`)
+
+// RegistrationPOST handler for self-registering an account. The account is only stored as a pending registration and
+// can't be used to authenticate until an administrator approves it.
+func RegistrationPOST(ctx *middlewares.AutheliaCtx) {
+	var bodyJSON registrationRequestBody
+
+	if err := ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Error(err, messageUnableToRegisterAccount)
+		return
+	}
+
+	if !regexpRegistrationUsername.MatchString(bodyJSON.Username) {
+		ctx.Error(fmt.Errorf("username '%s' contains invalid characters", bodyJSON.Username), messageUnableToRegisterAccount)
+		return
+	}
+
+	if !utils.IsEmailDomainPermitted(bodyJSON.Email, ctx.Configuration.EmailDomains.Allowed, ctx.Configuration.EmailDomains.Denied) {
+		registrationEmailDomainRefused(ctx, bodyJSON.Username, bodyJSON.Email)
+		return
+	}
+
+	provider, ok := ctx.Providers.UserProvider.(authentication.UserRegistrationProvider)
+	if !ok {
+		ctx.Error(fmt.Errorf("the user provider does not support creating users"), messageUnableToRegisterAccount)
+		return
+	}
+
+	if err := ctx.Providers.PasswordPolicy.Check(bodyJSON.Password); err != nil {
+		ctx.Error(err, messagePasswordWeak)
+		return
+	}
+
+	if _, err := ctx.Providers.UserProvider.GetDetails(bodyJSON.Username); err == nil {
+		ctx.Error(fmt.Errorf("user '%s' already exists", bodyJSON.Username), messageUnableToRegisterAccount)
+		return
+	}
+
+	registration, err := ctx.Providers.StorageProvider.LoadUserRegistration(ctx, bodyJSON.Username)
+
+	switch {
+	case err != nil:
+		ctx.Error(err, messageUnableToRegisterAccount)
+		return
+	case registration != nil:
+		ctx.Error(fmt.Errorf("user '%s' already has a pending registration", bodyJSON.Username), messageUnableToRegisterAccount)
+		return
+	}
+
+	hash, err := provider.HashPassword(bodyJSON.Password)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to hash the password of user '%s': %w", bodyJSON.Username, err), messageUnableToRegisterAccount)
+		return
+	}
+
+	registration = &model.UserRegistration{
+		RequestedAt: ctx.Clock.Now(),
+		RemoteIP:    model.NewNullIP(ctx.RemoteIP()),
+		Username:    bodyJSON.Username,
+		DisplayName: bodyJSON.DisplayName,
+		Email:       bodyJSON.Email,
+		Password:    hash,
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveUserRegistration(ctx, *registration); err != nil {
+		ctx.Error(err, messageUnableToRegisterAccount)
+		return
+	}
+
+	if ctx.Configuration.SelfRegistration.EmailVerification.Enabled {
+		if err = sendRegistrationVerification(ctx, registration); err != nil {
+			// The registration is removed so the user can register again, as the email address can't be verified.
+			if errDelete := ctx.Providers.StorageProvider.DeleteUserRegistration(ctx, registration.Username); errDelete != nil {
+				ctx.Logger.Errorf("Unable to delete the registration of user '%s': %+v", registration.Username, errDelete)
+			}
+
+			ctx.Error(err, messageUnableToRegisterAccount)
+
+			return
+		}
+	}
+
+	ctx.Logger.Infof("User '%s' has registered an account which is pending approval", registration.Username)
+
+	sendRegistrationPendingNotifications(ctx, registration)
+
+	ctx.ReplyOK()
+}
+
+// RegistrationsPendingGET handler for listing the pending self-registrations.
+func RegistrationsPendingGET(ctx *middlewares.AutheliaCtx) {
+	if !isUserAdministrator(ctx) {
+		ctx.ReplyForbidden()
+		return
+	}
+
+	registrations, err := ctx.Providers.StorageProvider.LoadUserRegistrations(ctx)
+	if err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	response := make([]registrationResponse, len(registrations))
+
+	for i, registration := range registrations {
+		response[i] = registrationResponse{
+			Username:    registration.Username,
+			DisplayName: registration.DisplayName,
+			Email:       registration.Email,
+			RequestedAt: registration.RequestedAt,
+		}
+
+		if ctx.Configuration.SelfRegistration.EmailVerification.Enabled {
+			verified, err := isRegistrationEmailVerified(ctx, &registration)
+			if err != nil {
+				ctx.Error(err, messageOperationFailed)
+				return
+			}
+
+			response[i].EmailVerified = &verified
+		}
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.Errorf("Unable to set pending registrations response in body: %s", err)
+	}
+}
+
+// RegistrationApprovalPOST handler for approving or denying a pending self-registration.
+func RegistrationApprovalPOST(ctx *middlewares.AutheliaCtx) {
+	if !isUserAdministrator(ctx) {
+		ctx.ReplyForbidden()
+		return
+	}
+
+	var bodyJSON registrationApprovalRequestBody
+
+	if err := ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	registration, err := ctx.Providers.StorageProvider.LoadUserRegistration(ctx, bodyJSON.Username)
+
+	switch {
+	case err != nil:
+		ctx.Error(err, messageOperationFailed)
+		return
+	case registration == nil:
+		ctx.Error(fmt.Errorf("user '%s' does not have a pending registration", bodyJSON.Username), messageOperationFailed)
+		return
+	}
+
+	if bodyJSON.Approved && ctx.Configuration.SelfRegistration.EmailVerification.Enabled {
+		verified, err := isRegistrationEmailVerified(ctx, registration)
+
+		switch {
+		case err != nil:
+			ctx.Error(err, messageOperationFailed)
+			return
+		case !verified:
+			ctx.Error(fmt.Errorf("user '%s' has not verified their email address", registration.Username), messageOperationFailed)
+			return
+		}
+	}
+
+	if bodyJSON.Approved {
+		provider, ok := ctx.Providers.UserProvider.(authentication.UserRegistrationProvider)
+		if !ok {
+			ctx.Error(fmt.Errorf("the user provider does not support creating users"), messageOperationFailed)
+			return
+		}
+
+		if err = provider.AddUser(registration.Username, authentication.UserDetails{
+			DisplayName: registration.DisplayName,
+			Emails:      []string{registration.Email},
+			Groups:      ctx.Configuration.SelfRegistration.DefaultGroups,
+		}, registration.Password); err != nil {
+			ctx.Error(fmt.Errorf("unable to add user '%s': %w", registration.Username, err), messageOperationFailed)
+			return
+		}
+	}
+
+	if err = ctx.Providers.StorageProvider.DeleteUserRegistration(ctx, registration.Username); err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	// The verification of a denied registration is removed as the user doesn't exist. The verification of an approved
+	// registration is kept as it records that the email address of the user is verified.
+	if !bodyJSON.Approved && ctx.Configuration.SelfRegistration.EmailVerification.Enabled {
+		if err = ctx.Providers.StorageProvider.DeleteEmailVerification(ctx, registration.Username); err != nil {
+			ctx.Logger.Errorf("Unable to delete the email verification of user '%s': %+v", registration.Username, err)
+		}
+	}
+
+	userSession := ctx.GetSession()
+
+	if bodyJSON.Approved {
+		ctx.Logger.Infof("Registration of user '%s' has been approved by '%s'", registration.Username, userSession.Username)
+	} else {
+		ctx.Logger.Infof("Registration of user '%s' has been denied by '%s'", registration.Username, userSession.Username)
+	}
+
+	sendRegistrationDecisionNotification(ctx, registration, bodyJSON.Approved)
+
+	ctx.ReplyOK()
+}
+
+// RegistrationEmailVerificationGET handler for verifying the email address of a pending self-registration with the
+// single-use token sent to it. The user is redirected to the portal when the email address is verified.
+func RegistrationEmailVerificationGET(ctx *middlewares.AutheliaCtx) {
+	username, token := string(ctx.QueryArgs().Peek("username")), string(ctx.QueryArgs().Peek("token"))
+
+	if username == "" || token == "" {
+		ctx.Error(fmt.Errorf("no username or token was provided to verify the email address"), messageUnableToVerifyEmail)
+		return
+	}
+
+	verification, err := ctx.Providers.StorageProvider.LoadEmailVerification(ctx, username)
+
+	switch {
+	case err != nil:
+		ctx.Error(err, messageUnableToVerifyEmail)
+		return
+	case verification == nil || !verification.Matches(token, ctx.Clock.Now()):
+		ctx.Error(fmt.Errorf("the email verification token of user '%s' is invalid, has expired, or was already used", username), messageUnableToVerifyEmail)
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.ConsumeEmailVerification(ctx, username, ctx.Clock.Now()); err != nil {
+		ctx.Error(err, messageUnableToVerifyEmail)
+		return
+	}
+
+	ctx.Logger.Infof("User '%s' has verified the email address '%s'", username, verification.Email)
+
+	uri, err := ctx.ExternalRootURL()
+	if err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	ctx.Redirect(uri, fasthttp.StatusFound)
+}
+
+// registrationEmailDomainRefused logs and audits a registration which was refused because the domain of the email
+// address is not permitted.
+func registrationEmailDomainRefused(ctx *middlewares.AutheliaCtx, username, email string) {
+	ctx.Logger.Warnf("Registration of user '%s' was refused as the domain of the email address '%s' is not permitted", username, email)
+
+	successful := false
+
+	ctx.Providers.Events.Emit(events.Event{
+		Type:       events.TypeEmailDomainRefused,
+		Username:   username,
+		RemoteIP:   ctx.RemoteIP().String(),
+		Successful: &successful,
+		Details: map[string]string{
+			"email":  email,
+			"action": "registration",
+		},
+	})
+
+	ctx.SetJSONError(messageEmailDomainNotPermitted)
+}
+
+// isRegistrationEmailVerified returns true if the email address of the registration has been verified. Registrations
+// made before email verification was enabled don't have a verification and are considered verified.
+func isRegistrationEmailVerified(ctx *middlewares.AutheliaCtx, registration *model.UserRegistration) (verified bool, err error) {
+	verification, err := ctx.Providers.StorageProvider.LoadEmailVerification(ctx, registration.Username)
+	if err != nil {
+		return false, err
+	}
+
+	return verification == nil || !verification.Unverified(registration.Email), nil
+}
+
+func sendRegistrationPendingNotifications(ctx *middlewares.AutheliaCtx, registration *model.UserRegistration) {
+	if len(ctx.Configuration.SelfRegistration.AdminEmails) == 0 {
+		return
+	}
+
+	bufText := new(bytes.Buffer)
+
+	if err := templates.EmailRegistrationPendingPlainText.Execute(bufText, map[string]interface{}{
+		"Username":    registration.Username,
+		"DisplayName": registration.DisplayName,
+		"Email":       registration.Email,
+		"RemoteIP":    ctx.RemoteIP().String(),
+	}); err != nil {
+		ctx.Logger.Error(err)
+		return
+	}
+
+	for _, email := range ctx.Configuration.SelfRegistration.AdminEmails {
+		ctx.Logger.Debugf("Sending an email to administrator %s to inform that the registration of user '%s' is pending approval", email, registration.Username)
+
+		if err := notification.SendEvent(ctx.Providers.Notifier, notification.EventRegistration, email, "Account registration pending approval", bufText.String(), ""); err != nil {
+			ctx.Logger.Error(err)
+		}
+	}
+}
+
+func sendRegistrationDecisionNotification(ctx *middlewares.AutheliaCtx, registration *model.UserRegistration, approved bool) {
+	bufText := new(bytes.Buffer)
+
+	if err := templates.EmailRegistrationDecisionPlainText.Execute(bufText, map[string]interface{}{
+		"Username":    registration.Username,
+		"DisplayName": registration.DisplayName,
+		"Approved":    approved,
+	}); err != nil {
+		ctx.Logger.Error(err)
+		return
+	}
+
+	subject := "Account registration denied"
+	if approved {
+		subject = "Account registration approved"
+	}
+
+	ctx.Logger.Debugf("Sending an email to user '%s' (%s) to inform them of the registration decision", registration.Username, registration.Email)
+
+	if err := notification.SendEvent(ctx.Providers.Notifier, notification.EventRegistration, registration.Email, subject, bufText.String(), ""); err != nil {
+		ctx.Logger.Error(err)
+	}
+}
+
+func sendRegistrationVerification(ctx *middlewares.AutheliaCtx, registration *model.UserRegistration) (err error) {
+	token := utils.RandomString(emailVerificationTokenLength, utils.AlphaNumericCharacters, true)
+	verification := model.NewEmailVerification(registration.Username, registration.Email, token, ctx.Clock.Now(), ctx.Configuration.SelfRegistration.EmailVerification.Lifespan)
+
+	if err = ctx.Providers.StorageProvider.SaveEmailVerification(ctx, verification); err != nil {
+		return err
+	}
+
+	uri, err := ctx.ExternalRootURL()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("username", registration.Username)
+	query.Set("token", token)
+
+	bufText := new(bytes.Buffer)
+
+	if err = templates.EmailRegistrationVerificationPlainText.Execute(bufText, map[string]interface{}{
+		"Username":    registration.Username,
+		"DisplayName": registration.DisplayName,
+		"LinkURL":     fmt.Sprintf("%s/api/registration/email/verify?%s", uri, query.Encode()),
+		"ExpiresAt":   verification.ExpiresAt.Format(time.RFC1123),
+		"RemoteIP":    ctx.RemoteIP().String(),
+	}); err != nil {
+		return err
+	}
+
+	ctx.Logger.Debugf("Sending an email to user '%s' (%s) to verify the email address of their registration", registration.Username, registration.Email)
+
+	return notification.SendEvent(ctx.Providers.Notifier, notification.EventRegistration, registration.Email, "Verify your email address", bufText.String(), "")
+}
@@ -0,0 +1,501 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/events"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+type RegistrationSuite struct {
+	suite.Suite
+
+	mock     *mocks.MockAutheliaCtx
+	provider *authentication.FileUserProvider
+}
+
+func (s *RegistrationSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+
+	path := filepath.Join(s.T().TempDir(), "users.yml")
+	s.Require().NoError(os.WriteFile(path, []byte(`users:
+  john:
+    displayname: "John Doe"
+    password: "$argon2id$v=19$m=32768,t=1,p=8$eUhVT1dQa082YVk2VUhDMQ$E8QI4jHbUBt3EdsU1NFDu4Bq5jObKNx7nBKSn1EYQxk"
+    email: john.doe@example.com
+    groups:
+      - admins
+`), 0600))
+
+	config := schema.FileAuthenticationBackendConfiguration{
+		Path: path,
+		Password: &schema.PasswordConfiguration{
+			Algorithm:   "sha512",
+			Iterations:  1000,
+			SaltLength:  16,
+			KeyLength:   32,
+			Memory:      64,
+			Parallelism: 1,
+		},
+	}
+
+	s.provider = authentication.NewFileUserProvider(&config)
+
+	s.mock.Ctx.Providers.UserProvider = s.provider
+	s.mock.Ctx.Configuration.AuthenticationBackend.AdminGroup = "admins"
+	s.mock.Ctx.Configuration.SelfRegistration = schema.SelfRegistrationConfiguration{
+		Enabled:       true,
+		AdminEmails:   []string{"admin@example.com"},
+		DefaultGroups: []string{"users"},
+	}
+}
+
+func (s *RegistrationSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *RegistrationSuite) setAdministratorSession(level authentication.Level) {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.Groups = []string{"admins"}
+	userSession.AuthenticationLevel = level
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *RegistrationSuite) TestShouldRegisterPendingAccount() {
+	var saved model.UserRegistration
+
+	s.mock.StorageMock.EXPECT().
+		LoadUserRegistration(s.mock.Ctx, gomock.Eq("jane")).
+		Return(nil, nil)
+
+	s.mock.StorageMock.EXPECT().
+		SaveUserRegistration(s.mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ interface{}, registration model.UserRegistration) error {
+			saved = registration
+			return nil
+		})
+
+	s.mock.NotifierMock.EXPECT().
+		Send(gomock.Eq("admin@example.com"), gomock.Eq("Account registration pending approval"), gomock.Any(), gomock.Eq("")).
+		Return(nil)
+
+	s.mock.SetRequestBody(s.T(), registrationRequestBody{
+		Username:    "jane",
+		DisplayName: "Jane Doe",
+		Email:       "jane.doe@example.com",
+		Password:    "password",
+	})
+
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Assert().Equal("jane", saved.Username)
+	s.Assert().Equal("Jane Doe", saved.DisplayName)
+	s.Assert().Equal("jane.doe@example.com", saved.Email)
+	s.Assert().True(strings.HasPrefix(saved.Password, "$6$"))
+
+	// The pending account must not be able to authenticate.
+	ok, err := s.provider.CheckUserPassword("jane", "password")
+	s.Assert().False(ok)
+	s.Assert().Equal(authentication.ErrUserNotFound, err)
+}
+
+func (s *RegistrationSuite) TestShouldRefuseRegistrationWithEmailDomainNotPermitted() {
+	sink := mocks.NewMockEventSink(s.mock.Ctrl)
+
+	gomock.InOrder(
+		sink.EXPECT().
+			Publish(gomock.Any()).
+			DoAndReturn(func(event events.Event) error {
+				s.Assert().Equal(events.TypeEmailDomainRefused, event.Type)
+				s.Assert().Equal("jane", event.Username)
+				s.Assert().Equal(map[string]string{"email": "jane.doe@Gmail.com", "action": "registration"}, event.Details)
+
+				return nil
+			}),
+		sink.EXPECT().Close().Return(nil),
+	)
+
+	s.mock.Ctx.Providers.Events = events.NewEmitter(sink, 10, &s.mock.Clock)
+	s.mock.Ctx.Configuration.EmailDomains = schema.EmailDomainsConfiguration{
+		Allowed: []string{"example.com", "*.example.com"},
+	}
+
+	s.mock.SetRequestBody(s.T(), registrationRequestBody{
+		Username:    "jane",
+		DisplayName: "Jane Doe",
+		Email:       "jane.doe@Gmail.com",
+		Password:    "password",
+	})
+
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Your email address domain is not permitted.")
+	s.Assert().Equal("Registration of user 'jane' was refused as the domain of the email address 'jane.doe@Gmail.com' is not permitted", s.mock.Hook.LastEntry().Message)
+
+	s.Require().NoError(s.mock.Ctx.Providers.Events.Close())
+}
+
+func (s *RegistrationSuite) enableEmailVerification() {
+	s.mock.Clock.Set(time.Unix(1640000000, 0))
+	s.mock.Ctx.Clock = &s.mock.Clock
+	s.mock.Ctx.Configuration.SelfRegistration.EmailVerification = schema.SelfRegistrationEmailVerificationConfiguration{
+		Enabled:  true,
+		Lifespan: time.Hour,
+	}
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+}
+
+func (s *RegistrationSuite) TestShouldRegisterPendingAccountAndSendEmailVerification() {
+	s.enableEmailVerification()
+
+	var (
+		verification model.EmailVerification
+		body         string
+	)
+
+	s.mock.StorageMock.EXPECT().
+		LoadUserRegistration(s.mock.Ctx, gomock.Eq("jane")).
+		Return(nil, nil)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			SaveUserRegistration(s.mock.Ctx, gomock.Any()).
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			SaveEmailVerification(s.mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, v model.EmailVerification) error {
+				verification = v
+				return nil
+			}),
+		s.mock.NotifierMock.EXPECT().
+			Send(gomock.Eq("jane.doe@example.com"), gomock.Eq("Verify your email address"), gomock.Any(), gomock.Eq("")).
+			DoAndReturn(func(_, _, text, _ string) error {
+				body = text
+				return nil
+			}),
+		s.mock.NotifierMock.EXPECT().
+			Send(gomock.Eq("admin@example.com"), gomock.Eq("Account registration pending approval"), gomock.Any(), gomock.Eq("")).
+			Return(nil),
+	)
+
+	s.mock.SetRequestBody(s.T(), registrationRequestBody{
+		Username:    "jane",
+		DisplayName: "Jane Doe",
+		Email:       "jane.doe@example.com",
+		Password:    "password",
+	})
+
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Assert().Equal("jane", verification.Username)
+	s.Assert().Equal("jane.doe@example.com", verification.Email)
+	s.Assert().Equal(s.mock.Clock.Now().Add(time.Hour), verification.ExpiresAt)
+
+	matches := regexp.MustCompile(`https://auth\.example\.com/api/registration/email/verify\?token=([a-zA-Z0-9]{32})&username=jane`).FindStringSubmatch(body)
+	s.Require().Len(matches, 2)
+	s.Assert().True(verification.Matches(matches[1], s.mock.Clock.Now()))
+}
+
+func (s *RegistrationSuite) TestShouldRemoveRegistrationWhenEmailVerificationCannotBeSent() {
+	s.enableEmailVerification()
+
+	s.mock.StorageMock.EXPECT().
+		LoadUserRegistration(s.mock.Ctx, gomock.Eq("jane")).
+		Return(nil, nil)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			SaveUserRegistration(s.mock.Ctx, gomock.Any()).
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			SaveEmailVerification(s.mock.Ctx, gomock.Any()).
+			Return(nil),
+		s.mock.NotifierMock.EXPECT().
+			Send(gomock.Eq("jane.doe@example.com"), gomock.Eq("Verify your email address"), gomock.Any(), gomock.Eq("")).
+			Return(fmt.Errorf("no notif")),
+		s.mock.StorageMock.EXPECT().
+			DeleteUserRegistration(s.mock.Ctx, gomock.Eq("jane")).
+			Return(nil),
+	)
+
+	s.mock.SetRequestBody(s.T(), registrationRequestBody{
+		Username:    "jane",
+		DisplayName: "Jane Doe",
+		Email:       "jane.doe@example.com",
+		Password:    "password",
+	})
+
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToRegisterAccount)
+	s.Assert().Equal("no notif", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *RegistrationSuite) TestShouldVerifyRegistrationEmail() {
+	s.enableEmailVerification()
+
+	verification := model.NewEmailVerification("jane", "jane.doe@example.com", "abc123", s.mock.Clock.Now(), time.Hour)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadEmailVerification(s.mock.Ctx, gomock.Eq("jane")).
+			Return(&verification, nil),
+		s.mock.StorageMock.EXPECT().
+			ConsumeEmailVerification(s.mock.Ctx, gomock.Eq("jane"), gomock.Eq(s.mock.Clock.Now())).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetRequestURI("/api/registration/email/verify?username=jane&token=abc123")
+
+	RegistrationEmailVerificationGET(s.mock.Ctx)
+
+	s.Assert().Equal(302, s.mock.Ctx.Response.StatusCode())
+	s.Assert().Equal("https://auth.example.com/", string(s.mock.Ctx.Response.Header.Peek("Location")))
+}
+
+func (s *RegistrationSuite) TestShouldNotVerifyRegistrationEmailWithInvalidToken() {
+	s.enableEmailVerification()
+
+	verification := model.NewEmailVerification("jane", "jane.doe@example.com", "abc123", s.mock.Clock.Now(), time.Hour)
+
+	s.mock.StorageMock.EXPECT().
+		LoadEmailVerification(s.mock.Ctx, gomock.Eq("jane")).
+		Return(&verification, nil)
+
+	s.mock.Ctx.Request.SetRequestURI("/api/registration/email/verify?username=jane&token=abc124")
+
+	RegistrationEmailVerificationGET(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToVerifyEmail)
+	s.Assert().Equal("the email verification token of user 'jane' is invalid, has expired, or was already used", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *RegistrationSuite) TestShouldNotRegisterExistingUser() {
+	s.mock.SetRequestBody(s.T(), registrationRequestBody{
+		Username:    testUsername,
+		DisplayName: "John Doe",
+		Email:       "john.doe@example.com",
+		Password:    "password",
+	})
+
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToRegisterAccount)
+	s.Assert().Equal("user 'john' already exists", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *RegistrationSuite) TestShouldNotRegisterPendingUserTwice() {
+	s.mock.StorageMock.EXPECT().
+		LoadUserRegistration(s.mock.Ctx, gomock.Eq("jane")).
+		Return(&model.UserRegistration{Username: "jane"}, nil)
+
+	s.mock.SetRequestBody(s.T(), registrationRequestBody{
+		Username:    "jane",
+		DisplayName: "Jane Doe",
+		Email:       "jane.doe@example.com",
+		Password:    "password",
+	})
+
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToRegisterAccount)
+	s.Assert().Equal("user 'jane' already has a pending registration", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *RegistrationSuite) TestShouldNotRegisterInvalidUsername() {
+	s.mock.SetRequestBody(s.T(), registrationRequestBody{
+		Username:    "jane doe",
+		DisplayName: "Jane Doe",
+		Email:       "jane.doe@example.com",
+		Password:    "password",
+	})
+
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToRegisterAccount)
+	s.Assert().Equal("username 'jane doe' contains invalid characters", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *RegistrationSuite) TestShouldNotListPendingRegistrationsForNonAdministrators() {
+	s.setAdministratorSession(authentication.OneFactor)
+
+	RegistrationsPendingGET(s.mock.Ctx)
+
+	s.Assert().Equal(403, s.mock.Ctx.Response.StatusCode())
+}
+
+func (s *RegistrationSuite) TestShouldListPendingRegistrations() {
+	s.setAdministratorSession(authentication.TwoFactor)
+
+	requestedAt := time.Unix(1640000000, 0).UTC()
+
+	s.mock.StorageMock.EXPECT().
+		LoadUserRegistrations(s.mock.Ctx).
+		Return([]model.UserRegistration{{Username: "jane", DisplayName: "Jane Doe", Email: "jane.doe@example.com", Password: "hash", RequestedAt: requestedAt}}, nil)
+
+	RegistrationsPendingGET(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), []registrationResponse{{Username: "jane", DisplayName: "Jane Doe", Email: "jane.doe@example.com", RequestedAt: requestedAt}})
+}
+
+func (s *RegistrationSuite) TestShouldApproveRegistration() {
+	s.setAdministratorSession(authentication.TwoFactor)
+
+	hash, err := s.provider.HashPassword("password")
+	s.Require().NoError(err)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadUserRegistration(s.mock.Ctx, gomock.Eq("jane")).
+			Return(&model.UserRegistration{Username: "jane", DisplayName: "Jane Doe", Email: "jane.doe@example.com", Password: hash}, nil),
+		s.mock.StorageMock.EXPECT().
+			DeleteUserRegistration(s.mock.Ctx, gomock.Eq("jane")).
+			Return(nil),
+		s.mock.NotifierMock.EXPECT().
+			Send(gomock.Eq("jane.doe@example.com"), gomock.Eq("Account registration approved"), gomock.Any(), gomock.Eq("")).
+			Return(nil),
+	)
+
+	s.mock.SetRequestBody(s.T(), registrationApprovalRequestBody{Username: "jane", Approved: true})
+
+	RegistrationApprovalPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+
+	ok, err := s.provider.CheckUserPassword("jane", "password")
+	s.Assert().NoError(err)
+	s.Assert().True(ok)
+
+	details, err := s.provider.GetDetails("jane")
+	s.Require().NoError(err)
+	s.Assert().Equal([]string{"users"}, details.Groups)
+}
+
+func (s *RegistrationSuite) TestShouldListPendingRegistrationsWithEmailVerification() {
+	s.enableEmailVerification()
+	s.setAdministratorSession(authentication.TwoFactor)
+
+	requestedAt := time.Unix(1640000000, 0).UTC()
+	verification := model.NewEmailVerification("jane", "jane.doe@example.com", "abc123", s.mock.Clock.Now(), time.Hour)
+
+	s.mock.StorageMock.EXPECT().
+		LoadUserRegistrations(s.mock.Ctx).
+		Return([]model.UserRegistration{{Username: "jane", DisplayName: "Jane Doe", Email: "jane.doe@example.com", Password: "hash", RequestedAt: requestedAt}}, nil)
+
+	s.mock.StorageMock.EXPECT().
+		LoadEmailVerification(s.mock.Ctx, gomock.Eq("jane")).
+		Return(&verification, nil)
+
+	RegistrationsPendingGET(s.mock.Ctx)
+
+	verified := false
+
+	s.mock.Assert200OK(s.T(), []registrationResponse{{Username: "jane", DisplayName: "Jane Doe", Email: "jane.doe@example.com", RequestedAt: requestedAt, EmailVerified: &verified}})
+}
+
+func (s *RegistrationSuite) TestShouldNotApproveRegistrationWithUnverifiedEmail() {
+	s.enableEmailVerification()
+	s.setAdministratorSession(authentication.TwoFactor)
+
+	verification := model.NewEmailVerification("jane", "jane.doe@example.com", "abc123", s.mock.Clock.Now(), time.Hour)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadUserRegistration(s.mock.Ctx, gomock.Eq("jane")).
+			Return(&model.UserRegistration{Username: "jane", DisplayName: "Jane Doe", Email: "jane.doe@example.com", Password: "hash"}, nil),
+		s.mock.StorageMock.EXPECT().
+			LoadEmailVerification(s.mock.Ctx, gomock.Eq("jane")).
+			Return(&verification, nil),
+	)
+
+	s.mock.SetRequestBody(s.T(), registrationApprovalRequestBody{Username: "jane", Approved: true})
+
+	RegistrationApprovalPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Assert().Equal("user 'jane' has not verified their email address", s.mock.Hook.LastEntry().Message)
+
+	_, err := s.provider.GetDetails("jane")
+	s.Assert().Error(err)
+}
+
+func (s *RegistrationSuite) TestShouldDenyRegistration() {
+	s.setAdministratorSession(authentication.TwoFactor)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadUserRegistration(s.mock.Ctx, gomock.Eq("jane")).
+			Return(&model.UserRegistration{Username: "jane", DisplayName: "Jane Doe", Email: "jane.doe@example.com", Password: "hash"}, nil),
+		s.mock.StorageMock.EXPECT().
+			DeleteUserRegistration(s.mock.Ctx, gomock.Eq("jane")).
+			Return(nil),
+		s.mock.NotifierMock.EXPECT().
+			Send(gomock.Eq("jane.doe@example.com"), gomock.Eq("Account registration denied"), gomock.Any(), gomock.Eq("")).
+			Return(nil),
+	)
+
+	s.mock.SetRequestBody(s.T(), registrationApprovalRequestBody{Username: "jane", Approved: false})
+
+	RegistrationApprovalPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+
+	_, err := s.provider.GetDetails("jane")
+	s.Assert().Error(err)
+}
+
+func (s *RegistrationSuite) TestShouldNotApproveUnknownRegistration() {
+	s.setAdministratorSession(authentication.TwoFactor)
+
+	s.mock.StorageMock.EXPECT().
+		LoadUserRegistration(s.mock.Ctx, gomock.Eq("jane")).
+		Return(nil, nil)
+
+	s.mock.SetRequestBody(s.T(), registrationApprovalRequestBody{Username: "jane", Approved: true})
+
+	RegistrationApprovalPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Assert().Equal("user 'jane' does not have a pending registration", s.mock.Hook.LastEntry().Message)
+}
+
+func TestRunRegistrationSuite(t *testing.T) {
+	s := new(RegistrationSuite)
+	suite.Run(t, s)
+}
+
+func TestShouldNotRegisterWithUnsupportedUserProvider(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.SetRequestBody(t, registrationRequestBody{
+		Username:    "jane",
+		DisplayName: "Jane Doe",
+		Email:       "jane.doe@example.com",
+		Password:    "password",
+	})
+
+	RegistrationPOST(mock.Ctx)
+
+	mock.Assert200KO(t, messageUnableToRegisterAccount)
+	require.NotNil(t, mock.Hook.LastEntry())
+	assert.Equal(t, "the user provider does not support creating users", mock.Hook.LastEntry().Message)
+}
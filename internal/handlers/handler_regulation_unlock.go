@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/notification"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+// RegulationUnlockGET handler for lifting the ban of a user with the single-use token sent to them when they were
+// banned. The user is redirected to the portal when the ban is lifted.
+func RegulationUnlockGET(ctx *middlewares.AutheliaCtx) {
+	username, token := string(ctx.QueryArgs().Peek("username")), string(ctx.QueryArgs().Peek("token"))
+
+	if username == "" || token == "" {
+		ctx.Error(fmt.Errorf("no username or token was provided to unlock the account"), messageUnableToUnlockAccount)
+		return
+	}
+
+	if err := ctx.Providers.Regulator.Unlock(ctx, username, token); err != nil {
+		if errors.Is(err, regulation.ErrUnlockInvalid) {
+			err = fmt.Errorf("the unlock token of user '%s' is invalid, has expired, or was already used", username)
+		}
+
+		ctx.Error(err, messageUnableToUnlockAccount)
+
+		return
+	}
+
+	ctx.Logger.Infof("User '%s' has unlocked their account", username)
+
+	uri, err := ctx.ExternalRootURL()
+	if err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	ctx.Redirect(uri, fasthttp.StatusFound)
+}
+
+// sendRegulationUnlock sends an email with a link to lift the ban to a user who has just been banned by the regulator
+// as a result of a failed authentication attempt. Failures are logged and otherwise ignored as the response to the
+// authentication attempt must not differ.
+func sendRegulationUnlock(ctx *middlewares.AutheliaCtx, username string) {
+	if !ctx.Configuration.Regulation.Unlock.Enabled {
+		return
+	}
+
+	bannedUntil, err := ctx.Providers.Regulator.Regulate(ctx, username)
+	if !errors.Is(err, regulation.ErrUserIsBanned) {
+		return
+	}
+
+	details, err := ctx.Providers.UserProvider.GetDetails(username)
+	if err != nil {
+		ctx.Logger.Errorf("Unable to send the unlock email to user '%s': %+v", username, err)
+		return
+	}
+
+	if len(details.Emails) == 0 {
+		ctx.Logger.Errorf("Unable to send the unlock email to user '%s': the user has no email address", username)
+		return
+	}
+
+	token, unlock, err := ctx.Providers.Regulator.NewUnlock(ctx, username, bannedUntil)
+	if err != nil {
+		ctx.Logger.Errorf("Unable to send the unlock email to user '%s': %+v", username, err)
+		return
+	}
+
+	uri, err := ctx.ExternalRootURL()
+	if err != nil {
+		ctx.Logger.Errorf("Unable to send the unlock email to user '%s': %+v", username, err)
+		return
+	}
+
+	query := url.Values{}
+	query.Set("username", username)
+	query.Set("token", token)
+
+	bufText := new(bytes.Buffer)
+
+	if err = templates.EmailRegulationUnlockPlainText.Execute(bufText, map[string]interface{}{
+		"Username":    username,
+		"DisplayName": details.DisplayName,
+		"LinkURL":     fmt.Sprintf("%s/api/regulation/unlock?%s", uri, query.Encode()),
+		"BannedUntil": bannedUntil.Format(time.RFC1123),
+		"ExpiresAt":   unlock.ExpiresAt.Format(time.RFC1123),
+		"RemoteIP":    ctx.RemoteIP().String(),
+	}); err != nil {
+		ctx.Logger.Errorf("Unable to send the unlock email to user '%s': %+v", username, err)
+		return
+	}
+
+	ctx.Logger.Debugf("Sending an email to user '%s' (%s) to unlock their account", username, details.Emails[0])
+
+	if err = notification.SendEvent(ctx.Providers.Notifier, notification.EventAccountLocked, details.Emails[0], "Your account has been locked", bufText.String(), ""); err != nil {
+		ctx.Logger.Errorf("Unable to send the unlock email to user '%s': %+v", username, err)
+	}
+}
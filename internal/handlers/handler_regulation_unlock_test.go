@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+)
+
+type RegulationUnlockSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *RegulationUnlockSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Clock.Set(time.Unix(1640000000, 0))
+	s.mock.Ctx.Clock = &s.mock.Clock
+
+	s.mock.Ctx.Configuration.Regulation = schema.RegulationConfiguration{
+		MaxRetries: 3,
+		FindTime:   time.Minute * 2,
+		BanTime:    time.Minute * 5,
+		Unlock: schema.RegulationUnlockConfiguration{
+			Enabled:     true,
+			Lifespan:    time.Minute * 15,
+			MaxAttempts: 3,
+		},
+	}
+
+	s.mock.Ctx.Providers.Regulator = regulation.NewRegulator(s.mock.Ctx.Configuration.Regulation, s.mock.StorageMock, &s.mock.Clock)
+
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+}
+
+func (s *RegulationUnlockSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *RegulationUnlockSuite) bannedAttempts() []model.AuthenticationAttempt {
+	return []model.AuthenticationAttempt{
+		{Username: testUsername, Time: s.mock.Clock.Now()},
+		{Username: testUsername, Time: s.mock.Clock.Now().Add(-time.Second)},
+		{Username: testUsername, Time: s.mock.Clock.Now().Add(-time.Second * 2)},
+	}
+}
+
+func (s *RegulationUnlockSuite) TestShouldSendUnlockWhenBanned() {
+	var (
+		unlock model.RegulationUnlock
+		body   string
+	)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadAuthenticationLogs(s.mock.Ctx, gomock.Eq(testUsername), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+			Return(s.bannedAttempts(), nil),
+		s.mock.StorageMock.EXPECT().
+			LoadRegulationUnlock(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(nil, nil),
+		s.mock.UserProviderMock.EXPECT().
+			GetDetails(gomock.Eq(testUsername)).
+			Return(&authentication.UserDetails{Username: testUsername, DisplayName: "John Doe", Emails: []string{"john@example.com"}}, nil),
+		s.mock.StorageMock.EXPECT().
+			SaveRegulationUnlock(s.mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, u model.RegulationUnlock) error {
+				unlock = u
+				return nil
+			}),
+		s.mock.NotifierMock.EXPECT().
+			Send(gomock.Eq("john@example.com"), gomock.Eq("Your account has been locked"), gomock.Any(), gomock.Eq("")).
+			DoAndReturn(func(_, _, text, _ string) error {
+				body = text
+				return nil
+			}),
+	)
+
+	sendRegulationUnlock(s.mock.Ctx, testUsername)
+
+	s.Assert().Equal(testUsername, unlock.Username)
+	s.Assert().Equal(s.mock.Clock.Now().Add(time.Minute*5), unlock.ExpiresAt)
+
+	matches := regexp.MustCompile(`https://auth\.example\.com/api/regulation/unlock\?token=([a-zA-Z0-9]{32})&username=john`).FindStringSubmatch(body)
+	s.Require().Len(matches, 2)
+	s.Assert().True(unlock.Matches(matches[1]))
+}
+
+func (s *RegulationUnlockSuite) TestShouldNotSendUnlockWhenNotBanned() {
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadAuthenticationLogs(s.mock.Ctx, gomock.Eq(testUsername), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+			Return(s.bannedAttempts()[:2], nil),
+		s.mock.StorageMock.EXPECT().
+			LoadRegulationUnlock(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(nil, nil),
+	)
+
+	sendRegulationUnlock(s.mock.Ctx, testUsername)
+}
+
+func (s *RegulationUnlockSuite) TestShouldNotSendUnlockWhenDisabled() {
+	s.mock.Ctx.Configuration.Regulation.Unlock.Enabled = false
+
+	sendRegulationUnlock(s.mock.Ctx, testUsername)
+}
+
+func (s *RegulationUnlockSuite) TestShouldLogErrorWhenUserHasNoEmail() {
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadAuthenticationLogs(s.mock.Ctx, gomock.Eq(testUsername), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+			Return(s.bannedAttempts(), nil),
+		s.mock.StorageMock.EXPECT().
+			LoadRegulationUnlock(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(nil, nil),
+		s.mock.UserProviderMock.EXPECT().
+			GetDetails(gomock.Eq(testUsername)).
+			Return(&authentication.UserDetails{Username: testUsername}, nil),
+	)
+
+	sendRegulationUnlock(s.mock.Ctx, testUsername)
+
+	s.Assert().Equal("Unable to send the unlock email to user 'john': the user has no email address", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *RegulationUnlockSuite) TestShouldUnlockAccount() {
+	unlock := model.NewRegulationUnlock(testUsername, "abc123", s.mock.Clock.Now(), s.mock.Clock.Now().Add(time.Minute*5), time.Minute*15)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadRegulationUnlock(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(&unlock, nil),
+		s.mock.StorageMock.EXPECT().
+			LoadAuthenticationLogs(s.mock.Ctx, gomock.Eq(testUsername), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+			Return(s.bannedAttempts(), nil),
+		s.mock.StorageMock.EXPECT().
+			LoadRegulationUnlock(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(&unlock, nil),
+		s.mock.StorageMock.EXPECT().
+			ConsumeRegulationUnlock(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(s.mock.Clock.Now())).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetRequestURI("/api/regulation/unlock?username=john&token=abc123")
+
+	RegulationUnlockGET(s.mock.Ctx)
+
+	s.Assert().Equal(302, s.mock.Ctx.Response.StatusCode())
+	s.Assert().Equal("https://auth.example.com/", string(s.mock.Ctx.Response.Header.Peek("Location")))
+}
+
+func (s *RegulationUnlockSuite) TestShouldNotUnlockAccountWithInvalidToken() {
+	unlock := model.NewRegulationUnlock(testUsername, "abc123", s.mock.Clock.Now(), s.mock.Clock.Now().Add(time.Minute*5), time.Minute*15)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadRegulationUnlock(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(&unlock, nil),
+		s.mock.StorageMock.EXPECT().
+			IncrementRegulationUnlockAttempts(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetRequestURI("/api/regulation/unlock?username=john&token=abc124")
+
+	RegulationUnlockGET(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToUnlockAccount)
+	s.Assert().Equal("the unlock token of user 'john' is invalid, has expired, or was already used", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *RegulationUnlockSuite) TestShouldNotUnlockAccountWithStorageError() {
+	s.mock.StorageMock.EXPECT().
+		LoadRegulationUnlock(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return(nil, fmt.Errorf("failed to connect"))
+
+	s.mock.Ctx.Request.SetRequestURI("/api/regulation/unlock?username=john&token=abc123")
+
+	RegulationUnlockGET(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToUnlockAccount)
+	s.Assert().Equal("failed to connect", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *RegulationUnlockSuite) TestShouldNotUnlockAccountWithoutToken() {
+	s.mock.Ctx.Request.SetRequestURI("/api/regulation/unlock?username=john")
+
+	RegulationUnlockGET(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToUnlockAccount)
+	s.Assert().Equal("no username or token was provided to unlock the account", s.mock.Hook.LastEntry().Message)
+}
+
+func TestRunRegulationUnlockSuite(t *testing.T) {
+	suite.Run(t, new(RegulationUnlockSuite))
+}
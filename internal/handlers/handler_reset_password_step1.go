@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/session"
 )
@@ -17,18 +19,20 @@ func identityRetrieverFromStorage(ctx *middlewares.AutheliaCtx) (*session.Identi
 		return nil, err
 	}
 
-	details, err := ctx.Providers.UserProvider.GetDetails(requestBody.Username)
+	username := authentication.CanonicalizeUsername(ctx.Configuration.AuthenticationBackend.UsernameCanonicalization, requestBody.Username)
+
+	details, err := ctx.Providers.UserProvider.GetDetails(username)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if len(details.Emails) == 0 {
-		return nil, fmt.Errorf("user %s has no email address configured", requestBody.Username)
+		return nil, fmt.Errorf("user '%s' has no email address configured", username)
 	}
 
 	return &session.Identity{
-		Username:    requestBody.Username,
+		Username:    username,
 		Email:       details.Emails[0],
 		DisplayName: details.DisplayName,
 	}, nil
@@ -36,7 +40,18 @@ func identityRetrieverFromStorage(ctx *middlewares.AutheliaCtx) (*session.Identi
 
 // ResetPasswordIdentityStart the handler for initiating the identity validation for resetting a password.
 // We need to ensure the attacker cannot perform user enumeration by always replying with 200 whatever what happens in backend.
-var ResetPasswordIdentityStart = middlewares.IdentityVerificationStart(middlewares.IdentityVerificationStartArgs{
+func ResetPasswordIdentityStart(ctx *middlewares.AutheliaCtx) {
+	switch ctx.Configuration.AuthenticationBackend.PasswordReset.Method {
+	case schema.PasswordResetMethodAdminCode:
+		// The codes are issued by an administrator so there is nothing to send to the user.
+		ctx.Logger.Debug("Password reset identity verification start skipped as the codes are issued by an administrator")
+		ctx.ReplyOK()
+	default:
+		resetPasswordIdentityStartEmail(ctx)
+	}
+}
+
+var resetPasswordIdentityStartEmail = middlewares.IdentityVerificationStart(middlewares.IdentityVerificationStartArgs{
 	MailTitle:             "Reset your password",
 	MailButtonContent:     "Reset",
 	TargetEndpoint:        "/reset-password/step2",
@@ -44,19 +59,60 @@ var ResetPasswordIdentityStart = middlewares.IdentityVerificationStart(middlewar
 	IdentityRetrieverFunc: identityRetrieverFromStorage,
 }, middlewares.TimingAttackDelay(10, 250, 85, time.Millisecond*500))
 
-func resetPasswordIdentityFinish(ctx *middlewares.AutheliaCtx, username string) {
+func resetPasswordIdentityFinish(ctx *middlewares.AutheliaCtx, username string, administrator bool) {
 	userSession := ctx.GetSession()
 	// TODO(c.michaud): use JWT tokens to expire the request in only few seconds for better security.
 	userSession.PasswordResetUsername = &username
+	userSession.PasswordResetAdministrator = administrator
 
 	err := ctx.SaveSession(userSession)
 	if err != nil {
-		ctx.Logger.Errorf("Unable to clear password reset flag in session for user %s: %s", userSession.Username, err)
+		ctx.Logger.Errorf("Unable to clear password reset flag in session for user '%s': %s", userSession.Username, err)
 	}
 
 	ctx.ReplyOK()
 }
 
 // ResetPasswordIdentityFinish the handler for finishing the identity validation.
-var ResetPasswordIdentityFinish = middlewares.IdentityVerificationFinish(
-	middlewares.IdentityVerificationFinishArgs{ActionClaim: ActionResetPassword}, resetPasswordIdentityFinish)
+func ResetPasswordIdentityFinish(ctx *middlewares.AutheliaCtx) {
+	switch ctx.Configuration.AuthenticationBackend.PasswordReset.Method {
+	case schema.PasswordResetMethodAdminCode:
+		resetPasswordIdentityFinishAdminCode(ctx)
+	default:
+		resetPasswordIdentityFinishEmail(ctx)
+	}
+}
+
+var resetPasswordIdentityFinishEmail = middlewares.IdentityVerificationFinish(
+	middlewares.IdentityVerificationFinishArgs{ActionClaim: ActionResetPassword}, func(ctx *middlewares.AutheliaCtx, username string) {
+		resetPasswordIdentityFinish(ctx, username, false)
+	})
+
+// resetPasswordIdentityFinishAdminCode finishes the identity validation by consuming the one-time code issued to the
+// user by an administrator.
+func resetPasswordIdentityFinishAdminCode(ctx *middlewares.AutheliaCtx) {
+	var bodyJSON resetPasswordAdminCodeRequestBody
+
+	if err := ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	code, err := ctx.Providers.StorageProvider.LoadPasswordResetCode(ctx, bodyJSON.Username)
+
+	switch {
+	case err != nil:
+		ctx.Error(err, messageOperationFailed)
+		return
+	case code == nil || !code.Matches(bodyJSON.Code, ctx.Clock.Now()):
+		ctx.Error(fmt.Errorf("the password reset code provided for user '%s' is invalid or has expired", bodyJSON.Username), messageOperationFailed)
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.DeletePasswordResetCode(ctx, bodyJSON.Username); err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	resetPasswordIdentityFinish(ctx, bodyJSON.Username, true)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+type ResetPasswordAdminCodeSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *ResetPasswordAdminCodeSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Ctx.Configuration.AuthenticationBackend.PasswordReset.Method = schema.PasswordResetMethodAdminCode
+}
+
+func (s *ResetPasswordAdminCodeSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *ResetPasswordAdminCodeSuite) TestShouldNotSendEmailOnStart() {
+	s.mock.SetRequestBody(s.T(), resetPasswordStep1RequestBody{Username: testUsername})
+
+	ResetPasswordIdentityStart(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *ResetPasswordAdminCodeSuite) TestShouldFinishWithValidCode() {
+	code := model.NewPasswordResetCode(testUsername, "ABCDEFGH", time.Now(), time.Hour)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadPasswordResetCode(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(&code, nil),
+		s.mock.StorageMock.EXPECT().
+			DeletePasswordResetCode(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(nil),
+	)
+
+	s.mock.SetRequestBody(s.T(), resetPasswordAdminCodeRequestBody{Username: testUsername, Code: "ABCDEFGH"})
+
+	ResetPasswordIdentityFinish(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+
+	userSession := s.mock.Ctx.GetSession()
+	s.Require().NotNil(userSession.PasswordResetUsername)
+	s.Assert().Equal(testUsername, *userSession.PasswordResetUsername)
+	s.Assert().True(userSession.PasswordResetAdministrator)
+}
+
+func (s *ResetPasswordAdminCodeSuite) TestShouldNotFinishWithInvalidCode() {
+	code := model.NewPasswordResetCode(testUsername, "ABCDEFGH", time.Now(), time.Hour)
+
+	s.mock.StorageMock.EXPECT().
+		LoadPasswordResetCode(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return(&code, nil)
+
+	s.mock.SetRequestBody(s.T(), resetPasswordAdminCodeRequestBody{Username: testUsername, Code: "ABCDEFGI"})
+
+	ResetPasswordIdentityFinish(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Assert().Equal("the password reset code provided for user 'john' is invalid or has expired", s.mock.Hook.LastEntry().Message)
+	s.Assert().Nil(s.mock.Ctx.GetSession().PasswordResetUsername)
+}
+
+func (s *ResetPasswordAdminCodeSuite) TestShouldNotFinishWithExpiredCode() {
+	code := model.NewPasswordResetCode(testUsername, "ABCDEFGH", time.Now().Add(-time.Hour*2), time.Hour)
+
+	s.mock.StorageMock.EXPECT().
+		LoadPasswordResetCode(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return(&code, nil)
+
+	s.mock.SetRequestBody(s.T(), resetPasswordAdminCodeRequestBody{Username: testUsername, Code: "ABCDEFGH"})
+
+	ResetPasswordIdentityFinish(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Assert().Nil(s.mock.Ctx.GetSession().PasswordResetUsername)
+}
+
+func (s *ResetPasswordAdminCodeSuite) TestShouldNotFinishWithoutCode() {
+	s.mock.StorageMock.EXPECT().
+		LoadPasswordResetCode(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return(nil, nil)
+
+	s.mock.SetRequestBody(s.T(), resetPasswordAdminCodeRequestBody{Username: testUsername, Code: "ABCDEFGH"})
+
+	ResetPasswordIdentityFinish(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Assert().Nil(s.mock.Ctx.GetSession().PasswordResetUsername)
+}
+
+func TestRunResetPasswordAdminCodeSuite(t *testing.T) {
+	s := new(ResetPasswordAdminCodeSuite)
+	suite.Run(t, s)
+}
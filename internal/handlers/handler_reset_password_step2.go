@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/notification"
+	"github.com/authelia/authelia/v4/internal/session"
 	"github.com/authelia/authelia/v4/internal/templates"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
@@ -31,11 +33,36 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	if err = ctx.Providers.PasswordPolicy.Check(requestBody.Password); err != nil {
+	name, policy, err := getPasswordPolicy(ctx, username)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to determine the password policy of user '%s': %w", username, err), messageUnableToResetPassword)
+		return
+	}
+
+	if err = policy.Check(requestBody.Password); err != nil {
+		if name != "" {
+			err = fmt.Errorf("the password of user '%s' does not meet the password policy '%s': %w", username, name, err)
+		}
+
 		ctx.Error(err, messagePasswordWeak)
+
 		return
 	}
 
+	if !userSession.PasswordResetAdministrator {
+		var recent bool
+
+		if recent, err = isPasswordChangedTooRecently(ctx, username); err != nil {
+			ctx.Error(fmt.Errorf("unable to determine when the password of user '%s' was last changed: %w", username, err), messageUnableToResetPassword)
+			return
+		}
+
+		if recent {
+			ctx.Error(fmt.Errorf("the password of user '%s' was changed less than %s ago", username, ctx.Configuration.PasswordPolicy.MinimumAge), messagePasswordTooRecent)
+			return
+		}
+	}
+
 	err = ctx.Providers.UserProvider.UpdatePassword(username, requestBody.Password)
 
 	if err != nil {
@@ -50,10 +77,29 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	ctx.Logger.Debugf("Password of user %s has been reset", username)
+	ctx.Providers.UserDetailsCache.Invalidate(username)
+
+	ctx.Logger.Debugf("Password of user '%s' has been reset", username)
+
+	if err = ctx.Providers.StorageProvider.SaveUserPasswordChange(ctx, username, ctx.Clock.Now()); err != nil {
+		ctx.Logger.Errorf("Unable to save the password change time of user '%s': %v", username, err)
+	}
+
+	logoutOnPasswordChange := !ctx.Configuration.Session.DisableLogoutOnPasswordChange
+
+	if logoutOnPasswordChange {
+		logoutUserEverywhere(ctx, username)
+	}
 
 	// Reset the request.
-	userSession.PasswordResetUsername = nil
+	if logoutOnPasswordChange && userSession.Username == username {
+		// The current session is logged out as well so the user is prompted to log in with their new password.
+		userSession = session.NewDefaultUserSession()
+	} else {
+		userSession.PasswordResetUsername = nil
+		userSession.PasswordResetAdministrator = false
+	}
+
 	err = ctx.SaveSession(userSession)
 
 	if err != nil {
@@ -71,7 +117,7 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 	}
 
 	if len(userInfo.Emails) == 0 {
-		ctx.Logger.Error(fmt.Errorf("user %s has no email address configured", username))
+		ctx.Logger.Error(fmt.Errorf("user '%s' has no email address configured", username))
 		ctx.ReplyOK()
 
 		return
@@ -115,10 +161,10 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	ctx.Logger.Debugf("Sending an email to user %s (%s) to inform that the password has changed.",
+	ctx.Logger.Debugf("Sending an email to user '%s' (%s) to inform that the password has changed.",
 		username, userInfo.Emails[0])
 
-	err = ctx.Providers.Notifier.Send(userInfo.Emails[0], "Password changed successfully", bufText.String(), bufHTML.String())
+	err = notification.SendEvent(ctx.Providers.Notifier, notification.EventPasswordChanged, userInfo.Emails[0], "Password changed successfully", bufText.String(), bufHTML.String())
 
 	if err != nil {
 		ctx.Logger.Error(err)
@@ -127,3 +173,39 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 }
+
+// logoutUserEverywhere destroys the other sessions of the user and revokes their OpenID Connect refresh tokens after
+// their password has changed so a stolen session or token can no longer be used. The password has already changed so
+// failures are only logged.
+func logoutUserEverywhere(ctx *middlewares.AutheliaCtx, username string) {
+	destroyed, err := ctx.Providers.SessionProvider.DestroyUserSessions(ctx.RequestCtx, username)
+	if err != nil {
+		ctx.Logger.Errorf("Unable to destroy the sessions of user '%s' after their password changed: %v", username, err)
+	} else {
+		ctx.Logger.Debugf("Destroyed %d sessions of user '%s' after their password changed", destroyed, username)
+	}
+
+	if ctx.Configuration.IdentityProviders.OIDC == nil {
+		return
+	}
+
+	if err = revokeOpenIDConnectRefreshTokens(ctx, username); err != nil {
+		ctx.Logger.Errorf("Unable to revoke the refresh tokens of user '%s' after their password changed: %v", username, err)
+	}
+}
+
+// isPasswordChangedTooRecently returns true if the minimum password age is configured and the password of the user
+// was last changed less than the minimum password age ago. It's not enforced for the LDAP backend as directories
+// enforce their own minimum password age.
+func isPasswordChangedTooRecently(ctx *middlewares.AutheliaCtx, username string) (recent bool, err error) {
+	if ctx.Configuration.PasswordPolicy.MinimumAge <= 0 || ctx.Configuration.AuthenticationBackend.LDAP != nil {
+		return false, nil
+	}
+
+	changed, err := ctx.Providers.StorageProvider.LoadUserPasswordChange(ctx, username)
+	if err != nil || changed == nil {
+		return false, err
+	}
+
+	return ctx.Clock.Now().Before(changed.Add(ctx.Configuration.PasswordPolicy.MinimumAge)), nil
+}
@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+type ResetPasswordMinimumAgeSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *ResetPasswordMinimumAgeSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Ctx.Clock = &s.mock.Clock
+	s.mock.Ctx.Configuration.PasswordPolicy.MinimumAge = time.Hour * 24
+	s.mock.Ctx.Providers.PasswordPolicy = middlewares.NewPasswordPolicyProvider(s.mock.Ctx.Configuration.PasswordPolicy)
+
+	s.mock.Ctx.Request.SetBodyString(`{"password":"password1234"}`)
+}
+
+func (s *ResetPasswordMinimumAgeSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *ResetPasswordMinimumAgeSuite) setSession(administrator bool) {
+	username := testUsername
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.PasswordResetUsername = &username
+	userSession.PasswordResetAdministrator = administrator
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *ResetPasswordMinimumAgeSuite) expectPasswordUpdated() {
+	gomock.InOrder(
+		s.mock.UserProviderMock.EXPECT().
+			UpdatePassword(testUsername, "password1234").
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			SaveUserPasswordChange(s.mock.Ctx, testUsername, s.mock.Clock.Now()).
+			Return(nil),
+		s.mock.UserProviderMock.EXPECT().
+			GetDetails(testUsername).
+			Return(&authentication.UserDetails{Username: testUsername}, nil),
+	)
+}
+
+func (s *ResetPasswordMinimumAgeSuite) TestShouldRejectPasswordChangedWithinMinimumAge() {
+	s.setSession(false)
+
+	changed := s.mock.Clock.Now().Add(-time.Hour*24 + time.Second)
+
+	s.mock.StorageMock.EXPECT().
+		LoadUserPasswordChange(s.mock.Ctx, testUsername).
+		Return(&changed, nil)
+
+	ResetPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messagePasswordTooRecent)
+	s.Equal("the password of user 'john' was changed less than 24h0m0s ago", s.mock.Hook.LastEntry().Message)
+	s.NotNil(s.mock.Ctx.GetSession().PasswordResetUsername)
+}
+
+func (s *ResetPasswordMinimumAgeSuite) TestShouldAllowPasswordChangedExactlyMinimumAgeAgo() {
+	s.setSession(false)
+
+	changed := s.mock.Clock.Now().Add(-time.Hour * 24)
+
+	s.mock.StorageMock.EXPECT().
+		LoadUserPasswordChange(s.mock.Ctx, testUsername).
+		Return(&changed, nil)
+
+	s.expectPasswordUpdated()
+
+	ResetPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Nil(s.mock.Ctx.GetSession().PasswordResetUsername)
+}
+
+func (s *ResetPasswordMinimumAgeSuite) TestShouldAllowPasswordNeverChanged() {
+	s.setSession(false)
+
+	s.mock.StorageMock.EXPECT().
+		LoadUserPasswordChange(s.mock.Ctx, testUsername).
+		Return(nil, nil)
+
+	s.expectPasswordUpdated()
+
+	ResetPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *ResetPasswordMinimumAgeSuite) TestShouldAllowAdministratorResetWithinMinimumAge() {
+	s.setSession(true)
+
+	s.expectPasswordUpdated()
+
+	ResetPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+
+	userSession := s.mock.Ctx.GetSession()
+	s.Nil(userSession.PasswordResetUsername)
+	s.False(userSession.PasswordResetAdministrator)
+}
+
+func (s *ResetPasswordMinimumAgeSuite) TestShouldNotEnforceMinimumAgeWithLDAP() {
+	s.setSession(false)
+
+	s.mock.Ctx.Configuration.AuthenticationBackend.LDAP = &schema.LDAPAuthenticationBackendConfiguration{}
+
+	s.expectPasswordUpdated()
+
+	ResetPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *ResetPasswordMinimumAgeSuite) TestShouldFailWhenPasswordChangeCantBeLoaded() {
+	s.setSession(false)
+
+	s.mock.StorageMock.EXPECT().
+		LoadUserPasswordChange(s.mock.Ctx, testUsername).
+		Return(nil, fmt.Errorf("failed to connect"))
+
+	ResetPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToResetPassword)
+	s.Equal("unable to determine when the password of user 'john' was last changed: failed to connect", s.mock.Hook.LastEntry().Message)
+}
+
+func TestRunResetPasswordMinimumAgeSuite(t *testing.T) {
+	suite.Run(t, new(ResetPasswordMinimumAgeSuite))
+}
+
+type ResetPasswordLogoutSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *ResetPasswordLogoutSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Ctx.Clock = &s.mock.Clock
+	s.mock.Ctx.Providers.PasswordPolicy = middlewares.NewPasswordPolicyProvider(s.mock.Ctx.Configuration.PasswordPolicy)
+
+	s.mock.Ctx.Request.SetBodyString(`{"password":"password1234"}`)
+}
+
+func (s *ResetPasswordLogoutSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *ResetPasswordLogoutSuite) newOtherSession() string {
+	userSession := session.NewDefaultUserSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.TwoFactor
+
+	return s.mock.NewOtherUserSession(s.T(), userSession)
+}
+
+func (s *ResetPasswordLogoutSuite) setSession(username string) {
+	resetUsername := testUsername
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = username
+	userSession.PasswordResetUsername = &resetUsername
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	if username != "" {
+		s.Require().NoError(s.mock.Ctx.Providers.SessionProvider.RegisterUserSession(s.mock.Ctx.RequestCtx, username, false))
+	}
+}
+
+func (s *ResetPasswordLogoutSuite) expectPasswordUpdated() {
+	gomock.InOrder(
+		s.mock.UserProviderMock.EXPECT().
+			UpdatePassword(testUsername, "password1234").
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			SaveUserPasswordChange(s.mock.Ctx, testUsername, s.mock.Clock.Now()).
+			Return(nil),
+		s.mock.UserProviderMock.EXPECT().
+			GetDetails(testUsername).
+			Return(&authentication.UserDetails{Username: testUsername}, nil),
+	)
+}
+
+func (s *ResetPasswordLogoutSuite) TestShouldDestroyOtherSessionsAndLogoutCurrentSession() {
+	other := s.newOtherSession()
+
+	s.setSession(testUsername)
+	s.expectPasswordUpdated()
+
+	ResetPasswordPOST(s.mock.Ctx)
+
+	_, found, err := s.mock.Ctx.Providers.SessionProvider.GetSessionByID(other)
+	s.Require().NoError(err)
+	s.False(found)
+
+	userSession := s.mock.Ctx.GetSession()
+	s.Equal("", userSession.Username)
+	s.Equal(authentication.NotAuthenticated, userSession.AuthenticationLevel)
+	s.Nil(userSession.PasswordResetUsername)
+}
+
+func (s *ResetPasswordLogoutSuite) TestShouldDestroyOtherSessionsWhenResetFromAnonymousSession() {
+	other := s.newOtherSession()
+
+	s.setSession("")
+	s.expectPasswordUpdated()
+
+	ResetPasswordPOST(s.mock.Ctx)
+
+	_, found, err := s.mock.Ctx.Providers.SessionProvider.GetSessionByID(other)
+	s.Require().NoError(err)
+	s.False(found)
+
+	s.Nil(s.mock.Ctx.GetSession().PasswordResetUsername)
+}
+
+func (s *ResetPasswordLogoutSuite) TestShouldRevokeRefreshTokens() {
+	s.mock.Ctx.Configuration.IdentityProviders.OIDC = &schema.OpenIDConnectConfiguration{
+		RefreshTokenLifespan: time.Hour,
+	}
+
+	s.setSession("")
+
+	gomock.InOrder(
+		s.mock.UserProviderMock.EXPECT().
+			UpdatePassword(testUsername, "password1234").
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			SaveUserPasswordChange(s.mock.Ctx, testUsername, s.mock.Clock.Now()).
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			LoadOAuth2RefreshTokensActive(s.mock.Ctx, testUsername).
+			Return([]model.OAuth2RefreshToken{{RequestID: "abc", RequestedAt: s.mock.Clock.Now()}}, nil),
+		s.mock.StorageMock.EXPECT().
+			RevokeOAuth2SessionByRequestID(s.mock.Ctx, storage.OAuth2SessionTypeRefreshToken, "abc").
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			RevokeOAuth2SessionByRequestID(s.mock.Ctx, storage.OAuth2SessionTypeAccessToken, "abc").
+			Return(nil),
+		s.mock.UserProviderMock.EXPECT().
+			GetDetails(testUsername).
+			Return(&authentication.UserDetails{Username: testUsername}, nil),
+	)
+
+	ResetPasswordPOST(s.mock.Ctx)
+}
+
+func (s *ResetPasswordLogoutSuite) TestShouldNotLogoutWhenDisabled() {
+	s.mock.Ctx.Configuration.Session.DisableLogoutOnPasswordChange = true
+	s.mock.Ctx.Configuration.IdentityProviders.OIDC = &schema.OpenIDConnectConfiguration{}
+
+	s.setSession(testUsername)
+	s.expectPasswordUpdated()
+
+	ResetPasswordPOST(s.mock.Ctx)
+
+	userSession := s.mock.Ctx.GetSession()
+	s.Equal(testUsername, userSession.Username)
+	s.Nil(userSession.PasswordResetUsername)
+}
+
+func TestRunResetPasswordLogoutSuite(t *testing.T) {
+	suite.Run(t, new(ResetPasswordLogoutSuite))
+}
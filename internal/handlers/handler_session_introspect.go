@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+	"net"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// SessionIntrospectPOST is the handler trusted services use to introspect the session of a user given the session ID
+// in the body or the session cookie. The session is only read so introspecting it never extends or refreshes it.
+func SessionIntrospectPOST(ctx *middlewares.AutheliaCtx) {
+	if !isSessionIntrospectionAuthorized(ctx) {
+		ctx.Logger.Warnf("Unauthorized session introspection request from '%s'", ctx.RequestCtx.RemoteIP())
+		ctx.ReplyUnauthorized()
+
+		return
+	}
+
+	bodyJSON := sessionIntrospectionRequestBody{}
+
+	if len(ctx.PostBody()) != 0 {
+		if err := ctx.ParseBody(&bodyJSON); err != nil {
+			ctx.Logger.Errorf("Unable to parse session introspection request body: %+v", err)
+			ctx.ReplyBadRequest()
+
+			return
+		}
+	}
+
+	id := bodyJSON.SessionID
+	if id == "" {
+		id = string(ctx.Request.Header.Cookie(ctx.Configuration.Session.Name))
+	}
+
+	if id == "" {
+		ctx.Logger.Debug("Session introspection request did not include a session")
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	userSession, found, err := ctx.Providers.SessionProvider.GetSessionByID(id)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to load the session to introspect: %w", err), messageOperationFailed)
+		return
+	}
+
+	response := SessionIntrospectionResponse{}
+
+	if found && userSession.Username != "" {
+		remaining, expires := ctx.Providers.SessionProvider.GetRemainingTime(userSession, ctx.Clock.Now())
+
+		if !expires || remaining > 0 {
+			response.Authenticated = true
+			response.Username = userSession.Username
+			response.DisplayName = userSession.DisplayName
+			response.Groups = userSession.Groups
+			response.AuthenticationLevel = userSession.AuthenticationLevel
+
+			if expires {
+				expiresAt := ctx.Clock.Now().Add(remaining).Unix()
+
+				response.ExpiresAt = &expiresAt
+			}
+		}
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.Errorf("Unable to set session introspection response in body: %s", err)
+	}
+}
+
+// isSessionIntrospectionAuthorized returns true if the request is from one of the trusted networks and includes the
+// secret as a bearer token, when these are configured. The X-Forwarded-For header is only taken into account when
+// trusted proxies are configured as the endpoint would otherwise be trivially accessible.
+func isSessionIntrospectionAuthorized(ctx *middlewares.AutheliaCtx) bool {
+	config := ctx.Configuration.Session.Introspection
+
+	if len(config.TrustedNetworks) != 0 {
+		ip := ctx.RequestCtx.RemoteIP()
+		if len(ctx.Configuration.Server.TrustedProxies) != 0 {
+			ip = ctx.RemoteIP()
+		}
+
+		networks := make([]*net.IPNet, 0, len(config.TrustedNetworks))
+
+		for _, network := range config.TrustedNetworks {
+			if cidr, err := utils.ParseNetwork(network); err == nil {
+				networks = append(networks, cidr)
+			}
+		}
+
+		if !utils.IsIPInNetworks(ip, networks) {
+			return false
+		}
+	}
+
+	if config.Secret != "" {
+		auth := ctx.Request.Header.PeekBytes(headerAuthorization)
+
+		if len(auth) <= len(headerPrefixBearer) || !bytes.EqualFold(auth[:len(headerPrefixBearer)], headerPrefixBearer) {
+			return false
+		}
+
+		if subtle.ConstantTimeCompare(auth[len(headerPrefixBearer):], []byte(config.Secret)) != 1 {
+			return false
+		}
+	}
+
+	return true
+}
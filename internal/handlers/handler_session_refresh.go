@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+)
+
+// SessionRefreshPOST is the handler resetting the inactivity period of the session when the user chooses to stay
+// signed in. The session is never extended beyond the maximum authentication age.
+func SessionRefreshPOST(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	if !userSession.KeepMeLoggedIn {
+		if inactive, _ := hasUserBeenInactiveTooLong(ctx); inactive {
+			if err := ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx); err != nil {
+				ctx.Logger.Errorf("Unable to destroy session of user '%s' after long inactivity: %+v", userSession.Username, err)
+			}
+
+			ctx.Logger.Debugf("User '%s' has been inactive for too long to refresh the session", userSession.Username)
+			ctx.ReplyUnauthorized()
+
+			return
+		}
+	}
+
+	now := ctx.Clock.Now()
+
+	ctx.Providers.SessionProvider.RefreshActivity(&userSession, now)
+
+	if err := ctx.SaveSession(userSession); err != nil {
+		ctx.Error(fmt.Errorf("unable to save the refreshed session of user '%s': %w", userSession.Username, err), messageOperationFailed)
+		return
+	}
+
+	response := SessionRefreshResponse{}
+
+	if remaining, expires := ctx.Providers.SessionProvider.GetRemainingTime(userSession, now); expires {
+		seconds := int64(remaining.Seconds())
+
+		response.SessionRemaining = &seconds
+	}
+
+	if err := ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.Errorf("Unable to set session refresh response in body: %s", err)
+	}
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+type SessionRefreshSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *SessionRefreshSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+
+	s.mock.Ctx.Configuration.Session.Inactivity = time.Minute * 5
+	s.mock.Ctx.Configuration.Session.InactivityWarning = time.Minute
+	s.mock.Ctx.Configuration.Session.MaximumAuthenticationAge = time.Minute * 10
+	s.mock.Ctx.Providers.SessionProvider = session.NewProvider(s.mock.Ctx.Configuration.Session, nil, nil)
+}
+
+func (s *SessionRefreshSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *SessionRefreshSuite) setSession(authenticated, lastActivity time.Time) {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.SetOneFactor(authenticated, &authentication.UserDetails{Username: testUsername}, false)
+	userSession.LastActivity = lastActivity.Unix()
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *SessionRefreshSuite) TestShouldRefreshSession() {
+	now := time.Now()
+
+	s.setSession(now.Add(-time.Minute*2), now.Add(-time.Minute))
+
+	SessionRefreshPOST(s.mock.Ctx)
+
+	response := SessionRefreshResponse{}
+	s.mock.GetResponseData(s.T(), &response)
+
+	s.Require().NotNil(response.SessionRemaining)
+	s.Assert().InDelta(300, *response.SessionRemaining, 1)
+	s.Assert().InDelta(now.Unix(), s.mock.Ctx.GetSession().LastActivity, 1)
+}
+
+func (s *SessionRefreshSuite) TestShouldCapRefreshAtMaximumAuthenticationAge() {
+	now := time.Now()
+
+	s.setSession(now.Add(-time.Minute*8), now.Add(-time.Minute))
+
+	SessionRefreshPOST(s.mock.Ctx)
+
+	response := SessionRefreshResponse{}
+	s.mock.GetResponseData(s.T(), &response)
+
+	s.Require().NotNil(response.SessionRemaining)
+	s.Assert().InDelta(120, *response.SessionRemaining, 1)
+}
+
+func (s *SessionRefreshSuite) TestShouldNotRefreshInactiveSession() {
+	now := time.Now()
+
+	s.setSession(now.Add(-time.Minute*8), now.Add(-time.Minute*6))
+
+	SessionRefreshPOST(s.mock.Ctx)
+
+	s.Assert().Equal(401, s.mock.Ctx.Response.StatusCode())
+	s.Assert().Equal("", s.mock.Ctx.GetSession().Username)
+}
+
+func (s *SessionRefreshSuite) TestShouldNotReturnRemainingTimeForKeepMeLoggedIn() {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.SetOneFactor(time.Now(), &authentication.UserDetails{Username: testUsername}, true)
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	SessionRefreshPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), SessionRefreshResponse{})
+}
+
+func TestRunSessionRefreshSuite(t *testing.T) {
+	s := new(SessionRefreshSuite)
+	suite.Run(t, s)
+}
+
+func TestShouldReturnSessionRemainingInState(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.Session = schema.SessionConfiguration{
+		Name:              "authelia_session",
+		Inactivity:        time.Minute * 5,
+		InactivityWarning: time.Minute,
+	}
+	mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil, nil)
+
+	userSession := mock.Ctx.GetSession()
+	userSession.SetOneFactor(time.Now(), &authentication.UserDetails{Username: testUsername}, false)
+	userSession.LastActivity = time.Now().Add(-time.Minute).Unix()
+
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	StateGET(mock.Ctx)
+
+	response := StateResponse{}
+	mock.GetResponseData(t, &response)
+
+	require.NotNil(t, response.SessionRemaining)
+	assert.InDelta(t, 240, *response.SessionRemaining, 1)
+	assert.Equal(t, int64(60), response.InactivityWarning)
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/duo"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
@@ -33,7 +35,7 @@ func DuoPOST(duoAPI duo.API) middlewares.RequestHandler {
 
 		duoDevice, err := ctx.Providers.StorageProvider.LoadPreferredDuoDevice(ctx, userSession.Username)
 		if err != nil {
-			ctx.Logger.Debugf("Error identifying preferred device for user %s: %s", userSession.Username, err)
+			ctx.Logger.Debugf("Error identifying preferred device for user '%s': %s", userSession.Username, err)
 			ctx.Logger.Debugf("Starting Duo PreAuth for initial device selection of user: %s", userSession.Username)
 			device, method, err = HandleInitialDeviceSelection(ctx, &userSession, duoAPI, requestBody.TargetURL)
 		} else {
@@ -63,15 +65,13 @@ func DuoPOST(duoAPI duo.API) middlewares.RequestHandler {
 
 		authResponse, err := duoAPI.AuthCall(ctx, values)
 		if err != nil {
-			ctx.Logger.Errorf("Failed to perform Duo Auth Call for user '%s': %+v", userSession.Username, err)
-
-			respondUnauthorized(ctx, messageMFAValidationFailed)
+			handleDuoAPIError(ctx, &userSession, "Auth Call", requestBody.TargetURL, err)
 
 			return
 		}
 
 		if authResponse.Result != allow {
-			_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeDuo,
+			markSecondFactorAttemptFailed(ctx, &userSession, regulation.AuthTypeDuo,
 				fmt.Errorf("duo auth result: %s, status: %s, message: %s", authResponse.Result, authResponse.Status,
 					authResponse.StatusMessage))
 
@@ -93,11 +93,9 @@ func DuoPOST(duoAPI duo.API) middlewares.RequestHandler {
 func HandleInitialDeviceSelection(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, duoAPI duo.API, targetURL string) (device string, method string, err error) {
 	result, message, devices, enrollURL, err := DuoPreAuth(ctx, duoAPI)
 	if err != nil {
-		ctx.Logger.Errorf("Failed to perform Duo PreAuth for user '%s': %+v", userSession.Username, err)
-
-		respondUnauthorized(ctx, messageMFAValidationFailed)
+		handleDuoAPIError(ctx, userSession, "PreAuth", targetURL, err)
 
-		return "", "", err
+		return "", "", nil
 	}
 
 	switch result {
@@ -138,9 +136,7 @@ func HandleInitialDeviceSelection(ctx *middlewares.AutheliaCtx, userSession *ses
 func HandlePreferredDeviceCheck(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, duoAPI duo.API, device string, method string, targetURL string) (string, string, error) {
 	result, message, devices, enrollURL, err := DuoPreAuth(ctx, duoAPI)
 	if err != nil {
-		ctx.Logger.Errorf("Failed to perform Duo PreAuth for user '%s': %+v", userSession.Username, err)
-
-		respondUnauthorized(ctx, messageMFAValidationFailed)
+		handleDuoAPIError(ctx, userSession, "PreAuth", targetURL, err)
 
 		return "", "", nil
 	}
@@ -150,7 +146,7 @@ func HandlePreferredDeviceCheck(ctx *middlewares.AutheliaCtx, userSession *sessi
 		ctx.Logger.Debugf("Duo user: %s no longer enrolled removing preferred device", userSession.Username)
 
 		if err := ctx.Providers.StorageProvider.DeletePreferredDuoDevice(ctx, userSession.Username); err != nil {
-			return "", "", fmt.Errorf("unable to delete preferred Duo device and method for user %s: %s", userSession.Username, err)
+			return "", "", fmt.Errorf("unable to delete preferred Duo device and method for user '%s': %s", userSession.Username, err)
 		}
 
 		if err := ctx.SetJSONBody(DuoSignResponse{Result: enroll, EnrollURL: enrollURL}); err != nil {
@@ -173,7 +169,7 @@ func HandlePreferredDeviceCheck(ctx *middlewares.AutheliaCtx, userSession *sessi
 			ctx.Logger.Debugf("Duo user: %s has no compatible device/method available removing preferred device", userSession.Username)
 
 			if err := ctx.Providers.StorageProvider.DeletePreferredDuoDevice(ctx, userSession.Username); err != nil {
-				return "", "", fmt.Errorf("unable to delete preferred Duo device and method for user %s: %s", userSession.Username, err)
+				return "", "", fmt.Errorf("unable to delete preferred Duo device and method for user '%s': %s", userSession.Username, err)
 			}
 
 			if err := ctx.SetJSONBody(DuoSignResponse{Result: enroll}); err != nil {
@@ -236,12 +232,42 @@ func HandleAutoSelection(ctx *middlewares.AutheliaCtx, devices []DuoDevice, user
 	ctx.Logger.Debugf("Exactly one device: '%s' and method: '%s' found, saving as new preferred Duo device and method for user: %s", device, method, username)
 
 	if err := ctx.Providers.StorageProvider.SavePreferredDuoDevice(ctx, model.DuoDevice{Username: username, Method: method, Device: device}); err != nil {
-		return "", "", fmt.Errorf("unable to save new preferred Duo device and method for user %s: %s", username, err)
+		return "", "", fmt.Errorf("unable to save new preferred Duo device and method for user '%s': %s", username, err)
 	}
 
 	return device, method, nil
 }
 
+// handleDuoAPIError responds to a failed call to the Duo API according to the configured failure mode. Timeouts are
+// logged distinctly so a slow Duo service can be told apart from other failures.
+func handleDuoAPIError(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, call, targetURL string, err error) {
+	timeout := errors.Is(err, duo.ErrTimeout)
+
+	if timeout {
+		ctx.Logger.Errorf("Duo %s for user '%s' timed out: %+v", call, userSession.Username, err)
+	} else {
+		ctx.Logger.Errorf("Failed to perform Duo %s for user '%s': %+v", call, userSession.Username, err)
+	}
+
+	switch {
+	case ctx.Configuration.DuoAPI != nil && ctx.Configuration.DuoAPI.FailureMode == schema.DuoFailureModeAllow:
+		ctx.Logger.Warnf("Allowing user '%s' to complete the second factor without Duo as the failure mode is '%s'", userSession.Username, schema.DuoFailureModeAllow)
+
+		if err = markAuthenticationAttempt(ctx, true, nil, userSession.Username, regulation.AuthTypeDuo, nil); err != nil {
+			respondUnauthorized(ctx, messageMFAValidationFailed)
+
+			return
+		}
+
+		HandleAllow(ctx, targetURL)
+	case timeout:
+		// A KO status is used instead of an unauthorized status so the portal can inform the user.
+		ctx.SetJSONError(messageDuoTimeout)
+	default:
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+	}
+}
+
 // HandleAllow handler for successful logins.
 func HandleAllow(ctx *middlewares.AutheliaCtx, targetURL string) {
 	userSession := ctx.GetSession()
@@ -13,6 +13,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/duo"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
@@ -171,6 +173,67 @@ func (s *SecondFactorDuoPostSuite) TestShouldFailAutoSelect() {
 	s.mock.Assert401KO(s.T(), "Authentication failed, please retry later.")
 }
 
+func (s *SecondFactorDuoPostSuite) TestShouldRespondWithTimeoutMessageWhenPreAuthTimesOut() {
+	duoMock := mocks.NewMockAPI(s.mock.Ctrl)
+
+	s.mock.StorageMock.EXPECT().
+		LoadPreferredDuoDevice(s.mock.Ctx, "john").
+		Return(nil, errors.New("no Duo device and method saved"))
+
+	duoMock.EXPECT().PreAuthCall(s.mock.Ctx, gomock.Any()).Return(nil, fmt.Errorf("%w: POST /auth/v2/preauth did not respond within 10s", duo.ErrTimeout))
+
+	bodyBytes, err := json.Marshal(signDuoRequestBody{TargetURL: "https://target.example.com"})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	DuoPOST(duoMock)(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Duo did not respond in time, please retry later.")
+	assert.Equal(s.T(), "Duo PreAuth for user 'john' timed out: the duo api call timed out: POST /auth/v2/preauth did not respond within 10s", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *SecondFactorDuoPostSuite) TestShouldAllowWhenAuthCallFailsAndFailureModeIsAllow() {
+	duoMock := mocks.NewMockAPI(s.mock.Ctrl)
+
+	s.mock.Ctx.Configuration.DuoAPI = &schema.DuoAPIConfiguration{FailureMode: schema.DuoFailureModeAllow}
+
+	s.mock.StorageMock.EXPECT().
+		LoadPreferredDuoDevice(s.mock.Ctx, "john").
+		Return(&model.DuoDevice{ID: 1, Username: "john", Device: "12345ABCDEFGHIJ67890", Method: "push"}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "john",
+			Successful: true,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthTypeDuo,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		})).
+		Return(nil)
+
+	preAuthResponse := duo.PreAuthResponse{}
+	preAuthResponse.Result = auth
+	preAuthResponse.Devices = []duo.Device{
+		{Capabilities: []string{"auto", "push", "sms", "mobile_otp"}, Number: " ", Device: "12345ABCDEFGHIJ67890", DisplayName: "Test Device 1"},
+	}
+
+	duoMock.EXPECT().PreAuthCall(s.mock.Ctx, gomock.Any()).Return(&preAuthResponse, nil)
+	duoMock.EXPECT().AuthCall(s.mock.Ctx, gomock.Any()).Return(nil, fmt.Errorf("%w: POST /auth/v2/auth did not respond within 1m15s", duo.ErrTimeout))
+
+	bodyBytes, err := json.Marshal(signDuoRequestBody{TargetURL: "https://mydomain.local"})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	DuoPOST(duoMock)(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), redirectResponse{
+		Redirect: "https://mydomain.local",
+	})
+	assert.True(s.T(), s.mock.Ctx.GetSession().AuthenticationLevel == authentication.TwoFactor)
+}
+
 func (s *SecondFactorDuoPostSuite) TestShouldDeleteOldDeviceAndEnroll() {
 	duoMock := mocks.NewMockAPI(s.mock.Ctrl)
 
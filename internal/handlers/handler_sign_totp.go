@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/storage"
 )
 
 // TimeBasedOneTimePasswordPOST validate the TOTP passcode provided by the user.
@@ -19,7 +23,7 @@ func TimeBasedOneTimePasswordPOST(ctx *middlewares.AutheliaCtx) {
 
 	userSession := ctx.GetSession()
 
-	config, err := ctx.Providers.StorageProvider.LoadTOTPConfiguration(ctx, userSession.Username)
+	configs, err := ctx.Providers.StorageProvider.LoadTOTPConfigurationsByUsername(ctx, userSession.Username)
 	if err != nil {
 		ctx.Logger.Errorf("Failed to load TOTP configuration: %+v", err)
 
@@ -28,17 +32,37 @@ func TimeBasedOneTimePasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	isValid, err := ctx.Providers.TOTP.Validate(requestBody.Token, config)
-	if err != nil {
+	config, step, err := validateTOTPConfigurations(ctx, requestBody.Token, configs)
+
+	switch {
+	case config != nil:
+		break
+	case err != nil:
 		ctx.Logger.Errorf("Failed to perform TOTP verification: %+v", err)
 
 		respondUnauthorized(ctx, messageMFAValidationFailed)
 
+		return
+	default:
+		markSecondFactorAttemptFailed(ctx, &userSession, regulation.AuthTypeTOTP, nil)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
 		return
 	}
 
-	if !isValid {
-		_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeTOTP, nil)
+	config.UpdateSignInInfo(ctx.Clock.Now(), step)
+
+	// The sign in information must be updated before the attempt is marked as successful as this is what prevents the
+	// code being replayed.
+	if err = ctx.Providers.StorageProvider.UpdateTOTPConfigurationSignIn(ctx, config.ID, config.LastUsedAt, step); err != nil {
+		if errors.Is(err, storage.ErrTOTPStepAlreadyUsed) {
+			ctx.Logger.Errorf("User '%s' attempted to reuse a %s code for the registration '%s'", userSession.Username, regulation.AuthTypeTOTP, config.Description)
+
+			markSecondFactorAttemptFailed(ctx, &userSession, regulation.AuthTypeTOTP, nil)
+		} else {
+			ctx.Logger.Errorf("Unable to save %s device sign in metadata for user '%s': %v", regulation.AuthTypeTOTP, userSession.Username, err)
+		}
 
 		respondUnauthorized(ctx, messageMFAValidationFailed)
 
@@ -58,16 +82,6 @@ func TimeBasedOneTimePasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	config.UpdateSignInInfo(ctx.Clock.Now())
-
-	if err = ctx.Providers.StorageProvider.UpdateTOTPConfigurationSignIn(ctx, config.ID, config.LastUsedAt); err != nil {
-		ctx.Logger.Errorf("Unable to save %s device sign in metadata for user '%s': %v", regulation.AuthTypeTOTP, userSession.Username, err)
-
-		respondUnauthorized(ctx, messageMFAValidationFailed)
-
-		return
-	}
-
 	userSession.SetTwoFactorTOTP(ctx.Clock.Now())
 
 	if err = ctx.SaveSession(userSession); err != nil {
@@ -84,3 +98,29 @@ func TimeBasedOneTimePasswordPOST(ctx *middlewares.AutheliaCtx) {
 		Handle2FAResponse(ctx, requestBody.TargetURL)
 	}
 }
+
+// validateTOTPConfigurations validates the token against each of the TOTP configurations of a user and returns the
+// first configuration the token is valid for along with the step of the token. An error is only returned when the
+// token isn't valid for any configuration.
+func validateTOTPConfigurations(ctx *middlewares.AutheliaCtx, token string, configs []model.TOTPConfiguration) (config *model.TOTPConfiguration, step uint64, err error) {
+	var (
+		valid   bool
+		lastErr error
+	)
+
+	for i := range configs {
+		if valid, step, err = ctx.Providers.TOTP.Validate(token, &configs[i]); err != nil {
+			ctx.Logger.Debugf("Failed to validate the %s code against the registration '%s': %+v", regulation.AuthTypeTOTP, configs[i].Description, err)
+
+			lastErr = err
+
+			continue
+		}
+
+		if valid {
+			return &configs[i], step, nil
+		}
+	}
+
+	return nil, 0, lastErr
+}
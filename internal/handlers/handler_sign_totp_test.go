@@ -5,14 +5,18 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/storage"
 )
 
 type HandlerSignTOTPSuite struct {
@@ -37,8 +41,8 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToDefaultURL() {
 	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
 
 	s.mock.StorageMock.EXPECT().
-		LoadTOTPConfiguration(s.mock.Ctx, gomock.Any()).
-		Return(&config, nil)
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Any()).
+		Return([]model.TOTPConfiguration{config}, nil)
 
 	s.mock.StorageMock.
 		EXPECT().
@@ -51,11 +55,11 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToDefaultURL() {
 			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
 		}))
 
-	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, uint64(1), nil)
 
 	s.mock.StorageMock.
 		EXPECT().
-		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any())
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Any())
 
 	s.mock.Ctx.Configuration.DefaultRedirectionURL = testRedirectionURL
 
@@ -75,25 +79,14 @@ func (s *HandlerSignTOTPSuite) TestShouldFailWhenTOTPSignInInfoFailsToUpdate() {
 	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
 
 	s.mock.StorageMock.EXPECT().
-		LoadTOTPConfiguration(s.mock.Ctx, gomock.Any()).
-		Return(&config, nil)
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Any()).
+		Return([]model.TOTPConfiguration{config}, nil)
 
-	s.mock.StorageMock.
-		EXPECT().
-		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
-			Username:   "john",
-			Successful: true,
-			Banned:     false,
-			Time:       s.mock.Clock.Now(),
-			Type:       regulation.AuthTypeTOTP,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
-		}))
-
-	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, uint64(1), nil)
 
 	s.mock.StorageMock.
 		EXPECT().
-		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any()).Return(errors.New("failed to perform update"))
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("failed to perform update"))
 
 	s.mock.Ctx.Configuration.DefaultRedirectionURL = testRedirectionURL
 
@@ -111,8 +104,8 @@ func (s *HandlerSignTOTPSuite) TestShouldNotReturnRedirectURL() {
 	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
 
 	s.mock.StorageMock.EXPECT().
-		LoadTOTPConfiguration(s.mock.Ctx, gomock.Any()).
-		Return(&config, nil)
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Any()).
+		Return([]model.TOTPConfiguration{config}, nil)
 
 	s.mock.StorageMock.
 		EXPECT().
@@ -125,11 +118,11 @@ func (s *HandlerSignTOTPSuite) TestShouldNotReturnRedirectURL() {
 			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
 		}))
 
-	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, uint64(1), nil)
 
 	s.mock.StorageMock.
 		EXPECT().
-		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any())
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Any())
 
 	bodyBytes, err := json.Marshal(signTOTPRequestBody{
 		Token: "abc",
@@ -145,8 +138,8 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToSafeTargetURL() {
 	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
 
 	s.mock.StorageMock.EXPECT().
-		LoadTOTPConfiguration(s.mock.Ctx, gomock.Any()).
-		Return(&config, nil)
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Any()).
+		Return([]model.TOTPConfiguration{config}, nil)
 
 	s.mock.StorageMock.
 		EXPECT().
@@ -159,11 +152,11 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToSafeTargetURL() {
 			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
 		}))
 
-	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, uint64(1), nil)
 
 	s.mock.StorageMock.
 		EXPECT().
-		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any())
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Any())
 
 	bodyBytes, err := json.Marshal(signTOTPRequestBody{
 		Token:     "abc",
@@ -181,8 +174,8 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToSafeTargetURL() {
 
 func (s *HandlerSignTOTPSuite) TestShouldNotRedirectToUnsafeURL() {
 	s.mock.StorageMock.EXPECT().
-		LoadTOTPConfiguration(s.mock.Ctx, "john").
-		Return(&model.TOTPConfiguration{Secret: []byte("secret")}, nil)
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, "john").
+		Return([]model.TOTPConfiguration{{Secret: []byte("secret")}}, nil)
 
 	s.mock.StorageMock.
 		EXPECT().
@@ -197,11 +190,11 @@ func (s *HandlerSignTOTPSuite) TestShouldNotRedirectToUnsafeURL() {
 
 	s.mock.StorageMock.
 		EXPECT().
-		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any())
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Any())
 
 	s.mock.TOTPMock.EXPECT().
 		Validate(gomock.Eq("abc"), gomock.Eq(&model.TOTPConfiguration{Secret: []byte("secret")})).
-		Return(true, nil)
+		Return(true, uint64(1), nil)
 
 	bodyBytes, err := json.Marshal(signTOTPRequestBody{
 		Token:     "abc",
@@ -219,8 +212,8 @@ func (s *HandlerSignTOTPSuite) TestShouldRegenerateSessionForPreventingSessionFi
 	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
 
 	s.mock.StorageMock.EXPECT().
-		LoadTOTPConfiguration(s.mock.Ctx, gomock.Any()).
-		Return(&config, nil)
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Any()).
+		Return([]model.TOTPConfiguration{config}, nil)
 
 	s.mock.StorageMock.
 		EXPECT().
@@ -235,11 +228,11 @@ func (s *HandlerSignTOTPSuite) TestShouldRegenerateSessionForPreventingSessionFi
 
 	s.mock.TOTPMock.EXPECT().
 		Validate(gomock.Eq("abc"), gomock.Eq(&config)).
-		Return(true, nil)
+		Return(true, uint64(1), nil)
 
 	s.mock.StorageMock.
 		EXPECT().
-		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any())
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Any())
 
 	bodyBytes, err := json.Marshal(signTOTPRequestBody{
 		Token: "abc",
@@ -258,6 +251,277 @@ func (s *HandlerSignTOTPSuite) TestShouldRegenerateSessionForPreventingSessionFi
 		string(s.mock.Ctx.Request.Header.Cookie("authelia_session")))
 }
 
+func (s *HandlerSignTOTPSuite) TestShouldInvalidatePreviousSessionIDAndKeepRememberMe() {
+	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
+
+	s.mock.Ctx.Providers.SessionProvider.RememberMe = time.Hour * 24 * 30
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.KeepMeLoggedIn = true
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+	s.Require().NoError(s.mock.Ctx.Providers.SessionProvider.UpdateExpiration(s.mock.Ctx.RequestCtx, s.mock.Ctx.Providers.SessionProvider.RememberMe))
+
+	oldID := string(s.mock.Ctx.Request.Header.Cookie("authelia_session"))
+	s.Require().NotEmpty(oldID)
+
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Any()).
+		Return([]model.TOTPConfiguration{config}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	s.mock.TOTPMock.EXPECT().
+		Validate(gomock.Eq("abc"), gomock.Eq(&config)).
+		Return(true, uint64(1), nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Any())
+
+	bodyBytes, err := json.Marshal(signTOTPRequestBody{
+		Token: "abc",
+	})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
+	s.mock.Assert200OK(s.T(), nil)
+
+	newID := string(s.mock.Ctx.Request.Header.Cookie("authelia_session"))
+	s.Assert().NotEqual(oldID, newID)
+
+	_, found, err := s.mock.Ctx.Providers.SessionProvider.GetSessionByID(oldID)
+	s.Require().NoError(err)
+	s.Assert().False(found)
+
+	userSession, found, err = s.mock.Ctx.Providers.SessionProvider.GetSessionByID(newID)
+	s.Require().NoError(err)
+	s.Require().True(found)
+	s.Assert().True(userSession.KeepMeLoggedIn)
+
+	// The cookie of a remembered session must keep its expiration when the session ID is regenerated.
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	s.Require().NoError(cookie.ParseBytes(s.mock.Ctx.Response.Header.PeekCookie("authelia_session")))
+	s.Assert().WithinDuration(time.Now().Add(time.Hour*24*30), cookie.Expire(), time.Minute)
+}
+
+func (s *HandlerSignTOTPSuite) TestShouldValidateAgainstEachRegistration() {
+	configs := []model.TOTPConfiguration{
+		{ID: 1, Username: "john", Description: "Primary", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"},
+		{ID: 2, Username: "john", Description: "Backup", Digits: 6, Secret: []byte("other"), Period: 30, Algorithm: "SHA1"},
+	}
+
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, "john").
+		Return(configs, nil)
+
+	gomock.InOrder(
+		s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&configs[0])).Return(false, uint64(0), nil),
+		s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&configs[1])).Return(true, uint64(10), nil),
+	)
+
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Eq(2), gomock.Any(), gomock.Eq(uint64(10)))
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "john",
+			Successful: true,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthTypeTOTP,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		}))
+
+	bodyBytes, err := json.Marshal(signTOTPRequestBody{
+		Token: "abc",
+	})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *HandlerSignTOTPSuite) TestShouldFailWhenNoRegistrationIsValid() {
+	configs := []model.TOTPConfiguration{
+		{ID: 1, Username: "john", Description: "Primary", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"},
+		{ID: 2, Username: "john", Description: "Backup", Digits: 6, Secret: []byte("other"), Period: 30, Algorithm: "SHA1"},
+	}
+
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, "john").
+		Return(configs, nil)
+
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Any()).Return(false, uint64(0), nil).Times(2)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "john",
+			Successful: false,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthTypeTOTP,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		}))
+
+	bodyBytes, err := json.Marshal(signTOTPRequestBody{
+		Token: "abc",
+	})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
+	s.mock.Assert401KO(s.T(), "Authentication failed, please retry later.")
+}
+
+func (s *HandlerSignTOTPSuite) TestShouldFailWhenTOTPCodeIsReplayed() {
+	config := model.TOTPConfiguration{ID: 1, Username: "john", Description: "Primary", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
+
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, "john").
+		Return([]model.TOTPConfiguration{config}, nil)
+
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, uint64(10), nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Eq(1), gomock.Any(), gomock.Eq(uint64(10))).
+		Return(storage.ErrTOTPStepAlreadyUsed)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "john",
+			Successful: false,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthTypeTOTP,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		}))
+
+	bodyBytes, err := json.Marshal(signTOTPRequestBody{
+		Token: "abc",
+	})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
+	s.mock.Assert401KO(s.T(), "Authentication failed, please retry later.")
+
+	entries := s.mock.Hook.AllEntries()
+	s.Require().Len(entries, 2)
+	s.Assert().Equal("User 'john' attempted to reuse a TOTP code for the registration 'Primary'", entries[0].Message)
+}
+
+func (s *HandlerSignTOTPSuite) TestShouldCountFailedAttemptsInStorage() {
+	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
+
+	s.mock.Ctx.Configuration.Regulation.SecondFactor = schema.RegulationSecondFactorConfiguration{
+		MaxAttempts: 3,
+		Action:      schema.SecondFactorLimitActionDestroySession,
+	}
+
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, "john").
+		Return([]model.TOTPConfiguration{config}, nil)
+
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(false, uint64(0), nil)
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.FirstFactorAuthnTimestamp = s.mock.Clock.Now().Add(-time.Minute).Unix()
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Any()),
+		s.mock.StorageMock.
+			EXPECT().
+			CountSecondFactorFailedAuthenticationLogs(s.mock.Ctx, "john", time.Unix(userSession.FirstFactorAuthnTimestamp, 0)).
+			Return(2, nil),
+	)
+
+	bodyBytes, err := json.Marshal(signTOTPRequestBody{
+		Token: "abc",
+	})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
+	s.mock.Assert401KO(s.T(), "Authentication failed, please retry later.")
+
+	userSession = s.mock.Ctx.GetSession()
+	s.Assert().Equal("john", userSession.Username)
+}
+
+func (s *HandlerSignTOTPSuite) TestShouldBanAndDestroySessionWhenMaxFailedAttemptsReached() {
+	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
+
+	s.mock.Ctx.Configuration.Regulation.BanTime = time.Minute * 5
+	s.mock.Ctx.Configuration.Regulation.SecondFactor = schema.RegulationSecondFactorConfiguration{
+		MaxAttempts: 3,
+		Action:      schema.SecondFactorLimitActionBan,
+	}
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.FirstFactorAuthnTimestamp = s.mock.Clock.Now().Add(-time.Minute).Unix()
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, "john").
+		Return([]model.TOTPConfiguration{config}, nil)
+
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(false, uint64(0), nil)
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   "john",
+				Successful: false,
+				Banned:     false,
+				Time:       s.mock.Clock.Now(),
+				Type:       regulation.AuthTypeTOTP,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})),
+		s.mock.StorageMock.
+			EXPECT().
+			CountSecondFactorFailedAuthenticationLogs(s.mock.Ctx, "john", time.Unix(userSession.FirstFactorAuthnTimestamp, 0)).
+			Return(3, nil),
+		s.mock.StorageMock.
+			EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   "john",
+				Successful: false,
+				Banned:     true,
+				Time:       s.mock.Clock.Now(),
+				Type:       regulation.AuthTypeSecondFactorLimit,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})),
+	)
+
+	bodyBytes, err := json.Marshal(signTOTPRequestBody{
+		Token: "abc",
+	})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
+	s.mock.Assert401KO(s.T(), "Authentication failed, please retry later.")
+
+	userSession = s.mock.Ctx.GetSession()
+	s.Assert().Equal("", userSession.Username)
+}
+
 func TestRunHandlerSignTOTPSuite(t *testing.T) {
 	suite.Run(t, new(HandlerSignTOTPSuite))
 }
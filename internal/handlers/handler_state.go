@@ -1,16 +1,39 @@
 package handlers
 
 import (
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
 )
 
 // StateGET is the handler serving the user state.
 func StateGET(ctx *middlewares.AutheliaCtx) {
 	userSession := ctx.GetSession()
+
+	if userSession.Username == "" {
+		userSession = stateTrustedJWT(ctx, userSession)
+	}
+
 	stateResponse := StateResponse{
 		Username:              userSession.Username,
 		AuthenticationLevel:   userSession.AuthenticationLevel,
-		DefaultRedirectionURL: ctx.Configuration.DefaultRedirectionURL,
+		DefaultRedirectionURL: getDefaultRedirectionURL(ctx),
+		Capabilities:          NewCapabilities(&ctx.Configuration),
+	}
+
+	if remaining, expires := ctx.Providers.SessionProvider.GetRemainingTime(userSession, ctx.Clock.Now()); expires {
+		seconds := int64(remaining.Seconds())
+
+		stateResponse.SessionRemaining = &seconds
+		stateResponse.InactivityWarning = int64(ctx.Configuration.Session.InactivityWarning.Seconds())
+	}
+
+	if userSession.CAPTCHARequired && ctx.Providers.CAPTCHA != nil {
+		stateResponse.CAPTCHA = &StateCAPTCHAResponse{
+			Provider: ctx.Providers.CAPTCHA.Provider(),
+			SiteKey:  ctx.Providers.CAPTCHA.SiteKey(),
+		}
 	}
 
 	err := ctx.SetJSONBody(stateResponse)
@@ -18,3 +41,66 @@ func StateGET(ctx *middlewares.AutheliaCtx) {
 		ctx.Logger.Errorf("Unable to set state response in body: %s", err)
 	}
 }
+
+// stateTrustedJWT establishes a one factor session for an anonymous user who presented a valid JWT forwarded by a
+// trusted upstream identity provider. The anonymous session is replaced with a regenerated one. The original session is
+// returned if no JWT was presented or if it failed verification.
+func stateTrustedJWT(ctx *middlewares.AutheliaCtx, userSession session.UserSession) session.UserSession {
+	details, err := verifyTrustedJWT(ctx)
+
+	switch {
+	case err != nil:
+		ctx.Logger.Errorf("Unable to verify the trusted JWT: %+v", err)
+
+		return userSession
+	case details == nil:
+		return userSession
+	}
+
+	if isUserDisabled(ctx, details.Username) {
+		_ = markAuthenticationAttempt(ctx, false, nil, details.Username, regulation.AuthTypeTrustedJWT, authentication.ErrUserDisabled)
+
+		return userSession
+	}
+
+	if err = markAuthenticationAttempt(ctx, true, nil, details.Username, regulation.AuthTypeTrustedJWT, nil); err != nil {
+		return userSession
+	}
+
+	newSession := session.NewDefaultUserSession()
+	newSession.ConsentChallengeID = userSession.ConsentChallengeID
+
+	if err = ctx.SaveSession(newSession); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionReset, regulation.AuthTypeTrustedJWT, details.Username, err)
+
+		return userSession
+	}
+
+	if err = ctx.Providers.SessionProvider.RegenerateSession(ctx.RequestCtx); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionRegenerate, regulation.AuthTypeTrustedJWT, details.Username, err)
+
+		return userSession
+	}
+
+	if err = ctx.Providers.SessionProvider.RegisterUserSession(ctx.RequestCtx, details.Username, false); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionRegister, regulation.AuthTypeTrustedJWT, details.Username, err)
+
+		return userSession
+	}
+
+	newSession.SetOneFactorTrustedJWT(ctx.Clock.Now(), details)
+
+	bindSession(ctx, &newSession)
+
+	if refresh, refreshInterval := getProfileRefreshSettings(ctx.Configuration.AuthenticationBackend); refresh {
+		newSession.RefreshTTL = ctx.Clock.Now().Add(refreshInterval)
+	}
+
+	if err = ctx.SaveSession(newSession); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionSave, "updated profile", regulation.AuthTypeTrustedJWT, details.Username, err)
+
+		return userSession
+	}
+
+	return newSession
+}
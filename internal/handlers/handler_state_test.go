@@ -2,14 +2,21 @@ package handlers
 
 import (
 	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
 )
 
 type StateGetSuite struct {
@@ -45,6 +52,7 @@ func (s *StateGetSuite) TestShouldReturnUsernameFromSession() {
 			Username:              "username",
 			DefaultRedirectionURL: "",
 			AuthenticationLevel:   authentication.NotAuthenticated,
+			Capabilities:          NewCapabilities(&s.mock.Ctx.Configuration),
 		},
 	}
 	actualBody := Response{}
@@ -75,6 +83,7 @@ func (s *StateGetSuite) TestShouldReturnAuthenticationLevelFromSession() {
 			Username:              "",
 			DefaultRedirectionURL: "",
 			AuthenticationLevel:   authentication.OneFactor,
+			Capabilities:          NewCapabilities(&s.mock.Ctx.Configuration),
 		},
 	}
 	actualBody := Response{}
@@ -86,6 +95,125 @@ func (s *StateGetSuite) TestShouldReturnAuthenticationLevelFromSession() {
 	assert.Equal(s.T(), expectedBody, actualBody)
 }
 
+func (s *StateGetSuite) TestShouldNotEstablishSessionFromClientCertificate() {
+	verifier, certificate := newTestClientCertificateVerifier(s.T(), time.Now())
+	s.mock.Ctx.Providers.ClientCertificate = verifier
+	s.mock.Ctx.SetRemoteAddr(&net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 443})
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Tls-Client-Cert", certificate)
+
+	StateGET(s.mock.Ctx)
+
+	actualBody := struct {
+		Status string
+		Data   StateResponse
+	}{}
+
+	err := json.Unmarshal(s.mock.Ctx.Response.Body(), &actualBody)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "", actualBody.Data.Username)
+	assert.Equal(s.T(), authentication.NotAuthenticated, actualBody.Data.AuthenticationLevel)
+
+	userSession := s.mock.Ctx.GetSession()
+	assert.Equal(s.T(), "", userSession.Username)
+}
+
+func (s *StateGetSuite) TestShouldEstablishSessionFromTrustedJWT() {
+	s.mock.Clock.Set(time.Now())
+
+	verifier, token := newTestTrustedJWTVerifier(s.T(), s.mock.Clock.Now())
+	s.mock.Ctx.Providers.TrustedJWT = verifier
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-JWT", token)
+
+	s.mock.StorageMock.EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	StateGET(s.mock.Ctx)
+
+	actualBody := struct {
+		Status string
+		Data   StateResponse
+	}{}
+
+	err := json.Unmarshal(s.mock.Ctx.Response.Body(), &actualBody)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), testUsername, actualBody.Data.Username)
+	assert.Equal(s.T(), authentication.OneFactor, actualBody.Data.AuthenticationLevel)
+
+	userSession := s.mock.Ctx.GetSession()
+	assert.Equal(s.T(), testUsername, userSession.Username)
+	assert.Equal(s.T(), []string{"dev"}, userSession.Groups)
+	assert.True(s.T(), userSession.AuthenticationMethodRefs.TrustedJWT)
+}
+
+func (s *StateGetSuite) TestShouldNotEstablishSessionFromTrustedJWTWhenUserDisabled() {
+	s.mock.Clock.Set(time.Now())
+
+	path := filepath.Join(s.T().TempDir(), "users.yml")
+	s.Require().NoError(os.WriteFile(path, []byte(`users:
+  john:
+    displayname: "John Doe"
+    password: "$argon2id$v=19$m=32768,t=1,p=8$eUhVT1dQa082YVk2VUhDMQ$E8QI4jHbUBt3EdsU1NFDu4Bq5jObKNx7nBKSn1EYQxk"
+    email: john.doe@example.com
+    disabled: true
+`), 0600))
+
+	s.mock.Ctx.Providers.UserProvider = authentication.NewFileUserProvider(&schema.FileAuthenticationBackendConfiguration{
+		Path:     path,
+		Password: &schema.DefaultPasswordConfiguration,
+	})
+
+	verifier, token := newTestTrustedJWTVerifier(s.T(), s.mock.Clock.Now())
+	s.mock.Ctx.Providers.TrustedJWT = verifier
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-JWT", token)
+
+	s.mock.StorageMock.EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ interface{}, attempt model.AuthenticationAttempt) error {
+			s.Assert().False(attempt.Successful)
+			return nil
+		})
+
+	StateGET(s.mock.Ctx)
+
+	actualBody := struct {
+		Status string
+		Data   StateResponse
+	}{}
+
+	err := json.Unmarshal(s.mock.Ctx.Response.Body(), &actualBody)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "", actualBody.Data.Username)
+	assert.Equal(s.T(), authentication.NotAuthenticated, actualBody.Data.AuthenticationLevel)
+
+	userSession := s.mock.Ctx.GetSession()
+	assert.Equal(s.T(), "", userSession.Username)
+}
+
+func (s *StateGetSuite) TestShouldReturnCAPTCHAWhenRequired() {
+	captchaMock := mocks.NewMockCAPTCHAProvider(s.mock.Ctrl)
+	s.mock.Ctx.Providers.CAPTCHA = captchaMock
+
+	captchaMock.EXPECT().Provider().Return("turnstile")
+	captchaMock.EXPECT().SiteKey().Return("site")
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.CAPTCHARequired = true
+	require.NoError(s.T(), s.mock.Ctx.SaveSession(userSession))
+
+	StateGET(s.mock.Ctx)
+
+	type Response struct {
+		Status string
+		Data   StateResponse
+	}
+
+	actualBody := Response{}
+
+	require.NoError(s.T(), json.Unmarshal(s.mock.Ctx.Response.Body(), &actualBody))
+	assert.Equal(s.T(), &StateCAPTCHAResponse{Provider: "turnstile", SiteKey: "site"}, actualBody.Data.CAPTCHA)
+}
+
 func TestRunStateGetSuite(t *testing.T) {
 	s := new(StateGetSuite)
 	suite.Run(t, s)
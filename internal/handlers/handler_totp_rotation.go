@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// TOTPRotationStartPOST generates a new secret for a TOTP registration of the user once they have proven possession of
+// the current secret with one of its codes. The new secret is kept in the session encrypted with the storage
+// encryption key and the registration continues to use the current secret until the new one is confirmed with
+// TOTPRotationFinishPOST.
+func TOTPRotationStartPOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		bodyJSON totpRotationStartRequestBody
+		configs  []model.TOTPConfiguration
+		current  *model.TOTPConfiguration
+		config   *model.TOTPConfiguration
+		valid    bool
+		step     uint64
+		err      error
+	)
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Error(err, messageUnableToRegisterOneTimePassword)
+		return
+	}
+
+	userSession := ctx.GetSession()
+
+	if configs, err = ctx.Providers.StorageProvider.LoadTOTPConfigurationsByUsername(ctx, userSession.Username); err != nil && !errors.Is(err, storage.ErrNoTOTPConfiguration) {
+		ctx.Error(fmt.Errorf("unable to load the TOTP configurations of user '%s': %w", userSession.Username, err), messageUnableToRegisterOneTimePassword)
+		return
+	}
+
+	for i := range configs {
+		if configs[i].ID == bodyJSON.ID {
+			current = &configs[i]
+			break
+		}
+	}
+
+	if current == nil {
+		ctx.Error(fmt.Errorf("user '%s' attempted to rotate the TOTP registration with id '%d' which does not exist", userSession.Username, bodyJSON.ID), messageUnableToRegisterOneTimePassword)
+		return
+	}
+
+	if valid, step, err = ctx.Providers.TOTP.Validate(bodyJSON.Token, current); err != nil {
+		ctx.Error(fmt.Errorf("unable to validate the %s code of user '%s' for the registration '%s': %w", regulation.AuthTypeTOTP, userSession.Username, current.Description, err), messageMFAValidationFailed)
+		return
+	}
+
+	if !valid {
+		ctx.Logger.Warnf("User '%s' provided an invalid %s code to rotate the registration '%s'", userSession.Username, regulation.AuthTypeTOTP, current.Description)
+
+		_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeTOTP, nil)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	current.UpdateSignInInfo(ctx.Clock.Now(), step)
+
+	// The code is consumed so it can't be replayed to sign in or to start another rotation.
+	if err = ctx.Providers.StorageProvider.UpdateTOTPConfigurationSignIn(ctx, current.ID, current.LastUsedAt, step); err != nil {
+		if errors.Is(err, storage.ErrTOTPStepAlreadyUsed) {
+			ctx.Logger.Errorf("User '%s' attempted to reuse a %s code to rotate the registration '%s'", userSession.Username, regulation.AuthTypeTOTP, current.Description)
+
+			_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeTOTP, nil)
+		} else {
+			ctx.Logger.Errorf("Unable to save %s device sign in metadata for user '%s': %v", regulation.AuthTypeTOTP, userSession.Username, err)
+		}
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	if config, err = ctx.Providers.TOTP.Generate(userSession.Username); err != nil {
+		ctx.Error(fmt.Errorf("unable to generate TOTP key: %w", err), messageUnableToRegisterOneTimePassword)
+		return
+	}
+
+	config.Description = current.Description
+
+	var secret []byte
+
+	if secret, err = encryptTOTPRotationSecret(ctx, config.Secret); err != nil {
+		ctx.Error(fmt.Errorf("unable to encrypt the TOTP rotation secret of user '%s': %w", userSession.Username, err), messageUnableToRegisterOneTimePassword)
+		return
+	}
+
+	userSession.TOTPRotation = &session.TOTPRotation{
+		Description: config.Description,
+		Issuer:      config.Issuer,
+		Algorithm:   config.Algorithm,
+		Digits:      config.Digits,
+		Period:      config.Period,
+		Secret:      secret,
+		Expires:     ctx.Clock.Now().Add(totpRotationLifespan),
+	}
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Error(fmt.Errorf("unable to save the TOTP rotation of user '%s' in the session: %w", userSession.Username, err), messageUnableToRegisterOneTimePassword)
+		return
+	}
+
+	ctx.Logger.Infof("User '%s' started the rotation of the %s registration '%s'", userSession.Username, regulation.AuthTypeTOTP, current.Description)
+
+	response := TOTPKeyResponse{
+		OTPAuthURL:   config.URIWithAccountName(totpAccountLabel(ctx, userSession.Username)),
+		Base32Secret: string(config.Secret),
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.Errorf("Unable to set TOTP key response in body: %s", err)
+	}
+}
+
+// TOTPRotationFinishPOST replaces the secret of the TOTP registration being rotated with the new secret once the user
+// confirms a code generated with it. The registration is replaced in a single statement so the current secret keeps
+// working until the new one is saved, and the step of the confirmed code is recorded so it can't be replayed.
+func TOTPRotationFinishPOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		bodyJSON totpRotationFinishRequestBody
+		secret   []byte
+		valid    bool
+		step     uint64
+		err      error
+	)
+
+	userSession := ctx.GetSession()
+
+	rotation := userSession.TOTPRotation
+
+	switch {
+	case rotation == nil:
+		ctx.Error(fmt.Errorf("user '%s' attempted to finish a TOTP rotation which was not started", userSession.Username), messageUnableToRegisterOneTimePassword)
+		return
+	case ctx.Clock.Now().After(rotation.Expires):
+		ctx.Error(fmt.Errorf("user '%s' attempted to finish a TOTP rotation which expired at %s", userSession.Username, rotation.Expires), messageUnableToRegisterOneTimePassword)
+		return
+	}
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Error(err, messageUnableToRegisterOneTimePassword)
+		return
+	}
+
+	if secret, err = decryptTOTPRotationSecret(ctx, rotation.Secret); err != nil {
+		ctx.Error(fmt.Errorf("unable to decrypt the TOTP rotation secret of user '%s': %w", userSession.Username, err), messageUnableToRegisterOneTimePassword)
+		return
+	}
+
+	config := model.TOTPConfiguration{
+		CreatedAt:   ctx.Clock.Now(),
+		Username:    userSession.Username,
+		Description: rotation.Description,
+		Issuer:      rotation.Issuer,
+		Algorithm:   rotation.Algorithm,
+		Digits:      rotation.Digits,
+		Period:      rotation.Period,
+		Secret:      secret,
+	}
+
+	if valid, step, err = ctx.Providers.TOTP.Validate(bodyJSON.Token, &config); err != nil {
+		ctx.Error(fmt.Errorf("unable to validate the %s code of user '%s' for the new secret of the registration '%s': %w", regulation.AuthTypeTOTP, userSession.Username, config.Description, err), messageMFAValidationFailed)
+		return
+	}
+
+	if !valid {
+		ctx.Logger.Warnf("User '%s' provided an invalid %s code to confirm the new secret of the registration '%s'", userSession.Username, regulation.AuthTypeTOTP, config.Description)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	config.UpdateSignInInfo(ctx.Clock.Now(), step)
+
+	// Saving the configuration with the description of the registration replaces its secret.
+	if err = ctx.Providers.StorageProvider.SaveTOTPConfiguration(ctx, config); err != nil {
+		ctx.Error(fmt.Errorf("unable to save TOTP secret in DB: %w", err), messageUnableToRegisterOneTimePassword)
+		return
+	}
+
+	userSession.TOTPRotation = nil
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.Errorf("Unable to remove the TOTP rotation of user '%s' from the session: %+v", userSession.Username, err)
+	}
+
+	ctx.Logger.Infof("User '%s' rotated the secret of the %s registration '%s'", userSession.Username, regulation.AuthTypeTOTP, config.Description)
+
+	ctx.ReplyOK()
+}
+
+// encryptTOTPRotationSecret encrypts the secret of a TOTP rotation with the storage encryption key so it's not stored in
+// the clear in the session.
+func encryptTOTPRotationSecret(ctx *middlewares.AutheliaCtx, secret []byte) (ciphertext []byte, err error) {
+	key := sha256.Sum256([]byte(ctx.Configuration.Storage.EncryptionKey))
+
+	return utils.Encrypt(secret, &key)
+}
+
+// decryptTOTPRotationSecret decrypts the secret of a TOTP rotation encrypted with encryptTOTPRotationSecret.
+func decryptTOTPRotationSecret(ctx *middlewares.AutheliaCtx, ciphertext []byte) (secret []byte, err error) {
+	key := sha256.Sum256([]byte(ctx.Configuration.Storage.EncryptionKey))
+
+	return utils.Decrypt(ciphertext, &key)
+}
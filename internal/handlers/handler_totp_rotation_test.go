@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+type TOTPRotationSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *TOTPRotationSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Ctx.Clock = &s.mock.Clock
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.OneFactor
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *TOTPRotationSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *TOTPRotationSuite) currentConfigs() []model.TOTPConfiguration {
+	return []model.TOTPConfiguration{
+		{ID: 1, Username: testUsername, Description: "Primary", Issuer: "Authelia", Algorithm: "SHA1", Digits: 6, Period: 30, Secret: []byte("CURRENT")},
+		{ID: 4, Username: testUsername, Description: "Backup", Issuer: "Authelia", Algorithm: "SHA1", Digits: 6, Period: 30, Secret: []byte("BACKUP")},
+	}
+}
+
+func (s *TOTPRotationSuite) newConfig() *model.TOTPConfiguration {
+	return &model.TOTPConfiguration{Username: testUsername, Issuer: "Authelia", Algorithm: "SHA1", Digits: 6, Period: 30, Secret: []byte("ROTATED")}
+}
+
+func (s *TOTPRotationSuite) TestShouldStartRotationWithCodeOfCurrentSecret() {
+	configs := s.currentConfigs()
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(configs, nil),
+		s.mock.TOTPMock.EXPECT().
+			Validate(gomock.Eq("123456"), gomock.Eq(&configs[1])).
+			Return(true, uint64(54321), nil),
+		s.mock.StorageMock.EXPECT().
+			UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Eq(4), gomock.Any(), gomock.Eq(uint64(54321))).
+			Return(nil),
+		s.mock.TOTPMock.EXPECT().
+			Generate(gomock.Eq(testUsername)).
+			Return(s.newConfig(), nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"id":4,"token":"123456"}`)
+
+	TOTPRotationStartPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), TOTPKeyResponse{
+		OTPAuthURL:   "otpauth://totp/Authelia:john?algorithm=SHA1&digits=6&issuer=Authelia&period=30&secret=ROTATED",
+		Base32Secret: "ROTATED",
+	})
+
+	rotation := s.mock.Ctx.GetSession().TOTPRotation
+
+	s.Require().NotNil(rotation)
+	s.Assert().Equal("Backup", rotation.Description)
+	s.Assert().NotContains(string(rotation.Secret), "ROTATED")
+
+	secret, err := decryptTOTPRotationSecret(s.mock.Ctx, rotation.Secret)
+	s.Require().NoError(err)
+	s.Assert().Equal([]byte("ROTATED"), secret)
+	s.Assert().Equal(s.mock.Clock.Now().Add(totpRotationLifespan), rotation.Expires)
+}
+
+func (s *TOTPRotationSuite) TestShouldNotStartRotationWithInvalidCode() {
+	configs := s.currentConfigs()
+
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return(configs, nil)
+
+	s.mock.TOTPMock.EXPECT().
+		Validate(gomock.Eq("123456"), gomock.Eq(&configs[0])).
+		Return(false, uint64(0), nil)
+
+	s.mock.StorageMock.EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"id":1,"token":"123456"}`)
+
+	TOTPRotationStartPOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageMFAValidationFailed)
+	s.Assert().Nil(s.mock.Ctx.GetSession().TOTPRotation)
+}
+
+func (s *TOTPRotationSuite) TestShouldNotStartRotationOfUnknownRegistration() {
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return(s.currentConfigs(), nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"id":2,"token":"123456"}`)
+
+	TOTPRotationStartPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToRegisterOneTimePassword)
+	s.Assert().Equal("user 'john' attempted to rotate the TOTP registration with id '2' which does not exist", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *TOTPRotationSuite) TestShouldFinishRotationWithCodeOfNewSecret() {
+	s.setRotation(s.mock.Clock.Now().Add(time.Minute))
+
+	expected := *s.newConfig()
+	expected.CreatedAt = s.mock.Clock.Now()
+	expected.Description = "Backup"
+
+	// The step of the confirmation code is saved with the registration so the code can't be replayed.
+	saved := expected
+	saved.UpdateSignInInfo(s.mock.Clock.Now(), 54322)
+
+	gomock.InOrder(
+		s.mock.TOTPMock.EXPECT().
+			Validate(gomock.Eq("654321"), gomock.Eq(&expected)).
+			Return(true, uint64(54322), nil),
+		s.mock.StorageMock.EXPECT().
+			SaveTOTPConfiguration(s.mock.Ctx, gomock.Eq(saved)).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"token":"654321"}`)
+
+	TOTPRotationFinishPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Assert().Nil(s.mock.Ctx.GetSession().TOTPRotation)
+}
+
+func (s *TOTPRotationSuite) TestShouldKeepCurrentSecretWhenNewSecretIsNotConfirmed() {
+	s.setRotation(s.mock.Clock.Now().Add(time.Minute))
+
+	// The registration must not be saved so the current secret keeps working.
+	s.mock.TOTPMock.EXPECT().
+		Validate(gomock.Eq("654321"), gomock.Any()).
+		Return(false, uint64(0), nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"token":"654321"}`)
+
+	TOTPRotationFinishPOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageMFAValidationFailed)
+	s.Assert().NotNil(s.mock.Ctx.GetSession().TOTPRotation)
+}
+
+func (s *TOTPRotationSuite) TestShouldNotFinishExpiredRotation() {
+	s.setRotation(s.mock.Clock.Now().Add(-time.Second))
+
+	s.mock.Ctx.Request.SetBodyString(`{"token":"654321"}`)
+
+	TOTPRotationFinishPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToRegisterOneTimePassword)
+}
+
+func (s *TOTPRotationSuite) TestShouldNotFinishRotationWhichWasNotStarted() {
+	s.mock.Ctx.Request.SetBodyString(`{"token":"654321"}`)
+
+	TOTPRotationFinishPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageUnableToRegisterOneTimePassword)
+	s.Assert().Equal("user 'john' attempted to finish a TOTP rotation which was not started", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *TOTPRotationSuite) setRotation(expires time.Time) {
+	config := s.newConfig()
+
+	secret, err := encryptTOTPRotationSecret(s.mock.Ctx, config.Secret)
+	s.Require().NoError(err)
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.TOTPRotation = &session.TOTPRotation{
+		Description: "Backup",
+		Issuer:      config.Issuer,
+		Algorithm:   config.Algorithm,
+		Digits:      config.Digits,
+		Period:      config.Period,
+		Secret:      secret,
+		Expires:     expires,
+	}
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func TestRunTOTPRotationSuite(t *testing.T) {
+	suite.Run(t, new(TOTPRotationSuite))
+}
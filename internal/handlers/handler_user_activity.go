@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+)
+
+// UserActivityGET returns the recent authentication activity and the active OpenID Connect grants of the user
+// identified by the session. The authentication activity is paginated with the page and limit query arguments.
+func UserActivityGET(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	page, err := getUserActivityQueryArg(ctx, "page", 0)
+	if err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	if page > userActivityPageMaximum {
+		ctx.Error(fmt.Errorf("query argument 'page' must be between 0 and %d but it is '%d'", userActivityPageMaximum, page), messageOperationFailed)
+		return
+	}
+
+	limit, err := getUserActivityQueryArg(ctx, "limit", userActivityLimitDefault)
+	if err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	if limit == 0 || limit > userActivityLimitMaximum {
+		ctx.Error(fmt.Errorf("query argument 'limit' must be between 1 and %d but it is '%d'", userActivityLimitMaximum, limit), messageOperationFailed)
+		return
+	}
+
+	attempts, err := ctx.Providers.StorageProvider.LoadUserAuthenticationLogs(ctx, userSession.Username, limit, page)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to load the authentication logs of user '%s': %w", userSession.Username, err), messageOperationFailed)
+		return
+	}
+
+	consents, err := ctx.Providers.StorageProvider.LoadOAuth2ConsentSessionsActive(ctx, userSession.Username)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to load the active grants of user '%s': %w", userSession.Username, err), messageOperationFailed)
+		return
+	}
+
+	response := userActivityResponse{
+		Page:                 page,
+		Limit:                limit,
+		AuthenticationEvents: make([]userActivityAuthenticationEvent, len(attempts)),
+		Grants:               make([]userActivityGrant, len(consents)),
+	}
+
+	for i, attempt := range attempts {
+		response.AuthenticationEvents[i] = userActivityAuthenticationEvent{
+			Time:       attempt.Time,
+			Type:       attempt.Type,
+			Successful: attempt.Successful,
+			Banned:     attempt.Banned,
+			UserAgent:  attempt.UserAgent,
+		}
+
+		if attempt.RemoteIP.IP != nil {
+			response.AuthenticationEvents[i].RemoteIP = attempt.RemoteIP.IP.String()
+		}
+	}
+
+	for i, consent := range consents {
+		response.Grants[i] = userActivityGrant{
+			ClientID:  consent.ClientID,
+			Scopes:    consent.GrantedScopes,
+			Audience:  consent.GrantedAudience,
+			GrantedAt: consent.RespondedAt,
+			ExpiresAt: consent.ExpiresAt,
+		}
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.Errorf("Unable to set user activity response in body: %s", err)
+	}
+}
+
+func getUserActivityQueryArg(ctx *middlewares.AutheliaCtx, key string, fallback int) (value int, err error) {
+	if value, err = ctx.QueryArgs().GetUint(key); err != nil {
+		if errors.Is(err, fasthttp.ErrNoArgValue) {
+			return fallback, nil
+		}
+
+		return 0, fmt.Errorf("query argument '%s' must be a positive integer but it is '%s'", key, ctx.QueryArgs().Peek(key))
+	}
+
+	return value, nil
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+type UserActivitySuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *UserActivitySuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *UserActivitySuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *UserActivitySuite) TestShouldReturnActivity() {
+	attemptAt := time.Unix(1640000000, 0).UTC()
+	grantedAt := time.Unix(1640000100, 0).UTC()
+	expiresAt := time.Unix(1650000000, 0).UTC()
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadUserAuthenticationLogs(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(20), gomock.Eq(0)).
+			Return([]model.AuthenticationAttempt{
+				{Time: attemptAt, Successful: true, Username: testUsername, Type: "1FA", RemoteIP: model.NewNullIP(net.ParseIP("192.168.0.1")), UserAgent: "Mozilla/5.0"},
+				{Time: attemptAt, Username: testUsername, Type: "TOTP"},
+			}, nil),
+		s.mock.StorageMock.EXPECT().
+			LoadOAuth2ConsentSessionsActive(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return([]model.OAuth2ConsentSession{
+				{ClientID: "example", GrantedScopes: []string{"openid", "profile"}, GrantedAudience: []string{"example"}, RespondedAt: &grantedAt, ExpiresAt: &expiresAt},
+			}, nil),
+	)
+
+	UserActivityGET(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), userActivityResponse{
+		Page:  0,
+		Limit: 20,
+		AuthenticationEvents: []userActivityAuthenticationEvent{
+			{Time: attemptAt, Type: "1FA", Successful: true, RemoteIP: "192.168.0.1", UserAgent: "Mozilla/5.0"},
+			{Time: attemptAt, Type: "TOTP"},
+		},
+		Grants: []userActivityGrant{
+			{ClientID: "example", Scopes: []string{"openid", "profile"}, Audience: []string{"example"}, GrantedAt: &grantedAt, ExpiresAt: &expiresAt},
+		},
+	})
+}
+
+func (s *UserActivitySuite) TestShouldReturnEmptyActivityForRequestedPage() {
+	s.mock.Ctx.QueryArgs().Add("page", "2")
+	s.mock.Ctx.QueryArgs().Add("limit", "50")
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadUserAuthenticationLogs(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(50), gomock.Eq(2)).
+			Return(nil, nil),
+		s.mock.StorageMock.EXPECT().
+			LoadOAuth2ConsentSessionsActive(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return(nil, nil),
+	)
+
+	UserActivityGET(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), userActivityResponse{
+		Page:                 2,
+		Limit:                50,
+		AuthenticationEvents: []userActivityAuthenticationEvent{},
+		Grants:               []userActivityGrant{},
+	})
+}
+
+func (s *UserActivitySuite) TestShouldFailOnInvalidPage() {
+	s.mock.Ctx.QueryArgs().Add("page", "abc")
+
+	UserActivityGET(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Assert().Equal("query argument 'page' must be a positive integer but it is 'abc'", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *UserActivitySuite) TestShouldFailOnPageTooLarge() {
+	s.mock.Ctx.QueryArgs().Add("page", "9223372036854775807")
+
+	UserActivityGET(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Assert().Equal("query argument 'page' must be between 0 and 10000 but it is '9223372036854775807'", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *UserActivitySuite) TestShouldFailOnLimitTooLarge() {
+	s.mock.Ctx.QueryArgs().Add("limit", "101")
+
+	UserActivityGET(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Assert().Equal("query argument 'limit' must be between 1 and 100 but it is '101'", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *UserActivitySuite) TestShouldFailOnStorageError() {
+	s.mock.StorageMock.EXPECT().
+		LoadUserAuthenticationLogs(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(20), gomock.Eq(0)).
+		Return(nil, errors.New("failed to connect"))
+
+	UserActivityGET(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Assert().Equal("unable to load the authentication logs of user 'john': failed to connect", s.mock.Hook.LastEntry().Message)
+}
+
+func TestRunUserActivitySuite(t *testing.T) {
+	s := new(UserActivitySuite)
+	suite.Run(t, s)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// UserDisabledPOST handler for disabling or enabling a user. Disabled users can't complete the first factor and their
+// existing sessions are rejected.
+func UserDisabledPOST(ctx *middlewares.AutheliaCtx) {
+	if !isUserAdministrator(ctx) {
+		ctx.ReplyForbidden()
+		return
+	}
+
+	var bodyJSON userDisabledRequestBody
+
+	if err := ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	provider, ok := ctx.Providers.UserProvider.(authentication.UserDisableProvider)
+	if !ok {
+		ctx.Error(fmt.Errorf("the user provider does not support disabling users"), messageOperationFailed)
+		return
+	}
+
+	userSession := ctx.GetSession()
+
+	if bodyJSON.Disabled && bodyJSON.Username == userSession.Username {
+		ctx.Error(fmt.Errorf("user '%s' attempted to disable their own account", userSession.Username), messageOperationFailed)
+		return
+	}
+
+	if err := provider.SetUserDisabled(bodyJSON.Username, bodyJSON.Disabled); err != nil {
+		ctx.Error(fmt.Errorf("unable to update the disabled state of user '%s': %w", bodyJSON.Username, err), messageOperationFailed)
+		return
+	}
+
+	if bodyJSON.Disabled {
+		ctx.Logger.Infof("User '%s' has been disabled by '%s'", bodyJSON.Username, userSession.Username)
+	} else {
+		ctx.Logger.Infof("User '%s' has been enabled by '%s'", bodyJSON.Username, userSession.Username)
+	}
+
+	ctx.ReplyOK()
+}
+
+// isUserAdministrator returns true if the current user has authenticated with two factors and is a member of the
+// configured authentication backend administrator group.
+func isUserAdministrator(ctx *middlewares.AutheliaCtx) bool {
+	group := ctx.Configuration.AuthenticationBackend.AdminGroup
+
+	if group == "" {
+		return false
+	}
+
+	userSession := ctx.GetSession()
+
+	return userSession.AuthenticationLevel >= authentication.TwoFactor &&
+		utils.IsStringInSlice(group, userSession.Groups)
+}
+
+// isUserDisabled returns true if the user has been disabled in the user provider. The user is treated as disabled when
+// it can't be determined to prevent bypassing the check.
+func isUserDisabled(ctx *middlewares.AutheliaCtx, username string) bool {
+	disabled, err := authentication.IsUserDisabled(ctx.Providers.UserProvider, username)
+	if err != nil {
+		ctx.Logger.Errorf("Unable to check if user '%s' is disabled: %v", username, err)
+
+		return true
+	}
+
+	return disabled
+}
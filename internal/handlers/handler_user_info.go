@@ -88,7 +88,7 @@ func MethodPreferencePOST(ctx *middlewares.AutheliaCtx) {
 	}
 
 	userSession := ctx.GetSession()
-	ctx.Logger.Debugf("Save new preferred 2FA method of user %s to %s", userSession.Username, bodyJSON.Method)
+	ctx.Logger.Debugf("Save new preferred 2FA method of user '%s' to %s", userSession.Username, bodyJSON.Method)
 	err = ctx.Providers.StorageProvider.SavePreferred2FAMethod(ctx, userSession.Username, bodyJSON.Method)
 
 	if err != nil {
@@ -2,18 +2,20 @@ package handlers
 
 import (
 	"errors"
+	"strconv"
 
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/storage"
 )
 
-// UserTOTPInfoGET returns the users TOTP configuration.
+// UserTOTPInfoGET returns the users TOTP configurations.
 func UserTOTPInfoGET(ctx *middlewares.AutheliaCtx) {
 	userSession := ctx.GetSession()
 
-	config, err := ctx.Providers.StorageProvider.LoadTOTPConfiguration(ctx, userSession.Username)
+	configs, err := ctx.Providers.StorageProvider.LoadTOTPConfigurationsByUsername(ctx, userSession.Username)
 	if err != nil {
 		if errors.Is(err, storage.ErrNoTOTPConfiguration) {
 			ctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -28,9 +30,66 @@ func UserTOTPInfoGET(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	if err = ctx.SetJSONBody(config); err != nil {
+	response := userTOTPInfoResponse{
+		Digits:        configs[0].Digits,
+		Period:        configs[0].Period,
+		Registrations: make([]userTOTPInfoRegistration, len(configs)),
+	}
+
+	for i, config := range configs {
+		response.Registrations[i] = userTOTPInfoRegistration{
+			ID:          config.ID,
+			Description: config.Description,
+			CreatedAt:   config.CreatedAt,
+			LastUsedAt:  config.LastUsedAt,
+			Algorithm:   config.Algorithm,
+			Digits:      config.Digits,
+			Period:      config.Period,
+		}
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
 		ctx.Logger.Errorf("Unable to perform TOTP configuration response: %s", err)
 	}
 
 	ctx.SetStatusCode(fasthttp.StatusOK)
 }
+
+// UserTOTPDELETE deletes a single TOTP configuration of the user. The user must have authenticated with two factors.
+func UserTOTPDELETE(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	if userSession.AuthenticationLevel < authentication.TwoFactor {
+		ctx.Logger.Errorf("User '%s' attempted to delete a TOTP configuration without having authenticated with two factors", userSession.Username)
+
+		ctx.ReplyForbidden()
+
+		return
+	}
+
+	value, _ := ctx.UserValue("id").(string)
+
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError("Invalid TOTP Configuration ID.")
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.DeleteTOTPConfigurationByID(ctx, userSession.Username, id); err != nil {
+		if errors.Is(err, storage.ErrNoTOTPConfiguration) {
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+			ctx.SetJSONError("Could not find TOTP Configuration for user.")
+			ctx.Logger.Errorf("Failed to delete TOTP configuration with id '%d' for user '%s' as it does not exist", id, userSession.Username)
+		} else {
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+			ctx.SetJSONError("Could not delete TOTP Configuration for user.")
+			ctx.Logger.Errorf("Failed to delete TOTP configuration with id '%d' for user '%s' with unknown error: %v", id, userSession.Username, err)
+		}
+
+		return
+	}
+
+	ctx.ReplyOK()
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+type UserTOTPSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *UserTOTPSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.TwoFactor
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *UserTOTPSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *UserTOTPSuite) TestShouldReturnRegistrations() {
+	createdAt := time.Unix(1640000000, 0).UTC()
+	usedAt := time.Unix(1640000100, 0).UTC()
+
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return([]model.TOTPConfiguration{
+			{ID: 1, CreatedAt: createdAt, LastUsedAt: &usedAt, Username: testUsername, Description: "Primary", Algorithm: "SHA1", Digits: 6, Period: 30},
+			{ID: 4, CreatedAt: createdAt, Username: testUsername, Description: "Backup", Algorithm: "SHA256", Digits: 8, Period: 60},
+		}, nil)
+
+	UserTOTPInfoGET(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), userTOTPInfoResponse{
+		Digits: 6,
+		Period: 30,
+		Registrations: []userTOTPInfoRegistration{
+			{ID: 1, Description: "Primary", CreatedAt: createdAt, LastUsedAt: &usedAt, Algorithm: "SHA1", Digits: 6, Period: 30},
+			{ID: 4, Description: "Backup", CreatedAt: createdAt, Algorithm: "SHA256", Digits: 8, Period: 60},
+		},
+	})
+}
+
+func (s *UserTOTPSuite) TestShouldReturnNotFoundWithoutRegistrations() {
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return(nil, storage.ErrNoTOTPConfiguration)
+
+	UserTOTPInfoGET(s.mock.Ctx)
+
+	s.Assert().Equal(fasthttp.StatusNotFound, s.mock.Ctx.Response.StatusCode())
+	s.Assert().Equal("Failed to lookup TOTP configuration for user 'john'", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *UserTOTPSuite) TestShouldDeleteRegistration() {
+	s.mock.Ctx.SetUserValue("id", "4")
+
+	s.mock.StorageMock.EXPECT().
+		DeleteTOTPConfigurationByID(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(4)).
+		Return(nil)
+
+	UserTOTPDELETE(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *UserTOTPSuite) TestShouldReturnNotFoundWhenDeletingUnknownRegistration() {
+	s.mock.Ctx.SetUserValue("id", "4")
+
+	s.mock.StorageMock.EXPECT().
+		DeleteTOTPConfigurationByID(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(4)).
+		Return(storage.ErrNoTOTPConfiguration)
+
+	UserTOTPDELETE(s.mock.Ctx)
+
+	s.Assert().Equal(fasthttp.StatusNotFound, s.mock.Ctx.Response.StatusCode())
+}
+
+func (s *UserTOTPSuite) TestShouldFailToDeleteRegistrationOnStorageError() {
+	s.mock.Ctx.SetUserValue("id", "4")
+
+	s.mock.StorageMock.EXPECT().
+		DeleteTOTPConfigurationByID(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(4)).
+		Return(errors.New("failed"))
+
+	UserTOTPDELETE(s.mock.Ctx)
+
+	s.Assert().Equal(fasthttp.StatusInternalServerError, s.mock.Ctx.Response.StatusCode())
+	s.Assert().Equal("Failed to delete TOTP configuration with id '4' for user 'john' with unknown error: failed", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *UserTOTPSuite) TestShouldForbidDeleteWithoutTwoFactor() {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.AuthenticationLevel = authentication.OneFactor
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.Ctx.SetUserValue("id", "4")
+
+	UserTOTPDELETE(s.mock.Ctx)
+
+	s.Assert().Equal(fasthttp.StatusForbidden, s.mock.Ctx.Response.StatusCode())
+}
+
+func TestRunUserTOTPSuite(t *testing.T) {
+	suite.Run(t, new(UserTOTPSuite))
+}
@@ -3,9 +3,13 @@ package handlers
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,6 +18,7 @@ import (
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/events"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/session"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -53,20 +58,24 @@ func parseBasicAuth(header []byte, auth string) (username, password string, err
 	return cs[:s], cs[s+1:], nil
 }
 
-// isTargetURLAuthorized check whether the given user is authorized to access the resource.
+// isTargetURLAuthorized check whether the given user is authorized to access the resource. The access control rule
+// which matched is also returned, it is nil if the default policy was applied.
 func isTargetURLAuthorized(authorizer *authorization.Authorizer, targetURL url.URL,
-	username string, userGroups []string, clientIP net.IP, method []byte, authLevel authentication.Level) authorizationMatching {
-	level := authorizer.GetRequiredLevel(
+	username string, userGroups []string, clientIP net.IP, method []byte, header http.Header, authLevel authentication.Level) (authorizationMatching, *authorization.AccessControlRule) {
+	object := authorization.NewObjectRaw(&targetURL, method)
+	object.Header = header
+
+	level, rule := authorizer.GetRequiredLevelAndRule(
 		authorization.Subject{
 			Username: username,
 			Groups:   userGroups,
 			IP:       clientIP,
 		},
-		authorization.NewObjectRaw(&targetURL, method))
+		object)
 
 	switch {
 	case level == authorization.Bypass:
-		return Authorized
+		return Authorized, rule
 	case level == authorization.Denied && username != "":
 		// If the user is not anonymous, it means that we went through
 		// all the rules related to that user and knowing who he is we can
@@ -74,13 +83,13 @@ func isTargetURLAuthorized(authorizer *authorization.Authorizer, targetURL url.U
 		// For anonymous users though, we cannot be sure that she
 		// could not be granted the rights to access the resource. Consequently
 		// for anonymous users we send Unauthorized instead of Forbidden.
-		return Forbidden
+		return Forbidden, rule
 	case level == authorization.OneFactor && authLevel >= authentication.OneFactor,
 		level == authorization.TwoFactor && authLevel >= authentication.TwoFactor:
-		return Authorized
+		return Authorized, rule
 	}
 
-	return NotAuthorized
+	return NotAuthorized, rule
 }
 
 // verifyBasicAuth verify that the provided username and password are correct and
@@ -101,18 +110,113 @@ func verifyBasicAuth(ctx *middlewares.AutheliaCtx, header, auth []byte) (usernam
 	// If the user is not correctly authenticated, send a 401.
 	if !authenticated {
 		// Request Basic Authentication otherwise.
-		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("user %s is not authenticated", username)
+		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("user '%s' is not authenticated", username)
+	}
+
+	if isUserDisabled(ctx, username) {
+		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("user '%s' is disabled", username)
 	}
 
-	details, err := ctx.Providers.UserProvider.GetDetails(username)
+	details, err := ctx.Providers.UserDetailsCache.GetDetails(ctx.Providers.UserProvider, username)
 
 	if err != nil {
-		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to retrieve details of user %s: %s", username, err)
+		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to retrieve details of user '%s': %w", username, err)
 	}
 
 	return username, details.DisplayName, details.Groups, details.Emails, authentication.OneFactor, nil
 }
 
+// verifyClientCertificate verifies the client certificate forwarded by the proxy when client certificate
+// authentication is enabled. It returns nil details without an error when no client certificate was forwarded.
+func verifyClientCertificate(ctx *middlewares.AutheliaCtx) (details *authentication.UserDetails, err error) {
+	if ctx.Providers.ClientCertificate == nil {
+		return nil, nil
+	}
+
+	value := ctx.Request.Header.Peek(ctx.Providers.ClientCertificate.Header())
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	username, err := ctx.Providers.ClientCertificate.Verify(ctx.RequestCtx.RemoteIP(), string(value), ctx.Clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if details, err = ctx.Providers.UserDetailsCache.GetDetails(ctx.Providers.UserProvider, username); err != nil {
+		return nil, fmt.Errorf("unable to retrieve details of user '%s': %w", username, err)
+	}
+
+	return details, nil
+}
+
+// verifyTrustedJWT verifies the JWT forwarded by a trusted upstream identity provider when trusted JWT authentication
+// is enabled. It returns nil details without an error when no JWT was forwarded.
+func verifyTrustedJWT(ctx *middlewares.AutheliaCtx) (details *authentication.UserDetails, err error) {
+	if ctx.Providers.TrustedJWT == nil {
+		return nil, nil
+	}
+
+	value := ctx.Request.Header.Peek(ctx.Providers.TrustedJWT.Header())
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	return ctx.Providers.TrustedJWT.Verify(string(value), ctx.Clock.Now())
+}
+
+// verifyTrustedHeader verifies the identity forwarded in a header by an upstream authenticator when trusted header
+// authentication is enabled. It returns nil details when no identity was forwarded or the target domain is not trusted.
+// An identity forwarded by a peer outside the trusted networks is ignored so the user has to authenticate normally.
+func verifyTrustedHeader(ctx *middlewares.AutheliaCtx, targetURL *url.URL) (details *authentication.UserDetails) {
+	if ctx.Providers.TrustedHeader == nil {
+		return nil
+	}
+
+	value := ctx.Request.Header.Peek(ctx.Providers.TrustedHeader.Header())
+	if len(value) == 0 || !ctx.Providers.TrustedHeader.IsDomainTrusted(targetURL.Hostname()) {
+		return nil
+	}
+
+	details, err := ctx.Providers.TrustedHeader.Verify(ctx.RequestCtx.RemoteIP(), string(value),
+		string(ctx.Request.Header.Peek(ctx.Providers.TrustedHeader.GroupsHeader())))
+	if err != nil {
+		ctx.Logger.Warnf("Ignoring the identity forwarded in the %s header when visiting %s: %v", ctx.Providers.TrustedHeader.Header(), targetURL.String(), err)
+
+		return nil
+	}
+
+	return details
+}
+
+// verifyAPIKey verifies the API key presented by a service when API keys are configured. It returns nil details
+// without an error when no configured API key was presented so the other authentication methods are tried.
+func verifyAPIKey(ctx *middlewares.AutheliaCtx, targetURL *url.URL) (details *authentication.UserDetails, err error) {
+	if ctx.Providers.APIKey == nil {
+		return nil, nil
+	}
+
+	value := string(ctx.Request.Header.Peek(ctx.Providers.APIKey.Header()))
+	if !ctx.Providers.APIKey.Presented(value) {
+		return nil, nil
+	}
+
+	return ctx.Providers.APIKey.Verify(value, targetURL)
+}
+
+// setMatchedRuleHeader sets the header containing the position and name of the matched access control rule, or
+// default if the default policy was applied.
+func setMatchedRuleHeader(headers *fasthttp.ResponseHeader, rule *authorization.AccessControlRule) {
+	switch {
+	case rule == nil:
+		headers.SetBytesK(headerAutheliaRule, "default")
+	case rule.Name == "":
+		headers.SetBytesK(headerAutheliaRule, strconv.Itoa(rule.Position))
+	default:
+		headers.SetBytesK(headerAutheliaRule, fmt.Sprintf("%d (%s)", rule.Position, rule.Name))
+	}
+}
+
 // setForwardedHeaders set the forwarded User, Groups, Name and Email headers.
 func setForwardedHeaders(headers *fasthttp.ResponseHeader, username, name string, groups, emails []string) {
 	if username != "" {
@@ -148,6 +252,79 @@ func hasUserBeenInactiveTooLong(ctx *middlewares.AutheliaCtx) (bool, error) { //
 	return false, nil
 }
 
+// hasAuthenticationExceededMaximumAge checks whether the last authentication of the user is older than the maximum
+// authentication age of the matched access control rule, or the global maximum if the rule doesn't configure one.
+func hasAuthenticationExceededMaximumAge(ctx *middlewares.AutheliaCtx, rule *authorization.AccessControlRule) bool {
+	maximumAge := ctx.Configuration.Session.MaximumAuthenticationAge
+
+	if rule != nil && rule.MaximumAuthenticationAge != 0 {
+		maximumAge = rule.MaximumAuthenticationAge
+	}
+
+	if maximumAge <= 0 {
+		return false
+	}
+
+	authenticationAge := ctx.Clock.Now().Sub(ctx.GetSession().LastAuthenticatedTime())
+
+	ctx.Logger.Tracef("Authentication age report: AuthenticationAge=%s, MaximumAuthenticationAge=%s", authenticationAge, maximumAge)
+
+	return authenticationAge > maximumAge
+}
+
+// isOutsideTimeWindow returns true if the rule has a time window and the current time is outside of it. Sessions
+// established during the most recent occurrence of the time window are permitted until they expire unless the rule
+// terminates them.
+func isOutsideTimeWindow(ctx *middlewares.AutheliaCtx, rule *authorization.AccessControlRule, isBasicAuth bool) bool {
+	if rule == nil || rule.TimeWindow == nil {
+		return false
+	}
+
+	now := ctx.Clock.Now()
+
+	if rule.TimeWindow.IsWithin(now) {
+		return false
+	}
+
+	if isBasicAuth || rule.TimeWindow.TerminateSessions {
+		return true
+	}
+
+	userSession := ctx.GetSession()
+
+	if userSession.FirstFactorAuthnTimestamp == 0 {
+		return true
+	}
+
+	return !rule.TimeWindow.IsEstablishedWithin(time.Unix(userSession.FirstFactorAuthnTimestamp, 0), now)
+}
+
+// isSecondFactorMethodRequired returns true if the rule requires two factor authentication with specific second factor
+// methods for the subject and the user hasn't completed the second factor with any of them.
+func isSecondFactorMethodRequired(ctx *middlewares.AutheliaCtx, rule *authorization.AccessControlRule) bool {
+	if rule == nil || len(rule.SecondFactorMethods) == 0 {
+		return false
+	}
+
+	if rule.GetPolicy(authorization.Subject{IP: ctx.RemoteIP()}) != authorization.TwoFactor {
+		return false
+	}
+
+	return !ctx.GetSession().HasSecondFactorMethod(rule.SecondFactorMethods)
+}
+
+// hasSecondFactorReverificationElapsed returns true if the remember me second factor re-verification interval is
+// configured and has elapsed since the user last completed the second factor.
+func hasSecondFactorReverificationElapsed(ctx *middlewares.AutheliaCtx, userSession *session.UserSession) bool {
+	interval := ctx.Configuration.Session.RememberMe.SecondFactorReverification
+
+	if interval <= 0 {
+		return false
+	}
+
+	return ctx.Clock.Now().Sub(time.Unix(userSession.SecondFactorAuthnTimestamp, 0)) > interval
+}
+
 // verifySessionCookie verifies if a user is identified by a cookie.
 func verifySessionCookie(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userSession *session.UserSession, refreshProfile bool,
 	refreshProfileInterval time.Duration) (username, name string, groups, emails []string, authLevel authentication.Level, err error) {
@@ -171,7 +348,38 @@ func verifySessionCookie(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userS
 				return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to destroy user session after long inactivity: %s", err)
 			}
 
-			return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, authentication.NotAuthenticated, fmt.Errorf("User %s has been inactive for too long", userSession.Username)
+			return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, authentication.NotAuthenticated, fmt.Errorf("User '%s' has been inactive for too long", userSession.Username)
+		}
+	}
+
+	if !isUserAnonymous && isSessionBindingMismatch(ctx, userSession) {
+		ctx.Logger.Warnf("The session of user '%s' was used by a client which doesn't match the client it's bound to from IP %s", userSession.Username, ctx.RemoteIP())
+
+		// Destroy the session so the possibly stolen cookie can't be used again and the user has to log in again.
+		if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx); err != nil {
+			ctx.Logger.Errorf("Unable to destroy user session after the client didn't match the session binding: %s", err)
+		}
+
+		return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, authentication.NotAuthenticated, fmt.Errorf("the session of user '%s' is bound to a different client", userSession.Username)
+	}
+
+	if !isUserAnonymous && isUserDisabled(ctx, userSession.Username) {
+		// Destroy the session so the user has to complete the first factor again which rejects disabled users.
+		if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx); err != nil {
+			ctx.Logger.Errorf("Unable to destroy user session after the user was disabled: %s", err)
+		}
+
+		return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, authentication.NotAuthenticated, fmt.Errorf("user '%s' is disabled", userSession.Username)
+	}
+
+	if userSession.KeepMeLoggedIn && userSession.AuthenticationLevel == authentication.TwoFactor && hasSecondFactorReverificationElapsed(ctx, userSession) {
+		ctx.Logger.Infof("User '%s' must complete the second factor again as the remember me second factor re-verification interval has elapsed", userSession.Username)
+
+		// The session is downgraded rather than destroyed so only resources which require two factor are affected.
+		userSession.AuthenticationLevel = authentication.OneFactor
+
+		if err = ctx.SaveSession(*userSession); err != nil {
+			return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to save user session after the second factor re-verification interval elapsed: %w", err)
 		}
 	}
 
@@ -194,7 +402,7 @@ func verifySessionCookie(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userS
 	return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, userSession.AuthenticationLevel, nil
 }
 
-func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, isBasicAuth bool, username string, method []byte) {
+func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, isBasicAuth bool, username string, method []byte, requiredMethods []string) {
 	var (
 		statusCode            int
 		redirectionURL        string
@@ -210,7 +418,7 @@ func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, is
 	}
 
 	if isBasicAuth {
-		ctx.Logger.Infof("Access to %s is not authorized to user %s, sending 401 response with basic auth header", targetURL.String(), friendlyUsername)
+		ctx.Logger.Infof("Access to %s is not authorized to user '%s', sending 401 response with basic auth header", targetURL.String(), friendlyUsername)
 		ctx.ReplyUnauthorized()
 		ctx.Response.Header.Add("WWW-Authenticate", "Basic realm=\"Authentication required\"")
 
@@ -237,6 +445,11 @@ func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, is
 		default:
 			redirectionURL = fmt.Sprintf("%s?rd=%s&rm=%s", rd, url.QueryEscape(targetURL.String()), rm)
 		}
+
+		// The second factor methods required by the matched rule are passed to the portal so it only offers those.
+		if len(requiredMethods) != 0 {
+			redirectionURL += "&sfm=" + url.QueryEscape(strings.Join(requiredMethods, ","))
+		}
 	}
 
 	switch {
@@ -252,14 +465,103 @@ func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, is
 	}
 
 	if redirectionURL != "" {
-		ctx.Logger.Infof("Access to %s (method %s) is not authorized to user %s, responding with status code %d with location redirect to %s", targetURL.String(), friendlyRequestMethod, friendlyUsername, statusCode, redirectionURL)
+		ctx.Logger.Infof("Access to %s (method %s) is not authorized to user '%s', responding with status code %d with location redirect to %s", targetURL.String(), friendlyRequestMethod, friendlyUsername, statusCode, redirectionURL)
 		ctx.SpecialRedirect(redirectionURL, statusCode)
 	} else {
-		ctx.Logger.Infof("Access to %s (method %s) is not authorized to user %s, responding with status code %d", targetURL.String(), friendlyRequestMethod, friendlyUsername, statusCode)
+		ctx.Logger.Infof("Access to %s (method %s) is not authorized to user '%s', responding with status code %d", targetURL.String(), friendlyRequestMethod, friendlyUsername, statusCode)
 		ctx.ReplyUnauthorized()
 	}
 }
 
+// handleForbidden sends the deny response of the matched rule, or the global deny response if the rule doesn't have
+// one, to a user who is forbidden from accessing the target URL.
+func handleForbidden(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, username string, rule *authorization.AccessControlRule) {
+	response := ctx.Configuration.AccessControl.DenyResponse
+
+	if rule != nil && rule.DenyResponse != nil {
+		response = *rule.DenyResponse
+	}
+
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = fasthttp.StatusForbidden
+	}
+
+	redirect := response.RedirectURL != ""
+
+	// Like for unauthorized requests the redirect is only sent to proxies which provide the rd parameter, as proxies
+	// such as nginx with auth_request don't follow a redirect from the verify endpoint and respond with an error.
+	if redirect && (len(ctx.QueryArgs().Peek("rd")) == 0 || ctx.IsXHR() || !ctx.AcceptsMIME("text/html")) {
+		redirect, statusCode = false, fasthttp.StatusForbidden
+	}
+
+	switch {
+	case redirect:
+		ctx.Logger.Infof("Access to %s is forbidden to user '%s', responding with status code %d with location redirect to %s", targetURL.String(), username, statusCode, response.RedirectURL)
+		ctx.SpecialRedirect(response.RedirectURL, statusCode)
+	case response.Message != "":
+		ctx.Logger.Infof("Access to %s is forbidden to user '%s', responding with status code %d with a custom message", targetURL.String(), username, statusCode)
+		ctx.SetStatusCode(statusCode)
+
+		if json.Valid([]byte(response.Message)) {
+			ctx.SetContentType("application/json")
+		} else {
+			ctx.SetContentType("text/plain; charset=utf-8")
+		}
+
+		ctx.SetBodyString(response.Message)
+	default:
+		ctx.Logger.Infof("Access to %s is forbidden to user '%s', responding with status code %d", targetURL.String(), username, statusCode)
+		ctx.RequestCtx.Error(fasthttp.StatusMessage(statusCode), statusCode)
+
+		// The error page with the help is rendered by the server once the response has been written.
+		if response.HelpMessage != "" || response.HelpURL != "" {
+			ctx.SetUserValueBytes(middlewares.UserValueKeyDenyResponse, &response)
+		}
+	}
+}
+
+// handleShadowDeny logs and audits an authorized request which would have been denied by a rule in shadow mode, which
+// is the case when the policy of the rule is deny or when the user hasn't completed the second factor it requires.
+func handleShadowDeny(ctx *middlewares.AutheliaCtx, targetURL *url.URL, username string, groups []string, method []byte,
+	header http.Header, authLevel authentication.Level) {
+	object := authorization.NewObjectRaw(targetURL, method)
+	object.Header = header
+
+	level, rule := ctx.Providers.Authorizer.GetShadowLevelAndRule(authorization.Subject{
+		Username: username,
+		Groups:   groups,
+		IP:       ctx.RemoteIP(),
+	}, object)
+
+	if rule == nil || (level == authorization.TwoFactor && authLevel >= authentication.TwoFactor) {
+		return
+	}
+
+	friendlyUsername := username
+	if friendlyUsername == "" {
+		friendlyUsername = "<anonymous>"
+	}
+
+	policy := authorization.LevelToPolicy(level)
+
+	ctx.Logger.Warnf("Access to %s (method %s) is authorized to user '%s' but would have been denied by the policy '%s' of rule %d which is in shadow mode",
+		targetURL.String(), method, friendlyUsername, policy, rule.Position)
+
+	ctx.Providers.Events.Emit(events.Event{
+		Type:     events.TypeAccessControlShadowDeny,
+		Username: username,
+		RemoteIP: ctx.RemoteIP().String(),
+		Details: map[string]string{
+			"url":           targetURL.String(),
+			"method":        string(method),
+			"rule_position": strconv.Itoa(rule.Position),
+			"rule_name":     rule.Name,
+			"policy":        policy,
+		},
+	})
+}
+
 func updateActivityTimestamp(ctx *middlewares.AutheliaCtx, isBasicAuth bool, username string) error {
 	if isBasicAuth || username == "" {
 		return nil
@@ -335,12 +637,18 @@ func verifySessionHasUpToDateProfile(ctx *middlewares.AutheliaCtx, targetURL *ur
 		return nil
 	}
 
+	// Users authenticated by a trusted upstream identity provider or with the break-glass account may not exist in the
+	// authentication backend.
+	if userSession.AuthenticationMethodRefs.TrustedJWT || userSession.BreakGlass {
+		return nil
+	}
+
 	if refreshProfileInterval != schema.RefreshIntervalAlways && userSession.RefreshTTL.After(ctx.Clock.Now()) {
 		return nil
 	}
 
-	ctx.Logger.Debugf("Checking the authentication backend for an updated profile for user %s", userSession.Username)
-	details, err := ctx.Providers.UserProvider.GetDetails(userSession.Username)
+	ctx.Logger.Debugf("Checking the authentication backend for an updated profile for user '%s'", userSession.Username)
+	details, err := ctx.Providers.UserDetailsCache.GetDetails(ctx.Providers.UserProvider, userSession.Username)
 	// Only update the session if we could get the new details.
 	if err != nil {
 		return err
@@ -402,8 +710,31 @@ func getProfileRefreshSettings(cfg schema.AuthenticationBackendConfiguration) (r
 	return refresh, refreshInterval
 }
 
+func getAuthenticationBackendName(cfg schema.AuthenticationBackendConfiguration) string {
+	if cfg.LDAP != nil {
+		return "ldap"
+	}
+
+	return "file"
+}
+
 func verifyAuth(ctx *middlewares.AutheliaCtx, targetURL *url.URL, refreshProfile bool, refreshProfileInterval time.Duration) (isBasicAuth bool, username, name string, groups, emails []string, authLevel authentication.Level, err error) {
 	authHeader := headerProxyAuthorization
+	var details *authentication.UserDetails
+
+	if details, err = verifyAPIKey(ctx, targetURL); err != nil {
+		return true, "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to verify the API key: %w", err)
+	} else if details != nil {
+		// API keys are verified on every request, are never stored in the session, and only ever satisfy one factor.
+		return true, details.Username, details.DisplayName, details.Groups, details.Emails, authentication.OneFactor, nil
+	}
+
+	if details = verifyTrustedHeader(ctx, targetURL); details != nil {
+		// Identities forwarded by a trusted upstream are trusted on every request, are never stored in the session, and
+		// only ever satisfy one factor.
+		return true, details.Username, details.DisplayName, details.Groups, details.Emails, authentication.OneFactor, nil
+	}
+
 	if bytes.Equal(ctx.QueryArgs().Peek("auth"), []byte("basic")) {
 		authHeader = headerAuthorization
 		isBasicAuth = true
@@ -425,6 +756,26 @@ func verifyAuth(ctx *middlewares.AutheliaCtx, targetURL *url.URL, refreshProfile
 	userSession := ctx.GetSession()
 	username, name, groups, emails, authLevel, err = verifySessionCookie(ctx, targetURL, &userSession, refreshProfile, refreshProfileInterval)
 
+	if err == nil && username == "" {
+		if details, err = verifyClientCertificate(ctx); err != nil {
+			return isBasicAuth, "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to verify the client certificate: %w", err)
+		}
+
+		if details != nil {
+			// Client certificates are verified on every request and like basic auth are not stored in the session.
+			return true, details.Username, details.DisplayName, details.Groups, details.Emails, authentication.OneFactor, nil
+		}
+
+		if details, err = verifyTrustedJWT(ctx); err != nil {
+			return isBasicAuth, "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to verify the trusted JWT: %w", err)
+		}
+
+		if details != nil {
+			// Trusted JWTs are verified on every request and like client certificates are not stored in the session.
+			return true, details.Username, details.DisplayName, details.Groups, details.Emails, authentication.OneFactor, nil
+		}
+	}
+
 	sessionUsername := ctx.Request.Header.PeekBytes(headerSessionUsername)
 	if sessionUsername != nil && !strings.EqualFold(string(sessionUsername), username) {
 		ctx.Logger.Warnf("Possible cookie hijack or attempt to bypass security detected destroying the session and sending 401 response")
@@ -434,12 +785,33 @@ func verifyAuth(ctx *middlewares.AutheliaCtx, targetURL *url.URL, refreshProfile
 			ctx.Logger.Errorf("Unable to destroy user session after handler could not match them to their %s header: %s", headerSessionUsername, err)
 		}
 
-		err = fmt.Errorf("could not match user %s to their %s header with a value of %s when visiting %s", username, headerSessionUsername, sessionUsername, targetURL.String())
+		err = fmt.Errorf("could not match user '%s' to their %s header with a value of %s when visiting %s", username, headerSessionUsername, sessionUsername, targetURL.String())
 	}
 
 	return
 }
 
+// getNetworkIdentityRule returns the matched rule when the request is bypassed by a rule with a synthetic identity. The
+// rule is matched as an anonymous subject so the session is never loaded, and a rule which only matches authenticated
+// users takes precedence as it would for any other anonymous request. Rules with an identity must be restricted to
+// networks, so the identity is only ever granted based on the network of the request.
+func getNetworkIdentityRule(ctx *middlewares.AutheliaCtx, targetURL *url.URL, method []byte) (rule *authorization.AccessControlRule) {
+	object := authorization.NewObjectRaw(targetURL, method)
+	object.Header = ctx.RequestHeader()
+
+	level, rule := ctx.Providers.Authorizer.GetRequiredLevelAndRule(authorization.Subject{IP: ctx.RemoteIP()}, object)
+
+	if level != authorization.Bypass || rule == nil || rule.Identity == nil || len(rule.Networks) == 0 {
+		return nil
+	}
+
+	if isOutsideTimeWindow(ctx, rule, true) {
+		return nil
+	}
+
+	return rule
+}
+
 // VerifyGET returns the handler verifying if a request is allowed to go through.
 func VerifyGET(cfg schema.AuthenticationBackendConfiguration) middlewares.RequestHandler {
 	refreshProfile, refreshProfileInterval := getProfileRefreshSettings(cfg)
@@ -472,34 +844,121 @@ func VerifyGET(cfg schema.AuthenticationBackendConfiguration) middlewares.Reques
 		}
 
 		method := ctx.XForwardedMethod()
+
+		// The synthetic identity is only forwarded to the backend. It's never stored in the session or used to update
+		// the activity of a user so it can't be used to access the portal or complete any interactive flow.
+		if rule := getNetworkIdentityRule(ctx, targetURL, method); rule != nil {
+			ctx.Logger.Debugf("Access to %s from %s is authorized as '%s' by the identity of rule %d", targetURL.String(), ctx.RemoteIP(), rule.Identity.Username, rule.Position)
+
+			var emails []string
+
+			if rule.Identity.Email != "" {
+				emails = []string{rule.Identity.Email}
+			}
+
+			setForwardedHeaders(&ctx.Response.Header, rule.Identity.Username, rule.Identity.DisplayName, rule.Identity.Groups, emails)
+
+			if ctx.Configuration.Server.EnableMatchedRuleHeader {
+				setMatchedRuleHeader(&ctx.Response.Header, rule)
+			}
+
+			return
+		}
+
 		isBasicAuth, username, name, groups, emails, authLevel, err := verifyAuth(ctx, targetURL, refreshProfile, refreshProfileInterval)
 
 		if err != nil {
 			ctx.Logger.Errorf("Error caught when verifying user authorization: %s", err)
 
+			if errors.Is(err, authentication.ErrBackendBusy) {
+				SetStatusCodeResponse(ctx, fasthttp.StatusServiceUnavailable)
+
+				return
+			}
+
 			if err := updateActivityTimestamp(ctx, isBasicAuth, username); err != nil {
 				ctx.Error(fmt.Errorf("unable to update last activity: %s", err), messageOperationFailed)
 				return
 			}
 
-			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method)
+			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method, nil)
 
 			return
 		}
 
-		authorized := isTargetURLAuthorized(ctx.Providers.Authorizer, *targetURL, username,
-			groups, ctx.RemoteIP(), method, authLevel)
+		if !isBasicAuth && authLevel == authentication.OneFactor && ctx.GetSession().ImpossibleTravel {
+			ctx.Logger.Debugf("User '%s' must complete the second factor as impossible travel was detected", username)
+
+			authLevel = authentication.NotAuthenticated
+		}
+
+		if !isBasicAuth && authLevel == authentication.OneFactor && ctx.GetSession().BreakGlass {
+			ctx.Logger.Debugf("User '%s' must complete the second factor as they logged in with the break-glass account", username)
+
+			authLevel = authentication.NotAuthenticated
+		}
+
+		if !isBasicAuth && authLevel == authentication.OneFactor && ctx.GetSession().SecondFactorEnrollmentRequired {
+			ctx.Logger.Debugf("User '%s' must enroll a second factor method and complete the second factor", username)
+
+			authLevel = authentication.NotAuthenticated
+		}
+
+		header := ctx.RequestHeader()
+
+		authorized, rule := isTargetURLAuthorized(ctx.Providers.Authorizer, *targetURL, username,
+			groups, ctx.RemoteIP(), method, header, authLevel)
+
+		if !isBasicAuth && authLevel != authentication.NotAuthenticated && hasAuthenticationExceededMaximumAge(ctx, rule) {
+			ctx.Logger.Infof("User '%s' must authenticate again as their last authentication is older than the maximum authentication age", username)
+
+			// Destroy the session so the user has to authenticate again, a new one will be generated on the next request.
+			if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx); err != nil {
+				ctx.Logger.Errorf("Unable to destroy user session after the maximum authentication age was exceeded: %s", err)
+			}
+
+			username, name, groups, emails, authLevel = "", "", nil, nil, authentication.NotAuthenticated
+
+			authorized, rule = isTargetURLAuthorized(ctx.Providers.Authorizer, *targetURL, username,
+				groups, ctx.RemoteIP(), method, header, authLevel)
+		}
+
+		var requiredMethods []string
+
+		if authorized == Authorized && !isBasicAuth && authLevel == authentication.TwoFactor && isSecondFactorMethodRequired(ctx, rule) {
+			ctx.Logger.Infof("User '%s' must complete the second factor with one of the methods '%s' required by the matched rule", username, strings.Join(rule.SecondFactorMethods, "', '"))
+
+			// The session is left untouched as the requirement only applies to this request, the portal is told which
+			// methods are required so it asks the user to complete the second factor with one of them.
+			requiredMethods = rule.SecondFactorMethods
+
+			authorized = NotAuthorized
+		}
+
+		if authorized == Authorized && isOutsideTimeWindow(ctx, rule, isBasicAuth) {
+			ctx.Logger.Infof("Access to %s by user '%s' is forbidden outside of the time window of the matched rule", targetURL.String(), username)
+
+			authorized = Forbidden
+		}
+
+		if authorized == Authorized {
+			handleShadowDeny(ctx, targetURL, username, groups, method, header, authLevel)
+		}
 
 		switch authorized {
 		case Forbidden:
-			ctx.Logger.Infof("Access to %s is forbidden to user %s", targetURL.String(), username)
-			ctx.ReplyForbidden()
+			handleForbidden(ctx, targetURL, username, rule)
 		case NotAuthorized:
-			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method)
+			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method, requiredMethods)
 		case Authorized:
 			setForwardedHeaders(&ctx.Response.Header, username, name, groups, emails)
 		}
 
+		// This must be set after the response has been written as replying with an error resets the headers.
+		if ctx.Configuration.Server.EnableMatchedRuleHeader {
+			setMatchedRuleHeader(&ctx.Response.Header, rule)
+		}
+
 		if err := updateActivityTimestamp(ctx, isBasicAuth, username); err != nil {
 			ctx.Error(fmt.Errorf("unable to update last activity: %s", err), messageOperationFailed)
 		}
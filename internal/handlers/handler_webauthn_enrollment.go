@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/events"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// WebauthnEnrollmentStartPOST returns the attestation challenge for an administrator enrolling a Webauthn device on
+// behalf of a user, for example a security key provisioned centrally for a kiosk or a shared device.
+func WebauthnEnrollmentStartPOST(ctx *middlewares.AutheliaCtx) {
+	if !isUserAdministrator(ctx) {
+		ctx.ReplyForbidden()
+		return
+	}
+
+	var (
+		bodyJSON webauthnEnrollmentStartRequestBody
+		details  *authentication.UserDetails
+		w        *webauthn.WebAuthn
+		user     *model.WebauthnUser
+		err      error
+	)
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	userSession := ctx.GetSession()
+
+	if details, err = ctx.Providers.UserProvider.GetDetails(bodyJSON.Username); err != nil {
+		ctx.Error(fmt.Errorf("unable to retrieve the details of user '%s' for the %s enrollment started by '%s': %w", bodyJSON.Username, regulation.AuthTypeWebauthn, userSession.Username, err), messageOperationFailed)
+		return
+	}
+
+	if w, err = newWebauthn(ctx); err != nil {
+		ctx.Error(fmt.Errorf("unable to configure %s for the enrollment of user '%s': %w", regulation.AuthTypeWebauthn, details.Username, err), messageOperationFailed)
+		return
+	}
+
+	if user, err = getWebAuthnUser(ctx, session.UserSession{Username: details.Username, DisplayName: details.DisplayName}); err != nil {
+		ctx.Error(fmt.Errorf("unable to load the %s devices of user '%s': %w", regulation.AuthTypeWebauthn, details.Username, err), messageOperationFailed)
+		return
+	}
+
+	enrollment := &session.WebauthnEnrollment{Username: details.Username}
+
+	var credentialCreation *protocol.CredentialCreation
+
+	if credentialCreation, enrollment.SessionData, err = w.BeginRegistration(user); err != nil {
+		ctx.Error(fmt.Errorf("unable to create the %s attestation challenge for the enrollment of user '%s': %w", regulation.AuthTypeWebauthn, details.Username, err), messageOperationFailed)
+		return
+	}
+
+	userSession.WebauthnEnrollment = enrollment
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Error(fmt.Errorf("unable to save the %s enrollment of user '%s' in the session: %w", regulation.AuthTypeWebauthn, details.Username, err), messageOperationFailed)
+		return
+	}
+
+	ctx.Logger.Infof("The %s enrollment of a device for user '%s' has been started by '%s'", regulation.AuthTypeWebauthn, details.Username, userSession.Username)
+
+	if err = ctx.SetJSONBody(credentialCreation); err != nil {
+		ctx.Error(fmt.Errorf("unable to set the %s attestation challenge in the response: %w", regulation.AuthTypeWebauthn, err), messageOperationFailed)
+		return
+	}
+}
+
+// WebauthnEnrollmentFinishPOST validates the attestation of a Webauthn device enrolled by an administrator on behalf
+// of a user exactly as WebauthnAttestationPOST does and saves the device for the user.
+func WebauthnEnrollmentFinishPOST(ctx *middlewares.AutheliaCtx) {
+	if !isUserAdministrator(ctx) {
+		ctx.ReplyForbidden()
+		return
+	}
+
+	var (
+		bodyJSON webauthnEnrollmentFinishRequestBody
+		w        *webauthn.WebAuthn
+		user     *model.WebauthnUser
+		err      error
+
+		attestationResponse *protocol.ParsedCredentialCreationData
+		credential          *webauthn.Credential
+	)
+
+	userSession := ctx.GetSession()
+
+	enrollment := userSession.WebauthnEnrollment
+	if enrollment == nil || enrollment.SessionData == nil {
+		ctx.Error(fmt.Errorf("user '%s' attempted to finish a %s enrollment which was not started", userSession.Username, regulation.AuthTypeWebauthn), messageOperationFailed)
+		return
+	}
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	if bodyJSON.Description == "" {
+		bodyJSON.Description = webauthnDefaultDescription
+	}
+
+	if len(bodyJSON.Description) > webauthnMaxDescriptionLength {
+		ctx.Error(fmt.Errorf("the description of the %s device enrolled for user '%s' must not be longer than %d characters", regulation.AuthTypeWebauthn, enrollment.Username, webauthnMaxDescriptionLength), messageOperationFailed)
+		return
+	}
+
+	if len(bodyJSON.Credential) == 0 {
+		ctx.Error(errors.New("the credential is required"), messageOperationFailed)
+		return
+	}
+
+	if w, err = newWebauthn(ctx); err != nil {
+		ctx.Error(fmt.Errorf("unable to configure %s for the enrollment of user '%s': %w", regulation.AuthTypeWebauthn, enrollment.Username, err), messageOperationFailed)
+		return
+	}
+
+	if attestationResponse, err = protocol.ParseCredentialCreationResponseBody(bytes.NewReader(bodyJSON.Credential)); err != nil {
+		webauthnEnrollmentFailed(ctx, enrollment.Username, bodyJSON.Description, fmt.Errorf("unable to parse the %s attestation: %w", regulation.AuthTypeWebauthn, err))
+		return
+	}
+
+	if user, err = getWebAuthnUser(ctx, session.UserSession{Username: enrollment.Username}); err != nil {
+		ctx.Error(fmt.Errorf("unable to load the %s devices of user '%s': %w", regulation.AuthTypeWebauthn, enrollment.Username, err), messageOperationFailed)
+		return
+	}
+
+	if credential, err = w.CreateCredential(user, *enrollment.SessionData, attestationResponse); err != nil {
+		webauthnEnrollmentFailed(ctx, enrollment.Username, bodyJSON.Description, fmt.Errorf("unable to validate the %s attestation: %w", regulation.AuthTypeWebauthn, err))
+		return
+	}
+
+	device := model.NewWebauthnDeviceFromCredential(w.Config.RPID, enrollment.Username, bodyJSON.Description, credential)
+
+	if err = ctx.Providers.StorageProvider.SaveWebauthnDevice(ctx, device); err != nil {
+		ctx.Error(fmt.Errorf("unable to save the %s device of user '%s': %w", regulation.AuthTypeWebauthn, enrollment.Username, err), messageOperationFailed)
+		return
+	}
+
+	userSession.WebauthnEnrollment = nil
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.Errorf("Unable to remove the %s enrollment of user '%s' from the session: %+v", regulation.AuthTypeWebauthn, enrollment.Username, err)
+	}
+
+	ctx.Logger.Infof("The %s device '%s' has been enrolled for user '%s' by '%s'", regulation.AuthTypeWebauthn, bodyJSON.Description, enrollment.Username, userSession.Username)
+
+	emitWebauthnEnrollment(ctx, enrollment.Username, bodyJSON.Description, true)
+
+	ctx.ReplyOK()
+	ctx.SetStatusCode(fasthttp.StatusCreated)
+}
+
+// webauthnEnrollmentFailed logs and audits an enrollment which failed because the attestation was invalid.
+func webauthnEnrollmentFailed(ctx *middlewares.AutheliaCtx, username, description string, err error) {
+	userSession := ctx.GetSession()
+
+	ctx.Logger.Warnf("The %s enrollment of device '%s' for user '%s' by '%s' was rejected: %v", regulation.AuthTypeWebauthn, description, username, userSession.Username, err)
+
+	emitWebauthnEnrollment(ctx, username, description, false)
+
+	ctx.SetJSONError(messageOperationFailed)
+}
+
+func emitWebauthnEnrollment(ctx *middlewares.AutheliaCtx, username, description string, successful bool) {
+	ctx.Providers.Events.Emit(events.Event{
+		Type:       events.TypeWebauthnEnrollment,
+		Username:   username,
+		RemoteIP:   ctx.RemoteIP().String(),
+		Successful: &successful,
+		Details: map[string]string{
+			"administrator": ctx.GetSession().Username,
+			"description":   description,
+		},
+	})
+}
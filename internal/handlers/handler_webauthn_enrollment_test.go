@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+type WebauthnEnrollmentSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *WebauthnEnrollmentSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Ctx.Configuration.AuthenticationBackend.AdminGroup = "admins"
+	s.mock.Ctx.Configuration.Webauthn = schema.DefaultWebauthnConfiguration
+
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Host", "login.example.com")
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-URI", "/")
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+
+	s.setSession([]string{"admins"}, authentication.TwoFactor)
+}
+
+func (s *WebauthnEnrollmentSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *WebauthnEnrollmentSuite) setSession(groups []string, level authentication.Level) {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.Groups = groups
+	userSession.AuthenticationLevel = level
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *WebauthnEnrollmentSuite) start() (challenge string) {
+	gomock.InOrder(
+		s.mock.UserProviderMock.EXPECT().
+			GetDetails("harry").
+			Return(&authentication.UserDetails{Username: "harry", DisplayName: "Harry Potter"}, nil),
+		s.mock.StorageMock.EXPECT().
+			LoadWebauthnDevicesByUsername(s.mock.Ctx, "harry").
+			Return(nil, nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"username":"harry"}`)
+
+	WebauthnEnrollmentStartPOST(s.mock.Ctx)
+
+	s.Require().Equal(fasthttp.StatusOK, s.mock.Ctx.Response.StatusCode())
+
+	enrollment := s.mock.Ctx.GetSession().WebauthnEnrollment
+	s.Require().NotNil(enrollment)
+	s.Require().NotNil(enrollment.SessionData)
+	s.Equal("harry", enrollment.Username)
+	s.Equal([]byte("harry"), enrollment.SessionData.UserID)
+
+	s.mock.Ctx.Response.Reset()
+
+	return enrollment.SessionData.Challenge
+}
+
+func (s *WebauthnEnrollmentSuite) TestShouldEnrollDevice() {
+	challenge := s.start()
+
+	credential := newTestWebauthnCredential(s.T(), "login.example.com", "https://login.example.com", challenge)
+
+	var device model.WebauthnDevice
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadWebauthnDevicesByUsername(s.mock.Ctx, "harry").
+			Return(nil, nil),
+		s.mock.StorageMock.EXPECT().
+			SaveWebauthnDevice(s.mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, d model.WebauthnDevice) error {
+				device = d
+				return nil
+			}),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(fmt.Sprintf(`{"description":"Kiosk","credential":%s}`, credential))
+
+	WebauthnEnrollmentFinishPOST(s.mock.Ctx)
+
+	s.Equal(fasthttp.StatusCreated, s.mock.Ctx.Response.StatusCode())
+	s.Equal("harry", device.Username)
+	s.Equal("Kiosk", device.Description)
+	s.Equal("login.example.com", device.RPID)
+	s.Nil(s.mock.Ctx.GetSession().WebauthnEnrollment)
+	s.Equal("The Webauthn device 'Kiosk' has been enrolled for user 'harry' by 'john'", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *WebauthnEnrollmentSuite) TestShouldRejectAttestationWithInvalidChallenge() {
+	s.start()
+
+	credential := newTestWebauthnCredential(s.T(), "login.example.com", "https://login.example.com", base64.RawURLEncoding.EncodeToString([]byte("not the challenge")))
+
+	s.mock.StorageMock.EXPECT().
+		LoadWebauthnDevicesByUsername(s.mock.Ctx, "harry").
+		Return(nil, nil)
+
+	s.mock.Ctx.Request.SetBodyString(fmt.Sprintf(`{"credential":%s}`, credential))
+
+	WebauthnEnrollmentFinishPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Contains(s.mock.Hook.LastEntry().Message, "The Webauthn enrollment of device 'Primary' for user 'harry' by 'john' was rejected: unable to validate the Webauthn attestation")
+	s.NotNil(s.mock.Ctx.GetSession().WebauthnEnrollment)
+}
+
+func (s *WebauthnEnrollmentSuite) TestShouldRejectAttestationWithInvalidOrigin() {
+	challenge := s.start()
+
+	credential := newTestWebauthnCredential(s.T(), "login.example.com", "https://evil.example.com", challenge)
+
+	s.mock.StorageMock.EXPECT().
+		LoadWebauthnDevicesByUsername(s.mock.Ctx, "harry").
+		Return(nil, nil)
+
+	s.mock.Ctx.Request.SetBodyString(fmt.Sprintf(`{"credential":%s}`, credential))
+
+	WebauthnEnrollmentFinishPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Contains(s.mock.Hook.LastEntry().Message, "unable to validate the Webauthn attestation")
+}
+
+func (s *WebauthnEnrollmentSuite) TestShouldRejectFinishWithoutStart() {
+	s.mock.Ctx.Request.SetBodyString(`{"credential":{}}`)
+
+	WebauthnEnrollmentFinishPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Equal("user 'john' attempted to finish a Webauthn enrollment which was not started", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *WebauthnEnrollmentSuite) TestShouldRejectLongDescription() {
+	s.start()
+
+	s.mock.Ctx.Request.SetBodyString(`{"description":"A description which is far too long for the storage","credential":{}}`)
+
+	WebauthnEnrollmentFinishPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Equal("the description of the Webauthn device enrolled for user 'harry' must not be longer than 30 characters", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *WebauthnEnrollmentSuite) TestShouldForbidNonAdministrators() {
+	s.setSession([]string{"dev"}, authentication.TwoFactor)
+
+	s.mock.Ctx.Request.SetBodyString(`{"username":"harry"}`)
+
+	WebauthnEnrollmentStartPOST(s.mock.Ctx)
+
+	s.Equal(fasthttp.StatusForbidden, s.mock.Ctx.Response.StatusCode())
+
+	s.mock.Ctx.Response.Reset()
+
+	WebauthnEnrollmentFinishPOST(s.mock.Ctx)
+
+	s.Equal(fasthttp.StatusForbidden, s.mock.Ctx.Response.StatusCode())
+}
+
+func (s *WebauthnEnrollmentSuite) TestShouldForbidAdministratorsWithOneFactor() {
+	s.setSession([]string{"admins"}, authentication.OneFactor)
+
+	s.mock.Ctx.Request.SetBodyString(`{"username":"harry"}`)
+
+	WebauthnEnrollmentStartPOST(s.mock.Ctx)
+
+	s.Equal(fasthttp.StatusForbidden, s.mock.Ctx.Response.StatusCode())
+}
+
+func (s *WebauthnEnrollmentSuite) TestShouldFailStartForUnknownUser() {
+	s.mock.UserProviderMock.EXPECT().
+		GetDetails("harry").
+		Return(nil, authentication.ErrUserNotFound)
+
+	s.mock.Ctx.Request.SetBodyString(`{"username":"harry"}`)
+
+	WebauthnEnrollmentStartPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), messageOperationFailed)
+	s.Nil(s.mock.Ctx.GetSession().WebauthnEnrollment)
+}
+
+func TestRunWebauthnEnrollmentSuite(t *testing.T) {
+	suite.Run(t, new(WebauthnEnrollmentSuite))
+}
+
+// newTestWebauthnCredential creates the JSON of a PublicKeyCredential with a 'none' attestation as created by an
+// authenticator for the provided relying party id, origin, and challenge.
+func newTestWebauthnCredential(t *testing.T, rpID, origin, challenge string) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := cbor.Marshal(map[int]interface{}{
+		1:  2,
+		3:  -7,
+		-1: 1,
+		-2: key.PublicKey.X.FillBytes(make([]byte, 32)),
+		-3: key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credentialID := []byte("test-credential-id")
+	rpIDHash := sha256.Sum256([]byte(rpID))
+
+	authData := append([]byte{}, rpIDHash[:]...)
+	authData = append(authData, byte(protocol.FlagUserPresent|protocol.FlagAttestedCredentialData))
+	authData = append(authData, 0, 0, 0, 0)
+	authData = append(authData, make([]byte, 16)...)
+	authData = append(authData, byte(len(credentialID)>>8), byte(len(credentialID)))
+	authData = append(authData, credentialID...)
+	authData = append(authData, publicKey...)
+
+	attestationObject, err := cbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientData, err := json.Marshal(map[string]string{
+		"type":      "webauthn.create",
+		"challenge": challenge,
+		"origin":    origin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	credential, err := json.Marshal(map[string]interface{}{
+		"id":    base64.RawURLEncoding.EncodeToString(credentialID),
+		"rawId": base64.RawURLEncoding.EncodeToString(credentialID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    base64.RawURLEncoding.EncodeToString(clientData),
+			"attestationObject": base64.RawURLEncoding.EncodeToString(attestationObject),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(credential)
+}
@@ -1,14 +1,27 @@
 package handlers
 
 import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/ory/fosite"
 
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
-func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2ConsentSession, userSession *session.UserSession) (extraClaims map[string]interface{}) {
+func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2ConsentSession, userSession *session.UserSession,
+	customScopes map[string]schema.OpenIDConnectCustomScopeConfiguration) (extraClaims map[string]interface{}) {
 	extraClaims = map[string]interface{}{}
 
 	for _, scope := range consent.GrantedScopes {
@@ -18,27 +31,268 @@ func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2Consen
 
 		switch scope {
 		case oidc.ScopeGroups:
-			extraClaims[oidc.ClaimGroups] = userSession.Groups
+			oidcGrantClaims(extraClaims, userSession, oidc.ClaimGroups)
 		case oidc.ScopeProfile:
+			oidcGrantClaims(extraClaims, userSession, oidc.ClaimPreferredUsername, oidc.ClaimDisplayName)
+		case oidc.ScopeEmail:
+			oidcGrantClaims(extraClaims, userSession, oidc.ClaimEmail, oidc.ClaimEmailAlts, oidc.ClaimEmailVerified)
+		default:
+			if customScope, ok := customScopes[scope]; ok {
+				oidcGrantClaims(extraClaims, userSession, customScope.Claims...)
+			}
+		}
+	}
+
+	if ar != nil {
+		for _, audience := range consent.GrantedAudience {
+			ar.GrantAudience(audience)
+		}
+	}
+
+	return extraClaims
+}
+
+func oidcGrantClaims(extraClaims map[string]interface{}, userSession *session.UserSession, claims ...string) {
+	for _, claim := range claims {
+		switch claim {
+		case oidc.ClaimGroups:
+			extraClaims[oidc.ClaimGroups] = userSession.Groups
+		case oidc.ClaimPreferredUsername:
 			extraClaims[oidc.ClaimPreferredUsername] = userSession.Username
+		case oidc.ClaimDisplayName:
 			extraClaims[oidc.ClaimDisplayName] = userSession.DisplayName
-		case oidc.ScopeEmail:
+		case oidc.ClaimEmail:
 			if len(userSession.Emails) != 0 {
 				extraClaims[oidc.ClaimEmail] = userSession.Emails[0]
-				if len(userSession.Emails) > 1 {
-					extraClaims[oidc.ClaimEmailAlts] = userSession.Emails[1:]
-				}
+			}
+		case oidc.ClaimEmailAlts:
+			if len(userSession.Emails) > 1 {
+				extraClaims[oidc.ClaimEmailAlts] = userSession.Emails[1:]
+			}
+		case oidc.ClaimEmailVerified:
+			if len(userSession.Emails) != 0 {
 				// TODO (james-d-elliott): actually verify emails and record that information.
 				extraClaims[oidc.ClaimEmailVerified] = true
 			}
 		}
 	}
+}
 
-	if ar != nil {
-		for _, audience := range consent.GrantedAudience {
-			ar.GrantAudience(audience)
+// oidcApplyDefaultScopes sets the requested scopes of an authorization request which omitted the scope parameter to
+// the default scopes of the client. The default scopes are validated to be scopes the client is permitted to request.
+func oidcApplyDefaultScopes(ctx *middlewares.AutheliaCtx, requester fosite.AuthorizeRequester, client *oidc.Client) {
+	if len(requester.GetRequestedScopes()) != 0 || len(client.DefaultScopes) == 0 {
+		return
+	}
+
+	ctx.Logger.Debugf("Authorization Request with id '%s' on client with id '%s' omitted the scope parameter so the default scopes '%s' have been requested",
+		requester.GetID(), client.GetID(), strings.Join(client.DefaultScopes, " "))
+
+	requester.SetRequestedScopes(client.DefaultScopes)
+}
+
+// oidcApplyClaimsRequest adds the scopes which release the claims requested by the claims parameter of an OpenID
+// Connect authorization request to the requested scopes, so they're presented to the user for consent and the claims
+// are released once granted. Only scopes allowed for the client are added.
+func oidcApplyClaimsRequest(ctx *middlewares.AutheliaCtx, requester fosite.AuthorizeRequester, client *oidc.Client, request *oidc.ClaimsRequest) {
+	if request == nil || !requester.GetRequestedScopes().Has(oidc.ScopeOpenID) {
+		return
+	}
+
+	for _, scope := range request.Scopes(client, ctx.Providers.OpenIDConnect.CustomScopes) {
+		if !requester.GetRequestedScopes().Has(scope) {
+			ctx.Logger.Debugf("Authorization Request with id '%s' on client with id '%s' requested claims released by the scope '%s' which has been added to the requested scopes",
+				requester.GetID(), client.GetID(), scope)
+
+			requester.AppendRequestedScope(scope)
 		}
 	}
+}
 
-	return extraClaims
+// oidcCheckClaimsRequest ensures every essential claim requested by the claims parameter is released with a value
+// which satisfies the request, and that the sub claim matches when it's requested with a value. Unknown claims are
+// ignored.
+func oidcCheckClaimsRequest(request *oidc.ClaimsRequest, subject string, extraClaims map[string]interface{},
+	customScopes map[string]schema.OpenIDConnectCustomScopeConfiguration) (err error) {
+	claims := request.Claims()
+
+	names := make([]string, 0, len(claims))
+
+	for name := range claims {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		claim := claims[name]
+
+		var (
+			value interface{}
+			ok    bool
+		)
+
+		switch {
+		case name == oidc.ClaimSubject:
+			value, ok = subject, true
+		case !claim.IsEssential() || len(oidc.ClaimScopes(name, customScopes)) == 0:
+			continue
+		default:
+			value, ok = extraClaims[name]
+		}
+
+		if !ok {
+			return fmt.Errorf("the essential claim '%s' could not be provided", name)
+		}
+
+		if !claim.Matches(value) {
+			return fmt.Errorf("the claim '%s' does not have the requested value", name)
+		}
+	}
+
+	return nil
+}
+
+// oidcApplyLoginHint stores the login_hint parameter of the authorization request in the session so the login portal
+// can pre-fill the username. The hint is advisory so it's ignored when the user is already authenticated, and only
+// logged when the authenticated user doesn't match it.
+func oidcApplyLoginHint(ctx *middlewares.AutheliaCtx, requester fosite.AuthorizeRequester, userSession *session.UserSession) {
+	hint := requester.GetRequestForm().Get(oidc.FormParameterLoginHint)
+
+	switch {
+	case hint == "":
+		return
+	case userSession.Username == "":
+		userSession.LoginHint = hint
+	case hint != userSession.Username && !utils.IsStringInSliceFold(hint, userSession.Emails):
+		ctx.Logger.Debugf("Authorization Request with id '%s' on client with id '%s' has a login hint which doesn't match the authenticated user '%s', the login hint will be ignored",
+			requester.GetID(), requester.GetClient().GetID(), userSession.Username)
+	}
+}
+
+// oidcIsConsentPromptRequested returns true if the prompt parameter of the authorization request includes consent, in
+// which case the user must be asked for consent even if the client is pre-authorized.
+func oidcIsConsentPromptRequested(requester fosite.AuthorizeRequester) bool {
+	for _, prompt := range strings.Fields(requester.GetRequestForm().Get(oidc.FormParameterPrompt)) {
+		if prompt == oidc.PromptConsent {
+			return true
+		}
+	}
+
+	return false
+}
+
+// oidcHandleMaximumAuthenticationAge resets the session of the user when their last authentication is older than the
+// max_age parameter of the authorization request or the global maximum authentication age, whichever is lower, so
+// they're required to authenticate again. Requests continuing an existing consent flow are not checked as the user has
+// already been through the login portal for this request.
+func oidcHandleMaximumAuthenticationAge(ctx *middlewares.AutheliaCtx, requester fosite.AuthorizeRequester, userSession *session.UserSession) (err error) {
+	if userSession.Username == "" || userSession.ConsentChallengeID != nil {
+		return nil
+	}
+
+	maximumAge := ctx.Configuration.Session.MaximumAuthenticationAge
+	limited := maximumAge > 0
+
+	if value := requester.GetRequestForm().Get(oidc.FormParameterMaximumAge); value != "" {
+		var seconds uint64
+
+		if seconds, err = strconv.ParseUint(value, 10, 32); err != nil {
+			return fmt.Errorf("invalid max_age parameter '%s': %w", value, err)
+		}
+
+		if age := time.Duration(seconds) * time.Second; !limited || age < maximumAge {
+			maximumAge, limited = age, true
+		}
+	}
+
+	if !limited || ctx.Clock.Now().Sub(userSession.LastAuthenticatedTime()) <= maximumAge {
+		return nil
+	}
+
+	ctx.Logger.Debugf("Authorization Request with id '%s' on client with id '%s' requires user '%s' to authenticate again as their last authentication is older than the maximum authentication age of %s",
+		requester.GetID(), requester.GetClient().GetID(), userSession.Username, maximumAge)
+
+	*userSession = session.NewDefaultUserSession()
+
+	return ctx.SaveSession(*userSession)
+}
+
+// oidcApplyACRValues resolves the acr_values parameter of an authorization request form and returns the client with
+// its policy raised to the authorization level the resolved acr value requires when it's higher than the policy of the
+// client, along with the resolved acr value. The configured client is never modified as the policy only applies to
+// this authorization request.
+func oidcApplyACRValues(ctx *middlewares.AutheliaCtx, client *oidc.Client, form url.Values) (effective *oidc.Client, acr oidc.ACRValue, err error) {
+	if acr, err = ctx.Providers.OpenIDConnect.ResolveACRValues(form.Get(oidc.FormParameterACRValues)); err != nil {
+		return nil, acr, err
+	}
+
+	if acr.Level <= client.Policy {
+		return client, acr, nil
+	}
+
+	c := *client
+	c.Policy = acr.Level
+
+	return &c, acr, nil
+}
+
+// oidcGetConsentClient returns the client of a consent session with the policy required by the authorization request
+// which initiated the consent session.
+func oidcGetConsentClient(ctx *middlewares.AutheliaCtx, consent *model.OAuth2ConsentSession) (client *oidc.Client, err error) {
+	if client, err = ctx.Providers.OpenIDConnect.Store.GetFullClient(consent.ClientID); err != nil {
+		return nil, err
+	}
+
+	var form url.Values
+
+	if form, err = consent.GetForm(); err != nil {
+		return nil, fmt.Errorf("failed to parse the authorization request form: %w", err)
+	}
+
+	if client, _, err = oidcApplyACRValues(ctx, client, form); err != nil {
+		return nil, err
+	}
+
+	return oidcApplyBreakGlassPolicy(client, ctx.GetSession()), nil
+}
+
+// oidcApplyBreakGlassPolicy returns the client with the two_factor policy when the user logged in with the break-glass
+// account, as the second factor must be completed regardless of the policy of the client.
+func oidcApplyBreakGlassPolicy(client *oidc.Client, userSession session.UserSession) (effective *oidc.Client) {
+	if !userSession.BreakGlass || client.Policy == authorization.TwoFactor {
+		return client
+	}
+
+	c := *client
+	c.Policy = authorization.TwoFactor
+
+	return &c
+}
+
+// oidcGetSubject returns the subject of the user for a client. When the LDAP backend is configured with a subject
+// attribute the immutable identifier of the user is used so the subject doesn't change when the username does,
+// otherwise the subject is the opaque identifier of the username for the sector of the client.
+func oidcGetSubject(ctx *middlewares.AutheliaCtx, client *oidc.Client, userSession *session.UserSession) (subject uuid.UUID, err error) {
+	ldap := ctx.Configuration.AuthenticationBackend.LDAP
+
+	if ldap == nil || ldap.SubjectAttribute == "" || userSession.Username == "" || userSession.BreakGlass {
+		return ctx.Providers.OpenIDConnect.Store.GetSubject(ctx, client.GetSectorIdentifier(), userSession.Username)
+	}
+
+	if userSession.Subject != uuid.Nil {
+		return userSession.Subject, nil
+	}
+
+	// Sessions established before the subject attribute was configured don't have a subject yet.
+	details, err := ctx.Providers.UserDetailsCache.GetDetails(ctx.Providers.UserProvider, userSession.Username)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if details.Subject == uuid.Nil {
+		return uuid.Nil, fmt.Errorf("the authentication backend didn't return a value for attribute '%s'", ldap.SubjectAttribute)
+	}
+
+	return details.Subject, nil
 }
@@ -1,11 +1,23 @@
 package handlers
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/url"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/session"
@@ -16,7 +28,7 @@ func TestShouldGrantAppropriateClaimsForScopeProfile(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeProfile},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -32,7 +44,7 @@ func TestShouldGrantAppropriateClaimsForScopeGroups(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeGroups},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil)
 
 	assert.Len(t, extraClaims, 1)
 
@@ -41,7 +53,7 @@ func TestShouldGrantAppropriateClaimsForScopeGroups(t *testing.T) {
 	assert.Contains(t, extraClaims[oidc.ClaimGroups], "admin")
 	assert.Contains(t, extraClaims[oidc.ClaimGroups], "dev")
 
-	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred, nil)
 
 	assert.Len(t, extraClaims, 1)
 
@@ -55,7 +67,7 @@ func TestShouldGrantAppropriateClaimsForScopeEmail(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeEmail},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil)
 
 	assert.Len(t, extraClaims, 3)
 
@@ -69,7 +81,7 @@ func TestShouldGrantAppropriateClaimsForScopeEmail(t *testing.T) {
 	require.Contains(t, extraClaims, oidc.ClaimEmailVerified)
 	assert.Equal(t, true, extraClaims[oidc.ClaimEmailVerified])
 
-	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -85,7 +97,7 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeOpenID, oidc.ScopeProfile},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -95,7 +107,7 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 	require.Contains(t, extraClaims, oidc.ClaimDisplayName)
 	assert.Equal(t, "John Smith", extraClaims[oidc.ClaimDisplayName])
 
-	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -106,6 +118,33 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 	assert.Equal(t, extraClaims[oidc.ClaimDisplayName], "Fred Smith")
 }
 
+func TestShouldGrantAppropriateClaimsForCustomScope(t *testing.T) {
+	consent := &model.OAuth2ConsentSession{
+		GrantedScopes: []string{oidc.ScopeOpenID, "company:hr"},
+	}
+
+	customScopes := map[string]schema.OpenIDConnectCustomScopeConfiguration{
+		"company:hr": {
+			Name:   "company:hr",
+			Claims: []string{oidc.ClaimGroups, oidc.ClaimEmail},
+		},
+	}
+
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, customScopes)
+
+	assert.Len(t, extraClaims, 2)
+
+	require.Contains(t, extraClaims, oidc.ClaimGroups)
+	assert.Len(t, extraClaims[oidc.ClaimGroups], 2)
+
+	require.Contains(t, extraClaims, oidc.ClaimEmail)
+	assert.Equal(t, "j.smith@authelia.com", extraClaims[oidc.ClaimEmail])
+
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil)
+
+	assert.Len(t, extraClaims, 0)
+}
+
 var (
 	oidcUserSessionJohn = session.UserSession{
 		Username:    "john",
@@ -121,3 +160,257 @@ var (
 		Emails:      []string{"f.smith@authelia.com"},
 	}
 )
+
+func TestShouldApplyLoginHint(t *testing.T) {
+	testCases := []struct {
+		name     string
+		hint     string
+		have     session.UserSession
+		expected string
+	}{
+		{"ShouldApplyHintWhenNotAuthenticated", "john@example.com", session.UserSession{}, "john@example.com"},
+		{"ShouldNotApplyEmptyHint", "", session.UserSession{}, ""},
+		{"ShouldNotApplyHintWhenAuthenticatedAsSameUser", "j.smith@authelia.com", oidcUserSessionJohn, ""},
+		{"ShouldNotApplyHintWhenAuthenticatedAsDifferentUser", "fred", oidcUserSessionJohn, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			requester := fosite.NewAuthorizeRequest()
+			requester.Client = &oidc.Client{ID: "test"}
+			requester.Form = url.Values{oidc.FormParameterLoginHint: []string{tc.hint}}
+
+			userSession := tc.have
+
+			oidcApplyLoginHint(mock.Ctx, requester, &userSession)
+
+			assert.Equal(t, tc.expected, userSession.LoginHint)
+		})
+	}
+}
+
+func TestShouldHandleMaximumAuthenticationAge(t *testing.T) {
+	testCases := []struct {
+		name            string
+		global          time.Duration
+		maxAge          string
+		authenticatedAt time.Duration
+		expected        string
+		err             string
+	}{
+		{"ShouldResetWhenMaxAgeExceeded", 0, "60", time.Hour, "", ""},
+		{"ShouldKeepWhenMaxAgeNotExceeded", 0, "7200", time.Hour, "john", ""},
+		{"ShouldResetWhenGlobalExceeded", time.Minute, "", time.Hour, "", ""},
+		{"ShouldUseLowestOfMaxAgeAndGlobal", time.Minute, "7200", time.Hour, "", ""},
+		{"ShouldKeepWhenNotLimited", 0, "", time.Hour, "john", ""},
+		{"ShouldErrorOnInvalidMaxAge", 0, "abc", time.Hour, "john", "invalid max_age parameter 'abc': strconv.ParseUint: parsing \"abc\": invalid syntax"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Configuration.Session.MaximumAuthenticationAge = tc.global
+
+			requester := fosite.NewAuthorizeRequest()
+			requester.Client = &oidc.Client{ID: "test"}
+			requester.Form = url.Values{}
+
+			if tc.maxAge != "" {
+				requester.Form.Set(oidc.FormParameterMaximumAge, tc.maxAge)
+			}
+
+			userSession := oidcUserSessionJohn
+			userSession.FirstFactorAuthnTimestamp = time.Now().Add(-tc.authenticatedAt).Unix()
+
+			err := oidcHandleMaximumAuthenticationAge(mock.Ctx, requester, &userSession)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+
+			assert.Equal(t, tc.expected, userSession.Username)
+		})
+	}
+}
+
+func TestShouldApplyDefaultScopes(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	client := &oidc.Client{ID: "test", Scopes: []string{oidc.ScopeOpenID, oidc.ScopeProfile}, DefaultScopes: []string{oidc.ScopeOpenID, oidc.ScopeProfile}}
+
+	requester := fosite.NewAuthorizeRequest()
+	requester.Client = client
+
+	oidcApplyDefaultScopes(mock.Ctx, requester, client)
+
+	assert.Equal(t, fosite.Arguments{oidc.ScopeOpenID, oidc.ScopeProfile}, requester.GetRequestedScopes())
+
+	requester.RequestedScope = fosite.Arguments{oidc.ScopeOpenID}
+
+	oidcApplyDefaultScopes(mock.Ctx, requester, client)
+
+	assert.Equal(t, fosite.Arguments{oidc.ScopeOpenID}, requester.GetRequestedScopes())
+
+	client.DefaultScopes = nil
+	requester.RequestedScope = fosite.Arguments{}
+
+	oidcApplyDefaultScopes(mock.Ctx, requester, client)
+
+	assert.Len(t, requester.GetRequestedScopes(), 0)
+}
+
+func TestShouldApplyClaimsRequest(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Providers.OpenIDConnect.CustomScopes = map[string]schema.OpenIDConnectCustomScopeConfiguration{
+		"roles": {Name: "roles", Claims: []string{oidc.ClaimGroups}},
+	}
+
+	client := &oidc.Client{ID: "test", Scopes: []string{oidc.ScopeOpenID, oidc.ScopeEmail, oidc.ScopeGroups}}
+
+	request, err := oidc.NewClaimsRequest(url.Values{oidc.FormParameterClaims: []string{`{"id_token":{"email":{"essential":true},"groups":null,"name":null,"unknown":{"essential":true}}}`}})
+	require.NoError(t, err)
+
+	requester := fosite.NewAuthorizeRequest()
+	requester.Client = client
+	requester.RequestedScope = fosite.Arguments{oidc.ScopeOpenID}
+
+	oidcApplyClaimsRequest(mock.Ctx, requester, client, request)
+
+	assert.Equal(t, fosite.Arguments{oidc.ScopeOpenID, oidc.ScopeEmail, oidc.ScopeGroups}, requester.GetRequestedScopes())
+
+	requester.RequestedScope = fosite.Arguments{"offline_access"}
+
+	oidcApplyClaimsRequest(mock.Ctx, requester, client, request)
+
+	assert.Equal(t, fosite.Arguments{"offline_access"}, requester.GetRequestedScopes())
+}
+
+func TestShouldCheckClaimsRequest(t *testing.T) {
+	subject := "0b14c8e2-3a70-4b0e-9b5d-3f2a5c9d1e7f"
+
+	extraClaims := map[string]interface{}{
+		oidc.ClaimEmail:  "j.smith@authelia.com",
+		oidc.ClaimGroups: []string{"admin", "dev"},
+	}
+
+	testCases := []struct {
+		name     string
+		claims   string
+		expected string
+	}{
+		{"ShouldPassWithoutClaimsRequest", "", ""},
+		{"ShouldPassEssentialClaims", `{"id_token":{"email":{"essential":true},"groups":{"essential":true,"value":"dev"}}}`, ""},
+		{"ShouldPassVoluntaryMissingClaim", `{"userinfo":{"name":null}}`, ""},
+		{"ShouldIgnoreUnknownEssentialClaims", `{"id_token":{"unknown":{"essential":true}}}`, ""},
+		{"ShouldPassMatchingSubject", `{"id_token":{"sub":{"value":"` + subject + `"}}}`, ""},
+		{"ShouldFailEssentialMissingClaim", `{"userinfo":{"name":{"essential":true}}}`, "the essential claim 'name' could not be provided"},
+		{"ShouldFailEssentialClaimWithOtherValue", `{"id_token":{"groups":{"essential":true,"values":["ops","test"]}}}`, "the claim 'groups' does not have the requested value"},
+		{"ShouldFailOtherSubject", `{"id_token":{"sub":{"value":"abc"}}}`, "the claim 'sub' does not have the requested value"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := oidc.NewClaimsRequest(url.Values{oidc.FormParameterClaims: []string{tc.claims}})
+			require.NoError(t, err)
+
+			err = oidcCheckClaimsRequest(request, subject, extraClaims, nil)
+
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestShouldApplyACRValues(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	mock.Ctx.Providers.OpenIDConnect, err = oidc.NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		HMACSecret:       "asbdhaaskmdlkamdklasmdlkamsasbdhaaskmdlkamdklasmdlkams",
+		UnknownACRValues: schema.UnknownACRValuesError,
+		ACRValues: []schema.OpenIDConnectACRValueConfiguration{
+			{Value: "urn:example:loa:1", Policy: "one_factor"},
+			{Value: "urn:example:loa:2", Policy: "two_factor"},
+		},
+	}, mock.StorageMock)
+	require.NoError(t, err)
+
+	client := &oidc.Client{ID: "test", Policy: authorization.OneFactor}
+
+	effective, acr, err := oidcApplyACRValues(mock.Ctx, client, url.Values{})
+	assert.NoError(t, err)
+	assert.Equal(t, "", acr.Value)
+	assert.Equal(t, client, effective)
+
+	effective, acr, err = oidcApplyACRValues(mock.Ctx, client, url.Values{oidc.FormParameterACRValues: []string{"urn:example:loa:1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:example:loa:1", acr.Value)
+	assert.Equal(t, authorization.OneFactor, effective.Policy)
+
+	effective, acr, err = oidcApplyACRValues(mock.Ctx, client, url.Values{oidc.FormParameterACRValues: []string{"urn:example:loa:2 urn:example:loa:1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:example:loa:2", acr.Value)
+	assert.Equal(t, authorization.TwoFactor, effective.Policy)
+	assert.Equal(t, authorization.OneFactor, client.Policy)
+
+	effective, _, err = oidcApplyACRValues(mock.Ctx, client, url.Values{oidc.FormParameterACRValues: []string{"urn:example:unknown"}})
+	assert.EqualError(t, err, "the acr value 'urn:example:unknown' is not supported")
+	assert.Nil(t, effective)
+}
+
+func TestShouldGetSubjectFromSubjectAttribute(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.AuthenticationBackend.LDAP = &schema.LDAPAuthenticationBackendConfiguration{
+		SubjectAttribute: "entryUUID",
+	}
+
+	expected := uuid.MustParse("597ae2f6-16a6-1027-98f4-ab46f1f1a6d7")
+
+	t.Run("ShouldUseSessionSubject", func(t *testing.T) {
+		subject, err := oidcGetSubject(mock.Ctx, &oidc.Client{}, &session.UserSession{Username: testUsername, Subject: expected})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, subject)
+	})
+
+	t.Run("ShouldRetrieveSubjectForSessionWithoutSubject", func(t *testing.T) {
+		mock.UserProviderMock.EXPECT().
+			GetDetails(testUsername).
+			Return(&authentication.UserDetails{Username: testUsername, Subject: expected}, nil)
+
+		subject, err := oidcGetSubject(mock.Ctx, &oidc.Client{}, &session.UserSession{Username: testUsername})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, subject)
+	})
+
+	t.Run("ShouldRaiseErrorWhenBackendHasNoSubject", func(t *testing.T) {
+		mock.UserProviderMock.EXPECT().
+			GetDetails(testUsername).
+			Return(&authentication.UserDetails{Username: testUsername}, nil)
+
+		subject, err := oidcGetSubject(mock.Ctx, &oidc.Client{}, &session.UserSession{Username: testUsername})
+
+		assert.EqualError(t, err, "the authentication backend didn't return a value for attribute 'entryUUID'")
+		assert.Equal(t, uuid.Nil, subject)
+	})
+}
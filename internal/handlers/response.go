@@ -2,14 +2,20 @@ package handlers
 
 import (
 	"fmt"
+	"net"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/events"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -43,7 +49,7 @@ func handleOIDCWorkflowResponse(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	client, err := ctx.Providers.OpenIDConnect.Store.GetFullClient(consent.ClientID)
+	client, err := oidcGetConsentClient(ctx, consent)
 	if err != nil {
 		ctx.Logger.Errorf("Unable to find client for the consent session: %v", err)
 
@@ -73,8 +79,8 @@ func handleOIDCWorkflowResponse(ctx *middlewares.AutheliaCtx) {
 // Handle1FAResponse handle the redirection upon 1FA authentication.
 func Handle1FAResponse(ctx *middlewares.AutheliaCtx, targetURI, requestMethod string, username string, groups []string) {
 	if targetURI == "" {
-		if !ctx.Providers.Authorizer.IsSecondFactorEnabled() && ctx.Configuration.DefaultRedirectionURL != "" {
-			err := ctx.SetJSONBody(redirectResponse{Redirect: ctx.Configuration.DefaultRedirectionURL})
+		if defaultRedirectionURL := getDefaultRedirectionURL(ctx); !ctx.Providers.Authorizer.IsSecondFactorEnabled() && defaultRedirectionURL != "" {
+			err := ctx.SetJSONBody(redirectResponse{Redirect: defaultRedirectionURL})
 			if err != nil {
 				ctx.Logger.Errorf("Unable to set default redirection URL in body: %s", err)
 			}
@@ -91,17 +97,23 @@ func Handle1FAResponse(ctx *middlewares.AutheliaCtx, targetURI, requestMethod st
 		return
 	}
 
+	object := authorization.NewObject(targetURL, requestMethod)
+	object.Header = ctx.RequestHeader()
+
 	requiredLevel := ctx.Providers.Authorizer.GetRequiredLevel(
 		authorization.Subject{
 			Username: username,
 			Groups:   groups,
 			IP:       ctx.RemoteIP(),
 		},
-		authorization.NewObject(targetURL, requestMethod))
+		object)
 
 	ctx.Logger.Debugf("Required level for the URL %s is %d", targetURI, requiredLevel)
 
-	if requiredLevel == authorization.TwoFactor {
+	userSession := ctx.GetSession()
+
+	if requiredLevel == authorization.TwoFactor ||
+		(requiredLevel == authorization.OneFactor && (userSession.ImpossibleTravel || userSession.SecondFactorEnrollmentRequired || userSession.BreakGlass)) {
 		ctx.Logger.Warnf("%s requires 2FA, cannot be redirected yet", targetURI)
 		ctx.ReplyOK()
 
@@ -113,8 +125,8 @@ func Handle1FAResponse(ctx *middlewares.AutheliaCtx, targetURI, requestMethod st
 	if !safeRedirection {
 		ctx.Logger.Debugf("Redirection URL %s is not safe", targetURI)
 
-		if !ctx.Providers.Authorizer.IsSecondFactorEnabled() && ctx.Configuration.DefaultRedirectionURL != "" {
-			err := ctx.SetJSONBody(redirectResponse{Redirect: ctx.Configuration.DefaultRedirectionURL})
+		if defaultRedirectionURL := getDefaultRedirectionURL(ctx); !ctx.Providers.Authorizer.IsSecondFactorEnabled() && defaultRedirectionURL != "" {
+			err := ctx.SetJSONBody(redirectResponse{Redirect: defaultRedirectionURL})
 			if err != nil {
 				ctx.Logger.Errorf("Unable to set default redirection URL in body: %s", err)
 			}
@@ -136,8 +148,8 @@ func Handle1FAResponse(ctx *middlewares.AutheliaCtx, targetURI, requestMethod st
 // Handle2FAResponse handle the redirection upon 2FA authentication.
 func Handle2FAResponse(ctx *middlewares.AutheliaCtx, targetURI string) {
 	if targetURI == "" {
-		if ctx.Configuration.DefaultRedirectionURL != "" {
-			err := ctx.SetJSONBody(redirectResponse{Redirect: ctx.Configuration.DefaultRedirectionURL})
+		if defaultRedirectionURL := getDefaultRedirectionURL(ctx); defaultRedirectionURL != "" {
+			err := ctx.SetJSONBody(redirectResponse{Redirect: defaultRedirectionURL})
 			if err != nil {
 				ctx.Logger.Errorf("Unable to set default redirection URL in body: %s", err)
 			}
@@ -167,6 +179,43 @@ func Handle2FAResponse(ctx *middlewares.AutheliaCtx, targetURI string) {
 	}
 }
 
+// getDefaultRedirectionURL returns the URL users are redirected to after authentication when no target URL was
+// provided. The session default redirection URL of the most specific domain matching the host the portal is accessed
+// on takes precedence over the global default redirection URL.
+func getDefaultRedirectionURL(ctx *middlewares.AutheliaCtx) (redirectionURL string) {
+	host := string(ctx.XForwardedHost())
+
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	host = strings.ToLower(host)
+
+	var matched string
+
+	for _, entry := range ctx.Configuration.Session.DefaultRedirectionURLs {
+		domain := strings.ToLower(entry.Domain)
+
+		if (host != domain && !strings.HasSuffix(host, "."+domain)) || len(domain) <= len(matched) {
+			continue
+		}
+
+		if safe, err := utils.IsRedirectionURISafe(entry.URL, ctx.Configuration.Session.Domain); err != nil || !safe {
+			ctx.Logger.Warnf("Default redirection URL %s for the domain %s is not safe", entry.URL, entry.Domain)
+
+			continue
+		}
+
+		matched, redirectionURL = domain, entry.URL
+	}
+
+	if redirectionURL != "" {
+		return redirectionURL
+	}
+
+	return ctx.Configuration.DefaultRedirectionURL
+}
+
 func markAuthenticationAttempt(ctx *middlewares.AutheliaCtx, successful bool, bannedUntil *time.Time, username string, authType string, errAuth error) (err error) {
 	// We only Mark if there was no underlying error.
 	ctx.Logger.Debugf("Mark %s authentication attempt made by user '%s'", authType, username)
@@ -184,12 +233,21 @@ func markAuthenticationAttempt(ctx *middlewares.AutheliaCtx, successful bool, ba
 		}
 	}
 
-	if err = ctx.Providers.Regulator.Mark(ctx, successful, bannedUntil != nil, username, requestURI, requestMethod, authType, ctx.RemoteIP()); err != nil {
+	if err = ctx.Providers.Regulator.Mark(ctx, successful, bannedUntil != nil, username, requestURI, requestMethod, authType, ctx.RemoteIP(), string(ctx.UserAgent())); err != nil {
 		ctx.Logger.Errorf("Unable to mark %s authentication attempt by user '%s': %+v", authType, username, err)
 
 		return err
 	}
 
+	ctx.Providers.Events.Emit(events.Event{
+		Type:       events.TypeAuthenticationAttempt,
+		Username:   username,
+		RemoteIP:   ctx.RemoteIP().String(),
+		Method:     authType,
+		Successful: &successful,
+		Banned:     bannedUntil != nil,
+	})
+
 	if successful {
 		ctx.Logger.Debugf("Successful %s authentication attempt made by user '%s'", authType, username)
 	} else {
@@ -206,11 +264,62 @@ func markAuthenticationAttempt(ctx *middlewares.AutheliaCtx, successful bool, ba
 	return nil
 }
 
+// markSecondFactorAttemptFailed marks a failed second factor attempt and counts the failed second factor attempts of
+// the user since the first or second factor of the session was last completed. The attempts are counted in the storage
+// so concurrent attempts can't bypass the limit. Once the configured maximum number of failed attempts is reached the session is
+// destroyed so the first factor has to be completed again, and the user is also banned when the configured action is
+// to ban.
+func markSecondFactorAttemptFailed(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, authType string, errAuth error) {
+	_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, authType, errAuth)
+
+	config := ctx.Configuration.Regulation.SecondFactor
+
+	if config.MaxAttempts <= 0 {
+		return
+	}
+
+	since := userSession.FirstFactorAuthnTimestamp
+
+	if userSession.SecondFactorAuthnTimestamp > since {
+		since = userSession.SecondFactorAuthnTimestamp
+	}
+
+	failed, err := ctx.Providers.StorageProvider.CountSecondFactorFailedAuthenticationLogs(ctx, userSession.Username, time.Unix(since, 0))
+	if err != nil {
+		ctx.Logger.Errorf("Unable to count the failed second factor attempts of user '%s': %+v", userSession.Username, err)
+
+		return
+	}
+
+	if failed < config.MaxAttempts {
+		return
+	}
+
+	ctx.Logger.Warnf("User '%s' reached the maximum of %d consecutive failed second factor attempts, the session will be destroyed", userSession.Username, config.MaxAttempts)
+
+	if config.Action == schema.SecondFactorLimitActionBan {
+		bannedUntil := ctx.Clock.Now().Add(ctx.Configuration.Regulation.BanTime)
+
+		_ = markAuthenticationAttempt(ctx, false, &bannedUntil, userSession.Username, regulation.AuthTypeSecondFactorLimit, nil)
+
+		sendRegulationUnlock(ctx, userSession.Username)
+	}
+
+	if err := ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx); err != nil {
+		ctx.Logger.Errorf("Unable to destroy the session of user '%s' after they reached the maximum of failed second factor attempts: %+v", userSession.Username, err)
+	}
+}
+
 func respondUnauthorized(ctx *middlewares.AutheliaCtx, message string) {
 	ctx.SetStatusCode(fasthttp.StatusUnauthorized)
 	ctx.SetJSONError(message)
 }
 
+func respondServiceUnavailable(ctx *middlewares.AutheliaCtx, message string) {
+	ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+	ctx.SetJSONError(message)
+}
+
 // SetStatusCodeResponse writes a response status code and an appropriate body on either a
 // *fasthttp.RequestCtx or *middlewares.AutheliaCtx.
 func SetStatusCodeResponse(ctx responseWriter, statusCode int) {
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func TestShouldGetDefaultRedirectionURL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		host     string
+		expected string
+	}{
+		{"ShouldUseMostSpecificDomain", "auth.dev.example.com", "https://dashboard.dev.example.com"},
+		{"ShouldUseDomainIgnoringPortAndCase", "Auth.Example.com:9091", "https://home.example.com"},
+		{"ShouldUseExactDomain", "dev.example.com", "https://dashboard.dev.example.com"},
+		{"ShouldSkipUnsafeURL", "auth.unsafe.example.com", "https://home.example.com"},
+		{"ShouldFallbackToGlobal", "auth.example.org", "https://global.example.com"},
+		{"ShouldNotMatchPartialLabel", "auth.notexample.com", "https://global.example.com"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Configuration.DefaultRedirectionURL = "https://global.example.com"
+			mock.Ctx.Configuration.Session.Domain = "example.com"
+			mock.Ctx.Configuration.Session.DefaultRedirectionURLs = []schema.SessionDefaultRedirectionURLConfiguration{
+				{Domain: "example.com", URL: "https://home.example.com"},
+				{Domain: "dev.example.com", URL: "https://dashboard.dev.example.com"},
+				{Domain: "unsafe.example.com", URL: "http://dashboard.unsafe.example.com"},
+			}
+
+			mock.Ctx.Request.Header.Set("X-Forwarded-Host", tc.host)
+
+			assert.Equal(t, tc.expected, getDefaultRedirectionURL(mock.Ctx))
+		})
+	}
+}
+
+func TestShouldRedirectToDomainDefaultRedirectionURLAfterSecondFactor(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.DefaultRedirectionURL = "https://global.example.com"
+	mock.Ctx.Configuration.Session.Domain = "example.com"
+	mock.Ctx.Configuration.Session.DefaultRedirectionURLs = []schema.SessionDefaultRedirectionURLConfiguration{
+		{Domain: "dev.example.com", URL: "https://dashboard.dev.example.com"},
+	}
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.dev.example.com")
+
+	Handle2FAResponse(mock.Ctx, "")
+
+	mock.Assert200OK(t, redirectResponse{Redirect: "https://dashboard.dev.example.com"})
+
+	mock.Ctx.Response.Reset()
+
+	// An explicit target URL takes precedence over the default redirection URL.
+	Handle2FAResponse(mock.Ctx, "https://app.example.com")
+
+	mock.Assert200OK(t, redirectResponse{Redirect: "https://app.example.com"})
+
+	mock.Ctx.Response.Reset()
+
+	Handle2FAResponse(mock.Ctx, "https://app.example.org")
+
+	mock.Assert200OK(t, nil)
+}
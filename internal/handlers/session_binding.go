@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// bindSession records the coarse fingerprint of the client in the session when the session binding is enabled. Both
+// parts of the fingerprint are always recorded so changing the binding mode doesn't require a new login.
+func bindSession(ctx *middlewares.AutheliaCtx, userSession *session.UserSession) {
+	if !isSessionBindingEnabled(ctx) {
+		return
+	}
+
+	userSession.BindingUserAgent, userSession.BindingNetwork = getSessionFingerprint(ctx)
+}
+
+// isSessionBindingMismatch returns true when the session binding is enabled and the client doesn't match the
+// fingerprint recorded in the session. Sessions without a recorded fingerprint never match.
+func isSessionBindingMismatch(ctx *middlewares.AutheliaCtx, userSession *session.UserSession) bool {
+	if !isSessionBindingEnabled(ctx) {
+		return false
+	}
+
+	userAgent, network := getSessionFingerprint(ctx)
+
+	switch ctx.Configuration.Session.Binding.Mode {
+	case schema.SessionBindingModeUserAgent:
+		return userSession.BindingUserAgent != userAgent
+	case schema.SessionBindingModeNetwork:
+		return userSession.BindingNetwork == "" || userSession.BindingNetwork != network
+	default:
+		return userSession.BindingNetwork == "" || userSession.BindingUserAgent != userAgent || userSession.BindingNetwork != network
+	}
+}
+
+func isSessionBindingEnabled(ctx *middlewares.AutheliaCtx) bool {
+	mode := ctx.Configuration.Session.Binding.Mode
+
+	return mode != "" && mode != schema.SessionBindingModeNone
+}
+
+// getSessionFingerprint returns the user agent of the client and the network of the client IP with the configured mask
+// so IP changes within the same network don't invalidate the session.
+func getSessionFingerprint(ctx *middlewares.AutheliaCtx) (userAgent, network string) {
+	config := ctx.Configuration.Session.Binding
+
+	ip, ones, bits := ctx.RemoteIP(), config.IPv6Mask, 128
+
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ip, ones, bits = ipv4, config.IPv4Mask, 32
+	}
+
+	mask := net.CIDRMask(ones, bits)
+
+	return string(ctx.UserAgent()), (&net.IPNet{IP: ip.Mask(mask), Mask: mask}).String()
+}
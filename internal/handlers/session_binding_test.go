@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+func TestShouldBindSessionToClientFingerprint(t *testing.T) {
+	testCases := []struct {
+		name                string
+		mode                string
+		userAgent, remoteIP string
+		expected            bool
+	}{
+		{"ShouldNotCheckWhenDisabled", schema.SessionBindingModeNone, "other", "10.1.0.1", false},
+		{"ShouldMatchSameClient", schema.SessionBindingModeUserAgentAndNetwork, "agent", "192.168.1.10", false},
+		{"ShouldMatchIPWithinIPv4Mask", schema.SessionBindingModeUserAgentAndNetwork, "agent", "192.168.1.200", false},
+		{"ShouldNotMatchIPOutsideIPv4Mask", schema.SessionBindingModeUserAgentAndNetwork, "agent", "192.168.2.10", true},
+		{"ShouldNotMatchOtherUserAgent", schema.SessionBindingModeUserAgentAndNetwork, "other", "192.168.1.10", true},
+		{"ShouldOnlyCheckUserAgent", schema.SessionBindingModeUserAgent, "agent", "10.1.0.1", false},
+		{"ShouldNotMatchOtherUserAgentOnly", schema.SessionBindingModeUserAgent, "other", "192.168.1.10", true},
+		{"ShouldOnlyCheckNetwork", schema.SessionBindingModeNetwork, "other", "192.168.1.20", false},
+		{"ShouldNotMatchOtherNetworkOnly", schema.SessionBindingModeNetwork, "agent", "10.1.0.1", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Configuration.Session.Binding = schema.SessionBindingConfiguration{
+				Mode:     schema.SessionBindingModeUserAgentAndNetwork,
+				IPv4Mask: 24,
+				IPv6Mask: 64,
+			}
+
+			mock.Ctx.Request.Header.Set("User-Agent", "agent")
+			mock.Ctx.Request.Header.Set("X-Forwarded-For", "192.168.1.10")
+
+			userSession := session.NewDefaultUserSession()
+
+			bindSession(mock.Ctx, &userSession)
+
+			assert.Equal(t, "agent", userSession.BindingUserAgent)
+			assert.Equal(t, "192.168.1.0/24", userSession.BindingNetwork)
+
+			mock.Ctx.Configuration.Session.Binding.Mode = tc.mode
+
+			mock.Ctx.Request.Header.Set("User-Agent", tc.userAgent)
+			mock.Ctx.Request.Header.Set("X-Forwarded-For", tc.remoteIP)
+
+			assert.Equal(t, tc.expected, isSessionBindingMismatch(mock.Ctx, &userSession))
+		})
+	}
+}
+
+func TestShouldBindSessionToIPv6Network(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.Session.Binding = schema.SessionBindingConfiguration{
+		Mode:     schema.SessionBindingModeNetwork,
+		IPv4Mask: 24,
+		IPv6Mask: 64,
+	}
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-For", "2001:db8:1:2::10")
+
+	userSession := session.NewDefaultUserSession()
+
+	bindSession(mock.Ctx, &userSession)
+
+	assert.Equal(t, "2001:db8:1:2::/64", userSession.BindingNetwork)
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-For", "2001:db8:1:2::20")
+	assert.False(t, isSessionBindingMismatch(mock.Ctx, &userSession))
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-For", "2001:db8:1:3::10")
+	assert.True(t, isSessionBindingMismatch(mock.Ctx, &userSession))
+}
+
+func TestShouldNotMatchSessionWithoutBinding(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.Session.Binding = schema.SessionBindingConfiguration{
+		Mode:     schema.SessionBindingModeNetwork,
+		IPv4Mask: 24,
+		IPv6Mask: 64,
+	}
+
+	userSession := session.NewDefaultUserSession()
+
+	assert.True(t, isSessionBindingMismatch(mock.Ctx, &userSession))
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"encoding/json"
 	"io"
+	"time"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 )
@@ -44,6 +46,7 @@ type firstFactorRequestBody struct {
 	TargetURL      string `json:"targetURL"`
 	RequestMethod  string `json:"requestMethod"`
 	KeepMeLoggedIn *bool  `json:"keepMeLoggedIn"`
+	CAPTCHAToken   string `json:"captchaToken"`
 	// KeepMeLoggedIn: Cannot require this field because of https://github.com/asaskevich/govalidator/pull/329
 	// TODO(c.michaud): add required validation once the above PR is merged.
 }
@@ -70,6 +73,43 @@ type TOTPKeyResponse struct {
 	OTPAuthURL   string `json:"otpauth_url"`
 }
 
+// totpRegistrationBody is the optional model of the body sent to the TOTP identity verification finish endpoint.
+type totpRegistrationBody struct {
+	Description string `json:"description"`
+}
+
+// totpRotationStartRequestBody is the model of the body sent to the TOTP rotation start endpoint, the token must be a
+// code of the registration being rotated.
+type totpRotationStartRequestBody struct {
+	ID    int    `json:"id" valid:"required"`
+	Token string `json:"token" valid:"required"`
+}
+
+// totpRotationFinishRequestBody is the model of the body sent to the TOTP rotation finish endpoint, the token must be a
+// code of the new secret.
+type totpRotationFinishRequestBody struct {
+	Token string `json:"token" valid:"required"`
+}
+
+// userTOTPInfoResponse is the model of the response sent by the TOTP info endpoint. The digits and period of the first
+// registration are included at the top level for compatibility with clients that only support a single registration.
+type userTOTPInfoResponse struct {
+	Digits        uint                       `json:"digits"`
+	Period        uint                       `json:"period"`
+	Registrations []userTOTPInfoRegistration `json:"registrations"`
+}
+
+// userTOTPInfoRegistration is the model of a single TOTP registration in the TOTP info response.
+type userTOTPInfoRegistration struct {
+	ID          int        `json:"id"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	Algorithm   string     `json:"algorithm"`
+	Digits      uint       `json:"digits"`
+	Period      uint       `json:"period"`
+}
+
 // DuoDeviceBody the selected Duo device and method.
 type DuoDeviceBody struct {
 	Device string `json:"device" valid:"required"`
@@ -103,6 +143,63 @@ type StateResponse struct {
 	Username              string               `json:"username"`
 	AuthenticationLevel   authentication.Level `json:"authentication_level"`
 	DefaultRedirectionURL string               `json:"default_redirection_url"`
+
+	// SessionRemaining is the number of seconds before the session expires due to inactivity, it's omitted if the
+	// session doesn't expire due to inactivity.
+	SessionRemaining *int64 `json:"session_remaining,omitempty"`
+
+	// InactivityWarning is the number of seconds before the session expires the user should be warned.
+	InactivityWarning int64 `json:"inactivity_warning,omitempty"`
+
+	// CAPTCHA is the CAPTCHA challenge the user must complete before attempting the first factor, it's omitted if no
+	// challenge is required.
+	CAPTCHA *StateCAPTCHAResponse `json:"captcha,omitempty"`
+
+	// Capabilities are the features of the portal enabled by the configuration.
+	Capabilities Capabilities `json:"capabilities"`
+}
+
+// Capabilities represents the features of the portal enabled by the configuration so the portal only renders the
+// enabled features.
+type Capabilities struct {
+	ResetPassword          bool   `json:"reset_password"`
+	ResetPasswordMethod    string `json:"reset_password_method,omitempty"`
+	ResetPasswordCustomURL string `json:"reset_password_custom_url,omitempty"`
+	RememberMe             bool   `json:"remember_me"`
+	SelfRegistration       bool   `json:"self_registration"`
+	DuoSelfEnrollment      bool   `json:"duo_self_enrollment"`
+
+	// SecondFactorMethods are the globally enabled 2FA methods.
+	SecondFactorMethods []string `json:"second_factor_methods"`
+
+	// IdentityProviders are the identity providers other than the password form users can authenticate with.
+	IdentityProviders []string `json:"identity_providers"`
+}
+
+// StateCAPTCHAResponse represents the CAPTCHA challenge sent by the state endpoint.
+type StateCAPTCHAResponse struct {
+	Provider string `json:"provider"`
+	SiteKey  string `json:"site_key"`
+}
+
+// SessionRefreshResponse represents the response sent by the session refresh endpoint.
+type SessionRefreshResponse struct {
+	SessionRemaining *int64 `json:"session_remaining,omitempty"`
+}
+
+// sessionIntrospectionRequestBody represents the body of the session introspection request.
+type sessionIntrospectionRequestBody struct {
+	SessionID string `json:"session_id"`
+}
+
+// SessionIntrospectionResponse represents the response sent by the session introspection endpoint.
+type SessionIntrospectionResponse struct {
+	Authenticated       bool                 `json:"authenticated"`
+	Username            string               `json:"username,omitempty"`
+	DisplayName         string               `json:"display_name,omitempty"`
+	Groups              []string             `json:"groups,omitempty"`
+	AuthenticationLevel authentication.Level `json:"authentication_level"`
+	ExpiresAt           *int64               `json:"expires_at,omitempty"`
 }
 
 // resetPasswordStep1RequestBody model of the reset password (step1) request body.
@@ -110,11 +207,104 @@ type resetPasswordStep1RequestBody struct {
 	Username string `json:"username"`
 }
 
+// resetPasswordAdminCodeRequestBody model of the reset password request body when verifying the identity with a code
+// issued by an administrator.
+type resetPasswordAdminCodeRequestBody struct {
+	Username string `json:"username"`
+	Code     string `json:"code"`
+}
+
 // resetPasswordStep2RequestBody model of the reset password (step2) request body.
 type resetPasswordStep2RequestBody struct {
 	Password string `json:"password"`
 }
 
+// registrationRequestBody represents the JSON body received by the self-registration endpoint.
+type registrationRequestBody struct {
+	Username    string `json:"username" valid:"required"`
+	DisplayName string `json:"displayname" valid:"required"`
+	Email       string `json:"email" valid:"required,email"`
+	Password    string `json:"password" valid:"required"`
+}
+
+// registrationApprovalRequestBody represents the JSON body received by the self-registration approval endpoint.
+type registrationApprovalRequestBody struct {
+	Username string `json:"username" valid:"required"`
+	Approved bool   `json:"approved"`
+}
+
+// userDisabledRequestBody represents the JSON body received by the user disabled endpoint.
+type userDisabledRequestBody struct {
+	Username string `json:"username" valid:"required"`
+	Disabled bool   `json:"disabled"`
+}
+
+// webauthnEnrollmentStartRequestBody represents the JSON body received by the Webauthn enrollment start endpoint.
+type webauthnEnrollmentStartRequestBody struct {
+	Username string `json:"username" valid:"required"`
+}
+
+// webauthnEnrollmentFinishRequestBody represents the JSON body received by the Webauthn enrollment finish endpoint, the
+// credential is the PublicKeyCredential returned by the authenticator during provisioning.
+type webauthnEnrollmentFinishRequestBody struct {
+	Description string          `json:"description"`
+	Credential  json.RawMessage `json:"credential"`
+}
+
+// registrationResponse represents a pending self-registration sent to administrators.
+type registrationResponse struct {
+	Username    string    `json:"username"`
+	DisplayName string    `json:"displayname"`
+	Email       string    `json:"email"`
+	RequestedAt time.Time `json:"requested_at"`
+
+	// EmailVerified is only included when email verification is enabled.
+	EmailVerified *bool `json:"email_verified,omitempty"`
+}
+
+// userActivityResponse represents the recent authentication activity and active OpenID Connect grants of a user.
+type userActivityResponse struct {
+	Page                 int                               `json:"page"`
+	Limit                int                               `json:"limit"`
+	AuthenticationEvents []userActivityAuthenticationEvent `json:"authentication_events"`
+	Grants               []userActivityGrant               `json:"grants"`
+}
+
+// userActivityAuthenticationEvent represents an authentication attempt of a user.
+type userActivityAuthenticationEvent struct {
+	Time       time.Time `json:"time"`
+	Type       string    `json:"type"`
+	Successful bool      `json:"successful"`
+	Banned     bool      `json:"banned"`
+	RemoteIP   string    `json:"remote_ip,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+}
+
+// userActivityGrant represents an active OpenID Connect consent granted by a user.
+type userActivityGrant struct {
+	ClientID  string     `json:"client_id"`
+	Scopes    []string   `json:"scopes"`
+	Audience  []string   `json:"audience"`
+	GrantedAt *time.Time `json:"granted_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// oidcRefreshTokenResponse represents an active OpenID Connect refresh token of a user.
+type oidcRefreshTokenResponse struct {
+	ID         string     `json:"id"`
+	ClientID   string     `json:"client_id"`
+	Scopes     []string   `json:"scopes"`
+	IssuedAt   time.Time  `json:"issued_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+}
+
+// oidcRefreshTokenRevokeRequestBody represents the JSON body received by the refresh token revocation endpoint.
+type oidcRefreshTokenRevokeRequestBody struct {
+	Username string `json:"username"`
+	ID       string `json:"id" valid:"required"`
+}
+
 // PassworPolicyBody represents the response sent by the password reset step 2.
 type PassworPolicyBody struct {
 	Mode             string `json:"mode"`
@@ -3,6 +3,7 @@ package handlers
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
@@ -10,6 +11,7 @@ import (
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 func getWebAuthnUser(ctx *middlewares.AutheliaCtx, userSession session.UserSession) (user *model.WebauthnUser, err error) {
@@ -41,6 +43,18 @@ func newWebauthn(ctx *middlewares.AutheliaCtx) (w *webauthn.WebAuthn, err error)
 	rpID := u.Hostname()
 	origin := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
 
+	if len(ctx.Configuration.Webauthn.AllowedOrigins) != 0 && !isWebauthnOriginAllowed(origin, ctx.Configuration.Webauthn.AllowedOrigins) {
+		return nil, fmt.Errorf("the origin '%s' is not one of the allowed origins", origin)
+	}
+
+	if ctx.Configuration.Webauthn.RPID != "" {
+		rpID = ctx.Configuration.Webauthn.RPID
+
+		if !utils.IsHostnameInDomain(u.Hostname(), rpID) {
+			return nil, fmt.Errorf("the origin '%s' is not permitted for the relying party id '%s'", origin, rpID)
+		}
+	}
+
 	config := &webauthn.Config{
 		RPDisplayName: ctx.Configuration.Webauthn.DisplayName,
 		RPID:          rpID,
@@ -61,3 +75,15 @@ func newWebauthn(ctx *middlewares.AutheliaCtx) (w *webauthn.WebAuthn, err error)
 
 	return webauthn.New(config)
 }
+
+func isWebauthnOriginAllowed(origin string, allowed []url.URL) bool {
+	for _, allowedOrigin := range allowed {
+		allowedOrigin = utils.OriginFromURL(allowedOrigin)
+
+		if strings.EqualFold(origin, allowedOrigin.String()) {
+			return true
+		}
+	}
+
+	return false
+}
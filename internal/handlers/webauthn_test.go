@@ -2,12 +2,14 @@ package handlers
 
 import (
 	"errors"
+	"net/url"
 	"testing"
 
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/session"
@@ -165,3 +167,58 @@ func TestWebauthnNewWebauthnShouldReturnErrWhenWebauthnNotConfigured(t *testing.
 	assert.Nil(t, w)
 	assert.EqualError(t, err, "Configuration error: Missing RPDisplayName")
 }
+
+func TestWebauthnNewWebauthnShouldUseConfiguredRPID(t *testing.T) {
+	ctx := mocks.NewMockAutheliaCtx(t)
+
+	ctx.Ctx.Configuration.Webauthn = schema.DefaultWebauthnConfiguration
+	ctx.Ctx.Configuration.Webauthn.RPID = "example.com"
+	ctx.Ctx.Configuration.Webauthn.AllowedOrigins = []url.URL{
+		{Scheme: "https", Host: "auth.example.com"},
+		{Scheme: "https", Host: "login.internal.example.com:8443"},
+	}
+
+	ctx.Ctx.Request.Header.Set("X-Forwarded-Host", "login.internal.example.com:8443")
+	ctx.Ctx.Request.Header.Set("X-Forwarded-URI", "/")
+	ctx.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+
+	w, err := newWebauthn(ctx.Ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", w.Config.RPID)
+	assert.Equal(t, "https://login.internal.example.com:8443", w.Config.RPOrigin)
+}
+
+func TestWebauthnNewWebauthnShouldReturnErrWhenOriginNotAllowed(t *testing.T) {
+	ctx := mocks.NewMockAutheliaCtx(t)
+
+	ctx.Ctx.Configuration.Webauthn = schema.DefaultWebauthnConfiguration
+	ctx.Ctx.Configuration.Webauthn.AllowedOrigins = []url.URL{
+		{Scheme: "https", Host: "auth.example.com"},
+	}
+
+	ctx.Ctx.Request.Header.Set("X-Forwarded-Host", "login.example.com")
+	ctx.Ctx.Request.Header.Set("X-Forwarded-URI", "/")
+	ctx.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+
+	w, err := newWebauthn(ctx.Ctx)
+
+	assert.Nil(t, w)
+	assert.EqualError(t, err, "the origin 'https://login.example.com' is not one of the allowed origins")
+}
+
+func TestWebauthnNewWebauthnShouldReturnErrWhenOriginNotInRPID(t *testing.T) {
+	ctx := mocks.NewMockAutheliaCtx(t)
+
+	ctx.Ctx.Configuration.Webauthn = schema.DefaultWebauthnConfiguration
+	ctx.Ctx.Configuration.Webauthn.RPID = "example.com"
+
+	ctx.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.org")
+	ctx.Ctx.Request.Header.Set("X-Forwarded-URI", "/")
+	ctx.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+
+	w, err := newWebauthn(ctx.Ctx)
+
+	assert.Nil(t, w)
+	assert.EqualError(t, err, "the origin 'https://auth.example.org' is not permitted for the relying party id 'example.com'")
+}
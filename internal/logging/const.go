@@ -1,3 +1,84 @@
 package logging
 
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
 const logFormatJSON = "json"
+
+const (
+	syslogSeverityCritical      = 2
+	syslogSeverityError         = 3
+	syslogSeverityWarning       = 4
+	syslogSeverityInformational = 6
+	syslogSeverityDebug         = 7
+)
+
+const syslogDialTimeout = 5 * time.Second
+
+const (
+	// outputQueueSize is the number of entries queued for the syslog and journald outputs before they're dropped.
+	outputQueueSize      = 1024
+	outputWriteTimeout   = 5 * time.Second
+	outputRedialInterval = 5 * time.Second
+)
+
+// syslogTLSVersions maps the names of the TLS versions to their codes, the utils package can't be used as it logs.
+var syslogTLSVersions = map[string]uint16{
+	"TLS1.3": tls.VersionTLS13,
+	"TLS1.2": tls.VersionTLS12,
+	"TLS1.1": tls.VersionTLS11,
+	"TLS1.0": tls.VersionTLS10,
+	"1.3":    tls.VersionTLS13,
+	"1.2":    tls.VersionTLS12,
+	"1.1":    tls.VersionTLS11,
+	"1.0":    tls.VersionTLS10,
+}
+
+// syslogUnixAddresses are the well known local syslog sockets.
+var syslogUnixAddresses = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// syslogFacilities maps the names of the syslog facilities to their codes.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+const (
+	journaldSocket     = "/run/systemd/journal/socket"
+	journaldIdentifier = "authelia"
+)
+
+const (
+	logRedactionMask       = "*****"
+	logRedactionHashPrefix = "hash:"
+	logRedactionHashLength = 16
+)
+
+// logRedactionFieldKeys maps the keys of the fields added to log entries to the redaction field they contain.
+var logRedactionFieldKeys = map[string]string{
+	"username":  schema.LogRedactionFieldUsername,
+	"remote_ip": schema.LogRedactionFieldRemoteIP,
+	"email":     schema.LogRedactionFieldEmail,
+}
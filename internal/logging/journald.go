@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// JournaldHook is a logrus.Hook which writes the log entries to the systemd journal using the native journal protocol
+// so the entries are recorded with the priority of their level.
+type JournaldHook struct {
+	formatter logrus.Formatter
+
+	socket string
+
+	writer *connWriter
+}
+
+// NewJournaldHook returns a new JournaldHook for the systemd journal, entries are dropped while the journal is
+// unavailable.
+func NewJournaldHook(formatter logrus.Formatter) (hook *JournaldHook, err error) {
+	return newJournaldHook(journaldSocket, formatter)
+}
+
+func newJournaldHook(socket string, formatter logrus.Formatter) (hook *JournaldHook, err error) {
+	hook = &JournaldHook{
+		formatter: formatter,
+		socket:    socket,
+	}
+
+	hook.writer = newConnWriter("systemd journal", hook.connect, nil)
+
+	return hook, nil
+}
+
+// Levels implements logrus.Hook.
+func (h *JournaldHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.
+func (h *JournaldHook) Fire(entry *logrus.Entry) (err error) {
+	line, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+
+	journaldWriteField(buf, "PRIORITY", []byte(strconv.Itoa(syslogSeverity(entry.Level))))
+	journaldWriteField(buf, "SYSLOG_IDENTIFIER", []byte(journaldIdentifier))
+	journaldWriteField(buf, "MESSAGE", bytes.TrimRight(line, "\n"))
+
+	h.writer.Write(buf.Bytes())
+
+	return nil
+}
+
+// Close writes the queued entries and closes the connection to the systemd journal.
+func (h *JournaldHook) Close() (err error) {
+	return h.writer.Close()
+}
+
+func (h *JournaldHook) connect() (conn net.Conn, err error) {
+	if conn, err = net.DialTimeout("unixgram", h.socket, syslogDialTimeout); err != nil {
+		return nil, fmt.Errorf("error connecting to the systemd journal: %w", err)
+	}
+
+	return conn, nil
+}
+
+// journaldWriteField writes a field in the format of the native journal protocol, values which contain a newline
+// are written in the binary format with their length.
+func journaldWriteField(buf *bytes.Buffer, name string, value []byte) {
+	buf.WriteString(name)
+
+	if bytes.IndexByte(value, '\n') == -1 {
+		buf.WriteByte('=')
+		buf.Write(value)
+		buf.WriteByte('\n')
+
+		return
+	}
+
+	buf.WriteByte('\n')
+
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+
+	buf.Write(value)
+	buf.WriteByte('\n')
+}
@@ -23,12 +23,28 @@ func InitializeLogger(config schema.LogConfiguration, log bool) error {
 	stackLevels := []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
 	logrus.AddHook(logrus_stack.NewHook(callerLevels, stackLevels))
 
-	if config.Format == logFormatJSON {
-		logrus.SetFormatter(&logrus.JSONFormatter{})
-	} else {
-		logrus.SetFormatter(&logrus.TextFormatter{})
+	var formatter logrus.Formatter
+
+	switch {
+	case config.Format == logFormatJSON:
+		formatter = &logrus.JSONFormatter{}
+	case config.FilePath != "":
+		formatter = &logrus.TextFormatter{
+			DisableColors: true,
+			FullTimestamp: true,
+		}
+	default:
+		formatter = &logrus.TextFormatter{}
 	}
 
+	if config.Redaction.Enabled {
+		formatter = NewRedactionFormatter(formatter, config.Redaction)
+	}
+
+	logrus.SetFormatter(formatter)
+
+	var outputs []io.Writer
+
 	if config.FilePath != "" {
 		f, err := os.OpenFile(config.FilePath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
 
@@ -36,22 +52,78 @@ func InitializeLogger(config schema.LogConfiguration, log bool) error {
 			return err
 		}
 
-		if config.Format != logFormatJSON {
-			logrus.SetFormatter(&logrus.TextFormatter{
-				DisableColors: true,
-				FullTimestamp: true,
-			})
+		outputs = append(outputs, f)
+	}
+
+	hooks, err := newOutputHooks(config)
+	if err != nil {
+		return err
+	}
+
+	if len(outputs) == 0 && len(hooks) == 0 {
+		return nil
+	}
+
+	for _, hook := range hooks {
+		logrus.AddHook(hook)
+	}
+
+	if config.KeepStdout {
+		outputs = append([]io.Writer{os.Stdout}, outputs...)
+	}
+
+	switch len(outputs) {
+	case 0:
+		logrus.SetOutput(io.Discard)
+	case 1:
+		logrus.SetOutput(outputs[0])
+	default:
+		logrus.SetOutput(io.MultiWriter(outputs...))
+	}
+
+	return nil
+}
+
+// newOutputHooks returns the hooks which write the log entries to the syslog and journald outputs. The entries are
+// formatted without a timestamp as these outputs record their own.
+func newOutputHooks(config schema.LogConfiguration) (hooks []logrus.Hook, err error) {
+	if !config.Syslog.Enabled && !config.Journald.Enabled {
+		return nil, nil
+	}
+
+	var formatter logrus.Formatter
+
+	if config.Format == logFormatJSON {
+		formatter = &logrus.JSONFormatter{DisableTimestamp: true}
+	} else {
+		formatter = &logrus.TextFormatter{DisableColors: true, DisableTimestamp: true}
+	}
+
+	if config.Redaction.Enabled {
+		formatter = NewRedactionFormatter(formatter, config.Redaction)
+	}
+
+	if config.Syslog.Enabled {
+		var hook *SyslogHook
+
+		if hook, err = NewSyslogHook(config.Syslog, formatter); err != nil {
+			return nil, err
 		}
 
-		if config.KeepStdout {
-			logLocations := io.MultiWriter(os.Stdout, f)
-			logrus.SetOutput(logLocations)
-		} else {
-			logrus.SetOutput(f)
+		hooks = append(hooks, hook)
+	}
+
+	if config.Journald.Enabled {
+		var hook *JournaldHook
+
+		if hook, err = NewJournaldHook(formatter); err != nil {
+			return nil, err
 		}
+
+		hooks = append(hooks, hook)
 	}
 
-	return nil
+	return hooks, nil
 }
 
 func setLevelStr(level string, log bool) {
@@ -1,12 +1,18 @@
 package logging
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -84,6 +90,35 @@ func TestShouldFormatLogsAsJSON(t *testing.T) {
 	assert.Contains(t, string(b), "{\"level\":\"info\",\"msg\":\"This is a test\",")
 }
 
+func TestShouldRedactLogs(t *testing.T) {
+	dir, err := os.MkdirTemp("/tmp", "logs-dir")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := fmt.Sprintf("%s/authelia.log", dir)
+	err = InitializeLogger(schema.LogConfiguration{Format: "text", FilePath: path, KeepStdout: false, Redaction: schema.LogRedactionConfiguration{
+		Enabled: true,
+		Method:  "mask",
+		Fields:  []string{"username"},
+	}}, false)
+	require.NoError(t, err)
+
+	defer logrus.SetFormatter(&logrus.TextFormatter{})
+
+	Logger().Info("This is a test for user 'john'")
+
+	f, err := os.OpenFile(path, os.O_RDONLY, 0)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(b), "level=info msg=\"This is a test for user '*****'\"\n")
+}
+
 func TestShouldRaiseErrorOnInvalidFile(t *testing.T) {
 	err := InitializeLogger(schema.LogConfiguration{FilePath: "/not/a/valid/path/to.log"}, false)
 
@@ -113,3 +148,236 @@ func TestSetLevels(t *testing.T) {
 	setLevelStr("trace", false)
 	assert.Equal(t, logrus.TraceLevel, logrus.GetLevel())
 }
+
+func TestShouldWriteLogsToSyslogAndStdout(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+	defer logrus.SetOutput(os.Stderr)
+
+	err = InitializeLogger(schema.LogConfiguration{Format: "text", KeepStdout: true, Syslog: schema.LogSyslogConfiguration{
+		Enabled:  true,
+		Network:  "udp",
+		Address:  conn.LocalAddr().String(),
+		Facility: "daemon",
+		Tag:      "authelia",
+	}}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, os.Stdout, logrus.StandardLogger().Out)
+
+	Logger().Warn("This is a test")
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+	b := make([]byte, 1024)
+
+	n, _, err := conn.ReadFrom(b)
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^<28>1 \S+ \S+ authelia \d+ - - level=warning msg="This is a test"$`, string(b[:n]))
+}
+
+func TestShouldWriteLogsToSyslogOverTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	hook, err := NewSyslogHook(schema.LogSyslogConfiguration{
+		Network:  "tcp",
+		Address:  listener.Addr().String(),
+		Facility: "local0",
+		Tag:      "authelia",
+	}, &logrus.TextFormatter{DisableColors: true, DisableTimestamp: true})
+	require.NoError(t, err)
+
+	defer hook.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(hook)
+
+	logger.Error("This is a test")
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+	b := make([]byte, 1024)
+
+	n, err := conn.Read(b)
+	require.NoError(t, err)
+
+	parts := strings.SplitN(string(b[:n]), " ", 2)
+	require.Len(t, parts, 2)
+
+	assert.Equal(t, strconv.Itoa(len(parts[1])), parts[0])
+	assert.Regexp(t, `^<131>1 \S+ \S+ authelia \d+ - - level=error msg="This is a test"$`, parts[1])
+}
+
+func TestShouldWriteLogsToLocalSyslog(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets are not supported")
+	}
+
+	path := filepath.Join(t.TempDir(), "log.sock")
+
+	conn, err := net.ListenPacket("unixgram", path)
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	hook, err := NewSyslogHook(schema.LogSyslogConfiguration{
+		Network:  "unix",
+		Address:  path,
+		Facility: "auth",
+		Tag:      "authelia",
+	}, &logrus.TextFormatter{DisableColors: true, DisableTimestamp: true})
+	require.NoError(t, err)
+
+	defer hook.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(logrus.DebugLevel)
+	logger.AddHook(hook)
+
+	logger.Debug("This is a test")
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+	b := make([]byte, 1024)
+
+	n, _, err := conn.ReadFrom(b)
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^<39>\w{3} [ \d]\d \d{2}:\d{2}:\d{2} authelia\[\d+\]: level=debug msg="This is a test"$`, string(b[:n]))
+}
+
+func TestShouldWriteLogsToJournald(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets are not supported")
+	}
+
+	path := filepath.Join(t.TempDir(), "journal.sock")
+
+	conn, err := net.ListenPacket("unixgram", path)
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	hook, err := newJournaldHook(path, &logrus.TextFormatter{DisableColors: true, DisableTimestamp: true})
+	require.NoError(t, err)
+
+	defer hook.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(hook)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+	b := make([]byte, 1024)
+
+	logger.Info("This is a test")
+
+	n, _, err := conn.ReadFrom(b)
+	require.NoError(t, err)
+
+	assert.Equal(t, "PRIORITY=6\nSYSLOG_IDENTIFIER=authelia\nMESSAGE=level=info msg=\"This is a test\"\n", string(b[:n]))
+
+	logger.WithField("stack", "line\nline").Error("This is a test")
+
+	n, _, err = conn.ReadFrom(b)
+	require.NoError(t, err)
+
+	message := "level=error msg=\"This is a test\" stack=\"line\\nline\""
+
+	assert.Equal(t, "PRIORITY=3\nSYSLOG_IDENTIFIER=authelia\nMESSAGE="+message+"\n", string(b[:n]))
+}
+
+func TestShouldWriteJournaldFieldsWithNewlines(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	journaldWriteField(buf, "MESSAGE", []byte("line\nline"))
+
+	assert.Equal(t, []byte("MESSAGE\n\x09\x00\x00\x00\x00\x00\x00\x00line\nline\n"), buf.Bytes())
+}
+
+func TestShouldNotRaiseErrorOnUnavailableSyslog(t *testing.T) {
+	hook, err := NewSyslogHook(schema.LogSyslogConfiguration{
+		Network:  "unix",
+		Address:  filepath.Join(t.TempDir(), "missing.sock"),
+		Facility: "daemon",
+	}, &logrus.TextFormatter{})
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(hook)
+
+	logger.Error("This is a test")
+
+	assert.NoError(t, hook.Close())
+	assert.Equal(t, uint64(1), hook.writer.Dropped())
+}
+
+func TestShouldDropEntriesWhenQueueIsFull(t *testing.T) {
+	release := make(chan struct{})
+
+	writer := newConnWriter("test", func() (net.Conn, error) {
+		<-release
+
+		return nil, fmt.Errorf("unavailable")
+	}, nil)
+
+	for i := 0; i < outputQueueSize+10; i++ {
+		writer.Write([]byte("This is a test"))
+	}
+
+	// The first entry is either queued or held by the blocked dial.
+	assert.GreaterOrEqual(t, writer.Dropped(), uint64(10))
+
+	close(release)
+
+	assert.NoError(t, writer.Close())
+	assert.Equal(t, uint64(outputQueueSize+10), writer.Dropped())
+}
+
+func TestShouldDropEntriesOnWriteTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	dialed := false
+
+	writer := newConnWriter("test", func() (net.Conn, error) {
+		if dialed {
+			return nil, fmt.Errorf("unavailable")
+		}
+
+		dialed = true
+
+		return net.Dial("tcp", listener.Addr().String())
+	}, nil)
+
+	writer.timeout = 50 * time.Millisecond
+
+	// The server never reads so the writes block once the socket buffers are full.
+	message := bytes.Repeat([]byte("a"), 1<<20)
+
+	for i := 0; i < 64; i++ {
+		writer.Write(message)
+	}
+
+	assert.NoError(t, writer.Close())
+	assert.Greater(t, writer.Dropped(), uint64(0))
+}
@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"regexp"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+var (
+	regexpRedactUsername = regexp.MustCompile(`(?i)(\buser(?:name)?\s+')([^']+)(')`)
+	regexpRedactEmail    = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+	regexpRedactIP       = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b|(?:[0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}`)
+)
+
+// RedactionFormatter is a logrus.Formatter which redacts the configured sensitive values from the message and fields of
+// an entry before passing it to the underlying formatter.
+type RedactionFormatter struct {
+	formatter logrus.Formatter
+	config    schema.LogRedactionConfiguration
+	fields    map[string]bool
+}
+
+// NewRedactionFormatter returns a new RedactionFormatter wrapping the given formatter.
+func NewRedactionFormatter(formatter logrus.Formatter, config schema.LogRedactionConfiguration) *RedactionFormatter {
+	fields := make(map[string]bool, len(config.Fields))
+
+	for _, field := range config.Fields {
+		fields[field] = true
+	}
+
+	return &RedactionFormatter{
+		formatter: formatter,
+		config:    config,
+		fields:    fields,
+	}
+}
+
+// Format implements logrus.Formatter.
+func (f *RedactionFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	redacted := *entry
+
+	redacted.Message = f.redactMessage(entry.Message)
+	redacted.Data = make(logrus.Fields, len(entry.Data))
+
+	for key, value := range entry.Data {
+		if field, ok := logRedactionFieldKeys[key]; ok && f.fields[field] {
+			redacted.Data[key] = f.redact(fmt.Sprint(value))
+
+			continue
+		}
+
+		redacted.Data[key] = value
+	}
+
+	return f.formatter.Format(&redacted)
+}
+
+func (f *RedactionFormatter) redactMessage(message string) string {
+	if f.fields[schema.LogRedactionFieldEmail] {
+		message = regexpRedactEmail.ReplaceAllStringFunc(message, f.redact)
+	}
+
+	if f.fields[schema.LogRedactionFieldUsername] {
+		message = regexpRedactUsername.ReplaceAllStringFunc(message, func(match string) string {
+			parts := regexpRedactUsername.FindStringSubmatch(match)
+
+			return parts[1] + f.redact(parts[2]) + parts[3]
+		})
+	}
+
+	if f.fields[schema.LogRedactionFieldRemoteIP] {
+		// The pattern also matches values such as times so only the matches which are IP addresses are redacted.
+		message = regexpRedactIP.ReplaceAllStringFunc(message, func(match string) string {
+			if net.ParseIP(match) == nil {
+				return match
+			}
+
+			return f.redact(match)
+		})
+	}
+
+	return message
+}
+
+// redact returns the redacted form of a value. Hashed values are keyed with the secret so the same value always results
+// in the same hash which allows correlating log lines without revealing the value.
+func (f *RedactionFormatter) redact(value string) string {
+	if f.config.Method == schema.LogRedactionMethodMask {
+		return logRedactionMask
+	}
+
+	mac := hmac.New(sha256.New, []byte(f.config.Secret))
+	mac.Write([]byte(value))
+
+	return logRedactionHashPrefix + hex.EncodeToString(mac.Sum(nil))[:logRedactionHashLength]
+}
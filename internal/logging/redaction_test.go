@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestRedactionFormatterShouldHashConsistently(t *testing.T) {
+	formatter := NewRedactionFormatter(&logrus.TextFormatter{DisableTimestamp: true}, schema.LogRedactionConfiguration{
+		Enabled: true,
+		Method:  schema.LogRedactionMethodHash,
+		Secret:  "secret",
+		Fields:  []string{schema.LogRedactionFieldUsername, schema.LogRedactionFieldRemoteIP, schema.LogRedactionFieldEmail},
+	})
+
+	entry := &logrus.Entry{
+		Level:   logrus.InfoLevel,
+		Message: "Unsuccessful 1FA authentication attempt by user 'john' with email john.doe@example.com from 192.168.1.10 and ::1 at 10:30:45",
+		Data: logrus.Fields{
+			"remote_ip": "192.168.1.10",
+			"method":    "POST",
+		},
+	}
+
+	out, err := formatter.Format(entry)
+	require.NoError(t, err)
+
+	username, ip, ipv6, email := formatter.redact("john"), formatter.redact("192.168.1.10"), formatter.redact("::1"), formatter.redact("john.doe@example.com")
+
+	assert.Equal(t, "hash:", username[:5])
+	assert.Len(t, username, 21)
+	assert.NotEqual(t, username, ip)
+
+	assert.Equal(t, `level=info msg="Unsuccessful 1FA authentication attempt by user '`+username+`' with email `+email+` from `+ip+` and `+ipv6+` at 10:30:45" method=POST remote_ip="`+ip+`"`+"\n", string(out))
+
+	// The original entry must not be modified as it may be formatted by other hooks.
+	assert.Equal(t, "192.168.1.10", entry.Data["remote_ip"])
+	assert.Contains(t, entry.Message, "'john'")
+
+	other := NewRedactionFormatter(&logrus.TextFormatter{}, schema.LogRedactionConfiguration{Method: schema.LogRedactionMethodHash, Secret: "other"})
+
+	assert.NotEqual(t, username, other.redact("john"))
+}
+
+func TestRedactionFormatterShouldMaskConfiguredFields(t *testing.T) {
+	formatter := NewRedactionFormatter(&logrus.JSONFormatter{DisableTimestamp: true}, schema.LogRedactionConfiguration{
+		Enabled: true,
+		Method:  schema.LogRedactionMethodMask,
+		Fields:  []string{schema.LogRedactionFieldUsername},
+	})
+
+	out, err := formatter.Format(&logrus.Entry{
+		Level:   logrus.InfoLevel,
+		Message: "User 'john' has been disabled from 192.168.1.10",
+		Data: logrus.Fields{
+			"remote_ip": "192.168.1.10",
+			"username":  "john",
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"level":"info","msg":"User '*****' has been disabled from 192.168.1.10","remote_ip":"192.168.1.10","username":"*****"}`+"\n", string(out))
+}
+
+// TestRedactionFormatterShouldRedactUsernameOfFormats checks the username is redacted from the messages of every format
+// string of the repository which contains a username.
+func TestRedactionFormatterShouldRedactUsernameOfFormats(t *testing.T) {
+	formatter := NewRedactionFormatter(&logrus.TextFormatter{}, schema.LogRedactionConfiguration{
+		Enabled: true,
+		Method:  schema.LogRedactionMethodMask,
+		Fields:  []string{schema.LogRedactionFieldUsername},
+	})
+
+	regexpUsernameVerb := regexp.MustCompile(`(?i)(\buser(?:name)?\s+'?)%[-+#]?[sv]`)
+	regexpVerb := regexp.MustCompile(`%[-+#]?[a-zA-Z]`)
+
+	fset := token.NewFileSet()
+	formats := 0
+
+	for _, dir := range []string{"../../cmd", "../../internal"} {
+		require.NoError(t, filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+
+			file, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				return err
+			}
+
+			ast.Inspect(file, func(node ast.Node) bool {
+				lit, ok := node.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+
+				format, err := strconv.Unquote(lit.Value)
+				if err != nil || !regexpUsernameVerb.MatchString(format) {
+					return true
+				}
+
+				formats++
+
+				message := regexpVerb.ReplaceAllString(regexpUsernameVerb.ReplaceAllString(format, "${1}john"), "value")
+
+				assert.NotContains(t, formatter.redactMessage(message), "john", "the username of the format at %s isn't redacted", fset.Position(lit.Pos()))
+
+				return true
+			})
+
+			return nil
+		}))
+	}
+
+	assert.Greater(t, formats, 0)
+}
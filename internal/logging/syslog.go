@@ -0,0 +1,194 @@
+package logging
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// SyslogHook is a logrus.Hook which writes the log entries to the local syslog socket or to a remote syslog server.
+// Entries written to the local socket use the RFC3164 format the local daemons expect, entries written to a remote
+// server use the RFC5424 format with the RFC6587 octet counting framing on streams.
+type SyslogHook struct {
+	formatter logrus.Formatter
+
+	network   string
+	address   string
+	tlsConfig *tls.Config
+
+	facility int
+	tag      string
+	hostname string
+	pid      int
+
+	writer *connWriter
+}
+
+// NewSyslogHook returns a new SyslogHook for the syslog server of the provided configuration. The connection is
+// established in the background so an unavailable server doesn't prevent the startup, entries are dropped until it is
+// available.
+func NewSyslogHook(config schema.LogSyslogConfiguration, formatter logrus.Formatter) (hook *SyslogHook, err error) {
+	facility, ok := syslogFacilities[config.Facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility '%s'", config.Facility)
+	}
+
+	hook = &SyslogHook{
+		formatter: formatter,
+		network:   config.Network,
+		address:   config.Address,
+		facility:  facility,
+		tag:       config.Tag,
+		pid:       os.Getpid(),
+	}
+
+	if hook.network == "" {
+		hook.network = schema.LogSyslogNetworkUnix
+	}
+
+	if hook.hostname, err = os.Hostname(); err != nil {
+		hook.hostname = "-"
+	}
+
+	if hook.network == schema.LogSyslogNetworkTCPTLS {
+		tlsConfig := schema.TLSConfig{}
+
+		if config.TLS != nil {
+			tlsConfig = *config.TLS
+		}
+
+		if tlsConfig.ServerName == "" {
+			if tlsConfig.ServerName, _, err = net.SplitHostPort(hook.address); err != nil {
+				return nil, fmt.Errorf("error parsing the syslog address '%s': %w", hook.address, err)
+			}
+		}
+
+		minVersion, ok := syslogTLSVersions[strings.ToUpper(tlsConfig.MinimumVersion)]
+		if !ok {
+			minVersion = tls.VersionTLS12
+		}
+
+		hook.tlsConfig = &tls.Config{
+			ServerName:         tlsConfig.ServerName,
+			InsecureSkipVerify: tlsConfig.SkipVerify, //nolint:gosec // Informed choice by user. Off by default.
+			MinVersion:         minVersion,
+		}
+	}
+
+	hook.writer = newConnWriter("syslog server", hook.connect, hook.frame)
+
+	return hook, nil
+}
+
+// Levels implements logrus.Hook.
+func (h *SyslogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.
+func (h *SyslogHook) Fire(entry *logrus.Entry) (err error) {
+	line, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+
+	h.writer.Write(h.message(entry.Time, syslogSeverity(entry.Level), bytes.TrimRight(line, "\n")))
+
+	return nil
+}
+
+// Close writes the queued entries and closes the connection to the syslog server.
+func (h *SyslogHook) Close() (err error) {
+	return h.writer.Close()
+}
+
+func (h *SyslogHook) connect() (conn net.Conn, err error) {
+	switch h.network {
+	case schema.LogSyslogNetworkUnix:
+		conn, err = dialSyslogUnix(h.address)
+	case schema.LogSyslogNetworkTCPTLS:
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: syslogDialTimeout}, "tcp", h.address, h.tlsConfig)
+	default:
+		conn, err = net.DialTimeout(h.network, h.address, syslogDialTimeout)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to the syslog server: %w", err)
+	}
+
+	return conn, nil
+}
+
+// frame delimits the messages written to local stream sockets with a newline.
+func (h *SyslogHook) frame(conn net.Conn, message []byte) []byte {
+	if h.network != schema.LogSyslogNetworkUnix || conn.LocalAddr().Network() != "unix" {
+		return message
+	}
+
+	return append(message[:len(message):len(message)], '\n')
+}
+
+func (h *SyslogHook) message(t time.Time, severity int, line []byte) []byte {
+	buf := &bytes.Buffer{}
+
+	priority := h.facility<<3 | severity
+
+	if h.network == schema.LogSyslogNetworkUnix {
+		fmt.Fprintf(buf, "<%d>%s %s[%d]: %s", priority, t.Format(time.Stamp), h.tag, h.pid, line)
+
+		return buf.Bytes()
+	}
+
+	fmt.Fprintf(buf, "<%d>1 %s %s %s %d - - %s", priority, t.Format(time.RFC3339Nano), h.hostname, h.tag, h.pid, line)
+
+	if h.network == schema.LogSyslogNetworkUDP {
+		return buf.Bytes()
+	}
+
+	return append([]byte(strconv.Itoa(buf.Len())+" "), buf.Bytes()...)
+}
+
+// dialSyslogUnix connects to the provided local syslog socket, or to the first of the well known local syslog sockets
+// which accepts the connection when no socket is provided.
+func dialSyslogUnix(address string) (conn net.Conn, err error) {
+	addresses := syslogUnixAddresses
+
+	if address != "" {
+		addresses = []string{address}
+	}
+
+	for _, addr := range addresses {
+		for _, network := range []string{"unixgram", "unix"} {
+			if conn, err = net.DialTimeout(network, addr, syslogDialTimeout); err == nil {
+				return conn, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no local syslog socket accepted the connection: %w", err)
+}
+
+// syslogSeverity returns the syslog severity of the provided logrus.Level.
+func syslogSeverity(level logrus.Level) int {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return syslogSeverityCritical
+	case logrus.ErrorLevel:
+		return syslogSeverityError
+	case logrus.WarnLevel:
+		return syslogSeverityWarning
+	case logrus.InfoLevel:
+		return syslogSeverityInformational
+	default:
+		return syslogSeverityDebug
+	}
+}
@@ -0,0 +1,177 @@
+package logging
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// connWriter writes the messages of an output to a connection from a bounded queue on its own goroutine so logging
+// never waits on the network. Messages are dropped when the queue is full or when the connection is unavailable, and
+// the connection is re-established at most once per redial interval.
+type connWriter struct {
+	name    string
+	dial    func() (net.Conn, error)
+	frame   func(conn net.Conn, message []byte) []byte
+	timeout time.Duration
+
+	queue   chan []byte
+	closing chan struct{}
+	done    chan struct{}
+	once    sync.Once
+
+	dropped uint64
+
+	conn     net.Conn
+	redialAt time.Time
+	failed   bool
+}
+
+// newConnWriter returns a new connWriter and starts its goroutine. The frame func is optional and allows the message
+// to be framed depending on the established connection.
+func newConnWriter(name string, dial func() (net.Conn, error), frame func(conn net.Conn, message []byte) []byte) *connWriter {
+	w := &connWriter{
+		name:    name,
+		dial:    dial,
+		frame:   frame,
+		timeout: outputWriteTimeout,
+		queue:   make(chan []byte, outputQueueSize),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go w.run()
+
+	return w
+}
+
+// Write queues the message without blocking, the message is dropped if the queue is full or the writer is closed.
+func (w *connWriter) Write(message []byte) {
+	select {
+	case <-w.closing:
+		atomic.AddUint64(&w.dropped, 1)
+
+		return
+	default:
+	}
+
+	select {
+	case w.queue <- message:
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+	}
+}
+
+// Dropped returns the number of messages which have been dropped.
+func (w *connWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// Close writes the queued messages, stopping at the first failure, and closes the connection.
+func (w *connWriter) Close() (err error) {
+	w.once.Do(func() {
+		close(w.closing)
+	})
+
+	<-w.done
+
+	return nil
+}
+
+func (w *connWriter) run() {
+	defer close(w.done)
+
+	for {
+		select {
+		case message := <-w.queue:
+			w.send(message)
+		case <-w.closing:
+			w.flush()
+
+			return
+		}
+	}
+}
+
+func (w *connWriter) flush() {
+	ok := true
+
+	for {
+		select {
+		case message := <-w.queue:
+			if !ok {
+				atomic.AddUint64(&w.dropped, 1)
+
+				continue
+			}
+
+			ok = w.send(message)
+		default:
+			if w.conn != nil {
+				_ = w.conn.Close()
+
+				w.conn = nil
+			}
+
+			return
+		}
+	}
+}
+
+// send writes the message to the connection, the connection is re-established once if the write fails so messages
+// survive a restart of the server.
+func (w *connWriter) send(message []byte) (ok bool) {
+	var err error
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil {
+			if time.Now().Before(w.redialAt) {
+				break
+			}
+
+			if w.conn, err = w.dial(); err != nil {
+				w.redialAt = time.Now().Add(outputRedialInterval)
+
+				break
+			}
+		}
+
+		_ = w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+
+		if _, err = w.conn.Write(w.framed(message)); err == nil {
+			if w.failed {
+				w.failed = false
+
+				logrus.Infof("Resumed writing the log entries to the %s, %d entries have been dropped", w.name, w.Dropped())
+			}
+
+			return true
+		}
+
+		_ = w.conn.Close()
+
+		w.conn = nil
+	}
+
+	atomic.AddUint64(&w.dropped, 1)
+
+	if !w.failed && err != nil {
+		w.failed = true
+
+		// This entry is queued for this writer as well, it's dropped or delivered once the connection recovers.
+		logrus.Warnf("Unable to write the log entries to the %s, entries are dropped until it's available: %v", w.name, err)
+	}
+
+	return false
+}
+
+func (w *connWriter) framed(message []byte) []byte {
+	if w.frame == nil {
+		return message
+	}
+
+	return w.frame(w.conn, message)
+}
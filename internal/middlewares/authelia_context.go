@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"path"
 	"strings"
@@ -57,17 +58,22 @@ func AutheliaMiddleware(configuration schema.Configuration, providers Providers)
 
 // AvailableSecondFactorMethods returns the available 2FA methods.
 func (ctx *AutheliaCtx) AvailableSecondFactorMethods() (methods []string) {
+	return AvailableSecondFactorMethods(&ctx.Configuration)
+}
+
+// AvailableSecondFactorMethods returns the 2FA methods enabled by the provided configuration.
+func AvailableSecondFactorMethods(config *schema.Configuration) (methods []string) {
 	methods = make([]string, 0, 3)
 
-	if !ctx.Configuration.TOTP.Disable {
+	if !config.TOTP.Disable {
 		methods = append(methods, model.SecondFactorMethodTOTP)
 	}
 
-	if !ctx.Configuration.Webauthn.Disable {
+	if !config.Webauthn.Disable {
 		methods = append(methods, model.SecondFactorMethodWebauthn)
 	}
 
-	if ctx.Configuration.DuoAPI != nil {
+	if config.DuoAPI != nil {
 		methods = append(methods, model.SecondFactorMethodDuo)
 	}
 
@@ -205,6 +211,17 @@ func (ctx *AutheliaCtx) XOriginalURL() []byte {
 	return ctx.RequestCtx.Request.Header.PeekBytes(headerXOriginalURL)
 }
 
+// RequestHeader returns a copy of the request headers as a http.Header.
+func (ctx *AutheliaCtx) RequestHeader() (header http.Header) {
+	header = http.Header{}
+
+	ctx.Request.Header.VisitAll(func(key, value []byte) {
+		header.Add(string(key), string(value))
+	})
+
+	return header
+}
+
 // GetSession return the user session. Any update will be saved in cache.
 func (ctx *AutheliaCtx) GetSession() session.UserSession {
 	userSession, err := ctx.Providers.SessionProvider.GetSession(ctx.RequestCtx)
@@ -261,8 +278,18 @@ func (ctx *AutheliaCtx) SetJSONBody(value interface{}) error {
 	return nil
 }
 
-// RemoteIP return the remote IP taking X-Forwarded-For header into account if provided.
+// SetBackend records the name of the backend which served the request, which is included in slow request logs.
+func (ctx *AutheliaCtx) SetBackend(backend string) {
+	ctx.SetUserValueBytes(UserValueKeyBackend, backend)
+}
+
+// RemoteIP return the remote IP taking X-Forwarded-For header into account if provided. When trusted proxies are
+// configured the header is only taken into account for requests received from a trusted proxy.
 func (ctx *AutheliaCtx) RemoteIP() net.IP {
+	if len(ctx.Configuration.Server.TrustedProxies) != 0 {
+		return ctx.remoteIPTrustedProxies()
+	}
+
 	XForwardedFor := ctx.Request.Header.PeekBytes(headerXForwardedFor)
 	if XForwardedFor != nil {
 		ips := strings.Split(string(XForwardedFor), ",")
@@ -275,6 +302,31 @@ func (ctx *AutheliaCtx) RemoteIP() net.IP {
 	return ctx.RequestCtx.RemoteIP()
 }
 
+// remoteIPTrustedProxies returns the remote IP of the client only trusting the X-Forwarded-For header when the request
+// is received from a trusted proxy. The header is read from right to left and the first IP which is not a trusted
+// proxy is the client.
+func (ctx *AutheliaCtx) remoteIPTrustedProxies() (ip net.IP) {
+	ip = ctx.RequestCtx.RemoteIP()
+
+	XForwardedFor := ctx.Request.Header.PeekBytes(headerXForwardedFor)
+	if XForwardedFor == nil {
+		return ip
+	}
+
+	ips := strings.Split(string(XForwardedFor), ",")
+
+	for i := len(ips) - 1; i >= 0 && utils.IsIPInNetworks(ip, ctx.Providers.TrustedProxies); i-- {
+		forwarded := net.ParseIP(strings.Trim(ips[i], " "))
+		if forwarded == nil {
+			break
+		}
+
+		ip = forwarded
+	}
+
+	return ip
+}
+
 // GetOriginalURL extract the URL from the request headers (X-Original-URL or X-Forwarded-* headers).
 func (ctx *AutheliaCtx) GetOriginalURL() (*url.URL, error) {
 	originalURL := ctx.XOriginalURL()
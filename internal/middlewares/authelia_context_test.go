@@ -1,11 +1,13 @@
 package middlewares_test
 
 import (
+	"net"
 	"net/url"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -20,7 +22,7 @@ func TestShouldCallNextWithAutheliaCtx(t *testing.T) {
 	ctx := &fasthttp.RequestCtx{}
 	configuration := schema.Configuration{}
 	userProvider := mocks.NewMockUserProvider(ctrl)
-	sessionProvider := session.NewProvider(configuration.Session, nil)
+	sessionProvider := session.NewProvider(configuration.Session, nil, nil)
 	providers := middlewares.Providers{
 		UserProvider:    userProvider,
 		SessionProvider: sessionProvider,
@@ -101,6 +103,70 @@ func TestShouldOnlyFallbackToNonXForwardedHeadersWhenNil(t *testing.T) {
 	assert.Equal(t, []byte("GET"), mock.Ctx.XForwardedMethod())
 }
 
+func TestShouldReturnRemoteIPFromXForwardedFor(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.SetRemoteAddr(&net.TCPAddr{IP: net.ParseIP("192.168.0.10"), Port: 443})
+
+	assert.Equal(t, "192.168.0.10", mock.Ctx.RemoteIP().String())
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-For", "1.2.3.4, 10.0.0.1")
+
+	assert.Equal(t, "1.2.3.4", mock.Ctx.RemoteIP().String())
+}
+
+func TestShouldReturnRemoteIPFromTrustedProxies(t *testing.T) {
+	testCases := []struct {
+		name, remote, forwarded, expected string
+	}{
+		{"ShouldIgnoreHeaderFromUntrustedRemote", "192.168.0.10", "1.2.3.4", "192.168.0.10"},
+		{"ShouldUseRemoteWithoutHeader", "10.0.0.1", "", "10.0.0.1"},
+		{"ShouldUseRightMostUntrusted", "10.0.0.1", "1.2.3.4, 5.6.7.8", "5.6.7.8"},
+		{"ShouldSkipTrustedProxies", "10.0.0.1", "1.2.3.4, 172.16.0.1, 10.0.0.2", "1.2.3.4"},
+		{"ShouldUseLeftMostWhenAllTrusted", "10.0.0.1", "172.16.0.1, 10.0.0.2", "172.16.0.1"},
+		{"ShouldStopAtInvalidAddress", "10.0.0.1", "1.2.3.4, invalid, 10.0.0.2", "10.0.0.2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Configuration.Server.TrustedProxies = []string{"10.0.0.0/8", "172.16.0.1"}
+
+			proxies, err := middlewares.NewTrustedProxies(mock.Ctx.Configuration.Server.TrustedProxies)
+			require.NoError(t, err)
+
+			mock.Ctx.Providers.TrustedProxies = proxies
+
+			mock.Ctx.SetRemoteAddr(&net.TCPAddr{IP: net.ParseIP(tc.remote), Port: 443})
+
+			if tc.forwarded != "" {
+				mock.Ctx.Request.Header.Set("X-Forwarded-For", tc.forwarded)
+			}
+
+			assert.Equal(t, tc.expected, mock.Ctx.RemoteIP().String())
+		})
+	}
+}
+
+func TestShouldReturnRequestHeader(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Request.Header.Set("User-Agent", "Mozilla/5.0")
+	mock.Ctx.Request.Header.Set("x-vpn-client", "yes")
+	mock.Ctx.Request.Header.Add("X-Multi", "a")
+	mock.Ctx.Request.Header.Add("X-Multi", "b")
+
+	header := mock.Ctx.RequestHeader()
+
+	assert.Equal(t, "Mozilla/5.0", header.Get("user-agent"))
+	assert.Equal(t, "yes", header.Get("X-VPN-Client"))
+	assert.Equal(t, []string{"a", "b"}, header.Values("x-multi"))
+}
+
 func TestShouldDetectXHR(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"errors"
+	"time"
 
 	"github.com/valyala/fasthttp"
 )
@@ -19,6 +20,10 @@ var (
 	headerXOriginalURL     = []byte("X-Original-URL")
 	headerXForwardedMethod = []byte("X-Forwarded-Method")
 
+	headerRetryAfter = []byte(fasthttp.HeaderRetryAfter)
+
+	headerAuthorization = []byte(fasthttp.HeaderAuthorization)
+
 	headerVary   = []byte(fasthttp.HeaderVary)
 	headerAllow  = []byte(fasthttp.HeaderAllow)
 	headerOrigin = []byte(fasthttp.HeaderOrigin)
@@ -40,6 +45,8 @@ var (
 	headerValueVaryWildcard   = []byte("Accept-Encoding")
 	headerValueOriginWildcard = []byte("*")
 	headerValueZero           = []byte("0")
+	headerValueBasicPrefix    = []byte("Basic ")
+	headerValueBearerPrefix   = []byte("Bearer ")
 )
 
 var (
@@ -49,9 +56,34 @@ var (
 	// UserValueKeyBaseURL is the User Value key where we store the Base URL.
 	UserValueKeyBaseURL = []byte("base_url")
 
+	// UserValueKeyBackend is the User Value key where we store the name of the backend which served the request.
+	UserValueKeyBackend = []byte("backend")
+
+	// UserValueKeyDenyResponse is the User Value key where we store the deny response which has help for the user who
+	// was forbidden from accessing a resource.
+	UserValueKeyDenyResponse = []byte("deny_response")
+
 	headerSeparator = []byte(", ")
 )
 
+const (
+	pathWellKnown = "/.well-known/"
+)
+
+const (
+	formKeyClientID    = "client_id"
+	formKeyAccessToken = "access_token"
+)
+
+const (
+	headerReferrerPolicy          = "Referrer-Policy"
+	headerPermissionsPolicy       = "Permissions-Policy"
+	headerXFrameOptions           = fasthttp.HeaderXFrameOptions
+	headerStrictTransportSecurity = fasthttp.HeaderStrictTransportSecurity
+
+	headerValueNoSniff = "nosniff"
+)
+
 const (
 	headerValueXRequestedWithXHR = "XMLHttpRequest"
 	contentTypeApplicationJSON   = "application/json"
@@ -64,8 +96,12 @@ const (
 	messageOperationFailed                      = "Operation failed"
 	messageIdentityVerificationTokenAlreadyUsed = "The identity verification token has already been used"
 	messageIdentityVerificationTokenHasExpired  = "The identity verification token has expired"
+	messageRecentAuthenticationRequired         = "You must authenticate again to perform this action"
 )
 
+// healthCheckTimeout is the maximum duration of each provider health check.
+const healthCheckTimeout = time.Second * 10
+
 var protoHostSeparator = []byte("://")
 
 var errPasswordPolicyNoMet = errors.New("the supplied password does not met the security policy")
@@ -37,11 +37,16 @@ type CORSPolicyBuilder struct {
 	methods     []string
 	headers     []string
 	origins     []string
+	originsFunc CORSAllowedOriginsFunc
 	credentials bool
 	vary        []string
 	maxAge      int
 }
 
+// CORSAllowedOriginsFunc returns the origins which are allowed for a specific request instead of the origins configured
+// with WithAllowedOrigins. A nil value indicates the origins configured with WithAllowedOrigins apply to the request.
+type CORSAllowedOriginsFunc func(ctx *fasthttp.RequestCtx) (origins []string)
+
 // Build reads the CORSPolicyBuilder configuration and generates a CORSPolicy.
 func (b *CORSPolicyBuilder) Build() (policy *CORSPolicy) {
 	policy = &CORSPolicy{
@@ -49,6 +54,7 @@ func (b *CORSPolicyBuilder) Build() (policy *CORSPolicy) {
 		varyOnly:    b.varyOnly,
 		credentials: []byte(strconv.FormatBool(b.credentials)),
 		origins:     b.buildOrigins(),
+		originsFunc: b.originsFunc,
 		headers:     b.buildHeaders(),
 		vary:        b.buildVary(),
 	}
@@ -147,6 +153,15 @@ func (b *CORSPolicyBuilder) WithAllowedOrigins(origins ...string) (policy *CORSP
 	return b
 }
 
+// WithAllowedOriginsFunc takes a CORSAllowedOriginsFunc which is used to determine the allowed origins of each request.
+// If the func returns nil the origin is validated against the origins from WithAllowedOrigins, otherwise the origin
+// must be in the returned list.
+func (b *CORSPolicyBuilder) WithAllowedOriginsFunc(fn CORSAllowedOriginsFunc) (policy *CORSPolicyBuilder) {
+	b.originsFunc = fn
+
+	return b
+}
+
 // WithAllowedHeaders takes a list of header strings and alters the default Access-Control-Allow-Headers header.
 func (b *CORSPolicyBuilder) WithAllowedHeaders(headers ...string) (policy *CORSPolicyBuilder) {
 	b.headers = headers
@@ -190,6 +205,7 @@ type CORSPolicy struct {
 	methods     []byte
 	headers     []byte
 	origins     [][]byte
+	originsFunc CORSAllowedOriginsFunc
 	credentials []byte
 	vary        []byte
 	maxAge      []byte
@@ -271,21 +287,8 @@ func (p CORSPolicy) handleCORS(ctx *fasthttp.RequestCtx) {
 
 	var allowedOrigin []byte
 
-	switch len(p.origins) {
-	case 0:
-		allowedOrigin = origin
-	default:
-		for i := 0; i < len(p.origins); i++ {
-			if bytes.Equal(p.origins[i], headerValueOriginWildcard) {
-				allowedOrigin = headerValueOriginWildcard
-			} else if bytes.Equal(p.origins[i], origin) {
-				allowedOrigin = origin
-			}
-		}
-
-		if len(allowedOrigin) == 0 {
-			return
-		}
+	if allowedOrigin = p.allowedOrigin(ctx, origin); len(allowedOrigin) == 0 {
+		return
 	}
 
 	ctx.Response.Header.SetBytesKV(headerAccessControlAllowOrigin, allowedOrigin)
@@ -302,6 +305,32 @@ func (p CORSPolicy) handleCORS(ctx *fasthttp.RequestCtx) {
 	p.handleAllowedMethods(ctx)
 }
 
+func (p CORSPolicy) allowedOrigin(ctx *fasthttp.RequestCtx, origin []byte) (allowedOrigin []byte) {
+	if p.originsFunc != nil {
+		if origins := p.originsFunc(ctx); origins != nil {
+			if !utils.IsStringInSlice(string(origin), origins) {
+				return nil
+			}
+
+			return origin
+		}
+	}
+
+	if len(p.origins) == 0 {
+		return origin
+	}
+
+	for i := 0; i < len(p.origins); i++ {
+		if bytes.Equal(p.origins[i], headerValueOriginWildcard) {
+			allowedOrigin = headerValueOriginWildcard
+		} else if bytes.Equal(p.origins[i], origin) {
+			allowedOrigin = origin
+		}
+	}
+
+	return allowedOrigin
+}
+
 func (p CORSPolicy) handleAllowedMethods(ctx *fasthttp.RequestCtx) {
 	switch len(p.methods) {
 	case 0:
@@ -581,6 +581,43 @@ func Test_CORSMiddleware_AsMiddleware(t *testing.T) {
 	assert.Equal(t, []byte("GET, OPTIONS"), ctx.Response.Header.PeekBytes(headerAccessControlAllowMethods))
 }
 
+func TestCORSPolicyBuilder_WithAllowedOriginsFunc(t *testing.T) {
+	var clientOrigins []string
+
+	policy := NewCORSPolicyBuilder().
+		WithAllowedOrigins("https://myapp.example.com", "https://other.example.com").
+		WithAllowedOriginsFunc(func(ctx *fasthttp.RequestCtx) (origins []string) {
+			return clientOrigins
+		}).
+		Build()
+
+	testCases := []struct {
+		name     string
+		origin   string
+		origins  []string
+		expected []byte
+	}{
+		{"ShouldFallbackToAllowedOrigins", "https://other.example.com", nil, []byte("https://other.example.com")},
+		{"ShouldAllowOriginInBoth", "https://myapp.example.com", []string{"https://myapp.example.com"}, []byte("https://myapp.example.com")},
+		{"ShouldNotAllowOriginNotInFunc", "https://other.example.com", []string{"https://myapp.example.com"}, nil},
+		{"ShouldAllowOriginOnlyInFunc", "https://another.example.com", []string{"https://another.example.com"}, []byte("https://another.example.com")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clientOrigins = tc.origins
+
+			ctx := newFastHTTPRequestCtx()
+
+			ctx.Request.Header.SetBytesK(headerOrigin, tc.origin)
+
+			policy.HandleOPTIONS(ctx)
+
+			assert.Equal(t, tc.expected, ctx.Response.Header.PeekBytes(headerAccessControlAllowOrigin))
+		})
+	}
+}
+
 func testNilHandler(_ *AutheliaCtx) {}
 
 func newFastHTTPRequestCtx() (ctx *fasthttp.RequestCtx) {
@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"context"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// NewProviderHealthChecks returns the health checks of the providers which support them and are not disabled in the
+// self-test configuration.
+func NewProviderHealthChecks(config schema.SelfTestConfiguration, providers Providers) (checks []ProviderHealthCheck) {
+	if check, ok := providers.UserProvider.(model.HealthCheck); ok && !config.DisableAuthenticationBackend {
+		checks = append(checks, ProviderHealthCheck{Name: "authentication_backend", Check: check})
+	}
+
+	if providers.SessionProvider != nil && !config.DisableSession {
+		checks = append(checks, ProviderHealthCheck{Name: "session", Check: providers.SessionProvider})
+	}
+
+	if check, ok := providers.StorageProvider.(model.HealthCheck); ok && !config.DisableStorage {
+		checks = append(checks, ProviderHealthCheck{Name: "storage", Check: check})
+	}
+
+	if check, ok := providers.Notifier.(model.HealthCheck); ok && !config.DisableNotifier {
+		checks = append(checks, ProviderHealthCheck{Name: "notifier", Check: check})
+	}
+
+	return checks
+}
+
+// RunProviderHealthChecks runs each of the health checks with a timeout and returns the errors keyed by the name of
+// the provider which failed.
+func RunProviderHealthChecks(ctx context.Context, checks []ProviderHealthCheck) (failures map[string]error) {
+	failures = map[string]error{}
+
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+
+		if err := check.Check.HealthCheck(checkCtx); err != nil {
+			failures[check.Name] = err
+		}
+
+		cancel()
+	}
+
+	return failures
+}
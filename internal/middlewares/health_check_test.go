@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/notification"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+type testHealthCheck struct {
+	err error
+}
+
+func (c testHealthCheck) HealthCheck(ctx context.Context) (err error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return errors.New("no deadline")
+	}
+
+	return c.err
+}
+
+func TestNewProviderHealthChecks(t *testing.T) {
+	providers := Providers{
+		SessionProvider: session.NewProvider(schema.SessionConfiguration{Name: "authelia_session", Domain: "example.com"}, nil, nil),
+		Notifier:        notification.NewFileNotifier(schema.FileSystemNotifierConfiguration{Filename: "/tmp/notification.txt"}),
+	}
+
+	checks := NewProviderHealthChecks(schema.SelfTestConfiguration{}, providers)
+
+	require.Len(t, checks, 2)
+	assert.Equal(t, "session", checks[0].Name)
+	assert.Equal(t, "notifier", checks[1].Name)
+
+	checks = NewProviderHealthChecks(schema.SelfTestConfiguration{DisableSession: true, DisableNotifier: true}, providers)
+
+	assert.Len(t, checks, 0)
+	assert.Len(t, NewProviderHealthChecks(schema.SelfTestConfiguration{}, Providers{}), 0)
+}
+
+func TestRunProviderHealthChecks(t *testing.T) {
+	failures := RunProviderHealthChecks(context.Background(), []ProviderHealthCheck{
+		{Name: "storage", Check: testHealthCheck{}},
+		{Name: "notifier", Check: testHealthCheck{err: errors.New("connection refused")}},
+	})
+
+	require.Len(t, failures, 1)
+	assert.EqualError(t, failures["notifier"], "connection refused")
+}
@@ -9,8 +9,12 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 
+	"github.com/authelia/authelia/v4/internal/events"
 	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/notification"
+	"github.com/authelia/authelia/v4/internal/session"
 	"github.com/authelia/authelia/v4/internal/templates"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // IdentityVerificationStart the handler for initiating the identity validation process.
@@ -36,6 +40,21 @@ func IdentityVerificationStart(args IdentityVerificationStartArgs, delayFunc Tim
 			return
 		}
 
+		if err = checkIdentityEmailVerified(ctx, identity); err != nil {
+			// In that case we reply ok to avoid user enumeration.
+			ctx.Logger.Error(err)
+			ctx.ReplyOK()
+
+			return
+		}
+
+		if !isIdentityEmailDomainPermitted(ctx, identity, args.ActionClaim) {
+			// In that case we reply ok to avoid user enumeration.
+			ctx.ReplyOK()
+
+			return
+		}
+
 		var jti uuid.UUID
 
 		if jti, err = uuid.NewRandom(); err != nil {
@@ -107,10 +126,10 @@ func IdentityVerificationStart(args IdentityVerificationStartArgs, delayFunc Tim
 			return
 		}
 
-		ctx.Logger.Debugf("Sending an email to user %s (%s) to confirm identity for registering a device.",
+		ctx.Logger.Debugf("Sending an email to user '%s' (%s) to confirm identity for registering a device.",
 			identity.Username, identity.Email)
 
-		err = ctx.Providers.Notifier.Send(identity.Email, args.MailTitle, bufText.String(), bufHTML.String())
+		err = notification.SendEvent(ctx.Providers.Notifier, notification.EventIdentityVerification, identity.Email, args.MailTitle, bufText.String(), bufHTML.String())
 
 		if err != nil {
 			ctx.Error(err, messageOperationFailed)
@@ -213,3 +232,47 @@ func IdentityVerificationFinish(args IdentityVerificationFinishArgs, next func(c
 		next(ctx, claims.Username)
 	}
 }
+
+// checkIdentityEmailVerified returns an error if email verification of self-registered accounts is enabled and the
+// email address of the identity has not been verified, so that nothing is delivered to an unverified email address.
+func checkIdentityEmailVerified(ctx *AutheliaCtx, identity *session.Identity) (err error) {
+	if !ctx.Configuration.SelfRegistration.Enabled || !ctx.Configuration.SelfRegistration.EmailVerification.Enabled {
+		return nil
+	}
+
+	verification, err := ctx.Providers.StorageProvider.LoadEmailVerification(ctx, identity.Username)
+
+	switch {
+	case err != nil:
+		return err
+	case verification != nil && verification.Unverified(identity.Email):
+		return fmt.Errorf("the email address '%s' of user '%s' has not been verified", identity.Email, identity.Username)
+	default:
+		return nil
+	}
+}
+
+// isIdentityEmailDomainPermitted returns true if the domain of the email address of the identity is permitted by the
+// email domains configuration. Refusals are logged and emitted as events so they can be audited.
+func isIdentityEmailDomainPermitted(ctx *AutheliaCtx, identity *session.Identity, action string) bool {
+	if utils.IsEmailDomainPermitted(identity.Email, ctx.Configuration.EmailDomains.Allowed, ctx.Configuration.EmailDomains.Denied) {
+		return true
+	}
+
+	ctx.Logger.Warnf("Refused to send the identity verification email for action '%s' to user '%s' as the domain of the email address '%s' is not permitted", action, identity.Username, identity.Email)
+
+	successful := false
+
+	ctx.Providers.Events.Emit(events.Event{
+		Type:       events.TypeEmailDomainRefused,
+		Username:   identity.Username,
+		RemoteIP:   ctx.RemoteIP().String(),
+		Successful: &successful,
+		Details: map[string]string{
+			"email":  identity.Email,
+			"action": action,
+		},
+	})
+
+	return false
+}
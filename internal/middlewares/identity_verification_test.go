@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
@@ -50,6 +51,47 @@ func TestShouldFailStartingProcessIfUserHasNoEmailAddress(t *testing.T) {
 	assert.Equal(t, "User does not have any email", mock.Hook.LastEntry().Message)
 }
 
+func TestShouldNotStartProcessIfEmailAddressIsUnverified(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.SelfRegistration.Enabled = true
+	mock.Ctx.Configuration.SelfRegistration.EmailVerification.Enabled = true
+
+	verification := model.NewEmailVerification("john", "john@example.com", "abc", time.Now(), time.Hour)
+
+	mock.StorageMock.EXPECT().
+		LoadEmailVerification(mock.Ctx, gomock.Eq("john")).
+		Return(&verification, nil)
+
+	middlewares.IdentityVerificationStart(newArgs(defaultRetriever), nil)(mock.Ctx)
+
+	assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, "the email address 'john@example.com' of user 'john' has not been verified", mock.Hook.LastEntry().Message)
+}
+
+func TestShouldNotStartProcessIfEmailDomainIsNotPermitted(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.EmailDomains = schema.EmailDomainsConfiguration{
+		Allowed: []string{"*.example.com"},
+		Denied:  []string{"old.example.com"},
+	}
+
+	retriever := func(ctx *middlewares.AutheliaCtx) (*session.Identity, error) {
+		return &session.Identity{
+			Username: "john",
+			Email:    "john@OLD.example.com",
+		}, nil
+	}
+
+	middlewares.IdentityVerificationStart(newArgs(retriever), nil)(mock.Ctx)
+
+	assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, "Refused to send the identity verification email for action 'Claim' to user 'john' as the domain of the email address 'john@OLD.example.com' is not permitted", mock.Hook.LastEntry().Message)
+}
+
 func TestShouldFailIfJWTCannotBeSaved(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
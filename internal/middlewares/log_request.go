@@ -1,17 +1,45 @@
 package middlewares
 
 import (
+	"time"
+
+	"github.com/fasthttp/router"
+	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
 )
 
-// LogRequestMiddleware logs the query that is being treated.
-func LogRequestMiddleware(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+// LogRequestMiddleware logs the query that is being treated. Requests which take at least the slowRequestThreshold
+// to complete are also logged at the warning level, a threshold of 0 disables this.
+func LogRequestMiddleware(slowRequestThreshold time.Duration, next fasthttp.RequestHandler) fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
 		autheliaCtx := &AutheliaCtx{RequestCtx: ctx}
 		logger := NewRequestLogger(autheliaCtx)
 
+		start := time.Now()
+
 		logger.Trace("Request hit")
 		next(ctx)
 		logger.Tracef("Replied (status=%d)", ctx.Response.StatusCode())
+
+		if duration := time.Since(start); slowRequestThreshold > 0 && duration >= slowRequestThreshold {
+			logSlowRequest(ctx, logger, duration, slowRequestThreshold)
+		}
+	}
+}
+
+func logSlowRequest(ctx *fasthttp.RequestCtx, logger *logrus.Entry, duration, threshold time.Duration) {
+	fields := logrus.Fields{
+		"duration": duration.String(),
+		"status":   ctx.Response.StatusCode(),
 	}
+
+	if route, ok := ctx.UserValue(router.MatchedRoutePathParam).(string); ok {
+		fields["route"] = route
+	}
+
+	if backend, ok := ctx.UserValueBytes(UserValueKeyBackend).(string); ok {
+		fields["backend"] = backend
+	}
+
+	logger.WithFields(fields).Warnf("Request took longer than the slow request threshold of %s", threshold)
 }
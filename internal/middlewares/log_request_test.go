@@ -2,9 +2,16 @@ package middlewares
 
 import (
 	"testing"
+	"time"
 
+	"github.com/fasthttp/router"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/logging"
 )
 
 func TestShouldCallNextFunction(t *testing.T) {
@@ -13,7 +20,51 @@ func TestShouldCallNextFunction(t *testing.T) {
 	f := func(ctx *fasthttp.RequestCtx) { val = true }
 
 	context := &fasthttp.RequestCtx{}
-	LogRequestMiddleware(f)(context)
+	LogRequestMiddleware(0, f)(context)
 
 	assert.Equal(t, true, val)
 }
+
+func TestShouldLogSlowRequests(t *testing.T) {
+	hook := test.NewLocal(logging.Logger())
+	defer hook.Reset()
+
+	f := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetUserValue(router.MatchedRoutePathParam, "/api/firstfactor")
+		ctx.SetUserValueBytes(UserValueKeyBackend, "ldap")
+
+		time.Sleep(time.Millisecond * 5)
+	}
+
+	context := &fasthttp.RequestCtx{}
+	context.Request.Header.SetMethod(fasthttp.MethodPost)
+	context.Request.SetRequestURI("/api/firstfactor")
+
+	LogRequestMiddleware(time.Millisecond, f)(context)
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+
+	assert.Equal(t, logrus.WarnLevel, entry.Level)
+	assert.Equal(t, "Request took longer than the slow request threshold of 1ms", entry.Message)
+	assert.Equal(t, "POST", entry.Data["method"])
+	assert.Equal(t, "/api/firstfactor", entry.Data["path"])
+	assert.Equal(t, "/api/firstfactor", entry.Data["route"])
+	assert.Equal(t, "ldap", entry.Data["backend"])
+	assert.Contains(t, entry.Data, "remote_ip")
+	assert.Contains(t, entry.Data, "duration")
+}
+
+func TestShouldNotLogFastRequests(t *testing.T) {
+	hook := test.NewLocal(logging.Logger())
+	defer hook.Reset()
+
+	f := func(ctx *fasthttp.RequestCtx) {}
+
+	LogRequestMiddleware(time.Hour, f)(&fasthttp.RequestCtx{})
+	LogRequestMiddleware(0, f)(&fasthttp.RequestCtx{})
+
+	for _, entry := range hook.AllEntries() {
+		assert.NotEqual(t, logrus.WarnLevel, entry.Level)
+	}
+}
@@ -0,0 +1,139 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+// OpenIDConnectTokenLimit applies the request body size and rate limits of the token endpoint to the next handler.
+// Requests are rate limited per remote IP and per client_id, and the clients isFirstParty returns true for have their
+// own higher limit and are not subject to the remote IP limit. Rejected requests receive an OAuth 2.0 error response.
+func OpenIDConnectTokenLimit(config schema.OpenIDConnectTokenEndpointConfiguration, isFirstParty func(clientID string) bool, next RequestHandler) RequestHandler {
+	newLimiter := func(limit int) *rateLimiter {
+		return &rateLimiter{limit: limit, period: config.RateLimitPeriod, windows: map[string]*rateLimitWindow{}}
+	}
+
+	limiterIP, limiterClient, limiterFirstParty := newLimiter(config.RateLimit), newLimiter(config.ClientRateLimit),
+		newLimiter(config.FirstPartyClientRateLimit)
+
+	return func(ctx *AutheliaCtx) {
+		if len(ctx.PostBody()) > config.MaxRequestBodySize {
+			ctx.Logger.Warnf("Access Request from '%s' was rejected as the body exceeds the maximum size of %d bytes", ctx.RemoteIP(), config.MaxRequestBodySize)
+
+			writeOpenIDConnectError(ctx, oidc.ErrRequestEntityTooLarge.WithHintf("The request body must not exceed %d bytes.", config.MaxRequestBodySize))
+
+			return
+		}
+
+		if config.DisableRateLimit {
+			next(ctx)
+
+			return
+		}
+
+		now := ctx.Clock.Now()
+		clientID := OpenIDConnectClientIDFromRequest(ctx.RequestCtx)
+		firstParty := clientID != "" && isFirstParty(clientID)
+
+		if !firstParty {
+			if retryAfter, ok := limiterIP.allow(ctx.RemoteIP().String(), now); !ok {
+				rejectOpenIDConnectTokenRateLimit(ctx, fmt.Sprintf("remote ip '%s'", ctx.RemoteIP()), config.RateLimit, config.RateLimitPeriod, retryAfter)
+
+				return
+			}
+		}
+
+		if clientID != "" {
+			limiter, limit := limiterClient, config.ClientRateLimit
+
+			if firstParty {
+				limiter, limit = limiterFirstParty, config.FirstPartyClientRateLimit
+			}
+
+			if retryAfter, ok := limiter.allow(clientID, now); !ok {
+				rejectOpenIDConnectTokenRateLimit(ctx, fmt.Sprintf("client '%s'", clientID), limit, config.RateLimitPeriod, retryAfter)
+
+				return
+			}
+		}
+
+		next(ctx)
+	}
+}
+
+func rejectOpenIDConnectTokenRateLimit(ctx *AutheliaCtx, source string, limit int, period, retryAfter time.Duration) {
+	ctx.Logger.Warnf("Access Request from %s exceeded the rate limit of %d requests per %s", source, limit, period)
+
+	ctx.Response.Header.SetBytesK(headerRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+	writeOpenIDConnectError(ctx, oidc.ErrRateLimitExceeded)
+}
+
+// OpenIDConnectClientIDFromRequest returns the client_id of a request from either the form or the HTTP Basic
+// authorization header. The client is not authenticated by this function.
+func OpenIDConnectClientIDFromRequest(ctx *fasthttp.RequestCtx) (id string) {
+	if id = string(ctx.PostArgs().Peek(formKeyClientID)); id != "" {
+		return id
+	}
+
+	auth := ctx.Request.Header.PeekBytes(headerAuthorization)
+
+	if len(auth) <= len(headerValueBasicPrefix) || !bytes.EqualFold(auth[:len(headerValueBasicPrefix)], headerValueBasicPrefix) {
+		return ""
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(string(auth[len(headerValueBasicPrefix):]))
+	if err != nil {
+		return ""
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+
+	if id, err = url.QueryUnescape(parts[0]); err != nil {
+		return ""
+	}
+
+	return id
+}
+
+// OpenIDConnectAccessTokenFromRequest returns the access token of a request from either the HTTP Bearer authorization
+// header or the form. The token is not validated by this function.
+func OpenIDConnectAccessTokenFromRequest(ctx *fasthttp.RequestCtx) (token string) {
+	auth := ctx.Request.Header.PeekBytes(headerAuthorization)
+
+	if len(auth) > len(headerValueBearerPrefix) && bytes.EqualFold(auth[:len(headerValueBearerPrefix)], headerValueBearerPrefix) {
+		return string(auth[len(headerValueBearerPrefix):])
+	}
+
+	return string(ctx.PostArgs().Peek(formKeyAccessToken))
+}
+
+// writeOpenIDConnectError writes an OAuth 2.0 error response in the same format as the fosite error responses.
+func writeOpenIDConnectError(ctx *AutheliaCtx, rfc *fosite.RFC6749Error) {
+	body, err := json.Marshal(rfc)
+	if err != nil {
+		ctx.Logger.Errorf("Failed to marshal the error response: %v", err)
+
+		body = []byte(fmt.Sprintf(`{"error":%q}`, rfc.ErrorField))
+	}
+
+	ctx.Response.Header.Set(fasthttp.HeaderCacheControl, "no-store")
+	ctx.Response.Header.Set("Pragma", "no-cache")
+
+	ctx.SetContentType(contentTypeApplicationJSON)
+	ctx.SetStatusCode(rfc.CodeField)
+	ctx.SetBody(body)
+}
@@ -0,0 +1,137 @@
+package middlewares_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func newOpenIDConnectTokenLimitConfig() schema.OpenIDConnectTokenEndpointConfiguration {
+	return schema.OpenIDConnectTokenEndpointConfiguration{
+		MaxRequestBodySize:        64,
+		RateLimit:                 2,
+		ClientRateLimit:           1,
+		FirstPartyClientRateLimit: 3,
+		RateLimitPeriod:           time.Minute,
+	}
+}
+
+func doOpenIDConnectTokenRequest(t *testing.T, handler middlewares.RequestHandler, ip, authorization, body string) *mocks.MockAutheliaCtx {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Ctrl.Finish()
+
+	mock.Ctx.Clock = &mock.Clock
+
+	mock.Ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	mock.Ctx.Request.Header.SetContentType("application/x-www-form-urlencoded")
+	mock.Ctx.Request.Header.Set(fasthttp.HeaderXForwardedFor, ip)
+
+	if authorization != "" {
+		mock.Ctx.Request.Header.Set(fasthttp.HeaderAuthorization, authorization)
+	}
+
+	mock.Ctx.Request.SetBodyString(body)
+
+	handler(mock.Ctx)
+
+	return mock
+}
+
+func newOpenIDConnectTokenLimitHandler(config schema.OpenIDConnectTokenEndpointConfiguration) (handler middlewares.RequestHandler, calls *int) {
+	calls = new(int)
+
+	isFirstParty := func(clientID string) bool {
+		return clientID == "first-party"
+	}
+
+	return middlewares.OpenIDConnectTokenLimit(config, isFirstParty, func(ctx *middlewares.AutheliaCtx) {
+		*calls++
+
+		ctx.ReplyOK()
+	}), calls
+}
+
+func TestOpenIDConnectTokenLimit_ShouldRejectLargeBodies(t *testing.T) {
+	handler, calls := newOpenIDConnectTokenLimitHandler(newOpenIDConnectTokenLimitConfig())
+
+	mock := doOpenIDConnectTokenRequest(t, handler, "192.168.0.1", "", "grant_type=authorization_code&code="+string(make([]byte, 64)))
+
+	assert.Equal(t, 0, *calls)
+	assert.Equal(t, fasthttp.StatusRequestEntityTooLarge, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, "no-store", string(mock.Ctx.Response.Header.Peek(fasthttp.HeaderCacheControl)))
+	assert.JSONEq(t, `{"error":"invalid_request","error_description":"The request body is too large. The request body must not exceed 64 bytes."}`, string(mock.Ctx.Response.Body()))
+}
+
+func TestOpenIDConnectTokenLimit_ShouldRateLimitPerRemoteIP(t *testing.T) {
+	handler, calls := newOpenIDConnectTokenLimitHandler(newOpenIDConnectTokenLimitConfig())
+
+	for i := 0; i < 2; i++ {
+		mock := doOpenIDConnectTokenRequest(t, handler, "192.168.0.1", "", "grant_type=authorization_code")
+		assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+	}
+
+	mock := doOpenIDConnectTokenRequest(t, handler, "192.168.0.1", "", "grant_type=authorization_code")
+
+	assert.Equal(t, 2, *calls)
+	assert.Equal(t, fasthttp.StatusTooManyRequests, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, "60", string(mock.Ctx.Response.Header.Peek(fasthttp.HeaderRetryAfter)))
+	assert.JSONEq(t, `{"error":"temporarily_unavailable","error_description":"The server is temporarily unable to handle the request as the rate limit has been exceeded."}`, string(mock.Ctx.Response.Body()))
+
+	mock = doOpenIDConnectTokenRequest(t, handler, "192.168.0.2", "", "grant_type=authorization_code")
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+}
+
+func TestOpenIDConnectTokenLimit_ShouldRateLimitPerClient(t *testing.T) {
+	handler, calls := newOpenIDConnectTokenLimitHandler(newOpenIDConnectTokenLimitConfig())
+
+	mock := doOpenIDConnectTokenRequest(t, handler, "192.168.0.1", "", "grant_type=client_credentials&client_id=app")
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+
+	// The client is identified from the HTTP Basic authorization header as well as the form.
+	mock = doOpenIDConnectTokenRequest(t, handler, "192.168.0.2", "Basic "+base64.StdEncoding.EncodeToString([]byte("app:secret")), "grant_type=client_credentials")
+	assert.Equal(t, fasthttp.StatusTooManyRequests, mock.Ctx.Response.StatusCode())
+
+	mock = doOpenIDConnectTokenRequest(t, handler, "192.168.0.2", "", "grant_type=client_credentials&client_id=other")
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+
+	assert.Equal(t, 2, *calls)
+}
+
+func TestOpenIDConnectTokenLimit_ShouldAllowFirstPartyClientsHigherLimit(t *testing.T) {
+	handler, calls := newOpenIDConnectTokenLimitHandler(newOpenIDConnectTokenLimitConfig())
+
+	for i := 0; i < 3; i++ {
+		mock := doOpenIDConnectTokenRequest(t, handler, "192.168.0.1", "", "grant_type=client_credentials&client_id=first-party")
+		assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+	}
+
+	mock := doOpenIDConnectTokenRequest(t, handler, "192.168.0.1", "", "grant_type=client_credentials&client_id=first-party")
+	assert.Equal(t, fasthttp.StatusTooManyRequests, mock.Ctx.Response.StatusCode())
+
+	// Requests of first party clients aren't counted towards the remote IP limit.
+	mock = doOpenIDConnectTokenRequest(t, handler, "192.168.0.1", "", "grant_type=authorization_code")
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+
+	assert.Equal(t, 4, *calls)
+}
+
+func TestOpenIDConnectTokenLimit_ShouldNotRateLimitWhenDisabled(t *testing.T) {
+	config := newOpenIDConnectTokenLimitConfig()
+	config.DisableRateLimit = true
+
+	handler, calls := newOpenIDConnectTokenLimitHandler(config)
+
+	for i := 0; i < 5; i++ {
+		mock := doOpenIDConnectTokenRequest(t, handler, "192.168.0.1", "", "grant_type=client_credentials&client_id=app")
+		assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+	}
+
+	assert.Equal(t, 5, *calls)
+}
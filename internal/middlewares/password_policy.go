@@ -4,29 +4,44 @@ import (
 	"regexp"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // NewPasswordPolicyProvider returns a new password policy provider.
 func NewPasswordPolicyProvider(config schema.PasswordPolicyConfiguration) (provider PasswordPolicyProvider) {
-	if !config.Standard.Enabled {
+	provider = newPasswordPolicyProvider(config.Standard)
+
+	for _, policy := range config.Policies {
+		provider.policies = append(provider.policies, groupPasswordPolicy{
+			name:     policy.Name,
+			groups:   policy.Groups,
+			provider: newPasswordPolicyProvider(policy.Standard),
+		})
+	}
+
+	return provider
+}
+
+func newPasswordPolicyProvider(config schema.PasswordPolicyStandardParams) (provider PasswordPolicyProvider) {
+	if !config.Enabled {
 		return provider
 	}
 
-	provider.min, provider.max = config.Standard.MinLength, config.Standard.MaxLength
+	provider.min, provider.max = config.MinLength, config.MaxLength
 
-	if config.Standard.RequireLowercase {
+	if config.RequireLowercase {
 		provider.patterns = append(provider.patterns, *regexp.MustCompile(`[a-z]+`))
 	}
 
-	if config.Standard.RequireUppercase {
+	if config.RequireUppercase {
 		provider.patterns = append(provider.patterns, *regexp.MustCompile(`[A-Z]+`))
 	}
 
-	if config.Standard.RequireNumber {
+	if config.RequireNumber {
 		provider.patterns = append(provider.patterns, *regexp.MustCompile(`[0-9]+`))
 	}
 
-	if config.Standard.RequireSpecial {
+	if config.RequireSpecial {
 		provider.patterns = append(provider.patterns, *regexp.MustCompile(`[^a-zA-Z0-9]+`))
 	}
 
@@ -37,6 +52,34 @@ func NewPasswordPolicyProvider(config schema.PasswordPolicyConfiguration) (provi
 type PasswordPolicyProvider struct {
 	patterns []regexp.Regexp
 	min, max int
+
+	policies []groupPasswordPolicy
+}
+
+type groupPasswordPolicy struct {
+	name     string
+	groups   []string
+	provider PasswordPolicyProvider
+}
+
+// HasGroupPolicies returns true if any password policies which apply to the members of specific groups are configured.
+func (p PasswordPolicyProvider) HasGroupPolicies() bool {
+	return len(p.policies) != 0
+}
+
+// ForGroups returns the name of the password policy and the PasswordPolicyProvider which applies to a user who is a
+// member of the provided groups. When a user is a member of the groups of multiple policies the first configured policy
+// takes precedence. If no policy applies the name is empty and the global password policy is returned.
+func (p PasswordPolicyProvider) ForGroups(groups []string) (name string, provider PasswordPolicyProvider) {
+	for _, policy := range p.policies {
+		for _, group := range groups {
+			if utils.IsStringInSlice(group, policy.groups) {
+				return policy.name, policy.provider
+			}
+		}
+	}
+
+	return "", p
 }
 
 // Check checks the password against the policy.
@@ -143,3 +143,37 @@ func TestPasswordPolicyProvider_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestPasswordPolicyProvider_ForGroups(t *testing.T) {
+	provider := NewPasswordPolicyProvider(schema.PasswordPolicyConfiguration{
+		Standard: schema.PasswordPolicyStandardParams{Enabled: true, MinLength: 8},
+		Policies: []schema.PasswordPolicyGroupConfiguration{
+			{Name: "admins", Groups: []string{"admins"}, Standard: schema.PasswordPolicyStandardParams{Enabled: true, MinLength: 16}},
+			{Name: "developers", Groups: []string{"dev", "ops"}, Standard: schema.PasswordPolicyStandardParams{Enabled: true, MinLength: 12}},
+		},
+	})
+
+	assert.True(t, provider.HasGroupPolicies())
+	assert.False(t, NewPasswordPolicyProvider(schema.PasswordPolicyConfiguration{}).HasGroupPolicies())
+
+	testCases := []struct {
+		desc     string
+		groups   []string
+		expected string
+		min      int
+	}{
+		{"ShouldReturnGlobalPolicyWithoutGroups", nil, "", 8},
+		{"ShouldReturnGlobalPolicyWithOtherGroups", []string{"users"}, "", 8},
+		{"ShouldReturnPolicyOfGroup", []string{"users", "ops"}, "developers", 12},
+		{"ShouldReturnFirstConfiguredPolicy", []string{"dev", "admins"}, "admins", 16},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			name, policy := provider.ForGroups(tc.groups)
+
+			assert.Equal(t, tc.expected, name)
+			assert.Equal(t, tc.min, policy.min)
+		})
+	}
+}
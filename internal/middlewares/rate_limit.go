@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// RateLimit only allows each remote IP to make limit requests to the next handler per period. Requests exceeding the
+// limit are rejected with a 429 Too Many Requests status code.
+func RateLimit(limit int, period time.Duration, next RequestHandler) RequestHandler {
+	limiter := &rateLimiter{
+		limit:   limit,
+		period:  period,
+		windows: map[string]*rateLimitWindow{},
+	}
+
+	return func(ctx *AutheliaCtx) {
+		if retryAfter, ok := limiter.allow(ctx.RemoteIP().String(), ctx.Clock.Now()); !ok {
+			ctx.Logger.Warnf("Request from '%s' exceeded the rate limit of %d requests per %s", ctx.RemoteIP(), limit, period)
+
+			ctx.Response.Header.SetBytesK(headerRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			ctx.RequestCtx.Error(fasthttp.StatusMessage(fasthttp.StatusTooManyRequests), fasthttp.StatusTooManyRequests)
+
+			return
+		}
+
+		next(ctx)
+	}
+}
+
+type rateLimiter struct {
+	limit  int
+	period time.Duration
+
+	mutex   sync.Mutex
+	windows map[string]*rateLimitWindow
+}
+
+type rateLimitWindow struct {
+	start time.Time
+	count int
+}
+
+// allow records a request from the key and returns true if it's within the limit, otherwise it returns the time
+// remaining until the current window ends. Windows which have ended are removed so the map doesn't grow unbounded.
+func (l *rateLimiter) allow(key string, now time.Time) (retryAfter time.Duration, ok bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for k, window := range l.windows {
+		if now.Sub(window.start) >= l.period {
+			delete(l.windows, k)
+		}
+	}
+
+	window, found := l.windows[key]
+	if !found {
+		window = &rateLimitWindow{start: now}
+		l.windows[key] = window
+	}
+
+	if window.count >= l.limit {
+		return window.start.Add(l.period).Sub(now), false
+	}
+
+	window.count++
+
+	return 0, true
+}
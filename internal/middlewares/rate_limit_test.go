@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_ShouldLimitRequestsPerPeriod(t *testing.T) {
+	limiter := &rateLimiter{limit: 2, period: time.Minute, windows: map[string]*rateLimitWindow{}}
+
+	now := time.Unix(1000, 0)
+
+	_, ok := limiter.allow("192.168.1.1", now)
+	assert.True(t, ok)
+
+	_, ok = limiter.allow("192.168.1.1", now.Add(time.Second))
+	assert.True(t, ok)
+
+	retryAfter, ok := limiter.allow("192.168.1.1", now.Add(time.Second*20))
+	assert.False(t, ok)
+	assert.Equal(t, time.Second*40, retryAfter)
+
+	_, ok = limiter.allow("192.168.1.2", now.Add(time.Second*20))
+	assert.True(t, ok)
+
+	_, ok = limiter.allow("192.168.1.1", now.Add(time.Minute+time.Second*20))
+	assert.True(t, ok)
+
+	assert.Len(t, limiter.windows, 1)
+}
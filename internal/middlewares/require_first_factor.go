@@ -7,8 +7,26 @@ import (
 // Require1FA check if user has enough permissions to execute the next handler.
 func Require1FA(next RequestHandler) RequestHandler {
 	return func(ctx *AutheliaCtx) {
-		if ctx.GetSession().AuthenticationLevel < authentication.OneFactor {
+		userSession := ctx.GetSession()
+
+		if userSession.AuthenticationLevel < authentication.OneFactor {
+			ctx.ReplyForbidden()
+			return
+		}
+
+		// The user is treated as disabled when it can't be determined to prevent bypassing the check.
+		switch disabled, err := authentication.IsUserDisabled(ctx.Providers.UserProvider, userSession.Username); {
+		case err != nil:
+			ctx.Logger.Errorf("Unable to check if user '%s' is disabled: %v", userSession.Username, err)
+
 			ctx.ReplyForbidden()
+
+			return
+		case disabled:
+			ctx.Logger.Infof("User '%s' is disabled and can't use an existing session", userSession.Username)
+
+			ctx.ReplyForbidden()
+
 			return
 		}
 
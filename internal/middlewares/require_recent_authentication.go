@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// RequireRecentAuthentication requires the user to have completed a factor within the configured window to perform
+// the sensitive action handled by next. Requests are rejected with a 401 Unauthorized status code otherwise so the
+// user can complete a factor again. The next handler is returned as is when the action doesn't require it.
+func RequireRecentAuthentication(config schema.SensitiveActionsConfiguration, action string, next RequestHandler) RequestHandler {
+	if !config.Enabled || !utils.IsStringInSlice(action, config.Actions) {
+		return next
+	}
+
+	return func(ctx *AutheliaCtx) {
+		userSession := ctx.GetSession()
+
+		authenticatedAt := userSession.FirstFactorAuthnTimestamp
+
+		if userSession.SecondFactorAuthnTimestamp > authenticatedAt {
+			authenticatedAt = userSession.SecondFactorAuthnTimestamp
+		}
+
+		if elapsed := ctx.Clock.Now().Sub(time.Unix(authenticatedAt, 0)); elapsed > config.Window {
+			ctx.Logger.Infof("User '%s' must authenticate again to perform the sensitive action '%s' as they last "+
+				"completed a factor %s ago", userSession.Username, action, elapsed.Truncate(time.Second))
+
+			ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			ctx.SetJSONError(messageRecentAuthenticationRequired)
+
+			return
+		}
+
+		next(ctx)
+	}
+}
@@ -0,0 +1,84 @@
+package middlewares_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+func newRecentAuthenticationMock(t *testing.T, firstFactor, secondFactor time.Duration) *mocks.MockAutheliaCtx {
+	mock := mocks.NewMockAutheliaCtx(t)
+	mock.Ctx.Clock = &mock.Clock
+
+	now := mock.Clock.Now()
+
+	userSession := session.UserSession{
+		Username:                  "john",
+		AuthenticationLevel:       authentication.TwoFactor,
+		FirstFactorAuthnTimestamp: now.Add(-firstFactor).Unix(),
+	}
+
+	if secondFactor != 0 {
+		userSession.SecondFactorAuthnTimestamp = now.Add(-secondFactor).Unix()
+	}
+
+	assert.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	return mock
+}
+
+func TestRequireRecentAuthentication(t *testing.T) {
+	config := schema.SensitiveActionsConfiguration{
+		Enabled: true,
+		Window:  time.Minute * 5,
+		Actions: []string{schema.SensitiveActionTOTPDeletion},
+	}
+
+	testCases := []struct {
+		name                      string
+		config                    schema.SensitiveActionsConfiguration
+		action                    string
+		firstFactor, secondFactor time.Duration
+		expected                  bool
+	}{
+		{"ShouldAllowRecentFirstFactor", config, schema.SensitiveActionTOTPDeletion, time.Minute, 0, true},
+		{"ShouldAllowRecentSecondFactor", config, schema.SensitiveActionTOTPDeletion, time.Hour, time.Minute, true},
+		{"ShouldAllowFactorAtEndOfWindow", config, schema.SensitiveActionTOTPDeletion, time.Minute * 5, 0, true},
+		{"ShouldDenyExpiredFactors", config, schema.SensitiveActionTOTPDeletion, time.Hour, time.Minute * 6, false},
+		{"ShouldDenyExpiredFirstFactor", config, schema.SensitiveActionTOTPDeletion, time.Minute * 6, 0, false},
+		{"ShouldAllowActionNotConfigured", config, schema.SensitiveActionTOTPRegistration, time.Hour, time.Hour, true},
+		{"ShouldAllowWhenDisabled", schema.SensitiveActionsConfiguration{Window: time.Minute, Actions: config.Actions}, schema.SensitiveActionTOTPDeletion, time.Hour, time.Hour, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := newRecentAuthenticationMock(t, tc.firstFactor, tc.secondFactor)
+			defer mock.Ctrl.Finish()
+
+			called := false
+
+			middlewares.RequireRecentAuthentication(tc.config, tc.action, func(ctx *middlewares.AutheliaCtx) {
+				called = true
+
+				ctx.ReplyOK()
+			})(mock.Ctx)
+
+			assert.Equal(t, tc.expected, called)
+
+			if tc.expected {
+				assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+			} else {
+				assert.Equal(t, fasthttp.StatusUnauthorized, mock.Ctx.Response.StatusCode())
+				assert.JSONEq(t, `{"status":"KO","message":"You must authenticate again to perform this action"}`, string(mock.Ctx.Response.Body()))
+			}
+		})
+	}
+}
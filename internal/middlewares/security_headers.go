@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// SecurityHeadersMiddleware sets the configured security headers on every response unless the handler already set
+// them. Headers configured with the schema.ServerHeaderValueDisabled value are not set.
+func SecurityHeadersMiddleware(config schema.ServerHeadersConfiguration, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	headers := map[string]string{
+		headerReferrerPolicy:          config.ReferrerPolicy,
+		headerPermissionsPolicy:       config.PermissionsPolicy,
+		headerXFrameOptions:           config.FrameOptions,
+		headerStrictTransportSecurity: config.StrictTransportSecurity,
+	}
+
+	for header, value := range headers {
+		if value == "" || value == schema.ServerHeaderValueDisabled {
+			delete(headers, header)
+		}
+	}
+
+	headers[fasthttp.HeaderXContentTypeOptions] = headerValueNoSniff
+
+	return func(ctx *fasthttp.RequestCtx) {
+		next(ctx)
+
+		for header, value := range headers {
+			if len(ctx.Response.Header.Peek(header)) == 0 {
+				ctx.Response.Header.Set(header, value)
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestSecurityHeadersMiddlewareShouldSetDefaultHeaders(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+
+	SecurityHeadersMiddleware(schema.DefaultServerConfiguration.Headers, func(ctx *fasthttp.RequestCtx) {})(ctx)
+
+	assert.Equal(t, "nosniff", string(ctx.Response.Header.Peek(fasthttp.HeaderXContentTypeOptions)))
+	assert.Equal(t, "DENY", string(ctx.Response.Header.Peek(fasthttp.HeaderXFrameOptions)))
+	assert.Equal(t, "strict-origin-when-cross-origin", string(ctx.Response.Header.Peek("Referrer-Policy")))
+	assert.Equal(t, "max-age=31536000", string(ctx.Response.Header.Peek(fasthttp.HeaderStrictTransportSecurity)))
+	assert.Equal(t, schema.DefaultServerConfiguration.Headers.PermissionsPolicy, string(ctx.Response.Header.Peek("Permissions-Policy")))
+}
+
+func TestSecurityHeadersMiddlewareShouldNotSetDisabledOrOverriddenHeaders(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+
+	config := schema.ServerHeadersConfiguration{
+		ReferrerPolicy:          "no-referrer",
+		FrameOptions:            schema.ServerHeaderValueDisabled,
+		StrictTransportSecurity: schema.ServerHeaderValueDisabled,
+	}
+
+	SecurityHeadersMiddleware(config, func(ctx *fasthttp.RequestCtx) {
+		ctx.Response.Header.Set("Referrer-Policy", "same-origin")
+	})(ctx)
+
+	assert.Equal(t, "nosniff", string(ctx.Response.Header.Peek(fasthttp.HeaderXContentTypeOptions)))
+	assert.Equal(t, "same-origin", string(ctx.Response.Header.Peek("Referrer-Policy")))
+	assert.Nil(t, ctx.Response.Header.Peek(fasthttp.HeaderXFrameOptions))
+	assert.Nil(t, ctx.Response.Header.Peek(fasthttp.HeaderStrictTransportSecurity))
+	assert.Nil(t, ctx.Response.Header.Peek("Permissions-Policy"))
+}
@@ -6,16 +6,28 @@ import (
 	"github.com/valyala/fasthttp"
 )
 
-// StripPathMiddleware strips the first level of a path.
+// StripPathMiddleware strips the first level of a path. The well-known paths are served at the root as required by
+// the specifications they implement but still use the path as the base URL so the issuer includes the path.
 func StripPathMiddleware(path string, next fasthttp.RequestHandler) fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
-		uri := ctx.RequestURI()
+		uri := string(ctx.RequestURI())
 
-		if strings.HasPrefix(string(uri), path) {
+		switch {
+		case uri == path:
 			ctx.SetUserValueBytes(UserValueKeyBaseURL, path)
+			ctx.Request.SetRequestURI("/")
+		case strings.HasPrefix(uri, path+"/"), strings.HasPrefix(uri, path+"?"):
+			ctx.SetUserValueBytes(UserValueKeyBaseURL, path)
+
+			newURI := strings.TrimPrefix(uri, path)
+
+			if strings.HasPrefix(newURI, "?") {
+				newURI = "/" + newURI
+			}
 
-			newURI := strings.TrimPrefix(string(uri), path)
 			ctx.Request.SetRequestURI(newURI)
+		case strings.HasPrefix(uri, pathWellKnown):
+			ctx.SetUserValueBytes(UserValueKeyBaseURL, path)
 		}
 
 		next(ctx)
@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestStripPathMiddleware(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     string
+		uri      string
+		expected string
+		base     interface{}
+	}{
+		{"ShouldStripPath", "/authelia", "/authelia/api/state", "/api/state", "/authelia"},
+		{"ShouldStripPathWithTrailingSlash", "/authelia", "/authelia/", "/", "/authelia"},
+		{"ShouldStripPathWithoutTrailingSlash", "/authelia", "/authelia", "/", "/authelia"},
+		{"ShouldStripPathWithQuery", "/authelia", "/authelia?rd=https%3A%2F%2Fexample.com", "/?rd=https%3A%2F%2Fexample.com", "/authelia"},
+		{"ShouldStripPathFromAssets", "/authelia", "/authelia/static/js/index.js", "/static/js/index.js", "/authelia"},
+		{"ShouldStripPathFromLocales", "/authelia", "/authelia/locales/en/portal.json", "/locales/en/portal.json", "/authelia"},
+		{"ShouldNotStripPartialPath", "/authelia", "/autheliax/api/state", "/autheliax/api/state", nil},
+		{"ShouldNotStripOtherPath", "/authelia", "/api/state", "/api/state", nil},
+		{"ShouldNotStripWellKnownButSetBase", "/authelia", "/.well-known/openid-configuration", "/.well-known/openid-configuration", "/authelia"},
+		{"ShouldStripWellKnownUnderPath", "/authelia", "/authelia/.well-known/openid-configuration", "/.well-known/openid-configuration", "/authelia"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				uri  string
+				base interface{}
+			)
+
+			handler := StripPathMiddleware(tc.path, func(ctx *fasthttp.RequestCtx) {
+				uri = string(ctx.RequestURI())
+				base = ctx.UserValueBytes(UserValueKeyBaseURL)
+			})
+
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.SetRequestURI(tc.uri)
+
+			handler(ctx)
+
+			assert.Equal(t, tc.expected, uri)
+			assert.Equal(t, tc.base, base)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewTrustedProxies parses the trusted proxies configuration into the networks used to determine the remote IP.
+func NewTrustedProxies(networks []string) (proxies []*net.IPNet, err error) {
+	proxies = make([]*net.IPNet, 0, len(networks))
+
+	for _, network := range networks {
+		cidr, err := utils.ParseNetwork(network)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing trusted proxy '%s': %w", network, err)
+		}
+
+		proxies = append(proxies, cidr)
+	}
+
+	return proxies, nil
+}
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTrustedProxies(t *testing.T) {
+	proxies, err := NewTrustedProxies([]string{"10.0.0.0/8", "172.16.0.1", "fec0::1"})
+	require.NoError(t, err)
+	require.Len(t, proxies, 3)
+
+	assert.Equal(t, "10.0.0.0/8", proxies[0].String())
+	assert.Equal(t, "172.16.0.1/32", proxies[1].String())
+	assert.Equal(t, "fec0::1/128", proxies[2].String())
+}
+
+func TestNewTrustedProxiesShouldReturnErrorOnInvalidNetwork(t *testing.T) {
+	proxies, err := NewTrustedProxies([]string{"10.0.0.0/8", "proxy.example.com"})
+
+	assert.Nil(t, proxies)
+	assert.EqualError(t, err, "error parsing trusted proxy 'proxy.example.com': invalid CIDR address: proxy.example.com/128")
+}
@@ -1,12 +1,16 @@
 package middlewares
 
 import (
+	"net"
+
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/events"
+	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/notification"
 	"github.com/authelia/authelia/v4/internal/ntp"
 	"github.com/authelia/authelia/v4/internal/oidc"
@@ -40,6 +44,22 @@ type Providers struct {
 	Notifier        notification.Notifier
 	TOTP            totp.Provider
 	PasswordPolicy  PasswordPolicyProvider
+	TrustedProxies  []*net.IPNet
+
+	ClientCertificate *authentication.ClientCertificateVerifier
+	TrustedJWT        *authentication.TrustedJWTVerifier
+	TrustedHeader     *authentication.TrustedHeaderVerifier
+	APIKey            *authentication.APIKeyVerifier
+	BreakGlass        *authentication.BreakGlassAccount
+	UserDetailsCache  *authentication.UserDetailsCache
+	CAPTCHA           regulation.CAPTCHAProvider
+	Events            *events.Emitter
+}
+
+// ProviderHealthCheck is the health check of a named provider.
+type ProviderHealthCheck struct {
+	Name  string
+	Check model.HealthCheck
 }
 
 // RequestHandler represents an Authelia request handler.
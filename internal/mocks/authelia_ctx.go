@@ -108,7 +108,7 @@ func NewMockAutheliaCtx(t *testing.T) *MockAutheliaCtx {
 		&configuration)
 
 	providers.SessionProvider = session.NewProvider(
-		configuration.Session, nil)
+		configuration.Session, nil, providers.StorageProvider)
 
 	providers.Regulator = regulation.NewRegulator(configuration.Regulation, providers.StorageProvider, &mockAuthelia.Clock)
 
@@ -139,6 +139,22 @@ func NewMockAutheliaCtxWithUserSession(t *testing.T, userSession session.UserSes
 	return mock
 }
 
+// NewOtherUserSession saves and registers another session of the user as if they were logged in from another browser and
+// returns its session ID.
+func (m *MockAutheliaCtx) NewOtherUserSession(t *testing.T, userSession session.UserSession) string {
+	ctx := &fasthttp.RequestCtx{}
+
+	require.NoError(t, m.Ctx.Providers.SessionProvider.SaveSession(ctx, userSession))
+	require.NoError(t, m.Ctx.Providers.SessionProvider.RegisterUserSession(ctx, userSession.Username, false))
+
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	require.NoError(t, cookie.ParseBytes(ctx.Response.Header.PeekCookie(m.Ctx.Configuration.Session.Name)))
+
+	return string(cookie.Value())
+}
+
 // Close close the mock.
 func (m *MockAutheliaCtx) Close() {
 	m.Hook.Reset()
@@ -8,3 +8,5 @@ package mocks
 //go:generate mockgen -package mocks -destination totp.go -mock_names Provider=MockTOTP github.com/authelia/authelia/v4/internal/totp Provider
 //go:generate mockgen -package mocks -destination storage.go -mock_names Provider=MockStorage github.com/authelia/authelia/v4/internal/storage Provider
 //go:generate mockgen -package mocks -destination duo_api.go -mock_names API=MockAPI github.com/authelia/authelia/v4/internal/duo API
+//go:generate mockgen -package mocks -destination event_sink.go -mock_names Sink=MockEventSink github.com/authelia/authelia/v4/internal/events Sink
+//go:generate mockgen -package mocks -destination captcha_provider.go -mock_names CAPTCHAProvider=MockCAPTCHAProvider github.com/authelia/authelia/v4/internal/regulation CAPTCHAProvider
@@ -96,6 +96,20 @@ func (mr *MockStorageMockRecorder) Commit(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockStorage)(nil).Commit), arg0)
 }
 
+// ConsumeEmailVerification mocks base method.
+func (m *MockStorage) ConsumeEmailVerification(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeEmailVerification", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeEmailVerification indicates an expected call of ConsumeEmailVerification.
+func (mr *MockStorageMockRecorder) ConsumeEmailVerification(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeEmailVerification", reflect.TypeOf((*MockStorage)(nil).ConsumeEmailVerification), arg0, arg1, arg2)
+}
+
 // ConsumeIdentityVerification mocks base method.
 func (m *MockStorage) ConsumeIdentityVerification(arg0 context.Context, arg1 string, arg2 model.NullIP) error {
 	m.ctrl.T.Helper()
@@ -110,6 +124,50 @@ func (mr *MockStorageMockRecorder) ConsumeIdentityVerification(arg0, arg1, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeIdentityVerification", reflect.TypeOf((*MockStorage)(nil).ConsumeIdentityVerification), arg0, arg1, arg2)
 }
 
+// ConsumeRegulationUnlock mocks base method.
+func (m *MockStorage) ConsumeRegulationUnlock(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeRegulationUnlock", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeRegulationUnlock indicates an expected call of ConsumeRegulationUnlock.
+func (mr *MockStorageMockRecorder) ConsumeRegulationUnlock(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeRegulationUnlock", reflect.TypeOf((*MockStorage)(nil).ConsumeRegulationUnlock), arg0, arg1, arg2)
+}
+
+// CountSecondFactorFailedAuthenticationLogs mocks base method.
+func (m *MockStorage) CountSecondFactorFailedAuthenticationLogs(arg0 context.Context, arg1 string, arg2 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSecondFactorFailedAuthenticationLogs", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSecondFactorFailedAuthenticationLogs indicates an expected call of CountSecondFactorFailedAuthenticationLogs.
+func (mr *MockStorageMockRecorder) CountSecondFactorFailedAuthenticationLogs(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSecondFactorFailedAuthenticationLogs", reflect.TypeOf((*MockStorage)(nil).CountSecondFactorFailedAuthenticationLogs), arg0, arg1, arg2)
+}
+
+// CountSessions mocks base method.
+func (m *MockStorage) CountSessions(arg0 context.Context, arg1 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSessions", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSessions indicates an expected call of CountSessions.
+func (mr *MockStorageMockRecorder) CountSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSessions", reflect.TypeOf((*MockStorage)(nil).CountSessions), arg0, arg1)
+}
+
 // DeactivateOAuth2Session mocks base method.
 func (m *MockStorage) DeactivateOAuth2Session(arg0 context.Context, arg1 storage.OAuth2SessionType, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -138,6 +196,91 @@ func (mr *MockStorageMockRecorder) DeactivateOAuth2SessionByRequestID(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateOAuth2SessionByRequestID", reflect.TypeOf((*MockStorage)(nil).DeactivateOAuth2SessionByRequestID), arg0, arg1, arg2)
 }
 
+// DeleteAuthenticationLogs mocks base method.
+func (m *MockStorage) DeleteAuthenticationLogs(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthenticationLogs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuthenticationLogs indicates an expected call of DeleteAuthenticationLogs.
+func (mr *MockStorageMockRecorder) DeleteAuthenticationLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthenticationLogs", reflect.TypeOf((*MockStorage)(nil).DeleteAuthenticationLogs), arg0, arg1)
+}
+
+// DeleteEmailVerification mocks base method.
+func (m *MockStorage) DeleteEmailVerification(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEmailVerification", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEmailVerification indicates an expected call of DeleteEmailVerification.
+func (mr *MockStorageMockRecorder) DeleteEmailVerification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEmailVerification", reflect.TypeOf((*MockStorage)(nil).DeleteEmailVerification), arg0, arg1)
+}
+
+// DeleteExpiredRows mocks base method.
+func (m *MockStorage) DeleteExpiredRows(arg0 context.Context, arg1, arg2 time.Time, arg3 int) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredRows", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredRows indicates an expected call of DeleteExpiredRows.
+func (mr *MockStorageMockRecorder) DeleteExpiredRows(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredRows", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredRows), arg0, arg1, arg2, arg3)
+}
+
+// DeleteExpiredSessions mocks base method.
+func (m *MockStorage) DeleteExpiredSessions(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredSessions indicates an expected call of DeleteExpiredSessions.
+func (mr *MockStorageMockRecorder) DeleteExpiredSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredSessions", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredSessions), arg0, arg1)
+}
+
+// DeleteLoginFingerprints mocks base method.
+func (m *MockStorage) DeleteLoginFingerprints(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLoginFingerprints", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLoginFingerprints indicates an expected call of DeleteLoginFingerprints.
+func (mr *MockStorageMockRecorder) DeleteLoginFingerprints(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoginFingerprints", reflect.TypeOf((*MockStorage)(nil).DeleteLoginFingerprints), arg0, arg1, arg2)
+}
+
+// DeletePasswordResetCode mocks base method.
+func (m *MockStorage) DeletePasswordResetCode(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePasswordResetCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePasswordResetCode indicates an expected call of DeletePasswordResetCode.
+func (mr *MockStorageMockRecorder) DeletePasswordResetCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePasswordResetCode", reflect.TypeOf((*MockStorage)(nil).DeletePasswordResetCode), arg0, arg1)
+}
+
 // DeletePreferredDuoDevice mocks base method.
 func (m *MockStorage) DeletePreferredDuoDevice(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -152,6 +295,20 @@ func (mr *MockStorageMockRecorder) DeletePreferredDuoDevice(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).DeletePreferredDuoDevice), arg0, arg1)
 }
 
+// DeleteSession mocks base method.
+func (m *MockStorage) DeleteSession(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MockStorageMockRecorder) DeleteSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockStorage)(nil).DeleteSession), arg0, arg1)
+}
+
 // DeleteTOTPConfiguration mocks base method.
 func (m *MockStorage) DeleteTOTPConfiguration(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -166,6 +323,34 @@ func (mr *MockStorageMockRecorder) DeleteTOTPConfiguration(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTPConfiguration", reflect.TypeOf((*MockStorage)(nil).DeleteTOTPConfiguration), arg0, arg1)
 }
 
+// DeleteTOTPConfigurationByID mocks base method.
+func (m *MockStorage) DeleteTOTPConfigurationByID(arg0 context.Context, arg1 string, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTOTPConfigurationByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTOTPConfigurationByID indicates an expected call of DeleteTOTPConfigurationByID.
+func (mr *MockStorageMockRecorder) DeleteTOTPConfigurationByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTPConfigurationByID", reflect.TypeOf((*MockStorage)(nil).DeleteTOTPConfigurationByID), arg0, arg1, arg2)
+}
+
+// DeleteUserRegistration mocks base method.
+func (m *MockStorage) DeleteUserRegistration(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserRegistration", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserRegistration indicates an expected call of DeleteUserRegistration.
+func (mr *MockStorageMockRecorder) DeleteUserRegistration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserRegistration", reflect.TypeOf((*MockStorage)(nil).DeleteUserRegistration), arg0, arg1)
+}
+
 // FindIdentityVerification mocks base method.
 func (m *MockStorage) FindIdentityVerification(arg0 context.Context, arg1 string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -181,6 +366,20 @@ func (mr *MockStorageMockRecorder) FindIdentityVerification(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentityVerification", reflect.TypeOf((*MockStorage)(nil).FindIdentityVerification), arg0, arg1)
 }
 
+// IncrementRegulationUnlockAttempts mocks base method.
+func (m *MockStorage) IncrementRegulationUnlockAttempts(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementRegulationUnlockAttempts", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementRegulationUnlockAttempts indicates an expected call of IncrementRegulationUnlockAttempts.
+func (mr *MockStorageMockRecorder) IncrementRegulationUnlockAttempts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementRegulationUnlockAttempts", reflect.TypeOf((*MockStorage)(nil).IncrementRegulationUnlockAttempts), arg0, arg1)
+}
+
 // LoadAuthenticationLogs mocks base method.
 func (m *MockStorage) LoadAuthenticationLogs(arg0 context.Context, arg1 string, arg2 time.Time, arg3, arg4 int) ([]model.AuthenticationAttempt, error) {
 	m.ctrl.T.Helper()
@@ -196,6 +395,36 @@ func (mr *MockStorageMockRecorder) LoadAuthenticationLogs(arg0, arg1, arg2, arg3
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAuthenticationLogs", reflect.TypeOf((*MockStorage)(nil).LoadAuthenticationLogs), arg0, arg1, arg2, arg3, arg4)
 }
 
+// LoadEmailVerification mocks base method.
+func (m *MockStorage) LoadEmailVerification(arg0 context.Context, arg1 string) (*model.EmailVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadEmailVerification", arg0, arg1)
+	ret0, _ := ret[0].(*model.EmailVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadEmailVerification indicates an expected call of LoadEmailVerification.
+func (mr *MockStorageMockRecorder) LoadEmailVerification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadEmailVerification", reflect.TypeOf((*MockStorage)(nil).LoadEmailVerification), arg0, arg1)
+}
+
+// LoadLoginFingerprints mocks base method.
+func (m *MockStorage) LoadLoginFingerprints(arg0 context.Context, arg1 string) ([]model.LoginFingerprint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadLoginFingerprints", arg0, arg1)
+	ret0, _ := ret[0].([]model.LoginFingerprint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadLoginFingerprints indicates an expected call of LoadLoginFingerprints.
+func (mr *MockStorageMockRecorder) LoadLoginFingerprints(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadLoginFingerprints", reflect.TypeOf((*MockStorage)(nil).LoadLoginFingerprints), arg0, arg1)
+}
+
 // LoadOAuth2BlacklistedJTI mocks base method.
 func (m *MockStorage) LoadOAuth2BlacklistedJTI(arg0 context.Context, arg1 string) (*model.OAuth2BlacklistedJTI, error) {
 	m.ctrl.T.Helper()
@@ -226,6 +455,21 @@ func (mr *MockStorageMockRecorder) LoadOAuth2ConsentSessionByChallengeID(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2ConsentSessionByChallengeID", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2ConsentSessionByChallengeID), arg0, arg1)
 }
 
+// LoadOAuth2ConsentSessionsActive mocks base method.
+func (m *MockStorage) LoadOAuth2ConsentSessionsActive(arg0 context.Context, arg1 string) ([]model.OAuth2ConsentSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2ConsentSessionsActive", arg0, arg1)
+	ret0, _ := ret[0].([]model.OAuth2ConsentSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2ConsentSessionsActive indicates an expected call of LoadOAuth2ConsentSessionsActive.
+func (mr *MockStorageMockRecorder) LoadOAuth2ConsentSessionsActive(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2ConsentSessionsActive", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2ConsentSessionsActive), arg0, arg1)
+}
+
 // LoadOAuth2ConsentSessionsPreConfigured mocks base method.
 func (m *MockStorage) LoadOAuth2ConsentSessionsPreConfigured(arg0 context.Context, arg1 string, arg2 uuid.UUID) (*storage.ConsentSessionRows, error) {
 	m.ctrl.T.Helper()
@@ -241,6 +485,21 @@ func (mr *MockStorageMockRecorder) LoadOAuth2ConsentSessionsPreConfigured(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2ConsentSessionsPreConfigured", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2ConsentSessionsPreConfigured), arg0, arg1, arg2)
 }
 
+// LoadOAuth2RefreshTokensActive mocks base method.
+func (m *MockStorage) LoadOAuth2RefreshTokensActive(arg0 context.Context, arg1 string) ([]model.OAuth2RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2RefreshTokensActive", arg0, arg1)
+	ret0, _ := ret[0].([]model.OAuth2RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2RefreshTokensActive indicates an expected call of LoadOAuth2RefreshTokensActive.
+func (mr *MockStorageMockRecorder) LoadOAuth2RefreshTokensActive(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2RefreshTokensActive", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2RefreshTokensActive), arg0, arg1)
+}
+
 // LoadOAuth2Session mocks base method.
 func (m *MockStorage) LoadOAuth2Session(arg0 context.Context, arg1 storage.OAuth2SessionType, arg2 string) (*model.OAuth2Session, error) {
 	m.ctrl.T.Helper()
@@ -256,6 +515,21 @@ func (mr *MockStorageMockRecorder) LoadOAuth2Session(arg0, arg1, arg2 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2Session", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2Session), arg0, arg1, arg2)
 }
 
+// LoadPasswordResetCode mocks base method.
+func (m *MockStorage) LoadPasswordResetCode(arg0 context.Context, arg1 string) (*model.PasswordResetCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadPasswordResetCode", arg0, arg1)
+	ret0, _ := ret[0].(*model.PasswordResetCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadPasswordResetCode indicates an expected call of LoadPasswordResetCode.
+func (mr *MockStorageMockRecorder) LoadPasswordResetCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPasswordResetCode", reflect.TypeOf((*MockStorage)(nil).LoadPasswordResetCode), arg0, arg1)
+}
+
 // LoadPreferred2FAMethod mocks base method.
 func (m *MockStorage) LoadPreferred2FAMethod(arg0 context.Context, arg1 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -286,19 +560,34 @@ func (mr *MockStorageMockRecorder) LoadPreferredDuoDevice(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).LoadPreferredDuoDevice), arg0, arg1)
 }
 
-// LoadTOTPConfiguration mocks base method.
-func (m *MockStorage) LoadTOTPConfiguration(arg0 context.Context, arg1 string) (*model.TOTPConfiguration, error) {
+// LoadRegulationUnlock mocks base method.
+func (m *MockStorage) LoadRegulationUnlock(arg0 context.Context, arg1 string) (*model.RegulationUnlock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadRegulationUnlock", arg0, arg1)
+	ret0, _ := ret[0].(*model.RegulationUnlock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadRegulationUnlock indicates an expected call of LoadRegulationUnlock.
+func (mr *MockStorageMockRecorder) LoadRegulationUnlock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadRegulationUnlock", reflect.TypeOf((*MockStorage)(nil).LoadRegulationUnlock), arg0, arg1)
+}
+
+// LoadSession mocks base method.
+func (m *MockStorage) LoadSession(arg0 context.Context, arg1 string, arg2 time.Time) (*model.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoadTOTPConfiguration", arg0, arg1)
-	ret0, _ := ret[0].(*model.TOTPConfiguration)
+	ret := m.ctrl.Call(m, "LoadSession", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// LoadTOTPConfiguration indicates an expected call of LoadTOTPConfiguration.
-func (mr *MockStorageMockRecorder) LoadTOTPConfiguration(arg0, arg1 interface{}) *gomock.Call {
+// LoadSession indicates an expected call of LoadSession.
+func (mr *MockStorageMockRecorder) LoadSession(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTOTPConfiguration", reflect.TypeOf((*MockStorage)(nil).LoadTOTPConfiguration), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadSession", reflect.TypeOf((*MockStorage)(nil).LoadSession), arg0, arg1, arg2)
 }
 
 // LoadTOTPConfigurations mocks base method.
@@ -316,6 +605,51 @@ func (mr *MockStorageMockRecorder) LoadTOTPConfigurations(arg0, arg1, arg2 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTOTPConfigurations", reflect.TypeOf((*MockStorage)(nil).LoadTOTPConfigurations), arg0, arg1, arg2)
 }
 
+// LoadTOTPConfigurationsByUsername mocks base method.
+func (m *MockStorage) LoadTOTPConfigurationsByUsername(arg0 context.Context, arg1 string) ([]model.TOTPConfiguration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadTOTPConfigurationsByUsername", arg0, arg1)
+	ret0, _ := ret[0].([]model.TOTPConfiguration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadTOTPConfigurationsByUsername indicates an expected call of LoadTOTPConfigurationsByUsername.
+func (mr *MockStorageMockRecorder) LoadTOTPConfigurationsByUsername(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTOTPConfigurationsByUsername", reflect.TypeOf((*MockStorage)(nil).LoadTOTPConfigurationsByUsername), arg0, arg1)
+}
+
+// LoadUserAuthenticationLogs mocks base method.
+func (m *MockStorage) LoadUserAuthenticationLogs(arg0 context.Context, arg1 string, arg2, arg3 int) ([]model.AuthenticationAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserAuthenticationLogs", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.AuthenticationAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserAuthenticationLogs indicates an expected call of LoadUserAuthenticationLogs.
+func (mr *MockStorageMockRecorder) LoadUserAuthenticationLogs(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserAuthenticationLogs", reflect.TypeOf((*MockStorage)(nil).LoadUserAuthenticationLogs), arg0, arg1, arg2, arg3)
+}
+
+// LoadUserFirstLogin mocks base method.
+func (m *MockStorage) LoadUserFirstLogin(arg0 context.Context, arg1 string) (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserFirstLogin", arg0, arg1)
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserFirstLogin indicates an expected call of LoadUserFirstLogin.
+func (mr *MockStorageMockRecorder) LoadUserFirstLogin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserFirstLogin", reflect.TypeOf((*MockStorage)(nil).LoadUserFirstLogin), arg0, arg1)
+}
+
 // LoadUserInfo mocks base method.
 func (m *MockStorage) LoadUserInfo(arg0 context.Context, arg1 string) (model.UserInfo, error) {
 	m.ctrl.T.Helper()
@@ -331,6 +665,21 @@ func (mr *MockStorageMockRecorder) LoadUserInfo(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserInfo", reflect.TypeOf((*MockStorage)(nil).LoadUserInfo), arg0, arg1)
 }
 
+// LoadUserLoginLocation mocks base method.
+func (m *MockStorage) LoadUserLoginLocation(arg0 context.Context, arg1 string) (*model.UserLoginLocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserLoginLocation", arg0, arg1)
+	ret0, _ := ret[0].(*model.UserLoginLocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserLoginLocation indicates an expected call of LoadUserLoginLocation.
+func (mr *MockStorageMockRecorder) LoadUserLoginLocation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserLoginLocation", reflect.TypeOf((*MockStorage)(nil).LoadUserLoginLocation), arg0, arg1)
+}
+
 // LoadUserOpaqueIdentifier mocks base method.
 func (m *MockStorage) LoadUserOpaqueIdentifier(arg0 context.Context, arg1 uuid.UUID) (*model.UserOpaqueIdentifier, error) {
 	m.ctrl.T.Helper()
@@ -376,6 +725,51 @@ func (mr *MockStorageMockRecorder) LoadUserOpaqueIdentifiers(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserOpaqueIdentifiers", reflect.TypeOf((*MockStorage)(nil).LoadUserOpaqueIdentifiers), arg0)
 }
 
+// LoadUserPasswordChange mocks base method.
+func (m *MockStorage) LoadUserPasswordChange(arg0 context.Context, arg1 string) (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserPasswordChange", arg0, arg1)
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserPasswordChange indicates an expected call of LoadUserPasswordChange.
+func (mr *MockStorageMockRecorder) LoadUserPasswordChange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserPasswordChange", reflect.TypeOf((*MockStorage)(nil).LoadUserPasswordChange), arg0, arg1)
+}
+
+// LoadUserRegistration mocks base method.
+func (m *MockStorage) LoadUserRegistration(arg0 context.Context, arg1 string) (*model.UserRegistration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserRegistration", arg0, arg1)
+	ret0, _ := ret[0].(*model.UserRegistration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserRegistration indicates an expected call of LoadUserRegistration.
+func (mr *MockStorageMockRecorder) LoadUserRegistration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserRegistration", reflect.TypeOf((*MockStorage)(nil).LoadUserRegistration), arg0, arg1)
+}
+
+// LoadUserRegistrations mocks base method.
+func (m *MockStorage) LoadUserRegistrations(arg0 context.Context) ([]model.UserRegistration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserRegistrations", arg0)
+	ret0, _ := ret[0].([]model.UserRegistration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserRegistrations indicates an expected call of LoadUserRegistrations.
+func (mr *MockStorageMockRecorder) LoadUserRegistrations(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserRegistrations", reflect.TypeOf((*MockStorage)(nil).LoadUserRegistrations), arg0)
+}
+
 // LoadWebauthnDevices mocks base method.
 func (m *MockStorage) LoadWebauthnDevices(arg0 context.Context, arg1, arg2 int) ([]model.WebauthnDevice, error) {
 	m.ctrl.T.Helper()
@@ -448,6 +842,20 @@ func (mr *MockStorageMockRecorder) Rollback(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockStorage)(nil).Rollback), arg0)
 }
 
+// SaveEmailVerification mocks base method.
+func (m *MockStorage) SaveEmailVerification(arg0 context.Context, arg1 model.EmailVerification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveEmailVerification", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveEmailVerification indicates an expected call of SaveEmailVerification.
+func (mr *MockStorageMockRecorder) SaveEmailVerification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveEmailVerification", reflect.TypeOf((*MockStorage)(nil).SaveEmailVerification), arg0, arg1)
+}
+
 // SaveIdentityVerification mocks base method.
 func (m *MockStorage) SaveIdentityVerification(arg0 context.Context, arg1 model.IdentityVerification) error {
 	m.ctrl.T.Helper()
@@ -462,6 +870,20 @@ func (mr *MockStorageMockRecorder) SaveIdentityVerification(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdentityVerification", reflect.TypeOf((*MockStorage)(nil).SaveIdentityVerification), arg0, arg1)
 }
 
+// SaveLoginFingerprint mocks base method.
+func (m *MockStorage) SaveLoginFingerprint(arg0 context.Context, arg1 model.LoginFingerprint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLoginFingerprint", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveLoginFingerprint indicates an expected call of SaveLoginFingerprint.
+func (mr *MockStorageMockRecorder) SaveLoginFingerprint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLoginFingerprint", reflect.TypeOf((*MockStorage)(nil).SaveLoginFingerprint), arg0, arg1)
+}
+
 // SaveOAuth2BlacklistedJTI mocks base method.
 func (m *MockStorage) SaveOAuth2BlacklistedJTI(arg0 context.Context, arg1 model.OAuth2BlacklistedJTI) error {
 	m.ctrl.T.Helper()
@@ -532,6 +954,20 @@ func (mr *MockStorageMockRecorder) SaveOAuth2Session(arg0, arg1, arg2 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2Session", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2Session), arg0, arg1, arg2)
 }
 
+// SavePasswordResetCode mocks base method.
+func (m *MockStorage) SavePasswordResetCode(arg0 context.Context, arg1 model.PasswordResetCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePasswordResetCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePasswordResetCode indicates an expected call of SavePasswordResetCode.
+func (mr *MockStorageMockRecorder) SavePasswordResetCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePasswordResetCode", reflect.TypeOf((*MockStorage)(nil).SavePasswordResetCode), arg0, arg1)
+}
+
 // SavePreferred2FAMethod mocks base method.
 func (m *MockStorage) SavePreferred2FAMethod(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -560,6 +996,34 @@ func (mr *MockStorageMockRecorder) SavePreferredDuoDevice(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).SavePreferredDuoDevice), arg0, arg1)
 }
 
+// SaveRegulationUnlock mocks base method.
+func (m *MockStorage) SaveRegulationUnlock(arg0 context.Context, arg1 model.RegulationUnlock) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRegulationUnlock", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRegulationUnlock indicates an expected call of SaveRegulationUnlock.
+func (mr *MockStorageMockRecorder) SaveRegulationUnlock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRegulationUnlock", reflect.TypeOf((*MockStorage)(nil).SaveRegulationUnlock), arg0, arg1)
+}
+
+// SaveSession mocks base method.
+func (m *MockStorage) SaveSession(arg0 context.Context, arg1 model.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSession indicates an expected call of SaveSession.
+func (mr *MockStorageMockRecorder) SaveSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSession", reflect.TypeOf((*MockStorage)(nil).SaveSession), arg0, arg1)
+}
+
 // SaveTOTPConfiguration mocks base method.
 func (m *MockStorage) SaveTOTPConfiguration(arg0 context.Context, arg1 model.TOTPConfiguration) error {
 	m.ctrl.T.Helper()
@@ -574,6 +1038,34 @@ func (mr *MockStorageMockRecorder) SaveTOTPConfiguration(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTOTPConfiguration", reflect.TypeOf((*MockStorage)(nil).SaveTOTPConfiguration), arg0, arg1)
 }
 
+// SaveUserFirstLogin mocks base method.
+func (m *MockStorage) SaveUserFirstLogin(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserFirstLogin", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserFirstLogin indicates an expected call of SaveUserFirstLogin.
+func (mr *MockStorageMockRecorder) SaveUserFirstLogin(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserFirstLogin", reflect.TypeOf((*MockStorage)(nil).SaveUserFirstLogin), arg0, arg1, arg2)
+}
+
+// SaveUserLoginLocation mocks base method.
+func (m *MockStorage) SaveUserLoginLocation(arg0 context.Context, arg1 model.UserLoginLocation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserLoginLocation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserLoginLocation indicates an expected call of SaveUserLoginLocation.
+func (mr *MockStorageMockRecorder) SaveUserLoginLocation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserLoginLocation", reflect.TypeOf((*MockStorage)(nil).SaveUserLoginLocation), arg0, arg1)
+}
+
 // SaveUserOpaqueIdentifier mocks base method.
 func (m *MockStorage) SaveUserOpaqueIdentifier(arg0 context.Context, arg1 model.UserOpaqueIdentifier) error {
 	m.ctrl.T.Helper()
@@ -588,6 +1080,34 @@ func (mr *MockStorageMockRecorder) SaveUserOpaqueIdentifier(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserOpaqueIdentifier", reflect.TypeOf((*MockStorage)(nil).SaveUserOpaqueIdentifier), arg0, arg1)
 }
 
+// SaveUserPasswordChange mocks base method.
+func (m *MockStorage) SaveUserPasswordChange(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserPasswordChange", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserPasswordChange indicates an expected call of SaveUserPasswordChange.
+func (mr *MockStorageMockRecorder) SaveUserPasswordChange(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserPasswordChange", reflect.TypeOf((*MockStorage)(nil).SaveUserPasswordChange), arg0, arg1, arg2)
+}
+
+// SaveUserRegistration mocks base method.
+func (m *MockStorage) SaveUserRegistration(arg0 context.Context, arg1 model.UserRegistration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserRegistration", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserRegistration indicates an expected call of SaveUserRegistration.
+func (mr *MockStorageMockRecorder) SaveUserRegistration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserRegistration", reflect.TypeOf((*MockStorage)(nil).SaveUserRegistration), arg0, arg1)
+}
+
 // SaveWebauthnDevice mocks base method.
 func (m *MockStorage) SaveWebauthnDevice(arg0 context.Context, arg1 model.WebauthnDevice) error {
 	m.ctrl.T.Helper()
@@ -603,17 +1123,17 @@ func (mr *MockStorageMockRecorder) SaveWebauthnDevice(arg0, arg1 interface{}) *g
 }
 
 // SchemaEncryptionChangeKey mocks base method.
-func (m *MockStorage) SchemaEncryptionChangeKey(arg0 context.Context, arg1 string) error {
+func (m *MockStorage) SchemaEncryptionChangeKey(arg0 context.Context, arg1 string, arg2 bool, arg3 func(storage.EncryptionChangeKeyResult)) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SchemaEncryptionChangeKey", arg0, arg1)
+	ret := m.ctrl.Call(m, "SchemaEncryptionChangeKey", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SchemaEncryptionChangeKey indicates an expected call of SchemaEncryptionChangeKey.
-func (mr *MockStorageMockRecorder) SchemaEncryptionChangeKey(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) SchemaEncryptionChangeKey(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaEncryptionChangeKey", reflect.TypeOf((*MockStorage)(nil).SchemaEncryptionChangeKey), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaEncryptionChangeKey", reflect.TypeOf((*MockStorage)(nil).SchemaEncryptionChangeKey), arg0, arg1, arg2, arg3)
 }
 
 // SchemaEncryptionCheckKey mocks base method.
@@ -748,18 +1268,74 @@ func (mr *MockStorageMockRecorder) StartupCheck() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartupCheck", reflect.TypeOf((*MockStorage)(nil).StartupCheck))
 }
 
+// SuppressLoginFingerprint mocks base method.
+func (m *MockStorage) SuppressLoginFingerprint(arg0 context.Context, arg1 string, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuppressLoginFingerprint", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SuppressLoginFingerprint indicates an expected call of SuppressLoginFingerprint.
+func (mr *MockStorageMockRecorder) SuppressLoginFingerprint(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuppressLoginFingerprint", reflect.TypeOf((*MockStorage)(nil).SuppressLoginFingerprint), arg0, arg1, arg2)
+}
+
+// UpdateLoginFingerprintSeen mocks base method.
+func (m *MockStorage) UpdateLoginFingerprintSeen(arg0 context.Context, arg1 int, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLoginFingerprintSeen", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLoginFingerprintSeen indicates an expected call of UpdateLoginFingerprintSeen.
+func (mr *MockStorageMockRecorder) UpdateLoginFingerprintSeen(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoginFingerprintSeen", reflect.TypeOf((*MockStorage)(nil).UpdateLoginFingerprintSeen), arg0, arg1, arg2)
+}
+
+// UpdateOAuth2RefreshTokenSessionLastUsed mocks base method.
+func (m *MockStorage) UpdateOAuth2RefreshTokenSessionLastUsed(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2RefreshTokenSessionLastUsed", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOAuth2RefreshTokenSessionLastUsed indicates an expected call of UpdateOAuth2RefreshTokenSessionLastUsed.
+func (mr *MockStorageMockRecorder) UpdateOAuth2RefreshTokenSessionLastUsed(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2RefreshTokenSessionLastUsed", reflect.TypeOf((*MockStorage)(nil).UpdateOAuth2RefreshTokenSessionLastUsed), arg0, arg1, arg2)
+}
+
+// UpdateSessionID mocks base method.
+func (m *MockStorage) UpdateSessionID(arg0 context.Context, arg1, arg2 string, arg3 *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSessionID", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSessionID indicates an expected call of UpdateSessionID.
+func (mr *MockStorageMockRecorder) UpdateSessionID(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSessionID", reflect.TypeOf((*MockStorage)(nil).UpdateSessionID), arg0, arg1, arg2, arg3)
+}
+
 // UpdateTOTPConfigurationSignIn mocks base method.
-func (m *MockStorage) UpdateTOTPConfigurationSignIn(arg0 context.Context, arg1 int, arg2 *time.Time) error {
+func (m *MockStorage) UpdateTOTPConfigurationSignIn(arg0 context.Context, arg1 int, arg2 *time.Time, arg3 uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTOTPConfigurationSignIn", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "UpdateTOTPConfigurationSignIn", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateTOTPConfigurationSignIn indicates an expected call of UpdateTOTPConfigurationSignIn.
-func (mr *MockStorageMockRecorder) UpdateTOTPConfigurationSignIn(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) UpdateTOTPConfigurationSignIn(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTOTPConfigurationSignIn", reflect.TypeOf((*MockStorage)(nil).UpdateTOTPConfigurationSignIn), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTOTPConfigurationSignIn", reflect.TypeOf((*MockStorage)(nil).UpdateTOTPConfigurationSignIn), arg0, arg1, arg2, arg3)
 }
 
 // UpdateWebauthnDeviceSignIn mocks base method.
@@ -15,4 +15,5 @@ type AuthenticationAttempt struct {
 	RemoteIP      NullIP    `db:"remote_ip"`
 	RequestURI    string    `db:"request_uri"`
 	RequestMethod string    `db:"request_method"`
+	UserAgent     string    `db:"user_agent"`
 }
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+)
+
+// EmailVerification represents the verification of the email address of a self-registered user. Only the SHA256 hash
+// of the token sent to the email address is stored.
+type EmailVerification struct {
+	ID         int        `db:"id"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	VerifiedAt *time.Time `db:"verified_at"`
+	Username   string     `db:"username"`
+	Email      string     `db:"email"`
+	TokenHash  string     `db:"token_hash"`
+}
+
+// NewEmailVerification creates a new unverified EmailVerification for the user which stores the hash of the provided
+// token.
+func NewEmailVerification(username, email, token string, now time.Time, lifespan time.Duration) EmailVerification {
+	return EmailVerification{
+		CreatedAt: now,
+		ExpiresAt: now.Add(lifespan),
+		Username:  username,
+		Email:     email,
+		TokenHash: hashToken(token),
+	}
+}
+
+// Matches returns true if the provided token matches the stored hash, the token has not expired, and the token has not
+// already been used to verify the email address.
+func (v EmailVerification) Matches(token string, now time.Time) bool {
+	if v.VerifiedAt != nil || !now.Before(v.ExpiresAt) {
+		return false
+	}
+
+	return matchesTokenHash(token, v.TokenHash)
+}
+
+// Unverified returns true if the provided email address is the one this verification was issued for and it has not
+// been verified.
+func (v EmailVerification) Unverified(email string) bool {
+	return v.VerifiedAt == nil && v.Email == email
+}
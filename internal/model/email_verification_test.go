@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldMatchEmailVerification(t *testing.T) {
+	now := time.Unix(1640000000, 0)
+
+	verification := NewEmailVerification("john", "john@example.com", "ABCDEFGH", now, time.Hour)
+
+	assert.Equal(t, "john", verification.Username)
+	assert.Equal(t, "john@example.com", verification.Email)
+	assert.Equal(t, now.Add(time.Hour), verification.ExpiresAt)
+	assert.Equal(t, "9ac2197d9258257b1ae8463e4214e4cd0a578bc1517f2415928b91be4283fc48", verification.TokenHash)
+
+	assert.True(t, verification.Matches("ABCDEFGH", now))
+	assert.True(t, verification.Matches("ABCDEFGH", now.Add(time.Minute*59)))
+	assert.False(t, verification.Matches("ABCDEFGI", now))
+	assert.False(t, verification.Matches("ABCDEFGH", now.Add(time.Hour)))
+
+	assert.True(t, verification.Unverified("john@example.com"))
+	assert.False(t, verification.Unverified("harry@example.com"))
+
+	verification.VerifiedAt = &now
+
+	assert.False(t, verification.Matches("ABCDEFGH", now))
+	assert.False(t, verification.Unverified("john@example.com"))
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"strings"
+	"time"
+)
+
+// LoginFingerprint represents the context a user has logged in from. The device is identified by the SHA256 hash of
+// the user agent and the location by the network of the remote IP and optionally the country.
+type LoginFingerprint struct {
+	ID                int        `db:"id"`
+	CreatedAt         time.Time  `db:"created_at"`
+	LastSeenAt        time.Time  `db:"last_seen_at"`
+	NotifiedAt        *time.Time `db:"notified_at"`
+	Username          string     `db:"username"`
+	Device            string     `db:"device"`
+	Network           string     `db:"network"`
+	Country           string     `db:"country"`
+	SuppressTokenHash string     `db:"suppress_token_hash"`
+	Suppressed        bool       `db:"suppressed"`
+}
+
+// NewLoginFingerprint creates a new LoginFingerprint for a login of the user. IPv4 addresses are reduced to their /24
+// network and IPv6 addresses to their /64 network so that minor changes of the address aren't considered a new location.
+func NewLoginFingerprint(username, userAgent string, ip net.IP, country string, now time.Time) LoginFingerprint {
+	device := sha256.Sum256([]byte(userAgent))
+
+	return LoginFingerprint{
+		CreatedAt:  now,
+		LastSeenAt: now,
+		Username:   username,
+		Device:     hex.EncodeToString(device[:]),
+		Network:    loginFingerprintNetwork(ip),
+		Country:    strings.ToUpper(country),
+	}
+}
+
+// Matches returns true if the other LoginFingerprint is from the same device and location.
+func (f LoginFingerprint) Matches(other LoginFingerprint) bool {
+	return f.Device == other.Device && f.Network == other.Network && f.Country == other.Country
+}
+
+// Recognizes returns true if the other LoginFingerprint is from the same device and location, or if it's from the same
+// device and the user has suppressed notifications for this device.
+func (f LoginFingerprint) Recognizes(other LoginFingerprint) bool {
+	return f.Matches(other) || (f.Suppressed && f.Device == other.Device)
+}
+
+// SetSuppressToken stores the hash of the token which allows the user to suppress notifications for this device.
+func (f *LoginFingerprint) SetSuppressToken(token string) {
+	f.SuppressTokenHash = hashToken(token)
+}
+
+// MatchesSuppressToken returns true if the provided token matches the stored hash.
+func (f LoginFingerprint) MatchesSuppressToken(token string) bool {
+	if f.SuppressTokenHash == "" {
+		return false
+	}
+
+	return matchesTokenHash(token, f.SuppressTokenHash)
+}
+
+func loginFingerprintNetwork(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return (&net.IPNet{IP: ip4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldCreateLoginFingerprint(t *testing.T) {
+	now := time.Unix(1640000000, 0)
+
+	fingerprint := NewLoginFingerprint("john", "Mozilla/5.0", net.ParseIP("192.168.1.20"), "nz", now)
+
+	assert.Equal(t, "john", fingerprint.Username)
+	assert.Equal(t, now, fingerprint.CreatedAt)
+	assert.Equal(t, now, fingerprint.LastSeenAt)
+	assert.Nil(t, fingerprint.NotifiedAt)
+	assert.Len(t, fingerprint.Device, 64)
+	assert.Equal(t, "192.168.1.0/24", fingerprint.Network)
+	assert.Equal(t, "NZ", fingerprint.Country)
+
+	fingerprint = NewLoginFingerprint("john", "Mozilla/5.0", net.ParseIP("2001:db8:1:2:3:4:5:6"), "", now)
+
+	assert.Equal(t, "2001:db8:1:2::/64", fingerprint.Network)
+	assert.Equal(t, "", fingerprint.Country)
+
+	fingerprint = NewLoginFingerprint("john", "Mozilla/5.0", nil, "", now)
+
+	assert.Equal(t, "", fingerprint.Network)
+}
+
+func TestShouldMatchLoginFingerprint(t *testing.T) {
+	now := time.Unix(1640000000, 0)
+
+	known := NewLoginFingerprint("john", "Mozilla/5.0", net.ParseIP("192.168.1.20"), "", now)
+
+	assert.True(t, known.Matches(NewLoginFingerprint("john", "Mozilla/5.0", net.ParseIP("192.168.1.200"), "", now)))
+	assert.False(t, known.Matches(NewLoginFingerprint("john", "Mozilla/5.0", net.ParseIP("192.168.2.20"), "", now)))
+	assert.False(t, known.Matches(NewLoginFingerprint("john", "curl/7.0", net.ParseIP("192.168.1.20"), "", now)))
+	assert.False(t, known.Matches(NewLoginFingerprint("john", "Mozilla/5.0", net.ParseIP("192.168.1.20"), "NZ", now)))
+
+	other := NewLoginFingerprint("john", "Mozilla/5.0", net.ParseIP("10.0.0.1"), "", now)
+
+	assert.False(t, known.Recognizes(other))
+
+	known.Suppressed = true
+
+	assert.True(t, known.Recognizes(other))
+	assert.False(t, known.Recognizes(NewLoginFingerprint("john", "curl/7.0", net.ParseIP("10.0.0.1"), "", now)))
+}
+
+func TestShouldMatchLoginFingerprintSuppressToken(t *testing.T) {
+	fingerprint := LoginFingerprint{}
+
+	assert.False(t, fingerprint.MatchesSuppressToken(""))
+
+	fingerprint.SetSuppressToken("ABCDEFGH")
+
+	assert.Equal(t, "9ac2197d9258257b1ae8463e4214e4cd0a578bc1517f2415928b91be4283fc48", fingerprint.SuppressTokenHash)
+	assert.True(t, fingerprint.MatchesSuppressToken("ABCDEFGH"))
+	assert.False(t, fingerprint.MatchesSuppressToken("ABCDEFGI"))
+}
@@ -226,3 +226,15 @@ func (s OAuth2Session) ToRequest(ctx context.Context, session fosite.Session, st
 		Session:           session,
 	}, nil
 }
+
+// OAuth2RefreshToken represents the tracked metadata of a OAuth2.0 refresh token session. The RequestedAt time is the
+// time the refresh token was originally issued as refresh tokens are rotated each time they're used.
+type OAuth2RefreshToken struct {
+	ID            int                      `db:"id"`
+	RequestID     string                   `db:"request_id"`
+	ClientID      string                   `db:"client_id"`
+	GrantedScopes StringSlicePipeDelimited `db:"granted_scopes"`
+	RequestedAt   time.Time                `db:"requested_at"`
+	LastUsedAt    *time.Time               `db:"last_used_at"`
+	Active        bool                     `db:"active"`
+}
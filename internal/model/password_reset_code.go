@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+)
+
+// PasswordResetCode represents a one-time password reset code issued to a user by an administrator. Only the SHA256
+// hash of the code is stored.
+type PasswordResetCode struct {
+	ID        int       `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+	Username  string    `db:"username"`
+	CodeHash  string    `db:"code_hash"`
+}
+
+// NewPasswordResetCode creates a new PasswordResetCode for the user which stores the hash of the provided code.
+func NewPasswordResetCode(username, code string, now time.Time, lifespan time.Duration) PasswordResetCode {
+	return PasswordResetCode{
+		CreatedAt: now,
+		ExpiresAt: now.Add(lifespan),
+		Username:  username,
+		CodeHash:  hashToken(code),
+	}
+}
+
+// Matches returns true if the provided code matches the stored hash and the code has not expired.
+func (c PasswordResetCode) Matches(code string, now time.Time) bool {
+	if !now.Before(c.ExpiresAt) {
+		return false
+	}
+
+	return matchesTokenHash(code, c.CodeHash)
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldMatchPasswordResetCode(t *testing.T) {
+	now := time.Unix(1640000000, 0)
+
+	code := NewPasswordResetCode("john", "ABCDEFGH", now, time.Hour)
+
+	assert.Equal(t, "john", code.Username)
+	assert.Equal(t, now.Add(time.Hour), code.ExpiresAt)
+	assert.Equal(t, "9ac2197d9258257b1ae8463e4214e4cd0a578bc1517f2415928b91be4283fc48", code.CodeHash)
+
+	assert.True(t, code.Matches("ABCDEFGH", now))
+	assert.True(t, code.Matches("ABCDEFGH", now.Add(time.Minute*59)))
+	assert.False(t, code.Matches("ABCDEFGI", now))
+	assert.False(t, code.Matches("ABCDEFGH", now.Add(time.Hour)))
+}
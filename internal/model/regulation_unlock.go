@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+)
+
+// RegulationUnlock represents a self-service unlock of a user banned by the regulator. It's issued when the user is
+// banned and expires when the ban does, at the latest. Only the SHA256 hash of the token sent to the user is stored.
+type RegulationUnlock struct {
+	ID         int        `db:"id"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	UnlockedAt *time.Time `db:"unlocked_at"`
+	Username   string     `db:"username"`
+	TokenHash  string     `db:"token_hash"`
+	Attempts   int        `db:"attempts"`
+}
+
+// NewRegulationUnlock creates a new RegulationUnlock for the user which stores the hash of the provided token. It
+// expires after the lifespan or when the ban expires, whichever is first.
+func NewRegulationUnlock(username, token string, now, bannedUntil time.Time, lifespan time.Duration) RegulationUnlock {
+	expiresAt := now.Add(lifespan)
+
+	if bannedUntil.Before(expiresAt) {
+		expiresAt = bannedUntil
+	}
+
+	return RegulationUnlock{
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+		Username:  username,
+		TokenHash: hashToken(token),
+	}
+}
+
+// Usable returns true if the unlock has not been used, has not expired, and has had less than the maximum number of
+// attempts.
+func (u RegulationUnlock) Usable(now time.Time, maxAttempts int) bool {
+	return u.UnlockedAt == nil && now.Before(u.ExpiresAt) && u.Attempts < maxAttempts
+}
+
+// Matches returns true if the provided token matches the stored hash.
+func (u RegulationUnlock) Matches(token string) bool {
+	return matchesTokenHash(token, u.TokenHash)
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldMatchRegulationUnlock(t *testing.T) {
+	now := time.Unix(1640000000, 0)
+
+	unlock := NewRegulationUnlock("john", "ABCDEFGH", now, now.Add(time.Hour), time.Minute*15)
+
+	assert.Equal(t, "john", unlock.Username)
+	assert.Equal(t, now.Add(time.Minute*15), unlock.ExpiresAt)
+	assert.Equal(t, "9ac2197d9258257b1ae8463e4214e4cd0a578bc1517f2415928b91be4283fc48", unlock.TokenHash)
+
+	assert.True(t, unlock.Matches("ABCDEFGH"))
+	assert.False(t, unlock.Matches("ABCDEFGI"))
+
+	assert.True(t, unlock.Usable(now, 3))
+	assert.False(t, unlock.Usable(now.Add(time.Minute*15), 3))
+
+	unlock.Attempts = 3
+
+	assert.False(t, unlock.Usable(now, 3))
+
+	unlock.Attempts = 0
+	unlock.UnlockedAt = &now
+
+	assert.False(t, unlock.Usable(now, 3))
+}
+
+func TestShouldExpireRegulationUnlockWithBan(t *testing.T) {
+	now := time.Unix(1640000000, 0)
+
+	unlock := NewRegulationUnlock("john", "ABCDEFGH", now, now.Add(time.Minute*5), time.Minute*15)
+
+	assert.Equal(t, now.Add(time.Minute*5), unlock.ExpiresAt)
+	assert.True(t, unlock.Usable(now.Add(time.Minute*4), 3))
+	assert.False(t, unlock.Usable(now.Add(time.Minute*5), 3))
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// Session represents the data of a user session persisted by the storage session provider. The ID is the SHA256 hash
+// of the session ID so the sessions can't be hijacked using the contents of the database.
+type Session struct {
+	ID        string     `db:"id"`
+	ExpiresAt *time.Time `db:"expires_at"`
+	Data      []byte     `db:"data"`
+}
+
+// IsExpired returns true if the session has an expiration which is not after the provided time.
+func (s Session) IsExpired(now time.Time) bool {
+	return s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// hashToken returns the hex encoded SHA256 hash of a token, which is stored in place of tokens sent to users.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// matchesTokenHash returns true if the hash of the token matches the stored hash using a constant time comparison.
+func matchesTokenHash(token, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(hash)) == 1
+}
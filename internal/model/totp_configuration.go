@@ -11,19 +11,27 @@ import (
 
 // TOTPConfiguration represents a users TOTP configuration row in the database.
 type TOTPConfiguration struct {
-	ID         int        `db:"id" json:"-"`
-	CreatedAt  time.Time  `db:"created_at" json:"-"`
-	LastUsedAt *time.Time `db:"last_used_at" json:"-"`
-	Username   string     `db:"username" json:"-"`
-	Issuer     string     `db:"issuer" json:"-"`
-	Algorithm  string     `db:"algorithm" json:"-"`
-	Digits     uint       `db:"digits" json:"digits"`
-	Period     uint       `db:"period" json:"period"`
-	Secret     []byte     `db:"secret" json:"-"`
+	ID           int        `db:"id" json:"-"`
+	CreatedAt    time.Time  `db:"created_at" json:"-"`
+	LastUsedAt   *time.Time `db:"last_used_at" json:"-"`
+	LastUsedStep *uint64    `db:"last_used_step" json:"-"`
+	Username     string     `db:"username" json:"-"`
+	Description  string     `db:"description" json:"-"`
+	Issuer       string     `db:"issuer" json:"-"`
+	Algorithm    string     `db:"algorithm" json:"-"`
+	Digits       uint       `db:"digits" json:"digits"`
+	Period       uint       `db:"period" json:"period"`
+	Secret       []byte     `db:"secret" json:"-"`
 }
 
 // URI shows the configuration in the URI representation.
 func (c TOTPConfiguration) URI() (uri string) {
+	return c.URIWithAccountName(c.Username)
+}
+
+// URIWithAccountName shows the configuration in the URI representation using the provided account name in the label
+// instead of the username.
+func (c TOTPConfiguration) URIWithAccountName(accountName string) (uri string) {
 	v := url.Values{}
 	v.Set("secret", string(c.Secret))
 	v.Set("issuer", c.Issuer)
@@ -34,16 +42,17 @@ func (c TOTPConfiguration) URI() (uri string) {
 	u := url.URL{
 		Scheme:   "otpauth",
 		Host:     "totp",
-		Path:     "/" + c.Issuer + ":" + c.Username,
+		Path:     "/" + c.Issuer + ":" + accountName,
 		RawQuery: v.Encode(),
 	}
 
 	return u.String()
 }
 
-// UpdateSignInInfo adjusts the values of the TOTPConfiguration after a sign in.
-func (c *TOTPConfiguration) UpdateSignInInfo(now time.Time) {
+// UpdateSignInInfo adjusts the values of the TOTPConfiguration after a sign in with the code of the step.
+func (c *TOTPConfiguration) UpdateSignInInfo(now time.Time, step uint64) {
 	c.LastUsedAt = &now
+	c.LastUsedStep = &step
 }
 
 // Key returns the *otp.Key using TOTPConfiguration.URI with otp.NewKeyFromURL.
@@ -61,3 +70,21 @@ func (c TOTPConfiguration) Image(width, height int) (img image.Image, err error)
 
 	return key.Image(width, height)
 }
+
+// TOTPConfigurationsImport represents a TOTP configurations import file.
+type TOTPConfigurationsImport struct {
+	Configurations []TOTPConfigurationImport `yaml:"totp_configurations"`
+}
+
+// TOTPConfigurationImport represents a TOTP configuration of a user imported from another system. The Code is a
+// current code of the configuration which proves the Secret and the parameters match the device of the user.
+type TOTPConfigurationImport struct {
+	Username    string `yaml:"username"`
+	Description string `yaml:"description"`
+	Issuer      string `yaml:"issuer"`
+	Algorithm   string `yaml:"algorithm"`
+	Digits      uint   `yaml:"digits"`
+	Period      uint   `yaml:"period"`
+	Secret      string `yaml:"secret"`
+	Code        string `yaml:"code"`
+}
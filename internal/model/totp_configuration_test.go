@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/pquerna/otp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -75,3 +76,23 @@ func TestShouldReturnImage(t *testing.T) {
 	assert.Equal(t, 41, img.Bounds().Dx())
 	assert.Equal(t, 41, img.Bounds().Dy())
 }
+
+func TestShouldReturnURIWithAccountName(t *testing.T) {
+	object := TOTPConfiguration{
+		Username:  "john",
+		Issuer:    "Authelia",
+		Algorithm: "SHA1",
+		Digits:    6,
+		Period:    30,
+		Secret:    []byte("ABC123"),
+	}
+
+	assert.Equal(t, "otpauth://totp/Authelia:john?algorithm=SHA1&digits=6&issuer=Authelia&period=30&secret=ABC123", object.URI())
+	assert.Equal(t, "otpauth://totp/Authelia:John%20Doe%20%28john@example.com%29?algorithm=SHA1&digits=6&issuer=Authelia&period=30&secret=ABC123", object.URIWithAccountName("John Doe (john@example.com)"))
+
+	key, err := otp.NewKeyFromURL(object.URIWithAccountName("john@example.com"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Authelia", key.Issuer())
+	assert.Equal(t, "john@example.com", key.AccountName())
+}
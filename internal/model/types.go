@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/base64"
@@ -154,6 +155,11 @@ type StartupCheck interface {
 	StartupCheck() (err error)
 }
 
+// HealthCheck represents a provider that can check the connectivity to its backend without any side effects.
+type HealthCheck interface {
+	HealthCheck(ctx context.Context) (err error)
+}
+
 // StringSlicePipeDelimited is a string slice that is stored in the database delimited by pipes.
 type StringSlicePipeDelimited []string
 
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+)
+
+// UserLoginLocation represents the most recent geographic location a user successfully logged in from.
+type UserLoginLocation struct {
+	ID        int       `db:"id"`
+	Time      time.Time `db:"time"`
+	Username  string    `db:"username"`
+	RemoteIP  NullIP    `db:"remote_ip"`
+	Latitude  float64   `db:"latitude"`
+	Longitude float64   `db:"longitude"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// UserRegistration represents a self-registered account which is pending approval by an administrator.
+type UserRegistration struct {
+	ID          int       `db:"id"`
+	RequestedAt time.Time `db:"requested_at"`
+	RemoteIP    NullIP    `db:"remote_ip"`
+	Username    string    `db:"username"`
+	DisplayName string    `db:"display_name"`
+	Email       string    `db:"email"`
+	Password    string    `db:"password"`
+}
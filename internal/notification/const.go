@@ -7,3 +7,19 @@ const (
 const (
 	rfc5322DateTimeLayout = "Mon, 2 Jan 2006 15:04:05 -0700"
 )
+
+// Event types which notifiers can be scoped to.
+const (
+	EventIdentityVerification = "identity_verification"
+	EventRegistration         = "registration"
+	EventPasswordChanged      = "password_changed"
+	EventAccountLocked        = "account_locked"
+	EventLogin                = "login"
+)
+
+// Events is the list of all event types which notifiers can be scoped to.
+var Events = []string{EventIdentityVerification, EventRegistration, EventPasswordChanged, EventAccountLocked, EventLogin}
+
+const (
+	webhookContentType = "application/json"
+)
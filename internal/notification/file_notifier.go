@@ -1,6 +1,7 @@
 package notification
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -21,6 +22,13 @@ func NewFileNotifier(configuration schema.FileSystemNotifierConfiguration) *File
 	}
 }
 
+// HealthCheck implements the health check provider interface by checking the directory of the file exists.
+func (n *FileNotifier) HealthCheck(_ context.Context) (err error) {
+	_, err = os.Stat(filepath.Dir(n.path))
+
+	return err
+}
+
 // StartupCheck implements the startup check provider interface.
 func (n *FileNotifier) StartupCheck() (err error) {
 	dir := filepath.Dir(n.path)
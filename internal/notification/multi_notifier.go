@@ -0,0 +1,106 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NamedNotifier is a Notifier which is identified by a name and optionally scoped to a list of event types.
+type NamedNotifier struct {
+	Name     string
+	Events   []string
+	Notifier Notifier
+}
+
+// MultiNotifier is a notifier which sends each notification to several notifiers in parallel. A failure of one of the
+// notifiers is logged and doesn't prevent the others from sending the notification.
+type MultiNotifier struct {
+	notifiers []NamedNotifier
+	log       *logrus.Logger
+}
+
+// NewMultiNotifier creates a MultiNotifier which sends notifications to the given notifiers.
+func NewMultiNotifier(notifiers ...NamedNotifier) *MultiNotifier {
+	return &MultiNotifier{
+		notifiers: notifiers,
+		log:       logging.Logger(),
+	}
+}
+
+// HealthCheck implements the health check provider interface by checking each notifier which supports it.
+func (n *MultiNotifier) HealthCheck(ctx context.Context) (err error) {
+	for _, notifier := range n.notifiers {
+		check, ok := notifier.Notifier.(model.HealthCheck)
+		if !ok {
+			continue
+		}
+
+		if err = check.HealthCheck(ctx); err != nil {
+			return fmt.Errorf("notifier '%s': %w", notifier.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// StartupCheck implements the startup check provider interface by checking each notifier.
+func (n *MultiNotifier) StartupCheck() (err error) {
+	for _, notifier := range n.notifiers {
+		if err = notifier.Notifier.StartupCheck(); err != nil {
+			return fmt.Errorf("notifier '%s': %w", notifier.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Send sends the notification to all notifiers which are not scoped to specific event types.
+func (n *MultiNotifier) Send(recipient, subject, body, htmlBody string) (err error) {
+	return n.SendEvent("", recipient, subject, body, htmlBody)
+}
+
+// SendEvent sends the notification to all notifiers which are not scoped to specific event types or which are scoped
+// to the given event type. An error is returned after all notifiers have completed if any of them failed.
+func (n *MultiNotifier) SendEvent(event, recipient, subject, body, htmlBody string) (err error) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []string
+	)
+
+	for _, notifier := range n.notifiers {
+		if len(notifier.Events) != 0 && !utils.IsStringInSlice(event, notifier.Events) {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(notifier NamedNotifier) {
+			defer wg.Done()
+
+			if err := SendEvent(notifier.Notifier, event, recipient, subject, body, htmlBody); err != nil {
+				n.log.WithError(err).Errorf("Notifier '%s' failed to send the notification '%s' to '%s'", notifier.Name, subject, recipient)
+
+				mu.Lock()
+				failed = append(failed, notifier.Name)
+				mu.Unlock()
+			}
+		}(notifier)
+	}
+
+	wg.Wait()
+
+	if len(failed) != 0 {
+		return fmt.Errorf("the following notifiers failed to send the notification: %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
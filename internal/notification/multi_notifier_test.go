@@ -0,0 +1,75 @@
+package notification
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testNotifier struct {
+	mu     sync.Mutex
+	err    error
+	events []string
+}
+
+func (n *testNotifier) StartupCheck() (err error) {
+	return n.err
+}
+
+func (n *testNotifier) Send(_, _, _, _ string) (err error) {
+	return n.SendEvent("", "", "", "", "")
+}
+
+func (n *testNotifier) SendEvent(event, _, _, _, _ string) (err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.events = append(n.events, event)
+
+	return n.err
+}
+
+func TestShouldSendEventToScopedNotifiers(t *testing.T) {
+	all, login, reset := &testNotifier{}, &testNotifier{}, &testNotifier{}
+
+	notifier := NewMultiNotifier(
+		NamedNotifier{Name: "all", Notifier: all},
+		NamedNotifier{Name: "login", Events: []string{EventLogin}, Notifier: login},
+		NamedNotifier{Name: "reset", Events: []string{EventIdentityVerification, EventPasswordChanged}, Notifier: reset},
+	)
+
+	require.NoError(t, SendEvent(notifier, EventLogin, "john@example.com", "subject", "body", ""))
+	require.NoError(t, SendEvent(notifier, EventPasswordChanged, "john@example.com", "subject", "body", ""))
+	require.NoError(t, notifier.Send("john@example.com", "subject", "body", ""))
+
+	assert.Equal(t, []string{EventLogin, EventPasswordChanged, ""}, all.events)
+	assert.Equal(t, []string{EventLogin}, login.events)
+	assert.Equal(t, []string{EventPasswordChanged}, reset.events)
+}
+
+func TestShouldSendToAllNotifiersWhenOneFails(t *testing.T) {
+	failing, working := &testNotifier{err: errors.New("connection refused")}, &testNotifier{}
+
+	notifier := NewMultiNotifier(
+		NamedNotifier{Name: "failing", Notifier: failing},
+		NamedNotifier{Name: "working", Notifier: working},
+	)
+
+	err := SendEvent(notifier, EventLogin, "john@example.com", "subject", "body", "")
+
+	assert.EqualError(t, err, "the following notifiers failed to send the notification: failing")
+	assert.Equal(t, []string{EventLogin}, failing.events)
+	assert.Equal(t, []string{EventLogin}, working.events)
+}
+
+func TestShouldReturnStartupCheckErrorOfNotifier(t *testing.T) {
+	notifier := NewMultiNotifier(
+		NamedNotifier{Name: "working", Notifier: &testNotifier{}},
+		NamedNotifier{Name: "failing", Notifier: &testNotifier{err: errors.New("connection refused")}},
+	)
+
+	assert.EqualError(t, notifier.StartupCheck(), "notifier 'failing': connection refused")
+}
@@ -10,3 +10,20 @@ type Notifier interface {
 
 	Send(recipient, subject, body, htmlBody string) (err error)
 }
+
+// EventNotifier is a Notifier which is aware of the type of event which caused the notification.
+type EventNotifier interface {
+	Notifier
+
+	SendEvent(event, recipient, subject, body, htmlBody string) (err error)
+}
+
+// SendEvent sends a notification for the given event type using the notifier. Notifiers which are not aware of the event
+// types send the notification as usual.
+func SendEvent(notifier Notifier, event, recipient, subject, body, htmlBody string) (err error) {
+	if n, ok := notifier.(EventNotifier); ok {
+		return n.SendEvent(event, recipient, subject, body, htmlBody)
+	}
+
+	return notifier.Send(recipient, subject, body, htmlBody)
+}
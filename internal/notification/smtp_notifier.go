@@ -1,6 +1,7 @@
 package notification
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -215,6 +216,26 @@ func (n *SMTPNotifier) cleanup() {
 	}
 }
 
+// HealthCheck implements the health check provider interface by performing the SMTP handshake including the
+// authentication without sending an email.
+func (n *SMTPNotifier) HealthCheck(_ context.Context) (err error) {
+	if err = n.dial(); err != nil {
+		return err
+	}
+
+	defer n.cleanup()
+
+	if err = n.client.Hello(n.configuration.Identifier); err != nil {
+		return err
+	}
+
+	if err = n.startTLS(); err != nil {
+		return err
+	}
+
+	return n.auth()
+}
+
 // StartupCheck implements the startup check provider interface.
 func (n *SMTPNotifier) StartupCheck() (err error) {
 	if err := n.dial(); err != nil {
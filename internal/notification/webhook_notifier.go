@@ -0,0 +1,76 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// WebhookNotifier a notifier which sends notifications as JSON to a HTTP endpoint.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a WebhookNotifier using the notifier configuration.
+func NewWebhookNotifier(configuration schema.WebhookNotifierConfiguration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    configuration.URL,
+		client: &http.Client{Timeout: configuration.Timeout},
+	}
+}
+
+// StartupCheck implements the startup check provider interface. The endpoint is not contacted as it can't be done
+// without sending a notification.
+func (n *WebhookNotifier) StartupCheck() (err error) {
+	return nil
+}
+
+// Send sends the notification to the endpoint.
+func (n *WebhookNotifier) Send(recipient, subject, body, htmlBody string) (err error) {
+	return n.SendEvent("", recipient, subject, body, htmlBody)
+}
+
+// SendEvent sends the notification including the event type to the endpoint.
+func (n *WebhookNotifier) SendEvent(event, recipient, subject, body, _ string) (err error) {
+	payload, err := json.Marshal(webhookPayload{
+		Event:     event,
+		Recipient: recipient,
+		Subject:   subject,
+		Body:      body,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to encode the webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("unable to create the webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", webhookContentType)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to perform the webhook request: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unable to perform the webhook request: unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+type webhookPayload struct {
+	Event     string `json:"event,omitempty"`
+	Recipient string `json:"recipient"`
+	Subject   string `json:"subject"`
+	Body      string `json:"body"`
+}
@@ -0,0 +1,44 @@
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldSendWebhookNotification(t *testing.T) {
+	var payload webhookPayload
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, webhookContentType, r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(schema.WebhookNotifierConfiguration{URL: server.URL, Timeout: time.Second})
+
+	require.NoError(t, SendEvent(notifier, EventLogin, "john@example.com", "New sign-in to your account", "body", "<p>body</p>"))
+
+	assert.Equal(t, webhookPayload{Event: EventLogin, Recipient: "john@example.com", Subject: "New sign-in to your account", Body: "body"}, payload)
+}
+
+func TestShouldFailWebhookNotificationOnUnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(schema.WebhookNotifierConfiguration{URL: server.URL, Timeout: time.Second})
+
+	assert.EqualError(t, notifier.Send("john@example.com", "subject", "body", ""), "unable to perform the webhook request: unexpected status code 500")
+}
@@ -0,0 +1,45 @@
+package oidc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// ACRValue is an Authentication Context Class Reference value and the authorization level required to satisfy it.
+type ACRValue struct {
+	Value string
+	Level authorization.Level
+}
+
+// ResolveACRValues returns the first configured value of the space delimited acr_values parameter, which is ordered by
+// preference, and the authorization level required to satisfy it. Values which are not configured are skipped unless
+// the provider is configured to reject them. An empty value and the authorization.Bypass level are returned when none
+// of the values are configured.
+func (p OpenIDConnectProvider) ResolveACRValues(acrValues string) (acr ACRValue, err error) {
+	var found bool
+
+	for _, value := range strings.Fields(acrValues) {
+		known := false
+
+		for _, configured := range p.acrValues {
+			if configured.Value == value {
+				known = true
+
+				if !found {
+					acr, found = configured, true
+				}
+
+				break
+			}
+		}
+
+		if !known && p.unknownACRValues == schema.UnknownACRValuesError {
+			return ACRValue{}, fmt.Errorf("the acr value '%s' is not supported", value)
+		}
+	}
+
+	return acr, nil
+}
@@ -0,0 +1,48 @@
+package oidc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestOpenIDConnectProvider_ResolveACRValues(t *testing.T) {
+	acrValues := []ACRValue{
+		{Value: "urn:example:loa:1", Level: authorization.OneFactor},
+		{Value: "urn:example:loa:2", Level: authorization.TwoFactor},
+	}
+
+	testCases := []struct {
+		name     string
+		unknown  string
+		have     string
+		expected ACRValue
+		err      string
+	}{
+		{"ShouldResolveNothingWhenEmpty", schema.UnknownACRValuesError, "", ACRValue{}, ""},
+		{"ShouldResolveSingleValue", schema.UnknownACRValuesIgnore, "urn:example:loa:2", acrValues[1], ""},
+		{"ShouldResolveFirstValueByPreference", schema.UnknownACRValuesIgnore, "urn:example:loa:1 urn:example:loa:2", acrValues[0], ""},
+		{"ShouldSkipUnknownValues", schema.UnknownACRValuesIgnore, "urn:example:unknown urn:example:loa:2", acrValues[1], ""},
+		{"ShouldResolveNothingWhenOnlyUnknownValues", schema.UnknownACRValuesIgnore, "urn:example:unknown", ACRValue{}, ""},
+		{"ShouldErrorOnUnknownValues", schema.UnknownACRValuesError, "urn:example:loa:2 urn:example:unknown", ACRValue{}, "the acr value 'urn:example:unknown' is not supported"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := OpenIDConnectProvider{acrValues: acrValues, unknownACRValues: tc.unknown}
+
+			acr, err := provider.ResolveACRValues(tc.have)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+
+			assert.Equal(t, tc.expected, acr)
+		})
+	}
+}
@@ -8,6 +8,10 @@ type AuthenticationMethodsReferences struct {
 	Webauthn             bool
 	WebauthnUserPresence bool
 	WebauthnUserVerified bool
+
+	// TrustedJWT indicates the session was established from a JWT forwarded by a trusted upstream identity provider. It
+	// has no RFC8176 value as the upstream identity provider performed the authentication.
+	TrustedJWT bool
 }
 
 // FactorKnowledge returns true if a "something you know" factor of authentication was used.
@@ -0,0 +1,138 @@
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ClaimsRequest represents the claims request parameter.
+//
+// https://openid.net/specs/openid-connect-core-1_0.html#ClaimsParameter
+type ClaimsRequest struct {
+	IDToken  map[string]*ClaimRequest `json:"id_token,omitempty"`
+	UserInfo map[string]*ClaimRequest `json:"userinfo,omitempty"`
+}
+
+// ClaimRequest represents an individual claim requested by the claims request parameter. A nil ClaimRequest is a
+// voluntary claim without any value constraints.
+type ClaimRequest struct {
+	Essential bool          `json:"essential,omitempty"`
+	Value     interface{}   `json:"value,omitempty"`
+	Values    []interface{} `json:"values,omitempty"`
+}
+
+// NewClaimsRequest parses the claims request parameter from the form. It returns nil without an error if the
+// parameter was not provided.
+func NewClaimsRequest(form url.Values) (request *ClaimsRequest, err error) {
+	value := form.Get(FormParameterClaims)
+	if value == "" {
+		return nil, nil
+	}
+
+	request = &ClaimsRequest{}
+
+	if err = json.Unmarshal([]byte(value), request); err != nil {
+		return nil, fmt.Errorf("the claims parameter is not a valid JSON object: %w", err)
+	}
+
+	return request, nil
+}
+
+// Claims returns the claims requested for either the ID Token or the UserInfo endpoint. A claim requested for both is
+// essential if it's essential for either of them.
+func (r *ClaimsRequest) Claims() (claims map[string]*ClaimRequest) {
+	claims = map[string]*ClaimRequest{}
+
+	if r == nil {
+		return claims
+	}
+
+	for _, requested := range []map[string]*ClaimRequest{r.IDToken, r.UserInfo} {
+		for name, claim := range requested {
+			if existing, ok := claims[name]; !ok || existing == nil || (claim != nil && claim.Essential && !existing.Essential) {
+				claims[name] = claim
+			}
+		}
+	}
+
+	return claims
+}
+
+// Scopes returns the scopes which release the requested claims and are allowed for the client. Claims which are not
+// released by any scope, such as unknown claims, are ignored.
+func (r *ClaimsRequest) Scopes(client *Client, customScopes map[string]schema.OpenIDConnectCustomScopeConfiguration) (scopes []string) {
+	for name := range r.Claims() {
+		for _, scope := range ClaimScopes(name, customScopes) {
+			if utils.IsStringInSlice(scope, client.Scopes) && !utils.IsStringInSlice(scope, scopes) {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	sort.Strings(scopes)
+
+	return scopes
+}
+
+// IsEssential returns true if the claim is essential.
+func (c *ClaimRequest) IsEssential() bool {
+	return c != nil && c.Essential
+}
+
+// Matches returns true if the value satisfies the value or values of the claim request. When the value is a list of
+// strings such as the groups claim it matches if any of the strings match.
+func (c *ClaimRequest) Matches(value interface{}) bool {
+	if c == nil || (c.Value == nil && len(c.Values) == 0) {
+		return true
+	}
+
+	expected := c.Values
+
+	if c.Value != nil {
+		expected = append([]interface{}{c.Value}, expected...)
+	}
+
+	for _, e := range expected {
+		if actual, ok := value.([]string); ok {
+			for _, v := range actual {
+				if reflect.DeepEqual(e, v) {
+					return true
+				}
+			}
+
+			continue
+		}
+
+		if reflect.DeepEqual(e, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClaimScopes returns the scopes which release the claim.
+func ClaimScopes(claim string, customScopes map[string]schema.OpenIDConnectCustomScopeConfiguration) (scopes []string) {
+	switch claim {
+	case ClaimGroups:
+		scopes = append(scopes, ScopeGroups)
+	case ClaimPreferredUsername, ClaimDisplayName:
+		scopes = append(scopes, ScopeProfile)
+	case ClaimEmail, ClaimEmailVerified, ClaimEmailAlts:
+		scopes = append(scopes, ScopeEmail)
+	}
+
+	for name, scope := range customScopes {
+		if utils.IsStringInSlice(claim, scope.Claims) {
+			scopes = append(scopes, name)
+		}
+	}
+
+	return scopes
+}
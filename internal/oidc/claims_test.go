@@ -0,0 +1,59 @@
+package oidc
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestNewClaimsRequest(t *testing.T) {
+	request, err := NewClaimsRequest(url.Values{})
+	assert.NoError(t, err)
+	assert.Nil(t, request)
+
+	request, err = NewClaimsRequest(url.Values{FormParameterClaims: []string{`{"id_token":{"email":{"essential":true},"groups":null},"userinfo":{"email":null,"name":{"value":"John"}}}`}})
+	require.NoError(t, err)
+	require.NotNil(t, request)
+
+	claims := request.Claims()
+
+	require.Len(t, claims, 3)
+	assert.True(t, claims[ClaimEmail].IsEssential())
+	assert.False(t, claims[ClaimGroups].IsEssential())
+	assert.Nil(t, claims[ClaimGroups])
+	assert.Equal(t, "John", claims[ClaimDisplayName].Value)
+
+	for _, value := range []string{`abc`, `[]`, `{"id_token":[]}`, `{"userinfo":{"email":true}}`} {
+		request, err = NewClaimsRequest(url.Values{FormParameterClaims: []string{value}})
+		assert.Error(t, err)
+		assert.Nil(t, request)
+	}
+}
+
+func TestClaimsRequest_Scopes(t *testing.T) {
+	request, err := NewClaimsRequest(url.Values{FormParameterClaims: []string{`{"id_token":{"email_verified":null,"preferred_username":null,"phone_number":null},"userinfo":{"groups":null}}`}})
+	require.NoError(t, err)
+
+	customScopes := map[string]schema.OpenIDConnectCustomScopeConfiguration{
+		"roles": {Name: "roles", Claims: []string{ClaimGroups}},
+	}
+
+	assert.Equal(t, []string{ScopeEmail, ScopeGroups, "roles"}, request.Scopes(&Client{Scopes: []string{ScopeOpenID, ScopeEmail, ScopeGroups, "roles"}}, customScopes))
+	assert.Equal(t, []string{ScopeEmail}, request.Scopes(&Client{Scopes: []string{ScopeOpenID, ScopeEmail}}, customScopes))
+	assert.Len(t, (*ClaimsRequest)(nil).Scopes(&Client{Scopes: []string{ScopeEmail}}, nil), 0)
+}
+
+func TestClaimRequest_Matches(t *testing.T) {
+	assert.True(t, (*ClaimRequest)(nil).Matches("abc"))
+	assert.True(t, (&ClaimRequest{Essential: true}).Matches("abc"))
+	assert.True(t, (&ClaimRequest{Value: "abc"}).Matches("abc"))
+	assert.False(t, (&ClaimRequest{Value: "abc"}).Matches("xyz"))
+	assert.True(t, (&ClaimRequest{Values: []interface{}{"abc", "xyz"}}).Matches("xyz"))
+	assert.True(t, (&ClaimRequest{Value: true}).Matches(true))
+	assert.True(t, (&ClaimRequest{Value: "dev"}).Matches([]string{"admin", "dev"}))
+	assert.False(t, (&ClaimRequest{Values: []interface{}{"ops"}}).Matches([]string{"admin", "dev"}))
+}
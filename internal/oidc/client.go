@@ -1,12 +1,15 @@
 package oidc
 
 import (
+	"time"
+
 	"github.com/ory/fosite"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // NewClient creates a new Client.
@@ -17,19 +20,52 @@ func NewClient(config schema.OpenIDConnectClientConfiguration) (client *Client)
 		Secret:           []byte(config.Secret),
 		SectorIdentifier: config.SectorIdentifier.String(),
 		Public:           config.Public,
+		FirstParty:       config.FirstParty,
+
+		PreviousSecretExpiresAt: config.PreviousSecretExpiresAt,
 
 		Audience:      config.Audience,
 		Scopes:        config.Scopes,
+		DefaultScopes: config.DefaultScopes,
 		RedirectURIs:  config.RedirectURIs,
 		GrantTypes:    config.GrantTypes,
 		ResponseTypes: config.ResponseTypes,
 		ResponseModes: []fosite.ResponseModeType{fosite.ResponseModeDefault},
 
+		AllowedOrigins: utils.StringSliceFromURLs(config.AllowedOrigins),
+		AllowedGroups:  config.AllowedGroups,
+
+		TokenEndpointAuthMethod:  config.TokenEndpointAuthMethod,
 		UserinfoSigningAlgorithm: config.UserinfoSigningAlgorithm,
 
+		RequirePKCE:         config.RequirePKCE,
+		PKCEChallengeMethod: config.PKCEChallengeMethod,
+
 		Policy: authorization.PolicyToLevel(config.Policy),
 
 		PreConfiguredConsentDuration: config.PreConfiguredConsentDuration,
+
+		IntrospectionEnabled:        config.Introspection.Enabled,
+		IntrospectionAllowedClients: config.Introspection.AllowedClients,
+		IntrospectionScopes:         config.Introspection.Scopes,
+
+		clock: &utils.RealClock{},
+	}
+
+	if config.PreviousSecret != "" {
+		client.PreviousSecret = []byte(config.PreviousSecret)
+	}
+
+	if config.FirstParty {
+		client.PreAuthorizedScopes = config.PreAuthorizedScopes
+	}
+
+	if len(config.ScopeAudiences) != 0 {
+		client.ScopeAudiences = make(map[string][]string, len(config.ScopeAudiences))
+
+		for _, scopeAudience := range config.ScopeAudiences {
+			client.ScopeAudiences[scopeAudience.Scope] = scopeAudience.Audience
+		}
 	}
 
 	for _, mode := range config.ResponseModes {
@@ -44,6 +80,105 @@ func (c Client) IsAuthenticationLevelSufficient(level authentication.Level) bool
 	return authorization.IsAuthLevelSufficient(level, c.Policy)
 }
 
+// IsUserAllowed returns true if a user who is a member of the provided groups may use this client. Clients without
+// any allowed groups configured are permitted for all users.
+func (c Client) IsUserAllowed(groups []string) bool {
+	if len(c.AllowedGroups) == 0 {
+		return true
+	}
+
+	for _, group := range groups {
+		if utils.IsStringInSlice(group, c.AllowedGroups) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsTokenEndpointAuthMethodAllowed returns true if the client may authenticate at the token endpoint with the provided
+// method. Clients without a configured token endpoint authentication method may use any method except
+// client_secret_jwt, which requires the client to be configured with it explicitly.
+func (c Client) IsTokenEndpointAuthMethodAllowed(method string) bool {
+	if c.TokenEndpointAuthMethod == "" {
+		return method != ClientAuthMethodClientSecretJWT
+	}
+
+	return c.TokenEndpointAuthMethod == method
+}
+
+// IsPreAuthorized returns true if this is a first party client and all of the provided scopes are pre-authorized,
+// in which case the user is not asked for consent.
+func (c Client) IsPreAuthorized(scopes []string) bool {
+	if len(c.PreAuthorizedScopes) == 0 {
+		return false
+	}
+
+	for _, scope := range scopes {
+		if !utils.IsStringInSlice(scope, c.PreAuthorizedScopes) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetScopeAudiences returns the audiences mapped to the provided scopes without duplicates.
+func (c Client) GetScopeAudiences(scopes []string) (audience []string) {
+	for _, scope := range scopes {
+		for _, aud := range c.ScopeAudiences[scope] {
+			if !utils.IsStringInSlice(aud, audience) {
+				audience = append(audience, aud)
+			}
+		}
+	}
+
+	return audience
+}
+
+// IsAudienceAllowed returns true if the audience is one of the audiences of this client.
+func (c Client) IsAudienceAllowed(audience string) bool {
+	return utils.IsStringInSlice(audience, c.Audience)
+}
+
+// IsIntrospectionAllowed returns true if this client may introspect the tokens of the provided requester, which is
+// only the case for tokens issued to this client, tokens which have this client as an audience, and tokens of the
+// clients explicitly allowed for this client.
+func (c Client) IsIntrospectionAllowed(requester fosite.Requester) bool {
+	if !c.IntrospectionEnabled {
+		return false
+	}
+
+	id := requester.GetClient().GetID()
+
+	return id == c.ID || requester.GetGrantedAudience().Has(c.ID) || utils.IsStringInSlice(id, c.IntrospectionAllowedClients)
+}
+
+// GetIntrospectionClaims returns the claims from the provided claims which are released by a scope that was granted
+// to the token of the provided requester and that is one of the introspection scopes of this client.
+func (c Client) GetIntrospectionClaims(requester fosite.Requester, claims map[string]interface{},
+	customScopes map[string]schema.OpenIDConnectCustomScopeConfiguration) (introspection map[string]interface{}) {
+	introspection = map[string]interface{}{}
+
+	if len(c.IntrospectionScopes) == 0 {
+		return introspection
+	}
+
+	granted := requester.GetGrantedScopes()
+
+	for name, value := range claims {
+		for _, scope := range ClaimScopes(name, customScopes) {
+			if granted.Has(scope) && utils.IsStringInSlice(scope, c.IntrospectionScopes) {
+				introspection[name] = value
+
+				break
+			}
+		}
+	}
+
+	return introspection
+}
+
 // GetID returns the ID.
 func (c Client) GetID() string {
 	return c.ID
@@ -54,7 +189,8 @@ func (c Client) GetSectorIdentifier() string {
 	return c.SectorIdentifier
 }
 
-// GetConsentResponseBody returns the proper consent response body for this session.OIDCWorkflowSession.
+// GetConsentResponseBody returns the proper consent response body for this session.OIDCWorkflowSession. Only the
+// requested scopes the client is permitted to request are shown to the user.
 func (c Client) GetConsentResponseBody(consent *model.OAuth2ConsentSession) ConsentGetResponseBody {
 	body := ConsentGetResponseBody{
 		ClientID:          c.ID,
@@ -63,7 +199,12 @@ func (c Client) GetConsentResponseBody(consent *model.OAuth2ConsentSession) Cons
 	}
 
 	if consent != nil {
-		body.Scopes = consent.RequestedScopes
+		for _, scope := range consent.RequestedScopes {
+			if utils.IsStringInSlice(scope, c.Scopes) {
+				body.Scopes = append(body.Scopes, scope)
+			}
+		}
+
 		body.Audience = consent.RequestedAudience
 	}
 
@@ -75,6 +216,22 @@ func (c Client) GetHashedSecret() []byte {
 	return c.Secret
 }
 
+// GetRotatedHashes returns the PreviousSecret until PreviousSecretExpiresAt so clients can authenticate with either
+// the Secret or the PreviousSecret while they're rotating their secret.
+//
+// Implements the fosite.ClientWithSecretRotation.
+func (c Client) GetRotatedHashes() [][]byte {
+	return c.getRotatedHashes(c.clock.Now())
+}
+
+func (c Client) getRotatedHashes(now time.Time) [][]byte {
+	if len(c.PreviousSecret) == 0 || !now.Before(c.PreviousSecretExpiresAt) {
+		return nil
+	}
+
+	return [][]byte{c.PreviousSecret}
+}
+
 // GetRedirectURIs returns the RedirectURIs.
 func (c Client) GetRedirectURIs() []string {
 	return c.RedirectURIs
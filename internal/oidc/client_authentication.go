@@ -0,0 +1,174 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/ory/fosite"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewClientAuthenticationStrategy returns a new ClientAuthenticationStrategy which delegates the authentication
+// methods it doesn't implement itself to the fosite.Fosite provider.
+func NewClientAuthenticationStrategy(provider *fosite.Fosite, store *OpenIDConnectStore, clock utils.Clock, clockSkew time.Duration) *ClientAuthenticationStrategy {
+	return &ClientAuthenticationStrategy{
+		provider:  provider,
+		store:     store,
+		clock:     clock,
+		clockSkew: clockSkew,
+	}
+}
+
+// ClientAuthenticationStrategy authenticates the clients at the token, introspection, and revocation endpoints. The
+// token endpoint authentication method configured for a client is enforced, and the client_secret_jwt method is
+// implemented here as fosite doesn't support it. The time based claims of the client_secret_jwt assertions tolerate the
+// clock skew.
+type ClientAuthenticationStrategy struct {
+	provider  *fosite.Fosite
+	store     *OpenIDConnectStore
+	clock     utils.Clock
+	clockSkew time.Duration
+}
+
+// AuthenticateClient authenticates the client of the request.
+//
+// Implements the fosite.ClientAuthenticationStrategy.
+func (s *ClientAuthenticationStrategy) AuthenticateClient(ctx context.Context, r *http.Request, form url.Values) (client fosite.Client, err error) {
+	if assertionType := form.Get(FormParameterClientAssertionType); assertionType != "" {
+		if assertionType != ClientAssertionTypeJWTBearer {
+			return nil, fosite.ErrInvalidRequest.WithHintf("Unknown client_assertion_type '%s'.", assertionType)
+		}
+
+		return s.authenticateClientSecretJWT(ctx, form)
+	}
+
+	method, id := ClientAuthMethodNone, form.Get(FormParameterClientID)
+
+	if username, _, ok := r.BasicAuth(); ok {
+		method = ClientAuthMethodClientSecretBasic
+
+		if id, err = url.QueryUnescape(username); err != nil {
+			return nil, fosite.ErrInvalidRequest.WithHint("The client id in the HTTP authorization header could not be decoded from 'application/x-www-form-urlencoded'.").WithWrap(err).WithDebug(err.Error())
+		}
+	} else if form.Get(FormParameterClientSecret) != "" {
+		method = ClientAuthMethodClientSecretPost
+	}
+
+	// Unknown clients are rejected by the default strategy.
+	if c, err := s.store.GetFullClient(id); err == nil && !c.IsTokenEndpointAuthMethodAllowed(method) {
+		return nil, fosite.ErrInvalidClient.WithHintf("The OAuth 2.0 Client only supports client authentication method '%s', but method '%s' was requested.", c.TokenEndpointAuthMethod, method)
+	}
+
+	return s.provider.DefaultClientAuthenticationStrategy(ctx, r, form)
+}
+
+// authenticateClientSecretJWT authenticates a client with a client_assertion signed with the client secret using a HMAC
+// algorithm as per https://openid.net/specs/openid-connect-core-1_0.html#ClientAuthentication.
+func (s *ClientAuthenticationStrategy) authenticateClientSecretJWT(ctx context.Context, form url.Values) (client fosite.Client, err error) {
+	assertion := form.Get(FormParameterClientAssertion)
+	if assertion == "" {
+		return nil, fosite.ErrInvalidRequest.WithHintf("The client_assertion request parameter must be set when using client_assertion_type of '%s'.", ClientAssertionTypeJWTBearer)
+	}
+
+	issuerCtx, ok := ctx.(issuerContext)
+	if !ok {
+		return nil, fosite.ErrMisconfiguration.WithHint("The authorization server's token endpoint URL could not be determined.")
+	}
+
+	issuer, err := issuerCtx.ExternalRootURL()
+	if err != nil {
+		return nil, fosite.ErrMisconfiguration.WithHint("The authorization server's token endpoint URL could not be determined.").WithWrap(err).WithDebug(err.Error())
+	}
+
+	claims := &jwt.RegisteredClaims{}
+
+	// The time based claims are validated below with the clock skew.
+	parser := jwt.NewParser(jwt.WithValidMethods(ClientSecretJWTSigningAlgorithms), jwt.WithoutClaimsValidation())
+
+	if _, _, err = parser.ParseUnverified(assertion, claims); err != nil {
+		return nil, fosite.ErrInvalidClient.WithHint("Unable to parse the 'client_assertion' value.").WithWrap(err).WithDebug(err.Error())
+	}
+
+	id := form.Get(FormParameterClientID)
+	if id == "" {
+		id = claims.Subject
+	}
+
+	c, err := s.store.GetFullClient(id)
+	if err != nil {
+		return nil, fosite.ErrInvalidClient.WithWrap(err).WithDebug(err.Error())
+	}
+
+	if c.TokenEndpointAuthMethod != ClientAuthMethodClientSecretJWT {
+		return nil, fosite.ErrInvalidClient.WithHintf("The OAuth 2.0 Client doesn't support client authentication method '%s'.", ClientAuthMethodClientSecretJWT)
+	}
+
+	tokenURL, now := fmt.Sprintf("%s%s", issuer, TokenPath), s.clock.Now()
+
+	if claims, err = c.parseClientSecretJWT(parser, assertion, now); err != nil {
+		return nil, fosite.ErrInvalidClient.WithHint("Unable to verify the integrity of the 'client_assertion' value.").WithWrap(err).WithDebug(err.Error())
+	}
+
+	switch {
+	case claims.ExpiresAt == nil:
+		return nil, fosite.ErrInvalidClient.WithHint("Claim 'exp' from 'client_assertion' must be set but is not.")
+	case !claims.VerifyExpiresAt(now.Add(-s.clockSkew), true):
+		return nil, fosite.ErrInvalidClient.WithHint("Claim 'exp' from 'client_assertion' indicates it has expired.")
+	case !claims.VerifyIssuedAt(now.Add(s.clockSkew), false):
+		return nil, fosite.ErrInvalidClient.WithHint("Claim 'iat' from 'client_assertion' indicates it was issued in the future.")
+	case !claims.VerifyNotBefore(now.Add(s.clockSkew), false):
+		return nil, fosite.ErrInvalidClient.WithHint("Claim 'nbf' from 'client_assertion' indicates it is not valid yet.")
+	case claims.Issuer != c.ID:
+		return nil, fosite.ErrInvalidClient.WithHint("Claim 'iss' from 'client_assertion' must match the 'client_id' of the OAuth 2.0 Client.")
+	case claims.Subject != c.ID:
+		return nil, fosite.ErrInvalidClient.WithHint("Claim 'sub' from 'client_assertion' must match the 'client_id' of the OAuth 2.0 Client.")
+	case claims.ID == "":
+		return nil, fosite.ErrInvalidClient.WithHint("Claim 'jti' from 'client_assertion' must be set but is not.")
+	case !claims.VerifyAudience(tokenURL, true):
+		return nil, fosite.ErrInvalidClient.WithHintf("Claim 'audience' from 'client_assertion' must match the authorization server's token endpoint '%s'.", tokenURL)
+	}
+
+	if err = s.store.ClientAssertionJWTValid(ctx, claims.ID); err != nil {
+		return nil, fosite.ErrJTIKnown.WithHint("Claim 'jti' from 'client_assertion' MUST only be used once.").WithWrap(err).WithDebug(err.Error())
+	}
+
+	// The jti is remembered until the assertion can no longer be accepted with the clock skew.
+	if err = s.store.SetClientAssertionJWT(ctx, claims.ID, claims.ExpiresAt.Add(s.clockSkew)); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// parseClientSecretJWT parses and verifies a client_assertion signed with the secret of the client, or with the
+// previous secret of the client until it expires.
+func (c Client) parseClientSecretJWT(parser *jwt.Parser, assertion string, now time.Time) (claims *jwt.RegisteredClaims, err error) {
+	var digest ClientSecretDigest
+
+	for _, secret := range append([][]byte{c.Secret}, c.getRotatedHashes(now)...) {
+		if digest, err = ParseClientSecretDigest(string(secret)); err != nil {
+			return nil, err
+		}
+
+		key, ok := digest.(plainTextClientSecretDigest)
+		if !ok {
+			continue
+		}
+
+		claims = &jwt.RegisteredClaims{}
+
+		if _, err = parser.ParseWithClaims(assertion, claims, func(_ *jwt.Token) (interface{}, error) {
+			return []byte(key), nil
+		}); err == nil || !errors.Is(err, jwt.ErrSignatureInvalid) {
+			return claims, err
+		}
+	}
+
+	return nil, jwt.ErrSignatureInvalid
+}
@@ -0,0 +1,293 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/storage"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+type testIssuerContext struct {
+	context.Context
+
+	issuer string
+}
+
+func (ctx testIssuerContext) ExternalRootURL() (string, error) {
+	return ctx.issuer, nil
+}
+
+func newTestClientAuthenticationStrategy(t *testing.T, clock utils.Clock, clockSkew time.Duration) fosite.ClientAuthenticationStrategy {
+	store := storage.NewSQLiteProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "a_very_long_encryption_key_used_for_testing",
+			Local:         &schema.LocalStorageConfiguration{Path: filepath.Join(t.TempDir(), "db.sqlite3")},
+		},
+	})
+
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+
+	require.NoError(t, store.StartupCheck())
+
+	provider, err := NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: exampleIssuerPrivateKey,
+		HMACSecret:       "asbdhaaskmdlkamdklasmdlkams",
+		ClockSkew:        clockSkew,
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{ID: "basic", Secret: "basic-secret", Policy: "one_factor", TokenEndpointAuthMethod: ClientAuthMethodClientSecretBasic},
+			{ID: "post", Secret: "post-secret", Policy: "one_factor", TokenEndpointAuthMethod: ClientAuthMethodClientSecretPost},
+			{ID: "jwt", Secret: "$plaintext$jwt-secret", Policy: "one_factor", TokenEndpointAuthMethod: ClientAuthMethodClientSecretJWT,
+				PreviousSecret: "jwt-previous-secret", PreviousSecretExpiresAt: time.Now().Add(time.Hour)},
+			{ID: "any", Secret: "any-secret", Policy: "one_factor"},
+			{ID: "public", Public: true, Policy: "one_factor", TokenEndpointAuthMethod: ClientAuthMethodNone},
+		},
+	}, store)
+
+	require.NoError(t, err)
+
+	for _, client := range provider.Store.clients {
+		client.clock = clock
+	}
+
+	return NewClientAuthenticationStrategy(provider.Fosite.(*fosite.Fosite), provider.Store, clock, clockSkew).AuthenticateClient
+}
+
+func newTestClientAssertion(t *testing.T, method jwt.SigningMethod, secret string, claims jwt.RegisteredClaims) string {
+	assertion, err := jwt.NewWithClaims(method, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	return assertion
+}
+
+func newTestClientAssertionClaims(id, jti string) jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Issuer:    id,
+		Subject:   id,
+		Audience:  jwt.ClaimStrings{"https://auth.example.com" + TokenPath},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ID:        jti,
+	}
+}
+
+func TestClientAuthenticationStrategy_ShouldEnforceTokenEndpointAuthMethod(t *testing.T) {
+	strategy := newTestClientAuthenticationStrategy(t, &utils.RealClock{}, 0)
+
+	ctx := testIssuerContext{Context: context.Background(), issuer: "https://auth.example.com"}
+
+	basic := func(id, secret string) func(r *http.Request, form url.Values) {
+		return func(r *http.Request, form url.Values) {
+			r.SetBasicAuth(id, secret)
+		}
+	}
+
+	post := func(id, secret string) func(r *http.Request, form url.Values) {
+		return func(r *http.Request, form url.Values) {
+			form.Set(FormParameterClientID, id)
+			form.Set(FormParameterClientSecret, secret)
+		}
+	}
+
+	assertion := func(value string) func(r *http.Request, form url.Values) {
+		return func(r *http.Request, form url.Values) {
+			form.Set(FormParameterClientAssertionType, ClientAssertionTypeJWTBearer)
+			form.Set(FormParameterClientAssertion, value)
+		}
+	}
+
+	expired := newTestClientAssertionClaims("jwt", "expired")
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	wrongAudience := newTestClientAssertionClaims("jwt", "wrong-audience")
+	wrongAudience.Audience = jwt.ClaimStrings{"https://auth.example.com"}
+
+	wrongIssuer := newTestClientAssertionClaims("jwt", "wrong-issuer")
+	wrongIssuer.Issuer = "basic"
+
+	testCases := []struct {
+		name     string
+		setup    func(r *http.Request, form url.Values)
+		expected string
+		err      error
+	}{
+		{"ShouldAllowClientSecretBasic", basic("basic", "basic-secret"), "basic", nil},
+		{"ShouldRejectClientSecretPostForClientSecretBasicClient", post("basic", "basic-secret"), "", fosite.ErrInvalidClient},
+		{"ShouldRejectClientSecretJWTForClientSecretBasicClient", assertion(newTestClientAssertion(t, jwt.SigningMethodHS256, "basic-secret", newTestClientAssertionClaims("basic", "basic"))), "", fosite.ErrInvalidClient},
+		{"ShouldAllowClientSecretPost", post("post", "post-secret"), "post", nil},
+		{"ShouldRejectClientSecretBasicForClientSecretPostClient", basic("post", "post-secret"), "", fosite.ErrInvalidClient},
+		{"ShouldAllowClientSecretJWT", assertion(newTestClientAssertion(t, jwt.SigningMethodHS256, "jwt-secret", newTestClientAssertionClaims("jwt", "hs256"))), "jwt", nil},
+		{"ShouldAllowClientSecretJWTWithHS512", assertion(newTestClientAssertion(t, jwt.SigningMethodHS512, "jwt-secret", newTestClientAssertionClaims("jwt", "hs512"))), "jwt", nil},
+		{"ShouldAllowClientSecretJWTSignedWithPreviousSecret", assertion(newTestClientAssertion(t, jwt.SigningMethodHS256, "jwt-previous-secret", newTestClientAssertionClaims("jwt", "previous"))), "jwt", nil},
+		{"ShouldRejectClientSecretJWTWithReusedJTI", assertion(newTestClientAssertion(t, jwt.SigningMethodHS256, "jwt-secret", newTestClientAssertionClaims("jwt", "hs256"))), "", fosite.ErrJTIKnown},
+		{"ShouldRejectClientSecretJWTWithWrongSecret", assertion(newTestClientAssertion(t, jwt.SigningMethodHS256, "wrong-secret", newTestClientAssertionClaims("jwt", "wrong-secret"))), "", fosite.ErrInvalidClient},
+		{"ShouldRejectClientSecretJWTWithExpiredAssertion", assertion(newTestClientAssertion(t, jwt.SigningMethodHS256, "jwt-secret", expired)), "", fosite.ErrInvalidClient},
+		{"ShouldRejectClientSecretJWTWithWrongAudience", assertion(newTestClientAssertion(t, jwt.SigningMethodHS256, "jwt-secret", wrongAudience)), "", fosite.ErrInvalidClient},
+		{"ShouldRejectClientSecretJWTWithWrongIssuer", assertion(newTestClientAssertion(t, jwt.SigningMethodHS256, "jwt-secret", wrongIssuer)), "", fosite.ErrInvalidClient},
+		{"ShouldRejectClientSecretBasicForClientSecretJWTClient", basic("jwt", "jwt-secret"), "", fosite.ErrInvalidClient},
+		{"ShouldRejectClientSecretPostForClientSecretJWTClient", post("jwt", "jwt-secret"), "", fosite.ErrInvalidClient},
+		{"ShouldAllowClientSecretBasicWithoutMethod", basic("any", "any-secret"), "any", nil},
+		{"ShouldAllowClientSecretPostWithoutMethod", post("any", "any-secret"), "any", nil},
+		{"ShouldRejectClientSecretJWTWithoutMethod", assertion(newTestClientAssertion(t, jwt.SigningMethodHS256, "any-secret", newTestClientAssertionClaims("any", "any"))), "", fosite.ErrInvalidClient},
+		{"ShouldAllowNone", post("public", ""), "public", nil},
+		{"ShouldRejectClientSecretBasicForNoneClient", basic("public", ""), "", fosite.ErrInvalidClient},
+		{"ShouldRejectUnknownAssertionType", func(r *http.Request, form url.Values) {
+			form.Set(FormParameterClientAssertionType, "urn:example")
+			form.Set(FormParameterClientAssertion, "assertion")
+		}, "", fosite.ErrInvalidRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, TokenPath, nil)
+			form := url.Values{}
+
+			tc.setup(r, form)
+
+			client, err := strategy(ctx, r, form)
+
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Nil(t, client)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, client.GetID())
+			}
+		})
+	}
+}
+
+func TestClientAuthenticationStrategy_ShouldTolerateClockSkew(t *testing.T) {
+	clock := &testKeyClock{now: time.Now()}
+
+	strategy := newTestClientAuthenticationStrategy(t, clock, time.Minute)
+
+	ctx := testIssuerContext{Context: context.Background(), issuer: "https://auth.example.com"}
+
+	claims := func(jti string, modify func(claims *jwt.RegisteredClaims)) jwt.RegisteredClaims {
+		c := newTestClientAssertionClaims("jwt", jti)
+
+		modify(&c)
+
+		return c
+	}
+
+	testCases := []struct {
+		name   string
+		claims jwt.RegisteredClaims
+		err    error
+	}{
+		{"ShouldAllowExpiredWithinClockSkew", claims("expired", func(c *jwt.RegisteredClaims) {
+			c.ExpiresAt = jwt.NewNumericDate(clock.now.Add(-time.Second * 30))
+		}), nil},
+		{"ShouldAllowIssuedInTheFutureWithinClockSkew", claims("future-iat", func(c *jwt.RegisteredClaims) {
+			c.IssuedAt = jwt.NewNumericDate(clock.now.Add(time.Second * 30))
+		}), nil},
+		{"ShouldAllowNotValidYetWithinClockSkew", claims("future-nbf", func(c *jwt.RegisteredClaims) {
+			c.NotBefore = jwt.NewNumericDate(clock.now.Add(time.Second * 30))
+		}), nil},
+		{"ShouldRejectExpiredBeyondClockSkew", claims("expired-beyond", func(c *jwt.RegisteredClaims) {
+			c.ExpiresAt = jwt.NewNumericDate(clock.now.Add(-time.Minute * 2))
+		}), fosite.ErrInvalidClient},
+		{"ShouldRejectIssuedInTheFutureBeyondClockSkew", claims("future-iat-beyond", func(c *jwt.RegisteredClaims) {
+			c.IssuedAt = jwt.NewNumericDate(clock.now.Add(time.Minute * 2))
+		}), fosite.ErrInvalidClient},
+		{"ShouldRejectNotValidYetBeyondClockSkew", claims("future-nbf-beyond", func(c *jwt.RegisteredClaims) {
+			c.NotBefore = jwt.NewNumericDate(clock.now.Add(time.Minute * 2))
+		}), fosite.ErrInvalidClient},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			form := url.Values{}
+			form.Set(FormParameterClientAssertionType, ClientAssertionTypeJWTBearer)
+			form.Set(FormParameterClientAssertion, newTestClientAssertion(t, jwt.SigningMethodHS256, "jwt-secret", tc.claims))
+
+			client, err := strategy(ctx, httptest.NewRequest(http.MethodPost, TokenPath, nil), form)
+
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Nil(t, client)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "jwt", client.GetID())
+			}
+		})
+	}
+}
+
+func TestClientAuthenticationStrategy_ShouldUseClock(t *testing.T) {
+	clock := &testKeyClock{now: time.Now()}
+
+	strategy := newTestClientAuthenticationStrategy(t, clock, 0)
+
+	ctx := testIssuerContext{Context: context.Background(), issuer: "https://auth.example.com"}
+
+	authenticate := func(secret, jti string) (fosite.Client, error) {
+		claims := newTestClientAssertionClaims("jwt", jti)
+		claims.ExpiresAt = jwt.NewNumericDate(clock.now.Add(time.Minute))
+		claims.IssuedAt = jwt.NewNumericDate(clock.now)
+
+		form := url.Values{}
+		form.Set(FormParameterClientAssertionType, ClientAssertionTypeJWTBearer)
+		form.Set(FormParameterClientAssertion, newTestClientAssertion(t, jwt.SigningMethodHS256, secret, claims))
+
+		return strategy(ctx, httptest.NewRequest(http.MethodPost, TokenPath, nil), form)
+	}
+
+	client, err := authenticate("jwt-previous-secret", "previous")
+	require.NoError(t, err)
+	assert.Equal(t, "jwt", client.GetID())
+
+	// The previous secret expires an hour after the client was registered.
+	clock.now = clock.now.Add(time.Hour * 2)
+
+	client, err = authenticate("jwt-previous-secret", "previous-expired")
+	assert.ErrorIs(t, err, fosite.ErrInvalidClient)
+	assert.Nil(t, client)
+
+	client, err = authenticate("jwt-secret", "current")
+	require.NoError(t, err)
+	assert.Equal(t, "jwt", client.GetID())
+}
+
+func TestClientAuthenticationStrategy_ShouldRejectClientSecretJWTWithoutIssuer(t *testing.T) {
+	strategy := newTestClientAuthenticationStrategy(t, &utils.RealClock{}, 0)
+
+	form := url.Values{}
+	form.Set(FormParameterClientAssertionType, ClientAssertionTypeJWTBearer)
+	form.Set(FormParameterClientAssertion, newTestClientAssertion(t, jwt.SigningMethodHS256, "jwt-secret", newTestClientAssertionClaims("jwt", "no-issuer")))
+
+	client, err := strategy(context.Background(), httptest.NewRequest(http.MethodPost, TokenPath, nil), form)
+
+	assert.ErrorIs(t, err, fosite.ErrMisconfiguration)
+	assert.Nil(t, client)
+}
+
+func TestClient_IsTokenEndpointAuthMethodAllowed(t *testing.T) {
+	client := Client{}
+
+	assert.True(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodClientSecretBasic))
+	assert.True(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodClientSecretPost))
+	assert.True(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodNone))
+	assert.False(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodClientSecretJWT))
+
+	client.TokenEndpointAuthMethod = ClientAuthMethodClientSecretJWT
+
+	assert.True(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodClientSecretJWT))
+	assert.False(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodClientSecretBasic))
+	assert.False(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodClientSecretPost))
+}
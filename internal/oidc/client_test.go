@@ -2,6 +2,7 @@ package oidc
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ory/fosite"
 	"github.com/stretchr/testify/assert"
@@ -11,6 +12,7 @@ import (
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 func TestNewClient(t *testing.T) {
@@ -32,10 +34,13 @@ func TestNewClient(t *testing.T) {
 		ResponseTypes: schema.DefaultOpenIDConnectClientConfiguration.ResponseTypes,
 		GrantTypes:    schema.DefaultOpenIDConnectClientConfiguration.GrantTypes,
 		ResponseModes: schema.DefaultOpenIDConnectClientConfiguration.ResponseModes,
+
+		AllowedOrigins: utils.URLsFromStringSlice([]string{"https://app.example.com"}),
 	}
 
 	exampleClient := NewClient(exampleConfig)
 	assert.Equal(t, "myapp", exampleClient.ID)
+	assert.Equal(t, []string{"https://app.example.com"}, exampleClient.AllowedOrigins)
 	require.Len(t, exampleClient.ResponseModes, 4)
 	assert.Equal(t, fosite.ResponseModeDefault, exampleClient.ResponseModes[0])
 	assert.Equal(t, fosite.ResponseModeFormPost, exampleClient.ResponseModes[1])
@@ -67,6 +72,51 @@ func TestIsAuthenticationLevelSufficient(t *testing.T) {
 	assert.False(t, c.IsAuthenticationLevelSufficient(authentication.TwoFactor))
 }
 
+func TestClient_IsUserAllowed(t *testing.T) {
+	c := Client{}
+
+	assert.True(t, c.IsUserAllowed(nil))
+	assert.True(t, c.IsUserAllowed([]string{"admins"}))
+
+	c.AllowedGroups = []string{"admins", "dev"}
+
+	assert.False(t, c.IsUserAllowed(nil))
+	assert.False(t, c.IsUserAllowed([]string{"users"}))
+	assert.True(t, c.IsUserAllowed([]string{"users", "dev"}))
+	assert.True(t, c.IsUserAllowed([]string{"admins"}))
+}
+
+func TestClient_IsPreAuthorized(t *testing.T) {
+	c := Client{}
+
+	assert.False(t, c.IsPreAuthorized(nil))
+	assert.False(t, c.IsPreAuthorized([]string{"openid"}))
+
+	c.PreAuthorizedScopes = []string{"openid", "profile"}
+
+	assert.True(t, c.IsPreAuthorized([]string{"openid"}))
+	assert.True(t, c.IsPreAuthorized([]string{"openid", "profile"}))
+	assert.False(t, c.IsPreAuthorized([]string{"openid", "groups"}))
+}
+
+func TestClient_GetScopeAudiences(t *testing.T) {
+	c := NewClient(schema.OpenIDConnectClientConfiguration{
+		Audience: []string{"https://api.example.com", "https://other.example.com"},
+		ScopeAudiences: []schema.OpenIDConnectClientScopeAudience{
+			{Scope: "groups", Audience: []string{"https://api.example.com"}},
+			{Scope: "email", Audience: []string{"https://api.example.com", "https://other.example.com"}},
+		},
+	})
+
+	assert.Nil(t, c.GetScopeAudiences(nil))
+	assert.Nil(t, c.GetScopeAudiences([]string{"openid"}))
+	assert.Equal(t, []string{"https://api.example.com"}, c.GetScopeAudiences([]string{"openid", "groups"}))
+	assert.Equal(t, []string{"https://api.example.com", "https://other.example.com"}, c.GetScopeAudiences([]string{"groups", "email"}))
+
+	assert.True(t, c.IsAudienceAllowed("https://other.example.com"))
+	assert.False(t, c.IsAudienceAllowed("https://bad.example.com"))
+}
+
 func TestInternalClient_GetConsentResponseBody(t *testing.T) {
 	c := Client{}
 
@@ -78,10 +128,11 @@ func TestInternalClient_GetConsentResponseBody(t *testing.T) {
 
 	c.ID = "myclient"
 	c.Description = "My Client"
+	c.Scopes = []string{"openid", "groups", "email"}
 
 	consent := &model.OAuth2ConsentSession{
 		RequestedAudience: []string{"https://example.com"},
-		RequestedScopes:   []string{"openid", "groups"},
+		RequestedScopes:   []string{"openid", "groups", "profile"},
 	}
 
 	expectedScopes := []string{"openid", "groups"}
@@ -146,6 +197,25 @@ func TestInternalClient_GetHashedSecret(t *testing.T) {
 	assert.Equal(t, []byte("a_bad_secret"), hashedSecret)
 }
 
+func TestInternalClient_GetRotatedHashes(t *testing.T) {
+	c := NewClient(schema.OpenIDConnectClientConfiguration{ID: "myapp", Secret: "new_secret"})
+
+	assert.Nil(t, c.GetRotatedHashes())
+
+	c = NewClient(schema.OpenIDConnectClientConfiguration{
+		ID:                      "myapp",
+		Secret:                  "new_secret",
+		PreviousSecret:          "old_secret",
+		PreviousSecretExpiresAt: time.Now().Add(time.Hour),
+	})
+
+	assert.Equal(t, [][]byte{[]byte("old_secret")}, c.GetRotatedHashes())
+
+	c.clock = &testKeyClock{now: c.PreviousSecretExpiresAt}
+
+	assert.Nil(t, c.GetRotatedHashes())
+}
+
 func TestInternalClient_GetID(t *testing.T) {
 	c := Client{}
 
@@ -213,3 +283,47 @@ func TestInternalClient_IsPublic(t *testing.T) {
 	c.Public = true
 	assert.True(t, c.IsPublic())
 }
+
+func TestInternalClient_IsIntrospectionAllowed(t *testing.T) {
+	c := Client{ID: "api", IntrospectionAllowedClients: []string{"trusted"}}
+
+	requester := &fosite.Request{Client: &Client{ID: "api"}}
+
+	assert.False(t, c.IsIntrospectionAllowed(requester))
+
+	c.IntrospectionEnabled = true
+
+	assert.True(t, c.IsIntrospectionAllowed(requester))
+	assert.True(t, c.IsIntrospectionAllowed(&fosite.Request{Client: &Client{ID: "trusted"}}))
+	assert.True(t, c.IsIntrospectionAllowed(&fosite.Request{Client: &Client{ID: "app"}, GrantedAudience: fosite.Arguments{"api"}}))
+	assert.False(t, c.IsIntrospectionAllowed(&fosite.Request{Client: &Client{ID: "app"}, GrantedAudience: fosite.Arguments{"other"}}))
+}
+
+func TestInternalClient_GetIntrospectionClaims(t *testing.T) {
+	c := Client{ID: "api", IntrospectionEnabled: true}
+
+	claims := map[string]interface{}{
+		ClaimGroups:            []string{"admin"},
+		ClaimEmail:             "john@example.com",
+		ClaimPreferredUsername: "john",
+	}
+
+	customScopes := map[string]schema.OpenIDConnectCustomScopeConfiguration{
+		"roles": {Name: "roles", Claims: []string{ClaimGroups}},
+	}
+
+	requester := &fosite.Request{GrantedScope: fosite.Arguments{ScopeOpenID, ScopeEmail, ScopeProfile, "roles"}}
+
+	assert.Equal(t, map[string]interface{}{}, c.GetIntrospectionClaims(requester, claims, customScopes))
+
+	c.IntrospectionScopes = []string{ScopeGroups, ScopeProfile, "roles"}
+
+	assert.Equal(t, map[string]interface{}{
+		ClaimGroups:            []string{"admin"},
+		ClaimPreferredUsername: "john",
+	}, c.GetIntrospectionClaims(requester, claims, customScopes))
+
+	c.IntrospectionScopes = []string{ScopeGroups}
+
+	assert.Equal(t, map[string]interface{}{}, c.GetIntrospectionClaims(requester, claims, customScopes))
+}
@@ -11,12 +11,67 @@ const (
 
 // Claim strings.
 const (
+	ClaimSubject           = "sub"
 	ClaimGroups            = "groups"
 	ClaimDisplayName       = "name"
 	ClaimPreferredUsername = "preferred_username"
 	ClaimEmail             = "email"
 	ClaimEmailVerified     = "email_verified"
 	ClaimEmailAlts         = "alt_emails"
+	ClaimNotBefore         = "nbf"
+)
+
+// Form parameter strings.
+const (
+	FormParameterLoginHint           = "login_hint"
+	FormParameterMaximumAge          = "max_age"
+	FormParameterClaims              = "claims"
+	FormParameterACRValues           = "acr_values"
+	FormParameterPrompt              = "prompt"
+	FormParameterCode                = "code"
+	FormParameterCodeChallenge       = "code_challenge"
+	FormParameterCodeChallengeMethod = "code_challenge_method"
+	FormParameterClientID            = "client_id"
+	FormParameterClientSecret        = "client_secret"
+	FormParameterClientAssertion     = "client_assertion"
+	FormParameterClientAssertionType = "client_assertion_type"
+)
+
+// Client authentication methods of the token endpoint.
+const (
+	ClientAuthMethodClientSecretBasic = "client_secret_basic"
+	ClientAuthMethodClientSecretPost  = "client_secret_post"
+	ClientAuthMethodClientSecretJWT   = "client_secret_jwt"
+	ClientAuthMethodNone              = "none"
+)
+
+// ClientAssertionTypeJWTBearer is the client assertion type of client assertions which are a JWT.
+const ClientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// ClientAuthMethodsSupported are the client authentication methods supported by the token, introspection, and
+// revocation endpoints.
+var ClientAuthMethodsSupported = []string{
+	ClientAuthMethodClientSecretBasic, ClientAuthMethodClientSecretPost, ClientAuthMethodClientSecretJWT, ClientAuthMethodNone,
+}
+
+// ClientSecretJWTSigningAlgorithms are the algorithms the client_assertion of the client_secret_jwt client
+// authentication method may be signed with.
+var ClientSecretJWTSigningAlgorithms = []string{"HS256", "HS384", "HS512"}
+
+// PKCE code challenge methods.
+const (
+	PKCEChallengeMethodSHA256 = "S256"
+	PKCEChallengeMethodPlain  = "plain"
+)
+
+// Grant type strings.
+const (
+	GrantTypeRefreshToken = "refresh_token"
+)
+
+// Prompt values.
+const (
+	PromptConsent = "consent"
 )
 
 // Endpoints.
@@ -136,3 +191,28 @@ const (
 	// RFC8176: https://datatracker.ietf.org/doc/html/rfc8176
 	AMRShortMessageService = "sms"
 )
+
+// Client secret digest prefixes.
+const (
+	clientSecretPrefixPlainText    = "$plaintext$"
+	clientSecretPrefixPBKDF2SHA1   = "$pbkdf2$"
+	clientSecretPrefixPBKDF2SHA256 = "$pbkdf2-sha256$"
+	clientSecretPrefixPBKDF2SHA512 = "$pbkdf2-sha512$"
+	clientSecretPrefixBCrypt2A     = "$2a$"
+	clientSecretPrefixBCrypt2B     = "$2b$"
+	clientSecretPrefixBCrypt2Y     = "$2y$"
+)
+
+// ClientSecretDefaultIterations is the default number of iterations of the pbkdf2-sha512 client secret digests.
+const ClientSecretDefaultIterations = 310000
+
+const clientSecretSaltLength = 16
+
+var (
+	clientSecretPrefixesBCrypt = []string{clientSecretPrefixBCrypt2A, clientSecretPrefixBCrypt2B, clientSecretPrefixBCrypt2Y}
+
+	clientSecretDigestPrefixes = []string{
+		clientSecretPrefixPBKDF2SHA1, clientSecretPrefixPBKDF2SHA256, clientSecretPrefixPBKDF2SHA512,
+		clientSecretPrefixBCrypt2A, clientSecretPrefixBCrypt2B, clientSecretPrefixBCrypt2Y,
+	}
+)
@@ -1,7 +1,7 @@
 package oidc
 
 // NewOpenIDConnectWellKnownConfiguration generates a new OpenIDConnectWellKnownConfiguration.
-func NewOpenIDConnectWellKnownConfiguration(enablePKCEPlainChallenge, pairwise bool) (config OpenIDConnectWellKnownConfiguration) {
+func NewOpenIDConnectWellKnownConfiguration(enablePKCEPlainChallenge, pairwise bool, customScopes []string) (config OpenIDConnectWellKnownConfiguration) {
 	config = OpenIDConnectWellKnownConfiguration{
 		CommonDiscoveryOptions: CommonDiscoveryOptions{
 			SubjectTypesSupported: []string{
@@ -29,6 +29,8 @@ func NewOpenIDConnectWellKnownConfiguration(enablePKCEPlainChallenge, pairwise b
 				ScopeGroups,
 				ScopeEmail,
 			},
+			TokenEndpointAuthMethodsSupported:          ClientAuthMethodsSupported,
+			TokenEndpointAuthSigningAlgValuesSupported: ClientSecretJWTSigningAlgorithms,
 			ClaimsSupported: []string{
 				"amr",
 				"aud",
@@ -52,10 +54,15 @@ func NewOpenIDConnectWellKnownConfiguration(enablePKCEPlainChallenge, pairwise b
 		},
 		OAuth2DiscoveryOptions: OAuth2DiscoveryOptions{
 			CodeChallengeMethodsSupported: []string{
-				"S256",
+				PKCEChallengeMethodSHA256,
 			},
+			IntrospectionEndpointAuthMethodsSupported:          ClientAuthMethodsSupported,
+			IntrospectionEndpointAuthSigningAlgValuesSupported: ClientSecretJWTSigningAlgorithms,
+			RevocationEndpointAuthMethodsSupported:             ClientAuthMethodsSupported,
+			RevocationEndpointAuthSigningAlgValuesSupported:    ClientSecretJWTSigningAlgorithms,
 		},
 		OpenIDConnectDiscoveryOptions: OpenIDConnectDiscoveryOptions{
+			ClaimsParameterSupported: true,
 			IDTokenSigningAlgValuesSupported: []string{
 				"RS256",
 			},
@@ -75,8 +82,10 @@ func NewOpenIDConnectWellKnownConfiguration(enablePKCEPlainChallenge, pairwise b
 	}
 
 	if enablePKCEPlainChallenge {
-		config.CodeChallengeMethodsSupported = append(config.CodeChallengeMethodsSupported, "plain")
+		config.CodeChallengeMethodsSupported = append(config.CodeChallengeMethodsSupported, PKCEChallengeMethodPlain)
 	}
 
+	config.ScopesSupported = append(config.ScopesSupported, customScopes...)
+
 	return config
 }
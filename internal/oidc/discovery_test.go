@@ -44,7 +44,7 @@ func TestNewOpenIDConnectWellKnownConfiguration(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
-			actual := NewOpenIDConnectWellKnownConfiguration(tc.pkcePlainChallenge, tc.pairwise)
+			actual := NewOpenIDConnectWellKnownConfiguration(tc.pkcePlainChallenge, tc.pairwise, nil)
 			for _, codeChallengeMethod := range tc.expectCodeChallengeMethodsSupported {
 				assert.Contains(t, actual.CodeChallengeMethodsSupported, codeChallengeMethod)
 			}
@@ -63,3 +63,24 @@ func TestNewOpenIDConnectWellKnownConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestNewOpenIDConnectWellKnownConfiguration_ShouldIncludeCustomScopes(t *testing.T) {
+	actual := NewOpenIDConnectWellKnownConfiguration(false, false, []string{"company:hr"})
+
+	assert.Contains(t, actual.ScopesSupported, ScopeOpenID)
+	assert.Contains(t, actual.ScopesSupported, "company:hr")
+}
+
+func TestNewOpenIDConnectWellKnownConfiguration_ShouldIncludeClientAuthMethods(t *testing.T) {
+	disco := NewOpenIDConnectWellKnownConfiguration(false, false, nil)
+
+	expectedMethods := []string{"client_secret_basic", "client_secret_post", "client_secret_jwt", "none"}
+	expectedAlgs := []string{"HS256", "HS384", "HS512"}
+
+	assert.Equal(t, expectedMethods, disco.TokenEndpointAuthMethodsSupported)
+	assert.Equal(t, expectedAlgs, disco.TokenEndpointAuthSigningAlgValuesSupported)
+	assert.Equal(t, expectedMethods, disco.IntrospectionEndpointAuthMethodsSupported)
+	assert.Equal(t, expectedAlgs, disco.IntrospectionEndpointAuthSigningAlgValuesSupported)
+	assert.Equal(t, expectedMethods, disco.RevocationEndpointAuthMethodsSupported)
+	assert.Equal(t, expectedAlgs, disco.RevocationEndpointAuthSigningAlgValuesSupported)
+}
@@ -1,5 +1,32 @@
 package oidc
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ory/fosite"
+)
 
 var errPasswordsDoNotMatch = errors.New("the passwords don't match")
+
+// ErrInvalidTarget is the RFC8707 error returned when a requested resource is invalid or not permitted for the client.
+var ErrInvalidTarget = &fosite.RFC6749Error{
+	ErrorField:       "invalid_target",
+	DescriptionField: "The requested resource is invalid, missing, unknown, or malformed.",
+	CodeField:        http.StatusBadRequest,
+}
+
+// ErrRequestEntityTooLarge is the error returned when the body of a request to the token endpoint exceeds the
+// configured size.
+var ErrRequestEntityTooLarge = &fosite.RFC6749Error{
+	ErrorField:       "invalid_request",
+	DescriptionField: "The request body is too large.",
+	CodeField:        http.StatusRequestEntityTooLarge,
+}
+
+// ErrRateLimitExceeded is the error returned when a request to the token endpoint exceeds the configured rate limit.
+var ErrRateLimitExceeded = &fosite.RFC6749Error{
+	ErrorField:       "temporarily_unavailable",
+	DescriptionField: "The server is temporarily unable to handle the request as the rate limit has been exceeded.",
+	CodeField:        http.StatusTooManyRequests,
+}
@@ -0,0 +1,46 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+)
+
+// formPostScript is the script which submits the form post authorization response. It's included in the
+// Content-Security-Policy by its hash so the response doesn't require inline scripts or event handlers to be allowed.
+const formPostScript = `document.forms[0].submit();`
+
+// FormPostHTMLTemplate is the template of the authorization responses of requests with the form_post response mode. The
+// form is submitted automatically when scripts are enabled and with the button otherwise.
+var FormPostHTMLTemplate = template.Must(template.New("form_post").Parse(`<!DOCTYPE html>
+<html lang="en">
+	<head>
+		<meta charset="utf-8">
+		<title>Submit This Form</title>
+	</head>
+	<body>
+		<form method="post" action="{{ .RedirURL }}">
+			{{- range $key, $value := .Parameters }}
+			{{- range $parameter := $value }}
+			<input type="hidden" name="{{ $key }}" value="{{ $parameter }}"/>
+			{{- end }}
+			{{- end }}
+			<noscript><button type="submit">Continue</button></noscript>
+		</form>
+		<script>` + formPostScript + `</script>
+	</body>
+</html>
+`))
+
+// FormPostContentSecurityPolicy is the Content-Security-Policy of the authorization responses of requests with the
+// form_post response mode. It only allows the script which submits the form, and doesn't restrict the form action as
+// the form is posted to the redirect URI of the client.
+var FormPostContentSecurityPolicy = fmt.Sprintf("default-src 'none'; script-src 'sha256-%s'; base-uri 'none'; frame-ancestors 'none'",
+	formPostScriptHash())
+
+func formPostScriptHash() string {
+	sum := sha256.Sum256([]byte(formPostScript))
+
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,46 @@
+package oidc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormPostHTMLTemplate_ShouldRenderSelfSubmittingForm(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	fosite.WriteAuthorizeFormPostResponse("https://example.com/callback?a=b", url.Values{
+		"code":  []string{"abc"},
+		"state": []string{`"><script>alert(1)</script>`},
+	}, FormPostHTMLTemplate, buf)
+
+	body := buf.String()
+
+	assert.Contains(t, body, `<form method="post" action="https://example.com/callback?a=b">`)
+	assert.Contains(t, body, `<input type="hidden" name="code" value="abc"/>`)
+	assert.Contains(t, body, `<input type="hidden" name="state" value="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;"/>`)
+	assert.Contains(t, body, `<noscript><button type="submit">Continue</button></noscript>`)
+	assert.NotContains(t, body, "onload")
+	assert.NotContains(t, body, "javascript:")
+}
+
+func TestFormPostContentSecurityPolicy_ShouldAllowOnlyTheRenderedScript(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	fosite.WriteAuthorizeFormPostResponse("https://example.com/callback", url.Values{"code": []string{"abc"}}, FormPostHTMLTemplate, buf)
+
+	scripts := regexp.MustCompile(`(?s)<script>(.*?)</script>`).FindAllStringSubmatch(buf.String(), -1)
+
+	require.Len(t, scripts, 1)
+
+	sum := sha256.Sum256([]byte(scripts[0][1]))
+
+	assert.Equal(t, "default-src 'none'; script-src 'sha256-"+base64.StdEncoding.EncodeToString(sum[:])+"'; base-uri 'none'; frame-ancestors 'none'", FormPostContentSecurityPolicy)
+}
@@ -2,19 +2,162 @@ package oidc
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // Required for compatibility with pbkdf2 digests generated by other tools.
+	"crypto/sha256"
+	"crypto/sha512"
 	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
 )
 
-// Compare compares the hash with the data and returns an error if they don't match.
-func (h PlainTextHasher) Compare(_ context.Context, hash, data []byte) (err error) {
-	if subtle.ConstantTimeCompare(hash, data) == 0 {
+// Compare compares the client secret digest with the secret and returns an error if they don't match.
+func (h ClientSecretHasher) Compare(_ context.Context, hash, data []byte) (err error) {
+	digest, err := ParseClientSecretDigest(string(hash))
+	if err != nil {
+		return err
+	}
+
+	if !digest.Match(data) {
 		return errPasswordsDoNotMatch
 	}
 
 	return nil
 }
 
-// Hash creates a new hash from data.
-func (h PlainTextHasher) Hash(_ context.Context, data []byte) (hash []byte, err error) {
-	return data, nil
+// Hash creates a new pbkdf2-sha512 digest of the client secret.
+func (h ClientSecretHasher) Hash(_ context.Context, data []byte) (hash []byte, err error) {
+	digest, err := HashClientSecret(string(data), ClientSecretDefaultIterations)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(digest), nil
+}
+
+// HashClientSecret returns the pbkdf2-sha512 digest of a client secret with a random salt in the modular crypt format
+// used by passlib.
+func HashClientSecret(secret string, iterations int) (digest string, err error) {
+	if iterations < 1 {
+		return "", fmt.Errorf("iterations must be more than 0 but it is %d", iterations)
+	}
+
+	salt := make([]byte, clientSecretSaltLength)
+
+	if _, err = rand.Read(salt); err != nil {
+		return "", fmt.Errorf("error generating the salt: %w", err)
+	}
+
+	key := pbkdf2.Key([]byte(secret), salt, iterations, sha512.Size, sha512.New)
+
+	return fmt.Sprintf("%s%d$%s$%s", clientSecretPrefixPBKDF2SHA512, iterations, encodeAB64(salt), encodeAB64(key)), nil
+}
+
+// IsClientSecretDigest returns true if the client secret is a digest rather than a plaintext secret.
+func IsClientSecretDigest(secret string) bool {
+	for _, prefix := range clientSecretDigestPrefixes {
+		if strings.HasPrefix(secret, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseClientSecretDigest parses a client secret digest. Secrets which are not digests are treated as plaintext.
+func ParseClientSecretDigest(secret string) (digest ClientSecretDigest, err error) {
+	switch {
+	case strings.HasPrefix(secret, clientSecretPrefixPlainText):
+		return plainTextClientSecretDigest(secret[len(clientSecretPrefixPlainText):]), nil
+	case strings.HasPrefix(secret, clientSecretPrefixPBKDF2SHA512):
+		return parsePBKDF2ClientSecretDigest(secret[len(clientSecretPrefixPBKDF2SHA512):], sha512.New)
+	case strings.HasPrefix(secret, clientSecretPrefixPBKDF2SHA256):
+		return parsePBKDF2ClientSecretDigest(secret[len(clientSecretPrefixPBKDF2SHA256):], sha256.New)
+	case strings.HasPrefix(secret, clientSecretPrefixPBKDF2SHA1):
+		return parsePBKDF2ClientSecretDigest(secret[len(clientSecretPrefixPBKDF2SHA1):], sha1.New)
+	case isBCryptClientSecretDigest(secret):
+		if _, err = bcrypt.Cost([]byte(secret)); err != nil {
+			return nil, fmt.Errorf("the bcrypt digest is invalid: %w", err)
+		}
+
+		return bcryptClientSecretDigest(secret), nil
+	default:
+		return plainTextClientSecretDigest(secret), nil
+	}
+}
+
+func isBCryptClientSecretDigest(secret string) bool {
+	for _, prefix := range clientSecretPrefixesBCrypt {
+		if strings.HasPrefix(secret, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parsePBKDF2ClientSecretDigest(value string, hash func() hash.Hash) (digest ClientSecretDigest, err error) {
+	parts := strings.Split(value, "$")
+
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("the pbkdf2 digest must have 3 parts after the algorithm but it has %d", len(parts))
+	}
+
+	d := pbkdf2ClientSecretDigest{hash: hash}
+
+	if d.iterations, err = strconv.Atoi(parts[0]); err != nil || d.iterations < 1 {
+		return nil, fmt.Errorf("the pbkdf2 digest iterations must be a number more than 0 but it is '%s'", parts[0])
+	}
+
+	if d.salt, err = decodeAB64(parts[1]); err != nil {
+		return nil, fmt.Errorf("the pbkdf2 digest salt is invalid: %w", err)
+	}
+
+	if d.key, err = decodeAB64(parts[2]); err != nil || len(d.key) == 0 {
+		return nil, fmt.Errorf("the pbkdf2 digest key is invalid")
+	}
+
+	return d, nil
+}
+
+type plainTextClientSecretDigest string
+
+// Match returns true if the secret matches the plaintext secret.
+func (d plainTextClientSecretDigest) Match(secret []byte) bool {
+	return subtle.ConstantTimeCompare([]byte(d), secret) == 1
+}
+
+type bcryptClientSecretDigest string
+
+// Match returns true if the secret matches the bcrypt digest.
+func (d bcryptClientSecretDigest) Match(secret []byte) bool {
+	return bcrypt.CompareHashAndPassword([]byte(d), secret) == nil
+}
+
+type pbkdf2ClientSecretDigest struct {
+	hash       func() hash.Hash
+	iterations int
+	salt       []byte
+	key        []byte
+}
+
+// Match returns true if the secret matches the pbkdf2 digest.
+func (d pbkdf2ClientSecretDigest) Match(secret []byte) bool {
+	return subtle.ConstantTimeCompare(pbkdf2.Key(secret, d.salt, d.iterations, len(d.key), d.hash), d.key) == 1
+}
+
+// encodeAB64 encodes the salt and key of pbkdf2 digests with the adapted base64 encoding of passlib, which is the
+// standard encoding without padding and with '.' instead of '+'.
+func encodeAB64(data []byte) string {
+	return strings.ReplaceAll(base64.RawStdEncoding.EncodeToString(data), "+", ".")
+}
+
+func decodeAB64(data string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(strings.ReplaceAll(data, ".", "+"))
 }
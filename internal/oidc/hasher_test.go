@@ -2,13 +2,15 @@ package oidc
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestShouldNotRaiseErrorOnEqualPasswordsPlainText(t *testing.T) {
-	hasher := PlainTextHasher{}
+	hasher := ClientSecretHasher{}
 
 	a := []byte("abc")
 	b := []byte("abc")
@@ -21,7 +23,7 @@ func TestShouldNotRaiseErrorOnEqualPasswordsPlainText(t *testing.T) {
 }
 
 func TestShouldRaiseErrorOnNonEqualPasswordsPlainText(t *testing.T) {
-	hasher := PlainTextHasher{}
+	hasher := ClientSecretHasher{}
 
 	a := []byte("abc")
 	b := []byte("abcd")
@@ -34,7 +36,7 @@ func TestShouldRaiseErrorOnNonEqualPasswordsPlainText(t *testing.T) {
 }
 
 func TestShouldHashPassword(t *testing.T) {
-	hasher := PlainTextHasher{}
+	hasher := ClientSecretHasher{}
 
 	data := []byte("abc")
 
@@ -42,6 +44,64 @@ func TestShouldHashPassword(t *testing.T) {
 
 	hash, err := hasher.Hash(ctx, data)
 
-	assert.NoError(t, err)
-	assert.Equal(t, data, hash)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(hash), "$pbkdf2-sha512$310000$"))
+	assert.True(t, IsClientSecretDigest(string(hash)))
+
+	assert.NoError(t, hasher.Compare(ctx, hash, data))
+	assert.Equal(t, errPasswordsDoNotMatch, hasher.Compare(ctx, hash, []byte("abcd")))
+}
+
+func TestShouldCompareClientSecretDigests(t *testing.T) {
+	testCases := []struct {
+		name   string
+		digest string
+	}{
+		{"PlainText", "client-secret"},
+		{"PlainTextPrefixed", "$plaintext$client-secret"},
+		{"PBKDF2SHA1", "$pbkdf2$1000$MDEyMzQ1Njc4OWFiY2RlZg$p68UpxG08RXmCOach0PpRz3ZT9w"},
+		{"PBKDF2SHA256", "$pbkdf2-sha256$1000$MDEyMzQ1Njc4OWFiY2RlZg$GUj6Bz761nDTSaPph.GxgdOzPrYWTQBqmptLm2M8UCU"},
+		{"BCrypt", "$2a$04$QAxfPQFJvohn.7hr8nKpT.xJCZeOVN21PQT6IG/WLw/eZVIJRyF8q"},
+	}
+
+	hasher := ClientSecretHasher{}
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.NoError(t, hasher.Compare(ctx, []byte(tc.digest), []byte("client-secret")))
+			assert.Equal(t, errPasswordsDoNotMatch, hasher.Compare(ctx, []byte(tc.digest), []byte("client-secret2")))
+
+			if IsClientSecretDigest(tc.digest) {
+				assert.Equal(t, errPasswordsDoNotMatch, hasher.Compare(ctx, []byte(tc.digest), []byte(tc.digest)))
+			}
+		})
+	}
+}
+
+func TestShouldRaiseErrorOnInvalidClientSecretDigests(t *testing.T) {
+	testCases := []struct {
+		name, digest, expected string
+	}{
+		{"MissingParts", "$pbkdf2-sha512$1000$abc", "the pbkdf2 digest must have 3 parts after the algorithm but it has 2"},
+		{"InvalidIterations", "$pbkdf2-sha512$0$abc$abc", "the pbkdf2 digest iterations must be a number more than 0 but it is '0'"},
+		{"InvalidSalt", "$pbkdf2-sha512$1000$a$abc", "the pbkdf2 digest salt is invalid: illegal base64 data at input byte 0"},
+		{"InvalidKey", "$pbkdf2-sha512$1000$abc$", "the pbkdf2 digest key is invalid"},
+		{"InvalidBCryptCost", "$2b$99$QAxfPQFJvohn.7hr8nKpT.xJCZeOVN21PQT6IG/WLw/eZVIJRyF8q", "the bcrypt digest is invalid: crypto/bcrypt: cost 99 is outside allowed range (4,31)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseClientSecretDigest(tc.digest)
+
+			assert.EqualError(t, err, tc.expected)
+			assert.Error(t, ClientSecretHasher{}.Compare(context.Background(), []byte(tc.digest), []byte("client-secret")))
+		})
+	}
+}
+
+func TestShouldNotHashClientSecretWithoutIterations(t *testing.T) {
+	_, err := HashClientSecret("client-secret", 0)
+
+	assert.EqualError(t, err, "iterations must be more than 0 but it is 0")
 }
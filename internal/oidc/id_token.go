@@ -0,0 +1,45 @@
+package oidc
+
+import (
+	"context"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/openid"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewIDTokenStrategy returns a new IDTokenStrategy which generates the ID Tokens with the provided openid.DefaultStrategy.
+func NewIDTokenStrategy(strategy *openid.DefaultStrategy, clock utils.Clock, clockSkew time.Duration) *IDTokenStrategy {
+	return &IDTokenStrategy{
+		DefaultStrategy: strategy,
+		clock:           clock,
+		clockSkew:       clockSkew,
+	}
+}
+
+// IDTokenStrategy is a decorator struct for the fosite openid.DefaultStrategy which includes the nbf claim in the ID
+// Tokens. The nbf claim is backdated by the clock skew so relying parties with a clock behind the clock of the server
+// accept the ID Tokens.
+type IDTokenStrategy struct {
+	*openid.DefaultStrategy
+
+	clock     utils.Clock
+	clockSkew time.Duration
+}
+
+// GenerateIDToken is a decorator func for the underlying fosite openid.DefaultStrategy.
+func (s *IDTokenStrategy) GenerateIDToken(ctx context.Context, requester fosite.Requester) (token string, err error) {
+	if session, ok := requester.GetSession().(openid.Session); ok && session.IDTokenClaims() != nil {
+		claims := session.IDTokenClaims()
+
+		if claims.Extra == nil {
+			claims.Extra = map[string]interface{}{}
+		}
+
+		claims.Extra[ClaimNotBefore] = s.clock.Now().UTC().Add(-s.clockSkew).Unix()
+	}
+
+	return s.DefaultStrategy.GenerateIDToken(ctx, requester)
+}
@@ -0,0 +1,47 @@
+package oidc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/openid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestIDTokenStrategy_ShouldIncludeNotBeforeWithClockSkew(t *testing.T) {
+	manager, err := NewKeyManagerWithConfiguration(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: exampleIssuerPrivateKey,
+		ClockSkew:        time.Minute,
+	})
+	require.NoError(t, err)
+
+	clock := &testKeyClock{now: time.Unix(1700000000, 0)}
+
+	strategy := NewIDTokenStrategy(&openid.DefaultStrategy{
+		JWTStrategy: manager.Strategy(),
+		Expiry:      time.Hour,
+		Issuer:      "https://auth.example.com",
+	}, clock, time.Minute)
+
+	session := NewSession()
+	session.Claims.Subject = "john"
+
+	requester := fosite.NewAuthorizeRequest()
+	requester.Session = session
+
+	token, err := strategy.GenerateIDToken(context.Background(), requester)
+	require.NoError(t, err)
+
+	decoded, err := manager.Strategy().Decode(context.Background(), token)
+	require.NoError(t, err)
+
+	nbf, ok := decoded.Claims[ClaimNotBefore].(int64)
+	require.True(t, ok)
+
+	assert.Equal(t, clock.now.Add(-time.Minute).Unix(), nbf)
+}
@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ory/fosite/token/jwt"
 	"gopkg.in/square/go-jose.v2"
@@ -16,15 +18,36 @@ import (
 )
 
 // NewKeyManagerWithConfiguration when provided a schema.OpenIDConnectConfiguration creates a new KeyManager and adds an
-// initial key to the manager.
+// initial key to the manager, followed by any additional scheduled or verification only keys.
 func NewKeyManagerWithConfiguration(configuration *schema.OpenIDConnectConfiguration) (manager *KeyManager, err error) {
 	manager = NewKeyManager()
+	manager.gracePeriod = configuration.KeyRotationGracePeriod
+	manager.clockSkew = configuration.ClockSkew
 
-	_, _, err = manager.AddActivePrivateKeyData(configuration.IssuerPrivateKey)
+	key, err := utils.ParseRsaPrivateKeyFromPemStr(configuration.IssuerPrivateKey)
 	if err != nil {
 		return nil, err
 	}
 
+	// The issuer private key is the active key until the first scheduled key is activated.
+	if _, err = manager.addPrivateKey(key, managedKey{signing: true}); err != nil {
+		return nil, err
+	}
+
+	for i, config := range configuration.IssuerPrivateKeys {
+		if key, err = utils.ParseRsaPrivateKeyFromPemStr(config.Key); err != nil {
+			return nil, fmt.Errorf("issuer private key #%d: %w", i+1, err)
+		}
+
+		if _, err = manager.addPrivateKey(key, managedKey{
+			signing:    !config.ActivateAt.IsZero(),
+			activateAt: config.ActivateAt,
+			expiresAt:  config.ExpiresAt,
+		}); err != nil {
+			return nil, fmt.Errorf("issuer private key #%d: %w", i+1, err)
+		}
+	}
+
 	return manager, nil
 }
 
@@ -33,22 +56,50 @@ func NewKeyManager() (manager *KeyManager) {
 	manager = new(KeyManager)
 	manager.keys = map[string]*rsa.PrivateKey{}
 	manager.keySet = new(jose.JSONWebKeySet)
+	manager.clock = &utils.RealClock{}
 
 	return manager
 }
 
 // Strategy returns the RS256JWTStrategy.
-func (m KeyManager) Strategy() (strategy *RS256JWTStrategy) {
+func (m *KeyManager) Strategy() (strategy *RS256JWTStrategy) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.rotate()
+
 	return m.strategy
 }
 
-// GetKeySet returns the joseJSONWebKeySet containing the rsa.PublicKey types.
-func (m KeyManager) GetKeySet() (keySet *jose.JSONWebKeySet) {
-	return m.keySet
+// GetKeySet returns the joseJSONWebKeySet containing the rsa.PublicKey types of the keys which are currently
+// published. This includes the active key, keys scheduled to become active, and superseded keys which are still within
+// the grace period.
+func (m *KeyManager) GetKeySet() (keySet *jose.JSONWebKeySet) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.rotate()
+
+	now := m.clock.Now()
+
+	keySet = &jose.JSONWebKeySet{Keys: make([]jose.JSONWebKey, 0, len(m.keySet.Keys))}
+
+	for _, wk := range m.keySet.Keys {
+		if m.isPublished(wk.KeyID, now) {
+			keySet.Keys = append(keySet.Keys, wk)
+		}
+	}
+
+	return keySet
 }
 
 // GetActiveWebKey obtains the currently active jose.JSONWebKey.
-func (m KeyManager) GetActiveWebKey() (webKey *jose.JSONWebKey, err error) {
+func (m *KeyManager) GetActiveWebKey() (webKey *jose.JSONWebKey, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.rotate()
+
 	webKeys := m.keySet.Key(m.activeKeyID)
 	if len(webKeys) == 1 {
 		return &webKeys[0], nil
@@ -62,12 +113,22 @@ func (m KeyManager) GetActiveWebKey() (webKey *jose.JSONWebKey, err error) {
 }
 
 // GetActiveKeyID returns the key id of the currently active key.
-func (m KeyManager) GetActiveKeyID() (keyID string) {
+func (m *KeyManager) GetActiveKeyID() (keyID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.rotate()
+
 	return m.activeKeyID
 }
 
 // GetActiveKey returns the rsa.PublicKey of the currently active key.
-func (m KeyManager) GetActiveKey() (key *rsa.PublicKey, err error) {
+func (m *KeyManager) GetActiveKey() (key *rsa.PublicKey, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.rotate()
+
 	if key, ok := m.keys[m.activeKeyID]; ok {
 		return &key.PublicKey, nil
 	}
@@ -76,7 +137,12 @@ func (m KeyManager) GetActiveKey() (key *rsa.PublicKey, err error) {
 }
 
 // GetActivePrivateKey returns the rsa.PrivateKey of the currently active key.
-func (m KeyManager) GetActivePrivateKey() (key *rsa.PrivateKey, err error) {
+func (m *KeyManager) GetActivePrivateKey() (key *rsa.PrivateKey, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.rotate()
+
 	if key, ok := m.keys[m.activeKeyID]; ok {
 		return key, nil
 	}
@@ -96,8 +162,16 @@ func (m *KeyManager) AddActivePrivateKeyData(data string) (key *rsa.PrivateKey,
 	return key, webKey, err
 }
 
-// AddActivePrivateKey adds a rsa.PublicKey, then sets it to the active key.
+// AddActivePrivateKey adds a rsa.PublicKey, then sets it to the active key. The previously active key is published
+// for the grace period.
 func (m *KeyManager) AddActivePrivateKey(key *rsa.PrivateKey) (webKey *jose.JSONWebKey, err error) {
+	return m.addPrivateKey(key, managedKey{signing: true, activateAt: m.clock.Now()})
+}
+
+func (m *KeyManager) addPrivateKey(key *rsa.PrivateKey, managed managedKey) (webKey *jose.JSONWebKey, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	wk := jose.JSONWebKey{
 		Key:       &key.PublicKey,
 		Algorithm: "RS256",
@@ -119,24 +193,117 @@ func (m *KeyManager) AddActivePrivateKey(key *rsa.PrivateKey) (webKey *jose.JSON
 		return nil, fmt.Errorf("key id %s already exists", strKeyID)
 	}
 
-	// TODO: Add Mutex here when implementing key rotation.
 	wk.KeyID = strKeyID
+	managed.id = strKeyID
+
 	m.keySet.Keys = append(m.keySet.Keys, wk)
 	m.keys[strKeyID] = key
-	m.activeKeyID = strKeyID
+	m.managed = append(m.managed, managed)
 
-	m.strategy, err = NewRS256JWTStrategy(wk.KeyID, key)
-	if err != nil {
-		return &wk, err
+	if m.strategy == nil {
+		if m.strategy, err = NewRS256JWTStrategy(wk.KeyID, key); err != nil {
+			return &wk, err
+		}
+
+		m.strategy.manager = m
 	}
 
+	m.rotate()
+
 	return &wk, nil
 }
 
+// getPublishedPrivateKey returns the rsa.PrivateKey with the given key id if it's currently published.
+func (m *KeyManager) getPublishedPrivateKey(keyID string) (key *rsa.PrivateKey, ok bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.rotate()
+
+	if !m.isPublished(keyID, m.clock.Now()) {
+		return nil, false
+	}
+
+	key, ok = m.keys[keyID]
+
+	return key, ok
+}
+
+// rotate sets the active key to the signing key with the latest activation which is not in the future. The caller
+// must hold the mutex.
+func (m *KeyManager) rotate() {
+	now := m.clock.Now()
+
+	var (
+		active  *managedKey
+		keyID   string
+		current time.Time
+	)
+
+	for i, managed := range m.managed {
+		if !managed.signing || managed.activateAt.After(now) {
+			continue
+		}
+
+		if active == nil || !managed.activateAt.Before(current) {
+			active, current, keyID = &m.managed[i], managed.activateAt, managed.id
+		}
+	}
+
+	if active == nil || keyID == m.activeKeyID {
+		return
+	}
+
+	m.activeKeyID = keyID
+
+	if m.strategy != nil {
+		m.strategy.SetKey(keyID, m.keys[keyID])
+	}
+}
+
+// isPublished returns true if the key with the given key id should be included in the key set. The caller must hold
+// the mutex.
+func (m *KeyManager) isPublished(keyID string, now time.Time) bool {
+	for _, managed := range m.managed {
+		if managed.id != keyID {
+			continue
+		}
+
+		if !managed.signing {
+			return managed.expiresAt.IsZero() || now.Before(managed.expiresAt)
+		}
+
+		if keyID == m.activeKeyID || managed.activateAt.After(now) {
+			return true
+		}
+
+		superseded, ok := m.supersededAt(managed, now)
+
+		return !ok || now.Before(superseded.Add(m.gracePeriod))
+	}
+
+	return false
+}
+
+// supersededAt returns the time the given signing key was superseded by the next signing key if that has happened.
+// The caller must hold the mutex.
+func (m *KeyManager) supersededAt(key managedKey, now time.Time) (superseded time.Time, ok bool) {
+	for _, managed := range m.managed {
+		if !managed.signing || managed.id == key.id || managed.activateAt.Before(key.activateAt) || managed.activateAt.After(now) {
+			continue
+		}
+
+		if !ok || managed.activateAt.Before(superseded) {
+			superseded, ok = managed.activateAt, true
+		}
+	}
+
+	return superseded, ok
+}
+
 // NewRS256JWTStrategy returns a new RS256JWTStrategy.
 func NewRS256JWTStrategy(id string, key *rsa.PrivateKey) (strategy *RS256JWTStrategy, err error) {
 	strategy = new(RS256JWTStrategy)
-	strategy.JWTStrategy = new(jwt.RS256JWTStrategy)
 
 	strategy.SetKey(id, key)
 
@@ -147,51 +314,158 @@ func NewRS256JWTStrategy(id string, key *rsa.PrivateKey) (strategy *RS256JWTStra
 type RS256JWTStrategy struct {
 	JWTStrategy *jwt.RS256JWTStrategy
 
-	keyID string
+	keyID   string
+	manager *KeyManager
+
+	mutex sync.RWMutex
 }
 
 // KeyID returns the key id.
-func (s RS256JWTStrategy) KeyID() (id string) {
-	return s.keyID
+func (s *RS256JWTStrategy) KeyID() (id string) {
+	id, _ = s.current()
+
+	return id
 }
 
 // SetKey sets the provided key id and key as the active key (this is what triggers fosite to use it).
 func (s *RS256JWTStrategy) SetKey(id string, key *rsa.PrivateKey) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.keyID = id
-	s.JWTStrategy.PrivateKey = key
+	s.JWTStrategy = &jwt.RS256JWTStrategy{PrivateKey: key}
+}
+
+// current rotates the key if this strategy belongs to a KeyManager and returns the active key id and strategy.
+func (s *RS256JWTStrategy) current() (id string, strategy *jwt.RS256JWTStrategy) {
+	if s.manager != nil {
+		s.manager.mutex.Lock()
+		s.manager.rotate()
+		s.manager.mutex.Unlock()
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.keyID, s.JWTStrategy
+}
+
+// strategyForToken returns the strategy for the published key which signed the token, falling back to the active
+// key when the token was signed by an unknown key.
+func (s *RS256JWTStrategy) strategyForToken(token string) (strategy *jwt.RS256JWTStrategy) {
+	id, strategy := s.current()
+
+	if s.manager == nil {
+		return strategy
+	}
+
+	signed, err := jose.ParseSigned(token)
+	if err != nil || len(signed.Signatures) == 0 || signed.Signatures[0].Header.KeyID == id {
+		return strategy
+	}
+
+	if key, ok := s.manager.getPublishedPrivateKey(signed.Signatures[0].Header.KeyID); ok {
+		return &jwt.RS256JWTStrategy{PrivateKey: key}
+	}
+
+	return strategy
 }
 
 // Hash is a decorator func for the underlying fosite RS256JWTStrategy.
 func (s *RS256JWTStrategy) Hash(ctx context.Context, in []byte) ([]byte, error) {
-	return s.JWTStrategy.Hash(ctx, in)
+	_, strategy := s.current()
+
+	return strategy.Hash(ctx, in)
 }
 
 // GetSigningMethodLength is a decorator func for the underlying fosite RS256JWTStrategy.
 func (s *RS256JWTStrategy) GetSigningMethodLength() int {
-	return s.JWTStrategy.GetSigningMethodLength()
+	_, strategy := s.current()
+
+	return strategy.GetSigningMethodLength()
 }
 
 // GetSignature is a decorator func for the underlying fosite RS256JWTStrategy.
 func (s *RS256JWTStrategy) GetSignature(ctx context.Context, token string) (string, error) {
-	return s.JWTStrategy.GetSignature(ctx, token)
+	_, strategy := s.current()
+
+	return strategy.GetSignature(ctx, token)
 }
 
-// Generate is a decorator func for the underlying fosite RS256JWTStrategy.
+// Generate is a decorator func for the underlying fosite RS256JWTStrategy. The kid header is always set to the id of
+// the key which signs the token as the active key may have been rotated since the header was created.
 func (s *RS256JWTStrategy) Generate(ctx context.Context, claims jwt.MapClaims, header jwt.Mapper) (string, string, error) {
-	return s.JWTStrategy.Generate(ctx, claims, header)
+	id, strategy := s.current()
+
+	if header != nil {
+		headers := &jwt.Headers{Extra: header.ToMap()}
+		headers.Add("kid", id)
+
+		header = headers
+	}
+
+	return strategy.Generate(ctx, claims, header)
 }
 
 // Validate is a decorator func for the underlying fosite RS256JWTStrategy.
 func (s *RS256JWTStrategy) Validate(ctx context.Context, token string) (string, error) {
-	return s.JWTStrategy.Validate(ctx, token)
+	return s.strategyForToken(token).Validate(ctx, token)
 }
 
-// Decode is a decorator func for the underlying fosite RS256JWTStrategy.
-func (s *RS256JWTStrategy) Decode(ctx context.Context, token string) (*jwt.Token, error) {
-	return s.JWTStrategy.Decode(ctx, token)
+// Decode is a decorator func for the underlying fosite RS256JWTStrategy. When the token is only rejected because of the
+// exp, iat, or nbf claims, these claims are validated again with the clock skew of the KeyManager.
+func (s *RS256JWTStrategy) Decode(ctx context.Context, token string) (decoded *jwt.Token, err error) {
+	decoded, err = s.strategyForToken(token).Decode(ctx, token)
+	if err == nil || decoded == nil || s.manager == nil || s.manager.clockSkew == 0 {
+		return decoded, err
+	}
+
+	var ve *jwt.ValidationError
+
+	if !errors.As(err, &ve) || ve.Errors&^validationErrorsTime != 0 {
+		return decoded, err
+	}
+
+	if err = validateTimeClaims(decoded.Claims, s.manager.clock.Now(), s.manager.clockSkew); err != nil {
+		return decoded, err
+	}
+
+	return decoded, nil
 }
 
 // GetPublicKeyID is a decorator func for the underlying fosite RS256JWTStrategy.
 func (s *RS256JWTStrategy) GetPublicKeyID(_ context.Context) (string, error) {
-	return s.keyID, nil
+	id, _ := s.current()
+
+	return id, nil
+}
+
+// validationErrorsTime are the jwt.ValidationError flags of the exp, iat, and nbf claims.
+const validationErrorsTime = jwt.ValidationErrorExpired | jwt.ValidationErrorIssuedAt | jwt.ValidationErrorNotValidYet
+
+// validateTimeClaims validates the exp, iat, and nbf claims like jwt.MapClaims.Valid while tolerating the provided
+// clock skew.
+func validateTimeClaims(claims jwt.MapClaims, now time.Time, clockSkew time.Duration) error {
+	ve := &jwt.ValidationError{}
+
+	if !claims.VerifyExpiresAt(now.Add(-clockSkew).Unix(), false) {
+		ve.Inner = errors.New("Token is expired")
+		ve.Errors |= jwt.ValidationErrorExpired
+	}
+
+	if !claims.VerifyIssuedAt(now.Add(clockSkew).Unix(), false) {
+		ve.Inner = errors.New("Token used before issued")
+		ve.Errors |= jwt.ValidationErrorIssuedAt
+	}
+
+	if !claims.VerifyNotBefore(now.Add(clockSkew).Unix(), false) {
+		ve.Inner = errors.New("Token is not valid yet")
+		ve.Errors |= jwt.ValidationErrorNotValidYet
+	}
+
+	if ve.Errors == 0 {
+		return nil
+	}
+
+	return ve
 }
@@ -1,13 +1,20 @@
 package oidc
 
 import (
+	"context"
 	"crypto"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/ory/fosite/token/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 func TestKeyManager_AddActiveKeyData(t *testing.T) {
@@ -51,3 +58,189 @@ func TestKeyManager_AddActiveKeyData(t *testing.T) {
 	assert.NotNil(t, keySet)
 	assert.Equal(t, kid, manager.GetActiveKeyID())
 }
+
+type testKeyClock struct {
+	now time.Time
+}
+
+func (c *testKeyClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testKeyClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func keyIDs(keySet *jose.JSONWebKeySet) (ids []string) {
+	for _, wk := range keySet.Keys {
+		ids = append(ids, wk.KeyID)
+	}
+
+	return ids
+}
+
+func TestKeyManager_ShouldRotateScheduledKeys(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	scheduled, _ := utils.GenerateRsaKeyPair(2048)
+	retired, _ := utils.GenerateRsaKeyPair(2048)
+
+	manager, err := NewKeyManagerWithConfiguration(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey:       exampleIssuerPrivateKey,
+		KeyRotationGracePeriod: time.Hour * 2,
+		IssuerPrivateKeys: []schema.OpenIDConnectIssuerPrivateKeyConfiguration{
+			{Key: utils.ExportRsaPrivateKeyAsPemStr(scheduled), ActivateAt: now.Add(time.Hour)},
+			{Key: utils.ExportRsaPrivateKeyAsPemStr(retired), ExpiresAt: now.Add(time.Minute * 30)},
+		},
+	})
+	require.NoError(t, err)
+
+	clock := &testKeyClock{now: now}
+	manager.clock = clock
+
+	require.Len(t, manager.keySet.Keys, 3)
+
+	initialID, scheduledID, retiredID := manager.keySet.Keys[0].KeyID, manager.keySet.Keys[1].KeyID, manager.keySet.Keys[2].KeyID
+
+	// The scheduled key is published before it's activated so relying parties can cache it ahead of time.
+	assert.Equal(t, initialID, manager.GetActiveKeyID())
+	assert.Equal(t, []string{initialID, scheduledID, retiredID}, keyIDs(manager.GetKeySet()))
+
+	strategy := manager.Strategy()
+
+	oldToken, _, err := strategy.Generate(context.Background(), jwt.MapClaims{"sub": "john"}, jwt.NewHeaders())
+	require.NoError(t, err)
+
+	decoded, err := strategy.Decode(context.Background(), oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, initialID, decoded.Header["kid"])
+
+	clock.now = now.Add(time.Minute * 45)
+
+	assert.Equal(t, []string{initialID, scheduledID}, keyIDs(manager.GetKeySet()))
+
+	clock.now = now.Add(time.Hour)
+
+	assert.Equal(t, scheduledID, manager.GetActiveKeyID())
+	assert.Equal(t, scheduledID, strategy.KeyID())
+	assert.Equal(t, []string{initialID, scheduledID}, keyIDs(manager.GetKeySet()))
+
+	headers := jwt.NewHeaders()
+	headers.Add("kid", initialID)
+
+	newToken, _, err := strategy.Generate(context.Background(), jwt.MapClaims{"sub": "john"}, headers)
+	require.NoError(t, err)
+
+	decoded, err = strategy.Decode(context.Background(), newToken)
+	require.NoError(t, err)
+	assert.Equal(t, scheduledID, decoded.Header["kid"])
+
+	// Tokens signed by the superseded key can still be verified during the grace period.
+	_, err = strategy.Validate(context.Background(), oldToken)
+	assert.NoError(t, err)
+
+	clock.now = now.Add(time.Hour * 3)
+
+	assert.Equal(t, []string{scheduledID}, keyIDs(manager.GetKeySet()))
+
+	_, err = strategy.Validate(context.Background(), oldToken)
+	assert.Error(t, err)
+
+	_, err = strategy.Validate(context.Background(), newToken)
+	assert.NoError(t, err)
+}
+
+func TestKeyManager_ShouldUseLatestPastScheduledKey(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	key, _ := utils.GenerateRsaKeyPair(2048)
+
+	manager, err := NewKeyManagerWithConfiguration(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey:       exampleIssuerPrivateKey,
+		KeyRotationGracePeriod: time.Hour,
+		IssuerPrivateKeys: []schema.OpenIDConnectIssuerPrivateKeyConfiguration{
+			{Key: utils.ExportRsaPrivateKeyAsPemStr(key), ActivateAt: now.Add(-time.Hour * 2)},
+		},
+	})
+	require.NoError(t, err)
+
+	manager.clock = &testKeyClock{now: now}
+
+	assert.Equal(t, manager.keySet.Keys[1].KeyID, manager.GetActiveKeyID())
+	assert.Equal(t, []string{manager.keySet.Keys[1].KeyID}, keyIDs(manager.GetKeySet()))
+
+	privateKey, err := manager.GetActivePrivateKey()
+	require.NoError(t, err)
+	assert.Equal(t, key, privateKey)
+}
+
+func TestKeyManager_ShouldErrorOnInvalidAdditionalKey(t *testing.T) {
+	manager, err := NewKeyManagerWithConfiguration(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: exampleIssuerPrivateKey,
+		IssuerPrivateKeys: []schema.OpenIDConnectIssuerPrivateKeyConfiguration{
+			{Key: exampleIssuerPrivateKey},
+		},
+	})
+
+	assert.Nil(t, manager)
+	assert.Regexp(t, "^issuer private key #1: key id [a-f0-9]{6} already exists$", err.Error())
+
+	manager, err = NewKeyManagerWithConfiguration(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: exampleIssuerPrivateKey,
+		IssuerPrivateKeys: []schema.OpenIDConnectIssuerPrivateKeyConfiguration{
+			{Key: "abc"},
+		},
+	})
+
+	assert.Nil(t, manager)
+	assert.EqualError(t, err, "issuer private key #1: failed to parse PEM block containing the key")
+}
+
+func TestRS256JWTStrategy_ShouldDecodeWithClockSkew(t *testing.T) {
+	manager, err := NewKeyManagerWithConfiguration(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: exampleIssuerPrivateKey,
+		ClockSkew:        time.Minute,
+	})
+	require.NoError(t, err)
+
+	strategy := manager.Strategy()
+
+	now := time.Now()
+
+	testCases := []struct {
+		name   string
+		claims jwt.MapClaims
+		err    string
+	}{
+		{"ShouldDecodeIssuedInTheFutureWithinClockSkew", jwt.MapClaims{"sub": "john", "iat": now.Add(time.Second * 30).Unix()}, ""},
+		{"ShouldDecodeNotValidYetWithinClockSkew", jwt.MapClaims{"sub": "john", "nbf": now.Add(time.Second * 30).Unix()}, ""},
+		{"ShouldDecodeExpiredWithinClockSkew", jwt.MapClaims{"sub": "john", "exp": now.Add(-time.Second * 30).Unix()}, ""},
+		{"ShouldNotDecodeIssuedInTheFutureBeyondClockSkew", jwt.MapClaims{"sub": "john", "iat": now.Add(time.Minute * 2).Unix()}, "Token used before issued"},
+		{"ShouldNotDecodeNotValidYetBeyondClockSkew", jwt.MapClaims{"sub": "john", "nbf": now.Add(time.Minute * 2).Unix()}, "Token is not valid yet"},
+		{"ShouldNotDecodeExpiredBeyondClockSkew", jwt.MapClaims{"sub": "john", "exp": now.Add(-time.Minute * 2).Unix()}, "Token is expired"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			token, _, err := strategy.Generate(context.Background(), tc.claims, &jwt.Headers{})
+			require.NoError(t, err)
+
+			decoded, err := strategy.Decode(context.Background(), token)
+			require.NotNil(t, decoded)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, "john", decoded.Claims["sub"])
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+
+	// The clock skew doesn't apply when the signature is invalid.
+	token, _, err := strategy.Generate(context.Background(), jwt.MapClaims{"sub": "john", "iat": now.Add(time.Second * 30).Unix()}, &jwt.Headers{})
+	require.NoError(t, err)
+
+	_, err = strategy.Decode(context.Background(), token[:len(token)-4]+"abcd")
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/compose"
+	"github.com/ory/fosite/handler/oauth2"
+	"github.com/ory/fosite/handler/pkce"
+)
+
+// ClientPKCEFactory creates a ClientPKCEHandler.
+func ClientPKCEFactory(_ *compose.Config, storage interface{}, strategy interface{}) interface{} {
+	return &ClientPKCEHandler{
+		AuthorizeCodeStrategy: strategy.(oauth2.AuthorizeCodeStrategy),
+		Storage:               storage.(pkce.PKCERequestStorage),
+	}
+}
+
+// ClientPKCEHandler enforces the PKCE requirements of each client in addition to the global requirements enforced by
+// the fosite PKCE handler, which also verifies the code verifier.
+type ClientPKCEHandler struct {
+	AuthorizeCodeStrategy oauth2.AuthorizeCodeStrategy
+	Storage               pkce.PKCERequestStorage
+}
+
+// HandleAuthorizeEndpointRequest implements fosite.AuthorizeEndpointHandler.
+func (h *ClientPKCEHandler) HandleAuthorizeEndpointRequest(_ context.Context, requester fosite.AuthorizeRequester, _ fosite.AuthorizeResponder) error {
+	if !requester.GetResponseTypes().Has(FormParameterCode) {
+		return nil
+	}
+
+	form := requester.GetRequestForm()
+
+	return validateClientPKCE(requester.GetClient(), form.Get(FormParameterCodeChallenge), form.Get(FormParameterCodeChallengeMethod))
+}
+
+// HandleTokenEndpointRequest implements fosite.TokenEndpointHandler. The authorization request is validated again in
+// case the requirements of the client changed after the authorization code was issued.
+func (h *ClientPKCEHandler) HandleTokenEndpointRequest(ctx context.Context, requester fosite.AccessRequester) error {
+	if !h.CanHandleTokenEndpointRequest(requester) {
+		return fosite.ErrUnknownRequest
+	}
+
+	client, ok := requester.GetClient().(*Client)
+	if !ok || !client.RequirePKCE {
+		return nil
+	}
+
+	signature := h.AuthorizeCodeStrategy.AuthorizeCodeSignature(requester.GetRequestForm().Get(FormParameterCode))
+
+	authorizeRequester, err := h.Storage.GetPKCERequestSession(ctx, signature, requester.GetSession())
+
+	switch {
+	case errors.Is(err, fosite.ErrNotFound):
+		return fosite.ErrInvalidGrant.
+			WithHint("This client must use PKCE but the authorization code was issued without a code_challenge.").
+			WithWrap(err).WithDebug(err.Error())
+	case err != nil:
+		return fosite.ErrServerError.WithWrap(err).WithDebug(err.Error())
+	}
+
+	form := authorizeRequester.GetRequestForm()
+
+	if err = validateClientPKCE(client, form.Get(FormParameterCodeChallenge), form.Get(FormParameterCodeChallengeMethod)); err != nil {
+		return fosite.ErrInvalidGrant.
+			WithHint("The authorization code was issued with a code_challenge which doesn't meet the PKCE requirements of this client.").
+			WithWrap(err).WithDebug(err.Error())
+	}
+
+	return nil
+}
+
+// PopulateTokenEndpointResponse implements fosite.TokenEndpointHandler.
+func (h *ClientPKCEHandler) PopulateTokenEndpointResponse(_ context.Context, _ fosite.AccessRequester, _ fosite.AccessResponder) error {
+	return nil
+}
+
+// CanSkipClientAuth implements fosite.TokenEndpointHandler.
+func (h *ClientPKCEHandler) CanSkipClientAuth(_ fosite.AccessRequester) bool {
+	return false
+}
+
+// CanHandleTokenEndpointRequest implements fosite.TokenEndpointHandler.
+func (h *ClientPKCEHandler) CanHandleTokenEndpointRequest(requester fosite.AccessRequester) bool {
+	return requester.GetGrantTypes().ExactOne("authorization_code")
+}
+
+// validateClientPKCE returns an error if the code challenge doesn't meet the PKCE requirements of the client. The
+// challenge method defaults to plain when omitted as per RFC7636.
+func validateClientPKCE(c fosite.Client, challenge, method string) error {
+	client, ok := c.(*Client)
+	if !ok || !client.RequirePKCE {
+		return nil
+	}
+
+	if challenge == "" {
+		return fosite.ErrInvalidRequest.
+			WithHint("This client must include a code_challenge when performing the authorize code flow, but it is missing.").
+			WithDebugf("The client '%s' is configured to require PKCE.", client.ID)
+	}
+
+	if method == "" {
+		method = PKCEChallengeMethodPlain
+	}
+
+	if client.PKCEChallengeMethod != "" && method != client.PKCEChallengeMethod {
+		return fosite.ErrInvalidRequest.
+			WithHintf("This client must use code_challenge_method=%s, %s is not allowed.", client.PKCEChallengeMethod, method).
+			WithDebugf("The client '%s' is configured to require the PKCE challenge method '%s'.", client.ID, client.PKCEChallengeMethod)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/compose"
+	"github.com/ory/fosite/handler/pkce"
+	"github.com/ory/fosite/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPKCEVerifier      = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testPKCEOtherVerifier = "kXjEOFWFg1Ww_r1p1UJUbhu72K29Bm-PVC4ZeJtfjBd"
+)
+
+func newTestPKCEChallenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+func newTestPKCEHandlers() (*ClientPKCEHandler, *pkce.Handler, *storage.MemoryStore) {
+	config := &compose.Config{
+		EnforcePKCEForPublicClients: true,
+	}
+
+	store := storage.NewMemoryStore()
+	strategy := compose.NewOAuth2HMACStrategy(config, []byte("a-very-long-secret-for-the-pkce-tests"), nil)
+
+	return ClientPKCEFactory(config, store, strategy).(*ClientPKCEHandler), compose.OAuth2PKCEFactory(config, store, strategy).(*pkce.Handler), store
+}
+
+func newTestPKCEAuthorizeRequest(client *Client, challenge, method string) *fosite.AuthorizeRequest {
+	form := url.Values{}
+
+	if challenge != "" {
+		form.Set(FormParameterCodeChallenge, challenge)
+	}
+
+	if method != "" {
+		form.Set(FormParameterCodeChallengeMethod, method)
+	}
+
+	return &fosite.AuthorizeRequest{
+		ResponseTypes: fosite.Arguments{FormParameterCode},
+		Request: fosite.Request{
+			ID:      "pkce-request",
+			Client:  client,
+			Form:    form,
+			Session: &fosite.DefaultSession{},
+		},
+	}
+}
+
+func TestClientPKCEHandler_ShouldRejectMissingChallenge(t *testing.T) {
+	handler, _, _ := newTestPKCEHandlers()
+
+	client := &Client{ID: "app", RequirePKCE: true, PKCEChallengeMethod: PKCEChallengeMethodSHA256}
+
+	err := handler.HandleAuthorizeEndpointRequest(context.Background(), newTestPKCEAuthorizeRequest(client, "", ""), fosite.NewAuthorizeResponse())
+
+	require.ErrorIs(t, err, fosite.ErrInvalidRequest)
+	assert.Equal(t, "This client must include a code_challenge when performing the authorize code flow, but it is missing.", fosite.ErrorToRFC6749Error(err).HintField)
+}
+
+func TestClientPKCEHandler_ShouldRejectPlainChallengeWhenSHA256IsRequired(t *testing.T) {
+	handler, _, _ := newTestPKCEHandlers()
+
+	client := &Client{ID: "app", RequirePKCE: true, PKCEChallengeMethod: PKCEChallengeMethodSHA256}
+
+	for _, method := range []string{PKCEChallengeMethodPlain, ""} {
+		err := handler.HandleAuthorizeEndpointRequest(context.Background(), newTestPKCEAuthorizeRequest(client, testPKCEVerifier, method), fosite.NewAuthorizeResponse())
+
+		require.ErrorIs(t, err, fosite.ErrInvalidRequest)
+		assert.Equal(t, "This client must use code_challenge_method=S256, plain is not allowed.", fosite.ErrorToRFC6749Error(err).HintField)
+	}
+}
+
+func TestClientPKCEHandler_ShouldAllowValidChallenge(t *testing.T) {
+	handler, _, _ := newTestPKCEHandlers()
+
+	testCases := []struct {
+		name      string
+		client    *Client
+		challenge string
+		method    string
+	}{
+		{"ShouldAllowSHA256", &Client{ID: "app", RequirePKCE: true, PKCEChallengeMethod: PKCEChallengeMethodSHA256}, newTestPKCEChallenge(testPKCEVerifier), PKCEChallengeMethodSHA256},
+		{"ShouldAllowAnyMethodWhenNotConfigured", &Client{ID: "app", RequirePKCE: true}, testPKCEVerifier, PKCEChallengeMethodPlain},
+		{"ShouldAllowMissingChallengeWhenNotRequired", &Client{ID: "app"}, "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := handler.HandleAuthorizeEndpointRequest(context.Background(), newTestPKCEAuthorizeRequest(tc.client, tc.challenge, tc.method), fosite.NewAuthorizeResponse())
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// issueTestPKCECode stores the PKCE request session for a new authorization code like the fosite PKCE handler does
+// when the authorization code is issued, and returns a token request exchanging the code with the verifier.
+func issueTestPKCECode(t *testing.T, handler *ClientPKCEHandler, store *storage.MemoryStore, authorizeRequest *fosite.AuthorizeRequest, verifier string) *fosite.AccessRequest {
+	code, signature, err := handler.AuthorizeCodeStrategy.GenerateAuthorizeCode(context.Background(), authorizeRequest)
+	require.NoError(t, err)
+
+	if authorizeRequest.GetRequestForm().Get(FormParameterCodeChallenge) != "" {
+		require.NoError(t, store.CreatePKCERequestSession(context.Background(), signature, authorizeRequest))
+	}
+
+	form := url.Values{}
+	form.Set(FormParameterCode, code)
+	form.Set("code_verifier", verifier)
+
+	return &fosite.AccessRequest{
+		GrantTypes: fosite.Arguments{"authorization_code"},
+		Request: fosite.Request{
+			Client:  authorizeRequest.Client,
+			Form:    form,
+			Session: &fosite.DefaultSession{},
+		},
+	}
+}
+
+func TestClientPKCEHandler_ShouldRejectVerifierMismatch(t *testing.T) {
+	handler, pkceHandler, store := newTestPKCEHandlers()
+
+	client := &Client{ID: "app", RequirePKCE: true, PKCEChallengeMethod: PKCEChallengeMethodSHA256}
+
+	request := issueTestPKCECode(t, handler, store,
+		newTestPKCEAuthorizeRequest(client, newTestPKCEChallenge(testPKCEVerifier), PKCEChallengeMethodSHA256), testPKCEOtherVerifier)
+
+	require.NoError(t, handler.HandleTokenEndpointRequest(context.Background(), request))
+
+	err := pkceHandler.HandleTokenEndpointRequest(context.Background(), request)
+
+	require.ErrorIs(t, err, fosite.ErrInvalidGrant)
+	assert.Equal(t, "The PKCE code challenge did not match the code verifier.", fosite.ErrorToRFC6749Error(err).HintField)
+}
+
+func TestClientPKCEHandler_ShouldAllowMatchingVerifier(t *testing.T) {
+	handler, pkceHandler, store := newTestPKCEHandlers()
+
+	client := &Client{ID: "app", RequirePKCE: true, PKCEChallengeMethod: PKCEChallengeMethodSHA256}
+
+	request := issueTestPKCECode(t, handler, store,
+		newTestPKCEAuthorizeRequest(client, newTestPKCEChallenge(testPKCEVerifier), PKCEChallengeMethodSHA256), testPKCEVerifier)
+
+	assert.NoError(t, handler.HandleTokenEndpointRequest(context.Background(), request))
+	assert.NoError(t, pkceHandler.HandleTokenEndpointRequest(context.Background(), request))
+}
+
+func TestClientPKCEHandler_ShouldRejectCodeIssuedWithoutChallenge(t *testing.T) {
+	handler, _, store := newTestPKCEHandlers()
+
+	// The requirement was enabled after the authorization code was issued.
+	client := &Client{ID: "app"}
+	authorizeRequest := newTestPKCEAuthorizeRequest(client, "", "")
+
+	request := issueTestPKCECode(t, handler, store, authorizeRequest, testPKCEVerifier)
+
+	client.RequirePKCE = true
+
+	err := handler.HandleTokenEndpointRequest(context.Background(), request)
+
+	require.ErrorIs(t, err, fosite.ErrInvalidGrant)
+	assert.Equal(t, "This client must use PKCE but the authorization code was issued without a code_challenge.", fosite.ErrorToRFC6749Error(err).HintField)
+}
+
+func TestClientPKCEHandler_ShouldRejectCodeIssuedWithPlainChallenge(t *testing.T) {
+	handler, _, store := newTestPKCEHandlers()
+
+	client := &Client{ID: "app", RequirePKCE: true}
+
+	request := issueTestPKCECode(t, handler, store,
+		newTestPKCEAuthorizeRequest(client, testPKCEVerifier, PKCEChallengeMethodPlain), testPKCEVerifier)
+
+	client.PKCEChallengeMethod = PKCEChallengeMethodSHA256
+
+	err := handler.HandleTokenEndpointRequest(context.Background(), request)
+
+	require.ErrorIs(t, err, fosite.ErrInvalidGrant)
+}
+
+func TestClientPKCEHandler_ShouldNotHandleOtherGrantTypes(t *testing.T) {
+	handler, _, _ := newTestPKCEHandlers()
+
+	request := &fosite.AccessRequest{GrantTypes: fosite.Arguments{"refresh_token"}}
+
+	assert.False(t, handler.CanHandleTokenEndpointRequest(request))
+	assert.False(t, handler.CanSkipClientAuth(request))
+	assert.ErrorIs(t, handler.HandleTokenEndpointRequest(context.Background(), request), fosite.ErrUnknownRequest)
+	assert.NoError(t, handler.PopulateTokenEndpointResponse(context.Background(), request, nil))
+}
@@ -3,10 +3,14 @@ package oidc
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/ory/fosite"
 	"github.com/ory/fosite/compose"
+	"github.com/ory/fosite/handler/openid"
 	"github.com/ory/herodot"
 
+	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -24,6 +28,24 @@ func NewOpenIDConnectProvider(config *schema.OpenIDConnectConfiguration, storage
 
 	provider.Store = NewOpenIDConnectStore(config, storageProvider)
 
+	provider.allowedGrantTypes = config.AllowedGrantTypes
+	provider.allowedResponseTypes = config.AllowedResponseTypes
+
+	for _, acr := range config.ACRValues {
+		provider.acrValues = append(provider.acrValues, ACRValue{Value: acr.Value, Level: authorization.PolicyToLevel(acr.Policy)})
+	}
+
+	provider.unknownACRValues = config.UnknownACRValues
+
+	provider.CustomScopes = map[string]schema.OpenIDConnectCustomScopeConfiguration{}
+
+	customScopes := make([]string, 0, len(config.CustomScopes))
+
+	for _, scope := range config.CustomScopes {
+		provider.CustomScopes[scope.Name] = scope
+		customScopes = append(customScopes, scope.Name)
+	}
+
 	composeConfiguration := &compose.Config{
 		AccessTokenLifespan:            config.AccessTokenLifespan,
 		AuthorizeCodeLifespan:          config.AuthorizeCodeLifespan,
@@ -34,6 +56,8 @@ func NewOpenIDConnectProvider(config *schema.OpenIDConnectConfiguration, storage
 		EnforcePKCE:                    config.EnforcePKCE == "always",
 		EnforcePKCEForPublicClients:    config.EnforcePKCE != "never",
 		EnablePKCEPlainChallengeMethod: config.EnablePKCEPlainChallenge,
+		FormPostHTMLTemplate:           FormPostHTMLTemplate,
+		ScopeStrategy:                  fosite.ExactScopeStrategy,
 	}
 
 	keyManager, err := NewKeyManagerWithConfiguration(config)
@@ -43,21 +67,19 @@ func NewOpenIDConnectProvider(config *schema.OpenIDConnectConfiguration, storage
 
 	provider.KeyManager = keyManager
 
-	key, err := provider.KeyManager.GetActivePrivateKey()
-	if err != nil {
-		return provider, err
-	}
-
 	strategy := &compose.CommonStrategy{
 		CoreStrategy: compose.NewOAuth2HMACStrategy(
 			composeConfiguration,
 			[]byte(utils.HashSHA256FromString(config.HMACSecret)),
 			nil,
 		),
-		OpenIDConnectTokenStrategy: compose.NewOpenIDConnectStrategy(
-			composeConfiguration,
-			key,
-		),
+		// The ID Token strategy uses the key manager strategy so ID Tokens are signed by the active key after rotation.
+		OpenIDConnectTokenStrategy: NewIDTokenStrategy(&openid.DefaultStrategy{
+			JWTStrategy:         provider.KeyManager.Strategy(),
+			Expiry:              composeConfiguration.GetIDTokenLifespan(),
+			Issuer:              composeConfiguration.IDTokenIssuer,
+			MinParameterEntropy: composeConfiguration.GetMinParameterEntropy(),
+		}, keyManager.clock, config.ClockSkew),
 		JWTStrategy: provider.KeyManager.Strategy(),
 	}
 
@@ -65,7 +87,7 @@ func NewOpenIDConnectProvider(config *schema.OpenIDConnectConfiguration, storage
 		composeConfiguration,
 		provider.Store,
 		strategy,
-		PlainTextHasher{},
+		ClientSecretHasher{},
 
 		/*
 			These are the OAuth2 and OpenIDConnect factories. Order is important (the OAuth2 factories at the top must
@@ -87,10 +109,26 @@ func NewOpenIDConnectProvider(config *schema.OpenIDConnectConfiguration, storage
 		compose.OAuth2TokenIntrospectionFactory,
 		compose.OAuth2TokenRevocationFactory,
 
+		// The client PKCE handler must be before the PKCE handler as the PKCE handler deletes the PKCE request session.
+		ClientPKCEFactory,
 		compose.OAuth2PKCEFactory,
 	)
 
-	provider.discovery = NewOpenIDConnectWellKnownConfiguration(config.EnablePKCEPlainChallenge, provider.Pairwise())
+	if f, ok := provider.Fosite.(*fosite.Fosite); ok {
+		f.ClientAuthenticationStrategy = NewClientAuthenticationStrategy(f, provider.Store, keyManager.clock, config.ClockSkew).AuthenticateClient
+	}
+
+	provider.discovery = NewOpenIDConnectWellKnownConfiguration(config.EnablePKCEPlainChallenge, provider.Pairwise(), customScopes)
+
+	if len(config.AllowedResponseTypes) != 0 {
+		provider.discovery.ResponseTypesSupported = config.AllowedResponseTypes
+	}
+
+	provider.discovery.GrantTypesSupported = config.AllowedGrantTypes
+
+	for _, acr := range provider.acrValues {
+		provider.discovery.ACRValuesSupported = append(provider.discovery.ACRValuesSupported, acr.Value)
+	}
 
 	provider.herodot = herodot.NewJSONWriter(nil)
 
@@ -99,8 +137,29 @@ func NewOpenIDConnectProvider(config *schema.OpenIDConnectConfiguration, storage
 
 // Pairwise returns true if this provider is configured with clients that require pairwise.
 func (p OpenIDConnectProvider) Pairwise() bool {
-	for _, c := range p.Store.clients {
-		if c.SectorIdentifier != "" {
+	return p.Store.HasSectorIdentifierClients()
+}
+
+// IsGrantTypeAllowed returns true if the grant type is globally allowed by the configuration. All grant types are
+// allowed when the allowed grant types are not configured.
+func (p OpenIDConnectProvider) IsGrantTypeAllowed(grantType string) bool {
+	if len(p.allowedGrantTypes) == 0 {
+		return true
+	}
+
+	return utils.IsStringInSlice(grantType, p.allowedGrantTypes)
+}
+
+// IsResponseTypeAllowed returns true if the response type is globally allowed by the configuration. The order of the
+// individual values of the response type is not significant. All response types are allowed when the allowed response
+// types are not configured.
+func (p OpenIDConnectProvider) IsResponseTypeAllowed(responseType fosite.Arguments) bool {
+	if len(p.allowedResponseTypes) == 0 {
+		return true
+	}
+
+	for _, allowed := range p.allowedResponseTypes {
+		if responseType.Matches(strings.Fields(allowed)...) {
 			return true
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/ory/fosite"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -170,6 +171,8 @@ func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetOpenIDConnectWellKnow
 	assert.Contains(t, disco.RequestObjectSigningAlgValuesSupported, "RS256")
 	assert.Contains(t, disco.RequestObjectSigningAlgValuesSupported, "none")
 
+	assert.True(t, disco.ClaimsParameterSupported)
+
 	assert.Len(t, disco.ClaimsSupported, 18)
 	assert.Contains(t, disco.ClaimsSupported, "amr")
 	assert.Contains(t, disco.ClaimsSupported, "aud")
@@ -293,3 +296,82 @@ func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetOpenIDConnectWellKnow
 	assert.Equal(t, "S256", disco.CodeChallengeMethodsSupported[0])
 	assert.Equal(t, "plain", disco.CodeChallengeMethodsSupported[1])
 }
+
+func TestOpenIDConnectProvider_ShouldOnlyAllowConfiguredTypes(t *testing.T) {
+	provider, err := NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey:     exampleIssuerPrivateKey,
+		HMACSecret:           "asbdhaaskmdlkamdklasmdlkams",
+		AllowedGrantTypes:    []string{"authorization_code", "refresh_token"},
+		AllowedResponseTypes: []string{"code", "code id_token"},
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:            "a-client",
+				Secret:        "a-client-secret",
+				Policy:        "one_factor",
+				RedirectURIs:  []string{"https://google.com"},
+				GrantTypes:    []string{"authorization_code", "implicit", "refresh_token", "password"},
+				ResponseTypes: []string{"code", "id_token code", "token"},
+			},
+		},
+	}, nil)
+
+	require.NoError(t, err)
+
+	assert.True(t, provider.IsGrantTypeAllowed("authorization_code"))
+	assert.True(t, provider.IsGrantTypeAllowed("refresh_token"))
+	assert.False(t, provider.IsGrantTypeAllowed("implicit"))
+	assert.False(t, provider.IsGrantTypeAllowed("password"))
+
+	assert.True(t, provider.IsResponseTypeAllowed(fosite.Arguments{"code"}))
+	assert.True(t, provider.IsResponseTypeAllowed(fosite.Arguments{"id_token", "code"}))
+	assert.False(t, provider.IsResponseTypeAllowed(fosite.Arguments{"token"}))
+	assert.False(t, provider.IsResponseTypeAllowed(fosite.Arguments{"code", "token"}))
+
+	client, err := provider.Store.GetFullClient("a-client")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"authorization_code", "refresh_token"}, client.GrantTypes)
+	assert.Equal(t, []string{"code", "id_token code"}, client.ResponseTypes)
+
+	disco := provider.GetOpenIDConnectWellKnownConfiguration("https://example.com")
+
+	assert.Equal(t, []string{"code", "code id_token"}, disco.ResponseTypesSupported)
+	assert.Equal(t, []string{"authorization_code", "refresh_token"}, disco.GrantTypesSupported)
+}
+
+func TestOpenIDConnectProvider_ShouldAllowAllTypesWhenNotConfigured(t *testing.T) {
+	provider, err := NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: exampleIssuerPrivateKey,
+		HMACSecret:       "asbdhaaskmdlkamdklasmdlkams",
+	}, nil)
+
+	require.NoError(t, err)
+
+	assert.True(t, provider.IsGrantTypeAllowed("implicit"))
+	assert.True(t, provider.IsResponseTypeAllowed(fosite.Arguments{"code", "token"}))
+}
+
+func TestOpenIDConnectProvider_ShouldAdvertiseConfiguredACRValues(t *testing.T) {
+	provider, err := NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: exampleIssuerPrivateKey,
+		HMACSecret:       "asbdhaaskmdlkamdklasmdlkams",
+		ACRValues: []schema.OpenIDConnectACRValueConfiguration{
+			{Value: "urn:example:loa:1", Policy: "one_factor"},
+			{Value: "urn:example:loa:2", Policy: "two_factor"},
+		},
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:           "a-client",
+				Secret:       "a-client-secret",
+				Policy:       "one_factor",
+				RedirectURIs: []string{"https://google.com"},
+			},
+		},
+	}, nil)
+
+	require.NoError(t, err)
+
+	disco := provider.GetOpenIDConnectWellKnownConfiguration("https://example.com")
+
+	assert.Equal(t, []string{"urn:example:loa:1", "urn:example:loa:2"}, disco.ACRValuesSupported)
+}
@@ -6,6 +6,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,25 +19,73 @@ import (
 	"github.com/authelia/authelia/v4/internal/logging"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/storage"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // NewOpenIDConnectStore returns a OpenIDConnectStore when provided with a schema.OpenIDConnectConfiguration and storage.Provider.
 func NewOpenIDConnectStore(config *schema.OpenIDConnectConfiguration, provider storage.Provider) (store *OpenIDConnectStore) {
-	logger := logging.Logger()
-
 	store = &OpenIDConnectStore{
-		provider: provider,
-		clients:  map[string]*Client{},
+		provider:             provider,
+		allowedGrantTypes:    config.AllowedGrantTypes,
+		allowedResponseTypes: config.AllowedResponseTypes,
+		lock:                 &sync.RWMutex{},
+		clock:                &utils.RealClock{},
 	}
 
-	for _, client := range config.Clients {
+	store.clients = store.newClients(config.Clients)
+
+	return store
+}
+
+func (s *OpenIDConnectStore) newClients(configs []schema.OpenIDConnectClientConfiguration) (clients map[string]*Client) {
+	logger := logging.Logger()
+
+	clients = make(map[string]*Client, len(configs))
+
+	for _, client := range configs {
 		policy := authorization.PolicyToLevel(client.Policy)
 		logger.Debugf("Registering client %s with policy %s (%v)", client.ID, client.Policy, policy)
 
-		store.clients[client.ID] = NewClient(client)
+		c := NewClient(client)
+		c.clock = s.clock
+
+		// Clients never have the grant types or response types which aren't globally allowed, this ensures that the
+		// individual handlers reject them and tokens for them are never issued.
+		if len(s.allowedGrantTypes) != 0 {
+			c.GrantTypes = filterAllowedTypes(c.GrantTypes, s.allowedGrantTypes)
+		}
+
+		if len(s.allowedResponseTypes) != 0 {
+			c.ResponseTypes = filterAllowedTypes(c.ResponseTypes, s.allowedResponseTypes)
+		}
+
+		clients[client.ID] = c
 	}
 
-	return store
+	return clients
+}
+
+// ReplaceClients replaces all the registered clients with the provided already validated clients, and returns the IDs
+// of the clients which were removed. The tokens and in-flight authorization requests of removed clients can no longer be
+// used as loading them requires the client.
+func (s *OpenIDConnectStore) ReplaceClients(configs []schema.OpenIDConnectClientConfiguration) (removed []string) {
+	clients := s.newClients(configs)
+
+	s.lock.Lock()
+
+	for id := range s.clients {
+		if _, ok := clients[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+
+	s.clients = clients
+
+	s.lock.Unlock()
+
+	sort.Strings(removed)
+
+	return removed
 }
 
 // GenerateOpaqueUserID either retrieves or creates an opaque user id from a sectorID and username.
@@ -66,7 +117,7 @@ func (s OpenIDConnectStore) GetSubject(ctx context.Context, sectorID, username s
 }
 
 // GetClientPolicy retrieves the policy from the client with the matching provided id.
-func (s OpenIDConnectStore) GetClientPolicy(id string) (level authorization.Level) {
+func (s *OpenIDConnectStore) GetClientPolicy(id string) (level authorization.Level) {
 	client, err := s.GetFullClient(id)
 	if err != nil {
 		return authorization.TwoFactor
@@ -76,7 +127,10 @@ func (s OpenIDConnectStore) GetClientPolicy(id string) (level authorization.Leve
 }
 
 // GetFullClient returns a fosite.Client asserted as an Client matching the provided id.
-func (s OpenIDConnectStore) GetFullClient(id string) (client *Client, err error) {
+func (s *OpenIDConnectStore) GetFullClient(id string) (client *Client, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
 	client, ok := s.clients[id]
 	if !ok {
 		return nil, fosite.ErrNotFound
@@ -86,12 +140,77 @@ func (s OpenIDConnectStore) GetFullClient(id string) (client *Client, err error)
 }
 
 // IsValidClientID returns true if the provided id exists in the OpenIDConnectProvider.Clients map.
-func (s OpenIDConnectStore) IsValidClientID(id string) (valid bool) {
+func (s *OpenIDConnectStore) IsValidClientID(id string) (valid bool) {
 	_, err := s.GetFullClient(id)
 
 	return err == nil
 }
 
+// IsFirstPartyClient returns true if the provided id is a registered first party client.
+func (s *OpenIDConnectStore) IsFirstPartyClient(id string) (firstParty bool) {
+	client, err := s.GetFullClient(id)
+
+	return err == nil && client.FirstParty
+}
+
+// HasSectorIdentifierClients returns true if any of the registered clients have a sector identifier.
+func (s *OpenIDConnectStore) HasSectorIdentifierClients() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, client := range s.clients {
+		if client.SectorIdentifier != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetAllowedOrigins returns the origins registered to all of the clients.
+func (s *OpenIDConnectStore) GetAllowedOrigins() (origins []string) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, client := range s.clients {
+		for _, origin := range client.AllowedOrigins {
+			if !utils.IsStringInSlice(origin, origins) {
+				origins = append(origins, origin)
+			}
+		}
+	}
+
+	sort.Strings(origins)
+
+	return origins
+}
+
+// GetAccessTokenClientID returns the ID of the client an active access token was issued to. The token is only looked
+// up by its signature and is not otherwise validated, so this must only be used to identify the client and never to
+// authorize the request.
+func (s *OpenIDConnectStore) GetAccessTokenClientID(ctx context.Context, token string) (id string, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return "", fosite.ErrNotFound
+	}
+
+	var session *model.OAuth2Session
+
+	if session, err = s.provider.LoadOAuth2Session(ctx, storage.OAuth2SessionTypeAccessToken, parts[1]); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fosite.ErrNotFound
+		}
+
+		return "", err
+	}
+
+	if !session.Active {
+		return "", fosite.ErrInactiveToken
+	}
+
+	return session.ClientID, nil
+}
+
 // BeginTX starts a transaction.
 // This implements a portion of fosite storage.Transactional interface.
 func (s *OpenIDConnectStore) BeginTX(ctx context.Context) (c context.Context, err error) {
@@ -124,7 +243,7 @@ func (s *OpenIDConnectStore) ClientAssertionJWTValid(ctx context.Context, jti st
 	blacklistedJTI, err := s.provider.LoadOAuth2BlacklistedJTI(ctx, signature)
 
 	switch {
-	case errors.Is(sql.ErrNoRows, err):
+	case errors.Is(err, sql.ErrNoRows):
 		return nil
 	case err != nil:
 		return err
@@ -195,8 +314,18 @@ func (s *OpenIDConnectStore) GetAccessTokenSession(ctx context.Context, signatur
 
 // CreateRefreshTokenSession stores the authorization request for a given refresh token.
 // This implements a portion of oauth2.RefreshTokenStorage.
+// The refresh token issued by the refresh token grant replaces the refresh token which was used, the time it was used is
+// recorded so the last use of the refresh token can be tracked.
 func (s *OpenIDConnectStore) CreateRefreshTokenSession(ctx context.Context, signature string, request fosite.Requester) (err error) {
-	return s.saveSession(ctx, storage.OAuth2SessionTypeRefreshToken, signature, request)
+	if err = s.saveSession(ctx, storage.OAuth2SessionTypeRefreshToken, signature, request); err != nil {
+		return err
+	}
+
+	if ar, ok := request.(fosite.AccessRequester); !ok || !ar.GetGrantTypes().ExactOne(GrantTypeRefreshToken) {
+		return nil
+	}
+
+	return s.provider.UpdateOAuth2RefreshTokenSessionLastUsed(ctx, signature, request.GetRequestedAt())
 }
 
 // DeleteRefreshTokenSession marks the authorization request for a given refresh token as deleted.
@@ -299,8 +428,13 @@ func (s *OpenIDConnectStore) loadSessionBySignature(ctx context.Context, session
 		return nil, err
 	}
 
-	if !sessionModel.Active && sessionType == storage.OAuth2SessionTypeAuthorizeCode {
-		return r, fosite.ErrInvalidatedAuthorizeCode
+	if !sessionModel.Active {
+		switch sessionType {
+		case storage.OAuth2SessionTypeAuthorizeCode:
+			return r, fosite.ErrInvalidatedAuthorizeCode
+		case storage.OAuth2SessionTypeRefreshToken:
+			return r, fosite.ErrInactiveToken
+		}
 	}
 
 	return r, nil
@@ -332,3 +466,21 @@ func (s *OpenIDConnectStore) revokeSessionByRequestID(ctx context.Context, sessi
 
 	return nil
 }
+
+// filterAllowedTypes returns the values which are in the allowed values. Values consisting of multiple space separated
+// values such as response types are compared regardless of the order of the individual values.
+func filterAllowedTypes(values, allowed []string) (filtered []string) {
+	filtered = make([]string, 0, len(values))
+
+	for _, value := range values {
+		for _, a := range allowed {
+			if fosite.Arguments(strings.Fields(value)).Matches(strings.Fields(a)...) {
+				filtered = append(filtered, value)
+
+				break
+			}
+		}
+	}
+
+	return filtered
+}
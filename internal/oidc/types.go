@@ -1,7 +1,9 @@
 package oidc
 
 import (
+	"context"
 	"crypto/rsa"
+	"sync"
 	"time"
 
 	"github.com/ory/fosite"
@@ -11,6 +13,7 @@ import (
 	"gopkg.in/square/go-jose.v2"
 
 	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -82,6 +85,15 @@ type OpenIDConnectProvider struct {
 	Store      *OpenIDConnectStore
 	KeyManager *KeyManager
 
+	// CustomScopes are the custom scopes keyed by name which are configured in addition to the standard scopes.
+	CustomScopes map[string]schema.OpenIDConnectCustomScopeConfiguration
+
+	allowedGrantTypes    []string
+	allowedResponseTypes []string
+
+	acrValues        []ACRValue
+	unknownACRValues string
+
 	herodot *herodot.JSONWriter
 
 	discovery OpenIDConnectWellKnownConfiguration
@@ -94,7 +106,13 @@ type OpenIDConnectProvider struct {
 // openid.OpenIDConnectRequestStorage, and partially implements rfc7523.RFC7523KeyStorage.
 type OpenIDConnectStore struct {
 	provider storage.Provider
-	clients  map[string]*Client
+
+	allowedGrantTypes    []string
+	allowedResponseTypes []string
+
+	lock    *sync.RWMutex
+	clients map[string]*Client
+	clock   utils.Clock
 }
 
 // Client represents the client internally.
@@ -104,32 +122,83 @@ type Client struct {
 	Secret           []byte
 	SectorIdentifier string
 	Public           bool
+	FirstParty       bool
+
+	PreviousSecret          []byte
+	PreviousSecretExpiresAt time.Time
 
-	Audience      []string
-	Scopes        []string
-	RedirectURIs  []string
-	GrantTypes    []string
-	ResponseTypes []string
-	ResponseModes []fosite.ResponseModeType
+	Audience       []string
+	ScopeAudiences map[string][]string
+	Scopes         []string
+	DefaultScopes  []string
+	RedirectURIs   []string
+	GrantTypes     []string
+	ResponseTypes  []string
+	ResponseModes  []fosite.ResponseModeType
 
+	AllowedOrigins []string
+	AllowedGroups  []string
+
+	TokenEndpointAuthMethod  string
 	UserinfoSigningAlgorithm string
 
+	RequirePKCE         bool
+	PKCEChallengeMethod string
+
 	Policy authorization.Level
 
 	PreConfiguredConsentDuration *time.Duration
+
+	PreAuthorizedScopes []string
+
+	IntrospectionEnabled        bool
+	IntrospectionAllowedClients []string
+	IntrospectionScopes         []string
+
+	clock utils.Clock
 }
 
-// KeyManager keeps track of all of the active/inactive rsa keys and provides them to services requiring them.
-// It additionally allows us to add keys for the purpose of key rotation in the future.
+// issuerContext is a context.Context which determines the issuer of the request, such as the middlewares.AutheliaCtx.
+type issuerContext interface {
+	context.Context
+
+	ExternalRootURL() (string, error)
+}
+
+// KeyManager keeps track of all of the active/inactive rsa keys and provides them to services requiring them. Keys
+// may be scheduled to become the active key at a point in time, superseded keys are published in the key set for
+// the grace period so relying parties which cached the key set can still verify tokens signed by them.
 type KeyManager struct {
 	activeKeyID string
 	keys        map[string]*rsa.PrivateKey
 	keySet      *jose.JSONWebKeySet
 	strategy    *RS256JWTStrategy
+
+	managed     []managedKey
+	gracePeriod time.Duration
+	clockSkew   time.Duration
+	clock       utils.Clock
+
+	mutex sync.Mutex
 }
 
-// PlainTextHasher implements the fosite.Hasher interface without an actual hashing algo.
-type PlainTextHasher struct{}
+// managedKey holds the rotation schedule of a key. Signing keys become the active key at activateAt, other keys
+// are only published for verification until expiresAt.
+type managedKey struct {
+	id         string
+	signing    bool
+	activateAt time.Time
+	expiresAt  time.Time
+}
+
+// ClientSecretHasher implements the fosite.Hasher interface for client secrets which are configured as either a
+// plaintext secret or a digest.
+type ClientSecretHasher struct{}
+
+// ClientSecretDigest is a parsed client secret which the secret presented by a client is compared with.
+type ClientSecretDigest interface {
+	Match(secret []byte) bool
+}
 
 // ConsentGetResponseBody schema of the response body of the consent GET endpoint.
 type ConsentGetResponseBody struct {
@@ -138,6 +207,8 @@ type ConsentGetResponseBody struct {
 	Scopes            []string `json:"scopes"`
 	Audience          []string `json:"audience"`
 	PreConfiguration  bool     `json:"pre_configuration"`
+
+	ScopeDescriptions map[string]string `json:"scope_descriptions,omitempty"`
 }
 
 // ConsentPostRequestBody schema of the request body of the consent POST endpoint.
@@ -0,0 +1,82 @@
+package regulation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewCAPTCHAVerifier creates a new CAPTCHAVerifier which verifies tokens with the configured provider.
+func NewCAPTCHAVerifier(config schema.CAPTCHAConfiguration) *CAPTCHAVerifier {
+	return &CAPTCHAVerifier{
+		config:    config,
+		verifyURL: captchaVerifyURLs[config.Provider],
+		client:    &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// Provider returns the name of the CAPTCHA provider.
+func (v *CAPTCHAVerifier) Provider() string {
+	return v.config.Provider
+}
+
+// SiteKey returns the public site key of the CAPTCHA provider.
+func (v *CAPTCHAVerifier) SiteKey() string {
+	return v.config.SiteKey
+}
+
+// Threshold returns the number of failed attempts after which a CAPTCHA challenge is required.
+func (v *CAPTCHAVerifier) Threshold() int {
+	return v.config.Threshold
+}
+
+// FailOpen returns true if the challenge should be skipped when the provider can't verify the token.
+func (v *CAPTCHAVerifier) FailOpen() bool {
+	return v.config.FailureMode == schema.CAPTCHAFailureModeOpen
+}
+
+// Verify the CAPTCHA token with the provider. An error is only returned when the provider could not verify the token,
+// an invalid token returns false without an error.
+func (v *CAPTCHAVerifier) Verify(ctx context.Context, token string, remoteIP net.IP) (valid bool, err error) {
+	form := url.Values{}
+
+	form.Set("secret", v.config.SecretKey)
+	form.Set("response", token)
+	form.Set("sitekey", v.config.SiteKey)
+
+	if remoteIP != nil {
+		form.Set("remoteip", remoteIP.String())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, fmt.Errorf("unable to create the %s verification request: %w", v.config.Provider, err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("unable to perform the %s verification request: %w", v.config.Provider, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unable to perform the %s verification request: unexpected status code %d", v.config.Provider, resp.StatusCode)
+	}
+
+	result := captchaVerifyResponse{}
+
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("unable to decode the %s verification response: %w", v.config.Provider, err)
+	}
+
+	return result.Success, nil
+}
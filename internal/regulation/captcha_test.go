@@ -0,0 +1,80 @@
+package regulation
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldCreateCAPTCHAVerifier(t *testing.T) {
+	verifier := NewCAPTCHAVerifier(schema.CAPTCHAConfiguration{
+		Provider:    schema.CAPTCHAProviderTurnstile,
+		SiteKey:     "site",
+		SecretKey:   "secret",
+		Threshold:   2,
+		Timeout:     time.Second,
+		FailureMode: schema.CAPTCHAFailureModeOpen,
+	})
+
+	require.NotNil(t, verifier)
+	assert.Equal(t, "turnstile", verifier.Provider())
+	assert.Equal(t, "site", verifier.SiteKey())
+	assert.Equal(t, 2, verifier.Threshold())
+	assert.True(t, verifier.FailOpen())
+	assert.Equal(t, "https://challenges.cloudflare.com/turnstile/v0/siteverify", verifier.verifyURL)
+}
+
+func TestShouldVerifyCAPTCHAToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "secret", r.PostForm.Get("secret"))
+		assert.Equal(t, "192.168.0.1", r.PostForm.Get("remoteip"))
+
+		switch r.PostForm.Get("response") {
+		case "valid":
+			_, _ = w.Write([]byte(`{"success":true}`))
+		case "invalid":
+			_, _ = w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+		case "malformed":
+			_, _ = w.Write([]byte(`not json`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	verifier := NewCAPTCHAVerifier(schema.CAPTCHAConfiguration{
+		Provider:  schema.CAPTCHAProviderHCaptcha,
+		SiteKey:   "site",
+		SecretKey: "secret",
+		Timeout:   time.Second,
+	})
+	verifier.verifyURL = server.URL
+
+	ip := net.ParseIP("192.168.0.1")
+
+	valid, err := verifier.Verify(context.Background(), "valid", ip)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = verifier.Verify(context.Background(), "invalid", ip)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	valid, err = verifier.Verify(context.Background(), "malformed", ip)
+	assert.Regexp(t, `^unable to decode the hcaptcha verification response: `, err.Error())
+	assert.False(t, valid)
+
+	valid, err = verifier.Verify(context.Background(), "error", ip)
+	assert.EqualError(t, err, "unable to perform the hcaptcha verification request: unexpected status code 500")
+	assert.False(t, valid)
+}
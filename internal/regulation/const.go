@@ -1,10 +1,17 @@
 package regulation
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
 
 // ErrUserIsBanned user is banned error message.
 var ErrUserIsBanned = fmt.Errorf("user is banned")
 
+// ErrUnlockInvalid is returned when a regulation unlock token is invalid, has expired, or was already used.
+var ErrUnlockInvalid = fmt.Errorf("unlock token is invalid, has expired, or was already used")
+
 const (
 	// AuthType1FA is the string representing an auth log for first-factor authentication.
 	AuthType1FA = "1FA"
@@ -17,4 +24,28 @@ const (
 
 	// AuthTypeDuo is the string representing an auth log for second-factor authentication via DUO.
 	AuthTypeDuo = "Duo"
+
+	// AuthTypeTrustedJWT is the string representing an auth log for first-factor authentication via a JWT forwarded by
+	// a trusted upstream identity provider.
+	AuthTypeTrustedJWT = "JWT"
+
+	// AuthTypeSecondFactorLimit is the string representing an auth log which bans a user after they reached the maximum
+	// number of failed second-factor attempts within a session.
+	AuthTypeSecondFactorLimit = "2FA Limit"
 )
+
+// unlockTokenLength is the length of the token sent to banned users to lift their ban.
+const unlockTokenLength = 32
+
+// maxUserAgentLength is the maximum length of the user agent stored in the authentication log.
+const maxUserAgentLength = 512
+
+// earthRadiusKilometers is the mean radius of the earth in kilometers used for great-circle distance calculations.
+const earthRadiusKilometers = 6371.0
+
+// captchaVerifyURLs are the server-side verification endpoints of the supported CAPTCHA providers.
+var captchaVerifyURLs = map[string]string{
+	schema.CAPTCHAProviderReCAPTCHA: "https://www.google.com/recaptcha/api/siteverify",
+	schema.CAPTCHAProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	schema.CAPTCHAProviderTurnstile: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
@@ -0,0 +1,67 @@
+package regulation
+
+import (
+	"context"
+	"math"
+	"net"
+
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// IsLocationTrusted returns true if the location provided by a request received directly from the remote IP is trusted
+// by the impossible travel detection. The remote IP must be the IP of the peer the request was directly received from,
+// not one taken from a forwarded header, otherwise anyone could provide their own location.
+func (r *Regulator) IsLocationTrusted(remoteIP net.IP) bool {
+	return utils.IsIPInNetworks(remoteIP, r.locationNetworks)
+}
+
+// ImpossibleTravel records the login location of a user and determines if the distance between it and the previously
+// recorded login location could not have been travelled in the elapsed time at the configured maximum speed. The
+// previous location is returned so that it can be logged alongside the current location.
+func (r *Regulator) ImpossibleTravel(ctx context.Context, location model.UserLoginLocation) (previous *model.UserLoginLocation, detected bool, err error) {
+	if !r.config.ImpossibleTravel.Enabled {
+		return nil, false, nil
+	}
+
+	if previous, err = r.storageProvider.LoadUserLoginLocation(ctx, location.Username); err != nil {
+		return nil, false, err
+	}
+
+	if location.Time.IsZero() {
+		location.Time = r.clock.Now()
+	}
+
+	if err = r.storageProvider.SaveUserLoginLocation(ctx, location); err != nil {
+		return previous, false, err
+	}
+
+	if previous == nil {
+		return nil, false, nil
+	}
+
+	distance := haversineDistance(previous.Latitude, previous.Longitude, location.Latitude, location.Longitude)
+
+	if distance < float64(r.config.ImpossibleTravel.MinimumDistance) {
+		return previous, false, nil
+	}
+
+	elapsed := location.Time.Sub(previous.Time).Hours()
+
+	if elapsed <= 0 {
+		return previous, true, nil
+	}
+
+	return previous, distance/elapsed > float64(r.config.ImpossibleTravel.MaximumSpeed), nil
+}
+
+// haversineDistance returns the great-circle distance in kilometers between two points given their latitude and
+// longitude in degrees.
+func haversineDistance(latitudeA, longitudeA, latitudeB, longitudeB float64) (distance float64) {
+	phiA, phiB := latitudeA*math.Pi/180, latitudeB*math.Pi/180
+	deltaPhi, deltaLambda := (latitudeB-latitudeA)*math.Pi/180, (longitudeB-longitudeA)*math.Pi/180
+
+	a := math.Pow(math.Sin(deltaPhi/2), 2) + math.Cos(phiA)*math.Cos(phiB)*math.Pow(math.Sin(deltaLambda/2), 2)
+
+	return 2 * earthRadiusKilometers * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,143 @@
+package regulation_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+)
+
+func newImpossibleTravelRegulator(t *testing.T) (regulator *regulation.Regulator, storageMock *mocks.MockStorage, clock *mocks.TestingClock) {
+	ctrl := gomock.NewController(t)
+	storageMock = mocks.NewMockStorage(ctrl)
+
+	clock = &mocks.TestingClock{}
+	clock.Set(time.Now())
+
+	config := schema.DefaultRegulationConfiguration
+	config.ImpossibleTravel.Enabled = true
+
+	return regulation.NewRegulator(config, storageMock, clock), storageMock, clock
+}
+
+func TestShouldOnlyTrustLocationFromConfiguredNetworks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	config := schema.DefaultRegulationConfiguration
+	config.ImpossibleTravel.Enabled = true
+	config.ImpossibleTravel.Networks = []string{"10.0.0.0/8", "192.168.1.1"}
+
+	regulator := regulation.NewRegulator(config, mocks.NewMockStorage(ctrl), &mocks.TestingClock{})
+
+	assert.True(t, regulator.IsLocationTrusted(net.ParseIP("10.20.30.40")))
+	assert.True(t, regulator.IsLocationTrusted(net.ParseIP("192.168.1.1")))
+	assert.False(t, regulator.IsLocationTrusted(net.ParseIP("192.168.1.2")))
+	assert.False(t, regulator.IsLocationTrusted(net.ParseIP("127.0.0.1")))
+}
+
+func TestShouldNotDetectImpossibleTravelWhenDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	storageMock := mocks.NewMockStorage(ctrl)
+
+	regulator := regulation.NewRegulator(schema.DefaultRegulationConfiguration, storageMock, &mocks.TestingClock{})
+
+	previous, detected, err := regulator.ImpossibleTravel(context.Background(), model.UserLoginLocation{Username: "john"})
+
+	assert.NoError(t, err)
+	assert.False(t, detected)
+	assert.Nil(t, previous)
+}
+
+func TestShouldNotDetectImpossibleTravelOnFirstLogin(t *testing.T) {
+	regulator, storageMock, clock := newImpossibleTravelRegulator(t)
+
+	location := model.UserLoginLocation{Time: clock.Now(), Username: "john", Latitude: 51.5072, Longitude: -0.1276}
+
+	gomock.InOrder(
+		storageMock.EXPECT().LoadUserLoginLocation(gomock.Any(), "john").Return(nil, nil),
+		storageMock.EXPECT().SaveUserLoginLocation(gomock.Any(), location).Return(nil),
+	)
+
+	previous, detected, err := regulator.ImpossibleTravel(context.Background(), location)
+
+	assert.NoError(t, err)
+	assert.False(t, detected)
+	assert.Nil(t, previous)
+}
+
+func TestShouldDetectImpossibleTravel(t *testing.T) {
+	regulator, storageMock, clock := newImpossibleTravelRegulator(t)
+
+	// London to Sydney is roughly 17,000km which can't be travelled in an hour.
+	last := &model.UserLoginLocation{Time: clock.Now().Add(-time.Hour), Username: "john", Latitude: 51.5072, Longitude: -0.1276}
+	location := model.UserLoginLocation{Time: clock.Now(), Username: "john", Latitude: -33.8688, Longitude: 151.2093}
+
+	gomock.InOrder(
+		storageMock.EXPECT().LoadUserLoginLocation(gomock.Any(), "john").Return(last, nil),
+		storageMock.EXPECT().SaveUserLoginLocation(gomock.Any(), location).Return(nil),
+	)
+
+	previous, detected, err := regulator.ImpossibleTravel(context.Background(), location)
+
+	assert.NoError(t, err)
+	assert.True(t, detected)
+	require.NotNil(t, previous)
+	assert.Equal(t, last, previous)
+}
+
+func TestShouldNotDetectPossibleTravel(t *testing.T) {
+	regulator, storageMock, clock := newImpossibleTravelRegulator(t)
+
+	// London to Sydney is roughly 17,000km which can be travelled in a day.
+	last := &model.UserLoginLocation{Time: clock.Now().Add(-24 * time.Hour), Username: "john", Latitude: 51.5072, Longitude: -0.1276}
+	location := model.UserLoginLocation{Time: clock.Now(), Username: "john", Latitude: -33.8688, Longitude: 151.2093}
+
+	gomock.InOrder(
+		storageMock.EXPECT().LoadUserLoginLocation(gomock.Any(), "john").Return(last, nil),
+		storageMock.EXPECT().SaveUserLoginLocation(gomock.Any(), location).Return(nil),
+	)
+
+	_, detected, err := regulator.ImpossibleTravel(context.Background(), location)
+
+	assert.NoError(t, err)
+	assert.False(t, detected)
+}
+
+func TestShouldNotDetectImpossibleTravelBelowMinimumDistance(t *testing.T) {
+	regulator, storageMock, clock := newImpossibleTravelRegulator(t)
+
+	// London to Reading is roughly 60km which is within the minimum distance.
+	last := &model.UserLoginLocation{Time: clock.Now().Add(-time.Second), Username: "john", Latitude: 51.5072, Longitude: -0.1276}
+	location := model.UserLoginLocation{Time: clock.Now(), Username: "john", Latitude: 51.4543, Longitude: -0.9781}
+
+	gomock.InOrder(
+		storageMock.EXPECT().LoadUserLoginLocation(gomock.Any(), "john").Return(last, nil),
+		storageMock.EXPECT().SaveUserLoginLocation(gomock.Any(), location).Return(nil),
+	)
+
+	_, detected, err := regulator.ImpossibleTravel(context.Background(), location)
+
+	assert.NoError(t, err)
+	assert.False(t, detected)
+}
+
+func TestShouldReturnErrorWhenImpossibleTravelLoadFails(t *testing.T) {
+	regulator, storageMock, clock := newImpossibleTravelRegulator(t)
+
+	storageMock.EXPECT().LoadUserLoginLocation(gomock.Any(), "john").Return(nil, errors.New("failed"))
+
+	_, detected, err := regulator.ImpossibleTravel(context.Background(), model.UserLoginLocation{Time: clock.Now(), Username: "john"})
+
+	assert.EqualError(t, err, "failed")
+	assert.False(t, detected)
+}
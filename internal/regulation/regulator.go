@@ -2,6 +2,7 @@ package regulation
 
 import (
 	"context"
+	"errors"
 	"net"
 	"time"
 
@@ -13,17 +14,31 @@ import (
 
 // NewRegulator create a regulator instance.
 func NewRegulator(config schema.RegulationConfiguration, provider storage.RegulatorProvider, clock utils.Clock) *Regulator {
-	return &Regulator{
-		enabled:         config.MaxRetries > 0,
-		storageProvider: provider,
-		clock:           clock,
-		config:          config,
+	regulator := &Regulator{
+		enabled:          config.MaxRetries > 0,
+		storageProvider:  provider,
+		clock:            clock,
+		config:           config,
+		locationNetworks: make([]*net.IPNet, 0, len(config.ImpossibleTravel.Networks)),
 	}
+
+	// The networks have already been validated by the configuration validator.
+	for _, network := range config.ImpossibleTravel.Networks {
+		if cidr, err := utils.ParseNetwork(network); err == nil {
+			regulator.locationNetworks = append(regulator.locationNetworks, cidr)
+		}
+	}
+
+	return regulator
 }
 
 // Mark an authentication attempt.
 // We split Mark and Regulate in order to avoid timing attacks.
-func (r *Regulator) Mark(ctx context.Context, successful, banned bool, username, requestURI, requestMethod, authType string, remoteIP net.IP) error {
+func (r *Regulator) Mark(ctx context.Context, successful, banned bool, username, requestURI, requestMethod, authType string, remoteIP net.IP, userAgent string) error {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
 	return r.storageProvider.AppendAuthenticationLog(ctx, model.AuthenticationAttempt{
 		Time:          r.clock.Now(),
 		Successful:    successful,
@@ -33,9 +48,30 @@ func (r *Regulator) Mark(ctx context.Context, successful, banned bool, username,
 		RemoteIP:      model.NewNullIP(remoteIP),
 		RequestURI:    requestURI,
 		RequestMethod: requestMethod,
+		UserAgent:     userAgent,
 	})
 }
 
+// FailedAttempts returns the number of consecutive failed authentication attempts for a given user within the find
+// time, counting at most limit attempts. Unlike Regulate it's not affected by the max retries so it can be used even
+// when banning is disabled.
+func (r *Regulator) FailedAttempts(ctx context.Context, username string, limit int) (failed int, err error) {
+	attempts, err := r.storageProvider.LoadAuthenticationLogs(ctx, username, r.clock.Now().Add(-r.config.FindTime), limit, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, attempt := range attempts {
+		if attempt.Successful {
+			break
+		}
+
+		failed++
+	}
+
+	return failed, nil
+}
+
 // Regulate the authentication attempts for a given user.
 // This method returns ErrUserIsBanned if the user is banned along with the time until when the user is banned.
 func (r *Regulator) Regulate(ctx context.Context, username string) (time.Time, error) {
@@ -49,6 +85,22 @@ func (r *Regulator) Regulate(ctx context.Context, username string) (time.Time, e
 		return time.Time{}, nil
 	}
 
+	if attempts, err = r.attemptsSinceUnlock(ctx, username, attempts); err != nil {
+		return time.Time{}, nil
+	}
+
+	// A ban from reaching the maximum number of failed second factor attempts applies for the ban time regardless of
+	// the number of failed attempts.
+	for _, attempt := range attempts {
+		if attempt.Successful {
+			break
+		}
+
+		if attempt.Banned && attempt.Type == AuthTypeSecondFactorLimit {
+			return attempt.Time.Add(r.config.BanTime), ErrUserIsBanned
+		}
+	}
+
 	latestFailedAttempts := make([]model.AuthenticationAttempt, 0, r.config.MaxRetries)
 
 	for _, attempt := range attempts {
@@ -79,3 +131,65 @@ func (r *Regulator) Regulate(ctx context.Context, username string) (time.Time, e
 
 	return time.Time{}, nil
 }
+
+// NewUnlock issues a regulation unlock for a banned user which expires at the latest when the ban does, and returns
+// the token which lifts the ban. It replaces any existing regulation unlock of the user.
+func (r *Regulator) NewUnlock(ctx context.Context, username string, bannedUntil time.Time) (token string, unlock model.RegulationUnlock, err error) {
+	token = utils.RandomString(unlockTokenLength, utils.AlphaNumericCharacters, true)
+	unlock = model.NewRegulationUnlock(username, token, r.clock.Now(), bannedUntil, r.config.Unlock.Lifespan)
+
+	if err = r.storageProvider.SaveRegulationUnlock(ctx, unlock); err != nil {
+		return "", unlock, err
+	}
+
+	return token, unlock, nil
+}
+
+// Unlock lifts the ban of a user with the token of their regulation unlock. The token can only be used once and only
+// while the ban it was issued for is in effect. Each invalid token counts towards the maximum number of attempts, once
+// reached the regulation unlock can no longer be used. ErrUnlockInvalid is returned when the ban can't be lifted.
+func (r *Regulator) Unlock(ctx context.Context, username, token string) (err error) {
+	unlock, err := r.storageProvider.LoadRegulationUnlock(ctx, username)
+
+	switch {
+	case err != nil:
+		return err
+	case unlock == nil || !unlock.Usable(r.clock.Now(), r.config.Unlock.MaxAttempts):
+		return ErrUnlockInvalid
+	}
+
+	if !unlock.Matches(token) {
+		if err = r.storageProvider.IncrementRegulationUnlockAttempts(ctx, username); err != nil {
+			return err
+		}
+
+		return ErrUnlockInvalid
+	}
+
+	if _, err = r.Regulate(ctx, username); !errors.Is(err, ErrUserIsBanned) {
+		return ErrUnlockInvalid
+	}
+
+	return r.storageProvider.ConsumeRegulationUnlock(ctx, username, r.clock.Now())
+}
+
+// attemptsSinceUnlock removes the attempts made before the user was last unlocked with a regulation unlock from the
+// attempts which are ordered from the latest to the oldest.
+func (r *Regulator) attemptsSinceUnlock(ctx context.Context, username string, attempts []model.AuthenticationAttempt) ([]model.AuthenticationAttempt, error) {
+	if !r.config.Unlock.Enabled {
+		return attempts, nil
+	}
+
+	unlock, err := r.storageProvider.LoadRegulationUnlock(ctx, username)
+	if err != nil || unlock == nil || unlock.UnlockedAt == nil {
+		return attempts, err
+	}
+
+	for i, attempt := range attempts {
+		if !attempt.Time.After(*unlock.UnlockedAt) {
+			return attempts[:i], nil
+		}
+	}
+
+	return attempts, nil
+}
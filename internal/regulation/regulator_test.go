@@ -61,6 +61,40 @@ func (s *RegulatorSuite) TestShouldNotThrowWhenUserIsLegitimate() {
 	assert.NoError(s.T(), err)
 }
 
+func (s *RegulatorSuite) TestShouldBanUserWhoReachedSecondFactorLimit() {
+	attemptsInDB := []model.AuthenticationAttempt{
+		{
+			Username:   "john",
+			Successful: false,
+			Banned:     true,
+			Type:       regulation.AuthTypeSecondFactorLimit,
+			Time:       s.clock.Now().Add(-1 * time.Minute),
+		},
+		{
+			Username:   "john",
+			Successful: false,
+			Type:       regulation.AuthTypeTOTP,
+			Time:       s.clock.Now().Add(-1 * time.Minute),
+		},
+		{
+			Username:   "john",
+			Successful: true,
+			Type:       regulation.AuthType1FA,
+			Time:       s.clock.Now().Add(-2 * time.Minute),
+		},
+	}
+
+	s.storageMock.EXPECT().
+		LoadAuthenticationLogs(s.ctx, gomock.Eq("john"), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+		Return(attemptsInDB, nil)
+
+	regulator := regulation.NewRegulator(s.config, s.storageMock, &s.clock)
+
+	until, err := regulator.Regulate(s.ctx, "john")
+	assert.Equal(s.T(), regulation.ErrUserIsBanned, err)
+	assert.Equal(s.T(), s.clock.Now().Add(2*time.Minute), until)
+}
+
 // This test checks the case in which a user failed to authenticate many times but always
 // with a certain amount of time larger than FindTime. Meaning the user should not be banned.
 func (s *RegulatorSuite) TestShouldNotThrowWhenFailedAuthenticationNotInFindTime() {
@@ -253,6 +287,63 @@ func (s *RegulatorSuite) TestShouldCheckRegulationHasBeenResetOnSuccessfulAttemp
 	assert.NoError(s.T(), err)
 }
 
+func (s *RegulatorSuite) TestShouldCountConsecutiveFailedAttempts() {
+	attemptsInDB := []model.AuthenticationAttempt{
+		{
+			Username:   "john",
+			Successful: false,
+			Time:       s.clock.Now().Add(-5 * time.Second),
+		},
+		{
+			Username:   "john",
+			Successful: false,
+			Time:       s.clock.Now().Add(-10 * time.Second),
+		},
+		{
+			Username:   "john",
+			Successful: true,
+			Time:       s.clock.Now().Add(-15 * time.Second),
+		},
+		{
+			Username:   "john",
+			Successful: false,
+			Time:       s.clock.Now().Add(-20 * time.Second),
+		},
+	}
+
+	s.storageMock.EXPECT().
+		LoadAuthenticationLogs(s.ctx, gomock.Eq("john"), gomock.Eq(s.clock.Now().Add(-s.config.FindTime)), gomock.Eq(10), gomock.Eq(0)).
+		Return(attemptsInDB, nil)
+
+	regulator := regulation.NewRegulator(s.config, s.storageMock, &s.clock)
+
+	failed, err := regulator.FailedAttempts(s.ctx, "john", 10)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, failed)
+}
+
+func (s *RegulatorSuite) TestShouldCountFailedAttemptsUpToTheLimit() {
+	attemptsInDB := make([]model.AuthenticationAttempt, 15)
+
+	for i := range attemptsInDB {
+		attemptsInDB[i] = model.AuthenticationAttempt{
+			Username:   "john",
+			Successful: false,
+			Time:       s.clock.Now().Add(-time.Duration(i) * time.Second),
+		}
+	}
+
+	s.storageMock.EXPECT().
+		LoadAuthenticationLogs(s.ctx, gomock.Eq("john"), gomock.Eq(s.clock.Now().Add(-s.config.FindTime)), gomock.Eq(15), gomock.Eq(0)).
+		Return(attemptsInDB, nil)
+
+	regulator := regulation.NewRegulator(s.config, s.storageMock, &s.clock)
+
+	failed, err := regulator.FailedAttempts(s.ctx, "john", 15)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 15, failed)
+}
+
 func TestRunRegulatorSuite(t *testing.T) {
 	s := new(RegulatorSuite)
 	suite.Run(t, s)
@@ -304,3 +395,147 @@ func (s *RegulatorSuite) TestShouldHaveRegulatorDisabled() {
 	_, err = regulator.Regulate(s.ctx, "john")
 	assert.Equal(s.T(), regulation.ErrUserIsBanned, err)
 }
+
+func (s *RegulatorSuite) bannedAttempts() []model.AuthenticationAttempt {
+	return []model.AuthenticationAttempt{
+		{
+			Username:   "john",
+			Successful: false,
+			Time:       s.clock.Now().Add(-1 * time.Second),
+		},
+		{
+			Username:   "john",
+			Successful: false,
+			Time:       s.clock.Now().Add(-4 * time.Second),
+		},
+		{
+			Username:   "john",
+			Successful: false,
+			Time:       s.clock.Now().Add(-6 * time.Second),
+		},
+	}
+}
+
+func (s *RegulatorSuite) unlockConfig() schema.RegulationConfiguration {
+	config := s.config
+	config.Unlock = schema.RegulationUnlockConfiguration{
+		Enabled:     true,
+		Lifespan:    time.Minute * 15,
+		MaxAttempts: 3,
+	}
+
+	return config
+}
+
+func (s *RegulatorSuite) TestShouldIgnoreAttemptsBeforeUnlock() {
+	unlockedAt := s.clock.Now().Add(-2 * time.Second)
+
+	s.storageMock.EXPECT().
+		LoadAuthenticationLogs(s.ctx, gomock.Eq("john"), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+		Return(s.bannedAttempts(), nil)
+
+	s.storageMock.EXPECT().
+		LoadRegulationUnlock(s.ctx, gomock.Eq("john")).
+		Return(&model.RegulationUnlock{Username: "john", UnlockedAt: &unlockedAt}, nil)
+
+	regulator := regulation.NewRegulator(s.unlockConfig(), s.storageMock, &s.clock)
+
+	_, err := regulator.Regulate(s.ctx, "john")
+	assert.NoError(s.T(), err)
+}
+
+func (s *RegulatorSuite) TestShouldIssueUnlock() {
+	bannedUntil := s.clock.Now().Add(time.Minute * 3)
+
+	s.storageMock.EXPECT().
+		SaveRegulationUnlock(s.ctx, gomock.Any()).
+		Return(nil)
+
+	regulator := regulation.NewRegulator(s.unlockConfig(), s.storageMock, &s.clock)
+
+	token, unlock, err := regulator.NewUnlock(s.ctx, "john", bannedUntil)
+	s.Require().NoError(err)
+
+	assert.Len(s.T(), token, 32)
+	assert.True(s.T(), unlock.Matches(token))
+	assert.Equal(s.T(), bannedUntil, unlock.ExpiresAt)
+}
+
+func (s *RegulatorSuite) TestShouldUnlockBannedUser() {
+	unlock := model.NewRegulationUnlock("john", "abc", s.clock.Now(), s.clock.Now().Add(time.Minute), time.Minute*15)
+
+	gomock.InOrder(
+		s.storageMock.EXPECT().
+			LoadRegulationUnlock(s.ctx, gomock.Eq("john")).
+			Return(&unlock, nil),
+		s.storageMock.EXPECT().
+			LoadAuthenticationLogs(s.ctx, gomock.Eq("john"), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+			Return(s.bannedAttempts(), nil),
+		s.storageMock.EXPECT().
+			LoadRegulationUnlock(s.ctx, gomock.Eq("john")).
+			Return(&unlock, nil),
+		s.storageMock.EXPECT().
+			ConsumeRegulationUnlock(s.ctx, gomock.Eq("john"), gomock.Eq(s.clock.Now())).
+			Return(nil),
+	)
+
+	regulator := regulation.NewRegulator(s.unlockConfig(), s.storageMock, &s.clock)
+
+	assert.NoError(s.T(), regulator.Unlock(s.ctx, "john", "abc"))
+}
+
+func (s *RegulatorSuite) TestShouldCountInvalidUnlockAttempts() {
+	unlock := model.NewRegulationUnlock("john", "abc", s.clock.Now(), s.clock.Now().Add(time.Minute), time.Minute*15)
+
+	gomock.InOrder(
+		s.storageMock.EXPECT().
+			LoadRegulationUnlock(s.ctx, gomock.Eq("john")).
+			Return(&unlock, nil),
+		s.storageMock.EXPECT().
+			IncrementRegulationUnlockAttempts(s.ctx, gomock.Eq("john")).
+			Return(nil),
+	)
+
+	regulator := regulation.NewRegulator(s.unlockConfig(), s.storageMock, &s.clock)
+
+	assert.Equal(s.T(), regulation.ErrUnlockInvalid, regulator.Unlock(s.ctx, "john", "abd"))
+}
+
+func (s *RegulatorSuite) TestShouldNotUnlockWhenUnlockNotUsable() {
+	unlock := model.NewRegulationUnlock("john", "abc", s.clock.Now(), s.clock.Now().Add(time.Minute), time.Minute*15)
+	unlock.Attempts = 3
+
+	s.storageMock.EXPECT().
+		LoadRegulationUnlock(s.ctx, gomock.Eq("john")).
+		Return(&unlock, nil)
+
+	regulator := regulation.NewRegulator(s.unlockConfig(), s.storageMock, &s.clock)
+
+	assert.Equal(s.T(), regulation.ErrUnlockInvalid, regulator.Unlock(s.ctx, "john", "abc"))
+
+	s.storageMock.EXPECT().
+		LoadRegulationUnlock(s.ctx, gomock.Eq("john")).
+		Return(nil, nil)
+
+	assert.Equal(s.T(), regulation.ErrUnlockInvalid, regulator.Unlock(s.ctx, "john", "abc"))
+}
+
+func (s *RegulatorSuite) TestShouldNotUnlockWhenNoLongerBanned() {
+	unlock := model.NewRegulationUnlock("john", "abc", s.clock.Now(), s.clock.Now().Add(time.Minute), time.Minute*15)
+
+	gomock.InOrder(
+		s.storageMock.EXPECT().
+			LoadRegulationUnlock(s.ctx, gomock.Eq("john")).
+			Return(&unlock, nil),
+		s.storageMock.EXPECT().
+			LoadAuthenticationLogs(s.ctx, gomock.Eq("john"), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+			Return([]model.AuthenticationAttempt{{Username: "john", Successful: true, Time: s.clock.Now()}}, nil),
+		s.storageMock.EXPECT().
+			LoadRegulationUnlock(s.ctx, gomock.Eq("john")).
+			Return(&unlock, nil),
+	)
+
+	regulator := regulation.NewRegulator(s.unlockConfig(), s.storageMock, &s.clock)
+
+	assert.Equal(s.T(), regulation.ErrUnlockInvalid, regulator.Unlock(s.ctx, "john", "abc"))
+}
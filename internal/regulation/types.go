@@ -1,6 +1,10 @@
 package regulation
 
 import (
+	"context"
+	"net"
+	"net/http"
+
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -13,7 +17,33 @@ type Regulator struct {
 
 	config schema.RegulationConfiguration
 
+	// locationNetworks are the networks trusted to provide the location used by the impossible travel detection.
+	locationNetworks []*net.IPNet
+
 	storageProvider storage.RegulatorProvider
 
 	clock utils.Clock
 }
+
+// CAPTCHAProvider is the interface used to verify the CAPTCHA challenge required after failed attempts.
+type CAPTCHAProvider interface {
+	Provider() string
+	SiteKey() string
+	Threshold() int
+	FailOpen() bool
+	Verify(ctx context.Context, token string, remoteIP net.IP) (valid bool, err error)
+}
+
+// CAPTCHAVerifier verifies the CAPTCHA tokens submitted by users who exceeded the configured number of failed attempts.
+type CAPTCHAVerifier struct {
+	config schema.CAPTCHAConfiguration
+
+	verifyURL string
+
+	client *http.Client
+}
+
+// captchaVerifyResponse is the response of the verification endpoint, all supported providers share this format.
+type captchaVerifyResponse struct {
+	Success bool `json:"success"`
+}
@@ -2,15 +2,16 @@ package server
 
 import (
 	"embed"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
 	"net/http"
+	"strings"
+	"sync"
 
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/fasthttpadaptor"
-
-	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 //go:embed locales
@@ -19,50 +20,75 @@ var locales embed.FS
 //go:embed public_html
 var assets embed.FS
 
+// templatedAssets is the file system the templated files are read from.
+var templatedAssets fs.FS = assets
+
 func newPublicHTMLEmbeddedHandler() fasthttp.RequestHandler {
 	embeddedPath, _ := fs.Sub(assets, "public_html")
 
 	return fasthttpadaptor.NewFastHTTPHandler(http.FileServer(http.FS(embeddedPath)))
 }
 
+// newTextHandler serves the configured content of a text file such as the robots.txt.
+func newTextHandler(content string) fasthttp.RequestHandler {
+	body := []byte(content)
+
+	return func(ctx *fasthttp.RequestCtx) {
+		ctx.SetContentType("text/plain; charset=utf-8")
+		ctx.SetBody(body)
+	}
+}
+
+// newLocalesEmbeddedHandler serves the embedded locales. Each locale is composed from its fallback chain so keys missing
+// from a region variant are filled from the base language, and keys missing from the base language are filled from the
+// default locale. The composed namespaces are cached as the embedded locales never change.
 func newLocalesEmbeddedHandler() (handler fasthttp.RequestHandler) {
-	var languages []string
+	directories := map[string]string{}
 
 	entries, err := locales.ReadDir("locales")
 	if err == nil {
 		for _, entry := range entries {
-			if entry.IsDir() && len(entry.Name()) == 2 {
-				languages = append(languages, entry.Name())
+			if entry.IsDir() {
+				directories[strings.ToLower(entry.Name())] = entry.Name()
 			}
 		}
 	}
 
+	var cache sync.Map
+
 	return func(ctx *fasthttp.RequestCtx) {
 		var (
-			language, variant, locale, namespace string
+			language, variant, namespace string
 		)
 
-		language = ctx.UserValue("language").(string)
+		language = strings.ToLower(ctx.UserValue("language").(string))
 		namespace = ctx.UserValue("namespace").(string)
-		locale = language
 
 		if v := ctx.UserValue("variant"); v != nil {
-			variant = v.(string)
-			locale = fmt.Sprintf("%s-%s", language, variant)
+			variant = strings.ToLower(v.(string))
 		}
 
-		var data []byte
+		chain := localeFallbackChain(directories, language, variant)
+		if len(chain) == 0 {
+			hfsHandleErr(ctx, fs.ErrNotExist)
 
-		if data, err = locales.ReadFile(fmt.Sprintf("locales/%s/%s.json", locale, namespace)); err != nil {
-			if variant != "" && utils.IsStringInSliceFold(language, languages) {
-				data = []byte("{}")
-			}
+			return
+		}
+
+		key := fmt.Sprintf("%s/%s", strings.Join(chain, ","), namespace)
+
+		var data []byte
 
-			if len(data) == 0 {
+		if cached, ok := cache.Load(key); ok {
+			data = cached.([]byte)
+		} else {
+			if data, err = composeLocaleNamespace(chain, namespace); err != nil {
 				hfsHandleErr(ctx, err)
 
 				return
 			}
+
+			cache.Store(key, data)
 		}
 
 		ctx.SetContentType("application/json")
@@ -70,6 +96,82 @@ func newLocalesEmbeddedHandler() (handler fasthttp.RequestHandler) {
 	}
 }
 
+// localeFallbackChain returns the embedded locale directories a locale is composed from ordered from the most specific
+// to the least specific. It returns nil when neither the locale nor its base language are embedded.
+func localeFallbackChain(directories map[string]string, language, variant string) (chain []string) {
+	if variant != "" {
+		if directory, ok := directories[fmt.Sprintf("%s-%s", language, variant)]; ok {
+			chain = append(chain, directory)
+		}
+	}
+
+	if directory, ok := directories[language]; ok {
+		chain = append(chain, directory)
+	}
+
+	if len(chain) == 0 {
+		return nil
+	}
+
+	if language != localeDefault {
+		if directory, ok := directories[localeDefault]; ok {
+			chain = append(chain, directory)
+		}
+	}
+
+	return chain
+}
+
+// composeLocaleNamespace merges a namespace of each locale in the chain with the more specific locales taking
+// precedence.
+func composeLocaleNamespace(chain []string, namespace string) (data []byte, err error) {
+	var found bool
+
+	composed := map[string]interface{}{}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		var raw []byte
+
+		if raw, err = locales.ReadFile(fmt.Sprintf("locales/%s/%s.json", chain[i], namespace)); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		values := map[string]interface{}{}
+
+		if err = json.Unmarshal(raw, &values); err != nil {
+			return nil, fmt.Errorf("error parsing locale '%s' namespace '%s': %w", chain[i], namespace, err)
+		}
+
+		mergeLocaleValues(composed, values)
+
+		found = true
+	}
+
+	if !found {
+		return nil, fs.ErrNotExist
+	}
+
+	return json.Marshal(composed)
+}
+
+func mergeLocaleValues(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if srcNested, ok := value.(map[string]interface{}); ok {
+			if dstNested, ok := dst[key].(map[string]interface{}); ok {
+				mergeLocaleValues(dstNested, srcNested)
+
+				continue
+			}
+		}
+
+		dst[key] = value
+	}
+}
+
 func hfsHandleErr(ctx *fasthttp.RequestCtx, err error) {
 	switch {
 	case errors.Is(err, fs.ErrNotExist):
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestLocaleFallbackChain(t *testing.T) {
+	directories := map[string]string{"en": "en", "de": "de", "pt": "pt", "pt-br": "pt-BR"}
+
+	testCases := []struct {
+		name, language, variant string
+		expected                []string
+	}{
+		{"ShouldUseDefault", "en", "", []string{"en"}},
+		{"ShouldUseDefaultForDefaultVariant", "en", "us", []string{"en"}},
+		{"ShouldFallBackToDefault", "de", "", []string{"de", "en"}},
+		{"ShouldFallBackToLanguage", "de", "at", []string{"de", "en"}},
+		{"ShouldUseVariant", "pt", "br", []string{"pt-BR", "pt", "en"}},
+		{"ShouldNotResolveUnknownLanguage", "xx", "", nil},
+		{"ShouldNotResolveUnknownLanguageVariant", "xx", "yy", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, localeFallbackChain(directories, tc.language, tc.variant))
+		})
+	}
+}
+
+func TestMergeLocaleValues(t *testing.T) {
+	dst := map[string]interface{}{
+		"a": "default a",
+		"b": "default b",
+		"nested": map[string]interface{}{
+			"c": "default c",
+			"d": "default d",
+		},
+	}
+
+	mergeLocaleValues(dst, map[string]interface{}{
+		"a": "specific a",
+		"nested": map[string]interface{}{
+			"c": "specific c",
+		},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"a": "specific a",
+		"b": "default b",
+		"nested": map[string]interface{}{
+			"c": "specific c",
+			"d": "default d",
+		},
+	}, dst)
+}
+
+func TestLocalesEmbeddedHandler(t *testing.T) {
+	handler := newLocalesEmbeddedHandler()
+
+	testCases := []struct {
+		name, language, variant, namespace string
+		status                             int
+		expected                           map[string]string
+	}{
+		{"ShouldServeDefault", "en", "", "portal", fasthttp.StatusOK, map[string]string{"Cancel": "Cancel", "Are you still there?": "Are you still there?"}},
+		{"ShouldFillMissingKeysFromDefault", "de", "", "portal", fasthttp.StatusOK, map[string]string{"Cancel": "Abbrechen", "Are you still there?": "Are you still there?"}},
+		{"ShouldFallBackToLanguageForVariant", "de", "AT", "portal", fasthttp.StatusOK, map[string]string{"Cancel": "Abbrechen", "Are you still there?": "Are you still there?"}},
+		{"ShouldNotFindUnknownLanguage", "xx", "", "portal", fasthttp.StatusNotFound, nil},
+		{"ShouldNotFindUnknownNamespace", "de", "", "unknown", fasthttp.StatusNotFound, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+			ctx.SetUserValue("language", tc.language)
+			ctx.SetUserValue("namespace", tc.namespace)
+
+			if tc.variant != "" {
+				ctx.SetUserValue("variant", tc.variant)
+			}
+
+			handler(ctx)
+
+			require.Equal(t, tc.status, ctx.Response.StatusCode())
+
+			if tc.expected == nil {
+				return
+			}
+
+			values := map[string]interface{}{}
+
+			require.NoError(t, json.Unmarshal(ctx.Response.Body(), &values))
+
+			for key, expected := range tc.expected {
+				assert.Equal(t, expected, values[key])
+			}
+		})
+	}
+}
+
+func TestShouldServeTextFile(t *testing.T) {
+	handler := newTextHandler("User-agent: *\nDisallow: /\n")
+
+	ctx := &fasthttp.RequestCtx{}
+
+	handler(ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, "text/plain; charset=utf-8", string(ctx.Response.Header.ContentType()))
+	assert.Equal(t, "User-agent: *\nDisallow: /\n", string(ctx.Response.Body()))
+}
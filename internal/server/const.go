@@ -1,15 +1,22 @@
 package server
 
+import (
+	"errors"
+)
+
 const (
 	embeddedAssets = "public_html/"
 	swaggerAssets  = embeddedAssets + "api/"
 	apiFile        = "openapi.yml"
 	indexFile      = "index.html"
 	logoFile       = "logo.png"
+
+	pathRobotsTXT   = "/robots.txt"
+	pathSecurityTXT = "/.well-known/security.txt"
 )
 
 var (
-	rootFiles    = []string{"manifest.json", "robots.txt"}
+	rootFiles    = []string{"manifest.json"}
 	swaggerFiles = []string{
 		"favicon-16x16.png",
 		"favicon-32x32.png",
@@ -50,6 +57,15 @@ const (
 	schemeHTTPS = "https"
 )
 
+const (
+	themeAuto  = "auto"
+	themeLight = "light"
+	themeDark  = "dark"
+
+	headerAcceptCH                = "Accept-CH"
+	headerSecCHPrefersColorScheme = "Sec-CH-Prefers-Color-Scheme"
+)
+
 const healthCheckEnv = `# Written by Authelia Process
 X_AUTHELIA_HEALTHCHECK=1
 X_AUTHELIA_HEALTHCHECK_SCHEME=%s
@@ -59,7 +75,71 @@ X_AUTHELIA_HEALTHCHECK_PATH=%s
 `
 
 const (
-	cspDefaultTemplate    = "default-src 'self'; object-src 'none'; style-src 'self' 'nonce-%s'"
-	cspDefaultDevTemplate = "default-src 'self' 'unsafe-eval'; object-src 'none'; style-src 'self' 'nonce-%s'"
+	cspDefaultTemplate    = "base-uri 'self'; default-src 'self'; object-src 'none'; style-src 'self' 'nonce-%s'"
+	cspDefaultDevTemplate = "base-uri 'self'; default-src 'self' 'unsafe-eval'; object-src 'none'; style-src 'self' 'nonce-%s'"
 	cspNoncePlaceholder   = "${NONCE}"
+	cspErrorPageTemplate  = "base-uri 'self'; default-src 'none'; style-src 'nonce-%s'"
+)
+
+// localeDefault is the locale every other locale falls back to.
+const localeDefault = "en"
+
+const (
+	errorPageLocaleFile      = "errors.json"
+	errorPageOverrideDir     = "errors"
+	errorPageDefaultLanguage = localeDefault
+
+	errorPageTemplate = `<!DOCTYPE html>
+<html lang="{{ .Language }}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .StatusCode }} {{ .Title }}</title>
+<style nonce="{{ .CSPNonce }}">
+body { margin: 0; min-height: 100vh; display: flex; align-items: center; justify-content: center; font-family: Roboto, Helvetica, Arial, sans-serif; }
+main { text-align: center; padding: 2rem; }
+h1 { font-size: 4rem; margin: 0; }
+h2 { font-weight: 400; }
+a { color: inherit; }
+body.light { background: #fff; color: #000; }
+body.dark { background: #121212; color: #fff; }
+body.grey { background: #2a2a2a; color: #fff; }
+@media (prefers-color-scheme: dark) { body.auto { background: #121212; color: #fff; } }
+</style>
+</head>
+<body class="{{ .Theme }}">
+<main>
+<h1>{{ .StatusCode }}</h1>
+<h2>{{ .Title }}</h2>
+{{- if .Description }}
+<p>{{ .Description }}</p>
+{{- end }}
+{{- if .Help.Message }}
+<p>{{ .Help.Message }}</p>
+{{- end }}
+{{- if .Help.URL }}
+<p><a href="{{ .Help.URL }}">{{ .Help.URL }}</a></p>
+{{- end }}
+</main>
+</body>
+</html>
+`
+)
+
+const (
+	proxyProtocolV1Prefix    = "PROXY "
+	proxyProtocolV1MaxLength = 107
+
+	proxyProtocolV2CommandLocal = 0x0
+	proxyProtocolV2CommandProxy = 0x1
+
+	proxyProtocolV2FamilyInet  = 0x1
+	proxyProtocolV2FamilyInet6 = 0x2
+)
+
+var proxyProtocolV2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+var (
+	errProxyProtocolHeaderMissing   = errors.New("connection did not start with a PROXY protocol header")
+	errProxyProtocolHeaderV1TooLong = errors.New("invalid version 1 header: header exceeds the maximum length")
 )
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/handlers"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// errorPageMessage is the localized title and description of the error page for a status code.
+type errorPageMessage struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// errorPageRenderer writes the error page for a status code to clients which accept HTML, other clients receive the
+// plain text status response. The page is localized using the errors namespace of the embedded locales, and can be
+// overridden per status code by placing an errors/<code>.html or errors/<code>.<language>.html file in the asset path.
+type errorPageRenderer struct {
+	assetPath string
+	theme     string
+	messages  map[string]map[string]errorPageMessage
+	tmpl      *template.Template
+}
+
+func newErrorPageRenderer(assetPath, theme string) (renderer *errorPageRenderer) {
+	logger := logging.Logger()
+
+	renderer = &errorPageRenderer{
+		assetPath: assetPath,
+		theme:     theme,
+		messages:  map[string]map[string]errorPageMessage{},
+		tmpl:      template.Must(template.New("error").Parse(errorPageTemplate)),
+	}
+
+	entries, err := locales.ReadDir("locales")
+	if err != nil {
+		logger.Errorf("Unable to read the error page locales: %v", err)
+
+		return renderer
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		data, err := locales.ReadFile(fmt.Sprintf("locales/%s/%s", entry.Name(), errorPageLocaleFile))
+		if err != nil {
+			continue
+		}
+
+		messages := map[string]errorPageMessage{}
+
+		if err = json.Unmarshal(data, &messages); err != nil {
+			logger.Errorf("Unable to parse the error page locale '%s': %v", entry.Name(), err)
+
+			continue
+		}
+
+		renderer.messages[entry.Name()] = messages
+	}
+
+	return renderer
+}
+
+// errorPageHelp is the message and the URL shown on the error page to help the user, such as the contact details of the
+// administrators.
+type errorPageHelp struct {
+	Message string
+	URL     string
+}
+
+func (r *errorPageRenderer) write(ctx *fasthttp.RequestCtx, statusCode int) {
+	r.writeWithHelp(ctx, statusCode, errorPageHelp{})
+}
+
+// writeWithHelp writes the error page for the status code including the help. The help is escaped by the template, and
+// it's appended to the plain text status response for clients which don't accept HTML. The overrides from the asset
+// path never include the help.
+func (r *errorPageRenderer) writeWithHelp(ctx *fasthttp.RequestCtx, statusCode int, help errorPageHelp) {
+	if !bytes.Contains(ctx.Request.Header.Peek(fasthttp.HeaderAccept), []byte("text/html")) {
+		handlers.SetStatusCodeResponse(ctx, statusCode)
+
+		for _, line := range []string{help.Message, help.URL} {
+			if line != "" {
+				ctx.Response.AppendBodyString("\n" + line)
+			}
+		}
+
+		return
+	}
+
+	language := r.language(ctx)
+
+	ctx.SetStatusCode(statusCode)
+	ctx.SetContentType("text/html; charset=utf-8")
+
+	if body, ok := r.override(statusCode, language); ok {
+		ctx.SetBody(body)
+
+		return
+	}
+
+	message, ok := r.messages[language][strconv.Itoa(statusCode)]
+	if !ok {
+		message = errorPageMessage{Title: fasthttp.StatusMessage(statusCode)}
+	}
+
+	nonce := utils.RandomString(32, utils.AlphaNumericCharacters, true)
+
+	ctx.Response.Header.Set(fasthttp.HeaderContentSecurityPolicy, fmt.Sprintf(cspErrorPageTemplate, nonce))
+
+	data := struct {
+		StatusCode                                    int
+		CSPNonce, Description, Language, Theme, Title string
+		Help                                          errorPageHelp
+	}{statusCode, nonce, message.Description, language, resolveTheme(ctx, r.theme), message.Title, help}
+
+	if err := r.tmpl.Execute(ctx.Response.BodyWriter(), data); err != nil {
+		logging.Logger().Errorf("Unable to execute the error page template: %v", err)
+
+		handlers.SetStatusCodeResponse(ctx, statusCode)
+	}
+}
+
+// override returns the content of the override for the status code from the asset path, preferring the override for
+// the language of the client.
+func (r *errorPageRenderer) override(statusCode int, language string) (body []byte, ok bool) {
+	if r.assetPath == "" {
+		return nil, false
+	}
+
+	for _, name := range []string{fmt.Sprintf("%d.%s.html", statusCode, language), fmt.Sprintf("%d.html", statusCode)} {
+		if body, err := os.ReadFile(filepath.Join(r.assetPath, errorPageOverrideDir, name)); err == nil {
+			return body, true
+		}
+	}
+
+	return nil, false
+}
+
+// language returns the first language in the Accept-Language header of the request which has error page messages,
+// otherwise the default language.
+func (r *errorPageRenderer) language(ctx *fasthttp.RequestCtx) string {
+	for _, value := range strings.Split(string(ctx.Request.Header.Peek(fasthttp.HeaderAcceptLanguage)), ",") {
+		if i := strings.Index(value, ";"); i != -1 {
+			value = value[:i]
+		}
+
+		if i := strings.Index(value, "-"); i != -1 {
+			value = value[:i]
+		}
+
+		value = strings.ToLower(strings.TrimSpace(value))
+
+		if _, ok := r.messages[value]; ok {
+			return value
+		}
+	}
+
+	return errorPageDefaultLanguage
+}
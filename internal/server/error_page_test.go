@@ -0,0 +1,205 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+)
+
+func TestErrorPageRenderer_ShouldWritePlainTextWhenHTMLNotAccepted(t *testing.T) {
+	renderer := newErrorPageRenderer("", "light")
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.Set(fasthttp.HeaderAccept, "application/json")
+
+	renderer.write(ctx, fasthttp.StatusNotFound)
+
+	assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+	assert.Equal(t, "404 Not Found", string(ctx.Response.Body()))
+}
+
+func TestErrorPageRenderer_ShouldWriteLocalizedPage(t *testing.T) {
+	renderer := newErrorPageRenderer("", "dark")
+
+	testCases := []struct {
+		name, acceptLanguage, language, title string
+	}{
+		{"ShouldDefaultToEnglish", "", "en", "Not Found"},
+		{"ShouldUseLanguageWithVariant", "de-DE,de;q=0.9,en;q=0.8", "de", "Nicht gefunden"},
+		{"ShouldSkipUnknownLanguages", "xx, es;q=0.5", "es", "No encontrado"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.Set(fasthttp.HeaderAccept, "text/html,application/xhtml+xml")
+			ctx.Request.Header.Set(fasthttp.HeaderAcceptLanguage, tc.acceptLanguage)
+
+			renderer.write(ctx, fasthttp.StatusNotFound)
+
+			body := string(ctx.Response.Body())
+
+			assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+			assert.Equal(t, "text/html; charset=utf-8", string(ctx.Response.Header.ContentType()))
+			assert.Contains(t, body, `<html lang="`+tc.language+`">`)
+			assert.Contains(t, body, "<h2>"+tc.title+"</h2>")
+			assert.Contains(t, body, `<body class="dark">`)
+			assert.Regexp(t, `^base-uri 'self'; default-src 'none'; style-src 'nonce-[a-zA-Z0-9]{32}'$`, string(ctx.Response.Header.Peek(fasthttp.HeaderContentSecurityPolicy)))
+		})
+	}
+}
+
+func TestErrorPageRenderer_ShouldFallBackToStatusMessage(t *testing.T) {
+	renderer := newErrorPageRenderer("", "light")
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.Set(fasthttp.HeaderAccept, "text/html")
+
+	renderer.write(ctx, fasthttp.StatusTeapot)
+
+	assert.Equal(t, fasthttp.StatusTeapot, ctx.Response.StatusCode())
+	assert.Contains(t, string(ctx.Response.Body()), "<h2>I&#39;m a teapot</h2>")
+}
+
+func TestErrorPageRenderer_ShouldServeOverrides(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, errorPageOverrideDir), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, errorPageOverrideDir, "404.html"), []byte("custom not found"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, errorPageOverrideDir, "404.de.html"), []byte("nicht gefunden"), 0600))
+
+	renderer := newErrorPageRenderer(dir, "light")
+
+	testCases := []struct {
+		name, acceptLanguage string
+		statusCode           int
+		expected             string
+	}{
+		{"ShouldServeOverride", "en", fasthttp.StatusNotFound, "custom not found"},
+		{"ShouldServeLanguageOverride", "de", fasthttp.StatusNotFound, "nicht gefunden"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.Set(fasthttp.HeaderAccept, "text/html")
+			ctx.Request.Header.Set(fasthttp.HeaderAcceptLanguage, tc.acceptLanguage)
+
+			renderer.write(ctx, tc.statusCode)
+
+			assert.Equal(t, tc.statusCode, ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(ctx.Response.Body()))
+		})
+	}
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.Set(fasthttp.HeaderAccept, "text/html")
+
+	renderer.write(ctx, fasthttp.StatusMethodNotAllowed)
+
+	assert.Contains(t, string(ctx.Response.Body()), "<h2>Method Not Allowed</h2>")
+}
+
+func TestErrorPageRenderer_ShouldResolveAutoTheme(t *testing.T) {
+	renderer := newErrorPageRenderer("", "auto")
+
+	testCases := []struct {
+		name, hint, expected string
+	}{
+		{"ShouldUseDarkHint", `"dark"`, "dark"},
+		{"ShouldUseLightHint", "light", "light"},
+		{"ShouldKeepAutoWithoutHint", "", "auto"},
+		{"ShouldKeepAutoWithUnknownHint", `"sepia"`, "auto"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.Set(fasthttp.HeaderAccept, "text/html")
+
+			if tc.hint != "" {
+				ctx.Request.Header.Set(headerSecCHPrefersColorScheme, tc.hint)
+			}
+
+			renderer.write(ctx, fasthttp.StatusNotFound)
+
+			assert.Contains(t, string(ctx.Response.Body()), `<body class="`+tc.expected+`">`)
+			assert.Equal(t, headerSecCHPrefersColorScheme, string(ctx.Response.Header.Peek(headerAcceptCH)))
+			assert.Equal(t, headerSecCHPrefersColorScheme, string(ctx.Response.Header.Peek(fasthttp.HeaderVary)))
+		})
+	}
+}
+
+func TestErrorPageRenderer_ShouldNotRequestClientHintsForExplicitTheme(t *testing.T) {
+	renderer := newErrorPageRenderer("", "grey")
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.Set(fasthttp.HeaderAccept, "text/html")
+	ctx.Request.Header.Set(headerSecCHPrefersColorScheme, `"dark"`)
+
+	renderer.write(ctx, fasthttp.StatusNotFound)
+
+	assert.Contains(t, string(ctx.Response.Body()), `<body class="grey">`)
+	assert.Empty(t, ctx.Response.Header.Peek(headerAcceptCH))
+}
+
+func TestErrorPageRenderer_ShouldWriteEscapedHelp(t *testing.T) {
+	renderer := newErrorPageRenderer("", "light")
+
+	help := errorPageHelp{Message: "Contact <b>IT</b>.", URL: "mailto:help@example.com?subject=access&body=denied"}
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.Set(fasthttp.HeaderAccept, "text/html")
+
+	renderer.writeWithHelp(ctx, fasthttp.StatusForbidden, help)
+
+	body := string(ctx.Response.Body())
+
+	assert.Equal(t, fasthttp.StatusForbidden, ctx.Response.StatusCode())
+	assert.Contains(t, body, "<p>Contact &lt;b&gt;IT&lt;/b&gt;.</p>")
+	assert.Contains(t, body, `<p><a href="mailto:help@example.com?subject=access&amp;body=denied">mailto:help@example.com?subject=access&amp;body=denied</a></p>`)
+
+	ctx = &fasthttp.RequestCtx{}
+	ctx.Request.Header.Set(fasthttp.HeaderAccept, "application/json")
+
+	renderer.writeWithHelp(ctx, fasthttp.StatusForbidden, help)
+
+	assert.Equal(t, "403 Forbidden\nContact <b>IT</b>.\nmailto:help@example.com?subject=access&body=denied", string(ctx.Response.Body()))
+}
+
+func TestHandlerDenyResponse_ShouldWriteHelpOfDenyResponse(t *testing.T) {
+	renderer := newErrorPageRenderer("", "light")
+
+	handler := handlerDenyResponse(renderer, func(ctx *fasthttp.RequestCtx) {
+		ctx.Error(fasthttp.StatusMessage(fasthttp.StatusForbidden), fasthttp.StatusForbidden)
+
+		if string(ctx.Path()) == "/help" {
+			ctx.SetUserValueBytes(middlewares.UserValueKeyDenyResponse, &schema.ACLDenyResponse{HelpURL: "https://help.example.com"})
+		}
+	})
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.Set(fasthttp.HeaderAccept, "text/html")
+	ctx.Request.SetRequestURI("/help")
+
+	handler(ctx)
+
+	assert.Equal(t, fasthttp.StatusForbidden, ctx.Response.StatusCode())
+	assert.Equal(t, "text/html; charset=utf-8", string(ctx.Response.Header.ContentType()))
+	assert.Contains(t, string(ctx.Response.Body()), `<a href="https://help.example.com">`)
+
+	ctx = &fasthttp.RequestCtx{}
+	ctx.Request.Header.Set(fasthttp.HeaderAccept, "text/html")
+	ctx.Request.SetRequestURI("/")
+
+	handler(ctx)
+
+	assert.Equal(t, "Forbidden", string(ctx.Response.Body()))
+}
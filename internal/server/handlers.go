@@ -1,13 +1,14 @@
 package server
 
 import (
+	"encoding/json"
+	"html"
 	"net"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
-	duoapi "github.com/duosecurity/duo_api_golang"
 	"github.com/fasthttp/router"
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/expvarhandler"
@@ -60,13 +61,13 @@ func handlerError() func(ctx *fasthttp.RequestCtx, err error) {
 	}
 }
 
-func handlerNotFound(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+func handlerNotFound(errorPages *errorPageRenderer, next fasthttp.RequestHandler) fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
 		path := strings.ToLower(string(ctx.Path()))
 
 		for i := 0; i < len(httpServerDirs); i++ {
 			if path == httpServerDirs[i].name || strings.HasPrefix(path, httpServerDirs[i].prefix) {
-				handlers.SetStatusCodeResponse(ctx, fasthttp.StatusNotFound)
+				errorPages.write(ctx, fasthttp.StatusNotFound)
 
 				return
 			}
@@ -76,8 +77,65 @@ func handlerNotFound(next fasthttp.RequestHandler) fasthttp.RequestHandler {
 	}
 }
 
-func handlerMethodNotAllowed(ctx *fasthttp.RequestCtx) {
-	handlers.SetStatusCodeResponse(ctx, fasthttp.StatusMethodNotAllowed)
+// handlerDenyResponse writes the error page including the help of the deny response when the next handler forbade the
+// user from accessing a resource with a deny response which has help.
+func handlerDenyResponse(errorPages *errorPageRenderer, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		next(ctx)
+
+		if response, ok := ctx.UserValueBytes(middlewares.UserValueKeyDenyResponse).(*schema.ACLDenyResponse); ok {
+			errorPages.writeWithHelp(ctx, ctx.Response.StatusCode(), errorPageHelp{Message: response.HelpMessage, URL: response.HelpURL})
+		}
+	}
+}
+
+func handlerMethodNotAllowed(errorPages *errorPageRenderer) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		errorPages.write(ctx, fasthttp.StatusMethodNotAllowed)
+	}
+}
+
+// newOpenIDConnectClientOriginsFunc returns a middlewares.CORSAllowedOriginsFunc which returns the allowed origins
+// registered to the OpenID Connect client making the request. The client is determined from the client_id form value,
+// the HTTP Basic Authorization header, or the access token presented to the userinfo endpoint. If it can't be determined
+// or has no allowed origins it returns nil so the global allowed origins apply.
+//
+// Preflight requests carry no credentials so the client can't be determined, the origins of every client are permitted
+// in addition to the global allowed origins and the actual request is then checked against the origins of its client.
+func newOpenIDConnectClientOriginsFunc(store *oidc.OpenIDConnectStore, allowedOrigins []string) middlewares.CORSAllowedOriginsFunc {
+	return func(ctx *fasthttp.RequestCtx) (origins []string) {
+		if ctx.IsOptions() {
+			// When no global origins are configured, or the wildcard origin is, every origin is already permitted.
+			if len(allowedOrigins) == 0 || utils.IsStringInSlice("*", allowedOrigins) {
+				return nil
+			}
+
+			if origins = store.GetAllowedOrigins(); len(origins) == 0 {
+				return nil
+			}
+
+			return append(append([]string(nil), allowedOrigins...), origins...)
+		}
+
+		id := middlewares.OpenIDConnectClientIDFromRequest(ctx)
+
+		if id == "" {
+			if token := middlewares.OpenIDConnectAccessTokenFromRequest(ctx); token != "" {
+				id, _ = store.GetAccessTokenClientID(ctx, token)
+			}
+		}
+
+		if id == "" {
+			return nil
+		}
+
+		client, err := store.GetFullClient(id)
+		if err != nil || len(client.AllowedOrigins) == 0 {
+			return nil
+		}
+
+		return client.AllowedOrigins
+	}
 }
 
 func getHandler(config schema.Configuration, providers middlewares.Providers) fasthttp.RequestHandler {
@@ -91,15 +149,25 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 		duoSelfEnrollment = strconv.FormatBool(config.DuoAPI.EnableSelfEnrollment)
 	}
 
+	// The capabilities are placed in a HTML attribute so they must be escaped.
+	capabilities, err := json.Marshal(handlers.NewCapabilities(&config))
+	if err != nil {
+		logging.Logger().Fatalf("Unable to encode the capabilities: %v", err)
+	}
+
+	capabilitiesAttribute := html.EscapeString(string(capabilities))
+
 	https := config.Server.TLS.Key != "" && config.Server.TLS.Certificate != ""
 
-	serveIndexHandler := ServeTemplatedFile(embeddedAssets, indexFile, config.Server.AssetPath, duoSelfEnrollment, rememberMe, resetPassword, resetPasswordCustomURL, config.Session.Name, config.Theme, https)
-	serveSwaggerHandler := ServeTemplatedFile(swaggerAssets, indexFile, config.Server.AssetPath, duoSelfEnrollment, rememberMe, resetPassword, resetPasswordCustomURL, config.Session.Name, config.Theme, https)
-	serveSwaggerAPIHandler := ServeTemplatedFile(swaggerAssets, apiFile, config.Server.AssetPath, duoSelfEnrollment, rememberMe, resetPassword, resetPasswordCustomURL, config.Session.Name, config.Theme, https)
+	serveIndexHandler := ServeTemplatedFile(embeddedAssets, indexFile, config.Server.AssetPath, capabilitiesAttribute, duoSelfEnrollment, rememberMe, resetPassword, resetPasswordCustomURL, config.AuthenticationBackend.PasswordReset.Method, config.Session.Name, config.Theme, config.ThemeFallback, https)
+	serveSwaggerHandler := ServeTemplatedFile(swaggerAssets, indexFile, config.Server.AssetPath, capabilitiesAttribute, duoSelfEnrollment, rememberMe, resetPassword, resetPasswordCustomURL, config.AuthenticationBackend.PasswordReset.Method, config.Session.Name, config.Theme, config.ThemeFallback, https)
+	serveSwaggerAPIHandler := ServeTemplatedFile(swaggerAssets, apiFile, config.Server.AssetPath, capabilitiesAttribute, duoSelfEnrollment, rememberMe, resetPassword, resetPasswordCustomURL, config.AuthenticationBackend.PasswordReset.Method, config.Session.Name, config.Theme, config.ThemeFallback, https)
 
 	handlerPublicHTML := newPublicHTMLEmbeddedHandler()
 	handlerLocales := newLocalesEmbeddedHandler()
 
+	errorPages := newErrorPageRenderer(config.Server.AssetPath, config.Theme)
+
 	middleware := middlewares.AutheliaMiddleware(config, providers)
 
 	policyCORSPublicGET := middlewares.NewCORSPolicyBuilder().
@@ -107,6 +175,12 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 		WithAllowedOrigins("*").
 		Build()
 
+	// requireRecentAuthn wraps the handlers of sensitive actions which may require the user to have recently completed a
+	// factor.
+	requireRecentAuthn := func(action string, next middlewares.RequestHandler) middlewares.RequestHandler {
+		return middlewares.Require1FA(middlewares.RequireRecentAuthentication(config.SensitiveActions, action, next))
+	}
+
 	r := router.New()
 
 	// Static Assets.
@@ -116,6 +190,14 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 		r.GET("/"+f, handlerPublicHTML)
 	}
 
+	r.GET(pathRobotsTXT, newTextHandler(config.Server.RobotsTXT))
+
+	// The security.txt is only served when it's configured as it requires contact information, otherwise the request is
+	// handled like any other unknown path within the /.well-known directory.
+	if config.Server.SecurityTXT != "" {
+		r.GET(pathSecurityTXT, newTextHandler(config.Server.SecurityTXT))
+	}
+
 	r.GET("/favicon.ico", middlewares.AssetOverrideMiddleware(config.Server.AssetPath, 0, handlerPublicHTML))
 	r.GET("/static/media/logo.png", middlewares.AssetOverrideMiddleware(config.Server.AssetPath, 2, handlerPublicHTML))
 	r.GET("/static/{filepath:*}", handlerPublicHTML)
@@ -141,8 +223,8 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 
 	r.GET("/api/configuration/password-policy", middleware(handlers.PasswordPolicyConfigurationGet))
 
-	r.GET("/api/verify", middleware(handlers.VerifyGET(config.AuthenticationBackend)))
-	r.HEAD("/api/verify", middleware(handlers.VerifyGET(config.AuthenticationBackend)))
+	r.GET("/api/verify", handlerDenyResponse(errorPages, middleware(handlers.VerifyGET(config.AuthenticationBackend))))
+	r.HEAD("/api/verify", handlerDenyResponse(errorPages, middleware(handlers.VerifyGET(config.AuthenticationBackend))))
 
 	r.POST("/api/checks/safe-redirection", middleware(handlers.CheckSafeRedirectionPOST))
 
@@ -150,6 +232,48 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 
 	r.POST("/api/firstfactor", middleware(handlers.FirstFactorPOST(delayFunc)))
 	r.POST("/api/logout", middleware(handlers.LogoutPOST))
+	r.POST("/api/session/refresh", middleware(middlewares.Require1FA(handlers.SessionRefreshPOST)))
+
+	// Only register the session introspection endpoint if it is enabled.
+	if config.Session.Introspection.Enabled {
+		r.POST("/api/session/introspect", middleware(middlewares.RateLimit(config.Session.Introspection.RateLimit,
+			config.Session.Introspection.RateLimitPeriod, handlers.SessionIntrospectPOST)))
+	}
+
+	// Only register the login notification suppression endpoint if it is enabled.
+	if config.LoginNotifications.Enabled {
+		r.GET("/api/login-notifications/suppress", middleware(handlers.LoginNotificationSuppressGET))
+	}
+
+	// Only register the regulation unlock endpoint if it is enabled.
+	if config.Regulation.Unlock.Enabled {
+		r.GET("/api/regulation/unlock", middleware(handlers.RegulationUnlockGET))
+	}
+
+	// Only register self-registration endpoints if it is enabled.
+	if config.SelfRegistration.Enabled {
+		r.POST("/api/registration", middleware(middlewares.RateLimit(config.SelfRegistration.RateLimit,
+			config.SelfRegistration.RateLimitPeriod, handlers.RegistrationPOST)))
+		r.GET("/api/registration/pending", middleware(middlewares.Require1FA(handlers.RegistrationsPendingGET)))
+		r.POST("/api/registration/approval", middleware(middlewares.Require1FA(handlers.RegistrationApprovalPOST)))
+
+		if config.SelfRegistration.EmailVerification.Enabled {
+			r.GET("/api/registration/email/verify", middleware(handlers.RegistrationEmailVerificationGET))
+		}
+	}
+
+	// Only register the administration endpoints if an administrator group is configured.
+	if config.AuthenticationBackend.AdminGroup != "" {
+		// Only the file backend supports disabling users.
+		if config.AuthenticationBackend.File != nil {
+			r.POST("/api/admin/user/disabled", middleware(requireRecentAuthn(schema.SensitiveActionAdministration, handlers.UserDisabledPOST)))
+		}
+
+		if !config.Webauthn.Disable {
+			r.POST("/api/admin/webauthn/enrollment/start", middleware(requireRecentAuthn(schema.SensitiveActionAdministration, handlers.WebauthnEnrollmentStartPOST)))
+			r.POST("/api/admin/webauthn/enrollment/finish", middleware(requireRecentAuthn(schema.SensitiveActionAdministration, handlers.WebauthnEnrollmentFinishPOST)))
+		}
+	}
 
 	// Only register endpoints if forgot password is not disabled.
 	if !config.AuthenticationBackend.DisableResetPassword &&
@@ -162,20 +286,24 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 
 	// Information about the user.
 	r.GET("/api/user/info", middleware(middlewares.Require1FA(handlers.UserInfoGET)))
+	r.GET("/api/user/activity", middleware(middlewares.Require1FA(handlers.UserActivityGET)))
 	r.POST("/api/user/info", middleware(middlewares.Require1FA(handlers.UserInfoPOST)))
-	r.POST("/api/user/info/2fa_method", middleware(middlewares.Require1FA(handlers.MethodPreferencePOST)))
+	r.POST("/api/user/info/2fa_method", middleware(requireRecentAuthn(schema.SensitiveActionPreferredMethod, handlers.MethodPreferencePOST)))
 
 	if !config.TOTP.Disable {
 		// TOTP related endpoints.
 		r.GET("/api/user/info/totp", middleware(middlewares.Require1FA(handlers.UserTOTPInfoGET)))
-		r.POST("/api/secondfactor/totp/identity/start", middleware(middlewares.Require1FA(handlers.TOTPIdentityStart)))
+		r.DELETE("/api/user/info/totp/{id:[0-9]+}", middleware(requireRecentAuthn(schema.SensitiveActionTOTPDeletion, handlers.UserTOTPDELETE)))
+		r.POST("/api/secondfactor/totp/identity/start", middleware(requireRecentAuthn(schema.SensitiveActionTOTPRegistration, handlers.TOTPIdentityStart)))
 		r.POST("/api/secondfactor/totp/identity/finish", middleware(middlewares.Require1FA(handlers.TOTPIdentityFinish)))
 		r.POST("/api/secondfactor/totp", middleware(middlewares.Require1FA(handlers.TimeBasedOneTimePasswordPOST)))
+		r.POST("/api/secondfactor/totp/rotation/start", middleware(requireRecentAuthn(schema.SensitiveActionTOTPRotation, handlers.TOTPRotationStartPOST)))
+		r.POST("/api/secondfactor/totp/rotation/finish", middleware(requireRecentAuthn(schema.SensitiveActionTOTPRotation, handlers.TOTPRotationFinishPOST)))
 	}
 
 	if !config.Webauthn.Disable {
 		// Webauthn Endpoints.
-		r.POST("/api/secondfactor/webauthn/identity/start", middleware(middlewares.Require1FA(handlers.WebauthnIdentityStart)))
+		r.POST("/api/secondfactor/webauthn/identity/start", middleware(requireRecentAuthn(schema.SensitiveActionWebauthnRegistration, handlers.WebauthnIdentityStart)))
 		r.POST("/api/secondfactor/webauthn/identity/finish", middleware(middlewares.Require1FA(handlers.WebauthnIdentityFinish)))
 		r.POST("/api/secondfactor/webauthn/attestation", middleware(middlewares.Require1FA(handlers.WebauthnAttestationPOST)))
 
@@ -185,22 +313,11 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 
 	// Configure DUO api endpoint only if configuration exists.
 	if config.DuoAPI != nil {
-		var duoAPI duo.API
-		if os.Getenv("ENVIRONMENT") == dev {
-			duoAPI = duo.NewDuoAPI(duoapi.NewDuoApi(
-				config.DuoAPI.IntegrationKey,
-				config.DuoAPI.SecretKey,
-				config.DuoAPI.Hostname, "", duoapi.SetInsecure()))
-		} else {
-			duoAPI = duo.NewDuoAPI(duoapi.NewDuoApi(
-				config.DuoAPI.IntegrationKey,
-				config.DuoAPI.SecretKey,
-				config.DuoAPI.Hostname, ""))
-		}
+		duoAPI := duo.NewDuoAPI(config.DuoAPI, os.Getenv("ENVIRONMENT") == dev)
 
 		r.GET("/api/secondfactor/duo_devices", middleware(middlewares.Require1FA(handlers.DuoDevicesGET(duoAPI))))
 		r.POST("/api/secondfactor/duo", middleware(middlewares.Require1FA(handlers.DuoPOST(duoAPI))))
-		r.POST("/api/secondfactor/duo_device", middleware(middlewares.Require1FA(handlers.DuoDevicePOST)))
+		r.POST("/api/secondfactor/duo_device", middleware(requireRecentAuthn(schema.SensitiveActionDuoDeviceSelection, handlers.DuoDevicePOST)))
 	}
 
 	if config.Server.EnablePprof {
@@ -215,7 +332,11 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 		r.GET("/api/oidc/consent", middleware(handlers.OpenIDConnectConsentGET))
 		r.POST("/api/oidc/consent", middleware(handlers.OpenIDConnectConsentPOST))
 
+		r.GET("/api/user/oidc/refresh-tokens", middleware(middlewares.Require1FA(handlers.OpenIDConnectRefreshTokensGET)))
+		r.POST("/api/user/oidc/refresh-tokens/revoke", middleware(requireRecentAuthn(schema.SensitiveActionOpenIDConnectRefreshTokenRevocation, handlers.OpenIDConnectRefreshTokensRevokePOST)))
+
 		allowedOrigins := utils.StringSliceFromURLs(config.IdentityProviders.OIDC.CORS.AllowedOrigins)
+		allowedOriginsClient := newOpenIDConnectClientOriginsFunc(providers.OpenIDConnect.Store, allowedOrigins)
 
 		r.OPTIONS(oidc.WellKnownOpenIDConfigurationPath, policyCORSPublicGET.HandleOPTIONS)
 		r.GET(oidc.WellKnownOpenIDConfigurationPath, policyCORSPublicGET.Middleware(middleware(handlers.OpenIDConnectConfigurationWellKnownGET)))
@@ -247,16 +368,19 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 			WithAllowCredentials(true).
 			WithAllowedMethods("OPTIONS", "POST").
 			WithAllowedOrigins(allowedOrigins...).
+			WithAllowedOriginsFunc(allowedOriginsClient).
 			WithEnabled(utils.IsStringInSlice(oidc.TokenEndpoint, config.IdentityProviders.OIDC.CORS.Endpoints)).
 			Build()
 
 		r.OPTIONS(oidc.TokenPath, policyCORSToken.HandleOPTIONS)
-		r.POST(oidc.TokenPath, policyCORSToken.Middleware(middleware(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OpenIDConnectTokenPOST))))
+		r.POST(oidc.TokenPath, policyCORSToken.Middleware(middleware(middlewares.OpenIDConnectTokenLimit(config.IdentityProviders.OIDC.TokenEndpoint,
+			providers.OpenIDConnect.Store.IsFirstPartyClient, middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OpenIDConnectTokenPOST)))))
 
 		policyCORSUserinfo := middlewares.NewCORSPolicyBuilder().
 			WithAllowCredentials(true).
 			WithAllowedMethods("OPTIONS", "GET", "POST").
 			WithAllowedOrigins(allowedOrigins...).
+			WithAllowedOriginsFunc(allowedOriginsClient).
 			WithEnabled(utils.IsStringInSlice(oidc.UserinfoEndpoint, config.IdentityProviders.OIDC.CORS.Endpoints)).
 			Build()
 
@@ -268,6 +392,7 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 			WithAllowCredentials(true).
 			WithAllowedMethods("OPTIONS", "POST").
 			WithAllowedOrigins(allowedOrigins...).
+			WithAllowedOriginsFunc(allowedOriginsClient).
 			WithEnabled(utils.IsStringInSlice(oidc.IntrospectionEndpoint, config.IdentityProviders.OIDC.CORS.Endpoints)).
 			Build()
 
@@ -282,6 +407,7 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 			WithAllowCredentials(true).
 			WithAllowedMethods("OPTIONS", "POST").
 			WithAllowedOrigins(allowedOrigins...).
+			WithAllowedOriginsFunc(allowedOriginsClient).
 			WithEnabled(utils.IsStringInSlice(oidc.RevocationEndpoint, config.IdentityProviders.OIDC.CORS.Endpoints)).
 			Build()
 
@@ -293,12 +419,14 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 		r.POST("/api/oidc/revoke", policyCORSRevocation.Middleware(middleware(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OAuthRevocationPOST))))
 	}
 
-	r.NotFound = handlerNotFound(middleware(serveIndexHandler))
+	r.NotFound = handlerNotFound(errorPages, middleware(serveIndexHandler))
 
 	r.HandleMethodNotAllowed = true
-	r.MethodNotAllowed = handlerMethodNotAllowed
+	r.MethodNotAllowed = handlerMethodNotAllowed(errorPages)
+
+	r.SaveMatchedRoutePath = config.Log.SlowRequestThreshold > 0
 
-	handler := middlewares.LogRequestMiddleware(r.Handler)
+	handler := middlewares.LogRequestMiddleware(config.Log.SlowRequestThreshold, middlewares.SecurityHeadersMiddleware(config.Server.Headers, r.Handler))
 	if config.Server.Path != "" {
 		handler = middlewares.StripPathMiddleware(config.Server.Path, handler)
 	}
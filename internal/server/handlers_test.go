@@ -0,0 +1,148 @@
+package server
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/storage"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func TestShouldReturnOpenIDConnectClientAllowedOrigins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	storageMock := mocks.NewMockStorage(ctrl)
+
+	storageMock.EXPECT().
+		LoadOAuth2Session(gomock.Any(), storage.OAuth2SessionTypeAccessToken, "signature").
+		Return(&model.OAuth2Session{ClientID: "app", Active: true}, nil).AnyTimes()
+	storageMock.EXPECT().
+		LoadOAuth2Session(gomock.Any(), storage.OAuth2SessionTypeAccessToken, "inactive").
+		Return(&model.OAuth2Session{ClientID: "app", Active: false}, nil).AnyTimes()
+	storageMock.EXPECT().
+		LoadOAuth2Session(gomock.Any(), storage.OAuth2SessionTypeAccessToken, "unknown").
+		Return(nil, sql.ErrNoRows).AnyTimes()
+
+	store := oidc.NewOpenIDConnectStore(&schema.OpenIDConnectConfiguration{
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:             "app",
+				Policy:         "two_factor",
+				AllowedOrigins: utils.URLsFromStringSlice([]string{"https://app.example.com"}),
+			},
+			{
+				ID:     "other app",
+				Policy: "two_factor",
+			},
+		},
+	}, storageMock)
+
+	originsFunc := newOpenIDConnectClientOriginsFunc(store, []string{"https://example.com"})
+
+	testCases := []struct {
+		name     string
+		setup    func(ctx *fasthttp.RequestCtx)
+		expected []string
+	}{
+		{
+			"ShouldResolveFromForm",
+			func(ctx *fasthttp.RequestCtx) {
+				ctx.Request.Header.SetContentType("application/x-www-form-urlencoded")
+				ctx.Request.SetBodyString("grant_type=authorization_code&client_id=app")
+			},
+			[]string{"https://app.example.com"},
+		},
+		{
+			"ShouldResolveFromBasicAuthorization",
+			func(ctx *fasthttp.RequestCtx) {
+				ctx.Request.Header.Set(fasthttp.HeaderAuthorization, "Basic "+base64.StdEncoding.EncodeToString([]byte("app:secret")))
+			},
+			[]string{"https://app.example.com"},
+		},
+		{
+			"ShouldReturnNilForClientWithoutAllowedOrigins",
+			func(ctx *fasthttp.RequestCtx) {
+				ctx.Request.Header.Set(fasthttp.HeaderAuthorization, "Basic "+base64.StdEncoding.EncodeToString([]byte("other+app:secret")))
+			},
+			nil,
+		},
+		{
+			"ShouldReturnNilForUnknownClient",
+			func(ctx *fasthttp.RequestCtx) {
+				ctx.Request.Header.SetContentType("application/x-www-form-urlencoded")
+				ctx.Request.SetBodyString("client_id=unknown")
+			},
+			nil,
+		},
+		{
+			"ShouldResolveFromBearerAuthorization",
+			func(ctx *fasthttp.RequestCtx) {
+				ctx.Request.Header.Set(fasthttp.HeaderAuthorization, "Bearer key.signature")
+			},
+			[]string{"https://app.example.com"},
+		},
+		{
+			"ShouldResolveFromAccessTokenForm",
+			func(ctx *fasthttp.RequestCtx) {
+				ctx.Request.Header.SetContentType("application/x-www-form-urlencoded")
+				ctx.Request.SetBodyString("access_token=key.signature")
+			},
+			[]string{"https://app.example.com"},
+		},
+		{
+			"ShouldReturnNilForInactiveAccessToken",
+			func(ctx *fasthttp.RequestCtx) {
+				ctx.Request.Header.Set(fasthttp.HeaderAuthorization, "Bearer key.inactive")
+			},
+			nil,
+		},
+		{
+			"ShouldReturnNilForUnknownAccessToken",
+			func(ctx *fasthttp.RequestCtx) {
+				ctx.Request.Header.Set(fasthttp.HeaderAuthorization, "Bearer key.unknown")
+			},
+			nil,
+		},
+		{
+			"ShouldReturnNilForMalformedAccessToken",
+			func(ctx *fasthttp.RequestCtx) {
+				ctx.Request.Header.Set(fasthttp.HeaderAuthorization, "Bearer abc")
+			},
+			nil,
+		},
+		{
+			"ShouldReturnAllOriginsForPreflight",
+			func(ctx *fasthttp.RequestCtx) {
+				ctx.Request.Header.SetMethod(fasthttp.MethodOptions)
+			},
+			[]string{"https://example.com", "https://app.example.com"},
+		},
+		{
+			"ShouldReturnNilForBadBasicAuthorization",
+			func(ctx *fasthttp.RequestCtx) {
+				ctx.Request.Header.Set(fasthttp.HeaderAuthorization, "Basic !!!")
+			},
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+
+			ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+
+			tc.setup(ctx)
+
+			assert.Equal(t, tc.expected, originsFunc(ctx))
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// newProxyProtocolListener wraps a net.Listener so connections accepted from the configured trusted upstreams have
+// their PROXY protocol header parsed and the source address it contains reported as the remote address.
+func newProxyProtocolListener(listener net.Listener, config schema.ServerProxyProtocolConfiguration) (ppl net.Listener, err error) {
+	trusted := make([]*net.IPNet, len(config.TrustedUpstreams))
+
+	for i, network := range config.TrustedUpstreams {
+		if trusted[i], err = utils.ParseNetwork(network); err != nil {
+			return nil, fmt.Errorf("failed to parse trusted upstream '%s': %w", network, err)
+		}
+	}
+
+	return &proxyProtocolListener{Listener: listener, trusted: trusted, timeout: config.HeaderTimeout}, nil
+}
+
+// proxyProtocolListener is a net.Listener which accepts the PROXY protocol header from trusted upstreams.
+type proxyProtocolListener struct {
+	net.Listener
+
+	trusted []*net.IPNet
+	timeout time.Duration
+}
+
+// Accept waits for and returns the next connection. Connections from trusted upstreams are wrapped so the PROXY
+// protocol header is consumed before any other data is read, all other connections are returned unmodified.
+func (l *proxyProtocolListener) Accept() (conn net.Conn, err error) {
+	if conn, err = l.Listener.Accept(); err != nil {
+		return nil, err
+	}
+
+	addr, ok := conn.RemoteAddr().(*net.TCPAddr)
+	if !ok || !utils.IsIPInNetworks(addr.IP, l.trusted) {
+		return conn, nil
+	}
+
+	return &proxyProtocolConn{Conn: conn, reader: bufio.NewReader(conn), timeout: l.timeout}, nil
+}
+
+// proxyProtocolConn is a net.Conn from a trusted upstream which is expected to start with a PROXY protocol header.
+// The header is read lazily on the first call to Read or RemoteAddr so a slow upstream can't block Accept.
+type proxyProtocolConn struct {
+	net.Conn
+
+	reader  *bufio.Reader
+	timeout time.Duration
+
+	once       sync.Once
+	remoteAddr net.Addr
+	err        error
+}
+
+// Read reads data from the connection after the PROXY protocol header.
+func (c *proxyProtocolConn) Read(b []byte) (n int, err error) {
+	c.once.Do(c.readHeader)
+
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	return c.reader.Read(b)
+}
+
+// RemoteAddr returns the source address from the PROXY protocol header, or the upstream address if the header did
+// not contain one.
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+
+	return c.Conn.RemoteAddr()
+}
+
+func (c *proxyProtocolConn) readHeader() {
+	if c.err = c.Conn.SetReadDeadline(time.Now().Add(c.timeout)); c.err != nil {
+		return
+	}
+
+	if c.remoteAddr, c.err = readProxyProtocolHeader(c.reader); c.err != nil {
+		c.err = fmt.Errorf("error reading PROXY protocol header from upstream '%s': %w", c.Conn.RemoteAddr(), c.err)
+
+		return
+	}
+
+	c.err = c.Conn.SetReadDeadline(time.Time{})
+}
+
+// readProxyProtocolHeader reads a version 1 or version 2 PROXY protocol header from the reader. A nil net.Addr is
+// returned without an error when the header is valid but does not describe a TCP source address, for example the
+// LOCAL command used by upstream health checks.
+func readProxyProtocolHeader(reader *bufio.Reader) (addr net.Addr, err error) {
+	var b []byte
+
+	if b, err = reader.Peek(1); err != nil {
+		return nil, err
+	}
+
+	switch b[0] {
+	case proxyProtocolV1Prefix[0]:
+		return readProxyProtocolHeaderV1(reader)
+	case proxyProtocolV2Signature[0]:
+		return readProxyProtocolHeaderV2(reader)
+	default:
+		return nil, errProxyProtocolHeaderMissing
+	}
+}
+
+func readProxyProtocolHeaderV1(reader *bufio.Reader) (addr net.Addr, err error) {
+	var line []byte
+
+	if line, err = reader.ReadSlice('\n'); err != nil {
+		if errors.Is(err, bufio.ErrBufferFull) {
+			return nil, errProxyProtocolHeaderV1TooLong
+		}
+
+		return nil, err
+	}
+
+	if len(line) > proxyProtocolV1MaxLength {
+		return nil, errProxyProtocolHeaderV1TooLong
+	}
+
+	if !bytes.HasPrefix(line, []byte(proxyProtocolV1Prefix)) || !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, errProxyProtocolHeaderMissing
+	}
+
+	fields := strings.Split(string(line[len(proxyProtocolV1Prefix):len(line)-2]), " ")
+
+	switch fields[0] {
+	case "UNKNOWN":
+		return nil, nil
+	case "TCP4", "TCP6":
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("invalid version 1 header: expected 6 fields but got %d", len(fields)+1)
+		}
+	default:
+		return nil, fmt.Errorf("invalid version 1 header: unknown protocol '%s'", fields[0])
+	}
+
+	ip := net.ParseIP(fields[1])
+
+	if ip == nil || (ip.To4() != nil) != (fields[0] == "TCP4") {
+		return nil, fmt.Errorf("invalid version 1 header: invalid %s source address '%s'", fields[0], fields[1])
+	}
+
+	port, err := strconv.ParseUint(fields[3], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version 1 header: invalid source port '%s'", fields[3])
+	}
+
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+func readProxyProtocolHeaderV2(reader *bufio.Reader) (addr net.Addr, err error) {
+	var header []byte
+
+	if header, err = reader.Peek(16); err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(header[:12], proxyProtocolV2Signature) {
+		return nil, errProxyProtocolHeaderMissing
+	}
+
+	version, command, family := header[12]>>4, header[12]&0x0F, header[13]>>4
+
+	if version != 2 {
+		return nil, fmt.Errorf("invalid version 2 header: unsupported version %d", version)
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+
+	if _, err = reader.Discard(16); err != nil {
+		return nil, err
+	}
+
+	if _, err = io.ReadFull(reader, payload); err != nil {
+		return nil, err
+	}
+
+	switch command {
+	case proxyProtocolV2CommandLocal:
+		return nil, nil
+	case proxyProtocolV2CommandProxy:
+		break
+	default:
+		return nil, fmt.Errorf("invalid version 2 header: unknown command %d", command)
+	}
+
+	switch family {
+	case proxyProtocolV2FamilyInet:
+		if len(payload) < 12 {
+			return nil, fmt.Errorf("invalid version 2 header: address block too short for IPv4")
+		}
+
+		return &net.TCPAddr{IP: net.IP(payload[0:4]), Port: int(binary.BigEndian.Uint16(payload[8:10]))}, nil
+	case proxyProtocolV2FamilyInet6:
+		if len(payload) < 36 {
+			return nil, fmt.Errorf("invalid version 2 header: address block too short for IPv6")
+		}
+
+		return &net.TCPAddr{IP: net.IP(payload[0:16]), Port: int(binary.BigEndian.Uint16(payload[32:34]))}, nil
+	default:
+		return nil, nil
+	}
+}
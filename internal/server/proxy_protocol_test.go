@@ -0,0 +1,224 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func proxyProtocolV2Header(command, family byte, payload []byte) []byte {
+	header := append([]byte{}, proxyProtocolV2Signature...)
+	header = append(header, 0x20|command, family<<4|0x1, byte(len(payload)>>8), byte(len(payload)))
+
+	return append(header, payload...)
+}
+
+func TestReadProxyProtocolHeader(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     []byte
+		expected string
+		err      string
+	}{
+		{
+			"ShouldParseV1TCP4",
+			[]byte("PROXY TCP4 192.168.1.10 10.0.0.1 56324 443\r\nGET / HTTP/1.1\r\n"),
+			"192.168.1.10:56324",
+			"",
+		},
+		{
+			"ShouldParseV1TCP6",
+			[]byte("PROXY TCP6 2001:db8::1 2001:db8::2 56324 443\r\nGET / HTTP/1.1\r\n"),
+			"[2001:db8::1]:56324",
+			"",
+		},
+		{
+			"ShouldParseV1Unknown",
+			[]byte("PROXY UNKNOWN\r\nGET / HTTP/1.1\r\n"),
+			"",
+			"",
+		},
+		{
+			"ShouldParseV2TCP4",
+			proxyProtocolV2Header(proxyProtocolV2CommandProxy, proxyProtocolV2FamilyInet, []byte{192, 168, 1, 10, 10, 0, 0, 1, 0xDC, 0x04, 0x01, 0xBB}),
+			"192.168.1.10:56324",
+			"",
+		},
+		{
+			"ShouldParseV2TCP6",
+			proxyProtocolV2Header(proxyProtocolV2CommandProxy, proxyProtocolV2FamilyInet6, append(append(net.ParseIP("2001:db8::1").To16(), net.ParseIP("2001:db8::2").To16()...), 0xDC, 0x04, 0x01, 0xBB)),
+			"[2001:db8::1]:56324",
+			"",
+		},
+		{
+			"ShouldParseV2Local",
+			proxyProtocolV2Header(proxyProtocolV2CommandLocal, 0x0, nil),
+			"",
+			"",
+		},
+		{
+			"ShouldRaiseErrorOnMissingHeader",
+			[]byte("GET / HTTP/1.1\r\n"),
+			"",
+			"connection did not start with a PROXY protocol header",
+		},
+		{
+			"ShouldRaiseErrorOnV1UnknownProtocol",
+			[]byte("PROXY UDP4 192.168.1.10 10.0.0.1 56324 443\r\n"),
+			"",
+			"invalid version 1 header: unknown protocol 'UDP4'",
+		},
+		{
+			"ShouldRaiseErrorOnV1MismatchedFamily",
+			[]byte("PROXY TCP4 2001:db8::1 10.0.0.1 56324 443\r\n"),
+			"",
+			"invalid version 1 header: invalid TCP4 source address '2001:db8::1'",
+		},
+		{
+			"ShouldRaiseErrorOnV1InvalidPort",
+			[]byte("PROXY TCP4 192.168.1.10 10.0.0.1 99999 443\r\n"),
+			"",
+			"invalid version 1 header: invalid source port '99999'",
+		},
+		{
+			"ShouldRaiseErrorOnV1TooLong",
+			append(append([]byte("PROXY TCP4 "), bytes.Repeat([]byte("1"), 100)...), '\r', '\n'),
+			"",
+			"invalid version 1 header: header exceeds the maximum length",
+		},
+		{
+			"ShouldRaiseErrorOnV2ShortAddressBlock",
+			proxyProtocolV2Header(proxyProtocolV2CommandProxy, proxyProtocolV2FamilyInet, []byte{192, 168, 1, 10}),
+			"",
+			"invalid version 2 header: address block too short for IPv4",
+		},
+		{
+			"ShouldRaiseErrorOnV2Truncated",
+			proxyProtocolV2Header(proxyProtocolV2CommandProxy, proxyProtocolV2FamilyInet, []byte{192, 168, 1, 10})[:18],
+			"",
+			"unexpected EOF",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			addr, err := readProxyProtocolHeader(bufio.NewReader(bytes.NewReader(tc.have)))
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				assert.Nil(t, addr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tc.expected == "" {
+				assert.Nil(t, addr)
+			} else {
+				require.NotNil(t, addr)
+				assert.Equal(t, tc.expected, addr.String())
+			}
+		})
+	}
+}
+
+func TestProxyProtocolListener(t *testing.T) {
+	testCases := []struct {
+		name     string
+		trusted  []string
+		have     string
+		expected string
+		data     string
+	}{
+		{
+			"ShouldUseHeaderAddressFromTrustedUpstream",
+			[]string{"127.0.0.1"},
+			"PROXY TCP4 192.168.1.10 10.0.0.1 56324 443\r\nexample",
+			"192.168.1.10",
+			"example",
+		},
+		{
+			"ShouldNotParseHeaderFromUntrustedUpstream",
+			[]string{"10.0.0.0/8"},
+			"PROXY TCP4 192.168.1.10 10.0.0.1 56324 443\r\nexample",
+			"127.0.0.1",
+			"PROXY TCP4 192.168.1.10 10.0.0.1 56324 443\r\nexample",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			defer raw.Close()
+
+			listener, err := newProxyProtocolListener(raw, schema.ServerProxyProtocolConfiguration{Enabled: true, TrustedUpstreams: tc.trusted, HeaderTimeout: time.Second})
+			require.NoError(t, err)
+
+			client, err := net.Dial("tcp", raw.Addr().String())
+			require.NoError(t, err)
+
+			defer client.Close()
+
+			_, err = client.Write([]byte(tc.have))
+			require.NoError(t, err)
+
+			conn, err := listener.Accept()
+			require.NoError(t, err)
+
+			defer conn.Close()
+
+			host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, host)
+
+			data := make([]byte, len(tc.data))
+
+			_, err = io.ReadFull(conn, data)
+			require.NoError(t, err)
+			assert.Equal(t, tc.data, string(data))
+		})
+	}
+}
+
+func TestProxyProtocolListenerShouldTimeoutWaitingForHeader(t *testing.T) {
+	raw, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer raw.Close()
+
+	listener, err := newProxyProtocolListener(raw, schema.ServerProxyProtocolConfiguration{Enabled: true, TrustedUpstreams: []string{"127.0.0.0/8"}, HeaderTimeout: time.Millisecond * 50})
+	require.NoError(t, err)
+
+	client, err := net.Dial("tcp", raw.Addr().String())
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	_, err = conn.Read(make([]byte, 1))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error reading PROXY protocol header from upstream")
+}
+
+func TestNewProxyProtocolListenerShouldRaiseErrorOnInvalidUpstream(t *testing.T) {
+	listener, err := newProxyProtocolListener(nil, schema.ServerProxyProtocolConfiguration{TrustedUpstreams: []string{"lb.example.com"}})
+
+	assert.Nil(t, listener)
+	assert.EqualError(t, err, "failed to parse trusted upstream 'lb.example.com': invalid CIDR address: lb.example.com/128")
+}
@@ -3,15 +3,18 @@ package server
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"net"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // CreateServer Create Authelia's internal webserver with the given configuration and providers.
@@ -22,6 +25,13 @@ func CreateServer(config schema.Configuration, providers middlewares.Providers)
 		NoDefaultServerHeader: true,
 		ReadBufferSize:        config.Server.ReadBufferSize,
 		WriteBufferSize:       config.Server.WriteBufferSize,
+		ReadTimeout:           config.Server.ReadTimeout,
+		WriteTimeout:          config.Server.WriteTimeout,
+		IdleTimeout:           config.Server.IdleTimeout,
+		Concurrency:           config.Server.MaxConnections,
+		MaxConnsPerIP:         config.Server.MaxConnectionsPerIP,
+		MaxRequestsPerConn:    config.Server.MaxRequestsPerConnection,
+		DisableKeepalive:      config.Server.DisableKeepAlive,
 	}
 
 	logger := logging.Logger()
@@ -42,6 +52,10 @@ func CreateServer(config schema.Configuration, providers middlewares.Providers)
 			logger.Fatalf("unable to load certificate: %v", err)
 		}
 
+		if err = applyServerTLSOptions(config.Server.TLS, server.TLSConfig); err != nil {
+			logger.Fatalf("unable to apply TLS options: %v", err)
+		}
+
 		if len(config.Server.TLS.ClientCertificates) > 0 {
 			caCertPool := x509.NewCertPool()
 
@@ -60,15 +74,11 @@ func CreateServer(config schema.Configuration, providers middlewares.Providers)
 			server.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
 		}
 
-		if listener, err = tls.Listen("tcp", address, server.TLSConfig.Clone()); err != nil {
-			logger.Fatalf("Error initializing listener: %s", err)
-		}
+		listener = tls.NewListener(createListener(config.Server, address), server.TLSConfig.Clone())
 	} else {
 		connectionType, connectionScheme = "non-TLS", schemeHTTP
 
-		if listener, err = net.Listen("tcp", address); err != nil {
-			logger.Fatalf("Error initializing listener: %s", err)
-		}
+		listener = createListener(config.Server, address)
 	}
 
 	if err = writeHealthCheckEnv(config.Server.DisableHealthcheck, connectionScheme, config.Server.Host,
@@ -84,3 +94,57 @@ func CreateServer(config schema.Configuration, providers middlewares.Providers)
 
 	return server, listener
 }
+
+// createListener creates the TCP listener for the server, wrapped to accept the PROXY protocol header from trusted
+// upstreams when enabled. This must happen beneath any TLS listener as the header precedes the TLS handshake.
+func createListener(config schema.ServerConfiguration, address string) (listener net.Listener) {
+	logger := logging.Logger()
+
+	var err error
+
+	if listener, err = net.Listen("tcp", address); err != nil {
+		logger.Fatalf("Error initializing listener: %s", err)
+	}
+
+	if !config.ProxyProtocol.Enabled {
+		return listener
+	}
+
+	if listener, err = newProxyProtocolListener(listener, config.ProxyProtocol); err != nil {
+		logger.Fatalf("Error initializing PROXY protocol listener: %s", err)
+	}
+
+	logger.Infof("PROXY protocol enabled for connections from trusted upstreams: %s", strings.Join(config.ProxyProtocol.TrustedUpstreams, ", "))
+
+	return listener
+}
+
+// applyServerTLSOptions applies the configured TLS versions and cipher suites to the servers tls.Config. Go never
+// supports renegotiation as a server so this doesn't need to be configured.
+func applyServerTLSOptions(config schema.ServerTLSConfiguration, tlsConfig *tls.Config) (err error) {
+	if config.MinimumVersion != "" {
+		if tlsConfig.MinVersion, err = utils.TLSStringToTLSConfigVersion(config.MinimumVersion); err != nil {
+			return fmt.Errorf("error parsing minimum version '%s': %w", config.MinimumVersion, err)
+		}
+	}
+
+	if config.MaximumVersion != "" {
+		if tlsConfig.MaxVersion, err = utils.TLSStringToTLSConfigVersion(config.MaximumVersion); err != nil {
+			return fmt.Errorf("error parsing maximum version '%s': %w", config.MaximumVersion, err)
+		}
+	}
+
+	if len(config.CipherSuites) == 0 {
+		return nil
+	}
+
+	tlsConfig.CipherSuites = make([]uint16, len(config.CipherSuites))
+
+	for i, name := range config.CipherSuites {
+		if tlsConfig.CipherSuites[i], _, err = utils.TLSStringToTLSConfigCipherSuite(name); err != nil {
+			return fmt.Errorf("error parsing cipher suite '%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
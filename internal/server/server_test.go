@@ -376,3 +376,21 @@ func TestShouldServeProperlyWhenMutualTLSIsConfiguredAndClientIsAuthenticated(t
 	require.NoError(t, err)
 	assert.Equal(t, "404 Not Found", res.Status)
 }
+
+func TestShouldApplyServerTLSOptions(t *testing.T) {
+	tlsConfig := &tls.Config{}
+
+	err := applyServerTLSOptions(schema.ServerTLSConfiguration{
+		MinimumVersion: "TLS1.2",
+		MaximumVersion: "TLS1.3",
+		CipherSuites:   []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"},
+	}, tlsConfig)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	assert.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MaxVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384}, tlsConfig.CipherSuites)
+
+	assert.EqualError(t, applyServerTLSOptions(schema.ServerTLSConfiguration{MinimumVersion: "SSL3.0"}, &tls.Config{}), "error parsing minimum version 'SSL3.0': supplied tls version isn't supported")
+	assert.EqualError(t, applyServerTLSOptions(schema.ServerTLSConfiguration{CipherSuites: []string{"TLS_BAD"}}, &tls.Config{}), "error parsing cipher suite 'TLS_BAD': supplied tls cipher suite isn't supported")
+}
@@ -2,12 +2,15 @@ package server
 
 import (
 	"fmt"
+	"html"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/valyala/fasthttp"
+
 	"github.com/authelia/authelia/v4/internal/logging"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -16,10 +19,10 @@ import (
 // ServeTemplatedFile serves a templated version of a specified file,
 // this is utilised to pass information between the backend and frontend
 // and generate a nonce to support a restrictive CSP while using material-ui.
-func ServeTemplatedFile(publicDir, file, assetPath, duoSelfEnrollment, rememberMe, resetPassword, resetPasswordCustomURL, session, theme string, https bool) middlewares.RequestHandler {
+func ServeTemplatedFile(publicDir, file, assetPath, capabilities, duoSelfEnrollment, rememberMe, resetPassword, resetPasswordCustomURL, resetPasswordMethod, session, theme, themeFallback string, https bool) middlewares.RequestHandler {
 	logger := logging.Logger()
 
-	a, err := assets.Open(publicDir + file)
+	a, err := templatedAssets.Open(publicDir + file)
 	if err != nil {
 		logger.Fatalf("Unable to open %s: %s", file, err)
 	}
@@ -81,7 +84,10 @@ func ServeTemplatedFile(publicDir, file, assetPath, duoSelfEnrollment, rememberM
 			ctx.Response.Header.Add("Content-Security-Policy", fmt.Sprintf(cspDefaultTemplate, nonce))
 		}
 
-		err := tmpl.Execute(ctx.Response.BodyWriter(), struct{ Base, BaseURL, CSPNonce, DuoSelfEnrollment, LogoOverride, RememberMe, ResetPassword, ResetPasswordCustomURL, Session, Theme string }{Base: base, BaseURL: baseURL, CSPNonce: nonce, DuoSelfEnrollment: duoSelfEnrollment, LogoOverride: logoOverride, RememberMe: rememberMe, ResetPassword: resetPassword, ResetPasswordCustomURL: resetPasswordCustomURL, Session: session, Theme: theme})
+		// The login hint is provided by the relying party so it must be escaped as it's placed in a HTML attribute.
+		loginHint := html.EscapeString(ctx.GetSession().LoginHint)
+
+		err := tmpl.Execute(ctx.Response.BodyWriter(), struct{ Base, BaseURL, Capabilities, CSPNonce, DuoSelfEnrollment, LoginHint, LogoOverride, RememberMe, ResetPassword, ResetPasswordCustomURL, ResetPasswordMethod, Session, Theme, ThemeFallback string }{Base: base, BaseURL: baseURL, Capabilities: capabilities, CSPNonce: nonce, DuoSelfEnrollment: duoSelfEnrollment, LoginHint: loginHint, LogoOverride: logoOverride, RememberMe: rememberMe, ResetPassword: resetPassword, ResetPasswordCustomURL: resetPasswordCustomURL, ResetPasswordMethod: resetPasswordMethod, Session: session, Theme: resolveTheme(ctx.RequestCtx, theme), ThemeFallback: themeFallback})
 		if err != nil {
 			ctx.RequestCtx.Error("an error occurred", 503)
 			logger.Errorf("Unable to execute template: %v", err)
@@ -91,6 +97,25 @@ func ServeTemplatedFile(publicDir, file, assetPath, duoSelfEnrollment, rememberM
 	}
 }
 
+// resolveTheme returns the theme for the request. The auto theme is resolved with the Sec-CH-Prefers-Color-Scheme
+// client hint when the browser sends it, otherwise it's left to the portal to resolve with the prefers-color-scheme
+// media query. Browsers are asked to send the client hint with subsequent requests.
+func resolveTheme(ctx *fasthttp.RequestCtx, theme string) string {
+	if theme != themeAuto {
+		return theme
+	}
+
+	ctx.Response.Header.Set(headerAcceptCH, headerSecCHPrefersColorScheme)
+	ctx.Response.Header.Add(fasthttp.HeaderVary, headerSecCHPrefersColorScheme)
+
+	switch hint := strings.Trim(string(ctx.Request.Header.Peek(headerSecCHPrefersColorScheme)), `"`); hint {
+	case themeLight, themeDark:
+		return hint
+	default:
+		return theme
+	}
+}
+
 func writeHealthCheckEnv(disabled bool, scheme, host, path string, port int) (err error) {
 	if disabled {
 		return nil
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func TestServeTemplatedFileShouldPrefixURLsWithBasePath(t *testing.T) {
+	original := templatedAssets
+
+	t.Cleanup(func() {
+		templatedAssets = original
+	})
+
+	templatedAssets = fstest.MapFS{
+		embeddedAssets + indexFile: &fstest.MapFile{
+			Data: []byte(`<base href="{{.BaseURL}}"><link rel="manifest" href="{{.Base}}/manifest.json"><script src="{{.Base}}/static/js/index.js"></script>`),
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		path     string
+		uri      string
+		expected string
+	}{
+		{
+			"ShouldServeUnderSubpath",
+			"/authelia", "/authelia/",
+			`<base href="https://auth.example.com/authelia/"><link rel="manifest" href="/authelia/manifest.json"><script src="/authelia/static/js/index.js"></script>`,
+		},
+		{
+			"ShouldServeUnderSubpathWithoutTrailingSlash",
+			"/authelia", "/authelia",
+			`<base href="https://auth.example.com/authelia/"><link rel="manifest" href="/authelia/manifest.json"><script src="/authelia/static/js/index.js"></script>`,
+		},
+		{
+			"ShouldServeUnderSubpathWithQuery",
+			"/authelia", "/authelia?rd=https%3A%2F%2Fexample.com",
+			`<base href="https://auth.example.com/authelia/"><link rel="manifest" href="/authelia/manifest.json"><script src="/authelia/static/js/index.js"></script>`,
+		},
+		{
+			"ShouldServeUnderRoot",
+			"", "/",
+			`<base href="https://auth.example.com/"><link rel="manifest" href="/manifest.json"><script src="/static/js/index.js"></script>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Request.SetRequestURI(tc.uri)
+			mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+
+			handler := ServeTemplatedFile(embeddedAssets, indexFile, "", "", "", "", "", "", "", "authelia_session", "light", "", true)
+
+			next := func(_ *fasthttp.RequestCtx) {
+				handler(mock.Ctx)
+			}
+
+			if tc.path == "" {
+				next(mock.Ctx.RequestCtx)
+			} else {
+				middlewares.StripPathMiddleware(tc.path, next)(mock.Ctx.RequestCtx)
+			}
+
+			assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, "text/html; charset=utf-8", string(mock.Ctx.Response.Header.ContentType()))
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+		})
+	}
+}
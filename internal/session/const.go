@@ -2,6 +2,8 @@ package session
 
 import (
 	"time"
+
+	"github.com/valyala/fasthttp"
 )
 
 const (
@@ -13,5 +15,20 @@ const (
 
 const (
 	userSessionStorerKey = "UserSession"
-	randomSessionChars   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_!#$%^*"
+
+	// userSessionsStorerKeyPrefix is the prefix of the key storing the sessions of a user. It contains a character
+	// which is never used in session IDs so it can't collide with them.
+	userSessionsStorerKeyPrefix = "user-sessions:"
+
+	// healthCheckSessionID is never generated as a session ID as it's shorter than the generated session IDs.
+	healthCheckSessionID = "authelia-health-check"
+
+	randomSessionChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_!#$%^*"
+)
+
+var (
+	headerSetCookie = []byte(fasthttp.HeaderSetCookie)
+
+	cookieAttributePartitioned      = []byte("; Partitioned")
+	cookieAttributePartitionedLower = []byte("partitioned")
 )
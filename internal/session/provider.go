@@ -1,6 +1,8 @@
 package session
 
 import (
+	"bytes"
+	"context"
 	"crypto/x509"
 	"encoding/json"
 	"time"
@@ -12,17 +14,30 @@ import (
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/storage"
 )
 
 // Provider a session provider.
 type Provider struct {
 	sessionHolder *fasthttpsession.Session
+	storage       fasthttpsession.Provider
+	decode        func(dst *fasthttpsession.Dict, src []byte) error
+	concurrency   schema.SessionConcurrencyConfiguration
+	indexed       bool
+	expiration    time.Duration
+	maximumAge    time.Duration
 	RememberMe    time.Duration
 	Inactivity    time.Duration
+
+	rememberMeCookieMaxAge time.Duration
+
+	cookieName  string
+	partitioned bool
 }
 
-// NewProvider instantiate a session provider given a configuration.
-func NewProvider(config schema.SessionConfiguration, certPool *x509.CertPool) *Provider {
+// NewProvider instantiate a session provider given a configuration. The storage provider is only used when the
+// storage session provider is configured.
+func NewProvider(config schema.SessionConfiguration, certPool *x509.CertPool, store storage.SessionProvider) *Provider {
 	c := NewProviderConfig(config, certPool)
 
 	provider := new(Provider)
@@ -31,6 +46,14 @@ func NewProvider(config schema.SessionConfiguration, certPool *x509.CertPool) *P
 	logger := logging.Logger()
 
 	provider.Inactivity, provider.RememberMe = config.Inactivity, config.RememberMeDuration
+	provider.concurrency, provider.expiration = config.Concurrency, config.Expiration
+	provider.indexed = provider.isConcurrencyLimited() || !config.DisableLogoutOnPasswordChange
+	provider.maximumAge = config.MaximumAuthenticationAge
+	provider.cookieName, provider.partitioned = config.Name, config.Partitioned
+
+	if provider.rememberMeCookieMaxAge = config.RememberMe.CookieMaxAge; provider.rememberMeCookieMaxAge <= 0 {
+		provider.rememberMeCookieMaxAge = provider.RememberMe
+	}
 
 	var (
 		providerImpl fasthttpsession.Provider
@@ -38,6 +61,12 @@ func NewProvider(config schema.SessionConfiguration, certPool *x509.CertPool) *P
 	)
 
 	switch {
+	case c.providerName == schema.SessionProviderStorage:
+		if store == nil {
+			logger.Fatal("the storage session provider requires a storage provider")
+		}
+
+		providerImpl = newStorageProvider(store)
 	case c.redisConfig != nil:
 		providerImpl, err = redis.New(*c.redisConfig)
 		if err != nil {
@@ -60,9 +89,23 @@ func NewProvider(config schema.SessionConfiguration, certPool *x509.CertPool) *P
 		logger.Fatal(err)
 	}
 
+	provider.storage = providerImpl
+
+	if provider.decode = c.config.DecodeFunc; provider.decode == nil {
+		provider.decode = fasthttpsession.Base64Decode
+	}
+
 	return provider
 }
 
+// HealthCheck implements the health check provider interface by loading a session which doesn't exist from the session
+// store.
+func (p *Provider) HealthCheck(_ context.Context) (err error) {
+	_, err = p.storage.Get([]byte(healthCheckSessionID))
+
+	return err
+}
+
 // GetSession return the user session from a request.
 func (p *Provider) GetSession(ctx *fasthttp.RequestCtx) (UserSession, error) {
 	store, err := p.sessionHolder.Get(ctx)
@@ -93,6 +136,32 @@ func (p *Provider) GetSession(ctx *fasthttp.RequestCtx) (UserSession, error) {
 	return userSession, nil
 }
 
+// GetSessionByID returns the user session with the given session ID without creating, saving, or extending it. The
+// boolean is false when no session exists with this ID.
+func (p *Provider) GetSessionByID(id string) (userSession UserSession, found bool, err error) {
+	data, err := p.storage.Get([]byte(id))
+	if err != nil || len(data) == 0 {
+		return NewDefaultUserSession(), false, err
+	}
+
+	dict := &fasthttpsession.Dict{}
+
+	if err = p.decode(dict, data); err != nil {
+		return NewDefaultUserSession(), false, err
+	}
+
+	userSessionJSON, ok := dict.Get(userSessionStorerKey).([]byte)
+	if !ok {
+		return NewDefaultUserSession(), false, nil
+	}
+
+	if err = json.Unmarshal(userSessionJSON, &userSession); err != nil {
+		return NewDefaultUserSession(), false, err
+	}
+
+	return userSession, true, nil
+}
+
 // SaveSession save the user session.
 func (p *Provider) SaveSession(ctx *fasthttp.RequestCtx, userSession UserSession) error {
 	store, err := p.sessionHolder.Get(ctx)
@@ -115,18 +184,66 @@ func (p *Provider) SaveSession(ctx *fasthttp.RequestCtx, userSession UserSession
 		return err
 	}
 
+	if userSession.KeepMeLoggedIn {
+		p.setCookieRememberMeMaxAge(ctx)
+	}
+
+	p.setCookiePartitioned(ctx)
+
 	return nil
 }
 
-// RegenerateSession regenerate a session ID.
+// RegenerateSession regenerate a session ID. When the sessions of each user are tracked the session of the user is also
+// updated to the new session ID.
 func (p *Provider) RegenerateSession(ctx *fasthttp.RequestCtx) error {
-	err := p.sessionHolder.Regenerate(ctx)
+	defer p.setCookiePartitioned(ctx)
 
-	return err
+	if !p.indexed {
+		return p.sessionHolder.Regenerate(ctx)
+	}
+
+	userSession, err := p.GetSession(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := p.getSessionID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if userSession.Username == "" {
+		return p.sessionHolder.Regenerate(ctx)
+	}
+
+	// The sessions of the user must be loaded before regenerating as sessions which no longer exist are excluded.
+	references, err := p.loadUserSessions(userSession.Username)
+	if err != nil {
+		return err
+	}
+
+	if err = p.sessionHolder.Regenerate(ctx); err != nil {
+		return err
+	}
+
+	newID, err := p.getSessionID(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i, reference := range references {
+		if reference.ID == id {
+			references[i].ID = newID
+		}
+	}
+
+	return p.saveUserSessions(userSession.Username, references)
 }
 
 // DestroySession destroy a session ID and delete the cookie.
 func (p *Provider) DestroySession(ctx *fasthttp.RequestCtx) error {
+	defer p.setCookiePartitioned(ctx)
+
 	return p.sessionHolder.Destroy(ctx)
 }
 
@@ -144,7 +261,73 @@ func (p *Provider) UpdateExpiration(ctx *fasthttp.RequestCtx, expiration time.Du
 		return err
 	}
 
-	return p.sessionHolder.Save(ctx, store)
+	if err = p.sessionHolder.Save(ctx, store); err != nil {
+		return err
+	}
+
+	if expiration == p.RememberMe {
+		p.setCookieRememberMeMaxAge(ctx)
+	}
+
+	p.setCookiePartitioned(ctx)
+
+	return nil
+}
+
+// setCookieRememberMeMaxAge replaces the expiration of the session cookie set on the response with the remember me
+// cookie max age when it differs from the remember me validity, as the session library always uses the expiration of
+// the session.
+func (p *Provider) setCookieRememberMeMaxAge(ctx *fasthttp.RequestCtx) {
+	if p.RememberMe <= 0 || p.rememberMeCookieMaxAge == p.RememberMe {
+		return
+	}
+
+	raw := ctx.Response.Header.PeekCookie(p.cookieName)
+	if raw == nil {
+		return
+	}
+
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	if err := cookie.ParseBytes(raw); err != nil {
+		return
+	}
+
+	cookie.SetExpire(time.Now().Add(p.rememberMeCookieMaxAge))
+
+	ctx.Response.Header.SetCookie(cookie)
+}
+
+// setCookiePartitioned adds the Partitioned attribute to the session cookie set on the response when enabled, as the
+// session library doesn't support it. Partitioned cookies must also have the Secure attribute including the cookie
+// which deletes the session cookie.
+func (p *Provider) setCookiePartitioned(ctx *fasthttp.RequestCtx) {
+	if !p.partitioned {
+		return
+	}
+
+	raw := ctx.Response.Header.PeekCookie(p.cookieName)
+	if raw == nil || bytes.Contains(bytes.ToLower(raw), cookieAttributePartitionedLower) {
+		return
+	}
+
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	if err := cookie.ParseBytes(raw); err != nil {
+		return
+	}
+
+	if !cookie.Secure() {
+		cookie.SetSecure(true)
+		cookie.SetSameSite(fasthttp.CookieSameSiteNoneMode)
+	}
+
+	value := append(cookie.Cookie(), cookieAttributePartitioned...)
+
+	ctx.Response.Header.DelCookie(p.cookieName)
+	ctx.Response.Header.SetCanonical(headerSetCookie, value)
 }
 
 // GetExpiration get the expiration of the current session.
@@ -157,3 +340,59 @@ func (p *Provider) GetExpiration(ctx *fasthttp.RequestCtx) (time.Duration, error
 
 	return store.GetExpiration(), nil
 }
+
+// GetRemainingTime returns the amount of time the user session has left before it expires due to inactivity or
+// reaching the maximum authentication age. The boolean is false if the user session never expires this way, which is
+// the case for anonymous sessions and sessions of users who checked keep me logged in.
+func (p *Provider) GetRemainingTime(userSession UserSession, now time.Time) (remaining time.Duration, expires bool) {
+	expiresAt, expires := p.getExpiresAt(userSession)
+	if !expires {
+		return 0, false
+	}
+
+	if remaining = expiresAt.Sub(now); remaining < 0 {
+		remaining = 0
+	}
+
+	return remaining, true
+}
+
+// RefreshActivity resets the last activity of the user session to now. The last activity is capped so the session
+// never outlives the maximum authentication age as a refresh must not extend the absolute lifetime of the session.
+func (p *Provider) RefreshActivity(userSession *UserSession, now time.Time) {
+	lastActivity := now.Unix()
+
+	if absolute, ok := p.getAbsoluteExpiresAt(*userSession); ok && p.Inactivity > 0 {
+		if limit := absolute.Add(-p.Inactivity).Unix(); lastActivity > limit {
+			lastActivity = limit
+		}
+	}
+
+	if lastActivity > userSession.LastActivity {
+		userSession.LastActivity = lastActivity
+	}
+}
+
+func (p *Provider) getExpiresAt(userSession UserSession) (expiresAt time.Time, expires bool) {
+	if userSession.Username == "" || userSession.KeepMeLoggedIn {
+		return expiresAt, false
+	}
+
+	if p.Inactivity > 0 {
+		expiresAt, expires = time.Unix(userSession.LastActivity, 0).Add(p.Inactivity), true
+	}
+
+	if absolute, ok := p.getAbsoluteExpiresAt(userSession); ok && (!expires || absolute.Before(expiresAt)) {
+		expiresAt, expires = absolute, true
+	}
+
+	return expiresAt, expires
+}
+
+func (p *Provider) getAbsoluteExpiresAt(userSession UserSession) (expiresAt time.Time, ok bool) {
+	if p.maximumAge <= 0 {
+		return expiresAt, false
+	}
+
+	return userSession.LastAuthenticatedTime().Add(p.maximumAge), true
+}
@@ -66,8 +66,15 @@ func NewProviderConfig(config schema.SessionConfiguration, certPool *x509.CertPo
 
 	var providerName string
 
-	// If redis configuration is provided, then use the redis provider.
+	// If the storage provider is selected use it, otherwise if redis configuration is provided use the redis provider.
 	switch {
+	case config.Provider == schema.SessionProviderStorage:
+		serializer := NewEncryptingSerializer(config.Secret)
+
+		providerName = schema.SessionProviderStorage
+
+		c.EncodeFunc = serializer.Encode
+		c.DecodeFunc = serializer.Decode
 	case config.Redis != nil:
 		serializer := NewEncryptingSerializer(config.Secret)
 
@@ -1,6 +1,8 @@
 package session
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,7 +23,7 @@ func TestShouldInitializerSession(t *testing.T) {
 	configuration.Name = testName
 	configuration.Expiration = testExpiration
 
-	provider := NewProvider(configuration, nil)
+	provider := NewProvider(configuration, nil, nil)
 	session, err := provider.GetSession(ctx)
 	require.NoError(t, err)
 
@@ -36,7 +38,7 @@ func TestShouldUpdateSession(t *testing.T) {
 	configuration.Name = testName
 	configuration.Expiration = testExpiration
 
-	provider := NewProvider(configuration, nil)
+	provider := NewProvider(configuration, nil, nil)
 	session, _ := provider.GetSession(ctx)
 
 	session.Username = testUsername
@@ -66,7 +68,7 @@ func TestShouldSetSessionAuthenticationLevels(t *testing.T) {
 	configuration.Name = testName
 	configuration.Expiration = testExpiration
 
-	provider := NewProvider(configuration, nil)
+	provider := NewProvider(configuration, nil, nil)
 	session, _ := provider.GetSession(ctx)
 
 	session.SetOneFactor(timeOneFactor, &authentication.UserDetails{Username: testUsername}, false)
@@ -139,7 +141,7 @@ func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 	configuration.Name = testName
 	configuration.Expiration = testExpiration
 
-	provider := NewProvider(configuration, nil)
+	provider := NewProvider(configuration, nil, nil)
 	session, _ := provider.GetSession(ctx)
 
 	session.SetOneFactor(timeOneFactor, &authentication.UserDetails{Username: testUsername}, false)
@@ -297,7 +299,7 @@ func TestShouldDestroySessionAndWipeSessionData(t *testing.T) {
 	configuration.Name = testName
 	configuration.Expiration = testExpiration
 
-	provider := NewProvider(configuration, nil)
+	provider := NewProvider(configuration, nil, nil)
 	session, err := provider.GetSession(ctx)
 	require.NoError(t, err)
 
@@ -320,3 +322,225 @@ func TestShouldDestroySessionAndWipeSessionData(t *testing.T) {
 	assert.Equal(t, "", newUserSession.Username)
 	assert.Equal(t, authentication.NotAuthenticated, newUserSession.AuthenticationLevel)
 }
+
+func TestShouldReturnLastAuthenticatedTime(t *testing.T) {
+	session := UserSession{}
+	assert.Equal(t, time.Unix(0, 0), session.LastAuthenticatedTime())
+
+	session.SetOneFactor(time.Unix(1625048140, 0), &authentication.UserDetails{Username: testUsername}, false)
+	assert.Equal(t, time.Unix(1625048140, 0), session.LastAuthenticatedTime())
+
+	session.SetTwoFactorDuo(time.Unix(1625048150, 0))
+	assert.Equal(t, time.Unix(1625048150, 0), session.LastAuthenticatedTime())
+}
+
+func TestShouldReturnRemainingTime(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.Inactivity = time.Minute * 5
+
+	provider := NewProvider(configuration, nil, nil)
+
+	session := NewDefaultUserSession()
+
+	_, expires := provider.GetRemainingTime(session, time.Unix(1625048140, 0))
+	assert.False(t, expires)
+
+	session.SetOneFactor(time.Unix(1625048140, 0), &authentication.UserDetails{Username: testUsername}, false)
+
+	remaining, expires := provider.GetRemainingTime(session, time.Unix(1625048200, 0))
+	assert.True(t, expires)
+	assert.Equal(t, time.Minute*4, remaining)
+
+	remaining, expires = provider.GetRemainingTime(session, time.Unix(1625049140, 0))
+	assert.True(t, expires)
+	assert.Equal(t, time.Duration(0), remaining)
+
+	session.KeepMeLoggedIn = true
+
+	_, expires = provider.GetRemainingTime(session, time.Unix(1625048200, 0))
+	assert.False(t, expires)
+}
+
+func TestShouldRefreshActivityWithinMaximumAuthenticationAge(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.Inactivity = time.Minute * 5
+	configuration.MaximumAuthenticationAge = time.Minute * 10
+
+	provider := NewProvider(configuration, nil, nil)
+
+	session := NewDefaultUserSession()
+	session.SetOneFactor(time.Unix(1625048100, 0), &authentication.UserDetails{Username: testUsername}, false)
+
+	provider.RefreshActivity(&session, time.Unix(1625048340, 0))
+	assert.Equal(t, int64(1625048340), session.LastActivity)
+
+	remaining, expires := provider.GetRemainingTime(session, time.Unix(1625048340, 0))
+	assert.True(t, expires)
+	assert.Equal(t, time.Minute*5, remaining)
+
+	// The refresh is capped so the inactivity expiration never exceeds the maximum authentication age.
+	provider.RefreshActivity(&session, time.Unix(1625048580, 0))
+	assert.Equal(t, int64(1625048400), session.LastActivity)
+
+	remaining, expires = provider.GetRemainingTime(session, time.Unix(1625048580, 0))
+	assert.True(t, expires)
+	assert.Equal(t, time.Minute*2, remaining)
+}
+
+func TestShouldSetPartitionedSessionCookie(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.SameSite = "none"
+	configuration.Partitioned = true
+
+	provider := NewProvider(configuration, nil, nil)
+	session, err := provider.GetSession(ctx)
+	require.NoError(t, err)
+
+	session.Username = testUsername
+
+	require.NoError(t, provider.SaveSession(ctx, session))
+
+	cookie := string(ctx.Response.Header.PeekCookie(testName))
+
+	assert.Regexp(t, `^my_session=[^;]+; .*secure; SameSite=None; Partitioned$`, cookie)
+
+	require.NoError(t, provider.RegenerateSession(ctx))
+	require.NoError(t, provider.UpdateExpiration(ctx, testExpiration))
+
+	assert.Equal(t, 1, strings.Count(string(ctx.Response.Header.PeekCookie(testName)), "Partitioned"))
+	assert.Equal(t, 1, strings.Count(ctx.Response.Header.String(), "Set-Cookie: my_session="))
+
+	require.NoError(t, provider.DestroySession(ctx))
+
+	assert.Regexp(t, `^my_session=; .*secure; SameSite=None; Partitioned$`, string(ctx.Response.Header.PeekCookie(testName)))
+}
+
+func TestShouldSetRememberMeCookieMaxAge(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.RememberMeDuration = time.Hour * 24 * 30
+	configuration.RememberMe.CookieMaxAge = time.Hour
+
+	provider := NewProvider(configuration, nil, nil)
+	session, err := provider.GetSession(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, provider.UpdateExpiration(ctx, provider.RememberMe))
+
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	require.NoError(t, cookie.ParseBytes(ctx.Response.Header.PeekCookie(testName)))
+	assert.WithinDuration(t, time.Now().Add(time.Hour), cookie.Expire(), time.Minute)
+
+	expiration, err := provider.GetExpiration(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour*24*30, expiration)
+
+	session.Username = testUsername
+	session.KeepMeLoggedIn = true
+
+	require.NoError(t, provider.SaveSession(ctx, session))
+
+	require.NoError(t, cookie.ParseBytes(ctx.Response.Header.PeekCookie(testName)))
+	assert.WithinDuration(t, time.Now().Add(time.Hour), cookie.Expire(), time.Minute)
+	assert.Equal(t, 1, strings.Count(ctx.Response.Header.String(), "Set-Cookie: my_session="))
+}
+
+func TestShouldNotSetRememberMeCookieMaxAgeWhenSameAsValidity(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.RememberMeDuration = time.Hour * 24 * 30
+
+	provider := NewProvider(configuration, nil, nil)
+
+	require.NoError(t, provider.UpdateExpiration(ctx, provider.RememberMe))
+
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	require.NoError(t, cookie.ParseBytes(ctx.Response.Header.PeekCookie(testName)))
+	assert.WithinDuration(t, time.Now().Add(time.Hour*24*30), cookie.Expire(), time.Minute)
+}
+
+func TestShouldNotSetPartitionedSessionCookieWhenDisabled(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+
+	provider := NewProvider(configuration, nil, nil)
+	session, err := provider.GetSession(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, provider.SaveSession(ctx, session))
+
+	cookie := string(ctx.Response.Header.PeekCookie(testName))
+
+	assert.NotEmpty(t, cookie)
+	assert.NotContains(t, cookie, "Partitioned")
+}
+
+func TestShouldHealthCheckSessionProvider(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+
+	provider := NewProvider(configuration, nil, nil)
+
+	assert.NoError(t, provider.HealthCheck(context.Background()))
+}
+
+func TestShouldGetSessionByID(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+
+	provider := NewProvider(configuration, nil, nil)
+	session, err := provider.GetSession(ctx)
+	require.NoError(t, err)
+
+	session.Username = testUsername
+	session.AuthenticationLevel = authentication.OneFactor
+
+	require.NoError(t, provider.SaveSession(ctx, session))
+
+	id, err := provider.getSessionID(ctx)
+	require.NoError(t, err)
+
+	actual, found, err := provider.GetSessionByID(id)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, testUsername, actual.Username)
+	assert.Equal(t, authentication.OneFactor, actual.AuthenticationLevel)
+
+	actual, found, err = provider.GetSessionByID("unknown")
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Equal(t, NewDefaultUserSession(), actual)
+}
@@ -0,0 +1,93 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// newStorageProvider creates a session provider which persists the sessions in the storage backend.
+func newStorageProvider(provider storage.SessionProvider) *storageProvider {
+	return &storageProvider{provider: provider}
+}
+
+// storageProvider is a fasthttp/session provider which persists the sessions in the storage backend. Only the SHA256
+// hash of each session ID is stored.
+type storageProvider struct {
+	provider storage.SessionProvider
+}
+
+// Get returns the data of the given session id.
+func (p *storageProvider) Get(id []byte) (data []byte, err error) {
+	session, err := p.provider.LoadSession(context.Background(), hashSessionID(id), time.Now())
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	return session.Data, nil
+}
+
+// Save saves the session data and expiration from the given session id.
+func (p *storageProvider) Save(id, data []byte, expiration time.Duration) (err error) {
+	return p.provider.SaveSession(context.Background(), model.Session{
+		ID:        hashSessionID(id),
+		ExpiresAt: sessionExpiresAt(expiration),
+		Data:      data,
+	})
+}
+
+// Regenerate updates the session id and expiration with the new session id of the given current session id.
+func (p *storageProvider) Regenerate(id, newID []byte, expiration time.Duration) (err error) {
+	return p.provider.UpdateSessionID(context.Background(), hashSessionID(id), hashSessionID(newID), sessionExpiresAt(expiration))
+}
+
+// Destroy destroys the session from the given id.
+func (p *storageProvider) Destroy(id []byte) (err error) {
+	return p.provider.DeleteSession(context.Background(), hashSessionID(id))
+}
+
+// Count returns the total of stored sessions.
+func (p *storageProvider) Count() (count int) {
+	count, err := p.provider.CountSessions(context.Background(), time.Now())
+	if err != nil {
+		logging.Logger().Errorf("Error occurred counting the sessions: %v", err)
+	}
+
+	return count
+}
+
+// NeedGC indicates if the GC needs to be run.
+func (p *storageProvider) NeedGC() bool {
+	return true
+}
+
+// GC deletes the expired sessions. Errors are logged here rather than returned as the session manager would otherwise
+// log them outside of the Authelia logger.
+func (p *storageProvider) GC() (err error) {
+	if err = p.provider.DeleteExpiredSessions(context.Background(), time.Now()); err != nil {
+		logging.Logger().Errorf("Error occurred deleting the expired sessions: %v", err)
+	}
+
+	return nil
+}
+
+func hashSessionID(id []byte) string {
+	sum := sha256.Sum256(id)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func sessionExpiresAt(expiration time.Duration) *time.Time {
+	if expiration <= 0 {
+		return nil
+	}
+
+	expiresAt := time.Now().Add(expiration)
+
+	return &expiresAt
+}
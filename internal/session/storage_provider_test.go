@@ -0,0 +1,116 @@
+package session
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func newTestStorageProvider(t *testing.T) *storage.SQLiteProvider {
+	config := &schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "a_very_long_encryption_key_used_for_testing",
+			Local: &schema.LocalStorageConfiguration{
+				Path: filepath.Join(t.TempDir(), "db.sqlite3"),
+			},
+		},
+	}
+
+	provider := storage.NewSQLiteProvider(config)
+
+	require.NoError(t, provider.StartupCheck())
+
+	t.Cleanup(func() {
+		_ = provider.Close()
+	})
+
+	return provider
+}
+
+func TestStorageProviderShouldSaveGetAndDestroySessions(t *testing.T) {
+	provider := newStorageProvider(newTestStorageProvider(t))
+
+	data, err := provider.Get([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	require.NoError(t, provider.Save([]byte("abc"), []byte("data"), time.Hour))
+	require.NoError(t, provider.Save([]byte("xyz"), []byte("other"), 0))
+
+	data, err = provider.Get([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+
+	require.NoError(t, provider.Save([]byte("abc"), []byte("updated"), time.Hour))
+
+	data, err = provider.Get([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("updated"), data)
+	assert.Equal(t, 2, provider.Count())
+
+	require.NoError(t, provider.Regenerate([]byte("abc"), []byte("def"), time.Hour))
+
+	data, err = provider.Get([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	data, err = provider.Get([]byte("def"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("updated"), data)
+
+	require.NoError(t, provider.Destroy([]byte("def")))
+
+	data, err = provider.Get([]byte("def"))
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Equal(t, 1, provider.Count())
+}
+
+func TestStorageProviderShouldNotReturnOrKeepExpiredSessions(t *testing.T) {
+	store := newTestStorageProvider(t)
+	provider := newStorageProvider(store)
+
+	require.NoError(t, provider.Save([]byte("abc"), []byte("data"), time.Millisecond))
+	require.NoError(t, provider.Save([]byte("xyz"), []byte("other"), 0))
+
+	time.Sleep(time.Millisecond * 5)
+
+	data, err := provider.Get([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Equal(t, 1, provider.Count())
+
+	assert.True(t, provider.NeedGC())
+	assert.NoError(t, provider.GC())
+
+	session, err := store.LoadSession(context.Background(), hashSessionID([]byte("abc")), time.Unix(0, 0))
+	assert.NoError(t, err)
+	assert.Nil(t, session)
+
+	data, err = provider.Get([]byte("xyz"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("other"), data)
+}
+
+func TestStorageProviderShouldStoreHashedSessionIDs(t *testing.T) {
+	store := newTestStorageProvider(t)
+	provider := newStorageProvider(store)
+
+	require.NoError(t, provider.Save([]byte("abc"), []byte("data"), time.Hour))
+
+	session, err := store.LoadSession(context.Background(), "abc", time.Now())
+	assert.NoError(t, err)
+	assert.Nil(t, session)
+
+	session, err = store.LoadSession(context.Background(), "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", time.Now())
+	require.NoError(t, err)
+	require.NotNil(t, session)
+	assert.Equal(t, []byte("data"), session.Data)
+}
@@ -31,6 +31,10 @@ type UserSession struct {
 	Groups []string
 	Emails []string
 
+	// Subject is the immutable identifier of the user retrieved from the authentication backend, it's uuid.Nil if the
+	// backend isn't configured to retrieve one.
+	Subject uuid.UUID
+
 	KeepMeLoggedIn      bool
 	AuthenticationLevel authentication.Level
 	LastActivity        int64
@@ -40,19 +44,73 @@ type UserSession struct {
 
 	AuthenticationMethodRefs oidc.AuthenticationMethodsReferences
 
+	// ImpossibleTravel is true when the first factor was completed from a location that could not have been reached
+	// since the previous login and the second factor must be completed regardless of the required level.
+	ImpossibleTravel bool
+
+	// SecondFactorEnrollmentRequired is true when the user hasn't enrolled a second factor method within the grace
+	// period after their first login and must enroll one and complete the second factor regardless of the required level.
+	SecondFactorEnrollmentRequired bool
+
+	// BreakGlass is true when the first factor was completed with the break-glass account. The second factor must be
+	// completed regardless of the required level and the profile is never refreshed from the authentication backend.
+	BreakGlass bool
+
 	// Webauthn holds the session registration data for this session.
 	Webauthn *webauthn.SessionData
 
+	// WebauthnEnrollment holds the registration data of an administrator enrolling a Webauthn device on behalf of
+	// another user.
+	WebauthnEnrollment *WebauthnEnrollment
+
+	// TOTPRotation holds the TOTP configuration generated to replace a registration of the user until it's confirmed.
+	TOTPRotation *TOTPRotation
+
 	// ConsentChallengeID is the OpenID Connect Consent Session challenge ID.
 	ConsentChallengeID *uuid.UUID
 
+	// CAPTCHARequired is true when the user has exceeded the configured number of failed first factor attempts and
+	// must complete a CAPTCHA challenge before their credentials are checked.
+	CAPTCHARequired bool
+
+	// BindingUserAgent and BindingNetwork are the coarse fingerprint of the client recorded when the first factor was
+	// completed, the session is only valid for clients with the same fingerprint when the session binding is enabled.
+	BindingUserAgent string
+	BindingNetwork   string
+
+	// LoginHint is the OpenID Connect login_hint parameter used to pre-fill the username in the login portal.
+	LoginHint string
+
 	// This boolean is set to true after identity verification and checked
 	// while doing the query actually updating the password.
 	PasswordResetUsername *string
 
+	// PasswordResetAdministrator is true when the password reset was initiated by an administrator, in which case
+	// the minimum password age is not enforced.
+	PasswordResetAdministrator bool
+
 	RefreshTTL time.Time
 }
 
+// WebauthnEnrollment is the registration data of a Webauthn device enrolled by an administrator on behalf of a user.
+type WebauthnEnrollment struct {
+	Username    string
+	SessionData *webauthn.SessionData
+}
+
+// TOTPRotation is a TOTP configuration generated to replace the registration of a user with the same description. The
+// registration is only replaced once the user confirms a code generated with the new configuration before it expires.
+// The Secret is encrypted with the storage encryption key.
+type TOTPRotation struct {
+	Description string
+	Issuer      string
+	Algorithm   string
+	Digits      uint
+	Period      uint
+	Secret      []byte
+	Expires     time.Time
+}
+
 // Identity identity of the user who is being verified.
 type Identity struct {
 	Username    string
@@ -6,6 +6,7 @@ import (
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/model"
 )
 
 // NewDefaultUserSession create a default user session.
@@ -19,6 +20,20 @@ func NewDefaultUserSession() UserSession {
 
 // SetOneFactor sets the 1FA AMR's and expected property values for one factor authentication.
 func (s *UserSession) SetOneFactor(now time.Time, details *authentication.UserDetails, keepMeLoggedIn bool) {
+	s.setOneFactor(now, details, keepMeLoggedIn)
+
+	s.AuthenticationMethodRefs.UsernameAndPassword = true
+}
+
+// SetOneFactorTrustedJWT sets the 1FA AMR's for a verified JWT forwarded by a trusted upstream identity provider and
+// the user details.
+func (s *UserSession) SetOneFactorTrustedJWT(now time.Time, details *authentication.UserDetails) {
+	s.setOneFactor(now, details, false)
+
+	s.AuthenticationMethodRefs.TrustedJWT = true
+}
+
+func (s *UserSession) setOneFactor(now time.Time, details *authentication.UserDetails, keepMeLoggedIn bool) {
 	s.FirstFactorAuthnTimestamp = now.Unix()
 	s.LastActivity = now.Unix()
 	s.AuthenticationLevel = authentication.OneFactor
@@ -29,8 +44,10 @@ func (s *UserSession) SetOneFactor(now time.Time, details *authentication.UserDe
 	s.DisplayName = details.DisplayName
 	s.Groups = details.Groups
 	s.Emails = details.Emails
+	s.Subject = details.Subject
 
-	s.AuthenticationMethodRefs.UsernameAndPassword = true
+	// The login hint only pre-fills the username of the login portal so it's no longer needed once authenticated.
+	s.LoginHint = ""
 }
 
 func (s *UserSession) setTwoFactor(now time.Time) {
@@ -60,6 +77,28 @@ func (s *UserSession) SetTwoFactorWebauthn(now time.Time, userPresence, userVeri
 	s.Webauthn = nil
 }
 
+// HasSecondFactorMethod returns true if the user completed the second factor with any of the provided methods.
+func (s UserSession) HasSecondFactorMethod(methods []string) bool {
+	for _, method := range methods {
+		switch method {
+		case model.SecondFactorMethodTOTP:
+			if s.AuthenticationMethodRefs.TOTP {
+				return true
+			}
+		case model.SecondFactorMethodWebauthn:
+			if s.AuthenticationMethodRefs.Webauthn {
+				return true
+			}
+		case model.SecondFactorMethodDuo:
+			if s.AuthenticationMethodRefs.Duo {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // AuthenticatedTime returns the unix timestamp this session authenticated successfully at the given level.
 func (s UserSession) AuthenticatedTime(level authorization.Level) (authenticatedTime time.Time, err error) {
 	switch level {
@@ -71,3 +110,12 @@ func (s UserSession) AuthenticatedTime(level authorization.Level) (authenticated
 		return time.Unix(0, 0), errors.New("invalid authorization level")
 	}
 }
+
+// LastAuthenticatedTime returns the time this session last authenticated successfully with any factor.
+func (s UserSession) LastAuthenticatedTime() (authenticatedTime time.Time) {
+	if s.SecondFactorAuthnTimestamp > s.FirstFactorAuthnTimestamp {
+		return time.Unix(s.SecondFactorAuthnTimestamp, 0)
+	}
+
+	return time.Unix(s.FirstFactorAuthnTimestamp, 0)
+}
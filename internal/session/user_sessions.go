@@ -0,0 +1,189 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+)
+
+// ErrConcurrentSessionLimitReached is returned when the user has reached the concurrent session limit and the
+// configured policy is to reject new sessions.
+var ErrConcurrentSessionLimitReached = errors.New("the concurrent session limit has been reached")
+
+// userSessionReference is a reference to one of the sessions of a user.
+type userSessionReference struct {
+	ID         string `json:"id"`
+	CreatedAt  int64  `json:"created_at"`
+	RememberMe bool   `json:"remember_me"`
+}
+
+// RegisterUserSession records the current session as one of the sessions of the user and enforces the concurrent
+// session limits. Remember me sessions are counted separately from the other sessions when a remember me limit is
+// configured. When the limit has been reached either ErrConcurrentSessionLimitReached is returned or the oldest
+// sessions are destroyed depending on the configured policy. The sessions are only recorded when they're limited or
+// destroyed when the user changes their password.
+func (p *Provider) RegisterUserSession(ctx *fasthttp.RequestCtx, username string, rememberMe bool) (err error) {
+	if !p.indexed {
+		return nil
+	}
+
+	id, err := p.getSessionID(ctx)
+	if err != nil {
+		return err
+	}
+
+	references, err := p.loadUserSessions(username)
+	if err != nil {
+		return err
+	}
+
+	limit := p.concurrency.Limit
+	if rememberMe && p.concurrency.RememberMeLimit != 0 {
+		limit = p.concurrency.RememberMeLimit
+	}
+
+	for limit != 0 {
+		i, count := -1, 0
+
+		for j, reference := range references {
+			if p.concurrency.RememberMeLimit != 0 && reference.RememberMe != rememberMe {
+				continue
+			}
+
+			if i == -1 {
+				i = j
+			}
+
+			count++
+		}
+
+		if count < limit {
+			break
+		}
+
+		if p.concurrency.Policy == schema.SessionConcurrencyPolicyReject {
+			return ErrConcurrentSessionLimitReached
+		}
+
+		if err = p.storage.Destroy([]byte(references[i].ID)); err != nil {
+			return err
+		}
+
+		logging.Logger().Debugf("Destroyed the oldest session of user '%s' as the concurrent session limit of %d has been reached", username, limit)
+
+		references = append(references[:i], references[i+1:]...)
+	}
+
+	references = append(references, userSessionReference{
+		ID:         id,
+		CreatedAt:  time.Now().Unix(),
+		RememberMe: rememberMe,
+	})
+
+	return p.saveUserSessions(username, references)
+}
+
+// IsUserSessionsIndexed returns true if the sessions of each user are recorded and can be destroyed together.
+func (p *Provider) IsUserSessionsIndexed() bool {
+	return p.indexed
+}
+
+// DestroyUserSessions destroys all the recorded sessions of the user except the current session, and returns the
+// number of sessions destroyed.
+func (p *Provider) DestroyUserSessions(ctx *fasthttp.RequestCtx, username string) (destroyed int, err error) {
+	if !p.indexed {
+		return 0, nil
+	}
+
+	id, err := p.getSessionID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	references, err := p.loadUserSessions(username)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, reference := range references {
+		if reference.ID == id {
+			continue
+		}
+
+		if err = p.storage.Destroy([]byte(reference.ID)); err != nil {
+			return destroyed, err
+		}
+
+		destroyed++
+	}
+
+	return destroyed, p.storage.Destroy(userSessionsKey(username))
+}
+
+func (p *Provider) isConcurrencyLimited() bool {
+	return p.concurrency.Limit != 0 || p.concurrency.RememberMeLimit != 0
+}
+
+// getSessionID returns a copy of the current session ID as the underlying bytes are reused when the session is
+// regenerated.
+func (p *Provider) getSessionID(ctx *fasthttp.RequestCtx) (id string, err error) {
+	store, err := p.sessionHolder.Get(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return string(store.GetSessionID()), nil
+}
+
+// loadUserSessions loads the references to the sessions of the user excluding the sessions which no longer exist.
+func (p *Provider) loadUserSessions(username string) (references []userSessionReference, err error) {
+	data, err := p.storage.Get(userSessionsKey(username))
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, &references); err != nil {
+		return nil, err
+	}
+
+	active := references[:0]
+
+	for _, reference := range references {
+		if data, err = p.storage.Get([]byte(reference.ID)); err != nil {
+			return nil, err
+		}
+
+		if len(data) != 0 {
+			active = append(active, reference)
+		}
+	}
+
+	return active, nil
+}
+
+func (p *Provider) saveUserSessions(username string, references []userSessionReference) (err error) {
+	if len(references) == 0 {
+		return p.storage.Destroy(userSessionsKey(username))
+	}
+
+	data, err := json.Marshal(references)
+	if err != nil {
+		return err
+	}
+
+	expiration := p.expiration
+	if p.RememberMe > expiration {
+		expiration = p.RememberMe
+	}
+
+	return p.storage.Save(userSessionsKey(username), data, expiration)
+}
+
+func userSessionsKey(username string) []byte {
+	return []byte(userSessionsStorerKeyPrefix + username)
+}
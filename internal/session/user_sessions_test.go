@@ -0,0 +1,189 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestConcurrencyProvider(concurrency schema.SessionConcurrencyConfiguration) *Provider {
+	configuration := schema.SessionConfiguration{
+		Domain:                        testDomain,
+		Name:                          testName,
+		Expiration:                    testExpiration,
+		RememberMeDuration:            testExpiration * 2,
+		Concurrency:                   concurrency,
+		DisableLogoutOnPasswordChange: true,
+	}
+
+	return NewProvider(configuration, nil, nil)
+}
+
+func newTestUserSession(t *testing.T, provider *Provider, rememberMe bool) (ctx *fasthttp.RequestCtx, err error) {
+	ctx = &fasthttp.RequestCtx{}
+
+	require.NoError(t, provider.SaveSession(ctx, NewDefaultUserSession()))
+
+	if err = provider.RegisterUserSession(ctx, testUsername, rememberMe); err != nil {
+		return ctx, err
+	}
+
+	userSession := NewDefaultUserSession()
+	userSession.Username = testUsername
+	userSession.KeepMeLoggedIn = rememberMe
+
+	require.NoError(t, provider.SaveSession(ctx, userSession))
+
+	return ctx, nil
+}
+
+func isTestSessionActive(t *testing.T, provider *Provider, ctx *fasthttp.RequestCtx) bool {
+	id, err := provider.getSessionID(ctx)
+	require.NoError(t, err)
+
+	data, err := provider.storage.Get([]byte(id))
+	require.NoError(t, err)
+
+	return len(data) != 0
+}
+
+func TestShouldNotLimitConcurrentSessionsByDefault(t *testing.T) {
+	provider := newTestConcurrencyProvider(schema.SessionConcurrencyConfiguration{})
+
+	for i := 0; i < 5; i++ {
+		_, err := newTestUserSession(t, provider, false)
+		require.NoError(t, err)
+	}
+
+	references, err := provider.loadUserSessions(testUsername)
+	require.NoError(t, err)
+	assert.Len(t, references, 0)
+}
+
+func TestShouldEvictOldestConcurrentSession(t *testing.T) {
+	provider := newTestConcurrencyProvider(schema.SessionConcurrencyConfiguration{Limit: 2, Policy: schema.SessionConcurrencyPolicyEvictOldest})
+
+	first, err := newTestUserSession(t, provider, false)
+	require.NoError(t, err)
+
+	second, err := newTestUserSession(t, provider, false)
+	require.NoError(t, err)
+
+	third, err := newTestUserSession(t, provider, false)
+	require.NoError(t, err)
+
+	assert.False(t, isTestSessionActive(t, provider, first))
+	assert.True(t, isTestSessionActive(t, provider, second))
+	assert.True(t, isTestSessionActive(t, provider, third))
+
+	references, err := provider.loadUserSessions(testUsername)
+	require.NoError(t, err)
+	assert.Len(t, references, 2)
+}
+
+func TestShouldRejectConcurrentSessionOverLimit(t *testing.T) {
+	provider := newTestConcurrencyProvider(schema.SessionConcurrencyConfiguration{Limit: 1, Policy: schema.SessionConcurrencyPolicyReject})
+
+	first, err := newTestUserSession(t, provider, false)
+	require.NoError(t, err)
+
+	_, err = newTestUserSession(t, provider, false)
+	assert.Equal(t, ErrConcurrentSessionLimitReached, err)
+
+	assert.True(t, isTestSessionActive(t, provider, first))
+
+	// Once the session is destroyed a new session is allowed.
+	require.NoError(t, provider.DestroySession(first))
+
+	_, err = newTestUserSession(t, provider, false)
+	assert.NoError(t, err)
+}
+
+func TestShouldLimitRememberMeSessionsSeparately(t *testing.T) {
+	provider := newTestConcurrencyProvider(schema.SessionConcurrencyConfiguration{Limit: 1, RememberMeLimit: 1, Policy: schema.SessionConcurrencyPolicyReject})
+
+	_, err := newTestUserSession(t, provider, false)
+	require.NoError(t, err)
+
+	_, err = newTestUserSession(t, provider, true)
+	require.NoError(t, err)
+
+	_, err = newTestUserSession(t, provider, true)
+	assert.Equal(t, ErrConcurrentSessionLimitReached, err)
+
+	_, err = newTestUserSession(t, provider, false)
+	assert.Equal(t, ErrConcurrentSessionLimitReached, err)
+}
+
+func TestShouldTrackRegeneratedConcurrentSession(t *testing.T) {
+	provider := newTestConcurrencyProvider(schema.SessionConcurrencyConfiguration{Limit: 1, Policy: schema.SessionConcurrencyPolicyReject})
+
+	ctx, err := newTestUserSession(t, provider, false)
+	require.NoError(t, err)
+
+	require.NoError(t, provider.RegenerateSession(ctx))
+
+	id, err := provider.getSessionID(ctx)
+	require.NoError(t, err)
+
+	references, err := provider.loadUserSessions(testUsername)
+	require.NoError(t, err)
+	require.Len(t, references, 1)
+	assert.Equal(t, id, references[0].ID)
+
+	_, err = newTestUserSession(t, provider, false)
+	assert.Equal(t, ErrConcurrentSessionLimitReached, err)
+}
+
+func TestShouldDestroyUserSessionsExceptCurrentSession(t *testing.T) {
+	provider := NewProvider(schema.SessionConfiguration{
+		Domain:     testDomain,
+		Name:       testName,
+		Expiration: testExpiration,
+	}, nil, nil)
+
+	first, err := newTestUserSession(t, provider, false)
+	require.NoError(t, err)
+
+	second, err := newTestUserSession(t, provider, true)
+	require.NoError(t, err)
+
+	current, err := newTestUserSession(t, provider, false)
+	require.NoError(t, err)
+
+	references, err := provider.loadUserSessions(testUsername)
+	require.NoError(t, err)
+	assert.Len(t, references, 3)
+
+	destroyed, err := provider.DestroyUserSessions(current, testUsername)
+	require.NoError(t, err)
+	assert.Equal(t, 2, destroyed)
+
+	assert.False(t, isTestSessionActive(t, provider, first))
+	assert.False(t, isTestSessionActive(t, provider, second))
+	assert.True(t, isTestSessionActive(t, provider, current))
+
+	references, err = provider.loadUserSessions(testUsername)
+	require.NoError(t, err)
+	assert.Len(t, references, 0)
+}
+
+func TestShouldNotDestroyUserSessionsWhenDisabled(t *testing.T) {
+	provider := newTestConcurrencyProvider(schema.SessionConcurrencyConfiguration{})
+
+	first, err := newTestUserSession(t, provider, false)
+	require.NoError(t, err)
+
+	current, err := newTestUserSession(t, provider, false)
+	require.NoError(t, err)
+
+	destroyed, err := provider.DestroyUserSessions(current, testUsername)
+	require.NoError(t, err)
+	assert.Equal(t, 0, destroyed)
+
+	assert.True(t, isTestSessionActive(t, provider, first))
+}
@@ -7,11 +7,20 @@ import (
 const (
 	tableAuthenticationLogs   = "authentication_logs"
 	tableDuoDevices           = "duo_devices"
+	tableEmailVerification    = "email_verification"
 	tableIdentityVerification = "identity_verification"
+	tableLoginFingerprint     = "login_fingerprint"
+	tablePasswordResetCode    = "password_reset_code"
+	tableRegulationUnlock     = "regulation_unlock"
+	tableUserFirstLogin       = "user_first_login"
+	tableUserPasswordChange   = "user_password_change"
+	tableUserLoginLocation    = "user_login_location"
 	tableTOTPConfigurations   = "totp_configurations"
 	tableUserOpaqueIdentifier = "user_opaque_identifier"
 	tableUserPreferences      = "user_preferences"
+	tableUserRegistration     = "user_registration"
 	tableWebauthnDevices      = "webauthn_devices"
+	tableSessions             = "sessions"
 
 	tableOAuth2ConsentSession       = "oauth2_consent_session"
 	tableOAuth2AuthorizeCodeSession = "oauth2_authorization_code_session"
@@ -41,8 +50,40 @@ const (
 
 const (
 	encryptionNameCheck = "check"
+
+	// encryptionBatchSize is the number of rows selected at a time when checking or changing the encryption key.
+	encryptionBatchSize = 100
+)
+
+// encryptedColumns are the columns which are encrypted with the encryption key.
+var encryptedColumns = []encryptedColumn{
+	{Table: tableTOTPConfigurations, Column: "secret"},
+	{Table: tableWebauthnDevices, Column: "public_key"},
+	{Table: tableOAuth2AuthorizeCodeSession, Column: "session_data"},
+	{Table: tableOAuth2AccessTokenSession, Column: "session_data"},
+	{Table: tableOAuth2RefreshTokenSession, Column: "session_data"},
+	{Table: tableOAuth2PKCERequestSession, Column: "session_data"},
+	{Table: tableOAuth2OpenIDConnectSession, Column: "session_data"},
+}
+
+const (
+	conditionExpiredOAuth2Session = "(active = FALSE OR revoked = TRUE) AND requested_at < ?"
 )
 
+var expiredRows = []expiredRowsTable{
+	{Table: tableSessions, Condition: "expires_at IS NOT NULL AND expires_at <= ?"},
+	{Table: tableIdentityVerification, Condition: "exp < ?"},
+	{Table: tablePasswordResetCode, Condition: "expires_at < ?"},
+	{Table: tableEmailVerification, Condition: "expires_at < ?"},
+	{Table: tableRegulationUnlock, Condition: "expires_at < ?"},
+	{Table: tableOAuth2BlacklistedJTI, Condition: "expires_at < ?"},
+	{Table: tableOAuth2AuthorizeCodeSession, Condition: conditionExpiredOAuth2Session, OAuth2: true},
+	{Table: tableOAuth2AccessTokenSession, Condition: conditionExpiredOAuth2Session, OAuth2: true},
+	{Table: tableOAuth2RefreshTokenSession, Condition: conditionExpiredOAuth2Session, OAuth2: true},
+	{Table: tableOAuth2PKCERequestSession, Condition: conditionExpiredOAuth2Session, OAuth2: true},
+	{Table: tableOAuth2OpenIDConnectSession, Condition: conditionExpiredOAuth2Session, OAuth2: true},
+}
+
 // WARNING: Do not change/remove these consts. They are used for Pre1 migrations.
 const (
 	tablePre1TOTPSecrets                = "totp_secrets"
@@ -78,7 +119,7 @@ const (
 
 const (
 	// This is the latest schema version for the purpose of tests.
-	testLatestVersion = 4
+	testLatestVersion = 16
 )
 
 const (
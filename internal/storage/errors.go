@@ -11,6 +11,9 @@ var (
 	// ErrNoTOTPConfiguration error thrown when no TOTP configuration has been found in DB.
 	ErrNoTOTPConfiguration = errors.New("no TOTP configuration for user")
 
+	// ErrTOTPStepAlreadyUsed error thrown when a TOTP code of a step which has already been used is used again.
+	ErrTOTPStepAlreadyUsed = errors.New("the TOTP code has already been used")
+
 	// ErrNoWebauthnDevice error thrown when no Webauthn device handle has been found in DB.
 	ErrNoWebauthnDevice = errors.New("no Webauthn device found")
 
@@ -36,6 +39,9 @@ var (
 	// ErrSchemaEncryptionInvalidKey is returned when the schema is checked if the encryption key is valid for
 	// the database but the key doesn't appear to be valid.
 	ErrSchemaEncryptionInvalidKey = errors.New("the encryption key is not valid against the schema check value")
+
+	// ErrCleanupLocked is returned when the expired rows are not deleted as another instance holds the cleanup lock.
+	ErrCleanupLocked = errors.New("the cleanup lock is held by another instance")
 )
 
 // Error formats for the storage provider.
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS user_login_location (
+    id INTEGER AUTO_INCREMENT,
+    time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL,
+    remote_ip VARCHAR(39) NULL DEFAULT NULL,
+    latitude DOUBLE NOT NULL,
+    longitude DOUBLE NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY (username)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS user_login_location (
+    id SERIAL,
+    time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL,
+    remote_ip VARCHAR(39) NULL DEFAULT NULL,
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (username)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS user_registration (
+    id INTEGER AUTO_INCREMENT,
+    requested_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    remote_ip VARCHAR(39) NULL DEFAULT NULL,
+    username VARCHAR(100) NOT NULL,
+    display_name VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    password TEXT NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY (username)
+);
@@ -0,0 +1 @@
+ALTER TABLE authentication_logs DROP COLUMN user_agent;
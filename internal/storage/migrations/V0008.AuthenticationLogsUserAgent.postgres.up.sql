@@ -0,0 +1 @@
+ALTER TABLE authentication_logs ADD COLUMN user_agent VARCHAR(512) NOT NULL DEFAULT '';
@@ -0,0 +1,24 @@
+ALTER TABLE totp_configurations RENAME TO _bkp_DOWN_V0009_totp_configurations;
+
+CREATE TABLE IF NOT EXISTS totp_configurations (
+    id SERIAL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL,
+    username VARCHAR(100) NOT NULL,
+    issuer VARCHAR(100),
+    algorithm VARCHAR(6) NOT NULL DEFAULT 'SHA1',
+    digits INTEGER NOT NULL DEFAULT 6,
+    period INTEGER NOT NULL DEFAULT 30,
+    secret BYTEA NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (username)
+);
+
+INSERT INTO totp_configurations (id, created_at, last_used_at, username, issuer, algorithm, digits, period, secret)
+SELECT id, created_at, last_used_at, username, issuer, algorithm, digits, period, secret
+FROM _bkp_DOWN_V0009_totp_configurations
+WHERE id IN (SELECT MIN(id) FROM _bkp_DOWN_V0009_totp_configurations GROUP BY username);
+
+SELECT setval(pg_get_serial_sequence('totp_configurations', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM totp_configurations;
+
+DROP TABLE IF EXISTS _bkp_DOWN_V0009_totp_configurations;
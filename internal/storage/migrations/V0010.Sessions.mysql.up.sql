@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    id VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NULL DEFAULT NULL,
+    data BLOB NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX sessions_expires_at_idx ON sessions (expires_at);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS login_fingerprint (
+    id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL,
+    notified_at TIMESTAMP NULL DEFAULT NULL,
+    username VARCHAR(100) NOT NULL,
+    device VARCHAR(64) NOT NULL,
+    network VARCHAR(50) NOT NULL,
+    country VARCHAR(100) NOT NULL,
+    suppress_token_hash VARCHAR(64) NOT NULL DEFAULT '',
+    suppressed BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (id),
+    UNIQUE (username, device, network, country)
+);
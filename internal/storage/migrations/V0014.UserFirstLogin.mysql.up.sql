@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS user_first_login (
+    id INTEGER AUTO_INCREMENT,
+    time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY (username)
+);
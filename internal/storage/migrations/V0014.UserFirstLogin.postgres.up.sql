@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS user_first_login (
+    id SERIAL,
+    time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (username)
+);
@@ -0,0 +1 @@
+ALTER TABLE oauth2_refresh_token_session DROP COLUMN last_used_at;
@@ -0,0 +1 @@
+ALTER TABLE oauth2_refresh_token_session ADD COLUMN last_used_at TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS user_password_change (
+    id SERIAL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (username)
+);
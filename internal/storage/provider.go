@@ -16,25 +16,48 @@ type Provider interface {
 
 	RegulatorProvider
 
+	SessionProvider
+
 	storage.Transactional
 
 	SavePreferred2FAMethod(ctx context.Context, username string, method string) (err error)
 	LoadPreferred2FAMethod(ctx context.Context, username string) (method string, err error)
 	LoadUserInfo(ctx context.Context, username string) (info model.UserInfo, err error)
 
+	SaveUserFirstLogin(ctx context.Context, username string, at time.Time) (err error)
+	LoadUserFirstLogin(ctx context.Context, username string) (at *time.Time, err error)
+
+	SaveUserPasswordChange(ctx context.Context, username string, at time.Time) (err error)
+	LoadUserPasswordChange(ctx context.Context, username string) (at *time.Time, err error)
+
 	SaveUserOpaqueIdentifier(ctx context.Context, subject model.UserOpaqueIdentifier) (err error)
 	LoadUserOpaqueIdentifier(ctx context.Context, opaqueUUID uuid.UUID) (subject *model.UserOpaqueIdentifier, err error)
 	LoadUserOpaqueIdentifiers(ctx context.Context) (opaqueIDs []model.UserOpaqueIdentifier, err error)
 	LoadUserOpaqueIdentifierBySignature(ctx context.Context, service, sectorID, username string) (subject *model.UserOpaqueIdentifier, err error)
 
+	SaveUserRegistration(ctx context.Context, registration model.UserRegistration) (err error)
+	LoadUserRegistration(ctx context.Context, username string) (registration *model.UserRegistration, err error)
+	LoadUserRegistrations(ctx context.Context) (registrations []model.UserRegistration, err error)
+	DeleteUserRegistration(ctx context.Context, username string) (err error)
+
+	SavePasswordResetCode(ctx context.Context, code model.PasswordResetCode) (err error)
+	LoadPasswordResetCode(ctx context.Context, username string) (code *model.PasswordResetCode, err error)
+	DeletePasswordResetCode(ctx context.Context, username string) (err error)
+
+	SaveEmailVerification(ctx context.Context, verification model.EmailVerification) (err error)
+	LoadEmailVerification(ctx context.Context, username string) (verification *model.EmailVerification, err error)
+	ConsumeEmailVerification(ctx context.Context, username string, verifiedAt time.Time) (err error)
+	DeleteEmailVerification(ctx context.Context, username string) (err error)
+
 	SaveIdentityVerification(ctx context.Context, verification model.IdentityVerification) (err error)
 	ConsumeIdentityVerification(ctx context.Context, jti string, ip model.NullIP) (err error)
 	FindIdentityVerification(ctx context.Context, jti string) (found bool, err error)
 
 	SaveTOTPConfiguration(ctx context.Context, config model.TOTPConfiguration) (err error)
-	UpdateTOTPConfigurationSignIn(ctx context.Context, id int, lastUsedAt *time.Time) (err error)
+	UpdateTOTPConfigurationSignIn(ctx context.Context, id int, lastUsedAt *time.Time, lastUsedStep uint64) (err error)
 	DeleteTOTPConfiguration(ctx context.Context, username string) (err error)
-	LoadTOTPConfiguration(ctx context.Context, username string) (config *model.TOTPConfiguration, err error)
+	DeleteTOTPConfigurationByID(ctx context.Context, username string, id int) (err error)
+	LoadTOTPConfigurationsByUsername(ctx context.Context, username string) (configs []model.TOTPConfiguration, err error)
 	LoadTOTPConfigurations(ctx context.Context, limit, page int) (configs []model.TOTPConfiguration, err error)
 
 	SaveWebauthnDevice(ctx context.Context, device model.WebauthnDevice) (err error)
@@ -46,11 +69,18 @@ type Provider interface {
 	DeletePreferredDuoDevice(ctx context.Context, username string) (err error)
 	LoadPreferredDuoDevice(ctx context.Context, username string) (device *model.DuoDevice, err error)
 
+	SaveLoginFingerprint(ctx context.Context, fingerprint model.LoginFingerprint) (err error)
+	LoadLoginFingerprints(ctx context.Context, username string) (fingerprints []model.LoginFingerprint, err error)
+	UpdateLoginFingerprintSeen(ctx context.Context, id int, seenAt time.Time) (err error)
+	SuppressLoginFingerprint(ctx context.Context, username string, id int) (err error)
+	DeleteLoginFingerprints(ctx context.Context, username string, before time.Time) (err error)
+
 	SaveOAuth2ConsentSession(ctx context.Context, consent model.OAuth2ConsentSession) (err error)
 	SaveOAuth2ConsentSessionResponse(ctx context.Context, consent model.OAuth2ConsentSession, rejection bool) (err error)
 	SaveOAuth2ConsentSessionGranted(ctx context.Context, id int) (err error)
 	LoadOAuth2ConsentSessionByChallengeID(ctx context.Context, challengeID uuid.UUID) (consent *model.OAuth2ConsentSession, err error)
 	LoadOAuth2ConsentSessionsPreConfigured(ctx context.Context, clientID string, subject uuid.UUID) (rows *ConsentSessionRows, err error)
+	LoadOAuth2ConsentSessionsActive(ctx context.Context, username string) (consents []model.OAuth2ConsentSession, err error)
 
 	SaveOAuth2Session(ctx context.Context, sessionType OAuth2SessionType, session model.OAuth2Session) (err error)
 	RevokeOAuth2Session(ctx context.Context, sessionType OAuth2SessionType, signature string) (err error)
@@ -59,6 +89,9 @@ type Provider interface {
 	DeactivateOAuth2SessionByRequestID(ctx context.Context, sessionType OAuth2SessionType, requestID string) (err error)
 	LoadOAuth2Session(ctx context.Context, sessionType OAuth2SessionType, signature string) (session *model.OAuth2Session, err error)
 
+	UpdateOAuth2RefreshTokenSessionLastUsed(ctx context.Context, signature string, lastUsedAt time.Time) (err error)
+	LoadOAuth2RefreshTokensActive(ctx context.Context, username string) (tokens []model.OAuth2RefreshToken, err error)
+
 	SaveOAuth2BlacklistedJTI(ctx context.Context, blacklistedJTI model.OAuth2BlacklistedJTI) (err error)
 	LoadOAuth2BlacklistedJTI(ctx context.Context, signature string) (blacklistedJTI *model.OAuth2BlacklistedJTI, err error)
 
@@ -71,9 +104,11 @@ type Provider interface {
 	SchemaMigrationsUp(ctx context.Context, version int) (migrations []model.SchemaMigration, err error)
 	SchemaMigrationsDown(ctx context.Context, version int) (migrations []model.SchemaMigration, err error)
 
-	SchemaEncryptionChangeKey(ctx context.Context, encryptionKey string) (err error)
+	SchemaEncryptionChangeKey(ctx context.Context, encryptionKey string, dryRun bool, progress func(result EncryptionChangeKeyResult)) (err error)
 	SchemaEncryptionCheckKey(ctx context.Context, verbose bool) (err error)
 
+	DeleteExpiredRows(ctx context.Context, now, oauth2Before time.Time, batchSize int) (deleted map[string]int64, err error)
+
 	Close() (err error)
 }
 
@@ -81,4 +116,25 @@ type Provider interface {
 type RegulatorProvider interface {
 	AppendAuthenticationLog(ctx context.Context, attempt model.AuthenticationAttempt) (err error)
 	LoadAuthenticationLogs(ctx context.Context, username string, fromDate time.Time, limit, page int) (attempts []model.AuthenticationAttempt, err error)
+	LoadUserAuthenticationLogs(ctx context.Context, username string, limit, page int) (attempts []model.AuthenticationAttempt, err error)
+	CountSecondFactorFailedAuthenticationLogs(ctx context.Context, username string, since time.Time) (count int, err error)
+	DeleteAuthenticationLogs(ctx context.Context, before time.Time) (err error)
+
+	SaveUserLoginLocation(ctx context.Context, location model.UserLoginLocation) (err error)
+	LoadUserLoginLocation(ctx context.Context, username string) (location *model.UserLoginLocation, err error)
+
+	SaveRegulationUnlock(ctx context.Context, unlock model.RegulationUnlock) (err error)
+	LoadRegulationUnlock(ctx context.Context, username string) (unlock *model.RegulationUnlock, err error)
+	ConsumeRegulationUnlock(ctx context.Context, username string, unlockedAt time.Time) (err error)
+	IncrementRegulationUnlockAttempts(ctx context.Context, username string) (err error)
+}
+
+// SessionProvider is an interface providing storage capabilities for persisting user sessions.
+type SessionProvider interface {
+	SaveSession(ctx context.Context, session model.Session) (err error)
+	LoadSession(ctx context.Context, id string, now time.Time) (session *model.Session, err error)
+	CountSessions(ctx context.Context, now time.Time) (count int, err error)
+	UpdateSessionID(ctx context.Context, id, newID string, expiresAt *time.Time) (err error)
+	DeleteSession(ctx context.Context, id string) (err error)
+	DeleteExpiredSessions(ctx context.Context, now time.Time) (err error)
 }
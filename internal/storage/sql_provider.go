@@ -32,15 +32,60 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 
 		sqlInsertAuthenticationAttempt:            fmt.Sprintf(queryFmtInsertAuthenticationLogEntry, tableAuthenticationLogs),
 		sqlSelectAuthenticationAttemptsByUsername: fmt.Sprintf(queryFmtSelect1FAAuthenticationLogEntryByUsername, tableAuthenticationLogs),
+		sqlSelectAuthenticationLogsByUsername:     fmt.Sprintf(queryFmtSelectAuthenticationLogEntriesByUsername, tableAuthenticationLogs),
+		sqlSelectSecondFactorFailedLogsCount:      fmt.Sprintf(queryFmtSelectSecondFactorFailedAuthenticationLogEntriesCount, tableAuthenticationLogs),
+		sqlDeleteAuthenticationLogsBefore:         fmt.Sprintf(queryFmtDeleteAuthenticationLogEntriesBefore, tableAuthenticationLogs),
+
+		sqlUpsertUserLoginLocation: fmt.Sprintf(queryFmtUpsertUserLoginLocation, tableUserLoginLocation),
+		sqlSelectUserLoginLocation: fmt.Sprintf(queryFmtSelectUserLoginLocation, tableUserLoginLocation),
+
+		sqlInsertUserFirstLogin: fmt.Sprintf(queryFmtInsertUserFirstLogin, tableUserFirstLogin),
+		sqlSelectUserFirstLogin: fmt.Sprintf(queryFmtSelectUserFirstLogin, tableUserFirstLogin),
+
+		sqlUpsertUserPasswordChange: fmt.Sprintf(queryFmtUpsertUserPasswordChange, tableUserPasswordChange),
+		sqlSelectUserPasswordChange: fmt.Sprintf(queryFmtSelectUserPasswordChange, tableUserPasswordChange),
+
+		sqlUpsertRegulationUnlock:         fmt.Sprintf(queryFmtUpsertRegulationUnlock, tableRegulationUnlock),
+		sqlSelectRegulationUnlock:         fmt.Sprintf(queryFmtSelectRegulationUnlock, tableRegulationUnlock),
+		sqlUpdateRegulationUnlockUnlocked: fmt.Sprintf(queryFmtUpdateRegulationUnlockUnlocked, tableRegulationUnlock),
+		sqlUpdateRegulationUnlockAttempts: fmt.Sprintf(queryFmtUpdateRegulationUnlockAttempts, tableRegulationUnlock),
+
+		sqlSelectLoginFingerprints:          fmt.Sprintf(queryFmtSelectLoginFingerprints, tableLoginFingerprint),
+		sqlInsertLoginFingerprint:           fmt.Sprintf(queryFmtInsertLoginFingerprint, tableLoginFingerprint),
+		sqlUpdateLoginFingerprintSeen:       fmt.Sprintf(queryFmtUpdateLoginFingerprintSeen, tableLoginFingerprint),
+		sqlUpdateLoginFingerprintSuppressed: fmt.Sprintf(queryFmtUpdateLoginFingerprintSuppressed, tableLoginFingerprint),
+		sqlDeleteLoginFingerprintsBefore:    fmt.Sprintf(queryFmtDeleteLoginFingerprintsBefore, tableLoginFingerprint),
+
+		sqlInsertUserRegistration:  fmt.Sprintf(queryFmtInsertUserRegistration, tableUserRegistration),
+		sqlSelectUserRegistration:  fmt.Sprintf(queryFmtSelectUserRegistration, tableUserRegistration),
+		sqlSelectUserRegistrations: fmt.Sprintf(queryFmtSelectUserRegistrations, tableUserRegistration),
+		sqlDeleteUserRegistration:  fmt.Sprintf(queryFmtDeleteUserRegistration, tableUserRegistration),
+
+		sqlUpsertPasswordResetCode: fmt.Sprintf(queryFmtUpsertPasswordResetCode, tablePasswordResetCode),
+		sqlSelectPasswordResetCode: fmt.Sprintf(queryFmtSelectPasswordResetCode, tablePasswordResetCode),
+		sqlDeletePasswordResetCode: fmt.Sprintf(queryFmtDeletePasswordResetCode, tablePasswordResetCode),
+
+		sqlUpsertEmailVerification:         fmt.Sprintf(queryFmtUpsertEmailVerification, tableEmailVerification),
+		sqlSelectEmailVerification:         fmt.Sprintf(queryFmtSelectEmailVerification, tableEmailVerification),
+		sqlUpdateEmailVerificationVerified: fmt.Sprintf(queryFmtUpdateEmailVerificationVerified, tableEmailVerification),
+		sqlDeleteEmailVerification:         fmt.Sprintf(queryFmtDeleteEmailVerification, tableEmailVerification),
+
+		sqlUpsertSession:         fmt.Sprintf(queryFmtUpsertSession, tableSessions),
+		sqlSelectSession:         fmt.Sprintf(queryFmtSelectSession, tableSessions),
+		sqlSelectSessionsCount:   fmt.Sprintf(queryFmtSelectSessionsCount, tableSessions),
+		sqlUpdateSessionID:       fmt.Sprintf(queryFmtUpdateSessionID, tableSessions),
+		sqlDeleteSession:         fmt.Sprintf(queryFmtDeleteSession, tableSessions),
+		sqlDeleteSessionsExpired: fmt.Sprintf(queryFmtDeleteSessionsExpired, tableSessions),
 
 		sqlInsertIdentityVerification:  fmt.Sprintf(queryFmtInsertIdentityVerification, tableIdentityVerification),
 		sqlConsumeIdentityVerification: fmt.Sprintf(queryFmtConsumeIdentityVerification, tableIdentityVerification),
 		sqlSelectIdentityVerification:  fmt.Sprintf(queryFmtSelectIdentityVerification, tableIdentityVerification),
 
-		sqlUpsertTOTPConfig:  fmt.Sprintf(queryFmtUpsertTOTPConfiguration, tableTOTPConfigurations),
-		sqlDeleteTOTPConfig:  fmt.Sprintf(queryFmtDeleteTOTPConfiguration, tableTOTPConfigurations),
-		sqlSelectTOTPConfig:  fmt.Sprintf(queryFmtSelectTOTPConfiguration, tableTOTPConfigurations),
-		sqlSelectTOTPConfigs: fmt.Sprintf(queryFmtSelectTOTPConfigurations, tableTOTPConfigurations),
+		sqlUpsertTOTPConfig:            fmt.Sprintf(queryFmtUpsertTOTPConfiguration, tableTOTPConfigurations),
+		sqlDeleteTOTPConfig:            fmt.Sprintf(queryFmtDeleteTOTPConfiguration, tableTOTPConfigurations),
+		sqlSelectTOTPConfigsByUsername: fmt.Sprintf(queryFmtSelectTOTPConfigurationsByUsername, tableTOTPConfigurations),
+		sqlDeleteTOTPConfigByID:        fmt.Sprintf(queryFmtDeleteTOTPConfigurationByID, tableTOTPConfigurations),
+		sqlSelectTOTPConfigs:           fmt.Sprintf(queryFmtSelectTOTPConfigurations, tableTOTPConfigurations),
 
 		sqlUpdateTOTPConfigSecret:                 fmt.Sprintf(queryFmtUpdateTOTPConfigurationSecret, tableTOTPConfigurations),
 		sqlUpdateTOTPConfigSecretByUsername:       fmt.Sprintf(queryFmtUpdateTOTPConfigurationSecretByUsername, tableTOTPConfigurations),
@@ -89,6 +134,8 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlRevokeOAuth2RefreshTokenSessionByRequestID:     fmt.Sprintf(queryFmtRevokeOAuth2SessionByRequestID, tableOAuth2RefreshTokenSession),
 		sqlDeactivateOAuth2RefreshTokenSession:            fmt.Sprintf(queryFmtDeactivateOAuth2Session, tableOAuth2RefreshTokenSession),
 		sqlDeactivateOAuth2RefreshTokenSessionByRequestID: fmt.Sprintf(queryFmtDeactivateOAuth2SessionByRequestID, tableOAuth2RefreshTokenSession),
+		sqlUpdateOAuth2RefreshTokenSessionLastUsed:        fmt.Sprintf(queryFmtUpdateOAuth2RefreshTokenSessionLastUsed, tableOAuth2RefreshTokenSession),
+		sqlSelectOAuth2RefreshTokenSessionsByUsername:     fmt.Sprintf(queryFmtSelectOAuth2RefreshTokenSessionsByUsername, tableOAuth2RefreshTokenSession, tableUserOpaqueIdentifier),
 
 		sqlInsertOAuth2PKCERequestSession:                fmt.Sprintf(queryFmtInsertOAuth2Session, tableOAuth2PKCERequestSession),
 		sqlSelectOAuth2PKCERequestSession:                fmt.Sprintf(queryFmtSelectOAuth2Session, tableOAuth2PKCERequestSession),
@@ -109,6 +156,7 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlUpdateOAuth2ConsentSessionGranted:        fmt.Sprintf(queryFmtUpdateOAuth2ConsentSessionGranted, tableOAuth2ConsentSession),
 		sqlSelectOAuth2ConsentSessionByChallengeID:  fmt.Sprintf(queryFmtSelectOAuth2ConsentSessionByChallengeID, tableOAuth2ConsentSession),
 		sqlSelectOAuth2ConsentSessionsPreConfigured: fmt.Sprintf(queryFmtSelectOAuth2ConsentSessionsPreConfigured, tableOAuth2ConsentSession),
+		sqlSelectOAuth2ConsentSessionsActive:        fmt.Sprintf(queryFmtSelectOAuth2ConsentSessionsActiveByUsername, tableOAuth2ConsentSession, tableUserOpaqueIdentifier),
 
 		sqlUpsertOAuth2BlacklistedJTI: fmt.Sprintf(queryFmtUpsertOAuth2BlacklistedJTI, tableOAuth2BlacklistedJTI),
 		sqlSelectOAuth2BlacklistedJTI: fmt.Sprintf(queryFmtSelectOAuth2BlacklistedJTI, tableOAuth2BlacklistedJTI),
@@ -141,6 +189,59 @@ type SQLProvider struct {
 	// Table: authentication_logs.
 	sqlInsertAuthenticationAttempt            string
 	sqlSelectAuthenticationAttemptsByUsername string
+	sqlSelectAuthenticationLogsByUsername     string
+	sqlSelectSecondFactorFailedLogsCount      string
+	sqlDeleteAuthenticationLogsBefore         string
+
+	// Table: user_login_location.
+	sqlUpsertUserLoginLocation string
+	sqlSelectUserLoginLocation string
+
+	// Table: user_first_login.
+	sqlInsertUserFirstLogin string
+	sqlSelectUserFirstLogin string
+
+	// Table: user_password_change.
+	sqlUpsertUserPasswordChange string
+	sqlSelectUserPasswordChange string
+
+	// Table: regulation_unlock.
+	sqlUpsertRegulationUnlock         string
+	sqlSelectRegulationUnlock         string
+	sqlUpdateRegulationUnlockUnlocked string
+	sqlUpdateRegulationUnlockAttempts string
+
+	// Table: login_fingerprint.
+	sqlSelectLoginFingerprints          string
+	sqlInsertLoginFingerprint           string
+	sqlUpdateLoginFingerprintSeen       string
+	sqlUpdateLoginFingerprintSuppressed string
+	sqlDeleteLoginFingerprintsBefore    string
+
+	// Table: user_registration.
+	sqlInsertUserRegistration  string
+	sqlSelectUserRegistration  string
+	sqlSelectUserRegistrations string
+	sqlDeleteUserRegistration  string
+
+	// Table: password_reset_code.
+	sqlUpsertPasswordResetCode string
+	sqlSelectPasswordResetCode string
+	sqlDeletePasswordResetCode string
+
+	// Table: email_verification.
+	sqlUpsertEmailVerification         string
+	sqlSelectEmailVerification         string
+	sqlUpdateEmailVerificationVerified string
+	sqlDeleteEmailVerification         string
+
+	// Table: sessions.
+	sqlUpsertSession         string
+	sqlSelectSession         string
+	sqlSelectSessionsCount   string
+	sqlUpdateSessionID       string
+	sqlDeleteSession         string
+	sqlDeleteSessionsExpired string
 
 	// Table: identity_verification.
 	sqlInsertIdentityVerification  string
@@ -150,9 +251,11 @@ type SQLProvider struct {
 	// Table: totp_configurations.
 	sqlUpsertTOTPConfig  string
 	sqlDeleteTOTPConfig  string
-	sqlSelectTOTPConfig  string
 	sqlSelectTOTPConfigs string
 
+	sqlSelectTOTPConfigsByUsername string
+	sqlDeleteTOTPConfigByID        string
+
 	sqlUpdateTOTPConfigSecret                 string
 	sqlUpdateTOTPConfigSecretByUsername       string
 	sqlUpdateTOTPConfigRecordSignIn           string
@@ -216,6 +319,8 @@ type SQLProvider struct {
 	sqlRevokeOAuth2RefreshTokenSessionByRequestID     string
 	sqlDeactivateOAuth2RefreshTokenSession            string
 	sqlDeactivateOAuth2RefreshTokenSessionByRequestID string
+	sqlUpdateOAuth2RefreshTokenSessionLastUsed        string
+	sqlSelectOAuth2RefreshTokenSessionsByUsername     string
 
 	// Table: oauth2_pkce_request_session.
 	sqlInsertOAuth2PKCERequestSession                string
@@ -239,6 +344,7 @@ type SQLProvider struct {
 	sqlUpdateOAuth2ConsentSessionGranted        string
 	sqlSelectOAuth2ConsentSessionByChallengeID  string
 	sqlSelectOAuth2ConsentSessionsPreConfigured string
+	sqlSelectOAuth2ConsentSessionsActive        string
 
 	sqlUpsertOAuth2BlacklistedJTI string
 	sqlSelectOAuth2BlacklistedJTI string
@@ -246,6 +352,10 @@ type SQLProvider struct {
 	// Utility.
 	sqlSelectExistingTables string
 	sqlFmtRenameTable       string
+
+	// Cleanup lock, SQLite doesn't require a lock as the database can't be shared by multiple instances.
+	sqlSelectCleanupLock   string
+	sqlSelectCleanupUnlock string
 }
 
 // Close the underlying database connection.
@@ -253,22 +363,26 @@ func (p *SQLProvider) Close() (err error) {
 	return p.db.Close()
 }
 
-// StartupCheck implements the provider startup check interface.
-func (p *SQLProvider) StartupCheck() (err error) {
+// HealthCheck implements the provider health check interface by pinging the database.
+func (p *SQLProvider) HealthCheck(ctx context.Context) (err error) {
 	if p.errOpen != nil {
 		return fmt.Errorf("error opening database: %w", p.errOpen)
 	}
 
-	// TODO: Decide if this is needed, or if it should be configurable.
-	for i := 0; i < 19; i++ {
-		if err = p.db.Ping(); err == nil {
-			break
-		}
+	if err = p.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("error pinging database: %w", err)
+	}
+
+	return nil
+}
 
-		time.Sleep(time.Millisecond * 500)
+// StartupCheck implements the provider startup check interface.
+func (p *SQLProvider) StartupCheck() (err error) {
+	if p.errOpen != nil {
+		return fmt.Errorf("error opening database: %w", p.errOpen)
 	}
 
-	if err != nil {
+	if err = p.ping(p.config.Storage.ConnectionRetry); err != nil {
 		return fmt.Errorf("error pinging database: %w", err)
 	}
 
@@ -293,6 +407,37 @@ func (p *SQLProvider) StartupCheck() (err error) {
 	}
 }
 
+// ping pings the database until it succeeds or the max duration of the provided configuration has elapsed. The
+// interval between the attempts is multiplied by the backoff factor after each failed attempt.
+func (p *SQLProvider) ping(config schema.StorageConnectionRetryConfiguration) (err error) {
+	start := time.Now()
+	interval := config.Interval
+
+	for attempt := 1; ; attempt++ {
+		if err = p.db.Ping(); err == nil {
+			return nil
+		}
+
+		remaining := config.MaxDuration - time.Since(start)
+
+		if remaining <= 0 {
+			p.log.Errorf("Attempt %d to connect to the database failed, giving up after %s: %v", attempt, time.Since(start).Round(time.Millisecond), err)
+
+			return err
+		}
+
+		if interval > remaining {
+			interval = remaining
+		}
+
+		p.log.Warnf("Attempt %d to connect to the database failed, retrying in %s: %v", attempt, interval, err)
+
+		time.Sleep(interval)
+
+		interval = time.Duration(float64(interval) * config.BackoffFactor)
+	}
+}
+
 // BeginTX begins a transaction.
 func (p *SQLProvider) BeginTX(ctx context.Context) (c context.Context, err error) {
 	var tx *sql.Tx
@@ -447,6 +592,15 @@ func (p *SQLProvider) LoadOAuth2ConsentSessionsPreConfigured(ctx context.Context
 	return &ConsentSessionRows{rows: r}, nil
 }
 
+// LoadOAuth2ConsentSessionsActive returns the OAuth2.0 consents of a user that are pre-configured and haven't expired.
+func (p *SQLProvider) LoadOAuth2ConsentSessionsActive(ctx context.Context, username string) (consents []model.OAuth2ConsentSession, err error) {
+	if err = p.db.SelectContext(ctx, &consents, p.sqlSelectOAuth2ConsentSessionsActive, username); err != nil {
+		return nil, fmt.Errorf("error selecting active oauth2 consent sessions for user '%s': %w", username, err)
+	}
+
+	return consents, nil
+}
+
 // SaveOAuth2Session saves a OAuth2Session to the database.
 func (p *SQLProvider) SaveOAuth2Session(ctx context.Context, sessionType OAuth2SessionType, session model.OAuth2Session) (err error) {
 	var query string
@@ -619,6 +773,45 @@ func (p *SQLProvider) LoadOAuth2Session(ctx context.Context, sessionType OAuth2S
 	return session, nil
 }
 
+// UpdateOAuth2RefreshTokenSessionLastUsed records the time the refresh token which preceded the refresh token with the
+// given signature was used.
+func (p *SQLProvider) UpdateOAuth2RefreshTokenSessionLastUsed(ctx context.Context, signature string, lastUsedAt time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateOAuth2RefreshTokenSessionLastUsed, lastUsedAt, signature); err != nil {
+		return fmt.Errorf("error updating oauth2 refresh token session last used time with signature '%s': %w", signature, err)
+	}
+
+	return nil
+}
+
+// LoadOAuth2RefreshTokensActive returns the OAuth2.0 refresh tokens of a user which are active and haven't been revoked.
+// Each refresh token is rotated when it's used, the rotated refresh tokens share the request id and the time of the
+// first one is the time the refresh token was originally issued.
+func (p *SQLProvider) LoadOAuth2RefreshTokensActive(ctx context.Context, username string) (tokens []model.OAuth2RefreshToken, err error) {
+	var sessions []model.OAuth2RefreshToken
+
+	if err = p.db.SelectContext(ctx, &sessions, p.sqlSelectOAuth2RefreshTokenSessionsByUsername, username); err != nil {
+		return nil, fmt.Errorf("error selecting oauth2 refresh token sessions for user '%s': %w", username, err)
+	}
+
+	issued := map[string]time.Time{}
+
+	for _, session := range sessions {
+		if _, ok := issued[session.RequestID]; !ok {
+			issued[session.RequestID] = session.RequestedAt
+		}
+
+		if !session.Active {
+			continue
+		}
+
+		session.RequestedAt = issued[session.RequestID]
+
+		tokens = append(tokens, session)
+	}
+
+	return tokens, nil
+}
+
 // SaveOAuth2BlacklistedJTI saves a OAuth2BlacklistedJTI to the database.
 func (p *SQLProvider) SaveOAuth2BlacklistedJTI(ctx context.Context, blacklistedJTI model.OAuth2BlacklistedJTI) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlUpsertOAuth2BlacklistedJTI, blacklistedJTI.Signature, blacklistedJTI.ExpiresAt); err != nil {
@@ -674,6 +867,121 @@ func (p *SQLProvider) LoadUserInfo(ctx context.Context, username string) (info m
 	}
 }
 
+// SaveUserFirstLogin saves the time a user first completed the first factor.
+func (p *SQLProvider) SaveUserFirstLogin(ctx context.Context, username string, at time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertUserFirstLogin, at, username); err != nil {
+		return fmt.Errorf("error inserting first login for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// LoadUserFirstLogin loads the time a user first completed the first factor, it returns nil if it wasn't recorded.
+func (p *SQLProvider) LoadUserFirstLogin(ctx context.Context, username string) (at *time.Time, err error) {
+	at = &time.Time{}
+
+	if err = p.db.GetContext(ctx, at, p.sqlSelectUserFirstLogin, username); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("error selecting first login for user '%s': %w", username, err)
+		}
+	}
+
+	return at, nil
+}
+
+// SaveUserPasswordChange saves the time a user last changed their password, replacing any previously recorded time.
+func (p *SQLProvider) SaveUserPasswordChange(ctx context.Context, username string, at time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpsertUserPasswordChange, at, username); err != nil {
+		return fmt.Errorf("error upserting password change for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// LoadUserPasswordChange loads the time a user last changed their password, it returns nil if it wasn't recorded.
+func (p *SQLProvider) LoadUserPasswordChange(ctx context.Context, username string) (at *time.Time, err error) {
+	at = &time.Time{}
+
+	if err = p.db.GetContext(ctx, at, p.sqlSelectUserPasswordChange, username); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("error selecting password change for user '%s': %w", username, err)
+		}
+	}
+
+	return at, nil
+}
+
+// SaveSession saves the data of a user session.
+func (p *SQLProvider) SaveSession(ctx context.Context, session model.Session) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpsertSession, session.ID, session.ExpiresAt, session.Data); err != nil {
+		return fmt.Errorf("error upserting session: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSession loads the data of a user session. Expired sessions are not returned.
+func (p *SQLProvider) LoadSession(ctx context.Context, id string, now time.Time) (session *model.Session, err error) {
+	session = &model.Session{}
+
+	if err = p.db.GetContext(ctx, session, p.sqlSelectSession, id); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("error selecting session: %w", err)
+		}
+	}
+
+	if session.IsExpired(now) {
+		return nil, nil
+	}
+
+	return session, nil
+}
+
+// CountSessions counts the user sessions which have not expired.
+func (p *SQLProvider) CountSessions(ctx context.Context, now time.Time) (count int, err error) {
+	if err = p.db.GetContext(ctx, &count, p.sqlSelectSessionsCount, now); err != nil {
+		return 0, fmt.Errorf("error counting sessions: %w", err)
+	}
+
+	return count, nil
+}
+
+// UpdateSessionID changes the ID and expiration of a user session.
+func (p *SQLProvider) UpdateSessionID(ctx context.Context, id, newID string, expiresAt *time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateSessionID, newID, expiresAt, id); err != nil {
+		return fmt.Errorf("error updating session id: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSession deletes a user session.
+func (p *SQLProvider) DeleteSession(ctx context.Context, id string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteSession, id); err != nil {
+		return fmt.Errorf("error deleting session: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredSessions deletes the user sessions which expired at or before the provided time.
+func (p *SQLProvider) DeleteExpiredSessions(ctx context.Context, now time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteSessionsExpired, now); err != nil {
+		return fmt.Errorf("error deleting sessions expired before '%s': %w", now.Format(time.RFC3339), err)
+	}
+
+	return nil
+}
+
 // SaveIdentityVerification save an identity verification record to the database.
 func (p *SQLProvider) SaveIdentityVerification(ctx context.Context, verification model.IdentityVerification) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlInsertIdentityVerification,
@@ -715,15 +1023,16 @@ func (p *SQLProvider) FindIdentityVerification(ctx context.Context, jti string)
 	}
 }
 
-// SaveTOTPConfiguration save a TOTP configuration of a given user in the database.
+// SaveTOTPConfiguration save a TOTP configuration of a given user in the database. A configuration with the same
+// username and description is replaced.
 func (p *SQLProvider) SaveTOTPConfiguration(ctx context.Context, config model.TOTPConfiguration) (err error) {
 	if config.Secret, err = p.encrypt(config.Secret); err != nil {
 		return fmt.Errorf("error encrypting the TOTP configuration secret for user '%s': %w", config.Username, err)
 	}
 
 	if _, err = p.db.ExecContext(ctx, p.sqlUpsertTOTPConfig,
-		config.CreatedAt, config.LastUsedAt,
-		config.Username, config.Issuer,
+		config.CreatedAt, config.LastUsedAt, config.LastUsedStep,
+		config.Username, config.Description, config.Issuer,
 		config.Algorithm, config.Digits, config.Period, config.Secret); err != nil {
 		return fmt.Errorf("error upserting TOTP configuration for user '%s': %w", config.Username, err)
 	}
@@ -731,16 +1040,30 @@ func (p *SQLProvider) SaveTOTPConfiguration(ctx context.Context, config model.TO
 	return nil
 }
 
-// UpdateTOTPConfigurationSignIn updates a registered Webauthn devices sign in information.
-func (p *SQLProvider) UpdateTOTPConfigurationSignIn(ctx context.Context, id int, lastUsedAt *time.Time) (err error) {
-	if _, err = p.db.ExecContext(ctx, p.sqlUpdateTOTPConfigRecordSignIn, lastUsedAt, id); err != nil {
+// UpdateTOTPConfigurationSignIn updates a TOTP configuration sign in information. It returns ErrTOTPStepAlreadyUsed if
+// the step is not more than the last step used with the configuration, which prevents replaying a code.
+func (p *SQLProvider) UpdateTOTPConfigurationSignIn(ctx context.Context, id int, lastUsedAt *time.Time, lastUsedStep uint64) (err error) {
+	var (
+		result   sql.Result
+		affected int64
+	)
+
+	if result, err = p.db.ExecContext(ctx, p.sqlUpdateTOTPConfigRecordSignIn, lastUsedAt, lastUsedStep, id, lastUsedStep); err != nil {
 		return fmt.Errorf("error updating TOTP configuration id %d: %w", id, err)
 	}
 
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error updating TOTP configuration id %d: %w", id, err)
+	}
+
+	if affected == 0 {
+		return ErrTOTPStepAlreadyUsed
+	}
+
 	return nil
 }
 
-// DeleteTOTPConfiguration delete a TOTP configuration from the database given a username.
+// DeleteTOTPConfiguration delete all TOTP configurations from the database given a username.
 func (p *SQLProvider) DeleteTOTPConfiguration(ctx context.Context, username string) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlDeleteTOTPConfig, username); err != nil {
 		return fmt.Errorf("error deleting TOTP configuration for user '%s': %w", username, err)
@@ -749,23 +1072,47 @@ func (p *SQLProvider) DeleteTOTPConfiguration(ctx context.Context, username stri
 	return nil
 }
 
-// LoadTOTPConfiguration load a TOTP configuration given a username from the database.
-func (p *SQLProvider) LoadTOTPConfiguration(ctx context.Context, username string) (config *model.TOTPConfiguration, err error) {
-	config = &model.TOTPConfiguration{}
+// DeleteTOTPConfigurationByID delete a TOTP configuration from the database given a username and the id. It returns
+// ErrNoTOTPConfiguration if the user doesn't have a configuration with the id.
+func (p *SQLProvider) DeleteTOTPConfigurationByID(ctx context.Context, username string, id int) (err error) {
+	var (
+		result   sql.Result
+		affected int64
+	)
 
-	if err = p.db.QueryRowxContext(ctx, p.sqlSelectTOTPConfig, username).StructScan(config); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrNoTOTPConfiguration
-		}
+	if result, err = p.db.ExecContext(ctx, p.sqlDeleteTOTPConfigByID, username, id); err != nil {
+		return fmt.Errorf("error deleting TOTP configuration id %d for user '%s': %w", id, username, err)
+	}
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error deleting TOTP configuration id %d for user '%s': %w", id, username, err)
+	}
+
+	if affected == 0 {
+		return ErrNoTOTPConfiguration
+	}
+
+	return nil
+}
+
+// LoadTOTPConfigurationsByUsername load the TOTP configurations of a given username from the database. It returns
+// ErrNoTOTPConfiguration if the user doesn't have any.
+func (p *SQLProvider) LoadTOTPConfigurationsByUsername(ctx context.Context, username string) (configs []model.TOTPConfiguration, err error) {
+	if err = p.db.SelectContext(ctx, &configs, p.sqlSelectTOTPConfigsByUsername, username); err != nil {
+		return nil, fmt.Errorf("error selecting TOTP configurations for user '%s': %w", username, err)
+	}
 
-		return nil, fmt.Errorf("error selecting TOTP configuration for user '%s': %w", username, err)
+	if len(configs) == 0 {
+		return nil, ErrNoTOTPConfiguration
 	}
 
-	if config.Secret, err = p.decrypt(config.Secret); err != nil {
-		return nil, fmt.Errorf("error decrypting the TOTP secret for user '%s': %w", username, err)
+	for i, c := range configs {
+		if configs[i].Secret, err = p.decrypt(c.Secret); err != nil {
+			return nil, fmt.Errorf("error decrypting the TOTP secret for user '%s': %w", username, err)
+		}
 	}
 
-	return config, nil
+	return configs, nil
 }
 
 // LoadTOTPConfigurations load a set of TOTP configurations.
@@ -789,21 +1136,6 @@ func (p *SQLProvider) LoadTOTPConfigurations(ctx context.Context, limit, page in
 	return configs, nil
 }
 
-func (p *SQLProvider) updateTOTPConfigurationSecret(ctx context.Context, config model.TOTPConfiguration) (err error) {
-	switch config.ID {
-	case 0:
-		_, err = p.db.ExecContext(ctx, p.sqlUpdateTOTPConfigSecretByUsername, config.Secret, config.Username)
-	default:
-		_, err = p.db.ExecContext(ctx, p.sqlUpdateTOTPConfigSecret, config.Secret, config.ID)
-	}
-
-	if err != nil {
-		return fmt.Errorf("error updating TOTP configuration secret for user '%s': %w", config.Username, err)
-	}
-
-	return nil
-}
-
 // SaveWebauthnDevice saves a registered Webauthn device.
 func (p *SQLProvider) SaveWebauthnDevice(ctx context.Context, device model.WebauthnDevice) (err error) {
 	if device.PublicKey, err = p.encrypt(device.PublicKey); err != nil {
@@ -871,21 +1203,6 @@ func (p *SQLProvider) LoadWebauthnDevicesByUsername(ctx context.Context, usernam
 	return devices, nil
 }
 
-func (p *SQLProvider) updateWebauthnDevicePublicKey(ctx context.Context, device model.WebauthnDevice) (err error) {
-	switch device.ID {
-	case 0:
-		_, err = p.db.ExecContext(ctx, p.sqlUpdateWebauthnDevicePublicKeyByUsername, device.PublicKey, device.Username, device.KID)
-	default:
-		_, err = p.db.ExecContext(ctx, p.sqlUpdateWebauthnDevicePublicKey, device.PublicKey, device.ID)
-	}
-
-	if err != nil {
-		return fmt.Errorf("error updating Webauthn public key for user '%s' kid '%x': %w", device.Username, device.KID, err)
-	}
-
-	return nil
-}
-
 // SavePreferredDuoDevice saves a Duo device.
 func (p *SQLProvider) SavePreferredDuoDevice(ctx context.Context, device model.DuoDevice) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlUpsertDuoDevice, device.Username, device.Device, device.Method); err != nil {
@@ -923,7 +1240,7 @@ func (p *SQLProvider) LoadPreferredDuoDevice(ctx context.Context, username strin
 func (p *SQLProvider) AppendAuthenticationLog(ctx context.Context, attempt model.AuthenticationAttempt) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlInsertAuthenticationAttempt,
 		attempt.Time, attempt.Successful, attempt.Banned, attempt.Username,
-		attempt.Type, attempt.RemoteIP, attempt.RequestURI, attempt.RequestMethod); err != nil {
+		attempt.Type, attempt.RemoteIP, attempt.RequestURI, attempt.RequestMethod, attempt.UserAgent); err != nil {
 		return fmt.Errorf("error inserting authentication attempt for user '%s': %w", attempt.Username, err)
 	}
 
@@ -944,3 +1261,309 @@ func (p *SQLProvider) LoadAuthenticationLogs(ctx context.Context, username strin
 
 	return attempts, nil
 }
+
+// LoadUserAuthenticationLogs retrieve the latest authentication attempts of all types for a user from the
+// authentication log.
+func (p *SQLProvider) LoadUserAuthenticationLogs(ctx context.Context, username string, limit, page int) (attempts []model.AuthenticationAttempt, err error) {
+	attempts = make([]model.AuthenticationAttempt, 0, limit)
+
+	if err = p.db.SelectContext(ctx, &attempts, p.sqlSelectAuthenticationLogsByUsername, username, limit, limit*page); err != nil {
+		return nil, fmt.Errorf("error selecting authentication logs for user '%s': %w", username, err)
+	}
+
+	return attempts, nil
+}
+
+// CountSecondFactorFailedAuthenticationLogs returns the number of failed second factor authentication attempts for a
+// user since the provided time.
+func (p *SQLProvider) CountSecondFactorFailedAuthenticationLogs(ctx context.Context, username string, since time.Time) (count int, err error) {
+	if err = p.db.GetContext(ctx, &count, p.sqlSelectSecondFactorFailedLogsCount, since, username); err != nil {
+		return 0, fmt.Errorf("error counting failed second factor authentication logs for user '%s': %w", username, err)
+	}
+
+	return count, nil
+}
+
+// DeleteAuthenticationLogs deletes the authentication attempts older than the provided time from the authentication
+// log.
+func (p *SQLProvider) DeleteAuthenticationLogs(ctx context.Context, before time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteAuthenticationLogsBefore, before); err != nil {
+		return fmt.Errorf("error deleting authentication logs before '%s': %w", before.Format(time.RFC3339), err)
+	}
+
+	return nil
+}
+
+// SaveUserLoginLocation saves the most recent login location of a user.
+func (p *SQLProvider) SaveUserLoginLocation(ctx context.Context, location model.UserLoginLocation) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpsertUserLoginLocation,
+		location.Time, location.Username, location.RemoteIP, location.Latitude, location.Longitude); err != nil {
+		return fmt.Errorf("error upserting login location for user '%s': %w", location.Username, err)
+	}
+
+	return nil
+}
+
+// LoadUserLoginLocation loads the most recent login location of a user.
+func (p *SQLProvider) LoadUserLoginLocation(ctx context.Context, username string) (location *model.UserLoginLocation, err error) {
+	location = &model.UserLoginLocation{}
+
+	if err = p.db.GetContext(ctx, location, p.sqlSelectUserLoginLocation, username); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("error selecting login location for user '%s': %w", username, err)
+		}
+	}
+
+	return location, nil
+}
+
+// SaveRegulationUnlock saves a regulation unlock, replacing any existing regulation unlock of the user.
+func (p *SQLProvider) SaveRegulationUnlock(ctx context.Context, unlock model.RegulationUnlock) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpsertRegulationUnlock,
+		unlock.CreatedAt, unlock.ExpiresAt, unlock.Username, unlock.TokenHash); err != nil {
+		return fmt.Errorf("error upserting regulation unlock for user '%s': %w", unlock.Username, err)
+	}
+
+	return nil
+}
+
+// LoadRegulationUnlock loads the regulation unlock of a user.
+func (p *SQLProvider) LoadRegulationUnlock(ctx context.Context, username string) (unlock *model.RegulationUnlock, err error) {
+	unlock = &model.RegulationUnlock{}
+
+	if err = p.db.GetContext(ctx, unlock, p.sqlSelectRegulationUnlock, username); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("error selecting regulation unlock for user '%s': %w", username, err)
+		}
+	}
+
+	return unlock, nil
+}
+
+// ConsumeRegulationUnlock marks the regulation unlock of a user as used. It returns an error if the regulation unlock
+// doesn't exist or was already used, which ensures each token can only be used once.
+func (p *SQLProvider) ConsumeRegulationUnlock(ctx context.Context, username string, unlockedAt time.Time) (err error) {
+	return p.consumeUserToken(ctx, p.sqlUpdateRegulationUnlockUnlocked, "regulation unlock", "the regulation unlock was already used", username, unlockedAt)
+}
+
+// SaveLoginFingerprint saves a new login fingerprint.
+func (p *SQLProvider) SaveLoginFingerprint(ctx context.Context, fingerprint model.LoginFingerprint) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertLoginFingerprint,
+		fingerprint.CreatedAt, fingerprint.LastSeenAt, fingerprint.NotifiedAt, fingerprint.Username,
+		fingerprint.Device, fingerprint.Network, fingerprint.Country, fingerprint.SuppressTokenHash); err != nil {
+		return fmt.Errorf("error inserting login fingerprint for user '%s': %w", fingerprint.Username, err)
+	}
+
+	return nil
+}
+
+// LoadLoginFingerprints loads all of the login fingerprints of a user.
+func (p *SQLProvider) LoadLoginFingerprints(ctx context.Context, username string) (fingerprints []model.LoginFingerprint, err error) {
+	if err = p.db.SelectContext(ctx, &fingerprints, p.sqlSelectLoginFingerprints, username); err != nil {
+		return nil, fmt.Errorf("error selecting login fingerprints for user '%s': %w", username, err)
+	}
+
+	return fingerprints, nil
+}
+
+// UpdateLoginFingerprintSeen updates the time a login fingerprint was last seen.
+func (p *SQLProvider) UpdateLoginFingerprintSeen(ctx context.Context, id int, seenAt time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateLoginFingerprintSeen, seenAt, id); err != nil {
+		return fmt.Errorf("error updating login fingerprint with id %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// SuppressLoginFingerprint suppresses future login notifications for the device of a login fingerprint of a user.
+func (p *SQLProvider) SuppressLoginFingerprint(ctx context.Context, username string, id int) (err error) {
+	var (
+		result   sql.Result
+		affected int64
+	)
+
+	if result, err = p.db.ExecContext(ctx, p.sqlUpdateLoginFingerprintSuppressed, id, username); err != nil {
+		return fmt.Errorf("error updating login fingerprint with id %d for user '%s': %w", id, username, err)
+	}
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error updating login fingerprint with id %d for user '%s': %w", id, username, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("error updating login fingerprint with id %d for user '%s': the login fingerprint doesn't exist", id, username)
+	}
+
+	return nil
+}
+
+// DeleteLoginFingerprints deletes the login fingerprints of a user which were last seen before the provided time
+// unless they have been suppressed.
+func (p *SQLProvider) DeleteLoginFingerprints(ctx context.Context, username string, before time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteLoginFingerprintsBefore, username, before); err != nil {
+		return fmt.Errorf("error deleting login fingerprints for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// IncrementRegulationUnlockAttempts increments the number of attempts to use the regulation unlock of a user.
+func (p *SQLProvider) IncrementRegulationUnlockAttempts(ctx context.Context, username string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateRegulationUnlockAttempts, username); err != nil {
+		return fmt.Errorf("error updating regulation unlock attempts for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// SaveUserRegistration saves a pending user registration.
+func (p *SQLProvider) SaveUserRegistration(ctx context.Context, registration model.UserRegistration) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertUserRegistration,
+		registration.RequestedAt, registration.RemoteIP, registration.Username, registration.DisplayName,
+		registration.Email, registration.Password); err != nil {
+		return fmt.Errorf("error inserting registration for user '%s': %w", registration.Username, err)
+	}
+
+	return nil
+}
+
+// LoadUserRegistration loads a pending user registration.
+func (p *SQLProvider) LoadUserRegistration(ctx context.Context, username string) (registration *model.UserRegistration, err error) {
+	registration = &model.UserRegistration{}
+
+	if err = p.db.GetContext(ctx, registration, p.sqlSelectUserRegistration, username); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("error selecting registration for user '%s': %w", username, err)
+		}
+	}
+
+	return registration, nil
+}
+
+// LoadUserRegistrations loads all pending user registrations ordered by the time they were requested.
+func (p *SQLProvider) LoadUserRegistrations(ctx context.Context) (registrations []model.UserRegistration, err error) {
+	registrations = make([]model.UserRegistration, 0)
+
+	if err = p.db.SelectContext(ctx, &registrations, p.sqlSelectUserRegistrations); err != nil {
+		return nil, fmt.Errorf("error selecting registrations: %w", err)
+	}
+
+	return registrations, nil
+}
+
+// DeleteUserRegistration deletes a pending user registration.
+func (p *SQLProvider) DeleteUserRegistration(ctx context.Context, username string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteUserRegistration, username); err != nil {
+		return fmt.Errorf("error deleting registration for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// SavePasswordResetCode saves a password reset code issued by an administrator, replacing any existing code of the user.
+func (p *SQLProvider) SavePasswordResetCode(ctx context.Context, code model.PasswordResetCode) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpsertPasswordResetCode,
+		code.CreatedAt, code.ExpiresAt, code.Username, code.CodeHash); err != nil {
+		return fmt.Errorf("error upserting password reset code for user '%s': %w", code.Username, err)
+	}
+
+	return nil
+}
+
+// LoadPasswordResetCode loads the password reset code of a user.
+func (p *SQLProvider) LoadPasswordResetCode(ctx context.Context, username string) (code *model.PasswordResetCode, err error) {
+	code = &model.PasswordResetCode{}
+
+	if err = p.db.GetContext(ctx, code, p.sqlSelectPasswordResetCode, username); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("error selecting password reset code for user '%s': %w", username, err)
+		}
+	}
+
+	return code, nil
+}
+
+// DeletePasswordResetCode deletes the password reset code of a user.
+func (p *SQLProvider) DeletePasswordResetCode(ctx context.Context, username string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeletePasswordResetCode, username); err != nil {
+		return fmt.Errorf("error deleting password reset code for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// SaveEmailVerification saves an unverified email verification, replacing any existing email verification of the user.
+func (p *SQLProvider) SaveEmailVerification(ctx context.Context, verification model.EmailVerification) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpsertEmailVerification,
+		verification.CreatedAt, verification.ExpiresAt, verification.Username, verification.Email, verification.TokenHash); err != nil {
+		return fmt.Errorf("error upserting email verification for user '%s': %w", verification.Username, err)
+	}
+
+	return nil
+}
+
+// LoadEmailVerification loads the email verification of a user.
+func (p *SQLProvider) LoadEmailVerification(ctx context.Context, username string) (verification *model.EmailVerification, err error) {
+	verification = &model.EmailVerification{}
+
+	if err = p.db.GetContext(ctx, verification, p.sqlSelectEmailVerification, username); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("error selecting email verification for user '%s': %w", username, err)
+		}
+	}
+
+	return verification, nil
+}
+
+// ConsumeEmailVerification marks the email verification of a user as verified. It returns an error if the email
+// verification doesn't exist or was already verified, which ensures each token can only be used once.
+func (p *SQLProvider) ConsumeEmailVerification(ctx context.Context, username string, verifiedAt time.Time) (err error) {
+	return p.consumeUserToken(ctx, p.sqlUpdateEmailVerificationVerified, "email verification", "the email address is already verified", username, verifiedAt)
+}
+
+// DeleteEmailVerification deletes the email verification of a user.
+func (p *SQLProvider) DeleteEmailVerification(ctx context.Context, username string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteEmailVerification, username); err != nil {
+		return fmt.Errorf("error deleting email verification for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// consumeUserToken executes the query which marks the single use token of a user as used. The reason is returned as
+// an error if no row was updated, i.e. the token doesn't exist or was already used.
+func (p *SQLProvider) consumeUserToken(ctx context.Context, query, name, reason, username string, usedAt time.Time) (err error) {
+	var (
+		result   sql.Result
+		affected int64
+	)
+
+	if result, err = p.db.ExecContext(ctx, query, usedAt, username); err != nil {
+		return fmt.Errorf("error updating %s for user '%s': %w", name, username, err)
+	}
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error updating %s for user '%s': %w", name, username, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("error updating %s for user '%s': %s", name, username, reason)
+	}
+
+	return nil
+}
@@ -26,6 +26,9 @@ func NewMySQLProvider(config *schema.Configuration) (provider *MySQLProvider) {
 	// Specific alterations to this provider.
 	provider.sqlFmtRenameTable = queryFmtMySQLRenameTable
 
+	provider.sqlSelectCleanupLock = queryMySQLSelectCleanupLock
+	provider.sqlSelectCleanupUnlock = queryMySQLSelectCleanupUnlock
+
 	return provider
 }
 
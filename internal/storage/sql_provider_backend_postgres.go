@@ -32,6 +32,15 @@ func NewPostgreSQLProvider(config *schema.Configuration) (provider *PostgreSQLPr
 	provider.sqlUpsertPreferred2FAMethod = fmt.Sprintf(queryFmtUpsertPreferred2FAMethodPostgreSQL, tableUserPreferences)
 	provider.sqlUpsertEncryptionValue = fmt.Sprintf(queryFmtUpsertEncryptionValuePostgreSQL, tableEncryption)
 	provider.sqlUpsertOAuth2BlacklistedJTI = fmt.Sprintf(queryFmtUpsertOAuth2BlacklistedJTIPostgreSQL, tableOAuth2BlacklistedJTI)
+	provider.sqlUpsertUserLoginLocation = fmt.Sprintf(queryFmtUpsertUserLoginLocationPostgreSQL, tableUserLoginLocation)
+	provider.sqlUpsertPasswordResetCode = fmt.Sprintf(queryFmtUpsertPasswordResetCodePostgreSQL, tablePasswordResetCode)
+	provider.sqlUpsertUserPasswordChange = fmt.Sprintf(queryFmtUpsertUserPasswordChangePostgreSQL, tableUserPasswordChange)
+	provider.sqlUpsertSession = fmt.Sprintf(queryFmtUpsertSessionPostgreSQL, tableSessions)
+	provider.sqlUpsertEmailVerification = fmt.Sprintf(queryFmtUpsertEmailVerificationPostgreSQL, tableEmailVerification)
+	provider.sqlUpsertRegulationUnlock = fmt.Sprintf(queryFmtUpsertRegulationUnlockPostgreSQL, tableRegulationUnlock)
+
+	provider.sqlSelectCleanupLock = queryPostgreSelectCleanupLock
+	provider.sqlSelectCleanupUnlock = queryPostgreSelectCleanupUnlock
 
 	// PostgreSQL requires rebinding of any query that contains a '?' placeholder to use the '$#' notation placeholders.
 	provider.sqlFmtRenameTable = provider.db.Rebind(provider.sqlFmtRenameTable)
@@ -43,11 +52,25 @@ func NewPostgreSQLProvider(config *schema.Configuration) (provider *PostgreSQLPr
 	provider.sqlSelectUserOpaqueIdentifier = provider.db.Rebind(provider.sqlSelectUserOpaqueIdentifier)
 	provider.sqlSelectUserOpaqueIdentifierBySignature = provider.db.Rebind(provider.sqlSelectUserOpaqueIdentifierBySignature)
 
+	provider.sqlSelectPasswordResetCode = provider.db.Rebind(provider.sqlSelectPasswordResetCode)
+	provider.sqlDeletePasswordResetCode = provider.db.Rebind(provider.sqlDeletePasswordResetCode)
+
+	provider.sqlSelectEmailVerification = provider.db.Rebind(provider.sqlSelectEmailVerification)
+	provider.sqlUpdateEmailVerificationVerified = provider.db.Rebind(provider.sqlUpdateEmailVerificationVerified)
+	provider.sqlDeleteEmailVerification = provider.db.Rebind(provider.sqlDeleteEmailVerification)
+
+	provider.sqlSelectSession = provider.db.Rebind(provider.sqlSelectSession)
+	provider.sqlSelectSessionsCount = provider.db.Rebind(provider.sqlSelectSessionsCount)
+	provider.sqlUpdateSessionID = provider.db.Rebind(provider.sqlUpdateSessionID)
+	provider.sqlDeleteSession = provider.db.Rebind(provider.sqlDeleteSession)
+	provider.sqlDeleteSessionsExpired = provider.db.Rebind(provider.sqlDeleteSessionsExpired)
+
 	provider.sqlSelectIdentityVerification = provider.db.Rebind(provider.sqlSelectIdentityVerification)
 	provider.sqlInsertIdentityVerification = provider.db.Rebind(provider.sqlInsertIdentityVerification)
 	provider.sqlConsumeIdentityVerification = provider.db.Rebind(provider.sqlConsumeIdentityVerification)
 
-	provider.sqlSelectTOTPConfig = provider.db.Rebind(provider.sqlSelectTOTPConfig)
+	provider.sqlSelectTOTPConfigsByUsername = provider.db.Rebind(provider.sqlSelectTOTPConfigsByUsername)
+	provider.sqlDeleteTOTPConfigByID = provider.db.Rebind(provider.sqlDeleteTOTPConfigByID)
 	provider.sqlUpdateTOTPConfigRecordSignIn = provider.db.Rebind(provider.sqlUpdateTOTPConfigRecordSignIn)
 	provider.sqlUpdateTOTPConfigRecordSignInByUsername = provider.db.Rebind(provider.sqlUpdateTOTPConfigRecordSignInByUsername)
 	provider.sqlDeleteTOTPConfig = provider.db.Rebind(provider.sqlDeleteTOTPConfig)
@@ -67,6 +90,30 @@ func NewPostgreSQLProvider(config *schema.Configuration) (provider *PostgreSQLPr
 
 	provider.sqlInsertAuthenticationAttempt = provider.db.Rebind(provider.sqlInsertAuthenticationAttempt)
 	provider.sqlSelectAuthenticationAttemptsByUsername = provider.db.Rebind(provider.sqlSelectAuthenticationAttemptsByUsername)
+	provider.sqlSelectAuthenticationLogsByUsername = provider.db.Rebind(provider.sqlSelectAuthenticationLogsByUsername)
+	provider.sqlSelectSecondFactorFailedLogsCount = provider.db.Rebind(provider.sqlSelectSecondFactorFailedLogsCount)
+	provider.sqlDeleteAuthenticationLogsBefore = provider.db.Rebind(provider.sqlDeleteAuthenticationLogsBefore)
+
+	provider.sqlSelectUserLoginLocation = provider.db.Rebind(provider.sqlSelectUserLoginLocation)
+
+	provider.sqlInsertUserFirstLogin = provider.db.Rebind(provider.sqlInsertUserFirstLogin)
+	provider.sqlSelectUserFirstLogin = provider.db.Rebind(provider.sqlSelectUserFirstLogin)
+
+	provider.sqlSelectUserPasswordChange = provider.db.Rebind(provider.sqlSelectUserPasswordChange)
+
+	provider.sqlSelectRegulationUnlock = provider.db.Rebind(provider.sqlSelectRegulationUnlock)
+	provider.sqlUpdateRegulationUnlockUnlocked = provider.db.Rebind(provider.sqlUpdateRegulationUnlockUnlocked)
+	provider.sqlUpdateRegulationUnlockAttempts = provider.db.Rebind(provider.sqlUpdateRegulationUnlockAttempts)
+
+	provider.sqlSelectLoginFingerprints = provider.db.Rebind(provider.sqlSelectLoginFingerprints)
+	provider.sqlInsertLoginFingerprint = provider.db.Rebind(provider.sqlInsertLoginFingerprint)
+	provider.sqlUpdateLoginFingerprintSeen = provider.db.Rebind(provider.sqlUpdateLoginFingerprintSeen)
+	provider.sqlUpdateLoginFingerprintSuppressed = provider.db.Rebind(provider.sqlUpdateLoginFingerprintSuppressed)
+	provider.sqlDeleteLoginFingerprintsBefore = provider.db.Rebind(provider.sqlDeleteLoginFingerprintsBefore)
+
+	provider.sqlInsertUserRegistration = provider.db.Rebind(provider.sqlInsertUserRegistration)
+	provider.sqlSelectUserRegistration = provider.db.Rebind(provider.sqlSelectUserRegistration)
+	provider.sqlDeleteUserRegistration = provider.db.Rebind(provider.sqlDeleteUserRegistration)
 
 	provider.sqlInsertMigration = provider.db.Rebind(provider.sqlInsertMigration)
 	provider.sqlSelectMigrations = provider.db.Rebind(provider.sqlSelectMigrations)
@@ -79,6 +126,7 @@ func NewPostgreSQLProvider(config *schema.Configuration) (provider *PostgreSQLPr
 	provider.sqlUpdateOAuth2ConsentSessionGranted = provider.db.Rebind(provider.sqlUpdateOAuth2ConsentSessionGranted)
 	provider.sqlSelectOAuth2ConsentSessionByChallengeID = provider.db.Rebind(provider.sqlSelectOAuth2ConsentSessionByChallengeID)
 	provider.sqlSelectOAuth2ConsentSessionsPreConfigured = provider.db.Rebind(provider.sqlSelectOAuth2ConsentSessionsPreConfigured)
+	provider.sqlSelectOAuth2ConsentSessionsActive = provider.db.Rebind(provider.sqlSelectOAuth2ConsentSessionsActive)
 
 	provider.sqlInsertOAuth2AuthorizeCodeSession = provider.db.Rebind(provider.sqlInsertOAuth2AuthorizeCodeSession)
 	provider.sqlRevokeOAuth2AuthorizeCodeSession = provider.db.Rebind(provider.sqlRevokeOAuth2AuthorizeCodeSession)
@@ -100,6 +148,8 @@ func NewPostgreSQLProvider(config *schema.Configuration) (provider *PostgreSQLPr
 	provider.sqlDeactivateOAuth2RefreshTokenSession = provider.db.Rebind(provider.sqlDeactivateOAuth2RefreshTokenSession)
 	provider.sqlDeactivateOAuth2RefreshTokenSessionByRequestID = provider.db.Rebind(provider.sqlDeactivateOAuth2RefreshTokenSessionByRequestID)
 	provider.sqlSelectOAuth2RefreshTokenSession = provider.db.Rebind(provider.sqlSelectOAuth2RefreshTokenSession)
+	provider.sqlUpdateOAuth2RefreshTokenSessionLastUsed = provider.db.Rebind(provider.sqlUpdateOAuth2RefreshTokenSessionLastUsed)
+	provider.sqlSelectOAuth2RefreshTokenSessionsByUsername = provider.db.Rebind(provider.sqlSelectOAuth2RefreshTokenSessionsByUsername)
 
 	provider.sqlInsertOAuth2PKCERequestSession = provider.db.Rebind(provider.sqlInsertOAuth2PKCERequestSession)
 	provider.sqlRevokeOAuth2PKCERequestSession = provider.db.Rebind(provider.sqlRevokeOAuth2PKCERequestSession)
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DeleteExpiredRows deletes the expired rows of the tables which have an expiration in batches of the provided size
+// and returns the number of deleted rows keyed by table. The OAuth 2.0 sessions are only deleted when they're revoked
+// or inactive and were requested before oauth2Before as the expiration of active sessions is encrypted, a zero
+// oauth2Before skips them. The ErrCleanupLocked error is returned when another instance holds the cleanup lock.
+func (p *SQLProvider) DeleteExpiredRows(ctx context.Context, now, oauth2Before time.Time, batchSize int) (deleted map[string]int64, err error) {
+	if p.sqlSelectCleanupLock != "" {
+		var conn *sqlx.Conn
+
+		if conn, err = p.db.Connx(ctx); err != nil {
+			return nil, fmt.Errorf("error acquiring a connection for the cleanup lock: %w", err)
+		}
+
+		defer conn.Close()
+
+		var locked bool
+
+		if err = conn.GetContext(ctx, &locked, p.sqlSelectCleanupLock); err != nil {
+			return nil, fmt.Errorf("error acquiring the cleanup lock: %w", err)
+		}
+
+		if !locked {
+			return nil, ErrCleanupLocked
+		}
+
+		defer func() {
+			var released bool
+
+			if err := conn.GetContext(context.Background(), &released, p.sqlSelectCleanupUnlock); err != nil {
+				p.log.Errorf("Error occurred releasing the cleanup lock: %v", err)
+			}
+		}()
+	}
+
+	deleted = map[string]int64{}
+
+	for _, expired := range expiredRows {
+		before := now
+
+		if expired.OAuth2 {
+			if oauth2Before.IsZero() {
+				continue
+			}
+
+			before = oauth2Before
+		}
+
+		if deleted[expired.Table], err = p.deleteExpiredRowsTable(ctx, expired, before, batchSize); err != nil {
+			return deleted, err
+		}
+	}
+
+	return deleted, nil
+}
+
+func (p *SQLProvider) deleteExpiredRowsTable(ctx context.Context, expired expiredRowsTable, before time.Time, batchSize int) (deleted int64, err error) {
+	query := p.db.Rebind(fmt.Sprintf(queryFmtDeleteExpiredRows, expired.Table, expired.Table, expired.Condition))
+
+	for {
+		var (
+			result sql.Result
+			n      int64
+		)
+
+		if result, err = p.db.ExecContext(ctx, query, before, batchSize); err != nil {
+			return deleted, fmt.Errorf("error deleting expired rows from table '%s': %w", expired.Table, err)
+		}
+
+		if n, err = result.RowsAffected(); err != nil {
+			return deleted, fmt.Errorf("error counting the deleted expired rows from table '%s': %w", expired.Table, err)
+		}
+
+		deleted += n
+
+		if n < int64(batchSize) {
+			return deleted, nil
+		}
+	}
+}
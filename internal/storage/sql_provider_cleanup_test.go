@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestShouldDeleteExpiredRowsInBatches(t *testing.T) {
+	provider := newTestEncryptionSQLiteProvider(t, filepath.Join(t.TempDir(), "db.sqlite3"), testEncryptionKey)
+
+	require.NoError(t, provider.StartupCheck())
+
+	ctx := context.Background()
+	now := time.Now()
+
+	expired, valid := now.Add(-time.Minute), now.Add(time.Hour)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, provider.SaveSession(ctx, model.Session{ID: fmt.Sprintf("expired%d", i), ExpiresAt: &expired, Data: []byte("data")}))
+	}
+
+	require.NoError(t, provider.SaveSession(ctx, model.Session{ID: "valid", ExpiresAt: &valid, Data: []byte("data")}))
+	require.NoError(t, provider.SaveSession(ctx, model.Session{ID: "persistent", Data: []byte("data")}))
+
+	require.NoError(t, provider.SavePasswordResetCode(ctx, model.PasswordResetCode{CreatedAt: now, ExpiresAt: expired, Username: "john", CodeHash: "hash"}))
+	require.NoError(t, provider.SavePasswordResetCode(ctx, model.PasswordResetCode{CreatedAt: now, ExpiresAt: valid, Username: "harry", CodeHash: "hash"}))
+
+	deleted, err := provider.DeleteExpiredRows(ctx, now, now.Add(-time.Hour), 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(5), deleted[tableSessions])
+	assert.Equal(t, int64(1), deleted[tablePasswordResetCode])
+	assert.Equal(t, int64(0), deleted[tableOAuth2AccessTokenSession])
+
+	count, err := provider.CountSessions(ctx, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	code, err := provider.LoadPasswordResetCode(ctx, "harry")
+	require.NoError(t, err)
+	assert.NotNil(t, code)
+
+	deleted, err = provider.DeleteExpiredRows(ctx, now, time.Time{}, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(0), deleted[tableSessions])
+
+	_, ok := deleted[tableOAuth2AccessTokenSession]
+	assert.False(t, ok)
+}
@@ -8,113 +8,131 @@ import (
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 
-	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // SchemaEncryptionChangeKey uses the currently configured key to decrypt values in the database and the key provided
-// by this command to encrypt the values again and update them using a transaction.
-func (p *SQLProvider) SchemaEncryptionChangeKey(ctx context.Context, encryptionKey string) (err error) {
-	tx, err := p.db.Beginx()
+// by this command to encrypt the values again and update them using a transaction. Values which are already encrypted
+// with the new key are left as is so a change which was interrupted can safely be run again. The number of rows of each
+// table is verified before the transaction is committed, and the transaction is rolled back instead when dryRun is
+// true. The progress function is called after each batch of rows is processed if it's not nil.
+func (p *SQLProvider) SchemaEncryptionChangeKey(ctx context.Context, encryptionKey string, dryRun bool, progress func(result EncryptionChangeKeyResult)) (err error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("error beginning transaction to change encryption key: %w", err)
 	}
 
 	key := sha256.Sum256([]byte(encryptionKey))
 
-	if err = p.schemaEncryptionChangeKeyTOTP(ctx, tx, key); err != nil {
-		return err
+	for _, column := range encryptedColumns {
+		if err = p.schemaEncryptionChangeKeyColumn(ctx, tx, column, key, progress); err != nil {
+			return p.rollbackWithError(tx, err)
+		}
 	}
 
-	if err = p.schemaEncryptionChangeKeyWebauthn(ctx, tx, key); err != nil {
-		return err
+	if err = p.schemaEncryptionChangeKeyCheckValue(ctx, tx, key); err != nil {
+		return p.rollbackWithError(tx, err)
 	}
 
-	if err = p.setNewEncryptionCheckValue(ctx, &key, tx); err != nil {
-		if rollbackErr := tx.Rollback(); rollbackErr != nil {
-			return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
-		}
-
-		return fmt.Errorf("rollback due to error: %w", err)
+	if dryRun {
+		return tx.Rollback()
 	}
 
 	return tx.Commit()
 }
 
-func (p *SQLProvider) schemaEncryptionChangeKeyTOTP(ctx context.Context, tx *sqlx.Tx, key [32]byte) (err error) {
-	var configs []model.TOTPConfiguration
+func (p *SQLProvider) schemaEncryptionChangeKeyColumn(ctx context.Context, tx *sqlx.Tx, column encryptedColumn, key [32]byte, progress func(result EncryptionChangeKeyResult)) (err error) {
+	var (
+		values []encryptedValue
+		value  []byte
+		total  int
+	)
 
-	for page := 0; true; page++ {
-		if configs, err = p.LoadTOTPConfigurations(ctx, 10, page); err != nil {
-			if rollbackErr := tx.Rollback(); rollbackErr != nil {
-				return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
-			}
+	queryCount := p.db.Rebind(fmt.Sprintf(queryFmtSelectRowCount, column.Table))
+	querySelect := p.db.Rebind(fmt.Sprintf(queryFmtSelectEncryptedValues, column.Column, column.Table))
+	queryUpdate := p.db.Rebind(fmt.Sprintf(queryFmtUpdateEncryptedValue, column.Table, column.Column))
+
+	if err = tx.GetContext(ctx, &total, queryCount); err != nil {
+		return fmt.Errorf("error counting the rows of table '%s': %w", column.Table, err)
+	}
 
-			return fmt.Errorf("rollback due to error: %w", err)
+	result := EncryptionChangeKeyResult{Table: column.Table, Column: column.Column, Total: total}
+
+	for lastID := 0; true; lastID = values[len(values)-1].ID {
+		values = nil
+
+		if err = tx.SelectContext(ctx, &values, querySelect, lastID, encryptionBatchSize); err != nil {
+			return fmt.Errorf("error selecting the encrypted values of table '%s': %w", column.Table, err)
 		}
 
-		for _, config := range configs {
-			if config.Secret, err = utils.Encrypt(config.Secret, &key); err != nil {
-				if rollbackErr := tx.Rollback(); rollbackErr != nil {
-					return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
+		for _, v := range values {
+			if value, err = p.decrypt(v.Value); err != nil {
+				// The value is skipped if it was already encrypted with the new key by an earlier change.
+				if _, err = utils.Decrypt(v.Value, &key); err != nil {
+					return fmt.Errorf("error decrypting the value of column '%s' of table '%s' with id %d with either key: %w", column.Column, column.Table, v.ID, err)
 				}
 
-				return fmt.Errorf("rollback due to error: %w", err)
+				result.Skipped++
+
+				continue
 			}
 
-			if err = p.updateTOTPConfigurationSecret(ctx, config); err != nil {
-				if rollbackErr := tx.Rollback(); rollbackErr != nil {
-					return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
-				}
+			if value, err = utils.Encrypt(value, &key); err != nil {
+				return fmt.Errorf("error encrypting the value of column '%s' of table '%s' with id %d: %w", column.Column, column.Table, v.ID, err)
+			}
 
-				return fmt.Errorf("rollback due to error: %w", err)
+			if _, err = tx.ExecContext(ctx, queryUpdate, value, v.ID); err != nil {
+				return fmt.Errorf("error updating the value of column '%s' of table '%s' with id %d: %w", column.Column, column.Table, v.ID, err)
 			}
+
+			result.Changed++
+		}
+
+		if progress != nil {
+			progress(result)
 		}
 
-		if len(configs) != 10 {
+		if len(values) < encryptionBatchSize {
 			break
 		}
 	}
 
+	if err = tx.GetContext(ctx, &total, queryCount); err != nil {
+		return fmt.Errorf("error counting the rows of table '%s': %w", column.Table, err)
+	}
+
+	if processed := result.Changed + result.Skipped; processed != result.Total || total != result.Total {
+		return fmt.Errorf("error verifying the rows of table '%s': %d rows were processed but the table had %d rows before and %d rows after the change", column.Table, processed, result.Total, total)
+	}
+
 	return nil
 }
 
-func (p *SQLProvider) schemaEncryptionChangeKeyWebauthn(ctx context.Context, tx *sqlx.Tx, key [32]byte) (err error) {
-	var devices []model.WebauthnDevice
+// schemaEncryptionChangeKeyCheckValue replaces the check value unless it's already encrypted with the new key.
+func (p *SQLProvider) schemaEncryptionChangeKeyCheckValue(ctx context.Context, tx *sqlx.Tx, key [32]byte) (err error) {
+	var value []byte
 
-	for page := 0; true; page++ {
-		if devices, err = p.LoadWebauthnDevices(ctx, 10, page); err != nil {
-			if rollbackErr := tx.Rollback(); rollbackErr != nil {
-				return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
-			}
+	if err = tx.GetContext(ctx, &value, p.sqlSelectEncryptionValue, encryptionNameCheck); err != nil {
+		return fmt.Errorf("error selecting the encryption check value: %w", err)
+	}
 
-			return fmt.Errorf("rollback due to error: %w", err)
+	if _, err = p.decrypt(value); err != nil {
+		if _, err = utils.Decrypt(value, &key); err == nil {
+			return nil
 		}
 
-		for _, device := range devices {
-			if device.PublicKey, err = utils.Encrypt(device.PublicKey, &key); err != nil {
-				if rollbackErr := tx.Rollback(); rollbackErr != nil {
-					return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
-				}
-
-				return fmt.Errorf("rollback due to error: %w", err)
-			}
-
-			if err = p.updateWebauthnDevicePublicKey(ctx, device); err != nil {
-				if rollbackErr := tx.Rollback(); rollbackErr != nil {
-					return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
-				}
+		return ErrSchemaEncryptionInvalidKey
+	}
 
-				return fmt.Errorf("rollback due to error: %w", err)
-			}
-		}
+	return p.setNewEncryptionCheckValue(ctx, &key, tx)
+}
 
-		if len(devices) != 10 {
-			break
-		}
+func (p *SQLProvider) rollbackWithError(tx *sqlx.Tx, err error) error {
+	if rollbackErr := tx.Rollback(); rollbackErr != nil {
+		return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
 	}
 
-	return nil
+	return fmt.Errorf("rollback due to error: %w", err)
 }
 
 // SchemaEncryptionCheckKey checks the encryption key configured is valid for the database.
@@ -135,12 +153,10 @@ func (p *SQLProvider) SchemaEncryptionCheckKey(ctx context.Context, verbose bool
 	}
 
 	if verbose {
-		if err = p.schemaEncryptionCheckTOTP(ctx); err != nil {
-			errs = append(errs, err)
-		}
-
-		if err = p.schemaEncryptionCheckU2F(ctx); err != nil {
-			errs = append(errs, err)
+		for _, column := range encryptedColumns {
+			if err = p.schemaEncryptionCheckColumn(ctx, column); err != nil {
+				errs = append(errs, err)
+			}
 		}
 	}
 
@@ -161,99 +177,37 @@ func (p *SQLProvider) SchemaEncryptionCheckKey(ctx context.Context, verbose bool
 	return nil
 }
 
-func (p *SQLProvider) schemaEncryptionCheckTOTP(ctx context.Context) (err error) {
+func (p *SQLProvider) schemaEncryptionCheckColumn(ctx context.Context, column encryptedColumn) (err error) {
 	var (
-		config  model.TOTPConfiguration
-		row     int
+		values  []encryptedValue
 		invalid int
 		total   int
 	)
 
-	pageSize := 10
-
-	var rows *sqlx.Rows
+	query := p.db.Rebind(fmt.Sprintf(queryFmtSelectEncryptedValues, column.Column, column.Table))
 
-	for page := 0; true; page++ {
-		if rows, err = p.db.QueryxContext(ctx, p.sqlSelectTOTPConfigs, pageSize, pageSize*page); err != nil {
-			_ = rows.Close()
+	for lastID := 0; true; lastID = values[len(values)-1].ID {
+		values = nil
 
-			return fmt.Errorf("error selecting TOTP configurations: %w", err)
+		if err = p.db.SelectContext(ctx, &values, query, lastID, encryptionBatchSize); err != nil {
+			return fmt.Errorf("error selecting the encrypted values of table '%s': %w", column.Table, err)
 		}
 
-		row = 0
-
-		for rows.Next() {
+		for _, v := range values {
 			total++
-			row++
-
-			if err = rows.StructScan(&config); err != nil {
-				_ = rows.Close()
-				return fmt.Errorf("error scanning TOTP configuration to struct: %w", err)
-			}
 
-			if _, err = p.decrypt(config.Secret); err != nil {
+			if _, err = p.decrypt(v.Value); err != nil {
 				invalid++
 			}
 		}
 
-		_ = rows.Close()
-
-		if row < pageSize {
-			break
-		}
-	}
-
-	if invalid != 0 {
-		return fmt.Errorf("%d of %d total TOTP secrets were invalid", invalid, total)
-	}
-
-	return nil
-}
-
-func (p *SQLProvider) schemaEncryptionCheckU2F(ctx context.Context) (err error) {
-	var (
-		device  model.U2FDevice
-		row     int
-		invalid int
-		total   int
-	)
-
-	pageSize := 10
-
-	var rows *sqlx.Rows
-
-	for page := 0; true; page++ {
-		if rows, err = p.db.QueryxContext(ctx, p.sqlSelectWebauthnDevices, pageSize, pageSize*page); err != nil {
-			_ = rows.Close()
-
-			return fmt.Errorf("error selecting U2F devices: %w", err)
-		}
-
-		row = 0
-
-		for rows.Next() {
-			total++
-			row++
-
-			if err = rows.StructScan(&device); err != nil {
-				_ = rows.Close()
-				return fmt.Errorf("error scanning U2F device to struct: %w", err)
-			}
-
-			if _, err = p.decrypt(device.PublicKey); err != nil {
-				invalid++
-			}
-		}
-
-		_ = rows.Close()
-
-		if row < pageSize {
+		if len(values) < encryptionBatchSize {
 			break
 		}
 	}
 
 	if invalid != 0 {
-		return fmt.Errorf("%d of %d total U2F devices were invalid", invalid, total)
+		return fmt.Errorf("%d of %d total values of column '%s' of table '%s' were invalid", invalid, total, column.Column, column.Table)
 	}
 
 	return nil
@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+const (
+	testEncryptionKey      = "a_very_long_encryption_key_used_for_testing"
+	testEncryptionKeyNew   = "a_new_very_long_encryption_key_used_for_testing"
+	testEncryptionKeyOther = "another_very_long_encryption_key_used_for_testing"
+)
+
+func newTestEncryptionSQLiteProvider(t *testing.T, path, key string) *SQLiteProvider {
+	provider := NewSQLiteProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: key,
+			Local:         &schema.LocalStorageConfiguration{Path: path},
+		},
+	})
+
+	t.Cleanup(func() {
+		_ = provider.Close()
+	})
+
+	return provider
+}
+
+func newTestEncryptionDatabase(t *testing.T, configs int) (path string, provider *SQLiteProvider) {
+	path = filepath.Join(t.TempDir(), "db.sqlite3")
+
+	provider = newTestEncryptionSQLiteProvider(t, path, testEncryptionKey)
+
+	require.NoError(t, provider.StartupCheck())
+
+	ctx := context.Background()
+
+	for i := 0; i < configs; i++ {
+		require.NoError(t, provider.SaveTOTPConfiguration(ctx, model.TOTPConfiguration{
+			CreatedAt: time.Now(),
+			Username:  fmt.Sprintf("user%d", i),
+			Issuer:    "Authelia",
+			Algorithm: "SHA1",
+			Digits:    6,
+			Period:    30,
+			Secret:    []byte(fmt.Sprintf("secret%d", i)),
+		}))
+	}
+
+	require.NoError(t, provider.SaveWebauthnDevice(ctx, model.WebauthnDevice{
+		CreatedAt:   time.Now(),
+		RPID:        "example.com",
+		Username:    "user0",
+		Description: "Primary",
+		KID:         model.NewBase64([]byte("kid")),
+		PublicKey:   []byte("public key"),
+	}))
+
+	return path, provider
+}
+
+func assertTestEncryptionDatabase(t *testing.T, provider *SQLiteProvider, configs int) {
+	ctx := context.Background()
+
+	require.NoError(t, provider.SchemaEncryptionCheckKey(ctx, true))
+
+	loaded, err := provider.LoadTOTPConfigurations(ctx, configs+1, 0)
+	require.NoError(t, err)
+	require.Len(t, loaded, configs)
+
+	for _, config := range loaded {
+		assert.Equal(t, "secret"+config.Username[len("user"):], string(config.Secret))
+	}
+
+	devices, err := provider.LoadWebauthnDevicesByUsername(ctx, "user0")
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+	assert.Equal(t, "public key", string(devices[0].PublicKey))
+}
+
+func TestShouldChangeEncryptionKey(t *testing.T) {
+	path, provider := newTestEncryptionDatabase(t, 150)
+
+	var results []EncryptionChangeKeyResult
+
+	require.NoError(t, provider.SchemaEncryptionChangeKey(context.Background(), testEncryptionKeyNew, false, func(result EncryptionChangeKeyResult) {
+		results = append(results, result)
+	}))
+
+	assert.Equal(t, []EncryptionChangeKeyResult{
+		{Table: tableTOTPConfigurations, Column: "secret", Total: 150, Changed: 100},
+		{Table: tableTOTPConfigurations, Column: "secret", Total: 150, Changed: 150},
+		{Table: tableWebauthnDevices, Column: "public_key", Total: 1, Changed: 1},
+		{Table: tableOAuth2AuthorizeCodeSession, Column: "session_data"},
+		{Table: tableOAuth2AccessTokenSession, Column: "session_data"},
+		{Table: tableOAuth2RefreshTokenSession, Column: "session_data"},
+		{Table: tableOAuth2PKCERequestSession, Column: "session_data"},
+		{Table: tableOAuth2OpenIDConnectSession, Column: "session_data"},
+	}, results)
+
+	assert.ErrorIs(t, provider.SchemaEncryptionCheckKey(context.Background(), false), ErrSchemaEncryptionInvalidKey)
+
+	assertTestEncryptionDatabase(t, newTestEncryptionSQLiteProvider(t, path, testEncryptionKeyNew), 150)
+}
+
+func TestShouldNotSaveEncryptionKeyChangeOnDryRun(t *testing.T) {
+	path, provider := newTestEncryptionDatabase(t, 5)
+
+	var changed int
+
+	require.NoError(t, provider.SchemaEncryptionChangeKey(context.Background(), testEncryptionKeyNew, true, func(result EncryptionChangeKeyResult) {
+		changed += result.Changed
+	}))
+
+	assert.Equal(t, 6, changed)
+
+	assertTestEncryptionDatabase(t, provider, 5)
+	assert.ErrorIs(t, newTestEncryptionSQLiteProvider(t, path, testEncryptionKeyNew).SchemaEncryptionCheckKey(context.Background(), false), ErrSchemaEncryptionInvalidKey)
+}
+
+func TestShouldSkipValuesAlreadyEncryptedWithNewKey(t *testing.T) {
+	path, provider := newTestEncryptionDatabase(t, 5)
+
+	ctx := context.Background()
+
+	require.NoError(t, provider.SchemaEncryptionChangeKey(ctx, testEncryptionKeyNew, false, nil))
+
+	// Simulate a change which was interrupted after some of the values were encrypted with the new key.
+	providerNew := newTestEncryptionSQLiteProvider(t, path, testEncryptionKeyNew)
+
+	require.NoError(t, providerNew.SaveTOTPConfiguration(ctx, model.TOTPConfiguration{
+		CreatedAt: time.Now(),
+		Username:  "user5",
+		Issuer:    "Authelia",
+		Algorithm: "SHA1",
+		Digits:    6,
+		Period:    30,
+		Secret:    []byte("secret5"),
+	}))
+
+	var results []EncryptionChangeKeyResult
+
+	require.NoError(t, provider.SchemaEncryptionChangeKey(ctx, testEncryptionKeyNew, false, func(result EncryptionChangeKeyResult) {
+		results = append(results, result)
+	}))
+
+	require.Len(t, results, 7)
+	assert.Equal(t, EncryptionChangeKeyResult{Table: tableTOTPConfigurations, Column: "secret", Total: 6, Skipped: 6}, results[0])
+	assert.Equal(t, EncryptionChangeKeyResult{Table: tableWebauthnDevices, Column: "public_key", Total: 1, Skipped: 1}, results[1])
+
+	assertTestEncryptionDatabase(t, providerNew, 6)
+}
+
+func TestShouldRollbackEncryptionKeyChangeOnInvalidValue(t *testing.T) {
+	path, provider := newTestEncryptionDatabase(t, 5)
+
+	ctx := context.Background()
+
+	require.NoError(t, newTestEncryptionSQLiteProvider(t, path, testEncryptionKeyOther).SaveTOTPConfiguration(ctx, model.TOTPConfiguration{
+		CreatedAt: time.Now(),
+		Username:  "other",
+		Issuer:    "Authelia",
+		Algorithm: "SHA1",
+		Digits:    6,
+		Period:    30,
+		Secret:    []byte("secret"),
+	}))
+
+	err := provider.SchemaEncryptionChangeKey(ctx, testEncryptionKeyNew, false, nil)
+
+	require.Error(t, err)
+	assert.Regexp(t, `^rollback due to error: error decrypting the value of column 'secret' of table 'totp_configurations' with id 6 with either key: `, err.Error())
+
+	require.NoError(t, provider.SchemaEncryptionCheckKey(ctx, false))
+
+	_, err = provider.LoadTOTPConfigurationsByUsername(ctx, "user0")
+	assert.NoError(t, err)
+}
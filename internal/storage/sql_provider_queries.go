@@ -72,13 +72,14 @@ const (
 )
 
 const (
-	queryFmtSelectTOTPConfiguration = `
-		SELECT id, username, issuer, algorithm, digits, period, secret
+	queryFmtSelectTOTPConfigurationsByUsername = `
+		SELECT id, created_at, last_used_at, last_used_step, username, description, issuer, algorithm, digits, period, secret
 		FROM %s
-		WHERE username = ?;`
+		WHERE username = ?
+		ORDER BY id;`
 
 	queryFmtSelectTOTPConfigurations = `
-		SELECT id, username, issuer, algorithm, digits, period, secret
+		SELECT id, username, description, issuer, algorithm, digits, period, secret
 		FROM %s
 		LIMIT ?
 		OFFSET ?;`
@@ -96,19 +97,19 @@ const (
 		WHERE username = ?;`
 
 	queryFmtUpsertTOTPConfiguration = `
-		REPLACE INTO %s (created_at, last_used_at, username, issuer, algorithm, digits, period, secret)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?);`
+		REPLACE INTO %s (created_at, last_used_at, last_used_step, username, description, issuer, algorithm, digits, period, secret)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
 
 	queryFmtUpsertTOTPConfigurationPostgreSQL = `
-		INSERT INTO %s (created_at, last_used_at, username, issuer, algorithm, digits, period, secret)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-			ON CONFLICT (username)
-			DO UPDATE SET created_at = $1, last_used_at = $2, issuer = $4, algorithm = $5, digits = $6, period = $7, secret = $8;`
+		INSERT INTO %s (created_at, last_used_at, last_used_step, username, description, issuer, algorithm, digits, period, secret)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (username, description)
+			DO UPDATE SET created_at = $1, last_used_at = $2, last_used_step = $3, issuer = $6, algorithm = $7, digits = $8, period = $9, secret = $10;`
 
 	queryFmtUpdateTOTPConfigRecordSignIn = `
 		UPDATE %s
-		SET last_used_at = ?
-		WHERE id = ?;`
+		SET last_used_at = ?, last_used_step = ?
+		WHERE id = ? AND (last_used_step IS NULL OR last_used_step < ?);`
 
 	queryFmtUpdateTOTPConfigRecordSignInByUsername = `
 		UPDATE %s
@@ -118,6 +119,10 @@ const (
 	queryFmtDeleteTOTPConfiguration = `
 		DELETE FROM %s
 		WHERE username = ?;`
+
+	queryFmtDeleteTOTPConfigurationByID = `
+		DELETE FROM %s
+		WHERE username = ? AND id = ?;`
 )
 
 const (
@@ -192,8 +197,8 @@ const (
 
 const (
 	queryFmtInsertAuthenticationLogEntry = `
-		INSERT INTO %s (time, successful, banned, username, auth_type, remote_ip, request_uri, request_method)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?);`
+		INSERT INTO %s (time, successful, banned, username, auth_type, remote_ip, request_uri, request_method, user_agent)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`
 
 	queryFmtSelect1FAAuthenticationLogEntryByUsername = `
 		SELECT time, successful, username
@@ -202,6 +207,222 @@ const (
 		ORDER BY time DESC
 		LIMIT ?
 		OFFSET ?;`
+
+	queryFmtSelectAuthenticationLogEntriesByUsername = `
+		SELECT id, time, successful, banned, username, auth_type, remote_ip, request_uri, request_method, user_agent
+		FROM %s
+		WHERE username = ?
+		ORDER BY time DESC
+		LIMIT ?
+		OFFSET ?;`
+
+	queryFmtSelectSecondFactorFailedAuthenticationLogEntriesCount = `
+		SELECT COUNT(id)
+		FROM %s
+		WHERE time >= ? AND username = ? AND auth_type IN ('TOTP', 'Webauthn', 'Duo') AND successful = FALSE AND banned = FALSE;`
+
+	queryFmtDeleteAuthenticationLogEntriesBefore = `
+		DELETE FROM %s
+		WHERE time < ?;`
+)
+
+const (
+	queryFmtSelectUserLoginLocation = `
+		SELECT id, time, username, remote_ip, latitude, longitude
+		FROM %s
+		WHERE username = ?;`
+
+	queryFmtUpsertUserLoginLocation = `
+		REPLACE INTO %s (time, username, remote_ip, latitude, longitude)
+		VALUES (?, ?, ?, ?, ?);`
+
+	queryFmtUpsertUserLoginLocationPostgreSQL = `
+		INSERT INTO %s (time, username, remote_ip, latitude, longitude)
+		VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (username)
+			DO UPDATE SET time = $1, remote_ip = $3, latitude = $4, longitude = $5;`
+)
+
+const (
+	queryFmtSelectUserFirstLogin = `
+		SELECT time
+		FROM %s
+		WHERE username = ?;`
+
+	queryFmtInsertUserFirstLogin = `
+		INSERT INTO %s (time, username)
+		VALUES (?, ?);`
+)
+
+const (
+	queryFmtSelectUserPasswordChange = `
+		SELECT changed_at
+		FROM %s
+		WHERE username = ?;`
+
+	queryFmtUpsertUserPasswordChange = `
+		REPLACE INTO %s (changed_at, username)
+		VALUES (?, ?);`
+
+	queryFmtUpsertUserPasswordChangePostgreSQL = `
+		INSERT INTO %s (changed_at, username)
+		VALUES ($1, $2)
+			ON CONFLICT (username)
+			DO UPDATE SET changed_at = $1;`
+)
+
+const (
+	queryFmtSelectRegulationUnlock = `
+		SELECT id, created_at, expires_at, unlocked_at, username, token_hash, attempts
+		FROM %s
+		WHERE username = ?;`
+
+	queryFmtUpsertRegulationUnlock = `
+		REPLACE INTO %s (created_at, expires_at, unlocked_at, username, token_hash, attempts)
+		VALUES (?, ?, NULL, ?, ?, 0);`
+
+	queryFmtUpsertRegulationUnlockPostgreSQL = `
+		INSERT INTO %s (created_at, expires_at, unlocked_at, username, token_hash, attempts)
+		VALUES ($1, $2, NULL, $3, $4, 0)
+			ON CONFLICT (username)
+			DO UPDATE SET created_at = $1, expires_at = $2, unlocked_at = NULL, token_hash = $4, attempts = 0;`
+
+	queryFmtUpdateRegulationUnlockUnlocked = `
+		UPDATE %s
+		SET unlocked_at = ?
+		WHERE username = ? AND unlocked_at IS NULL;`
+
+	queryFmtUpdateRegulationUnlockAttempts = `
+		UPDATE %s
+		SET attempts = attempts + 1
+		WHERE username = ?;`
+)
+
+const (
+	queryFmtSelectLoginFingerprints = `
+		SELECT id, created_at, last_seen_at, notified_at, username, device, network, country, suppress_token_hash, suppressed
+		FROM %s
+		WHERE username = ?;`
+
+	queryFmtInsertLoginFingerprint = `
+		INSERT INTO %s (created_at, last_seen_at, notified_at, username, device, network, country, suppress_token_hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtUpdateLoginFingerprintSeen = `
+		UPDATE %s
+		SET last_seen_at = ?
+		WHERE id = ?;`
+
+	queryFmtUpdateLoginFingerprintSuppressed = `
+		UPDATE %s
+		SET suppressed = TRUE
+		WHERE id = ? AND username = ?;`
+
+	queryFmtDeleteLoginFingerprintsBefore = `
+		DELETE FROM %s
+		WHERE username = ? AND last_seen_at < ? AND suppressed = FALSE;`
+)
+
+const (
+	queryFmtInsertUserRegistration = `
+		INSERT INTO %s (requested_at, remote_ip, username, display_name, email, password)
+		VALUES (?, ?, ?, ?, ?, ?);`
+
+	queryFmtSelectUserRegistration = `
+		SELECT id, requested_at, remote_ip, username, display_name, email, password
+		FROM %s
+		WHERE username = ?;`
+
+	queryFmtSelectUserRegistrations = `
+		SELECT id, requested_at, remote_ip, username, display_name, email, password
+		FROM %s
+		ORDER BY requested_at ASC;`
+
+	queryFmtDeleteUserRegistration = `
+		DELETE FROM %s
+		WHERE username = ?;`
+)
+
+const (
+	queryFmtSelectPasswordResetCode = `
+		SELECT id, created_at, expires_at, username, code_hash
+		FROM %s
+		WHERE username = ?;`
+
+	queryFmtUpsertPasswordResetCode = `
+		REPLACE INTO %s (created_at, expires_at, username, code_hash)
+		VALUES (?, ?, ?, ?);`
+
+	queryFmtUpsertPasswordResetCodePostgreSQL = `
+		INSERT INTO %s (created_at, expires_at, username, code_hash)
+		VALUES ($1, $2, $3, $4)
+			ON CONFLICT (username)
+			DO UPDATE SET created_at = $1, expires_at = $2, code_hash = $4;`
+
+	queryFmtDeletePasswordResetCode = `
+		DELETE FROM %s
+		WHERE username = ?;`
+)
+
+const (
+	queryFmtSelectEmailVerification = `
+		SELECT id, created_at, expires_at, verified_at, username, email, token_hash
+		FROM %s
+		WHERE username = ?;`
+
+	queryFmtUpsertEmailVerification = `
+		REPLACE INTO %s (created_at, expires_at, verified_at, username, email, token_hash)
+		VALUES (?, ?, NULL, ?, ?, ?);`
+
+	queryFmtUpsertEmailVerificationPostgreSQL = `
+		INSERT INTO %s (created_at, expires_at, verified_at, username, email, token_hash)
+		VALUES ($1, $2, NULL, $3, $4, $5)
+			ON CONFLICT (username)
+			DO UPDATE SET created_at = $1, expires_at = $2, verified_at = NULL, email = $4, token_hash = $5;`
+
+	queryFmtUpdateEmailVerificationVerified = `
+		UPDATE %s
+		SET verified_at = ?
+		WHERE username = ? AND verified_at IS NULL;`
+
+	queryFmtDeleteEmailVerification = `
+		DELETE FROM %s
+		WHERE username = ?;`
+)
+
+const (
+	queryFmtSelectSession = `
+		SELECT id, expires_at, data
+		FROM %s
+		WHERE id = ?;`
+
+	queryFmtSelectSessionsCount = `
+		SELECT COUNT(id)
+		FROM %s
+		WHERE expires_at IS NULL OR expires_at > ?;`
+
+	queryFmtUpsertSession = `
+		REPLACE INTO %s (id, expires_at, data)
+		VALUES (?, ?, ?);`
+
+	queryFmtUpsertSessionPostgreSQL = `
+		INSERT INTO %s (id, expires_at, data)
+		VALUES ($1, $2, $3)
+			ON CONFLICT (id)
+			DO UPDATE SET expires_at = $2, data = $3;`
+
+	queryFmtUpdateSessionID = `
+		UPDATE %s
+		SET id = ?, expires_at = ?
+		WHERE id = ?;`
+
+	queryFmtDeleteSession = `
+		DELETE FROM %s
+		WHERE id = ?;`
+
+	queryFmtDeleteSessionsExpired = `
+		DELETE FROM %s
+		WHERE expires_at IS NOT NULL AND expires_at <= ?;`
 )
 
 const (
@@ -219,6 +440,22 @@ const (
 		VALUES ($1, $2)
 			ON CONFLICT (name)
 			DO UPDATE SET value = $2;`
+
+	queryFmtSelectRowCount = `
+		SELECT COUNT(id)
+		FROM %s;`
+
+	queryFmtSelectEncryptedValues = `
+		SELECT id, %s AS value
+		FROM %s
+		WHERE id > ?
+		ORDER BY id
+		LIMIT ?;`
+
+	queryFmtUpdateEncryptedValue = `
+		UPDATE %s
+		SET %s = ?
+		WHERE id = ?;`
 )
 
 const (
@@ -235,6 +472,15 @@ const (
 		WHERE client_id = ? AND subject = ? AND 
 			  authorized = TRUE AND granted = TRUE AND expires_at IS NOT NULL AND expires_at >= CURRENT_TIMESTAMP;`
 
+	queryFmtSelectOAuth2ConsentSessionsActiveByUsername = `
+		SELECT c.id, c.challenge_id, c.client_id, c.subject, c.authorized, c.granted, c.requested_at, c.responded_at,
+		c.expires_at, c.form_data, c.requested_scopes, c.granted_scopes, c.requested_audience, c.granted_audience
+		FROM %s AS c
+		JOIN %s AS u ON u.identifier = c.subject
+		WHERE u.username = ? AND
+			  c.authorized = TRUE AND c.granted = TRUE AND c.expires_at IS NOT NULL AND c.expires_at >= CURRENT_TIMESTAMP
+		ORDER BY c.responded_at DESC;`
+
 	queryFmtInsertOAuth2ConsentSession = `
 		INSERT INTO %s (challenge_id, client_id, subject, authorized, granted, requested_at, responded_at, expires_at,
 		form_data, requested_scopes, granted_scopes, requested_audience, granted_audience)
@@ -281,7 +527,19 @@ const (
 	queryFmtDeactivateOAuth2SessionByRequestID = `
 		UPDATE %s
 		SET active = FALSE
-		WHERE request_id = ?;"`
+		WHERE request_id = ?;`
+
+	queryFmtUpdateOAuth2RefreshTokenSessionLastUsed = `
+		UPDATE %s
+		SET last_used_at = ?
+		WHERE signature = ?;`
+
+	queryFmtSelectOAuth2RefreshTokenSessionsByUsername = `
+		SELECT r.id, r.request_id, r.client_id, r.granted_scopes, r.requested_at, r.last_used_at, r.active
+		FROM %s AS r
+		JOIN %s AS u ON u.identifier = r.subject
+		WHERE u.username = ? AND r.revoked = FALSE
+		ORDER BY r.id ASC;`
 
 	queryFmtSelectOAuth2BlacklistedJTI = `
 		SELECT id, signature, expires_at
@@ -12,6 +12,28 @@ const (
 		RENAME %s;`
 )
 
+// Cleanup constants.
+const (
+	// The nested sub query is necessary as MySQL doesn't support LIMIT in an IN sub query or a sub query selecting
+	// from the table being deleted from.
+	queryFmtDeleteExpiredRows = `
+		DELETE FROM %s
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id
+				FROM %s
+				WHERE %s
+				LIMIT ?
+			) AS expired
+		);`
+
+	queryMySQLSelectCleanupLock   = `SELECT GET_LOCK(CONCAT(DATABASE(), '.authelia_storage_cleanup'), 0);`
+	queryMySQLSelectCleanupUnlock = `SELECT RELEASE_LOCK(CONCAT(DATABASE(), '.authelia_storage_cleanup'));`
+
+	queryPostgreSelectCleanupLock   = `SELECT pg_try_advisory_lock(hashtext('authelia_storage_cleanup'));`
+	queryPostgreSelectCleanupUnlock = `SELECT pg_advisory_unlock(hashtext('authelia_storage_cleanup'));`
+)
+
 // Pre1 migration constants.
 const (
 	queryFmtPre1To1SelectAuthenticationLogs = `
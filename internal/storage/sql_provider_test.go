@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestShouldRetryPingUntilMaxDuration(t *testing.T) {
+	provider := NewPostgreSQLProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			PostgreSQL: &schema.PostgreSQLStorageConfiguration{
+				SQLStorageConfiguration: schema.SQLStorageConfiguration{
+					Host:     "127.0.0.1",
+					Port:     1,
+					Database: "authelia",
+					Username: "authelia",
+					Password: "authelia",
+					Timeout:  time.Second,
+				},
+				Schema: "public",
+				SSL:    schema.PostgreSQLSSLStorageConfiguration{Mode: "disable"},
+			},
+			ConnectionRetry: schema.StorageConnectionRetryConfiguration{
+				Interval:      10 * time.Millisecond,
+				BackoffFactor: 2,
+				MaxDuration:   100 * time.Millisecond,
+			},
+		},
+	})
+
+	t.Cleanup(func() {
+		_ = provider.Close()
+	})
+
+	start := time.Now()
+
+	err := provider.StartupCheck()
+
+	assert.ErrorContains(t, err, "error pinging database: ")
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestShouldCountSecondFactorFailedAuthenticationLogs(t *testing.T) {
+	provider := newTestEncryptionSQLiteProvider(t, filepath.Join(t.TempDir(), "db.sqlite3"), testEncryptionKey)
+
+	require.NoError(t, provider.StartupCheck())
+
+	ctx := context.Background()
+	now := time.Now()
+
+	attempts := []model.AuthenticationAttempt{
+		{Time: now.Add(-time.Hour), Username: "john", Type: "TOTP"},
+		{Time: now, Username: "john", Type: "1FA"},
+		{Time: now, Username: "john", Type: "TOTP"},
+		{Time: now, Username: "john", Type: "Webauthn"},
+		{Time: now, Username: "john", Type: "Duo"},
+		{Time: now, Username: "john", Type: "TOTP", Successful: true},
+		{Time: now, Username: "john", Type: "2FA Limit", Banned: true},
+		{Time: now, Username: "harry", Type: "TOTP"},
+	}
+
+	for _, attempt := range attempts {
+		require.NoError(t, provider.AppendAuthenticationLog(ctx, attempt))
+	}
+
+	count, err := provider.CountSecondFactorFailedAuthenticationLogs(ctx, "john", now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestShouldSaveTOTPConfigurationLastUsedStep(t *testing.T) {
+	provider := newTestEncryptionSQLiteProvider(t, filepath.Join(t.TempDir(), "db.sqlite3"), testEncryptionKey)
+
+	require.NoError(t, provider.StartupCheck())
+
+	ctx := context.Background()
+
+	config := model.TOTPConfiguration{CreatedAt: time.Now(), Username: "john", Description: "Primary", Issuer: "Authelia", Algorithm: "SHA1", Digits: 6, Period: 30, Secret: []byte("secret")}
+	config.UpdateSignInInfo(time.Now(), 100)
+
+	require.NoError(t, provider.SaveTOTPConfiguration(ctx, config))
+
+	configs, err := provider.LoadTOTPConfigurationsByUsername(ctx, "john")
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	require.NotNil(t, configs[0].LastUsedStep)
+	assert.Equal(t, uint64(100), *configs[0].LastUsedStep)
+
+	now := time.Now()
+
+	assert.ErrorIs(t, provider.UpdateTOTPConfigurationSignIn(ctx, configs[0].ID, &now, 100), ErrTOTPStepAlreadyUsed)
+	assert.NoError(t, provider.UpdateTOTPConfigurationSignIn(ctx, configs[0].ID, &now, 101))
+}
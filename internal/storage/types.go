@@ -0,0 +1,35 @@
+package storage
+
+// EncryptionChangeKeyResult is the progress of changing the encryption key of an encrypted column.
+type EncryptionChangeKeyResult struct {
+	Table  string
+	Column string
+
+	// Total is the number of rows of the table.
+	Total int
+
+	// Changed is the number of values which were encrypted with the new key.
+	Changed int
+
+	// Skipped is the number of values which were already encrypted with the new key.
+	Skipped int
+}
+
+type encryptedColumn struct {
+	Table  string
+	Column string
+}
+
+// expiredRowsTable is a table with expiring rows and the condition matching the rows which expired before a time.
+type expiredRowsTable struct {
+	Table     string
+	Condition string
+
+	// OAuth2 indicates the condition uses the OAuth 2.0 session cutoff instead of the current time.
+	OAuth2 bool
+}
+
+type encryptedValue struct {
+	ID    int    `db:"id"`
+	Value []byte `db:"value"`
+}
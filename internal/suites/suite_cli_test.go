@@ -71,7 +71,7 @@ func (s *CLISuite) TestShouldPrintVersion() {
 }
 
 func (s *CLISuite) TestShouldValidateConfig() {
-	output, err := s.Exec("authelia-backend", []string{"authelia", s.testArg, s.coverageArg, "validate-config", "--config=/config/configuration.yml"})
+	output, err := s.Exec("authelia-backend", []string{"authelia", s.testArg, s.coverageArg, "config", "validate", "--config=/config/configuration.yml"})
 	s.Assert().NoError(err)
 	s.Assert().Contains(output, "Configuration parsed and loaded successfully without errors.")
 }
@@ -337,7 +337,8 @@ func (s *CLISuite) TestStorage03ShouldExportTOTP() {
 	}
 
 	var (
-		config   *model.TOTPConfiguration
+		configs  []model.TOTPConfiguration
+		config   model.TOTPConfiguration
 		fileInfo os.FileInfo
 	)
 
@@ -357,8 +358,11 @@ func (s *CLISuite) TestStorage03ShouldExportTOTP() {
 			s.Assert().NoError(err)
 		}
 
-		config, err = storageProvider.LoadTOTPConfiguration(ctx, testCase.config.Username)
+		configs, err = storageProvider.LoadTOTPConfigurationsByUsername(ctx, testCase.config.Username)
 		s.Assert().NoError(err)
+		s.Require().Len(configs, 1)
+
+		config = configs[0]
 
 		s.Assert().Contains(output, config.URI())
 
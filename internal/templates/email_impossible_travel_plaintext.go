@@ -0,0 +1,32 @@
+package templates
+
+import (
+	"text/template"
+)
+
+// EmailImpossibleTravelPlainText the template of email that the user will receive when they log in from a location
+// which could not have been reached since their previous login.
+var EmailImpossibleTravelPlainText *template.Template
+
+func init() {
+	t, err := template.New("email_impossible_travel_plain_text").Parse(emailContentImpossibleTravelPlainText)
+	if err != nil {
+		panic(err)
+	}
+
+	EmailImpossibleTravelPlainText = t
+}
+
+const emailContentImpossibleTravelPlainText = `
+Hi {{ .DisplayName }},
+
+Your account with the username {{ .Username }} was signed in to from a location which could not have been reached
+since the previous sign in.
+
+Previous sign in: {{ .PreviousTime }} from {{ .PreviousRemoteIP }} ({{ .PreviousLatitude }}, {{ .PreviousLongitude }})
+Current sign in: {{ .Time }} from {{ .RemoteIP }} ({{ .Latitude }}, {{ .Longitude }})
+
+The second factor is required to access any resource with this sign in.
+
+If this wasn't you, someone else may know your password. You should change your password immediately.
+`
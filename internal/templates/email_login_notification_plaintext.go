@@ -0,0 +1,34 @@
+package templates
+
+import (
+	"text/template"
+)
+
+// EmailLoginNotificationPlainText the template of email that the user will receive when they log in from a device or
+// location which hasn't been seen before.
+var EmailLoginNotificationPlainText *template.Template
+
+func init() {
+	t, err := template.New("email_login_notification_plain_text").Parse(emailContentLoginNotificationPlainText)
+	if err != nil {
+		panic(err)
+	}
+
+	EmailLoginNotificationPlainText = t
+}
+
+const emailContentLoginNotificationPlainText = `
+Hi {{ .DisplayName }},
+
+Your account with the username {{ .Username }} was signed in to from a device or location which hasn't been used
+recently.
+
+Time: {{ .Time }}
+IP: {{ .RemoteIP }}
+Device: {{ .UserAgent }}
+
+If this was you and you don't want to be notified when signing in with this device in the future, visit the following
+URL: {{ .LinkURL }}
+
+If this wasn't you, someone else may know your password. You should change your password immediately.
+`
@@ -0,0 +1,72 @@
+package templates
+
+import (
+	"text/template"
+)
+
+// EmailRegistrationPendingPlainText the template of email that the administrators will receive when an account
+// registration is pending approval.
+var EmailRegistrationPendingPlainText *template.Template
+
+// EmailRegistrationDecisionPlainText the template of email that the user will receive when their account registration
+// has been approved or denied.
+var EmailRegistrationDecisionPlainText *template.Template
+
+// EmailRegistrationVerificationPlainText the template of email that the user will receive to verify the email address
+// of their account registration.
+var EmailRegistrationVerificationPlainText *template.Template
+
+func init() {
+	t, err := template.New("email_registration_pending_plain_text").Parse(emailContentRegistrationPendingPlainText)
+	if err != nil {
+		panic(err)
+	}
+
+	EmailRegistrationPendingPlainText = t
+
+	t, err = template.New("email_registration_decision_plain_text").Parse(emailContentRegistrationDecisionPlainText)
+	if err != nil {
+		panic(err)
+	}
+
+	EmailRegistrationDecisionPlainText = t
+
+	t, err = template.New("email_registration_verification_plain_text").Parse(emailContentRegistrationVerificationPlainText)
+	if err != nil {
+		panic(err)
+	}
+
+	EmailRegistrationVerificationPlainText = t
+}
+
+const emailContentRegistrationPendingPlainText = `
+A new account registration is pending approval.
+
+Username: {{ .Username }}
+Display Name: {{ .DisplayName }}
+Email: {{ .Email }}
+
+This registration was requested by a user with the IP {{ .RemoteIP }}.
+
+The account can't be used until an administrator approves it.
+`
+
+const emailContentRegistrationDecisionPlainText = `
+Hi {{ .DisplayName }},
+{{ if .Approved }}
+Your account registration has been approved. You can now log in with the username {{ .Username }}.
+{{ else }}
+Your account registration has been denied. Please contact an administrator if you believe this is a mistake.
+{{ end }}`
+
+const emailContentRegistrationVerificationPlainText = `
+Hi {{ .DisplayName }},
+
+Please verify the email address of your account registration with the username {{ .Username }} by visiting the
+following URL: {{ .LinkURL }}
+
+This link can only be used once and expires at {{ .ExpiresAt }}.
+
+This registration was requested by a user with the IP {{ .RemoteIP }}. If you did not register an account you can
+ignore this email.
+`
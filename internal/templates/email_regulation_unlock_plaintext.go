@@ -0,0 +1,32 @@
+package templates
+
+import (
+	"text/template"
+)
+
+// EmailRegulationUnlockPlainText the template of email that the user will receive when they have been banned by the
+// regulator which includes a link to lift the ban.
+var EmailRegulationUnlockPlainText *template.Template
+
+func init() {
+	t, err := template.New("email_regulation_unlock_plain_text").Parse(emailContentRegulationUnlockPlainText)
+	if err != nil {
+		panic(err)
+	}
+
+	EmailRegulationUnlockPlainText = t
+}
+
+const emailContentRegulationUnlockPlainText = `
+Hi {{ .DisplayName }},
+
+Your account with the username {{ .Username }} has been locked until {{ .BannedUntil }} after too many failed
+authentication attempts, the latest from a user with the IP {{ .RemoteIP }}.
+
+If these attempts were made by you, you can unlock your account now by visiting the following URL: {{ .LinkURL }}
+
+This link can only be used once and expires at {{ .ExpiresAt }}.
+
+If these attempts were not made by you, someone may be trying to access your account. You can ignore this email and
+your account will be unlocked automatically when the lock expires.
+`
@@ -8,5 +8,5 @@ import (
 type Provider interface {
 	Generate(username string) (config *model.TOTPConfiguration, err error)
 	GenerateCustom(username string, algorithm, secret string, digits, period, secretSize uint) (config *model.TOTPConfiguration, err error)
-	Validate(token string, config *model.TOTPConfiguration) (valid bool, err error)
+	Validate(token string, config *model.TOTPConfiguration) (valid bool, step uint64, err error)
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/hotp"
 	"github.com/pquerna/otp/totp"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -77,14 +78,40 @@ func (p TimeBased) Generate(username string) (config *model.TOTPConfiguration, e
 	return p.GenerateCustom(username, p.config.Algorithm, "", p.config.Digits, p.config.Period, p.config.SecretSize)
 }
 
-// Validate the token against the given configuration.
-func (p TimeBased) Validate(token string, config *model.TOTPConfiguration) (valid bool, err error) {
-	opts := totp.ValidateOpts{
-		Period:    config.Period,
-		Skew:      p.skew,
+// Validate the token against the configuration. The step of the code is returned when it is valid. Codes of steps
+// which aren't more than the last step used with the configuration are never valid to prevent them being replayed.
+func (p TimeBased) Validate(token string, config *model.TOTPConfiguration) (valid bool, step uint64, err error) {
+	period := config.Period
+	if period == 0 {
+		period = 30
+	}
+
+	opts := hotp.ValidateOpts{
 		Digits:    otp.Digits(config.Digits),
 		Algorithm: otpStringToAlgo(config.Algorithm),
 	}
 
-	return totp.ValidateCustom(token, string(config.Secret), time.Now().UTC(), opts)
+	current := uint64(time.Now().UTC().Unix()) / uint64(period)
+
+	steps := []uint64{current}
+
+	for i := uint64(1); i <= uint64(p.skew); i++ {
+		steps = append(steps, current+i)
+
+		if current >= i {
+			steps = append(steps, current-i)
+		}
+	}
+
+	for _, step = range steps {
+		if config.LastUsedStep != nil && step <= *config.LastUsedStep {
+			continue
+		}
+
+		if valid, err = hotp.ValidateCustom(token, step, string(config.Secret), opts); err != nil || valid {
+			return valid, step, err
+		}
+	}
+
+	return false, 0, nil
 }
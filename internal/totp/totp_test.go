@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/hotp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -149,3 +151,48 @@ func TestTOTPGenerate(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, secret, 32)
 }
+
+func TestTOTPValidate(t *testing.T) {
+	skew := uint(1)
+
+	totp := NewTimeBasedProvider(schema.TOTPConfiguration{
+		Issuer:     "Authelia",
+		Algorithm:  "SHA1",
+		Digits:     6,
+		Period:     30,
+		Skew:       &skew,
+		SecretSize: 32,
+	})
+
+	config, err := totp.Generate("john")
+	require.NoError(t, err)
+
+	current := uint64(time.Now().UTC().Unix()) / 30
+
+	token, err := hotp.GenerateCodeCustom(string(config.Secret), current, hotp.ValidateOpts{Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1})
+	require.NoError(t, err)
+
+	valid, step, err := totp.Validate(token, config)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, current, step)
+
+	config.UpdateSignInInfo(time.Now(), step)
+
+	valid, step, err = totp.Validate(token, config)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+	assert.Equal(t, uint64(0), step)
+
+	token, err = hotp.GenerateCodeCustom(string(config.Secret), current+1, hotp.ValidateOpts{Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1})
+	require.NoError(t, err)
+
+	valid, step, err = totp.Validate(token, config)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, current+1, step)
+
+	valid, _, err = totp.Validate("000", config)
+	assert.Error(t, err)
+	assert.False(t, valid)
+}
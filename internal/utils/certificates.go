@@ -105,6 +105,26 @@ func TLSStringToTLSConfigVersion(input string) (version uint16, err error) {
 	return 0, ErrTLSVersionNotSupported
 }
 
+// TLSStringToTLSConfigCipherSuite returns a go crypto/tls cipher suite for a tls.Config based on the IANA name of the
+// cipher suite. Insecure is true if the cipher suite has known security issues.
+func TLSStringToTLSConfigCipherSuite(input string) (suite uint16, insecure bool, err error) {
+	name := strings.ToUpper(input)
+
+	for _, s := range tls.CipherSuites() {
+		if s.Name == name {
+			return s.ID, false, nil
+		}
+	}
+
+	for _, s := range tls.InsecureCipherSuites() {
+		if s.Name == name {
+			return s.ID, true, nil
+		}
+	}
+
+	return 0, false, ErrTLSCipherSuiteNotSupported
+}
+
 // GenerateCertificate generate a certificate given a private key. RSA, Ed25519 and ECDSA are officially supported.
 func GenerateCertificate(privateKeyBuilder PrivateKeyBuilder, hosts []string, validFrom time.Time, validFor time.Duration, isCA bool) ([]byte, []byte, error) {
 	privateKey, err := privateKeyBuilder.Build()
@@ -66,6 +66,28 @@ func TestShouldReturnCorrectTLSVersions(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestShouldReturnCipherSuitesFromStrings(t *testing.T) {
+	suite, insecure, err := TLSStringToTLSConfigCipherSuite("TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256")
+	assert.NoError(t, err)
+	assert.False(t, insecure)
+	assert.Equal(t, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, suite)
+
+	suite, insecure, err = TLSStringToTLSConfigCipherSuite("tls_ecdhe_ecdsa_with_chacha20_poly1305_sha256")
+	assert.NoError(t, err)
+	assert.False(t, insecure)
+	assert.Equal(t, tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256, suite)
+
+	suite, insecure, err = TLSStringToTLSConfigCipherSuite("TLS_RSA_WITH_RC4_128_SHA")
+	assert.NoError(t, err)
+	assert.True(t, insecure)
+	assert.Equal(t, tls.TLS_RSA_WITH_RC4_128_SHA, suite)
+
+	suite, insecure, err = TLSStringToTLSConfigCipherSuite("TLS_NOT_A_CIPHER")
+	assert.EqualError(t, err, "supplied tls cipher suite isn't supported")
+	assert.False(t, insecure)
+	assert.Equal(t, uint16(0), suite)
+}
+
 func TestShouldReturnZeroAndErrorOnInvalidTLSVersions(t *testing.T) {
 	version, err := TLSStringToTLSConfigVersion("TLS1.4")
 	assert.Error(t, err)
@@ -92,3 +92,6 @@ var ErrTimeoutReached = errors.New("timeout reached")
 
 // ErrTLSVersionNotSupported returned when an unknown TLS version supplied.
 var ErrTLSVersionNotSupported = errors.New("supplied tls version isn't supported")
+
+// ErrTLSCipherSuiteNotSupported returned when an unknown TLS cipher suite is supplied.
+var ErrTLSCipherSuiteNotSupported = errors.New("supplied tls cipher suite isn't supported")
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"strings"
+)
+
+// IsEmailDomainPermitted returns true if the domain of the email address is permitted by the allowed and denied domain
+// patterns. A domain which matches a denied pattern is never permitted, and when there are allowed patterns the domain
+// must match one of them. Patterns are either a domain which must match exactly, or a domain prefixed with '*.' which
+// matches any of its subdomains. Matching is case-insensitive.
+func IsEmailDomainPermitted(email string, allowed, denied []string) (permitted bool) {
+	i := strings.LastIndex(email, "@")
+	if i == -1 {
+		return false
+	}
+
+	domain := strings.TrimSuffix(strings.ToLower(email[i+1:]), ".")
+
+	if domain == "" {
+		return false
+	}
+
+	for _, pattern := range denied {
+		if IsDomainMatchingPattern(domain, pattern) {
+			return false
+		}
+	}
+
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, pattern := range allowed {
+		if IsDomainMatchingPattern(domain, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsDomainMatchingPattern returns true if the domain matches the pattern. A pattern prefixed with '*.' matches any
+// subdomain of the remainder of the pattern but not the remainder itself. Matching is case-insensitive.
+func IsDomainMatchingPattern(domain, pattern string) (matches bool) {
+	domain, pattern = strings.ToLower(domain), strings.ToLower(pattern)
+
+	if strings.HasPrefix(pattern, "*.") {
+		return strings.HasSuffix(domain, pattern[1:]) && len(domain) > len(pattern)-1
+	}
+
+	return domain == pattern
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDomainMatchingPattern(t *testing.T) {
+	testCases := []struct {
+		desc, domain, pattern string
+		want                  bool
+	}{
+		{desc: "ShouldMatchExactDomain", domain: "example.com", pattern: "example.com", want: true},
+		{desc: "ShouldMatchExactDomainCaseInsensitive", domain: "Example.COM", pattern: "example.com", want: true},
+		{desc: "ShouldNotMatchSubdomainWithExactPattern", domain: "mail.example.com", pattern: "example.com", want: false},
+		{desc: "ShouldMatchSubdomainWithWildcard", domain: "mail.example.com", pattern: "*.example.com", want: true},
+		{desc: "ShouldMatchNestedSubdomainWithWildcard", domain: "a.mail.example.com", pattern: "*.Example.com", want: true},
+		{desc: "ShouldNotMatchApexWithWildcard", domain: "example.com", pattern: "*.example.com", want: false},
+		{desc: "ShouldNotMatchSuffixWithoutDot", domain: "badexample.com", pattern: "*.example.com", want: false},
+		{desc: "ShouldNotMatchDifferentDomain", domain: "example.org", pattern: "example.com", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.want, IsDomainMatchingPattern(tc.domain, tc.pattern))
+		})
+	}
+}
+
+func TestIsEmailDomainPermitted(t *testing.T) {
+	testCases := []struct {
+		desc, email     string
+		allowed, denied []string
+		want            bool
+	}{
+		{desc: "ShouldPermitAnyDomainWithoutPatterns", email: "john@example.org", want: true},
+		{desc: "ShouldPermitAllowedDomain", email: "john@EXAMPLE.com", allowed: []string{"example.com"}, want: true},
+		{desc: "ShouldPermitAllowedSubdomain", email: "john@corp.example.com", allowed: []string{"*.example.com"}, want: true},
+		{desc: "ShouldNotPermitDomainNotAllowed", email: "john@gmail.com", allowed: []string{"example.com", "*.example.com"}, want: false},
+		{desc: "ShouldNotPermitDeniedDomain", email: "john@gmail.com", denied: []string{"gmail.com"}, want: false},
+		{desc: "ShouldNotPermitDeniedDomainWhichIsAlsoAllowed", email: "john@old.example.com", allowed: []string{"*.example.com"}, denied: []string{"old.example.com"}, want: false},
+		{desc: "ShouldPermitDomainNotDenied", email: "john@example.com", denied: []string{"*.example.com"}, want: true},
+		{desc: "ShouldPermitDomainWithTrailingDot", email: "john@example.com.", allowed: []string{"example.com"}, want: true},
+		{desc: "ShouldNotPermitInvalidEmail", email: "john", want: false},
+		{desc: "ShouldNotPermitEmptyDomain", email: "john@", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.want, IsEmailDomainPermitted(tc.email, tc.allowed, tc.denied))
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	memInfoPath        = "/proc/meminfo"
+	cgroupV2MemoryPath = "/sys/fs/cgroup"
+	cgroupV1MemoryPath = "/sys/fs/cgroup/memory"
+)
+
+// AvailableMemory returns the number of bytes of memory available to the process. It's the memory the kernel reports as
+// available limited by the remaining memory of the cgroup of the process when the cgroup has a memory limit, so the
+// limits of containers are respected. It's only supported on Linux.
+func AvailableMemory() (available uint64, err error) {
+	return availableMemory(memInfoPath, cgroupV2MemoryPath, cgroupV1MemoryPath)
+}
+
+func availableMemory(memInfo, cgroupV2, cgroupV1 string) (available uint64, err error) {
+	if available, err = readMemInfoAvailable(memInfo); err != nil {
+		return 0, err
+	}
+
+	limit, usage, ok := readCgroupMemory(cgroupV2+"/memory.max", cgroupV2+"/memory.current")
+	if !ok {
+		limit, usage, ok = readCgroupMemory(cgroupV1+"/memory.limit_in_bytes", cgroupV1+"/memory.usage_in_bytes")
+	}
+
+	if ok && limit < available+usage {
+		if usage >= limit {
+			return 0, nil
+		}
+
+		return limit - usage, nil
+	}
+
+	return available, nil
+}
+
+func readMemInfoAvailable(path string) (available uint64, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("unable to determine the available memory: %w", err)
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) < 2 || fields[0] != "MemAvailable:" {
+			continue
+		}
+
+		if available, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+			return 0, fmt.Errorf("unable to determine the available memory: %w", err)
+		}
+
+		if len(fields) == 3 && fields[2] == "kB" {
+			available *= 1024
+		}
+
+		return available, nil
+	}
+
+	if err = scanner.Err(); err != nil {
+		return 0, fmt.Errorf("unable to determine the available memory: %w", err)
+	}
+
+	return 0, errors.New("unable to determine the available memory: the kernel does not report the available memory")
+}
+
+// readCgroupMemory reads the memory limit and usage of a cgroup. It's not ok if either can't be read or the cgroup has
+// no memory limit.
+func readCgroupMemory(limitPath, usagePath string) (limit, usage uint64, ok bool) {
+	var err error
+
+	if limit, err = readUintFile(limitPath); err != nil {
+		return 0, 0, false
+	}
+
+	if usage, err = readUintFile(usagePath); err != nil {
+		return 0, 0, false
+	}
+
+	return limit, usage, true
+}
+
+func readUintFile(path string) (value uint64, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMemInfo = `MemTotal:        2048000 kB
+MemFree:          512000 kB
+MemAvailable:    1024000 kB
+Buffers:           10000 kB
+`
+
+func writeMemoryTestFiles(t *testing.T, files map[string]string) (dir string) {
+	dir = t.TempDir()
+
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	return dir
+}
+
+func TestShouldReadAvailableMemory(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		expected uint64
+	}{
+		{"ShouldUseMemInfoWithoutCgroup", map[string]string{"meminfo": testMemInfo}, 1024000 * 1024},
+		{"ShouldUseMemInfoWithUnlimitedCgroupV2", map[string]string{"meminfo": testMemInfo, "v2/memory.max": "max\n", "v2/memory.current": "1000\n"}, 1024000 * 1024},
+		{"ShouldUseCgroupV2Limit", map[string]string{"meminfo": testMemInfo, "v2/memory.max": "536870912\n", "v2/memory.current": "268435456\n"}, 268435456},
+		{"ShouldUseCgroupV1Limit", map[string]string{"meminfo": testMemInfo, "v1/memory.limit_in_bytes": "536870912\n", "v1/memory.usage_in_bytes": "134217728\n"}, 402653184},
+		{"ShouldUseMemInfoWithLargeCgroupV1Limit", map[string]string{"meminfo": testMemInfo, "v1/memory.limit_in_bytes": "9223372036854771712\n", "v1/memory.usage_in_bytes": "134217728\n"}, 1024000 * 1024},
+		{"ShouldNotUnderflowWhenCgroupUsageExceedsLimit", map[string]string{"meminfo": testMemInfo, "v2/memory.max": "1000\n", "v2/memory.current": "2000\n"}, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeMemoryTestFiles(t, tc.files)
+
+			available, err := availableMemory(filepath.Join(dir, "meminfo"), filepath.Join(dir, "v2"), filepath.Join(dir, "v1"))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, available)
+		})
+	}
+}
+
+func TestShouldErrorWhenAvailableMemoryIsNotReported(t *testing.T) {
+	dir := writeMemoryTestFiles(t, map[string]string{"meminfo": "MemTotal:        2048000 kB\n"})
+
+	_, err := availableMemory(filepath.Join(dir, "meminfo"), dir, dir)
+	assert.EqualError(t, err, "unable to determine the available memory: the kernel does not report the available memory")
+
+	_, err = availableMemory(filepath.Join(dir, "missing"), dir, dir)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
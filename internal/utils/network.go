@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"net"
+	"strings"
+)
+
+// ParseNetwork parses a network in CIDR notation or a single IP which is converted to a /32 or /128 network.
+func ParseNetwork(network string) (cidr *net.IPNet, err error) {
+	if !strings.Contains(network, "/") {
+		ip := net.ParseIP(network)
+		if ip.To4() != nil {
+			_, cidr, err = net.ParseCIDR(network + "/32")
+		} else {
+			_, cidr, err = net.ParseCIDR(network + "/128")
+		}
+	} else {
+		_, cidr, err = net.ParseCIDR(network)
+	}
+
+	return cidr, err
+}
+
+// IsIPInNetworks returns true if the IP is contained in any of the networks.
+func IsIPInNetworks(ip net.IP, networks []*net.IPNet) (match bool) {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShouldParseNetwork(t *testing.T) {
+	cidr, err := ParseNetwork("192.168.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "192.168.0.1/32", cidr.String())
+
+	cidr, err = ParseNetwork("fec0::1")
+	require.NoError(t, err)
+	assert.Equal(t, "fec0::1/128", cidr.String())
+
+	cidr, err = ParseNetwork("10.0.0.1/8")
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", cidr.String())
+
+	_, err = ParseNetwork("example.com")
+	assert.Error(t, err)
+}
+
+func TestShouldCheckIPInNetworks(t *testing.T) {
+	a, err := ParseNetwork("10.0.0.0/8")
+	require.NoError(t, err)
+
+	b, err := ParseNetwork("fec0::/64")
+	require.NoError(t, err)
+
+	networks := []*net.IPNet{a, b}
+
+	assert.True(t, IsIPInNetworks(net.ParseIP("10.1.2.3"), networks))
+	assert.True(t, IsIPInNetworks(net.ParseIP("fec0::2"), networks))
+	assert.False(t, IsIPInNetworks(net.ParseIP("192.168.0.1"), networks))
+	assert.False(t, IsIPInNetworks(net.ParseIP("192.168.0.1"), nil))
+}
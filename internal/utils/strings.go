@@ -194,6 +194,18 @@ func OriginFromURL(u url.URL) (origin url.URL) {
 	}
 }
 
+// IsHostnameInDomain returns true if the hostname is the domain or a subdomain of the domain, i.e. the domain is a
+// registrable suffix of the hostname. The comparison is case insensitive.
+func IsHostnameInDomain(hostname, domain string) (in bool) {
+	if hostname == "" || domain == "" || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return false
+	}
+
+	hostname, domain = strings.ToLower(hostname), strings.ToLower(domain)
+
+	return hostname == domain || strings.HasSuffix(hostname, "."+domain)
+}
+
 // StringSlicesDelta takes a before and after []string and compares them returning a added and removed []string.
 func StringSlicesDelta(before, after []string) (added, removed []string) {
 	for _, s := range before {
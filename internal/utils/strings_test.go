@@ -263,6 +263,20 @@ func TestJoinAndCanonicalizeHeaders(t *testing.T) {
 	assert.Equal(t, []byte("X-Example-One, X-Egg-Two"), result)
 }
 
+func TestIsHostnameInDomain(t *testing.T) {
+	assert.True(t, IsHostnameInDomain("example.com", "example.com"))
+	assert.True(t, IsHostnameInDomain("auth.example.com", "example.com"))
+	assert.True(t, IsHostnameInDomain("AUTH.Example.com", "example.COM"))
+	assert.True(t, IsHostnameInDomain("a.b.example.com", "b.example.com"))
+
+	assert.False(t, IsHostnameInDomain("example.com", "auth.example.com"))
+	assert.False(t, IsHostnameInDomain("badexample.com", "example.com"))
+	assert.False(t, IsHostnameInDomain("example.com", ".example.com"))
+	assert.False(t, IsHostnameInDomain("example.com", "example.com."))
+	assert.False(t, IsHostnameInDomain("", "example.com"))
+	assert.False(t, IsHostnameInDomain("example.com", ""))
+}
+
 func TestIsURLHostComponent(t *testing.T) {
 	testCases := []struct {
 		desc, have           string
@@ -20,10 +20,13 @@ import * as themes from "@themes/index";
 import { getBasePath } from "@utils/BasePath";
 import {
     getDuoSelfEnrollment,
+    getLoginHint,
     getRememberMe,
     getResetPassword,
     getResetPasswordCustomURL,
+    getResetPasswordMethod,
     getTheme,
+    getThemeFallback,
 } from "@utils/Configuration";
 import RegisterOneTimePassword from "@views/DeviceRegistration/RegisterOneTimePassword";
 import RegisterWebauthn from "@views/DeviceRegistration/RegisterWebauthn";
@@ -45,17 +48,37 @@ function Theme() {
         case "grey":
             return themes.Grey;
         case "auto":
-            return window.matchMedia("(prefers-color-scheme: dark)").matches ? themes.Dark : themes.Light;
+            return AutoTheme();
         default:
             return themes.Light;
     }
 }
 
+function FallbackTheme() {
+    switch (getThemeFallback()) {
+        case "dark":
+            return themes.Dark;
+        case "grey":
+            return themes.Grey;
+        default:
+            return themes.Light;
+    }
+}
+
+function AutoTheme() {
+    // Browsers which don't support the prefers-color-scheme media feature report the media as "not all".
+    if (!window.matchMedia || window.matchMedia("(prefers-color-scheme)").media === "not all") {
+        return FallbackTheme();
+    }
+
+    return window.matchMedia("(prefers-color-scheme: dark)").matches ? themes.Dark : themes.Light;
+}
+
 const App: React.FC = () => {
     const [notification, setNotification] = useState(null as Notification | null);
     const [theme, setTheme] = useState(Theme());
     useEffect(() => {
-        if (getTheme() === "auto") {
+        if (getTheme() === "auto" && window.matchMedia) {
             const query = window.matchMedia("(prefers-color-scheme: dark)");
             // MediaQueryLists does not inherit from EventTarget in Internet Explorer
             if (query.addEventListener) {
@@ -73,8 +96,14 @@ const App: React.FC = () => {
                     <Router basename={getBasePath()}>
                         <NotificationBar onClose={() => setNotification(null)} />
                         <Routes>
-                            <Route path={ResetPasswordStep1Route} element={<ResetPasswordStep1 />} />
-                            <Route path={ResetPasswordStep2Route} element={<ResetPasswordStep2 />} />
+                            <Route
+                                path={ResetPasswordStep1Route}
+                                element={<ResetPasswordStep1 resetPasswordMethod={getResetPasswordMethod()} />}
+                            />
+                            <Route
+                                path={ResetPasswordStep2Route}
+                                element={<ResetPasswordStep2 resetPasswordMethod={getResetPasswordMethod()} />}
+                            />
                             <Route path={RegisterWebauthnRoute} element={<RegisterWebauthn />} />
                             <Route path={RegisterOneTimePasswordRoute} element={<RegisterOneTimePassword />} />
                             <Route path={LogoutRoute} element={<SignOut />} />
@@ -84,6 +113,7 @@ const App: React.FC = () => {
                                 element={
                                     <LoginPortal
                                         duoSelfEnrollment={getDuoSelfEnrollment()}
+                                        loginHint={getLoginHint()}
                                         rememberMe={getRememberMe()}
                                         resetPassword={getResetPassword()}
                                         resetPasswordCustomURL={getResetPasswordCustomURL()}
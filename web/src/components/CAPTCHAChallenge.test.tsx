@@ -0,0 +1,9 @@
+import React from "react";
+
+import { render } from "@testing-library/react";
+
+import CAPTCHAChallenge from "@components/CAPTCHAChallenge";
+
+it("renders without crashing", () => {
+    render(<CAPTCHAChallenge provider="turnstile" siteKey="site" onToken={() => {}} />);
+});
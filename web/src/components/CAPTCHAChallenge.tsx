@@ -0,0 +1,56 @@
+import React, { useEffect, useRef } from "react";
+
+export interface Props {
+    provider: string;
+    siteKey: string;
+
+    onToken: (token: string) => void;
+}
+
+interface CAPTCHARenderer {
+    render: (container: HTMLElement, options: { sitekey: string; callback: (token: string) => void }) => void;
+}
+
+// All the supported providers expose the same explicit render API under a different global.
+const providers: { [provider: string]: { script: string; global: string } } = {
+    recaptcha: { script: "https://www.google.com/recaptcha/api.js?render=explicit", global: "grecaptcha" },
+    hcaptcha: { script: "https://js.hcaptcha.com/1/api.js?render=explicit", global: "hcaptcha" },
+    turnstile: { script: "https://challenges.cloudflare.com/turnstile/v0/api.js?render=explicit", global: "turnstile" },
+};
+
+const CAPTCHAChallenge = function (props: Props) {
+    const containerRef = useRef<HTMLDivElement>(null);
+    const { provider, siteKey, onToken } = props;
+
+    useEffect(() => {
+        const config = providers[provider];
+        if (!config || !containerRef.current) {
+            return;
+        }
+
+        const container = containerRef.current;
+        const renderChallenge = () => {
+            const renderer = (window as any)[config.global] as CAPTCHARenderer | undefined;
+            if (renderer && container.childElementCount === 0) {
+                renderer.render(container, { sitekey: siteKey, callback: onToken });
+            }
+        };
+
+        let script = document.querySelector(`script[src="${config.script}"]`) as HTMLScriptElement | null;
+        if (script === null) {
+            script = document.createElement("script");
+            script.src = config.script;
+            script.async = true;
+            document.head.appendChild(script);
+        }
+
+        script.addEventListener("load", renderChallenge);
+        renderChallenge();
+
+        return () => script?.removeEventListener("load", renderChallenge);
+    }, [provider, siteKey, onToken]);
+
+    return <div id="captcha-challenge" ref={containerRef} />;
+};
+
+export default CAPTCHAChallenge;
@@ -0,0 +1,9 @@
+import React from "react";
+
+import { render } from "@testing-library/react";
+
+import InactivityWarningDialog from "@components/InactivityWarningDialog";
+
+it("renders without crashing", () => {
+    render(<InactivityWarningDialog sessionRemaining={300} inactivityWarning={60} onExpired={() => {}} />);
+});
@@ -0,0 +1,71 @@
+import React, { useCallback, useEffect, useState } from "react";
+
+import { Button, Dialog, DialogActions, DialogContent, DialogTitle, Typography } from "@material-ui/core";
+import { useTranslation } from "react-i18next";
+
+import { refreshSession } from "@services/State";
+
+export interface Props {
+    sessionRemaining: number;
+    inactivityWarning: number;
+
+    onExpired: () => void;
+}
+
+const InactivityWarningDialog = function (props: Props) {
+    const { t: translate } = useTranslation();
+    const [expiresAt, setExpiresAt] = useState(() => Date.now() + props.sessionRemaining * 1000);
+    const [secondsLeft, setSecondsLeft] = useState(props.sessionRemaining);
+    const { onExpired } = props;
+
+    useEffect(() => {
+        setExpiresAt(Date.now() + props.sessionRemaining * 1000);
+    }, [props.sessionRemaining]);
+
+    useEffect(() => {
+        const intervalNode = setInterval(() => {
+            const left = Math.max(0, Math.round((expiresAt - Date.now()) / 1000));
+
+            setSecondsLeft(left);
+
+            if (left === 0) {
+                clearInterval(intervalNode);
+                onExpired();
+            }
+        }, 1000);
+
+        return () => clearInterval(intervalNode);
+    }, [expiresAt, onExpired]);
+
+    const handleStaySignedIn = useCallback(async () => {
+        try {
+            const response = await refreshSession();
+            if (response && response.session_remaining !== undefined) {
+                setExpiresAt(Date.now() + response.session_remaining * 1000);
+            }
+        } catch (err) {
+            console.error(err);
+            onExpired();
+        }
+    }, [onExpired]);
+
+    return (
+        <Dialog open={secondsLeft > 0 && secondsLeft <= props.inactivityWarning} id="inactivity-warning-dialog">
+            <DialogTitle>{translate("Are you still there?")}</DialogTitle>
+            <DialogContent>
+                <Typography>
+                    {translate("You will be signed out due to inactivity in {{seconds}} seconds", {
+                        seconds: secondsLeft,
+                    })}
+                </Typography>
+            </DialogContent>
+            <DialogActions>
+                <Button id="stay-signed-in-button" color="primary" variant="contained" onClick={handleStaySignedIn}>
+                    {translate("Stay signed in")}
+                </Button>
+            </DialogActions>
+        </Dialog>
+    );
+};
+
+export default InactivityWarningDialog;
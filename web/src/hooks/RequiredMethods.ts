@@ -0,0 +1,8 @@
+import queryString from "query-string";
+import { useLocation } from "react-router-dom";
+
+export function useRequiredMethods() {
+    const location = useLocation();
+    const queryParams = queryString.parse(location.search);
+    return queryParams && "sfm" in queryParams ? (queryParams["sfm"] as string) : undefined;
+}
@@ -3,6 +3,8 @@ import LanguageDetector from "i18next-browser-languagedetector";
 import Backend from "i18next-http-backend";
 import { initReactI18next } from "react-i18next";
 
+import { getBasePath } from "@utils/BasePath";
+
 i18n.use(Backend)
     .use(LanguageDetector)
     .use(initReactI18next)
@@ -12,7 +14,7 @@ i18n.use(Backend)
             lookupQuerystring: "lng",
         },
         backend: {
-            loadPath: "/locales/{{lng}}/{{ns}}.json",
+            loadPath: getBasePath() + "/locales/{{lng}}/{{ns}}.json",
         },
         ns: ["portal"],
         defaultNS: "portal",
@@ -32,6 +32,7 @@ export const ChecksSafeRedirectionPath = basePath + "/api/checks/safe-redirectio
 
 export const LogoutPath = basePath + "/api/logout";
 export const StatePath = basePath + "/api/state";
+export const SessionRefreshPath = basePath + "/api/session/refresh";
 export const UserInfoPath = basePath + "/api/user/info";
 export const UserInfo2FAMethodPath = basePath + "/api/user/info/2fa_method";
 export const UserInfoTOTPConfigurationPath = basePath + "/api/user/info/totp";
@@ -17,6 +17,7 @@ interface ConsentGetResponseBody {
     scopes: string[];
     audience: string[];
     pre_configuration: boolean;
+    scope_descriptions?: Record<string, string>;
 }
 
 export function getConsentResponse() {
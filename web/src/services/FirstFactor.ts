@@ -8,6 +8,7 @@ interface PostFirstFactorBody {
     keepMeLoggedIn: boolean;
     targetURL?: string;
     requestMethod?: string;
+    captchaToken?: string;
 }
 
 export async function postFirstFactor(
@@ -16,6 +17,7 @@ export async function postFirstFactor(
     rememberMe: boolean,
     targetURL?: string,
     requestMethod?: string,
+    captchaToken?: string,
 ) {
     const data: PostFirstFactorBody = {
         username,
@@ -31,6 +33,10 @@ export async function postFirstFactor(
         data.requestMethod = requestMethod;
     }
 
+    if (captchaToken) {
+        data.captchaToken = captchaToken;
+    }
+
     const res = await PostWithOptionalResponse<SignInResponse>(FirstFactorPath, data);
     return res ? res : ({} as SignInResponse);
 }
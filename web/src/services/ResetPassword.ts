@@ -9,6 +9,10 @@ export async function completeResetPasswordProcess(token: string) {
     return PostWithOptionalResponse(CompleteResetPasswordPath, { token });
 }
 
+export async function completeResetPasswordProcessWithCode(username: string, code: string) {
+    return PostWithOptionalResponse(CompleteResetPasswordPath, { username, code });
+}
+
 export async function resetPassword(newPassword: string) {
     return PostWithOptionalResponse(ResetPasswordPath, { password: newPassword });
 }
@@ -3,15 +3,21 @@ import { PostWithOptionalResponse } from "@services/Client";
 
 export type SignOutResponse = { safeTargetURL: boolean } | undefined;
 
+export type SignOutScope = "current" | "all";
+
 export type SignOutBody = {
     targetURL?: string;
+    scope?: SignOutScope;
 };
 
-export async function signOut(targetURL: string | undefined): Promise<SignOutResponse> {
+export async function signOut(targetURL: string | undefined, scope?: SignOutScope): Promise<SignOutResponse> {
     const body: SignOutBody = {};
     if (targetURL) {
         body.targetURL = targetURL;
     }
+    if (scope) {
+        body.scope = scope;
+    }
 
     return PostWithOptionalResponse<SignOutResponse>(LogoutPath, body);
 }
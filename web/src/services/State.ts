@@ -1,5 +1,6 @@
-import { StatePath } from "@services/Api";
-import { Get } from "@services/Client";
+import { SessionRefreshPath, StatePath } from "@services/Api";
+import { Get, PostWithOptionalResponse } from "@services/Client";
+import { Capabilities } from "@utils/Configuration";
 
 export enum AuthenticationLevel {
     Unauthenticated = 0,
@@ -10,8 +11,25 @@ export enum AuthenticationLevel {
 export interface AutheliaState {
     username: string;
     authentication_level: AuthenticationLevel;
+    session_remaining?: number;
+    inactivity_warning?: number;
+    captcha?: CAPTCHAState;
+    capabilities: Capabilities;
+}
+
+export interface CAPTCHAState {
+    provider: string;
+    site_key: string;
+}
+
+export interface SessionRefreshResponse {
+    session_remaining?: number;
 }
 
 export async function getState(): Promise<AutheliaState> {
     return Get<AutheliaState>(StatePath);
 }
+
+export async function refreshSession(): Promise<SessionRefreshResponse | undefined> {
+    return PostWithOptionalResponse<SessionRefreshResponse>(SessionRefreshPath);
+}
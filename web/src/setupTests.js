@@ -5,4 +5,6 @@ document.body.setAttribute("data-duoselfenrollment", "true");
 document.body.setAttribute("data-rememberme", "true");
 document.body.setAttribute("data-resetpassword", "true");
 document.body.setAttribute("data-resetpasswordcustomurl", "");
+document.body.setAttribute("data-resetpasswordmethod", "email");
 document.body.setAttribute("data-theme", "light");
+document.body.setAttribute("data-themefallback", "light");
@@ -7,10 +7,29 @@ export function getEmbeddedVariable(variableName: string) {
     return value;
 }
 
+export interface Capabilities {
+    reset_password: boolean;
+    reset_password_method?: string;
+    reset_password_custom_url?: string;
+    remember_me: boolean;
+    self_registration: boolean;
+    duo_self_enrollment: boolean;
+    second_factor_methods: string[];
+    identity_providers: string[];
+}
+
+export function getCapabilities(): Capabilities {
+    return JSON.parse(getEmbeddedVariable("capabilities"));
+}
+
 export function getDuoSelfEnrollment() {
     return getEmbeddedVariable("duoselfenrollment") === "true";
 }
 
+export function getLoginHint() {
+    return getEmbeddedVariable("loginhint");
+}
+
 export function getLogoOverride() {
     return getEmbeddedVariable("logooverride") === "true";
 }
@@ -27,6 +46,14 @@ export function getResetPasswordCustomURL() {
     return getEmbeddedVariable("resetpasswordcustomurl");
 }
 
+export function getResetPasswordMethod() {
+    return getEmbeddedVariable("resetpasswordmethod");
+}
+
 export function getTheme() {
     return getEmbeddedVariable("theme");
 }
+
+export function getThemeFallback() {
+    return getEmbeddedVariable("themefallback");
+}
@@ -4,12 +4,15 @@ import { Grid, makeStyles, Button } from "@material-ui/core";
 import { useTranslation } from "react-i18next";
 import { useNavigate } from "react-router-dom";
 
+import InactivityWarningDialog from "@components/InactivityWarningDialog";
 import { LogoutRoute as SignOutRoute } from "@constants/Routes";
 import LoginLayout from "@layouts/LoginLayout";
 import Authenticated from "@views/LoginPortal/Authenticated";
 
 export interface Props {
     name: string;
+    sessionRemaining?: number;
+    inactivityWarning?: number;
 }
 
 const AuthenticatedView = function (props: Props) {
@@ -33,6 +36,13 @@ const AuthenticatedView = function (props: Props) {
                     <Authenticated />
                 </Grid>
             </Grid>
+            {props.sessionRemaining !== undefined && props.inactivityWarning !== undefined ? (
+                <InactivityWarningDialog
+                    sessionRemaining={props.sessionRemaining}
+                    inactivityWarning={props.inactivityWarning}
+                    onExpired={handleLogoutClick}
+                />
+            ) : null}
         </LoginLayout>
     );
 };
@@ -91,7 +91,7 @@ const ConsentView = function (props: Props) {
             case "email":
                 return translate("Access your email addresses");
             default:
-                return id;
+                return resp?.scope_descriptions?.[id] ?? id;
         }
     };
 
@@ -5,6 +5,7 @@ import classnames from "classnames";
 import { useTranslation } from "react-i18next";
 import { useNavigate } from "react-router-dom";
 
+import CAPTCHAChallenge from "@components/CAPTCHAChallenge";
 import FixedTextField from "@components/FixedTextField";
 import { ResetPasswordStep1Route } from "@constants/Routes";
 import { useNotifications } from "@hooks/NotificationsContext";
@@ -12,14 +13,18 @@ import { useRedirectionURL } from "@hooks/RedirectionURL";
 import { useRequestMethod } from "@hooks/RequestMethod";
 import LoginLayout from "@layouts/LoginLayout";
 import { postFirstFactor } from "@services/FirstFactor";
+import { CAPTCHAState } from "@services/State";
 
 export interface Props {
     disabled: boolean;
+    loginHint: string;
     rememberMe: boolean;
 
     resetPassword: boolean;
     resetPasswordCustomURL: string;
 
+    captcha?: CAPTCHAState;
+
     onAuthenticationStart: () => void;
     onAuthenticationFailure: () => void;
     onAuthenticationSuccess: (redirectURL: string | undefined) => void;
@@ -32,19 +37,22 @@ const FirstFactorForm = function (props: Props) {
     const requestMethod = useRequestMethod();
 
     const [rememberMe, setRememberMe] = useState(false);
-    const [username, setUsername] = useState("");
+    const [username, setUsername] = useState(props.loginHint);
     const [usernameError, setUsernameError] = useState(false);
     const [password, setPassword] = useState("");
     const [passwordError, setPasswordError] = useState(false);
+    const [captchaToken, setCAPTCHAToken] = useState("");
+    // Tokens can only be verified once so the challenge is rendered again after each failed attempt.
+    const [captchaKey, setCAPTCHAKey] = useState(0);
     const { createErrorNotification } = useNotifications();
     // TODO (PR: #806, Issue: #511) potentially refactor
     const usernameRef = useRef() as MutableRefObject<HTMLInputElement>;
     const passwordRef = useRef() as MutableRefObject<HTMLInputElement>;
     const { t: translate } = useTranslation();
     useEffect(() => {
-        const timeout = setTimeout(() => usernameRef.current.focus(), 10);
+        const timeout = setTimeout(() => (props.loginHint === "" ? usernameRef : passwordRef).current.focus(), 10);
         return () => clearTimeout(timeout);
-    }, [usernameRef]);
+    }, [usernameRef, passwordRef, props.loginHint]);
 
     const disabled = props.disabled;
 
@@ -66,12 +74,37 @@ const FirstFactorForm = function (props: Props) {
 
         props.onAuthenticationStart();
         try {
-            const res = await postFirstFactor(username, password, rememberMe, redirectionURL, requestMethod);
+            const res = await postFirstFactor(
+                username,
+                password,
+                rememberMe,
+                redirectionURL,
+                requestMethod,
+                captchaToken,
+            );
             props.onAuthenticationSuccess(res ? res.redirect : undefined);
         } catch (err) {
             console.error(err);
-            createErrorNotification(translate("Incorrect username or password"));
+            const message = (err as Error).message;
+            if (props.captcha && captchaToken === "") {
+                createErrorNotification(translate("Please complete the CAPTCHA challenge"));
+            } else if (message.includes("Your password must be reset.")) {
+                createErrorNotification(translate("Your password has expired and must be reset"));
+                props.onAuthenticationFailure();
+                handleResetPasswordClick();
+                return;
+            } else if (message.includes("Your account has expired.")) {
+                createErrorNotification(translate("Your account has expired"));
+            } else if (message.includes("Your password has expired.")) {
+                createErrorNotification(translate("Your password has expired"));
+            } else if (message.includes("Your password must be changed.")) {
+                createErrorNotification(translate("Your password must be changed"));
+            } else {
+                createErrorNotification(translate("Incorrect username or password"));
+            }
             props.onAuthenticationFailure();
+            setCAPTCHAToken("");
+            setCAPTCHAKey(captchaKey + 1);
             setPassword("");
             passwordRef.current.focus();
         }
@@ -149,6 +182,16 @@ const FirstFactorForm = function (props: Props) {
                         }}
                     />
                 </Grid>
+                {props.captcha ? (
+                    <Grid item xs={12} className={classnames(style.captcha)}>
+                        <CAPTCHAChallenge
+                            key={captchaKey}
+                            provider={props.captcha.provider}
+                            siteKey={props.captcha.site_key}
+                            onToken={setCAPTCHAToken}
+                        />
+                    </Grid>
+                ) : null}
                 {props.rememberMe ? (
                     <Grid item xs={12} className={classnames(style.actionRow)}>
                         <FormControlLabel
@@ -223,6 +266,10 @@ const useStyles = makeStyles((theme) => ({
     rememberMe: {
         flexGrow: 1,
     },
+    captcha: {
+        display: "flex",
+        justifyContent: "center",
+    },
     flexEnd: {
         justifyContent: "flex-end",
     },
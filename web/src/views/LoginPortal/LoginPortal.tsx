@@ -1,4 +1,4 @@
-import React, { Fragment, ReactNode, useCallback, useEffect, useState } from "react";
+import React, { Fragment, ReactNode, useCallback, useEffect, useMemo, useState } from "react";
 
 import { Route, Routes, useLocation, useNavigate } from "react-router-dom";
 
@@ -15,11 +15,13 @@ import { useNotifications } from "@hooks/NotificationsContext";
 import { useRedirectionURL } from "@hooks/RedirectionURL";
 import { useRedirector } from "@hooks/Redirector";
 import { useRequestMethod } from "@hooks/RequestMethod";
+import { useRequiredMethods } from "@hooks/RequiredMethods";
 import { useAutheliaState } from "@hooks/State";
 import { useUserInfoPOST } from "@hooks/UserInfo";
 import { SecondFactorMethod } from "@models/Methods";
 import { checkSafeRedirection } from "@services/SafeRedirection";
 import { AuthenticationLevel } from "@services/State";
+import { Method2FA, toEnum } from "@services/UserInfo";
 import LoadingPage from "@views/LoadingPage/LoadingPage";
 import AuthenticatedView from "@views/LoginPortal/AuthenticatedView/AuthenticatedView";
 import FirstFactorForm from "@views/LoginPortal/FirstFactor/FirstFactorForm";
@@ -27,6 +29,7 @@ import SecondFactorForm from "@views/LoginPortal/SecondFactor/SecondFactorForm";
 
 export interface Props {
     duoSelfEnrollment: boolean;
+    loginHint: string;
     rememberMe: boolean;
 
     resetPassword: boolean;
@@ -41,13 +44,40 @@ const LoginPortal = function (props: Props) {
     const location = useLocation();
     const redirectionURL = useRedirectionURL();
     const requestMethod = useRequestMethod();
+    const requiredMethods = useRequiredMethods();
     const { createErrorNotification } = useNotifications();
     const [firstFactorDisabled, setFirstFactorDisabled] = useState(true);
     const redirector = useRedirector();
 
     const [state, fetchState, , fetchStateError] = useAutheliaState();
     const [userInfo, fetchUserInfo, , fetchUserInfoError] = useUserInfoPOST();
-    const [configuration, fetchConfiguration, , fetchConfigurationError] = useConfiguration();
+    const [fetchedConfiguration, fetchConfiguration, , fetchConfigurationError] = useConfiguration();
+
+    // When the resource requires specific second factor methods only those methods are offered, and a user who completed
+    // the second factor with another method must complete it again with one of them.
+    const required = useMemo(
+        () =>
+            requiredMethods
+                ? new Set(requiredMethods.split(",").map((method) => toEnum(method as Method2FA)))
+                : undefined,
+        [requiredMethods],
+    );
+    const configuration = useMemo(
+        () =>
+            fetchedConfiguration && required
+                ? {
+                      ...fetchedConfiguration,
+                      available_methods: new Set(
+                          Array.from(fetchedConfiguration.available_methods).filter((method) => required.has(method)),
+                      ),
+                  }
+                : fetchedConfiguration,
+        [fetchedConfiguration, required],
+    );
+    const authenticationLevel =
+        state && required && state.authentication_level === AuthenticationLevel.TwoFactor
+            ? AuthenticationLevel.OneFactor
+            : state?.authentication_level;
 
     const redirect = useCallback((url: string) => navigate(url), [navigate]);
 
@@ -104,7 +134,7 @@ const LoginPortal = function (props: Props) {
                 ((configuration &&
                     configuration.available_methods.size === 0 &&
                     state.authentication_level >= AuthenticationLevel.OneFactor) ||
-                    state.authentication_level === AuthenticationLevel.TwoFactor)
+                    authenticationLevel === AuthenticationLevel.TwoFactor)
             ) {
                 try {
                     const res = await checkSafeRedirection(redirectionURL);
@@ -120,7 +150,9 @@ const LoginPortal = function (props: Props) {
             }
 
             const redirectionSuffix = redirectionURL
-                ? `?rd=${encodeURIComponent(redirectionURL)}${requestMethod ? `&rm=${requestMethod}` : ""}`
+                ? `?rd=${encodeURIComponent(redirectionURL)}${requestMethod ? `&rm=${requestMethod}` : ""}${
+                      requiredMethods ? `&sfm=${encodeURIComponent(requiredMethods)}` : ""
+                  }`
                 : "";
 
             if (state.authentication_level === AuthenticationLevel.Unauthenticated) {
@@ -130,9 +162,14 @@ const LoginPortal = function (props: Props) {
                 if (configuration.available_methods.size === 0) {
                     redirect(AuthenticatedRoute);
                 } else {
-                    if (userInfo.method === SecondFactorMethod.Webauthn) {
+                    // The preferred method may not be available when the resource requires specific methods.
+                    const method = configuration.available_methods.has(userInfo.method)
+                        ? userInfo.method
+                        : Array.from(configuration.available_methods)[0];
+
+                    if (method === SecondFactorMethod.Webauthn) {
                         redirect(`${SecondFactorRoute}${SecondFactorWebauthnSubRoute}${redirectionSuffix}`);
-                    } else if (userInfo.method === SecondFactorMethod.MobilePush) {
+                    } else if (method === SecondFactorMethod.MobilePush) {
                         redirect(`${SecondFactorRoute}${SecondFactorPushSubRoute}${redirectionSuffix}`);
                     } else {
                         redirect(`${SecondFactorRoute}${SecondFactorTOTPSubRoute}${redirectionSuffix}`);
@@ -142,8 +179,10 @@ const LoginPortal = function (props: Props) {
         })();
     }, [
         state,
+        authenticationLevel,
         redirectionURL,
         requestMethod,
+        requiredMethods,
         redirect,
         userInfo,
         setFirstFactorDisabled,
@@ -175,11 +214,16 @@ const LoginPortal = function (props: Props) {
                     <ComponentOrLoading ready={firstFactorReady}>
                         <FirstFactorForm
                             disabled={firstFactorDisabled}
+                            loginHint={props.loginHint}
                             rememberMe={props.rememberMe}
                             resetPassword={props.resetPassword}
                             resetPasswordCustomURL={props.resetPasswordCustomURL}
+                            captcha={state ? state.captcha : undefined}
                             onAuthenticationStart={() => setFirstFactorDisabled(true)}
-                            onAuthenticationFailure={() => setFirstFactorDisabled(false)}
+                            onAuthenticationFailure={() => {
+                                setFirstFactorDisabled(false);
+                                fetchState();
+                            }}
                             onAuthenticationSuccess={handleAuthSuccess}
                         />
                     </ComponentOrLoading>
@@ -188,9 +232,9 @@ const LoginPortal = function (props: Props) {
             <Route
                 path={`${SecondFactorRoute}*`}
                 element={
-                    state && userInfo && configuration ? (
+                    state && authenticationLevel !== undefined && userInfo && configuration ? (
                         <SecondFactorForm
-                            authenticationLevel={state.authentication_level}
+                            authenticationLevel={authenticationLevel}
                             userInfo={userInfo}
                             configuration={configuration}
                             duoSelfEnrollment={props.duoSelfEnrollment}
@@ -202,7 +246,15 @@ const LoginPortal = function (props: Props) {
             />
             <Route
                 path={AuthenticatedRoute}
-                element={userInfo ? <AuthenticatedView name={userInfo.display_name} /> : null}
+                element={
+                    userInfo ? (
+                        <AuthenticatedView
+                            name={userInfo.display_name}
+                            sessionRemaining={state?.session_remaining}
+                            inactivityWarning={state?.inactivity_warning}
+                        />
+                    ) : null
+                }
             />
         </Routes>
     );
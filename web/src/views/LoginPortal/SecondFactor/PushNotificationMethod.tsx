@@ -128,7 +128,11 @@ const PushNotificationMethod = function (props: Props) {
             if (!mounted.current || state !== State.SignInInProgress) return;
 
             console.error(err);
-            onSignInErrorCallback(new Error("There was an issue completing sign in process"));
+            if ((err as Error).message.includes("Duo did not respond in time")) {
+                onSignInErrorCallback(new Error("Duo did not respond in time, please retry later"));
+            } else {
+                onSignInErrorCallback(new Error("There was an issue completing sign in process"));
+            }
             setState(State.Failure);
         }
     }, [
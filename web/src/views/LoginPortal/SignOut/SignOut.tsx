@@ -1,8 +1,9 @@
 import React, { useEffect, useCallback, useState } from "react";
 
 import { Typography, makeStyles } from "@material-ui/core";
+import queryString from "query-string";
 import { useTranslation } from "react-i18next";
-import { Navigate } from "react-router-dom";
+import { Navigate, useLocation } from "react-router-dom";
 
 import { IndexRoute } from "@constants/Routes";
 import { useIsMountedRef } from "@hooks/Mounted";
@@ -20,13 +21,15 @@ const SignOut = function (props: Props) {
     const { createErrorNotification } = useNotifications();
     const redirectionURL = useRedirectionURL();
     const redirector = useRedirector();
+    const location = useLocation();
+    const scope = queryString.parse(location.search)["scope"] === "all" ? "all" : undefined;
     const [timedOut, setTimedOut] = useState(false);
     const [safeRedirect, setSafeRedirect] = useState(false);
     const { t: translate } = useTranslation();
 
     const doSignOut = useCallback(async () => {
         try {
-            const res = await signOut(redirectionURL);
+            const res = await signOut(redirectionURL, scope);
             if (res !== undefined && res.safeTargetURL) {
                 setSafeRedirect(true);
             }
@@ -40,7 +43,7 @@ const SignOut = function (props: Props) {
             console.error(err);
             createErrorNotification(translate("There was an issue signing out"));
         }
-    }, [createErrorNotification, redirectionURL, setSafeRedirect, setTimedOut, mounted, translate]);
+    }, [createErrorNotification, redirectionURL, scope, setSafeRedirect, setTimedOut, mounted, translate]);
 
     useEffect(() => {
         doSignOut();
@@ -5,22 +5,42 @@ import { useTranslation } from "react-i18next";
 import { useNavigate } from "react-router-dom";
 
 import FixedTextField from "@components/FixedTextField";
-import { IndexRoute } from "@constants/Routes";
+import { IndexRoute, ResetPasswordStep2Route } from "@constants/Routes";
 import { useNotifications } from "@hooks/NotificationsContext";
 import LoginLayout from "@layouts/LoginLayout";
-import { initiateResetPasswordProcess } from "@services/ResetPassword";
+import { completeResetPasswordProcessWithCode, initiateResetPasswordProcess } from "@services/ResetPassword";
 
-const ResetPasswordStep1 = function () {
+export interface Props {
+    resetPasswordMethod: string;
+}
+
+const ResetPasswordStep1 = function (props: Props) {
     const style = useStyles();
     const [username, setUsername] = useState("");
+    const [code, setCode] = useState("");
     const [error, setError] = useState(false);
+    const [errorCode, setErrorCode] = useState(false);
+    const adminCode = props.resetPasswordMethod === "admin_code";
     const { createInfoNotification, createErrorNotification } = useNotifications();
     const navigate = useNavigate();
     const { t: translate } = useTranslation();
 
     const doInitiateResetPasswordProcess = async () => {
-        if (username === "") {
-            setError(true);
+        if (username === "" || (adminCode && code === "")) {
+            setError(username === "");
+            setErrorCode(adminCode && code === "");
+            return;
+        }
+
+        if (adminCode) {
+            try {
+                await completeResetPasswordProcessWithCode(username, code);
+                navigate(ResetPasswordStep2Route);
+            } catch (err) {
+                createErrorNotification(
+                    translate("There was an issue verifying the reset code. It might be invalid or have expired"),
+                );
+            }
             return;
         }
 
@@ -60,6 +80,26 @@ const ResetPasswordStep1 = function () {
                         }}
                     />
                 </Grid>
+                {adminCode ? (
+                    <Grid item xs={12}>
+                        <FixedTextField
+                            id="reset-code-textfield"
+                            label={translate("Reset code")}
+                            variant="outlined"
+                            fullWidth
+                            error={errorCode}
+                            value={code}
+                            helperText={translate("Contact your administrator to obtain a password reset code")}
+                            onChange={(e) => setCode(e.target.value.trim())}
+                            onKeyPress={(ev) => {
+                                if (ev.key === "Enter") {
+                                    doInitiateResetPasswordProcess();
+                                    ev.preventDefault();
+                                }
+                            }}
+                        />
+                    </Grid>
+                ) : null}
                 <Grid item xs={6}>
                     <Button id="reset-button" variant="contained" color="primary" fullWidth onClick={handleResetClick}>
                         {translate("Reset")}
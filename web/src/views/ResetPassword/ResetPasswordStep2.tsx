@@ -16,7 +16,11 @@ import { getPasswordPolicyConfiguration } from "@services/PasswordPolicyConfigur
 import { completeResetPasswordProcess, resetPassword } from "@services/ResetPassword";
 import { extractIdentityToken } from "@utils/IdentityToken";
 
-const ResetPasswordStep2 = function () {
+export interface Props {
+    resetPasswordMethod: string;
+}
+
+const ResetPasswordStep2 = function (props: Props) {
     const style = useStyles();
     const location = useLocation();
     const [formDisabled, setFormDisabled] = useState(true);
@@ -42,8 +46,22 @@ const ResetPasswordStep2 = function () {
     // Get the token from the query param to give it back to the API when requesting
     // the secret for OTP.
     const processToken = extractIdentityToken(location.search);
+    const adminCode = props.resetPasswordMethod === "admin_code";
 
     const completeProcess = useCallback(async () => {
+        // The identity has already been verified using the code issued by an administrator.
+        if (adminCode) {
+            try {
+                const policy = await getPasswordPolicyConfiguration();
+                setPPolicy(policy);
+                setFormDisabled(false);
+            } catch (err) {
+                console.error(err);
+                setFormDisabled(true);
+            }
+            return;
+        }
+
         if (!processToken) {
             setFormDisabled(true);
             createErrorNotification(translate("No verification token provided"));
@@ -63,7 +81,7 @@ const ResetPasswordStep2 = function () {
             );
             setFormDisabled(true);
         }
-    }, [processToken, createErrorNotification, translate]);
+    }, [adminCode, processToken, createErrorNotification, translate]);
 
     useEffect(() => {
         completeProcess();
@@ -97,6 +115,8 @@ const ResetPasswordStep2 = function () {
                 createErrorNotification("Your supplied password does not meet the password policy requirements.");
             } else if ((err as Error).message.includes("policy")) {
                 createErrorNotification("Your supplied password does not meet the password policy requirements.");
+            } else if ((err as Error).message.includes("too recently")) {
+                createErrorNotification("Your password was changed too recently and can't be changed again yet.");
             } else {
                 createErrorNotification(translate("There was an issue resetting the password"));
             }